@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metrics gated, as go test -benchmem reports them
+var gatedUnits = []string{"ns/op", "B/op", "allocs/op"}
+
+// procsSuffix is the -GOMAXPROCS suffix go test adds to benchmark names
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// Results maps benchmark name to unit to the values of each run
+type Results map[string]map[string][]float64
+
+// Parse reads `go test -bench` output. Lines that aren't benchmark results
+// (package headers, PASS, logs) are skipped.
+func Parse(r io.Reader) (Results, error) {
+	results := Results{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue // Not a result line, e.g. a log line starting with the name
+		}
+		name := procsSuffix.ReplaceAllString(fields[0], "")
+		if results[name] == nil {
+			results[name] = map[string][]float64{}
+		}
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: bad value %q", name, fields[i])
+			}
+			results[name][fields[i+1]] = append(results[name][fields[i+1]], value)
+		}
+	}
+	return results, scanner.Err()
+}
+
+// Comparison is one metric of one benchmark against its baseline
+type Comparison struct {
+	Name      string
+	Unit      string
+	Baseline  float64
+	Current   float64
+	Change    float64 // (current - baseline) / baseline
+	Regressed bool
+}
+
+// Thresholds are the largest growth of each metric that isn't a regression
+// (0.2 is 20%). Time is noisier than memory, which barely moves between runs.
+type Thresholds struct {
+	Time   float64 // ns/op
+	Memory float64 // B/op and allocs/op
+}
+
+func (t Thresholds) forUnit(unit string) float64 {
+	if unit == "ns/op" {
+		return t.Time
+	}
+	return t.Memory
+}
+
+// Compare sets the median of each gated metric against the baseline's.
+// Benchmarks missing from either side are left out: database benchmarks
+// skip without a test database.
+func Compare(baseline, current Results, thresholds Thresholds) []Comparison {
+	var comparisons []Comparison
+	for name, units := range current {
+		base, ok := baseline[name]
+		if !ok {
+			continue
+		}
+		for _, unit := range gatedUnits {
+			if len(units[unit]) == 0 || len(base[unit]) == 0 {
+				continue
+			}
+			c := Comparison{Name: name, Unit: unit, Baseline: median(base[unit]), Current: median(units[unit])}
+			switch {
+			case c.Baseline > 0:
+				c.Change = (c.Current - c.Baseline) / c.Baseline
+			case c.Current > 0:
+				c.Change = 1 // From nothing, e.g. allocations where there were none
+			}
+			c.Regressed = c.Change > thresholds.forUnit(unit)
+			comparisons = append(comparisons, c)
+		}
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		if comparisons[i].Name != comparisons[j].Name {
+			return comparisons[i].Name < comparisons[j].Name
+		}
+		return slices.Index(gatedUnits, comparisons[i].Unit) < slices.Index(gatedUnits, comparisons[j].Unit)
+	})
+	return comparisons
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baselineOutput = `goos: linux
+goarch: amd64
+pkg: ling-app/api/internal/services
+BenchmarkProcessAudioMessage-8   	     300	   4000000 ns/op	  400000 B/op	   64000 allocs/op
+BenchmarkProcessAudioMessage-8   	     300	   3000000 ns/op	  400000 B/op	   64000 allocs/op
+BenchmarkProcessAudioMessage-8   	     300	   3500000 ns/op	  400000 B/op	   64000 allocs/op
+BenchmarkPronunciationAnalysis-8 	    4000	    280000 ns/op	   34000 B/op	     680 allocs/op
+BenchmarkThreadListing-8         	     100	  12000000 ns/op	 2000000 B/op	   30000 allocs/op
+PASS
+ok  	ling-app/api/internal/services	3.1s
+`
+
+func TestParse(t *testing.T) {
+	results, err := Parse(strings.NewReader(baselineOutput))
+	require.NoError(t, err)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, []float64{4000000, 3000000, 3500000}, results["BenchmarkProcessAudioMessage"]["ns/op"])
+	assert.Equal(t, []float64{680}, results["BenchmarkPronunciationAnalysis"]["allocs/op"])
+}
+
+func TestCompare(t *testing.T) {
+	baseline, err := Parse(strings.NewReader(baselineOutput))
+	require.NoError(t, err)
+	current, err := Parse(strings.NewReader(`
+BenchmarkProcessAudioMessage-16   	     300	   3600000 ns/op	  400000 B/op	   64000 allocs/op
+BenchmarkPronunciationAnalysis-16 	    4000	    290000 ns/op	   34000 B/op	     900 allocs/op
+BenchmarkNew-16                   	    4000	       100 ns/op	       0 B/op	       0 allocs/op
+`))
+	require.NoError(t, err)
+
+	comparisons := Compare(baseline, current, Thresholds{Time: 0.3, Memory: 0.1})
+
+	// The database benchmark skipped and the new one has no baseline
+	require.Len(t, comparisons, 6)
+	byMetric := map[string]Comparison{}
+	for _, c := range comparisons {
+		byMetric[c.Name+" "+c.Unit] = c
+	}
+
+	audio := byMetric["BenchmarkProcessAudioMessage ns/op"]
+	assert.Equal(t, 3500000.0, audio.Baseline, "median of the baseline runs")
+	assert.False(t, audio.Regressed)
+
+	assert.False(t, byMetric["BenchmarkPronunciationAnalysis ns/op"].Regressed, "within the threshold")
+	allocs := byMetric["BenchmarkPronunciationAnalysis allocs/op"]
+	assert.True(t, allocs.Regressed)
+	assert.InDelta(t, 0.32, allocs.Change, 0.01)
+}
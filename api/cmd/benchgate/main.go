@@ -0,0 +1,59 @@
+// Command benchgate compares `go test -bench -benchmem` output with a stored
+// baseline and fails when a benchmark got significantly slower or allocates
+// significantly more. Run with -count above 1, the median of the runs is
+// compared. `make bench-check` runs the benchmarks and this gate.
+//
+//	go test -run '^$' -bench . -benchmem -count 5 ./... | benchgate -baseline bench/baseline.txt [-time-threshold 0.3] [-memory-threshold 0.1]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+)
+
+func main() {
+	baselinePath := flag.String("baseline", "bench/baseline.txt", "benchmark output to compare against")
+	timeThreshold := flag.Float64("time-threshold", 0.3, "largest growth of ns/op that isn't a regression (0.3 is 30%)")
+	memoryThreshold := flag.Float64("memory-threshold", 0.1, "largest growth of B/op and allocs/op that isn't a regression")
+	flag.Parse()
+
+	baselineFile, err := os.Open(*baselinePath)
+	if err != nil {
+		log.Fatal("Failed to open baseline: ", err)
+	}
+	defer baselineFile.Close()
+	baseline, err := Parse(baselineFile)
+	if err != nil {
+		log.Fatal("Failed to read baseline: ", err)
+	}
+	current, err := Parse(os.Stdin)
+	if err != nil {
+		log.Fatal("Failed to read benchmark output: ", err)
+	}
+
+	comparisons := Compare(baseline, current, Thresholds{Time: *timeThreshold, Memory: *memoryThreshold})
+	if len(comparisons) == 0 {
+		log.Fatal("No benchmark in the output has a baseline")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "benchmark\tunit\tbaseline\tcurrent\tchange\t\t")
+	regressions := 0
+	for _, c := range comparisons {
+		verdict := ""
+		if c.Regressed {
+			verdict = "REGRESSION"
+			regressions++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.0f\t%.0f\t%+.1f%%\t%s\t\n", c.Name, c.Unit, c.Baseline, c.Current, c.Change*100, verdict)
+	}
+	w.Flush()
+
+	if regressions > 0 {
+		fmt.Printf("\n%d metrics regressed past the threshold\n", regressions)
+		os.Exit(1)
+	}
+}
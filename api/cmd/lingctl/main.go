@@ -0,0 +1,264 @@
+// Command lingctl runs common support actions against the database. It reads
+// the same environment as the server. Every action is written to the audit
+// log under the operator, and changes ask for confirmation unless -yes is set.
+//
+//	lingctl [-operator <name>] [-yes] user <email>
+//	lingctl [-operator <name>] [-yes] grant-credits -user <id> -amount <n> -reason <why>
+//	lingctl [-operator <name>] [-yes] revoke-sessions -user <id> -reason <why>
+//	lingctl [-operator <name>] [-yes] requeue-analysis -message <id> -reason <why>
+//	lingctl [-operator <name>] [-yes] export -user <id> -reason <why> [-out user.json]
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/app"
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/services"
+)
+
+const usage = `usage: lingctl [-operator <name>] [-yes] <command> [flags]
+
+commands:
+  user <email>          show an account: credits, signup signals
+  grant-credits         add credits (-user, -amount, -reason)
+  revoke-sessions       sign a user out everywhere (-user, -reason)
+  requeue-analysis      analyze a failed voice message again (-message, -reason)
+  export                write a user's data as JSON (-user, -reason, -out)
+`
+
+func main() {
+	operator := flag.String("operator", "", "who is running the command, for the audit log (defaults to the OS user)")
+	yes := flag.Bool("yes", false, "don't ask before making changes")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	actor, err := operatorName(*operator)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Invalid configuration:")
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			log.Printf("  - %s", problem)
+		}
+		os.Exit(1)
+	}
+
+	server, err := app.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize:", err)
+	}
+	defer server.DB.ClosePool()
+
+	c := &command{
+		support: server.Services.Support,
+		users:   server.Services.AdminUsers,
+		actor:   actor,
+		yes:     *yes,
+		in:      bufio.NewReader(os.Stdin),
+	}
+	if err := c.run(flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// operatorName is the audit log actor: the -operator flag, or the OS user
+func operatorName(flagValue string) (string, error) {
+	name := strings.TrimSpace(flagValue)
+	if name == "" {
+		if current, err := user.Current(); err == nil {
+			name = current.Username
+		}
+	}
+	if name == "" {
+		return "", fmt.Errorf("-operator is required")
+	}
+	return "lingctl:" + name, nil
+}
+
+type command struct {
+	support *services.SupportService
+	users   services.AdminUserProvider
+	actor   string
+	yes     bool
+	in      *bufio.Reader
+}
+
+func (c *command) run(name string, args []string) error {
+	switch name {
+	case "user":
+		return c.user(args)
+	case "grant-credits":
+		return c.grantCredits(args)
+	case "revoke-sessions":
+		return c.revokeSessions(args)
+	case "requeue-analysis":
+		return c.requeueAnalysis(args)
+	case "export":
+		return c.export(args)
+	case "resend-verification":
+		return fmt.Errorf("resend-verification: accounts aren't email-verified, so there is nothing to resend")
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+func (c *command) user(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: lingctl user <email>")
+	}
+	view, err := c.support.FindUser(c.actor, args[0])
+	if err != nil {
+		return fmt.Errorf("look up %s: %w", args[0], err)
+	}
+	return printJSON(os.Stdout, view)
+}
+
+func (c *command) grantCredits(args []string) error {
+	fs := flag.NewFlagSet("grant-credits", flag.ExitOnError)
+	userID := fs.String("user", "", "user ID")
+	amount := fs.Int("amount", 0, fmt.Sprintf("credits to add (at most %d)", services.MaxSupportCreditGrant))
+	reason := fs.String("reason", "", "why, shown on the user's credit history")
+	fs.Parse(args)
+
+	id, err := uuid.Parse(*userID)
+	if err != nil {
+		return fmt.Errorf("-user: %w", err)
+	}
+	if !c.confirm(fmt.Sprintf("Grant %d credits to %s", *amount, c.describeUser(id))) {
+		return nil
+	}
+	if err := c.support.GrantCredits(c.actor, id, *amount, *reason); err != nil {
+		return fmt.Errorf("grant credits: %w", err)
+	}
+	fmt.Printf("Granted %d credits\n", *amount)
+	return nil
+}
+
+func (c *command) revokeSessions(args []string) error {
+	fs := flag.NewFlagSet("revoke-sessions", flag.ExitOnError)
+	userID := fs.String("user", "", "user ID")
+	reason := fs.String("reason", "", "why, for the audit log")
+	fs.Parse(args)
+
+	id, err := uuid.Parse(*userID)
+	if err != nil {
+		return fmt.Errorf("-user: %w", err)
+	}
+	if !c.confirm("Sign out " + c.describeUser(id) + " everywhere") {
+		return nil
+	}
+	if err := c.support.RevokeSessions(c.actor, id, *reason); err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	fmt.Println("Sessions revoked")
+	return nil
+}
+
+func (c *command) requeueAnalysis(args []string) error {
+	fs := flag.NewFlagSet("requeue-analysis", flag.ExitOnError)
+	messageID := fs.String("message", "", "message ID")
+	reason := fs.String("reason", "", "why, for the audit log")
+	fs.Parse(args)
+
+	id, err := uuid.Parse(*messageID)
+	if err != nil {
+		return fmt.Errorf("-message: %w", err)
+	}
+	if !c.confirm("Requeue pronunciation analysis of message " + id.String()) {
+		return nil
+	}
+	if err := c.support.RequeueAnalysis(c.actor, id, *reason); err != nil {
+		return fmt.Errorf("requeue analysis: %w", err)
+	}
+	fmt.Println("Analysis queued")
+	return nil
+}
+
+func (c *command) export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	userID := fs.String("user", "", "user ID")
+	reason := fs.String("reason", "", "why, for the audit log, e.g. the data request's ticket")
+	out := fs.String("out", "", "file to write (default stdout)")
+	fs.Parse(args)
+
+	id, err := uuid.Parse(*userID)
+	if err != nil {
+		return fmt.Errorf("-user: %w", err)
+	}
+	if !c.confirm("Export all data of " + c.describeUser(id)) {
+		return nil
+	}
+	export, err := c.support.ExportUser(c.actor, id, *reason)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	if *out == "" {
+		return printJSON(os.Stdout, export)
+	}
+	// The export holds the user's conversations: keep it private to the operator
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := printJSON(f, export); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d threads to %s\n", len(export.Threads), *out)
+	return nil
+}
+
+// describeUser names the account in confirmation prompts, so the operator
+// can tell a mistyped ID from the intended user
+func (c *command) describeUser(id uuid.UUID) string {
+	view, err := c.users.GetUser(id)
+	if err != nil {
+		return id.String() + " (not found)"
+	}
+	return fmt.Sprintf("%s <%s>", id, view.User.Email)
+}
+
+// confirm asks before a change; -yes skips the prompt. Anything but y or yes
+// (including a closed stdin) declines.
+func (c *command) confirm(action string) bool {
+	if c.yes {
+		return true
+	}
+	fmt.Fprintf(os.Stderr, "%s? [y/N] ", action)
+	answer, err := c.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Fprintln(os.Stderr, "Aborted")
+	return false
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
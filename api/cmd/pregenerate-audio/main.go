@@ -0,0 +1,84 @@
+// Command pregenerate-audio synthesizes the example words of the phoneme
+// drills into the reference audio library ahead of time, so learners never
+// wait for TTS. Words already in the library are skipped. It reads the same
+// environment as the server.
+//
+//	pregenerate-audio [-phoneme θ,ð] [-rate 1.0] [-json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"ling-app/api/internal/app"
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+)
+
+func main() {
+	phonemes := flag.String("phoneme", "", "comma-separated phonemes to generate (default every drilled phoneme)")
+	rate := flag.Float64("rate", models.DefaultSpeechRate, "speaking rate of the clips, 0.5 to 1.5")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	if !services.ValidSpeechRate(*rate) {
+		log.Fatalf("-rate must be between %.1f and %.1f", models.MinSpeechRate, models.MaxSpeechRate)
+	}
+	var selected []string
+	for _, p := range strings.Split(*phonemes, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			selected = append(selected, p)
+		}
+	}
+
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Invalid configuration:")
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			log.Printf("  - %s", problem)
+		}
+		os.Exit(1)
+	}
+
+	server, err := app.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize:", err)
+	}
+	defer server.DB.ClosePool()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := server.Services.ReferenceAudio.Pregenerate(ctx, selected, *rate)
+	if err != nil && report == nil {
+		log.Fatal("Pregeneration failed: ", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		fmt.Printf("%d clips generated, %d already in the library, %d failed\n", report.Generated, report.Cached, report.Failed)
+		for _, e := range report.Errors {
+			fmt.Printf("error: %s\n", e)
+		}
+	}
+
+	if err != nil {
+		log.Fatal("Pregeneration stopped early: ", err)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,127 @@
+// Command replay-events delivers logged domain events to one subscriber, to
+// backfill a projection added after the events happened. It reads the same
+// environment as the server.
+//
+//	replay-events -consumer phoneme_stats [-user <id>] [-from 2026-01-01] [-to 2026-02-01] [-type analysis.completed] [-dry-run] [-json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/app"
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/services"
+)
+
+func main() {
+	consumer := flag.String("consumer", "", "name of the subscriber to replay into, e.g. phoneme_stats or streaks")
+	user := flag.String("user", "", "only replay this user's events")
+	from := flag.String("from", "", "only replay events at or after this time (RFC 3339 or YYYY-MM-DD, UTC)")
+	to := flag.String("to", "", "only replay events before this time (RFC 3339 or YYYY-MM-DD, UTC)")
+	types := flag.String("type", "", "comma-separated event types to replay, e.g. analysis.completed")
+	dryRun := flag.Bool("dry-run", false, "count the events without delivering them")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	if *consumer == "" {
+		log.Fatal("-consumer is required")
+	}
+	opts := services.EventReplayOptions{Consumer: *consumer, DryRun: *dryRun}
+	var err error
+	if *user != "" {
+		if opts.UserID, err = uuid.Parse(*user); err != nil {
+			log.Fatalf("-user: %v", err)
+		}
+	}
+	if opts.From, err = parseTime(*from); err != nil {
+		log.Fatalf("-from: %v", err)
+	}
+	if opts.To, err = parseTime(*to); err != nil {
+		log.Fatalf("-to: %v", err)
+	}
+	for _, name := range strings.Split(*types, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.Names = append(opts.Names, name)
+		}
+	}
+
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Invalid configuration:")
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			log.Printf("  - %s", problem)
+		}
+		os.Exit(1)
+	}
+
+	server, err := app.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize:", err)
+	}
+	defer server.DB.ClosePool()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := server.Services.EventLog.Replay(ctx, server.Services.Events, opts)
+	if err != nil && report == nil {
+		log.Fatal("Replay failed: ", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if err != nil {
+		log.Fatal("Replay stopped early: ", err)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseTime accepts an RFC 3339 time or a UTC date; empty is the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func printReport(report *services.EventReplayReport) {
+	if report.DryRun {
+		fmt.Printf("%d events would be replayed into %s (dry run)\n", report.Matched, report.Consumer)
+		return
+	}
+
+	fmt.Printf("Replayed %d of %d events into %s\n", report.Replayed, report.Matched, report.Consumer)
+	if report.Skipped > 0 {
+		fmt.Printf("%d events skipped: %s doesn't subscribe to their type\n", report.Skipped, report.Consumer)
+	}
+	if report.Failed > 0 {
+		fmt.Printf("%d events failed\n", report.Failed)
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("error: %s\n", e)
+	}
+}
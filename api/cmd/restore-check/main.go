@@ -0,0 +1,146 @@
+// Command restore-check verifies a database restored from backup: row counts
+// against the live database, foreign key and id references, and a sample of
+// users rebuilt with their recordings looked up in S3. It reads the same
+// environment as the server, where DATABASE_URL is the live database.
+//
+//	restore-check (-host restored.example.com | -url postgres://...) [-no-compare] [-users 10] [-json]
+//
+// -host reuses DATABASE_URL's credentials, which a point-in-time restore
+// keeps, so no password has to go on the command line.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/services"
+)
+
+func main() {
+	host := flag.String("host", "", "host of the restored database, with DATABASE_URL's credentials and name")
+	restoredURL := flag.String("url", os.Getenv("RESTORE_DATABASE_URL"), "URL of the restored database (instead of -host)")
+	noCompare := flag.Bool("no-compare", false, "don't compare row counts with the live database")
+	noAudio := flag.Bool("no-audio", false, "don't look up recordings in S3")
+	users := flag.Int("users", services.DefaultRestoreSampleUsers, "number of random users to rebuild")
+	maxDrift := flag.Float64("max-drift", services.DefaultRestoreMaxRowDrift, "share of a table's live rows the restored count may differ by")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	cfg := config.Load()
+
+	target := *restoredURL
+	if *host != "" {
+		u, err := url.Parse(cfg.DatabaseURL)
+		if err != nil {
+			log.Fatal("Invalid DATABASE_URL: ", err)
+		}
+		if port := u.Port(); port != "" {
+			u.Host = *host + ":" + port
+		} else {
+			u.Host = *host
+		}
+		target = u.String()
+	}
+	if target == "" {
+		log.Fatal("Set -host or -url to the restored database")
+	}
+	if target == cfg.DatabaseURL {
+		log.Fatal("The restored database is the live one; point -host or -url at the restore")
+	}
+
+	restored, err := db.New(target)
+	if err != nil {
+		log.Fatal("Failed to connect to the restored database: ", err)
+	}
+
+	var live *db.DB
+	if !*noCompare {
+		if live, err = db.New(cfg.DatabaseURL); err != nil {
+			log.Fatal("Failed to connect to the live database: ", err)
+		}
+	}
+
+	var storage client.StorageClient
+	if !*noAudio {
+		storage, err = client.NewStorageClient(
+			cfg.S3Endpoint,
+			cfg.S3AccessKey,
+			cfg.S3SecretKey,
+			cfg.S3Bucket,
+			cfg.S3Region,
+			cfg.Environment == "production",
+		)
+		if err != nil {
+			log.Fatal("Failed to create storage client: ", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := services.NewRestoreCheckService(restored, live, storage).Check(ctx, services.RestoreCheckOptions{
+		SampleUsers: *users,
+		MaxRowDrift: *maxDrift,
+	})
+	if err != nil {
+		log.Fatal("Check failed: ", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
+
+func printReport(report *services.RestoreCheckReport) {
+	fmt.Println("Row counts:")
+	for _, table := range report.Tables {
+		restored, live := "missing", ""
+		if table.Restored != nil {
+			restored = fmt.Sprint(*table.Restored)
+		}
+		if table.Live != nil {
+			live = fmt.Sprintf(" (live %d)", *table.Live)
+		}
+		mark := ""
+		if !table.OK {
+			mark = "  <- FAIL"
+		}
+		fmt.Printf("  %-28s %s%s%s\n", table.Table, restored, live, mark)
+	}
+
+	fmt.Printf("%d references with orphaned rows\n", len(report.ReferenceViolations))
+
+	fmt.Println("Users rebuilt:")
+	for _, user := range report.Users {
+		fmt.Printf("  %s: %d threads, %d messages, %d/%d recordings found\n",
+			user.UserID, user.Threads, user.Messages, user.AudioObjects-len(user.MissingAudio), user.AudioObjects)
+	}
+
+	if report.Passed() {
+		fmt.Println("PASS: the restore is complete and consistent")
+		return
+	}
+	for _, failure := range report.Failures {
+		fmt.Printf("FAIL: %s\n", failure)
+	}
+}
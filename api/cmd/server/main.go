@@ -4,226 +4,58 @@ import (
 	"context"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
-	"ling-app/api/internal/client"
+	"ling-app/api/internal/app"
 	"ling-app/api/internal/config"
-	"ling-app/api/internal/db"
-	"ling-app/api/internal/handlers"
-	"ling-app/api/internal/middleware"
-	"ling-app/api/internal/models"
-	"ling-app/api/internal/repository"
-	"ling-app/api/internal/services"
-	"ling-app/api/internal/services/auth"
-
-	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		log.Fatalf("Configuration error: %v", err)
-	}
-
-	// Connect to database
-	database, err := db.New(cfg.DatabaseURL)
-	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
-		os.Exit(1)
+	// Show what the server is running with, then refuse to start on bad config
+	log.Printf("Effective configuration:")
+	for _, setting := range cfg.EffectiveSettings() {
+		log.Printf("  %s", setting)
 	}
-
-	// Run migrations (order matters for foreign keys)
-	if err := database.RunMigrations(
-		&models.User{},
-		&models.Session{},
-		&models.Thread{},
-		&models.Message{},
-		&models.Subscription{},
-		&models.Credits{},
-		&models.CreditTransaction{},
-		&models.PhonemeStats{},
-		&models.PhonemeSubstitution{},
-	); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Invalid configuration. Fix these environment variables (or .env) and restart:")
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			log.Printf("  - %s", problem)
+		}
 		os.Exit(1)
 	}
 
-	// Initialize repositories
-	userRepo := repository.NewUserRepository()
-	sessionRepo := repository.NewSessionRepository()
-	creditsRepo := repository.NewCreditsRepository()
-	creditTxRepo := repository.NewCreditTransactionRepository()
-	threadRepo := repository.NewThreadRepository()
-	messageRepo := repository.NewMessageRepository()
-
-	// Initialize auth services
-	authService := auth.NewAuthService(database, userRepo, sessionRepo, cfg.SessionMaxAge)
-	oauthService := services.NewOAuthService(cfg)
-
-	// Initialize storage client
-	isProduction := cfg.Environment == "production"
-	storageClient, err := client.NewStorageClient(
-		cfg.S3Endpoint,
-		cfg.S3AccessKey,
-		cfg.S3SecretKey,
-		cfg.S3Bucket,
-		cfg.S3Region,
-		isProduction,
-	)
+	// Wire database, clients, services, and routes
+	server, err := app.New(cfg)
 	if err != nil {
-		log.Fatal("Failed to initialize storage client:", err)
-		os.Exit(1)
-	}
-
-	// Ensure bucket exists (only in local dev - Terraform creates bucket in production)
-	if !isProduction {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-		if err := storageClient.EnsureBucketExists(ctx); err != nil {
-			log.Printf("Warning: Failed to ensure bucket exists: %v", err)
-		} else {
-			log.Printf("Storage bucket '%s' is ready", cfg.S3Bucket)
-		}
-	} else {
-		log.Printf("Production mode: using S3 bucket '%s' in region '%s'", cfg.S3Bucket, cfg.S3Region)
+		log.Fatal("Failed to initialize server:", err)
 	}
 
-	// Initialize AI clients
-	openAIClient := client.NewOpenAIClient(cfg.OpenAIAPIKey)
-
-	// STT: use ML service if configured, otherwise OpenAI Whisper
-	var whisperClient client.WhisperClient
-	if cfg.STTServiceURL != "" {
-		log.Printf("Using ML service for STT: %s", cfg.STTServiceURL)
-		whisperClient = client.NewMLWhisperClient(cfg.STTServiceURL)
-	} else {
-		log.Println("Using OpenAI Whisper API")
-		whisperClient = client.NewOpenAIWhisperClient(cfg.OpenAIAPIKey)
-	}
-
-	// TTS: use ML service if configured, otherwise OpenAI
-	var ttsClient client.TTSClient
-	if cfg.TTSServiceURL != "" {
-		log.Printf("Using ML service for TTS: %s", cfg.TTSServiceURL)
-		ttsClient = client.NewMLTTSClient(cfg.TTSServiceURL)
-	} else {
-		log.Println("Using OpenAI TTS API")
-		ttsClient = client.NewOpenAITTSClient(cfg.OpenAIAPIKey)
-	}
-
-	// Initialize ML client for pronunciation analysis
-	mlClient := client.NewMLClient(cfg.MLServiceURL, time.Duration(cfg.MLServiceTimeout)*time.Second)
-
-	// Initialize phoneme stats repositories and service
-	phonemeStatsRepo := repository.NewPhonemeStatsRepository()
-	phonemeSubsRepo := repository.NewPhonemeSubstitutionRepository()
-	phonemeStatsService := services.NewPhonemeStatsService(database, phonemeStatsRepo, phonemeSubsRepo)
-
-	// Initialize pronunciation worker
-	pronunciationWorker := services.NewPronunciationWorker(database, mlClient, storageClient, phonemeStatsService)
-
-	// Initialize conversation service
-	conversationService := services.NewConversationService(
-		database.DB,
-		messageRepo,
-		threadRepo,
-		whisperClient,
-		openAIClient,
-		ttsClient,
-		storageClient,
-		pronunciationWorker,
-		cfg.MaxAudioFileSize,
-	)
-
-	// Initialize credits and subscription services
-	creditsService := services.NewCreditsService(database, creditsRepo, creditTxRepo)
-	subscriptionRepo := repository.NewSubscriptionRepository()
-	stripeService := services.NewStripeService(cfg, database, subscriptionRepo, creditsService)
-
-	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService, oauthService, creditsService, cfg)
-	threadHandler := handlers.NewThreadHandler(database.DB, threadRepo, messageRepo, conversationService, openAIClient, creditsService)
-	audioHandler := handlers.NewAudioHandler(storageClient)
-	subscriptionHandler := handlers.NewSubscriptionHandler(stripeService, creditsService)
-	phonemeStatsHandler := handlers.NewPhonemeStatsHandler(phonemeStatsService)
-
-	// Set Gin mode
-	gin.SetMode(cfg.GinMode)
-
-	// Initialize router
-	router := gin.Default()
-
-	// Apply middleware
-	router.Use(middleware.CORS(cfg.CORSAllowedOrigins))
-
-	// Health check endpoint
-	router.GET("/health", handlers.HealthCheck)
-
-	// API routes
-	api := router.Group("/api")
-	{
-		// Public routes (no auth required)
-		api.GET("/prompts/random", handlers.GetRandomPrompt)
-
-		// Auth routes
-		auth := api.Group("/auth")
-		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/logout", authHandler.Logout)
-			// /me requires authentication
-			auth.GET("/me", middleware.RequireAuth(authService), authHandler.GetMe)
-			// OAuth routes
-			auth.GET("/google", authHandler.GoogleLogin)
-			auth.GET("/google/callback", authHandler.GoogleCallback)
-			auth.GET("/github", authHandler.GitHubLogin)
-			auth.GET("/github/callback", authHandler.GitHubCallback)
+	// Start server
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run()
+	}()
+
+	// Wait for a shutdown signal or a server error
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			log.Fatal("Failed to start server:", err)
 		}
-
-		// Protected routes (require authentication)
-		protected := api.Group("")
-		protected.Use(middleware.RequireAuth(authService))
-		{
-			// Threads
-			protected.GET("/threads", threadHandler.GetThreads)
-			protected.GET("/threads/archived", threadHandler.GetArchivedThreads)
-			protected.POST("/threads", threadHandler.CreateThread)
-			protected.GET("/threads/:id", threadHandler.GetThread)
-			protected.PATCH("/threads/:id", threadHandler.UpdateThread)
-			protected.DELETE("/threads/:id", threadHandler.DeleteThread)
-			protected.POST("/threads/:id/archive", threadHandler.ArchiveThread)
-			protected.POST("/threads/:id/unarchive", threadHandler.UnarchiveThread)
-			// Voice message - with credit enforcement (1 credit per voice submission)
-			protected.POST("/threads/:id/messages/audio",
-				middleware.RequireCredits(creditsService, models.CreditCostPerMessage),
-				threadHandler.SendAudioMessage)
-
-			// Audio - use *key to capture full path including slashes
-			protected.GET("/audio/*key", audioHandler.GetAudio)
-
-			// Subscription and Credits
-			protected.GET("/subscription", subscriptionHandler.GetSubscriptionStatus)
-			protected.POST("/subscription/checkout", subscriptionHandler.CreateCheckoutSession)
-			protected.POST("/subscription/portal", subscriptionHandler.CreatePortalSession)
-			protected.GET("/credits", subscriptionHandler.GetCreditsBalance)
-			protected.GET("/credits/history", subscriptionHandler.GetCreditHistory)
-
-			// Pronunciation stats
-			protected.GET("/pronunciation/stats", phonemeStatsHandler.GetStats)
+	case <-quit:
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Graceful shutdown failed: %v", err)
 		}
-
-		// Stripe webhook (no auth - verified by Stripe signature)
-		api.POST("/webhooks/stripe", subscriptionHandler.HandleStripeWebhook)
-	}
-
-	// Start server
-	addr := cfg.Host + ":" + cfg.Port
-	log.Printf("Server starting on %s", addr)
-	if err := router.Run(addr); err != nil {
-		log.Fatal("Failed to start server:", err)
-		os.Exit(1)
 	}
 }
@@ -0,0 +1,93 @@
+// Command stripe-sync reconciles local subscriptions and credits with Stripe
+// after missed webhooks. It reads the same environment as the server.
+//
+//	stripe-sync [-since 72h] [-all] [-dry-run] [-json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"ling-app/api/internal/app"
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/services"
+)
+
+func main() {
+	since := flag.Duration("since", services.DefaultStripeSyncWindow, "check customers with billing events in this window (at most 720h)")
+	all := flag.Bool("all", false, "check every customer with a Stripe subscription")
+	dryRun := flag.Bool("dry-run", false, "report discrepancies without fixing them")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Invalid configuration:")
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			log.Printf("  - %s", problem)
+		}
+		os.Exit(1)
+	}
+	if cfg.StripeSecretKey == "" {
+		log.Fatal("STRIPE_SECRET_KEY is not set")
+	}
+
+	server, err := app.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize:", err)
+	}
+	defer server.DB.ClosePool()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := server.Services.StripeSync.Sync(ctx, services.StripeSyncOptions{
+		Since:  time.Now().Add(-*since),
+		All:    *all,
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		log.Fatal("Sync failed: ", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		printReport(report)
+	}
+
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printReport(report *services.StripeSyncReport) {
+	action := "fixed"
+	if report.DryRun {
+		action = "found (dry run)"
+	}
+
+	fmt.Printf("Checked %d customers\n", report.Checked)
+	for _, d := range report.Discrepancies {
+		fmt.Printf("  user %s (%s): %s %q -> %q\n", d.UserID, d.CustomerID, d.Field, d.Local, d.Stripe)
+	}
+	fmt.Printf("%d discrepancies %s\n", len(report.Discrepancies), action)
+	if len(report.Unknown) > 0 {
+		fmt.Printf("%d Stripe customers have no local subscription: %s\n", len(report.Unknown), strings.Join(report.Unknown, ", "))
+	}
+	for _, e := range report.Errors {
+		fmt.Printf("error: %s\n", e)
+	}
+}
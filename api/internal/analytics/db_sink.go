@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"context"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// DBSink stores events in the analytics_events table.
+type DBSink struct {
+	exec repository.Executor
+	repo repository.AnalyticsEventRepository
+}
+
+// NewDBSink creates a sink that writes events through repo.
+func NewDBSink(exec repository.Executor, repo repository.AnalyticsEventRepository) *DBSink {
+	return &DBSink{exec: exec, repo: repo}
+}
+
+// Name identifies the sink in logs.
+func (s *DBSink) Name() string {
+	return "db"
+}
+
+// Write inserts the batch in a single statement.
+func (s *DBSink) Write(ctx context.Context, events []Event) error {
+	rows := make([]models.AnalyticsEvent, len(events))
+	for i, event := range events {
+		rows[i] = models.AnalyticsEvent{
+			UserID:     event.UserID,
+			Event:      event.Name,
+			Properties: models.JSONMap(event.Properties),
+			CreatedAt:  event.Timestamp,
+		}
+	}
+	return s.repo.CreateBatch(s.exec, rows)
+}
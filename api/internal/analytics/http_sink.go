@@ -0,0 +1,124 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const segmentBatchURL = "https://api.segment.io/v1/batch"
+
+// HTTPSink exports batches to a third-party analytics API.
+type HTTPSink struct {
+	name       string
+	url        string
+	httpClient *http.Client
+	encode     func(events []Event) ([]byte, error)
+	authorize  func(req *http.Request)
+}
+
+// NewSegmentSink exports events to Segment's batch API.
+func NewSegmentSink(writeKey string) *HTTPSink {
+	return &HTTPSink{
+		name:       "segment",
+		url:        segmentBatchURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		encode:     encodeSegment,
+		authorize: func(req *http.Request) {
+			req.SetBasicAuth(writeKey, "")
+		},
+	}
+}
+
+// NewPostHogSink exports events to PostHog's batch API at host
+// (e.g. https://us.i.posthog.com).
+func NewPostHogSink(host, apiKey string) *HTTPSink {
+	return &HTTPSink{
+		name:       "posthog",
+		url:        strings.TrimRight(host, "/") + "/batch/",
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		encode: func(events []Event) ([]byte, error) {
+			return encodePostHog(apiKey, events)
+		},
+		authorize: func(*http.Request) {},
+	}
+}
+
+// Name identifies the sink in logs.
+func (s *HTTPSink) Name() string {
+	return s.name
+}
+
+// Write posts the batch as a single request.
+func (s *HTTPSink) Write(ctx context.Context, events []Event) error {
+	body, err := s.encode(events)
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.authorize(req)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %d: %s", s.name, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+type segmentEvent struct {
+	Type       string         `json:"type"`
+	UserID     string         `json:"userId"`
+	Event      string         `json:"event"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Timestamp  string         `json:"timestamp"`
+}
+
+func encodeSegment(events []Event) ([]byte, error) {
+	batch := make([]segmentEvent, len(events))
+	for i, event := range events {
+		batch[i] = segmentEvent{
+			Type:       "track",
+			UserID:     event.UserID.String(),
+			Event:      event.Name,
+			Properties: event.Properties,
+			Timestamp:  event.Timestamp.UTC().Format(time.RFC3339Nano),
+		}
+	}
+	return json.Marshal(map[string]any{"batch": batch})
+}
+
+type postHogEvent struct {
+	Event      string         `json:"event"`
+	DistinctID string         `json:"distinct_id"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Timestamp  string         `json:"timestamp"`
+}
+
+func encodePostHog(apiKey string, events []Event) ([]byte, error) {
+	batch := make([]postHogEvent, len(events))
+	for i, event := range events {
+		batch[i] = postHogEvent{
+			Event:      event.Name,
+			DistinctID: event.UserID.String(),
+			Properties: event.Properties,
+			Timestamp:  event.Timestamp.UTC().Format(time.RFC3339Nano),
+		}
+	}
+	return json.Marshal(map[string]any{"api_key": apiKey, "batch": batch})
+}
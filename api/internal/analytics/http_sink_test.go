@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostHogSink_Write(t *testing.T) {
+	userID := uuid.New()
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/batch/", r.URL.Path)
+		raw, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(raw, &body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewPostHogSink(server.URL+"/", "phc_test")
+	err := sink.Write(context.Background(), []Event{{
+		UserID:     userID,
+		Name:       EventSubscriptionUpgraded,
+		Properties: map[string]any{"tier": "pro"},
+		Timestamp:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "phc_test", body["api_key"])
+	batch := body["batch"].([]any)
+	require.Len(t, batch, 1)
+	event := batch[0].(map[string]any)
+	assert.Equal(t, EventSubscriptionUpgraded, event["event"])
+	assert.Equal(t, userID.String(), event["distinct_id"])
+	assert.Equal(t, "2025-01-02T03:04:05Z", event["timestamp"])
+}
+
+func TestSegmentSink_Write(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "write-key", user)
+
+		var body struct {
+			Batch []segmentEvent `json:"batch"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.Batch, 1)
+		assert.Equal(t, "track", body.Batch[0].Type)
+		assert.Equal(t, EventUserRegistered, body.Batch[0].Event)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewSegmentSink("write-key")
+	sink.url = server.URL
+
+	err := sink.Write(context.Background(), []Event{{UserID: uuid.New(), Name: EventUserRegistered, Timestamp: time.Now()}})
+	require.NoError(t, err)
+}
+
+func TestHTTPSink_WriteErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	sink := NewPostHogSink(server.URL, "bad")
+	err := sink.Write(context.Background(), []Event{{UserID: uuid.New(), Name: EventFirstMessage}})
+	assert.ErrorContains(t, err, "401")
+}
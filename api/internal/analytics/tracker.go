@@ -0,0 +1,195 @@
+// Package analytics records product events from key flows (registration,
+// first message, upgrades, cancellations, analysis results) and ships them to
+// pluggable sinks.
+//
+// Track never blocks the caller: events go into a bounded buffer and are
+// written to every sink in batches by a background goroutine. When the buffer
+// is full, new events are dropped and counted rather than slowing requests.
+package analytics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event names tracked by the API.
+const (
+	EventUserRegistered        = "user_registered"
+	EventFirstMessage          = "first_message"
+	EventSubscriptionUpgraded  = "subscription_upgraded"
+	EventSubscriptionCancelled = "subscription_cancelled"
+	EventAnalysisCompleted     = "pronunciation_analysis_completed"
+)
+
+// Tracker records analytics events.
+type Tracker interface {
+	Track(ctx context.Context, userID uuid.UUID, event string, props map[string]any)
+}
+
+// Event is a single tracked event.
+type Event struct {
+	UserID     uuid.UUID
+	Name       string
+	Properties map[string]any
+	Timestamp  time.Time
+}
+
+// Sink receives batches of events. Write errors are logged; the batch is not retried.
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, events []Event) error
+}
+
+// Nop is a Tracker that discards every event.
+type Nop struct{}
+
+// Track discards the event.
+func (Nop) Track(context.Context, uuid.UUID, string, map[string]any) {}
+
+// Config controls buffering and batching.
+type Config struct {
+	BufferSize    int           // Events held in memory before new ones are dropped
+	BatchSize     int           // Events per sink write
+	FlushInterval time.Duration // Max time an event waits before being written
+}
+
+// Pipeline is a batched, non-blocking Tracker that fans events out to sinks.
+type Pipeline struct {
+	cfg     Config
+	sinks   []Sink
+	events  chan Event
+	dropped atomic.Int64
+	wg      sync.WaitGroup
+
+	now func() time.Time
+}
+
+// NewPipeline creates a pipeline writing to sinks. Call Start to begin flushing.
+func NewPipeline(cfg Config, sinks ...Sink) *Pipeline {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+
+	return &Pipeline{
+		cfg:    cfg,
+		sinks:  sinks,
+		events: make(chan Event, cfg.BufferSize),
+		now:    time.Now,
+	}
+}
+
+// Track buffers an event for the sinks. It never blocks; if the buffer is
+// full the event is dropped.
+func (p *Pipeline) Track(ctx context.Context, userID uuid.UUID, event string, props map[string]any) {
+	if len(p.sinks) == 0 {
+		return
+	}
+
+	select {
+	case p.events <- Event{UserID: userID, Name: event, Properties: props, Timestamp: p.now()}:
+	default:
+		if p.dropped.Add(1)%100 == 1 {
+			log.Printf("[Analytics] Buffer full, dropping events (%d dropped so far)", p.dropped.Load())
+		}
+	}
+}
+
+// Dropped returns how many events were dropped because the buffer was full.
+func (p *Pipeline) Dropped() int64 {
+	return p.dropped.Load()
+}
+
+// Start launches the flusher. When ctx is cancelled it writes out whatever is
+// still buffered and stops; Wait blocks until that final flush is done.
+func (p *Pipeline) Start(ctx context.Context) {
+	if len(p.sinks) == 0 {
+		return
+	}
+
+	names := make([]string, len(p.sinks))
+	for i, sink := range p.sinks {
+		names[i] = sink.Name()
+	}
+	log.Printf("[Analytics] Starting pipeline with sinks %v", names)
+
+	p.wg.Add(1)
+	go p.run(ctx)
+}
+
+// Wait blocks until the flusher has stopped.
+func (p *Pipeline) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Pipeline) run(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, p.cfg.BatchSize)
+	for {
+		select {
+		case event := <-p.events:
+			batch = append(batch, event)
+			if len(batch) >= p.cfg.BatchSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ctx.Done():
+			p.drain(batch)
+			return
+		}
+	}
+}
+
+// drain writes the pending batch plus anything left in the buffer
+func (p *Pipeline) drain(batch []Event) {
+	for {
+		select {
+		case event := <-p.events:
+			batch = append(batch, event)
+			if len(batch) >= p.cfg.BatchSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				p.flush(batch)
+			}
+			return
+		}
+	}
+}
+
+// flush writes a batch to every sink. Sinks get their own copy of the slice
+// header, so the caller may reuse the batch buffer afterwards.
+func (p *Pipeline) flush(batch []Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events := make([]Event, len(batch))
+	copy(events, batch)
+
+	for _, sink := range p.sinks {
+		if err := sink.Write(ctx, events); err != nil {
+			log.Printf("[Analytics] Sink %s failed to write %d events: %v", sink.Name(), len(events), err)
+		}
+	}
+}
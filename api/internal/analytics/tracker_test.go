@@ -0,0 +1,112 @@
+package analytics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps every batch it is given.
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]Event
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Write(ctx context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *recordingSink) events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var all []Event
+	for _, batch := range s.batches {
+		all = append(all, batch...)
+	}
+	return all
+}
+
+func TestPipeline_BatchesBySize(t *testing.T) {
+	sink := &recordingSink{}
+	p := NewPipeline(Config{BatchSize: 2, FlushInterval: time.Hour}, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+
+	userID := uuid.New()
+	for i := 0; i < 4; i++ {
+		p.Track(context.Background(), userID, EventFirstMessage, map[string]any{"i": i})
+	}
+
+	require.Eventually(t, func() bool { return len(sink.events()) == 4 }, time.Second, 5*time.Millisecond)
+	cancel()
+	p.Wait()
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	assert.Len(t, sink.batches, 2)
+	assert.Equal(t, userID, sink.batches[0][0].UserID)
+	assert.Equal(t, EventFirstMessage, sink.batches[0][0].Name)
+}
+
+func TestPipeline_FlushesOnInterval(t *testing.T) {
+	sink := &recordingSink{}
+	p := NewPipeline(Config{BatchSize: 100, FlushInterval: 10 * time.Millisecond}, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	p.Track(context.Background(), uuid.New(), EventUserRegistered, nil)
+
+	require.Eventually(t, func() bool { return len(sink.events()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestPipeline_DrainsOnShutdown(t *testing.T) {
+	sink := &recordingSink{}
+	p := NewPipeline(Config{BatchSize: 100, FlushInterval: time.Hour}, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+
+	for i := 0; i < 3; i++ {
+		p.Track(context.Background(), uuid.New(), EventAnalysisCompleted, nil)
+	}
+	cancel()
+	p.Wait()
+
+	assert.Len(t, sink.events(), 3)
+}
+
+func TestPipeline_DropsWhenBufferFull(t *testing.T) {
+	sink := &recordingSink{}
+	p := NewPipeline(Config{BufferSize: 2}, sink)
+
+	// Not started, so nothing drains the buffer; Track must not block
+	for i := 0; i < 5; i++ {
+		p.Track(context.Background(), uuid.New(), EventFirstMessage, nil)
+	}
+
+	assert.Equal(t, int64(3), p.Dropped())
+}
+
+func TestPipeline_NoSinks(t *testing.T) {
+	p := NewPipeline(Config{BufferSize: 1})
+	p.Start(context.Background())
+
+	p.Track(context.Background(), uuid.New(), EventFirstMessage, nil)
+	p.Track(context.Background(), uuid.New(), EventFirstMessage, nil)
+
+	assert.Equal(t, int64(0), p.Dropped())
+	p.Wait()
+}
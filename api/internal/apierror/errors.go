@@ -3,6 +3,8 @@ package apierror
 import (
 	"net/http"
 
+	"ling-app/api/internal/services"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +25,7 @@ const (
 
 	// Payment errors
 	CodeInsufficientCredits = "INSUFFICIENT_CREDITS"
+	CodeTierLimitReached    = "TIER_LIMIT_REACHED"
 
 	// Not found errors
 	CodeThreadNotFound   = "THREAD_NOT_FOUND"
@@ -33,6 +36,14 @@ const (
 	CodeExternalServiceError  = "EXTERNAL_SERVICE_ERROR"
 	CodeAudioProcessingFailed = "AUDIO_PROCESSING_FAILED"
 
+	// Voice turn stage errors
+	CodeAudioUploadFailed   = "AUDIO_UPLOAD_FAILED"
+	CodeAudioPresignFailed  = "AUDIO_PRESIGN_FAILED"
+	CodeTranscriptionFailed = "TRANSCRIPTION_FAILED"
+	CodeReplyFailed         = "REPLY_FAILED"
+	CodeSpeechFailed        = "SPEECH_FAILED"
+	CodeMessageSaveFailed   = "MESSAGE_SAVE_FAILED"
+
 	// Internal errors
 	CodeInternalError = "INTERNAL_ERROR"
 )
@@ -146,6 +157,20 @@ func InsufficientCredits(creditsNeeded int) *AppError {
 	}
 }
 
+func TierLimitReached(resource, tier string, limit int, count int64) *AppError {
+	return &AppError{
+		Code:    CodeTierLimitReached,
+		Message: "You've reached the " + resource + " limit for your plan",
+		Status:  http.StatusForbidden,
+		Details: map[string]interface{}{
+			"resource": resource,
+			"tier":     tier,
+			"limit":    limit,
+			"count":    count,
+		},
+	}
+}
+
 // Not found errors
 
 func ThreadNotFound() *AppError {
@@ -194,6 +219,60 @@ func AudioProcessingFailed() *AppError {
 	}
 }
 
+// Voice turn stage errors. A failed turn isn't charged, so the messages
+// invite the learner to try again.
+
+func AudioUploadFailed() *AppError {
+	return &AppError{
+		Code:    CodeAudioUploadFailed,
+		Message: "Your recording couldn't be uploaded. Check your connection and try again.",
+		Status:  http.StatusServiceUnavailable,
+	}
+}
+
+func AudioPresignFailed() *AppError {
+	return &AppError{
+		Code:    CodeAudioPresignFailed,
+		Message: "Your recording couldn't be prepared for listening. Please try again.",
+		Status:  http.StatusServiceUnavailable,
+	}
+}
+
+func TranscriptionFailed() *AppError {
+	return &AppError{
+		Code:    CodeTranscriptionFailed,
+		Message: "Your recording couldn't be understood. Try again, speaking close to the microphone.",
+		Status:  http.StatusBadGateway,
+	}
+}
+
+func ReplyFailed() *AppError {
+	return &AppError{
+		Code:    CodeReplyFailed,
+		Message: "Your tutor couldn't come up with a reply. Please send your message again.",
+		Status:  http.StatusBadGateway,
+	}
+}
+
+// SpeechFailed is a warning rather than an error response: a reply that
+// couldn't be spoken is still saved and returned
+func SpeechFailed() *AppError {
+	return &AppError{
+		Code:    CodeSpeechFailed,
+		Message: "This reply couldn't be spoken, but you can still read it.",
+		Status:  http.StatusOK,
+		Details: map[string]string{"stage": services.StageTTS},
+	}
+}
+
+func MessageSaveFailed() *AppError {
+	return &AppError{
+		Code:    CodeMessageSaveFailed,
+		Message: "Your message couldn't be saved. Please try again.",
+		Status:  http.StatusInternalServerError,
+	}
+}
+
 // Internal errors
 
 func InternalError(message string) *AppError {
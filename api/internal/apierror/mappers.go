@@ -36,6 +36,38 @@ func FromCreditsError(err error, creditsNeeded int) *AppError {
 	}
 }
 
+// FromUsageError maps tier limit errors to AppError
+func FromUsageError(err error) *AppError {
+	var limitErr *services.TierLimitError
+	if errors.As(err, &limitErr) {
+		return TierLimitReached(limitErr.Resource, string(limitErr.Tier), limitErr.Limit, limitErr.Count)
+	}
+	return InternalError("")
+}
+
+// FromVoiceTurnError maps the stage a voice turn failed at to AppError,
+// with the stage in the details
+func FromVoiceTurnError(err error) *AppError {
+	var appErr *AppError
+	stage := services.TurnStage(err)
+	switch stage {
+	case services.StageUpload:
+		appErr = AudioUploadFailed()
+	case services.StagePresign:
+		appErr = AudioPresignFailed()
+	case services.StageTranscribe:
+		appErr = TranscriptionFailed()
+	case services.StageGenerate:
+		appErr = ReplyFailed()
+	case services.StagePersist:
+		appErr = MessageSaveFailed()
+	default:
+		return AudioProcessingFailed()
+	}
+	appErr.Details = map[string]string{"stage": stage}
+	return appErr
+}
+
 // FromRepositoryError maps repository errors to AppError
 func FromRepositoryError(err error, resource string) *AppError {
 	if errors.Is(err, repository.ErrNotFound) {
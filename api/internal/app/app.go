@@ -0,0 +1,814 @@
+// Package app is the composition root for the API server.
+// It wires config, database, clients, repositories, services, and handlers
+// into a runnable Server so that main.go and tests share the same wiring.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"ling-app/api/internal/analytics"
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/crypt"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/handlers"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+	"ling-app/api/internal/services/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repositories groups every repository used by the app.
+type Repositories struct {
+	User         repository.UserRepository
+	Session      repository.SessionRepository
+	Credits      repository.CreditsRepository
+	CreditTx     repository.CreditTransactionRepository
+	CreditHolds  repository.CreditReservationRepository
+	Disputes     repository.CreditDisputeRepository
+	Thread       repository.ThreadRepository
+	Message      repository.MessageRepository
+	PhonemeStats repository.PhonemeStatsRepository
+	PhonemeSubs  repository.PhonemeSubstitutionRepository
+	Subscription repository.SubscriptionRepository
+	Notification repository.NotificationRepository
+	Analytics    repository.AnalyticsEventRepository
+	Safety       repository.SafetyIncidentRepository
+	Settings     repository.UserSettingsRepository
+	Audit        repository.AuditLogRepository
+	Badge        repository.StatsBadgeRepository
+	ReadState    repository.ThreadReadStateRepository
+	Runtime      repository.RuntimeSettingRepository
+	Signups      repository.SignupSignalRepository
+	Invites      repository.InviteCodeRepository
+	Waitlist     repository.WaitlistRepository
+	Profiles     repository.LearnerProfileRepository
+	Chunks       repository.MessageChunkRepository
+	Warehouse    repository.WarehouseRepository
+	Snapshots    repository.PhonemeStatsSnapshotRepository
+	Sessions     repository.PracticeSessionRepository
+	FeatureUsage repository.FeatureUsageRepository
+	Streaks      repository.StreakRepository
+	DomainEvents repository.DomainEventRepository
+	Guests       repository.GuestRepository
+	ThreadShares repository.ThreadShareRepository
+	Reference    repository.ReferenceAudioRepository
+	Emails       repository.EmailDeliveryRepository
+	Verification repository.EmailVerificationRepository
+	Merges       repository.AccountMergeRepository
+	AudioKeys    repository.AudioKeyMigrationRepository
+	Comparisons  repository.AnalysisComparisonRepository
+	Idempotency  repository.IdempotencyKeyRepository
+	Deletions    repository.StorageDeletionRepository
+
+	// ContentEncryption is nil unless CONTENT_ENCRYPTION_KEY is set
+	ContentEncryption repository.ContentEncryptionRepository
+	// ThreadCache is nil unless THREAD_CACHE_TTL is set
+	ThreadCache *repository.ThreadCache
+}
+
+// Services groups the business services used by handlers and middleware.
+type Services struct {
+	Auth                *auth.AuthService
+	OAuth               *services.OAuthService
+	Credits             *services.CreditsService
+	CreditAudit         *services.CreditAuditService
+	Usage               *services.UsageService
+	Stripe              *services.StripeService
+	StripeSync          *services.StripeSyncService
+	Pricing             *services.StripePricingService
+	PhonemeStats        *services.PhonemeStatsService
+	PronunciationWorker *services.PronunciationWorker
+	MLCallbackSigner    *services.MLCallbackSigner
+	Conversation        *services.ConversationService
+	MLLoadMonitor       *services.MLLoadMonitor
+	MLShadow            *services.MLShadow
+	Notification        *services.NotificationService
+	Goal                *services.GoalService
+	SubscriptionGrace   *services.SubscriptionGraceWorker
+	Dunning             *services.DunningService
+	FreeCreditRefresh   *services.FreeCreditRefreshWorker
+	ReservationExpiry   *services.ReservationExpiryWorker
+	Settings            *services.SettingsService
+	AudioRetention      *services.AudioRetentionWorker
+	FeatureUsage        *services.FeatureUsageService
+	Streaks             *services.StreakService
+	Events              *events.Bus
+	EventLog            *services.EventLogService
+	Audit               *services.AuditService
+	AnkiExport          *services.AnkiExportService
+	StatsBadge          *services.StatsBadgeService
+	Report              *services.PronunciationReportService
+	ThreadTitles        *services.ThreadTitleService
+	RuntimeSettings     *services.RuntimeSettingsService
+	SignupGuard         *services.SignupGuard
+	AdminUsers          *services.AdminUserService
+	AccountMerges       *services.AccountMergeService
+	Invites             *services.InviteService
+	Guests              *services.GuestService
+	LearnerProfiles     *services.LearnerProfileService
+	LongForm            *services.LongFormService
+	Corrections         *services.TranscriptCorrectionService
+	AnalysisRetries     *services.PronunciationRetryService
+	PracticeSessions    *services.PracticeSessionService
+	ThreadShares        *services.ThreadShareService
+	LiveUpdates         *services.LiveUpdateHub
+	Continuations       *services.ThreadContinuationService
+	Home                *services.HomeService
+	Bootstrap           *services.BootstrapService
+	LLM                 *services.LLMDispatcher
+	WarehouseExport     *services.WarehouseExportService
+	Support             *services.SupportService
+	EmailVerification   *services.EmailVerificationService
+	AccountDeletion     *services.AccountDeletionService
+	Idempotency         *services.IdempotencyService
+	PronunciationJobs   *services.PronunciationMonitorService
+	ReferenceAudio      *services.ReferenceAudioService
+	NotificationEmail   *services.NotificationEmailWorker // nil unless SMTP_HOST is set
+	ContentEncryption   *services.ContentEncryptionWorker // nil unless CONTENT_ENCRYPTION_KEY is set
+	AudioKeyMigration   *services.AudioKeyMigrationWorker // nil unless AUDIO_KEY_MIGRATION_INTERVAL is set
+	Analytics           analytics.Tracker
+}
+
+// Handlers groups the HTTP handlers mounted on the router.
+type Handlers struct {
+	Auth         *handlers.AuthHandler
+	Thread       *handlers.ThreadHandler
+	Audio        *handlers.AudioHandler
+	Subscription *handlers.SubscriptionHandler
+	CreditAudit  *handlers.CreditAuditHandler
+	Usage        *handlers.UsageHandler
+	Settings     *handlers.SettingsHandler
+	PhonemeStats *handlers.PhonemeStatsHandler
+	Notification *handlers.NotificationHandler
+	Jobs         *handlers.JobsHandler
+	MLCallback   *handlers.MLCallbackHandler
+	Practice     *handlers.PracticeHandler
+	Badge        *handlers.BadgeHandler
+	Report       *handlers.ReportHandler
+	Runtime      *handlers.RuntimeSettingsHandler
+	Admin        *handlers.AdminHandler
+	StripeSync   *handlers.StripeSyncHandler
+	Invite       *handlers.InviteHandler
+	Memory       *handlers.LearnerProfileHandler
+	Warehouse    *handlers.WarehouseHandler
+	FeatureUsage *handlers.FeatureUsageHandler
+	MLShadow     *handlers.MLShadowHandler
+	Sessions     *handlers.PracticeSessionHandler
+	ThreadShares *handlers.ThreadShareHandler
+	LiveUpdates  *handlers.LiveUpdatesHandler
+	Home         *handlers.HomeHandler
+	Bootstrap    *handlers.BootstrapHandler
+}
+
+// Server is a fully wired API server.
+type Server struct {
+	Config       *config.Config
+	DB           *db.DB
+	Clients      *Clients
+	Jobs         *jobs.Queue
+	Analytics    *analytics.Pipeline
+	Repositories *Repositories
+	Services     *Services
+	Handlers     *Handlers
+	Router       *gin.Engine
+
+	httpServer     *http.Server
+	stopBackground context.CancelFunc
+}
+
+// New connects to the database, runs migrations, builds the real external
+// clients, and wires the server.
+func New(cfg *config.Config) (*Server, error) {
+	database, err := db.New(cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := database.RunMigrations(models.All()...); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	if cfg.RepositoryBackend == "pgx" {
+		if err := database.OpenPool(context.Background(), cfg.DatabaseURL); err != nil {
+			return nil, err
+		}
+	}
+
+	clients, err := NewClients(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithDependencies(cfg, database, clients), nil
+}
+
+// NewWithDependencies wires the server around an existing database and set of
+// clients. Tests use this to run the full app against fakes.
+func NewWithDependencies(cfg *config.Config, database *db.DB, clients *Clients) *Server {
+	s := &Server{
+		Config:  cfg,
+		DB:      database,
+		Clients: clients,
+		Jobs: jobs.NewQueue(jobs.Config{
+			Workers:        cfg.JobWorkers,
+			PriorityWeight: cfg.JobPriorityWeight,
+			MaxWait:        time.Duration(cfg.JobMaxWaitSeconds) * time.Second,
+			MaxDepth:       cfg.JobMaxDepth,
+		}),
+	}
+
+	s.Repositories = newRepositories(cfg, database)
+	s.Analytics = newAnalytics(cfg, database, s.Repositories)
+	s.Services = newServices(cfg, database, clients, s.Repositories, s.Jobs, s.Analytics)
+	s.Handlers = newHandlers(cfg, database, clients, s.Repositories, s.Services, s.Jobs)
+	s.Router = newRouter(cfg, clients, s.Services, s.Handlers)
+
+	s.httpServer = &http.Server{
+		Addr:    cfg.Host + ":" + cfg.Port,
+		Handler: s.Router,
+	}
+	// Shared thread streams and live update sockets never go idle on their own
+	s.httpServer.RegisterOnShutdown(s.Services.ThreadShares.CloseAll)
+	s.httpServer.RegisterOnShutdown(s.Services.LiveUpdates.CloseAll)
+
+	return s
+}
+
+// newRepositories builds the GORM repositories, swapping in the sqlc/pgx
+// implementations for the hot tables when the database has a pgx pool, and
+// wrapping the content tables with encryption when a master key is set.
+func newRepositories(cfg *config.Config, database *db.DB) *Repositories {
+	repos := &Repositories{
+		User:         repository.NewUserRepository(),
+		Session:      repository.NewSessionRepository(),
+		Credits:      repository.NewCreditsRepository(),
+		CreditTx:     repository.NewCreditTransactionRepository(),
+		CreditHolds:  repository.NewCreditReservationRepository(),
+		Disputes:     repository.NewCreditDisputeRepository(),
+		Thread:       repository.NewThreadRepository(),
+		Message:      repository.NewMessageRepository(),
+		PhonemeStats: repository.NewPhonemeStatsRepository(),
+		PhonemeSubs:  repository.NewPhonemeSubstitutionRepository(),
+		Subscription: repository.NewSubscriptionRepository(),
+		Notification: repository.NewNotificationRepository(),
+		Analytics:    repository.NewAnalyticsEventRepository(),
+		Safety:       repository.NewSafetyIncidentRepository(),
+		Settings:     repository.NewUserSettingsRepository(),
+		Audit:        repository.NewAuditLogRepository(),
+		Badge:        repository.NewStatsBadgeRepository(),
+		ReadState:    repository.NewThreadReadStateRepository(),
+		Runtime:      repository.NewRuntimeSettingRepository(),
+		Signups:      repository.NewSignupSignalRepository(),
+		Invites:      repository.NewInviteCodeRepository(),
+		Waitlist:     repository.NewWaitlistRepository(),
+		Profiles:     repository.NewLearnerProfileRepository(),
+		Chunks:       repository.NewMessageChunkRepository(),
+		Warehouse:    repository.NewWarehouseRepository(),
+		Snapshots:    repository.NewPhonemeStatsSnapshotRepository(),
+		Sessions:     repository.NewPracticeSessionRepository(),
+		FeatureUsage: repository.NewFeatureUsageRepository(),
+		Streaks:      repository.NewStreakRepository(),
+		DomainEvents: repository.NewDomainEventRepository(),
+		Guests:       repository.NewGuestRepository(),
+		ThreadShares: repository.NewThreadShareRepository(),
+		Reference:    repository.NewReferenceAudioRepository(),
+		Emails:       repository.NewEmailDeliveryRepository(),
+		Verification: repository.NewEmailVerificationRepository(),
+		Merges:       repository.NewAccountMergeRepository(),
+		AudioKeys:    repository.NewAudioKeyMigrationRepository(),
+		Comparisons:  repository.NewAnalysisComparisonRepository(),
+		Idempotency:  repository.NewIdempotencyKeyRepository(),
+		Deletions:    repository.NewStorageDeletionRepository(),
+	}
+
+	if database.Pool != nil {
+		repos.Session = repository.NewPgxSessionRepository(database.Pool)
+		repos.Message = repository.NewPgxMessageRepository(database.Pool)
+	}
+
+	if cfg.ContentEncryptionKey != "" {
+		master, err := crypt.ParseKey(cfg.ContentEncryptionKey)
+		if err != nil {
+			// Config validation rejects a bad key before we get here
+			log.Fatalf("Invalid CONTENT_ENCRYPTION_KEY: %v", err)
+		}
+		cipher := repository.NewContentCipher(master)
+		repos.Message = repository.NewEncryptedMessageRepository(repos.Message, cipher)
+		repos.Thread = repository.NewEncryptedThreadRepository(repos.Thread, cipher)
+		repos.Chunks = repository.NewEncryptedMessageChunkRepository(repos.Chunks, cipher)
+		repos.ContentEncryption = repository.NewContentEncryptionRepository(cipher)
+	}
+
+	// Outside the encryption, so the cache holds what callers see
+	if cfg.ThreadCacheTTL > 0 {
+		repos.ThreadCache = repository.NewThreadCache(time.Duration(cfg.ThreadCacheTTL) * time.Second)
+		repos.Thread = repository.NewCachedThreadRepository(repos.Thread, repos.ThreadCache)
+		repos.Message = repository.NewCachedMessageRepository(repos.Message, repos.ThreadCache)
+	}
+
+	return repos
+}
+
+// newAnalytics builds the analytics pipeline from whichever sinks are configured.
+func newAnalytics(cfg *config.Config, database *db.DB, repos *Repositories) *analytics.Pipeline {
+	var sinks []analytics.Sink
+	if cfg.AnalyticsDBEnabled {
+		sinks = append(sinks, analytics.NewDBSink(database.DB, repos.Analytics))
+	}
+	if cfg.SegmentWriteKey != "" {
+		sinks = append(sinks, analytics.NewSegmentSink(cfg.SegmentWriteKey))
+	}
+	if cfg.PostHogAPIKey != "" {
+		sinks = append(sinks, analytics.NewPostHogSink(cfg.PostHogHost, cfg.PostHogAPIKey))
+	}
+
+	return analytics.NewPipeline(analytics.Config{
+		BufferSize:    cfg.AnalyticsBufferSize,
+		BatchSize:     cfg.AnalyticsBatchSize,
+		FlushInterval: time.Duration(cfg.AnalyticsFlushInterval) * time.Second,
+	}, sinks...)
+}
+
+func newServices(cfg *config.Config, database *db.DB, clients *Clients, repos *Repositories, queue *jobs.Queue, tracker analytics.Tracker) *Services {
+	storage := recordingStorage(clients)
+	authService := auth.NewAuthService(database, repos.User, repos.Session, cfg.SessionMaxAge)
+	authService.RefreshPercent = cfg.SessionRefreshPercent
+	authService.AbsoluteMaxAge = time.Duration(cfg.SessionAbsoluteMax) * time.Second
+	oauthService := services.NewOAuthService(cfg)
+	auditService := services.NewAuditService(database, repos.Audit)
+	runtimeSettings := services.NewRuntimeSettingsService(
+		database,
+		repos.Runtime,
+		auditService,
+		time.Duration(cfg.RuntimeSettingsRefreshInterval)*time.Second,
+	)
+
+	// Every LLM feature shares one token budget
+	llm := services.NewLLMDispatcher(clients.OpenAI, cfg.OpenAITokensPerMinute)
+
+	// Domain events fan out to the subscribers registered below
+	bus := events.NewBus()
+
+	creditsService := services.NewCreditsService(database, repos.Credits, repos.CreditTx, repos.CreditHolds)
+	creditsService.Runtime = runtimeSettings
+	creditsService.Events = bus
+	signupGuard := services.NewSignupGuard(database, repos.Signups, creditsService, auditService)
+	adminUsers := services.NewAdminUserService(database, repos.User, repos.Credits, repos.Signups)
+	accountMerges := services.NewAccountMergeService(
+		database,
+		repos.User,
+		repos.Session,
+		repos.Credits,
+		repos.CreditTx,
+		repos.Subscription,
+		repos.Merges,
+		repos.Audit,
+	)
+	invites := services.NewInviteService(database, repos.Invites, repos.Waitlist, auditService, cfg.InviteOnly)
+	guests := services.NewGuestService(
+		database,
+		repos.User,
+		repos.Guests,
+		repos.Thread,
+		creditsService,
+		storage,
+		cfg.GuestMode,
+		cfg.GuestMessageLimit,
+		time.Duration(cfg.GuestPurgeInterval)*time.Second,
+	)
+	guests.Runtime = runtimeSettings
+	phonemeStatsService := services.NewPhonemeStatsService(database, repos.PhonemeStats, repos.PhonemeSubs)
+	phonemeStatsService.Snapshots = repos.Snapshots
+	phonemeStatsService.Runtime = runtimeSettings
+	pronunciationWorker := services.NewPronunciationWorker(
+		database,
+		repos.Message,
+		repos.Thread,
+		clients.ML,
+		storage,
+		bus,
+		queue,
+		creditsService,
+		cfg.PronunciationMinConfidence,
+		tracker,
+	)
+	pronunciationWorker.Runtime = runtimeSettings
+	pronunciationWorker.Chunks = repos.Chunks
+	mlShadow := services.NewMLShadow(database, repos.Comparisons, clients.MLShadow, runtimeSettings)
+	if clients.MLShadow != nil {
+		pronunciationWorker.Shadow = mlShadow
+	}
+	var mlCallbackSigner *services.MLCallbackSigner
+	if cfg.MLAsyncCallbacks {
+		mlCallbackSigner = services.NewMLCallbackSigner(cfg.MLCallbackSecret, 0)
+		pronunciationWorker.CallbackURL = cfg.MLCallbackURL
+		pronunciationWorker.CallbackSigner = mlCallbackSigner
+	}
+	mlLoadMonitor := services.NewMLLoadMonitor(
+		clients.ML,
+		time.Duration(cfg.MLHealthPollInterval)*time.Second,
+		cfg.MLShedQueueDepth,
+		cfg.MLShedPaidQueueDepth,
+	)
+
+	outputSafety := services.NewOutputSafetyChecker(database, clients.Moderation, repos.Safety)
+	conversationService := services.NewConversationService(
+		database.DB,
+		repos.Message,
+		repos.Thread,
+		clients.Whisper,
+		llm,
+		clients.TTS,
+		storage,
+		pronunciationWorker,
+		creditsService,
+		outputSafety,
+		runtimeSettings,
+	)
+	learnerProfiles := services.NewLearnerProfileService(database, repos.Profiles, repos.Message, llm, queue)
+	conversationService.Memory = learnerProfiles
+	conversationService.Adaptation = services.NewAdaptationService()
+	conversationService.Normalizer = services.NewLLMTranscriptNormalizer(llm)
+	conversationService.Tones = services.NewTonePolicy()
+	conversationService.Citations = services.NewCorrectionCitations()
+	conversationService.Events = bus
+	conversationService.LowBitrateAudio = cfg.LowBitrateAudio
+	longForm := services.NewLongFormService(conversationService, repos.Chunks)
+	corrections := services.NewTranscriptCorrectionService(database, repos.Thread, repos.Message, phonemeStatsService, pronunciationWorker)
+	analysisRetries := services.NewPronunciationRetryService(database, repos.Thread, repos.Message, pronunciationWorker)
+	practiceSessions := services.NewPracticeSessionService(database, repos.Sessions, repos.Thread, repos.Message)
+	home := services.NewHomeService(database, repos.Thread, repos.Message, repos.PhonemeStats, creditsService, learnerProfiles, llm)
+	bootstrap := services.NewBootstrapService(database, repos.Thread, creditsService)
+	bootstrap.Threads = repos.ThreadCache
+
+	creditAuditService := services.NewCreditAuditService(database, repos.CreditTx, repos.Disputes, repos.Message, repos.Thread)
+	usageService := services.NewUsageService(database, repos.Subscription, repos.Thread, repos.Message)
+	usageService.Runtime = runtimeSettings
+	conversationService.ThreadCaps = usageService
+	continuations := services.NewThreadContinuationService(database, repos.Thread, repos.Message, llm)
+	notificationService := services.NewNotificationService(database, repos.Notification)
+	stripeService := services.NewStripeService(cfg, database, repos.Subscription, creditsService, notificationService, tracker)
+	stripeService.Runtime = runtimeSettings
+	stripeService.Events = bus
+	stripeSync := services.NewStripeSyncService(stripeService, services.NewStripeAPIBilling(), auditService)
+	pricing := services.NewStripePricingService(cfg, services.NewStripeAPICatalog())
+	pricing.Runtime = runtimeSettings
+	subscriptionGrace := services.NewSubscriptionGraceWorker(
+		database,
+		repos.Subscription,
+		creditsService,
+		notificationService,
+		queue,
+		time.Duration(cfg.SubscriptionGraceSweepInterval)*time.Second,
+	)
+	subscriptionGrace.Runtime = runtimeSettings
+	dunning := services.NewDunningService(
+		database,
+		repos.Subscription,
+		repos.User,
+		notificationService,
+		clients.Email,
+		queue,
+		time.Duration(cfg.DunningSweepInterval)*time.Second,
+		cfg.FrontendURL,
+	)
+	stripeService.Dunning = dunning
+	freeCreditRefresh := services.NewFreeCreditRefreshWorker(
+		database,
+		repos.Credits,
+		repos.CreditTx,
+		repos.CreditHolds,
+		time.Duration(cfg.FreeCreditRefreshInterval)*time.Second,
+	)
+	reservationExpiry := services.NewReservationExpiryWorker(
+		database,
+		repos.Credits,
+		repos.CreditTx,
+		repos.CreditHolds,
+		time.Duration(cfg.ReservationSweepInterval)*time.Second,
+	)
+	settingsService := services.NewSettingsService(database, repos.Settings)
+	var notificationEmail *services.NotificationEmailWorker
+	if clients.Email != nil {
+		notificationService.EmailDelivery = true
+		notificationEmail = services.NewNotificationEmailWorker(
+			database,
+			repos.Notification,
+			repos.Emails,
+			repos.User,
+			settingsService,
+			clients.Email,
+			services.NotificationEmailPolicy{
+				MaxPerDay:   cfg.EmailMaxPerDay,
+				BatchWindow: time.Duration(cfg.EmailBatchWindow) * time.Second,
+			},
+			time.Duration(cfg.EmailSweepInterval)*time.Second,
+			cfg.FrontendURL,
+		)
+	}
+	var contentEncryption *services.ContentEncryptionWorker
+	if repos.ContentEncryption != nil {
+		settingsService.ContentEncryption = true
+		contentEncryption = services.NewContentEncryptionWorker(database, repos.ContentEncryption, 0)
+	}
+	var audioKeyMigration *services.AudioKeyMigrationWorker
+	if cfg.AudioKeyMigrationInterval > 0 {
+		// Reads keys as given: the fallback would hide a failed copy
+		audioKeyMigration = services.NewAudioKeyMigrationWorker(
+			database,
+			repos.AudioKeys,
+			clients.Storage,
+			time.Duration(cfg.AudioKeyMigrationInterval)*time.Second,
+			max(services.DefaultAudioKeyMigrationGrace, time.Duration(cfg.AudioURLExpiry)*time.Second),
+		)
+	}
+	conversationService.Settings = settingsService
+	home.Timezones = settingsService
+	audioRetention := services.NewAudioRetentionWorker(
+		database,
+		repos.Message,
+		storage,
+		time.Duration(cfg.AudioRetentionSweepInterval)*time.Second,
+	)
+	audioRetention.Chunks = repos.Chunks
+	featureUsage := services.NewFeatureUsageService(
+		database,
+		repos.FeatureUsage,
+		time.Duration(cfg.FeatureUsageRollupInterval)*time.Second,
+	)
+	referenceAudio := services.NewReferenceAudioService(database, repos.Reference, clients.TTS, storage, ttsVoice(cfg))
+	ankiExport := services.NewAnkiExportService(
+		database,
+		repos.Message,
+		repos.PhonemeStats,
+		repos.PhonemeSubs,
+		clients.TTS,
+		storage,
+		notificationService,
+		queue,
+	)
+	ankiExport.ReferenceAudio = referenceAudio
+	statsBadge := services.NewStatsBadgeService(database, repos.Badge, repos.Message, repos.PhonemeStats)
+	statsBadge.Timezones = settingsService
+	threadTitles := services.NewThreadTitleService(database, repos.Thread, llm, queue)
+	threadTitles.Events = bus
+	report := services.NewPronunciationReportService(database, repos.Message, repos.PhonemeStats, repos.PhonemeSubs, storage)
+	goalService := services.NewGoalService(database, repos.Thread, repos.Message, llm, creditsService, notificationService)
+	streaks := services.NewStreakService(database, repos.Streaks, notificationService)
+	streaks.Timezones = settingsService
+	eventLog := services.NewEventLogService(database, repos.DomainEvents)
+	threadShares := services.NewThreadShareService(database, repos.ThreadShares, repos.Thread, repos.Message)
+	liveUpdates := services.NewLiveUpdateHub()
+
+	phonemeStatsService.Subscribe(bus)
+	notificationService.Subscribe(bus)
+	streaks.Subscribe(bus)
+	threadShares.Subscribe(bus)
+	liveUpdates.Subscribe(bus)
+	eventLog.Subscribe(bus)
+	events.NewWebhook(cfg.EventsWebhookURL, cfg.EventsWebhookSecret, queue).Subscribe(bus)
+
+	warehouseExport := services.NewWarehouseExportService(
+		database,
+		repos.Warehouse,
+		clients.Warehouse,
+		cfg.WarehousePrefix,
+		cfg.WarehouseHashKey,
+		cfg.WarehouseExportHour,
+		auditService,
+	)
+	support := services.NewSupportService(
+		database,
+		adminUsers,
+		repos.User,
+		repos.Session,
+		repos.Thread,
+		repos.Message,
+		repos.CreditTx,
+		creditsService,
+		pronunciationWorker,
+		auditService,
+	)
+	support.Subscriptions = repos.Subscription
+	pronunciationJobs := services.NewPronunciationMonitorService(database, repos.Message, queue)
+	emailVerification := services.NewEmailVerificationService(database, repos.Verification, repos.User, clients.Email, queue, cfg.EmailVerifyURL)
+	emailVerification.Required = cfg.RequireVerifiedEmail
+	accountDeletion := services.NewAccountDeletionService(
+		database,
+		repos.User,
+		repos.Guests,
+		repos.Deletions,
+		stripeService,
+		storage,
+		queue,
+		time.Duration(cfg.StorageDeletionSweepInterval)*time.Second,
+	)
+
+	return &Services{
+		Auth:                authService,
+		OAuth:               oauthService,
+		Credits:             creditsService,
+		CreditAudit:         creditAuditService,
+		Usage:               usageService,
+		Stripe:              stripeService,
+		StripeSync:          stripeSync,
+		Pricing:             pricing,
+		PhonemeStats:        phonemeStatsService,
+		PronunciationWorker: pronunciationWorker,
+		MLCallbackSigner:    mlCallbackSigner,
+		Conversation:        conversationService,
+		MLLoadMonitor:       mlLoadMonitor,
+		MLShadow:            mlShadow,
+		Notification:        notificationService,
+		Goal:                goalService,
+		SubscriptionGrace:   subscriptionGrace,
+		Dunning:             dunning,
+		FreeCreditRefresh:   freeCreditRefresh,
+		ReservationExpiry:   reservationExpiry,
+		Settings:            settingsService,
+		AudioRetention:      audioRetention,
+		FeatureUsage:        featureUsage,
+		Streaks:             streaks,
+		Events:              bus,
+		EventLog:            eventLog,
+		Audit:               auditService,
+		AnkiExport:          ankiExport,
+		StatsBadge:          statsBadge,
+		Report:              report,
+		ThreadTitles:        threadTitles,
+		RuntimeSettings:     runtimeSettings,
+		SignupGuard:         signupGuard,
+		AdminUsers:          adminUsers,
+		AccountMerges:       accountMerges,
+		Invites:             invites,
+		Guests:              guests,
+		LearnerProfiles:     learnerProfiles,
+		LongForm:            longForm,
+		Corrections:         corrections,
+		AnalysisRetries:     analysisRetries,
+		PracticeSessions:    practiceSessions,
+		ThreadShares:        threadShares,
+		LiveUpdates:         liveUpdates,
+		Continuations:       continuations,
+		Home:                home,
+		Bootstrap:           bootstrap,
+		LLM:                 llm,
+		WarehouseExport:     warehouseExport,
+		Support:             support,
+		PronunciationJobs:   pronunciationJobs,
+		EmailVerification:   emailVerification,
+		AccountDeletion:     accountDeletion,
+		Idempotency:         services.NewIdempotencyService(database, repos.Idempotency),
+		ReferenceAudio:      referenceAudio,
+		NotificationEmail:   notificationEmail,
+		ContentEncryption:   contentEncryption,
+		AudioKeyMigration:   audioKeyMigration,
+		Analytics:           tracker,
+	}
+}
+
+// recordingStorage reads recordings still requested by their legacy key from
+// the key they were migrated to, once the legacy object is gone
+func recordingStorage(clients *Clients) client.StorageClient {
+	return client.NewFallbackStorage(clients.Storage, func(key string) (string, bool) {
+		migrated, _, ok := models.MigratedAudioKey(key)
+		return migrated, ok
+	})
+}
+
+// ttsVoice names the TTS backend and voice that reference clips are spoken in.
+// Fake tones get their own name so they never stand in for real clips.
+func ttsVoice(cfg *config.Config) string {
+	if cfg.FakeML {
+		return "fake"
+	}
+	if cfg.TTSServiceURL != "" {
+		return "chatterbox"
+	}
+	return "openai:alloy"
+}
+
+func newHandlers(cfg *config.Config, database *db.DB, clients *Clients, repos *Repositories, svc *Services, queue *jobs.Queue) *Handlers {
+	authHandler := handlers.NewAuthHandler(svc.Auth, svc.OAuth, svc.Credits, cfg, svc.Analytics)
+	authHandler.SignupGuard = svc.SignupGuard
+	authHandler.Invites = svc.Invites
+	authHandler.Guests = svc.Guests
+	authHandler.Verification = svc.EmailVerification
+	authHandler.Accounts = svc.AccountDeletion
+	threadHandler := handlers.NewThreadHandler(database.DB, repos.Thread, repos.Message, repos.ReadState, svc.Conversation, svc.LLM, svc.Credits, svc.Goal, svc.Usage, svc.Analytics, svc.ThreadTitles)
+	threadHandler.Memory = svc.LearnerProfiles
+	threadHandler.LongForm = svc.LongForm
+	threadHandler.Corrections = svc.Corrections
+	threadHandler.Retries = svc.AnalysisRetries
+	threadHandler.FeatureUsage = svc.FeatureUsage
+	threadHandler.Guests = svc.Guests
+	threadHandler.Continuations = svc.Continuations
+	practiceHandler := handlers.NewPracticeHandler(svc.AnkiExport)
+	practiceHandler.FeatureUsage = svc.FeatureUsage
+	reportHandler := handlers.NewReportHandler(svc.Report)
+	reportHandler.FeatureUsage = svc.FeatureUsage
+	sessionsHandler := handlers.NewPracticeSessionHandler(svc.PracticeSessions)
+	sessionsHandler.FeatureUsage = svc.FeatureUsage
+	jobsHandler := handlers.NewJobsHandler(queue)
+	jobsHandler.LLM = svc.LLM
+	phonemeStatsHandler := handlers.NewPhonemeStatsHandler(svc.PhonemeStats)
+	phonemeStatsHandler.Examples = svc.ReferenceAudio
+	audioHandler := handlers.NewAudioHandler(database.DB, repos.Thread, repos.Message, recordingStorage(clients), cfg.AudioProxyMode)
+	audioHandler.URLExpiry = time.Duration(cfg.AudioURLExpiry) * time.Second
+	subscriptionHandler := handlers.NewSubscriptionHandler(svc.Stripe, svc.Credits)
+	subscriptionHandler.Pricing = svc.Pricing
+	adminHandler := handlers.NewAdminHandler(svc.AdminUsers, svc.SignupGuard)
+	adminHandler.Merges = svc.AccountMerges
+	adminHandler.Support = svc.Support
+	adminHandler.Jobs = svc.PronunciationJobs
+
+	return &Handlers{
+		Auth:         authHandler,
+		Thread:       threadHandler,
+		Audio:        audioHandler,
+		Subscription: subscriptionHandler,
+		CreditAudit:  handlers.NewCreditAuditHandler(svc.CreditAudit),
+		Usage:        handlers.NewUsageHandler(svc.Usage),
+		Settings:     handlers.NewSettingsHandler(svc.Settings),
+		PhonemeStats: phonemeStatsHandler,
+		Notification: handlers.NewNotificationHandler(svc.Notification),
+		Jobs:         jobsHandler,
+		MLCallback:   handlers.NewMLCallbackHandler(svc.PronunciationWorker, svc.MLCallbackSigner),
+		Practice:     practiceHandler,
+		Badge:        handlers.NewBadgeHandler(svc.StatsBadge),
+		Report:       reportHandler,
+		Runtime:      handlers.NewRuntimeSettingsHandler(svc.RuntimeSettings),
+		Admin:        adminHandler,
+		StripeSync:   handlers.NewStripeSyncHandler(svc.StripeSync),
+		Invite:       handlers.NewInviteHandler(svc.Invites),
+		Memory:       handlers.NewLearnerProfileHandler(svc.LearnerProfiles),
+		Warehouse:    handlers.NewWarehouseHandler(svc.WarehouseExport),
+		FeatureUsage: handlers.NewFeatureUsageHandler(svc.FeatureUsage),
+		MLShadow:     handlers.NewMLShadowHandler(svc.MLShadow),
+		Sessions:     sessionsHandler,
+		ThreadShares: handlers.NewThreadShareHandler(svc.ThreadShares),
+		LiveUpdates:  handlers.NewLiveUpdatesHandler(svc.LiveUpdates, cfg.CORSAllowedOrigins),
+		Home:         handlers.NewHomeHandler(svc.Home),
+		Bootstrap:    handlers.NewBootstrapHandler(svc.Bootstrap),
+	}
+}
+
+// Run starts background workers and serves HTTP, blocking until the server
+// stops. It returns nil after a graceful Shutdown.
+func (s *Server) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopBackground = cancel
+	s.Jobs.Start(ctx)
+	s.Analytics.Start(ctx)
+	go s.Services.PronunciationWorker.RecoverPending(ctx, time.Now())
+	go s.Services.RuntimeSettings.Start(ctx)
+	go s.Services.MLLoadMonitor.Start(ctx)
+	go s.Services.SubscriptionGrace.Start(ctx)
+	go s.Services.Dunning.Start(ctx)
+	go s.Services.FreeCreditRefresh.Start(ctx)
+	go s.Services.ReservationExpiry.Start(ctx)
+	go s.Services.AudioRetention.Start(ctx)
+	go s.Services.Guests.Start(ctx)
+	go s.Services.FeatureUsage.Start(ctx)
+	go s.Services.WarehouseExport.Start(ctx)
+	go s.Services.Idempotency.Start(ctx)
+	go s.Services.AccountDeletion.Start(ctx)
+	if s.Services.NotificationEmail != nil {
+		go s.Services.NotificationEmail.Start(ctx)
+	}
+	if s.Services.ContentEncryption != nil {
+		go s.Services.ContentEncryption.Start(ctx)
+	}
+	if s.Services.AudioKeyMigration != nil {
+		go s.Services.AudioKeyMigration.Start(ctx)
+	}
+
+	log.Printf("Server starting on %s", s.httpServer.Addr)
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully stops the HTTP server, waiting for in-flight requests
+// until ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	log.Println("Server shutting down")
+	if s.stopBackground != nil {
+		s.stopBackground()
+	}
+	err := s.httpServer.Shutdown(ctx)
+	s.Analytics.Wait()
+	s.DB.ClosePool()
+	return err
+}
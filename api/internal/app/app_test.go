@@ -0,0 +1,142 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, configure ...func(*config.Config)) *Server {
+	t.Helper()
+
+	cfg := &config.Config{
+		Host:               "127.0.0.1",
+		Port:               "0",
+		GinMode:            "test",
+		CORSAllowedOrigins: []string{"http://localhost:5173"},
+	}
+	for _, fn := range configure {
+		fn(cfg)
+	}
+	clients := &Clients{
+		Storage: new(mocks.MockStorageClient),
+		OpenAI:  new(mocks.MockOpenAIClient),
+		Whisper: new(mocks.MockWhisperClient),
+		TTS:     new(mocks.MockTTSClient),
+		ML:      new(mocks.MockMLClient),
+	}
+
+	// No queries run during wiring, so an unconnected DB is enough here.
+	return NewWithDependencies(cfg, &db.DB{}, clients)
+}
+
+func TestNewWithDependencies_WiresEverything(t *testing.T) {
+	s := newTestServer(t)
+
+	require.NotNil(t, s.Router)
+	assert.NotNil(t, s.Repositories.Thread)
+	assert.NotNil(t, s.Services.Conversation)
+	assert.NotNil(t, s.Handlers.Audio)
+}
+
+func TestServer_HealthCheck(t *testing.T) {
+	s := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestServer_ProtectedRouteRequiresAuth(t *testing.T) {
+	s := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/threads", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestServer_VersionedRoutesUseEnvelope(t *testing.T) {
+	s := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/prompts/random", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var ok struct {
+		Data struct {
+			Prompt string `json:"prompt"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ok))
+	assert.NotEmpty(t, ok.Data.Prompt)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/threads", nil)
+	s.Router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var failed struct {
+		Error struct {
+			Status int    `json:"status"`
+			Code   string `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &failed))
+	assert.Equal(t, http.StatusUnauthorized, failed.Error.Status)
+	assert.NotEmpty(t, failed.Error.Code)
+}
+
+func TestServer_LegacyRoutesDeprecation(t *testing.T) {
+	deprecatedAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC)
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.LegacyAPIDeprecatedAt = &deprecatedAt
+		cfg.LegacyAPISunsetAt = &sunsetAt
+	})
+
+	w := httptest.NewRecorder()
+	s.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/prompts/random", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/prompts/random>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	s.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/prompts/random", nil))
+
+	assert.Empty(t, w.Header().Get("Deprecation"), "versioned routes aren't deprecated")
+}
+
+func TestServer_LegacyRoutesDisabled(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.LegacyAPIDisabled = true
+	})
+
+	w := httptest.NewRecorder()
+	s.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/threads", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, `</api/v1/threads>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	s.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/threads", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	s.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/nope", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/client/fake"
+	"ling-app/api/internal/config"
+
+	"google.golang.org/grpc"
+)
+
+// Clients groups the external service clients. Any of them can be replaced
+// with a fake before calling NewWithDependencies.
+type Clients struct {
+	Storage client.StorageClient
+	OpenAI  client.OpenAIClient
+	Whisper client.WhisperClient
+	TTS     client.TTSClient
+	ML      client.MLClient
+
+	// MLShadow runs the model under evaluation; nil when ML_SHADOW_URL is unset.
+	MLShadow client.MLClient
+
+	// ServiceSigner signs ML service requests and verifies its callbacks;
+	// nil when internal service authentication is disabled.
+	ServiceSigner *client.ServiceSigner
+
+	// Moderation is nil when output moderation is disabled; replies are
+	// then screened by the word filter alone.
+	Moderation client.ModerationClient
+
+	// Warehouse receives the nightly Parquet export; nil when
+	// WAREHOUSE_PREFIX is unset.
+	Warehouse client.StorageClient
+
+	// Email sends transactional mail; nil when SMTP_HOST is unset.
+	Email client.EmailClient
+}
+
+// NewClients builds the real external clients from config.
+func NewClients(cfg *config.Config) (*Clients, error) {
+	// Initialize storage client
+	isProduction := cfg.Environment == "production"
+	storageClient, err := client.NewStorageClient(
+		cfg.S3Endpoint,
+		cfg.S3AccessKey,
+		cfg.S3SecretKey,
+		cfg.S3Bucket,
+		cfg.S3Region,
+		isProduction,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage client: %w", err)
+	}
+
+	// Ensure bucket exists (only in local dev - Terraform creates bucket in production)
+	if !isProduction {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := storageClient.EnsureBucketExists(ctx); err != nil {
+			log.Printf("Warning: Failed to ensure bucket exists: %v", err)
+		} else {
+			log.Printf("Storage bucket '%s' is ready", cfg.S3Bucket)
+		}
+	} else {
+		log.Printf("Production mode: using S3 bucket '%s' in region '%s'", cfg.S3Bucket, cfg.S3Region)
+	}
+
+	// Requests to the ML service are signed with the internal service secret
+	serviceSigner := client.NewServiceSigner(cfg.InternalServiceSecret, cfg.InternalServicePreviousSecrets)
+	if serviceSigner == nil {
+		log.Println("Warning: INTERNAL_SERVICE_SECRET not set; ML service requests are unsigned")
+	}
+
+	// With the gRPC transport every ML service call shares one connection
+	var mlConn *grpc.ClientConn
+	if cfg.MLTransport == "grpc" && !cfg.FakeML {
+		tlsConfig, err := client.LoadMLTLSConfig(cfg.MLGRPCCertFile, cfg.MLGRPCKeyFile, cfg.MLGRPCCAFile)
+		if err != nil {
+			return nil, err
+		}
+		mlConn, err = client.NewMLConn(cfg.MLGRPCAddr, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Using gRPC for ML service calls: %s", cfg.MLGRPCAddr)
+	}
+
+	// STT: use ML service if configured, otherwise OpenAI Whisper
+	var whisperClient client.WhisperClient
+	if cfg.FakeML {
+		log.Println("Using fake STT, TTS and pronunciation analysis (FAKE_ML)")
+		whisperClient = fake.NewWhisperClient()
+	} else if cfg.STTServiceURL != "" && mlConn != nil {
+		whisperClient = client.NewGRPCWhisperClient(mlConn)
+	} else if cfg.STTServiceURL != "" {
+		log.Printf("Using ML service for STT: %s", cfg.STTServiceURL)
+		whisperClient = client.NewMLWhisperClient(cfg.STTServiceURL, serviceSigner)
+	} else {
+		log.Println("Using OpenAI Whisper API")
+		whisperClient = client.NewOpenAIWhisperClient(cfg.OpenAIAPIKey)
+	}
+
+	// TTS: use ML service if configured, otherwise OpenAI
+	var ttsClient client.TTSClient
+	if cfg.FakeML {
+		ttsClient = fake.NewTTSClient()
+	} else if cfg.TTSServiceURL != "" && mlConn != nil {
+		ttsClient = client.NewGRPCTTSClient(mlConn)
+	} else if cfg.TTSServiceURL != "" {
+		log.Printf("Using ML service for TTS: %s", cfg.TTSServiceURL)
+		ttsClient = client.NewMLTTSClient(cfg.TTSServiceURL, serviceSigner)
+	} else {
+		log.Println("Using OpenAI TTS API")
+		ttsClient = client.NewOpenAITTSClient(cfg.OpenAIAPIKey)
+	}
+
+	var moderationClient client.ModerationClient
+	if cfg.OutputModerationEnabled && cfg.OpenAIAPIKey != "" {
+		moderationClient = client.NewModerationClient(cfg.OpenAIAPIKey)
+	} else {
+		log.Println("Output moderation disabled: assistant replies are screened by the word filter only")
+	}
+
+	var emailClient client.EmailClient
+	if cfg.SMTPHost != "" {
+		emailClient = client.NewSMTPEmailClient(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.EmailFrom)
+	} else {
+		log.Println("Email disabled: billing reminders are sent as in-app notifications only")
+	}
+
+	mlClient := client.NewMLClient(cfg.MLServiceURL, time.Duration(cfg.MLServiceTimeout)*time.Second, serviceSigner)
+	if mlConn != nil {
+		mlClient = client.NewGRPCMLClient(mlConn, time.Duration(cfg.MLServiceTimeout)*time.Second)
+	}
+	if cfg.FakeML {
+		mlClient = fake.NewMLClient()
+	}
+
+	var mlShadowClient client.MLClient
+	if cfg.MLShadowURL != "" {
+		log.Printf("Sending sampled pronunciation analyses to the shadow ML service: %s", cfg.MLShadowURL)
+		mlShadowClient = client.NewMLClient(cfg.MLShadowURL, time.Duration(cfg.MLServiceTimeout)*time.Second, serviceSigner)
+	}
+
+	// Warehouse export: same bucket as audio unless WAREHOUSE_BUCKET is set
+	var warehouseClient client.StorageClient
+	if cfg.WarehousePrefix != "" {
+		warehouseClient = storageClient
+		if cfg.WarehouseBucket != "" && cfg.WarehouseBucket != cfg.S3Bucket {
+			warehouseClient, err = client.NewStorageClient(
+				cfg.S3Endpoint,
+				cfg.S3AccessKey,
+				cfg.S3SecretKey,
+				cfg.WarehouseBucket,
+				cfg.S3Region,
+				isProduction,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize warehouse storage client: %w", err)
+			}
+		}
+	}
+
+	return &Clients{
+		Storage: storageClient,
+		OpenAI:  client.NewOpenAIClient(cfg.OpenAIAPIKey),
+		Whisper: whisperClient,
+		TTS:     ttsClient,
+		ML:      mlClient,
+
+		MLShadow:      mlShadowClient,
+		Moderation:    moderationClient,
+		ServiceSigner: serviceSigner,
+		Warehouse:     warehouseClient,
+		Email:         emailClient,
+	}, nil
+}
@@ -0,0 +1,271 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/handlers"
+	"ling-app/api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API route prefixes. A breaking change to payloads gets a new version
+// prefix; older versions stay mounted until they're deprecated and retired.
+const (
+	legacyAPIPrefix = "/api"
+	apiV1Prefix     = "/api/v1"
+)
+
+// newRouter builds the Gin engine and mounts every route.
+func newRouter(cfg *config.Config, clients *Clients, svc *Services, h *Handlers) *gin.Engine {
+	// Set Gin mode
+	gin.SetMode(cfg.GinMode)
+
+	// Initialize router
+	router := gin.Default()
+
+	// Apply middleware
+	router.Use(middleware.CORS(cfg.CORSAllowedOrigins))
+	router.Use(middleware.Gzip())
+	router.Use(middleware.SessionCookies(cfg.Environment == "production"))
+
+	// Health check endpoint
+	router.GET("/health", handlers.HealthCheck)
+	router.GET("/health/jobs", h.Jobs.GetStats)
+
+	// API routes. /api/v1 wraps every JSON response in an envelope; the
+	// unversioned /api routes serve the same handlers with bare bodies until
+	// they're retired.
+	v1 := router.Group(apiV1Prefix, middleware.EnvelopeResponses())
+	registerAPIRoutes(v1, svc, h)
+
+	api := router.Group(legacyAPIPrefix)
+	if cfg.LegacyAPIDisabled {
+		router.NoRoute(legacyAPIGone)
+	} else {
+		legacy := api.Group("")
+		if cfg.LegacyAPIDeprecatedAt != nil || cfg.LegacyAPISunsetAt != nil {
+			legacy.Use(middleware.Deprecated(middleware.Deprecation{
+				Since:     cfg.LegacyAPIDeprecatedAt,
+				Sunset:    cfg.LegacyAPISunsetAt,
+				Successor: legacyAPISuccessor,
+			}))
+		}
+		registerAPIRoutes(legacy, svc, h)
+	}
+
+	// Server-to-server endpoints whose URLs are configured in other systems
+	// stay unversioned
+	{
+		// Stripe webhook (no auth - verified by Stripe signature)
+		api.POST("/webhooks/stripe", h.Subscription.HandleStripeWebhook)
+
+		// Internal service routes (no user auth - verified by the internal
+		// service signature, then the per-job callback token)
+		internal := api.Group("/internal")
+		internal.Use(middleware.RequireServiceSignature(clients.ServiceSigner, "ml-service", svc.Audit))
+		{
+			if svc.MLCallbackSigner != nil {
+				internal.POST("/ml/callbacks", h.MLCallback.HandlePronunciationCallback)
+			}
+		}
+	}
+
+	return router
+}
+
+// registerAPIRoutes mounts the client-facing API on group
+func registerAPIRoutes(api *gin.RouterGroup, svc *Services, h *Handlers) {
+	// Public routes (no auth required)
+	api.GET("/prompts/random", handlers.GetRandomPrompt)
+	api.GET("/public/badge/:token", h.Badge.GetPublicBadge)
+	api.GET("/public/shares/:token", h.ThreadShares.StreamShare)
+	api.POST("/waitlist", h.Invite.JoinWaitlist)
+
+	// Auth routes
+	auth := api.Group("/auth")
+	{
+		auth.POST("/register", h.Auth.Register)
+		auth.GET("/signup-options", h.Invite.GetSignupOptions)
+		auth.POST("/login", h.Auth.Login)
+		auth.POST("/logout", h.Auth.Logout)
+		auth.POST("/guest", h.Auth.StartGuest)
+		// /me and /sessions require authentication
+		auth.GET("/me", middleware.RequireAuth(svc.Auth), h.Auth.GetMe)
+		auth.DELETE("/me", middleware.RequireAuth(svc.Auth), h.Auth.DeleteAccount)
+		auth.GET("/sessions", middleware.RequireAuth(svc.Auth), h.Auth.ListSessions)
+		auth.DELETE("/sessions", middleware.RequireAuth(svc.Auth), h.Auth.LogoutEverywhere)
+		auth.DELETE("/sessions/:id", middleware.RequireAuth(svc.Auth), h.Auth.RevokeSession)
+		// Email verification for password accounts
+		auth.GET("/verify", h.Auth.VerifyEmail)
+		auth.POST("/verify/resend", middleware.RequireAuth(svc.Auth), middleware.RejectGuests(), h.Auth.ResendVerification)
+		// OAuth routes
+		auth.GET("/google", h.Auth.GoogleLogin)
+		auth.GET("/google/callback", h.Auth.GoogleCallback)
+		auth.GET("/github", h.Auth.GitHubLogin)
+		auth.GET("/github/callback", h.Auth.GitHubCallback)
+	}
+
+	// Protected routes (require authentication)
+	protected := api.Group("")
+	protected.Use(middleware.RequireAuth(svc.Auth))
+	{
+		// App start-up and home screen
+		protected.GET("/bootstrap", h.Bootstrap.GetBootstrap)
+		protected.GET("/home", h.Home.GetHome)
+
+		// Live updates from background jobs, instead of polling threads
+		protected.GET("/ws", h.LiveUpdates.Connect)
+
+		// Threads
+		protected.GET("/threads", h.Thread.GetThreads)
+		protected.GET("/threads/archived", h.Thread.GetArchivedThreads)
+		protected.POST("/threads", h.Thread.CreateThread)
+		protected.GET("/threads/:id", h.Thread.GetThread)
+		protected.PATCH("/threads/:id", h.Thread.UpdateThread)
+		protected.DELETE("/threads/:id", h.Thread.DeleteThread)
+		protected.POST("/threads/:id/archive", h.Thread.ArchiveThread)
+		protected.POST("/threads/:id/unarchive", h.Thread.UnarchiveThread)
+		protected.POST("/threads/:id/read", h.Thread.MarkThreadRead)
+		protected.POST("/threads/:id/continue", h.Thread.ContinueThread)
+		protected.PATCH("/threads/:id/messages/:messageId", h.Thread.CorrectTranscript)
+		protected.GET("/threads/:id/messages/:messageId/analysis", h.Thread.GetMessageAnalysis)
+		protected.GET("/threads/:id/messages/:messageId/text", h.Thread.RevealMessageText)
+		protected.GET("/threads/:id/messages/:messageId/audio/manifest", h.Audio.GetAudioManifest)
+		// Typed message - for when the user can't speak; no pronunciation analysis to shed
+		protected.POST("/threads/:id/messages",
+			middleware.RequireCredits(svc.Credits, svc.RuntimeSettings.CreditCostPerTextMessage),
+			h.Thread.SendTextMessage)
+		// Voice message - with load shedding and credit enforcement (1 credit per voice submission).
+		// Retries with the same Idempotency-Key get the original turn back.
+		protected.POST("/threads/:id/messages/audio",
+			middleware.Idempotent(svc.Idempotency),
+			middleware.ShedLoad(svc.MLLoadMonitor, svc.Stripe),
+			middleware.RequireCredits(svc.Credits, svc.RuntimeSettings.CreditCostPerMessage),
+			h.Thread.SendAudioMessage)
+		protected.POST("/threads/:id/messages/audio/stream",
+			middleware.Idempotent(svc.Idempotency),
+			middleware.ShedLoad(svc.MLLoadMonitor, svc.Stripe),
+			middleware.RequireCredits(svc.Credits, svc.RuntimeSettings.CreditCostPerMessage),
+			h.Thread.StreamAudioMessage)
+		// Long-form message - several recordings, charged per started minute
+		protected.POST("/threads/:id/messages/long-form",
+			middleware.RejectGuests(),
+			middleware.ShedLoad(svc.MLLoadMonitor, svc.Stripe),
+			middleware.RequireCredits(svc.Credits, svc.RuntimeSettings.LongFormCreditCostPerMinute),
+			h.Thread.SendLongFormMessage)
+		protected.GET("/threads/:id/messages/:messageId/chunks", h.Thread.GetMessageChunks)
+		// Failed pronunciation analyses - run again, with load shedding
+		protected.POST("/messages/:id/pronunciation/retry",
+			middleware.ShedLoad(svc.MLLoadMonitor, svc.Stripe),
+			h.Thread.RetryPronunciation)
+
+		// Read-only live links for a tutor
+		protected.GET("/threads/:id/shares", h.ThreadShares.GetShares)
+		protected.POST("/threads/:id/shares",
+			middleware.RejectGuests(),
+			middleware.RequireVerifiedEmail(svc.EmailVerification.Required),
+			h.ThreadShares.CreateShare)
+		protected.DELETE("/threads/:id/shares/:shareId", h.ThreadShares.RevokeShare)
+
+		// Timed practice sessions
+		protected.GET("/sessions", h.Sessions.GetSessions)
+		protected.POST("/sessions", h.Sessions.StartSession)
+		protected.POST("/sessions/:id/end", h.Sessions.EndSession)
+
+		// Audio - use *key to capture full path including slashes
+		protected.GET("/audio/*key", h.Audio.GetAudio)
+		protected.POST("/audio/refresh", h.Audio.RefreshAudio)
+
+		// Subscription and Credits
+		protected.GET("/subscription", h.Subscription.GetSubscriptionStatus)
+		protected.GET("/subscription/pricing", h.Subscription.GetPricing)
+		protected.POST("/subscription/checkout", middleware.RejectGuests(), h.Subscription.CreateCheckoutSession)
+		protected.POST("/subscription/portal", middleware.RejectGuests(), h.Subscription.CreatePortalSession)
+		protected.GET("/credits", h.Subscription.GetCreditsBalance)
+		protected.GET("/credits/history", h.Subscription.GetCreditHistory)
+		protected.GET("/credits/history/:transactionId", h.CreditAudit.GetTransaction)
+		protected.POST("/credits/history/:transactionId/dispute", middleware.RejectGuests(), h.CreditAudit.DisputeTransaction)
+		protected.GET("/usage", h.Usage.GetUsage)
+
+		// Settings
+		protected.GET("/settings", h.Settings.GetSettings)
+		protected.PATCH("/settings", h.Settings.UpdateSettings)
+
+		// What the assistant remembers about the learner
+		protected.GET("/profile/memory", h.Memory.GetMemory)
+		protected.PATCH("/profile/memory", h.Memory.UpdateMemory)
+
+		// Pronunciation stats
+		protected.GET("/pronunciation/stats", h.PhonemeStats.GetStats)
+		protected.GET("/pronunciation/phonemes/:phoneme/examples", h.PhonemeStats.GetPhonemeExamples)
+
+		// Practice exports
+		protected.GET("/practice/export/anki", middleware.RejectGuests(), h.Practice.ExportAnki)
+		protected.GET("/practice/export/anki/:exportId", h.Practice.DownloadAnkiExport)
+
+		// Shareable reports
+		protected.POST("/reports/pronunciation", middleware.RejectGuests(), h.Report.CreatePronunciationReport)
+
+		// Public stats badge (opt-in)
+		protected.GET("/badge", h.Badge.GetBadge)
+		protected.POST("/badge", middleware.RejectGuests(), h.Badge.EnableBadge)
+		protected.DELETE("/badge", h.Badge.RevokeBadge)
+
+		// Notifications
+		protected.GET("/notifications", h.Notification.GetNotifications)
+		protected.POST("/notifications/read-all", h.Notification.MarkAllNotificationsRead)
+		protected.POST("/notifications/:id/read", h.Notification.MarkNotificationRead)
+
+		// Admin
+		admin := protected.Group("/admin")
+		admin.Use(middleware.RequireAdmin(svc.Audit))
+		{
+			admin.GET("/runtime-settings", h.Runtime.GetRuntimeSettings)
+			admin.POST("/runtime-settings/refresh", h.Runtime.RefreshRuntimeSettings)
+			admin.PUT("/runtime-settings/:key", h.Runtime.UpdateRuntimeSetting)
+			admin.DELETE("/runtime-settings/:key", h.Runtime.ResetRuntimeSetting)
+
+			admin.GET("/users", h.Admin.ListUsers)
+			admin.GET("/users/:id", h.Admin.GetUser)
+			admin.POST("/users/:id/credits", h.Admin.AdjustCredits)
+			admin.PUT("/users/:id/subscription", h.Admin.OverrideTier)
+			admin.POST("/users/:id/signup/review", h.Admin.ReviewSignup)
+			admin.POST("/users/:id/merge", h.Admin.MergeUser)
+			admin.GET("/signups/review", h.Admin.GetPendingSignups)
+			admin.GET("/pronunciation/jobs", h.Admin.GetPronunciationJobs)
+
+			admin.POST("/stripe/sync", h.StripeSync.SyncStripe)
+			admin.POST("/warehouse/export", h.Warehouse.ExportWarehouse)
+			admin.GET("/feature-usage", h.FeatureUsage.GetFeatureUsage)
+			admin.GET("/ml-shadow", h.MLShadow.GetMLShadowReport)
+
+			admin.GET("/invites", h.Invite.ListInvites)
+			admin.POST("/invites", h.Invite.MintInvite)
+			admin.GET("/waitlist", h.Invite.ListWaitlist)
+			admin.POST("/waitlist/:id/invite", h.Invite.InviteWaitlistEntry)
+		}
+	}
+}
+
+// legacyAPISuccessor maps a legacy route to its /api/v1 equivalent
+func legacyAPISuccessor(path string) string {
+	return apiV1Prefix + strings.TrimPrefix(path, legacyAPIPrefix)
+}
+
+// legacyAPIGone answers retired legacy routes. Unknown paths elsewhere still 404.
+func legacyAPIGone(c *gin.Context) {
+	path := c.Request.URL.Path
+	if !strings.HasPrefix(path, legacyAPIPrefix+"/") || strings.HasPrefix(path, apiV1Prefix+"/") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, legacyAPISuccessor(path)))
+	c.JSON(http.StatusGone, gin.H{
+		"error": "The unversioned API has been retired; use " + apiV1Prefix,
+		"code":  "API_VERSION_RETIRED",
+	})
+}
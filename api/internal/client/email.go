@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpEmailClient implements EmailClient by sending plain-text mail over SMTP.
+type smtpEmailClient struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPEmailClient creates an email client for an SMTP server. Without a
+// username the server is used unauthenticated.
+func NewSMTPEmailClient(host string, port int, username, password, from string) EmailClient {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpEmailClient{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		auth: auth,
+		from: from,
+	}
+}
+
+// Send delivers a plain-text email to one recipient.
+func (c *smtpEmailClient) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	msg := strings.Join([]string{
+		"From: " + c.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(c.addr, c.auth, c.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
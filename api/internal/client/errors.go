@@ -1,6 +1,15 @@
 package client
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// Storage errors
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrInvalidRange   = errors.New("requested range not satisfiable")
+)
 
 // MLServiceError represents a structured error from the ML service
 type MLServiceError struct {
@@ -0,0 +1,69 @@
+package fake
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+)
+
+func TestWhisperClient_IgnoresPresignedQuery(t *testing.T) {
+	whisper := NewWhisperClient()
+	ctx := context.Background()
+
+	first, err := whisper.TranscribeFromURL(ctx, "http://localhost:9000/audio/user/a.webm?X-Amz-Signature=1", "")
+	require.NoError(t, err)
+	second, err := whisper.TranscribeFromURL(ctx, "http://localhost:9000/audio/user/a.webm?X-Amz-Signature=2", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Text, second.Text)
+	assert.Contains(t, Transcripts, first.Text)
+	assert.Greater(t, first.Duration, 0.0)
+}
+
+func TestTTSClient_SynthesizeAtRate(t *testing.T) {
+	tts := NewTTSClient().(client.RateSynthesizer)
+
+	normal, err := tts.SynthesizeAtRate(context.Background(), "one two three four five six", 1.0)
+	require.NoError(t, err)
+	slow, err := tts.SynthesizeAtRate(context.Background(), "one two three four five six", 0.5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "RIFF", string(normal.AudioBytes[:4]))
+	assert.Equal(t, "WAVE", string(normal.AudioBytes[8:12]))
+	dataSize := binary.LittleEndian.Uint32(normal.AudioBytes[40:44])
+	assert.Equal(t, int(dataSize), len(normal.AudioBytes)-44)
+	assert.InDelta(t, normal.Duration, float64(dataSize)/2/sampleRate, 0.01)
+	assert.InDelta(t, 2*normal.Duration, slow.Duration, 0.01)
+}
+
+func TestMLClient_AnalyzePronunciation(t *testing.T) {
+	ml := NewMLClient()
+	text := "I think the weather is really nice this Thursday"
+
+	first, err := ml.AnalyzePronunciation(context.Background(), "", text, "en-us", client.QualityFast)
+	require.NoError(t, err)
+	second, err := ml.AnalyzePronunciation(context.Background(), "", text, "en-us", client.QualityFast)
+	require.NoError(t, err)
+
+	require.Equal(t, "success", first.Status)
+	assert.Equal(t, first, second, "the same text is always analyzed the same way")
+
+	analysis := first.Analysis
+	assert.Equal(t, "θ", analysis.PhonemeDetails[1].Expected, `"th" is one phoneme`)
+	assert.Equal(t, len(analysis.PhonemeDetails), analysis.PhonemeCount)
+	assert.Equal(t, analysis.PhonemeCount, analysis.MatchCount+analysis.SubstitutionCount+analysis.DeletionCount)
+	assert.Positive(t, analysis.MatchCount)
+	assert.Less(t, analysis.MatchCount, analysis.PhonemeCount, "the fake learner makes mistakes")
+	assert.Equal(t, client.QualityFast, analysis.Quality)
+
+	empty, err := ml.AnalyzePronunciation(context.Background(), "", "...", "en-us", client.QualityFast)
+	require.NoError(t, err)
+	assert.Equal(t, "error", empty.Status)
+
+	assert.ErrorIs(t, ml.SubmitPronunciation(context.Background(), "", text, "en-us", client.QualityFast, "", ""), ErrCallbacksUnsupported)
+}
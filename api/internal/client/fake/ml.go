@@ -0,0 +1,120 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"ling-app/api/internal/client"
+)
+
+// digraphs and letters are a rough English spelling-to-IPA table, close
+// enough for the stats and drills to have realistic phonemes to work with
+var (
+	digraphs = map[string][]string{
+		"th": {"θ"}, "sh": {"ʃ"}, "ch": {"tʃ"}, "ng": {"ŋ"}, "ee": {"iː"}, "oo": {"uː"},
+	}
+	letters = map[rune][]string{
+		'a': {"æ"}, 'e': {"ɛ"}, 'i': {"ɪ"}, 'o': {"ɑ"}, 'u': {"ʌ"}, 'y': {"j"},
+		'c': {"k"}, 'q': {"k"}, 'x': {"k", "s"}, 'j': {"dʒ"}, 'r': {"ɹ"},
+	}
+)
+
+// mistakes are the substitutions the fake learner makes, the classic ones
+// of many learners of English
+var mistakes = map[string]string{
+	"θ": "t", "ɹ": "l", "v": "b", "ʃ": "s", "ɪ": "iː", "æ": "ɛ", "z": "s", "w": "v",
+}
+
+// ErrCallbacksUnsupported is returned by the fake ML client for async jobs;
+// it answers synchronously only
+var ErrCallbacksUnsupported = errors.New("the fake ML client can't post callbacks; unset ML_ASYNC_CALLBACKS")
+
+// mlClient makes up pronunciation analyses from the expected text alone
+type mlClient struct{}
+
+// NewMLClient creates a fake pronunciation analysis client. Some phonemes
+// come back mispronounced or dropped, always the same ones for the same text.
+func NewMLClient() client.MLClient {
+	return &mlClient{}
+}
+
+func (m *mlClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string, quality client.AnalysisQuality) (*client.PronunciationResponse, error) {
+	expected := phonemize(expectedText)
+	if len(expected) == 0 {
+		return &client.PronunciationResponse{
+			Status: "error",
+			Error:  &client.PronunciationError{Code: "NO_PHONEMES", Message: "expected text has no phonemes"},
+		}, nil
+	}
+
+	analysis := &client.PronunciationAnalysis{
+		PhonemeDetails: make([]client.PhonemeDetail, 0, len(expected)),
+		AudioQuality: &client.AudioQuality{
+			QualityScore:    90,
+			SNRDB:           30,
+			DurationSeconds: spokenSeconds(expectedText, 1.0),
+			Warnings:        []string{},
+		},
+		ProcessingTimeMs: 50,
+		Quality:          quality,
+	}
+	var heard []string
+	for i, phoneme := range expected {
+		detail := client.PhonemeDetail{Expected: phoneme, Actual: phoneme, Type: "match", Position: i}
+		roll := pick(fmt.Sprintf("%s#%d", expectedText, i), 20)
+		if substitute, ok := mistakes[phoneme]; ok && roll < 8 {
+			detail.Actual, detail.Type = substitute, "substitute"
+			analysis.SubstitutionCount++
+		} else if roll == 19 {
+			detail.Actual, detail.Type = "", "delete"
+			analysis.DeletionCount++
+		} else {
+			analysis.MatchCount++
+		}
+		if detail.Actual != "" {
+			heard = append(heard, detail.Actual)
+		}
+		analysis.PhonemeDetails = append(analysis.PhonemeDetails, detail)
+	}
+	analysis.PhonemeCount = len(expected)
+	analysis.ExpectedIPA = strings.Join(expected, "")
+	analysis.AudioIPA = strings.Join(heard, "")
+
+	return &client.PronunciationResponse{Status: "success", Analysis: analysis}, nil
+}
+
+func (m *mlClient) SubmitPronunciation(ctx context.Context, audioURL, expectedText, language string, quality client.AnalysisQuality, callbackURL, callbackToken string) error {
+	return ErrCallbacksUnsupported
+}
+
+func (m *mlClient) Health(ctx context.Context) (*client.MLHealth, error) {
+	return &client.MLHealth{Status: "healthy", ModelLoaded: true}, nil
+}
+
+// phonemize turns English text into phonemes with the spelling table.
+// Letters it has no entry for stand for themselves.
+func phonemize(text string) []string {
+	var phonemes []string
+	runes := []rune(strings.ToLower(text))
+	for i := 0; i < len(runes); i++ {
+		if !unicode.IsLetter(runes[i]) {
+			continue
+		}
+		if i+1 < len(runes) {
+			if p, ok := digraphs[string(runes[i:i+2])]; ok {
+				phonemes = append(phonemes, p...)
+				i++
+				continue
+			}
+		}
+		if p, ok := letters[runes[i]]; ok {
+			phonemes = append(phonemes, p...)
+		} else {
+			phonemes = append(phonemes, string(runes[i]))
+		}
+	}
+	return phonemes
+}
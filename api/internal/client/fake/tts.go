@@ -0,0 +1,98 @@
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"ling-app/api/internal/client"
+)
+
+const (
+	sampleRate = 16000
+
+	// secondsPerWord is the pace of the fake voice at normal speed
+	secondsPerWord = 0.4
+)
+
+// ttsClient speaks text as a sine-wave tone whose pitch comes from the text
+// and whose length follows its word count
+type ttsClient struct{}
+
+// NewTTSClient creates a fake text-to-speech client. It can change speaking
+// rate and language like OpenAI TTS, so pace and language settings can be
+// tried out.
+func NewTTSClient() client.TTSClient {
+	return &ttsClient{}
+}
+
+var (
+	_ client.RateSynthesizer     = (*ttsClient)(nil)
+	_ client.LanguageSynthesizer = (*ttsClient)(nil)
+)
+
+// ForLanguage returns the same client: tones have no language
+func (t *ttsClient) ForLanguage(language string) client.TTSClient {
+	return t
+}
+
+func (t *ttsClient) Synthesize(ctx context.Context, text string) (*client.TTSResult, error) {
+	return t.SynthesizeAtRate(ctx, text, 1.0)
+}
+
+// SynthesizeWithOptions ignores the options: every tone sounds the same
+func (t *ttsClient) SynthesizeWithOptions(ctx context.Context, text string, exaggeration float64, format string) (*client.TTSResult, error) {
+	return t.SynthesizeAtRate(ctx, text, 1.0)
+}
+
+// SynthesizeAtRate returns WAV audio, whatever the format asked for.
+// Browsers sniff the content, so it plays even when stored as audio/mpeg.
+func (t *ttsClient) SynthesizeAtRate(ctx context.Context, text string, rate float64) (*client.TTSResult, error) {
+	if rate <= 0 {
+		rate = 1.0
+	}
+	seconds := spokenSeconds(text, rate)
+	// 220-440 Hz, so different replies are told apart by ear
+	frequency := 220 + float64(pick(text, 221))
+	return &client.TTSResult{AudioBytes: sineWAV(frequency, seconds), Duration: seconds}, nil
+}
+
+// spokenSeconds is how long text takes to say at rate, at least a second
+func spokenSeconds(text string, rate float64) float64 {
+	seconds := float64(len(strings.Fields(text))) * secondsPerWord / rate
+	return math.Max(1, math.Round(seconds*100)/100)
+}
+
+// sineWAV renders a quiet 16-bit mono tone, faded in and out to avoid clicks
+func sineWAV(frequency, seconds float64) []byte {
+	samples := int(seconds * sampleRate)
+	fade := sampleRate / 50
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+samples*2))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))           // fmt chunk size
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))   // sample rate
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2)) // byte rate
+	binary.Write(&buf, binary.LittleEndian, uint16(2))            // block align
+	binary.Write(&buf, binary.LittleEndian, uint16(16))           // bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(samples*2))
+
+	for i := 0; i < samples; i++ {
+		amplitude := 0.2
+		if i < fade {
+			amplitude *= float64(i) / float64(fade)
+		} else if samples-i < fade {
+			amplitude *= float64(samples-i) / float64(fade)
+		}
+		sample := amplitude * math.Sin(2*math.Pi*frequency*float64(i)/sampleRate)
+		binary.Write(&buf, binary.LittleEndian, int16(sample*math.MaxInt16))
+	}
+	return buf.Bytes()
+}
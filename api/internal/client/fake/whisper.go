@@ -0,0 +1,62 @@
+// Package fake has deterministic stand-ins for the ML-backed clients, so the
+// app runs end to end on a laptop without the ML service, GPUs or OpenAI
+// speech APIs. The same input always gives the same output.
+package fake
+
+import (
+	"context"
+	"hash/fnv"
+	"net/url"
+
+	"ling-app/api/internal/client"
+)
+
+// Transcripts are the canned transcripts the fake Whisper returns
+var Transcripts = []string{
+	"Hello, how are you today?",
+	"I would like to order a coffee, please.",
+	"Yesterday I went to the park with my friends.",
+	"Can you tell me where the train station is?",
+	"I think this weather is really nice.",
+	"My brother is studying to become a teacher.",
+}
+
+// whisperClient transcribes every recording as one of Transcripts
+type whisperClient struct{}
+
+// NewWhisperClient creates a fake speech-to-text client
+func NewWhisperClient() client.WhisperClient {
+	return &whisperClient{}
+}
+
+// TranscribeFromURL picks a transcript from the recording's URL. Presigned
+// URLs change with every request, so only the object path is used. The
+// transcripts are English whatever the language, which is only reported back.
+func (w *whisperClient) TranscribeFromURL(ctx context.Context, audioURL, language string) (*client.TranscriptionResult, error) {
+	text := Transcripts[pick(objectPath(audioURL), len(Transcripts))]
+	if language == "" {
+		language = "en"
+	}
+	return &client.TranscriptionResult{
+		Text:     text,
+		Language: language,
+		Duration: spokenSeconds(text, 1.0),
+	}, nil
+}
+
+// pick deterministically chooses an index below n for s
+func pick(s string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return int(h.Sum32() % uint32(n))
+}
+
+// objectPath is a URL without its query, which for presigned URLs holds the
+// signature and expiry
+func objectPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host + u.Path
+}
@@ -0,0 +1,363 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"ling-app/api/internal/client/mlpb"
+)
+
+// grpcAudioChunkSize is how much audio each streamed message carries
+const grpcAudioChunkSize = 32 * 1024
+
+// Per-call timeouts, matching the HTTP clients
+const (
+	grpcTranscribeTimeout = 120 * time.Second
+	grpcSynthesizeTimeout = 60 * time.Second
+)
+
+// NewMLConn creates a gRPC connection to the ML service at target
+// (host:port), shared by the gRPC ML, Whisper and TTS clients. Calls run
+// over mutual TLS, which authenticates both ends and covers every streamed
+// message; the HMAC headers of the HTTP clients sign a body known up front,
+// which a stream doesn't have. opts are added after the credentials.
+func NewMLConn(target string, tlsConfig *tls.Config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if tlsConfig == nil {
+		return nil, errors.New("ML service gRPC client needs a TLS config")
+	}
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}, opts...)
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ML service gRPC client: %w", err)
+	}
+	return conn, nil
+}
+
+// LoadMLTLSConfig builds the mutual TLS config for NewMLConn from the API's
+// client certificate and key and the CA that signed the ML service's
+// certificate, all PEM files.
+func LoadMLTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ML service client certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ML service CA: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in ML service CA %s", caFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      roots,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// grpcMLClient implements MLClient using the ML service's gRPC API.
+type grpcMLClient struct {
+	ml      mlpb.MLServiceClient
+	timeout time.Duration
+}
+
+// NewGRPCMLClient creates an ML client on a connection from NewMLConn.
+func NewGRPCMLClient(conn grpc.ClientConnInterface, timeout time.Duration) MLClient {
+	if timeout == 0 {
+		timeout = 120 * time.Second // Default 2 minutes for pronunciation analysis
+	}
+	return &grpcMLClient{
+		ml:      mlpb.NewMLServiceClient(conn),
+		timeout: timeout,
+	}
+}
+
+// AnalyzePronunciation downloads the audio and streams it to the ML service.
+func (c *grpcMLClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string, quality AnalysisQuality) (*PronunciationResponse, error) {
+	if language == "" {
+		language = "en-us"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	stream, err := c.ml.AnalyzePronunciation(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	err = stream.Send(&mlpb.AnalyzePronunciationRequest{
+		Payload: &mlpb.AnalyzePronunciationRequest_Config{
+			Config: &mlpb.AnalyzeConfig{ExpectedText: expectedText, Language: language, Quality: string(quality)},
+		},
+	})
+	if err == nil {
+		err = streamAudio(ctx, audioURL, func(chunk []byte) error {
+			return stream.Send(&mlpb.AnalyzePronunciationRequest{
+				Payload: &mlpb.AnalyzePronunciationRequest_AudioChunk{AudioChunk: chunk},
+			})
+		})
+	}
+	// io.EOF means the service ended the stream early; its reason comes from CloseAndRecv
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	if resp.GetError() != nil {
+		return nil, mlServiceError(resp.GetError())
+	}
+
+	return &PronunciationResponse{
+		Status:   "success",
+		Analysis: pronunciationAnalysisFromProto(resp.GetAnalysis()),
+	}, nil
+}
+
+// SubmitPronunciation queues pronunciation analysis on the ML service, which
+// POSTs the result to callbackURL with callbackToken. The job outlives the
+// call, so the service fetches the audio from audioURL itself.
+func (c *grpcMLClient) SubmitPronunciation(ctx context.Context, audioURL, expectedText, language string, quality AnalysisQuality, callbackURL, callbackToken string) error {
+	if language == "" {
+		language = "en-us"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.ml.SubmitPronunciation(ctx, &mlpb.SubmitPronunciationRequest{
+		AudioUrl:      audioURL,
+		ExpectedText:  expectedText,
+		Language:      language,
+		Quality:       string(quality),
+		CallbackUrl:   callbackURL,
+		CallbackToken: callbackToken,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to call ML service: %w", err)
+	}
+	if resp.GetError() != nil {
+		return mlServiceError(resp.GetError())
+	}
+	return nil
+}
+
+// Health fetches the ML service's health and current queue depth.
+func (c *grpcMLClient) Health(ctx context.Context) (*MLHealth, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.ml.Health(ctx, &mlpb.HealthRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	return &MLHealth{
+		Status:      resp.GetStatus(),
+		ModelLoaded: resp.GetModelLoaded(),
+		QueueDepth:  int(resp.GetQueueDepth()),
+	}, nil
+}
+
+// grpcWhisperClient transcribes with faster-whisper over the ML service's gRPC API.
+type grpcWhisperClient struct {
+	ml mlpb.MLServiceClient
+}
+
+// NewGRPCWhisperClient creates a Whisper client on a connection from NewMLConn.
+func NewGRPCWhisperClient(conn grpc.ClientConnInterface) WhisperClient {
+	return &grpcWhisperClient{ml: mlpb.NewMLServiceClient(conn)}
+}
+
+// TranscribeFromURL downloads the audio and streams it to the ML service.
+func (w *grpcWhisperClient) TranscribeFromURL(ctx context.Context, audioURL, language string) (*TranscriptionResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, grpcTranscribeTimeout)
+	defer cancel()
+
+	stream, err := w.ml.Transcribe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	err = stream.Send(&mlpb.TranscribeRequest{
+		Payload: &mlpb.TranscribeRequest_Config{Config: &mlpb.TranscribeConfig{Language: language}},
+	})
+	if err == nil {
+		err = streamAudio(ctx, audioURL, func(chunk []byte) error {
+			return stream.Send(&mlpb.TranscribeRequest{
+				Payload: &mlpb.TranscribeRequest_AudioChunk{AudioChunk: chunk},
+			})
+		})
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	if resp.GetError() != nil {
+		return nil, fmt.Errorf("transcription failed: %s", resp.GetError().GetMessage())
+	}
+
+	return &TranscriptionResult{
+		Text:     resp.GetText(),
+		Language: resp.GetLanguage(),
+		Duration: resp.GetDuration(),
+	}, nil
+}
+
+// grpcTTSClient synthesizes with Chatterbox over the ML service's gRPC API.
+type grpcTTSClient struct {
+	ml mlpb.MLServiceClient
+}
+
+// NewGRPCTTSClient creates a TTS client on a connection from NewMLConn.
+func NewGRPCTTSClient(conn grpc.ClientConnInterface) TTSClient {
+	return &grpcTTSClient{ml: mlpb.NewMLServiceClient(conn)}
+}
+
+func (t *grpcTTSClient) Synthesize(ctx context.Context, text string) (*TTSResult, error) {
+	return t.SynthesizeWithOptions(ctx, text, 0.5, "mp3")
+}
+
+// SynthesizeWithOptions collects the audio the ML service streams back.
+func (t *grpcTTSClient) SynthesizeWithOptions(ctx context.Context, text string, exaggeration float64, format string) (*TTSResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, grpcSynthesizeTimeout)
+	defer cancel()
+
+	stream, err := t.ml.Synthesize(ctx, &mlpb.SynthesizeRequest{
+		Text:         text,
+		Exaggeration: exaggeration,
+		Format:       format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+
+	var audio bytes.Buffer
+	duration := 0.0
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to call ML service: %w", err)
+		}
+
+		switch payload := msg.GetPayload().(type) {
+		case *mlpb.SynthesizeResponse_AudioChunk:
+			audio.Write(payload.AudioChunk)
+		case *mlpb.SynthesizeResponse_Summary:
+			duration = payload.Summary.GetDuration()
+		case *mlpb.SynthesizeResponse_Error:
+			return nil, fmt.Errorf("synthesis failed: %s", payload.Error.GetMessage())
+		}
+	}
+
+	if audio.Len() == 0 {
+		return nil, fmt.Errorf("synthesis succeeded but no audio data returned")
+	}
+
+	return &TTSResult{
+		AudioBytes: audio.Bytes(),
+		Duration:   duration,
+	}, nil
+}
+
+// streamAudio downloads audioURL and hands it to send in chunks, so the
+// file is never held in memory whole. Errors from send are returned as is.
+func streamAudio(ctx context.Context, audioURL string, send func(chunk []byte) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, audioURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create audio request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download audio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download audio: status %d", resp.StatusCode)
+	}
+
+	// gRPC serializes a message before Send returns, so the buffer is reused
+	buf := make([]byte, grpcAudioChunkSize)
+	for {
+		n, err := io.ReadFull(resp.Body, buf)
+		if n > 0 {
+			if sendErr := send(buf[:n]); sendErr != nil {
+				return sendErr
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read audio: %w", err)
+		}
+	}
+}
+
+func mlServiceError(e *mlpb.Error) *MLServiceError {
+	return &MLServiceError{
+		Code:      e.GetCode(),
+		Message:   e.GetMessage(),
+		Retryable: e.GetRetryable(),
+	}
+}
+
+func pronunciationAnalysisFromProto(a *mlpb.PronunciationAnalysis) *PronunciationAnalysis {
+	if a == nil {
+		return nil
+	}
+
+	details := make([]PhonemeDetail, len(a.GetPhonemeDetails()))
+	for i, d := range a.GetPhonemeDetails() {
+		details[i] = PhonemeDetail{
+			Expected: d.GetExpected(),
+			Actual:   d.GetActual(),
+			Type:     d.GetType(),
+			Position: int(d.GetPosition()),
+		}
+	}
+
+	analysis := &PronunciationAnalysis{
+		AudioIPA:          a.GetAudioIpa(),
+		ExpectedIPA:       a.GetExpectedIpa(),
+		PhonemeCount:      int(a.GetPhonemeCount()),
+		MatchCount:        int(a.GetMatchCount()),
+		SubstitutionCount: int(a.GetSubstitutionCount()),
+		DeletionCount:     int(a.GetDeletionCount()),
+		InsertionCount:    int(a.GetInsertionCount()),
+		PhonemeDetails:    details,
+		ProcessingTimeMs:  a.GetProcessingTimeMs(),
+		Quality:           AnalysisQuality(a.GetQuality()),
+	}
+	if q := a.GetAudioQuality(); q != nil {
+		analysis.AudioQuality = &AudioQuality{
+			QualityScore:    q.GetQualityScore(),
+			SNRDB:           q.GetSnrDb(),
+			DurationSeconds: q.GetDurationSeconds(),
+			Warnings:        q.GetWarnings(),
+		}
+	}
+	return analysis
+}
@@ -0,0 +1,377 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
+
+	"ling-app/api/internal/client/mlpb"
+)
+
+// testPKI is a CA with a server certificate for "bufnet" and a client
+// certificate, both signed by it
+type testPKI struct {
+	caPEM         []byte
+	roots         *x509.CertPool
+	server        tls.Certificate
+	client        tls.Certificate
+	clientCertPEM []byte
+	clientKeyPEM  []byte
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	pki := &testPKI{
+		caPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		roots: x509.NewCertPool(),
+	}
+	pki.roots.AddCert(ca)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	serverCert, serverKey := issue(2, "bufnet", x509.ExtKeyUsageServerAuth)
+	pki.server, err = tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	pki.clientCertPEM, pki.clientKeyPEM = issue(3, "api", x509.ExtKeyUsageClientAuth)
+	pki.client, err = tls.X509KeyPair(pki.clientCertPEM, pki.clientKeyPEM)
+	require.NoError(t, err)
+	return pki
+}
+
+// clientTLS is the config the API would load with LoadMLTLSConfig
+func (p *testPKI) clientTLS() *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{p.client},
+		RootCAs:      p.roots,
+		MinVersion:   tls.VersionTLS13,
+	}
+}
+
+// fakeMLServer records what the clients send and answers with canned results
+type fakeMLServer struct {
+	mlpb.UnimplementedMLServiceServer
+
+	mu           sync.Mutex
+	transcribe   *mlpb.TranscribeConfig
+	analyze      *mlpb.AnalyzeConfig
+	audio        []byte
+	submitted    *mlpb.SubmitPronunciationRequest
+	analyzeError *mlpb.Error
+}
+
+func (s *fakeMLServer) Transcribe(stream grpc.ClientStreamingServer[mlpb.TranscribeRequest, mlpb.TranscribeResponse]) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&mlpb.TranscribeResponse{Text: "hello there", Language: "en", Duration: 2.5})
+		}
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		if config := req.GetConfig(); config != nil {
+			s.transcribe = config
+		}
+		s.audio = append(s.audio, req.GetAudioChunk()...)
+		s.mu.Unlock()
+	}
+}
+
+func (s *fakeMLServer) AnalyzePronunciation(stream grpc.ClientStreamingServer[mlpb.AnalyzePronunciationRequest, mlpb.AnalyzePronunciationResponse]) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		if config := req.GetConfig(); config != nil {
+			s.analyze = config
+		}
+		s.audio = append(s.audio, req.GetAudioChunk()...)
+		s.mu.Unlock()
+	}
+
+	if s.analyzeError != nil {
+		return stream.SendAndClose(&mlpb.AnalyzePronunciationResponse{Error: s.analyzeError})
+	}
+	return stream.SendAndClose(&mlpb.AnalyzePronunciationResponse{
+		Analysis: &mlpb.PronunciationAnalysis{
+			AudioIpa:     "həloʊ",
+			ExpectedIpa:  "həloʊ",
+			PhonemeCount: 4,
+			MatchCount:   4,
+			PhonemeDetails: []*mlpb.PhonemeDetail{
+				{Expected: "h", Actual: "h", Type: "match", Position: 0},
+			},
+			AudioQuality: &mlpb.AudioQuality{QualityScore: 90, SnrDb: 25, Warnings: []string{"clipping"}},
+			Quality:      "fast",
+		},
+	})
+}
+
+func (s *fakeMLServer) Synthesize(req *mlpb.SynthesizeRequest, stream grpc.ServerStreamingServer[mlpb.SynthesizeResponse]) error {
+	if req.GetText() == "" {
+		return stream.Send(&mlpb.SynthesizeResponse{
+			Payload: &mlpb.SynthesizeResponse_Error{Error: &mlpb.Error{Code: "EMPTY_TEXT", Message: "nothing to say"}},
+		})
+	}
+	for _, chunk := range [][]byte{[]byte("ID3"), []byte("-audio")} {
+		if err := stream.Send(&mlpb.SynthesizeResponse{Payload: &mlpb.SynthesizeResponse_AudioChunk{AudioChunk: chunk}}); err != nil {
+			return err
+		}
+	}
+	return stream.Send(&mlpb.SynthesizeResponse{
+		Payload: &mlpb.SynthesizeResponse_Summary{Summary: &mlpb.SynthesizeSummary{Duration: 1.5, Format: req.GetFormat()}},
+	})
+}
+
+func (s *fakeMLServer) SubmitPronunciation(_ context.Context, req *mlpb.SubmitPronunciationRequest) (*mlpb.SubmitPronunciationResponse, error) {
+	s.mu.Lock()
+	s.submitted = req
+	s.mu.Unlock()
+	return &mlpb.SubmitPronunciationResponse{}, nil
+}
+
+func (s *fakeMLServer) Health(context.Context, *mlpb.HealthRequest) (*mlpb.HealthResponse, error) {
+	return &mlpb.HealthResponse{Status: "healthy", ModelLoaded: true, QueueDepth: 3}, nil
+}
+
+// startFakeML serves fake over an in-memory listener with mutual TLS, like
+// the ML service would, and returns a connection made with clientTLS
+func startFakeML(t *testing.T, pki *testPKI, fake *fakeMLServer, clientTLS *tls.Config) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pki.server},
+		ClientCAs:    pki.roots,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	})))
+	mlpb.RegisterMLServiceServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := NewMLConn("passthrough:///bufnet", clientTLS,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// serveAudio serves audio the way storage serves a presigned URL
+func serveAudio(t *testing.T, audio []byte) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(audio)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestGRPCWhisperClient_StreamsAudioInChunks(t *testing.T) {
+	pki := newTestPKI(t)
+	fake := &fakeMLServer{}
+	conn := startFakeML(t, pki, fake, pki.clientTLS())
+
+	// More than two chunks, the last one short
+	audio := bytes.Repeat([]byte("a"), 2*grpcAudioChunkSize+100)
+	result, err := NewGRPCWhisperClient(conn).TranscribeFromURL(context.Background(), serveAudio(t, audio), "es")
+
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", result.Text)
+	assert.Equal(t, "en", result.Language)
+	assert.Equal(t, 2.5, result.Duration)
+	assert.Equal(t, "es", fake.transcribe.GetLanguage())
+	assert.Equal(t, audio, fake.audio)
+}
+
+func TestGRPCMLClient_AnalyzePronunciation(t *testing.T) {
+	t.Run("maps the analysis", func(t *testing.T) {
+		pki := newTestPKI(t)
+		fake := &fakeMLServer{}
+		conn := startFakeML(t, pki, fake, pki.clientTLS())
+
+		resp, err := NewGRPCMLClient(conn, 0).AnalyzePronunciation(context.Background(), serveAudio(t, []byte("wav")), "hello", "", QualityFast)
+
+		require.NoError(t, err)
+		assert.Equal(t, "success", resp.Status)
+		assert.Equal(t, 4, resp.Analysis.PhonemeCount)
+		assert.Equal(t, []PhonemeDetail{{Expected: "h", Actual: "h", Type: "match"}}, resp.Analysis.PhonemeDetails)
+		assert.Equal(t, []string{"clipping"}, resp.Analysis.AudioQuality.Warnings)
+		assert.Equal(t, QualityFast, resp.Analysis.Quality)
+		// The language defaults like the HTTP client's
+		assert.Equal(t, "en-us", fake.analyze.GetLanguage())
+		assert.Equal(t, "hello", fake.analyze.GetExpectedText())
+		assert.Equal(t, []byte("wav"), fake.audio)
+	})
+
+	t.Run("returns the service's error", func(t *testing.T) {
+		pki := newTestPKI(t)
+		fake := &fakeMLServer{analyzeError: &mlpb.Error{Code: "MODEL_LOADING", Message: "warming up", Retryable: true}}
+		conn := startFakeML(t, pki, fake, pki.clientTLS())
+
+		_, err := NewGRPCMLClient(conn, 0).AnalyzePronunciation(context.Background(), serveAudio(t, []byte("wav")), "hello", "en-us", QualityAccurate)
+
+		var mlErr *MLServiceError
+		require.ErrorAs(t, err, &mlErr)
+		assert.Equal(t, "MODEL_LOADING", mlErr.Code)
+		assert.True(t, mlErr.Retryable)
+	})
+}
+
+func TestGRPCMLClient_SubmitPronunciationAndHealth(t *testing.T) {
+	pki := newTestPKI(t)
+	fake := &fakeMLServer{}
+	conn := startFakeML(t, pki, fake, pki.clientTLS())
+	ml := NewGRPCMLClient(conn, 0)
+
+	err := ml.SubmitPronunciation(context.Background(), "https://audio", "hello", "fr-fr", QualityAccurate, "https://api/callback", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "https://audio", fake.submitted.GetAudioUrl())
+	assert.Equal(t, "fr-fr", fake.submitted.GetLanguage())
+	assert.Equal(t, "token", fake.submitted.GetCallbackToken())
+
+	health, err := ml.Health(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &MLHealth{Status: "healthy", ModelLoaded: true, QueueDepth: 3}, health)
+}
+
+func TestGRPCTTSClient_CollectsStreamedAudio(t *testing.T) {
+	pki := newTestPKI(t)
+	conn := startFakeML(t, pki, &fakeMLServer{}, pki.clientTLS())
+	tts := NewGRPCTTSClient(conn)
+
+	result, err := tts.Synthesize(context.Background(), "Hi!")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ID3-audio"), result.AudioBytes)
+	assert.Equal(t, 1.5, result.Duration)
+
+	_, err = tts.Synthesize(context.Background(), "")
+	assert.ErrorContains(t, err, "nothing to say")
+}
+
+func TestNewMLConn_RequiresTrustedClientCertificate(t *testing.T) {
+	pki := newTestPKI(t)
+
+	t.Run("no client certificate", func(t *testing.T) {
+		conn := startFakeML(t, pki, &fakeMLServer{}, &tls.Config{RootCAs: pki.roots, MinVersion: tls.VersionTLS13})
+
+		_, err := NewGRPCMLClient(conn, time.Second).Health(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("certificate from another CA", func(t *testing.T) {
+		other := newTestPKI(t)
+		conn := startFakeML(t, pki, &fakeMLServer{}, &tls.Config{
+			Certificates: []tls.Certificate{other.client},
+			RootCAs:      pki.roots,
+			MinVersion:   tls.VersionTLS13,
+		})
+
+		_, err := NewGRPCMLClient(conn, time.Second).Health(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("no TLS config", func(t *testing.T) {
+		_, err := NewMLConn("bufnet:50051", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadMLTLSConfig(t *testing.T) {
+	pki := newTestPKI(t)
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		return path
+	}
+	certFile := write("client.pem", pki.clientCertPEM)
+	keyFile := write("client-key.pem", pki.clientKeyPEM)
+	caFile := write("ca.pem", pki.caPEM)
+
+	t.Run("loads a config the ML service accepts", func(t *testing.T) {
+		tlsConfig, err := LoadMLTLSConfig(certFile, keyFile, caFile)
+		require.NoError(t, err)
+
+		conn := startFakeML(t, pki, &fakeMLServer{}, tlsConfig)
+		_, err = NewGRPCMLClient(conn, time.Second).Health(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects a CA file without certificates", func(t *testing.T) {
+		_, err := LoadMLTLSConfig(certFile, keyFile, keyFile)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects a missing key", func(t *testing.T) {
+		_, err := LoadMLTLSConfig(certFile, filepath.Join(dir, "missing.pem"), caFile)
+		assert.Error(t, err)
+	})
+}
@@ -8,12 +8,15 @@ import (
 
 // MLClient handles pronunciation analysis via the ML service.
 type MLClient interface {
-	AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string) (*PronunciationResponse, error)
+	AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string, quality AnalysisQuality) (*PronunciationResponse, error)
+	SubmitPronunciation(ctx context.Context, audioURL, expectedText, language string, quality AnalysisQuality, callbackURL, callbackToken string) error
+	Health(ctx context.Context) (*MLHealth, error)
 }
 
-// WhisperClient handles speech-to-text transcription.
+// WhisperClient handles speech-to-text transcription. language is the ISO
+// 639-1 code the audio is spoken in, e.g. "es"; empty detects it.
 type WhisperClient interface {
-	TranscribeFromURL(ctx context.Context, audioURL string) (*TranscriptionResult, error)
+	TranscribeFromURL(ctx context.Context, audioURL, language string) (*TranscriptionResult, error)
 }
 
 // TTSClient handles text-to-speech synthesis.
@@ -22,26 +25,101 @@ type TTSClient interface {
 	SynthesizeWithOptions(ctx context.Context, text string, exaggeration float64, format string) (*TTSResult, error)
 }
 
+// RateSynthesizer is implemented by TTS clients that can change the speaking
+// rate, where 1.0 is normal speed. Chatterbox can't, so only OpenAI TTS does.
+type RateSynthesizer interface {
+	SynthesizeAtRate(ctx context.Context, text string, rate float64) (*TTSResult, error)
+}
+
+// LowBitrateSynthesizer is implemented by TTS clients that can also speak in
+// Ogg Opus, a fraction of the size of the standard MP3, for listeners on
+// mobile data. Only OpenAI TTS does.
+type LowBitrateSynthesizer interface {
+	SynthesizeLowBitrate(ctx context.Context, text string, rate float64) (*TTSResult, error)
+}
+
+// LanguageSynthesizer is implemented by TTS clients that can speak languages
+// other than English. ForLanguage returns the client speaking in the voice
+// for an ISO 639-1 language code, with the same capabilities. Chatterbox
+// only speaks English, so only OpenAI TTS does.
+type LanguageSynthesizer interface {
+	ForLanguage(language string) TTSClient
+}
+
 // OpenAIClient handles LLM generation via OpenAI.
 type OpenAIClient interface {
 	Generate(messages []ConversationMessage) (string, error)
 	GenerateTitle(content string) (string, error)
+	EvaluateGoal(goal string, messages []ConversationMessage) (bool, error)
+	SuggestReplies(messages []ConversationMessage) ([]string, error)
+	ExtractLearnerFacts(known LearnerFacts, messages []ConversationMessage) (*LearnerFacts, error)
+	PunctuateTranscript(text, locale string) (string, error)
+}
+
+// StreamGenerator is implemented by LLM clients that can stream a reply as
+// it is generated. onToken receives each piece of text in order; the full
+// reply is returned at the end.
+type StreamGenerator interface {
+	GenerateStream(ctx context.Context, messages []ConversationMessage, onToken func(string)) (string, error)
+}
+
+// ModerationClient screens text for unsafe content.
+type ModerationClient interface {
+	Moderate(ctx context.Context, text string) (*ModerationResult, error)
+}
+
+// EmailClient sends transactional email.
+type EmailClient interface {
+	Send(ctx context.Context, to, subject, body string) error
 }
 
 // StorageClient handles object storage operations.
 type StorageClient interface {
 	UploadAudio(ctx context.Context, file io.Reader, key string, contentType string) (string, error)
 	GetPresignedURL(ctx context.Context, key string, expiration time.Duration) (string, error)
+	GetObject(ctx context.Context, key string, byteRange string) (*StorageObject, error)
+	StatObject(ctx context.Context, key string) (*ObjectInfo, error)
 	DeleteAudio(ctx context.Context, key string) error
+	// DeletePrefix deletes every object whose key starts with prefix and
+	// returns how many there were
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+	// CopyObject copies the object at src, with its content type, to dst
+	CopyObject(ctx context.Context, src, dst string) error
 	EnsureBucketExists(ctx context.Context) error
 }
 
+// StorageObject is a (possibly partial) object streamed from storage.
+// The caller must close Body.
+type StorageObject struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64
+	ContentRange  string // Set when a byte range was requested, e.g. "bytes 0-1023/4096"
+	ETag          string
+	LastModified  *time.Time
+}
+
+// ObjectInfo is object metadata without the body.
+type ObjectInfo struct {
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified *time.Time
+}
+
 // ConversationMessage represents a chat message for LLM generation.
 type ConversationMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
 }
 
+// LearnerFacts are durable facts about a learner gathered from conversations.
+type LearnerFacts struct {
+	PreferredName     string   `json:"preferredName"`
+	Interests         []string `json:"interests"`
+	RecurringMistakes []string `json:"recurringMistakes"`
+}
+
 // TranscriptionResult is the result from speech-to-text.
 type TranscriptionResult struct {
 	Text     string
@@ -49,14 +127,38 @@ type TranscriptionResult struct {
 	Duration float64
 }
 
+// ModerationResult is the verdict from a moderation check. Categories lists
+// the flagged categories (e.g. "sexual/minors", "self-harm").
+type ModerationResult struct {
+	Flagged    bool
+	Categories []string
+}
+
 // TTSResult is the result from text-to-speech.
 type TTSResult struct {
 	AudioBytes []byte
 	Duration   float64
 }
 
+// MLHealth is the ML service's health and load report.
+type MLHealth struct {
+	Status      string `json:"status"`
+	ModelLoaded bool   `json:"model_loaded"`
+	QueueDepth  int    `json:"queue_depth"`
+}
+
 // Pronunciation analysis types
 
+// AnalysisQuality selects the model used for pronunciation analysis
+type AnalysisQuality string
+
+const (
+	// QualityFast uses a smaller model: quicker and cheaper, less accurate
+	QualityFast AnalysisQuality = "fast"
+	// QualityAccurate uses the full model
+	QualityAccurate AnalysisQuality = "accurate"
+)
+
 // PronunciationResponse is the full response from pronunciation analysis.
 type PronunciationResponse struct {
 	Status   string                 `json:"status"`
@@ -76,6 +178,8 @@ type PronunciationAnalysis struct {
 	PhonemeDetails    []PhonemeDetail `json:"phoneme_details"`
 	AudioQuality      *AudioQuality   `json:"audio_quality,omitempty"`
 	ProcessingTimeMs  int64           `json:"processing_time_ms"`
+	// Quality level that produced the analysis, echoed by the ML service
+	Quality AnalysisQuality `json:"quality,omitempty"`
 }
 
 // PhonemeDetail represents a single phoneme comparison.
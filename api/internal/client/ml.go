@@ -15,28 +15,33 @@ type mlClient struct {
 	httpClient *http.Client
 }
 
-// NewMLClient creates a new ML client.
-func NewMLClient(baseURL string, timeout time.Duration) MLClient {
+// NewMLClient creates a new ML client. Requests are signed when signer is non-nil.
+func NewMLClient(baseURL string, timeout time.Duration, signer *ServiceSigner) MLClient {
 	if timeout == 0 {
 		timeout = 120 * time.Second // Default 2 minutes for pronunciation analysis
 	}
 	return &mlClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: serviceTransport(signer),
 		},
 	}
 }
 
-// pronunciationRequest is the request body for the ML service.
+// pronunciationRequest is the request body for the ML service. With a
+// callback URL the service answers 202 and POSTs the result there instead.
 type pronunciationRequest struct {
-	AudioURL     string `json:"audio_url"`
-	ExpectedText string `json:"expected_text"`
-	Language     string `json:"language"`
+	AudioURL      string `json:"audio_url"`
+	ExpectedText  string `json:"expected_text"`
+	Language      string `json:"language"`
+	Quality       string `json:"quality,omitempty"`
+	CallbackURL   string `json:"callback_url,omitempty"`
+	CallbackToken string `json:"callback_token,omitempty"`
 }
 
 // AnalyzePronunciation calls the ML service to analyze pronunciation.
-func (c *mlClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string) (*PronunciationResponse, error) {
+func (c *mlClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string, quality AnalysisQuality) (*PronunciationResponse, error) {
 	if language == "" {
 		language = "en-us"
 	}
@@ -45,6 +50,7 @@ func (c *mlClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedT
 		AudioURL:     audioURL,
 		ExpectedText: expectedText,
 		Language:     language,
+		Quality:      string(quality),
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -86,3 +92,79 @@ func (c *mlClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedT
 
 	return &result, nil
 }
+
+// SubmitPronunciation queues pronunciation analysis on the ML service, which
+// POSTs the result to callbackURL with callbackToken. It returns once the job
+// is accepted.
+func (c *mlClient) SubmitPronunciation(ctx context.Context, audioURL, expectedText, language string, quality AnalysisQuality, callbackURL, callbackToken string) error {
+	if language == "" {
+		language = "en-us"
+	}
+
+	reqBody := pronunciationRequest{
+		AudioURL:      audioURL,
+		ExpectedText:  expectedText,
+		Language:      language,
+		Quality:       string(quality),
+		CallbackURL:   callbackURL,
+		CallbackToken: callbackToken,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v1/analyze-pronunciation", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call ML service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+
+	// The service rejects jobs it can't start (e.g. models still loading)
+	// with the same structured error as a synchronous call
+	var result PronunciationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil && result.Error != nil {
+		return &MLServiceError{
+			Code:      result.Error.Code,
+			Message:   result.Error.Message,
+			Retryable: result.Error.Retryable,
+		}
+	}
+	return fmt.Errorf("ML service returned status %d", resp.StatusCode)
+}
+
+// Health fetches the ML service's health and current queue depth.
+func (c *mlClient) Health(ctx context.Context) (*MLHealth, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/health", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ML service returned status %d", resp.StatusCode)
+	}
+
+	var health MLHealth
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &health, nil
+}
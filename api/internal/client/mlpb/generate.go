@@ -0,0 +1,5 @@
+// Package mlpb holds the generated gRPC stubs for the ML service contract in
+// proto/ml/v1/ml.proto.
+package mlpb
+
+//go:generate protoc -I ../../../../proto --go_out=. --go_opt=module=ling-app/api/internal/client/mlpb --go-grpc_out=. --go-grpc_opt=module=ling-app/api/internal/client/mlpb ml/v1/ml.proto
@@ -0,0 +1,1369 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: ml/v1/ml.proto
+
+// Contract between the API and the ML service. The HTTP/JSON endpoints under
+// /api/v1 remain the default; this service carries the same operations with
+// audio streamed as raw bytes instead of presigned URLs and base64 bodies.
+//
+// Go stubs live in api/internal/client/mlpb; regenerate with `go generate`
+// from that directory.
+
+package mlpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error is a structured failure the caller can act on, e.g. MODELS_NOT_LOADED.
+// Transport failures are reported as gRPC status codes instead.
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Retryable     bool                   `protobuf:"varint,3,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_ml_v1_ml_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+type TranscribeConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty detects the language
+	Language      string `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscribeConfig) Reset() {
+	*x = TranscribeConfig{}
+	mi := &file_ml_v1_ml_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscribeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscribeConfig) ProtoMessage() {}
+
+func (x *TranscribeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscribeConfig.ProtoReflect.Descriptor instead.
+func (*TranscribeConfig) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TranscribeConfig) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type TranscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*TranscribeRequest_Config
+	//	*TranscribeRequest_AudioChunk
+	Payload       isTranscribeRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscribeRequest) Reset() {
+	*x = TranscribeRequest{}
+	mi := &file_ml_v1_ml_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscribeRequest) ProtoMessage() {}
+
+func (x *TranscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscribeRequest.ProtoReflect.Descriptor instead.
+func (*TranscribeRequest) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TranscribeRequest) GetPayload() isTranscribeRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *TranscribeRequest) GetConfig() *TranscribeConfig {
+	if x != nil {
+		if x, ok := x.Payload.(*TranscribeRequest_Config); ok {
+			return x.Config
+		}
+	}
+	return nil
+}
+
+func (x *TranscribeRequest) GetAudioChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*TranscribeRequest_AudioChunk); ok {
+			return x.AudioChunk
+		}
+	}
+	return nil
+}
+
+type isTranscribeRequest_Payload interface {
+	isTranscribeRequest_Payload()
+}
+
+type TranscribeRequest_Config struct {
+	Config *TranscribeConfig `protobuf:"bytes,1,opt,name=config,proto3,oneof"`
+}
+
+type TranscribeRequest_AudioChunk struct {
+	AudioChunk []byte `protobuf:"bytes,2,opt,name=audio_chunk,json=audioChunk,proto3,oneof"`
+}
+
+func (*TranscribeRequest_Config) isTranscribeRequest_Payload() {}
+
+func (*TranscribeRequest_AudioChunk) isTranscribeRequest_Payload() {}
+
+type TranscribeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Duration      float64                `protobuf:"fixed64,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Error         *Error                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TranscribeResponse) Reset() {
+	*x = TranscribeResponse{}
+	mi := &file_ml_v1_ml_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TranscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscribeResponse) ProtoMessage() {}
+
+func (x *TranscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscribeResponse.ProtoReflect.Descriptor instead.
+func (*TranscribeResponse) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TranscribeResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *TranscribeResponse) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *TranscribeResponse) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *TranscribeResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type SynthesizeRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Text         string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Exaggeration float64                `protobuf:"fixed64,2,opt,name=exaggeration,proto3" json:"exaggeration,omitempty"`
+	// "mp3" or "wav"
+	Format        string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeRequest) Reset() {
+	*x = SynthesizeRequest{}
+	mi := &file_ml_v1_ml_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeRequest) ProtoMessage() {}
+
+func (x *SynthesizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeRequest.ProtoReflect.Descriptor instead.
+func (*SynthesizeRequest) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SynthesizeRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SynthesizeRequest) GetExaggeration() float64 {
+	if x != nil {
+		return x.Exaggeration
+	}
+	return 0
+}
+
+func (x *SynthesizeRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type SynthesizeSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Duration      float64                `protobuf:"fixed64,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeSummary) Reset() {
+	*x = SynthesizeSummary{}
+	mi := &file_ml_v1_ml_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeSummary) ProtoMessage() {}
+
+func (x *SynthesizeSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeSummary.ProtoReflect.Descriptor instead.
+func (*SynthesizeSummary) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SynthesizeSummary) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *SynthesizeSummary) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type SynthesizeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*SynthesizeResponse_AudioChunk
+	//	*SynthesizeResponse_Summary
+	//	*SynthesizeResponse_Error
+	Payload       isSynthesizeResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeResponse) Reset() {
+	*x = SynthesizeResponse{}
+	mi := &file_ml_v1_ml_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeResponse) ProtoMessage() {}
+
+func (x *SynthesizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeResponse.ProtoReflect.Descriptor instead.
+func (*SynthesizeResponse) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SynthesizeResponse) GetPayload() isSynthesizeResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SynthesizeResponse) GetAudioChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*SynthesizeResponse_AudioChunk); ok {
+			return x.AudioChunk
+		}
+	}
+	return nil
+}
+
+func (x *SynthesizeResponse) GetSummary() *SynthesizeSummary {
+	if x != nil {
+		if x, ok := x.Payload.(*SynthesizeResponse_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+func (x *SynthesizeResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Payload.(*SynthesizeResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isSynthesizeResponse_Payload interface {
+	isSynthesizeResponse_Payload()
+}
+
+type SynthesizeResponse_AudioChunk struct {
+	AudioChunk []byte `protobuf:"bytes,1,opt,name=audio_chunk,json=audioChunk,proto3,oneof"`
+}
+
+type SynthesizeResponse_Summary struct {
+	Summary *SynthesizeSummary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+type SynthesizeResponse_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*SynthesizeResponse_AudioChunk) isSynthesizeResponse_Payload() {}
+
+func (*SynthesizeResponse_Summary) isSynthesizeResponse_Payload() {}
+
+func (*SynthesizeResponse_Error) isSynthesizeResponse_Payload() {}
+
+type AnalyzeConfig struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ExpectedText string                 `protobuf:"bytes,1,opt,name=expected_text,json=expectedText,proto3" json:"expected_text,omitempty"`
+	Language     string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	// "fast" (smaller model) or "accurate"; empty means accurate
+	Quality       string `protobuf:"bytes,3,opt,name=quality,proto3" json:"quality,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeConfig) Reset() {
+	*x = AnalyzeConfig{}
+	mi := &file_ml_v1_ml_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeConfig) ProtoMessage() {}
+
+func (x *AnalyzeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeConfig.ProtoReflect.Descriptor instead.
+func (*AnalyzeConfig) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AnalyzeConfig) GetExpectedText() string {
+	if x != nil {
+		return x.ExpectedText
+	}
+	return ""
+}
+
+func (x *AnalyzeConfig) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *AnalyzeConfig) GetQuality() string {
+	if x != nil {
+		return x.Quality
+	}
+	return ""
+}
+
+type AnalyzePronunciationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*AnalyzePronunciationRequest_Config
+	//	*AnalyzePronunciationRequest_AudioChunk
+	Payload       isAnalyzePronunciationRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzePronunciationRequest) Reset() {
+	*x = AnalyzePronunciationRequest{}
+	mi := &file_ml_v1_ml_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzePronunciationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzePronunciationRequest) ProtoMessage() {}
+
+func (x *AnalyzePronunciationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzePronunciationRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzePronunciationRequest) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AnalyzePronunciationRequest) GetPayload() isAnalyzePronunciationRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *AnalyzePronunciationRequest) GetConfig() *AnalyzeConfig {
+	if x != nil {
+		if x, ok := x.Payload.(*AnalyzePronunciationRequest_Config); ok {
+			return x.Config
+		}
+	}
+	return nil
+}
+
+func (x *AnalyzePronunciationRequest) GetAudioChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*AnalyzePronunciationRequest_AudioChunk); ok {
+			return x.AudioChunk
+		}
+	}
+	return nil
+}
+
+type isAnalyzePronunciationRequest_Payload interface {
+	isAnalyzePronunciationRequest_Payload()
+}
+
+type AnalyzePronunciationRequest_Config struct {
+	Config *AnalyzeConfig `protobuf:"bytes,1,opt,name=config,proto3,oneof"`
+}
+
+type AnalyzePronunciationRequest_AudioChunk struct {
+	AudioChunk []byte `protobuf:"bytes,2,opt,name=audio_chunk,json=audioChunk,proto3,oneof"`
+}
+
+func (*AnalyzePronunciationRequest_Config) isAnalyzePronunciationRequest_Payload() {}
+
+func (*AnalyzePronunciationRequest_AudioChunk) isAnalyzePronunciationRequest_Payload() {}
+
+type PhonemeDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expected      string                 `protobuf:"bytes,1,opt,name=expected,proto3" json:"expected,omitempty"`
+	Actual        string                 `protobuf:"bytes,2,opt,name=actual,proto3" json:"actual,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Position      int32                  `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PhonemeDetail) Reset() {
+	*x = PhonemeDetail{}
+	mi := &file_ml_v1_ml_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PhonemeDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhonemeDetail) ProtoMessage() {}
+
+func (x *PhonemeDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhonemeDetail.ProtoReflect.Descriptor instead.
+func (*PhonemeDetail) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PhonemeDetail) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
+func (x *PhonemeDetail) GetActual() string {
+	if x != nil {
+		return x.Actual
+	}
+	return ""
+}
+
+func (x *PhonemeDetail) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PhonemeDetail) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type AudioQuality struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	QualityScore    float64                `protobuf:"fixed64,1,opt,name=quality_score,json=qualityScore,proto3" json:"quality_score,omitempty"`
+	SnrDb           float64                `protobuf:"fixed64,2,opt,name=snr_db,json=snrDb,proto3" json:"snr_db,omitempty"`
+	DurationSeconds float64                `protobuf:"fixed64,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	Warnings        []string               `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AudioQuality) Reset() {
+	*x = AudioQuality{}
+	mi := &file_ml_v1_ml_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioQuality) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioQuality) ProtoMessage() {}
+
+func (x *AudioQuality) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioQuality.ProtoReflect.Descriptor instead.
+func (*AudioQuality) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AudioQuality) GetQualityScore() float64 {
+	if x != nil {
+		return x.QualityScore
+	}
+	return 0
+}
+
+func (x *AudioQuality) GetSnrDb() float64 {
+	if x != nil {
+		return x.SnrDb
+	}
+	return 0
+}
+
+func (x *AudioQuality) GetDurationSeconds() float64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *AudioQuality) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type PronunciationAnalysis struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AudioIpa          string                 `protobuf:"bytes,1,opt,name=audio_ipa,json=audioIpa,proto3" json:"audio_ipa,omitempty"`
+	ExpectedIpa       string                 `protobuf:"bytes,2,opt,name=expected_ipa,json=expectedIpa,proto3" json:"expected_ipa,omitempty"`
+	PhonemeCount      int32                  `protobuf:"varint,3,opt,name=phoneme_count,json=phonemeCount,proto3" json:"phoneme_count,omitempty"`
+	MatchCount        int32                  `protobuf:"varint,4,opt,name=match_count,json=matchCount,proto3" json:"match_count,omitempty"`
+	SubstitutionCount int32                  `protobuf:"varint,5,opt,name=substitution_count,json=substitutionCount,proto3" json:"substitution_count,omitempty"`
+	DeletionCount     int32                  `protobuf:"varint,6,opt,name=deletion_count,json=deletionCount,proto3" json:"deletion_count,omitempty"`
+	InsertionCount    int32                  `protobuf:"varint,7,opt,name=insertion_count,json=insertionCount,proto3" json:"insertion_count,omitempty"`
+	PhonemeDetails    []*PhonemeDetail       `protobuf:"bytes,8,rep,name=phoneme_details,json=phonemeDetails,proto3" json:"phoneme_details,omitempty"`
+	AudioQuality      *AudioQuality          `protobuf:"bytes,9,opt,name=audio_quality,json=audioQuality,proto3" json:"audio_quality,omitempty"`
+	ProcessingTimeMs  int64                  `protobuf:"varint,10,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"`
+	// Quality level that produced the analysis
+	Quality       string `protobuf:"bytes,11,opt,name=quality,proto3" json:"quality,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PronunciationAnalysis) Reset() {
+	*x = PronunciationAnalysis{}
+	mi := &file_ml_v1_ml_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PronunciationAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PronunciationAnalysis) ProtoMessage() {}
+
+func (x *PronunciationAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PronunciationAnalysis.ProtoReflect.Descriptor instead.
+func (*PronunciationAnalysis) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PronunciationAnalysis) GetAudioIpa() string {
+	if x != nil {
+		return x.AudioIpa
+	}
+	return ""
+}
+
+func (x *PronunciationAnalysis) GetExpectedIpa() string {
+	if x != nil {
+		return x.ExpectedIpa
+	}
+	return ""
+}
+
+func (x *PronunciationAnalysis) GetPhonemeCount() int32 {
+	if x != nil {
+		return x.PhonemeCount
+	}
+	return 0
+}
+
+func (x *PronunciationAnalysis) GetMatchCount() int32 {
+	if x != nil {
+		return x.MatchCount
+	}
+	return 0
+}
+
+func (x *PronunciationAnalysis) GetSubstitutionCount() int32 {
+	if x != nil {
+		return x.SubstitutionCount
+	}
+	return 0
+}
+
+func (x *PronunciationAnalysis) GetDeletionCount() int32 {
+	if x != nil {
+		return x.DeletionCount
+	}
+	return 0
+}
+
+func (x *PronunciationAnalysis) GetInsertionCount() int32 {
+	if x != nil {
+		return x.InsertionCount
+	}
+	return 0
+}
+
+func (x *PronunciationAnalysis) GetPhonemeDetails() []*PhonemeDetail {
+	if x != nil {
+		return x.PhonemeDetails
+	}
+	return nil
+}
+
+func (x *PronunciationAnalysis) GetAudioQuality() *AudioQuality {
+	if x != nil {
+		return x.AudioQuality
+	}
+	return nil
+}
+
+func (x *PronunciationAnalysis) GetProcessingTimeMs() int64 {
+	if x != nil {
+		return x.ProcessingTimeMs
+	}
+	return 0
+}
+
+func (x *PronunciationAnalysis) GetQuality() string {
+	if x != nil {
+		return x.Quality
+	}
+	return ""
+}
+
+type AnalyzePronunciationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Analysis      *PronunciationAnalysis `protobuf:"bytes,1,opt,name=analysis,proto3" json:"analysis,omitempty"`
+	Error         *Error                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzePronunciationResponse) Reset() {
+	*x = AnalyzePronunciationResponse{}
+	mi := &file_ml_v1_ml_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzePronunciationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzePronunciationResponse) ProtoMessage() {}
+
+func (x *AnalyzePronunciationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzePronunciationResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzePronunciationResponse) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AnalyzePronunciationResponse) GetAnalysis() *PronunciationAnalysis {
+	if x != nil {
+		return x.Analysis
+	}
+	return nil
+}
+
+func (x *AnalyzePronunciationResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type SubmitPronunciationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AudioUrl      string                 `protobuf:"bytes,1,opt,name=audio_url,json=audioUrl,proto3" json:"audio_url,omitempty"`
+	ExpectedText  string                 `protobuf:"bytes,2,opt,name=expected_text,json=expectedText,proto3" json:"expected_text,omitempty"`
+	Language      string                 `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	CallbackUrl   string                 `protobuf:"bytes,4,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	CallbackToken string                 `protobuf:"bytes,5,opt,name=callback_token,json=callbackToken,proto3" json:"callback_token,omitempty"`
+	Quality       string                 `protobuf:"bytes,6,opt,name=quality,proto3" json:"quality,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPronunciationRequest) Reset() {
+	*x = SubmitPronunciationRequest{}
+	mi := &file_ml_v1_ml_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPronunciationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPronunciationRequest) ProtoMessage() {}
+
+func (x *SubmitPronunciationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPronunciationRequest.ProtoReflect.Descriptor instead.
+func (*SubmitPronunciationRequest) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubmitPronunciationRequest) GetAudioUrl() string {
+	if x != nil {
+		return x.AudioUrl
+	}
+	return ""
+}
+
+func (x *SubmitPronunciationRequest) GetExpectedText() string {
+	if x != nil {
+		return x.ExpectedText
+	}
+	return ""
+}
+
+func (x *SubmitPronunciationRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *SubmitPronunciationRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *SubmitPronunciationRequest) GetCallbackToken() string {
+	if x != nil {
+		return x.CallbackToken
+	}
+	return ""
+}
+
+func (x *SubmitPronunciationRequest) GetQuality() string {
+	if x != nil {
+		return x.Quality
+	}
+	return ""
+}
+
+type SubmitPronunciationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set when the job was rejected, e.g. models still loading
+	Error         *Error `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPronunciationResponse) Reset() {
+	*x = SubmitPronunciationResponse{}
+	mi := &file_ml_v1_ml_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPronunciationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPronunciationResponse) ProtoMessage() {}
+
+func (x *SubmitPronunciationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPronunciationResponse.ProtoReflect.Descriptor instead.
+func (*SubmitPronunciationResponse) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SubmitPronunciationResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_ml_v1_ml_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{15}
+}
+
+type HealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	ModelLoaded   bool                   `protobuf:"varint,2,opt,name=model_loaded,json=modelLoaded,proto3" json:"model_loaded,omitempty"`
+	QueueDepth    int32                  `protobuf:"varint,3,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_ml_v1_ml_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ml_v1_ml_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_ml_v1_ml_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HealthResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetModelLoaded() bool {
+	if x != nil {
+		return x.ModelLoaded
+	}
+	return false
+}
+
+func (x *HealthResponse) GetQueueDepth() int32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+var File_ml_v1_ml_proto protoreflect.FileDescriptor
+
+const file_ml_v1_ml_proto_rawDesc = "" +
+	"\n" +
+	"\x0eml/v1/ml.proto\x12\rlingapp.ml.v1\"S\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\tretryable\x18\x03 \x01(\bR\tretryable\".\n" +
+	"\x10TranscribeConfig\x12\x1a\n" +
+	"\blanguage\x18\x01 \x01(\tR\blanguage\"|\n" +
+	"\x11TranscribeRequest\x129\n" +
+	"\x06config\x18\x01 \x01(\v2\x1f.lingapp.ml.v1.TranscribeConfigH\x00R\x06config\x12!\n" +
+	"\vaudio_chunk\x18\x02 \x01(\fH\x00R\n" +
+	"audioChunkB\t\n" +
+	"\apayload\"\x8c\x01\n" +
+	"\x12TranscribeResponse\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x1a\n" +
+	"\bduration\x18\x03 \x01(\x01R\bduration\x12*\n" +
+	"\x05error\x18\x04 \x01(\v2\x14.lingapp.ml.v1.ErrorR\x05error\"c\n" +
+	"\x11SynthesizeRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\"\n" +
+	"\fexaggeration\x18\x02 \x01(\x01R\fexaggeration\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\"G\n" +
+	"\x11SynthesizeSummary\x12\x1a\n" +
+	"\bduration\x18\x01 \x01(\x01R\bduration\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"\xae\x01\n" +
+	"\x12SynthesizeResponse\x12!\n" +
+	"\vaudio_chunk\x18\x01 \x01(\fH\x00R\n" +
+	"audioChunk\x12<\n" +
+	"\asummary\x18\x02 \x01(\v2 .lingapp.ml.v1.SynthesizeSummaryH\x00R\asummary\x12,\n" +
+	"\x05error\x18\x03 \x01(\v2\x14.lingapp.ml.v1.ErrorH\x00R\x05errorB\t\n" +
+	"\apayload\"j\n" +
+	"\rAnalyzeConfig\x12#\n" +
+	"\rexpected_text\x18\x01 \x01(\tR\fexpectedText\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x18\n" +
+	"\aquality\x18\x03 \x01(\tR\aquality\"\x83\x01\n" +
+	"\x1bAnalyzePronunciationRequest\x126\n" +
+	"\x06config\x18\x01 \x01(\v2\x1c.lingapp.ml.v1.AnalyzeConfigH\x00R\x06config\x12!\n" +
+	"\vaudio_chunk\x18\x02 \x01(\fH\x00R\n" +
+	"audioChunkB\t\n" +
+	"\apayload\"s\n" +
+	"\rPhonemeDetail\x12\x1a\n" +
+	"\bexpected\x18\x01 \x01(\tR\bexpected\x12\x16\n" +
+	"\x06actual\x18\x02 \x01(\tR\x06actual\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\x05R\bposition\"\x91\x01\n" +
+	"\fAudioQuality\x12#\n" +
+	"\rquality_score\x18\x01 \x01(\x01R\fqualityScore\x12\x15\n" +
+	"\x06snr_db\x18\x02 \x01(\x01R\x05snrDb\x12)\n" +
+	"\x10duration_seconds\x18\x03 \x01(\x01R\x0fdurationSeconds\x12\x1a\n" +
+	"\bwarnings\x18\x04 \x03(\tR\bwarnings\"\xed\x03\n" +
+	"\x15PronunciationAnalysis\x12\x1b\n" +
+	"\taudio_ipa\x18\x01 \x01(\tR\baudioIpa\x12!\n" +
+	"\fexpected_ipa\x18\x02 \x01(\tR\vexpectedIpa\x12#\n" +
+	"\rphoneme_count\x18\x03 \x01(\x05R\fphonemeCount\x12\x1f\n" +
+	"\vmatch_count\x18\x04 \x01(\x05R\n" +
+	"matchCount\x12-\n" +
+	"\x12substitution_count\x18\x05 \x01(\x05R\x11substitutionCount\x12%\n" +
+	"\x0edeletion_count\x18\x06 \x01(\x05R\rdeletionCount\x12'\n" +
+	"\x0finsertion_count\x18\a \x01(\x05R\x0einsertionCount\x12E\n" +
+	"\x0fphoneme_details\x18\b \x03(\v2\x1c.lingapp.ml.v1.PhonemeDetailR\x0ephonemeDetails\x12@\n" +
+	"\raudio_quality\x18\t \x01(\v2\x1b.lingapp.ml.v1.AudioQualityR\faudioQuality\x12,\n" +
+	"\x12processing_time_ms\x18\n" +
+	" \x01(\x03R\x10processingTimeMs\x12\x18\n" +
+	"\aquality\x18\v \x01(\tR\aquality\"\x8c\x01\n" +
+	"\x1cAnalyzePronunciationResponse\x12@\n" +
+	"\banalysis\x18\x01 \x01(\v2$.lingapp.ml.v1.PronunciationAnalysisR\banalysis\x12*\n" +
+	"\x05error\x18\x02 \x01(\v2\x14.lingapp.ml.v1.ErrorR\x05error\"\xde\x01\n" +
+	"\x1aSubmitPronunciationRequest\x12\x1b\n" +
+	"\taudio_url\x18\x01 \x01(\tR\baudioUrl\x12#\n" +
+	"\rexpected_text\x18\x02 \x01(\tR\fexpectedText\x12\x1a\n" +
+	"\blanguage\x18\x03 \x01(\tR\blanguage\x12!\n" +
+	"\fcallback_url\x18\x04 \x01(\tR\vcallbackUrl\x12%\n" +
+	"\x0ecallback_token\x18\x05 \x01(\tR\rcallbackToken\x12\x18\n" +
+	"\aquality\x18\x06 \x01(\tR\aquality\"I\n" +
+	"\x1bSubmitPronunciationResponse\x12*\n" +
+	"\x05error\x18\x01 \x01(\v2\x14.lingapp.ml.v1.ErrorR\x05error\"\x0f\n" +
+	"\rHealthRequest\"l\n" +
+	"\x0eHealthResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12!\n" +
+	"\fmodel_loaded\x18\x02 \x01(\bR\vmodelLoaded\x12\x1f\n" +
+	"\vqueue_depth\x18\x03 \x01(\x05R\n" +
+	"queueDepth2\xdd\x03\n" +
+	"\tMLService\x12S\n" +
+	"\n" +
+	"Transcribe\x12 .lingapp.ml.v1.TranscribeRequest\x1a!.lingapp.ml.v1.TranscribeResponse(\x01\x12S\n" +
+	"\n" +
+	"Synthesize\x12 .lingapp.ml.v1.SynthesizeRequest\x1a!.lingapp.ml.v1.SynthesizeResponse0\x01\x12q\n" +
+	"\x14AnalyzePronunciation\x12*.lingapp.ml.v1.AnalyzePronunciationRequest\x1a+.lingapp.ml.v1.AnalyzePronunciationResponse(\x01\x12l\n" +
+	"\x13SubmitPronunciation\x12).lingapp.ml.v1.SubmitPronunciationRequest\x1a*.lingapp.ml.v1.SubmitPronunciationResponse\x12E\n" +
+	"\x06Health\x12\x1c.lingapp.ml.v1.HealthRequest\x1a\x1d.lingapp.ml.v1.HealthResponseB#Z!ling-app/api/internal/client/mlpbb\x06proto3"
+
+var (
+	file_ml_v1_ml_proto_rawDescOnce sync.Once
+	file_ml_v1_ml_proto_rawDescData []byte
+)
+
+func file_ml_v1_ml_proto_rawDescGZIP() []byte {
+	file_ml_v1_ml_proto_rawDescOnce.Do(func() {
+		file_ml_v1_ml_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ml_v1_ml_proto_rawDesc), len(file_ml_v1_ml_proto_rawDesc)))
+	})
+	return file_ml_v1_ml_proto_rawDescData
+}
+
+var file_ml_v1_ml_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_ml_v1_ml_proto_goTypes = []any{
+	(*Error)(nil),                        // 0: lingapp.ml.v1.Error
+	(*TranscribeConfig)(nil),             // 1: lingapp.ml.v1.TranscribeConfig
+	(*TranscribeRequest)(nil),            // 2: lingapp.ml.v1.TranscribeRequest
+	(*TranscribeResponse)(nil),           // 3: lingapp.ml.v1.TranscribeResponse
+	(*SynthesizeRequest)(nil),            // 4: lingapp.ml.v1.SynthesizeRequest
+	(*SynthesizeSummary)(nil),            // 5: lingapp.ml.v1.SynthesizeSummary
+	(*SynthesizeResponse)(nil),           // 6: lingapp.ml.v1.SynthesizeResponse
+	(*AnalyzeConfig)(nil),                // 7: lingapp.ml.v1.AnalyzeConfig
+	(*AnalyzePronunciationRequest)(nil),  // 8: lingapp.ml.v1.AnalyzePronunciationRequest
+	(*PhonemeDetail)(nil),                // 9: lingapp.ml.v1.PhonemeDetail
+	(*AudioQuality)(nil),                 // 10: lingapp.ml.v1.AudioQuality
+	(*PronunciationAnalysis)(nil),        // 11: lingapp.ml.v1.PronunciationAnalysis
+	(*AnalyzePronunciationResponse)(nil), // 12: lingapp.ml.v1.AnalyzePronunciationResponse
+	(*SubmitPronunciationRequest)(nil),   // 13: lingapp.ml.v1.SubmitPronunciationRequest
+	(*SubmitPronunciationResponse)(nil),  // 14: lingapp.ml.v1.SubmitPronunciationResponse
+	(*HealthRequest)(nil),                // 15: lingapp.ml.v1.HealthRequest
+	(*HealthResponse)(nil),               // 16: lingapp.ml.v1.HealthResponse
+}
+var file_ml_v1_ml_proto_depIdxs = []int32{
+	1,  // 0: lingapp.ml.v1.TranscribeRequest.config:type_name -> lingapp.ml.v1.TranscribeConfig
+	0,  // 1: lingapp.ml.v1.TranscribeResponse.error:type_name -> lingapp.ml.v1.Error
+	5,  // 2: lingapp.ml.v1.SynthesizeResponse.summary:type_name -> lingapp.ml.v1.SynthesizeSummary
+	0,  // 3: lingapp.ml.v1.SynthesizeResponse.error:type_name -> lingapp.ml.v1.Error
+	7,  // 4: lingapp.ml.v1.AnalyzePronunciationRequest.config:type_name -> lingapp.ml.v1.AnalyzeConfig
+	9,  // 5: lingapp.ml.v1.PronunciationAnalysis.phoneme_details:type_name -> lingapp.ml.v1.PhonemeDetail
+	10, // 6: lingapp.ml.v1.PronunciationAnalysis.audio_quality:type_name -> lingapp.ml.v1.AudioQuality
+	11, // 7: lingapp.ml.v1.AnalyzePronunciationResponse.analysis:type_name -> lingapp.ml.v1.PronunciationAnalysis
+	0,  // 8: lingapp.ml.v1.AnalyzePronunciationResponse.error:type_name -> lingapp.ml.v1.Error
+	0,  // 9: lingapp.ml.v1.SubmitPronunciationResponse.error:type_name -> lingapp.ml.v1.Error
+	2,  // 10: lingapp.ml.v1.MLService.Transcribe:input_type -> lingapp.ml.v1.TranscribeRequest
+	4,  // 11: lingapp.ml.v1.MLService.Synthesize:input_type -> lingapp.ml.v1.SynthesizeRequest
+	8,  // 12: lingapp.ml.v1.MLService.AnalyzePronunciation:input_type -> lingapp.ml.v1.AnalyzePronunciationRequest
+	13, // 13: lingapp.ml.v1.MLService.SubmitPronunciation:input_type -> lingapp.ml.v1.SubmitPronunciationRequest
+	15, // 14: lingapp.ml.v1.MLService.Health:input_type -> lingapp.ml.v1.HealthRequest
+	3,  // 15: lingapp.ml.v1.MLService.Transcribe:output_type -> lingapp.ml.v1.TranscribeResponse
+	6,  // 16: lingapp.ml.v1.MLService.Synthesize:output_type -> lingapp.ml.v1.SynthesizeResponse
+	12, // 17: lingapp.ml.v1.MLService.AnalyzePronunciation:output_type -> lingapp.ml.v1.AnalyzePronunciationResponse
+	14, // 18: lingapp.ml.v1.MLService.SubmitPronunciation:output_type -> lingapp.ml.v1.SubmitPronunciationResponse
+	16, // 19: lingapp.ml.v1.MLService.Health:output_type -> lingapp.ml.v1.HealthResponse
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_ml_v1_ml_proto_init() }
+func file_ml_v1_ml_proto_init() {
+	if File_ml_v1_ml_proto != nil {
+		return
+	}
+	file_ml_v1_ml_proto_msgTypes[2].OneofWrappers = []any{
+		(*TranscribeRequest_Config)(nil),
+		(*TranscribeRequest_AudioChunk)(nil),
+	}
+	file_ml_v1_ml_proto_msgTypes[6].OneofWrappers = []any{
+		(*SynthesizeResponse_AudioChunk)(nil),
+		(*SynthesizeResponse_Summary)(nil),
+		(*SynthesizeResponse_Error)(nil),
+	}
+	file_ml_v1_ml_proto_msgTypes[8].OneofWrappers = []any{
+		(*AnalyzePronunciationRequest_Config)(nil),
+		(*AnalyzePronunciationRequest_AudioChunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ml_v1_ml_proto_rawDesc), len(file_ml_v1_ml_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ml_v1_ml_proto_goTypes,
+		DependencyIndexes: file_ml_v1_ml_proto_depIdxs,
+		MessageInfos:      file_ml_v1_ml_proto_msgTypes,
+	}.Build()
+	File_ml_v1_ml_proto = out.File
+	file_ml_v1_ml_proto_goTypes = nil
+	file_ml_v1_ml_proto_depIdxs = nil
+}
@@ -0,0 +1,286 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: ml/v1/ml.proto
+
+// Contract between the API and the ML service. The HTTP/JSON endpoints under
+// /api/v1 remain the default; this service carries the same operations with
+// audio streamed as raw bytes instead of presigned URLs and base64 bodies.
+//
+// Go stubs live in api/internal/client/mlpb; regenerate with `go generate`
+// from that directory.
+
+package mlpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MLService_Transcribe_FullMethodName           = "/lingapp.ml.v1.MLService/Transcribe"
+	MLService_Synthesize_FullMethodName           = "/lingapp.ml.v1.MLService/Synthesize"
+	MLService_AnalyzePronunciation_FullMethodName = "/lingapp.ml.v1.MLService/AnalyzePronunciation"
+	MLService_SubmitPronunciation_FullMethodName  = "/lingapp.ml.v1.MLService/SubmitPronunciation"
+	MLService_Health_FullMethodName               = "/lingapp.ml.v1.MLService/Health"
+)
+
+// MLServiceClient is the client API for MLService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MLServiceClient interface {
+	// Transcribe speech to text. The first message carries the config; every
+	// following message carries a chunk of the audio file.
+	Transcribe(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[TranscribeRequest, TranscribeResponse], error)
+	// Synthesize speech. The audio is streamed back in chunks, followed by a
+	// final message with the summary.
+	Synthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SynthesizeResponse], error)
+	// Analyze pronunciation against the expected text. Streams like Transcribe.
+	AnalyzePronunciation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AnalyzePronunciationRequest, AnalyzePronunciationResponse], error)
+	// Queue pronunciation analysis; the result is POSTed to the callback URL.
+	// The job outlives the call, so the audio is fetched from a URL.
+	SubmitPronunciation(ctx context.Context, in *SubmitPronunciationRequest, opts ...grpc.CallOption) (*SubmitPronunciationResponse, error)
+	// Health and current load.
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+}
+
+type mLServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMLServiceClient(cc grpc.ClientConnInterface) MLServiceClient {
+	return &mLServiceClient{cc}
+}
+
+func (c *mLServiceClient) Transcribe(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[TranscribeRequest, TranscribeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MLService_ServiceDesc.Streams[0], MLService_Transcribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TranscribeRequest, TranscribeResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MLService_TranscribeClient = grpc.ClientStreamingClient[TranscribeRequest, TranscribeResponse]
+
+func (c *mLServiceClient) Synthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SynthesizeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MLService_ServiceDesc.Streams[1], MLService_Synthesize_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SynthesizeRequest, SynthesizeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MLService_SynthesizeClient = grpc.ServerStreamingClient[SynthesizeResponse]
+
+func (c *mLServiceClient) AnalyzePronunciation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AnalyzePronunciationRequest, AnalyzePronunciationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MLService_ServiceDesc.Streams[2], MLService_AnalyzePronunciation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AnalyzePronunciationRequest, AnalyzePronunciationResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MLService_AnalyzePronunciationClient = grpc.ClientStreamingClient[AnalyzePronunciationRequest, AnalyzePronunciationResponse]
+
+func (c *mLServiceClient) SubmitPronunciation(ctx context.Context, in *SubmitPronunciationRequest, opts ...grpc.CallOption) (*SubmitPronunciationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitPronunciationResponse)
+	err := c.cc.Invoke(ctx, MLService_SubmitPronunciation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mLServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, MLService_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MLServiceServer is the server API for MLService service.
+// All implementations must embed UnimplementedMLServiceServer
+// for forward compatibility.
+type MLServiceServer interface {
+	// Transcribe speech to text. The first message carries the config; every
+	// following message carries a chunk of the audio file.
+	Transcribe(grpc.ClientStreamingServer[TranscribeRequest, TranscribeResponse]) error
+	// Synthesize speech. The audio is streamed back in chunks, followed by a
+	// final message with the summary.
+	Synthesize(*SynthesizeRequest, grpc.ServerStreamingServer[SynthesizeResponse]) error
+	// Analyze pronunciation against the expected text. Streams like Transcribe.
+	AnalyzePronunciation(grpc.ClientStreamingServer[AnalyzePronunciationRequest, AnalyzePronunciationResponse]) error
+	// Queue pronunciation analysis; the result is POSTed to the callback URL.
+	// The job outlives the call, so the audio is fetched from a URL.
+	SubmitPronunciation(context.Context, *SubmitPronunciationRequest) (*SubmitPronunciationResponse, error)
+	// Health and current load.
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	mustEmbedUnimplementedMLServiceServer()
+}
+
+// UnimplementedMLServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMLServiceServer struct{}
+
+func (UnimplementedMLServiceServer) Transcribe(grpc.ClientStreamingServer[TranscribeRequest, TranscribeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Transcribe not implemented")
+}
+func (UnimplementedMLServiceServer) Synthesize(*SynthesizeRequest, grpc.ServerStreamingServer[SynthesizeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Synthesize not implemented")
+}
+func (UnimplementedMLServiceServer) AnalyzePronunciation(grpc.ClientStreamingServer[AnalyzePronunciationRequest, AnalyzePronunciationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AnalyzePronunciation not implemented")
+}
+func (UnimplementedMLServiceServer) SubmitPronunciation(context.Context, *SubmitPronunciationRequest) (*SubmitPronunciationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPronunciation not implemented")
+}
+func (UnimplementedMLServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedMLServiceServer) mustEmbedUnimplementedMLServiceServer() {}
+func (UnimplementedMLServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeMLServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MLServiceServer will
+// result in compilation errors.
+type UnsafeMLServiceServer interface {
+	mustEmbedUnimplementedMLServiceServer()
+}
+
+func RegisterMLServiceServer(s grpc.ServiceRegistrar, srv MLServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMLServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MLService_ServiceDesc, srv)
+}
+
+func _MLService_Transcribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MLServiceServer).Transcribe(&grpc.GenericServerStream[TranscribeRequest, TranscribeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MLService_TranscribeServer = grpc.ClientStreamingServer[TranscribeRequest, TranscribeResponse]
+
+func _MLService_Synthesize_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SynthesizeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MLServiceServer).Synthesize(m, &grpc.GenericServerStream[SynthesizeRequest, SynthesizeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MLService_SynthesizeServer = grpc.ServerStreamingServer[SynthesizeResponse]
+
+func _MLService_AnalyzePronunciation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MLServiceServer).AnalyzePronunciation(&grpc.GenericServerStream[AnalyzePronunciationRequest, AnalyzePronunciationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MLService_AnalyzePronunciationServer = grpc.ClientStreamingServer[AnalyzePronunciationRequest, AnalyzePronunciationResponse]
+
+func _MLService_SubmitPronunciation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitPronunciationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MLServiceServer).SubmitPronunciation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MLService_SubmitPronunciation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MLServiceServer).SubmitPronunciation(ctx, req.(*SubmitPronunciationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MLService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MLServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MLService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MLServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MLService_ServiceDesc is the grpc.ServiceDesc for MLService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MLService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lingapp.ml.v1.MLService",
+	HandlerType: (*MLServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitPronunciation",
+			Handler:    _MLService_SubmitPronunciation_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _MLService_Health_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Transcribe",
+			Handler:       _MLService_Transcribe_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Synthesize",
+			Handler:       _MLService_Synthesize_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "AnalyzePronunciation",
+			Handler:       _MLService_AnalyzePronunciation_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ml/v1/ml.proto",
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/client"
+)
+
+// MockEmailClient is a mock implementation of EmailClient for testing.
+type MockEmailClient struct {
+	mock.Mock
+}
+
+// Ensure MockEmailClient implements client.EmailClient.
+var _ client.EmailClient = (*MockEmailClient)(nil)
+
+func (m *MockEmailClient) Send(ctx context.Context, to, subject, body string) error {
+	args := m.Called(ctx, to, subject, body)
+	return args.Error(0)
+}
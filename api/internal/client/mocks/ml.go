@@ -16,10 +16,23 @@ type MockMLClient struct {
 // Ensure MockMLClient implements client.MLClient.
 var _ client.MLClient = (*MockMLClient)(nil)
 
-func (m *MockMLClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string) (*client.PronunciationResponse, error) {
-	args := m.Called(ctx, audioURL, expectedText, language)
+func (m *MockMLClient) AnalyzePronunciation(ctx context.Context, audioURL, expectedText, language string, quality client.AnalysisQuality) (*client.PronunciationResponse, error) {
+	args := m.Called(ctx, audioURL, expectedText, language, quality)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*client.PronunciationResponse), args.Error(1)
 }
+
+func (m *MockMLClient) SubmitPronunciation(ctx context.Context, audioURL, expectedText, language string, quality client.AnalysisQuality, callbackURL, callbackToken string) error {
+	args := m.Called(ctx, audioURL, expectedText, language, quality, callbackURL, callbackToken)
+	return args.Error(0)
+}
+
+func (m *MockMLClient) Health(ctx context.Context) (*client.MLHealth, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.MLHealth), args.Error(1)
+}
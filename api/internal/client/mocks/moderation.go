@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/client"
+)
+
+// MockModerationClient is a mock implementation of ModerationClient for testing.
+type MockModerationClient struct {
+	mock.Mock
+}
+
+// Ensure MockModerationClient implements client.ModerationClient.
+var _ client.ModerationClient = (*MockModerationClient)(nil)
+
+func (m *MockModerationClient) Moderate(ctx context.Context, text string) (*client.ModerationResult, error) {
+	args := m.Called(ctx, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.ModerationResult), args.Error(1)
+}
@@ -23,3 +23,29 @@ func (m *MockOpenAIClient) GenerateTitle(content string) (string, error) {
 	args := m.Called(content)
 	return args.String(0), args.Error(1)
 }
+
+func (m *MockOpenAIClient) EvaluateGoal(goal string, messages []client.ConversationMessage) (bool, error) {
+	args := m.Called(goal, messages)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOpenAIClient) SuggestReplies(messages []client.ConversationMessage) ([]string, error) {
+	args := m.Called(messages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockOpenAIClient) ExtractLearnerFacts(known client.LearnerFacts, messages []client.ConversationMessage) (*client.LearnerFacts, error) {
+	args := m.Called(known, messages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.LearnerFacts), args.Error(1)
+}
+
+func (m *MockOpenAIClient) PunctuateTranscript(text, locale string) (string, error) {
+	args := m.Called(text, locale)
+	return args.String(0), args.Error(1)
+}
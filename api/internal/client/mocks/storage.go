@@ -28,11 +28,37 @@ func (m *MockStorageClient) GetPresignedURL(ctx context.Context, key string, exp
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockStorageClient) GetObject(ctx context.Context, key string, byteRange string) (*client.StorageObject, error) {
+	args := m.Called(ctx, key, byteRange)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.StorageObject), args.Error(1)
+}
+
+func (m *MockStorageClient) StatObject(ctx context.Context, key string) (*client.ObjectInfo, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.ObjectInfo), args.Error(1)
+}
+
 func (m *MockStorageClient) DeleteAudio(ctx context.Context, key string) error {
 	args := m.Called(ctx, key)
 	return args.Error(0)
 }
 
+func (m *MockStorageClient) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	args := m.Called(ctx, prefix)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStorageClient) CopyObject(ctx context.Context, src, dst string) error {
+	args := m.Called(ctx, src, dst)
+	return args.Error(0)
+}
+
 func (m *MockStorageClient) EnsureBucketExists(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -13,8 +13,14 @@ type MockTTSClient struct {
 	mock.Mock
 }
 
-// Ensure MockTTSClient implements client.TTSClient.
-var _ client.TTSClient = (*MockTTSClient)(nil)
+// Ensure MockTTSClient implements client.TTSClient, client.RateSynthesizer,
+// client.LowBitrateSynthesizer and client.LanguageSynthesizer.
+var (
+	_ client.TTSClient             = (*MockTTSClient)(nil)
+	_ client.RateSynthesizer       = (*MockTTSClient)(nil)
+	_ client.LowBitrateSynthesizer = (*MockTTSClient)(nil)
+	_ client.LanguageSynthesizer   = (*MockTTSClient)(nil)
+)
 
 func (m *MockTTSClient) Synthesize(ctx context.Context, text string) (*client.TTSResult, error) {
 	args := m.Called(ctx, text)
@@ -31,3 +37,24 @@ func (m *MockTTSClient) SynthesizeWithOptions(ctx context.Context, text string,
 	}
 	return args.Get(0).(*client.TTSResult), args.Error(1)
 }
+
+func (m *MockTTSClient) SynthesizeAtRate(ctx context.Context, text string, rate float64) (*client.TTSResult, error) {
+	args := m.Called(ctx, text, rate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.TTSResult), args.Error(1)
+}
+
+func (m *MockTTSClient) SynthesizeLowBitrate(ctx context.Context, text string, rate float64) (*client.TTSResult, error) {
+	args := m.Called(ctx, text, rate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.TTSResult), args.Error(1)
+}
+
+func (m *MockTTSClient) ForLanguage(language string) client.TTSClient {
+	args := m.Called(language)
+	return args.Get(0).(client.TTSClient)
+}
@@ -16,8 +16,8 @@ type MockWhisperClient struct {
 // Ensure MockWhisperClient implements client.WhisperClient.
 var _ client.WhisperClient = (*MockWhisperClient)(nil)
 
-func (m *MockWhisperClient) TranscribeFromURL(ctx context.Context, audioURL string) (*client.TranscriptionResult, error) {
-	args := m.Called(ctx, audioURL)
+func (m *MockWhisperClient) TranscribeFromURL(ctx context.Context, audioURL, language string) (*client.TranscriptionResult, error) {
+	args := m.Called(ctx, audioURL, language)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// openaiModerationClient implements ModerationClient using the OpenAI moderation API.
+type openaiModerationClient struct {
+	client *openai.Client
+}
+
+// NewModerationClient creates a new OpenAI moderation client.
+func NewModerationClient(apiKey string) ModerationClient {
+	return &openaiModerationClient{
+		client: openai.NewClient(apiKey),
+	}
+}
+
+// Moderate classifies text with the omni moderation model.
+func (c *openaiModerationClient) Moderate(ctx context.Context, text string) (*ModerationResult, error) {
+	resp, err := c.client.Moderations(ctx, openai.ModerationRequest{
+		Input: text,
+		Model: openai.ModerationOmniLatest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate text: %w", err)
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, fmt.Errorf("no moderation results returned from OpenAI")
+	}
+
+	result := resp.Results[0]
+	return &ModerationResult{
+		Flagged:    result.Flagged,
+		Categories: flaggedCategories(result.Categories),
+	}, nil
+}
+
+// flaggedCategories lists the categories set in c, using OpenAI's names.
+func flaggedCategories(c openai.ResultCategories) []string {
+	categories := []struct {
+		name    string
+		flagged bool
+	}{
+		{"hate", c.Hate},
+		{"hate/threatening", c.HateThreatening},
+		{"harassment", c.Harassment},
+		{"harassment/threatening", c.HarassmentThreatening},
+		{"self-harm", c.SelfHarm},
+		{"self-harm/intent", c.SelfHarmIntent},
+		{"self-harm/instructions", c.SelfHarmInstructions},
+		{"sexual", c.Sexual},
+		{"sexual/minors", c.SexualMinors},
+		{"violence", c.Violence},
+		{"violence/graphic", c.ViolenceGraphic},
+	}
+
+	var flagged []string
+	for _, category := range categories {
+		if category.flagged {
+			flagged = append(flagged, category.name)
+		}
+	}
+	return flagged
+}
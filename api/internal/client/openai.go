@@ -2,7 +2,11 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
 )
@@ -50,6 +54,52 @@ func (c *openaiClient) Generate(messages []ConversationMessage) (string, error)
 	return resp.Choices[0].Message.Content, nil
 }
 
+// GenerateStream is Generate with the reply streamed to onToken as it arrives.
+func (c *openaiClient) GenerateStream(ctx context.Context, messages []ConversationMessage, onToken func(string)) (string, error) {
+	openaiMessages := make([]openai.ChatCompletionMessage, len(messages))
+	for i, msg := range messages {
+		openaiMessages[i] = openai.ChatCompletionMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+
+	stream, err := c.client.CreateChatCompletionStream(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model:    openai.GPT4oMini,
+			Messages: openaiMessages,
+			Stream:   true,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create chat completion stream: %w", err)
+	}
+	defer stream.Close()
+
+	var reply strings.Builder
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read chat completion stream: %w", err)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Delta.Content == "" {
+			continue
+		}
+		token := resp.Choices[0].Delta.Content
+		reply.WriteString(token)
+		onToken(token)
+	}
+
+	if reply.Len() == 0 {
+		return "", fmt.Errorf("no response content returned from OpenAI")
+	}
+	return reply.String(), nil
+}
+
 // GenerateTitle generates a short title (3-5 words) from conversation content.
 func (c *openaiClient) GenerateTitle(content string) (string, error) {
 	resp, err := c.client.CreateChatCompletion(
@@ -80,3 +130,177 @@ func (c *openaiClient) GenerateTitle(content string) (string, error) {
 
 	return resp.Choices[0].Message.Content, nil
 }
+
+// EvaluateGoal asks the model whether the learner has accomplished the goal in the conversation.
+func (c *openaiClient) EvaluateGoal(goal string, messages []ConversationMessage) (bool, error) {
+	var transcript strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, msg.Content)
+	}
+
+	resp, err := c.client.CreateChatCompletion(
+		context.Background(),
+		openai.ChatCompletionRequest{
+			Model: openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role: "system",
+					Content: "You judge language-learning roleplays. Given the learner's goal and the conversation, " +
+						"answer YES if the learner (the user) has fully accomplished the goal, otherwise NO. Answer with one word.",
+				},
+				{
+					Role:    "user",
+					Content: fmt.Sprintf("Goal: %s\n\nConversation:\n%s", goal, transcript.String()),
+				},
+			},
+			MaxTokens: 3,
+		},
+	)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate goal: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return false, fmt.Errorf("no response choices returned from OpenAI")
+	}
+
+	answer := strings.ToUpper(strings.TrimSpace(resp.Choices[0].Message.Content))
+	return strings.HasPrefix(answer, "YES"), nil
+}
+
+// SuggestReplies generates three short replies the learner could say next,
+// pitched at the same difficulty as the conversation so far.
+func (c *openaiClient) SuggestReplies(messages []ConversationMessage) ([]string, error) {
+	openaiMessages := make([]openai.ChatCompletionMessage, 0, len(messages)+1)
+	openaiMessages = append(openaiMessages, openai.ChatCompletionMessage{
+		Role: "system",
+		Content: "Suggest exactly three different short replies the learner (the user) could say next in this conversation. " +
+			"Match the vocabulary and grammar level the learner has been using. " +
+			`Respond with JSON only: {"replies": ["...", "...", "..."]}`,
+	})
+	for _, msg := range messages {
+		openaiMessages = append(openaiMessages, openai.ChatCompletionMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		})
+	}
+
+	resp, err := c.client.CreateChatCompletion(
+		context.Background(),
+		openai.ChatCompletionRequest{
+			Model:          openai.GPT4oMini,
+			Messages:       openaiMessages,
+			ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+			MaxTokens:      200,
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest replies: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response choices returned from OpenAI")
+	}
+
+	var result struct {
+		Replies []string `json:"replies"`
+	}
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse suggested replies: %w", err)
+	}
+
+	if len(result.Replies) > 3 {
+		result.Replies = result.Replies[:3]
+	}
+	return result.Replies, nil
+}
+
+// ExtractLearnerFacts reads a conversation and returns the learner's facts
+// updated with anything new it reveals: their preferred name, interests and
+// mistakes they keep making. known facts are kept unless the conversation
+// contradicts them.
+func (c *openaiClient) ExtractLearnerFacts(known LearnerFacts, messages []ConversationMessage) (*LearnerFacts, error) {
+	knownJSON, err := json.Marshal(known)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode known facts: %w", err)
+	}
+
+	var transcript strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, msg.Content)
+	}
+
+	resp, err := c.client.CreateChatCompletion(
+		context.Background(),
+		openai.ChatCompletionRequest{
+			Model: openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role: "system",
+					Content: "You keep notes about a language learner (the user) for their tutor. " +
+						"Given the current notes and a new conversation, return the updated notes: the name the learner wants to be called, " +
+						"their interests and topics they like to talk about, and language mistakes they make repeatedly (e.g. \"confuses ser and estar\"). " +
+						"Keep existing notes unless the conversation contradicts them. Only record things the learner said or clearly did; " +
+						"leave out anything sensitive such as health, religion, politics, addresses or contact details. Keep each item under ten words. " +
+						`Respond with JSON only: {"preferredName": "", "interests": ["..."], "recurringMistakes": ["..."]}`,
+				},
+				{
+					Role:    "user",
+					Content: fmt.Sprintf("Current notes: %s\n\nConversation:\n%s", knownJSON, transcript.String()),
+				},
+			},
+			ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+			MaxTokens:      300,
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract learner facts: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response choices returned from OpenAI")
+	}
+
+	var facts LearnerFacts
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &facts); err != nil {
+		return nil, fmt.Errorf("failed to parse learner facts: %w", err)
+	}
+	return &facts, nil
+}
+
+// PunctuateTranscript restores punctuation and capitalization to a speech
+// transcript in the given locale, leaving the words themselves unchanged.
+func (c *openaiClient) PunctuateTranscript(text, locale string) (string, error) {
+	resp, err := c.client.CreateChatCompletion(
+		context.Background(),
+		openai.ChatCompletionRequest{
+			Model: openai.GPT4oMini,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role: "system",
+					Content: fmt.Sprintf("You restore punctuation and capitalization to speech transcripts in %s. ", locale) +
+						"Add sentence punctuation and fix capitalization only. Do not add, remove, reorder, translate or correct any words, " +
+						"even if they are grammatically wrong: the transcript is a language learner's speech. Return only the transcript.",
+				},
+				{
+					Role:    "user",
+					Content: text,
+				},
+			},
+			MaxTokens: len(text)/2 + 50,
+		},
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to punctuate transcript: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response choices returned from OpenAI")
+	}
+
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
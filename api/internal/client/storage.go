@@ -2,14 +2,17 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
 )
 
 // storageClient implements StorageClient using S3/MinIO.
@@ -95,6 +98,63 @@ func (s *storageClient) GetPresignedURL(ctx context.Context, key string, expirat
 	return request.URL, nil
 }
 
+// GetObject streams an object from S3/MinIO.
+// byteRange is an optional HTTP Range header value (e.g. "bytes=0-1023").
+func (s *storageClient) GetObject(ctx context.Context, key string, byteRange string) (*StorageObject, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+
+	out, err := s.client.GetObject(ctx, input)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.ErrorCode() {
+			case "NoSuchKey", "NotFound":
+				return nil, ErrObjectNotFound
+			case "InvalidRange":
+				return nil, ErrInvalidRange
+			}
+		}
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+
+	return &StorageObject{
+		Body:          out.Body,
+		ContentType:   aws.ToString(out.ContentType),
+		ContentLength: aws.ToInt64(out.ContentLength),
+		ContentRange:  aws.ToString(out.ContentRange),
+		ETag:          aws.ToString(out.ETag),
+		LastModified:  out.LastModified,
+	}, nil
+}
+
+// StatObject returns an object's size and content metadata without downloading it.
+func (s *storageClient) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	return &ObjectInfo{
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         aws.ToString(out.ETag),
+		LastModified: out.LastModified,
+	}, nil
+}
+
 // DeleteAudio deletes an audio file from S3/MinIO.
 func (s *storageClient) DeleteAudio(ctx context.Context, key string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
@@ -107,6 +167,47 @@ func (s *storageClient) DeleteAudio(ctx context.Context, key string) error {
 	return nil
 }
 
+// DeletePrefix deletes every object under a key prefix, one page of the
+// listing at a time.
+func (s *storageClient) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	deleted := 0
+	pages := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if err := s.DeleteAudio(ctx, aws.ToString(obj.Key)); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// CopyObject copies an object within the bucket. Its metadata, including the
+// content type, is copied with it.
+func (s *storageClient) CopyObject(ctx context.Context, src, dst string) error {
+	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		CopySource: aws.String((&url.URL{Path: s.bucket + "/" + src}).EscapedPath()),
+		Key:        aws.String(dst),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NoSuchKey" || apiErr.ErrorCode() == "NotFound") {
+			return ErrObjectNotFound
+		}
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	return nil
+}
+
 // EnsureBucketExists creates the bucket if it doesn't exist.
 func (s *storageClient) EnsureBucketExists(ctx context.Context) error {
 	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// fallbackStorage reads an object from a second key when the first one is
+// gone, for keys that moved to another naming scheme
+type fallbackStorage struct {
+	StorageClient
+	fallback func(key string) (string, bool)
+}
+
+// NewFallbackStorage wraps a storage client so reads of a key that no longer
+// exists are served from fallback(key) instead, when fallback maps it.
+// Presigning such a key costs a stat first, since a URL for a missing object
+// only fails once it's played. Writes and deletes go to the key as given.
+func NewFallbackStorage(inner StorageClient, fallback func(key string) (string, bool)) StorageClient {
+	return &fallbackStorage{StorageClient: inner, fallback: fallback}
+}
+
+func (s *fallbackStorage) GetPresignedURL(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	if other, ok := s.fallback(key); ok {
+		if _, err := s.StorageClient.StatObject(ctx, key); errors.Is(err, ErrObjectNotFound) {
+			key = other
+		}
+	}
+	return s.StorageClient.GetPresignedURL(ctx, key, expiration)
+}
+
+func (s *fallbackStorage) GetObject(ctx context.Context, key string, byteRange string) (*StorageObject, error) {
+	obj, err := s.StorageClient.GetObject(ctx, key, byteRange)
+	if errors.Is(err, ErrObjectNotFound) {
+		if other, ok := s.fallback(key); ok {
+			return s.StorageClient.GetObject(ctx, other, byteRange)
+		}
+	}
+	return obj, err
+}
+
+func (s *fallbackStorage) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := s.StorageClient.StatObject(ctx, key)
+	if errors.Is(err, ErrObjectNotFound) {
+		if other, ok := s.fallback(key); ok {
+			return s.StorageClient.StatObject(ctx, other)
+		}
+	}
+	return info, err
+}
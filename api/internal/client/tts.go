@@ -15,6 +15,19 @@ import (
 type openAITTSClient struct {
 	apiKey     string
 	httpClient *http.Client
+	voice      string
+}
+
+// defaultOpenAIVoice speaks English and languages without a voice of their own
+const defaultOpenAIVoice = "alloy" // Options: alloy, echo, fable, onyx, nova, shimmer
+
+// openAIVoices is the voice each language is spoken in, by ISO 639-1 code.
+// Every voice speaks every language; giving each its own tells the learner's
+// threads apart by ear.
+var openAIVoices = map[string]string{
+	"es": "nova",
+	"fr": "shimmer",
+	"de": "onyx",
 }
 
 // NewOpenAITTSClient creates a TTS client using OpenAI TTS API.
@@ -24,20 +37,44 @@ func NewOpenAITTSClient(apiKey string) TTSClient {
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
+		voice: defaultOpenAIVoice,
 	}
 }
 
+// ForLanguage returns a client speaking in language's voice
+func (t *openAITTSClient) ForLanguage(language string) TTSClient {
+	voice, ok := openAIVoices[language]
+	if !ok {
+		voice = defaultOpenAIVoice
+	}
+	return &openAITTSClient{apiKey: t.apiKey, httpClient: t.httpClient, voice: voice}
+}
+
 func (t *openAITTSClient) Synthesize(ctx context.Context, text string) (*TTSResult, error) {
 	return t.SynthesizeWithOptions(ctx, text, 1.0, "mp3")
 }
 
 func (t *openAITTSClient) SynthesizeWithOptions(ctx context.Context, text string, exaggeration float64, format string) (*TTSResult, error) {
+	return t.synthesize(ctx, text, format, 1.0)
+}
+
+// SynthesizeAtRate speaks text faster or slower; OpenAI accepts 0.25 to 4.0.
+func (t *openAITTSClient) SynthesizeAtRate(ctx context.Context, text string, rate float64) (*TTSResult, error) {
+	return t.synthesize(ctx, text, "mp3", rate)
+}
+
+// SynthesizeLowBitrate speaks text as Ogg Opus at rate, for the mobile data variant
+func (t *openAITTSClient) SynthesizeLowBitrate(ctx context.Context, text string, rate float64) (*TTSResult, error) {
+	return t.synthesize(ctx, text, "opus", rate)
+}
+
+func (t *openAITTSClient) synthesize(ctx context.Context, text string, format string, speed float64) (*TTSResult, error) {
 	reqBody := map[string]interface{}{
 		"model":           "tts-1",
 		"input":           text,
-		"voice":           "alloy", // Options: alloy, echo, fable, onyx, nova, shimmer
+		"voice":           t.voice,
 		"response_format": format,
-		"speed":           1.0,
+		"speed":           speed,
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
@@ -68,8 +105,8 @@ func (t *openAITTSClient) SynthesizeWithOptions(ctx context.Context, text string
 		return nil, fmt.Errorf("failed to read audio response: %w", err)
 	}
 
-	// Estimate duration (~150 words/min, ~5 chars/word)
-	estimatedDuration := float64(len(text)) / (5.0 * 150.0 / 60.0)
+	// Estimate duration (~150 words/min at normal speed, ~5 chars/word)
+	estimatedDuration := float64(len(text)) / (5.0 * 150.0 / 60.0) / speed
 
 	return &TTSResult{
 		AudioBytes: audioBytes,
@@ -84,11 +121,13 @@ type mlTTSClient struct {
 }
 
 // NewMLTTSClient creates a TTS client using the ML service (Chatterbox).
-func NewMLTTSClient(mlServiceURL string) TTSClient {
+// Requests are signed when signer is non-nil.
+func NewMLTTSClient(mlServiceURL string, signer *ServiceSigner) TTSClient {
 	return &mlTTSClient{
 		mlServiceURL: mlServiceURL,
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: serviceTransport(signer),
 		},
 	}
 }
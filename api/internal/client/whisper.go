@@ -27,7 +27,7 @@ func NewOpenAIWhisperClient(apiKey string) WhisperClient {
 	}
 }
 
-func (w *openAIWhisperClient) TranscribeFromURL(ctx context.Context, audioURL string) (*TranscriptionResult, error) {
+func (w *openAIWhisperClient) TranscribeFromURL(ctx context.Context, audioURL, language string) (*TranscriptionResult, error) {
 	// Download audio from URL first
 	audioResp, err := http.Get(audioURL)
 	if err != nil {
@@ -67,6 +67,13 @@ func (w *openAIWhisperClient) TranscribeFromURL(ctx context.Context, audioURL st
 		return nil, fmt.Errorf("failed to write response_format field: %w", err)
 	}
 
+	// Transcribe in the learner's language rather than guessing it from an accent
+	if language != "" {
+		if err := writer.WriteField("language", language); err != nil {
+			return nil, fmt.Errorf("failed to write language field: %w", err)
+		}
+	}
+
 	writer.Close()
 
 	// Create request
@@ -115,11 +122,13 @@ type mlWhisperClient struct {
 }
 
 // NewMLWhisperClient creates a Whisper client using the ML service.
-func NewMLWhisperClient(mlServiceURL string) WhisperClient {
+// Requests are signed when signer is non-nil.
+func NewMLWhisperClient(mlServiceURL string, signer *ServiceSigner) WhisperClient {
 	return &mlWhisperClient{
 		mlServiceURL: mlServiceURL,
 		httpClient: &http.Client{
-			Timeout: 120 * time.Second, // 2 minute timeout for transcription
+			Timeout:   120 * time.Second, // 2 minute timeout for transcription
+			Transport: serviceTransport(signer),
 		},
 	}
 }
@@ -143,10 +152,13 @@ type transcribeResponse struct {
 }
 
 // TranscribeFromURL transcribes audio from a presigned URL using the ML service.
-func (w *mlWhisperClient) TranscribeFromURL(ctx context.Context, audioURL string) (*TranscriptionResult, error) {
+func (w *mlWhisperClient) TranscribeFromURL(ctx context.Context, audioURL, language string) (*TranscriptionResult, error) {
 	reqBody := transcribeRequest{
 		AudioURL: audioURL,
 	}
+	if language != "" {
+		reqBody.Language = &language
+	}
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -193,9 +205,9 @@ func (w *mlWhisperClient) TranscribeFromURL(ctx context.Context, audioURL string
 		text = *result.Text
 	}
 
-	language := ""
+	detected := ""
 	if result.Language != nil {
-		language = *result.Language
+		detected = *result.Language
 	}
 
 	duration := 0.0
@@ -205,7 +217,7 @@ func (w *mlWhisperClient) TranscribeFromURL(ctx context.Context, audioURL string
 
 	return &TranscriptionResult{
 		Text:     text,
-		Language: language,
+		Language: detected,
 		Duration: duration,
 	}, nil
 }
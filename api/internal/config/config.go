@@ -3,9 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
-	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,13 +18,44 @@ type Config struct {
 	GinMode  string
 	Environment string
 
+	// Legacy unversioned /api routes. Setting the dates announces the
+	// deprecation (Deprecation/Sunset headers); once clients have moved to
+	// /api/v1, disabling them answers 410 Gone.
+	LegacyAPIDisabled     bool
+	LegacyAPIDeprecatedAt *time.Time
+	LegacyAPISunsetAt     *time.Time
+
 	// Database
-	DatabaseURL string
+	DatabaseURL       string
+	RepositoryBackend string // "gorm" or "pgx" (sqlc/pgx for session and message queries)
 
 	// Session
 	SessionSecret string
 	SessionMaxAge int
 
+	// SessionRefreshPercent slides sessions: once that share of
+	// SessionMaxAge has passed, a request extends the session to a full max
+	// age again. 0 keeps the hard expiry.
+	SessionRefreshPercent int
+
+	// SessionAbsoluteMax is how many seconds after sign-in a session ends
+	// however active it is; sliding never extends it past that
+	SessionAbsoluteMax int
+
+	// Invite-only soft launch: new accounts need an invite code, and
+	// everyone else can join the waitlist
+	InviteOnly bool
+
+	// Guest demo: POST /api/auth/guest starts a 24-hour account without an
+	// email, limited to one thread and GuestMessageLimit voice messages
+	GuestMode          bool
+	GuestMessageLimit  int
+	GuestPurgeInterval int // seconds between purges of expired guests
+
+	// Seconds between retries of stored objects of deleted accounts that
+	// couldn't be deleted yet
+	StorageDeletionSweepInterval int
+
 	// OAuth
 	GoogleClientID     string
 	GoogleClientSecret string
@@ -37,6 +69,61 @@ type Config struct {
 	MLServiceURL     string
 	MLServiceTimeout int // timeout in seconds for ML service calls
 
+	// MLTransport is "http" (JSON, default) or "grpc". With gRPC, pronunciation
+	// analysis and ML-backed STT/TTS all go to MLGRPCAddr, streaming audio.
+	// gRPC is refused until the ML service serves it.
+	MLTransport string
+	MLGRPCAddr  string // host:port of the ML service's gRPC server
+
+	// gRPC calls authenticate with mutual TLS: the API's client certificate
+	// and key, and the CA that signed the ML service's certificate
+	MLGRPCCertFile string
+	MLGRPCKeyFile  string
+	MLGRPCCAFile   string
+
+	// Async pronunciation analysis: the ML service posts results to
+	// MLCallbackURL instead of the API waiting on the HTTP call
+	MLAsyncCallbacks bool
+	MLCallbackURL    string
+	MLCallbackSecret string // signs the per-job callback token
+
+	// MLShadowURL is a second ML service running a model under evaluation.
+	// The share of analyses set by the mlShadowPercent runtime setting is
+	// also sent to it and compared; empty disables shadow analysis.
+	MLShadowURL string
+
+	// FakeML replaces speech-to-text, TTS and pronunciation analysis with
+	// deterministic local fakes, for development without the ML stack
+	FakeML bool
+
+	// Shared secret signing requests between the API and the ML service
+	// (empty disables). Previous secrets are still accepted, for rotation.
+	InternalServiceSecret          string
+	InternalServicePreviousSecrets []string
+
+	// ML load shedding (queue depth thresholds; 0 disables)
+	MLShedQueueDepth     int // reject free-tier voice submissions at this depth
+	MLShedPaidQueueDepth int // reject paid-tier voice submissions at this depth
+	MLHealthPollInterval int // seconds between ML health polls
+
+	// Pronunciation scores below this confidence (0-1) are flagged and excluded from stats
+	PronunciationMinConfidence float64
+
+	// Background job queue
+	JobWorkers        int // concurrent background workers
+	JobPriorityWeight int // priority-lane jobs run per standard-lane job under contention
+	JobMaxWaitSeconds int // standard-lane jobs older than this run next
+	JobMaxDepth       int // jobs each lane holds waiting before new ones are rejected
+
+	// Product analytics (events are batched and written to each enabled sink)
+	AnalyticsDBEnabled     bool
+	AnalyticsBufferSize    int
+	AnalyticsBatchSize     int
+	AnalyticsFlushInterval int // seconds
+	SegmentWriteKey        string
+	PostHogAPIKey          string
+	PostHogHost            string
+
 	// TTS Service (empty = use OpenAI TTS, set to ML service URL for Chatterbox)
 	TTSServiceURL string
 
@@ -45,6 +132,13 @@ type Config struct {
 
 	// OpenAI
 	OpenAIAPIKey string
+	// Estimated tokens per minute shared by all LLM features (0 = no limit).
+	// Background features get part of it; see services.LLMBackgroundShare.
+	OpenAITokensPerMinute int
+
+	// Output safety: screen assistant replies with the OpenAI moderation API
+	// in addition to the built-in word filter
+	OutputModerationEnabled bool
 
 	// S3/MinIO Storage
 	S3Endpoint  string
@@ -53,8 +147,14 @@ type Config struct {
 	S3Bucket    string
 	S3Region    string
 
-	// Audio Limits
-	MaxAudioFileSize int64
+	// Audio delivery (true = stream audio through the API instead of returning presigned URLs)
+	AudioProxyMode bool
+
+	// Also store a low-bitrate Opus copy of each assistant reply for mobile data
+	LowBitrateAudio bool
+
+	// Seconds a presigned audio URL stays valid
+	AudioURLExpiry int
 
 	// CORS
 	CORSAllowedOrigins []string
@@ -66,6 +166,88 @@ type Config struct {
 	StripePricePro      string
 	StripeSuccessURL    string
 	StripeCancelURL     string
+
+	// Prices in other currencies, for customers whose country uses one.
+	// STRIPE_PRICE_BASIC and STRIPE_PRICE_PRO are the default for everyone else.
+	StripeRegionalPrices []StripeRegionalPrice
+
+	// StripeTaxEnabled turns on Stripe Tax: checkout calculates tax from the
+	// billing address, and pricing estimates it per country. The account
+	// needs Stripe Tax set up with its tax registrations first.
+	StripeTaxEnabled bool
+
+	// Cancelled paid plans keep read-only access for this many days before the
+	// downgrade is finalized (0 = downgrade immediately)
+	SubscriptionGraceDays          int
+	SubscriptionGraceSweepInterval int // seconds between checks for expired grace periods
+
+	// Seconds between checks for failed-payment reminders that are due
+	DunningSweepInterval int
+
+	// Seconds between checks for free accounts due their monthly credit refresh
+	FreeCreditRefreshInterval int
+
+	// Seconds between checks for credit reservations held past their expiry
+	ReservationSweepInterval int
+
+	// Transactional email over SMTP (empty host = email disabled; users
+	// still get in-app notifications)
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string
+
+	// Notification emails: at most EmailMaxPerDay instant emails a day (the
+	// rest wait for the daily digest), each held EmailBatchWindow seconds to
+	// batch with later notifications
+	EmailMaxPerDay     int
+	EmailBatchWindow   int
+	EmailSweepInterval int // seconds between checks for notifications to email
+
+	// Email verification for password accounts: the link in the email points
+	// at EmailVerifyURL (GET /api/auth/verify on this API). With
+	// RequireVerifiedEmail, unverified accounts can't share threads.
+	EmailVerifyURL       string
+	RequireVerifiedEmail bool
+
+	// Seconds between purges of recordings past each user's audio retention setting
+	AudioRetentionSweepInterval int
+
+	// Seconds between sweeps moving recordings to the current key scheme
+	// (0 = off)
+	AudioKeyMigrationInterval int
+
+	// Seconds between rollups of feature usage events into daily totals
+	FeatureUsageRollupInterval int
+
+	// Domain events are also posted to this URL, signed with the secret
+	// (empty URL = no webhook)
+	EventsWebhookURL    string
+	EventsWebhookSecret string
+
+	// Nightly warehouse export of anonymized fact tables as Parquet (empty
+	// prefix = disabled). The bucket defaults to S3Bucket; user IDs are
+	// replaced with an HMAC keyed by WarehouseHashKey.
+	WarehouseBucket     string
+	WarehousePrefix     string
+	WarehouseHashKey    string
+	WarehouseExportHour int // UTC hour the nightly export runs
+
+	// Master key (base64, 32 bytes) that wraps each user's data key for
+	// content encryption. Empty = users can't turn encryption on.
+	ContentEncryptionKey string
+
+	// Seconds between reloads of the runtime settings (audio limits, credit
+	// costs, tier limits) from the database
+	RuntimeSettingsRefreshInterval int
+
+	// Seconds each user's last active thread stays cached in memory for
+	// GET /api/bootstrap (0 = no cache)
+	ThreadCacheTTL int
+
+	// Values that were set but couldn't be parsed; reported by Validate
+	loadProblems []string
 }
 
 func Load() *Config {
@@ -74,101 +256,248 @@ func Load() *Config {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	return &Config{
-		Port:     getEnv("PORT", "8080"),
-		Host:     getEnv("HOST", "0.0.0.0"),
-		GinMode:  getEnv("GIN_MODE", "debug"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+	env := &envReader{}
+	cfg := &Config{
+		Port:     env.getEnv("PORT", "8080"),
+		Host:     env.getEnv("HOST", "0.0.0.0"),
+		GinMode:  env.getEnv("GIN_MODE", "debug"),
+		Environment: env.getEnv("ENVIRONMENT", "development"),
+
+		DatabaseURL:       env.getEnv("DATABASE_URL", ""),
+		RepositoryBackend: env.getEnv("REPOSITORY_BACKEND", "gorm"),
 
-		DatabaseURL: getEnv("DATABASE_URL", ""),
+		LegacyAPIDisabled:     env.getEnvBool("LEGACY_API_DISABLED", false),
+		LegacyAPIDeprecatedAt: env.getEnvDate("LEGACY_API_DEPRECATED_AT"),
+		LegacyAPISunsetAt:     env.getEnvDate("LEGACY_API_SUNSET_AT"),
 
-		SessionSecret: getEnv("SESSION_SECRET", ""),
-		SessionMaxAge: 86400, // 24 hours
+		SessionSecret:         env.getEnv("SESSION_SECRET", ""),
+		SessionMaxAge:         86400, // 24 hours
+		SessionRefreshPercent: env.getEnvInt("SESSION_REFRESH_PERCENT", 50),
+		SessionAbsoluteMax:    env.getEnvInt("SESSION_ABSOLUTE_MAX", 2592000), // 30 days
 
-		GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
-		GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
-		GoogleRedirectURL:  getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/auth/google/callback"),
-		GitHubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
-		GitHubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
-		GitHubRedirectURL:  getEnv("GITHUB_REDIRECT_URL", "http://localhost:8080/api/auth/github/callback"),
-		FrontendURL:        getEnv("FRONTEND_URL", "http://localhost:3000"),
+		InviteOnly: env.getEnvBool("INVITE_ONLY", false),
 
-		MLServiceURL:     getEnv("ML_SERVICE_URL", "http://localhost:8000"),
+		GuestMode:          env.getEnvBool("GUEST_MODE", false),
+		GuestMessageLimit:  env.getEnvInt("GUEST_MESSAGE_LIMIT", 5),
+		GuestPurgeInterval: env.getEnvInt("GUEST_PURGE_INTERVAL", 900),
+
+		StorageDeletionSweepInterval: env.getEnvInt("STORAGE_DELETION_SWEEP_INTERVAL", 900),
+
+		GoogleClientID:     env.getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret: env.getEnv("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURL:  env.getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/auth/google/callback"),
+		GitHubClientID:     env.getEnv("GITHUB_CLIENT_ID", ""),
+		GitHubClientSecret: env.getEnv("GITHUB_CLIENT_SECRET", ""),
+		GitHubRedirectURL:  env.getEnv("GITHUB_REDIRECT_URL", "http://localhost:8080/api/auth/github/callback"),
+		FrontendURL:        env.getEnv("FRONTEND_URL", "http://localhost:3000"),
+
+		MLServiceURL:     env.getEnv("ML_SERVICE_URL", "http://localhost:8000"),
 		MLServiceTimeout: 120, // 2 minutes for pronunciation analysis
 
-		TTSServiceURL: getEnv("TTS_SERVICE_URL", ""), // Empty = OpenAI TTS, or set to ML service URL
+		MLTransport: env.getEnv("ML_TRANSPORT", "http"),
+		MLGRPCAddr:  env.getEnv("ML_GRPC_ADDR", "localhost:50051"),
+
+		MLGRPCCertFile: env.getEnv("ML_GRPC_CERT_FILE", ""),
+		MLGRPCKeyFile:  env.getEnv("ML_GRPC_KEY_FILE", ""),
+		MLGRPCCAFile:   env.getEnv("ML_GRPC_CA_FILE", ""),
+
+		MLAsyncCallbacks: env.getEnvBool("ML_ASYNC_CALLBACKS", false),
+		MLCallbackURL:    env.getEnv("ML_CALLBACK_URL", ""),
+		MLCallbackSecret: env.getEnv("ML_CALLBACK_SECRET", ""),
+
+		MLShadowURL: env.getEnv("ML_SHADOW_URL", ""),
+
+		FakeML: env.getEnvBool("FAKE_ML", false),
+
+		InternalServiceSecret:          env.getEnv("INTERNAL_SERVICE_SECRET", ""),
+		InternalServicePreviousSecrets: strings.Split(env.getEnv("INTERNAL_SERVICE_PREVIOUS_SECRETS", ""), ","),
+
+		MLShedQueueDepth:     env.getEnvInt("ML_SHED_QUEUE_DEPTH", 0),
+		MLShedPaidQueueDepth: env.getEnvInt("ML_SHED_PAID_QUEUE_DEPTH", 0),
+		MLHealthPollInterval: env.getEnvInt("ML_HEALTH_POLL_INTERVAL", 5),
+
+		PronunciationMinConfidence: env.getEnvFloat("PRONUNCIATION_MIN_CONFIDENCE", 0.5),
+
+		JobWorkers:        env.getEnvInt("JOB_WORKERS", 4),
+		JobPriorityWeight: env.getEnvInt("JOB_PRIORITY_WEIGHT", 3),
+		JobMaxWaitSeconds: env.getEnvInt("JOB_MAX_WAIT_SECONDS", 30),
+		JobMaxDepth:       env.getEnvInt("JOB_MAX_DEPTH", 1000),
+
+		AnalyticsDBEnabled:     env.getEnvBool("ANALYTICS_DB_ENABLED", true),
+		AnalyticsBufferSize:    env.getEnvInt("ANALYTICS_BUFFER_SIZE", 1000),
+		AnalyticsBatchSize:     env.getEnvInt("ANALYTICS_BATCH_SIZE", 50),
+		AnalyticsFlushInterval: env.getEnvInt("ANALYTICS_FLUSH_INTERVAL", 5),
+		SegmentWriteKey:        env.getEnv("SEGMENT_WRITE_KEY", ""),
+		PostHogAPIKey:          env.getEnv("POSTHOG_API_KEY", ""),
+		PostHogHost:            env.getEnv("POSTHOG_HOST", "https://us.i.posthog.com"),
+
+		TTSServiceURL: env.getEnv("TTS_SERVICE_URL", ""), // Empty = OpenAI TTS, or set to ML service URL
+
+		STTServiceURL: env.getEnv("STT_SERVICE_URL", ""), // Empty = OpenAI Whisper, or set to ML service URL
+
+		OpenAIAPIKey:          env.getEnv("OPENAI_API_KEY", ""),
+		OpenAITokensPerMinute: env.getEnvInt("OPENAI_TOKENS_PER_MINUTE", 200000),
+
+		OutputModerationEnabled: env.getEnvBool("OUTPUT_MODERATION_ENABLED", true),
+
+		S3Endpoint:  env.getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3AccessKey: env.getEnv("S3_ACCESS_KEY", "minioadmin"),
+		S3SecretKey: env.getEnv("S3_SECRET_KEY", "minioadmin"),
+		S3Bucket:    env.getEnv("S3_BUCKET", "ling-app-audio"),
+		S3Region:    env.getEnv("S3_REGION", "us-east-1"),
+
+		AudioProxyMode:  env.getEnvBool("AUDIO_PROXY_MODE", false),
+		LowBitrateAudio: env.getEnvBool("LOW_BITRATE_AUDIO", false),
+		AudioURLExpiry:  env.getEnvInt("AUDIO_URL_EXPIRY", 900),
+
+		CORSAllowedOrigins: strings.Split(env.getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000,http://127.0.0.1:3000"), ","),
+
+		StripeSecretKey:     env.getEnv("STRIPE_SECRET_KEY", ""),
+		StripeWebhookSecret: env.getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		StripePriceBasic:    env.getEnv("STRIPE_PRICE_BASIC", ""),
+		StripePricePro:      env.getEnv("STRIPE_PRICE_PRO", ""),
+		StripeSuccessURL:    env.getEnv("STRIPE_SUCCESS_URL", "http://localhost:3000/subscription/success"),
+		StripeCancelURL:     env.getEnv("STRIPE_CANCEL_URL", "http://localhost:3000/pricing"),
+		StripeTaxEnabled:    env.getEnvBool("STRIPE_TAX_ENABLED", false),
 
-		STTServiceURL: getEnv("STT_SERVICE_URL", ""), // Empty = OpenAI Whisper, or set to ML service URL
+		StripeRegionalPrices: env.getEnvRegionalPrices("STRIPE_REGIONAL_PRICES"),
 
-		OpenAIAPIKey: getEnv("OPENAI_API_KEY", ""),
+		SubscriptionGraceDays:          env.getEnvInt("SUBSCRIPTION_GRACE_DAYS", 7),
+		SubscriptionGraceSweepInterval: env.getEnvInt("SUBSCRIPTION_GRACE_SWEEP_INTERVAL", 3600),
 
-		S3Endpoint:  getEnv("S3_ENDPOINT", "http://localhost:9000"),
-		S3AccessKey: getEnv("S3_ACCESS_KEY", "minioadmin"),
-		S3SecretKey: getEnv("S3_SECRET_KEY", "minioadmin"),
-		S3Bucket:    getEnv("S3_BUCKET", "ling-app-audio"),
-		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		DunningSweepInterval: env.getEnvInt("DUNNING_SWEEP_INTERVAL", 3600),
 
-		MaxAudioFileSize: 10485760, // 10MB
+		FreeCreditRefreshInterval: env.getEnvInt("FREE_CREDIT_REFRESH_INTERVAL", 3600),
 
-		CORSAllowedOrigins: strings.Split(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000,http://127.0.0.1:3000"), ","),
+		ReservationSweepInterval: env.getEnvInt("RESERVATION_SWEEP_INTERVAL", 300),
 
-		StripeSecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
-		StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
-		StripePriceBasic:    getEnv("STRIPE_PRICE_BASIC", ""),
-		StripePricePro:      getEnv("STRIPE_PRICE_PRO", ""),
-		StripeSuccessURL:    getEnv("STRIPE_SUCCESS_URL", "http://localhost:3000/subscription/success"),
-		StripeCancelURL:     getEnv("STRIPE_CANCEL_URL", "http://localhost:3000/pricing"),
+		SMTPHost:     env.getEnv("SMTP_HOST", ""),
+		SMTPPort:     env.getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: env.getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: env.getEnv("SMTP_PASSWORD", ""),
+		EmailFrom:    env.getEnv("EMAIL_FROM", ""),
+
+		EmailMaxPerDay:     env.getEnvInt("EMAIL_MAX_PER_DAY", 3),
+		EmailBatchWindow:   env.getEnvInt("EMAIL_BATCH_WINDOW", 600),
+		EmailSweepInterval: env.getEnvInt("EMAIL_SWEEP_INTERVAL", 60),
+
+		EmailVerifyURL:       env.getEnv("EMAIL_VERIFY_URL", "http://localhost:8080/api/auth/verify"),
+		RequireVerifiedEmail: env.getEnvBool("REQUIRE_VERIFIED_EMAIL", false),
+
+		AudioRetentionSweepInterval: env.getEnvInt("AUDIO_RETENTION_SWEEP_INTERVAL", 3600),
+		AudioKeyMigrationInterval:   env.getEnvInt("AUDIO_KEY_MIGRATION_INTERVAL", 0),
+
+		FeatureUsageRollupInterval: env.getEnvInt("FEATURE_USAGE_ROLLUP_INTERVAL", 900),
+
+		EventsWebhookURL:    env.getEnv("EVENTS_WEBHOOK_URL", ""),
+		EventsWebhookSecret: env.getEnv("EVENTS_WEBHOOK_SECRET", ""),
+
+		WarehouseBucket:     env.getEnv("WAREHOUSE_BUCKET", ""),
+		WarehousePrefix:     strings.Trim(env.getEnv("WAREHOUSE_PREFIX", ""), "/"),
+		WarehouseHashKey:    env.getEnv("WAREHOUSE_HASH_KEY", ""),
+		WarehouseExportHour: env.getEnvInt("WAREHOUSE_EXPORT_HOUR", 2),
+
+		ContentEncryptionKey: env.getEnv("CONTENT_ENCRYPTION_KEY", ""),
+
+		RuntimeSettingsRefreshInterval: env.getEnvInt("RUNTIME_SETTINGS_REFRESH_INTERVAL", 30),
+
+		ThreadCacheTTL: env.getEnvInt("THREAD_CACHE_TTL", 0),
 	}
+	cfg.loadProblems = env.problems
+	return cfg
+}
+
+// envReader reads settings from the environment, remembering values that
+// are set but don't parse so startup can fail instead of using a default
+type envReader struct {
+	problems []string
+}
+
+func (e *envReader) invalid(key, value, want string) {
+	e.problems = append(e.problems, fmt.Sprintf("%s=%q is not %s", key, value, want))
 }
 
-func getEnv(key, defaultValue string) string {
+func (e *envReader) getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
-// Validate checks that required configuration values are set and valid
-func (c *Config) Validate() error {
-	// Required fields (always needed)
-	required := map[string]string{
-		"DATABASE_URL":   c.DatabaseURL,
-		"SESSION_SECRET": c.SessionSecret,
-		"S3_BUCKET":      c.S3Bucket,
+func (e *envReader) getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			e.invalid(key, value, "a boolean (true or false)")
+			return defaultValue
+		}
+		return parsed
 	}
+	return defaultValue
+}
 
-	// In development, S3 endpoint and credentials are required for MinIO
-	// In production, IAM roles are used instead (no explicit credentials needed)
-	if c.Environment != "production" {
-		required["S3_ENDPOINT"] = c.S3Endpoint
-		required["S3_ACCESS_KEY"] = c.S3AccessKey
-		required["S3_SECRET_KEY"] = c.S3SecretKey
+func (e *envReader) getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			e.invalid(key, value, "a whole number")
+			return defaultValue
+		}
+		return parsed
 	}
+	return defaultValue
+}
 
-	for name, value := range required {
-		if value == "" {
-			return fmt.Errorf("required config %s is not set", name)
+func (e *envReader) getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.invalid(key, value, "a number")
+			return defaultValue
 		}
+		return parsed
 	}
+	return defaultValue
+}
 
-	// Validate DATABASE_URL format
-	if _, err := url.Parse(c.DatabaseURL); err != nil {
-		return fmt.Errorf("invalid DATABASE_URL: %w", err)
-	}
+// StripeRegionalPrice is the Stripe price of each paid tier in one currency
+type StripeRegionalPrice struct {
+	Currency string // lowercase ISO 4217 code, as Stripe writes it
+	Basic    string
+	Pro      string
+}
 
-	// Validate SESSION_SECRET length
-	if len(c.SessionSecret) < 32 {
-		return fmt.Errorf("SESSION_SECRET must be at least 32 characters for security")
+// getEnvRegionalPrices parses comma-separated currency:basic_price:pro_price
+// entries, e.g. eur:price_123:price_456,gbp:price_789:price_012
+func (e *envReader) getEnvRegionalPrices(key string) []StripeRegionalPrice {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
 	}
-
-	// Validate that at least one of STT/TTS service URL or OpenAI key is set
-	if c.STTServiceURL == "" && c.OpenAIAPIKey == "" {
-		return fmt.Errorf("either STT_SERVICE_URL or OPENAI_API_KEY must be set for speech-to-text")
+	var prices []StripeRegionalPrice
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 3 || len(parts[0]) != 3 || parts[1] == "" || parts[2] == "" {
+			e.invalid(key, value, "a list of currency:basic_price:pro_price entries")
+			return nil
+		}
+		prices = append(prices, StripeRegionalPrice{Currency: strings.ToLower(parts[0]), Basic: parts[1], Pro: parts[2]})
 	}
+	return prices
+}
 
-	if c.TTSServiceURL == "" && c.OpenAIAPIKey == "" {
-		return fmt.Errorf("either TTS_SERVICE_URL or OPENAI_API_KEY must be set for text-to-speech")
+// getEnvDate parses a date (2006-01-02) or timestamp (RFC 3339), or returns
+// nil when unset
+func (e *envReader) getEnvDate(key string) *time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
 	}
-
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed
+		}
+	}
+	e.invalid(key, value, "a date (2026-11-01) or RFC 3339 timestamp")
 	return nil
 }
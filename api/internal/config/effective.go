@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Setting is one loaded configuration value, named by its environment variable
+type Setting struct {
+	Name  string
+	Value string
+}
+
+const redacted = "[redacted]"
+
+var dsnPassword = regexp.MustCompile(`(password=)\S+`)
+
+// EffectiveSettings lists the configuration the server is running with,
+// secrets masked, so it can be logged at startup
+func (c *Config) EffectiveSettings() []Setting {
+	return []Setting{
+		{"ENVIRONMENT", c.Environment},
+		{"GIN_MODE", c.GinMode},
+		{"HOST", c.Host},
+		{"PORT", c.Port},
+		{"DATABASE_URL", redactDSN(c.DatabaseURL)},
+		{"REPOSITORY_BACKEND", c.RepositoryBackend},
+		{"LEGACY_API_DISABLED", strconv.FormatBool(c.LegacyAPIDisabled)},
+		{"LEGACY_API_DEPRECATED_AT", formatDate(c.LegacyAPIDeprecatedAt)},
+		{"LEGACY_API_SUNSET_AT", formatDate(c.LegacyAPISunsetAt)},
+		{"SESSION_SECRET", secret(c.SessionSecret)},
+		{"SESSION_REFRESH_PERCENT", strconv.Itoa(c.SessionRefreshPercent)},
+		{"SESSION_ABSOLUTE_MAX", strconv.Itoa(c.SessionAbsoluteMax)},
+		{"INVITE_ONLY", strconv.FormatBool(c.InviteOnly)},
+		{"GUEST_MODE", strconv.FormatBool(c.GuestMode)},
+		{"GUEST_MESSAGE_LIMIT", strconv.Itoa(c.GuestMessageLimit)},
+		{"GUEST_PURGE_INTERVAL", strconv.Itoa(c.GuestPurgeInterval)},
+		{"FRONTEND_URL", c.FrontendURL},
+		{"CORS_ALLOWED_ORIGINS", strings.Join(c.CORSAllowedOrigins, ",")},
+		{"GOOGLE_CLIENT_ID", c.GoogleClientID},
+		{"GOOGLE_CLIENT_SECRET", secret(c.GoogleClientSecret)},
+		{"GOOGLE_REDIRECT_URL", c.GoogleRedirectURL},
+		{"GITHUB_CLIENT_ID", c.GitHubClientID},
+		{"GITHUB_CLIENT_SECRET", secret(c.GitHubClientSecret)},
+		{"GITHUB_REDIRECT_URL", c.GitHubRedirectURL},
+		{"OPENAI_API_KEY", secret(c.OpenAIAPIKey)},
+		{"OPENAI_TOKENS_PER_MINUTE", strconv.Itoa(c.OpenAITokensPerMinute)},
+		{"OUTPUT_MODERATION_ENABLED", strconv.FormatBool(c.OutputModerationEnabled)},
+		{"STT_SERVICE_URL", c.STTServiceURL},
+		{"TTS_SERVICE_URL", c.TTSServiceURL},
+		{"ML_SERVICE_URL", c.MLServiceURL},
+		{"ML_TRANSPORT", c.MLTransport},
+		{"ML_GRPC_ADDR", c.MLGRPCAddr},
+		{"ML_GRPC_CERT_FILE", c.MLGRPCCertFile},
+		{"ML_GRPC_KEY_FILE", c.MLGRPCKeyFile},
+		{"ML_GRPC_CA_FILE", c.MLGRPCCAFile},
+		{"ML_ASYNC_CALLBACKS", strconv.FormatBool(c.MLAsyncCallbacks)},
+		{"ML_CALLBACK_URL", c.MLCallbackURL},
+		{"ML_CALLBACK_SECRET", secret(c.MLCallbackSecret)},
+		{"ML_SHADOW_URL", c.MLShadowURL},
+		{"FAKE_ML", strconv.FormatBool(c.FakeML)},
+		{"INTERNAL_SERVICE_SECRET", secret(c.InternalServiceSecret)},
+		{"INTERNAL_SERVICE_PREVIOUS_SECRETS", secret(strings.Join(c.InternalServicePreviousSecrets, ""))},
+		{"ML_SHED_QUEUE_DEPTH", strconv.Itoa(c.MLShedQueueDepth)},
+		{"ML_SHED_PAID_QUEUE_DEPTH", strconv.Itoa(c.MLShedPaidQueueDepth)},
+		{"ML_HEALTH_POLL_INTERVAL", strconv.Itoa(c.MLHealthPollInterval)},
+		{"PRONUNCIATION_MIN_CONFIDENCE", strconv.FormatFloat(c.PronunciationMinConfidence, 'g', -1, 64)},
+		{"JOB_WORKERS", strconv.Itoa(c.JobWorkers)},
+		{"JOB_PRIORITY_WEIGHT", strconv.Itoa(c.JobPriorityWeight)},
+		{"JOB_MAX_WAIT_SECONDS", strconv.Itoa(c.JobMaxWaitSeconds)},
+		{"ANALYTICS_DB_ENABLED", strconv.FormatBool(c.AnalyticsDBEnabled)},
+		{"ANALYTICS_BUFFER_SIZE", strconv.Itoa(c.AnalyticsBufferSize)},
+		{"ANALYTICS_BATCH_SIZE", strconv.Itoa(c.AnalyticsBatchSize)},
+		{"ANALYTICS_FLUSH_INTERVAL", strconv.Itoa(c.AnalyticsFlushInterval)},
+		{"SEGMENT_WRITE_KEY", secret(c.SegmentWriteKey)},
+		{"POSTHOG_API_KEY", secret(c.PostHogAPIKey)},
+		{"POSTHOG_HOST", c.PostHogHost},
+		{"S3_ENDPOINT", c.S3Endpoint},
+		{"S3_ACCESS_KEY", secret(c.S3AccessKey)},
+		{"S3_SECRET_KEY", secret(c.S3SecretKey)},
+		{"S3_BUCKET", c.S3Bucket},
+		{"S3_REGION", c.S3Region},
+		{"AUDIO_PROXY_MODE", strconv.FormatBool(c.AudioProxyMode)},
+		{"LOW_BITRATE_AUDIO", strconv.FormatBool(c.LowBitrateAudio)},
+		{"AUDIO_URL_EXPIRY", strconv.Itoa(c.AudioURLExpiry)},
+		{"AUDIO_RETENTION_SWEEP_INTERVAL", strconv.Itoa(c.AudioRetentionSweepInterval)},
+		{"AUDIO_KEY_MIGRATION_INTERVAL", strconv.Itoa(c.AudioKeyMigrationInterval)},
+		{"FEATURE_USAGE_ROLLUP_INTERVAL", strconv.Itoa(c.FeatureUsageRollupInterval)},
+		{"EVENTS_WEBHOOK_URL", c.EventsWebhookURL},
+		{"EVENTS_WEBHOOK_SECRET", secret(c.EventsWebhookSecret)},
+		{"WAREHOUSE_BUCKET", c.WarehouseBucket},
+		{"WAREHOUSE_PREFIX", c.WarehousePrefix},
+		{"WAREHOUSE_HASH_KEY", secret(c.WarehouseHashKey)},
+		{"WAREHOUSE_EXPORT_HOUR", strconv.Itoa(c.WarehouseExportHour)},
+		{"CONTENT_ENCRYPTION_KEY", secret(c.ContentEncryptionKey)},
+		{"RUNTIME_SETTINGS_REFRESH_INTERVAL", strconv.Itoa(c.RuntimeSettingsRefreshInterval)},
+		{"THREAD_CACHE_TTL", strconv.Itoa(c.ThreadCacheTTL)},
+		{"STRIPE_SECRET_KEY", secret(c.StripeSecretKey)},
+		{"STRIPE_WEBHOOK_SECRET", secret(c.StripeWebhookSecret)},
+		{"STRIPE_PRICE_BASIC", c.StripePriceBasic},
+		{"STRIPE_PRICE_PRO", c.StripePricePro},
+		{"STRIPE_TAX_ENABLED", strconv.FormatBool(c.StripeTaxEnabled)},
+		{"STRIPE_REGIONAL_PRICES", regionalPrices(c.StripeRegionalPrices)},
+		{"STRIPE_SUCCESS_URL", c.StripeSuccessURL},
+		{"STRIPE_CANCEL_URL", c.StripeCancelURL},
+		{"SUBSCRIPTION_GRACE_DAYS", strconv.Itoa(c.SubscriptionGraceDays)},
+		{"SUBSCRIPTION_GRACE_SWEEP_INTERVAL", strconv.Itoa(c.SubscriptionGraceSweepInterval)},
+		{"DUNNING_SWEEP_INTERVAL", strconv.Itoa(c.DunningSweepInterval)},
+		{"SMTP_HOST", c.SMTPHost},
+		{"SMTP_PORT", strconv.Itoa(c.SMTPPort)},
+		{"SMTP_USERNAME", c.SMTPUsername},
+		{"SMTP_PASSWORD", secret(c.SMTPPassword)},
+		{"EMAIL_FROM", c.EmailFrom},
+		{"EMAIL_MAX_PER_DAY", strconv.Itoa(c.EmailMaxPerDay)},
+		{"EMAIL_BATCH_WINDOW", strconv.Itoa(c.EmailBatchWindow)},
+		{"EMAIL_SWEEP_INTERVAL", strconv.Itoa(c.EmailSweepInterval)},
+		{"EMAIL_VERIFY_URL", c.EmailVerifyURL},
+		{"REQUIRE_VERIFIED_EMAIL", strconv.FormatBool(c.RequireVerifiedEmail)},
+	}
+}
+
+// String renders the effective settings one per line
+func (s Setting) String() string {
+	return fmt.Sprintf("%s=%s", s.Name, s.Value)
+}
+
+// secret shows whether a secret is set without showing it
+func secret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactDSN masks the password in either form of postgres connection string
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		return u.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// regionalPrices renders regional prices in the STRIPE_REGIONAL_PRICES format
+func regionalPrices(prices []StripeRegionalPrice) string {
+	entries := make([]string, len(prices))
+	for i, p := range prices {
+		entries[i] = strings.Join([]string{p.Currency, p.Basic, p.Pro}, ":")
+	}
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,161 @@
+// Package crypt encrypts message content with per-user data keys.
+//
+// Each user who turns on content encryption gets a random 256-bit data key,
+// stored wrapped (encrypted) by the master key from CONTENT_ENCRYPTION_KEY.
+// Values are sealed with AES-256-GCM and stored as text:
+//
+//	enc:v1:<user id>:<base64 of nonce || ciphertext>
+//
+// The owner is bound to the ciphertext as additional data, so a sealed value
+// copied into another user's row fails to open.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// SealedPrefix starts every sealed value
+const SealedPrefix = "enc:v1:"
+
+// KeySize is the size of master and data keys in bytes
+const KeySize = 32
+
+var (
+	ErrInvalidKey    = errors.New("key must be 32 bytes, base64-encoded")
+	ErrNotSealed     = errors.New("value is not sealed")
+	ErrDecryptFailed = errors.New("decryption failed")
+)
+
+// Key is an AES-256-GCM key
+type Key struct {
+	aead cipher.AEAD
+}
+
+// NewKey creates a key from KeySize raw bytes
+func NewKey(raw []byte) (*Key, error) {
+	if len(raw) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{aead: aead}, nil
+}
+
+// ParseKey creates a key from its base64 encoding, as in CONTENT_ENCRYPTION_KEY
+func ParseKey(encoded string) (*Key, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return NewKey(raw)
+}
+
+// GenerateDataKey returns a new random data key and its raw bytes, which are
+// what gets wrapped for storage
+func GenerateDataKey() (*Key, []byte, error) {
+	raw := make([]byte, KeySize)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, nil, fmt.Errorf("generate key: %w", err)
+	}
+	key, err := NewKey(raw)
+	return key, raw, err
+}
+
+// Wrap encrypts a data key for the given owner
+func (k *Key) Wrap(owner uuid.UUID, dataKey []byte) []byte {
+	return k.encrypt(owner, dataKey)
+}
+
+// Unwrap decrypts a data key wrapped for the given owner
+func (k *Key) Unwrap(owner uuid.UUID, wrapped []byte) (*Key, error) {
+	raw, err := k.decrypt(owner, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(raw)
+}
+
+// Seal encrypts plaintext for the given owner
+func (k *Key) Seal(owner uuid.UUID, plaintext string) string {
+	sealed := k.encrypt(owner, []byte(plaintext))
+	return SealedPrefix + owner.String() + ":" + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// Open decrypts a value sealed by Seal with this key
+func (k *Key) Open(sealed string) (string, error) {
+	owner, payload, err := split(sealed)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := k.decrypt(owner, payload)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether s is a sealed value
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, SealedPrefix)
+}
+
+// Owner returns the user a sealed value was sealed for, which tells whose
+// key opens it
+func Owner(sealed string) (uuid.UUID, error) {
+	owner, _, err := split(sealed)
+	return owner, err
+}
+
+func split(sealed string) (uuid.UUID, []byte, error) {
+	if !IsSealed(sealed) {
+		return uuid.Nil, nil, ErrNotSealed
+	}
+	ownerText, encoded, ok := strings.Cut(strings.TrimPrefix(sealed, SealedPrefix), ":")
+	if !ok {
+		return uuid.Nil, nil, ErrNotSealed
+	}
+	owner, err := uuid.Parse(ownerText)
+	if err != nil {
+		return uuid.Nil, nil, ErrNotSealed
+	}
+	payload, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return uuid.Nil, nil, ErrNotSealed
+	}
+	return owner, payload, nil
+}
+
+func (k *Key) encrypt(owner uuid.UUID, plaintext []byte) []byte {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		// crypto/rand doesn't fail on supported platforms
+		panic(fmt.Sprintf("crypt: read nonce: %v", err))
+	}
+	return k.aead.Seal(nonce, nonce, plaintext, owner[:])
+}
+
+func (k *Key) decrypt(owner uuid.UUID, payload []byte) ([]byte, error) {
+	if len(payload) < k.aead.NonceSize() {
+		return nil, ErrDecryptFailed
+	}
+	nonce, ciphertext := payload[:k.aead.NonceSize()], payload[k.aead.NonceSize():]
+	plaintext, err := k.aead.Open(nil, nonce, ciphertext, owner[:])
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	return plaintext, nil
+}
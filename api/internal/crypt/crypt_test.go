@@ -0,0 +1,87 @@
+package crypt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMasterKey(t *testing.T) *Key {
+	t.Helper()
+	key, err := ParseKey(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	require.NoError(t, err)
+	return key
+}
+
+func TestParseKey(t *testing.T) {
+	_, err := ParseKey("not base64!")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString([]byte("too short")))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestKey_SealOpen(t *testing.T) {
+	owner := uuid.New()
+	key, _, err := GenerateDataKey()
+	require.NoError(t, err)
+
+	sealed := key.Seal(owner, "¿Dónde está la biblioteca?")
+
+	assert.True(t, IsSealed(sealed))
+	assert.True(t, strings.HasPrefix(sealed, SealedPrefix+owner.String()+":"))
+	assert.NotContains(t, sealed, "biblioteca")
+	assert.NotEqual(t, sealed, key.Seal(owner, "¿Dónde está la biblioteca?"), "every seal uses a fresh nonce")
+
+	got, err := key.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "¿Dónde está la biblioteca?", got)
+
+	gotOwner, err := Owner(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, owner, gotOwner)
+}
+
+func TestKey_Open_Rejects(t *testing.T) {
+	owner := uuid.New()
+	key, _, _ := GenerateDataKey()
+	other, _, _ := GenerateDataKey()
+	sealed := key.Seal(owner, "hola")
+
+	_, err := other.Open(sealed)
+	assert.ErrorIs(t, err, ErrDecryptFailed, "another user's key")
+
+	moved := strings.Replace(sealed, owner.String(), uuid.NewString(), 1)
+	_, err = key.Open(moved)
+	assert.ErrorIs(t, err, ErrDecryptFailed, "the owner is authenticated")
+
+	_, err = key.Open("hola")
+	assert.ErrorIs(t, err, ErrNotSealed)
+	_, err = key.Open(SealedPrefix + "garbage")
+	assert.ErrorIs(t, err, ErrNotSealed)
+	_, err = key.Open(SealedPrefix + owner.String() + ":AAAA")
+	assert.ErrorIs(t, err, ErrDecryptFailed)
+}
+
+func TestKey_WrapUnwrap(t *testing.T) {
+	master := testMasterKey(t)
+	owner := uuid.New()
+	dataKey, raw, err := GenerateDataKey()
+	require.NoError(t, err)
+
+	wrapped := master.Wrap(owner, raw)
+	assert.NotContains(t, string(wrapped), string(raw))
+
+	unwrapped, err := master.Unwrap(owner, wrapped)
+	require.NoError(t, err)
+	got, err := unwrapped.Open(dataKey.Seal(owner, "hola"))
+	require.NoError(t, err)
+	assert.Equal(t, "hola", got)
+
+	_, err = master.Unwrap(uuid.New(), wrapped)
+	assert.ErrorIs(t, err, ErrDecryptFailed, "a wrapped key only unwraps for its owner")
+}
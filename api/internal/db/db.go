@@ -1,32 +1,72 @@
 package db
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 type DB struct {
 	*gorm.DB
+
+	// Pool is a native pgx pool for the sqlc-backed repositories.
+	// It is nil unless OpenPool was called.
+	Pool *pgxpool.Pool
 }
 
 func New(databaseURL string) (*DB, error) {
-	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{})
+	// Timestamps GORM fills in (CreatedAt, UpdatedAt) are taken in UTC, so
+	// nothing depends on the server's local timezone
+	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	log.Println("Database connected successfully")
 
-	return &DB{db}, nil
+	return &DB{DB: db}, nil
 }
 
+// RunMigrations auto-migrates the models, then applies the hand-written Migrations
 func (db *DB) RunMigrations(models ...interface{}) error {
 	if err := db.AutoMigrate(models...); err != nil {
 		return err
 	}
 
+	if err := db.applyMigrations(Migrations); err != nil {
+		return err
+	}
+
 	log.Println("Database migrations completed successfully")
 	return nil
 }
+
+// OpenPool connects the pgx pool used by the sqlc-backed repositories
+func (db *DB) OpenPool(ctx context.Context, databaseURL string) error {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to create pgx pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return fmt.Errorf("failed to connect pgx pool: %w", err)
+	}
+
+	db.Pool = pool
+	log.Println("pgx pool connected successfully")
+	return nil
+}
+
+// ClosePool closes the pgx pool, if one was opened
+func (db *DB) ClosePool() {
+	if db.Pool != nil {
+		db.Pool.Close()
+	}
+}
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Reference is a column set in one table that points at another table's
+// key. Declared references are foreign key constraints; the rest are
+// inferred from naming (user_id -> users.id) because most of the schema is
+// created by AutoMigrate, which only declares the relations on the models.
+type Reference struct {
+	Name       string   `json:"name"`
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+	Declared   bool     `json:"declared"`
+}
+
+// ReferenceViolation is a Reference with rows that point at nothing
+type ReferenceViolation struct {
+	Reference
+	Orphans int64 `json:"orphans"`
+}
+
+// TableRowCounts counts the rows of every table in the current schema
+func (db *DB) TableRowCounts(ctx context.Context) (map[string]int64, error) {
+	var tables []string
+	err := db.WithContext(ctx).Raw(`
+		SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'
+		ORDER BY table_name`).Scan(&tables).Error
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var n int64
+		if err := db.WithContext(ctx).Raw("SELECT count(*) FROM " + quoteIdent(table)).Scan(&n).Error; err != nil {
+			return nil, fmt.Errorf("count %s: %w", table, err)
+		}
+		counts[table] = n
+	}
+	return counts, nil
+}
+
+// References lists the declared foreign keys of the current schema, then the
+// inferred ones: a uuid column named <name>_id in any table, where a table
+// named <name>s has a uuid id column and no constraint already covers it.
+func (db *DB) References(ctx context.Context) ([]Reference, error) {
+	var declared []struct {
+		Name       string
+		Table      string
+		Columns    string
+		RefTable   string
+		RefColumns string
+	}
+	err := db.WithContext(ctx).Raw(`
+		SELECT con.conname AS name,
+			child.relname AS "table",
+			(SELECT string_agg(a.attname, ',' ORDER BY k.ord)
+				FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum) AS columns,
+			parent.relname AS ref_table,
+			(SELECT string_agg(a.attname, ',' ORDER BY k.ord)
+				FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum) AS ref_columns
+		FROM pg_constraint con
+		JOIN pg_class child ON child.oid = con.conrelid
+		JOIN pg_class parent ON parent.oid = con.confrelid
+		JOIN pg_namespace ns ON ns.oid = con.connamespace
+		WHERE con.contype = 'f' AND ns.nspname = current_schema()
+		ORDER BY child.relname, con.conname`).Scan(&declared).Error
+	if err != nil {
+		return nil, fmt.Errorf("list foreign keys: %w", err)
+	}
+
+	refs := make([]Reference, 0, len(declared))
+	covered := map[string]bool{}
+	for _, d := range declared {
+		ref := Reference{
+			Name:       d.Name,
+			Table:      d.Table,
+			Columns:    strings.Split(d.Columns, ","),
+			RefTable:   d.RefTable,
+			RefColumns: strings.Split(d.RefColumns, ","),
+			Declared:   true,
+		}
+		refs = append(refs, ref)
+		if len(ref.Columns) == 1 {
+			covered[ref.Table+"."+ref.Columns[0]] = true
+		}
+	}
+
+	var inferred []struct {
+		Table    string
+		Column   string
+		RefTable string
+	}
+	err = db.WithContext(ctx).Raw(`
+		SELECT c.table_name AS "table", c.column_name AS "column", p.table_name AS ref_table
+		FROM information_schema.columns c
+		JOIN information_schema.columns p
+			ON p.table_schema = c.table_schema
+			AND p.table_name = left(c.column_name, -3) || 's'
+			AND p.column_name = 'id'
+			AND p.data_type = 'uuid'
+		WHERE c.table_schema = current_schema()
+			AND c.column_name LIKE '%\_id'
+			AND c.data_type = 'uuid'
+		ORDER BY c.table_name, c.column_name`).Scan(&inferred).Error
+	if err != nil {
+		return nil, fmt.Errorf("list id columns: %w", err)
+	}
+
+	for _, i := range inferred {
+		if covered[i.Table+"."+i.Column] {
+			continue
+		}
+		refs = append(refs, Reference{
+			Name:       i.Table + "." + i.Column,
+			Table:      i.Table,
+			Columns:    []string{i.Column},
+			RefTable:   i.RefTable,
+			RefColumns: []string{"id"},
+		})
+	}
+	return refs, nil
+}
+
+// ReferenceViolations counts, for every reference, the rows whose columns are
+// all set but match no row of the referenced table. Only references with
+// orphans are returned.
+func (db *DB) ReferenceViolations(ctx context.Context) ([]ReferenceViolation, error) {
+	refs, err := db.References(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := []ReferenceViolation{}
+	for _, ref := range refs {
+		var n int64
+		if err := db.WithContext(ctx).Raw(orphanQuery(ref)).Scan(&n).Error; err != nil {
+			return nil, fmt.Errorf("check %s: %w", ref.Name, err)
+		}
+		if n > 0 {
+			violations = append(violations, ReferenceViolation{Reference: ref, Orphans: n})
+		}
+	}
+	return violations, nil
+}
+
+// RandomUserIDs picks up to n users at random
+func (db *DB) RandomUserIDs(ctx context.Context, n int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).Table("users").Order("random()").Limit(n).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("sample users: %w", err)
+	}
+	return ids, nil
+}
+
+// orphanQuery counts the rows of ref.Table that reference a missing row
+func orphanQuery(ref Reference) string {
+	var set, match []string
+	for i, col := range ref.Columns {
+		set = append(set, "c."+quoteIdent(col)+" IS NOT NULL")
+		match = append(match, "p."+quoteIdent(ref.RefColumns[i])+" = c."+quoteIdent(col))
+	}
+	return fmt.Sprintf("SELECT count(*) FROM %s c WHERE %s AND NOT EXISTS (SELECT 1 FROM %s p WHERE %s)",
+		quoteIdent(ref.Table), strings.Join(set, " AND "), quoteIdent(ref.RefTable), strings.Join(match, " AND "))
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,59 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/testutil"
+)
+
+func TestIntegrity(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	ctx := context.Background()
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Restore"}
+	require.NoError(t, testDB.Create(user).Error)
+	require.NoError(t, testDB.Create(&models.Thread{UserID: user.ID, Locale: "es-MX"}).Error)
+
+	counts, err := testDB.TableRowCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), counts["users"])
+	assert.Equal(t, int64(1), counts["threads"])
+	assert.Contains(t, counts, "schema_migrations")
+
+	refs, err := testDB.References(ctx)
+	require.NoError(t, err)
+	byName := map[string]bool{}
+	for _, ref := range refs {
+		byName[ref.Table+"."+ref.Columns[0]+"->"+ref.RefTable] = ref.Declared
+	}
+	assert.True(t, byName["messages.thread_id->threads"], "declared by the model")
+	declared, found := byName["notifications.user_id->users"]
+	assert.True(t, found, "inferred from the name")
+	assert.False(t, declared)
+
+	violations, err := testDB.ReferenceViolations(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// Notifications carry no constraint on user_id, so an orphan can be written
+	require.NoError(t, testDB.Create(&models.Notification{UserID: uuid.New(), Type: "test", Title: "Orphan"}).Error)
+	violations, err = testDB.ReferenceViolations(ctx)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "notifications.user_id", violations[0].Name)
+	assert.Equal(t, int64(1), violations[0].Orphans)
+
+	ids, err := testDB.RandomUserIDs(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{user.ID}, ids)
+}
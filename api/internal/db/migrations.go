@@ -0,0 +1,134 @@
+package db
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a hand-written schema change for what AutoMigrate can't
+// express from struct tags, such as composite indexes tuned to a query.
+type Migration struct {
+	ID  string
+	SQL string
+}
+
+// Migrations run in order after AutoMigrate. Each one is recorded in
+// schema_migrations and never runs twice, so append new entries rather than
+// editing applied ones.
+var Migrations = []Migration{
+	{
+		// Thread history: WHERE thread_id = ? ORDER BY timestamp
+		ID:  "0001_messages_thread_timestamp",
+		SQL: `CREATE INDEX IF NOT EXISTS idx_messages_thread_timestamp ON messages (thread_id, timestamp)`,
+	},
+	{
+		// Thread lists: WHERE user_id = ? AND archived_at IS [NOT] NULL ORDER BY created_at DESC
+		ID:  "0002_threads_user_archived_created",
+		SQL: `CREATE INDEX IF NOT EXISTS idx_threads_user_archived_created ON threads (user_id, archived_at, created_at)`,
+	},
+	{
+		// Expired session cleanup. AutoMigrate creates this from the model tag;
+		// it is listed so the query plan test doesn't depend on that tag.
+		ID:  "0003_sessions_expires_at",
+		SQL: `CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at)`,
+	},
+	{
+		// Credit history: WHERE user_id = ? ORDER BY created_at DESC LIMIT n
+		ID:  "0004_credit_transactions_user_created",
+		SQL: `CREATE INDEX IF NOT EXISTS idx_credit_transactions_user_created ON credit_transactions (user_id, created_at)`,
+	},
+	{
+		// Per-user phoneme lookups and upserts. Also created from the model's
+		// uniqueIndex tag; same reasoning as sessions.
+		ID:  "0005_phoneme_stats_user_phoneme",
+		SQL: `CREATE UNIQUE INDEX IF NOT EXISTS idx_phoneme_stats_user_phoneme ON phoneme_stats (user_id, phoneme)`,
+	},
+	{
+		// A thread has at most one open practice session
+		ID:  "0006_practice_sessions_open_thread",
+		SQL: `CREATE UNIQUE INDEX IF NOT EXISTS idx_practice_sessions_open_thread ON practice_sessions (thread_id) WHERE ended_at IS NULL`,
+	},
+	{
+		// Phoneme stats are kept per language. Also created from the model's
+		// uniqueIndex tag; listed for the query plan test, like 0005.
+		ID:  "0007_phoneme_stats_user_language_phoneme",
+		SQL: `CREATE UNIQUE INDEX IF NOT EXISTS idx_phoneme_stats_user_language_phoneme ON phoneme_stats (user_id, language, phoneme)`,
+	},
+	{
+		// Superseded by 0007: it would stop a second language's row for the
+		// same symbol
+		ID:  "0008_drop_phoneme_stats_user_phoneme",
+		SQL: `DROP INDEX IF EXISTS idx_phoneme_stats_user_phoneme`,
+	},
+	{
+		// Substitutions are kept per language too; the model's tag creates
+		// the index with the language
+		ID:  "0009_drop_phoneme_subs_user_expected_actual",
+		SQL: `DROP INDEX IF EXISTS idx_phoneme_subs_user_expected_actual`,
+	},
+	{
+		// Subscriptions created for a tier override have no Stripe customer,
+		// so customer IDs are only unique when set. Replaced by 0011 under
+		// the same name, which keeps AutoMigrate from recreating it.
+		ID:  "0010_drop_subscriptions_stripe_customer_id",
+		SQL: `DROP INDEX IF EXISTS idx_subscriptions_stripe_customer_id`,
+	},
+	{
+		ID:  "0011_subscriptions_stripe_customer_id",
+		SQL: `CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_stripe_customer_id ON subscriptions (stripe_customer_id) WHERE stripe_customer_id <> ''`,
+	},
+	{
+		// Reservations made before they expired get the default lifetime
+		ID:  "0012_credit_reservations_expires_at",
+		SQL: `UPDATE credit_reservations SET expires_at = created_at + INTERVAL '30 minutes' WHERE expires_at IS NULL`,
+	},
+}
+
+// schemaMigration records an applied Migration
+type schemaMigration struct {
+	ID        string `gorm:"type:varchar(255);primary_key"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// applyMigrations runs every migration not yet recorded in schema_migrations,
+// each in its own transaction
+func (db *DB) applyMigrations(migrations []Migration) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var applied []string
+	if err := db.Model(&schemaMigration{}).Pluck("id", &applied).Error; err != nil {
+		return fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	done := make(map[string]bool, len(applied))
+	for _, id := range applied {
+		done[id] = true
+	}
+
+	for _, m := range migrations {
+		if done[m.ID] {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.SQL).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.ID, err)
+		}
+		log.Printf("Applied migration %s", m.ID)
+	}
+
+	return nil
+}
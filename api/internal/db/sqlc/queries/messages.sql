@@ -0,0 +1,43 @@
+-- name: CreateMessage :exec
+INSERT INTO messages (
+    id, thread_id, role, content, audio_url, audio_duration_seconds, has_audio,
+    timestamp, suggested_replies, expected_text, pronunciation_status,
+    pronunciation_analysis, pronunciation_error, pronunciation_updated_at,
+    pronunciation_confidence, pronunciation_low_confidence, spoken_text, adaptation,
+    kind, raw_transcript, tone, audio_format, pronunciation_quality, corrections,
+    corrected_message_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+    $22, $23, $24, $25
+);
+
+-- name: GetMessage :one
+SELECT * FROM messages WHERE id = $1;
+
+-- name: ListMessagesByThread :many
+SELECT * FROM messages WHERE thread_id = $1 ORDER BY timestamp ASC;
+
+-- name: CountUserMessagesByUser :one
+SELECT count(*) FROM messages
+WHERE role = 'user' AND thread_id IN (SELECT id FROM threads WHERE user_id = $1);
+
+-- name: UpdatePronunciationStatus :exec
+UPDATE messages SET pronunciation_status = $2 WHERE id = $1;
+
+-- name: UpdatePronunciationAnalysis :exec
+UPDATE messages
+SET pronunciation_status = $2,
+    pronunciation_analysis = $3,
+    pronunciation_confidence = $4,
+    pronunciation_low_confidence = $5,
+    pronunciation_error = NULL,
+    pronunciation_updated_at = $6,
+    pronunciation_quality = $7
+WHERE id = $1;
+
+-- name: UpdatePronunciationError :exec
+UPDATE messages
+SET pronunciation_status = $2,
+    pronunciation_error = $3,
+    pronunciation_updated_at = $4
+WHERE id = $1;
@@ -0,0 +1,18 @@
+-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, user_agent, ip_address, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetSessionWithUser :one
+SELECT sqlc.embed(sessions), sqlc.embed(users)
+FROM sessions
+JOIN users ON users.id = sessions.user_id
+WHERE sessions.id = $1;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions WHERE id = $1;
+
+-- name: DeleteSessionsByUser :exec
+DELETE FROM sessions WHERE user_id = $1;
+
+-- name: DeleteSessionsExpiredBefore :execrows
+DELETE FROM sessions WHERE expires_at < $1;
@@ -0,0 +1,80 @@
+-- Schema for sqlc code generation only. GORM AutoMigrate owns the real
+-- schema; keep these tables in step with the models they mirror.
+
+-- models.User
+CREATE TABLE users (
+    id uuid PRIMARY KEY,
+    email varchar(255) NOT NULL UNIQUE,
+    password_hash varchar(255),
+    name varchar(255),
+    avatar_url varchar(500),
+    google_id varchar(255) UNIQUE,
+    git_hub_id varchar(255) UNIQUE,
+    email_verified boolean DEFAULT false,
+    role varchar(20) NOT NULL DEFAULT 'user',
+    guest_expires_at timestamptz,
+    merged_into_id uuid,
+    merged_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+-- models.Session
+CREATE TABLE sessions (
+    id varchar(64) PRIMARY KEY,
+    user_id uuid NOT NULL,
+    user_agent varchar(500),
+    ip_address varchar(45),
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz
+);
+
+-- models.Thread
+CREATE TABLE threads (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    name varchar(255),
+    archived_at timestamptz,
+    goal text,
+    goal_completed_at timestamptz,
+    suggest_replies boolean DEFAULT false,
+    speech_only boolean DEFAULT false,
+    language varchar(8),
+    locale varchar(35),
+    reply_length varchar(10),
+    speech_rate decimal,
+    ended_at timestamptz,
+    continued_from_id uuid,
+    created_at timestamptz
+);
+
+-- models.Message
+CREATE TABLE messages (
+    id uuid PRIMARY KEY,
+    thread_id uuid NOT NULL REFERENCES threads (id) ON DELETE CASCADE,
+    role varchar(20) NOT NULL,
+    content text NOT NULL,
+    audio_url varchar(500),
+    audio_format varchar(10),
+    audio_duration_seconds decimal(10,2),
+    has_audio boolean DEFAULT false,
+    timestamp timestamptz,
+    suggested_replies jsonb,
+    expected_text text,
+    pronunciation_status varchar(20) DEFAULT 'none',
+    pronunciation_analysis jsonb,
+    pronunciation_error text,
+    pronunciation_updated_at timestamptz,
+    pronunciation_confidence decimal,
+    pronunciation_low_confidence boolean DEFAULT false,
+    spoken_text text,
+    adaptation jsonb,
+    kind varchar(20),
+    raw_transcript text,
+    transcript_corrected_at timestamptz,
+    tone varchar(20),
+    pronunciation_quality varchar(10),
+    pronunciation_retries integer DEFAULT 0,
+    corrections jsonb,
+    corrected_message_id uuid
+);
@@ -0,0 +1,141 @@
+// Package events is an in-process domain event bus. Services publish what
+// happened (a message was processed, an analysis completed) and subscribers
+// react to it, so a new feature registers a subscriber instead of being
+// wired into the code path that triggers it.
+//
+// Publish delivers synchronously, in subscription order, on the caller's
+// goroutine. A subscriber that fails or panics is logged and skipped; the
+// rest still run and the publisher never sees the error. Subscribers with
+// slow work (HTTP calls) should hand it to the job queue.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Event is something that happened in the domain
+type Event interface {
+	// EventName identifies the event type, e.g. "message.processed"
+	EventName() string
+	// EventUser is the user the event is about
+	EventUser() uuid.UUID
+}
+
+// Bus delivers published events to their subscribers. A nil *Bus is valid
+// and drops every event.
+type Bus struct {
+	mu     sync.RWMutex
+	byName map[string][]subscriber
+	all    []subscriber
+}
+
+type subscriber struct {
+	name   string
+	handle func(context.Context, Event) error
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{byName: make(map[string][]subscriber)}
+}
+
+// Subscribe registers handle for every published event of type E. name
+// identifies the subscriber in logs.
+func Subscribe[E Event](b *Bus, name string, handle func(context.Context, E) error) {
+	var zero E
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.byName[zero.EventName()] = append(b.byName[zero.EventName()], subscriber{
+		name: name,
+		handle: func(ctx context.Context, event Event) error {
+			return handle(ctx, event.(E))
+		},
+	})
+}
+
+// SubscribeAll registers handle for every published event, after the
+// subscribers to its type
+func SubscribeAll(b *Bus, name string, handle func(context.Context, Event) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, subscriber{name: name, handle: handle})
+}
+
+// Publish delivers event to its subscribers
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := append(append([]subscriber(nil), b.byName[event.EventName()]...), b.all...)
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		if err := deliver(ctx, s, event); err != nil {
+			log.Printf("[Events] %s failed to handle %s: %v", s.name, event.EventName(), err)
+		}
+	}
+}
+
+// HasSubscriber reports whether a subscriber named name is registered
+func (b *Bus) HasSubscriber(name string) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, s := range b.all {
+		if s.name == name {
+			return true
+		}
+	}
+	for _, subscribers := range b.byName {
+		for _, s := range subscribers {
+			if s.name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Replay delivers event only to the subscribers named name, returning their
+// errors instead of logging them. handled is false if none of them take
+// events of this type.
+func (b *Bus) Replay(ctx context.Context, name string, event Event) (handled bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.RLock()
+	var subscribers []subscriber
+	for _, s := range append(append([]subscriber(nil), b.byName[event.EventName()]...), b.all...) {
+		if s.name == name {
+			subscribers = append(subscribers, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscribers {
+		errs = append(errs, deliver(ctx, s, event))
+	}
+	return len(subscribers) > 0, errors.Join(errs...)
+}
+
+// deliver runs one subscriber, turning a panic into an error
+func deliver(ctx context.Context, s subscriber, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handle(ctx, event)
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"ling-app/api/internal/client"
+)
+
+func TestBus_DeliversByType(t *testing.T) {
+	bus := NewBus()
+	userID := uuid.New()
+
+	var order []string
+	Subscribe(bus, "first", func(ctx context.Context, e CreditsLow) error {
+		assert.Equal(t, userID, e.UserID)
+		order = append(order, "first")
+		return nil
+	})
+	Subscribe(bus, "second", func(ctx context.Context, e CreditsLow) error {
+		order = append(order, "second")
+		return nil
+	})
+	Subscribe(bus, "other", func(ctx context.Context, e MessageProcessed) error {
+		order = append(order, "other")
+		return nil
+	})
+	SubscribeAll(bus, "all", func(ctx context.Context, e Event) error {
+		order = append(order, "all:"+e.EventName())
+		return nil
+	})
+
+	bus.Publish(context.Background(), CreditsLow{UserID: userID, Balance: 2, Threshold: 5})
+
+	assert.Equal(t, []string{"first", "second", "all:credits.low"}, order)
+}
+
+func TestBus_IsolatesFailingSubscribers(t *testing.T) {
+	bus := NewBus()
+
+	delivered := false
+	Subscribe(bus, "errors", func(ctx context.Context, e CreditsLow) error {
+		return errors.New("boom")
+	})
+	Subscribe(bus, "panics", func(ctx context.Context, e CreditsLow) error {
+		panic("boom")
+	})
+	Subscribe(bus, "works", func(ctx context.Context, e CreditsLow) error {
+		delivered = true
+		return nil
+	})
+
+	assert.NotPanics(t, func() { bus.Publish(context.Background(), CreditsLow{}) })
+	assert.True(t, delivered)
+}
+
+func TestBus_NilDropsEvents(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() { bus.Publish(context.Background(), CreditsLow{}) })
+}
+
+func TestBus_ReplayTargetsOneSubscriber(t *testing.T) {
+	bus := NewBus()
+
+	var order []string
+	Subscribe(bus, "stats", func(ctx context.Context, e CreditsLow) error {
+		order = append(order, "stats")
+		return nil
+	})
+	Subscribe(bus, "notifications", func(ctx context.Context, e CreditsLow) error {
+		order = append(order, "notifications")
+		return nil
+	})
+	Subscribe(bus, "failing", func(ctx context.Context, e CreditsLow) error {
+		return errors.New("boom")
+	})
+
+	handled, err := bus.Replay(context.Background(), "stats", CreditsLow{})
+	assert.True(t, handled)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stats"}, order)
+
+	handled, err = bus.Replay(context.Background(), "stats", MessageProcessed{})
+	assert.False(t, handled, "stats doesn't subscribe to message.processed")
+	assert.NoError(t, err)
+
+	_, err = bus.Replay(context.Background(), "failing", CreditsLow{})
+	assert.Error(t, err, "replay returns errors instead of logging them")
+
+	assert.True(t, bus.HasSubscriber("notifications"))
+	assert.False(t, bus.HasSubscriber("missing"))
+}
+
+func TestDecode_RoundTrips(t *testing.T) {
+	event := AnalysisCompleted{
+		UserID:       uuid.New(),
+		MessageID:    uuid.New(),
+		PhonemeCount: 2,
+		Phonemes:     [][]client.PhonemeDetail{{{Expected: "θ", Actual: "f", Type: "substitute"}}},
+	}
+	data, err := json.Marshal(event)
+	assert.NoError(t, err)
+
+	decoded, err := Decode(NameAnalysisCompleted, data)
+	assert.NoError(t, err)
+	assert.Equal(t, event, decoded)
+
+	_, err = Decode("message.deleted", data)
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+}
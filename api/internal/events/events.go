@@ -0,0 +1,138 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+)
+
+// Event names
+const (
+	NameMessageProcessed    = "message.processed"
+	NameAnalysisCompleted   = "analysis.completed"
+	NameAnalysisFailed      = "analysis.failed"
+	NameCreditsLow          = "credits.low"
+	NameSubscriptionChanged = "subscription.changed"
+	NameThreadTitled        = "thread.titled"
+)
+
+// MessageProcessed is published when a voice or long-form message has been
+// transcribed, charged and answered
+type MessageProcessed struct {
+	UserID    uuid.UUID `json:"userId"`
+	ThreadID  uuid.UUID `json:"threadId"`
+	MessageID uuid.UUID `json:"messageId"`
+	Kind      string    `json:"kind,omitempty"` // models.MessageKindLongForm, MessageKindText, or empty
+	Credits   int       `json:"credits"`
+	At        time.Time `json:"at"`
+}
+
+func (MessageProcessed) EventName() string { return NameMessageProcessed }
+
+func (e MessageProcessed) EventUser() uuid.UUID { return e.UserID }
+
+// AnalysisCompleted is published when a message's pronunciation analysis is
+// stored. Low-confidence results carry no phonemes: they stay out of stats.
+type AnalysisCompleted struct {
+	UserID        uuid.UUID `json:"userId"`
+	ThreadID      uuid.UUID `json:"threadId"`
+	MessageID     uuid.UUID `json:"messageId"`
+	PhonemeCount  int       `json:"phonemeCount"`
+	MatchCount    int       `json:"matchCount"`
+	Confidence    float64   `json:"confidence"`
+	LowConfidence bool      `json:"lowConfidence"`
+	Quality       string    `json:"quality,omitempty"`
+	Chunks        int       `json:"chunks,omitempty"`   // Recordings of a long-form message
+	Language      string    `json:"language,omitempty"` // The thread's; empty on events from before threads had one, which were English
+
+	// Phoneme results to record, one list per confident recording
+	Phonemes [][]client.PhonemeDetail `json:"phonemes,omitempty"`
+}
+
+func (AnalysisCompleted) EventName() string { return NameAnalysisCompleted }
+
+func (e AnalysisCompleted) EventUser() uuid.UUID { return e.UserID }
+
+// AnalysisFailed is published when a message's pronunciation analysis fails
+type AnalysisFailed struct {
+	UserID    uuid.UUID `json:"userId"`
+	ThreadID  uuid.UUID `json:"threadId"`
+	MessageID uuid.UUID `json:"messageId"`
+	Code      string    `json:"code"` // e.g. "ML_SERVICE_ERROR"
+}
+
+func (AnalysisFailed) EventName() string { return NameAnalysisFailed }
+
+func (e AnalysisFailed) EventUser() uuid.UUID { return e.UserID }
+
+// CreditsLow is published when a debit takes a balance below Threshold
+type CreditsLow struct {
+	UserID    uuid.UUID `json:"userId"`
+	Balance   int       `json:"balance"`
+	Threshold int       `json:"threshold"`
+}
+
+func (CreditsLow) EventName() string { return NameCreditsLow }
+
+func (e CreditsLow) EventUser() uuid.UUID { return e.UserID }
+
+// SubscriptionChanged is published when a subscription's tier or status
+// changes: an upgrade, a Stripe update or a cancellation
+type SubscriptionChanged struct {
+	UserID       uuid.UUID               `json:"userId"`
+	PreviousTier models.SubscriptionTier `json:"previousTier"`
+	Tier         models.SubscriptionTier `json:"tier"`
+	Status       string                  `json:"status"`
+}
+
+func (SubscriptionChanged) EventName() string { return NameSubscriptionChanged }
+
+func (e SubscriptionChanged) EventUser() uuid.UUID { return e.UserID }
+
+// ThreadTitled is published when an untitled thread is named after its
+// first exchange
+type ThreadTitled struct {
+	UserID   uuid.UUID `json:"userId"`
+	ThreadID uuid.UUID `json:"threadId"`
+	Title    string    `json:"title"`
+}
+
+func (ThreadTitled) EventName() string { return NameThreadTitled }
+
+func (e ThreadTitled) EventUser() uuid.UUID { return e.UserID }
+
+// ErrUnknownEvent is returned by Decode for an event name it doesn't know
+var ErrUnknownEvent = errors.New("unknown event")
+
+// decoders rebuild each event type from its JSON
+var decoders = map[string]func([]byte) (Event, error){
+	NameMessageProcessed:    decode[MessageProcessed],
+	NameAnalysisCompleted:   decode[AnalysisCompleted],
+	NameAnalysisFailed:      decode[AnalysisFailed],
+	NameCreditsLow:          decode[CreditsLow],
+	NameSubscriptionChanged: decode[SubscriptionChanged],
+	NameThreadTitled:        decode[ThreadTitled],
+}
+
+// Decode rebuilds an event from its name and JSON, as stored by the event log
+func Decode(name string, data []byte) (Event, error) {
+	decoder, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEvent, name)
+	}
+	return decoder(data)
+}
+
+func decode[E Event](data []byte) (Event, error) {
+	var event E
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
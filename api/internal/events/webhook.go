@@ -0,0 +1,119 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/jobs"
+)
+
+// Headers carrying a webhook delivery's signature
+const (
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature"
+)
+
+// WebhookPayload is the body posted for each event
+type WebhookPayload struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Data       Event     `json:"data"`
+}
+
+// Webhook posts every event to an operator-configured URL. Deliveries run on
+// the job queue so publishers never wait on the receiver; a failed delivery
+// is logged by the queue and not retried. The signature is a hex
+// HMAC-SHA256 of "<unix timestamp>\n<body>" keyed with the secret.
+type Webhook struct {
+	url        string
+	secret     []byte
+	queue      *jobs.Queue
+	httpClient *http.Client
+
+	now func() time.Time
+}
+
+// NewWebhook creates a webhook posting to url, or returns nil if url is empty
+func NewWebhook(url, secret string, queue *jobs.Queue) *Webhook {
+	if url == "" {
+		return nil
+	}
+	return &Webhook{
+		url:        url,
+		secret:     []byte(secret),
+		queue:      queue,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// Subscribe delivers every event published on bus. A nil webhook subscribes
+// nothing.
+func (w *Webhook) Subscribe(bus *Bus) {
+	if w == nil {
+		return
+	}
+	SubscribeAll(bus, "webhook", func(ctx context.Context, event Event) error {
+		payload := WebhookPayload{ID: uuid.New(), Type: event.EventName(), OccurredAt: w.now(), Data: event}
+		if w.queue == nil {
+			return w.Deliver(ctx, payload)
+		}
+		return w.queue.Enqueue(jobs.Job{
+			Name: "webhook:" + payload.Type,
+			Lane: jobs.LaneStandard,
+			Run: func(ctx context.Context) error {
+				return w.Deliver(ctx, payload)
+			},
+		})
+	})
+}
+
+// Deliver posts one payload
+func (w *Webhook) Deliver(ctx context.Context, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	timestamp := strconv.FormatInt(w.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, WebhookSignature(w.secret, timestamp, body))
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post %s: %w", payload.Type, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// WebhookSignature signs a delivery; receivers compute the same to verify it
+func WebhookSignature(secret []byte, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, secret)
+	fmt.Fprintf(h, "%s\n", timestamp)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_DeliversSignedEvents(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	var received []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(HeaderWebhookTimestamp)
+		assert.Equal(t, "1792141200", timestamp)
+		assert.Equal(t, WebhookSignature([]byte("secret"), timestamp, body), r.Header.Get(HeaderWebhookSignature))
+
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(body, &payload))
+		received = append(received, payload)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, "secret", nil)
+	webhook.now = func() time.Time { return now }
+	bus := NewBus()
+	webhook.Subscribe(bus)
+
+	// Without a queue, deliveries are made inline
+	bus.Publish(context.Background(), CreditsLow{UserID: userID, Balance: 3, Threshold: 5})
+
+	require.Len(t, received, 1)
+	assert.Equal(t, NameCreditsLow, received[0]["type"])
+	data := received[0]["data"].(map[string]any)
+	assert.Equal(t, userID.String(), data["userId"])
+	assert.Equal(t, 3.0, data["balance"])
+}
+
+func TestWebhook_ReportsRejectedDeliveries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(server.URL, "secret", nil).Deliver(context.Background(), WebhookPayload{Type: NameCreditsLow, Data: CreditsLow{}})
+
+	assert.ErrorContains(t, err, "webhook returned 500: nope")
+}
+
+func TestNewWebhook_DisabledWithoutURL(t *testing.T) {
+	webhook := NewWebhook("", "secret", nil)
+
+	assert.Nil(t, webhook)
+	assert.NotPanics(t, func() { webhook.Subscribe(NewBus()) })
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AdminHandler struct {
+	Users   services.AdminUserProvider
+	Signups services.SignupReviewer
+	Merges  services.AccountMerger
+	Support services.AccountSupport
+	Jobs    services.PronunciationJobMonitor
+}
+
+func NewAdminHandler(users services.AdminUserProvider, signups services.SignupReviewer) *AdminHandler {
+	return &AdminHandler{
+		Users:   users,
+		Signups: signups,
+	}
+}
+
+type ReviewSignupRequest struct {
+	GrantCredits bool `json:"grantCredits"`
+}
+
+type MergeUserRequest struct {
+	IntoUserID uuid.UUID `json:"intoUserId" binding:"required"`
+}
+
+type AdjustCreditsRequest struct {
+	Amount int    `json:"amount" binding:"required"` // Negative takes credits away
+	Reason string `json:"reason" binding:"required"`
+}
+
+type OverrideTierRequest struct {
+	Tier   *models.SubscriptionTier `json:"tier"` // null clears the override
+	Reason string                   `json:"reason" binding:"required"`
+}
+
+// adminActor names the admin in the audit log of support actions, as lingctl
+// names its operators
+func adminActor(admin *models.User) string {
+	return "admin:" + admin.ID.String()
+}
+
+// ListUsers lists the newest accounts, or with ?q= those whose email or name
+// contains it
+// GET /api/admin/users?q=&limit=50
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	users, err := h.Users.ListUsers(c.Query("q"), adminListLimit(c))
+	if err != nil {
+		handleError(c, err, "AdminListUsers")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// GetUser returns an account with its credits and signup signals
+// GET /api/admin/users/:id
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	view, err := h.Users.GetUser(userID)
+	if err != nil {
+		handleError(c, err, "AdminGetUser")
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// GetPendingSignups lists flagged signups waiting for review, oldest first
+// GET /api/admin/signups/review?limit=50
+func (h *AdminHandler) GetPendingSignups(c *gin.Context) {
+	signals, err := h.Signups.PendingReview(adminListLimit(c))
+	if err != nil {
+		handleError(c, err, "GetPendingSignups")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signups": signals})
+}
+
+// ReviewSignup closes a flagged signup, optionally releasing the trial
+// credits it was denied
+// POST /api/admin/users/:id/signup/review
+func (h *AdminHandler) ReviewSignup(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req ReviewSignupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	signal, err := h.Signups.Review(userID, admin.ID, req.GrantCredits)
+	if err != nil {
+		handleError(c, err, "ReviewSignup")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signal": signal})
+}
+
+// MergeUser folds a duplicate account into the one its owner keeps
+// POST /api/admin/users/:id/merge
+func (h *AdminHandler) MergeUser(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req MergeUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	result, err := h.Merges.Merge(userID, req.IntoUserID, admin.ID)
+	if err != nil {
+		handleError(c, err, "MergeUser")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AdjustCredits adds credits to an account or takes them away
+// POST /api/admin/users/:id/credits
+func (h *AdminHandler) AdjustCredits(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req AdjustCreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	if err := h.Support.AdjustCredits(adminActor(admin), userID, req.Amount, req.Reason); err != nil {
+		handleError(c, err, "AdjustCredits")
+		return
+	}
+
+	view, err := h.Users.GetUser(userID)
+	if err != nil {
+		handleError(c, err, "AdjustCredits")
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// OverrideTier sets the tier an account gets regardless of billing, or with
+// a null tier clears the override
+// PUT /api/admin/users/:id/subscription
+func (h *AdminHandler) OverrideTier(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req OverrideTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	sub, err := h.Support.OverrideTier(adminActor(admin), userID, req.Tier, req.Reason)
+	if err != nil {
+		handleError(c, err, "OverrideTier")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"subscription": sub, "effectiveTier": sub.EffectiveTier()})
+}
+
+// GetPronunciationJobs reports on the analysis queue: lane stats, the last
+// day's outcomes, the analyses waiting longest and the latest failures
+// GET /api/admin/pronunciation/jobs?limit=50
+func (h *AdminHandler) GetPronunciationJobs(c *gin.Context) {
+	report, err := h.Jobs.Report(adminListLimit(c))
+	if err != nil {
+		handleError(c, err, "GetPronunciationJobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
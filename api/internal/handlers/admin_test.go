@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAdminRouter(admin *models.User, users services.AdminUserProvider, signups services.SignupReviewer) *gin.Engine {
+	handler := NewAdminHandler(users, signups)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, admin)
+		c.Next()
+	})
+	router.GET("/admin/users/:id", handler.GetUser)
+	router.POST("/admin/users/:id/signup/review", handler.ReviewSignup)
+	return router
+}
+
+func TestAdminHandler_GetUser(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("includes signup signals", func(t *testing.T) {
+		userID := uuid.New()
+		users := new(servicemocks.MockAdminUserProvider)
+		users.On("GetUser", userID).Return(&services.AdminUserView{
+			User: &models.User{ID: userID, Email: "learner@example.com"},
+			Signup: &services.SignupReport{
+				Signal:          &models.SignupSignal{UserID: userID, Verdict: models.SignupReview, Score: 3},
+				RelatedAccounts: []services.RelatedSignup{{UserID: uuid.New(), MatchedOn: []string{"fingerprint"}}},
+			},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupAdminRouter(admin, users, nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/"+userID.String(), nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body services.AdminUserView
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.NotNil(t, body.Signup)
+		assert.Equal(t, models.SignupReview, body.Signup.Signal.Verdict)
+		assert.Len(t, body.Signup.RelatedAccounts, 1)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		userID := uuid.New()
+		users := new(servicemocks.MockAdminUserProvider)
+		users.On("GetUser", userID).Return(nil, repository.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		setupAdminRouter(admin, users, nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users/"+userID.String(), nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAdminHandler_ReviewSignup(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("grants the withheld credits", func(t *testing.T) {
+		userID := uuid.New()
+		signups := new(servicemocks.MockSignupReviewer)
+		signups.On("Review", userID, admin.ID, true).Return(&models.SignupSignal{UserID: userID, CreditsGranted: true}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/signup/review", strings.NewReader(`{"grantCredits": true}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupAdminRouter(admin, nil, signups).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		signups.AssertExpectations(t)
+	})
+
+	t.Run("not pending review", func(t *testing.T) {
+		userID := uuid.New()
+		signups := new(servicemocks.MockSignupReviewer)
+		signups.On("Review", userID, admin.ID, false).Return(nil, services.ErrSignupNotPendingReview)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/signup/review", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupAdminRouter(admin, nil, signups).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestAdminHandler_MergeUser(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	fromID, intoID := uuid.New(), uuid.New()
+
+	setup := func(merges services.AccountMerger) *gin.Engine {
+		handler := NewAdminHandler(nil, nil)
+		handler.Merges = merges
+		router := setupTestRouter()
+		router.Use(func(c *gin.Context) {
+			c.Set(middleware.UserContextKey, admin)
+			c.Next()
+		})
+		router.POST("/admin/users/:id/merge", handler.MergeUser)
+		return router
+	}
+	mergeRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/admin/users/"+fromID.String()+"/merge", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("merges into the surviving account", func(t *testing.T) {
+		merges := new(servicemocks.MockAccountMerger)
+		merges.On("Merge", fromID, intoID, admin.ID).Return(&services.AccountMergeResult{
+			FromUserID:   fromID,
+			IntoUserID:   intoID,
+			Moved:        map[string]int64{"threads": 2},
+			CreditsMoved: 40,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setup(merges).ServeHTTP(w, mergeRequest(`{"intoUserId": "`+intoID.String()+`"}`))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body services.AccountMergeResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, int64(2), body.Moved["threads"])
+		assert.Equal(t, 40, body.CreditsMoved)
+	})
+
+	t.Run("requires the surviving account", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setup(new(servicemocks.MockAccountMerger)).ServeHTTP(w, mergeRequest(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("two paid plans", func(t *testing.T) {
+		merges := new(servicemocks.MockAccountMerger)
+		merges.On("Merge", fromID, intoID, admin.ID).Return(nil, services.ErrMergeBothSubscribed)
+
+		w := httptest.NewRecorder()
+		setup(merges).ServeHTTP(w, mergeRequest(`{"intoUserId": "`+intoID.String()+`"}`))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		wantQuery string
+		wantLimit int
+	}{
+		{name: "newest accounts", path: "/admin/users", wantQuery: "", wantLimit: 50},
+		{name: "searches by email or name", path: "/admin/users?q=maria&limit=10", wantQuery: "maria", wantLimit: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(servicemocks.MockAdminUserProvider)
+			users.On("ListUsers", tt.wantQuery, tt.wantLimit).Return([]models.User{{ID: uuid.New(), Email: "maria@example.com"}}, nil)
+
+			handler := NewAdminHandler(users, nil)
+			router := setupTestRouter()
+			router.GET("/admin/users", handler.ListUsers)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var body struct {
+				Users []models.User `json:"users"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Len(t, body.Users, 1)
+			users.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_AdjustCredits(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	actor := "admin:" + admin.ID.String()
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(*servicemocks.MockAccountSupport, *servicemocks.MockAdminUserProvider)
+		wantStatus int
+	}{
+		{
+			name: "deducts and returns the account",
+			body: `{"amount": -30, "reason": "duplicate grant"}`,
+			setup: func(s *servicemocks.MockAccountSupport, u *servicemocks.MockAdminUserProvider) {
+				s.On("AdjustCredits", actor, userID, -30, "duplicate grant").Return(nil)
+				u.On("GetUser", userID).Return(&services.AdminUserView{User: &models.User{ID: userID}, Credits: &models.Credits{Balance: 70}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "requires a reason",
+			body:       `{"amount": 30}`,
+			setup:      func(*servicemocks.MockAccountSupport, *servicemocks.MockAdminUserProvider) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "over the cap",
+			body: `{"amount": 50000, "reason": "typo"}`,
+			setup: func(s *servicemocks.MockAccountSupport, _ *servicemocks.MockAdminUserProvider) {
+				s.On("AdjustCredits", actor, userID, 50000, "typo").Return(services.ErrInvalidCreditGrant)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "more than the balance",
+			body: `{"amount": -500, "reason": "abuse"}`,
+			setup: func(s *servicemocks.MockAccountSupport, _ *servicemocks.MockAdminUserProvider) {
+				s.On("AdjustCredits", actor, userID, -500, "abuse").Return(services.ErrInsufficientCredits)
+			},
+			wantStatus: http.StatusPaymentRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			support := new(servicemocks.MockAccountSupport)
+			users := new(servicemocks.MockAdminUserProvider)
+			tt.setup(support, users)
+
+			handler := NewAdminHandler(users, nil)
+			handler.Support = support
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, admin)
+				c.Next()
+			})
+			router.POST("/admin/users/:id/credits", handler.AdjustCredits)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/credits", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			support.AssertExpectations(t)
+			users.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_OverrideTier(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	actor := "admin:" + admin.ID.String()
+	userID := uuid.New()
+	pro := models.TierPro
+
+	tests := []struct {
+		name          string
+		body          string
+		setup         func(*servicemocks.MockAccountSupport)
+		wantStatus    int
+		wantEffective models.SubscriptionTier
+	}{
+		{
+			name: "sets an override",
+			body: `{"tier": "pro", "reason": "beta tester"}`,
+			setup: func(s *servicemocks.MockAccountSupport) {
+				s.On("OverrideTier", actor, userID, &pro, "beta tester").
+					Return(&models.Subscription{UserID: userID, Tier: models.TierFree, TierOverride: &pro}, nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantEffective: models.TierPro,
+		},
+		{
+			name: "null clears it",
+			body: `{"tier": null, "reason": "trial over"}`,
+			setup: func(s *servicemocks.MockAccountSupport) {
+				s.On("OverrideTier", actor, userID, (*models.SubscriptionTier)(nil), "trial over").
+					Return(&models.Subscription{UserID: userID, Tier: models.TierBasic}, nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantEffective: models.TierBasic,
+		},
+		{
+			name: "unknown tier",
+			body: `{"tier": "gold", "reason": "typo"}`,
+			setup: func(s *servicemocks.MockAccountSupport) {
+				gold := models.SubscriptionTier("gold")
+				s.On("OverrideTier", actor, userID, &gold, "typo").Return(nil, services.ErrInvalidTier)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown user",
+			body: `{"tier": "pro", "reason": "beta tester"}`,
+			setup: func(s *servicemocks.MockAccountSupport) {
+				s.On("OverrideTier", actor, userID, &pro, "beta tester").Return(nil, repository.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			support := new(servicemocks.MockAccountSupport)
+			tt.setup(support)
+
+			handler := NewAdminHandler(nil, nil)
+			handler.Support = support
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, admin)
+				c.Next()
+			})
+			router.PUT("/admin/users/:id/subscription", handler.OverrideTier)
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/"+userID.String()+"/subscription", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var body struct {
+					EffectiveTier models.SubscriptionTier `json:"effectiveTier"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantEffective, body.EffectiveTier)
+			}
+			support.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_GetPronunciationJobs(t *testing.T) {
+	monitor := new(servicemocks.MockPronunciationJobMonitor)
+	monitor.On("Report", 50).Return(&services.PronunciationJobReport{
+		StatusCounts:   map[string]int64{"failed": 3},
+		OldestPending:  []services.PronunciationJob{},
+		RecentFailures: []services.PronunciationJob{{MessageID: uuid.New(), Status: "failed"}},
+	}, nil)
+
+	handler := NewAdminHandler(nil, nil)
+	handler.Jobs = monitor
+	router := setupTestRouter()
+	router.GET("/admin/pronunciation/jobs", handler.GetPronunciationJobs)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/pronunciation/jobs", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body services.PronunciationJobReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, int64(3), body.StatusCounts["failed"])
+	assert.Len(t, body.RecentFailures, 1)
+	monitor.AssertExpectations(t)
+}
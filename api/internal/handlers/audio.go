@@ -2,28 +2,165 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"path"
+	"strings"
 	"time"
 
 	"ling-app/api/internal/client"
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// DefaultAudioURLExpiry is how long presigned audio URLs stay valid unless
+// configured otherwise. Players renew them with RefreshAudio.
+const DefaultAudioURLExpiry = 15 * time.Minute
+
+// maxAudioRefreshKeys caps how many URLs one refresh call renews
+const maxAudioRefreshKeys = 100
+
 type AudioHandler struct {
-	Storage client.StorageClient
+	exec        repository.Executor
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+	Storage     client.StorageClient
+	ProxyMode   bool
+
+	// URLExpiry is how long presigned URLs stay valid
+	URLExpiry time.Duration
 }
 
-func NewAudioHandler(storage client.StorageClient) *AudioHandler {
+// NewAudioHandler creates a new audio handler.
+// When proxyMode is true, audio is streamed through the API instead of
+// handing the browser a presigned storage URL (for strict CSP deployments).
+func NewAudioHandler(
+	exec repository.Executor,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	storage client.StorageClient,
+	proxyMode bool,
+) *AudioHandler {
 	return &AudioHandler{
-		Storage: storage,
+		exec:        exec,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		Storage:     storage,
+		ProxyMode:   proxyMode,
+		URLExpiry:   DefaultAudioURLExpiry,
 	}
 }
 
-// GetAudio generates a presigned URL for audio playback
+// AudioManifest describes a message's audio so players can seek before downloading it
+type AudioManifest struct {
+	MessageID       uuid.UUID  `json:"messageId"`
+	Key             string     `json:"key"`
+	URL             string     `json:"url"`
+	ContentType     string     `json:"contentType"`
+	ByteSize        int64      `json:"byteSize"`
+	DurationSeconds *float64   `json:"durationSeconds,omitempty"`
+	AcceptRanges    bool       `json:"acceptRanges"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+}
+
+// GetAudioManifest returns duration, byte size, and a playback URL for a message's audio
+// GET /api/threads/:id/messages/:messageId/audio/manifest
+func (h *AudioHandler) GetAudioManifest(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	if _, err := h.threadRepo.FindByIDAndUserID(h.exec, threadID, user.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch thread"})
+		return
+	}
+
+	message, err := h.messageRepo.FindByID(h.exec, messageID)
+	if err != nil || message.ThreadID != threadID {
+		if err == nil || errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch message"})
+		return
+	}
+
+	if !message.HasAudio || message.AudioURL == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message has no audio"})
+		return
+	}
+	key := *message.AudioURL
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if wantsLowBitrate(c) {
+		key = h.lowBitrateKey(ctx, key)
+	}
+
+	info, err := h.Storage.StatObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, client.ErrObjectNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Audio not found"})
+			return
+		}
+		handleError(c, err, "GetAudioManifest")
+		return
+	}
+
+	url, expiresAt, err := h.audioURL(ctx, *message.AudioURL, key)
+	if err != nil {
+		handleError(c, err, "GetAudioManifest")
+		return
+	}
+
+	contentType := info.ContentType
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = audioContentType(key)
+		if format := models.AudioContentType(message.AudioFormat); format != "" && key == *message.AudioURL {
+			contentType = format
+		}
+	}
+
+	c.JSON(http.StatusOK, AudioManifest{
+		MessageID:       message.ID,
+		Key:             key,
+		URL:             url,
+		ContentType:     contentType,
+		ByteSize:        info.Size,
+		DurationSeconds: message.AudioDurationSeconds,
+		AcceptRanges:    true,
+		ExpiresAt:       expiresAt,
+	})
+}
+
+// GetAudio generates a presigned URL for audio playback, or streams the
+// audio itself when proxy mode is enabled. ?quality=low or a Save-Data
+// header picks the low-bitrate variant when the reply has one. Only the
+// user's own recordings and shared reference clips are served.
 // GET /api/audio/*key
 func (h *AudioHandler) GetAudio(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
 	key := c.Param("key")
 	if key == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio key is required"})
@@ -35,15 +172,249 @@ func (h *AudioHandler) GetAudio(c *gin.Context) {
 		key = key[1:]
 	}
 
+	owned, err := h.ownedAudioKeys(user.ID, []string{key})
+	if err != nil {
+		handleError(c, err, "GetAudio")
+		return
+	}
+	if !owned[key] {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Audio not found"})
+		return
+	}
+
+	base := key
+	if models.LowBitrateAudioKey(key) != "" {
+		c.Header("Vary", "Save-Data")
+		if wantsLowBitrate(c) {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+			key = h.lowBitrateKey(ctx, key)
+			cancel()
+		}
+	}
+
+	if h.ProxyMode {
+		// JSON callers (the frontend's URL lookup) get a same-origin URL back,
+		// media elements requesting that URL get the audio stream.
+		if strings.Contains(c.GetHeader("Accept"), gin.MIMEJSON) {
+			url, _, err := h.audioURL(c.Request.Context(), base, key)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to generate audio URL: %v", err)})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"url": url})
+			return
+		}
+		h.streamAudio(c, key)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Generate presigned URL valid for 24 hours
-	url, err := h.Storage.GetPresignedURL(ctx, key, 24*time.Hour)
+	url, expiresAt, err := h.audioURL(ctx, base, key)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to generate audio URL: %v", err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"url": url})
+	c.JSON(http.StatusOK, gin.H{"url": url, "expiresAt": expiresAt})
+}
+
+// RefreshAudioRequest lists the audio keys whose URLs are about to expire
+type RefreshAudioRequest struct {
+	Keys []string `json:"keys" binding:"required,min=1"`
+}
+
+// RefreshAudio renews the playback URLs of many recordings at once, so a
+// player can refresh a whole playlist before its URLs expire. URLs are keyed
+// by the requested key; ?quality=low and Save-Data pick the low-bitrate
+// variant as in GetAudio. Keys the user may not play fail the whole call.
+// POST /api/audio/refresh
+func (h *AudioHandler) RefreshAudio(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	var req RefreshAudioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Keys) > maxAudioRefreshKeys {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d keys per refresh", maxAudioRefreshKeys)})
+		return
+	}
+
+	keys := make([]string, len(req.Keys))
+	for i, requested := range req.Keys {
+		keys[i] = strings.TrimPrefix(requested, "/")
+		if keys[i] == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Audio keys can't be empty"})
+			return
+		}
+	}
+
+	owned, err := h.ownedAudioKeys(user.ID, keys)
+	if err != nil {
+		handleError(c, err, "RefreshAudio")
+		return
+	}
+	for _, key := range keys {
+		if !owned[key] {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Audio not found"})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	lowBitrate := wantsLowBitrate(c)
+	urls := make(map[string]string, len(req.Keys))
+	var expiresAt *time.Time
+	for i, requested := range req.Keys {
+		base, key := keys[i], keys[i]
+		if lowBitrate {
+			key = h.lowBitrateKey(ctx, key)
+		}
+
+		url, expires, err := h.audioURL(ctx, base, key)
+		if err != nil {
+			handleError(c, err, "RefreshAudio")
+			return
+		}
+		urls[requested] = url
+		if expiresAt == nil {
+			expiresAt = expires
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"urls": urls, "expiresAt": expiresAt})
+}
+
+// ownedAudioKeys reports which of keys the user may play: the recordings of
+// messages in their own threads, and reference clips, which are shared
+func (h *AudioHandler) ownedAudioKeys(userID uuid.UUID, keys []string) (map[string]bool, error) {
+	owned := make(map[string]bool, len(keys))
+	var lookup []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, models.ReferenceAudioPrefix) && path.Clean(key) == key {
+			owned[key] = true
+			continue
+		}
+		lookup = append(lookup, key)
+	}
+	if len(lookup) == 0 {
+		return owned, nil
+	}
+
+	found, err := h.messageRepo.FindOwnedAudioKeys(h.exec, userID, lookup)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range found {
+		owned[key] = true
+	}
+	return owned, nil
+}
+
+// audioURL returns where the browser can play key, a variant of the stored
+// recording base, and when that URL stops working. Proxy URLs go through the
+// session and never expire; they name base, which is what ownership is
+// checked against, and ask for the low-bitrate variant when key is one.
+func (h *AudioHandler) audioURL(ctx context.Context, base, key string) (string, *time.Time, error) {
+	if h.ProxyMode {
+		if key != base {
+			return "/api/audio/" + base + "?quality=low", nil, nil
+		}
+		return "/api/audio/" + base, nil, nil
+	}
+
+	expiresAt := time.Now().Add(h.URLExpiry)
+	url, err := h.Storage.GetPresignedURL(ctx, key, h.URLExpiry)
+	if err != nil {
+		return "", nil, err
+	}
+	return url, &expiresAt, nil
+}
+
+// wantsLowBitrate reports whether the client asked for the smaller variant,
+// either explicitly or because the browser is in data saver mode
+func wantsLowBitrate(c *gin.Context) bool {
+	return c.Query("quality") == "low" || strings.EqualFold(c.GetHeader("Save-Data"), "on")
+}
+
+// lowBitrateKey returns the low-bitrate variant of key when it was stored,
+// and key itself otherwise (older replies, other TTS backends)
+func (h *AudioHandler) lowBitrateKey(ctx context.Context, key string) string {
+	variant := models.LowBitrateAudioKey(key)
+	if variant == "" {
+		return key
+	}
+	if _, err := h.Storage.StatObject(ctx, variant); err != nil {
+		if !errors.Is(err, client.ErrObjectNotFound) {
+			log.Printf("[GetAudio] Error checking %s: %v", variant, err)
+		}
+		return key
+	}
+	return variant
+}
+
+// streamAudio proxies an audio object from storage, honoring Range requests
+func (h *AudioHandler) streamAudio(c *gin.Context, key string) {
+	obj, err := h.Storage.GetObject(c.Request.Context(), key, c.GetHeader("Range"))
+	if err != nil {
+		switch {
+		case errors.Is(err, client.ErrObjectNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Audio not found"})
+		case errors.Is(err, client.ErrInvalidRange):
+			c.Header("Content-Range", "bytes */*")
+			c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"error": "Requested range not satisfiable"})
+		default:
+			log.Printf("[GetAudio] Error streaming %s: %v", key, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load audio"})
+		}
+		return
+	}
+	defer obj.Body.Close()
+
+	contentType := obj.ContentType
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = audioContentType(key)
+	}
+
+	headers := map[string]string{
+		"Accept-Ranges": "bytes",
+		// Audio objects are immutable once written, so they can be cached
+		// privately by the browser for a day.
+		"Cache-Control": "private, max-age=86400, immutable",
+	}
+	if obj.ETag != "" {
+		headers["ETag"] = obj.ETag
+	}
+	if obj.LastModified != nil {
+		headers["Last-Modified"] = obj.LastModified.UTC().Format(http.TimeFormat)
+	}
+
+	status := http.StatusOK
+	if obj.ContentRange != "" {
+		status = http.StatusPartialContent
+		headers["Content-Range"] = obj.ContentRange
+	}
+
+	c.DataFromReader(status, obj.ContentLength, contentType, obj.Body, headers)
+}
+
+// audioContentType infers a MIME type from the key's extension
+func audioContentType(key string) string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".webm":
+		return "audio/webm"
+	case ".mp3":
+		return "audio/mpeg"
+	case ".wav":
+		return "audio/wav"
+	case ".ogg", ".opus":
+		return "audio/ogg"
+	default:
+		return "application/octet-stream"
+	}
 }
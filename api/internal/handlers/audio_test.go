@@ -3,32 +3,57 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"ling-app/api/internal/client"
 	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
 )
 
 func init() {
 	gin.SetMode(gin.TestMode)
 }
 
+var audioTestUser = &models.User{ID: uuid.New()}
+
+// newOwningAudioHandler returns a handler for which audioTestUser owns the
+// recordings at owned
+func newOwningAudioHandler(storage client.StorageClient, proxyMode bool, owned ...string) *AudioHandler {
+	messageRepo := new(repomocks.MockMessageRepository)
+	messageRepo.On("FindOwnedAudioKeys", mock.Anything, audioTestUser.ID, mock.Anything).Return(owned, nil)
+	return NewAudioHandler(nil, nil, messageRepo, storage, proxyMode)
+}
+
+// newAudioTestContext returns a test context signed in as audioTestUser
+func newAudioTestContext(w *httptest.ResponseRecorder) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Set(middleware.UserContextKey, audioTestUser)
+	return c
+}
+
 func TestAudioHandler_GetAudio(t *testing.T) {
 	t.Run("returns presigned URL successfully", func(t *testing.T) {
 		storageClient := new(clientmocks.MockStorageClient)
-		handler := NewAudioHandler(storageClient)
+		handler := newOwningAudioHandler(storageClient, false, "audio/test.wav")
 
-		storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", 24*time.Hour).
+		storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", DefaultAudioURLExpiry).
 			Return("https://presigned.url/audio/test.wav", nil)
 
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
+		c := newAudioTestContext(w)
 		c.Params = gin.Params{{Key: "key", Value: "/audio/test.wav"}}
 
 		handler.GetAudio(c)
@@ -44,14 +69,14 @@ func TestAudioHandler_GetAudio(t *testing.T) {
 
 	t.Run("strips leading slash from key", func(t *testing.T) {
 		storageClient := new(clientmocks.MockStorageClient)
-		handler := NewAudioHandler(storageClient)
+		handler := newOwningAudioHandler(storageClient, false, "user/123/audio.wav")
 
 		// The key passed to storage should have the leading slash removed
-		storageClient.On("GetPresignedURL", mock.Anything, "user/123/audio.wav", 24*time.Hour).
+		storageClient.On("GetPresignedURL", mock.Anything, "user/123/audio.wav", DefaultAudioURLExpiry).
 			Return("https://presigned.url/user/123/audio.wav", nil)
 
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
+		c := newAudioTestContext(w)
 		c.Params = gin.Params{{Key: "key", Value: "/user/123/audio.wav"}}
 
 		handler.GetAudio(c)
@@ -62,10 +87,10 @@ func TestAudioHandler_GetAudio(t *testing.T) {
 
 	t.Run("returns error when key is empty", func(t *testing.T) {
 		storageClient := new(clientmocks.MockStorageClient)
-		handler := NewAudioHandler(storageClient)
+		handler := newOwningAudioHandler(storageClient, false)
 
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
+		c := newAudioTestContext(w)
 		c.Params = gin.Params{{Key: "key", Value: ""}}
 
 		handler.GetAudio(c)
@@ -81,13 +106,13 @@ func TestAudioHandler_GetAudio(t *testing.T) {
 
 	t.Run("returns error when storage fails", func(t *testing.T) {
 		storageClient := new(clientmocks.MockStorageClient)
-		handler := NewAudioHandler(storageClient)
+		handler := newOwningAudioHandler(storageClient, false, "audio/test.wav")
 
-		storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", 24*time.Hour).
+		storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", DefaultAudioURLExpiry).
 			Return("", errors.New("storage unavailable"))
 
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
+		c := newAudioTestContext(w)
 		c.Params = gin.Params{{Key: "key", Value: "/audio/test.wav"}}
 
 		handler.GetAudio(c)
@@ -99,4 +124,382 @@ func TestAudioHandler_GetAudio(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Contains(t, response["error"], "Failed to generate audio URL")
 	})
+
+	t.Run("returns 404 for audio the user doesn't own", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		messageRepo := new(repomocks.MockMessageRepository)
+		handler := NewAudioHandler(nil, nil, messageRepo, storageClient, false)
+
+		messageRepo.On("FindOwnedAudioKeys", mock.Anything, audioTestUser.ID, []string{"v2/user/1/2"}).Return([]string{}, nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Params = gin.Params{{Key: "key", Value: "/v2/user/1/2"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		messageRepo.AssertExpectations(t)
+		storageClient.AssertNotCalled(t, "GetPresignedURL")
+	})
+
+	t.Run("serves reference clips to everyone", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		messageRepo := new(repomocks.MockMessageRepository)
+		handler := NewAudioHandler(nil, nil, messageRepo, storageClient, false)
+
+		storageClient.On("GetPresignedURL", mock.Anything, "reference/abc.mp3", DefaultAudioURLExpiry).
+			Return("https://presigned.url/reference/abc.mp3", nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Params = gin.Params{{Key: "key", Value: "/reference/abc.mp3"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		messageRepo.AssertNotCalled(t, "FindOwnedAudioKeys")
+	})
+
+	t.Run("doesn't treat paths escaping the reference prefix as clips", func(t *testing.T) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		handler := NewAudioHandler(nil, nil, messageRepo, new(clientmocks.MockStorageClient), false)
+
+		messageRepo.On("FindOwnedAudioKeys", mock.Anything, audioTestUser.ID, []string{"reference/../v2/user/1/2"}).Return([]string{}, nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Params = gin.Params{{Key: "key", Value: "/reference/../v2/user/1/2"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAudioHandler_GetAudio_ProxyMode(t *testing.T) {
+	t.Run("streams full object with cache headers", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, true, "assistant/1/2.mp3")
+
+		storageClient.On("GetObject", mock.Anything, "assistant/1/2.mp3", "").
+			Return(&client.StorageObject{
+				Body:          io.NopCloser(strings.NewReader("mp3data")),
+				ContentType:   "audio/mpeg",
+				ContentLength: 7,
+				ETag:          `"abc"`,
+			}, nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/audio/assistant/1/2.mp3", nil)
+		c.Params = gin.Params{{Key: "key", Value: "/assistant/1/2.mp3"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "mp3data", w.Body.String())
+		assert.Equal(t, "audio/mpeg", w.Header().Get("Content-Type"))
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+		assert.Equal(t, `"abc"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Header().Get("Cache-Control"), "private")
+		storageClient.AssertExpectations(t)
+	})
+
+	t.Run("forwards range and returns partial content", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, true, "user/1/2.webm")
+
+		storageClient.On("GetObject", mock.Anything, "user/1/2.webm", "bytes=0-3").
+			Return(&client.StorageObject{
+				Body:          io.NopCloser(strings.NewReader("webm")),
+				ContentLength: 4,
+				ContentRange:  "bytes 0-3/100",
+			}, nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/audio/user/1/2.webm", nil)
+		c.Request.Header.Set("Range", "bytes=0-3")
+		c.Params = gin.Params{{Key: "key", Value: "/user/1/2.webm"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "bytes 0-3/100", w.Header().Get("Content-Range"))
+		// Content type falls back to the key's extension
+		assert.Equal(t, "audio/webm", w.Header().Get("Content-Type"))
+		storageClient.AssertExpectations(t)
+	})
+
+	t.Run("returns same-origin URL to JSON callers", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, true, "user/1/2.webm")
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/audio/user/1/2.webm", nil)
+		c.Request.Header.Set("Accept", "application/json")
+		c.Params = gin.Params{{Key: "key", Value: "/user/1/2.webm"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "/api/audio/user/1/2.webm", response["url"])
+		storageClient.AssertNotCalled(t, "GetObject")
+	})
+
+	t.Run("maps storage errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			err    error
+			status int
+		}{
+			{"not found", client.ErrObjectNotFound, http.StatusNotFound},
+			{"invalid range", client.ErrInvalidRange, http.StatusRequestedRangeNotSatisfiable},
+			{"other", errors.New("boom"), http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				storageClient := new(clientmocks.MockStorageClient)
+				handler := newOwningAudioHandler(storageClient, true, "a.mp3")
+
+				storageClient.On("GetObject", mock.Anything, "a.mp3", "").Return(nil, tt.err)
+
+				w := httptest.NewRecorder()
+				c := newAudioTestContext(w)
+				c.Request = httptest.NewRequest(http.MethodGet, "/api/audio/a.mp3", nil)
+				c.Params = gin.Params{{Key: "key", Value: "/a.mp3"}}
+
+				handler.GetAudio(c)
+
+				assert.Equal(t, tt.status, w.Code)
+			})
+		}
+	})
+}
+
+func TestAudioHandler_RefreshAudio(t *testing.T) {
+	refresh := func(handler *AudioHandler, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/audio/refresh", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.RefreshAudio(c)
+		return w
+	}
+
+	t.Run("renews every URL with the configured expiry", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, false, "user/1/a.webm", "assistant/1/b.mp3")
+		handler.URLExpiry = 5 * time.Minute
+
+		storageClient.On("GetPresignedURL", mock.Anything, "user/1/a.webm", 5*time.Minute).Return("https://presigned.url/a", nil)
+		storageClient.On("GetPresignedURL", mock.Anything, "assistant/1/b.mp3", 5*time.Minute).Return("https://presigned.url/b", nil)
+
+		w := refresh(handler, `{"keys": ["user/1/a.webm", "assistant/1/b.mp3"]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			URLs      map[string]string `json:"urls"`
+			ExpiresAt time.Time         `json:"expiresAt"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, map[string]string{
+			"user/1/a.webm":     "https://presigned.url/a",
+			"assistant/1/b.mp3": "https://presigned.url/b",
+		}, response.URLs)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), response.ExpiresAt, time.Minute)
+	})
+
+	t.Run("returns proxy paths without an expiry", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, true, "user/1/a.webm")
+
+		w := refresh(handler, `{"keys": ["user/1/a.webm"]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"urls": {"user/1/a.webm": "/api/audio/user/1/a.webm"}, "expiresAt": null}`, w.Body.String())
+		storageClient.AssertNotCalled(t, "GetPresignedURL")
+	})
+
+	t.Run("returns 404 when any key isn't the user's", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, false, "user/1/a.webm")
+
+		w := refresh(handler, `{"keys": ["user/1/a.webm", "user/2/b.webm"]}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		storageClient.AssertNotCalled(t, "GetPresignedURL")
+	})
+
+	t.Run("rejects empty and oversized batches", func(t *testing.T) {
+		handler := newOwningAudioHandler(new(clientmocks.MockStorageClient), false)
+		keys := make([]string, maxAudioRefreshKeys+1)
+		for i := range keys {
+			keys[i] = `"k"`
+		}
+
+		assert.Equal(t, http.StatusBadRequest, refresh(handler, `{"keys": []}`).Code)
+		assert.Equal(t, http.StatusBadRequest, refresh(handler, `{"keys": [`+strings.Join(keys, ",")+`]}`).Code)
+	})
+}
+
+func TestAudioHandler_GetAudio_LowBitrate(t *testing.T) {
+	t.Run("presigns the variant for ?quality=low", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, false, "assistant/1/2.mp3")
+
+		storageClient.On("StatObject", mock.Anything, "assistant/1/2.low.ogg").Return(&client.ObjectInfo{Size: 10}, nil)
+		storageClient.On("GetPresignedURL", mock.Anything, "assistant/1/2.low.ogg", DefaultAudioURLExpiry).
+			Return("https://presigned.url/assistant/1/2.low.ogg", nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/audio/assistant/1/2.mp3?quality=low", nil)
+		c.Params = gin.Params{{Key: "key", Value: "/assistant/1/2.mp3"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "2.low.ogg")
+		assert.Equal(t, "Save-Data", w.Header().Get("Vary"))
+		storageClient.AssertExpectations(t)
+	})
+
+	t.Run("returns a proxy URL naming the stored reply", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, true, "assistant/1/2.mp3")
+
+		storageClient.On("StatObject", mock.Anything, "assistant/1/2.low.ogg").Return(&client.ObjectInfo{Size: 10}, nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/audio/assistant/1/2.mp3?quality=low", nil)
+		c.Request.Header.Set("Accept", "application/json")
+		c.Params = gin.Params{{Key: "key", Value: "/assistant/1/2.mp3"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"url": "/api/audio/assistant/1/2.mp3?quality=low"}`, w.Body.String())
+	})
+
+	t.Run("streams the standard file when there is no variant", func(t *testing.T) {
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := newOwningAudioHandler(storageClient, true, "assistant/1/2.mp3")
+
+		storageClient.On("StatObject", mock.Anything, "assistant/1/2.low.ogg").Return(nil, client.ErrObjectNotFound)
+		storageClient.On("GetObject", mock.Anything, "assistant/1/2.mp3", "").
+			Return(&client.StorageObject{Body: io.NopCloser(strings.NewReader("mp3data")), ContentLength: 7}, nil)
+
+		w := httptest.NewRecorder()
+		c := newAudioTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/audio/assistant/1/2.mp3", nil)
+		c.Request.Header.Set("Save-Data", "on")
+		c.Params = gin.Params{{Key: "key", Value: "/assistant/1/2.mp3"}}
+
+		handler.GetAudio(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "mp3data", w.Body.String())
+		assert.Equal(t, "audio/mpeg", w.Header().Get("Content-Type"))
+		storageClient.AssertExpectations(t)
+	})
+}
+
+func TestAudioHandler_GetAudioManifest(t *testing.T) {
+	userID := uuid.New()
+	threadID := uuid.New()
+	messageID := uuid.New()
+	key := "assistant/" + threadID.String() + "/" + messageID.String() + ".mp3"
+	duration := 42.5
+
+	setup := func(proxyMode bool) (*gin.Engine, *repomocks.MockThreadRepository, *repomocks.MockMessageRepository, *clientmocks.MockStorageClient) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		storageClient := new(clientmocks.MockStorageClient)
+		handler := NewAudioHandler(nil, threadRepo, messageRepo, storageClient, proxyMode)
+
+		router := setupTestRouter()
+		router.Use(func(c *gin.Context) {
+			c.Set(middleware.UserContextKey, &models.User{ID: userID})
+			c.Next()
+		})
+		router.GET("/threads/:id/messages/:messageId/audio/manifest", handler.GetAudioManifest)
+		return router, threadRepo, messageRepo, storageClient
+	}
+
+	path := "/threads/" + threadID.String() + "/messages/" + messageID.String() + "/audio/manifest"
+
+	t.Run("returns size, duration and presigned URL", func(t *testing.T) {
+		router, threadRepo, messageRepo, storageClient := setup(false)
+
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+		messageRepo.On("FindByID", mock.Anything, messageID).Return(&models.Message{
+			ID: messageID, ThreadID: threadID, HasAudio: true, AudioURL: &key, AudioDurationSeconds: &duration,
+		}, nil)
+		storageClient.On("StatObject", mock.Anything, key).Return(&client.ObjectInfo{Size: 123456, ContentType: "audio/mpeg"}, nil)
+		storageClient.On("GetPresignedURL", mock.Anything, key, DefaultAudioURLExpiry).Return("https://presigned.url/a.mp3", nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var manifest AudioManifest
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &manifest))
+		assert.Equal(t, int64(123456), manifest.ByteSize)
+		assert.Equal(t, "audio/mpeg", manifest.ContentType)
+		assert.Equal(t, "https://presigned.url/a.mp3", manifest.URL)
+		assert.Equal(t, 42.5, *manifest.DurationSeconds)
+		assert.True(t, manifest.AcceptRanges)
+	})
+
+	t.Run("returns proxy URL in proxy mode", func(t *testing.T) {
+		router, threadRepo, messageRepo, storageClient := setup(true)
+
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+		messageRepo.On("FindByID", mock.Anything, messageID).Return(&models.Message{
+			ID: messageID, ThreadID: threadID, HasAudio: true, AudioURL: &key,
+		}, nil)
+		storageClient.On("StatObject", mock.Anything, key).Return(&client.ObjectInfo{Size: 10}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var manifest AudioManifest
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &manifest))
+		assert.Equal(t, "/api/audio/"+key, manifest.URL)
+		assert.Equal(t, "audio/mpeg", manifest.ContentType)
+		storageClient.AssertNotCalled(t, "GetPresignedURL")
+	})
+
+	t.Run("returns 404 when message belongs to another thread", func(t *testing.T) {
+		router, threadRepo, messageRepo, _ := setup(false)
+
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+		messageRepo.On("FindByID", mock.Anything, messageID).Return(&models.Message{
+			ID: messageID, ThreadID: uuid.New(), HasAudio: true, AudioURL: &key,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("returns 404 when thread is not owned by user", func(t *testing.T) {
+		router, threadRepo, _, _ := setup(false)
+
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(nil, repository.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
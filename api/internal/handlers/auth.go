@@ -3,14 +3,20 @@ package handlers
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"ling-app/api/internal/analytics"
 	"ling-app/api/internal/config"
 	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
 	"ling-app/api/internal/services"
 	"ling-app/api/internal/services/auth"
 )
@@ -21,15 +27,36 @@ type AuthHandler struct {
 	OAuthService   *services.OAuthService
 	CreditsService *services.CreditsService
 	Config         *config.Config
+	Analytics      analytics.Tracker
+
+	// SignupGuard, if set, checks new accounts for duplicates before
+	// granting their trial credits
+	SignupGuard *services.SignupGuard
+
+	// Invites, if set, requires new accounts to redeem an invite code while
+	// signups are invite-only
+	Invites *services.InviteService
+
+	// Guests, if set, starts guest demos and moves a guest's demo thread to
+	// the account they sign up or in with
+	Guests *services.GuestService
+
+	// Verification, if set, emails password signups a link to verify their
+	// address
+	Verification services.EmailVerifier
+
+	// Accounts, if set, lets users delete their account
+	Accounts services.AccountDeleter
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *auth.AuthService, oauthService *services.OAuthService, creditsService *services.CreditsService, cfg *config.Config) *AuthHandler {
+func NewAuthHandler(authService *auth.AuthService, oauthService *services.OAuthService, creditsService *services.CreditsService, cfg *config.Config, tracker analytics.Tracker) *AuthHandler {
 	return &AuthHandler{
 		AuthService:    authService,
 		OAuthService:   oauthService,
 		CreditsService: creditsService,
 		Config:         cfg,
+		Analytics:      tracker,
 	}
 }
 
@@ -39,6 +66,13 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
 	Name     string `json:"name" binding:"required"`
+
+	// Fingerprint is an optional client-side device hash used to spot
+	// repeat trial signups
+	Fingerprint string `json:"fingerprint" binding:"omitempty,max=128"`
+
+	// InviteCode is required while signups are invite-only
+	InviteCode string `json:"inviteCode" binding:"omitempty,max=64"`
 }
 
 type LoginRequest struct {
@@ -52,6 +86,11 @@ type UserResponse struct {
 	Name          string  `json:"name"`
 	AvatarURL     *string `json:"avatarUrl,omitempty"`
 	EmailVerified bool    `json:"emailVerified"`
+
+	// Guest is set on demo accounts, which are deleted at GuestExpiresAt
+	// unless the guest signs up
+	Guest          bool       `json:"guest,omitempty"`
+	GuestExpiresAt *time.Time `json:"guestExpiresAt,omitempty"`
 }
 
 // Helper to determine cookie settings based on environment
@@ -65,13 +104,18 @@ func (h *AuthHandler) getCookieSettings() (secure bool, sameSite http.SameSite,
 
 // setSessionCookie sets the session cookie on the response
 func (h *AuthHandler) setSessionCookie(c *gin.Context, token string) {
+	h.setSessionCookieMaxAge(c, token, h.Config.SessionMaxAge)
+}
+
+// setSessionCookieMaxAge sets a session cookie that lasts maxAge seconds
+func (h *AuthHandler) setSessionCookieMaxAge(c *gin.Context, token string, maxAge int) {
 	secure, sameSite, domain := h.getCookieSettings()
 
 	c.SetSameSite(sameSite)
 	c.SetCookie(
 		"session_token",           // name
 		token,                     // value
-		h.Config.SessionMaxAge,    // maxAge in seconds
+		maxAge,                    // maxAge in seconds
 		"/",                       // path
 		domain,                    // domain (empty = current domain)
 		secure,                    // secure (HTTPS only)
@@ -95,6 +139,117 @@ func (h *AuthHandler) clearSessionCookie(c *gin.Context) {
 	)
 }
 
+// creditsFor returns the credits initializer for a new account: the signup
+// guard's admission when the guard is enabled, otherwise the credits service,
+// behind the invite check when signups are invite-only
+func (h *AuthHandler) creditsFor(c *gin.Context, method, fingerprint, inviteCode string) auth.CreditsInitializer {
+	var initializer auth.CreditsInitializer = h.CreditsService
+	if h.SignupGuard != nil {
+		initializer = h.SignupGuard.Admit(method, services.SignupSignals{
+			IPAddress:       c.ClientIP(),
+			UserAgent:       c.Request.UserAgent(),
+			FingerprintHash: fingerprint,
+		})
+	}
+	if h.Invites != nil {
+		initializer = h.Invites.Gate(inviteCode, initializer)
+	}
+	return initializer
+}
+
+// inviteErrorParam returns the login page error for a refused invite, or ""
+func inviteErrorParam(err error) string {
+	switch {
+	case errors.Is(err, services.ErrInviteRequired):
+		return "invite_required"
+	case errors.Is(err, services.ErrInvalidInviteCode):
+		return "invalid_invite"
+	}
+	return ""
+}
+
+// trackRegistration records a new account and how it was created
+func (h *AuthHandler) trackRegistration(c *gin.Context, userID uuid.UUID, method string) {
+	if h.Analytics == nil {
+		return
+	}
+	h.Analytics.Track(c.Request.Context(), userID, analytics.EventUserRegistered, map[string]any{
+		"method": method,
+	})
+}
+
+// adoptGuest moves the demo thread of the guest session this request carries,
+// if any, to the account the user just signed up or in with, and ends the
+// guest session. Failures are logged: they don't stop the sign-in.
+func (h *AuthHandler) adoptGuest(c *gin.Context, user *models.User) {
+	if h.Guests == nil {
+		return
+	}
+	token, err := c.Cookie("session_token")
+	if err != nil || token == "" {
+		return
+	}
+	guest, err := h.AuthService.ValidateSession(token)
+	if err != nil || !guest.IsGuest() || guest.ID == user.ID {
+		return
+	}
+
+	if err := h.Guests.Upgrade(guest.ID, user.ID); err != nil {
+		log.Printf("[Auth] Failed to move guest %s to user %s: %v", guest.ID, user.ID, err)
+		return
+	}
+	_ = h.AuthService.DeleteSession(token)
+}
+
+// StartGuest starts a guest demo without an email and signs the visitor in
+// to it. A visitor who is already signed in gets their current account back.
+// POST /api/auth/guest
+func (h *AuthHandler) StartGuest(c *gin.Context) {
+	if !h.Guests.Enabled() {
+		handleError(c, services.ErrGuestModeDisabled, "StartGuest")
+		return
+	}
+	if token, err := c.Cookie("session_token"); err == nil && token != "" {
+		if user, err := h.AuthService.ValidateSession(token); err == nil {
+			c.JSON(http.StatusOK, newUserResponse(user))
+			return
+		}
+	}
+
+	guest, err := h.Guests.Create(c.ClientIP())
+	if err != nil {
+		handleError(c, err, "StartGuest")
+		return
+	}
+
+	token, err := h.AuthService.CreateSessionUntil(guest.ID, c.Request.UserAgent(), c.ClientIP(), *guest.GuestExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+	h.setSessionCookieMaxAge(c, token, int(time.Until(*guest.GuestExpiresAt).Seconds()))
+
+	c.JSON(http.StatusCreated, newUserResponse(guest))
+}
+
+// newUserResponse returns the user without sensitive fields
+func newUserResponse(user *models.User) UserResponse {
+	resp := UserResponse{
+		ID:            user.ID.String(),
+		Email:         user.Email,
+		Name:          user.Name,
+		AvatarURL:     user.AvatarURL,
+		EmailVerified: user.EmailVerified,
+	}
+	if user.IsGuest() {
+		// The placeholder email isn't the guest's
+		resp.Email = ""
+		resp.Guest = true
+		resp.GuestExpiresAt = user.GuestExpiresAt
+	}
+	return resp
+}
+
 // Register creates a new user account
 // POST /api/auth/register
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -109,15 +264,22 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	name := strings.TrimSpace(req.Name)
 
 	// Create user with credits (atomic transaction)
-	user, err := h.AuthService.CreateUser(email, req.Password, name, h.CreditsService)
+	user, err := h.AuthService.CreateUser(email, req.Password, name, h.creditsFor(c, "password", req.Fingerprint, req.InviteCode))
 	if err != nil {
 		if err == auth.ErrEmailTaken {
 			c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
 			return
 		}
+		if inviteErrorParam(err) != "" {
+			handleError(c, err, "Register")
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create account"})
 		return
 	}
+	h.trackRegistration(c, user.ID, "password")
+	h.adoptGuest(c, user)
+	h.sendVerification(user)
 
 	// Create session
 	token, err := h.AuthService.CreateSession(user.ID, c.Request.UserAgent(), c.ClientIP())
@@ -139,6 +301,56 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	})
 }
 
+// sendVerification emails a new password account its verification link.
+// Failures are logged: the user can ask for another link.
+func (h *AuthHandler) sendVerification(user *models.User) {
+	if h.Verification == nil {
+		return
+	}
+	err := h.Verification.SendVerification(user)
+	if err != nil && !errors.Is(err, services.ErrEmailDisabled) {
+		log.Printf("[Auth] Failed to send verification email to user %s: %v", user.ID, err)
+	}
+}
+
+// ResendVerification emails the current user a new verification link
+// POST /api/auth/verify/resend
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	if h.Verification == nil {
+		handleError(c, services.ErrEmailDisabled, "ResendVerification")
+		return
+	}
+	if err := h.Verification.SendVerification(user); err != nil {
+		handleError(c, err, "ResendVerification")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+// VerifyEmail is where verification links land. It verifies the address and
+// sends the browser on to the app, or to the login page if the link is
+// invalid, used or expired.
+// GET /api/auth/verify?token=
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	if h.Verification == nil {
+		c.Redirect(http.StatusTemporaryRedirect, h.Config.FrontendURL+"/login?error=verification_failed")
+		return
+	}
+
+	if _, err := h.Verification.Verify(c.Query("token")); err != nil {
+		if !errors.Is(err, services.ErrInvalidVerificationToken) {
+			log.Printf("[Auth] Failed to verify email: %v", err)
+		}
+		c.Redirect(http.StatusTemporaryRedirect, h.Config.FrontendURL+"/login?error=verification_failed")
+		return
+	}
+
+	c.Redirect(http.StatusTemporaryRedirect, h.Config.FrontendURL+"/?emailVerified=1")
+}
+
 // Login authenticates a user and creates a session
 // POST /api/auth/login
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -161,6 +373,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Authentication failed"})
 		return
 	}
+	h.adoptGuest(c, user)
 
 	// Create session
 	token, err := h.AuthService.CreateSession(user.ID, c.Request.UserAgent(), c.ClientIP())
@@ -208,13 +421,7 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:            user.ID.String(),
-		Email:         user.Email,
-		Name:          user.Name,
-		AvatarURL:     user.AvatarURL,
-		EmailVerified: user.EmailVerified,
-	})
+	c.JSON(http.StatusOK, newUserResponse(user))
 }
 
 // ============================================
@@ -252,8 +459,32 @@ func (h *AuthHandler) clearOAuthStateCookie(c *gin.Context) {
 	c.SetCookie("oauth_state", "", -1, "/", domain, secure, true)
 }
 
+// setOAuthInviteCookie carries an invite code through the OAuth redirect,
+// for accounts created while signups are invite-only
+func (h *AuthHandler) setOAuthInviteCookie(c *gin.Context, code string) {
+	if code == "" || len(code) > 64 {
+		return
+	}
+	secure, sameSite, domain := h.getCookieSettings()
+	c.SetSameSite(sameSite)
+	c.SetCookie("oauth_invite", code, 300, "/", domain, secure, true)
+}
+
+// takeOAuthInviteCookie returns the invite code from the OAuth redirect and
+// removes the cookie
+func (h *AuthHandler) takeOAuthInviteCookie(c *gin.Context) string {
+	code, err := c.Cookie("oauth_invite")
+	if err != nil {
+		return ""
+	}
+	secure, sameSite, domain := h.getCookieSettings()
+	c.SetSameSite(sameSite)
+	c.SetCookie("oauth_invite", "", -1, "/", domain, secure, true)
+	return code
+}
+
 // GoogleLogin initiates Google OAuth flow
-// GET /api/auth/google
+// GET /api/auth/google?invite=CODE
 func (h *AuthHandler) GoogleLogin(c *gin.Context) {
 	if !h.OAuthService.IsGoogleEnabled() {
 		c.JSON(http.StatusNotImplemented, gin.H{"error": "Google OAuth not configured"})
@@ -267,6 +498,7 @@ func (h *AuthHandler) GoogleLogin(c *gin.Context) {
 	}
 
 	h.setOAuthStateCookie(c, state)
+	h.setOAuthInviteCookie(c, c.Query("invite"))
 
 	url, err := h.OAuthService.GetGoogleAuthURL(state)
 	if err != nil {
@@ -304,18 +536,26 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 	}
 
 	// Find or create user (credits initialized atomically for new users)
-	user, _, err := h.AuthService.FindOrCreateOAuthUser(
+	user, isNew, err := h.AuthService.FindOrCreateOAuthUser(
 		"google",
 		googleUser.ID,
 		googleUser.Email,
 		googleUser.Name,
 		googleUser.Picture,
-		h.CreditsService,
+		h.creditsFor(c, "google", "", h.takeOAuthInviteCookie(c)),
 	)
 	if err != nil {
+		if param := inviteErrorParam(err); param != "" {
+			c.Redirect(http.StatusTemporaryRedirect, h.Config.FrontendURL+"/login?error="+param)
+			return
+		}
 		c.Redirect(http.StatusTemporaryRedirect, h.Config.FrontendURL+"/login?error=account_error")
 		return
 	}
+	if isNew {
+		h.trackRegistration(c, user.ID, "google")
+	}
+	h.adoptGuest(c, user)
 
 	// Create session
 	token, err := h.AuthService.CreateSession(user.ID, c.Request.UserAgent(), c.ClientIP())
@@ -332,7 +572,7 @@ func (h *AuthHandler) GoogleCallback(c *gin.Context) {
 }
 
 // GitHubLogin initiates GitHub OAuth flow
-// GET /api/auth/github
+// GET /api/auth/github?invite=CODE
 func (h *AuthHandler) GitHubLogin(c *gin.Context) {
 	if !h.OAuthService.IsGitHubEnabled() {
 		c.JSON(http.StatusNotImplemented, gin.H{"error": "GitHub OAuth not configured"})
@@ -346,6 +586,7 @@ func (h *AuthHandler) GitHubLogin(c *gin.Context) {
 	}
 
 	h.setOAuthStateCookie(c, state)
+	h.setOAuthInviteCookie(c, c.Query("invite"))
 
 	url, err := h.OAuthService.GetGitHubAuthURL(state)
 	if err != nil {
@@ -392,18 +633,26 @@ func (h *AuthHandler) GitHubCallback(c *gin.Context) {
 	}
 
 	// Find or create user (credits initialized atomically for new users)
-	user, _, err := h.AuthService.FindOrCreateOAuthUser(
+	user, isNew, err := h.AuthService.FindOrCreateOAuthUser(
 		"github",
 		githubID,
 		githubUser.Email,
 		name,
 		githubUser.AvatarURL,
-		h.CreditsService,
+		h.creditsFor(c, "github", "", h.takeOAuthInviteCookie(c)),
 	)
 	if err != nil {
+		if param := inviteErrorParam(err); param != "" {
+			c.Redirect(http.StatusTemporaryRedirect, h.Config.FrontendURL+"/login?error="+param)
+			return
+		}
 		c.Redirect(http.StatusTemporaryRedirect, h.Config.FrontendURL+"/login?error=account_error")
 		return
 	}
+	if isNew {
+		h.trackRegistration(c, user.ID, "github")
+	}
+	h.adoptGuest(c, user)
 
 	// Create session
 	token, err := h.AuthService.CreateSession(user.ID, c.Request.UserAgent(), c.ClientIP())
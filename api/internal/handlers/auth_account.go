@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ling-app/api/internal/middleware"
+)
+
+// DeleteAccount deletes the current user's account and all of their data,
+// and signs them out
+// DELETE /api/auth/me
+// Requires: RequireAuth middleware
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	if h.Accounts == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Account deletion is not available"})
+		return
+	}
+	if err := h.Accounts.DeleteAccount(user); err != nil {
+		handleError(c, err, "DeleteAccount")
+		return
+	}
+	h.clearSessionCookie(c)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted"})
+}
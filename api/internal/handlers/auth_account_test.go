@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthHandler_DeleteAccount(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "ana@example.com"}
+
+	serve := func(accounts *servicemocks.MockAccountDeleter) *httptest.ResponseRecorder {
+		handler := &AuthHandler{Config: &config.Config{}, Accounts: accounts}
+		router := setupTestRouter()
+		router.DELETE("/auth/me", func(c *gin.Context) {
+			c.Set(middleware.UserContextKey, user)
+			c.Next()
+		}, handler.DeleteAccount)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/auth/me", nil))
+		return w
+	}
+
+	t.Run("deletes the account and clears the session cookie", func(t *testing.T) {
+		accounts := new(servicemocks.MockAccountDeleter)
+		accounts.On("DeleteAccount", user).Return(nil)
+
+		w := serve(accounts)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		cookies := w.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, "session_token", cookies[0].Name)
+			assert.Negative(t, cookies[0].MaxAge)
+		}
+		accounts.AssertExpectations(t)
+	})
+
+	t.Run("failure keeps the session", func(t *testing.T) {
+		accounts := new(servicemocks.MockAccountDeleter)
+		accounts.On("DeleteAccount", user).Return(errors.New("stripe down"))
+
+		w := serve(accounts)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Result().Cookies())
+	})
+}
@@ -15,6 +15,7 @@ import (
 
 	"ling-app/api/internal/config"
 	"ling-app/api/internal/handlers"
+	"ling-app/api/internal/middleware"
 	"ling-app/api/internal/repository"
 	"ling-app/api/internal/services"
 	"ling-app/api/internal/services/auth"
@@ -38,10 +39,11 @@ func setupAuthTestRouter(t *testing.T) (*gin.Engine, *testutil.TestDB) {
 	sessionRepo := repository.NewSessionRepository()
 	creditsRepo := repository.NewCreditsRepository()
 	creditTxRepo := repository.NewCreditTransactionRepository()
+	creditHoldRepo := repository.NewCreditReservationRepository()
 
 	// Initialize services
 	authService := auth.NewAuthService(testDB.DB, userRepo, sessionRepo, 86400)
-	creditsService := services.NewCreditsService(testDB.DB, creditsRepo, creditTxRepo)
+	creditsService := services.NewCreditsService(testDB.DB, creditsRepo, creditTxRepo, creditHoldRepo)
 
 	// Config for testing
 	cfg := &config.Config{
@@ -51,7 +53,7 @@ func setupAuthTestRouter(t *testing.T) (*gin.Engine, *testutil.TestDB) {
 	}
 
 	// Initialize handler
-	authHandler := handlers.NewAuthHandler(authService, nil, creditsService, cfg)
+	authHandler := handlers.NewAuthHandler(authService, nil, creditsService, cfg, nil)
 
 	// Setup router
 	router := gin.New()
@@ -60,6 +62,9 @@ func setupAuthTestRouter(t *testing.T) (*gin.Engine, *testutil.TestDB) {
 		api.POST("/register", authHandler.Register)
 		api.POST("/login", authHandler.Login)
 		api.POST("/logout", authHandler.Logout)
+		api.GET("/sessions", middleware.RequireAuth(authService), authHandler.ListSessions)
+		api.DELETE("/sessions", middleware.RequireAuth(authService), authHandler.LogoutEverywhere)
+		api.DELETE("/sessions/:id", middleware.RequireAuth(authService), authHandler.RevokeSession)
 	}
 
 	return router, testDB
@@ -284,6 +289,92 @@ func TestLogoutIntegration(t *testing.T) {
 	}
 }
 
+func sessionCookieFrom(t *testing.T, w *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "session_token" {
+			return c
+		}
+	}
+	t.Fatal("no session cookie set")
+	return nil
+}
+
+func TestSessionsIntegration(t *testing.T) {
+	router, testDB := setupAuthTestRouter(t)
+	if testDB == nil {
+		return
+	}
+
+	// Sign in on two devices
+	registerPayload, _ := json.Marshal(map[string]string{
+		"email":    "devices@example.com",
+		"password": "password123",
+		"name":     "Devices Test User",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/register", bytes.NewReader(registerPayload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	laptop := sessionCookieFrom(t, w)
+
+	loginPayload, _ := json.Marshal(map[string]string{"email": "devices@example.com", "password": "password123"})
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(loginPayload))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	phone := sessionCookieFrom(t, w)
+
+	// The laptop sees both, and is marked current
+	req = httptest.NewRequest(http.MethodGet, "/api/auth/sessions", nil)
+	req.AddCookie(laptop)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list struct {
+		Sessions []handlers.SessionResponse `json:"sessions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Sessions, 2)
+	var phoneID string
+	for _, s := range list.Sessions {
+		assert.NotEqual(t, laptop.Value, s.ID, "tokens must not be listed")
+		if !s.Current {
+			phoneID = s.ID
+		}
+	}
+	require.NotEmpty(t, phoneID)
+
+	// Revoking the phone signs it out
+	req = httptest.NewRequest(http.MethodDelete, "/api/auth/sessions/"+phoneID, nil)
+	req.AddCookie(laptop)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/auth/sessions", nil)
+	req.AddCookie(phone)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Logging out everywhere ends the laptop's session too
+	req = httptest.NewRequest(http.MethodDelete, "/api/auth/sessions", nil)
+	req.AddCookie(laptop)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/auth/sessions", nil)
+	req.AddCookie(laptop)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 func TestCreditsInitializedOnRegister(t *testing.T) {
 	router, testDB := setupAuthTestRouter(t)
 	if testDB == nil {
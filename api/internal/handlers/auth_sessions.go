@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+)
+
+// SessionResponse is one signed-in device. The ID is the session's public ID,
+// never its token.
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"userAgent"`
+	IPAddress string    `json:"ipAddress"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Current   bool      `json:"current"`
+}
+
+func newSessionResponse(session *models.Session, currentToken string) SessionResponse {
+	return SessionResponse{
+		ID:        session.PublicID(),
+		UserAgent: session.UserAgent,
+		IPAddress: session.IPAddress,
+		CreatedAt: session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+		Current:   currentToken != "" && session.ID == currentToken,
+	}
+}
+
+// ListSessions returns the devices the user is signed in on, newest first,
+// flagging the one making the request
+// GET /api/auth/sessions
+// Requires: RequireAuth middleware
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	sessions, err := h.AuthService.ActiveSessions(user.ID)
+	if err != nil {
+		handleError(c, err, "ListSessions")
+		return
+	}
+
+	token, _ := c.Cookie("session_token")
+	response := make([]SessionResponse, len(sessions))
+	for i := range sessions {
+		response[i] = newSessionResponse(&sessions[i], token)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": response})
+}
+
+// RevokeSession signs one device out. Revoking the current session also
+// clears its cookie.
+// DELETE /api/auth/sessions/:id
+// Requires: RequireAuth middleware
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+	publicID := c.Param("id")
+
+	token, _ := c.Cookie("session_token")
+	current := token != "" && (&models.Session{ID: token}).PublicID() == publicID
+
+	if err := h.AuthService.RevokeSession(user.ID, publicID); err != nil {
+		handleError(c, err, "RevokeSession")
+		return
+	}
+	if current {
+		h.clearSessionCookie(c)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// LogoutEverywhere ends every session of the user, including this one
+// DELETE /api/auth/sessions
+// Requires: RequireAuth middleware
+func (h *AuthHandler) LogoutEverywhere(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	if err := h.AuthService.DeleteAllUserSessions(user.ID); err != nil {
+		handleError(c, err, "LogoutEverywhere")
+		return
+	}
+	h.clearSessionCookie(c)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out everywhere"})
+}
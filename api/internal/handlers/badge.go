@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Public badges are cached by browsers and CDNs for this long, which is also
+// how long a revoked badge can keep showing in an embed
+const (
+	badgeCacheControl        = "public, max-age=900"
+	badgeMissingCacheControl = "public, max-age=60"
+)
+
+type BadgeHandler struct {
+	BadgeService services.BadgeManager
+}
+
+func NewBadgeHandler(badgeService services.BadgeManager) *BadgeHandler {
+	return &BadgeHandler{
+		BadgeService: badgeService,
+	}
+}
+
+// GetBadge returns the current user's badge token, if they have opted in
+// GET /api/badge
+func (h *BadgeHandler) GetBadge(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	badge, err := h.BadgeService.GetBadge(user.ID)
+	if err != nil {
+		handleError(c, err, "GetBadge")
+		return
+	}
+
+	c.JSON(http.StatusOK, badge)
+}
+
+// EnableBadge opts in to a public badge, or rotates the token of an existing one
+// POST /api/badge
+func (h *BadgeHandler) EnableBadge(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	badge, err := h.BadgeService.EnableBadge(user.ID)
+	if err != nil {
+		handleError(c, err, "EnableBadge")
+		return
+	}
+
+	c.JSON(http.StatusOK, badge)
+}
+
+// RevokeBadge turns the public badge off
+// DELETE /api/badge
+func (h *BadgeHandler) RevokeBadge(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	if err := h.BadgeService.RevokeBadge(user.ID); err != nil {
+		handleError(c, err, "RevokeBadge")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Badge revoked"})
+}
+
+// GetPublicBadge renders a badge for embedding: SVG for "<token>.svg" or
+// ?format=svg, JSON otherwise. No authentication; the token is the capability.
+// GET /api/public/badge/:token
+func (h *BadgeHandler) GetPublicBadge(c *gin.Context) {
+	token := c.Param("token")
+	asSVG := c.Query("format") == "svg"
+	if trimmed, ok := strings.CutSuffix(token, ".svg"); ok {
+		token, asSVG = trimmed, true
+	}
+
+	stats, err := h.BadgeService.PublicStats(token)
+	if err != nil {
+		if errors.Is(err, services.ErrBadgeNotFound) {
+			c.Header("Cache-Control", badgeMissingCacheControl)
+		}
+		handleError(c, err, "GetPublicBadge")
+		return
+	}
+
+	var body []byte
+	contentType := "application/json; charset=utf-8"
+	if asSVG {
+		body = []byte(stats.SVG())
+		contentType = "image/svg+xml; charset=utf-8"
+		// Embedded as an image, never as a document with scripts
+		c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	} else if body, err = json.Marshal(stats); err != nil {
+		handleError(c, err, "GetPublicBadge")
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	c.Header("Cache-Control", badgeCacheControl)
+	c.Header("ETag", etag)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Vary", "Accept-Encoding")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBadgeHandler_GetPublicBadge(t *testing.T) {
+	accuracy := 92.0
+	stats := &services.BadgeStats{StreakDays: 120, Accuracy: &accuracy, PhonemesPracticed: 900}
+
+	newRouter := func(badgeService services.BadgeManager) *gin.Engine {
+		router := setupTestRouter()
+		router.GET("/api/public/badge/:token", NewBadgeHandler(badgeService).GetPublicBadge)
+		return router
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		badgeService := new(servicemocks.MockBadgeManager)
+		badgeService.On("PublicStats", "tok").Return(stats, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/public/badge/tok", nil)
+		newRouter(badgeService).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, badgeCacheControl, w.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+		var resp services.BadgeStats
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 120, resp.StreakDays)
+	})
+
+	t.Run("SVG by extension", func(t *testing.T) {
+		badgeService := new(servicemocks.MockBadgeManager)
+		badgeService.On("PublicStats", "tok").Return(stats, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/public/badge/tok.svg", nil)
+		newRouter(badgeService).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "image/svg+xml")
+		assert.Contains(t, w.Body.String(), "120-day streak · 92% accuracy")
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		badgeService := new(servicemocks.MockBadgeManager)
+		badgeService.On("PublicStats", "tok").Return(stats, nil)
+		router := newRouter(badgeService)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/public/badge/tok?format=svg", nil)
+		router.ServeHTTP(w, req)
+		etag := w.Header().Get("ETag")
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/api/public/badge/tok?format=svg", nil)
+		req.Header.Set("If-None-Match", etag)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		badgeService := new(servicemocks.MockBadgeManager)
+		badgeService.On("PublicStats", "old").Return(nil, services.ErrBadgeNotFound)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/public/badge/old.svg", nil)
+		newRouter(badgeService).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, badgeMissingCacheControl, w.Header().Get("Cache-Control"))
+	})
+}
+
+func TestBadgeHandler_EnableAndRevoke(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	badgeService := new(servicemocks.MockBadgeManager)
+	badgeService.On("EnableBadge", user.ID).Return(&models.StatsBadge{UserID: user.ID, Token: "new-token"}, nil)
+	badgeService.On("RevokeBadge", user.ID).Return(nil)
+
+	handler := NewBadgeHandler(badgeService)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/api/badge", handler.EnableBadge)
+	router.DELETE("/api/badge", handler.RevokeBadge)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/badge", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"new-token"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodDelete, "/api/badge", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	badgeService.AssertExpectations(t)
+}
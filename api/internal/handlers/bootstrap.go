@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BootstrapHandler struct {
+	Bootstrap services.Bootstrapper
+}
+
+func NewBootstrapHandler(bootstrap services.Bootstrapper) *BootstrapHandler {
+	return &BootstrapHandler{
+		Bootstrap: bootstrap,
+	}
+}
+
+// BootstrapResponse is everything the app loads when it opens
+type BootstrapResponse struct {
+	User UserResponse `json:"user"`
+	*services.Bootstrap
+}
+
+// GetBootstrap returns the signed-in user, their credits and their last
+// active thread with its messages in one round trip. Sections that fail to
+// load are listed in "unavailable" instead of failing the request.
+// GET /api/bootstrap
+func (h *BootstrapHandler) GetBootstrap(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	c.JSON(http.StatusOK, BootstrapResponse{
+		User:      newUserResponse(user),
+		Bootstrap: h.Bootstrap.GetBootstrap(user),
+	})
+}
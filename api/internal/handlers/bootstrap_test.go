@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapHandler_GetBootstrap(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "ana@example.com"}
+	thread := &models.Thread{ID: uuid.New(), UserID: user.ID, Messages: []models.Message{{ID: uuid.New(), Content: "Hello"}}}
+	bootstrap := new(servicemocks.MockBootstrapper)
+	bootstrap.On("GetBootstrap", user).Return(&services.Bootstrap{
+		LastThread:  thread,
+		Unavailable: []string{services.BootstrapSectionCredits},
+	})
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/api/bootstrap", NewBootstrapHandler(bootstrap).GetBootstrap)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/bootstrap", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code, "a missing section doesn't fail the request")
+	var resp struct {
+		User        map[string]interface{} `json:"user"`
+		Credits     *models.Credits        `json:"credits"`
+		LastThread  *models.Thread         `json:"lastThread"`
+		Unavailable []string               `json:"unavailable"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "ana@example.com", resp.User["email"])
+	assert.Nil(t, resp.Credits)
+	require.NotNil(t, resp.LastThread)
+	assert.Equal(t, thread.ID, resp.LastThread.ID)
+	assert.Len(t, resp.LastThread.Messages, 1)
+	assert.Equal(t, []string{"credits"}, resp.Unavailable)
+}
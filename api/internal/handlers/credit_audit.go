@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreditAuditHandler struct {
+	CreditAuditService services.CreditAuditor
+}
+
+func NewCreditAuditHandler(creditAuditService services.CreditAuditor) *CreditAuditHandler {
+	return &CreditAuditHandler{
+		CreditAuditService: creditAuditService,
+	}
+}
+
+type DisputeTransactionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GetTransaction returns one credit transaction with the message it paid for
+// GET /api/credits/history/:transactionId
+func (h *CreditAuditHandler) GetTransaction(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	transactionID, err := uuid.Parse(c.Param("transactionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	detail, err := h.CreditAuditService.GetTransactionDetail(user.ID, transactionID)
+	if err != nil {
+		handleError(c, err, "GetTransaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// DisputeTransaction flags a credit charge for admin review
+// POST /api/credits/history/:transactionId/dispute
+func (h *CreditAuditHandler) DisputeTransaction(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	transactionID, err := uuid.Parse(c.Param("transactionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	var req DisputeTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reason is required"})
+		return
+	}
+	if len(reason) > services.MaxDisputeReasonLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reason is too long"})
+		return
+	}
+
+	dispute, err := h.CreditAuditService.OpenDispute(user.ID, transactionID, reason)
+	if err != nil {
+		handleError(c, err, "DisputeTransaction")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
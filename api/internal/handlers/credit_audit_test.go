@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCreditAuditRouter(handler *CreditAuditHandler, user *models.User) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/credits/history/:transactionId", handler.GetTransaction)
+	router.POST("/credits/history/:transactionId/dispute", handler.DisputeTransaction)
+	return router
+}
+
+func TestCreditAuditHandler_GetTransaction(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	txID := uuid.New()
+
+	auditService := new(servicemocks.MockCreditAuditor)
+	auditService.On("GetTransactionDetail", user.ID, txID).Return(&services.TransactionDetail{
+		Transaction: models.CreditTransaction{ID: txID, Type: models.TransactionDebit, Amount: -1},
+	}, nil)
+
+	router := setupCreditAuditRouter(NewCreditAuditHandler(auditService), user)
+
+	req := httptest.NewRequest("GET", "/credits/history/"+txID.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	auditService.AssertExpectations(t)
+}
+
+func TestCreditAuditHandler_GetTransaction_NotFound(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	txID := uuid.New()
+
+	auditService := new(servicemocks.MockCreditAuditor)
+	auditService.On("GetTransactionDetail", user.ID, txID).Return(nil, services.ErrTransactionNotFound)
+
+	router := setupCreditAuditRouter(NewCreditAuditHandler(auditService), user)
+
+	req := httptest.NewRequest("GET", "/credits/history/"+txID.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreditAuditHandler_DisputeTransaction(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	txID := uuid.New()
+
+	auditService := new(servicemocks.MockCreditAuditor)
+	auditService.On("OpenDispute", user.ID, txID, "Charged for a failed message").
+		Return(&models.CreditDispute{ID: uuid.New(), TransactionID: txID, Status: models.DisputeOpen}, nil)
+
+	router := setupCreditAuditRouter(NewCreditAuditHandler(auditService), user)
+
+	req := httptest.NewRequest("POST", "/credits/history/"+txID.String()+"/dispute",
+		strings.NewReader(`{"reason":"  Charged for a failed message "}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	auditService.AssertExpectations(t)
+}
+
+func TestCreditAuditHandler_DisputeTransaction_AlreadyDisputed(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	txID := uuid.New()
+
+	auditService := new(servicemocks.MockCreditAuditor)
+	auditService.On("OpenDispute", user.ID, txID, "Again").Return(nil, services.ErrDisputeExists)
+
+	router := setupCreditAuditRouter(NewCreditAuditHandler(auditService), user)
+
+	req := httptest.NewRequest("POST", "/credits/history/"+txID.String()+"/dispute", strings.NewReader(`{"reason":"Again"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
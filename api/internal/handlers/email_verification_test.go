@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupVerificationRouter(user *models.User, verifier services.EmailVerifier) *gin.Engine {
+	handler := &AuthHandler{Config: &config.Config{FrontendURL: "http://app.test"}, Verification: verifier}
+	router := setupTestRouter()
+	router.GET("/auth/verify", handler.VerifyEmail)
+	router.POST("/auth/verify/resend", func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	}, handler.ResendVerification)
+	return router
+}
+
+func TestAuthHandler_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		location string
+	}{
+		{name: "verified", location: "http://app.test/?emailVerified=1"},
+		{name: "invalid link", err: services.ErrInvalidVerificationToken, location: "http://app.test/login?error=verification_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := new(servicemocks.MockEmailVerifier)
+			if tt.err != nil {
+				verifier.On("Verify", "tok").Return(nil, tt.err)
+			} else {
+				verifier.On("Verify", "tok").Return(&models.User{EmailVerified: true}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/verify?token=tok", nil)
+			w := httptest.NewRecorder()
+			setupVerificationRouter(nil, verifier).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestAuthHandler_ResendVerification(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "ana@example.com"}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "sent", status: http.StatusAccepted},
+		{name: "already verified", err: services.ErrEmailAlreadyVerified, status: http.StatusConflict},
+		{name: "rate limited", err: services.ErrTooManyVerificationEmails, status: http.StatusTooManyRequests},
+		{name: "email disabled", err: services.ErrEmailDisabled, status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := new(servicemocks.MockEmailVerifier)
+			verifier.On("SendVerification", user).Return(tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/verify/resend", nil)
+			w := httptest.NewRecorder()
+			setupVerificationRouter(user, verifier).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			verifier.AssertExpectations(t)
+		})
+	}
+}
@@ -2,9 +2,14 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"ling-app/api/internal/apierror"
+	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
 	"ling-app/api/internal/services"
 	"ling-app/api/internal/services/auth"
@@ -32,6 +37,8 @@ func handleError(c *gin.Context, err error, operation string) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 	case errors.Is(err, auth.ErrSessionNotFound):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
+	case errors.Is(err, auth.ErrUnknownSession):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
 
 	// Service errors
 	case errors.Is(err, services.ErrSubscriptionNotFound):
@@ -39,15 +46,137 @@ func handleError(c *gin.Context, err error, operation string) {
 	case errors.Is(err, services.ErrInvalidWebhook):
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook"})
 	case errors.Is(err, services.ErrInsufficientCredits):
-		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient credits"})
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient credits", "code": "INSUFFICIENT_CREDITS"})
+	case errors.Is(err, services.ErrTierLimitReached):
+		apierror.RespondWithError(c, apierror.FromUsageError(err))
+	case errors.Is(err, services.ErrNotificationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+	case errors.Is(err, services.ErrTransactionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+	case errors.Is(err, services.ErrTransactionNotDisputable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only charges can be disputed"})
+	case errors.Is(err, services.ErrDisputeExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "This transaction has already been disputed"})
+	case errors.Is(err, services.ErrNothingToExport):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Keep practicing! There aren't enough pronunciation results to build a deck yet.", "code": "NOTHING_TO_EXPORT"})
+	case errors.Is(err, services.ErrExportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found. It may still be building."})
+	case errors.Is(err, services.ErrNothingToReport):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Keep practicing! There aren't any pronunciation results to report yet.", "code": "NOTHING_TO_REPORT"})
+	case errors.Is(err, services.ErrUnknownPhoneme):
+		c.JSON(http.StatusNotFound, gin.H{"error": "No examples for this phoneme"})
+	case errors.Is(err, services.ErrBadgeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Badge not found"})
+	case errors.Is(err, services.ErrUnknownRuntimeSetting):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRuntimeSetting):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMergeSameAccount):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An account can't be merged into itself"})
+	case errors.Is(err, services.ErrMergeNotAllowed), errors.Is(err, services.ErrMergeBothSubscribed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSignupNotPendingReview):
+		c.JSON(http.StatusConflict, gin.H{"error": "This signup is not waiting for review"})
+	case errors.Is(err, services.ErrInviteRequired):
+		c.JSON(http.StatusForbidden, gin.H{"error": "An invite code is required to sign up", "code": "INVITE_REQUIRED"})
+	case errors.Is(err, services.ErrInvalidInviteCode):
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invite code is invalid, used up or expired", "code": "INVALID_INVITE"})
+	case errors.Is(err, services.ErrInvalidInvite):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyInvited):
+		c.JSON(http.StatusConflict, gin.H{"error": "This waitlist entry has already been invited"})
+	case errors.Is(err, services.ErrWarehouseExportDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Warehouse export is not configured"})
+	case errors.Is(err, services.ErrWarehouseExportRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": "A warehouse export is already running"})
+	case errors.Is(err, services.ErrAnalysisPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "Wait for the pronunciation analysis to finish before correcting the transcript"})
+	case errors.Is(err, services.ErrAnalysisNotFailed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Only failed pronunciation analyses can be retried"})
+	case errors.Is(err, services.ErrTooManyAnalysisRetries):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("This analysis has already been retried %d times", services.MaxPronunciationRetries), "code": "TOO_MANY_RETRIES"})
+	case errors.Is(err, services.ErrPracticeSessionRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": "A practice session is already running in this thread"})
+	case errors.Is(err, services.ErrContentEncryptionUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Content encryption is not configured"})
+	case errors.Is(err, services.ErrGuestModeDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": "The guest demo is not available", "code": "GUEST_MODE_DISABLED"})
+	case errors.Is(err, services.ErrGuestThreadLimit):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Sign up to start another conversation", "code": "GUEST_LIMIT"})
+	case errors.Is(err, services.ErrTooManyGuests):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many demos from this network. Sign up to keep practicing.", "code": "TOO_MANY_GUESTS"})
+	case errors.Is(err, services.ErrThreadShareNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "This link has expired or been revoked", "code": "SHARE_NOT_FOUND"})
+	case errors.Is(err, services.ErrTooManyLiveConnections):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many open connections. Close another tab or device.", "code": "TOO_MANY_CONNECTIONS"})
+	case errors.Is(err, services.ErrTooManyShareViewers):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many people are viewing this link", "code": "TOO_MANY_VIEWERS"})
+	case errors.Is(err, services.ErrThreadEnded):
+		c.JSON(http.StatusConflict, gin.H{"error": "This conversation has ended. Start a new one to keep practicing.", "code": "THREAD_ENDED"})
+	case errors.Is(err, services.ErrThreadNotEnded):
+		c.JSON(http.StatusConflict, gin.H{"error": "Only ended conversations can be continued"})
+	case services.TurnStage(err) != "":
+		apierror.RespondWithError(c, apierror.FromVoiceTurnError(err))
 
 	// Validation errors
 	case errors.Is(err, services.ErrAudioTooShort):
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio must be at least 1 second long. Please record a longer message."})
+		c.JSON(http.StatusBadRequest, gin.H{"error": audioDurationMessage(err)})
 	case errors.Is(err, services.ErrAudioTooLong):
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio must be 30 seconds or less. Please record a shorter message."})
+		c.JSON(http.StatusBadRequest, gin.H{"error": audioDurationMessage(err)})
 	case errors.Is(err, services.ErrAudioInvalid):
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid audio file"})
+	case errors.Is(err, services.ErrNoAudioChunks):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one audio file is required"})
+	case errors.Is(err, services.ErrTooManyAudioChunks):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A long-form message can have at most %d recordings", services.LongFormMaxChunks)})
+	case errors.Is(err, services.ErrAudioFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Audio file is too large"})
+	case errors.Is(err, services.ErrInvalidStripeSyncWindow):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sync start must be within the last 30 days"})
+	case errors.Is(err, services.ErrInvalidAudioRetention):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio retention must be 0 (keep), 7, 30 or 90 days"})
+	case errors.Is(err, services.ErrInvalidReplyLength):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reply length must be short, medium or long"})
+	case errors.Is(err, services.ErrInvalidLanguage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Language must be one of " + strings.Join(services.LanguageCodes(), ", ")})
+	case errors.Is(err, services.ErrLocaleLanguageMismatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Locale must be a variant of the thread's language"})
+	case errors.Is(err, services.ErrThreadLanguageLocked):
+		c.JSON(http.StatusConflict, gin.H{"error": "The language can't be changed once the thread has messages"})
+	case errors.Is(err, services.ErrInvalidSpeechRate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Speech rate must be between %.1f and %.1f", models.MinSpeechRate, models.MaxSpeechRate)})
+	case errors.Is(err, services.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Timezone must be an IANA timezone such as Europe/Madrid"})
+	case errors.Is(err, services.ErrInvalidEmailFrequency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email frequency must be instant, daily or off"})
+	case errors.Is(err, services.ErrUnsupportedCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Plans aren't sold in that currency"})
+	case errors.Is(err, services.ErrInvalidCountry):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Country must be a two-letter ISO 3166 code such as DE"})
+	case errors.Is(err, services.ErrInvalidTextMessage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Message must be 1 to %d characters", services.MaxTextMessageLength)})
+	case errors.Is(err, services.ErrInvalidTranscript):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Transcript must be 1 to %d characters", services.MaxTranscriptLength)})
+	case errors.Is(err, services.ErrTranscriptNotEditable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only the transcripts of single voice messages can be corrected"})
+	case errors.Is(err, services.ErrAnalysisNotRequeuable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only single voice messages whose recording is still stored can be analyzed again"})
+	case errors.Is(err, services.ErrInvalidPracticeSessionLength):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Practice sessions must be %d to %d minutes long", models.MinPracticeSessionMinutes, models.MaxPracticeSessionMinutes)})
+	case errors.Is(err, services.ErrInvalidCreditGrant), errors.Is(err, services.ErrInvalidCreditAdjustment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Credit adjustments must be between -%d and %d, and not 0", services.MaxSupportCreditGrant, services.MaxSupportCreditGrant)})
+	case errors.Is(err, services.ErrInvalidTier):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown subscription tier"})
+	case errors.Is(err, services.ErrSupportReasonRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required"})
+	case errors.Is(err, services.ErrEmailDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email is not configured"})
+	case errors.Is(err, services.ErrEmailAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already verified"})
+	case errors.Is(err, services.ErrTooManyVerificationEmails):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many verification emails. Try again in an hour.", "code": "TOO_MANY_VERIFICATION_EMAILS"})
+	case errors.Is(err, services.ErrInvalidLearnerProfile):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 
 	// Default to internal server error
 	default:
@@ -55,6 +184,28 @@ func handleError(c *gin.Context, err error, operation string) {
 	}
 }
 
+// audioDurationMessage tells the user which duration limit their recording broke
+func audioDurationMessage(err error) string {
+	var durationErr *services.AudioDurationError
+	switch {
+	case errors.As(err, &durationErr) && errors.Is(err, services.ErrAudioTooLong):
+		return fmt.Sprintf("Audio must be %s or less. Please record a shorter message.", formatSeconds(durationErr.Limit))
+	case errors.As(err, &durationErr):
+		return fmt.Sprintf("Audio must be at least %s long. Please record a longer message.", formatSeconds(durationErr.Limit))
+	case errors.Is(err, services.ErrAudioTooLong):
+		return "Audio is too long. Please record a shorter message."
+	default:
+		return "Audio is too short. Please record a longer message."
+	}
+}
+
+func formatSeconds(seconds float64) string {
+	if seconds == 1 {
+		return "1 second"
+	}
+	return strconv.FormatFloat(seconds, 'f', -1, 64) + " seconds"
+}
+
 // handleValidationError handles request validation/binding errors
 func handleValidationError(c *gin.Context, err error) {
 	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FeatureUsageHandler struct {
+	Reporter services.FeatureUsageReporter
+}
+
+func NewFeatureUsageHandler(reporter services.FeatureUsageReporter) *FeatureUsageHandler {
+	return &FeatureUsageHandler{
+		Reporter: reporter,
+	}
+}
+
+// GetFeatureUsage reports uses, failures, credits and latency per feature
+// over the last few days, with the daily breakdown
+// GET /api/admin/feature-usage?days=30
+func (h *FeatureUsageHandler) GetFeatureUsage(c *gin.Context) {
+	days := services.DefaultFeatureUsageDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed <= 0 || parsed > services.MaxFeatureUsageDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.Reporter.Report(days)
+	if err != nil {
+		handleError(c, err, "GetFeatureUsage")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// recordFeatureUsage records a use of feature that started at start and
+// ended with err. Failed requests are recorded without credits: they were
+// either never charged or refunded.
+func recordFeatureUsage(recorder services.FeatureUsageRecorder, userID uuid.UUID, feature string, credits int, start time.Time, err error) {
+	if recorder == nil {
+		return
+	}
+	if err != nil {
+		credits = 0
+	}
+	recorder.Record(userID, feature, credits, time.Since(start), err == nil)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFeatureUsageRouter(user *models.User, reporter services.FeatureUsageReporter) *gin.Engine {
+	handler := NewFeatureUsageHandler(reporter)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/admin/feature-usage", handler.GetFeatureUsage)
+	return router
+}
+
+func TestFeatureUsageHandler_GetFeatureUsage(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("returns the report for the requested days", func(t *testing.T) {
+		reporter := new(servicemocks.MockFeatureUsageReporter)
+		reporter.On("Report", 7).Return(&services.FeatureUsageReport{
+			Features: []services.FeatureUsageSummary{{Feature: models.FeatureVoiceMessage, Uses: 120, Credits: 118}},
+			Days:     []models.FeatureUsageDaily{},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupFeatureUsageRouter(admin, reporter).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/feature-usage?days=7", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var report services.FeatureUsageReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		require.Len(t, report.Features, 1)
+		assert.Equal(t, int64(118), report.Features[0].Credits)
+		reporter.AssertExpectations(t)
+	})
+
+	t.Run("defaults to thirty days", func(t *testing.T) {
+		reporter := new(servicemocks.MockFeatureUsageReporter)
+		reporter.On("Report", services.DefaultFeatureUsageDays).Return(&services.FeatureUsageReport{}, nil)
+
+		w := httptest.NewRecorder()
+		setupFeatureUsageRouter(admin, reporter).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/feature-usage", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		reporter.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid days", func(t *testing.T) {
+		for _, days := range []string{"0", "abc", "366"} {
+			reporter := new(servicemocks.MockFeatureUsageReporter)
+
+			w := httptest.NewRecorder()
+			setupFeatureUsageRouter(admin, reporter).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/feature-usage?days="+days, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, days)
+			reporter.AssertNotCalled(t, "Report", mock.Anything)
+		}
+	})
+}
+
+func TestRecordFeatureUsage(t *testing.T) {
+	userID := uuid.New()
+	start := time.Now().Add(-time.Second)
+
+	t.Run("records credits on success", func(t *testing.T) {
+		recorder := new(servicemocks.MockFeatureUsageRecorder)
+		recorder.On("Record", userID, models.FeatureVoiceMessage, 2,
+			mock.MatchedBy(func(d time.Duration) bool { return d >= time.Second }), true).Return()
+
+		recordFeatureUsage(recorder, userID, models.FeatureVoiceMessage, 2, start, nil)
+
+		recorder.AssertExpectations(t)
+	})
+
+	t.Run("records a failure without credits", func(t *testing.T) {
+		recorder := new(servicemocks.MockFeatureUsageRecorder)
+		recorder.On("Record", userID, models.FeatureVoiceMessage, 0, mock.Anything, false).Return()
+
+		recordFeatureUsage(recorder, userID, models.FeatureVoiceMessage, 2, start, errors.New("whisper down"))
+
+		recorder.AssertExpectations(t)
+	})
+
+	t.Run("does nothing without a recorder", func(t *testing.T) {
+		recordFeatureUsage(nil, userID, models.FeatureVoiceMessage, 2, start, nil)
+	})
+}
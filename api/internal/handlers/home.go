@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HomeHandler struct {
+	Home services.HomeProvider
+}
+
+func NewHomeHandler(home services.HomeProvider) *HomeHandler {
+	return &HomeHandler{
+		Home: home,
+	}
+}
+
+// GetHome returns the user's home screen in one payload. Sections that fail
+// to load are listed in "unavailable" instead of failing the request.
+// GET /api/home
+func (h *HomeHandler) GetHome(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	c.JSON(http.StatusOK, h.Home.GetHome(c.Request.Context(), user))
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHomeHandler_GetHome(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	home := new(servicemocks.MockHomeProvider)
+	home.On("GetHome", mock.Anything, user).Return(&services.HomeScreen{
+		Streak:      &services.HomeStreak{Days: 3},
+		DueReviews:  []services.DueReview{},
+		Motivation:  "Keep going!",
+		Unavailable: []string{services.HomeSectionCredits},
+	})
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/api/home", NewHomeHandler(home).GetHome)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/home", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code, "a missing section doesn't fail the request")
+	var resp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Keep going!", resp["motivation"])
+	assert.Nil(t, resp["credits"])
+	assert.Equal(t, []interface{}{"credits"}, resp["unavailable"])
+}
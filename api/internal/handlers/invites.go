@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InviteHandler struct {
+	Invites services.InviteManager
+}
+
+func NewInviteHandler(invites services.InviteManager) *InviteHandler {
+	return &InviteHandler{
+		Invites: invites,
+	}
+}
+
+type JoinWaitlistRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type MintInviteRequest struct {
+	Uses          int    `json:"uses" binding:"omitempty,min=1"`
+	ExpiresInDays int    `json:"expiresInDays" binding:"omitempty,min=1,max=365"` // omit for no expiry
+	Note          string `json:"note" binding:"omitempty,max=200"`
+}
+
+type InviteWaitlistRequest struct {
+	ExpiresInDays int `json:"expiresInDays" binding:"omitempty,min=1,max=365"`
+}
+
+// expiresIn returns the expiry days from now, or nil for no expiry
+func expiresIn(days int) *time.Time {
+	if days == 0 {
+		return nil
+	}
+	t := time.Now().AddDate(0, 0, days)
+	return &t
+}
+
+// GetSignupOptions tells the signup page whether an invite code is needed
+// GET /api/auth/signup-options
+func (h *InviteHandler) GetSignupOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"inviteOnly": h.Invites.InviteOnly()})
+}
+
+// JoinWaitlist adds an email to the waitlist while signups are invite-only
+// POST /api/waitlist
+func (h *InviteHandler) JoinWaitlist(c *gin.Context) {
+	if !h.Invites.InviteOnly() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Signups are open; there is no waitlist"})
+		return
+	}
+
+	var req JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	if err := h.Invites.JoinWaitlist(req.Email); err != nil {
+		handleError(c, err, "JoinWaitlist")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "You're on the waitlist. We'll email you an invite."})
+}
+
+// MintInvite creates an invite code
+// POST /api/admin/invites
+func (h *InviteHandler) MintInvite(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	var req MintInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	invite, err := h.Invites.Mint(admin.ID, services.MintInviteOptions{
+		Uses:      req.Uses,
+		ExpiresAt: expiresIn(req.ExpiresInDays),
+		Note:      strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		handleError(c, err, "MintInvite")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"invite": invite})
+}
+
+// ListInvites returns recently minted invite codes
+// GET /api/admin/invites?limit=50
+func (h *InviteHandler) ListInvites(c *gin.Context) {
+	invites, err := h.Invites.ListInvites(adminListLimit(c))
+	if err != nil {
+		handleError(c, err, "ListInvites")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invites": invites})
+}
+
+// ListWaitlist returns the waitlist, oldest first. Only entries without an
+// invite are listed unless status=all.
+// GET /api/admin/waitlist?status=pending&limit=50
+func (h *InviteHandler) ListWaitlist(c *gin.Context) {
+	entries, err := h.Invites.Waitlist(c.Query("status") != "all", adminListLimit(c))
+	if err != nil {
+		handleError(c, err, "ListWaitlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"waitlist": entries})
+}
+
+// InviteWaitlistEntry mints a single-use invite code for a waitlist entry
+// POST /api/admin/waitlist/:id/invite
+func (h *InviteHandler) InviteWaitlistEntry(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid waitlist entry ID"})
+		return
+	}
+
+	var req InviteWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	entry, invite, err := h.Invites.InviteFromWaitlist(admin.ID, entryID, expiresIn(req.ExpiresInDays))
+	if err != nil {
+		handleError(c, err, "InviteWaitlistEntry")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"entry": entry, "invite": invite})
+}
+
+// adminListLimit reads ?limit= for admin lists: 50 by default, at most 200
+func adminListLimit(c *gin.Context) int {
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 200 {
+		return l
+	}
+	return 50
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupInviteRouter(user *models.User, invites services.InviteManager) *gin.Engine {
+	handler := NewInviteHandler(invites)
+	router := setupTestRouter()
+	router.POST("/waitlist", handler.JoinWaitlist)
+	admin := router.Group("/admin", func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	admin.POST("/invites", handler.MintInvite)
+	return router
+}
+
+func TestInviteHandler_JoinWaitlist(t *testing.T) {
+	t.Run("records the email", func(t *testing.T) {
+		invites := new(servicemocks.MockInviteManager)
+		invites.On("InviteOnly").Return(true)
+		invites.On("JoinWaitlist", "learner@example.com").Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/waitlist", strings.NewReader(`{"email": "learner@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupInviteRouter(nil, invites).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		invites.AssertExpectations(t)
+	})
+
+	t.Run("closed while signups are open", func(t *testing.T) {
+		invites := new(servicemocks.MockInviteManager)
+		invites.On("InviteOnly").Return(false)
+
+		req := httptest.NewRequest(http.MethodPost, "/waitlist", strings.NewReader(`{"email": "learner@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupInviteRouter(nil, invites).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		invites.AssertNotCalled(t, "JoinWaitlist", mock.Anything)
+	})
+}
+
+func TestInviteHandler_MintInvite(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("mints with an expiry", func(t *testing.T) {
+		invites := new(servicemocks.MockInviteManager)
+		invites.On("Mint", admin.ID, mock.MatchedBy(func(opts services.MintInviteOptions) bool {
+			return opts.Uses == 25 && opts.ExpiresAt != nil && opts.Note == "spring cohort"
+		})).Return(&models.InviteCode{Code: "ABCD2345EF", UsesRemaining: 25}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/invites", strings.NewReader(`{"uses": 25, "expiresInDays": 14, "note": " spring cohort "}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupInviteRouter(admin, invites).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "ABCD2345EF")
+		invites.AssertExpectations(t)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		invites := new(servicemocks.MockInviteManager)
+		invites.On("Mint", admin.ID, mock.Anything).Return(nil, services.ErrInvalidInvite)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/invites", strings.NewReader(`{"uses": 5000}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupInviteRouter(admin, invites).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
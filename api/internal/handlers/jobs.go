@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobsHandler struct {
+	Queue *jobs.Queue
+
+	// LLM reports the shared LLM token budget alongside the queue; optional
+	LLM *services.LLMDispatcher
+}
+
+func NewJobsHandler(queue *jobs.Queue) *JobsHandler {
+	return &JobsHandler{Queue: queue}
+}
+
+// GetStats returns per-lane background job queue metrics and the LLM budget
+// background jobs draw from
+// GET /health/jobs
+func (h *JobsHandler) GetStats(c *gin.Context) {
+	stats := gin.H{"lanes": h.Queue.Stats()}
+	if h.LLM != nil {
+		stats["llm"] = h.LLM.Stats()
+	}
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LearnerProfileHandler struct {
+	Memory services.LearnerMemory
+}
+
+func NewLearnerProfileHandler(memory services.LearnerMemory) *LearnerProfileHandler {
+	return &LearnerProfileHandler{
+		Memory: memory,
+	}
+}
+
+type UpdateLearnerProfileRequest struct {
+	Enabled           *bool     `json:"enabled"` // false turns memory off and forgets the profile
+	PreferredName     *string   `json:"preferredName"`
+	Interests         *[]string `json:"interests"`
+	RecurringMistakes *[]string `json:"recurringMistakes"`
+}
+
+// GetMemory returns what the assistant remembers about the current user
+// GET /api/profile/memory
+func (h *LearnerProfileHandler) GetMemory(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	profile, err := h.Memory.GetProfile(user.ID)
+	if err != nil {
+		handleError(c, err, "GetMemory")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateMemory edits what the assistant remembers, or turns memory on or off
+// PATCH /api/profile/memory
+func (h *LearnerProfileHandler) UpdateMemory(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	var req UpdateLearnerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	profile, err := h.Memory.UpdateProfile(user.ID, services.LearnerProfileUpdate{
+		Enabled:           req.Enabled,
+		PreferredName:     req.PreferredName,
+		Interests:         req.Interests,
+		RecurringMistakes: req.RecurringMistakes,
+	})
+	if err != nil {
+		handleError(c, err, "UpdateMemory")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLearnerProfileRouter(user *models.User, memory services.LearnerMemory) *gin.Engine {
+	handler := NewLearnerProfileHandler(memory)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/profile/memory", handler.GetMemory)
+	router.PATCH("/profile/memory", handler.UpdateMemory)
+	return router
+}
+
+func TestLearnerProfileHandler_UpdateMemory(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	t.Run("edits only the fields sent", func(t *testing.T) {
+		memory := new(servicemocks.MockLearnerMemory)
+		memory.On("UpdateProfile", user.ID, mock.MatchedBy(func(u services.LearnerProfileUpdate) bool {
+			return u.PreferredName == nil && u.Enabled == nil && u.Interests != nil && len(*u.Interests) == 1
+		})).Return(&models.LearnerProfile{UserID: user.ID, Interests: models.StringList{"hiking"}}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/profile/memory", strings.NewReader(`{"interests": ["hiking"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupLearnerProfileRouter(user, memory).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []interface{}{"hiking"}, body["interests"])
+		memory.AssertExpectations(t)
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		memory := new(servicemocks.MockLearnerMemory)
+		memory.On("UpdateProfile", user.ID, mock.Anything).Return(nil, services.ErrInvalidLearnerProfile)
+
+		req := httptest.NewRequest(http.MethodPatch, "/profile/memory", strings.NewReader(`{"preferredName": "x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupLearnerProfileRouter(user, memory).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// liveUpdatesPing is how often an idle connection sends a ping to keep
+// proxies from closing it
+const liveUpdatesPing = 25 * time.Second
+
+type LiveUpdatesHandler struct {
+	Hub *services.LiveUpdateHub
+
+	// Origins browsers may connect from, besides the API's own
+	AllowedOrigins []string
+}
+
+func NewLiveUpdatesHandler(hub *services.LiveUpdateHub, allowedOrigins []string) *LiveUpdatesHandler {
+	return &LiveUpdatesHandler{
+		Hub:            hub,
+		AllowedOrigins: allowedOrigins,
+	}
+}
+
+// Connect upgrades to a WebSocket that pushes the user's background results
+// as JSON messages: "message" when a voice turn is answered, "analysis" when
+// a pronunciation analysis finishes or fails, and "title" when a thread is
+// named. A "ping" is sent when idle, and a final "closed" message with the
+// reason when the server drops the connection. The client sends nothing.
+// GET /api/ws
+func (h *LiveUpdatesHandler) Connect(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	conn, err := h.Hub.Connect(user.ID)
+	if err != nil {
+		handleError(c, err, "LiveUpdates")
+		return
+	}
+	defer conn.Close()
+
+	server := websocket.Server{
+		Handshake: h.checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			serveLiveUpdates(ws, conn)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin refuses browsers on other sites, which would otherwise connect
+// with the user's session cookie
+func (h *LiveUpdatesHandler) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil // Not a browser
+	}
+	if slices.Contains(h.AllowedOrigins, origin) || origin == "http://"+req.Host || origin == "https://"+req.Host {
+		return nil
+	}
+	return fmt.Errorf("origin %q not allowed", origin)
+}
+
+// serveLiveUpdates writes the connection's updates until either side closes it
+func serveLiveUpdates(ws *websocket.Conn, conn *services.LiveConnection) {
+	// Reading only notices the client going away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	ping := time.NewTicker(liveUpdatesPing)
+	defer ping.Stop()
+
+	for {
+		var err error
+		select {
+		case <-gone:
+			return
+		case update := <-conn.Updates():
+			err = websocket.JSON.Send(ws, update)
+		case <-conn.Done():
+			_ = websocket.JSON.Send(ws, gin.H{"type": "closed", "reason": conn.Reason()})
+			return
+		case <-ping.C:
+			err = websocket.JSON.Send(ws, gin.H{"type": "ping"})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
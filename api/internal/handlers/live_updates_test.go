@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func newLiveUpdatesServer(t *testing.T, hub *services.LiveUpdateHub, userID uuid.UUID) *httptest.Server {
+	handler := NewLiveUpdatesHandler(hub, []string{"http://app.example"})
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, &models.User{ID: userID})
+		c.Next()
+	})
+	router.GET("/ws", handler.Connect)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLiveUpdatesHandler_PushesUpdates(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	hub := services.NewLiveUpdateHub()
+	server := newLiveUpdatesServer(t, hub, userID)
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", "", "http://app.example")
+	require.NoError(t, err)
+	defer ws.Close()
+
+	// The connection registers before the handshake, so this can't be missed
+	hub.Publish(userID, services.LiveUpdate{Type: services.LiveUpdateTitle, ThreadID: threadID, Title: "At the market"})
+
+	var update services.LiveUpdate
+	require.NoError(t, websocket.JSON.Receive(ws, &update))
+	assert.Equal(t, services.LiveUpdateTitle, update.Type)
+	assert.Equal(t, threadID, update.ThreadID)
+	assert.Equal(t, "At the market", update.Title)
+
+	hub.CloseAll()
+	var closed map[string]string
+	require.NoError(t, websocket.JSON.Receive(ws, &closed))
+	assert.Equal(t, map[string]string{"type": "closed", "reason": services.LiveClosedRestart}, closed)
+}
+
+func TestLiveUpdatesHandler_RejectsOtherOrigins(t *testing.T) {
+	hub := services.NewLiveUpdateHub()
+	server := newLiveUpdatesServer(t, hub, uuid.New())
+
+	_, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", "", "http://evil.example")
+	assert.Error(t, err)
+
+	// Nothing to upgrade: the handshake is refused before any update flows
+	resp, err := http.Get(server.URL + "/ws")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
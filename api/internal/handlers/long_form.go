@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SendLongFormMessage handles a monologue sent as several recordings (repeated
+// "audio" form files, in order), or as a single long recording on Pro
+// POST /api/threads/:id/messages/long-form
+func (h *ThreadHandler) SendLongFormMessage(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+
+	if h.LongForm == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Long-form messages are not available"})
+		return
+	}
+
+	thread, err := h.threadRepo.FindByIDAndUserID(h.exec, parsedID, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch thread"})
+		return
+	}
+	if thread.EndedAt != nil {
+		handleError(c, services.ErrThreadEnded, "SendLongFormMessage")
+		return
+	}
+
+	tier := models.TierFree
+	if h.Usage != nil {
+		if err := h.Usage.CheckMessageLimit(user.ID); err != nil {
+			handleError(c, err, "SendLongFormMessage")
+			return
+		}
+		if tier, err = h.Usage.Tier(user.ID); err != nil {
+			handleError(c, err, "SendLongFormMessage")
+			return
+		}
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["audio"]) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one audio file is required"})
+		return
+	}
+
+	start := time.Now()
+	turn, err := h.LongForm.ProcessLongFormMessage(c.Request.Context(), parsedID, form.File["audio"], tier)
+	recordFeatureUsage(h.FeatureUsage, user.ID, models.FeatureLongFormMessage, turnCredits(turn), start, err)
+	if err != nil {
+		handleError(c, err, "ProcessLongFormMessage")
+		return
+	}
+
+	h.requestTitle(thread, turn.UserMessage.Content, turn.AssistantMessage.Content)
+
+	// Pick up new facts about the learner (async)
+	if h.Memory != nil {
+		h.Memory.RequestExtraction(user.ID, thread.ID)
+	}
+
+	h.trackFirstMessage(c, user.ID, thread.ID)
+
+	// Check whether this turn accomplished the thread's goal (async)
+	if h.GoalService != nil && thread.Goal != nil && thread.GoalCompletedAt == nil {
+		go h.checkGoal(thread.ID)
+	}
+
+	c.JSON(http.StatusOK, turnResponse(thread, turn))
+}
+
+// GetMessageChunks returns the recordings of a long-form message, each with
+// its transcript and pronunciation analysis
+// GET /api/threads/:id/messages/:messageId/chunks
+func (h *ThreadHandler) GetMessageChunks(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	if _, err := h.threadRepo.FindByIDAndUserID(h.exec, threadID, user.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch thread"})
+		return
+	}
+
+	message, err := h.messageRepo.FindByID(h.exec, messageID)
+	if err != nil || message.ThreadID != threadID {
+		if err == nil || errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch message"})
+		return
+	}
+
+	if message.Kind != models.MessageKindLongForm || h.LongForm == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message is not a long-form message"})
+		return
+	}
+
+	chunks, err := h.LongForm.GetChunks(message.ID)
+	if err != nil {
+		handleError(c, err, "GetMessageChunks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messageId": message.ID,
+		"chunks":    chunks,
+	})
+}
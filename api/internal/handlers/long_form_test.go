@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLongFormRouter(user *models.User, handler *ThreadHandler) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/threads/:id/messages/long-form", handler.SendLongFormMessage)
+	router.GET("/threads/:id/messages/:messageId/chunks", handler.GetMessageChunks)
+	return router
+}
+
+func newLongFormRequest(t *testing.T, threadID uuid.UUID, files int) *http.Request {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for i := range files {
+		part, err := writer.CreateFormFile("audio", fmt.Sprintf("chunk-%d.webm", i))
+		require.NoError(t, err)
+		_, err = part.Write([]byte("fake audio data"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/threads/"+threadID.String()+"/messages/long-form", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestThreadHandler_SendLongFormMessage(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	threadName := "Monologue"
+	thread := &models.Thread{ID: uuid.New(), UserID: user.ID, Name: &threadName}
+
+	t.Run("passes the chunks in order with the user's tier", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, thread.ID, user.ID).Return(thread, nil)
+		usage := new(servicemocks.MockUsageLimiter)
+		usage.On("CheckMessageLimit", user.ID).Return(nil)
+		usage.On("Tier", user.ID).Return(models.TierPro, nil)
+
+		longForm := new(servicemocks.MockLongFormProcessor)
+		longForm.On("ProcessLongFormMessage", mock.Anything, thread.ID, mock.MatchedBy(func(chunks []*multipart.FileHeader) bool {
+			return len(chunks) == 2 && chunks[0].Filename == "chunk-0.webm" && chunks[1].Filename == "chunk-1.webm"
+		}), models.TierPro).Return(&services.ConversationTurn{
+			UserMessage:      &models.Message{ID: uuid.New(), Role: "user", Content: "hola", Kind: models.MessageKindLongForm},
+			AssistantMessage: &models.Message{ID: uuid.New(), Role: "assistant", Content: "¡Muy bien!"},
+		}, nil)
+
+		handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, usage, nil, nil)
+		handler.LongForm = longForm
+
+		w := httptest.NewRecorder()
+		setupLongFormRouter(user, handler).ServeHTTP(w, newLongFormRequest(t, thread.ID, 2))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			UserMessage map[string]interface{} `json:"userMessage"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "long_form", body.UserMessage["kind"])
+		longForm.AssertExpectations(t)
+	})
+
+	t.Run("requires audio", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, thread.ID, user.ID).Return(thread, nil)
+		longForm := new(servicemocks.MockLongFormProcessor)
+
+		handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.LongForm = longForm
+
+		w := httptest.NewRecorder()
+		setupLongFormRouter(user, handler).ServeHTTP(w, newLongFormRequest(t, thread.ID, 0))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		longForm.AssertNotCalled(t, "ProcessLongFormMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too many chunks", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, thread.ID, user.ID).Return(thread, nil)
+		longForm := new(servicemocks.MockLongFormProcessor)
+		longForm.On("ProcessLongFormMessage", mock.Anything, thread.ID, mock.Anything, models.TierFree).
+			Return(nil, services.ErrTooManyAudioChunks)
+
+		handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.LongForm = longForm
+
+		w := httptest.NewRecorder()
+		setupLongFormRouter(user, handler).ServeHTTP(w, newLongFormRequest(t, thread.ID, 11))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "at most 10 recordings")
+	})
+}
+
+func TestThreadHandler_GetMessageChunks(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	threadID := uuid.New()
+
+	threadRepo := new(repomocks.MockThreadRepository)
+	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, user.ID).Return(&models.Thread{ID: threadID, UserID: user.ID}, nil)
+
+	longFormMsg := &models.Message{ID: uuid.New(), ThreadID: threadID, Kind: models.MessageKindLongForm}
+	plainMsg := &models.Message{ID: uuid.New(), ThreadID: threadID}
+	messageRepo := new(repomocks.MockMessageRepository)
+	messageRepo.On("FindByID", mock.Anything, longFormMsg.ID).Return(longFormMsg, nil)
+	messageRepo.On("FindByID", mock.Anything, plainMsg.ID).Return(plainMsg, nil)
+
+	longForm := new(servicemocks.MockLongFormProcessor)
+	longForm.On("GetChunks", longFormMsg.ID).Return([]models.MessageChunk{
+		{ID: uuid.New(), MessageID: longFormMsg.ID, Position: 0, Transcript: "hola", PronunciationStatus: "complete"},
+	}, nil)
+
+	handler := NewThreadHandler(nil, threadRepo, messageRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+	handler.LongForm = longForm
+	router := setupLongFormRouter(user, handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/threads/"+threadID.String()+"/messages/"+longFormMsg.ID.String()+"/chunks", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Chunks []models.MessageChunk `json:"chunks"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Chunks, 1)
+	assert.Equal(t, "hola", body.Chunks[0].Transcript)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/threads/"+threadID.String()+"/messages/"+plainMsg.ID.String()+"/chunks", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
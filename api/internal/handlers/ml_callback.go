@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MLCallbackHandler struct {
+	worker *services.PronunciationWorker
+	signer *services.MLCallbackSigner
+}
+
+func NewMLCallbackHandler(worker *services.PronunciationWorker, signer *services.MLCallbackSigner) *MLCallbackHandler {
+	return &MLCallbackHandler{
+		worker: worker,
+		signer: signer,
+	}
+}
+
+// MLCallbackRequest is an async pronunciation result posted by the ML service,
+// carrying the token it was given when the job was submitted
+type MLCallbackRequest struct {
+	CallbackToken string `json:"callback_token" binding:"required"`
+	client.PronunciationResponse
+}
+
+// HandlePronunciationCallback stores an async pronunciation analysis result
+// POST /api/internal/ml/callbacks
+func (h *MLCallbackHandler) HandlePronunciationCallback(c *gin.Context) {
+	var req MLCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	messageID, err := h.signer.Verify(req.CallbackToken)
+	if err != nil {
+		log.Printf("ML callback: rejected token: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid callback token"})
+		return
+	}
+
+	if err := h.worker.HandleCallback(c.Request.Context(), messageID, &req.PronunciationResponse); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// The message was deleted while the job ran; nothing to retry
+			c.JSON(http.StatusOK, gin.H{"received": true})
+			return
+		}
+		handleError(c, err, "HandlePronunciationCallback")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMLCallbackHandler_HandlePronunciationCallback(t *testing.T) {
+	signer := services.NewMLCallbackSigner("test-callback-secret-that-is-32-chars", time.Hour)
+	messageID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "valid token",
+			body:       `{"callback_token": "` + signer.Sign(messageID) + `", "status": "success", "analysis": {"phoneme_count": 1}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "forged token",
+			body:       `{"callback_token": "` + messageID.String() + `.9999999999.deadbeef", "status": "success"}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing token",
+			body:       `{"status": "success"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageRepo := new(repomocks.MockMessageRepository)
+			// Already complete, so the handler acknowledges without reprocessing
+			messageRepo.On("FindByID", mock.Anything, messageID).
+				Return(&models.Message{ID: messageID, PronunciationStatus: "complete"}, nil)
+			worker := services.NewPronunciationWorkerForTest(nil, messageRepo, nil, nil, nil, nil)
+
+			router := setupTestRouter()
+			router.POST("/internal/ml/callbacks", NewMLCallbackHandler(worker, signer).HandlePronunciationCallback)
+
+			req := httptest.NewRequest("POST", "/internal/ml/callbacks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				messageRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestMLCallbackHandler_RequiresServiceSignature(t *testing.T) {
+	callbackSigner := services.NewMLCallbackSigner("test-callback-secret-that-is-32-chars", time.Hour)
+	messageID := uuid.New()
+	body := `{"callback_token": "` + callbackSigner.Sign(messageID) + `", "status": "success"}`
+
+	tests := []struct {
+		name       string
+		signWith   string
+		wantStatus int
+	}{
+		{name: "signed with current secret", signWith: "current-internal-secret-32-chars!", wantStatus: http.StatusOK},
+		{name: "signed with previous secret", signWith: "previous-internal-secret-32-chars", wantStatus: http.StatusOK},
+		{name: "signed with unknown secret", signWith: "someone-elses-secret-of-32-chars!", wantStatus: http.StatusUnauthorized},
+		{name: "unsigned", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageRepo := new(repomocks.MockMessageRepository)
+			messageRepo.On("FindByID", mock.Anything, messageID).
+				Return(&models.Message{ID: messageID, PronunciationStatus: "complete"}, nil)
+			worker := services.NewPronunciationWorkerForTest(nil, messageRepo, nil, nil, nil, nil)
+			audit := new(servicemocks.MockAuditLogger)
+			audit.On("Record", mock.Anything).Return()
+
+			serviceSigner := client.NewServiceSigner("current-internal-secret-32-chars!", []string{"previous-internal-secret-32-chars"})
+			router := setupTestRouter()
+			router.POST("/internal/ml/callbacks",
+				middleware.RequireServiceSignature(serviceSigner, "ml-service", audit),
+				NewMLCallbackHandler(worker, callbackSigner).HandlePronunciationCallback)
+
+			req := httptest.NewRequest("POST", "/internal/ml/callbacks", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.signWith != "" {
+				client.NewServiceSigner(tt.signWith, nil).Sign(req, []byte(body))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				audit.AssertCalled(t, "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+					return entry.Action == middleware.AuditActionInternalAuth &&
+						entry.Outcome == models.AuditOutcomeFailure &&
+						entry.Details["path"] == "/internal/ml/callbacks"
+				}))
+			} else {
+				audit.AssertNotCalled(t, "Record", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestMLCallbackHandler_SignatureCoversQuery(t *testing.T) {
+	audit := new(servicemocks.MockAuditLogger)
+	audit.On("Record", mock.Anything).Return()
+	serviceSigner := client.NewServiceSigner("current-internal-secret-32-chars!", nil)
+	router := setupTestRouter()
+	router.POST("/internal/ml/callbacks",
+		middleware.RequireServiceSignature(serviceSigner, "ml-service", audit),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	body := `{"status": "success"}`
+	signed := httptest.NewRequest("POST", "/internal/ml/callbacks?attempt=1", strings.NewReader(body))
+	serviceSigner.Sign(signed, []byte(body))
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{name: "query as signed", target: "/internal/ml/callbacks?attempt=1", wantStatus: http.StatusOK},
+		{name: "query changed", target: "/internal/ml/callbacks?attempt=2", wantStatus: http.StatusUnauthorized},
+		{name: "query added to", target: "/internal/ml/callbacks?attempt=1&debug=true", wantStatus: http.StatusUnauthorized},
+		{name: "query dropped", target: "/internal/ml/callbacks", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(body))
+			req.Header = signed.Header.Clone()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestMLCallbackHandler_SamplesRejectionAudits(t *testing.T) {
+	audit := new(servicemocks.MockAuditLogger)
+	audit.On("Record", mock.Anything).Return()
+	serviceSigner := client.NewServiceSigner("current-internal-secret-32-chars!", nil)
+	router := setupTestRouter()
+	router.POST("/internal/ml/callbacks",
+		middleware.RequireServiceSignature(serviceSigner, "ml-service", audit),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(remoteAddr string) int {
+		req := httptest.NewRequest("POST", "/internal/ml/callbacks", strings.NewReader(`{}`))
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusUnauthorized, send("203.0.113.7:4000"))
+	}
+	assert.Equal(t, http.StatusUnauthorized, send("198.51.100.9:4000"))
+
+	// One row per client IP, however many times it was rejected
+	audit.AssertNumberOfCalls(t, "Record", 2)
+	audit.AssertCalled(t, "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.IPAddress == "203.0.113.7"
+	}))
+	audit.AssertCalled(t, "Record", mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.IPAddress == "198.51.100.9"
+	}))
+}
+
+func TestMLCallbackHandler_RejectsOversizedBodies(t *testing.T) {
+	audit := new(servicemocks.MockAuditLogger)
+	serviceSigner := client.NewServiceSigner("current-internal-secret-32-chars!", nil)
+	router := setupTestRouter()
+	router.POST("/internal/ml/callbacks",
+		middleware.RequireServiceSignature(serviceSigner, "ml-service", audit),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	body := strings.Repeat("x", 4<<20+1)
+	req := httptest.NewRequest("POST", "/internal/ml/callbacks", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	audit.AssertNotCalled(t, "Record", mock.Anything)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MLShadowHandler struct {
+	Reporter services.MLShadowReporter
+}
+
+func NewMLShadowHandler(reporter services.MLShadowReporter) *MLShadowHandler {
+	return &MLShadowHandler{
+		Reporter: reporter,
+	}
+}
+
+// GetMLShadowReport reports how the shadow ML model compares with the
+// primary one over the last few days: agreement, accuracy and latency per
+// analysis quality
+// GET /api/admin/ml-shadow?days=7
+func (h *MLShadowHandler) GetMLShadowReport(c *gin.Context) {
+	days := services.DefaultMLShadowDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed <= 0 || parsed > services.MaxMLShadowDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.Reporter.Report(days)
+	if err != nil {
+		handleError(c, err, "GetMLShadowReport")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMLShadowRouter(user *models.User, reporter services.MLShadowReporter) *gin.Engine {
+	handler := NewMLShadowHandler(reporter)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/admin/ml-shadow", handler.GetMLShadowReport)
+	return router
+}
+
+func TestMLShadowHandler_GetMLShadowReport(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("returns the report for the requested days", func(t *testing.T) {
+		reporter := new(servicemocks.MockMLShadowReporter)
+		reporter.On("Report", 30).Return(&services.MLShadowReport{
+			Qualities: []models.AnalysisComparisonSummary{{Quality: "accurate", Comparisons: 250, AvgAgreement: 0.94}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupMLShadowRouter(admin, reporter).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ml-shadow?days=30", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var report services.MLShadowReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		require.Len(t, report.Qualities, 1)
+		assert.Equal(t, 0.94, report.Qualities[0].AvgAgreement)
+		reporter.AssertExpectations(t)
+	})
+
+	t.Run("defaults to a week", func(t *testing.T) {
+		reporter := new(servicemocks.MockMLShadowReporter)
+		reporter.On("Report", services.DefaultMLShadowDays).Return(&services.MLShadowReport{}, nil)
+
+		w := httptest.NewRecorder()
+		setupMLShadowRouter(admin, reporter).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ml-shadow", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		reporter.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid days", func(t *testing.T) {
+		for _, days := range []string{"0", "abc", "91"} {
+			reporter := new(servicemocks.MockMLShadowReporter)
+
+			w := httptest.NewRecorder()
+			setupMLShadowRouter(admin, reporter).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ml-shadow?days="+days, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, days)
+			reporter.AssertNotCalled(t, "Report", mock.Anything)
+		}
+	})
+
+	t.Run("report failure", func(t *testing.T) {
+		reporter := new(servicemocks.MockMLShadowReporter)
+		reporter.On("Report", services.DefaultMLShadowDays).Return(nil, errors.New("db down"))
+
+		w := httptest.NewRecorder()
+		setupMLShadowRouter(admin, reporter).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/ml-shadow", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	NotificationService services.NotificationManager
+}
+
+func NewNotificationHandler(notificationService services.NotificationManager) *NotificationHandler {
+	return &NotificationHandler{
+		NotificationService: notificationService,
+	}
+}
+
+// GetNotifications returns the current user's recent notifications and unread count
+// GET /api/notifications?unread=true&limit=20&offset=0
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	unreadOnly := c.Query("unread") == "true"
+	limit := services.DefaultNotificationLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = min(parsed, services.MaxNotificationLimit)
+	}
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		offset = parsed
+	}
+
+	list, err := h.NotificationService.List(user.ID, unreadOnly, limit, offset)
+	if err != nil {
+		handleError(c, err, "GetNotifications")
+		return
+	}
+
+	middleware.SetPagination(c, limit, offset, len(list.Notifications))
+	c.JSON(http.StatusOK, list)
+}
+
+// MarkNotificationRead marks one notification as read
+// POST /api/notifications/:id/read
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.NotificationService.MarkRead(user.ID, notificationID); err != nil {
+		handleError(c, err, "MarkNotificationRead")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsRead marks all of the current user's notifications as read
+// POST /api/notifications/read-all
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	if err := h.NotificationService.MarkAllRead(user.ID); err != nil {
+		handleError(c, err, "MarkAllNotificationsRead")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read"})
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupNotificationRouter(handler *NotificationHandler, user *models.User) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/notifications", handler.GetNotifications)
+	router.POST("/notifications/read-all", handler.MarkAllNotificationsRead)
+	router.POST("/notifications/:id/read", handler.MarkNotificationRead)
+	return router
+}
+
+func TestNotificationHandler_GetNotifications(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	notificationService := new(servicemocks.MockNotificationManager)
+	notificationService.On("List", user.ID, true, 5, 0).Return(&services.NotificationList{
+		Notifications: []models.Notification{{ID: uuid.New(), Type: models.NotificationGoalCompleted, Title: "Goal completed!"}},
+		UnreadCount:   1,
+	}, nil)
+
+	router := setupNotificationRouter(NewNotificationHandler(notificationService), user)
+
+	req := httptest.NewRequest("GET", "/notifications?unread=true&limit=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response services.NotificationList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Notifications, 1)
+	assert.Equal(t, int64(1), response.UnreadCount)
+	notificationService.AssertExpectations(t)
+}
+
+func TestNotificationHandler_GetNotifications_InvalidLimit(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	router := setupNotificationRouter(NewNotificationHandler(new(servicemocks.MockNotificationManager)), user)
+
+	req := httptest.NewRequest("GET", "/notifications?limit=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNotificationHandler_GetNotifications_InvalidOffset(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	router := setupNotificationRouter(NewNotificationHandler(new(servicemocks.MockNotificationManager)), user)
+
+	for _, offset := range []string{"abc", "-5"} {
+		req := httptest.NewRequest("GET", "/notifications?offset="+offset, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, offset)
+	}
+}
+
+func TestNotificationHandler_MarkNotificationRead_NotFound(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	notificationID := uuid.New()
+
+	notificationService := new(servicemocks.MockNotificationManager)
+	notificationService.On("MarkRead", user.ID, notificationID).Return(services.ErrNotificationNotFound)
+
+	router := setupNotificationRouter(NewNotificationHandler(notificationService), user)
+
+	req := httptest.NewRequest("POST", "/notifications/"+notificationID.String()+"/read", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNotificationHandler_MarkAllNotificationsRead(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	notificationService := new(servicemocks.MockNotificationManager)
+	notificationService.On("MarkAllRead", user.ID).Return(nil)
+
+	router := setupNotificationRouter(NewNotificationHandler(notificationService), user)
+
+	req := httptest.NewRequest("POST", "/notifications/read-all", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	notificationService.AssertExpectations(t)
+}
+
+func TestNotificationHandler_GetNotifications_Envelope(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	notificationService := new(servicemocks.MockNotificationManager)
+	notificationService.On("List", user.ID, false, 5, 10).Return(&services.NotificationList{
+		Notifications: []models.Notification{{ID: uuid.New()}, {ID: uuid.New()}},
+		UnreadCount:   2,
+	}, nil)
+	handler := NewNotificationHandler(notificationService)
+
+	router := setupTestRouter()
+	v1 := router.Group("/v1", middleware.EnvelopeResponses())
+	v1.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	v1.GET("/notifications", handler.GetNotifications)
+
+	t.Run("pagination meta", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/notifications?limit=5&offset=10", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data services.NotificationList `json:"data"`
+			Meta middleware.EnvelopeMeta   `json:"meta"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Data.Notifications, 2)
+		assert.Equal(t, &middleware.Pagination{Limit: 5, Offset: 10, Count: 2}, response.Meta.Pagination)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/notifications?limit=abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.NotContains(t, response, "data")
+		assert.Equal(t, map[string]interface{}{
+			"status":  float64(http.StatusBadRequest),
+			"code":    "BAD_REQUEST",
+			"message": "Invalid limit",
+		}, response["error"])
+	})
+}
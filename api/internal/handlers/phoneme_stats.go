@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
 	"ling-app/api/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +15,7 @@ import (
 
 type PhonemeStatsHandler struct {
 	PhonemeStatsService services.PhonemeStatsProvider
+	Examples            services.PhonemeExampleProvider
 }
 
 func NewPhonemeStatsHandler(phonemeStatsService services.PhonemeStatsProvider) *PhonemeStatsHandler {
@@ -19,12 +24,13 @@ func NewPhonemeStatsHandler(phonemeStatsService services.PhonemeStatsProvider) *
 	}
 }
 
-// GetStats returns aggregated phoneme statistics for the current user
+// GetStats returns aggregated phoneme statistics for the current user in one
+// language, ?language= or English by default
 // GET /api/pronunciation/stats
 func (h *PhonemeStatsHandler) GetStats(c *gin.Context) {
 	user := middleware.MustGetUser(c)
 
-	stats, err := h.PhonemeStatsService.GetUserStats(user.ID)
+	stats, err := h.PhonemeStatsService.GetUserStats(user.ID, c.Query("language"))
 	if err != nil {
 		handleError(c, err, "GetStats")
 		return
@@ -32,3 +38,30 @@ func (h *PhonemeStatsHandler) GetStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// GetPhonemeExamples returns a drill for one phoneme: a tip and example
+// words with the keys of their clips. ?rate= picks the speaking rate of the
+// clips, 1.0 by default.
+// GET /api/pronunciation/phonemes/:phoneme/examples
+func (h *PhonemeStatsHandler) GetPhonemeExamples(c *gin.Context) {
+	rate := models.DefaultSpeechRate
+	if raw := c.Query("rate"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || !services.ValidSpeechRate(parsed) {
+			handleError(c, services.ErrInvalidSpeechRate, "GetPhonemeExamples")
+			return
+		}
+		rate = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	examples, err := h.Examples.PhonemeExamples(ctx, c.Param("phoneme"), rate)
+	if err != nil {
+		handleError(c, err, "GetPhonemeExamples")
+		return
+	}
+
+	c.JSON(http.StatusOK, examples)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestPhonemeStatsHandler_GetStats_Success(t *testing.T) {
@@ -48,7 +49,7 @@ func TestPhonemeStatsHandler_GetStats_Success(t *testing.T) {
 
 	// Mock service
 	phonemeService := new(servicemocks.MockPhonemeStatsProvider)
-	phonemeService.On("GetUserStats", userID).Return(expectedStats, nil)
+	phonemeService.On("GetUserStats", userID, "").Return(expectedStats, nil)
 
 	handler := NewPhonemeStatsHandler(phonemeService)
 
@@ -89,7 +90,7 @@ func TestPhonemeStatsHandler_GetStats_ServiceError(t *testing.T) {
 
 	// Mock service to return error
 	phonemeService := new(servicemocks.MockPhonemeStatsProvider)
-	phonemeService.On("GetUserStats", userID).Return(nil, errors.New("database error"))
+	phonemeService.On("GetUserStats", userID, "").Return(nil, errors.New("database error"))
 
 	handler := NewPhonemeStatsHandler(phonemeService)
 
@@ -134,7 +135,7 @@ func TestPhonemeStatsHandler_GetStats_EmptyStats(t *testing.T) {
 	}
 
 	phonemeService := new(servicemocks.MockPhonemeStatsProvider)
-	phonemeService.On("GetUserStats", userID).Return(emptyStats, nil)
+	phonemeService.On("GetUserStats", userID, "").Return(emptyStats, nil)
 
 	handler := NewPhonemeStatsHandler(phonemeService)
 
@@ -164,3 +165,124 @@ func TestPhonemeStatsHandler_GetStats_EmptyStats(t *testing.T) {
 
 	phonemeService.AssertExpectations(t)
 }
+
+func TestPhonemeStatsHandler_GetStats_Language(t *testing.T) {
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+
+	tests := []struct {
+		name       string
+		path       string
+		setup      func(*servicemocks.MockPhonemeStatsProvider)
+		wantStatus int
+	}{
+		{
+			name: "filters by language",
+			path: "/stats?language=es",
+			setup: func(m *servicemocks.MockPhonemeStatsProvider) {
+				m.On("GetUserStats", userID, "es").Return(&services.UserPhonemeStatsResponse{Language: "es", Languages: []string{"en", "es"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "rejects an unsupported language",
+			path: "/stats?language=xx",
+			setup: func(m *servicemocks.MockPhonemeStatsProvider) {
+				m.On("GetUserStats", userID, "xx").Return(nil, services.ErrInvalidLanguage)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phonemeService := new(servicemocks.MockPhonemeStatsProvider)
+			tt.setup(phonemeService)
+			handler := NewPhonemeStatsHandler(phonemeService)
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, user)
+				c.Next()
+			})
+			router.GET("/stats", handler.GetStats)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			phonemeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPhonemeStatsHandler_GetPhonemeExamples(t *testing.T) {
+	examples := &services.PhonemeExamples{
+		Phoneme:  "θ",
+		Tip:      "Put your tongue between your teeth",
+		Examples: []services.PhonemeExample{{Text: "think", AudioKey: "reference/think.mp3"}},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		setup      func(*servicemocks.MockPhonemeExampleProvider)
+		wantStatus int
+	}{
+		{
+			name: "returns the drill at the requested rate",
+			path: "/phonemes/θ/examples?rate=0.75",
+			setup: func(m *servicemocks.MockPhonemeExampleProvider) {
+				m.On("PhonemeExamples", mock.Anything, "θ", 0.75).Return(examples, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "defaults to normal speed",
+			path: "/phonemes/θ/examples",
+			setup: func(m *servicemocks.MockPhonemeExampleProvider) {
+				m.On("PhonemeExamples", mock.Anything, "θ", 1.0).Return(examples, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "rejects an invalid rate",
+			path:       "/phonemes/θ/examples?rate=9",
+			setup:      func(*servicemocks.MockPhonemeExampleProvider) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown phoneme",
+			path: "/phonemes/q/examples",
+			setup: func(m *servicemocks.MockPhonemeExampleProvider) {
+				m.On("PhonemeExamples", mock.Anything, "q", 1.0).Return(nil, services.ErrUnknownPhoneme)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := new(servicemocks.MockPhonemeExampleProvider)
+			tt.setup(provider)
+
+			handler := NewPhonemeStatsHandler(new(servicemocks.MockPhonemeStatsProvider))
+			handler.Examples = provider
+
+			router := setupTestRouter()
+			router.GET("/phonemes/:phoneme/examples", handler.GetPhonemeExamples)
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response services.PhonemeExamples
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "reference/think.mp3", response.Examples[0].AudioKey)
+			}
+			provider.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PracticeHandler struct {
+	AnkiExporter services.AnkiExporter
+	FeatureUsage services.FeatureUsageRecorder
+}
+
+func NewPracticeHandler(ankiExporter services.AnkiExporter) *PracticeHandler {
+	return &PracticeHandler{
+		AnkiExporter: ankiExporter,
+	}
+}
+
+// ExportAnki starts building a flashcard deck from the user's weak phonemes.
+// The deck is built in the background; a notification links to the download.
+// GET /api/practice/export/anki
+func (h *PracticeHandler) ExportAnki(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	start := time.Now()
+	exportID, err := h.AnkiExporter.RequestExport(user.ID)
+	recordFeatureUsage(h.FeatureUsage, user.ID, models.FeatureAnkiExport, 0, start, err)
+	if err != nil {
+		handleError(c, err, "ExportAnki")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"exportId":    exportID,
+		"status":      "pending",
+		"downloadUrl": services.AnkiExportDownloadPath(exportID),
+	})
+}
+
+// DownloadAnkiExport serves a finished deck
+// GET /api/practice/export/anki/:exportId
+func (h *PracticeHandler) DownloadAnkiExport(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return
+	}
+
+	obj, err := h.AnkiExporter.OpenExport(c.Request.Context(), user.ID, exportID)
+	if err != nil {
+		handleError(c, err, "DownloadAnkiExport")
+		return
+	}
+	defer obj.Body.Close()
+
+	c.DataFromReader(http.StatusOK, obj.ContentLength, "application/zip", obj.Body, map[string]string{
+		"Content-Disposition": `attachment; filename="lingapp-pronunciation-deck.zip"`,
+		"Cache-Control":       "private, no-store",
+	})
+}
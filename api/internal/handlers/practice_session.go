@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PracticeSessionHandler struct {
+	SessionService services.PracticeSessionManager
+	FeatureUsage   services.FeatureUsageRecorder
+}
+
+func NewPracticeSessionHandler(sessionService services.PracticeSessionManager) *PracticeSessionHandler {
+	return &PracticeSessionHandler{
+		SessionService: sessionService,
+	}
+}
+
+type StartPracticeSessionRequest struct {
+	ThreadID uuid.UUID `json:"threadId" binding:"required"`
+	Minutes  int       `json:"minutes"` // Timer length; 0 uses the default of 10
+}
+
+// StartSession starts a timed practice session in one of the user's threads
+// POST /api/sessions
+func (h *PracticeSessionHandler) StartSession(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	var req StartPracticeSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	start := time.Now()
+	session, err := h.SessionService.Start(user.ID, req.ThreadID, req.Minutes)
+	recordFeatureUsage(h.FeatureUsage, user.ID, models.FeaturePracticeSession, 0, start, err)
+	if err != nil {
+		handleError(c, err, "StartSession")
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// EndSession ends a practice session and returns its summary
+// POST /api/sessions/:id/end
+func (h *PracticeSessionHandler) EndSession(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	session, err := h.SessionService.End(user.ID, sessionID)
+	if err != nil {
+		handleError(c, err, "EndSession")
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// GetSessions returns the user's practice session history, newest first
+// GET /api/sessions?limit=20&offset=0
+func (h *PracticeSessionHandler) GetSessions(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	limit := services.DefaultPracticeSessionLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = min(parsed, services.MaxPracticeSessionLimit)
+	}
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		offset = parsed
+	}
+
+	sessions, err := h.SessionService.List(user.ID, limit, offset)
+	if err != nil {
+		handleError(c, err, "GetSessions")
+		return
+	}
+
+	middleware.SetPagination(c, limit, offset, len(sessions))
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
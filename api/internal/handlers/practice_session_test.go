@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupPracticeSessionRouter(handler *PracticeSessionHandler, user *models.User) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/sessions", handler.GetSessions)
+	router.POST("/sessions", handler.StartSession)
+	router.POST("/sessions/:id/end", handler.EndSession)
+	return router
+}
+
+func TestPracticeSessionHandler_StartSession(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	threadID := uuid.New()
+
+	t.Run("starts a session", func(t *testing.T) {
+		sessionService := new(servicemocks.MockPracticeSessionManager)
+		sessionService.On("Start", user.ID, threadID, 15).Return(&models.PracticeSession{
+			ID:             uuid.New(),
+			ThreadID:       threadID,
+			PlannedMinutes: 15,
+		}, nil)
+		router := setupPracticeSessionRouter(NewPracticeSessionHandler(sessionService), user)
+
+		body, _ := json.Marshal(map[string]interface{}{"threadId": threadID, "minutes": 15})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions", bytes.NewReader(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.PracticeSession
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 15, response.PlannedMinutes)
+		sessionService.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "already running", err: services.ErrPracticeSessionRunning, wantCode: http.StatusConflict},
+		{name: "bad length", err: services.ErrInvalidPracticeSessionLength, wantCode: http.StatusBadRequest},
+		{name: "someone else's thread", err: repository.ErrNotFound, wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionService := new(servicemocks.MockPracticeSessionManager)
+			sessionService.On("Start", user.ID, threadID, 0).Return(nil, tt.err)
+			router := setupPracticeSessionRouter(NewPracticeSessionHandler(sessionService), user)
+
+			body, _ := json.Marshal(map[string]interface{}{"threadId": threadID})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions", bytes.NewReader(body)))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+
+	t.Run("thread is required", func(t *testing.T) {
+		router := setupPracticeSessionRouter(NewPracticeSessionHandler(new(servicemocks.MockPracticeSessionManager)), user)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions", bytes.NewBufferString(`{"minutes": 5}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPracticeSessionHandler_EndSession(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	sessionID := uuid.New()
+	endedAt := time.Now()
+	accuracy := 87.5
+
+	sessionService := new(servicemocks.MockPracticeSessionManager)
+	sessionService.On("End", user.ID, sessionID).Return(&models.PracticeSession{
+		ID:      sessionID,
+		EndedAt: &endedAt,
+		Summary: &models.PracticeSessionSummary{
+			DurationSeconds: 300,
+			Turns:           4,
+			Accuracy:        &accuracy,
+			ScoredTurns:     3,
+			NewVocabulary:   models.StringList{"biblioteca"},
+		},
+	}, nil)
+	router := setupPracticeSessionRouter(NewPracticeSessionHandler(sessionService), user)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions/"+sessionID.String()+"/end", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.PracticeSession
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.Summary) {
+		assert.Equal(t, 4, response.Summary.Turns)
+		assert.Equal(t, models.StringList{"biblioteca"}, response.Summary.NewVocabulary)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions/nope/end", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPracticeSessionHandler_GetSessions(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	sessionService := new(servicemocks.MockPracticeSessionManager)
+	sessionService.On("List", user.ID, services.DefaultPracticeSessionLimit, 0).Return([]models.PracticeSession{{ID: uuid.New()}}, nil)
+	sessionService.On("List", user.ID, services.MaxPracticeSessionLimit, 40).Return([]models.PracticeSession{}, nil)
+	router := setupPracticeSessionRouter(NewPracticeSessionHandler(sessionService), user)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sessions", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Sessions []models.PracticeSession `json:"sessions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Sessions, 1)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sessions?limit=500&offset=40", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	sessionService.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sessions?limit=0", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sessions?offset=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupPracticeRouter(user *models.User, exporter services.AnkiExporter) *gin.Engine {
+	handler := NewPracticeHandler(exporter)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/api/practice/export/anki", handler.ExportAnki)
+	router.GET("/api/practice/export/anki/:exportId", handler.DownloadAnkiExport)
+	return router
+}
+
+func TestPracticeHandler_ExportAnki(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+
+	t.Run("queues the export", func(t *testing.T) {
+		exportID := uuid.New()
+		exporter := new(servicemocks.MockAnkiExporter)
+		exporter.On("RequestExport", user.ID).Return(exportID, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/practice/export/anki", nil)
+		setupPracticeRouter(user, exporter).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var resp map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, exportID.String(), resp["exportId"])
+		assert.Equal(t, "/api/practice/export/anki/"+exportID.String(), resp["downloadUrl"])
+	})
+
+	t.Run("nothing to export yet", func(t *testing.T) {
+		exporter := new(servicemocks.MockAnkiExporter)
+		exporter.On("RequestExport", user.ID).Return(uuid.Nil, services.ErrNothingToExport)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/practice/export/anki", nil)
+		setupPracticeRouter(user, exporter).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "NOTHING_TO_EXPORT")
+	})
+}
+
+func TestPracticeHandler_DownloadAnkiExport(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	exportID := uuid.New()
+
+	t.Run("streams the deck", func(t *testing.T) {
+		exporter := new(servicemocks.MockAnkiExporter)
+		exporter.On("OpenExport", mock.Anything, user.ID, exportID).Return(&client.StorageObject{
+			Body:          io.NopCloser(strings.NewReader("zip bytes")),
+			ContentLength: 9,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/practice/export/anki/"+exportID.String(), nil)
+		setupPracticeRouter(user, exporter).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+		assert.Equal(t, "zip bytes", w.Body.String())
+	})
+
+	t.Run("not ready or not theirs", func(t *testing.T) {
+		exporter := new(servicemocks.MockAnkiExporter)
+		exporter.On("OpenExport", mock.Anything, user.ID, exportID).Return(nil, services.ErrExportNotFound)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/practice/export/anki/"+exportID.String(), nil)
+		setupPracticeRouter(user, exporter).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/practice/export/anki/nope", nil)
+		setupPracticeRouter(user, new(servicemocks.MockAnkiExporter)).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
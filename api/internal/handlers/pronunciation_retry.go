@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RetryPronunciation runs one of the user's failed pronunciation analyses
+// again and returns the message, pending until the new result arrives
+// POST /api/messages/:id/pronunciation/retry
+func (h *ThreadHandler) RetryPronunciation(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	if h.Retries == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pronunciation analysis is not available"})
+		return
+	}
+
+	message, err := h.Retries.RetryAnalysis(user.ID, messageID)
+	if err != nil {
+		handleError(c, err, "RetryPronunciation")
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRetryRouter(user *models.User, handler *ThreadHandler) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/messages/:id/pronunciation/retry", handler.RetryPronunciation)
+	return router
+}
+
+func TestThreadHandler_RetryPronunciation(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	messageID := uuid.New()
+	path := "/messages/" + messageID.String() + "/pronunciation/retry"
+
+	t.Run("returns the pending message", func(t *testing.T) {
+		retries := new(servicemocks.MockPronunciationRetrier)
+		retries.On("RetryAnalysis", user.ID, messageID).
+			Return(&models.Message{ID: messageID, Role: "user", PronunciationStatus: "pending", PronunciationRetries: 1}, nil)
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.Retries = retries
+
+		w := httptest.NewRecorder()
+		setupRetryRouter(user, handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "pending", body["pronunciationStatus"])
+		assert.Equal(t, float64(1), body["pronunciationRetries"])
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not the user's message", repository.ErrNotFound, http.StatusNotFound},
+		{"analysis didn't fail", services.ErrAnalysisNotFailed, http.StatusConflict},
+		{"out of retries", services.ErrTooManyAnalysisRetries, http.StatusConflict},
+		{"recording deleted", services.ErrAnalysisNotRequeuable, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries := new(servicemocks.MockPronunciationRetrier)
+			retries.On("RetryAnalysis", user.ID, messageID).Return(nil, tt.err)
+			handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			handler.Retries = retries
+
+			w := httptest.NewRecorder()
+			setupRetryRouter(user, handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+
+	t.Run("invalid message ID", func(t *testing.T) {
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.Retries = new(servicemocks.MockPronunciationRetrier)
+
+		w := httptest.NewRecorder()
+		setupRetryRouter(user, handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/messages/nope/pronunciation/retry", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
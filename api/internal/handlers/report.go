@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	Reporter     services.PronunciationReporter
+	FeatureUsage services.FeatureUsageRecorder
+}
+
+func NewReportHandler(reporter services.PronunciationReporter) *ReportHandler {
+	return &ReportHandler{
+		Reporter: reporter,
+	}
+}
+
+// CreatePronunciationReport renders the user's pronunciation progress as a
+// PDF and returns a link to it that can be shared with a tutor
+// POST /api/reports/pronunciation
+func (h *ReportHandler) CreatePronunciationReport(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	start := time.Now()
+	report, err := h.Reporter.CreateReport(c.Request.Context(), user)
+	recordFeatureUsage(h.FeatureUsage, user.ID, models.FeaturePronunciationReport, 0, start, err)
+	if err != nil {
+		handleError(c, err, "CreatePronunciationReport")
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupReportRouter(user *models.User, reporter services.PronunciationReporter) *gin.Engine {
+	handler := NewReportHandler(reporter)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/api/reports/pronunciation", handler.CreatePronunciationReport)
+	return router
+}
+
+func TestReportHandler_CreatePronunciationReport(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+
+	t.Run("returns the shareable link", func(t *testing.T) {
+		report := &services.PronunciationReport{
+			ReportID:  uuid.New(),
+			URL:       "https://s3.example/report.pdf?sig",
+			ExpiresAt: time.Now().Add(services.ReportLinkExpiry),
+		}
+		reporter := new(servicemocks.MockPronunciationReporter)
+		reporter.On("CreateReport", mock.Anything, user).Return(report, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/reports/pronunciation", nil)
+		setupReportRouter(user, reporter).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var resp map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, report.ReportID.String(), resp["reportId"])
+		assert.Equal(t, report.URL, resp["url"])
+	})
+
+	t.Run("records feature usage", func(t *testing.T) {
+		reporter := new(servicemocks.MockPronunciationReporter)
+		reporter.On("CreateReport", mock.Anything, user).Return(nil, services.ErrNothingToReport)
+		recorder := new(servicemocks.MockFeatureUsageRecorder)
+		recorder.On("Record", user.ID, models.FeaturePronunciationReport, 0, mock.Anything, false).Return()
+
+		handler := NewReportHandler(reporter)
+		handler.FeatureUsage = recorder
+		router := setupTestRouter()
+		router.Use(func(c *gin.Context) {
+			c.Set(middleware.UserContextKey, user)
+			c.Next()
+		})
+		router.POST("/api/reports/pronunciation", handler.CreatePronunciationReport)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/reports/pronunciation", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		recorder.AssertExpectations(t)
+	})
+
+	t.Run("nothing to report yet", func(t *testing.T) {
+		reporter := new(servicemocks.MockPronunciationReporter)
+		reporter.On("CreateReport", mock.Anything, user).Return(nil, services.ErrNothingToReport)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/reports/pronunciation", nil)
+		setupReportRouter(user, reporter).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "NOTHING_TO_REPORT")
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RuntimeSettingsHandler struct {
+	RuntimeSettings services.RuntimeSettingsManager
+}
+
+func NewRuntimeSettingsHandler(runtimeSettings services.RuntimeSettingsManager) *RuntimeSettingsHandler {
+	return &RuntimeSettingsHandler{
+		RuntimeSettings: runtimeSettings,
+	}
+}
+
+type UpdateRuntimeSettingRequest struct {
+	Value json.RawMessage `json:"value" binding:"required"` // JSON, e.g. 2 or {"free": {"maxThreads": 30}}
+}
+
+// GetRuntimeSettings returns the settings in force and the stored overrides
+// GET /api/admin/runtime-settings
+func (h *RuntimeSettingsHandler) GetRuntimeSettings(c *gin.Context) {
+	overrides, err := h.RuntimeSettings.Overrides()
+	if err != nil {
+		handleError(c, err, "GetRuntimeSettings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings":  h.RuntimeSettings.Current(),
+		"overrides": overrides,
+	})
+}
+
+// UpdateRuntimeSetting overrides one setting. Other instances pick it up on
+// their next refresh.
+// PUT /api/admin/runtime-settings/:key
+func (h *RuntimeSettingsHandler) UpdateRuntimeSetting(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	var req UpdateRuntimeSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	settings, err := h.RuntimeSettings.Set(c.Param("key"), req.Value, user.ID)
+	if err != nil {
+		handleError(c, err, "UpdateRuntimeSetting")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// ResetRuntimeSetting removes an override so the default applies again
+// DELETE /api/admin/runtime-settings/:key
+func (h *RuntimeSettingsHandler) ResetRuntimeSetting(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	settings, err := h.RuntimeSettings.Reset(c.Param("key"), user.ID)
+	if err != nil {
+		handleError(c, err, "ResetRuntimeSetting")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// RefreshRuntimeSettings reloads the settings on this instance now instead
+// of waiting for the next refresh
+// POST /api/admin/runtime-settings/refresh
+func (h *RuntimeSettingsHandler) RefreshRuntimeSettings(c *gin.Context) {
+	if err := h.RuntimeSettings.Refresh(); err != nil {
+		handleError(c, err, "RefreshRuntimeSettings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": h.RuntimeSettings.Current()})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRuntimeSettingsRouter(user *models.User, runtimeSettings services.RuntimeSettingsManager) *gin.Engine {
+	handler := NewRuntimeSettingsHandler(runtimeSettings)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.PUT("/admin/runtime-settings/:key", handler.UpdateRuntimeSetting)
+	router.POST("/admin/runtime-settings/refresh", handler.RefreshRuntimeSettings)
+	return router
+}
+
+func TestRuntimeSettingsHandler_UpdateRuntimeSetting(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("applies the override", func(t *testing.T) {
+		settings := services.DefaultRuntimeSettings()
+		settings.MaxAudioDurationSeconds = 60
+		runtimeSettings := new(servicemocks.MockRuntimeSettingsManager)
+		runtimeSettings.On("Set", "maxAudioDurationSeconds", json.RawMessage("60"), admin.ID).Return(settings, nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/runtime-settings/maxAudioDurationSeconds", strings.NewReader(`{"value": 60}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupRuntimeSettingsRouter(admin, runtimeSettings).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Settings services.RuntimeSettings `json:"settings"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 60.0, body.Settings.MaxAudioDurationSeconds)
+		runtimeSettings.AssertExpectations(t)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		runtimeSettings := new(servicemocks.MockRuntimeSettingsManager)
+		runtimeSettings.On("Set", "creditCostPerMessage", mock.Anything, admin.ID).
+			Return(services.RuntimeSettings{}, fmt.Errorf("%w: creditCostPerMessage: must be at least 1", services.ErrInvalidRuntimeSetting))
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/runtime-settings/creditCostPerMessage", strings.NewReader(`{"value": 0}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupRuntimeSettingsRouter(admin, runtimeSettings).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "must be at least 1")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		runtimeSettings := new(servicemocks.MockRuntimeSettingsManager)
+		runtimeSettings.On("Set", "maxThreads", mock.Anything, admin.ID).
+			Return(services.RuntimeSettings{}, fmt.Errorf("%w: %q", services.ErrUnknownRuntimeSetting, "maxThreads"))
+
+		req := httptest.NewRequest(http.MethodPut, "/admin/runtime-settings/maxThreads", strings.NewReader(`{"value": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupRuntimeSettingsRouter(admin, runtimeSettings).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestRuntimeSettingsHandler_RefreshRuntimeSettings(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	runtimeSettings := new(servicemocks.MockRuntimeSettingsManager)
+	runtimeSettings.On("Refresh").Return(nil)
+	runtimeSettings.On("Current").Return(services.DefaultRuntimeSettings())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/runtime-settings/refresh", nil)
+	w := httptest.NewRecorder()
+	setupRuntimeSettingsRouter(admin, runtimeSettings).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	runtimeSettings.AssertExpectations(t)
+}
+
+func TestAudioDurationMessage(t *testing.T) {
+	tooLong := &services.AudioDurationError{Err: services.ErrAudioTooLong, Seconds: 75, Limit: 60}
+	tooShort := &services.AudioDurationError{Err: services.ErrAudioTooShort, Seconds: 0.4, Limit: 1}
+
+	assert.Equal(t, "Audio must be 60 seconds or less. Please record a shorter message.", audioDurationMessage(tooLong))
+	assert.Equal(t, "Audio must be at least 1 second long. Please record a longer message.", audioDurationMessage(tooShort))
+	assert.Equal(t, "Audio is too short. Please record a longer message.", audioDurationMessage(services.ErrAudioTooShort))
+}
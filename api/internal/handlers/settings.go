@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	SettingsService services.SettingsManager
+}
+
+func NewSettingsHandler(settingsService services.SettingsManager) *SettingsHandler {
+	return &SettingsHandler{
+		SettingsService: settingsService,
+	}
+}
+
+type UpdateSettingsRequest struct {
+	AudioRetentionDays *int     `json:"audioRetentionDays"` // 0 keeps recordings; otherwise 7, 30 or 90
+	ReplyLength        *string  `json:"replyLength"`        // "short", "medium" or "long"
+	SpeechRate         *float64 `json:"speechRate"`         // 0.5 to 1.5, where 1.0 is normal speed
+	EncryptContent     *bool    `json:"encryptContent"`     // Encrypt transcripts and analyses at rest
+	Timezone           *string  `json:"timezone"`           // IANA name, e.g. "Europe/Madrid"
+	EmailAchievements  *string  `json:"emailAchievements"`  // "instant", "daily" or "off"
+	EmailUpdates       *string  `json:"emailUpdates"`       // "instant", "daily" or "off"
+}
+
+// GetSettings returns the current user's account settings
+// GET /api/settings
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	settings, err := h.SettingsService.GetSettings(user.ID)
+	if err != nil {
+		handleError(c, err, "GetSettings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings changes the current user's account settings
+// PATCH /api/settings
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	var req UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	// Check every field before saving any, so a bad value changes nothing
+	if req.ReplyLength != nil && !services.ValidReplyLength(*req.ReplyLength) {
+		handleError(c, services.ErrInvalidReplyLength, "UpdateSettings")
+		return
+	}
+	if req.SpeechRate != nil && !services.ValidSpeechRate(*req.SpeechRate) {
+		handleError(c, services.ErrInvalidSpeechRate, "UpdateSettings")
+		return
+	}
+	if req.Timezone != nil && !services.ValidTimezone(*req.Timezone) {
+		handleError(c, services.ErrInvalidTimezone, "UpdateSettings")
+		return
+	}
+	for _, frequency := range []*string{req.EmailAchievements, req.EmailUpdates} {
+		if frequency != nil && !services.ValidEmailFrequency(*frequency) {
+			handleError(c, services.ErrInvalidEmailFrequency, "UpdateSettings")
+			return
+		}
+	}
+
+	var settings *models.UserSettings
+	var err error
+	if req.AudioRetentionDays != nil {
+		if settings, err = h.SettingsService.SetAudioRetention(user.ID, *req.AudioRetentionDays); err != nil {
+			handleError(c, err, "UpdateSettings")
+			return
+		}
+	}
+	if req.ReplyLength != nil {
+		if settings, err = h.SettingsService.SetReplyLength(user.ID, *req.ReplyLength); err != nil {
+			handleError(c, err, "UpdateSettings")
+			return
+		}
+	}
+	if req.SpeechRate != nil {
+		if settings, err = h.SettingsService.SetSpeechRate(user.ID, *req.SpeechRate); err != nil {
+			handleError(c, err, "UpdateSettings")
+			return
+		}
+	}
+	if req.EncryptContent != nil {
+		if settings, err = h.SettingsService.SetEncryptContent(user.ID, *req.EncryptContent); err != nil {
+			handleError(c, err, "UpdateSettings")
+			return
+		}
+	}
+	if req.Timezone != nil {
+		if settings, err = h.SettingsService.SetTimezone(user.ID, *req.Timezone); err != nil {
+			handleError(c, err, "UpdateSettings")
+			return
+		}
+	}
+	if req.EmailAchievements != nil {
+		if settings, err = h.SettingsService.SetEmailFrequency(user.ID, models.EmailCategoryAchievements, *req.EmailAchievements); err != nil {
+			handleError(c, err, "UpdateSettings")
+			return
+		}
+	}
+	if req.EmailUpdates != nil {
+		if settings, err = h.SettingsService.SetEmailFrequency(user.ID, models.EmailCategoryUpdates, *req.EmailUpdates); err != nil {
+			handleError(c, err, "UpdateSettings")
+			return
+		}
+	}
+
+	if settings == nil {
+		h.GetSettings(c)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSettingsRouter(user *models.User, settingsService services.SettingsManager) *gin.Engine {
+	handler := NewSettingsHandler(settingsService)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/settings", handler.GetSettings)
+	router.PATCH("/settings", handler.UpdateSettings)
+	return router
+}
+
+func TestSettingsHandler_UpdateSettings(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	settingsService := new(servicemocks.MockSettingsManager)
+	settingsService.On("SetAudioRetention", user.ID, 30).
+		Return(&models.UserSettings{UserID: user.ID, AudioRetentionDays: 30}, nil)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"audioRetentionDays": 30}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(30), body["audioRetentionDays"])
+	settingsService.AssertExpectations(t)
+}
+
+func TestSettingsHandler_UpdateSettings_InvalidRetention(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	settingsService := new(servicemocks.MockSettingsManager)
+	settingsService.On("SetAudioRetention", user.ID, 14).Return(nil, services.ErrInvalidAudioRetention)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"audioRetentionDays": 14}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSettingsHandler_UpdateSettings_ReplyStyle(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	settingsService := new(servicemocks.MockSettingsManager)
+	settingsService.On("SetReplyLength", user.ID, "short").
+		Return(&models.UserSettings{UserID: user.ID, ReplyLength: "short", SpeechRate: 1.0}, nil)
+	settingsService.On("SetSpeechRate", user.ID, 0.8).
+		Return(&models.UserSettings{UserID: user.ID, ReplyLength: "short", SpeechRate: 0.8}, nil)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"replyLength": "short", "speechRate": 0.8}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "short", body["replyLength"])
+	assert.Equal(t, 0.8, body["speechRate"])
+	settingsService.AssertExpectations(t)
+}
+
+func TestSettingsHandler_UpdateSettings_InvalidValueSavesNothing(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	settingsService := new(servicemocks.MockSettingsManager)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"audioRetentionDays": 30, "speechRate": 3}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	settingsService.AssertNotCalled(t, "SetAudioRetention", user.ID, 30)
+}
+
+func TestSettingsHandler_UpdateSettings_InvalidTimezone(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	settingsService := new(servicemocks.MockSettingsManager)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"replyLength": "short", "timezone": "Mars/Olympus_Mons"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "IANA timezone")
+	settingsService.AssertNotCalled(t, "SetReplyLength", user.ID, "short")
+}
+
+func TestSettingsHandler_UpdateSettings_EmailFrequency(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	updated := models.DefaultUserSettings(user.ID)
+	updated.EmailAchievements = models.EmailFrequencyOff
+
+	settingsService := new(servicemocks.MockSettingsManager)
+	settingsService.On("SetEmailFrequency", user.ID, models.EmailCategoryAchievements, models.EmailFrequencyOff).Return(updated, nil)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"emailAchievements": "off"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "off", body["emailAchievements"])
+	settingsService.AssertExpectations(t)
+}
+
+func TestSettingsHandler_UpdateSettings_InvalidEmailFrequency(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	settingsService := new(servicemocks.MockSettingsManager)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"replyLength": "short", "emailUpdates": "hourly"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "instant, daily or off")
+	settingsService.AssertNotCalled(t, "SetReplyLength", user.ID, "short")
+}
+
+func TestSettingsHandler_UpdateSettings_EncryptContentUnavailable(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	settingsService := new(servicemocks.MockSettingsManager)
+	settingsService.On("SetEncryptContent", user.ID, true).Return(nil, services.ErrContentEncryptionUnavailable)
+
+	req := httptest.NewRequest("PATCH", "/settings", strings.NewReader(`{"encryptContent": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupSettingsRouter(user, settingsService).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	settingsService.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StripeSyncHandler struct {
+	Sync services.StripeSyncer
+}
+
+func NewStripeSyncHandler(sync services.StripeSyncer) *StripeSyncHandler {
+	return &StripeSyncHandler{
+		Sync: sync,
+	}
+}
+
+type StripeSyncRequest struct {
+	Since  *time.Time `json:"since"`  // RFC 3339; defaults to 72 hours ago
+	All    bool       `json:"all"`    // check every Stripe customer instead of recent events
+	DryRun bool       `json:"dryRun"` // report without fixing
+}
+
+// SyncStripe reconciles subscriptions and credits with Stripe after missed
+// webhooks, reporting every discrepancy found
+// POST /api/admin/stripe/sync
+func (h *StripeSyncHandler) SyncStripe(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	var req StripeSyncRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			handleValidationError(c, err)
+			return
+		}
+	}
+
+	opts := services.StripeSyncOptions{All: req.All, DryRun: req.DryRun, Actor: &admin.ID}
+	if req.Since != nil {
+		opts.Since = *req.Since
+	}
+
+	report, err := h.Sync.Sync(c.Request.Context(), opts)
+	if err != nil {
+		handleError(c, err, "SyncStripe")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
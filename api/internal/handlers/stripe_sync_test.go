@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStripeSyncRouter(user *models.User, sync services.StripeSyncer) *gin.Engine {
+	handler := NewStripeSyncHandler(sync)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/admin/stripe/sync", handler.SyncStripe)
+	return router
+}
+
+func TestStripeSyncHandler_SyncStripe(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("defaults without a body", func(t *testing.T) {
+		sync := new(servicemocks.MockStripeSyncer)
+		sync.On("Sync", mock.Anything, mock.MatchedBy(func(opts services.StripeSyncOptions) bool {
+			return opts.Since.IsZero() && !opts.All && !opts.DryRun && *opts.Actor == admin.ID
+		})).Return(&services.StripeSyncReport{Checked: 2, Discrepancies: []services.StripeDiscrepancy{
+			{UserID: uuid.New(), CustomerID: "cus_1", Field: "tier", Local: "free", Stripe: "pro", Fixed: true},
+		}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/stripe/sync", nil)
+		w := httptest.NewRecorder()
+		setupStripeSyncRouter(admin, sync).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var report services.StripeSyncReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, 2, report.Checked)
+		require.Len(t, report.Discrepancies, 1)
+		assert.Equal(t, "pro", report.Discrepancies[0].Stripe)
+		sync.AssertExpectations(t)
+	})
+
+	t.Run("passes options through", func(t *testing.T) {
+		since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		sync := new(servicemocks.MockStripeSyncer)
+		sync.On("Sync", mock.Anything, mock.MatchedBy(func(opts services.StripeSyncOptions) bool {
+			return opts.Since.Equal(since) && opts.DryRun
+		})).Return(&services.StripeSyncReport{DryRun: true}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/stripe/sync", strings.NewReader(`{"since": "2026-10-01T00:00:00Z", "dryRun": true}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupStripeSyncRouter(admin, sync).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		sync.AssertExpectations(t)
+	})
+
+	t.Run("window too old", func(t *testing.T) {
+		sync := new(servicemocks.MockStripeSyncer)
+		sync.On("Sync", mock.Anything, mock.Anything).Return(nil, services.ErrInvalidStripeSyncWindow)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/stripe/sync", strings.NewReader(`{"since": "2025-01-01T00:00:00Z"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupStripeSyncRouter(admin, sync).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
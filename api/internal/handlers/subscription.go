@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -16,6 +17,8 @@ import (
 type SubscriptionHandler struct {
 	stripeService  services.StripeProcessor
 	creditsService services.CreditsManager
+
+	Pricing services.PricingProvider
 }
 
 func NewSubscriptionHandler(stripeService services.StripeProcessor, creditsService services.CreditsManager) *SubscriptionHandler {
@@ -25,7 +28,8 @@ func NewSubscriptionHandler(stripeService services.StripeProcessor, creditsServi
 	}
 }
 
-// GetSubscriptionStatus returns the user's subscription and credits
+// GetSubscriptionStatus returns the user's subscription and credits, and the
+// banner to show if their last payment failed
 // GET /api/subscription
 func (h *SubscriptionHandler) GetSubscriptionStatus(c *gin.Context) {
 	user := middleware.MustGetUser(c)
@@ -44,13 +48,42 @@ func (h *SubscriptionHandler) GetSubscriptionStatus(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"subscription": sub,
-		"credits":      credits,
+		"subscription":  sub,
+		"credits":       credits,
+		"paymentBanner": h.stripeService.PaymentBanner(sub),
 	})
 }
 
+// GetPricing returns the paid tiers' monthly prices from Stripe. ?currency=
+// picks one of the currencies the prices are sold in. Otherwise the
+// currency follows ?country=, which also estimates the tax there. The
+// country defaults to the user's billing country, then to the region of
+// their Accept-Language.
+// GET /api/subscription/pricing
+func (h *SubscriptionHandler) GetPricing(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	country := c.Query("country")
+	if country == "" {
+		if sub, err := h.stripeService.GetSubscription(user.ID); err == nil {
+			country = sub.BillingCountry
+		}
+	}
+	if country == "" {
+		country = services.CountryFromLocale(c.GetHeader("Accept-Language"))
+	}
+
+	pricing, err := h.Pricing.Pricing(c.Request.Context(), c.Query("currency"), country)
+	if err != nil {
+		handleError(c, err, "GetPricing")
+		return
+	}
+	c.JSON(http.StatusOK, pricing)
+}
+
 type CheckoutRequest struct {
-	Tier string `json:"tier" binding:"required,oneof=basic pro"`
+	Tier     string `json:"tier" binding:"required,oneof=basic pro"`
+	Currency string `json:"currency" binding:"omitempty,len=3"` // the currency the pricing showed
 }
 
 // CreateCheckoutSession creates a Stripe checkout session
@@ -60,11 +93,11 @@ func (h *SubscriptionHandler) CreateCheckoutSession(c *gin.Context) {
 
 	var req CheckoutRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "tier must be 'basic' or 'pro'"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tier must be 'basic' or 'pro', and currency a three-letter code"})
 		return
 	}
 
-	url, err := h.stripeService.CreateCheckoutSession(user.ID, user.Email, user.Name, models.SubscriptionTier(req.Tier))
+	url, err := h.stripeService.CreateCheckoutSession(user.ID, user.Email, user.Name, models.SubscriptionTier(req.Tier), req.Currency)
 	if err != nil {
 		log.Printf("CreateCheckoutSession error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create checkout session"})
@@ -108,16 +141,28 @@ func (h *SubscriptionHandler) GetCreditsBalance(c *gin.Context) {
 }
 
 // GetCreditHistory returns the user's credit transaction history
-// GET /api/credits/history
+// GET /api/credits/history?offset=0
 func (h *SubscriptionHandler) GetCreditHistory(c *gin.Context) {
 	user := middleware.MustGetUser(c)
 
-	transactions, err := h.creditsService.GetTransactionHistory(user.ID, 50)
+	const limit = 50
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		offset = parsed
+	}
+
+	transactions, err := h.creditsService.GetTransactionHistory(user.ID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transaction history"})
 		return
 	}
 
+	middleware.SetPagination(c, limit, offset, len(transactions))
 	c.JSON(http.StatusOK, gin.H{"transactions": transactions})
 }
 
@@ -6,7 +6,13 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,6 +36,60 @@ func TestSubscriptionHandler_HandleStripeWebhook(t *testing.T) {
 	})
 }
 
+func TestSubscriptionHandler_GetPricing(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	pricing := &services.Pricing{Currency: "eur", Country: "DE", TaxEnabled: true, Tiers: []services.TierPrice{}}
+
+	tests := []struct {
+		name       string
+		query      string
+		locale     string
+		sub        *models.Subscription
+		wantQuery  [2]string
+		err        error
+		wantStatus int
+	}{
+		{name: "billing country by default", query: "?currency=eur", sub: &models.Subscription{BillingCountry: "DE"}, wantQuery: [2]string{"eur", "DE"}, wantStatus: http.StatusOK},
+		{name: "country from the query", query: "?country=FR", wantQuery: [2]string{"", "FR"}, wantStatus: http.StatusOK},
+		{name: "no subscription yet", wantQuery: [2]string{"", ""}, wantStatus: http.StatusOK},
+		{name: "locale without a billing country", locale: "en-GB,en;q=0.9", sub: &models.Subscription{}, wantQuery: [2]string{"", "GB"}, wantStatus: http.StatusOK},
+		{name: "unsupported currency", query: "?currency=jpy&country=JP", wantQuery: [2]string{"jpy", "JP"}, err: services.ErrUnsupportedCurrency, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripeService := new(servicemocks.MockStripeProcessor)
+			if tt.sub != nil {
+				stripeService.On("GetSubscription", user.ID).Return(tt.sub, nil)
+			} else {
+				stripeService.On("GetSubscription", user.ID).Return(nil, services.ErrSubscriptionNotFound)
+			}
+			pricingService := new(servicemocks.MockPricingProvider)
+			if tt.err != nil {
+				pricingService.On("Pricing", tt.wantQuery[0], tt.wantQuery[1]).Return(nil, tt.err)
+			} else {
+				pricingService.On("Pricing", tt.wantQuery[0], tt.wantQuery[1]).Return(pricing, nil)
+			}
+
+			handler := NewSubscriptionHandler(stripeService, nil)
+			handler.Pricing = pricingService
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, user)
+				c.Next()
+			})
+			router.GET("/subscription/pricing", handler.GetPricing)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/subscription/pricing"+tt.query, nil)
+			req.Header.Set("Accept-Language", tt.locale)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			pricingService.AssertExpectations(t)
+		})
+	}
+}
+
 // Note: Full subscription handler tests require either:
 // 1. Making StripeService and CreditsService into interfaces
 // 2. Using integration tests with a test database
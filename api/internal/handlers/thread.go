@@ -4,8 +4,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"ling-app/api/internal/analytics"
+	"ling-app/api/internal/apierror"
 	"ling-app/api/internal/client"
 	"ling-app/api/internal/middleware"
 	"ling-app/api/internal/models"
@@ -20,39 +23,81 @@ type ThreadHandler struct {
 	exec                repository.Executor
 	threadRepo          repository.ThreadRepository
 	messageRepo         repository.MessageRepository
+	readStateRepo       repository.ThreadReadStateRepository
 	conversationService services.ConversationProcessor
 	OpenAIClient        client.OpenAIClient
 	CreditsService      *services.CreditsService
+	GoalService         *services.GoalService
+	Usage               services.UsageLimiter
+	Analytics           analytics.Tracker
+	Titles              services.ThreadTitler
+	Memory              services.LearnerMemory
+	LongForm            services.LongFormProcessor
+	Corrections         services.TranscriptCorrector
+	Retries             services.PronunciationRetrier
+	FeatureUsage        services.FeatureUsageRecorder
+	Guests              services.GuestLimiter
+	Continuations       services.ThreadContinuer
 }
 
 func NewThreadHandler(
 	exec repository.Executor,
 	threadRepo repository.ThreadRepository,
 	messageRepo repository.MessageRepository,
+	readStateRepo repository.ThreadReadStateRepository,
 	conversationService services.ConversationProcessor,
 	openAIClient client.OpenAIClient,
 	creditsService *services.CreditsService,
+	goalService *services.GoalService,
+	usage services.UsageLimiter,
+	tracker analytics.Tracker,
+	titles services.ThreadTitler,
 ) *ThreadHandler {
 	return &ThreadHandler{
 		exec:                exec,
 		threadRepo:          threadRepo,
 		messageRepo:         messageRepo,
+		readStateRepo:       readStateRepo,
 		conversationService: conversationService,
 		OpenAIClient:        openAIClient,
 		CreditsService:      creditsService,
+		GoalService:         goalService,
+		Usage:               usage,
+		Analytics:           tracker,
+		Titles:              titles,
 	}
 }
 
 type CreateThreadRequest struct {
 	InitialPrompt    string `json:"initialPrompt"`
 	FirstUserMessage string `json:"firstUserMessage"`
+	Goal             string `json:"goal"`
+	SuggestReplies   bool   `json:"suggestReplies"`
+	SpeechOnly       bool   `json:"speechOnly"`
+	Language         string `json:"language"` // e.g. "es"; empty practises English
+	Locale           string `json:"locale"`   // e.g. "es-MX"; empty uses the language's default
 }
 
-// GetThreads retrieves all non-archived threads for the current user, ordered by most recent
+// IncludeAnalysis is the ?include= value that adds pronunciation analyses to GetThread
+const IncludeAnalysis = "analysis"
+
+// includes reports whether the comma-separated include query parameter
+// names field, e.g. ?include=analysis
+func includes(c *gin.Context, field string) bool {
+	for _, f := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(f) == field {
+			return true
+		}
+	}
+	return false
+}
+
+// GetThreads retrieves all non-archived threads for the current user with
+// their last message preview and message count, most recently active first
 func (h *ThreadHandler) GetThreads(c *gin.Context) {
 	user := middleware.MustGetUser(c)
 
-	threads, err := h.threadRepo.FindByUserID(h.exec, user.ID)
+	threads, err := h.threadRepo.FindSummariesByUserID(h.exec, user.ID)
 	if err != nil {
 		handleError(c, err, "GetThreads")
 		return
@@ -83,10 +128,45 @@ func (h *ThreadHandler) CreateThread(c *gin.Context) {
 		return
 	}
 
+	goal := strings.TrimSpace(req.Goal)
+	if len(goal) > services.MaxGoalLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Goal is too long"})
+		return
+	}
+
+	if req.Locale != "" && !services.ValidLocale(req.Locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale"})
+		return
+	}
+	if err := checkThreadLanguage(req.Language, req.Locale); err != nil {
+		handleError(c, err, "CreateThread")
+		return
+	}
+
+	if h.Usage != nil {
+		if err := h.Usage.CheckThreadLimit(user.ID); err != nil {
+			handleError(c, err, "CreateThread")
+			return
+		}
+	}
+	if h.Guests != nil {
+		if err := h.Guests.CheckThreadLimit(user); err != nil {
+			handleError(c, err, "CreateThread")
+			return
+		}
+	}
+
 	thread := models.Thread{
-		ID:        uuid.New(),
-		UserID:    user.ID, // Associate thread with user
-		CreatedAt: time.Now(),
+		ID:             uuid.New(),
+		UserID:         user.ID, // Associate thread with user
+		SuggestReplies: req.SuggestReplies,
+		SpeechOnly:     req.SpeechOnly,
+		Language:       req.Language,
+		Locale:         req.Locale,
+		CreatedAt:      time.Now(),
+	}
+	if goal != "" {
+		thread.Goal = &goal
 	}
 
 	// Create thread
@@ -96,6 +176,13 @@ func (h *ThreadHandler) CreateThread(c *gin.Context) {
 		return
 	}
 
+	// The creator has seen the thread, so later replies count as unread
+	if h.readStateRepo != nil {
+		if err := h.readStateRepo.MarkRead(h.exec, user.ID, thread.ID, thread.CreatedAt); err != nil {
+			log.Printf("Error marking new thread %s read: %v", thread.ID, err)
+		}
+	}
+
 	// Add initial AI prompt message only if provided
 	if req.InitialPrompt != "" {
 		aiMessage := models.Message{
@@ -131,6 +218,17 @@ func (h *ThreadHandler) CreateThread(c *gin.Context) {
 
 		// Generate AI response
 		conversationHistory := []client.ConversationMessage{}
+		if prompt := services.LanguageSystemPrompt(services.ThreadLanguage(&thread)); prompt != nil {
+			conversationHistory = append(conversationHistory, *prompt)
+		}
+		if h.Memory != nil {
+			if memory := h.Memory.SystemPrompt(user.ID); memory != nil {
+				conversationHistory = append(conversationHistory, *memory)
+			}
+		}
+		if thread.Goal != nil {
+			conversationHistory = append(conversationHistory, services.GoalSystemPrompt(*thread.Goal))
+		}
 		if req.InitialPrompt != "" {
 			conversationHistory = append(conversationHistory, client.ConversationMessage{
 				Role:    "assistant",
@@ -163,8 +261,7 @@ func (h *ThreadHandler) CreateThread(c *gin.Context) {
 			return
 		}
 
-		// Auto-generate thread name from AI response (async)
-		go h.generateThreadName(thread.ID, aiResponse)
+		h.requestTitle(&thread, req.FirstUserMessage, aiResponse)
 	}
 
 	// Load thread with messages
@@ -189,7 +286,9 @@ func (h *ThreadHandler) GetThread(c *gin.Context) {
 		return
 	}
 
-	thread, err := h.threadRepo.FindByIDAndUserIDWithMessages(h.exec, parsedID, user.ID)
+	// Analyses are left out unless asked for; clients fetch them per message
+	withAnalysis := includes(c, IncludeAnalysis)
+	thread, err := h.threadRepo.FindByIDAndUserIDWithMessages(h.exec, parsedID, user.ID, withAnalysis)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
@@ -202,20 +301,71 @@ func (h *ThreadHandler) GetThread(c *gin.Context) {
 	c.JSON(http.StatusOK, thread)
 }
 
-// SendAudioMessage handles audio message upload, transcription, AI response, and TTS
-// POST /api/threads/:id/messages/audio
-func (h *ThreadHandler) SendAudioMessage(c *gin.Context) {
+// GetMessageAnalysis returns one message's pronunciation analysis
+// GET /api/threads/:id/messages/:messageId/analysis
+func (h *ThreadHandler) GetMessageAnalysis(c *gin.Context) {
 	user := middleware.MustGetUser(c)
-	threadID := c.Param("id")
-	parsedID, err := uuid.Parse(threadID)
+
+	threadID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
 		return
 	}
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
 
-	// Check if thread exists and belongs to current user
-	thread, err := h.threadRepo.FindByIDAndUserID(h.exec, parsedID, user.ID)
+	if _, err := h.threadRepo.FindByIDAndUserID(h.exec, threadID, user.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch thread"})
+		return
+	}
+
+	message, err := h.messageRepo.FindByID(h.exec, messageID)
+	if err != nil || message.ThreadID != threadID {
+		if err == nil || errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch message"})
+		return
+	}
+
+	if message.PronunciationAnalysis == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message has no pronunciation analysis"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messageId":             message.ID,
+		"pronunciationStatus":   message.PronunciationStatus,
+		"pronunciationAnalysis": message.PronunciationAnalysis,
+	})
+}
+
+// RevealMessageText returns the text of one message, for speech-only threads
+// whose voice turns come back without it
+// GET /api/threads/:id/messages/:messageId/text
+func (h *ThreadHandler) RevealMessageText(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+	messageID, err := uuid.Parse(c.Param("messageId"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	if _, err := h.threadRepo.FindByIDAndUserID(h.exec, threadID, user.ID); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
 			return
@@ -224,6 +374,36 @@ func (h *ThreadHandler) SendAudioMessage(c *gin.Context) {
 		return
 	}
 
+	message, err := h.messageRepo.FindByID(h.exec, messageID)
+	if err != nil || message.ThreadID != threadID {
+		if err == nil || errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch message"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messageId":          message.ID,
+		"content":            message.Content,
+		"rawTranscript":      message.RawTranscript,
+		"spokenText":         message.SpokenText,
+		"suggestedReplies":   message.SuggestedReplies,
+		"corrections":        message.Corrections,
+		"correctedMessageId": message.CorrectedMessageID,
+	})
+}
+
+// SendAudioMessage handles audio message upload, transcription, AI response, and TTS
+// POST /api/threads/:id/messages/audio
+func (h *ThreadHandler) SendAudioMessage(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+	thread, ok := h.turnThread(c, user.ID, "SendAudioMessage")
+	if !ok {
+		return
+	}
+
 	// Get audio file from multipart form
 	file, fileHeader, err := c.Request.FormFile("audio")
 	if err != nil {
@@ -232,67 +412,267 @@ func (h *ThreadHandler) SendAudioMessage(c *gin.Context) {
 	}
 	defer file.Close()
 
+	// Optional practice line the user was reading; scored against instead of the transcript
+	expectedText := c.PostForm("expectedText")
+
 	// Process audio message via ConversationService
-	turn, err := h.conversationService.ProcessAudioMessage(c.Request.Context(), parsedID, file, fileHeader)
+	start := time.Now()
+	turn, err := h.conversationService.ProcessAudioMessage(c.Request.Context(), thread.ID, file, fileHeader, expectedText)
+	recordFeatureUsage(h.FeatureUsage, user.ID, models.FeatureVoiceMessage, turnCredits(turn), start, err)
 	if err != nil {
 		handleError(c, err, "ProcessAudioMessage")
 		return
 	}
 
-	// Auto-generate thread name from AI response (async)
-	go h.generateThreadName(thread.ID, turn.AssistantMessage.Content)
+	h.afterTurn(c, user.ID, thread, turn)
+	c.JSON(http.StatusOK, turnResponse(thread, turn))
+}
+
+// SendTextMessageRequest is the body of a typed message
+type SendTextMessageRequest struct {
+	Content string `json:"content" binding:"required"`
+	Speak   bool   `json:"speak"` // Also speak the reply; always on in speech-only threads
+}
+
+// SendTextMessage sends a typed message, for when the user can't speak, and
+// returns the turn with the same body as SendAudioMessage. The reply has no
+// audio unless speak is set.
+// POST /api/threads/:id/messages
+func (h *ThreadHandler) SendTextMessage(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+	thread, ok := h.turnThread(c, user.ID, "SendTextMessage")
+	if !ok {
+		return
+	}
+
+	var req SendTextMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message content is required"})
+		return
+	}
+
+	start := time.Now()
+	turn, err := h.conversationService.SendTextMessage(c.Request.Context(), thread.ID, req.Content, req.Speak)
+	recordFeatureUsage(h.FeatureUsage, user.ID, models.FeatureTextMessage, turnCredits(turn), start, err)
+	if err != nil {
+		handleError(c, err, "SendTextMessage")
+		return
+	}
+
+	h.afterTurn(c, user.ID, thread, turn)
+	c.JSON(http.StatusOK, turnResponse(thread, turn))
+}
+
+// StreamAudioMessage is SendAudioMessage answered as server-sent events, so
+// the client can show the turn as it happens: a "transcribed" event with the
+// user message, "token" events as the reply is written, "retracted" if that
+// draft fails the safety check, a "reply" event with the final text, and a
+// "turn" event with the same body as SendAudioMessage once the reply has
+// been spoken. Speech-only threads get no text in any of them.
+//
+// Failures before the first event are ordinary JSON errors; after it they
+// end the stream with an "error" event.
+// POST /api/threads/:id/messages/audio/stream
+func (h *ThreadHandler) StreamAudioMessage(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+	thread, ok := h.turnThread(c, user.ID, "StreamAudioMessage")
+	if !ok {
+		return
+	}
+
+	file, fileHeader, err := c.Request.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
+		return
+	}
+	defer file.Close()
 
-	// Deduct credits for voice message
-	if h.CreditsService != nil {
-		cost := middleware.GetCreditsCost(c)
-		if cost > 0 {
-			if err := h.CreditsService.DeductCredits(user.ID, cost, turn.AssistantMessage.ID.String(), "Voice message"); err != nil {
-				// Credit deduction failed - this is a billing issue that needs attention
-				// The message was already processed, so we return it but log the error prominently
-				log.Printf("CRITICAL: Failed to deduct credits for user %s, message %s: %v", user.ID, turn.AssistantMessage.ID, err)
-				// Still return success since the message was processed - but this needs monitoring
+	expectedText := c.PostForm("expectedText")
+
+	streaming := false
+	progress := func(event services.TurnEvent) {
+		if thread.SpeechOnly {
+			withheld, keep := event.WithoutText()
+			if !keep {
+				return
 			}
+			event = withheld
+		}
+		if !streaming {
+			c.Header("Cache-Control", "no-store")
+			c.Header("X-Accel-Buffering", "no")
+			streaming = true
 		}
+		c.SSEvent(event.Type, event)
+		c.Writer.Flush()
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	start := time.Now()
+	turn, err := h.conversationService.StreamAudioMessage(c.Request.Context(), thread.ID, file, fileHeader, expectedText, progress)
+	recordFeatureUsage(h.FeatureUsage, user.ID, models.FeatureVoiceMessage, turnCredits(turn), start, err)
+	if err != nil && !streaming {
+		handleError(c, err, "StreamAudioMessage")
+		return
+	}
+	if err != nil {
+		log.Printf("[StreamAudioMessage] Error: %v", err)
+		// The status is already 200; this tells Idempotent the turn failed
+		c.Error(err)
+		c.SSEvent("error", apierror.FromVoiceTurnError(err))
+		return
+	}
+
+	h.afterTurn(c, user.ID, thread, turn)
+	c.SSEvent("turn", turnResponse(thread, turn))
+}
+
+// turnThread loads the user's thread for a turn, spoken or typed, and
+// checks it can take one. It writes the error response and returns false if
+// not.
+func (h *ThreadHandler) turnThread(c *gin.Context, userID uuid.UUID, operation string) (*models.Thread, bool) {
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return nil, false
+	}
+
+	// Check if thread exists and belongs to current user
+	thread, err := h.threadRepo.FindByIDAndUserID(h.exec, parsedID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Thread not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch thread"})
+		return nil, false
+	}
+	if thread.EndedAt != nil {
+		handleError(c, services.ErrThreadEnded, operation)
+		return nil, false
+	}
+
+	if h.Usage != nil {
+		if err := h.Usage.CheckMessageLimit(userID); err != nil {
+			handleError(c, err, operation)
+			return nil, false
+		}
+	}
+	return thread, true
+}
+
+// afterTurn starts the background work that follows an answered turn:
+// naming the thread, learner memory, analytics and the goal check
+func (h *ThreadHandler) afterTurn(c *gin.Context, userID uuid.UUID, thread *models.Thread, turn *services.ConversationTurn) {
+	h.requestTitle(thread, turn.UserMessage.Content, turn.AssistantMessage.Content)
+
+	// Pick up new facts about the learner (async)
+	if h.Memory != nil {
+		h.Memory.RequestExtraction(userID, thread.ID)
+	}
+
+	h.trackFirstMessage(c, userID, thread.ID)
+
+	// Check whether this turn accomplished the thread's goal (async)
+	if h.GoalService != nil && thread.Goal != nil && thread.GoalCompletedAt == nil {
+		go h.checkGoal(thread.ID)
+	}
+}
+
+// turnResponse shapes a turn for the client, without the text in
+// speech-only threads. A reply that couldn't be spoken carries a warning.
+func turnResponse(thread *models.Thread, turn *services.ConversationTurn) gin.H {
+	if thread.SpeechOnly {
+		turn = turn.WithoutText()
+	}
+	response := gin.H{
 		"userMessage":      turn.UserMessage,
 		"assistantMessage": turn.AssistantMessage,
-	})
+		"threadEnded":      turn.ThreadEnded,
+		"textWithheld":     turn.TextWithheld,
+	}
+	if turn.AssistantMessage != nil && !turn.AssistantMessage.HasAudio && !turn.Silent {
+		response["warning"] = apierror.SpeechFailed()
+	}
+	return response
+}
+
+// turnCredits returns what a turn was charged, or 0 if it failed
+func turnCredits(turn *services.ConversationTurn) int {
+	if turn == nil {
+		return 0
+	}
+	return turn.Credits
 }
 
-// generateThreadName generates a thread name from AI response content (runs async)
-func (h *ThreadHandler) generateThreadName(threadID uuid.UUID, aiResponse string) {
-	// Check if thread already has a name
-	thread, err := h.threadRepo.FindByID(h.exec, threadID)
+// trackFirstMessage records the user's first message across all threads
+func (h *ThreadHandler) trackFirstMessage(c *gin.Context, userID, threadID uuid.UUID) {
+	if h.Analytics == nil {
+		return
+	}
+
+	count, err := h.messageRepo.CountUserMessagesByUserID(h.exec, userID)
 	if err != nil {
-		log.Printf("Error fetching thread for naming: %v", err)
+		log.Printf("Failed to count messages for analytics: %v", err)
 		return
 	}
+	if count == 1 {
+		h.Analytics.Track(c.Request.Context(), userID, analytics.EventFirstMessage, map[string]any{
+			"threadId": threadID.String(),
+		})
+	}
+}
 
-	if thread.Name != nil {
-		return // Already named
+// requestTitle names an untitled thread in the background
+func (h *ThreadHandler) requestTitle(thread *models.Thread, userText, assistantText string) {
+	if h.Titles == nil || thread.Name != nil {
+		return
 	}
+	h.Titles.RequestTitle(thread.ID, userText, assistantText)
+}
 
-	// Generate title from AI response
-	title, err := h.OpenAIClient.GenerateTitle(aiResponse)
+// checkGoal runs the post-turn goal completion check (runs async)
+func (h *ThreadHandler) checkGoal(threadID uuid.UUID) {
+	completed, err := h.GoalService.CheckCompletion(threadID)
 	if err != nil {
-		log.Printf("Error generating thread title: %v", err)
+		log.Printf("Error checking thread goal: %v", err)
 		return
 	}
+	if completed {
+		log.Printf("Thread %s goal completed", threadID)
+	}
+}
 
-	// Update thread name
-	if err := h.threadRepo.UpdateName(h.exec, threadID, title); err != nil {
-		log.Printf("Error updating thread name: %v", err)
+// checkThreadLanguage validates a thread's language and that its locale, if
+// any, is a variant of it. Threads without a language (English) predate the
+// setting and may have any locale.
+func checkThreadLanguage(language, locale string) error {
+	if language == "" {
+		return nil
+	}
+	if !services.ValidLanguage(language) {
+		return services.ErrInvalidLanguage
 	}
+	if locale != "" && !services.LocaleMatchesLanguage(locale, language) {
+		return services.ErrLocaleLanguageMismatch
+	}
+	return nil
 }
 
 // UpdateThreadRequest represents the request body for updating a thread
 type UpdateThreadRequest struct {
-	Name *string `json:"name"`
+	Name           *string  `json:"name"`
+	Goal           *string  `json:"goal"` // Empty string clears the goal
+	SuggestReplies *bool    `json:"suggestReplies"`
+	SpeechOnly     *bool    `json:"speechOnly"`
+	Language       *string  `json:"language"` // Only while the thread has no messages
+	Locale         *string  `json:"locale"`
+	ReplyLength    *string  `json:"replyLength"` // Empty string uses the user's setting
+	SpeechRate     *float64 `json:"speechRate"`  // 0 uses the user's setting
 }
 
-// UpdateThread updates a thread's properties (rename)
+// UpdateThread updates a thread's properties (rename, set goal, toggle reply
+// suggestions and speech-only mode, set language and locale, override reply
+// length and speaking rate)
 func (h *ThreadHandler) UpdateThread(c *gin.Context) {
 	user := middleware.MustGetUser(c)
 	threadID := c.Param("id")
@@ -323,6 +703,86 @@ func (h *ThreadHandler) UpdateThread(c *gin.Context) {
 		thread.Name = req.Name
 	}
 
+	if req.SuggestReplies != nil {
+		thread.SuggestReplies = *req.SuggestReplies
+	}
+
+	if req.SpeechOnly != nil {
+		thread.SpeechOnly = *req.SpeechOnly
+	}
+
+	if req.Language != nil && *req.Language != thread.Language {
+		// Scores and stats are kept per language, so a conversation stays in one
+		messages, err := h.messageRepo.FindByThreadID(h.exec, thread.ID)
+		if err != nil {
+			handleError(c, err, "UpdateThread")
+			return
+		}
+		if len(messages) > 0 {
+			handleError(c, services.ErrThreadLanguageLocked, "UpdateThread")
+			return
+		}
+		thread.Language = *req.Language
+		// The old language's locale goes with it unless a new one is given
+		if req.Locale == nil && thread.Language != "" && !services.LocaleMatchesLanguage(thread.Locale, thread.Language) {
+			thread.Locale = ""
+		}
+	}
+
+	if req.Locale != nil {
+		if *req.Locale != "" && !services.ValidLocale(*req.Locale) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale"})
+			return
+		}
+		thread.Locale = *req.Locale
+	}
+
+	if err := checkThreadLanguage(thread.Language, thread.Locale); err != nil {
+		handleError(c, err, "UpdateThread")
+		return
+	}
+
+	if req.ReplyLength != nil {
+		switch {
+		case *req.ReplyLength == "":
+			thread.ReplyLength = nil
+		case services.ValidReplyLength(*req.ReplyLength):
+			thread.ReplyLength = req.ReplyLength
+		default:
+			handleError(c, services.ErrInvalidReplyLength, "UpdateThread")
+			return
+		}
+	}
+
+	if req.SpeechRate != nil {
+		switch {
+		case *req.SpeechRate == 0:
+			thread.SpeechRate = nil
+		case services.ValidSpeechRate(*req.SpeechRate):
+			thread.SpeechRate = req.SpeechRate
+		default:
+			handleError(c, services.ErrInvalidSpeechRate, "UpdateThread")
+			return
+		}
+	}
+
+	if req.Goal != nil {
+		goal := strings.TrimSpace(*req.Goal)
+		if len(goal) > services.MaxGoalLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Goal is too long"})
+			return
+		}
+		// A new goal starts over; clearing it removes completion too
+		if thread.Goal == nil || *thread.Goal != goal {
+			thread.GoalCompletedAt = nil
+		}
+		if goal == "" {
+			thread.Goal = nil
+		} else {
+			thread.Goal = &goal
+		}
+	}
+
 	if err := h.threadRepo.Save(h.exec, thread); err != nil {
 		log.Printf("Error updating thread: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update thread"})
@@ -363,6 +823,53 @@ func (h *ThreadHandler) DeleteThread(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Thread deleted"})
 }
 
+// MarkThreadReadRequest is the body of POST /threads/:id/read
+type MarkThreadReadRequest struct {
+	// Newest message the client has shown; defaults to now. Later times are
+	// clamped to now so a device with a fast clock can't hide future replies.
+	ReadAt *time.Time `json:"readAt"`
+}
+
+// MarkThreadRead records that the user has read a thread, for every device
+func (h *ThreadHandler) MarkThreadRead(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+
+	var req MarkThreadReadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			handleValidationError(c, err)
+			return
+		}
+	}
+
+	if _, err := h.threadRepo.FindByIDAndUserID(h.exec, parsedID, user.ID); err != nil {
+		handleError(c, err, "MarkThreadRead")
+		return
+	}
+
+	readAt := time.Now()
+	if req.ReadAt != nil && req.ReadAt.Before(readAt) {
+		readAt = *req.ReadAt
+	}
+	if err := h.readStateRepo.MarkRead(h.exec, user.ID, parsedID, readAt); err != nil {
+		handleError(c, err, "MarkThreadRead")
+		return
+	}
+
+	state, err := h.readStateRepo.Find(h.exec, user.ID, parsedID)
+	if err != nil {
+		handleError(c, err, "MarkThreadRead")
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
 // ArchiveThread sets the ArchivedAt timestamp on a thread
 func (h *ThreadHandler) ArchiveThread(c *gin.Context) {
 	user := middleware.MustGetUser(c)
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ContinueThread starts a new thread that carries on from an ended one,
+// opening with a recap of it
+// POST /api/threads/:id/continue
+func (h *ThreadHandler) ContinueThread(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+
+	if h.Continuations == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Continuing threads is not available"})
+		return
+	}
+
+	if h.Usage != nil {
+		if err := h.Usage.CheckThreadLimit(user.ID); err != nil {
+			handleError(c, err, "ContinueThread")
+			return
+		}
+	}
+	if h.Guests != nil {
+		if err := h.Guests.CheckThreadLimit(user); err != nil {
+			handleError(c, err, "ContinueThread")
+			return
+		}
+	}
+
+	thread, err := h.Continuations.Continue(user.ID, threadID)
+	if err != nil {
+		handleError(c, err, "ContinueThread")
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
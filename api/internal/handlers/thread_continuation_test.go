@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadHandler_ContinueThread(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	threadID := uuid.New()
+	path := "/threads/" + threadID.String() + "/continue"
+
+	newRouter := func(handler *ThreadHandler) *gin.Engine {
+		router := setupTestRouter()
+		router.Use(func(c *gin.Context) {
+			c.Set(middleware.UserContextKey, user)
+			c.Next()
+		})
+		router.POST("/threads/:id/continue", handler.ContinueThread)
+		return router
+	}
+
+	t.Run("returns the continuation thread", func(t *testing.T) {
+		usage := new(servicemocks.MockUsageLimiter)
+		usage.On("CheckThreadLimit", user.ID).Return(nil)
+		continuations := new(servicemocks.MockThreadContinuer)
+		continuations.On("Continue", user.ID, threadID).
+			Return(&models.Thread{ID: uuid.New(), ContinuedFromID: &threadID, Messages: []models.Message{{Role: "assistant", Content: "Last time..."}}}, nil)
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, usage, nil, nil)
+		handler.Continuations = continuations
+
+		w := httptest.NewRecorder()
+		newRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, threadID.String(), body["continuedFromId"])
+	})
+
+	t.Run("respects the thread limit", func(t *testing.T) {
+		usage := new(servicemocks.MockUsageLimiter)
+		usage.On("CheckThreadLimit", user.ID).Return(&services.TierLimitError{Resource: services.LimitThreads, Tier: models.TierFree, Limit: 20, Count: 20})
+		continuations := new(servicemocks.MockThreadContinuer)
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, usage, nil, nil)
+		handler.Continuations = continuations
+
+		w := httptest.NewRecorder()
+		newRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		continuations.AssertNotCalled(t, "Continue", mock.Anything, mock.Anything)
+	})
+
+	t.Run("thread still running", func(t *testing.T) {
+		continuations := new(servicemocks.MockThreadContinuer)
+		continuations.On("Continue", user.ID, threadID).Return(nil, services.ErrThreadNotEnded)
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.Continuations = continuations
+
+		w := httptest.NewRecorder()
+		newRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
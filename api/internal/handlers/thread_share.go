@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// shareStreamHeartbeat is how often an idle share stream sends a comment to
+// keep proxies from closing it, and checks that the share wasn't revoked
+const shareStreamHeartbeat = 25 * time.Second
+
+type ThreadShareHandler struct {
+	ShareService services.ThreadSharer
+}
+
+func NewThreadShareHandler(shareService services.ThreadSharer) *ThreadShareHandler {
+	return &ThreadShareHandler{
+		ShareService: shareService,
+	}
+}
+
+// CreateShare issues a one-hour read-only link to one of the user's threads
+// POST /api/threads/:id/shares
+func (h *ThreadShareHandler) CreateShare(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+
+	share, err := h.ShareService.Create(user.ID, threadID)
+	if err != nil {
+		handleError(c, err, "CreateShare")
+		return
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+// GetShares lists the thread's links that still work
+// GET /api/threads/:id/shares
+func (h *ThreadShareHandler) GetShares(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+
+	shares, err := h.ShareService.List(user.ID, threadID)
+	if err != nil {
+		handleError(c, err, "GetShares")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shares": shares})
+}
+
+// RevokeShare stops a link from working and disconnects its viewers
+// DELETE /api/threads/:id/shares/:shareId
+func (h *ThreadShareHandler) RevokeShare(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+	shareID, err := uuid.Parse(c.Param("shareId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share ID"})
+		return
+	}
+
+	if err := h.ShareService.Revoke(user.ID, threadID, shareID); err != nil {
+		handleError(c, err, "RevokeShare")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share revoked"})
+}
+
+// StreamShare streams a shared thread as server-sent events: a "thread"
+// event with the thread so far, then "message" and "analysis" events as
+// they happen, and a final "closed" event with the reason when the link is
+// revoked or expires. No authentication; the token is the capability.
+// GET /api/public/shares/:token
+func (h *ThreadShareHandler) StreamShare(c *gin.Context) {
+	shared, watch, err := h.ShareService.Watch(c.Param("token"))
+	if err != nil {
+		handleError(c, err, "StreamShare")
+		return
+	}
+	defer watch.Close()
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("thread", shared)
+	c.Writer.Flush()
+
+	expired := time.NewTimer(time.Until(watch.ExpiresAt))
+	defer expired.Stop()
+	heartbeat := time.NewTicker(shareStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case update := <-watch.Updates():
+			c.SSEvent(update.Type, update)
+		case <-watch.Done():
+			c.SSEvent("closed", gin.H{"reason": watch.Reason()})
+			return
+		case <-expired.C:
+			c.SSEvent("closed", gin.H{"reason": services.ThreadWatchExpired})
+			return
+		case <-heartbeat.C:
+			if err := h.ShareService.Check(watch); errors.Is(err, services.ErrThreadShareNotFound) {
+				c.SSEvent("closed", gin.H{"reason": services.ThreadWatchRevoked})
+				return
+			}
+			if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
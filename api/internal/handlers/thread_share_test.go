@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestThreadShareHandler_StreamShare(t *testing.T) {
+	threadID := uuid.New()
+
+	newRouter := func(shareRepo *repomocks.MockThreadShareRepository) http.Handler {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDWithMessages", mock.Anything, threadID).Return(&models.Thread{ID: threadID, Messages: []models.Message{}}, nil)
+		service := services.NewThreadShareServiceForTest(nil, shareRepo, threadRepo, nil)
+
+		router := setupTestRouter()
+		router.GET("/api/public/shares/:token", NewThreadShareHandler(service).StreamShare)
+		return router
+	}
+
+	t.Run("streams the thread", func(t *testing.T) {
+		shareRepo := new(repomocks.MockThreadShareRepository)
+		shareRepo.On("FindByToken", mock.Anything, "tok").Return(&models.ThreadShare{ID: uuid.New(), ThreadID: threadID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/api/public/shares/tok", nil)
+		newRouter(shareRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+		assert.Contains(t, w.Body.String(), "event:thread")
+		assert.Contains(t, w.Body.String(), threadID.String())
+	})
+
+	t.Run("expired link", func(t *testing.T) {
+		shareRepo := new(repomocks.MockThreadShareRepository)
+		shareRepo.On("FindByToken", mock.Anything, "old").Return(&models.ThreadShare{ID: uuid.New(), ThreadID: threadID, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/public/shares/old", nil)
+		newRouter(shareRepo).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "SHARE_NOT_FOUND")
+	})
+}
@@ -4,14 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
-	clientmocks "ling-app/api/internal/client/mocks"
 	"ling-app/api/internal/middleware"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
@@ -70,21 +71,18 @@ func TestThreadHandler_SendAudioMessage_Success(t *testing.T) {
 	// Mock repositories
 	threadRepo := new(repomocks.MockThreadRepository)
 	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(thread, nil)
-	// For generateThreadName goroutine
-	threadRepo.On("FindByID", mock.Anything, threadID).Return(thread, nil).Maybe()
-	threadRepo.On("UpdateName", mock.Anything, threadID, mock.Anything).Return(nil).Maybe()
 
-	// Mock OpenAI client for title generation
-	openAIClient := new(clientmocks.MockOpenAIClient)
-	openAIClient.On("GenerateTitle", mock.Anything).Return("Test Thread", nil).Maybe()
+	// The untitled thread is named from this exchange
+	titles := new(servicemocks.MockThreadTitler)
+	titles.On("RequestTitle", threadID, "hello", "Hi there!").Return()
 
 	// Mock conversation service
 	conversationService := new(servicemocks.MockConversationProcessor)
-	conversationService.On("ProcessAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything).
+	conversationService.On("ProcessAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "").
 		Return(turn, nil)
 
 	// Create handler
-	handler := NewThreadHandler(nil, threadRepo, nil, conversationService, openAIClient, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, titles)
 
 	// Setup router
 	router := setupTestRouter()
@@ -121,6 +119,7 @@ func TestThreadHandler_SendAudioMessage_Success(t *testing.T) {
 	// Verify mocks
 	threadRepo.AssertExpectations(t)
 	conversationService.AssertExpectations(t)
+	titles.AssertExpectations(t)
 }
 
 func TestThreadHandler_SendAudioMessage_InvalidThreadID(t *testing.T) {
@@ -130,7 +129,7 @@ func TestThreadHandler_SendAudioMessage_InvalidThreadID(t *testing.T) {
 		Email: "test@example.com",
 	}
 
-	handler := NewThreadHandler(nil, nil, nil, nil, nil, nil)
+	handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -178,7 +177,7 @@ func TestThreadHandler_SendAudioMessage_ThreadNotFound(t *testing.T) {
 	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).
 		Return(nil, repository.ErrNotFound)
 
-	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -213,6 +212,34 @@ func TestThreadHandler_SendAudioMessage_ThreadNotFound(t *testing.T) {
 	threadRepo.AssertExpectations(t)
 }
 
+func TestThreadHandler_SendAudioMessage_ThreadEnded(t *testing.T) {
+	userID := uuid.New()
+	threadID := uuid.New()
+	endedAt := time.Now()
+
+	threadRepo := new(repomocks.MockThreadRepository)
+	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).
+		Return(&models.Thread{ID: threadID, UserID: userID, EndedAt: &endedAt}, nil)
+	mockConversation := new(servicemocks.MockConversationProcessor)
+
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, mockConversation, nil, nil, nil, nil, nil, nil)
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, &models.User{ID: userID})
+		c.Next()
+	})
+	router.POST("/threads/:id/messages/audio", handler.SendAudioMessage)
+
+	req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/messages/audio", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "THREAD_ENDED")
+	mockConversation.AssertNotCalled(t, "ProcessAudioMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestThreadHandler_SendAudioMessage_ThreadRepoError(t *testing.T) {
 	// Setup
 	userID := uuid.New()
@@ -228,7 +255,7 @@ func TestThreadHandler_SendAudioMessage_ThreadRepoError(t *testing.T) {
 	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).
 		Return(nil, errors.New("database error"))
 
-	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -281,7 +308,7 @@ func TestThreadHandler_SendAudioMessage_MissingAudioFile(t *testing.T) {
 	threadRepo := new(repomocks.MockThreadRepository)
 	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(thread, nil)
 
-	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -327,10 +354,10 @@ func TestThreadHandler_SendAudioMessage_ProcessingError(t *testing.T) {
 
 	// Mock conversation service to return error
 	conversationService := new(servicemocks.MockConversationProcessor)
-	conversationService.On("ProcessAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything).
+	conversationService.On("ProcessAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "").
 		Return(nil, errors.New("processing failed"))
 
-	handler := NewThreadHandler(nil, threadRepo, nil, conversationService, nil, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -383,15 +410,16 @@ func TestThreadHandler_GetThreads_Success(t *testing.T) {
 		Email: "test@example.com",
 	}
 
-	threads := []models.Thread{
-		{ID: uuid.New(), UserID: userID},
-		{ID: uuid.New(), UserID: userID},
+	preview := "¿Qué tal tu día?"
+	threads := []models.ThreadSummary{
+		{Thread: models.Thread{ID: uuid.New(), UserID: userID}, LastMessagePreview: &preview, MessageCount: 4},
+		{Thread: models.Thread{ID: uuid.New(), UserID: userID}},
 	}
 
 	threadRepo := new(repomocks.MockThreadRepository)
-	threadRepo.On("FindByUserID", mock.Anything, userID).Return(threads, nil)
+	threadRepo.On("FindSummariesByUserID", mock.Anything, userID).Return(threads, nil)
 
-	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -408,10 +436,15 @@ func TestThreadHandler_GetThreads_Success(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response []models.Thread
+	var response []map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Len(t, response, 2)
+	assert.Equal(t, threads[0].ID.String(), response[0]["id"])
+	assert.Equal(t, preview, response[0]["lastMessagePreview"])
+	assert.EqualValues(t, 4, response[0]["messageCount"])
+	assert.Nil(t, response[1]["lastMessagePreview"])
+	assert.Contains(t, response[1], "lastActivityAt")
 
 	threadRepo.AssertExpectations(t)
 }
@@ -425,9 +458,9 @@ func TestThreadHandler_GetThreads_Error(t *testing.T) {
 	}
 
 	threadRepo := new(repomocks.MockThreadRepository)
-	threadRepo.On("FindByUserID", mock.Anything, userID).Return(nil, errors.New("database error"))
+	threadRepo.On("FindSummariesByUserID", mock.Anything, userID).Return(nil, errors.New("database error"))
 
-	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -468,7 +501,7 @@ func TestThreadHandler_GetArchivedThreads_Success(t *testing.T) {
 	threadRepo := new(repomocks.MockThreadRepository)
 	threadRepo.On("FindArchivedByUserID", mock.Anything, userID).Return(threads, nil)
 
-	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil)
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	router := setupTestRouter()
 	router.Use(func(c *gin.Context) {
@@ -493,3 +526,768 @@ func TestThreadHandler_GetArchivedThreads_Success(t *testing.T) {
 
 	threadRepo.AssertExpectations(t)
 }
+
+func TestThreadHandler_UpdateThread_Goal(t *testing.T) {
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+	threadID := uuid.New()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantGoal *string
+	}{
+		{"sets goal", `{"goal": "  order food and ask for the bill "}`, strPtr("order food and ask for the bill")},
+		{"clears goal", `{"goal": ""}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldGoal := "greet the waiter"
+			completedAt := time.Now()
+			threadRepo := new(repomocks.MockThreadRepository)
+			threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).
+				Return(&models.Thread{ID: threadID, UserID: userID, Goal: &oldGoal, GoalCompletedAt: &completedAt}, nil)
+			threadRepo.On("Save", mock.Anything, mock.MatchedBy(func(thread *models.Thread) bool {
+				return thread.GoalCompletedAt == nil
+			})).Return(nil)
+
+			handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, user)
+				c.Next()
+			})
+			router.PATCH("/threads/:id", handler.UpdateThread)
+
+			req := httptest.NewRequest("PATCH", "/threads/"+threadID.String(), bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response models.Thread
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantGoal, response.Goal)
+			assert.Nil(t, response.GoalCompletedAt)
+			threadRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestThreadHandler_UpdateThread_ReplyStyle(t *testing.T) {
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+	threadID := uuid.New()
+	short, rate, fast := models.ReplyLengthShort, 0.8, 1.25
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantLength *string
+		wantRate   *float64
+	}{
+		{"sets overrides", `{"replyLength": "long", "speechRate": 1.25}`, http.StatusOK, strPtr("long"), &fast},
+		{"clears overrides", `{"replyLength": "", "speechRate": 0}`, http.StatusOK, nil, nil},
+		{"invalid reply length", `{"replyLength": "epic"}`, http.StatusBadRequest, nil, nil},
+		{"invalid speech rate", `{"speechRate": 4}`, http.StatusBadRequest, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadRepo := new(repomocks.MockThreadRepository)
+			threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).
+				Return(&models.Thread{ID: threadID, UserID: userID, ReplyLength: &short, SpeechRate: &rate}, nil)
+			threadRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+			handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, user)
+				c.Next()
+			})
+			router.PATCH("/threads/:id", handler.UpdateThread)
+
+			req := httptest.NewRequest("PATCH", "/threads/"+threadID.String(), bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				threadRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+				return
+			}
+
+			var response models.Thread
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantLength, response.ReplyLength)
+			assert.Equal(t, tt.wantRate, response.SpeechRate)
+		})
+	}
+}
+
+func TestThreadHandler_UpdateThread_Language(t *testing.T) {
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+	threadID := uuid.New()
+
+	tests := []struct {
+		name       string
+		language   string // The thread's before the update
+		locale     string
+		messages   int
+		body       string
+		wantStatus int
+		wantLocale string
+	}{
+		{name: "sets the language of a new thread", body: `{"language": "es", "locale": "es-MX"}`, wantStatus: http.StatusOK, wantLocale: "es-MX"},
+		{name: "drops the old language's locale", language: "es", locale: "es-MX", body: `{"language": "fr"}`, wantStatus: http.StatusOK},
+		{name: "unsupported language", body: `{"language": "xx"}`, wantStatus: http.StatusBadRequest},
+		{name: "locale of another language", language: "de", body: `{"locale": "es-MX"}`, wantStatus: http.StatusBadRequest},
+		{name: "thread with messages", messages: 2, body: `{"language": "de"}`, wantStatus: http.StatusConflict},
+		{name: "same language with messages", language: "de", messages: 2, body: `{"language": "de", "locale": "de-AT"}`, wantStatus: http.StatusOK, wantLocale: "de-AT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadRepo := new(repomocks.MockThreadRepository)
+			messageRepo := new(repomocks.MockMessageRepository)
+			threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).
+				Return(&models.Thread{ID: threadID, UserID: userID, Language: tt.language, Locale: tt.locale}, nil)
+			threadRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+			messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(make([]models.Message, tt.messages), nil)
+
+			handler := NewThreadHandler(nil, threadRepo, messageRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, user)
+				c.Next()
+			})
+			router.PATCH("/threads/:id", handler.UpdateThread)
+
+			req := httptest.NewRequest("PATCH", "/threads/"+threadID.String(), bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				threadRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+				return
+			}
+
+			var response models.Thread
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantLocale, response.Locale)
+		})
+	}
+}
+
+func TestThreadHandler_MarkThreadRead(t *testing.T) {
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+	threadID := uuid.New()
+	earlier := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		body       string
+		findErr    error
+		wantStatus int
+		wantReadAt func(readAt time.Time) bool
+	}{
+		{
+			name:       "defaults to now",
+			wantStatus: http.StatusOK,
+			wantReadAt: func(readAt time.Time) bool { return time.Since(readAt) < time.Minute },
+		},
+		{
+			name:       "uses the client's read position",
+			body:       `{"readAt": "` + earlier.Format(time.RFC3339) + `"}`,
+			wantStatus: http.StatusOK,
+			wantReadAt: func(readAt time.Time) bool { return readAt.Equal(earlier) },
+		},
+		{
+			name:       "clamps future read positions",
+			body:       `{"readAt": "` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`,
+			wantStatus: http.StatusOK,
+			wantReadAt: func(readAt time.Time) bool { return !readAt.After(time.Now()) },
+		},
+		{
+			name:       "someone else's thread",
+			findErr:    repository.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadRepo := new(repomocks.MockThreadRepository)
+			readStateRepo := new(repomocks.MockThreadReadStateRepository)
+			if tt.findErr != nil {
+				threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(nil, tt.findErr)
+			} else {
+				threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).
+					Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+				readStateRepo.On("MarkRead", mock.Anything, userID, threadID, mock.MatchedBy(tt.wantReadAt)).Return(nil)
+				readStateRepo.On("Find", mock.Anything, userID, threadID).
+					Return(&models.ThreadReadState{UserID: userID, ThreadID: threadID, LastReadAt: earlier}, nil)
+			}
+
+			handler := NewThreadHandler(nil, threadRepo, nil, readStateRepo, nil, nil, nil, nil, nil, nil, nil)
+
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, user)
+				c.Next()
+			})
+			router.POST("/threads/:id/read", handler.MarkThreadRead)
+
+			req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/read", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			readStateRepo.AssertExpectations(t)
+			if tt.findErr != nil {
+				readStateRepo.AssertNotCalled(t, "MarkRead", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestThreadHandler_GetThread_Include(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+
+	tests := []struct {
+		name         string
+		query        string
+		withAnalysis bool
+	}{
+		{"omitted by default", "", false},
+		{"include analysis", "?include=analysis", true},
+		{"include list", "?include=goal,%20analysis", true},
+		{"unknown include", "?include=timings", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadRepo := new(repomocks.MockThreadRepository)
+			threadRepo.On("FindByIDAndUserIDWithMessages", mock.Anything, threadID, userID, tt.withAnalysis).
+				Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+
+			handler := NewThreadHandler(nil, threadRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, user)
+				c.Next()
+			})
+			router.GET("/threads/:id", handler.GetThread)
+
+			req := httptest.NewRequest("GET", "/threads/"+threadID.String()+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			threadRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestThreadHandler_GetMessageAnalysis(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+	analyzed := &models.Message{
+		ID:                    uuid.New(),
+		ThreadID:              threadID,
+		Role:                  "user",
+		PronunciationStatus:   "complete",
+		PronunciationAnalysis: models.JSONMap{"audio_ipa": "həloʊ"},
+	}
+	pending := &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", PronunciationStatus: "pending"}
+	otherThread := &models.Message{ID: uuid.New(), ThreadID: uuid.New(), PronunciationAnalysis: models.JSONMap{}}
+
+	threadRepo := new(repomocks.MockThreadRepository)
+	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+	messageRepo := new(repomocks.MockMessageRepository)
+	messageRepo.On("FindByID", mock.Anything, analyzed.ID).Return(analyzed, nil)
+	messageRepo.On("FindByID", mock.Anything, pending.ID).Return(pending, nil)
+	messageRepo.On("FindByID", mock.Anything, otherThread.ID).Return(otherThread, nil)
+
+	handler := NewThreadHandler(nil, threadRepo, messageRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/threads/:id/messages/:messageId/analysis", handler.GetMessageAnalysis)
+
+	get := func(messageID uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/threads/"+threadID.String()+"/messages/"+messageID.String()+"/analysis", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get(analyzed.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, analyzed.ID.String(), response["messageId"])
+	assert.Equal(t, map[string]interface{}{"audio_ipa": "həloʊ"}, response["pronunciationAnalysis"])
+
+	assert.Equal(t, http.StatusNotFound, get(pending.ID).Code, "no analysis yet")
+	assert.Equal(t, http.StatusNotFound, get(otherThread.ID).Code, "message from another thread")
+}
+
+func TestThreadHandler_SendAudioMessage_SpeechOnly(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+	name := "Ordering coffee"
+	thread := &models.Thread{ID: threadID, UserID: userID, Name: &name, SpeechOnly: true}
+	audioURL := "audio/reply.mp3"
+	turn := &services.ConversationTurn{
+		UserMessage:      &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", Content: "A latte please", HasAudio: true},
+		AssistantMessage: &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "assistant", Content: "Coming right up!", AudioURL: &audioURL, HasAudio: true, SuggestedReplies: models.StringList{"Thanks!"}},
+	}
+
+	threadRepo := new(repomocks.MockThreadRepository)
+	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(thread, nil)
+	conversationService := new(servicemocks.MockConversationProcessor)
+	conversationService.On("ProcessAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "").Return(turn, nil)
+
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, nil)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/threads/:id/messages/audio", handler.SendAudioMessage)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "test.webm")
+	assert.NoError(t, err)
+	_, err = part.Write([]byte("fake audio data"))
+	assert.NoError(t, err)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/messages/audio", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		UserMessage      models.Message `json:"userMessage"`
+		AssistantMessage models.Message `json:"assistantMessage"`
+		TextWithheld     bool           `json:"textWithheld"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.TextWithheld)
+	assert.Empty(t, response.UserMessage.Content)
+	assert.Empty(t, response.AssistantMessage.Content)
+	assert.Empty(t, response.AssistantMessage.SuggestedReplies)
+	assert.Equal(t, &audioURL, response.AssistantMessage.AudioURL, "the audio still comes back")
+	assert.Equal(t, "Coming right up!", turn.AssistantMessage.Content, "the stored turn keeps its text")
+}
+
+func TestThreadHandler_RevealMessageText(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com"}
+	reply := &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "assistant", Content: "Coming right up!", SuggestedReplies: models.StringList{"Thanks!"}}
+	otherThread := &models.Message{ID: uuid.New(), ThreadID: uuid.New(), Content: "Not yours"}
+
+	threadRepo := new(repomocks.MockThreadRepository)
+	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID, SpeechOnly: true}, nil)
+	messageRepo := new(repomocks.MockMessageRepository)
+	messageRepo.On("FindByID", mock.Anything, reply.ID).Return(reply, nil)
+	messageRepo.On("FindByID", mock.Anything, otherThread.ID).Return(otherThread, nil)
+
+	handler := NewThreadHandler(nil, threadRepo, messageRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/threads/:id/messages/:messageId/text", handler.RevealMessageText)
+
+	get := func(messageID uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/threads/"+threadID.String()+"/messages/"+messageID.String()+"/text", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get(reply.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Coming right up!", response["content"])
+	assert.Equal(t, []interface{}{"Thanks!"}, response["suggestedReplies"])
+
+	assert.Equal(t, http.StatusNotFound, get(otherThread.ID).Code, "message from another thread")
+}
+
+func TestThreadHandler_StreamAudioMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		speechOnly bool
+		wantEvents []string
+	}{
+		{
+			name:       "streams each stage",
+			wantEvents: []string{"event:transcribed", "event:token", "event:reply", "event:turn"},
+		},
+		{
+			name:       "speech-only threads get no text",
+			speechOnly: true,
+			wantEvents: []string{"event:transcribed", "event:turn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			threadID := uuid.New()
+			thread := &models.Thread{ID: threadID, UserID: userID, SpeechOnly: tt.speechOnly}
+			userMessage := &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", Content: "hello", HasAudio: true}
+			turn := &services.ConversationTurn{
+				UserMessage:      userMessage,
+				AssistantMessage: &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "assistant", Content: "Hi there!", HasAudio: true},
+			}
+
+			threadRepo := new(repomocks.MockThreadRepository)
+			threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(thread, nil)
+			conversationService := new(servicemocks.MockConversationProcessor)
+			conversationService.On("StreamAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "", mock.Anything).
+				Run(func(args mock.Arguments) {
+					progress := args.Get(5).(services.TurnProgress)
+					progress(services.TurnEvent{Type: services.TurnEventTranscribed, Message: userMessage})
+					progress(services.TurnEvent{Type: services.TurnEventToken, Text: "Hi there!"})
+					progress(services.TurnEvent{Type: services.TurnEventReply, Text: "Hi there!"})
+				}).
+				Return(turn, nil)
+
+			handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, nil)
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, &models.User{ID: userID})
+				c.Next()
+			})
+			router.POST("/threads/:id/messages/audio/stream", handler.StreamAudioMessage)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("audio", "test.webm")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte("fake audio data"))
+			assert.NoError(t, err)
+			writer.Close()
+
+			req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/messages/audio/stream", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+			var events []string
+			for _, line := range bytes.Split(w.Body.Bytes(), []byte("\n")) {
+				if bytes.HasPrefix(line, []byte("event:")) {
+					events = append(events, string(line))
+				}
+			}
+			assert.Equal(t, tt.wantEvents, events)
+			if tt.speechOnly {
+				assert.NotContains(t, w.Body.String(), "hello")
+				assert.NotContains(t, w.Body.String(), "Hi there!")
+			}
+		})
+	}
+}
+
+func TestThreadHandler_StreamAudioMessage_FailsBeforeStreaming(t *testing.T) {
+	userID := uuid.New()
+	threadID := uuid.New()
+
+	threadRepo := new(repomocks.MockThreadRepository)
+	threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+	conversationService := new(servicemocks.MockConversationProcessor)
+	conversationService.On("StreamAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "", mock.Anything).
+		Return(nil, services.ErrInsufficientCredits)
+
+	handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, nil)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, &models.User{ID: userID})
+		c.Next()
+	})
+	router.POST("/threads/:id/messages/audio/stream", handler.StreamAudioMessage)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("audio", "test.webm")
+	assert.NoError(t, err)
+	_, err = part.Write([]byte("fake audio data"))
+	assert.NoError(t, err)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/messages/audio/stream", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPaymentRequired, w.Code)
+	assert.Contains(t, w.Body.String(), "INSUFFICIENT_CREDITS")
+}
+
+func TestThreadHandler_StreamAudioMessage_Idempotent(t *testing.T) {
+	userID := uuid.New()
+	threadID := uuid.New()
+	thread := &models.Thread{ID: threadID, UserID: userID}
+	userMessage := &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", Content: "hello", HasAudio: true}
+
+	newRouter := func(conversationService *servicemocks.MockConversationProcessor, keys *repomocks.MockIdempotencyKeyRepository) *gin.Engine {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(thread, nil)
+		titles := new(servicemocks.MockThreadTitler)
+		titles.On("RequestTitle", mock.Anything, mock.Anything, mock.Anything).Return()
+		handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, titles)
+		store := services.NewIdempotencyServiceForTest(nil, keys, time.Now)
+
+		router := setupTestRouter()
+		router.Use(func(c *gin.Context) {
+			c.Set(middleware.UserContextKey, &models.User{ID: userID})
+			c.Next()
+		})
+		router.POST("/threads/:id/messages/audio/stream", middleware.Idempotent(store), handler.StreamAudioMessage)
+		return router
+	}
+	// Each request gets its own multipart boundary, as a client's retry would
+	send := func(router *gin.Engine, audio string) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("audio", "test.webm")
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(audio))
+		assert.NoError(t, err)
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/messages/audio/stream", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set(middleware.IdempotencyKeyHeader, "k1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("replays a finished stream and rejects other audio", func(t *testing.T) {
+		stored := &models.IdempotencyKey{}
+		keys := new(repomocks.MockIdempotencyKeyRepository)
+		keys.On("Reserve", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*stored = *args.Get(1).(*models.IdempotencyKey)
+		}).Return(true, nil).Once()
+		keys.On("Complete", mock.Anything, mock.Anything, http.StatusOK, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			completedAt := args.Get(5).(time.Time)
+			stored.ResponseStatus = args.Int(2)
+			stored.ResponseContentType = args.String(3)
+			stored.ResponseBody = args.Get(4).([]byte)
+			stored.CompletedAt = &completedAt
+		}).Return(nil)
+		keys.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+		keys.On("FindByUserAndKey", mock.Anything, userID, "k1").Return(stored, nil)
+
+		conversationService := new(servicemocks.MockConversationProcessor)
+		conversationService.On("StreamAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "", mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(5).(services.TurnProgress)(services.TurnEvent{Type: services.TurnEventTranscribed, Message: userMessage})
+			}).
+			Return(&services.ConversationTurn{
+				UserMessage:      userMessage,
+				AssistantMessage: &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "assistant", Content: "Hi there!"},
+			}, nil).Once()
+		router := newRouter(conversationService, keys)
+
+		first := send(router, "fake audio data")
+		assert.Equal(t, http.StatusOK, first.Code)
+
+		retry := send(router, "fake audio data")
+		assert.Equal(t, http.StatusOK, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		assert.Contains(t, retry.Header().Get("Content-Type"), "text/event-stream")
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+
+		other := send(router, "other audio data")
+		assert.Equal(t, http.StatusUnprocessableEntity, other.Code)
+		assert.Contains(t, other.Body.String(), "IDEMPOTENCY_KEY_REUSED")
+
+		conversationService.AssertNumberOfCalls(t, "StreamAudioMessage", 1)
+	})
+
+	t.Run("releases the key of a stream that failed", func(t *testing.T) {
+		keys := new(repomocks.MockIdempotencyKeyRepository)
+		keys.On("Reserve", mock.Anything, mock.Anything).Return(true, nil)
+		keys.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+		conversationService := new(servicemocks.MockConversationProcessor)
+		conversationService.On("StreamAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "", mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(5).(services.TurnProgress)(services.TurnEvent{Type: services.TurnEventTranscribed, Message: userMessage})
+			}).
+			Return(nil, &services.VoiceTurnError{Stage: services.StageGenerate, Err: errors.New("llm down")})
+		router := newRouter(conversationService, keys)
+
+		w := send(router, "fake audio data")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "event:error")
+		keys.AssertCalled(t, "Delete", mock.Anything, mock.Anything)
+		keys.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestThreadHandler_SendAudioMessage_StageErrors(t *testing.T) {
+	tests := []struct {
+		stage      string
+		wantStatus int
+		wantCode   string
+	}{
+		{stage: services.StageUpload, wantStatus: http.StatusServiceUnavailable, wantCode: "AUDIO_UPLOAD_FAILED"},
+		{stage: services.StagePresign, wantStatus: http.StatusServiceUnavailable, wantCode: "AUDIO_PRESIGN_FAILED"},
+		{stage: services.StageTranscribe, wantStatus: http.StatusBadGateway, wantCode: "TRANSCRIPTION_FAILED"},
+		{stage: services.StageGenerate, wantStatus: http.StatusBadGateway, wantCode: "REPLY_FAILED"},
+		{stage: services.StagePersist, wantStatus: http.StatusInternalServerError, wantCode: "MESSAGE_SAVE_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stage, func(t *testing.T) {
+			userID := uuid.New()
+			threadID := uuid.New()
+
+			threadRepo := new(repomocks.MockThreadRepository)
+			threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+			stageErr := &services.VoiceTurnError{Stage: tt.stage, Err: errors.New("boom")}
+			conversationService := new(servicemocks.MockConversationProcessor)
+			conversationService.On("ProcessAudioMessage", mock.Anything, threadID, mock.Anything, mock.Anything, "").
+				Return(nil, fmt.Errorf("failed to process user audio: %w", stageErr))
+
+			handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, nil)
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, &models.User{ID: userID})
+				c.Next()
+			})
+			router.POST("/threads/:id/messages/audio", handler.SendAudioMessage)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("audio", "test.webm")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte("fake audio data"))
+			assert.NoError(t, err)
+			writer.Close()
+
+			req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/messages/audio", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var response struct {
+				Code    string            `json:"code"`
+				Error   string            `json:"error"`
+				Details map[string]string `json:"details"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantCode, response.Code)
+			assert.Equal(t, tt.stage, response.Details["stage"])
+			assert.NotEmpty(t, response.Error)
+		})
+	}
+}
+
+func TestThreadHandler_SendTextMessage(t *testing.T) {
+	userID := uuid.New()
+	threadID := uuid.New()
+
+	tests := []struct {
+		name        string
+		body        string
+		turn        *services.ConversationTurn
+		err         error
+		wantStatus  int
+		wantWarning bool
+	}{
+		{
+			name: "silent reply",
+			body: `{"content": "I'm on the train"}`,
+			turn: &services.ConversationTurn{
+				UserMessage:      &models.Message{Role: "user", Content: "I'm on the train", Kind: models.MessageKindText},
+				AssistantMessage: &models.Message{Role: "assistant", Content: "No problem!"},
+				Silent:           true,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "reply that couldn't be spoken",
+			body: `{"content": "I'm on the train", "speak": true}`,
+			turn: &services.ConversationTurn{
+				UserMessage:      &models.Message{Role: "user", Content: "I'm on the train", Kind: models.MessageKindText},
+				AssistantMessage: &models.Message{Role: "assistant", Content: "No problem!"},
+			},
+			wantStatus:  http.StatusOK,
+			wantWarning: true,
+		},
+		{name: "missing content", body: `{"speak": true}`, wantStatus: http.StatusBadRequest},
+		{name: "too long", body: `{"content": "..."}`, err: services.ErrInvalidTextMessage, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadRepo := new(repomocks.MockThreadRepository)
+			threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID, Name: strPtr("Commute")}, nil)
+			conversationService := new(servicemocks.MockConversationProcessor)
+			if tt.turn != nil || tt.err != nil {
+				var req SendTextMessageRequest
+				assert.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+				conversationService.On("SendTextMessage", mock.Anything, threadID, req.Content, req.Speak).Return(tt.turn, tt.err)
+			}
+
+			handler := NewThreadHandler(nil, threadRepo, nil, nil, conversationService, nil, nil, nil, nil, nil, nil)
+			router := setupTestRouter()
+			router.Use(func(c *gin.Context) {
+				c.Set(middleware.UserContextKey, &models.User{ID: userID})
+				c.Next()
+			})
+			router.POST("/threads/:id/messages", handler.SendTextMessage)
+
+			req := httptest.NewRequest("POST", "/threads/"+threadID.String()+"/messages", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			conversationService.AssertExpectations(t)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var response map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "text", response["userMessage"].(map[string]any)["kind"])
+			_, warned := response["warning"]
+			assert.Equal(t, tt.wantWarning, warned)
+		})
+	}
+}
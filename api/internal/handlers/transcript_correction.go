@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CorrectTranscriptRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// CorrectTranscript replaces the transcript of one of the user's voice
+// messages. If the correction changes what the recording is scored against,
+// its old results are taken out of the user's phoneme stats and it is
+// analyzed again.
+// PATCH /api/threads/:id/messages/:messageId
+func (h *ThreadHandler) CorrectTranscript(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	threadID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid thread ID"})
+		return
+	}
+	messageID, err := uuid.Parse(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	if h.Corrections == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transcript corrections are not available"})
+		return
+	}
+
+	var req CorrectTranscriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleValidationError(c, err)
+		return
+	}
+
+	message, err := h.Corrections.CorrectTranscript(user.ID, threadID, messageID, req.Content)
+	if err != nil {
+		handleError(c, err, "CorrectTranscript")
+		return
+	}
+
+	c.JSON(http.StatusOK, message)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCorrectionRouter(user *models.User, handler *ThreadHandler) *gin.Engine {
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.PATCH("/threads/:id/messages/:messageId", handler.CorrectTranscript)
+	return router
+}
+
+func TestThreadHandler_CorrectTranscript(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+	threadID, messageID := uuid.New(), uuid.New()
+	path := "/threads/" + threadID.String() + "/messages/" + messageID.String()
+
+	t.Run("returns the corrected message", func(t *testing.T) {
+		corrections := new(servicemocks.MockTranscriptCorrector)
+		corrections.On("CorrectTranscript", user.ID, threadID, messageID, "I think so").
+			Return(&models.Message{ID: messageID, ThreadID: threadID, Role: "user", Content: "I think so", PronunciationStatus: "pending"}, nil)
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.Corrections = corrections
+
+		req := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{"content": "I think so"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupCorrectionRouter(user, handler).ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "I think so", body["content"])
+		assert.Equal(t, "pending", body["pronunciationStatus"])
+	})
+
+	t.Run("analysis still running", func(t *testing.T) {
+		corrections := new(servicemocks.MockTranscriptCorrector)
+		corrections.On("CorrectTranscript", user.ID, threadID, messageID, "I think so").Return(nil, services.ErrAnalysisPending)
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.Corrections = corrections
+
+		req := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{"content": "I think so"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupCorrectionRouter(user, handler).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("missing content", func(t *testing.T) {
+		handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		handler.Corrections = new(servicemocks.MockTranscriptCorrector)
+
+		req := httptest.NewRequest(http.MethodPatch, path, strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupCorrectionRouter(user, handler).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
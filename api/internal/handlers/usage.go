@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageHandler struct {
+	UsageService services.UsageLimiter
+}
+
+func NewUsageHandler(usageService services.UsageLimiter) *UsageHandler {
+	return &UsageHandler{
+		UsageService: usageService,
+	}
+}
+
+// GetUsage returns the user's stored threads and messages against their plan limits
+// GET /api/usage
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	user := middleware.MustGetUser(c)
+
+	usage, err := h.UsageService.GetUsage(user.ID)
+	if err != nil {
+		handleError(c, err, "GetUsage")
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageHandler_GetUsage(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	usageService := new(servicemocks.MockUsageLimiter)
+	usageService.On("GetUsage", user.ID).Return(&services.Usage{
+		Tier:     models.TierFree,
+		Threads:  services.UsageCount{Count: 3, Limit: 20},
+		Messages: services.UsageCount{Count: 42, Limit: 500},
+	}, nil)
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.GET("/usage", NewUsageHandler(usageService).GetUsage)
+
+	req := httptest.NewRequest("GET", "/usage", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "free", body["tier"])
+	assert.Equal(t, map[string]interface{}{"count": float64(3), "limit": float64(20)}, body["threads"])
+}
+
+func TestThreadHandler_CreateThread_TierLimitReached(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com"}
+
+	usageService := new(servicemocks.MockUsageLimiter)
+	usageService.On("CheckThreadLimit", user.ID).Return(&services.TierLimitError{
+		Resource: services.LimitThreads, Tier: models.TierFree, Limit: 20, Count: 20,
+	})
+
+	handler := NewThreadHandler(nil, nil, nil, nil, nil, nil, nil, nil, usageService, nil, nil)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/threads", handler.CreateThread)
+
+	req := httptest.NewRequest("POST", "/threads", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	var body struct {
+		Code    string                 `json:"code"`
+		Details map[string]interface{} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "TIER_LIMIT_REACHED", body.Code)
+	assert.Equal(t, "threads", body.Details["resource"])
+	assert.Equal(t, float64(20), body.Details["limit"])
+	assert.Equal(t, float64(20), body.Details["count"])
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WarehouseHandler struct {
+	Exporter services.WarehouseExporter
+}
+
+func NewWarehouseHandler(exporter services.WarehouseExporter) *WarehouseHandler {
+	return &WarehouseHandler{
+		Exporter: exporter,
+	}
+}
+
+// ExportWarehouse runs the warehouse export now instead of waiting for the
+// nightly run, writing every finished day not yet exported
+// POST /api/admin/warehouse/export
+func (h *WarehouseHandler) ExportWarehouse(c *gin.Context) {
+	admin := middleware.MustGetUser(c)
+
+	report, err := h.Exporter.Export(c.Request.Context(), &admin.ID)
+	if err != nil {
+		handleError(c, err, "ExportWarehouse")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
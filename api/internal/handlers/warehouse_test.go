@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ling-app/api/internal/middleware"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+	servicemocks "ling-app/api/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupWarehouseRouter(user *models.User, exporter services.WarehouseExporter) *gin.Engine {
+	handler := NewWarehouseHandler(exporter)
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserContextKey, user)
+		c.Next()
+	})
+	router.POST("/admin/warehouse/export", handler.ExportWarehouse)
+	return router
+}
+
+func TestWarehouseHandler_ExportWarehouse(t *testing.T) {
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	t.Run("returns the report", func(t *testing.T) {
+		through := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+		exporter := new(servicemocks.MockWarehouseExporter)
+		exporter.On("Export", mock.Anything, &admin.ID).Return(&services.WarehouseExportReport{
+			Through: through,
+			Files:   []services.WarehouseExportFile{{Table: "messages", Day: "2026-10-15", Key: "wh/messages/dt=2026-10-15/part-0.parquet", Rows: 42}},
+		}, nil)
+
+		w := httptest.NewRecorder()
+		setupWarehouseRouter(admin, exporter).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/warehouse/export", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var report services.WarehouseExportReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.True(t, through.Equal(report.Through))
+		require.Len(t, report.Files, 1)
+		assert.Equal(t, 42, report.Files[0].Rows)
+		exporter.AssertExpectations(t)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		exporter := new(servicemocks.MockWarehouseExporter)
+		exporter.On("Export", mock.Anything, mock.Anything).Return(nil, services.ErrWarehouseExportDisabled)
+
+		w := httptest.NewRecorder()
+		setupWarehouseRouter(admin, exporter).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/warehouse/export", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("already running", func(t *testing.T) {
+		exporter := new(servicemocks.MockWarehouseExporter)
+		exporter.On("Export", mock.Anything, mock.Anything).Return(nil, services.ErrWarehouseExportRunning)
+
+		w := httptest.NewRecorder()
+		setupWarehouseRouter(admin, exporter).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/warehouse/export", nil))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
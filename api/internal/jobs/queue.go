@@ -0,0 +1,256 @@
+// Package jobs is an in-process background job queue with priority lanes.
+//
+// Workers take jobs from the priority lane first, up to PriorityWeight jobs in
+// a row while standard jobs are waiting. A standard job that has waited longer
+// than MaxWait runs next regardless, so the standard lane is never starved.
+// Each lane holds at most MaxDepth waiting jobs; Enqueue turns away the rest
+// with ErrQueueFull.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Lane is a priority class in the queue.
+type Lane string
+
+const (
+	LanePriority Lane = "priority" // Paid tiers
+	LaneStandard Lane = "standard" // Free tier
+)
+
+// Lanes lists every lane, highest priority first.
+var Lanes = []Lane{LanePriority, LaneStandard}
+
+var (
+	ErrQueueClosed = errors.New("job queue is closed")
+	ErrQueueFull   = errors.New("job queue lane is full")
+)
+
+// Job is a unit of background work.
+type Job struct {
+	Name string
+	Lane Lane
+	Run  func(ctx context.Context) error
+
+	enqueuedAt time.Time
+}
+
+// Config controls worker count and lane scheduling.
+type Config struct {
+	Workers        int           // Number of concurrent workers
+	PriorityWeight int           // Priority jobs taken per standard job while both lanes are busy
+	MaxWait        time.Duration // Standard jobs waiting longer than this run next
+	MaxDepth       int           // Jobs a lane holds waiting before new ones are rejected
+}
+
+// LaneStats are counters for a single lane.
+type LaneStats struct {
+	Depth     int   `json:"depth"`
+	Enqueued  int64 `json:"enqueued"`
+	Completed int64 `json:"completed"`
+	Failed    int64 `json:"failed"`
+	Rejected  int64 `json:"rejected"`
+	AvgWaitMs int64 `json:"avgWaitMs"`
+}
+
+type laneCounters struct {
+	enqueued  int64
+	completed int64
+	failed    int64
+	rejected  int64
+	started   int64
+	totalWait time.Duration
+}
+
+// Queue is a bounded pool of workers fed from priority lanes.
+type Queue struct {
+	cfg Config
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	lanes    map[Lane][]*Job
+	counters map[Lane]*laneCounters
+	// priorityStreak counts priority jobs taken since the last standard job
+	priorityStreak int
+	closed         bool
+
+	now func() time.Time
+}
+
+// NewQueue creates a queue. Call Start to begin processing.
+func NewQueue(cfg Config) *Queue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.PriorityWeight <= 0 {
+		cfg.PriorityWeight = 3
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = 30 * time.Second
+	}
+	if cfg.MaxDepth <= 0 {
+		cfg.MaxDepth = 1000
+	}
+
+	q := &Queue{
+		cfg:      cfg,
+		lanes:    make(map[Lane][]*Job),
+		counters: make(map[Lane]*laneCounters),
+		now:      time.Now,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	for _, lane := range Lanes {
+		q.counters[lane] = &laneCounters{}
+	}
+	return q
+}
+
+// Enqueue adds a job to its lane. Jobs with an unknown lane go to the standard lane.
+// A lane already holding MaxDepth jobs gives ErrQueueFull, counted in the
+// lane's Rejected stat.
+func (q *Queue) Enqueue(job Job) error {
+	if job.Run == nil {
+		return fmt.Errorf("job %q has no Run func", job.Name)
+	}
+	if _, ok := q.counters[job.Lane]; !ok {
+		job.Lane = LaneStandard
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if len(q.lanes[job.Lane]) >= q.cfg.MaxDepth {
+		q.counters[job.Lane].rejected++
+		return ErrQueueFull
+	}
+
+	job.enqueuedAt = q.now()
+	q.lanes[job.Lane] = append(q.lanes[job.Lane], &job)
+	q.counters[job.Lane].enqueued++
+	q.cond.Signal()
+	return nil
+}
+
+// Start launches the workers. They stop taking new jobs once ctx is cancelled;
+// jobs still waiting in the queue are dropped.
+func (q *Queue) Start(ctx context.Context) {
+	log.Printf("[Jobs] Starting %d workers (priority weight %d, max wait %s, max depth %d)",
+		q.cfg.Workers, q.cfg.PriorityWeight, q.cfg.MaxWait, q.cfg.MaxDepth)
+
+	for i := 0; i < q.cfg.Workers; i++ {
+		go q.work(ctx)
+	}
+
+	go func() {
+		<-ctx.Done()
+		q.mu.Lock()
+		q.closed = true
+		q.mu.Unlock()
+		q.cond.Broadcast()
+	}()
+}
+
+// Stats returns a snapshot of per-lane counters.
+func (q *Queue) Stats() map[Lane]LaneStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := make(map[Lane]LaneStats, len(q.counters))
+	for lane, c := range q.counters {
+		s := LaneStats{
+			Depth:     len(q.lanes[lane]),
+			Enqueued:  c.enqueued,
+			Completed: c.completed,
+			Failed:    c.failed,
+			Rejected:  c.rejected,
+		}
+		if c.started > 0 {
+			s.AvgWaitMs = (c.totalWait / time.Duration(c.started)).Milliseconds()
+		}
+		stats[lane] = s
+	}
+	return stats
+}
+
+func (q *Queue) work(ctx context.Context) {
+	for {
+		job, ok := q.next()
+		if !ok {
+			return
+		}
+		q.run(ctx, job)
+	}
+}
+
+// next blocks until a job is available or the queue is closed.
+func (q *Queue) next() (*Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if q.closed {
+			return nil, false
+		}
+		if job := q.pick(); job != nil {
+			c := q.counters[job.Lane]
+			c.started++
+			c.totalWait += q.now().Sub(job.enqueuedAt)
+			return job, true
+		}
+		q.cond.Wait()
+	}
+}
+
+// pick removes and returns the next job to run, or nil if both lanes are empty.
+// Must be called with q.mu held.
+func (q *Queue) pick() *Job {
+	priority := q.lanes[LanePriority]
+	standard := q.lanes[LaneStandard]
+
+	takeStandard := len(standard) > 0 &&
+		(len(priority) == 0 ||
+			q.priorityStreak >= q.cfg.PriorityWeight ||
+			q.now().Sub(standard[0].enqueuedAt) >= q.cfg.MaxWait)
+
+	switch {
+	case takeStandard:
+		q.lanes[LaneStandard] = standard[1:]
+		q.priorityStreak = 0
+		return standard[0]
+	case len(priority) > 0:
+		q.lanes[LanePriority] = priority[1:]
+		q.priorityStreak++
+		return priority[0]
+	default:
+		return nil
+	}
+}
+
+func (q *Queue) run(ctx context.Context, job *Job) {
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return job.Run(ctx)
+	}()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err != nil {
+		log.Printf("[Jobs] %s (%s) failed: %v", job.Name, job.Lane, err)
+		q.counters[job.Lane].failed++
+		return
+	}
+	q.counters[job.Lane].completed++
+}
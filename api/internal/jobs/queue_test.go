@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noop(ctx context.Context) error { return nil }
+
+// drain picks every queued job without running it and returns the lane order.
+func drain(q *Queue) []Lane {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var order []Lane
+	for job := q.pick(); job != nil; job = q.pick() {
+		order = append(order, job.Lane)
+	}
+	return order
+}
+
+func TestQueue_PriorityWeight(t *testing.T) {
+	q := NewQueue(Config{PriorityWeight: 2, MaxWait: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Enqueue(Job{Name: "std", Lane: LaneStandard, Run: noop}))
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, q.Enqueue(Job{Name: "pri", Lane: LanePriority, Run: noop}))
+	}
+
+	assert.Equal(t, []Lane{
+		LanePriority, LanePriority, LaneStandard,
+		LanePriority, LanePriority, LaneStandard,
+		LanePriority, LaneStandard,
+	}, drain(q))
+}
+
+func TestQueue_StarvationProtection(t *testing.T) {
+	now := time.Now()
+	q := NewQueue(Config{PriorityWeight: 100, MaxWait: 10 * time.Second})
+	q.now = func() time.Time { return now }
+
+	require.NoError(t, q.Enqueue(Job{Name: "std", Lane: LaneStandard, Run: noop}))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Enqueue(Job{Name: "pri", Lane: LanePriority, Run: noop}))
+	}
+
+	q.mu.Lock()
+	first := q.pick()
+	now = now.Add(11 * time.Second)
+	second := q.pick()
+	q.mu.Unlock()
+
+	assert.Equal(t, LanePriority, first.Lane)
+	assert.Equal(t, LaneStandard, second.Lane, "standard job past MaxWait should jump ahead")
+}
+
+func TestQueue_UnknownLaneFallsBackToStandard(t *testing.T) {
+	q := NewQueue(Config{})
+
+	require.NoError(t, q.Enqueue(Job{Name: "x", Lane: "bogus", Run: noop}))
+
+	assert.Equal(t, 1, q.Stats()[LaneStandard].Depth)
+}
+
+func TestQueue_RejectsJobsWhenLaneIsFull(t *testing.T) {
+	q := NewQueue(Config{MaxDepth: 2})
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, q.Enqueue(Job{Name: "std", Lane: LaneStandard, Run: noop}))
+	}
+	assert.ErrorIs(t, q.Enqueue(Job{Name: "std", Lane: LaneStandard, Run: noop}), ErrQueueFull)
+	require.NoError(t, q.Enqueue(Job{Name: "pri", Lane: LanePriority, Run: noop}), "lanes fill up separately")
+
+	stats := q.Stats()
+	assert.Equal(t, 2, stats[LaneStandard].Depth)
+	assert.Equal(t, int64(1), stats[LaneStandard].Rejected)
+	assert.Equal(t, int64(2), stats[LaneStandard].Enqueued)
+
+	drain(q)
+	assert.NoError(t, q.Enqueue(Job{Name: "std", Lane: LaneStandard, Run: noop}), "a drained lane takes jobs again")
+}
+
+func TestQueue_RunsJobsAndRecordsStats(t *testing.T) {
+	q := NewQueue(Config{Workers: 2})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	require.NoError(t, q.Enqueue(Job{Name: "ok", Lane: LanePriority, Run: func(ctx context.Context) error {
+		defer wg.Done()
+		return nil
+	}}))
+	require.NoError(t, q.Enqueue(Job{Name: "fail", Lane: LaneStandard, Run: func(ctx context.Context) error {
+		defer wg.Done()
+		return errors.New("boom")
+	}}))
+	require.NoError(t, q.Enqueue(Job{Name: "panic", Lane: LaneStandard, Run: func(ctx context.Context) error {
+		defer wg.Done()
+		panic("boom")
+	}}))
+
+	q.Start(ctx)
+	wg.Wait()
+
+	assert.Eventually(t, func() bool {
+		stats := q.Stats()
+		return stats[LanePriority].Completed == 1 && stats[LaneStandard].Failed == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestQueue_EnqueueAfterShutdown(t *testing.T) {
+	q := NewQueue(Config{Workers: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	q.Start(ctx)
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		return errors.Is(q.Enqueue(Job{Name: "late", Run: noop}), ErrQueueClosed)
+	}, time.Second, 10*time.Millisecond)
+}
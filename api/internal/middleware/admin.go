@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+)
+
+// AuditActionAdminAuth is the audit log action for a rejected admin request
+const AuditActionAdminAuth = "admin_auth.rejected"
+
+// RequireAdmin is middleware that only lets admins through. It must run
+// after RequireAuth. Rejections are written to the audit log.
+func RequireAdmin(audit services.AuditLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := MustGetUser(c)
+		if user.IsAdmin() {
+			c.Next()
+			return
+		}
+
+		audit.Record(&models.AuditLog{
+			Action:    AuditActionAdminAuth,
+			Actor:     user.ID.String(),
+			Outcome:   models.AuditOutcomeFailure,
+			IPAddress: c.ClientIP(),
+			Details: models.JSONMap{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+			},
+		})
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "Admin access required",
+			"code":  "ADMIN_REQUIRED",
+		})
+	}
+}
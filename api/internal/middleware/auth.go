@@ -16,7 +16,8 @@ const (
 
 // RequireAuth is middleware that requires a valid session.
 // If the session is invalid or missing, it returns 401 Unauthorized.
-// If valid, it sets the user in the Gin context for handlers to access.
+// If valid, it sets the user in the Gin context for handlers to access,
+// and re-sends the cookie if the session was extended.
 func RequireAuth(authService *auth.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get session token from cookie
@@ -30,7 +31,7 @@ func RequireAuth(authService *auth.AuthService) gin.HandlerFunc {
 		}
 
 		// Validate session and get user
-		user, err := authService.ValidateSession(token)
+		user, expiresAt, err := authService.Authenticate(token)
 		if err != nil {
 			// Invalid or expired session
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
@@ -38,6 +39,10 @@ func RequireAuth(authService *auth.AuthService) gin.HandlerFunc {
 			})
 			return
 		}
+		if expiresAt != nil {
+			// The session was extended; keep the cookie as long
+			refreshSessionCookie(c, token, *expiresAt)
+		}
 
 		// Store user in context for handlers to access
 		c.Set(UserContextKey, user)
@@ -60,12 +65,15 @@ func OptionalAuth(authService *auth.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		user, err := authService.ValidateSession(token)
+		user, expiresAt, err := authService.Authenticate(token)
 		if err != nil {
 			// Invalid session, continue without user
 			c.Next()
 			return
 		}
+		if expiresAt != nil {
+			refreshSessionCookie(c, token, *expiresAt)
+		}
 
 		// Valid session, set user in context
 		c.Set(UserContextKey, user)
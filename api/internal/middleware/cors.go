@@ -1,18 +1,39 @@
 package middleware
 
 import (
+	"regexp"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// publicPath matches unauthenticated, embeddable endpoints (/api/public/...
+// and its versioned equivalents). They are readable from any origin, without
+// credentials.
+var publicPath = regexp.MustCompile(`^/api/(v[0-9]+/)?public/`)
+
 func CORS(allowedOrigins []string) gin.HandlerFunc {
 	config := cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Deprecation", "Sunset", "Link"},
 		AllowCredentials: true,
 	}
+	restricted := cors.New(config)
 
-	return cors.New(config)
+	public := cors.New(cors.Config{
+		AllowAllOrigins: true,
+		AllowMethods:    []string{"GET", "OPTIONS"},
+		AllowHeaders:    []string{"Origin", "Accept", "If-None-Match"},
+		ExposeHeaders:   []string{"Content-Length", "ETag"},
+	})
+
+	return func(c *gin.Context) {
+		if publicPath.MatchString(c.Request.URL.Path) {
+			public(c)
+			return
+		}
+		restricted(c)
+	}
 }
@@ -15,10 +15,13 @@ const (
 
 // RequireCredits is middleware that checks if the user has enough credits.
 // If they don't, it returns 402 Payment Required with INSUFFICIENT_CREDITS error code.
-// The cost is stored in context for the handler to use for deduction after success.
-func RequireCredits(creditsService *services.CreditsService, amount int) gin.HandlerFunc {
+// This is only a pre-check; the cost is stored in context, and whatever does
+// the work is responsible for charging it. cost is read per request so
+// runtime setting changes apply without a restart.
+func RequireCredits(creditsService *services.CreditsService, cost func() int) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		user := MustGetUser(c)
+		amount := cost()
 
 		hasCredits, err := creditsService.HasCredits(user.ID, amount)
 		if err != nil {
@@ -37,7 +40,7 @@ func RequireCredits(creditsService *services.CreditsService, amount int) gin.Han
 			return
 		}
 
-		// Store the cost in context for the handler to deduct after success
+		// Store the cost in context for the handler
 		c.Set(CreditsCostContextKey, amount)
 		c.Next()
 	}
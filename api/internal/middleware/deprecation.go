@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes a deprecated route or group of routes
+type Deprecation struct {
+	// Since is when the deprecation took effect (Deprecation header, RFC 9745)
+	Since *time.Time
+	// Sunset is when the routes stop responding (Sunset header, RFC 8594)
+	Sunset *time.Time
+	// Successor maps a request path to its replacement, advertised as a
+	// successor-version link; nil if there is none
+	Successor func(path string) string
+}
+
+// Deprecated announces a deprecation on every response of the routes it's
+// attached to. Clients that watch for these headers get a warning before
+// the routes are removed.
+func Deprecated(d Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		if d.Since != nil {
+			header.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+		}
+		if d.Sunset != nil {
+			header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Successor != nil {
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor(c.Request.URL.Path)))
+		}
+		c.Next()
+	}
+}
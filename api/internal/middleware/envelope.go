@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// paginationContextKey holds the *Pagination a handler reports for its list
+const paginationContextKey = "envelopePagination"
+
+// Envelope is the body of every JSON response on a versioned route. Exactly
+// one of Data and Error is set.
+type Envelope struct {
+	Data  json.RawMessage `json:"data,omitempty"`
+	Meta  *EnvelopeMeta   `json:"meta,omitempty"`
+	Error *EnvelopeError  `json:"error,omitempty"`
+}
+
+// EnvelopeMeta describes the response rather than the resource
+type EnvelopeMeta struct {
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes one page of a list. Lists page by offset: the next
+// page starts at Offset+Count, and a page with fewer than Limit items is the
+// last.
+type Pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"` // Items skipped before this page
+	Count  int `json:"count"`  // Items on this page
+}
+
+// EnvelopeError is a failed response. Code is a stable machine-readable
+// value such as "THREAD_NOT_FOUND"; Message is for people.
+type EnvelopeError struct {
+	Status  int         `json:"status"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// SetPagination records the page a list handler returned. It only shows up
+// in enveloped responses; unversioned routes ignore it.
+func SetPagination(c *gin.Context, limit, offset, count int) {
+	c.Set(paginationContextKey, &Pagination{Limit: limit, Offset: offset, Count: count})
+}
+
+// EnvelopeResponses rewrites handlers' JSON responses into an Envelope, so the
+// handlers serve both the legacy and the versioned routes unchanged. Other
+// content (audio, SVG, downloads) passes through as is.
+func EnvelopeResponses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &envelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		if w.body == nil {
+			return
+		}
+		c.Writer = w.ResponseWriter
+		w.ResponseWriter.Header().Del("Content-Length")
+		_, _ = w.ResponseWriter.Write(envelope(c, w.Status(), w.body.Bytes()))
+	}
+}
+
+// envelopeWriter holds back JSON bodies so they can be wrapped once the
+// handler is done
+type envelopeWriter struct {
+	gin.ResponseWriter
+	body    *bytes.Buffer
+	decided bool
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if mediaType == "application/json" && !w.Written() {
+			w.body = &bytes.Buffer{}
+		}
+	}
+	if w.body == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// envelope wraps a handler's JSON body
+func envelope(c *gin.Context, status int, body []byte) []byte {
+	var env Envelope
+	if status >= http.StatusBadRequest {
+		env.Error = envelopeError(status, body)
+	} else {
+		env.Data = body
+		if p, ok := c.Get(paginationContextKey); ok {
+			env.Meta = &EnvelopeMeta{Pagination: p.(*Pagination)}
+		}
+	}
+
+	out, err := json.Marshal(env)
+	if err != nil {
+		// body came from the handler's own JSON encoding, so this is unreachable
+		return body
+	}
+	return out
+}
+
+// envelopeError converts the handlers' {"error": "...", "code": "..."} bodies.
+// Any other fields become the details.
+func envelopeError(status int, body []byte) *EnvelopeError {
+	e := &EnvelopeError{
+		Status:  status,
+		Code:    strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_")),
+		Message: http.StatusText(status),
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal(body, &fields) != nil {
+		return e
+	}
+	if message, ok := fields["error"].(string); ok {
+		e.Message = message
+		delete(fields, "error")
+	}
+	if code, ok := fields["code"].(string); ok && code != "" {
+		e.Code = code
+		delete(fields, "code")
+	}
+
+	if details, ok := fields["details"]; ok && len(fields) == 1 {
+		e.Details = details
+	} else if len(fields) > 0 {
+		e.Details = fields
+	}
+	return e
+}
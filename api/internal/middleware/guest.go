@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RejectGuests is middleware that keeps guest demo accounts out of features
+// that need a real account. It must run after RequireAuth.
+func RejectGuests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if MustGetUser(c).IsGuest() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Sign up to use this feature",
+				"code":  "GUEST_NOT_ALLOWED",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressibleTypes are the content types worth gzipping. Audio is already
+// compressed, and event streams must reach the client as they're written.
+var compressibleTypes = map[string]bool{
+	"application/json": true,
+	"image/svg+xml":    true,
+	"text/plain":       true,
+	"text/html":        true,
+	"text/csv":         true,
+}
+
+var gzipWriters = sync.Pool{
+	New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// Gzip compresses responses for clients that accept it. Whether to compress
+// is decided on the first write, once the handler has set the content type.
+func Gzip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.Request.Header.Get("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		w := &gzipResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer w.close()
+
+		c.Next()
+	}
+}
+
+// gzipResponseWriter compresses the body when the response turns out to be
+// compressible, and passes it through untouched otherwise
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	gz      *gzip.Writer
+	decided bool
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.gz == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.gz.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide picks compression before the headers go out
+func (w *gzipResponseWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	header := w.Header()
+	if w.Written() || header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if !compressibleTypes[mediaType] {
+		return
+	}
+	switch w.Status() {
+	case http.StatusNoContent, http.StatusNotModified:
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+
+	w.gz = gzipWriters.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	gzipWriters.Put(w.gz)
+	w.gz = nil
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
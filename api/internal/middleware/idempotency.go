@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"ling-app/api/internal/services"
+)
+
+// IdempotencyKeyHeader is the request header clients set so a retry of the
+// request isn't processed twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength matches the key column
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentBodyBytes caps the body read to be hashed. Multipart uploads
+// past the in-memory part of it go to temporary files, as they would in the
+// handler.
+const maxIdempotentBodyBytes = 32 << 20
+
+// Idempotent is middleware that answers a retried request with the response
+// to the original one, for requests with an Idempotency-Key header. Put it
+// before the credit check, so a retry of a turn that spent the last credits
+// is still answered. A retry must send the same body as the original, or it
+// gets a 422. Only successful responses are kept; after an error, including
+// one a streamed response reports with c.Error once its status is written,
+// the key is released and a retry is processed again. Requests without the
+// header pass through.
+func Idempotent(store services.IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency-Key is too long",
+			})
+			return
+		}
+
+		hash, err := requestHash(c)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+
+		user := MustGetUser(c)
+		claimed, err := store.Begin(user.ID, key, c.Request.URL.Path, hash)
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyInUse):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error": "A request with this Idempotency-Key is still being processed",
+				"code":  "IDEMPOTENCY_KEY_IN_USE",
+			})
+			return
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"error": "This Idempotency-Key was already used for a different request",
+				"code":  "IDEMPOTENCY_KEY_REUSED",
+			})
+			return
+		case err != nil:
+			log.Printf("[Idempotency] Failed to claim key: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to check Idempotency-Key",
+			})
+			return
+		}
+
+		if claimed.Completed() {
+			contentType := claimed.ResponseContentType
+			if contentType == "" {
+				contentType = "application/json; charset=utf-8"
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(claimed.ResponseStatus, contentType, claimed.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		w := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if status := w.Status(); status >= 200 && status < 300 && len(c.Errors) == 0 {
+			err = store.Complete(claimed.ID, status, w.Header().Get("Content-Type"), w.body.Bytes())
+		} else {
+			err = store.Release(claimed.ID)
+		}
+		if err != nil {
+			log.Printf("[Idempotency] Failed to record response for key of user %s: %v", user.ID, err)
+		}
+	}
+}
+
+// requestHash returns a hex SHA-256 of the request's body. A multipart form
+// is hashed by its fields and files rather than its bytes, since a client
+// resending one picks a new boundary. The form stays parsed for the handler;
+// any other body is put back.
+func requestHash(c *gin.Context) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodyBytes)
+		if err := c.Request.ParseMultipartForm(maxIdempotentBodyBytes); err != nil {
+			return "", err
+		}
+		return formHash(c.Request.MultipartForm)
+	}
+
+	if c.Request.Body == nil {
+		return hashBytes(nil), nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodyBytes))
+	if err != nil {
+		return "", err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return hashBytes(body), nil
+}
+
+// formHash hashes a parsed multipart form, field names in order
+func formHash(form *multipart.Form) (string, error) {
+	h := sha256.New()
+	for _, name := range sortedKeys(form.Value) {
+		for _, value := range form.Value[name] {
+			fmt.Fprintf(h, "value %q %q\n", name, value)
+		}
+	}
+	for _, name := range sortedKeys(form.File) {
+		for _, header := range form.File[name] {
+			fmt.Fprintf(h, "file %q %d\n", name, header.Size)
+			file, err := header.Open()
+			if err != nil {
+				return "", err
+			}
+			_, err = io.Copy(h, file)
+			file.Close()
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashBytes(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// idempotencyWriter keeps a copy of the response body as it is written
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
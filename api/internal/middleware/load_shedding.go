@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+)
+
+// ShedLoad is middleware that rejects new ML-bound submissions with
+// 503 Service Unavailable while the ML service queue is saturated.
+// Paid tiers are shed only once the queue passes a higher threshold.
+func ShedLoad(monitor *services.MLLoadMonitor, stripeService services.StripeProcessor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Fast path: no tier lookup unless we're shedding
+		if !monitor.Overloaded() {
+			c.Next()
+			return
+		}
+
+		user := MustGetUser(c)
+
+		tier := models.TierFree
+		sub, err := stripeService.GetSubscription(user.ID)
+		if err == nil {
+			tier = sub.EffectiveTier()
+		} else if !errors.Is(err, services.ErrSubscriptionNotFound) {
+			log.Printf("[ShedLoad] Failed to look up tier for user %s: %v", user.ID, err)
+		}
+
+		if monitor.ShouldShed(tier) {
+			retryAfter := int(monitor.RetryAfter().Seconds())
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":      "Pronunciation service is busy, please try again shortly",
+				"code":       "SERVICE_OVERLOADED",
+				"retryAfter": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
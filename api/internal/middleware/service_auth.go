@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+)
+
+// AuditActionInternalAuth is the audit log action for a rejected internal request
+const AuditActionInternalAuth = "internal_auth.rejected"
+
+// maxServiceRequestBytes caps the body of an internal request. The body is
+// read before the signature is checked, so unsigned callers must not be able
+// to make the API buffer as much as they like.
+const maxServiceRequestBytes = 4 << 20
+
+// rejectionAuditInterval is how often rejections from one client IP are
+// written to the audit log. Rejections in between are counted, and the count
+// goes on that IP's next audit row, so a caller hammering the internal routes
+// cannot flood the audit log.
+const rejectionAuditInterval = time.Minute
+
+// maxTrackedRejectionIPs caps how many client IPs the rejection sampler
+// remembers. Past it, rejections from new IPs are only logged.
+const maxTrackedRejectionIPs = 10000
+
+// RequireServiceSignature is middleware that only lets through requests signed
+// by another internal service (see client.ServiceSigner). Rejections are
+// written to the audit log, at most once per client IP per
+// rejectionAuditInterval. Bodies over maxServiceRequestBytes get a 413. A
+// nil signer disables the check.
+func RequireServiceSignature(signer *client.ServiceSigner, actor string, audit services.AuditLogger) gin.HandlerFunc {
+	rejections := newRejectionSampler(rejectionAuditInterval, maxTrackedRejectionIPs)
+	return func(c *gin.Context) {
+		if signer == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxServiceRequestBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+				return
+			}
+			log.Printf("[ServiceAuth] Failed to read request body: %v", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+		// Put the body back for the handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err := signer.Verify(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery, c.Request.Header, body); err != nil {
+			ip := c.ClientIP()
+			if audited, suppressed := rejections.allow(ip); audited {
+				audit.Record(&models.AuditLog{
+					Action:    AuditActionInternalAuth,
+					Actor:     actor,
+					Outcome:   models.AuditOutcomeFailure,
+					IPAddress: ip,
+					Details: models.JSONMap{
+						"method":     c.Request.Method,
+						"path":       c.Request.URL.Path,
+						"reason":     err.Error(),
+						"suppressed": suppressed,
+					},
+				})
+			} else if suppressed < 0 {
+				log.Printf("[ServiceAuth] Rejected %s %s from %s: %v", c.Request.Method, c.Request.URL.Path, ip, err)
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid service signature"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rejectionSampler decides which rejected internal requests are audited: the
+// first from each client IP per interval
+type rejectionSampler struct {
+	mu       sync.Mutex
+	interval time.Duration
+	maxIPs   int
+	windows  map[string]*rejectionWindow
+
+	now func() time.Time
+}
+
+// rejectionWindow is one client IP's current audit interval
+type rejectionWindow struct {
+	start      time.Time
+	suppressed int
+}
+
+func newRejectionSampler(interval time.Duration, maxIPs int) *rejectionSampler {
+	return &rejectionSampler{
+		interval: interval,
+		maxIPs:   maxIPs,
+		windows:  make(map[string]*rejectionWindow),
+		now:      time.Now,
+	}
+}
+
+// allow reports whether a rejection from ip should be audited. When it
+// should, it also returns how many rejections from ip were skipped since the
+// last audited one. It returns -1 when ip could not be tracked at all.
+func (s *rejectionSampler) allow(ip string) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if w, ok := s.windows[ip]; ok {
+		if now.Sub(w.start) < s.interval {
+			w.suppressed++
+			return false, w.suppressed
+		}
+		suppressed := w.suppressed
+		s.windows[ip] = &rejectionWindow{start: now}
+		return true, suppressed
+	}
+
+	if len(s.windows) >= s.maxIPs {
+		for key, w := range s.windows {
+			if now.Sub(w.start) >= s.interval {
+				delete(s.windows, key)
+			}
+		}
+		if len(s.windows) >= s.maxIPs {
+			return false, -1
+		}
+	}
+	s.windows[ip] = &rejectionWindow{start: now}
+	return true, 0
+}
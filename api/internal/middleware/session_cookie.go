@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const sessionCookieSecureKey = "sessionCookieSecure"
+
+// SessionCookies tells the auth middleware how to re-send the session cookie
+// when a request slides the session's expiry forward. secure must match the
+// cookie the auth handlers set at login.
+func SessionCookies(secure bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(sessionCookieSecureKey, secure)
+		c.Next()
+	}
+}
+
+// refreshSessionCookie re-sends the session cookie so the browser keeps it
+// until the session's new expiry
+func refreshSessionCookie(c *gin.Context, token string, expiresAt time.Time) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		"session_token",
+		token,
+		int(time.Until(expiresAt).Seconds()),
+		"/",
+		"",
+		c.GetBool(sessionCookieSecureKey),
+		true,
+	)
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail is middleware that, when required, keeps accounts
+// whose email isn't verified out of features that reach other people. It
+// must run after RequireAuth.
+func RequireVerifiedEmail(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && !MustGetUser(c).EmailVerified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Verify your email to use this feature",
+				"code":  "EMAIL_NOT_VERIFIED",
+			})
+			return
+		}
+		c.Next()
+	}
+}
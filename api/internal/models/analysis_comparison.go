@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnalysisComparison is one analysis run by both the primary ML model and
+// the shadow model under evaluation. It holds only scores and timings, for
+// the admin report; the shadow result is never shown to the user.
+type AnalysisComparison struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	MessageID uuid.UUID `gorm:"type:uuid;index;not null" json:"messageId"`
+	Quality   string    `gorm:"type:varchar(20);not null" json:"quality"`
+
+	// "success" or "error", as reported by each model
+	PrimaryStatus string `gorm:"type:varchar(20);not null" json:"primaryStatus"`
+	ShadowStatus  string `gorm:"type:varchar(20);not null" json:"shadowStatus"`
+	ShadowError   string `gorm:"type:varchar(100)" json:"shadowError,omitempty"` // Error code, when the shadow failed
+
+	PrimaryPhonemes int     `gorm:"not null;default:0" json:"primaryPhonemes"`
+	ShadowPhonemes  int     `gorm:"not null;default:0" json:"shadowPhonemes"`
+	PrimaryAccuracy float64 `gorm:"not null;default:0" json:"primaryAccuracy"` // Matched share of phonemes, 0-1
+	ShadowAccuracy  float64 `gorm:"not null;default:0" json:"shadowAccuracy"`
+	// Share of expected phonemes both models judged the same way (matched or
+	// not); nil unless both succeeded
+	Agreement *float64 `json:"agreement,omitempty"`
+
+	PrimaryLatencyMs int64 `gorm:"not null" json:"primaryLatencyMs"`
+	ShadowLatencyMs  int64 `gorm:"not null" json:"shadowLatencyMs"`
+
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new comparisons
+func (a *AnalysisComparison) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// AnalysisComparisonSummary sums the comparisons of one analysis quality
+type AnalysisComparisonSummary struct {
+	Quality        string `json:"quality"`
+	Comparisons    int64  `json:"comparisons"`
+	ShadowFailures int64  `json:"shadowFailures"`
+	// Comparisons where one model failed and the other didn't
+	StatusMismatches int64 `json:"statusMismatches"`
+
+	// Averaged over the comparisons both models succeeded on
+	AvgAgreement     float64 `json:"avgAgreement"`
+	AvgAccuracyDelta float64 `json:"avgAccuracyDelta"` // Shadow minus primary
+
+	AvgPrimaryLatencyMs int64 `json:"avgPrimaryLatencyMs"`
+	AvgShadowLatencyMs  int64 `json:"avgShadowLatencyMs"`
+	P95PrimaryLatencyMs int64 `json:"p95PrimaryLatencyMs"`
+	P95ShadowLatencyMs  int64 `json:"p95ShadowLatencyMs"`
+}
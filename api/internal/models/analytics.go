@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnalyticsEvent is a product analytics event recorded by the database sink
+type AnalyticsEvent struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index;not null" json:"userId"`
+
+	Event      string  `gorm:"type:varchar(100);index:idx_analytics_events_event_created;not null" json:"event"`
+	Properties JSONMap `gorm:"type:jsonb" json:"properties,omitempty"`
+
+	CreatedAt time.Time `gorm:"index:idx_analytics_events_event_created" json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new events
+func (e *AnalyticsEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AudioKeyPrefix starts every recording key in the current scheme:
+// v2/<user|assistant>/<threadID>/<messageID>[-<position>]. The keys carry no
+// extension; the format is stored on the message or chunk instead.
+const AudioKeyPrefix = "v2/"
+
+// UserAudioPrefix is the storage prefix of the learner's recordings
+const UserAudioPrefix = "user/"
+
+// Formats a recording is stored in
+const (
+	AudioFormatWebM = "webm"
+	AudioFormatMP3  = "mp3"
+)
+
+// UserAudioKey is where the recording of a voice message is stored
+func UserAudioKey(threadID, messageID uuid.UUID) string {
+	return fmt.Sprintf("%s%s%s/%s", AudioKeyPrefix, UserAudioPrefix, threadID, messageID)
+}
+
+// ChunkAudioKey is where one recording of a long-form message is stored
+func ChunkAudioKey(threadID, messageID uuid.UUID, position int) string {
+	return fmt.Sprintf("%s-%d", UserAudioKey(threadID, messageID), position)
+}
+
+// AssistantAudioKey is where the spoken reply of an assistant message is stored
+func AssistantAudioKey(threadID, messageID uuid.UUID) string {
+	return fmt.Sprintf("%s%s%s/%s", AudioKeyPrefix, AssistantAudioPrefix, threadID, messageID)
+}
+
+// MigratedAudioKey maps a key of the legacy scheme, which baked the format
+// into the extension (user/<threadID>/<messageID>.webm), to its key in the
+// current scheme and the format to store alongside it. Low-bitrate variants
+// map to the variant of the migrated reply. ok is false for keys that aren't
+// legacy recordings.
+func MigratedAudioKey(key string) (migrated, format string, ok bool) {
+	if !strings.HasPrefix(key, UserAudioPrefix) && !strings.HasPrefix(key, AssistantAudioPrefix) {
+		return "", "", false
+	}
+	if base, found := strings.CutSuffix(key, lowBitrateSuffix); found {
+		return LowBitrateAudioKey(AudioKeyPrefix + base), "ogg", true
+	}
+	ext := path.Ext(key)
+	if ext == "" {
+		return "", "", false
+	}
+	return AudioKeyPrefix + strings.TrimSuffix(key, ext), strings.ToLower(ext[1:]), true
+}
+
+// AudioContentType is the MIME type of a stored format, or "" if unknown
+func AudioContentType(format string) string {
+	switch format {
+	case AudioFormatWebM:
+		return "audio/webm"
+	case AudioFormatMP3:
+		return "audio/mpeg"
+	default:
+		return ""
+	}
+}
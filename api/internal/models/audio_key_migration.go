@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AudioKeyMigration records a recording copied from its legacy key to the
+// current scheme. The legacy object is kept until the record is old enough
+// that no playback URL handed out for it is still in use, then deleted along
+// with the record.
+type AudioKeyMigration struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	LegacyKey  string    `gorm:"type:varchar(500);not null" json:"legacyKey"`
+	Key        string    `gorm:"type:varchar(500);not null" json:"key"`
+	MigratedAt time.Time `gorm:"not null;index" json:"migratedAt"`
+}
+
+// BeforeCreate generates a UUID for new migration records
+func (m *AudioKeyMigration) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
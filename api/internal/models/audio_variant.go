@@ -0,0 +1,21 @@
+package models
+
+import (
+	"path"
+	"strings"
+)
+
+// AssistantAudioPrefix is the storage prefix of the assistant's spoken replies
+const AssistantAudioPrefix = "assistant/"
+
+// lowBitrateSuffix replaces the extension of a reply's key in its variant's
+const lowBitrateSuffix = ".low.ogg"
+
+// LowBitrateAudioKey is where the low-bitrate Opus variant of the assistant
+// reply stored at key goes, or "" for audio that never has one
+func LowBitrateAudioKey(key string) string {
+	if !strings.HasPrefix(strings.TrimPrefix(key, AudioKeyPrefix), AssistantAudioPrefix) {
+		return ""
+	}
+	return strings.TrimSuffix(key, path.Ext(key)) + lowBitrateSuffix
+}
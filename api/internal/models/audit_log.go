@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditOutcome is whether an audited action was allowed
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "success"
+	AuditOutcomeFailure AuditOutcome = "failure"
+)
+
+// AuditLog records a security-relevant action: who attempted it, from where,
+// and whether it was allowed. Rows are append-only.
+type AuditLog struct {
+	ID      uuid.UUID    `gorm:"type:uuid;primary_key" json:"id"`
+	Action  string       `gorm:"type:varchar(100);index;not null" json:"action"`
+	Actor   string       `gorm:"type:varchar(255);not null" json:"actor"`
+	Outcome AuditOutcome `gorm:"type:varchar(20);not null" json:"outcome"`
+
+	IPAddress string  `gorm:"type:varchar(45)" json:"ipAddress,omitempty"`
+	Details   JSONMap `gorm:"type:jsonb" json:"details,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new audit log entries
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserContentKey is a user's data key for content encryption, wrapped by the
+// master key. Deleting it makes the user's encrypted messages unreadable.
+type UserContentKey struct {
+	UserID     uuid.UUID `gorm:"type:uuid;primary_key"`
+	WrappedKey []byte    `gorm:"type:bytea;not null"`
+	CreatedAt  time.Time
+}
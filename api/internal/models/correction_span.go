@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// CorrectionSpan is a stretch of a learner's message that the assistant's
+// reply corrected. Start and End are UTF-16 offsets into the message's
+// Content, End exclusive, so a JavaScript client can slice the text directly.
+type CorrectionSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// CorrectionSpans is a []CorrectionSpan stored as a JSONB array
+type CorrectionSpans []CorrectionSpan
+
+// Scan implements sql.Scanner for reading from the database
+func (c *CorrectionSpans) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+
+	bytes, err := jsonBytes(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, c)
+}
+
+// Value implements driver.Valuer for writing to the database
+func (c CorrectionSpans) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreditDisputeStatus tracks where a dispute is in admin review
+type CreditDisputeStatus string
+
+const (
+	DisputeOpen     CreditDisputeStatus = "open"
+	DisputeRefunded CreditDisputeStatus = "refunded"
+	DisputeRejected CreditDisputeStatus = "rejected"
+)
+
+// CreditDispute is a user's challenge to a credit transaction, queued for admin review
+type CreditDispute struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID        uuid.UUID `gorm:"type:uuid;index;not null" json:"userId"`
+	TransactionID uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"transactionId"`
+
+	Reason string              `gorm:"type:text;not null" json:"reason"`
+	Status CreditDisputeStatus `gorm:"type:varchar(20);default:'open';index" json:"status"`
+
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new disputes
+func (d *CreditDispute) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
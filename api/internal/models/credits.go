@@ -7,9 +7,19 @@ import (
 	"gorm.io/gorm"
 )
 
-// Credit cost per voice message (only input type in this pronunciation app)
+// Default credit cost per voice message. The cost in force comes from
+// services.RuntimeSettings.
 const CreditCostPerMessage = 1
 
+// Default credit cost per typed message
+const CreditCostPerTextMessage = 1
+
+// Default credit cost per started minute of a long-form message
+const LongFormCreditCostPerMinute = 2
+
+// Bonus credits awarded when a thread's conversation goal is completed
+const GoalCompletionBonusCredits = 2
+
 // Credits tracks a user's credit balance
 type Credits struct {
 	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
@@ -21,7 +31,7 @@ type Credits struct {
 
 	// Tracking
 	UsedThisPeriod  int       `gorm:"not null;default:0" json:"usedThisPeriod"`
-	LastRefreshedAt time.Time `json:"lastRefreshedAt"`
+	LastRefreshedAt time.Time `gorm:"index" json:"lastRefreshedAt"`
 
 	// Timestamps
 	CreatedAt time.Time `json:"createdAt"`
@@ -48,6 +58,9 @@ const (
 	TransactionDebit   CreditTransactionType = "debit"
 	TransactionCredit  CreditTransactionType = "credit"
 	TransactionRefresh CreditTransactionType = "refresh"
+	TransactionRefund  CreditTransactionType = "refund"
+	TransactionDemo    CreditTransactionType = "demo"    // A guest's demo allowance, kept apart from paid-for credits
+	TransactionRelease CreditTransactionType = "release" // Held credits given back after their reservation expired
 )
 
 // CreditTransaction records credit balance changes for auditing
@@ -73,3 +86,43 @@ func (t *CreditTransaction) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// CreditReservationStatus is where a reservation is in its life
+type CreditReservationStatus string
+
+const (
+	ReservationHeld     CreditReservationStatus = "held"     // Taken from the balance, work in progress
+	ReservationCaptured CreditReservationStatus = "captured" // Work delivered, recorded as a debit
+	ReservationReleased CreditReservationStatus = "released" // Work failed, given back to the balance
+	ReservationExpired  CreditReservationStatus = "expired"  // Never settled in time, given back to the balance
+	ReservationRefunded CreditReservationStatus = "refunded" // Captured, then refunded because the turn fell short
+)
+
+// CreditReservation holds credits taken from a balance while the work they
+// pay for is done. Capturing it records the debit; releasing it gives the
+// credits back without a debit ever showing in the history. A reservation
+// still held at ExpiresAt, because the turn crashed or its capture or release
+// failed, expires: the credits go back with a release transaction. A
+// captured reservation is refunded at most once.
+type CreditReservation struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index;not null" json:"userId"`
+
+	Amount      int                     `gorm:"not null" json:"amount"`
+	Status      CreditReservationStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Reference   string                  `gorm:"type:varchar(255);index" json:"reference"`
+	Description string                  `gorm:"type:varchar(500)" json:"description"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `gorm:"index" json:"expiresAt"`
+	SettledAt  *time.Time `json:"settledAt,omitempty"`
+	RefundedAt *time.Time `json:"refundedAt,omitempty"`
+}
+
+// BeforeCreate generates a UUID for new reservations
+func (r *CreditReservation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
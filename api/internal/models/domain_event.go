@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DomainEvent is a published domain event as stored by the event log, kept
+// so new projections can be backfilled by replaying history
+type DomainEvent struct {
+	ID      uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	Name    string    `gorm:"type:varchar(100);index;not null" json:"name"`
+	UserID  uuid.UUID `gorm:"type:uuid;index:idx_domain_events_user_occurred;not null" json:"userId"`
+	Payload JSONMap   `gorm:"type:jsonb;not null" json:"payload"`
+
+	OccurredAt time.Time `gorm:"index;index:idx_domain_events_user_occurred;not null" json:"occurredAt"`
+}
+
+// BeforeCreate generates a UUID for new events
+func (e *DomainEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of notification email
+const (
+	EmailDeliveryBilling = "billing" // one billing notification, never capped
+	EmailDeliveryInstant = "instant" // the notifications of one batch window
+	EmailDeliveryDigest  = "digest"  // the daily digest
+)
+
+// EmailDelivery is a notification email sent to a user. Frequency caps and
+// the once-a-day digest are counted from these.
+type EmailDelivery struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index:idx_email_deliveries_user_sent" json:"userId"`
+
+	Kind          string    `gorm:"type:varchar(10);not null" json:"kind"`
+	Notifications int       `gorm:"not null" json:"notifications"` // how many notifications it carried
+	SentAt        time.Time `gorm:"not null;index:idx_email_deliveries_user_sent" json:"sentAt"`
+}
+
+// BeforeCreate generates a UUID for new email deliveries
+func (d *EmailDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
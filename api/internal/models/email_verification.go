@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailVerificationLifetime is how long a verification link works
+const EmailVerificationLifetime = 24 * time.Hour
+
+// EmailVerification is a link sent to confirm that a password account owns
+// its email address. Only a hash of the token is stored; the token itself is
+// only ever in the email.
+type EmailVerification struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;index;not null" json:"userId"`
+	Email     string     `gorm:"type:varchar(255);not null" json:"email"` // The address the link was sent to
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `gorm:"index" json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new verifications
+func (v *EmailVerification) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+// Usable reports whether the link can still verify its address at now
+func (v *EmailVerification) Usable(now time.Time) bool {
+	return v.UsedAt == nil && now.Before(v.ExpiresAt)
+}
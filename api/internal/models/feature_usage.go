@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Features whose usage is recorded for pricing decisions
+const (
+	FeatureVoiceMessage        = "voice_message"
+	FeatureLongFormMessage     = "long_form_message"
+	FeatureTextMessage         = "text_message"
+	FeaturePracticeSession     = "practice_session"
+	FeatureAnkiExport          = "anki_export"
+	FeaturePronunciationReport = "pronunciation_report"
+)
+
+// FeatureUsageEvent is one use of a paid-for feature. Events are rolled up
+// into FeatureUsageDaily and pruned after a retention period.
+type FeatureUsageEvent struct {
+	ID      uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID  uuid.UUID `gorm:"type:uuid;index;not null" json:"userId"`
+	Feature string    `gorm:"type:varchar(50);not null" json:"feature"`
+
+	Credits   int   `gorm:"not null;default:0" json:"credits"` // Credits charged; 0 when the request failed
+	LatencyMs int64 `gorm:"not null" json:"latencyMs"`
+	Success   bool  `gorm:"not null" json:"success"`
+
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new events
+func (e *FeatureUsageEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// FeatureUsageDaily is a feature's usage over one UTC day
+type FeatureUsageDaily struct {
+	Day     time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Feature string    `gorm:"type:varchar(50);primaryKey" json:"feature"`
+
+	Uses         int64 `gorm:"not null" json:"uses"`
+	Failures     int64 `gorm:"not null" json:"failures"`
+	Users        int64 `gorm:"not null" json:"users"` // Distinct users
+	Credits      int64 `gorm:"not null" json:"credits"`
+	AvgLatencyMs int64 `gorm:"not null" json:"avgLatencyMs"`
+	P95LatencyMs int64 `gorm:"not null" json:"p95LatencyMs"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName overrides GORM's plural, "feature_usage_dailies"
+func (FeatureUsageDaily) TableName() string {
+	return "feature_usage_daily"
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyLifetime is how long a key is remembered. A retry after that
+// is processed as a new request.
+const IdempotencyKeyLifetime = 24 * time.Hour
+
+// IdempotencyKey is a request a user sent with an Idempotency-Key header, and
+// the response it got, so a retry of it is answered with that response
+// instead of being processed again
+type IdempotencyKey struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"userId"`
+	Key    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+
+	// RequestPath is the path the key was first sent to; the key can't be
+	// reused for another request
+	RequestPath string `gorm:"type:varchar(500);not null" json:"requestPath"`
+
+	// RequestHash is a hex SHA-256 of the request's body; a retry must send
+	// the same one. Empty for keys stored before it was recorded.
+	RequestHash string `gorm:"type:varchar(64);not null;default:''" json:"-"`
+
+	// The response, once the request has completed. ResponseStatus is 0
+	// while it is being processed.
+	ResponseStatus      int    `gorm:"not null;default:0" json:"responseStatus"`
+	ResponseContentType string `gorm:"type:varchar(100);not null;default:''" json:"-"`
+	ResponseBody        []byte `json:"-"`
+
+	CreatedAt   time.Time  `gorm:"index" json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// BeforeCreate generates a UUID for new keys
+func (k *IdempotencyKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// Completed reports whether the request has a response to replay
+func (k *IdempotencyKey) Completed() bool {
+	return k.ResponseStatus != 0
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InviteCode lets new accounts register while signups are invite-only
+type InviteCode struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Code          string     `gorm:"type:varchar(32);uniqueIndex;not null" json:"code"`
+	UsesRemaining int        `gorm:"not null" json:"usesRemaining"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"` // nil never expires
+	Note          string     `gorm:"type:varchar(200)" json:"note,omitempty"`
+	CreatedBy     *uuid.UUID `gorm:"type:uuid" json:"createdBy,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new invite codes
+func (i *InviteCode) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// WaitlistEntry is someone waiting for an invite
+type WaitlistEntry struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Email        string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	InviteCodeID *uuid.UUID `gorm:"type:uuid" json:"inviteCodeId,omitempty"` // set once converted
+	InvitedAt    *time.Time `gorm:"index" json:"invitedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new waitlist entries
+func (w *WaitlistEntry) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LearnerProfile is what the assistant remembers about a learner across
+// threads. Facts are extracted from conversations in the background and can
+// be edited by the learner. Users without a row have memory on and nothing
+// remembered yet.
+type LearnerProfile struct {
+	UserID uuid.UUID `gorm:"type:uuid;primary_key" json:"-"`
+
+	// Disabled turns memory off: nothing is extracted or added to prompts
+	Disabled bool `gorm:"not null;default:false" json:"disabled"`
+
+	PreferredName     string     `gorm:"type:varchar(100)" json:"preferredName"`
+	Interests         StringList `gorm:"type:jsonb" json:"interests"`
+	RecurringMistakes StringList `gorm:"type:jsonb" json:"recurringMistakes"`
+
+	// ExtractedAt is when conversations were last read for new facts;
+	// messages sent before it have already been considered
+	ExtractedAt *time.Time `json:"extractedAt,omitempty"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Empty reports whether nothing is remembered
+func (p *LearnerProfile) Empty() bool {
+	return p.PreferredName == "" && len(p.Interests) == 0 && len(p.RecurringMistakes) == 0
+}
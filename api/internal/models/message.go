@@ -21,9 +21,9 @@ func (j *JSONMap) Scan(value interface{}) error {
 		return nil
 	}
 
-	bytes, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
+	bytes, err := jsonBytes(value)
+	if err != nil {
+		return err
 	}
 
 	return json.Unmarshal(bytes, j)
@@ -37,22 +37,134 @@ func (j JSONMap) Value() (driver.Value, error) {
 	return json.Marshal(j)
 }
 
+// StringList is a []string stored as a JSONB array
+type StringList []string
+
+// Scan implements sql.Scanner for reading from the database
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, err := jsonBytes(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
+// Value implements driver.Valuer for writing to the database
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// jsonBytes returns the raw JSON of a scanned column. database/sql hands over
+// []byte; pgx hands jsonb to sql.Scanner implementations as a string.
+func jsonBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, errors.New("type assertion to []byte failed")
+	}
+}
+
+// MessageKindLongForm marks a user message stitched together from several
+// recordings
+const MessageKindLongForm = "long_form"
+
+// MessageKindText marks a user message that was typed rather than spoken
+const MessageKindText = "text"
+
+// Tones the assistant can speak a reply in
+const (
+	ToneNeutral     = "neutral"
+	ToneCheerful    = "cheerful"
+	ToneCalm        = "calm"
+	ToneQuestioning = "questioning"
+)
+
 type Message struct {
 	ID                   uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
 	ThreadID             uuid.UUID `gorm:"type:uuid;index;not null" json:"threadId"`
 	Role                 string    `gorm:"type:varchar(20);not null" json:"role"` // "user" or "assistant"
 	Content              string    `gorm:"type:text;not null" json:"content"`
-	AudioURL             *string   `gorm:"type:varchar(500)" json:"audioUrl,omitempty"`
+	AudioURL             *string   `gorm:"type:varchar(500);index" json:"audioUrl,omitempty"`
+	AudioFormat          string    `gorm:"type:varchar(10)" json:"audioFormat,omitempty"` // e.g. AudioFormatWebM; empty for legacy keys, whose extension says
 	AudioDurationSeconds *float64  `gorm:"type:decimal(10,2)" json:"audioDurationSeconds,omitempty"`
 	HasAudio             bool      `gorm:"default:false" json:"hasAudio"`
 	Timestamp            time.Time `json:"timestamp"`
 
+	// Suggested learner replies (assistant messages in threads with SuggestReplies on)
+	SuggestedReplies StringList `gorm:"type:jsonb" json:"suggestedReplies,omitempty"`
+
+	// Difficulty adaptation applied to the reply (assistant messages), e.g.
+	// {"level": "simplify", "reasons": [...], "speechRate": 0.85}
+	Adaptation JSONMap `gorm:"type:jsonb" json:"adaptation,omitempty"`
+
+	// Text the TTS audio was synthesized from, when normalization changed it
+	// ("three quarters" for "3/4"); audio alignment uses this, not Content
+	SpokenText *string `gorm:"type:text" json:"spokenText,omitempty"`
+
+	// Transcript as Whisper returned it, when punctuation and casing were
+	// restored for Content (user messages)
+	RawTranscript *string `gorm:"type:text" json:"rawTranscript,omitempty"`
+
+	// When the user last corrected the transcript in Content (user messages)
+	TranscriptCorrectedAt *time.Time `json:"transcriptCorrectedAt,omitempty"`
+
+	// Practice line the user was asked to say (empty in free conversation)
+	ExpectedText *string `gorm:"type:text" json:"expectedText,omitempty"`
+
+	// Tone the assistant meant the reply to be spoken in (assistant
+	// messages), e.g. ToneCheerful
+	Tone string `gorm:"type:varchar(20)" json:"tone,omitempty"`
+
+	// Parts of the learner's message CorrectedMessageID that the reply
+	// corrects (assistant messages), so the UI can highlight them. Only
+	// offsets are kept: the text stays in that message, sealed if encrypted.
+	Corrections        CorrectionSpans `gorm:"type:jsonb" json:"corrections,omitempty"`
+	CorrectedMessageID *uuid.UUID      `gorm:"type:uuid" json:"correctedMessageId,omitempty"`
+
+	// Kind is MessageKindLongForm for a monologue sent as several recordings
+	// (see Chunks), MessageKindText for a typed message; empty for an
+	// ordinary turn
+	Kind string `gorm:"type:varchar(20)" json:"kind,omitempty"`
+
+	// Recordings of a long-form message in order. Each is analyzed on its
+	// own; PronunciationAnalysis holds the combined report. Not loaded with
+	// the message.
+	Chunks []MessageChunk `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"chunks,omitempty"`
+
+	// What the analysis added to the user's phoneme stats. Not loaded with
+	// the message.
+	PhonemeStatsSnapshot *PhonemeStatsSnapshot `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"-"`
+
 	// Pronunciation analysis fields (for user messages)
-	PronunciationStatus    string     `gorm:"type:varchar(20);default:'none'" json:"pronunciationStatus"` // "none", "pending", "complete", "failed"
+	PronunciationStatus    string     `gorm:"type:varchar(20);default:'none'" json:"pronunciationStatus"` // "none", "pending", "complete", "failed", "skipped_divergent"
 	PronunciationAnalysis  JSONMap    `gorm:"type:jsonb" json:"pronunciationAnalysis,omitempty"`          // Full analysis JSON object
 	PronunciationError     *string    `gorm:"type:text" json:"pronunciationError,omitempty"`              // Error message if failed
 	PronunciationUpdatedAt *time.Time `json:"pronunciationUpdatedAt,omitempty"`
 
+	// Confidence (0-1) in the analysis, from audio quality and match ratio.
+	// Low-confidence results are shown but excluded from phoneme stats.
+	PronunciationConfidence    *float64 `json:"pronunciationConfidence,omitempty"`
+	PronunciationLowConfidence bool     `gorm:"default:false" json:"pronunciationLowConfidence"`
+
+	// Model quality that produced the analysis ("fast" or "accurate"), kept
+	// outside the analysis so it stays readable when content is encrypted
+	PronunciationQuality string `gorm:"type:varchar(10)" json:"pronunciationQuality,omitempty"`
+
+	// How many times the user had a failed analysis run again
+	PronunciationRetries int `gorm:"default:0" json:"pronunciationRetries"`
+
 }
 
 func (m *Message) BeforeCreate(tx *gorm.DB) error {
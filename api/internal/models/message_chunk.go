@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MessageChunk is one recording of a long-form message. The message holds
+// the stitched transcript and the combined pronunciation report; each chunk
+// keeps its own audio, transcript and analysis.
+type MessageChunk struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	MessageID       uuid.UUID `gorm:"type:uuid;index;not null" json:"messageId"`
+	Position        int       `gorm:"not null" json:"position"` // 0-based order within the message
+	Transcript      string    `gorm:"type:text;not null" json:"transcript"`
+	AudioURL        *string   `gorm:"type:varchar(500);index" json:"audioUrl,omitempty"`
+	AudioFormat     string    `gorm:"type:varchar(10)" json:"audioFormat,omitempty"`
+	DurationSeconds float64   `gorm:"type:decimal(10,2);not null" json:"durationSeconds"`
+
+	// Pronunciation analysis of this chunk alone
+	PronunciationStatus        string     `gorm:"type:varchar(20);default:'pending'" json:"pronunciationStatus"` // "pending", "complete", "failed"
+	PronunciationAnalysis      JSONMap    `gorm:"type:jsonb" json:"pronunciationAnalysis,omitempty"`
+	PronunciationError         *string    `gorm:"type:text" json:"pronunciationError,omitempty"`
+	PronunciationConfidence    *float64   `json:"pronunciationConfidence,omitempty"`
+	PronunciationLowConfidence bool       `gorm:"default:false" json:"pronunciationLowConfidence"`
+	PronunciationQuality       string     `gorm:"type:varchar(10)" json:"pronunciationQuality,omitempty"` // "fast" or "accurate"
+	PronunciationUpdatedAt     *time.Time `json:"pronunciationUpdatedAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (c *MessageChunk) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package models
+
+// All returns every persisted model in migration order.
+// Order matters for foreign keys: parents must come before children.
+func All() []interface{} {
+	return []interface{}{
+		&User{},
+		&Session{},
+		&EmailVerification{},
+		&UserSettings{},
+		&UserContentKey{},
+		&LearnerProfile{},
+		&StatsBadge{},
+		&UserStreak{},
+		&Thread{},
+		&Message{},
+		&MessageChunk{},
+		&ThreadReadState{},
+		&PracticeSession{},
+		&ThreadShare{},
+		&SafetyIncident{},
+		&Subscription{},
+		&Credits{},
+		&CreditTransaction{},
+		&CreditDispute{},
+		&PhonemeStats{},
+		&PhonemeSubstitution{},
+		&PhonemeStatsSnapshot{},
+		&Notification{},
+		&AnalyticsEvent{},
+		&FeatureUsageEvent{},
+		&FeatureUsageDaily{},
+		&DomainEvent{},
+		&AuditLog{},
+		&RuntimeSetting{},
+		&SignupSignal{},
+		&InviteCode{},
+		&WaitlistEntry{},
+		&WarehouseWatermark{},
+		&ReferenceAudio{},
+		&AudioKeyMigration{},
+		&AnalysisComparison{},
+		&EmailDelivery{},
+		&IdempotencyKey{},
+		&CreditReservation{},
+		&PendingStorageDeletion{},
+	}
+}
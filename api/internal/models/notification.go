@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationType identifies what a notification is about
+type NotificationType string
+
+const (
+	NotificationGoalCompleted         NotificationType = "goal_completed"
+	NotificationSubscriptionEnding    NotificationType = "subscription_ending"
+	NotificationSubscriptionDowngrade NotificationType = "subscription_downgraded"
+	NotificationPaymentFailed         NotificationType = "payment_failed"
+	NotificationExportReady           NotificationType = "export_ready"
+	NotificationExportFailed          NotificationType = "export_failed"
+	NotificationCreditsLow            NotificationType = "credits_low"
+	NotificationStreakMilestone       NotificationType = "streak_milestone"
+)
+
+// Email categories of notifications. Billing is always emailed straight
+// away; users choose how the others reach them (see UserSettings).
+const (
+	EmailCategoryBilling      = "billing"
+	EmailCategoryAchievements = "achievements"
+	EmailCategoryUpdates      = "updates"
+)
+
+// EmailCategory is the email category of the notification type, or "" if
+// notifications of the type aren't emailed. Payment failures aren't: dunning
+// emails them itself, with the link to update the card.
+func (t NotificationType) EmailCategory() string {
+	switch t {
+	case NotificationSubscriptionEnding, NotificationSubscriptionDowngrade:
+		return EmailCategoryBilling
+	case NotificationGoalCompleted, NotificationStreakMilestone:
+		return EmailCategoryAchievements
+	case NotificationExportReady, NotificationExportFailed, NotificationCreditsLow:
+		return EmailCategoryUpdates
+	default:
+		return ""
+	}
+}
+
+// Email delivery states of a notification
+const (
+	EmailStatusNone    = "none"    // not emailed: no email configured, or a type that isn't emailed
+	EmailStatusPending = "pending" // waiting for the notification email worker
+	EmailStatusSent    = "sent"
+	EmailStatusSkipped = "skipped" // category turned off, or read in the app before it went out
+)
+
+// Notification is an in-app message shown to a user
+type Notification struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index:idx_notifications_user_created;not null" json:"-"`
+
+	Type  NotificationType `gorm:"type:varchar(50);not null" json:"type"`
+	Title string           `gorm:"type:varchar(255);not null" json:"title"`
+	Body  string           `gorm:"type:text" json:"body"`
+	Data  JSONMap          `gorm:"type:jsonb" json:"data,omitempty"` // Type-specific payload (e.g. threadId)
+
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+	CreatedAt time.Time  `gorm:"index:idx_notifications_user_created" json:"createdAt"`
+
+	// EmailStatus tracks the notification's email, one of the EmailStatus
+	// constants
+	EmailStatus string `gorm:"type:varchar(10);not null;default:'none';index:idx_notifications_email_pending,where:email_status = 'pending'" json:"-"`
+}
+
+// BeforeCreate generates a UUID for new notifications
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
@@ -1,19 +1,26 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-// PhonemeStats tracks per-user accuracy for each phoneme (IPA symbol)
+// PhonemeStats tracks per-user accuracy for each phoneme (IPA symbol) of
+// each language practised
 type PhonemeStats struct {
 	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_phoneme_stats_user_phoneme" json:"userId"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_phoneme_stats_user_language_phoneme" json:"userId"`
+
+	// The thread language the phoneme was practised in (e.g., "en", "es").
+	// The same symbol in two languages is two different sounds to learn.
+	Language string `gorm:"type:varchar(8);not null;default:'en';uniqueIndex:idx_phoneme_stats_user_language_phoneme" json:"language"`
 
 	// The IPA phoneme symbol (e.g., "θ", "ɪ", "r")
-	Phoneme string `gorm:"type:varchar(10);not null;uniqueIndex:idx_phoneme_stats_user_phoneme" json:"phoneme"`
+	Phoneme string `gorm:"type:varchar(10);not null;uniqueIndex:idx_phoneme_stats_user_language_phoneme" json:"phoneme"`
 
 	// Aggregate statistics
 	TotalAttempts int `gorm:"not null;default:0" json:"totalAttempts"`
@@ -45,13 +52,16 @@ func (p *PhonemeStats) Accuracy() float64 {
 // e.g., user often says /t/ instead of /θ/
 type PhonemeSubstitution struct {
 	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_phoneme_subs_user_expected_actual" json:"userId"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_phoneme_subs_user_language_expected_actual" json:"userId"`
+
+	// The thread language, as in PhonemeStats
+	Language string `gorm:"type:varchar(8);not null;default:'en';uniqueIndex:idx_phoneme_subs_user_language_expected_actual" json:"language"`
 
 	// The expected phoneme (what should have been said)
-	ExpectedPhoneme string `gorm:"type:varchar(10);not null;uniqueIndex:idx_phoneme_subs_user_expected_actual" json:"expectedPhoneme"`
+	ExpectedPhoneme string `gorm:"type:varchar(10);not null;uniqueIndex:idx_phoneme_subs_user_language_expected_actual" json:"expectedPhoneme"`
 
 	// The actual phoneme (what was said instead)
-	ActualPhoneme string `gorm:"type:varchar(10);not null;uniqueIndex:idx_phoneme_subs_user_expected_actual" json:"actualPhoneme"`
+	ActualPhoneme string `gorm:"type:varchar(10);not null;uniqueIndex:idx_phoneme_subs_user_language_expected_actual" json:"actualPhoneme"`
 
 	// How many times this substitution occurred
 	OccurrenceCount int `gorm:"not null;default:1" json:"occurrenceCount"`
@@ -68,3 +78,50 @@ func (p *PhonemeSubstitution) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// PhonemeCounts is one phoneme's share of a PhonemeTally
+type PhonemeCounts struct {
+	TotalAttempts int `json:"totalAttempts"`
+	CorrectCount  int `json:"correctCount"`
+	DeletionCount int `json:"deletionCount"`
+}
+
+// PhonemeTally is what one or more analyses add to a user's phoneme stats:
+// counts per expected phoneme, and substitutions per expected and actual
+// phoneme. Stored as JSONB.
+type PhonemeTally struct {
+	Language      string                    `json:"language,omitempty"` // Empty for tallies kept before stats had languages, which were English
+	Phonemes      map[string]PhonemeCounts  `json:"phonemes"`
+	Substitutions map[string]map[string]int `json:"substitutions"`
+}
+
+// Scan implements sql.Scanner for reading from the database
+func (t *PhonemeTally) Scan(value interface{}) error {
+	if value == nil {
+		*t = PhonemeTally{}
+		return nil
+	}
+
+	bytes, err := jsonBytes(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, t)
+}
+
+// Value implements driver.Valuer for writing to the database
+func (t PhonemeTally) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+// PhonemeStatsSnapshot records what the analysis of one message added to its
+// user's PhonemeStats and PhonemeSubstitutions, so that exactly that can be
+// taken back out when the transcript is corrected and the message re-analyzed.
+// Long-form messages have one snapshot covering all their chunks.
+type PhonemeStatsSnapshot struct {
+	MessageID uuid.UUID    `gorm:"type:uuid;primary_key" json:"messageId"`
+	UserID    uuid.UUID    `gorm:"type:uuid;index;not null" json:"userId"`
+	Tally     PhonemeTally `gorm:"type:jsonb;not null" json:"tally"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
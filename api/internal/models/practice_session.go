@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Practice session timer limits, in minutes
+const (
+	DefaultPracticeSessionMinutes = 10
+	MinPracticeSessionMinutes     = 1
+	MaxPracticeSessionMinutes     = 60
+)
+
+// PracticeSession is a timed stretch of practice in one thread. It groups the
+// messages sent in the thread between StartedAt and EndedAt; a thread has at
+// most one open session at a time.
+type PracticeSession struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID   uuid.UUID `gorm:"type:uuid;index;not null" json:"-"`
+	ThreadID uuid.UUID `gorm:"type:uuid;index;not null" json:"threadId"`
+
+	// The timer: the session ends itself at TimerEndsAt if the user doesn't
+	// end it sooner
+	PlannedMinutes int       `gorm:"not null" json:"plannedMinutes"`
+	StartedAt      time.Time `gorm:"not null" json:"startedAt"`
+	TimerEndsAt    time.Time `gorm:"not null" json:"timerEndsAt"`
+
+	// Set together when the session ends
+	EndedAt *time.Time              `json:"endedAt,omitempty"`
+	Summary *PracticeSessionSummary `gorm:"type:jsonb" json:"summary,omitempty"`
+
+	CreatedAt time.Time `json:"-"`
+}
+
+func (s *PracticeSession) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// PracticeSessionSummary is how a practice session went. Stored as JSONB.
+type PracticeSessionSummary struct {
+	DurationSeconds int `json:"durationSeconds"`
+	// User messages sent during the session
+	Turns int `json:"turns"`
+	// Percent of phonemes pronounced correctly across the turns with a
+	// confident analysis; nil when none were scored
+	Accuracy    *float64 `json:"accuracy"`
+	ScoredTurns int      `json:"scoredTurns"`
+	// Words the user said for the first time, in the order they were said
+	NewVocabulary StringList `json:"newVocabulary"`
+}
+
+// Scan implements sql.Scanner for reading from the database
+func (s *PracticeSessionSummary) Scan(value interface{}) error {
+	bytes, err := jsonBytes(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
+// Value implements driver.Valuer for writing to the database
+func (s PracticeSessionSummary) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReferenceAudio is a synthesized clip of example text, such as a drill word,
+// stored once and reused by everything that plays the same text in the same
+// voice and at the same speed
+type ReferenceAudio struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	Text     string    `gorm:"type:text;not null;uniqueIndex:idx_reference_audio_clip" json:"text"`
+	Language string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_reference_audio_clip" json:"language"`
+	Voice    string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_reference_audio_clip" json:"voice"` // TTS backend and voice, e.g. "openai:alloy"
+	Speed    float64   `gorm:"not null;uniqueIndex:idx_reference_audio_clip" json:"speed"`
+
+	AudioKey string `gorm:"type:varchar(500);not null" json:"audioKey"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new reference clips
+func (r *ReferenceAudio) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// ReferenceAudioPrefix is the storage prefix of reference clips. They belong
+// to no one, so any signed-in user may play them.
+const ReferenceAudioPrefix = "reference/"
+
+// ReferenceAudioKey is where a clip is stored: derived from what it says and
+// how, so two instances synthesizing the same clip write the same object
+func ReferenceAudioKey(text, language, voice string, speed float64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%.2f", text, language, voice, speed)))
+	return ReferenceAudioPrefix + hex.EncodeToString(sum[:16]) + ".mp3"
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuntimeSetting overrides one tunable (see services.RuntimeSettings) without
+// a restart. Value is JSON; keys without a row use the built-in default.
+type RuntimeSetting struct {
+	Key       string     `gorm:"type:varchar(100);primary_key" json:"key"`
+	Value     string     `gorm:"type:text;not null" json:"value"`
+	UpdatedBy *uuid.UUID `gorm:"type:uuid" json:"updatedBy,omitempty"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
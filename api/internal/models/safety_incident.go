@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SafetyIncidentKind is which generated text was withheld
+type SafetyIncidentKind string
+
+const (
+	SafetyIncidentResponse   SafetyIncidentKind = "response"
+	SafetyIncidentSuggestion SafetyIncidentKind = "suggestion"
+)
+
+// SafetyIncident records generated text that failed the output safety check
+// and was never spoken or stored. The text itself isn't kept.
+type SafetyIncident struct {
+	ID       uuid.UUID          `gorm:"type:uuid;primary_key" json:"id"`
+	ThreadID uuid.UUID          `gorm:"type:uuid;index;not null" json:"threadId"`
+	Kind     SafetyIncidentKind `gorm:"type:varchar(20);not null" json:"kind"`
+
+	// Source is the check that flagged it: "rules" or "moderation"
+	Source     string     `gorm:"type:varchar(20);not null" json:"source"`
+	Categories StringList `gorm:"type:jsonb" json:"categories"`
+	Attempt    int        `gorm:"not null" json:"attempt"`
+
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new incidents
+func (i *SafetyIncident) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
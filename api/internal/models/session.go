@@ -1,6 +1,8 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,6 +35,13 @@ type Session struct {
 	CreatedAt time.Time
 }
 
+// PublicID identifies the session to its owner without giving away the
+// token, which would let anyone holding it sign in as them
+func (s *Session) PublicID() string {
+	sum := sha256.Sum256([]byte(s.ID))
+	return hex.EncodeToString(sum[:8])
+}
+
 // IsExpired checks if the session has passed its expiration time
 func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SignupVerdict is how a new account's trial was handled after duplicate checks
+type SignupVerdict string
+
+const (
+	SignupClear   SignupVerdict = "clear"   // full trial credits
+	SignupReduced SignupVerdict = "reduced" // probable duplicate; reduced trial credits
+	SignupReview  SignupVerdict = "review"  // likely duplicate; trial credits held for manual review
+)
+
+// SignupSignal records what a new account was created from, so repeat
+// signups from the same device or network can be spotted. One row per user.
+type SignupSignal struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"userId"`
+	Method string    `gorm:"type:varchar(20);not null" json:"method"` // password, google, github
+
+	IPAddress       string  `gorm:"type:varchar(45);index" json:"ipAddress"`
+	UserAgent       string  `gorm:"type:varchar(500)" json:"userAgent"`
+	FingerprintHash *string `gorm:"type:varchar(128);index" json:"fingerprintHash,omitempty"` // client-side device hash, optional
+
+	Score           int           `gorm:"not null;default:0" json:"score"`
+	Verdict         SignupVerdict `gorm:"type:varchar(20);not null;index" json:"verdict"`
+	Reasons         JSONMap       `gorm:"type:jsonb" json:"reasons,omitempty"` // {"reasons": [...], "matchedUserIds": [...]}
+	CreditsWithheld int           `gorm:"not null;default:0" json:"creditsWithheld"`
+
+	// Manual review
+	ReviewedBy     *uuid.UUID `gorm:"type:uuid" json:"reviewedBy,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewedAt,omitempty"`
+	CreditsGranted bool       `gorm:"not null;default:false" json:"creditsGranted"`
+
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// BeforeCreate generates a UUID for new signup signals
+func (s *SignupSignal) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
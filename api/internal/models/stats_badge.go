@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatsBadge is a user's opt-in public progress badge. Anyone with the token
+// can read the badge, so it only ever exposes aggregate stats. Revoking the
+// badge deletes the row; enabling it again issues a new token.
+type StatsBadge struct {
+	UserID uuid.UUID `gorm:"type:uuid;primary_key" json:"-"`
+	Token  string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"token"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PendingStorageDeletion is a stored object, or with Prefix every object
+// under a prefix, that belonged to a deleted account and is still to be
+// deleted from storage. Rows are written in the transaction that deletes the
+// account and removed once the objects are gone, so a restart or a failing
+// storage backend can't leave them behind unrecorded.
+type PendingStorageDeletion struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index;not null" json:"userId"` // The deleted account; not a foreign key
+	Key    string    `gorm:"type:varchar(500);not null" json:"key"`
+	Prefix bool      `gorm:"not null;default:false" json:"prefix"`
+
+	// Failed attempts so far, and the last error
+	Attempts  int    `gorm:"not null;default:0" json:"attempts"`
+	LastError string `gorm:"type:varchar(500)" json:"lastError,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// BeforeCreate generates a UUID for new pending deletions
+func (d *PendingStorageDeletion) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
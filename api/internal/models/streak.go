@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserStreak counts the consecutive days, in the user's timezone, on which
+// they have sent a voice message. LastActiveDay is that local date.
+type UserStreak struct {
+	UserID        uuid.UUID `gorm:"type:uuid;primary_key" json:"-"`
+	CurrentDays   int       `gorm:"not null" json:"currentDays"`
+	LongestDays   int       `gorm:"not null" json:"longestDays"`
+	LastActiveDay time.Time `gorm:"type:date;not null" json:"lastActiveDay"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
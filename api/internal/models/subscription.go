@@ -16,20 +16,54 @@ const (
 	TierPro   SubscriptionTier = "pro"
 )
 
-// TierCredits defines how many credits each tier gets per month
+// Rank orders tiers from free up, to tell upgrades from downgrades
+func (t SubscriptionTier) Rank() int {
+	switch t {
+	case TierBasic:
+		return 1
+	case TierPro:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// TierCredits defines how many credits each tier gets per month by default.
+// The values in force come from services.RuntimeSettings.
 var TierCredits = map[SubscriptionTier]int{
 	TierFree:  20,
 	TierBasic: 400,  // Increased from 200
 	TierPro:   1200, // Increased from 600
 }
 
+// TierLimit caps how much a tier can keep stored, and how long one thread
+// can run before the assistant wraps it up. Zero means unlimited.
+type TierLimit struct {
+	MaxThreads  int `json:"maxThreads"`  // threads, archived included
+	MaxMessages int `json:"maxMessages"` // voice messages across all threads
+
+	MaxThreadMessages int `json:"maxThreadMessages"` // learner messages in one thread
+	MaxThreadMinutes  int `json:"maxThreadMinutes"`  // minutes of learner speech in one thread
+}
+
+// TierLimits defines each tier's default storage limits (the values in force
+// come from services.RuntimeSettings). They are soft: reaching one blocks new
+// threads or messages, but nothing already stored is removed (e.g. after a
+// downgrade), and deleting threads frees room again.
+var TierLimits = map[SubscriptionTier]TierLimit{
+	TierFree:  {MaxThreads: 20, MaxMessages: 500, MaxThreadMessages: 40, MaxThreadMinutes: 15},
+	TierBasic: {MaxThreads: 500, MaxMessages: 20000, MaxThreadMessages: 100, MaxThreadMinutes: 45},
+	TierPro:   {MaxThreadMessages: 200, MaxThreadMinutes: 90},
+}
+
 // Subscription tracks a user's Stripe subscription status
 type Subscription struct {
 	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
 	UserID uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"userId"`
 
-	// Stripe IDs
-	StripeCustomerID     string  `gorm:"type:varchar(255);uniqueIndex" json:"-"`
+	// Stripe IDs. The customer is empty for subscriptions an admin created
+	// to override the tier of a user who never went to checkout.
+	StripeCustomerID     string  `gorm:"type:varchar(255);index" json:"-"`
 	StripeSubscriptionID *string `gorm:"type:varchar(255);uniqueIndex" json:"-"`
 	StripePriceID        *string `gorm:"type:varchar(255)" json:"-"`
 
@@ -42,6 +76,35 @@ type Subscription struct {
 	CurrentPeriodEnd   *time.Time `json:"currentPeriodEnd,omitempty"`
 	CancelAtPeriodEnd  bool       `gorm:"default:false" json:"cancelAtPeriodEnd"`
 
+	// A downgrade waits for the end of the period already paid for:
+	// PendingTier replaces Tier at PendingTierAt. Cleared if the user goes
+	// back to their current plan or cancels.
+	PendingTier   *SubscriptionTier `gorm:"type:varchar(50)" json:"pendingTier,omitempty"`
+	PendingTierAt *time.Time        `json:"pendingTierAt,omitempty"`
+
+	// TierOverride is set by an admin (comp accounts, make-goods) and replaces
+	// Tier for limits, queue lanes and the credit allowance until cleared or
+	// the user pays for a plan. Tier keeps following Stripe underneath.
+	TierOverride *SubscriptionTier `gorm:"type:varchar(50)" json:"tierOverride,omitempty"`
+
+	// Retention grace after cancellation: the user is on the free tier but keeps
+	// read-only access to GraceTier features until GraceEndsAt, when the
+	// remaining downgrade (credit allowance) is applied
+	GraceTier   *SubscriptionTier `gorm:"type:varchar(50)" json:"graceTier,omitempty"`
+	GraceEndsAt *time.Time        `gorm:"index" json:"graceEndsAt,omitempty"`
+
+	// Dunning after a failed renewal: when the first failed charge of the
+	// current run happened, and how many reminders have gone out since.
+	// Cleared once a payment goes through or the plan is cancelled.
+	PaymentFailedAt      *time.Time `gorm:"index" json:"paymentFailedAt,omitempty"`
+	DunningRemindersSent int        `gorm:"default:0" json:"-"`
+
+	// Billing address country (ISO 3166-1 alpha-2) and Stripe Tax outcome
+	// from checkout: complete, failed or requires_location_inputs. TaxStatus
+	// is empty when tax wasn't collected.
+	BillingCountry string `gorm:"type:varchar(2)" json:"billingCountry,omitempty"`
+	TaxStatus      string `gorm:"type:varchar(30)" json:"taxStatus,omitempty"`
+
 	// Timestamps
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
@@ -55,11 +118,30 @@ func (s *Subscription) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// EffectiveTier returns the tier the user gets: the admin override if there
+// is one, otherwise the billed tier
+func (s *Subscription) EffectiveTier() SubscriptionTier {
+	if s.TierOverride != nil {
+		return *s.TierOverride
+	}
+	return s.Tier
+}
+
+// TierChangeDue returns true if a scheduled downgrade should now take effect
+func (s *Subscription) TierChangeDue(now time.Time) bool {
+	return s.PendingTier != nil && (s.PendingTierAt == nil || !now.Before(*s.PendingTierAt))
+}
+
 // IsPaid returns true if the subscription is a paid tier
 func (s *Subscription) IsPaid() bool {
 	return s.Tier == TierBasic || s.Tier == TierPro
 }
 
+// InGracePeriod returns true if a cancelled paid plan is still in its retention window
+func (s *Subscription) InGracePeriod(now time.Time) bool {
+	return s.GraceTier != nil && s.GraceEndsAt != nil && now.Before(*s.GraceEndsAt)
+}
+
 // IsActive returns true if the subscription is in good standing
 func (s *Subscription) IsActive() bool {
 	return s.Status == "active"
@@ -12,8 +12,65 @@ type Thread struct {
 	UserID     uuid.UUID  `gorm:"type:uuid;index;not null" json:"-"` // Owner of the thread
 	Name       *string    `gorm:"type:varchar(255)" json:"name"`
 	ArchivedAt *time.Time `gorm:"index" json:"archivedAt,omitempty"`
-	Messages   []Message  `gorm:"foreignKey:ThreadID;constraint:OnDelete:CASCADE" json:"messages"`
-	CreatedAt  time.Time  `json:"createdAt"`
+
+	// Conversation goal set by the learner, e.g. "order food and ask for the bill"
+	Goal            *string    `gorm:"type:text" json:"goal,omitempty"`
+	GoalCompletedAt *time.Time `json:"goalCompletedAt,omitempty"`
+
+	// Generate three suggested learner replies after each assistant turn
+	SuggestReplies bool `gorm:"default:false" json:"suggestReplies"`
+
+	// Speech-to-speech practice: voice turns answer with audio only, and the
+	// learner reveals a message's text when they want it. The text is
+	// stored and shown in the thread as usual.
+	SpeechOnly bool `gorm:"default:false" json:"speechOnly"`
+
+	// ISO 639-1 code of the language practised (e.g. "es"): what the learner
+	// is transcribed, answered and scored in. Empty means
+	// services.DefaultLanguage.
+	Language string `gorm:"type:varchar(8)" json:"language,omitempty"`
+
+	// BCP 47 locale set by the frontend (e.g. "es-MX"); decides how numbers
+	// and dates in replies are read aloud. Empty means the language's default.
+	Locale string `gorm:"type:varchar(35)" json:"locale,omitempty"`
+
+	// Per-thread overrides of the user's reply length and speaking rate;
+	// nil uses UserSettings
+	ReplyLength *string  `gorm:"type:varchar(10)" json:"replyLength,omitempty"`
+	SpeechRate  *float64 `json:"speechRate,omitempty"`
+
+	// Set once the thread reached its tier's message or speaking-time cap
+	// and the assistant wrapped up; no more messages can be sent to it
+	EndedAt *time.Time `json:"endedAt,omitempty"`
+
+	// The ended thread this one carries on from, if any
+	ContinuedFromID *uuid.UUID `gorm:"type:uuid;index" json:"continuedFromId,omitempty"`
+
+	Messages   []Message         `gorm:"foreignKey:ThreadID;constraint:OnDelete:CASCADE" json:"messages"`
+	ReadStates []ThreadReadState `gorm:"foreignKey:ThreadID;constraint:OnDelete:CASCADE" json:"-"`
+	Sessions   []PracticeSession `gorm:"foreignKey:ThreadID;constraint:OnDelete:CASCADE" json:"-"`
+	Shares     []ThreadShare     `gorm:"foreignKey:ThreadID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt  time.Time         `json:"createdAt"`
+}
+
+// ThreadPreviewLength is how many characters of the last message a thread
+// summary includes
+const ThreadPreviewLength = 120
+
+// ThreadSummary is a thread as shown in the thread list, with its latest activity
+type ThreadSummary struct {
+	Thread
+
+	// Start of the newest message; nil for a thread with no messages
+	LastMessagePreview *string `json:"lastMessagePreview"`
+	// Time of the newest message, or when the thread was created
+	LastActivityAt time.Time `json:"lastActivityAt"`
+	MessageCount   int64     `json:"messageCount"`
+
+	// Replies and pronunciation results that arrived after the user last
+	// read the thread on any device; 0 for threads never marked read
+	UnreadCount int64      `json:"unreadCount"`
+	LastReadAt  *time.Time `json:"lastReadAt"`
 }
 
 func (t *Thread) BeforeCreate(tx *gorm.DB) error {
@@ -28,4 +85,4 @@ func (t *Thread) AfterFind(tx *gorm.DB) error {
 		t.Messages = []Message{}
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThreadReadState is how far a user has read a thread, shared by all of
+// their devices. Threads have a single owner today; keying by user as well
+// leaves room for shared threads.
+type ThreadReadState struct {
+	UserID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	ThreadID uuid.UUID `gorm:"type:uuid;primaryKey" json:"threadId"`
+
+	LastReadAt time.Time `gorm:"not null" json:"lastReadAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ThreadShareLifetime is how long a share-for-review link works
+const ThreadShareLifetime = time.Hour
+
+// ThreadShare lets anyone with the token follow a thread live, read-only,
+// until it expires or the learner revokes it. Meant for a tutor reviewing a
+// conversation as it happens.
+type ThreadShare struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	ThreadID uuid.UUID `gorm:"type:uuid;index;not null" json:"threadId"`
+	UserID   uuid.UUID `gorm:"type:uuid;index;not null" json:"-"`
+	Token    string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"token"`
+
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (s *ThreadShare) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// Active reports whether the share can still be opened at now
+func (s *ThreadShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
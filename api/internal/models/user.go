@@ -7,6 +7,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// UserRole controls access to the admin API
+type UserRole string
+
+const (
+	RoleUser  UserRole = "user"
+	RoleAdmin UserRole = "admin"
+)
+
 // User represents an authenticated user in the system.
 // Users can authenticate via email/password OR OAuth providers (Google/GitHub).
 // OAuth-only users will have a nil PasswordHash.
@@ -22,7 +30,18 @@ type User struct {
 	GitHubID *string `gorm:"type:varchar(255);uniqueIndex" json:"-"`
 
 	// Account status
-	EmailVerified bool `gorm:"default:false" json:"emailVerified"`
+	EmailVerified bool     `gorm:"default:false" json:"emailVerified"`
+	Role          UserRole `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
+
+	// GuestExpiresAt is set on demo accounts created without an email; they
+	// and their data are purged once it passes
+	GuestExpiresAt *time.Time `gorm:"index" json:"-"`
+
+	// MergedIntoID is set once the account has been merged into another. The
+	// row stays, without credentials, so what was sealed with its content key
+	// still opens and its email isn't registered again.
+	MergedIntoID *uuid.UUID `gorm:"type:uuid;index" json:"mergedIntoId,omitempty"`
+	MergedAt     *time.Time `json:"mergedAt,omitempty"`
 
 	// Timestamps
 	CreatedAt time.Time `json:"createdAt"`
@@ -44,3 +63,18 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// IsGuest reports whether the user is a demo account without an email
+func (u *User) IsGuest() bool {
+	return u.GuestExpiresAt != nil
+}
+
+// IsAdmin reports whether the user can use the admin API
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsMerged reports whether the account was merged into another
+func (u *User) IsMerged() bool {
+	return u.MergedIntoID != nil
+}
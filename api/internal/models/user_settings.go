@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AudioRetentionKeep keeps a user's recordings until they delete the thread
+const AudioRetentionKeep = 0
+
+// AudioRetentionOptions are the allowed values for UserSettings.AudioRetentionDays
+var AudioRetentionOptions = []int{AudioRetentionKeep, 7, 30, 90}
+
+// Reply lengths for UserSettings.ReplyLength and Thread.ReplyLength
+const (
+	ReplyLengthShort  = "short"
+	ReplyLengthMedium = "medium"
+	ReplyLengthLong   = "long"
+)
+
+// ReplyLengthOptions are the allowed reply lengths
+var ReplyLengthOptions = []string{ReplyLengthShort, ReplyLengthMedium, ReplyLengthLong}
+
+// Bounds of the assistant's speaking rate, where 1.0 is normal speed
+const (
+	MinSpeechRate     = 0.5
+	MaxSpeechRate     = 1.5
+	DefaultSpeechRate = 1.0
+)
+
+// DefaultTimezone is the timezone of users who haven't set theirs
+const DefaultTimezone = "UTC"
+
+// How often a category of notifications is emailed
+const (
+	EmailFrequencyInstant = "instant" // soon after, a few minutes' worth per email
+	EmailFrequencyDaily   = "daily"   // in the daily digest
+	EmailFrequencyOff     = "off"     // in the app only
+)
+
+// EmailFrequencyOptions are the allowed email frequencies
+var EmailFrequencyOptions = []string{EmailFrequencyInstant, EmailFrequencyDaily, EmailFrequencyOff}
+
+// UserSettings holds a user's account preferences. Users without a row get
+// DefaultUserSettings.
+type UserSettings struct {
+	UserID uuid.UUID `gorm:"type:uuid;primary_key" json:"-"`
+
+	// AudioRetentionDays deletes the user's recordings this many days after
+	// they were sent, keeping transcripts and pronunciation analyses.
+	// AudioRetentionKeep (0) keeps them.
+	AudioRetentionDays int `gorm:"not null;default:0" json:"audioRetentionDays"`
+
+	// ReplyLength is how long the assistant's replies should be, one of
+	// ReplyLengthOptions. Threads can override it.
+	ReplyLength string `gorm:"type:varchar(10);not null;default:'medium'" json:"replyLength"`
+
+	// SpeechRate is how fast replies are read aloud, between MinSpeechRate and
+	// MaxSpeechRate. Threads can override it.
+	SpeechRate float64 `gorm:"not null;default:1" json:"speechRate"`
+
+	// EncryptContent encrypts the user's transcripts and pronunciation
+	// analyses with their own key. Turning it off stops encrypting new
+	// messages; what is already encrypted stays that way.
+	EncryptContent bool `gorm:"not null;default:false" json:"encryptContent"`
+
+	// Timezone is the user's IANA timezone, e.g. "Europe/Madrid". Streaks and
+	// the home screen's day start at midnight there; timestamps are still
+	// stored in UTC.
+	Timezone string `gorm:"type:varchar(64);not null;default:'UTC'" json:"timezone"`
+
+	// How often achievement and update notifications are emailed, one of
+	// EmailFrequencyOptions. Billing notifications are always emailed.
+	EmailAchievements string `gorm:"type:varchar(10);not null;default:'daily'" json:"emailAchievements"`
+	EmailUpdates      string `gorm:"type:varchar(10);not null;default:'instant'" json:"emailUpdates"`
+
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DefaultUserSettings returns the settings of a user who never changed any
+func DefaultUserSettings(userID uuid.UUID) *UserSettings {
+	return &UserSettings{
+		UserID:             userID,
+		AudioRetentionDays: AudioRetentionKeep,
+		ReplyLength:        ReplyLengthMedium,
+		SpeechRate:         DefaultSpeechRate,
+		Timezone:           DefaultTimezone,
+		EmailAchievements:  EmailFrequencyDaily,
+		EmailUpdates:       EmailFrequencyInstant,
+	}
+}
+
+// Location returns the user's timezone, or UTC if it is unset or unknown
+func (s *UserSettings) Location() *time.Location {
+	if s == nil || s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// EmailFrequency is how often notifications of an email category are
+// emailed. Billing is always instant; unknown categories are never emailed.
+func (s *UserSettings) EmailFrequency(category string) string {
+	switch category {
+	case EmailCategoryBilling:
+		return EmailFrequencyInstant
+	case EmailCategoryAchievements:
+		return s.EmailAchievements
+	case EmailCategoryUpdates:
+		return s.EmailUpdates
+	default:
+		return EmailFrequencyOff
+	}
+}
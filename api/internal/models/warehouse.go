@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Warehouse fact tables, named as their folders under the export prefix
+const (
+	WarehouseTableMessages     = "messages"
+	WarehouseTableAnalyses     = "analyses"
+	WarehouseTableTransactions = "credit_transactions"
+	WarehouseTableUsage        = "usage_daily"
+)
+
+// WarehouseWatermark records how far a fact table has been exported: every
+// row before ExportedThrough is in the warehouse, and the next export starts
+// there. Exports cover whole UTC days, so it is always a midnight.
+type WarehouseWatermark struct {
+	Table           string    `gorm:"column:fact_table;type:varchar(50);primary_key" json:"table"`
+	ExportedThrough time.Time `gorm:"not null" json:"exportedThrough"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// WarehouseMessageFact is the metadata of one message, without its content
+type WarehouseMessageFact struct {
+	ID                   uuid.UUID
+	ThreadID             uuid.UUID
+	UserID               uuid.UUID
+	Locale               string
+	Role                 string
+	Kind                 string
+	HasAudio             bool
+	AudioDurationSeconds *float64
+	ContentLength        *int64 // nil for encrypted messages
+	PronunciationStatus  string
+	Tone                 string
+	Timestamp            time.Time
+}
+
+// WarehouseAnalysisFact is one completed pronunciation analysis
+type WarehouseAnalysisFact struct {
+	ID                         uuid.UUID
+	UserID                     uuid.UUID
+	Locale                     string
+	PronunciationAnalysis      JSONMap
+	PronunciationConfidence    *float64
+	PronunciationLowConfidence bool
+	PronunciationQuality       string
+	PronunciationUpdatedAt     time.Time
+}
+
+// WarehouseUsageFact is one user's message activity over a day
+type WarehouseUsageFact struct {
+	UserID            uuid.UUID
+	UserMessages      int64
+	VoiceMessages     int64
+	AssistantMessages int64
+	AudioSeconds      float64
+	ActiveThreads     int64
+}
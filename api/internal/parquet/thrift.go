@@ -0,0 +1,97 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type ids
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the handful of Thrift compact protocol shapes that
+// Parquet page headers and file metadata use. Structs nest by pushing the
+// last field id, which field headers are delta-encoded against.
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16
+	lastID  int16
+}
+
+func (w *thriftWriter) varint(v uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	w.buf.Write(scratch[:n])
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) fieldHeader(typ byte, id int16) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+	w.lastID = id
+}
+
+func (w *thriftWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(thriftI32, id)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(thriftI64, id)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) stringField(id int16, s string) {
+	w.fieldHeader(thriftBinary, id)
+	w.stringValue(s)
+}
+
+func (w *thriftWriter) stringValue(s string) {
+	w.varint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+// listField writes a list field header; the caller then writes n elements
+func (w *thriftWriter) listField(id int16, elemType byte, n int) {
+	w.fieldHeader(thriftList, id)
+	w.listHeader(elemType, n)
+}
+
+func (w *thriftWriter) listHeader(elemType byte, n int) {
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.varint(uint64(n))
+}
+
+// structField starts a nested struct field; close it with structEnd
+func (w *thriftWriter) structField(id int16) {
+	w.fieldHeader(thriftStruct, id)
+	w.structBegin()
+}
+
+// structBegin starts a struct that is a list element or the top-level value
+func (w *thriftWriter) structBegin() {
+	w.lastIDs = append(w.lastIDs, w.lastID)
+	w.lastID = 0
+}
+
+func (w *thriftWriter) structEnd() {
+	w.buf.WriteByte(0)
+	w.lastID = w.lastIDs[len(w.lastIDs)-1]
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
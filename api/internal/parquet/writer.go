@@ -0,0 +1,335 @@
+// Package parquet writes slices of flat structs as Parquet files for
+// downstream analytics tools.
+//
+// It covers only what the warehouse export needs: one row group, one
+// GZIP-compressed data page per column, PLAIN encoding and no nesting.
+// Each field with a `parquet:"name"` tag becomes a column; untagged fields
+// are skipped. Supported field types are string, bool, int, int32, int64,
+// float64 and time.Time (TIMESTAMP_MILLIS, or DATE with `parquet:"name,date"`).
+// A pointer to any of them makes the column optional, with nil as null.
+//
+// The tests read its output back with a decoder written from parquet.thrift
+// and the format spec, independently of the writer's own constants.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const magic = "PAR1"
+
+// createdBy identifies the writer in the file footer
+const createdBy = "ling-app parquet writer"
+
+// Physical types
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+)
+
+// Converted (logical) types
+const (
+	convertedUTF8            = 0
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+)
+
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecGzip    = 2
+	pageTypeData = 0
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// column is one leaf of the schema, read from a struct field
+type column struct {
+	name      string
+	field     int
+	physical  int32
+	converted int32 // -1 when the column has no converted type
+	optional  bool
+	date      bool
+}
+
+// Marshal encodes rows, a slice of structs, as a Parquet file
+func Marshal(rows any) ([]byte, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("parquet: rows must be a slice of structs, got %T", rows)
+	}
+
+	columns, err := schemaOf(v.Type().Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteString(magic)
+
+	numRows := v.Len()
+	var chunks []chunkMeta
+	if numRows > 0 {
+		for _, col := range columns {
+			chunk, err := writeColumn(&out, col, v)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	footer := fileMetaData(columns, chunks, int64(numRows))
+	out.Write(footer)
+	binary.Write(&out, binary.LittleEndian, uint32(len(footer)))
+	out.WriteString(magic)
+	return out.Bytes(), nil
+}
+
+// schemaOf reads the columns from the tagged fields of a struct type
+func schemaOf(t reflect.Type) ([]column, error) {
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("parquet")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		col := column{name: name, field: i, converted: -1, date: options == "date"}
+
+		typ := field.Type
+		if typ.Kind() == reflect.Pointer {
+			col.optional = true
+			typ = typ.Elem()
+		}
+
+		switch {
+		case typ == timeType && col.date:
+			col.physical, col.converted = typeInt32, convertedDate
+		case typ == timeType:
+			col.physical, col.converted = typeInt64, convertedTimestampMillis
+		case typ.Kind() == reflect.String:
+			col.physical, col.converted = typeByteArray, convertedUTF8
+		case typ.Kind() == reflect.Bool:
+			col.physical = typeBoolean
+		case typ.Kind() == reflect.Int32:
+			col.physical = typeInt32
+		case typ.Kind() == reflect.Int || typ.Kind() == reflect.Int64:
+			col.physical = typeInt64
+		case typ.Kind() == reflect.Float64:
+			col.physical = typeDouble
+		default:
+			return nil, fmt.Errorf("parquet: field %s has unsupported type %s", field.Name, field.Type)
+		}
+		columns = append(columns, col)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet: %s has no parquet-tagged fields", t)
+	}
+	return columns, nil
+}
+
+// chunkMeta locates one written column chunk for the footer
+type chunkMeta struct {
+	offset           int64
+	uncompressedSize int64
+	compressedSize   int64
+	numValues        int64
+}
+
+// writeColumn writes a column chunk of a single data page
+func writeColumn(out *bytes.Buffer, col column, rows reflect.Value) (chunkMeta, error) {
+	var page bytes.Buffer
+	var values bytes.Buffer
+	var defLevels []bool
+	var bits []bool
+
+	for i := 0; i < rows.Len(); i++ {
+		value := rows.Index(i).Field(col.field)
+		if col.optional {
+			defLevels = append(defLevels, !value.IsNil())
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+
+		switch {
+		case col.physical == typeBoolean:
+			bits = append(bits, value.Bool())
+		case col.converted == convertedDate:
+			days := value.Interface().(time.Time).UTC().Unix() / 86400
+			binary.Write(&values, binary.LittleEndian, int32(days))
+		case col.converted == convertedTimestampMillis:
+			binary.Write(&values, binary.LittleEndian, value.Interface().(time.Time).UnixMilli())
+		case col.physical == typeByteArray:
+			s := value.String()
+			binary.Write(&values, binary.LittleEndian, uint32(len(s)))
+			values.WriteString(s)
+		case col.physical == typeInt32:
+			binary.Write(&values, binary.LittleEndian, int32(value.Int()))
+		case col.physical == typeInt64:
+			binary.Write(&values, binary.LittleEndian, value.Int())
+		case col.physical == typeDouble:
+			binary.Write(&values, binary.LittleEndian, math.Float64bits(value.Float()))
+		}
+	}
+	if col.physical == typeBoolean {
+		values.Write(packBits(bits))
+	}
+
+	if col.optional {
+		levels := encodeLevels(defLevels)
+		binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+	page.Write(values.Bytes())
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(page.Bytes()); err != nil {
+		return chunkMeta{}, fmt.Errorf("parquet: compress %s: %w", col.name, err)
+	}
+	if err := gz.Close(); err != nil {
+		return chunkMeta{}, fmt.Errorf("parquet: compress %s: %w", col.name, err)
+	}
+
+	header := pageHeader(rows.Len(), page.Len(), compressed.Len(), col.optional)
+	meta := chunkMeta{
+		offset:           int64(out.Len()),
+		uncompressedSize: int64(len(header) + page.Len()),
+		compressedSize:   int64(len(header) + compressed.Len()),
+		numValues:        int64(rows.Len()),
+	}
+	out.Write(header)
+	out.Write(compressed.Bytes())
+	return meta, nil
+}
+
+// packBits packs booleans LSB first, as PLAIN encodes them
+func packBits(bits []bool) []byte {
+	packed := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+// encodeLevels RLE-encodes definition levels with a bit width of 1: each run
+// is a varint of its length shifted left once, then the level in one byte
+func encodeLevels(levels []bool) []byte {
+	var buf []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		buf = binary.AppendUvarint(buf, uint64(j-i)<<1)
+		if levels[i] {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+		i = j
+	}
+	return buf
+}
+
+func pageHeader(numValues, uncompressedSize, compressedSize int, optional bool) []byte {
+	var w thriftWriter
+	w.structBegin()
+	w.i32Field(1, pageTypeData)
+	w.i32Field(2, int32(uncompressedSize))
+	w.i32Field(3, int32(compressedSize))
+	w.structField(5)
+	w.i32Field(1, int32(numValues))
+	w.i32Field(2, encodingPlain)
+	w.i32Field(3, encodingRLE)
+	w.i32Field(4, encodingRLE)
+	w.structEnd()
+	w.structEnd()
+	return w.buf.Bytes()
+}
+
+func fileMetaData(columns []column, chunks []chunkMeta, numRows int64) []byte {
+	var w thriftWriter
+	w.structBegin()
+	w.i32Field(1, 1)
+
+	w.listField(2, thriftStruct, len(columns)+1)
+	w.structBegin()
+	w.stringField(4, "schema")
+	w.i32Field(5, int32(len(columns)))
+	w.structEnd()
+	for _, col := range columns {
+		w.structBegin()
+		w.i32Field(1, col.physical)
+		repetition := int32(repetitionRequired)
+		if col.optional {
+			repetition = repetitionOptional
+		}
+		w.i32Field(3, repetition)
+		w.stringField(4, col.name)
+		if col.converted >= 0 {
+			w.i32Field(6, col.converted)
+		}
+		w.structEnd()
+	}
+
+	w.i64Field(3, numRows)
+
+	if len(chunks) == 0 {
+		w.listField(4, thriftStruct, 0)
+	} else {
+		w.listField(4, thriftStruct, 1)
+		w.structBegin()
+		w.listField(1, thriftStruct, len(chunks))
+		var totalSize int64
+		for i, chunk := range chunks {
+			totalSize += chunk.uncompressedSize
+			w.structBegin()
+			w.i64Field(2, chunk.offset)
+			w.structField(3)
+			w.i32Field(1, columns[i].physical)
+			w.listField(2, thriftI32, 2)
+			w.zigzag(encodingPlain)
+			w.zigzag(encodingRLE)
+			w.listField(3, thriftBinary, 1)
+			w.stringValue(columns[i].name)
+			w.i32Field(4, codecGzip)
+			w.i64Field(5, chunk.numValues)
+			w.i64Field(6, chunk.uncompressedSize)
+			w.i64Field(7, chunk.compressedSize)
+			w.i64Field(9, chunk.offset)
+			w.structEnd()
+			w.structEnd()
+		}
+		w.i64Field(2, totalSize)
+		w.i64Field(3, numRows)
+		w.structEnd()
+	}
+
+	w.stringField(6, createdBy)
+	w.structEnd()
+	return w.buf.Bytes()
+}
@@ -0,0 +1,648 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes Thrift compact structs into field id -> value maps
+// so the tests can check what the writer produced
+type thriftReader struct {
+	r *bytes.Reader
+}
+
+func (r *thriftReader) varint() uint64 {
+	v, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		b := make([]byte, r.varint())
+		io.ReadFull(r.r, b)
+		return string(b)
+	case thriftList:
+		header, _ := r.r.ReadByte()
+		n, elemType := int(header>>4), header&0x0f
+		if n == 15 {
+			n = int(r.varint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(elemType)
+		}
+		return list
+	case thriftStruct:
+		fields := map[int16]any{}
+		var last int16
+		for {
+			header, _ := r.r.ReadByte()
+			if header == 0 {
+				return fields
+			}
+			id := last + int16(header>>4)
+			if header>>4 == 0 {
+				id = int16(r.zigzag())
+			}
+			fields[id] = r.value(header & 0x0f)
+			last = id
+		}
+	}
+	panic("unexpected thrift type")
+}
+
+type testRow struct {
+	ID       string     `parquet:"id"`
+	Count    int64      `parquet:"count"`
+	Score    *float64   `parquet:"score"`
+	Flag     bool       `parquet:"flag"`
+	At       time.Time  `parquet:"at"`
+	Day      time.Time  `parquet:"day,date"`
+	Note     *string    `parquet:"note"`
+	Seen     *time.Time `parquet:"seen"`
+	Internal string
+}
+
+func readFooter(t *testing.T, data []byte) map[int16]any {
+	t.Helper()
+	require.Equal(t, magic, string(data[:4]))
+	require.Equal(t, magic, string(data[len(data)-4:]))
+	footerLen := binary.LittleEndian.Uint32(data[len(data)-8:])
+	footer := data[len(data)-8-int(footerLen) : len(data)-8]
+	return (&thriftReader{r: bytes.NewReader(footer)}).value(thriftStruct).(map[int16]any)
+}
+
+// readPage returns a column chunk's definition levels (nil when required)
+// and its plain-encoded values
+func readPage(t *testing.T, data []byte, offset int64, optional bool) ([]bool, []byte) {
+	t.Helper()
+	r := bytes.NewReader(data[offset:])
+	header := (&thriftReader{r: r}).value(thriftStruct).(map[int16]any)
+	compressed := make([]byte, header[3].(int64))
+	_, err := io.ReadFull(r, compressed)
+	require.NoError(t, err)
+
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	page, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, header[2].(int64), int64(len(page)))
+
+	if !optional {
+		return nil, page
+	}
+	levelsLen := binary.LittleEndian.Uint32(page)
+	levels := bytes.NewReader(page[4 : 4+levelsLen])
+	var defined []bool
+	for levels.Len() > 0 {
+		run, _ := binary.ReadUvarint(levels)
+		level, _ := levels.ReadByte()
+		for i := uint64(0); i < run>>1; i++ {
+			defined = append(defined, level == 1)
+		}
+	}
+	return defined, page[4+levelsLen:]
+}
+
+func TestMarshal(t *testing.T) {
+	at := time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC)
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	score, note := 0.75, "hola"
+	rows := []testRow{
+		{ID: "a", Count: 3, Score: &score, Flag: true, At: at, Day: day, Note: &note, Internal: "skipped"},
+		{ID: "bcd", Count: -1, Flag: false, At: at.Add(time.Second), Day: day},
+		{ID: "", Count: 1 << 40, Score: &score, Flag: true, At: at, Day: day},
+	}
+
+	data, err := Marshal(rows)
+	require.NoError(t, err)
+
+	meta := readFooter(t, data)
+	assert.Equal(t, int64(3), meta[3])
+	assert.Equal(t, createdBy, meta[6])
+
+	schema := meta[2].([]any)
+	require.Len(t, schema, 9)
+	assert.Equal(t, "schema", schema[0].(map[int16]any)[4])
+	assert.Equal(t, int64(8), schema[0].(map[int16]any)[5])
+	names := make([]string, 0, 8)
+	for _, element := range schema[1:] {
+		names = append(names, element.(map[int16]any)[4].(string))
+	}
+	assert.Equal(t, []string{"id", "count", "score", "flag", "at", "day", "note", "seen"}, names)
+	assert.Equal(t, int64(repetitionOptional), schema[3].(map[int16]any)[3])
+	assert.Equal(t, int64(convertedDate), schema[6].(map[int16]any)[6])
+
+	rowGroup := meta[4].([]any)[0].(map[int16]any)
+	assert.Equal(t, int64(3), rowGroup[3])
+	chunks := rowGroup[1].([]any)
+	require.Len(t, chunks, 8)
+	chunkAt := func(i int) (int64, map[int16]any) {
+		chunk := chunks[i].(map[int16]any)
+		return chunk[2].(int64), chunk[3].(map[int16]any)
+	}
+
+	t.Run("strings", func(t *testing.T) {
+		offset, colMeta := chunkAt(0)
+		assert.Equal(t, []any{"id"}, colMeta[3])
+		assert.Equal(t, int64(codecGzip), colMeta[4])
+		_, values := readPage(t, data, offset, false)
+		var got []string
+		for r := bytes.NewReader(values); r.Len() > 0; {
+			var n uint32
+			binary.Read(r, binary.LittleEndian, &n)
+			s := make([]byte, n)
+			io.ReadFull(r, s)
+			got = append(got, string(s))
+		}
+		assert.Equal(t, []string{"a", "bcd", ""}, got)
+	})
+
+	t.Run("int64", func(t *testing.T) {
+		offset, _ := chunkAt(1)
+		_, values := readPage(t, data, offset, false)
+		got := make([]int64, 3)
+		require.NoError(t, binary.Read(bytes.NewReader(values), binary.LittleEndian, got))
+		assert.Equal(t, []int64{3, -1, 1 << 40}, got)
+	})
+
+	t.Run("optional double", func(t *testing.T) {
+		offset, _ := chunkAt(2)
+		defined, values := readPage(t, data, offset, true)
+		assert.Equal(t, []bool{true, false, true}, defined)
+		require.Len(t, values, 16)
+		assert.Equal(t, 0.75, math.Float64frombits(binary.LittleEndian.Uint64(values[8:])))
+	})
+
+	t.Run("booleans", func(t *testing.T) {
+		offset, _ := chunkAt(3)
+		_, values := readPage(t, data, offset, false)
+		assert.Equal(t, []byte{0b101}, values)
+	})
+
+	t.Run("timestamps and dates", func(t *testing.T) {
+		offset, _ := chunkAt(4)
+		_, values := readPage(t, data, offset, false)
+		assert.Equal(t, at.UnixMilli(), int64(binary.LittleEndian.Uint64(values)))
+
+		offset, _ = chunkAt(5)
+		_, values = readPage(t, data, offset, false)
+		assert.Equal(t, int32(day.Unix()/86400), int32(binary.LittleEndian.Uint32(values)))
+	})
+
+	t.Run("all null", func(t *testing.T) {
+		offset, _ := chunkAt(7)
+		defined, values := readPage(t, data, offset, true)
+		assert.Equal(t, []bool{false, false, false}, defined)
+		assert.Empty(t, values)
+	})
+}
+
+func TestMarshal_NoRows(t *testing.T) {
+	data, err := Marshal([]testRow{})
+	require.NoError(t, err)
+
+	meta := readFooter(t, data)
+	assert.Equal(t, int64(0), meta[3])
+	assert.Empty(t, meta[4])
+}
+
+func TestMarshal_RejectsUnsupportedTypes(t *testing.T) {
+	_, err := Marshal([]struct {
+		Tags []string `parquet:"tags"`
+	}{{}})
+	assert.ErrorContains(t, err, "unsupported type")
+
+	_, err = Marshal([]int{1})
+	assert.Error(t, err)
+}
+
+// The conformance tests below read files the way any Parquet reader has to:
+// from the field ids and enum values in parquet.thrift and the encodings in
+// the format spec, written out here rather than taken from the writer, so a
+// wrong constant or layout in the writer fails them. They also check the
+// offsets and sizes readers rely on to find and skip pages.
+
+// Values from parquet.thrift
+const (
+	specTypeBoolean   = 0
+	specTypeInt32     = 1
+	specTypeInt64     = 2
+	specTypeDouble    = 5
+	specTypeByteArray = 6
+
+	specConvertedUTF8            = 0
+	specConvertedDate            = 6
+	specConvertedTimestampMillis = 9
+
+	specRequired = 0
+	specOptional = 1
+
+	specEncodingPlain = 0
+	specEncodingRLE   = 3
+
+	specCodecGzip    = 2
+	specPageTypeData = 0
+)
+
+// specStruct decodes a Thrift compact protocol struct, skipping nothing: it
+// understands every type the protocol has, so an unexpected one is read, not
+// misparsed
+func specStruct(t *testing.T, r *bytes.Reader) map[int16]any {
+	t.Helper()
+	fields := map[int16]any{}
+	var last int16
+	for {
+		header, err := r.ReadByte()
+		require.NoError(t, err, "struct ends early")
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(specZigzag(t, r))
+		}
+		require.Greater(t, id, last, "field ids must increase")
+		typ := header & 0x0f
+		switch typ {
+		case 1, 2: // bool, with the value in the type
+			fields[id] = typ == 1
+		default:
+			fields[id] = specValue(t, r, typ)
+		}
+		last = id
+	}
+}
+
+func specZigzag(t *testing.T, r *bytes.Reader) int64 {
+	t.Helper()
+	v, err := binary.ReadUvarint(r)
+	require.NoError(t, err)
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func specValue(t *testing.T, r *bytes.Reader, typ byte) any {
+	t.Helper()
+	switch typ {
+	case 3: // byte
+		b, err := r.ReadByte()
+		require.NoError(t, err)
+		return int64(int8(b))
+	case 4, 5, 6: // i16, i32, i64
+		return specZigzag(t, r)
+	case 7: // double
+		var bits uint64
+		require.NoError(t, binary.Read(r, binary.LittleEndian, &bits))
+		return math.Float64frombits(bits)
+	case 8: // binary
+		n, err := binary.ReadUvarint(r)
+		require.NoError(t, err)
+		require.LessOrEqual(t, n, uint64(r.Len()), "binary runs past the end")
+		b := make([]byte, n)
+		io.ReadFull(r, b)
+		return string(b)
+	case 9, 10: // list, set
+		header, err := r.ReadByte()
+		require.NoError(t, err)
+		n, elemType := int(header>>4), header&0x0f
+		if n == 15 {
+			size, err := binary.ReadUvarint(r)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, size, uint64(15), "long list header for a short list")
+			n = int(size)
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = specValue(t, r, elemType)
+		}
+		return list
+	case 12:
+		return specStruct(t, r)
+	}
+	t.Fatalf("unknown thrift compact type %d", typ)
+	return nil
+}
+
+// specHybrid decodes n values of the RLE/bit-packed hybrid encoding
+func specHybrid(t *testing.T, data []byte, bitWidth, n int) []int {
+	t.Helper()
+	r := bytes.NewReader(data)
+	var values []int
+	for len(values) < n {
+		header, err := binary.ReadUvarint(r)
+		require.NoError(t, err, "levels end after %d of %d values", len(values), n)
+		if header&1 == 0 {
+			raw := make([]byte, (bitWidth+7)/8)
+			_, err := io.ReadFull(r, raw)
+			require.NoError(t, err)
+			v := 0
+			for i, b := range raw {
+				v |= int(b) << (8 * i)
+			}
+			require.Less(t, v, 1<<bitWidth, "RLE value wider than the bit width")
+			for i := uint64(0); i < header>>1; i++ {
+				values = append(values, v)
+			}
+			continue
+		}
+		packed := make([]byte, int(header>>1)*bitWidth)
+		_, err = io.ReadFull(r, packed)
+		require.NoError(t, err)
+		for i := 0; i < int(header>>1)*8; i++ {
+			v := 0
+			for b := 0; b < bitWidth; b++ {
+				bit := i*bitWidth + b
+				v |= int(packed[bit/8]>>(bit%8)&1) << b
+			}
+			values = append(values, v)
+		}
+	}
+	require.Zero(t, r.Len(), "levels carry bytes past the values")
+	// Bit-packed runs pad to a multiple of 8
+	return values[:n]
+}
+
+// specColumn is one decoded column: its schema and a value per row, nil for null
+type specColumn struct {
+	name      string
+	physical  int64
+	converted int64 // -1 if none
+	optional  bool
+	values    []any
+}
+
+// specRead decodes a whole file, checking along the way what readers rely on
+func specRead(t *testing.T, data []byte) (int64, []specColumn) {
+	t.Helper()
+	require.GreaterOrEqual(t, len(data), 12)
+	require.Equal(t, "PAR1", string(data[:4]))
+	require.Equal(t, "PAR1", string(data[len(data)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	require.LessOrEqual(t, footerLen, len(data)-12)
+	footerStart := len(data) - 8 - footerLen
+	footer := bytes.NewReader(data[footerStart : len(data)-8])
+	meta := specStruct(t, footer)
+	require.Zero(t, footer.Len(), "footer length covers more than the metadata")
+
+	require.Contains(t, meta, int16(1), "version is required")
+	numRows := meta[3].(int64)
+
+	schema := meta[2].([]any)
+	root := schema[0].(map[int16]any)
+	require.NotContains(t, root, int16(1), "the root has no type")
+	require.Equal(t, int64(len(schema)-1), root[5], "root num_children")
+	columns := make([]specColumn, len(schema)-1)
+	for i, element := range schema[1:] {
+		el := element.(map[int16]any)
+		require.NotContains(t, el, int16(5), "flat schema leaves have no children")
+		columns[i] = specColumn{name: el[4].(string), physical: el[1].(int64), converted: -1}
+		if converted, ok := el[6]; ok {
+			columns[i].converted = converted.(int64)
+		}
+		switch el[3].(int64) {
+		case specRequired:
+		case specOptional:
+			columns[i].optional = true
+		default:
+			t.Fatalf("column %s: repetition %d in a flat schema", columns[i].name, el[3])
+		}
+	}
+
+	rowGroups := meta[4].([]any)
+	if numRows == 0 {
+		require.Empty(t, rowGroups)
+		return 0, columns
+	}
+	require.Len(t, rowGroups, 1)
+	rowGroup := rowGroups[0].(map[int16]any)
+	require.Equal(t, numRows, rowGroup[3])
+	chunks := rowGroup[1].([]any)
+	require.Len(t, chunks, len(columns), "one chunk per leaf column")
+
+	next := int64(4)
+	var totalSize int64
+	for i, c := range chunks {
+		chunk := c.(map[int16]any)
+		colMeta := chunk[3].(map[int16]any)
+		col := &columns[i]
+		require.Equal(t, col.physical, colMeta[1], "column %s type", col.name)
+		require.Equal(t, []any{col.name}, colMeta[3], "column %s path", col.name)
+		require.Equal(t, int64(specCodecGzip), colMeta[4])
+		require.Equal(t, numRows, colMeta[5], "column %s num_values", col.name)
+
+		offset := colMeta[9].(int64)
+		require.Equal(t, next, offset, "column %s chunks are laid out in order", col.name)
+		compressedSize := colMeta[7].(int64)
+		require.LessOrEqual(t, offset+compressedSize, int64(footerStart), "column %s runs into the footer", col.name)
+		next = offset + compressedSize
+		totalSize += colMeta[6].(int64)
+
+		r := bytes.NewReader(data[offset : offset+compressedSize])
+		header := specStruct(t, r)
+		headerLen := compressedSize - int64(r.Len())
+		require.Equal(t, int64(specPageTypeData), header[1])
+		require.Equal(t, compressedSize-headerLen, header[3], "column %s page size", col.name)
+		require.Equal(t, colMeta[6].(int64)-headerLen, header[2], "column %s uncompressed size", col.name)
+
+		dataPage := header[5].(map[int16]any)
+		require.Equal(t, numRows, dataPage[1])
+		require.Equal(t, int64(specEncodingPlain), dataPage[2])
+		require.Equal(t, int64(specEncodingRLE), dataPage[3])
+		for _, encoding := range []any{dataPage[2], dataPage[3]} {
+			require.Contains(t, colMeta[2], encoding, "column %s lists the encodings its pages use", col.name)
+		}
+
+		gz, err := gzip.NewReader(r)
+		require.NoError(t, err)
+		page, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		require.Equal(t, header[2], int64(len(page)))
+
+		defined := make([]bool, numRows)
+		for j := range defined {
+			defined[j] = true
+		}
+		if col.optional {
+			levelsLen := binary.LittleEndian.Uint32(page)
+			levels := specHybrid(t, page[4:4+levelsLen], 1, int(numRows))
+			for j, level := range levels {
+				defined[j] = level == 1
+			}
+			page = page[4+levelsLen:]
+		}
+		col.values = specPlain(t, col.physical, page, defined)
+	}
+	require.Equal(t, totalSize, rowGroup[2], "row group total_byte_size")
+	require.Equal(t, int64(footerStart), next, "nothing between the last chunk and the footer")
+	return numRows, columns
+}
+
+// specPlain decodes PLAIN values, one per defined row
+func specPlain(t *testing.T, physical int64, page []byte, defined []bool) []any {
+	t.Helper()
+	r := bytes.NewReader(page)
+	values := make([]any, len(defined))
+	bit := 0
+	for i, ok := range defined {
+		if !ok {
+			continue
+		}
+		switch physical {
+		case specTypeBoolean:
+			require.Less(t, bit/8, len(page))
+			values[i] = page[bit/8]>>(bit%8)&1 == 1
+			bit++
+		case specTypeInt32:
+			var v int32
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &v))
+			values[i] = v
+		case specTypeInt64:
+			var v int64
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &v))
+			values[i] = v
+		case specTypeDouble:
+			var v float64
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &v))
+			values[i] = v
+		case specTypeByteArray:
+			var n uint32
+			require.NoError(t, binary.Read(r, binary.LittleEndian, &n))
+			b := make([]byte, n)
+			_, err := io.ReadFull(r, b)
+			require.NoError(t, err)
+			values[i] = string(b)
+		default:
+			t.Fatalf("unexpected physical type %d", physical)
+		}
+	}
+	if physical == specTypeBoolean {
+		require.Equal(t, (bit+7)/8, len(page), "booleans are packed into whole bytes")
+	} else {
+		require.Zero(t, r.Len(), "page has bytes past its values")
+	}
+	return values
+}
+
+// wideRow has more than 14 columns, so the schema and chunk lists need the
+// long list header
+type wideRow struct {
+	C0  string   `parquet:"c0"`
+	C1  int64    `parquet:"c1"`
+	C2  int32    `parquet:"c2"`
+	C3  bool     `parquet:"c3"`
+	C4  float64  `parquet:"c4"`
+	C5  *string  `parquet:"c5"`
+	C6  *int64   `parquet:"c6"`
+	C7  *int32   `parquet:"c7"`
+	C8  *bool    `parquet:"c8"`
+	C9  *float64 `parquet:"c9"`
+	C10 int      `parquet:"c10"`
+	C11 string   `parquet:"c11"`
+	C12 bool     `parquet:"c12"`
+	C13 int64    `parquet:"c13"`
+	C14 *string  `parquet:"c14"`
+	C15 float64  `parquet:"c15"`
+}
+
+func TestMarshal_ConformsToSpec(t *testing.T) {
+	at := time.Date(2026, 10, 15, 8, 30, 0, 123e6, time.UTC)
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	score, note := 0.75, "¿qué tal?"
+	rows := []testRow{
+		{ID: "a", Count: 3, Score: &score, Flag: true, At: at, Day: day, Note: &note, Seen: &at},
+		{ID: "bcd", Count: -1, At: at.Add(time.Second), Day: day.AddDate(0, 0, -1)},
+		{ID: "", Count: math.MinInt64, Score: &score, Flag: true, At: at, Day: day},
+	}
+
+	data, err := Marshal(rows)
+	require.NoError(t, err)
+	numRows, columns := specRead(t, data)
+	require.Equal(t, int64(3), numRows)
+
+	byName := map[string]specColumn{}
+	for _, col := range columns {
+		byName[col.name] = col
+	}
+	assert.Equal(t, int64(specConvertedUTF8), byName["id"].converted)
+	assert.Equal(t, int64(-1), byName["count"].converted)
+	assert.Equal(t, int64(specConvertedTimestampMillis), byName["at"].converted)
+	assert.Equal(t, int64(specTypeInt64), byName["at"].physical)
+	assert.Equal(t, int64(specConvertedDate), byName["day"].converted)
+	assert.Equal(t, int64(specTypeInt32), byName["day"].physical)
+	assert.False(t, byName["flag"].optional)
+	assert.True(t, byName["seen"].optional)
+
+	assert.Equal(t, []any{"a", "bcd", ""}, byName["id"].values)
+	assert.Equal(t, []any{int64(3), int64(-1), int64(math.MinInt64)}, byName["count"].values)
+	assert.Equal(t, []any{0.75, nil, 0.75}, byName["score"].values)
+	assert.Equal(t, []any{true, false, true}, byName["flag"].values)
+	assert.Equal(t, []any{at.UnixMilli(), at.Add(time.Second).UnixMilli(), at.UnixMilli()}, byName["at"].values)
+	assert.Equal(t, []any{int32(20741), int32(20740), int32(20741)}, byName["day"].values, "days since 1970-01-01")
+	assert.Equal(t, []any{"¿qué tal?", nil, nil}, byName["note"].values)
+	assert.Equal(t, []any{at.UnixMilli(), nil, nil}, byName["seen"].values)
+}
+
+func TestMarshal_ConformsToSpec_ManyRowsAndColumns(t *testing.T) {
+	rows := make([]wideRow, 1000)
+	for i := range rows {
+		s, n, n32, b, f := fmt.Sprintf("row %d", i), int64(i)*1e9, int32(-i), i%3 == 0, float64(i)/7
+		rows[i] = wideRow{C0: s, C1: n, C2: n32, C3: b, C4: f, C10: i, C11: strings.Repeat("x", i%50), C12: !b, C13: -n, C15: -f}
+		// Nulls in long runs and alternating stretches, so level runs of
+		// every length appear
+		if i < 300 || i%2 == 0 {
+			rows[i].C5, rows[i].C6, rows[i].C7, rows[i].C8, rows[i].C9 = &s, &n, &n32, &b, &f
+		}
+		if i >= 990 {
+			rows[i].C14 = &s
+		}
+	}
+
+	data, err := Marshal(rows)
+	require.NoError(t, err)
+	numRows, columns := specRead(t, data)
+	require.Equal(t, int64(len(rows)), numRows)
+	require.Len(t, columns, 16)
+
+	for i, row := range rows {
+		defined := i < 300 || i%2 == 0
+		want := []any{row.C0, row.C1, row.C2, row.C3, row.C4, nil, nil, nil, nil, nil, int64(row.C10), row.C11, row.C12, row.C13, nil, row.C15}
+		if defined {
+			want[5], want[6], want[7], want[8], want[9] = row.C0, row.C1, row.C2, row.C3, row.C4
+		}
+		if row.C14 != nil {
+			want[14] = *row.C14
+		}
+		for c, col := range columns {
+			require.Equal(t, want[c], col.values[i], "row %d column %s", i, col.name)
+		}
+	}
+}
+
+func TestMarshal_ConformsToSpec_NoRows(t *testing.T) {
+	data, err := Marshal([]wideRow{})
+	require.NoError(t, err)
+	numRows, columns := specRead(t, data)
+	assert.Equal(t, int64(0), numRows)
+	assert.Len(t, columns, 16)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+)
+
+// accountMergeRepository implements AccountMergeRepository using GORM.
+type accountMergeRepository struct{}
+
+// NewAccountMergeRepository creates a new GORM-backed account merge repository.
+func NewAccountMergeRepository() AccountMergeRepository {
+	return &accountMergeRepository{}
+}
+
+// mergeOwnedModels are the models keyed by user_id without a per-user unique
+// key, so their rows can change owner as they are. Read states and practice
+// sessions carry the owner alongside the thread and move with it. Rows change
+// owner with UpdateColumn, which leaves updated_at alone so moved threads
+// keep their place in the list. They are built per merge because an update
+// writes the new owner back into its model.
+func mergeOwnedModels() []any {
+	return []any{
+		&models.Thread{},
+		&models.ThreadReadState{},
+		&models.PracticeSession{},
+		&models.ThreadShare{},
+		&models.CreditTransaction{},
+		&models.CreditDispute{},
+		&models.PhonemeStatsSnapshot{},
+		&models.Notification{},
+		&models.EmailDelivery{},
+		&models.AnalyticsEvent{},
+		&models.FeatureUsageEvent{},
+		&models.DomainEvent{},
+	}
+}
+
+// mergeSingletonModels are keyed by user_id alone. Signup signals and the
+// content key stay with the source: they describe that account, and the
+// key opens what was sealed under its ID.
+func mergeSingletonModels() []any {
+	return []any{
+		&models.UserSettings{},
+		&models.LearnerProfile{},
+		&models.UserStreak{},
+		&models.StatsBadge{},
+	}
+}
+
+func (r *accountMergeRepository) MoveOwned(exec Executor, fromUserID, toUserID uuid.UUID) (map[string]int64, error) {
+	moved := map[string]int64{}
+	for _, model := range mergeOwnedModels() {
+		result := exec.Model(model).Where("user_id = ?", fromUserID).UpdateColumn("user_id", toUserID)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		moved[result.Statement.Table] = result.RowsAffected
+	}
+	return moved, nil
+}
+
+func (r *accountMergeRepository) MergePhonemeStats(exec Executor, fromUserID, toUserID uuid.UUID) (int64, error) {
+	var combined int64
+
+	var stats []models.PhonemeStats
+	if err := exec.Where("user_id = ?", fromUserID).Find(&stats).Error; err != nil {
+		return 0, err
+	}
+	for _, s := range stats {
+		result := exec.Model(&models.PhonemeStats{}).
+			Where("user_id = ? AND language = ? AND phoneme = ?", toUserID, s.Language, s.Phoneme).
+			Updates(map[string]any{
+				"total_attempts": gorm.Expr("total_attempts + ?", s.TotalAttempts),
+				"correct_count":  gorm.Expr("correct_count + ?", s.CorrectCount),
+				"deletion_count": gorm.Expr("deletion_count + ?", s.DeletionCount),
+			})
+		if err := r.combineOrMove(exec, result, &s, s.ID, toUserID); err != nil {
+			return 0, err
+		}
+		combined += result.RowsAffected
+	}
+
+	var subs []models.PhonemeSubstitution
+	if err := exec.Where("user_id = ?", fromUserID).Find(&subs).Error; err != nil {
+		return 0, err
+	}
+	for _, s := range subs {
+		result := exec.Model(&models.PhonemeSubstitution{}).
+			Where("user_id = ? AND language = ? AND expected_phoneme = ? AND actual_phoneme = ?", toUserID, s.Language, s.ExpectedPhoneme, s.ActualPhoneme).
+			Update("occurrence_count", gorm.Expr("occurrence_count + ?", s.OccurrenceCount))
+		if err := r.combineOrMove(exec, result, &s, s.ID, toUserID); err != nil {
+			return 0, err
+		}
+		combined += result.RowsAffected
+	}
+	return combined, nil
+}
+
+// combineOrMove finishes one source row after its counts were added to the
+// target's matching row: it is deleted if there was one, and otherwise
+// handed to the target
+func (r *accountMergeRepository) combineOrMove(exec Executor, added *gorm.DB, model any, id, toUserID uuid.UUID) error {
+	if added.Error != nil {
+		return added.Error
+	}
+	if added.RowsAffected > 0 {
+		return exec.Delete(model, "id = ?", id).Error
+	}
+	return exec.Model(model).Where("id = ?", id).UpdateColumn("user_id", toUserID).Error
+}
+
+func (r *accountMergeRepository) MoveSingletons(exec Executor, fromUserID, toUserID uuid.UUID) ([]string, error) {
+	moved := []string{}
+	for _, model := range mergeSingletonModels() {
+		result := exec.Model(model).
+			Where("user_id = ? AND NOT EXISTS (?)", fromUserID, exec.Model(model).Select("1").Where("user_id = ?", toUserID)).
+			UpdateColumn("user_id", toUserID)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			moved = append(moved, result.Statement.Table)
+		}
+	}
+	return moved, nil
+}
+
+func (r *accountMergeRepository) MoveSubscription(exec Executor, fromUserID, toUserID uuid.UUID) error {
+	if err := exec.Where("user_id = ?", toUserID).Delete(&models.Subscription{}).Error; err != nil {
+		return err
+	}
+	return exec.Model(&models.Subscription{}).Where("user_id = ?", fromUserID).UpdateColumn("user_id", toUserID).Error
+}
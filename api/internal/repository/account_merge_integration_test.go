@@ -0,0 +1,89 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestAccountMergeRepository_Merge(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewAccountMergeRepository()
+	exec := testDB.DB.DB
+
+	from := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Work"}
+	require.NoError(t, testDB.Create(from).Error)
+	into := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Home"}
+	require.NoError(t, testDB.Create(into).Error)
+
+	thread := &models.Thread{UserID: from.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	require.NoError(t, testDB.Create(&models.CreditTransaction{UserID: from.ID, Type: models.TransactionDebit, Amount: -1}).Error)
+
+	require.NoError(t, testDB.Create(&models.PhonemeStats{UserID: from.ID, Phoneme: "θ", TotalAttempts: 4, CorrectCount: 1}).Error)
+	require.NoError(t, testDB.Create(&models.PhonemeStats{UserID: from.ID, Phoneme: "r", TotalAttempts: 2, CorrectCount: 2}).Error)
+	require.NoError(t, testDB.Create(&models.PhonemeStats{UserID: into.ID, Phoneme: "θ", TotalAttempts: 6, CorrectCount: 3}).Error)
+	require.NoError(t, testDB.Create(&models.PhonemeSubstitution{UserID: from.ID, ExpectedPhoneme: "θ", ActualPhoneme: "t", OccurrenceCount: 2}).Error)
+	require.NoError(t, testDB.Create(&models.PhonemeSubstitution{UserID: into.ID, ExpectedPhoneme: "θ", ActualPhoneme: "t", OccurrenceCount: 1}).Error)
+
+	require.NoError(t, testDB.Create(&models.LearnerProfile{UserID: from.ID}).Error)
+	require.NoError(t, testDB.Create(&models.UserStreak{UserID: from.ID, CurrentDays: 2}).Error)
+	require.NoError(t, testDB.Create(&models.UserStreak{UserID: into.ID, CurrentDays: 9}).Error)
+
+	fromSub := &models.Subscription{UserID: from.ID, StripeCustomerID: "cus_" + uuid.NewString(), Tier: models.TierPro}
+	require.NoError(t, testDB.Create(fromSub).Error)
+	require.NoError(t, testDB.Create(&models.Subscription{UserID: into.ID, StripeCustomerID: "cus_" + uuid.NewString(), Tier: models.TierFree}).Error)
+
+	moved, err := repo.MoveOwned(exec, from.ID, into.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), moved["threads"])
+	assert.Equal(t, int64(1), moved["credit_transactions"])
+
+	combined, err := repo.MergePhonemeStats(exec, from.ID, into.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), combined, "θ in the stats and θ→t in the substitutions")
+
+	singletons, err := repo.MoveSingletons(exec, from.ID, into.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"learner_profiles"}, singletons, "the survivor keeps its own streak")
+
+	require.NoError(t, repo.MoveSubscription(exec, from.ID, into.ID))
+
+	var kept models.Thread
+	require.NoError(t, exec.First(&kept, "id = ?", thread.ID).Error)
+	assert.Equal(t, into.ID, kept.UserID)
+
+	var stats []models.PhonemeStats
+	require.NoError(t, exec.Where("user_id = ?", into.ID).Order("phoneme").Find(&stats).Error)
+	require.Len(t, stats, 2)
+	assert.Equal(t, "r", stats[0].Phoneme)
+	assert.Equal(t, 10, stats[1].TotalAttempts)
+	assert.Equal(t, 4, stats[1].CorrectCount)
+
+	var sub models.PhonemeSubstitution
+	require.NoError(t, exec.First(&sub, "user_id = ?", into.ID).Error)
+	assert.Equal(t, 3, sub.OccurrenceCount)
+
+	var left int64
+	require.NoError(t, exec.Model(&models.PhonemeStats{}).Where("user_id = ?", from.ID).Count(&left).Error)
+	assert.Zero(t, left)
+
+	var streak models.UserStreak
+	require.NoError(t, exec.First(&streak, "user_id = ?", into.ID).Error)
+	assert.Equal(t, 9, streak.CurrentDays)
+
+	var subscription models.Subscription
+	require.NoError(t, exec.First(&subscription, "user_id = ?", into.ID).Error)
+	assert.Equal(t, fromSub.ID, subscription.ID)
+	assert.Equal(t, models.TierPro, subscription.Tier)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"time"
+
+	"ling-app/api/internal/models"
+)
+
+// analysisComparisonRepository implements AnalysisComparisonRepository using GORM.
+type analysisComparisonRepository struct{}
+
+// NewAnalysisComparisonRepository creates a new GORM-backed analysis comparison repository.
+func NewAnalysisComparisonRepository() AnalysisComparisonRepository {
+	return &analysisComparisonRepository{}
+}
+
+func (r *analysisComparisonRepository) Create(exec Executor, comparison *models.AnalysisComparison) error {
+	return exec.Create(comparison).Error
+}
+
+func (r *analysisComparisonRepository) Summarize(exec Executor, since time.Time) ([]models.AnalysisComparisonSummary, error) {
+	var summaries []models.AnalysisComparisonSummary
+	err := exec.Model(&models.AnalysisComparison{}).
+		Select(`quality,
+			COUNT(*) AS comparisons,
+			COUNT(*) FILTER (WHERE shadow_status <> 'success') AS shadow_failures,
+			COUNT(*) FILTER (WHERE shadow_status <> primary_status) AS status_mismatches,
+			COALESCE(AVG(agreement), 0) AS avg_agreement,
+			COALESCE(AVG(shadow_accuracy - primary_accuracy) FILTER (WHERE agreement IS NOT NULL), 0) AS avg_accuracy_delta,
+			ROUND(AVG(primary_latency_ms))::bigint AS avg_primary_latency_ms,
+			ROUND(AVG(shadow_latency_ms))::bigint AS avg_shadow_latency_ms,
+			ROUND(percentile_cont(0.95) WITHIN GROUP (ORDER BY primary_latency_ms))::bigint AS p95_primary_latency_ms,
+			ROUND(percentile_cont(0.95) WITHIN GROUP (ORDER BY shadow_latency_ms))::bigint AS p95_shadow_latency_ms`).
+		Where("created_at >= ?", since).
+		Group("quality").
+		Order("quality ASC").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"ling-app/api/internal/models"
+)
+
+// analyticsEventRepository implements AnalyticsEventRepository using GORM.
+type analyticsEventRepository struct{}
+
+// NewAnalyticsEventRepository creates a new GORM-backed analytics event repository.
+func NewAnalyticsEventRepository() AnalyticsEventRepository {
+	return &analyticsEventRepository{}
+}
+
+func (r *analyticsEventRepository) CreateBatch(exec Executor, events []models.AnalyticsEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return exec.Create(&events).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// legacyAudioKeys matches audio_url values still in the legacy key scheme
+const legacyAudioKeys = "audio_url LIKE 'user/%' OR audio_url LIKE 'assistant/%'"
+
+// audioKeyMigrationRepository implements AudioKeyMigrationRepository using GORM.
+type audioKeyMigrationRepository struct{}
+
+// NewAudioKeyMigrationRepository creates a new GORM-backed audio key migration repository.
+func NewAudioKeyMigrationRepository() AudioKeyMigrationRepository {
+	return &audioKeyMigrationRepository{}
+}
+
+func (r *audioKeyMigrationRepository) FindLegacy(exec Executor, limit int) ([]LegacyAudio, error) {
+	var recordings []LegacyAudio
+	err := exec.Model(&models.Message{}).Select("id, audio_url AS key").
+		Where(legacyAudioKeys).
+		Order("timestamp ASC").
+		Limit(limit).
+		Scan(&recordings).Error
+	if err != nil || len(recordings) == limit {
+		return recordings, err
+	}
+
+	var chunks []LegacyAudio
+	err = exec.Model(&models.MessageChunk{}).Select("id, audio_url AS key, true AS chunk").
+		Where(legacyAudioKeys).
+		Order("created_at ASC").
+		Limit(limit - len(recordings)).
+		Scan(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return append(recordings, chunks...), nil
+}
+
+func (r *audioKeyMigrationRepository) Switch(exec Executor, recording LegacyAudio, key, format string, at time.Time) (bool, error) {
+	var model interface{} = &models.Message{}
+	if recording.Chunk {
+		model = &models.MessageChunk{}
+	}
+	result := exec.Model(model).
+		Where("id = ? AND audio_url IN ?", recording.ID, []string{recording.Key, key}).
+		Updates(map[string]interface{}{"audio_url": key, "audio_format": format})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	migration := &models.AudioKeyMigration{LegacyKey: recording.Key, Key: key, MigratedAt: at}
+	if err := exec.Create(migration).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *audioKeyMigrationRepository) FindMigratedBefore(exec Executor, before time.Time, limit int) ([]models.AudioKeyMigration, error) {
+	var migrations []models.AudioKeyMigration
+	err := exec.Where("migrated_at < ?", before).
+		Order("migrated_at ASC").
+		Limit(limit).
+		Find(&migrations).Error
+	return migrations, err
+}
+
+func (r *audioKeyMigrationRepository) Delete(exec Executor, id uuid.UUID) error {
+	return exec.Delete(&models.AudioKeyMigration{}, "id = ?", id).Error
+}
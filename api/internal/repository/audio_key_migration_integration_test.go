@@ -0,0 +1,79 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestAudioKeyMigrationRepository_SwitchAndExpire(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewAudioKeyMigrationRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Keys"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+
+	legacyKey := fmt.Sprintf("user/%s/%s.webm", thread.ID, uuid.New())
+	message := &models.Message{ThreadID: thread.ID, Role: "user", Content: "hi", AudioURL: &legacyKey, HasAudio: true, Timestamp: time.Now()}
+	require.NoError(t, testDB.Create(message).Error)
+	currentKey := models.UserAudioKey(thread.ID, uuid.New())
+	require.NoError(t, testDB.Create(&models.Message{ThreadID: thread.ID, Role: "user", Content: "new", AudioURL: &currentKey, HasAudio: true, Timestamp: time.Now()}).Error)
+	chunkKey := fmt.Sprintf("user/%s/%s-0.webm", thread.ID, message.ID)
+	chunk := &models.MessageChunk{MessageID: message.ID, AudioURL: &chunkKey, CreatedAt: time.Now()}
+	require.NoError(t, testDB.Create(chunk).Error)
+
+	recordings, err := repo.FindLegacy(exec, 10)
+	require.NoError(t, err)
+	require.Len(t, recordings, 2, "recordings already on the current scheme are skipped")
+	assert.Equal(t, repository.LegacyAudio{ID: message.ID, Key: legacyKey}, recordings[0])
+	assert.Equal(t, repository.LegacyAudio{ID: chunk.ID, Key: chunkKey, Chunk: true}, recordings[1])
+
+	key, format, ok := models.MigratedAudioKey(legacyKey)
+	require.True(t, ok)
+	migratedAt := time.Now().Add(-2 * time.Hour)
+	switched, err := repo.Switch(exec, recordings[0], key, format, migratedAt)
+	require.NoError(t, err)
+	assert.True(t, switched)
+
+	switched, err = repo.Switch(exec, recordings[0], key, format, migratedAt)
+	require.NoError(t, err)
+	assert.True(t, switched, "a row already on the new key counts as switched")
+
+	var stored models.Message
+	require.NoError(t, exec.First(&stored, "id = ?", message.ID).Error)
+	assert.Equal(t, key, *stored.AudioURL)
+	assert.Equal(t, models.AudioFormatWebM, stored.AudioFormat)
+
+	require.NoError(t, exec.Model(&models.MessageChunk{}).Where("id = ?", chunk.ID).Update("audio_url", nil).Error)
+	switched, err = repo.Switch(exec, recordings[1], "v2/elsewhere", format, migratedAt)
+	require.NoError(t, err)
+	assert.False(t, switched, "a recording cleared meanwhile is left alone")
+
+	expired, err := repo.FindMigratedBefore(exec, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 2)
+	assert.Equal(t, legacyKey, expired[0].LegacyKey)
+
+	none, err := repo.FindMigratedBefore(exec, time.Now().Add(-3*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	require.NoError(t, repo.Delete(exec, expired[0].ID))
+	left, err := repo.FindMigratedBefore(exec, time.Now(), 10)
+	require.NoError(t, err)
+	assert.Len(t, left, 1)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"ling-app/api/internal/models"
+)
+
+// auditLogRepository implements AuditLogRepository using GORM.
+type auditLogRepository struct{}
+
+// NewAuditLogRepository creates a new GORM-backed audit log repository.
+func NewAuditLogRepository() AuditLogRepository {
+	return &auditLogRepository{}
+}
+
+func (r *auditLogRepository) Create(exec Executor, entry *models.AuditLog) error {
+	return exec.Create(entry).Error
+}
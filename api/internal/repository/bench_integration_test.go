@@ -0,0 +1,74 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+	"ling-app/api/internal/testutil/factory"
+)
+
+// Database benchmarks for the performance gate (see README). Like the other
+// integration tests they need TEST_DATABASE_URL and skip without it.
+
+func BenchmarkRecordPhonemeResults(b *testing.B) {
+	testDB, user, _ := setupRepoDB(b)
+	stats := services.NewPhonemeStatsService(testDB.DB, repository.NewPhonemeStatsRepository(), repository.NewPhonemeSubstitutionRepository())
+
+	// A long sentence: 40 phonemes, a few of them missed
+	builder := factory.Analysis()
+	for i := range 40 {
+		phoneme := fmt.Sprintf("p%d", i%25)
+		switch i % 8 {
+		case 3:
+			builder.Substitute(phoneme, "s")
+		case 6:
+			builder.Delete(phoneme)
+		default:
+			builder.Match(phoneme)
+		}
+	}
+	details := builder.Build().PhonemeDetails
+
+	for b.Loop() {
+		if err := stats.RecordPhonemeResults(user.ID, details); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkThreadListing(b *testing.B) {
+	testDB, user, _ := setupRepoDB(b)
+
+	// A heavy user: 1000 threads of 10 messages
+	const threadCount, messagesPerThread = 1000, 10
+	start := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Microsecond)
+	threads := make([]models.Thread, threadCount)
+	var messages []models.Message
+	for i := range threads {
+		threads[i] = *factory.Thread(user.ID, func(t *models.Thread) {
+			t.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		})
+		for j := range messagesPerThread {
+			messages = append(messages, *factory.AssistantMessage(threads[i].ID, fmt.Sprintf("thread %d message %d", i, j), func(m *models.Message) {
+				m.Timestamp = threads[i].CreatedAt.Add(time.Duration(j) * time.Second)
+			}))
+		}
+	}
+	require.NoError(b, testDB.CreateInBatches(threads, 200).Error)
+	require.NoError(b, testDB.CreateInBatches(messages, 500).Error)
+
+	repo := repository.NewThreadRepository()
+	for b.Loop() {
+		if _, err := repo.FindSummariesByUserID(testDB.DB.DB, user.ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
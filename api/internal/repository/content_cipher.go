@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/crypt"
+	"ling-app/api/internal/models"
+)
+
+// sealedMapKey holds a sealed analysis in its jsonb column, as
+// {"enc": "enc:v1:..."}, so the column keeps its type
+const sealedMapKey = "enc"
+
+// ContentCipher seals the transcripts and pronunciation analyses of users
+// who turned on UserSettings.EncryptContent, and opens sealed values as they
+// are read. Each user's data key is created on first use, stored wrapped by
+// the master key, and cached unwrapped for the life of the process.
+//
+// Sealed: Message.Content, RawTranscript, SpokenText and
+// PronunciationAnalysis, and MessageChunk.Transcript and
+// PronunciationAnalysis. Everything else, including audio keys, timestamps
+// and scores, stays in the clear so queries and stats keep working.
+type ContentCipher struct {
+	master *crypt.Key
+
+	mu   sync.Mutex
+	keys map[uuid.UUID]*crypt.Key
+}
+
+// NewContentCipher creates a cipher around the master key
+func NewContentCipher(master *crypt.Key) *ContentCipher {
+	return &ContentCipher{master: master, keys: map[uuid.UUID]*crypt.Key{}}
+}
+
+// contentOwner is the user whose content a row holds, and whether they
+// want it encrypted
+type contentOwner struct {
+	UserID         uuid.UUID
+	EncryptContent bool
+}
+
+const contentOwnerColumns = "threads.user_id, COALESCE(user_settings.encrypt_content, false) AS encrypt_content"
+
+func (c *ContentCipher) threadOwner(exec Executor, threadID uuid.UUID) (contentOwner, error) {
+	var owner contentOwner
+	err := exec.Model(&models.Thread{}).
+		Select(contentOwnerColumns).
+		Joins("LEFT JOIN user_settings ON user_settings.user_id = threads.user_id").
+		Where("threads.id = ?", threadID).
+		Scan(&owner).Error
+	return owner, err
+}
+
+func (c *ContentCipher) messageOwner(exec Executor, messageID uuid.UUID) (contentOwner, error) {
+	var owner contentOwner
+	err := exec.Model(&models.Message{}).
+		Select(contentOwnerColumns).
+		Joins("JOIN threads ON threads.id = messages.thread_id").
+		Joins("LEFT JOIN user_settings ON user_settings.user_id = threads.user_id").
+		Where("messages.id = ?", messageID).
+		Scan(&owner).Error
+	return owner, err
+}
+
+func (c *ContentCipher) chunkOwner(exec Executor, chunkID uuid.UUID) (contentOwner, error) {
+	var owner contentOwner
+	err := exec.Model(&models.MessageChunk{}).
+		Select(contentOwnerColumns).
+		Joins("JOIN messages ON messages.id = message_chunks.message_id").
+		Joins("JOIN threads ON threads.id = messages.thread_id").
+		Joins("LEFT JOIN user_settings ON user_settings.user_id = threads.user_id").
+		Where("message_chunks.id = ?", chunkID).
+		Scan(&owner).Error
+	return owner, err
+}
+
+// userKey returns the user's data key, creating it if create is set
+func (c *ContentCipher) userKey(exec Executor, userID uuid.UUID, create bool) (*crypt.Key, error) {
+	c.mu.Lock()
+	key, ok := c.keys[userID]
+	c.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	var stored models.UserContentKey
+	err := exec.Where("user_id = ?", userID).First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && create {
+		_, raw, genErr := crypt.GenerateDataKey()
+		if genErr != nil {
+			return nil, genErr
+		}
+		created := models.UserContentKey{UserID: userID, WrappedKey: c.master.Wrap(userID, raw)}
+		if err := exec.Clauses(clause.OnConflict{DoNothing: true}).Create(&created).Error; err != nil {
+			return nil, fmt.Errorf("create content key: %w", err)
+		}
+		// Read back rather than use ours: a concurrent writer may have won
+		err = exec.Where("user_id = ?", userID).First(&stored).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("no content key for user %s: %w", userID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find content key: %w", err)
+	}
+
+	key, err = c.master.Unwrap(userID, stored.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap content key of user %s: %w", userID, err)
+	}
+	c.mu.Lock()
+	c.keys[userID] = key
+	c.mu.Unlock()
+	return key, nil
+}
+
+// sealString seals s, leaving empty and already sealed values as they are
+func sealString(key *crypt.Key, owner uuid.UUID, s string) string {
+	if s == "" || crypt.IsSealed(s) {
+		return s
+	}
+	return key.Seal(owner, s)
+}
+
+func sealStringPtr(key *crypt.Key, owner uuid.UUID, s *string) *string {
+	if s == nil {
+		return nil
+	}
+	sealed := sealString(key, owner, *s)
+	return &sealed
+}
+
+func isSealedMap(m models.JSONMap) bool {
+	s, ok := m[sealedMapKey].(string)
+	return ok && len(m) == 1 && crypt.IsSealed(s)
+}
+
+func sealMap(key *crypt.Key, owner uuid.UUID, m models.JSONMap) (models.JSONMap, error) {
+	if m == nil || isSealedMap(m) {
+		return m, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("encode analysis: %w", err)
+	}
+	return models.JSONMap{sealedMapKey: key.Seal(owner, string(data))}, nil
+}
+
+// openString opens s if it is sealed, with its owner's key
+func (c *ContentCipher) openString(exec Executor, s string) (string, error) {
+	if !crypt.IsSealed(s) {
+		return s, nil
+	}
+	owner, err := crypt.Owner(s)
+	if err != nil {
+		return "", err
+	}
+	key, err := c.userKey(exec, owner, false)
+	if err != nil {
+		return "", err
+	}
+	return key.Open(s)
+}
+
+func (c *ContentCipher) openStringPtr(exec Executor, s *string) (*string, error) {
+	if s == nil {
+		return nil, nil
+	}
+	opened, err := c.openString(exec, *s)
+	return &opened, err
+}
+
+func (c *ContentCipher) openMap(exec Executor, m models.JSONMap) (models.JSONMap, error) {
+	if !isSealedMap(m) {
+		return m, nil
+	}
+	data, err := c.openString(exec, m[sealedMapKey].(string))
+	if err != nil {
+		return nil, err
+	}
+	var opened models.JSONMap
+	if err := json.Unmarshal([]byte(data), &opened); err != nil {
+		return nil, fmt.Errorf("decode analysis: %w", err)
+	}
+	return opened, nil
+}
+
+// sealMessage seals a message's content fields in place
+func sealMessage(key *crypt.Key, owner uuid.UUID, m *models.Message) error {
+	analysis, err := sealMap(key, owner, m.PronunciationAnalysis)
+	if err != nil {
+		return err
+	}
+	m.Content = sealString(key, owner, m.Content)
+	m.RawTranscript = sealStringPtr(key, owner, m.RawTranscript)
+	m.SpokenText = sealStringPtr(key, owner, m.SpokenText)
+	m.PronunciationAnalysis = analysis
+	return nil
+}
+
+// openMessage opens a message's sealed fields in place
+func (c *ContentCipher) openMessage(exec Executor, m *models.Message) error {
+	var err error
+	if m.Content, err = c.openString(exec, m.Content); err != nil {
+		return fmt.Errorf("open message %s: %w", m.ID, err)
+	}
+	if m.RawTranscript, err = c.openStringPtr(exec, m.RawTranscript); err != nil {
+		return fmt.Errorf("open message %s: %w", m.ID, err)
+	}
+	if m.SpokenText, err = c.openStringPtr(exec, m.SpokenText); err != nil {
+		return fmt.Errorf("open message %s: %w", m.ID, err)
+	}
+	if m.PronunciationAnalysis, err = c.openMap(exec, m.PronunciationAnalysis); err != nil {
+		return fmt.Errorf("open message %s: %w", m.ID, err)
+	}
+	return nil
+}
+
+func (c *ContentCipher) openMessages(exec Executor, messages []models.Message) error {
+	for i := range messages {
+		if err := c.openMessage(exec, &messages[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sealChunk seals a chunk's transcript and analysis in place
+func sealChunk(key *crypt.Key, owner uuid.UUID, chunk *models.MessageChunk) error {
+	analysis, err := sealMap(key, owner, chunk.PronunciationAnalysis)
+	if err != nil {
+		return err
+	}
+	chunk.Transcript = sealString(key, owner, chunk.Transcript)
+	chunk.PronunciationAnalysis = analysis
+	return nil
+}
+
+func (c *ContentCipher) openChunks(exec Executor, chunks []models.MessageChunk) error {
+	for i := range chunks {
+		var err error
+		if chunks[i].Transcript, err = c.openString(exec, chunks[i].Transcript); err != nil {
+			return fmt.Errorf("open chunk %s: %w", chunks[i].ID, err)
+		}
+		if chunks[i].PronunciationAnalysis, err = c.openMap(exec, chunks[i].PronunciationAnalysis); err != nil {
+			return fmt.Errorf("open chunk %s: %w", chunks[i].ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/crypt"
+	"ling-app/api/internal/models"
+)
+
+// contentEncryptionRepository implements ContentEncryptionRepository using GORM.
+type contentEncryptionRepository struct {
+	cipher *ContentCipher
+}
+
+// NewContentEncryptionRepository creates a repository that seals existing
+// content with the given cipher
+func NewContentEncryptionRepository(cipher *ContentCipher) ContentEncryptionRepository {
+	return &contentEncryptionRepository{cipher: cipher}
+}
+
+// ownerRow maps a thread or message to its user
+type ownerRow struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (r *contentEncryptionRepository) optedInThreads(exec Executor) interface{} {
+	return exec.Model(&models.Thread{}).Select("threads.id").
+		Joins("JOIN user_settings ON user_settings.user_id = threads.user_id").
+		Where("user_settings.encrypt_content")
+}
+
+// SealPendingMessages seals messages of opted-in users that still hold
+// plaintext. Each row is only updated if it hasn't changed since it was read.
+func (r *contentEncryptionRepository) SealPendingMessages(exec Executor, limit int) (int, error) {
+	var messages []models.Message
+	err := exec.Where("thread_id IN (?)", r.optedInThreads(exec)).
+		Where(`(content <> '' AND NOT starts_with(content, @prefix))
+			OR (raw_transcript <> '' AND NOT starts_with(raw_transcript, @prefix))
+			OR (spoken_text <> '' AND NOT starts_with(spoken_text, @prefix))
+			OR (pronunciation_analysis IS NOT NULL AND pronunciation_analysis->>@key IS NULL)`,
+			sql.Named("prefix", crypt.SealedPrefix), sql.Named("key", sealedMapKey)).
+		Order("timestamp ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	threadIDs := make([]uuid.UUID, 0, len(messages))
+	for _, m := range messages {
+		threadIDs = append(threadIDs, m.ThreadID)
+	}
+	var owners []ownerRow
+	if err := exec.Model(&models.Thread{}).Select("id, user_id").Where("id IN ?", threadIDs).Scan(&owners).Error; err != nil {
+		return 0, err
+	}
+	ownerOf := make(map[uuid.UUID]uuid.UUID, len(owners))
+	for _, o := range owners {
+		ownerOf[o.ID] = o.UserID
+	}
+
+	sealed := 0
+	for _, m := range messages {
+		owner := ownerOf[m.ThreadID]
+		key, err := r.cipher.userKey(exec, owner, true)
+		if err != nil {
+			return sealed, err
+		}
+		next := m
+		if err := sealMessage(key, owner, &next); err != nil {
+			return sealed, err
+		}
+		result := exec.Model(&models.Message{}).
+			Where("id = ? AND content = ? AND pronunciation_updated_at IS NOT DISTINCT FROM ?", m.ID, m.Content, m.PronunciationUpdatedAt).
+			Updates(map[string]interface{}{
+				"content":                next.Content,
+				"raw_transcript":         next.RawTranscript,
+				"spoken_text":            next.SpokenText,
+				"pronunciation_analysis": next.PronunciationAnalysis,
+			})
+		if result.Error != nil {
+			return sealed, result.Error
+		}
+		sealed += int(result.RowsAffected)
+	}
+	return sealed, nil
+}
+
+// SealPendingChunks seals long-form chunks of opted-in users that still hold
+// plaintext
+func (r *contentEncryptionRepository) SealPendingChunks(exec Executor, limit int) (int, error) {
+	var chunks []models.MessageChunk
+	err := exec.Where("message_id IN (?)", exec.Model(&models.Message{}).Select("id").
+		Where("thread_id IN (?)", r.optedInThreads(exec))).
+		Where(`(transcript <> '' AND NOT starts_with(transcript, @prefix))
+			OR (pronunciation_analysis IS NOT NULL AND pronunciation_analysis->>@key IS NULL)`,
+			sql.Named("prefix", crypt.SealedPrefix), sql.Named("key", sealedMapKey)).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&chunks).Error
+	if err != nil || len(chunks) == 0 {
+		return 0, err
+	}
+
+	messageIDs := make([]uuid.UUID, 0, len(chunks))
+	for _, c := range chunks {
+		messageIDs = append(messageIDs, c.MessageID)
+	}
+	var owners []ownerRow
+	err = exec.Model(&models.Message{}).Select("messages.id, threads.user_id").
+		Joins("JOIN threads ON threads.id = messages.thread_id").
+		Where("messages.id IN ?", messageIDs).
+		Scan(&owners).Error
+	if err != nil {
+		return 0, err
+	}
+	ownerOf := make(map[uuid.UUID]uuid.UUID, len(owners))
+	for _, o := range owners {
+		ownerOf[o.ID] = o.UserID
+	}
+
+	sealed := 0
+	for _, c := range chunks {
+		owner := ownerOf[c.MessageID]
+		key, err := r.cipher.userKey(exec, owner, true)
+		if err != nil {
+			return sealed, err
+		}
+		next := c
+		if err := sealChunk(key, owner, &next); err != nil {
+			return sealed, err
+		}
+		result := exec.Model(&models.MessageChunk{}).
+			Where("id = ? AND transcript = ? AND pronunciation_updated_at IS NOT DISTINCT FROM ?", c.ID, c.Transcript, c.PronunciationUpdatedAt).
+			Updates(map[string]interface{}{
+				"transcript":             next.Transcript,
+				"pronunciation_analysis": next.PronunciationAnalysis,
+			})
+		if result.Error != nil {
+			return sealed, result.Error
+		}
+		sealed += int(result.RowsAffected)
+	}
+	return sealed, nil
+}
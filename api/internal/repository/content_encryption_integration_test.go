@@ -0,0 +1,149 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/crypt"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func newTestCipher(t *testing.T) *repository.ContentCipher {
+	t.Helper()
+	master, err := crypt.ParseKey(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	require.NoError(t, err)
+	return repository.NewContentCipher(master)
+}
+
+// createEncryptionUser creates a user with a thread and content encryption set as given
+func createEncryptionUser(t *testing.T, testDB *testutil.TestDB, encrypt bool) (*models.User, *models.Thread) {
+	t.Helper()
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Encryption"}
+	require.NoError(t, testDB.Create(user).Error)
+	settings := models.DefaultUserSettings(user.ID)
+	settings.EncryptContent = encrypt
+	require.NoError(t, testDB.Create(settings).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	return user, thread
+}
+
+func TestEncryptedMessageRepository_RoundTrip(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	cipher := newTestCipher(t)
+	messages := repository.NewEncryptedMessageRepository(repository.NewMessageRepository(), cipher)
+	threads := repository.NewEncryptedThreadRepository(repository.NewThreadRepository(), cipher)
+
+	user, thread := createEncryptionUser(t, testDB, true)
+	raw := "dónde está la biblioteka"
+	message := &models.Message{
+		ThreadID:      thread.ID,
+		Role:          "user",
+		Content:       "¿Dónde está la biblioteca?",
+		RawTranscript: &raw,
+		Timestamp:     time.Now(),
+	}
+	require.NoError(t, messages.Create(testDB.DB.DB, message))
+	assert.Equal(t, "¿Dónde está la biblioteca?", message.Content, "the caller keeps its plaintext")
+
+	var stored models.Message
+	require.NoError(t, testDB.First(&stored, "id = ?", message.ID).Error)
+	assert.True(t, crypt.IsSealed(stored.Content), "content is sealed at rest")
+	assert.NotContains(t, *stored.RawTranscript, "biblioteka")
+
+	analysis := models.JSONMap{"overallScore": 82.0}
+	require.NoError(t, messages.UpdatePronunciationAnalysis(testDB.DB.DB, message.ID, "complete", analysis, "accurate", 0.9, false, time.Now()))
+	require.NoError(t, testDB.First(&stored, "id = ?", message.ID).Error)
+	assert.NotContains(t, stored.PronunciationAnalysis, "overallScore", "the analysis is sealed at rest")
+
+	found, err := messages.FindByID(testDB.DB.DB, message.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "¿Dónde está la biblioteca?", found.Content)
+	assert.Equal(t, raw, *found.RawTranscript)
+	assert.Equal(t, 82.0, found.PronunciationAnalysis["overallScore"])
+
+	withMessages, err := threads.FindByIDAndUserIDWithMessages(testDB.DB.DB, thread.ID, user.ID, true)
+	require.NoError(t, err)
+	require.Len(t, withMessages.Messages, 1)
+	assert.Equal(t, "¿Dónde está la biblioteca?", withMessages.Messages[0].Content)
+
+	summaries, err := threads.FindSummariesByUserID(testDB.DB.DB, user.ID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.NotNil(t, summaries[0].LastMessagePreview)
+	assert.Equal(t, "¿Dónde está la biblioteca?", *summaries[0].LastMessagePreview)
+}
+
+func TestEncryptedMessageRepository_OptedOutStaysPlain(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	messages := repository.NewEncryptedMessageRepository(repository.NewMessageRepository(), newTestCipher(t))
+
+	_, thread := createEncryptionUser(t, testDB, false)
+	message := &models.Message{ThreadID: thread.ID, Role: "user", Content: "hola", Timestamp: time.Now()}
+	require.NoError(t, messages.Create(testDB.DB.DB, message))
+
+	var stored models.Message
+	require.NoError(t, testDB.First(&stored, "id = ?", message.ID).Error)
+	assert.Equal(t, "hola", stored.Content)
+
+	var keys int64
+	require.NoError(t, testDB.Model(&models.UserContentKey{}).Count(&keys).Error)
+	assert.Zero(t, keys, "no key is made for users who haven't opted in")
+}
+
+func TestContentEncryptionRepository_SealPendingMessages(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	cipher := newTestCipher(t)
+	repo := repository.NewContentEncryptionRepository(cipher)
+	messages := repository.NewEncryptedMessageRepository(repository.NewMessageRepository(), cipher)
+
+	_, optedIn := createEncryptionUser(t, testDB, true)
+	_, optedOut := createEncryptionUser(t, testDB, false)
+	now := time.Now()
+	plain := []models.Message{
+		{ThreadID: optedIn.ID, Role: "user", Content: "first", Timestamp: now, PronunciationAnalysis: models.JSONMap{"overallScore": 70.0}},
+		{ThreadID: optedIn.ID, Role: "assistant", Content: "second", Timestamp: now.Add(time.Second)},
+		{ThreadID: optedOut.ID, Role: "user", Content: "left alone", Timestamp: now},
+	}
+	require.NoError(t, testDB.Create(&plain).Error)
+
+	sealed, err := repo.SealPendingMessages(testDB.DB.DB, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sealed, "the limit caps a batch")
+	sealed, err = repo.SealPendingMessages(testDB.DB.DB, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sealed)
+	sealed, err = repo.SealPendingMessages(testDB.DB.DB, 100)
+	require.NoError(t, err)
+	assert.Zero(t, sealed, "nothing is left to seal")
+
+	var stored []models.Message
+	require.NoError(t, testDB.Order("timestamp ASC").Find(&stored, "thread_id = ?", optedIn.ID).Error)
+	for _, m := range stored {
+		assert.True(t, strings.HasPrefix(m.Content, crypt.SealedPrefix))
+	}
+	var untouched models.Message
+	require.NoError(t, testDB.First(&untouched, "id = ?", plain[2].ID).Error)
+	assert.Equal(t, "left alone", untouched.Content)
+
+	found, err := messages.FindByThreadID(testDB.DB.DB, optedIn.ID)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "first", found[0].Content)
+	assert.Equal(t, 70.0, found[0].PronunciationAnalysis["overallScore"])
+	assert.Equal(t, "second", found[1].Content)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+)
+
+// creditDisputeRepository implements CreditDisputeRepository using GORM.
+type creditDisputeRepository struct{}
+
+// NewCreditDisputeRepository creates a new GORM-backed credit dispute repository.
+func NewCreditDisputeRepository() CreditDisputeRepository {
+	return &creditDisputeRepository{}
+}
+
+func (r *creditDisputeRepository) Create(exec Executor, dispute *models.CreditDispute) error {
+	return exec.Create(dispute).Error
+}
+
+func (r *creditDisputeRepository) FindByTransactionID(exec Executor, transactionID uuid.UUID) (*models.CreditDispute, error) {
+	var dispute models.CreditDispute
+	err := exec.Where("transaction_id = ?", transactionID).First(&dispute).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
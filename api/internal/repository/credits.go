@@ -2,9 +2,11 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"ling-app/api/internal/models"
 )
@@ -29,6 +31,18 @@ func (r *creditsRepository) FindByUserID(exec Executor, userID uuid.UUID) (*mode
 	return &credits, nil
 }
 
+func (r *creditsRepository) FindByUserIDForUpdate(exec Executor, userID uuid.UUID) (*models.Credits, error) {
+	var credits models.Credits
+	err := exec.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&credits).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &credits, nil
+}
+
 func (r *creditsRepository) Create(exec Executor, credits *models.Credits) error {
 	return exec.Create(credits).Error
 }
@@ -41,6 +55,40 @@ func (r *creditsRepository) UpdateAllowance(exec Executor, userID uuid.UUID, all
 	return exec.Model(&models.Credits{}).Where("user_id = ?", userID).Update("monthly_allowance", allowance).Error
 }
 
+func (r *creditsRepository) FindFreeRefreshDue(exec Executor, refreshedBefore time.Time, limit int) ([]models.Credits, error) {
+	var credits []models.Credits
+	err := exec.Select("credits.*").
+		Joins("JOIN users ON users.id = credits.user_id").
+		Joins("LEFT JOIN subscriptions ON subscriptions.user_id = credits.user_id").
+		Where("credits.last_refreshed_at < ?", refreshedBefore).
+		Where("users.guest_expires_at IS NULL AND users.merged_into_id IS NULL").
+		Where("subscriptions.id IS NULL OR (subscriptions.tier = ? AND subscriptions.grace_tier IS NULL)", models.TierFree).
+		Order("credits.last_refreshed_at ASC").
+		Limit(limit).
+		Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// Refresh only matches the row as it was read, so when two instances sweep
+// at once the second update matches nothing and the month is granted once
+func (r *creditsRepository) Refresh(exec Executor, credits *models.Credits, balance int, refreshedAt time.Time) (bool, error) {
+	result := exec.Model(&models.Credits{}).
+		Where("id = ? AND last_refreshed_at = ? AND balance = ? AND monthly_allowance = ?",
+			credits.ID, credits.LastRefreshedAt, credits.Balance, credits.MonthlyAllowance).
+		Updates(map[string]interface{}{
+			"balance":           balance,
+			"used_this_period":  0,
+			"last_refreshed_at": refreshedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // creditTransactionRepository implements CreditTransactionRepository using GORM.
 type creditTransactionRepository struct{}
 
@@ -53,10 +101,11 @@ func (r *creditTransactionRepository) Create(exec Executor, tx *models.CreditTra
 	return exec.Create(tx).Error
 }
 
-func (r *creditTransactionRepository) FindByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.CreditTransaction, error) {
+func (r *creditTransactionRepository) FindByUserID(exec Executor, userID uuid.UUID, limit, offset int) ([]models.CreditTransaction, error) {
 	var transactions []models.CreditTransaction
 	err := exec.Where("user_id = ?", userID).
 		Order("created_at DESC").
+		Offset(offset).
 		Limit(limit).
 		Find(&transactions).Error
 	if err != nil {
@@ -64,3 +113,102 @@ func (r *creditTransactionRepository) FindByUserID(exec Executor, userID uuid.UU
 	}
 	return transactions, nil
 }
+
+func (r *creditTransactionRepository) FindByIDAndUserID(exec Executor, id, userID uuid.UUID) (*models.CreditTransaction, error) {
+	var transaction models.CreditTransaction
+	err := exec.Where("id = ? AND user_id = ?", id, userID).First(&transaction).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// creditReservationRepository implements CreditReservationRepository using GORM.
+type creditReservationRepository struct{}
+
+// NewCreditReservationRepository creates a new GORM-backed credit reservation repository.
+func NewCreditReservationRepository() CreditReservationRepository {
+	return &creditReservationRepository{}
+}
+
+func (r *creditReservationRepository) Create(exec Executor, reservation *models.CreditReservation) error {
+	return exec.Create(reservation).Error
+}
+
+func (r *creditReservationRepository) FindByID(exec Executor, id uuid.UUID) (*models.CreditReservation, error) {
+	var reservation models.CreditReservation
+	err := exec.Where("id = ?", id).First(&reservation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *creditReservationRepository) FindByReference(exec Executor, reference string) (*models.CreditReservation, error) {
+	var reservation models.CreditReservation
+	err := exec.Where("reference = ?", reference).Order("created_at DESC").First(&reservation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *creditReservationRepository) MarkRefunded(exec Executor, id uuid.UUID, refundedAt time.Time) (bool, error) {
+	result := exec.Model(&models.CreditReservation{}).
+		Where("id = ? AND status = ?", id, models.ReservationCaptured).
+		Updates(map[string]any{"status": models.ReservationRefunded, "refunded_at": refundedAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *creditReservationRepository) Settle(exec Executor, id uuid.UUID, status models.CreditReservationStatus, settledAt time.Time) (bool, error) {
+	result := exec.Model(&models.CreditReservation{}).
+		Where("id = ? AND status = ?", id, models.ReservationHeld).
+		Updates(map[string]any{"status": status, "settled_at": settledAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *creditReservationRepository) Reduce(exec Executor, id uuid.UUID, from, to int) (bool, error) {
+	result := exec.Model(&models.CreditReservation{}).
+		Where("id = ? AND status = ? AND amount = ?", id, models.ReservationHeld, from).
+		Update("amount", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *creditReservationRepository) FindExpired(exec Executor, now time.Time, limit int) ([]models.CreditReservation, error) {
+	var reservations []models.CreditReservation
+	err := exec.Where("status = ? AND expires_at < ?", models.ReservationHeld, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *creditReservationRepository) SumHeld(exec Executor, userID uuid.UUID) (int, error) {
+	var held int
+	err := exec.Model(&models.CreditReservation{}).
+		Where("user_id = ? AND status = ?", userID, models.ReservationHeld).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&held).Error
+	return held, err
+}
@@ -0,0 +1,170 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestCreditsRepository_FreeRefresh(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewCreditsRepository()
+	exec := testDB.DB.DB
+
+	now := time.Now().UTC().Truncate(time.Second)
+	stale := now.AddDate(0, -1, -1)
+	proTier := models.TierPro
+	guestExpiresAt := now.Add(time.Hour)
+
+	account := func(refreshedAt time.Time, guestUntil *time.Time, sub *models.Subscription) uuid.UUID {
+		user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Refresh", GuestExpiresAt: guestUntil}
+		require.NoError(t, testDB.Create(user).Error)
+		require.NoError(t, testDB.Create(&models.Credits{
+			UserID: user.ID, Balance: 3, MonthlyAllowance: 20, UsedThisPeriod: 17, LastRefreshedAt: refreshedAt,
+		}).Error)
+		if sub != nil {
+			sub.UserID = user.ID
+			require.NoError(t, testDB.Create(sub).Error)
+		}
+		return user.ID
+	}
+
+	noSubscription := account(stale, nil, nil)
+	freeSubscription := account(stale, nil, &models.Subscription{Tier: models.TierFree, Status: "canceled"})
+	recent := account(now.AddDate(0, 0, -3), nil, nil)
+	paid := account(stale, nil, &models.Subscription{Tier: models.TierPro, Status: "active"})
+	inGrace := account(stale, nil, &models.Subscription{Tier: models.TierFree, Status: "canceled", GraceTier: &proTier})
+	guest := account(stale, &guestExpiresAt, nil)
+
+	due, err := repo.FindFreeRefreshDue(exec, now.AddDate(0, -1, 0), 1000)
+	require.NoError(t, err)
+	found := map[uuid.UUID]models.Credits{}
+	for _, c := range due {
+		found[c.UserID] = c
+	}
+	assert.Contains(t, found, noSubscription)
+	assert.Contains(t, found, freeSubscription)
+	for _, id := range []uuid.UUID{recent, paid, inGrace, guest} {
+		assert.NotContains(t, found, id)
+	}
+
+	// Two instances that read the same row: only the first refresh applies
+	credits := found[noSubscription]
+	refreshed, err := repo.Refresh(exec, &credits, credits.MonthlyAllowance, now)
+	require.NoError(t, err)
+	assert.True(t, refreshed)
+	refreshed, err = repo.Refresh(exec, &credits, credits.MonthlyAllowance, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.False(t, refreshed)
+
+	after, err := repo.FindByUserID(exec, noSubscription)
+	require.NoError(t, err)
+	assert.Equal(t, 20, after.Balance)
+	assert.Equal(t, 0, after.UsedThisPeriod)
+	assert.True(t, after.LastRefreshedAt.Equal(now))
+}
+
+func TestCreditReservationRepository_FindExpired(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewCreditReservationRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Holds"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	hold := func(status models.CreditReservationStatus, expiresAt time.Time) uuid.UUID {
+		reservation := &models.CreditReservation{UserID: user.ID, Amount: 1, Status: status, ExpiresAt: expiresAt}
+		require.NoError(t, repo.Create(exec, reservation))
+		return reservation.ID
+	}
+	older := hold(models.ReservationHeld, now.Add(-time.Hour))
+	newer := hold(models.ReservationHeld, now.Add(-time.Minute))
+	hold(models.ReservationHeld, now.Add(time.Minute))
+	hold(models.ReservationCaptured, now.Add(-time.Hour))
+
+	expired, err := repo.FindExpired(exec, now, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 2, "only held reservations past their expiry")
+	assert.Equal(t, older, expired[0].ID)
+	assert.Equal(t, newer, expired[1].ID)
+
+	settled, err := repo.Settle(exec, older, models.ReservationExpired, now)
+	require.NoError(t, err)
+	assert.True(t, settled)
+	expired, err = repo.FindExpired(exec, now, 10)
+	require.NoError(t, err)
+	assert.Len(t, expired, 1)
+}
+
+func TestCreditReservationRepository_Reduce(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewCreditReservationRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Holds"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	reservation := &models.CreditReservation{UserID: user.ID, Amount: 10, Status: models.ReservationHeld, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.Create(exec, reservation))
+
+	reduced, err := repo.Reduce(exec, reservation.ID, 10, 4)
+	require.NoError(t, err)
+	assert.True(t, reduced)
+
+	// The amount changed, so a second reduction from 10 finds nothing
+	reduced, err = repo.Reduce(exec, reservation.ID, 10, 2)
+	require.NoError(t, err)
+	assert.False(t, reduced)
+
+	found, err := repo.FindByID(exec, reservation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, found.Amount)
+
+	_, err = repo.Settle(exec, reservation.ID, models.ReservationCaptured, time.Now())
+	require.NoError(t, err)
+	reduced, err = repo.Reduce(exec, reservation.ID, 4, 2)
+	require.NoError(t, err)
+	assert.False(t, reduced, "a captured reservation keeps its amount")
+}
+
+func TestCreditReservationRepository_SumHeld(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewCreditReservationRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Holds"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	held, err := repo.SumHeld(exec, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, held)
+
+	expiresAt := time.Now().Add(time.Hour)
+	for _, reservation := range []*models.CreditReservation{
+		{UserID: user.ID, Amount: 2, Status: models.ReservationHeld, ExpiresAt: expiresAt},
+		{UserID: user.ID, Amount: 5, Status: models.ReservationHeld, ExpiresAt: expiresAt},
+		{UserID: user.ID, Amount: 7, Status: models.ReservationCaptured, ExpiresAt: expiresAt},
+		{UserID: user.ID, Amount: 11, Status: models.ReservationReleased, ExpiresAt: expiresAt},
+	} {
+		require.NoError(t, repo.Create(exec, reservation))
+	}
+
+	held, err = repo.SumHeld(exec, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, held)
+}
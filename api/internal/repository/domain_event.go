@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// domainEventRepository implements DomainEventRepository using GORM.
+type domainEventRepository struct{}
+
+// NewDomainEventRepository creates a new GORM-backed domain event repository.
+func NewDomainEventRepository() DomainEventRepository {
+	return &domainEventRepository{}
+}
+
+func (r *domainEventRepository) Create(exec Executor, event *models.DomainEvent) error {
+	return exec.Create(event).Error
+}
+
+func (r *domainEventRepository) FindPage(exec Executor, filter DomainEventFilter, after *models.DomainEvent, limit int) ([]models.DomainEvent, error) {
+	query := exec.Model(&models.DomainEvent{})
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if len(filter.Names) > 0 {
+		query = query.Where("name IN ?", filter.Names)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+	if after != nil {
+		query = query.Where("(occurred_at, id) > (?, ?)", after.OccurredAt, after.ID)
+	}
+
+	var events []models.DomainEvent
+	err := query.Order("occurred_at ASC, id ASC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestDomainEventRepository_FindPage(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewDomainEventRepository()
+	exec := testDB.DB.DB
+
+	users := make([]uuid.UUID, 2)
+	for i := range users {
+		user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Events"}
+		require.NoError(t, testDB.Create(user).Error)
+		users[i] = user.ID
+	}
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	logged := []models.DomainEvent{
+		{Name: "credits.low", UserID: users[0], Payload: models.JSONMap{"balance": 4}, OccurredAt: start},
+		{Name: "message.processed", UserID: users[0], Payload: models.JSONMap{}, OccurredAt: start.Add(time.Hour)},
+		{Name: "credits.low", UserID: users[1], Payload: models.JSONMap{"balance": 2}, OccurredAt: start.Add(2 * time.Hour)},
+		{Name: "credits.low", UserID: users[0], Payload: models.JSONMap{"balance": 1}, OccurredAt: start.Add(3 * time.Hour)},
+	}
+	for i := range logged {
+		require.NoError(t, repo.Create(exec, &logged[i]))
+	}
+
+	filter := repository.DomainEventFilter{UserID: users[0], Names: []string{"credits.low"}}
+	first, err := repo.FindPage(exec, filter, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, logged[0].ID, first[0].ID)
+	assert.Equal(t, float64(4), first[0].Payload["balance"])
+
+	rest, err := repo.FindPage(exec, filter, &first[0], 10)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, logged[3].ID, rest[0].ID)
+
+	ranged, err := repo.FindPage(exec, repository.DomainEventFilter{From: start.Add(time.Hour), To: start.Add(3 * time.Hour)}, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, ranged, 2)
+	assert.Equal(t, logged[1].ID, ranged[0].ID)
+	assert.Equal(t, logged[2].ID, ranged[1].ID)
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// emailDeliveryRepository implements EmailDeliveryRepository using GORM.
+type emailDeliveryRepository struct{}
+
+// NewEmailDeliveryRepository creates a new GORM-backed email delivery repository.
+func NewEmailDeliveryRepository() EmailDeliveryRepository {
+	return &emailDeliveryRepository{}
+}
+
+func (r *emailDeliveryRepository) Create(exec Executor, delivery *models.EmailDelivery) error {
+	return exec.Create(delivery).Error
+}
+
+func (r *emailDeliveryRepository) CountSince(exec Executor, userID uuid.UUID, kind string, since time.Time) (int64, error) {
+	var count int64
+	err := exec.Model(&models.EmailDelivery{}).
+		Where("user_id = ? AND kind = ? AND sent_at >= ?", userID, kind, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *emailDeliveryRepository) LastSentAt(exec Executor, userID uuid.UUID, kind string) (*time.Time, error) {
+	var deliveries []models.EmailDelivery
+	err := exec.Where("user_id = ? AND kind = ?", userID, kind).
+		Order("sent_at DESC").Limit(1).Find(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return &deliveries[0].SentAt, nil
+}
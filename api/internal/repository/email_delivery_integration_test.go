@@ -0,0 +1,78 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestNotificationRepository_PendingEmail(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewNotificationRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Email"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	now := time.Now().UTC()
+	later := &models.Notification{UserID: user.ID, Type: models.NotificationExportReady, Title: "later", EmailStatus: models.EmailStatusPending, CreatedAt: now}
+	earlier := &models.Notification{UserID: user.ID, Type: models.NotificationGoalCompleted, Title: "earlier", EmailStatus: models.EmailStatusPending, CreatedAt: now.Add(-time.Hour)}
+	inAppOnly := &models.Notification{UserID: user.ID, Type: models.NotificationPaymentFailed, Title: "in-app", CreatedAt: now}
+	for _, n := range []*models.Notification{later, earlier, inAppOnly} {
+		require.NoError(t, repo.Create(exec, n))
+	}
+
+	users, err := repo.FindUsersWithPendingEmail(exec)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{user.ID}, users)
+
+	pending, err := repo.FindPendingEmailByUserID(exec, user.ID)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "earlier", pending[0].Title, "oldest first")
+
+	require.NoError(t, repo.SetEmailStatus(exec, []uuid.UUID{earlier.ID, later.ID}, models.EmailStatusSent))
+	users, err = repo.FindUsersWithPendingEmail(exec)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestEmailDeliveryRepository(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewEmailDeliveryRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Email"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	last, err := repo.LastSentAt(exec, user.ID, models.EmailDeliveryDigest)
+	require.NoError(t, err)
+	assert.Nil(t, last, "never sent")
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	for _, sentAt := range []time.Time{now.Add(-30 * time.Hour), now.Add(-2 * time.Hour), now} {
+		require.NoError(t, repo.Create(exec, &models.EmailDelivery{UserID: user.ID, Kind: models.EmailDeliveryInstant, Notifications: 1, SentAt: sentAt}))
+	}
+	require.NoError(t, repo.Create(exec, &models.EmailDelivery{UserID: user.ID, Kind: models.EmailDeliveryDigest, Notifications: 4, SentAt: now.Add(-time.Hour)}))
+
+	count, err := repo.CountSince(exec, user.ID, models.EmailDeliveryInstant, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	last, err = repo.LastSentAt(exec, user.ID, models.EmailDeliveryDigest)
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	assert.True(t, last.Equal(now.Add(-time.Hour)))
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+)
+
+// emailVerificationRepository implements EmailVerificationRepository using GORM.
+type emailVerificationRepository struct{}
+
+// NewEmailVerificationRepository creates a new GORM-backed email verification repository.
+func NewEmailVerificationRepository() EmailVerificationRepository {
+	return &emailVerificationRepository{}
+}
+
+func (r *emailVerificationRepository) Create(exec Executor, verification *models.EmailVerification) error {
+	return exec.Create(verification).Error
+}
+
+func (r *emailVerificationRepository) FindByTokenHash(exec Executor, tokenHash string) (*models.EmailVerification, error) {
+	var verification models.EmailVerification
+	err := exec.Where("token_hash = ?", tokenHash).First(&verification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+func (r *emailVerificationRepository) Use(exec Executor, id uuid.UUID, now time.Time) (bool, error) {
+	result := exec.Model(&models.EmailVerification{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *emailVerificationRepository) CountSince(exec Executor, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := exec.Model(&models.EmailVerification{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// The encrypted repositories wrap the plain ones with a ContentCipher:
+// writes for users with content encryption on are sealed, and every read
+// opens whatever is sealed, whoever owns it and whatever their setting is now.
+// Callers always see plaintext.
+
+// encryptedMessageRepository seals and opens message content
+type encryptedMessageRepository struct {
+	MessageRepository
+	cipher *ContentCipher
+}
+
+// NewEncryptedMessageRepository wraps a message repository with content encryption
+func NewEncryptedMessageRepository(inner MessageRepository, cipher *ContentCipher) MessageRepository {
+	return &encryptedMessageRepository{MessageRepository: inner, cipher: cipher}
+}
+
+// Create seals the message for the insert, then hands it back in plaintext
+// with the fields the insert filled in
+func (r *encryptedMessageRepository) Create(exec Executor, message *models.Message) error {
+	owner, err := r.cipher.threadOwner(exec, message.ThreadID)
+	if err != nil {
+		return err
+	}
+	if !owner.EncryptContent {
+		return r.MessageRepository.Create(exec, message)
+	}
+
+	key, err := r.cipher.userKey(exec, owner.UserID, true)
+	if err != nil {
+		return err
+	}
+	content, rawTranscript, spokenText, analysis := message.Content, message.RawTranscript, message.SpokenText, message.PronunciationAnalysis
+	defer func() {
+		message.Content, message.RawTranscript, message.SpokenText, message.PronunciationAnalysis = content, rawTranscript, spokenText, analysis
+	}()
+	if err := sealMessage(key, owner.UserID, message); err != nil {
+		return err
+	}
+	return r.MessageRepository.Create(exec, message)
+}
+
+func (r *encryptedMessageRepository) FindByID(exec Executor, id uuid.UUID) (*models.Message, error) {
+	message, err := r.MessageRepository.FindByID(exec, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cipher.openMessage(exec, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (r *encryptedMessageRepository) FindByThreadID(exec Executor, threadID uuid.UUID) ([]models.Message, error) {
+	return r.open(exec)(r.MessageRepository.FindByThreadID(exec, threadID))
+}
+
+func (r *encryptedMessageRepository) FindExpiredUserAudio(exec Executor, now time.Time, limit int) ([]models.Message, error) {
+	return r.open(exec)(r.MessageRepository.FindExpiredUserAudio(exec, now, limit))
+}
+
+func (r *encryptedMessageRepository) FindAnalyzedByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.Message, error) {
+	return r.open(exec)(r.MessageRepository.FindAnalyzedByUserID(exec, userID, limit))
+}
+
+func (r *encryptedMessageRepository) FindUserMessagesByUserID(exec Executor, userID uuid.UUID, before time.Time, limit int) ([]models.Message, error) {
+	return r.open(exec)(r.MessageRepository.FindUserMessagesByUserID(exec, userID, before, limit))
+}
+
+func (r *encryptedMessageRepository) UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	owner, err := r.cipher.messageOwner(exec, id)
+	if err != nil {
+		return err
+	}
+	if owner.EncryptContent {
+		key, err := r.cipher.userKey(exec, owner.UserID, true)
+		if err != nil {
+			return err
+		}
+		if analysis, err = sealMap(key, owner.UserID, analysis); err != nil {
+			return err
+		}
+	}
+	return r.MessageRepository.UpdatePronunciationAnalysis(exec, id, status, analysis, quality, confidence, lowConfidence, updatedAt)
+}
+
+// UpdateContent seals a corrected transcript if the owner has content encryption on
+func (r *encryptedMessageRepository) UpdateContent(exec Executor, id uuid.UUID, content string, correctedAt time.Time) error {
+	owner, err := r.cipher.messageOwner(exec, id)
+	if err != nil {
+		return err
+	}
+	if owner.EncryptContent {
+		key, err := r.cipher.userKey(exec, owner.UserID, true)
+		if err != nil {
+			return err
+		}
+		content = sealString(key, owner.UserID, content)
+	}
+	return r.MessageRepository.UpdateContent(exec, id, content, correctedAt)
+}
+
+// open returns a function that opens the messages of a find
+func (r *encryptedMessageRepository) open(exec Executor) func([]models.Message, error) ([]models.Message, error) {
+	return func(messages []models.Message, err error) ([]models.Message, error) {
+		if err != nil {
+			return nil, err
+		}
+		if err := r.cipher.openMessages(exec, messages); err != nil {
+			return nil, err
+		}
+		return messages, nil
+	}
+}
+
+// encryptedThreadRepository opens the messages and previews loaded with threads
+type encryptedThreadRepository struct {
+	ThreadRepository
+	cipher *ContentCipher
+}
+
+// NewEncryptedThreadRepository wraps a thread repository with content encryption
+func NewEncryptedThreadRepository(inner ThreadRepository, cipher *ContentCipher) ThreadRepository {
+	return &encryptedThreadRepository{ThreadRepository: inner, cipher: cipher}
+}
+
+func (r *encryptedThreadRepository) FindByIDWithMessages(exec Executor, id uuid.UUID) (*models.Thread, error) {
+	return r.open(exec)(r.ThreadRepository.FindByIDWithMessages(exec, id))
+}
+
+func (r *encryptedThreadRepository) FindByIDAndUserIDWithMessages(exec Executor, id, userID uuid.UUID, withAnalysis bool) (*models.Thread, error) {
+	return r.open(exec)(r.ThreadRepository.FindByIDAndUserIDWithMessages(exec, id, userID, withAnalysis))
+}
+
+// FindSummariesByUserID opens the previews, which come back whole when
+// sealed, and cuts them to models.ThreadPreviewLength
+func (r *encryptedThreadRepository) FindSummariesByUserID(exec Executor, userID uuid.UUID) ([]models.ThreadSummary, error) {
+	summaries, err := r.ThreadRepository.FindSummariesByUserID(exec, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		preview := summaries[i].LastMessagePreview
+		if preview == nil {
+			continue
+		}
+		opened, err := r.cipher.openString(exec, *preview)
+		if err != nil {
+			return nil, err
+		}
+		if runes := []rune(opened); len(runes) > models.ThreadPreviewLength {
+			opened = string(runes[:models.ThreadPreviewLength])
+		}
+		summaries[i].LastMessagePreview = &opened
+	}
+	return summaries, nil
+}
+
+func (r *encryptedThreadRepository) open(exec Executor) func(*models.Thread, error) (*models.Thread, error) {
+	return func(thread *models.Thread, err error) (*models.Thread, error) {
+		if err != nil {
+			return nil, err
+		}
+		if err := r.cipher.openMessages(exec, thread.Messages); err != nil {
+			return nil, err
+		}
+		return thread, nil
+	}
+}
+
+// encryptedMessageChunkRepository seals and opens long-form chunk transcripts
+// and analyses
+type encryptedMessageChunkRepository struct {
+	MessageChunkRepository
+	cipher *ContentCipher
+}
+
+// NewEncryptedMessageChunkRepository wraps a message chunk repository with content encryption
+func NewEncryptedMessageChunkRepository(inner MessageChunkRepository, cipher *ContentCipher) MessageChunkRepository {
+	return &encryptedMessageChunkRepository{MessageChunkRepository: inner, cipher: cipher}
+}
+
+// CreateBatch seals the chunks for the insert and hands them back in plaintext
+func (r *encryptedMessageChunkRepository) CreateBatch(exec Executor, chunks []models.MessageChunk) error {
+	owners := map[uuid.UUID]contentOwner{}
+	for _, chunk := range chunks {
+		if _, ok := owners[chunk.MessageID]; ok {
+			continue
+		}
+		owner, err := r.cipher.messageOwner(exec, chunk.MessageID)
+		if err != nil {
+			return err
+		}
+		owners[chunk.MessageID] = owner
+	}
+
+	plain := make([]models.MessageChunk, len(chunks))
+	copy(plain, chunks)
+	defer func() {
+		for i := range chunks {
+			chunks[i].Transcript, chunks[i].PronunciationAnalysis = plain[i].Transcript, plain[i].PronunciationAnalysis
+		}
+	}()
+	for i := range chunks {
+		owner := owners[chunks[i].MessageID]
+		if !owner.EncryptContent {
+			continue
+		}
+		key, err := r.cipher.userKey(exec, owner.UserID, true)
+		if err != nil {
+			return err
+		}
+		if err := sealChunk(key, owner.UserID, &chunks[i]); err != nil {
+			return err
+		}
+	}
+	return r.MessageChunkRepository.CreateBatch(exec, chunks)
+}
+
+func (r *encryptedMessageChunkRepository) FindByMessageID(exec Executor, messageID uuid.UUID) ([]models.MessageChunk, error) {
+	return r.open(exec)(r.MessageChunkRepository.FindByMessageID(exec, messageID))
+}
+
+func (r *encryptedMessageChunkRepository) FindExpiredAudio(exec Executor, now time.Time, limit int) ([]models.MessageChunk, error) {
+	return r.open(exec)(r.MessageChunkRepository.FindExpiredAudio(exec, now, limit))
+}
+
+func (r *encryptedMessageChunkRepository) UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	owner, err := r.cipher.chunkOwner(exec, id)
+	if err != nil {
+		return err
+	}
+	if owner.EncryptContent {
+		key, err := r.cipher.userKey(exec, owner.UserID, true)
+		if err != nil {
+			return err
+		}
+		if analysis, err = sealMap(key, owner.UserID, analysis); err != nil {
+			return err
+		}
+	}
+	return r.MessageChunkRepository.UpdatePronunciationAnalysis(exec, id, status, analysis, quality, confidence, lowConfidence, updatedAt)
+}
+
+func (r *encryptedMessageChunkRepository) open(exec Executor) func([]models.MessageChunk, error) ([]models.MessageChunk, error) {
+	return func(chunks []models.MessageChunk, err error) ([]models.MessageChunk, error) {
+		if err != nil {
+			return nil, err
+		}
+		if err := r.cipher.openChunks(exec, chunks); err != nil {
+			return nil, err
+		}
+		return chunks, nil
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// featureUsageRepository implements FeatureUsageRepository using GORM.
+type featureUsageRepository struct{}
+
+// NewFeatureUsageRepository creates a new GORM-backed feature usage repository.
+func NewFeatureUsageRepository() FeatureUsageRepository {
+	return &featureUsageRepository{}
+}
+
+func (r *featureUsageRepository) Create(exec Executor, event *models.FeatureUsageEvent) error {
+	return exec.Create(event).Error
+}
+
+// RollupDay aggregates the day's events per feature and upserts the rows, so
+// rolling up a day again (e.g. today, as it fills in) replaces its totals
+func (r *featureUsageRepository) RollupDay(exec Executor, day time.Time) error {
+	day = day.UTC().Truncate(24 * time.Hour)
+
+	var rows []models.FeatureUsageDaily
+	err := exec.Model(&models.FeatureUsageEvent{}).
+		Select(`feature,
+			COUNT(*) AS uses,
+			COUNT(*) FILTER (WHERE NOT success) AS failures,
+			COUNT(DISTINCT user_id) AS users,
+			COALESCE(SUM(credits), 0) AS credits,
+			ROUND(AVG(latency_ms))::bigint AS avg_latency_ms,
+			ROUND(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms))::bigint AS p95_latency_ms`).
+		Where("created_at >= ? AND created_at < ?", day, day.Add(24*time.Hour)).
+		Group("feature").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for i := range rows {
+		rows[i].Day = day
+	}
+	return exec.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "day"}, {Name: "feature"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"uses", "failures", "users", "credits", "avg_latency_ms", "p95_latency_ms", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+func (r *featureUsageRepository) FindDaily(exec Executor, from, to time.Time) ([]models.FeatureUsageDaily, error) {
+	var rows []models.FeatureUsageDaily
+	err := exec.Where("day >= ? AND day <= ?", from.UTC().Truncate(24*time.Hour), to.UTC().Truncate(24*time.Hour)).
+		Order("day ASC, feature ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *featureUsageRepository) DeleteEventsBefore(exec Executor, before time.Time) (int64, error) {
+	result := exec.Where("created_at < ?", before).Delete(&models.FeatureUsageEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestFeatureUsageRepository_Rollup(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewFeatureUsageRepository()
+	exec := testDB.DB.DB
+
+	users := make([]uuid.UUID, 2)
+	for i := range users {
+		user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Usage"}
+		require.NoError(t, testDB.Create(user).Error)
+		users[i] = user.ID
+	}
+
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	events := []models.FeatureUsageEvent{
+		{UserID: users[0], Feature: models.FeatureVoiceMessage, Credits: 1, LatencyMs: 1000, Success: true, CreatedAt: day.Add(time.Hour)},
+		{UserID: users[0], Feature: models.FeatureVoiceMessage, Credits: 1, LatencyMs: 3000, Success: true, CreatedAt: day.Add(2 * time.Hour)},
+		{UserID: users[1], Feature: models.FeatureVoiceMessage, LatencyMs: 2000, Success: false, CreatedAt: day.Add(3 * time.Hour)},
+		{UserID: users[1], Feature: models.FeatureAnkiExport, LatencyMs: 40, Success: true, CreatedAt: day.Add(4 * time.Hour)},
+		{UserID: users[1], Feature: models.FeatureVoiceMessage, Credits: 1, LatencyMs: 500, Success: true, CreatedAt: day.AddDate(0, 0, 1)},
+	}
+	for i := range events {
+		require.NoError(t, repo.Create(exec, &events[i]))
+	}
+
+	require.NoError(t, repo.RollupDay(exec, day.Add(12*time.Hour)))
+	// Rolling up again replaces the totals rather than adding to them
+	require.NoError(t, repo.RollupDay(exec, day))
+
+	rows, err := repo.FindDaily(exec, day, day)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	anki, voice := rows[0], rows[1]
+	assert.Equal(t, models.FeatureAnkiExport, anki.Feature)
+	assert.Equal(t, int64(1), anki.Uses)
+
+	assert.Equal(t, models.FeatureVoiceMessage, voice.Feature)
+	assert.True(t, voice.Day.Equal(day))
+	assert.Equal(t, int64(3), voice.Uses, "the next day's event is left out")
+	assert.Equal(t, int64(1), voice.Failures)
+	assert.Equal(t, int64(2), voice.Users)
+	assert.Equal(t, int64(2), voice.Credits)
+	assert.Equal(t, int64(2000), voice.AvgLatencyMs)
+	assert.Equal(t, int64(2900), voice.P95LatencyMs)
+
+	deleted, err := repo.DeleteEventsBefore(exec, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+}
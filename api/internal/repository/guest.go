@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// guestRepository implements GuestRepository using GORM.
+type guestRepository struct{}
+
+// NewGuestRepository creates a new GORM-backed guest repository.
+func NewGuestRepository() GuestRepository {
+	return &guestRepository{}
+}
+
+// userOwnedModels are the models keyed by user_id that a deleted user's rows
+// are removed from. Threads go last: deleting them cascades to their
+// messages, chunks, read states and practice sessions. Safety incidents and
+// analysis comparisons hang off threads and messages without a cascade, so
+// DeleteUser removes them first.
+var userOwnedModels = []any{
+	&models.Session{},
+	&models.EmailVerification{},
+	&models.EmailDelivery{},
+	&models.IdempotencyKey{},
+	&models.CreditDispute{},
+	&models.CreditReservation{},
+	&models.CreditTransaction{},
+	&models.Credits{},
+	&models.Subscription{},
+	&models.PhonemeStatsSnapshot{},
+	&models.PhonemeSubstitution{},
+	&models.PhonemeStats{},
+	&models.Notification{},
+	&models.AnalyticsEvent{},
+	&models.FeatureUsageEvent{},
+	&models.DomainEvent{},
+	&models.UserSettings{},
+	&models.UserContentKey{},
+	&models.LearnerProfile{},
+	&models.StatsBadge{},
+	&models.UserStreak{},
+	&models.SignupSignal{},
+	&models.ThreadReadState{},
+	&models.PracticeSession{},
+}
+
+func (r *guestRepository) FindExpired(exec Executor, now time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	err := exec.Where("guest_expires_at IS NOT NULL AND guest_expires_at < ?", now).
+		Order("guest_expires_at ASC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *guestRepository) CountCreatedFromIP(exec Executor, ip string, since time.Time) (int64, error) {
+	var count int64
+	err := exec.Model(&models.Session{}).
+		Select("COUNT(DISTINCT sessions.user_id)").
+		Where("sessions.ip_address = ? AND sessions.created_at >= ?", ip, since).
+		Where("sessions.user_id IN (?)", exec.Model(&models.User{}).Select("id").Where("guest_expires_at IS NOT NULL")).
+		Scan(&count).Error
+	return count, err
+}
+
+func (r *guestRepository) FindAudioKeys(exec Executor, userID uuid.UUID) ([]string, error) {
+	threadIDs := exec.Model(&models.Thread{}).Select("id").Where("user_id = ?", userID)
+
+	var keys []string
+	err := exec.Model(&models.Message{}).
+		Where("thread_id IN (?) AND audio_url IS NOT NULL", threadIDs).
+		Pluck("audio_url", &keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var chunkKeys []string
+	err = exec.Model(&models.MessageChunk{}).
+		Where("message_id IN (?) AND audio_url IS NOT NULL",
+			exec.Model(&models.Message{}).Select("id").Where("thread_id IN (?)", threadIDs)).
+		Pluck("audio_url", &chunkKeys).Error
+	if err != nil {
+		return nil, err
+	}
+	return append(keys, chunkKeys...), nil
+}
+
+// MoveThreads also moves the read states and practice sessions, which carry
+// the owner alongside the thread
+func (r *guestRepository) MoveThreads(exec Executor, fromUserID, toUserID uuid.UUID) (int64, error) {
+	for _, model := range []any{&models.ThreadReadState{}, &models.PracticeSession{}} {
+		if err := exec.Model(model).Where("user_id = ?", fromUserID).Update("user_id", toUserID).Error; err != nil {
+			return 0, err
+		}
+	}
+	result := exec.Model(&models.Thread{}).Where("user_id = ?", fromUserID).Update("user_id", toUserID)
+	return result.RowsAffected, result.Error
+}
+
+func (r *guestRepository) DeleteUser(exec Executor, userID uuid.UUID) error {
+	threadIDs := exec.Model(&models.Thread{}).Select("id").Where("user_id = ?", userID)
+	if err := exec.Where("thread_id IN (?)", threadIDs).Delete(&models.SafetyIncident{}).Error; err != nil {
+		return err
+	}
+	messageIDs := exec.Model(&models.Message{}).Select("id").Where("thread_id IN (?)", threadIDs)
+	if err := exec.Where("message_id IN (?)", messageIDs).Delete(&models.AnalysisComparison{}).Error; err != nil {
+		return err
+	}
+	for _, model := range userOwnedModels {
+		if err := exec.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := exec.Where("user_id = ?", userID).Delete(&models.Thread{}).Error; err != nil {
+		return err
+	}
+	return exec.Delete(&models.User{}, "id = ?", userID).Error
+}
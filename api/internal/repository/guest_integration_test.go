@@ -0,0 +1,94 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestGuestRepository_Lifecycle(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewGuestRepository()
+	exec := testDB.DB.DB
+
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	guest := &models.User{Email: fmt.Sprintf("%s@guest.invalid", uuid.NewString()), Name: "Guest", GuestExpiresAt: &expired}
+	require.NoError(t, testDB.Create(guest).Error)
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Member"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	session := &models.Session{ID: uuid.NewString(), UserID: guest.ID, IPAddress: "203.0.113.7", ExpiresAt: expired}
+	require.NoError(t, testDB.Create(session).Error)
+	require.NoError(t, testDB.Create(&models.Credits{UserID: guest.ID, Balance: 10}).Error)
+
+	thread := &models.Thread{UserID: guest.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	audio := "audio/guest.webm"
+	require.NoError(t, testDB.Create(&models.Message{ThreadID: thread.ID, Role: "user", Content: "hola", AudioURL: &audio}).Error)
+
+	found, err := repo.FindExpired(exec, now, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1, "registered users never expire")
+	assert.Equal(t, guest.ID, found[0].ID)
+
+	count, err := repo.CountCreatedFromIP(exec, "203.0.113.7", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	keys, err := repo.FindAudioKeys(exec, guest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{audio}, keys)
+
+	moved, err := repo.MoveThreads(exec, guest.ID, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), moved)
+
+	require.NoError(t, repo.DeleteUser(exec, guest.ID))
+
+	var remaining int64
+	require.NoError(t, exec.Model(&models.User{}).Where("id = ?", guest.ID).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+	require.NoError(t, exec.Model(&models.Credits{}).Where("user_id = ?", guest.ID).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+
+	var kept models.Thread
+	require.NoError(t, exec.First(&kept, "id = ?", thread.ID).Error)
+	assert.Equal(t, user.ID, kept.UserID, "the moved thread survives the guest")
+}
+
+func TestGuestRepository_DeleteUser_RemovesAnalysisComparisons(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewGuestRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Leaving"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	message := &models.Message{ThreadID: thread.ID, Role: "user", Content: "hola"}
+	require.NoError(t, testDB.Create(message).Error)
+	require.NoError(t, testDB.Create(&models.AnalysisComparison{
+		MessageID: message.ID, Quality: "fast", PrimaryStatus: "success", ShadowStatus: "success",
+	}).Error)
+
+	require.NoError(t, repo.DeleteUser(exec, user.ID))
+
+	var remaining int64
+	require.NoError(t, exec.Model(&models.AnalysisComparison{}).Where("message_id = ?", message.ID).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+	require.NoError(t, exec.Model(&models.Message{}).Where("id = ?", message.ID).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
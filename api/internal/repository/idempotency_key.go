@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// idempotencyKeyRepository implements IdempotencyKeyRepository using GORM.
+type idempotencyKeyRepository struct{}
+
+// NewIdempotencyKeyRepository creates a new GORM-backed idempotency key repository.
+func NewIdempotencyKeyRepository() IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{}
+}
+
+func (r *idempotencyKeyRepository) Reserve(exec Executor, key *models.IdempotencyKey) (bool, error) {
+	result := exec.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoNothing: true,
+	}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *idempotencyKeyRepository) FindByUserAndKey(exec Executor, userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	var found models.IdempotencyKey
+	err := exec.Where("user_id = ? AND key = ?", userID, key).First(&found).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+func (r *idempotencyKeyRepository) Complete(exec Executor, id uuid.UUID, status int, contentType string, body []byte, completedAt time.Time) error {
+	return exec.Model(&models.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]any{
+		"response_status":       status,
+		"response_content_type": contentType,
+		"response_body":         body,
+		"completed_at":          completedAt,
+	}).Error
+}
+
+func (r *idempotencyKeyRepository) Delete(exec Executor, id uuid.UUID) error {
+	return exec.Delete(&models.IdempotencyKey{}, "id = ?", id).Error
+}
+
+func (r *idempotencyKeyRepository) DeleteCreatedBefore(exec Executor, t time.Time) (int64, error) {
+	result := exec.Where("created_at < ?", t).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -32,17 +32,41 @@ type Executor interface {
 
 // UserRepository handles user persistence.
 type UserRepository interface {
+	FindByID(exec Executor, id uuid.UUID) (*models.User, error)
 	FindByEmail(exec Executor, email string) (*models.User, error)
 	FindByGoogleID(exec Executor, googleID string) (*models.User, error)
 	FindByGitHubID(exec Executor, githubID string) (*models.User, error)
 	Create(exec Executor, user *models.User) error
 	Save(exec Executor, user *models.User) error
+	// Search returns accounts whose email or name contains the query, newest
+	// first; an empty query lists every account
+	Search(exec Executor, query string, limit int) ([]models.User, error)
+	// MarkEmailVerified marks the user's email verified if it is still the
+	// given address. It returns false if the user is gone or the address
+	// changed.
+	MarkEmailVerified(exec Executor, id uuid.UUID, email string) (bool, error)
+	// FindMergedInto returns the tombstones of accounts merged into the user
+	FindMergedInto(exec Executor, id uuid.UUID) ([]models.User, error)
+}
+
+// UserSettingsRepository handles user settings persistence.
+type UserSettingsRepository interface {
+	FindByUserID(exec Executor, userID uuid.UUID) (*models.UserSettings, error)
+	Upsert(exec Executor, settings *models.UserSettings) error
+}
+
+// LearnerProfileRepository handles learner profile persistence.
+type LearnerProfileRepository interface {
+	FindByUserID(exec Executor, userID uuid.UUID) (*models.LearnerProfile, error)
+	Upsert(exec Executor, profile *models.LearnerProfile) error
 }
 
 // SessionRepository handles session persistence.
 type SessionRepository interface {
 	Create(exec Executor, session *models.Session) error
 	FindByIDWithUser(exec Executor, token string) (*models.Session, error)
+	FindActiveByUserID(exec Executor, userID uuid.UUID, now time.Time) ([]models.Session, error)
+	UpdateExpiresAt(exec Executor, token string, expiresAt time.Time) error
 	DeleteByID(exec Executor, token string) error
 	DeleteByUserID(exec Executor, userID uuid.UUID) error
 	DeleteExpiredBefore(exec Executor, t time.Time) (int64, error)
@@ -51,22 +75,67 @@ type SessionRepository interface {
 // CreditsRepository handles credits persistence.
 type CreditsRepository interface {
 	FindByUserID(exec Executor, userID uuid.UUID) (*models.Credits, error)
+	// FindByUserIDForUpdate is FindByUserID holding the row lock until exec's
+	// transaction ends, so a read-modify-write of the balance can't lose a
+	// concurrent one. exec must be a transaction.
+	FindByUserIDForUpdate(exec Executor, userID uuid.UUID) (*models.Credits, error)
 	Create(exec Executor, credits *models.Credits) error
 	Save(exec Executor, credits *models.Credits) error
 	UpdateAllowance(exec Executor, userID uuid.UUID, allowance int) error
+	// FindFreeRefreshDue returns the credits of free-tier accounts last
+	// refreshed before refreshedBefore, oldest first. Guests, merged accounts
+	// and cancelled plans still in their grace period are left out.
+	FindFreeRefreshDue(exec Executor, refreshedBefore time.Time, limit int) ([]models.Credits, error)
+	// Refresh starts a new period with balance, reporting false if the row
+	// changed since it was read, e.g. because another instance refreshed it
+	// first
+	Refresh(exec Executor, credits *models.Credits, balance int, refreshedAt time.Time) (bool, error)
 }
 
 // CreditTransactionRepository handles credit transaction persistence.
 type CreditTransactionRepository interface {
 	Create(exec Executor, tx *models.CreditTransaction) error
-	FindByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.CreditTransaction, error)
+	FindByUserID(exec Executor, userID uuid.UUID, limit, offset int) ([]models.CreditTransaction, error)
+	FindByIDAndUserID(exec Executor, id, userID uuid.UUID) (*models.CreditTransaction, error)
 }
 
-// PhonemeStatsRepository handles phoneme statistics persistence.
+// CreditReservationRepository handles credit reservation persistence.
+type CreditReservationRepository interface {
+	Create(exec Executor, reservation *models.CreditReservation) error
+	FindByID(exec Executor, id uuid.UUID) (*models.CreditReservation, error)
+	// Settle moves a held reservation to status, reporting false if it was
+	// no longer held
+	Settle(exec Executor, id uuid.UUID, status models.CreditReservationStatus, settledAt time.Time) (bool, error)
+	// Reduce lowers a held reservation's amount from from to to, reporting
+	// false if it was no longer held at from
+	Reduce(exec Executor, id uuid.UUID, from, to int) (bool, error)
+	// FindByReference returns the latest reservation made for reference
+	FindByReference(exec Executor, reference string) (*models.CreditReservation, error)
+	// MarkRefunded moves a captured reservation to refunded, reporting false
+	// if it wasn't captured
+	MarkRefunded(exec Executor, id uuid.UUID, refundedAt time.Time) (bool, error)
+	// FindExpired returns reservations still held past their expiry at now,
+	// oldest first
+	FindExpired(exec Executor, now time.Time, limit int) ([]models.CreditReservation, error)
+	// SumHeld returns how many of the user's credits are in reservations
+	// still held
+	SumHeld(exec Executor, userID uuid.UUID) (int, error)
+}
+
+// CreditDisputeRepository handles credit dispute persistence.
+type CreditDisputeRepository interface {
+	Create(exec Executor, dispute *models.CreditDispute) error
+	FindByTransactionID(exec Executor, transactionID uuid.UUID) (*models.CreditDispute, error)
+}
+
+// PhonemeStatsRepository handles phoneme statistics persistence. Stats are
+// kept apart per language; reads take the thread language code.
 type PhonemeStatsRepository interface {
 	Upsert(exec Executor, stats *models.PhonemeStats) error
-	FindByUserID(exec Executor, userID uuid.UUID) ([]models.PhonemeStats, error)
-	GetAccuracyRanking(exec Executor, userID uuid.UUID) ([]PhonemeAccuracy, error)
+	FindByUserID(exec Executor, userID uuid.UUID, language string) ([]models.PhonemeStats, error)
+	// FindLanguages lists the languages the user has stats in, in order
+	FindLanguages(exec Executor, userID uuid.UUID) ([]string, error)
+	GetAccuracyRanking(exec Executor, userID uuid.UUID, language string) ([]PhonemeAccuracy, error)
 }
 
 // PhonemeAccuracy represents a single phoneme's accuracy stats.
@@ -81,7 +150,16 @@ type PhonemeAccuracy struct {
 // PhonemeSubstitutionRepository handles phoneme substitution patterns persistence.
 type PhonemeSubstitutionRepository interface {
 	Upsert(exec Executor, sub *models.PhonemeSubstitution) error
-	FindTopByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.PhonemeSubstitution, error)
+	FindTopByUserID(exec Executor, userID uuid.UUID, language string, limit int) ([]models.PhonemeSubstitution, error)
+}
+
+// PhonemeStatsSnapshotRepository handles what each message's analysis added
+// to its user's phoneme stats.
+type PhonemeStatsSnapshotRepository interface {
+	Save(exec Executor, snapshot *models.PhonemeStatsSnapshot) error
+	// Take deletes and returns a message's snapshot, so only one caller can
+	// take any snapshot back out of the stats. ErrNotFound if there is none.
+	Take(exec Executor, messageID uuid.UUID) (*models.PhonemeStatsSnapshot, error)
 }
 
 // SubscriptionRepository handles subscription persistence.
@@ -92,6 +170,18 @@ type SubscriptionRepository interface {
 	Create(exec Executor, sub *models.Subscription) error
 	Save(exec Executor, sub *models.Subscription) error
 	UpdateStatus(exec Executor, subscriptionID string, status string) error
+	FindGraceExpired(exec Executor, now time.Time, limit int) ([]models.Subscription, error)
+	EndGracePeriod(exec Executor, id uuid.UUID) (bool, error)
+	// StartDunning records the first failed payment of a run. It returns
+	// false if a run is already under way.
+	StartDunning(exec Executor, id uuid.UUID, failedAt time.Time) (bool, error)
+	// AdvanceDunning counts a reminder as sent if remindersSent is still the
+	// stored count, so each reminder goes out once
+	AdvanceDunning(exec Executor, id uuid.UUID, remindersSent int) (bool, error)
+	EndDunning(exec Executor, id uuid.UUID) error
+	// FindDunningDue returns past-due subscriptions that have had
+	// remindersSent reminders and whose payment first failed before failedBefore
+	FindDunningDue(exec Executor, remindersSent int, failedBefore time.Time, limit int) ([]models.Subscription, error)
 }
 
 // ThreadRepository handles thread persistence.
@@ -100,12 +190,16 @@ type ThreadRepository interface {
 	FindByID(exec Executor, id uuid.UUID) (*models.Thread, error)
 	FindByIDWithMessages(exec Executor, id uuid.UUID) (*models.Thread, error)
 	FindByUserID(exec Executor, userID uuid.UUID) ([]models.Thread, error)
+	FindSummariesByUserID(exec Executor, userID uuid.UUID) ([]models.ThreadSummary, error)
 	FindArchivedByUserID(exec Executor, userID uuid.UUID) ([]models.Thread, error)
+	CountByUserID(exec Executor, userID uuid.UUID) (int64, error)
 	FindByIDAndUserID(exec Executor, id, userID uuid.UUID) (*models.Thread, error)
-	FindByIDAndUserIDWithMessages(exec Executor, id, userID uuid.UUID) (*models.Thread, error)
+	FindByIDAndUserIDWithMessages(exec Executor, id, userID uuid.UUID, withAnalysis bool) (*models.Thread, error)
 	Save(exec Executor, thread *models.Thread) error
 	Delete(exec Executor, thread *models.Thread) error
 	UpdateName(exec Executor, id uuid.UUID, name string) error
+	MarkGoalCompleted(exec Executor, id uuid.UUID, completedAt time.Time) (bool, error)
+	MarkEnded(exec Executor, id uuid.UUID, endedAt time.Time) (bool, error)
 }
 
 // MessageRepository handles message persistence.
@@ -113,7 +207,311 @@ type MessageRepository interface {
 	Create(exec Executor, message *models.Message) error
 	FindByID(exec Executor, id uuid.UUID) (*models.Message, error)
 	FindByThreadID(exec Executor, threadID uuid.UUID) ([]models.Message, error)
+	CountUserMessagesByUserID(exec Executor, userID uuid.UUID) (int64, error)
 	UpdatePronunciationStatus(exec Executor, id uuid.UUID, status string) error
-	UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, updatedAt time.Time) error
+	UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error
 	UpdatePronunciationError(exec Executor, id uuid.UUID, status string, errMsg string, updatedAt time.Time) error
+	FindExpiredUserAudio(exec Executor, now time.Time, limit int) ([]models.Message, error)
+	FindAnalyzedByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.Message, error)
+	FindActiveDaysByUserID(exec Executor, userID uuid.UUID, since time.Time, loc *time.Location) ([]time.Time, error)
+	ClearAudio(exec Executor, id uuid.UUID) error
+	UpdateContent(exec Executor, id uuid.UUID, content string, correctedAt time.Time) error
+	ResetPronunciation(exec Executor, id uuid.UUID, status string, updatedAt time.Time) error
+	RetryPronunciation(exec Executor, id uuid.UUID, maxRetries int, updatedAt time.Time) (bool, error)
+	FindUserMessagesByUserID(exec Executor, userID uuid.UUID, before time.Time, limit int) ([]models.Message, error)
+	CountPronunciationStatuses(exec Executor, since time.Time) (map[string]int64, error)
+	FindPendingPronunciation(exec Executor, limit int) ([]models.Message, error)
+	// FindPendingPronunciationBefore returns whole user messages sent before
+	// before whose analysis is still pending, oldest first
+	FindPendingPronunciationBefore(exec Executor, before time.Time, limit int) ([]models.Message, error)
+	FindFailedPronunciation(exec Executor, since time.Time, limit int) ([]models.Message, error)
+	// FindOwnedAudioKeys returns those of keys that are the recording of a
+	// message, or of a chunk of one, in the user's threads
+	FindOwnedAudioKeys(exec Executor, userID uuid.UUID, keys []string) ([]string, error)
+}
+
+// MessageChunkRepository handles the recordings of long-form messages.
+type MessageChunkRepository interface {
+	CreateBatch(exec Executor, chunks []models.MessageChunk) error
+	FindByMessageID(exec Executor, messageID uuid.UUID) ([]models.MessageChunk, error)
+	UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error
+	UpdatePronunciationError(exec Executor, id uuid.UUID, status string, errMsg string, updatedAt time.Time) error
+	FindExpiredAudio(exec Executor, now time.Time, limit int) ([]models.MessageChunk, error)
+	ClearAudio(exec Executor, id uuid.UUID) error
+}
+
+// NotificationRepository handles notification persistence.
+type NotificationRepository interface {
+	Create(exec Executor, notification *models.Notification) error
+	FindByUserID(exec Executor, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, error)
+	CountUnread(exec Executor, userID uuid.UUID) (int64, error)
+	MarkRead(exec Executor, id, userID uuid.UUID, readAt time.Time) error
+	MarkAllRead(exec Executor, userID uuid.UUID, readAt time.Time) error
+	// FindUsersWithPendingEmail returns every user with notifications
+	// waiting to be emailed
+	FindUsersWithPendingEmail(exec Executor) ([]uuid.UUID, error)
+	// FindPendingEmailByUserID returns the user's notifications waiting to be
+	// emailed, oldest first
+	FindPendingEmailByUserID(exec Executor, userID uuid.UUID) ([]models.Notification, error)
+	SetEmailStatus(exec Executor, ids []uuid.UUID, status string) error
+}
+
+// AnalyticsEventRepository handles analytics event persistence.
+type AnalyticsEventRepository interface {
+	CreateBatch(exec Executor, events []models.AnalyticsEvent) error
+}
+
+// FeatureUsageRepository handles feature usage events and their daily rollup.
+type FeatureUsageRepository interface {
+	Create(exec Executor, event *models.FeatureUsageEvent) error
+	// RollupDay recomputes the rollup rows of the UTC day starting at day
+	RollupDay(exec Executor, day time.Time) error
+	// FindDaily returns rollup rows for days in [from, to], oldest first
+	FindDaily(exec Executor, from, to time.Time) ([]models.FeatureUsageDaily, error)
+	DeleteEventsBefore(exec Executor, before time.Time) (int64, error)
+}
+
+// GuestRepository handles guest (demo) accounts and their purge.
+type GuestRepository interface {
+	// FindExpired returns up to limit guests whose demo ended before now
+	FindExpired(exec Executor, now time.Time, limit int) ([]models.User, error)
+	// CountCreatedFromIP counts guests whose session was started from ip since the given time
+	CountCreatedFromIP(exec Executor, ip string, since time.Time) (int64, error)
+	// FindAudioKeys returns the storage keys of every recording in the user's threads
+	FindAudioKeys(exec Executor, userID uuid.UUID) ([]string, error)
+	// MoveThreads hands every thread of one user to another
+	MoveThreads(exec Executor, fromUserID, toUserID uuid.UUID) (int64, error)
+	// DeleteUser deletes the user and every row that belongs to them. Run it
+	// in a transaction.
+	DeleteUser(exec Executor, userID uuid.UUID) error
+}
+
+// AccountMergeRepository moves one account's data onto another. Run its
+// methods in one transaction.
+type AccountMergeRepository interface {
+	// MoveOwned hands every row of the tables a user can have any number of
+	// rows in to the target, and returns how many moved per table. Messages
+	// and their chunks move with their threads.
+	MoveOwned(exec Executor, fromUserID, toUserID uuid.UUID) (map[string]int64, error)
+	// MergePhonemeStats adds the source's phoneme and substitution counts to
+	// the target's and moves the ones the target has none of. It returns how
+	// many rows were combined.
+	MergePhonemeStats(exec Executor, fromUserID, toUserID uuid.UUID) (int64, error)
+	// MoveSingletons moves the one-per-user rows (settings, learner profile,
+	// streak, stats badge) the target doesn't have yet, and returns the tables
+	// moved from. The source keeps the rest.
+	MoveSingletons(exec Executor, fromUserID, toUserID uuid.UUID) ([]string, error)
+	// MoveSubscription replaces the target's subscription with the source's
+	MoveSubscription(exec Executor, fromUserID, toUserID uuid.UUID) error
+}
+
+// DomainEventFilter selects logged domain events to replay. Zero fields
+// match everything.
+type DomainEventFilter struct {
+	UserID uuid.UUID
+	Names  []string
+	From   time.Time // inclusive
+	To     time.Time // exclusive
+}
+
+// DomainEventRepository handles the domain event log.
+type DomainEventRepository interface {
+	Create(exec Executor, event *models.DomainEvent) error
+	// FindPage returns up to limit events matching filter, oldest first,
+	// starting after the event after (nil = from the beginning)
+	FindPage(exec Executor, filter DomainEventFilter, after *models.DomainEvent, limit int) ([]models.DomainEvent, error)
+}
+
+// SafetyIncidentRepository handles safety incident persistence.
+type SafetyIncidentRepository interface {
+	Create(exec Executor, incident *models.SafetyIncident) error
+}
+
+// ThreadReadStateRepository handles per-user thread read positions.
+type ThreadReadStateRepository interface {
+	Find(exec Executor, userID, threadID uuid.UUID) (*models.ThreadReadState, error)
+	MarkRead(exec Executor, userID, threadID uuid.UUID, readAt time.Time) error
+}
+
+// PracticeSessionRepository handles timed practice sessions.
+type PracticeSessionRepository interface {
+	Create(exec Executor, session *models.PracticeSession) error
+	FindByIDAndUserID(exec Executor, id, userID uuid.UUID) (*models.PracticeSession, error)
+	FindOpenByThreadID(exec Executor, threadID uuid.UUID) (*models.PracticeSession, error)
+	FindByUserID(exec Executor, userID uuid.UUID, limit, offset int) ([]models.PracticeSession, error)
+	// End stores the summary of an open session. It reports false if the
+	// session had already ended.
+	End(exec Executor, id uuid.UUID, endedAt time.Time, summary *models.PracticeSessionSummary) (bool, error)
+}
+
+// EmailVerificationRepository handles email verification links.
+type EmailVerificationRepository interface {
+	Create(exec Executor, verification *models.EmailVerification) error
+	FindByTokenHash(exec Executor, tokenHash string) (*models.EmailVerification, error)
+	// Use marks a verification used. It returns false if it was already used
+	// or had expired at now, so each link verifies once.
+	Use(exec Executor, id uuid.UUID, now time.Time) (bool, error)
+	// CountSince counts the verifications sent to the user since the given time
+	CountSince(exec Executor, userID uuid.UUID, since time.Time) (int64, error)
+}
+
+// ThreadShareRepository handles share-for-review links to threads.
+type ThreadShareRepository interface {
+	Create(exec Executor, share *models.ThreadShare) error
+	FindByToken(exec Executor, token string) (*models.ThreadShare, error)
+	// FindActiveByThreadID returns the thread's shares that are neither
+	// expired nor revoked at now, newest first
+	FindActiveByThreadID(exec Executor, threadID uuid.UUID, now time.Time) ([]models.ThreadShare, error)
+	// Revoke revokes one of the thread's shares. It returns ErrNotFound if
+	// the share doesn't exist or was already revoked.
+	Revoke(exec Executor, id, threadID uuid.UUID, revokedAt time.Time) error
+}
+
+// AuditLogRepository handles audit log persistence.
+type AuditLogRepository interface {
+	Create(exec Executor, entry *models.AuditLog) error
+}
+
+// StatsBadgeRepository handles public stats badge tokens.
+type StatsBadgeRepository interface {
+	FindByUserID(exec Executor, userID uuid.UUID) (*models.StatsBadge, error)
+	FindByToken(exec Executor, token string) (*models.StatsBadge, error)
+	Upsert(exec Executor, badge *models.StatsBadge) error
+	DeleteByUserID(exec Executor, userID uuid.UUID) error
+}
+
+// StreakRepository handles daily practice streaks.
+type StreakRepository interface {
+	// Advance counts day (the user's local date, at midnight UTC) as active,
+	// extending the streak if the last active day was the day before and
+	// restarting it otherwise. It returns the updated streak, or false if day
+	// was already counted.
+	Advance(exec Executor, userID uuid.UUID, day time.Time) (*models.UserStreak, bool, error)
+}
+
+// RuntimeSettingRepository handles runtime setting overrides.
+type RuntimeSettingRepository interface {
+	FindAll(exec Executor) ([]models.RuntimeSetting, error)
+	Upsert(exec Executor, setting *models.RuntimeSetting) error
+	Delete(exec Executor, key string) error
+}
+
+// SignupSignalRepository handles signup signals for duplicate account detection.
+type SignupSignalRepository interface {
+	Create(exec Executor, signal *models.SignupSignal) error
+	Save(exec Executor, signal *models.SignupSignal) error
+	FindByUserID(exec Executor, userID uuid.UUID) (*models.SignupSignal, error)
+	// FindMatching returns signals sharing the IP address (created at or after
+	// since) or, when fingerprintHash is set, the fingerprint (any time)
+	FindMatching(exec Executor, ipAddress, fingerprintHash string, since time.Time) ([]models.SignupSignal, error)
+	FindPendingReview(exec Executor, limit int) ([]models.SignupSignal, error)
+}
+
+// InviteCodeRepository handles invite codes for invite-only signups.
+type InviteCodeRepository interface {
+	Create(exec Executor, invite *models.InviteCode) error
+	FindAll(exec Executor, limit int) ([]models.InviteCode, error)
+	// Redeem uses up one use of a code that hasn't expired at now, returning
+	// ErrNotFound if there is no such code
+	Redeem(exec Executor, code string, now time.Time) error
+}
+
+// IdempotencyKeyRepository handles the Idempotency-Key headers of requests
+// and their responses.
+type IdempotencyKeyRepository interface {
+	// Reserve stores a new key. It returns false if the user already has it.
+	Reserve(exec Executor, key *models.IdempotencyKey) (bool, error)
+	FindByUserAndKey(exec Executor, userID uuid.UUID, key string) (*models.IdempotencyKey, error)
+	Complete(exec Executor, id uuid.UUID, status int, contentType string, body []byte, completedAt time.Time) error
+	Delete(exec Executor, id uuid.UUID) error
+	DeleteCreatedBefore(exec Executor, t time.Time) (int64, error)
+}
+
+// StorageDeletionRepository handles the stored objects of deleted accounts
+// that are still to be deleted.
+type StorageDeletionRepository interface {
+	Create(exec Executor, deletions []models.PendingStorageDeletion) error
+	// FindPending returns deletions created before createdBefore, least
+	// recently tried first
+	FindPending(exec Executor, createdBefore time.Time, limit int) ([]models.PendingStorageDeletion, error)
+	Delete(exec Executor, id uuid.UUID) error
+	// RecordFailure counts a failed attempt and keeps its error
+	RecordFailure(exec Executor, id uuid.UUID, errMsg string, at time.Time) error
+}
+
+// WaitlistRepository handles waitlist entries.
+type WaitlistRepository interface {
+	// Create adds an entry; an email already on the list is left as it is
+	Create(exec Executor, entry *models.WaitlistEntry) error
+	FindByID(exec Executor, id uuid.UUID) (*models.WaitlistEntry, error)
+	FindAll(exec Executor, pendingOnly bool, limit int) ([]models.WaitlistEntry, error)
+	Save(exec Executor, entry *models.WaitlistEntry) error
+}
+
+// WarehouseRepository reads fact rows for the warehouse export and tracks how
+// far each fact table has been exported. Fact queries cover [from, to).
+type WarehouseRepository interface {
+	FindWatermarks(exec Executor) ([]models.WarehouseWatermark, error)
+	SaveWatermark(exec Executor, watermark *models.WarehouseWatermark) error
+	// FirstFactTime returns the time of a fact table's earliest row, or nil
+	// if it has none yet
+	FirstFactTime(exec Executor, table string) (*time.Time, error)
+	MessageFacts(exec Executor, from, to time.Time) ([]models.WarehouseMessageFact, error)
+	// AnalysisFacts leaves out analyses sealed by content encryption
+	AnalysisFacts(exec Executor, from, to time.Time) ([]models.WarehouseAnalysisFact, error)
+	TransactionFacts(exec Executor, from, to time.Time) ([]models.CreditTransaction, error)
+	UsageFacts(exec Executor, from, to time.Time) ([]models.WarehouseUsageFact, error)
+}
+
+// ReferenceAudioRepository tracks the example clips already synthesized.
+type ReferenceAudioRepository interface {
+	Find(exec Executor, text, language, voice string, speed float64) (*models.ReferenceAudio, error)
+	// Create records a clip; one already recorded for the same text, language,
+	// voice and speed is kept
+	Create(exec Executor, clip *models.ReferenceAudio) error
+}
+
+// ContentEncryptionRepository seals content written before its owner turned
+// on content encryption. Each call seals up to limit rows and returns how
+// many it sealed, so callers repeat until it returns less than limit.
+type ContentEncryptionRepository interface {
+	SealPendingMessages(exec Executor, limit int) (int, error)
+	SealPendingChunks(exec Executor, limit int) (int, error)
+}
+
+// LegacyAudio is a message or long-form chunk whose recording is still
+// stored under a legacy key
+type LegacyAudio struct {
+	ID    uuid.UUID
+	Key   string
+	Chunk bool // ID is a message chunk's
+}
+
+// AudioKeyMigrationRepository moves recordings to the current key scheme
+// and tracks the legacy objects left to delete.
+type AudioKeyMigrationRepository interface {
+	// FindLegacy returns up to limit recordings with a legacy key, messages first
+	FindLegacy(exec Executor, limit int) ([]LegacyAudio, error)
+	// Switch points the recording at key, unless it changed since it was
+	// read, and records the migration. Already pointing at key counts as
+	// switched, so two workers racing on it both succeed.
+	Switch(exec Executor, recording LegacyAudio, key, format string, at time.Time) (bool, error)
+	FindMigratedBefore(exec Executor, before time.Time, limit int) ([]models.AudioKeyMigration, error)
+	Delete(exec Executor, id uuid.UUID) error
+}
+
+// EmailDeliveryRepository records the notification emails sent to users.
+type EmailDeliveryRepository interface {
+	Create(exec Executor, delivery *models.EmailDelivery) error
+	CountSince(exec Executor, userID uuid.UUID, kind string, since time.Time) (int64, error)
+	// LastSentAt returns when the user was last sent an email of the kind,
+	// or nil if never
+	LastSentAt(exec Executor, userID uuid.UUID, kind string) (*time.Time, error)
+}
+
+// AnalysisComparisonRepository stores shadow ML analyses next to the primary ones.
+type AnalysisComparisonRepository interface {
+	Create(exec Executor, comparison *models.AnalysisComparison) error
+	// Summarize sums the comparisons made since the given time, per quality
+	Summarize(exec Executor, since time.Time) ([]models.AnalysisComparisonSummary, error)
 }
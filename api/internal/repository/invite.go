@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// inviteCodeRepository implements InviteCodeRepository using GORM.
+type inviteCodeRepository struct{}
+
+// NewInviteCodeRepository creates a new GORM-backed invite code repository.
+func NewInviteCodeRepository() InviteCodeRepository {
+	return &inviteCodeRepository{}
+}
+
+func (r *inviteCodeRepository) Create(exec Executor, invite *models.InviteCode) error {
+	return exec.Create(invite).Error
+}
+
+func (r *inviteCodeRepository) FindAll(exec Executor, limit int) ([]models.InviteCode, error) {
+	var invites []models.InviteCode
+	if err := exec.Order("created_at DESC").Limit(limit).Find(&invites).Error; err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (r *inviteCodeRepository) Redeem(exec Executor, code string, now time.Time) error {
+	// A single conditional update, so concurrent signups can't overspend a code
+	result := exec.Model(&models.InviteCode{}).
+		Where("code = ? AND uses_remaining > 0 AND (expires_at IS NULL OR expires_at > ?)", code, now).
+		Update("uses_remaining", gorm.Expr("uses_remaining - 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// waitlistRepository implements WaitlistRepository using GORM.
+type waitlistRepository struct{}
+
+// NewWaitlistRepository creates a new GORM-backed waitlist repository.
+func NewWaitlistRepository() WaitlistRepository {
+	return &waitlistRepository{}
+}
+
+func (r *waitlistRepository) Create(exec Executor, entry *models.WaitlistEntry) error {
+	return exec.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoNothing: true,
+	}).Create(entry).Error
+}
+
+func (r *waitlistRepository) FindByID(exec Executor, id uuid.UUID) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := exec.Where("id = ?", id).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) FindAll(exec Executor, pendingOnly bool, limit int) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	query := exec.Order("created_at").Limit(limit)
+	if pendingOnly {
+		query = query.Where("invited_at IS NULL")
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) Save(exec Executor, entry *models.WaitlistEntry) error {
+	return exec.Save(entry).Error
+}
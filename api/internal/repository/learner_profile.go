@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// learnerProfileRepository implements LearnerProfileRepository using GORM.
+type learnerProfileRepository struct{}
+
+// NewLearnerProfileRepository creates a new GORM-backed learner profile repository.
+func NewLearnerProfileRepository() LearnerProfileRepository {
+	return &learnerProfileRepository{}
+}
+
+func (r *learnerProfileRepository) FindByUserID(exec Executor, userID uuid.UUID) (*models.LearnerProfile, error) {
+	var profile models.LearnerProfile
+	err := exec.Where("user_id = ?", userID).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *learnerProfileRepository) Upsert(exec Executor, profile *models.LearnerProfile) error {
+	return exec.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"disabled", "preferred_name", "interests", "recurring_mistakes", "extracted_at", "updated_at",
+		}),
+	}).Create(profile).Error
+}
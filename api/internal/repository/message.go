@@ -43,15 +43,26 @@ func (r *messageRepository) FindByThreadID(exec Executor, threadID uuid.UUID) ([
 	return messages, nil
 }
 
+func (r *messageRepository) CountUserMessagesByUserID(exec Executor, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := exec.Model(&models.Message{}).
+		Where("role = ? AND thread_id IN (SELECT id FROM threads WHERE user_id = ?)", "user", userID).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *messageRepository) UpdatePronunciationStatus(exec Executor, id uuid.UUID, status string) error {
 	return exec.Model(&models.Message{}).Where("id = ?", id).Update("pronunciation_status", status).Error
 }
 
-func (r *messageRepository) UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, updatedAt time.Time) error {
+func (r *messageRepository) UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
 	return exec.Model(&models.Message{}).
 		Where("id = ?", id).
 		Update("pronunciation_status", status).
 		Update("pronunciation_analysis", analysis).
+		Update("pronunciation_quality", quality).
+		Update("pronunciation_confidence", confidence).
+		Update("pronunciation_low_confidence", lowConfidence).
 		Update("pronunciation_error", nil).
 		Update("pronunciation_updated_at", updatedAt).Error
 }
@@ -63,3 +74,217 @@ func (r *messageRepository) UpdatePronunciationError(exec Executor, id uuid.UUID
 		Update("pronunciation_error", errMsg).
 		Update("pronunciation_updated_at", updatedAt).Error
 }
+
+// FindExpiredUserAudio returns user messages whose recording is older than the
+// owner's audio retention setting, oldest first.
+func (r *messageRepository) FindExpiredUserAudio(exec Executor, now time.Time, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	err := exec.Where("role = ? AND has_audio = ? AND audio_url IS NOT NULL", "user", true).
+		Where(`timestamp < (
+			SELECT ?::timestamptz - user_settings.audio_retention_days * INTERVAL '1 day'
+			FROM threads JOIN user_settings ON user_settings.user_id = threads.user_id
+			WHERE threads.id = messages.thread_id AND user_settings.audio_retention_days > 0
+		)`, now).
+		Order("timestamp ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// FindAnalyzedByUserID returns the user's most recent messages with a
+// confident pronunciation analysis, newest first.
+func (r *messageRepository) FindAnalyzedByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	err := exec.Where("role = ? AND pronunciation_status = ? AND pronunciation_low_confidence = ?", "user", "complete", false).
+		Where("thread_id IN (?)", exec.Model(&models.Thread{}).Select("id").Where("user_id = ?", userID)).
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// FindActiveDaysByUserID returns the days, as dates in loc, since the given
+// time on which the user sent at least one message, most recent first.
+func (r *messageRepository) FindActiveDaysByUserID(exec Executor, userID uuid.UUID, since time.Time, loc *time.Location) ([]time.Time, error) {
+	var days []time.Time
+	err := exec.Model(&models.Message{}).
+		Select("DISTINCT (timestamp AT TIME ZONE ?)::date AS day", loc.String()).
+		Where("role = ? AND timestamp >= ?", "user", since).
+		Where("thread_id IN (?)", exec.Model(&models.Thread{}).Select("id").Where("user_id = ?", userID)).
+		Order("day DESC").
+		Scan(&days).Error
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// ClearAudio detaches a message from its recording once the audio is deleted
+func (r *messageRepository) ClearAudio(exec Executor, id uuid.UUID) error {
+	return exec.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("audio_url", nil).
+		Update("has_audio", false).Error
+}
+
+// UpdateContent replaces a user message's transcript with the user's correction
+func (r *messageRepository) UpdateContent(exec Executor, id uuid.UUID, content string, correctedAt time.Time) error {
+	return exec.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("content", content).
+		Update("transcript_corrected_at", correctedAt).Error
+}
+
+// ResetPronunciation drops a message's analysis and sets its status, before
+// it is analyzed again or when it no longer can be
+func (r *messageRepository) ResetPronunciation(exec Executor, id uuid.UUID, status string, updatedAt time.Time) error {
+	return exec.Model(&models.Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"pronunciation_status":         status,
+			"pronunciation_analysis":       nil,
+			"pronunciation_confidence":     nil,
+			"pronunciation_low_confidence": false,
+			"pronunciation_error":          nil,
+			"pronunciation_updated_at":     updatedAt,
+		}).Error
+}
+
+// RetryPronunciation sets a failed analysis back to pending and counts the
+// retry. Returns false if the analysis hasn't failed (or the message is gone)
+// or was already retried maxRetries times.
+func (r *messageRepository) RetryPronunciation(exec Executor, id uuid.UUID, maxRetries int, updatedAt time.Time) (bool, error) {
+	result := exec.Model(&models.Message{}).
+		Where("id = ? AND pronunciation_status = ? AND pronunciation_retries < ?", id, "failed", maxRetries).
+		Updates(map[string]interface{}{
+			"pronunciation_status":     "pending",
+			"pronunciation_error":      nil,
+			"pronunciation_retries":    gorm.Expr("pronunciation_retries + 1"),
+			"pronunciation_updated_at": updatedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindUserMessagesByUserID returns the user's most recent messages sent
+// before the given time, newest first.
+func (r *messageRepository) FindUserMessagesByUserID(exec Executor, userID uuid.UUID, before time.Time, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	err := exec.Where("role = ? AND timestamp < ?", "user", before).
+		Where("thread_id IN (?)", exec.Model(&models.Thread{}).Select("id").Where("user_id = ?", userID)).
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// pronunciationJobColumns are what the job monitor reads, leaving out the
+// content and analysis
+var pronunciationJobColumns = []string{
+	"id", "thread_id", "role", "timestamp",
+	"pronunciation_status", "pronunciation_error", "pronunciation_retries", "pronunciation_updated_at",
+}
+
+// CountPronunciationStatuses counts user messages by pronunciation status,
+// for analyses requested or finished since the given time
+func (r *messageRepository) CountPronunciationStatuses(exec Executor, since time.Time) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := exec.Model(&models.Message{}).
+		Select("pronunciation_status AS status, COUNT(*) AS count").
+		Where("role = ? AND pronunciation_status <> ?", "user", "none").
+		Where("COALESCE(pronunciation_updated_at, timestamp) >= ?", since).
+		Group("pronunciation_status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// FindPendingPronunciation returns the messages waiting on an analysis,
+// oldest first, without their content
+func (r *messageRepository) FindPendingPronunciation(exec Executor, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	err := exec.Select(pronunciationJobColumns).
+		Where("role = ? AND pronunciation_status = ?", "user", "pending").
+		Order("timestamp ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *messageRepository) FindPendingPronunciationBefore(exec Executor, before time.Time, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	err := exec.Where("role = ? AND pronunciation_status = ? AND timestamp < ?", "user", "pending", before).
+		Order("timestamp ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// FindFailedPronunciation returns the messages whose analysis failed since
+// the given time, most recent failure first, without their content
+func (r *messageRepository) FindFailedPronunciation(exec Executor, since time.Time, limit int) ([]models.Message, error) {
+	var messages []models.Message
+	err := exec.Select(pronunciationJobColumns).
+		Where("role = ? AND pronunciation_status = ? AND pronunciation_updated_at >= ?", "user", "failed", since).
+		Order("pronunciation_updated_at DESC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// FindOwnedAudioKeys returns those of keys that are the recording of a
+// message, or of a chunk of one, in the user's threads
+func (r *messageRepository) FindOwnedAudioKeys(exec Executor, userID uuid.UUID, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	threads := "thread_id IN (SELECT id FROM threads WHERE user_id = ?)"
+
+	var owned []string
+	err := exec.Model(&models.Message{}).Select("audio_url").
+		Where("audio_url IN ?", keys).
+		Where(threads, userID).
+		Scan(&owned).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []string
+	err = exec.Model(&models.MessageChunk{}).Select("audio_url").
+		Where("audio_url IN ?", keys).
+		Where("message_id IN (SELECT id FROM messages WHERE "+threads+")", userID).
+		Scan(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return append(owned, chunks...), nil
+}
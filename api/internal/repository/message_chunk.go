@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// messageChunkRepository implements MessageChunkRepository using GORM.
+type messageChunkRepository struct{}
+
+// NewMessageChunkRepository creates a new GORM-backed message chunk repository.
+func NewMessageChunkRepository() MessageChunkRepository {
+	return &messageChunkRepository{}
+}
+
+func (r *messageChunkRepository) CreateBatch(exec Executor, chunks []models.MessageChunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	return exec.Create(&chunks).Error
+}
+
+func (r *messageChunkRepository) FindByMessageID(exec Executor, messageID uuid.UUID) ([]models.MessageChunk, error) {
+	var chunks []models.MessageChunk
+	err := exec.Where("message_id = ?", messageID).Order("position ASC").Find(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+func (r *messageChunkRepository) UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	return exec.Model(&models.MessageChunk{}).
+		Where("id = ?", id).
+		Update("pronunciation_status", status).
+		Update("pronunciation_analysis", analysis).
+		Update("pronunciation_quality", quality).
+		Update("pronunciation_confidence", confidence).
+		Update("pronunciation_low_confidence", lowConfidence).
+		Update("pronunciation_error", nil).
+		Update("pronunciation_updated_at", updatedAt).Error
+}
+
+func (r *messageChunkRepository) UpdatePronunciationError(exec Executor, id uuid.UUID, status string, errMsg string, updatedAt time.Time) error {
+	return exec.Model(&models.MessageChunk{}).
+		Where("id = ?", id).
+		Update("pronunciation_status", status).
+		Update("pronunciation_error", errMsg).
+		Update("pronunciation_updated_at", updatedAt).Error
+}
+
+// FindExpiredAudio returns chunks whose recording is older than the owner's
+// audio retention setting, oldest first.
+func (r *messageChunkRepository) FindExpiredAudio(exec Executor, now time.Time, limit int) ([]models.MessageChunk, error) {
+	var chunks []models.MessageChunk
+	err := exec.Where("audio_url IS NOT NULL").
+		Where(`created_at < (
+			SELECT ?::timestamptz - user_settings.audio_retention_days * INTERVAL '1 day'
+			FROM messages
+			JOIN threads ON threads.id = messages.thread_id
+			JOIN user_settings ON user_settings.user_id = threads.user_id
+			WHERE messages.id = message_chunks.message_id AND user_settings.audio_retention_days > 0
+		)`, now).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&chunks).Error
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+func (r *messageChunkRepository) ClearAudio(exec Executor, id uuid.UUID) error {
+	return exec.Model(&models.MessageChunk{}).Where("id = ?", id).Update("audio_url", nil).Error
+}
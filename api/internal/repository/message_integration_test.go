@@ -0,0 +1,81 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestMessageRepository_FindOwnedAudioKeys(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewMessageRepository()
+	exec := testDB.DB.DB
+
+	owner := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Owner"}
+	require.NoError(t, testDB.Create(owner).Error)
+	other := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Other"}
+	require.NoError(t, testDB.Create(other).Error)
+
+	thread := &models.Thread{UserID: owner.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	messageKey := models.UserAudioKey(thread.ID, uuid.New())
+	message := &models.Message{ThreadID: thread.ID, Role: "user", Content: "hi", AudioURL: &messageKey, HasAudio: true, Timestamp: time.Now()}
+	require.NoError(t, testDB.Create(message).Error)
+	chunkKey := models.ChunkAudioKey(thread.ID, message.ID, 0)
+	require.NoError(t, testDB.Create(&models.MessageChunk{MessageID: message.ID, AudioURL: &chunkKey, CreatedAt: time.Now()}).Error)
+
+	otherThread := &models.Thread{UserID: other.ID}
+	require.NoError(t, testDB.Create(otherThread).Error)
+	otherKey := models.AssistantAudioKey(otherThread.ID, uuid.New())
+	require.NoError(t, testDB.Create(&models.Message{ThreadID: otherThread.ID, Role: "assistant", Content: "hello", AudioURL: &otherKey, HasAudio: true, Timestamp: time.Now()}).Error)
+
+	owned, err := repo.FindOwnedAudioKeys(exec, owner.ID, []string{messageKey, chunkKey, otherKey, "v2/user/missing"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{messageKey, chunkKey}, owned)
+
+	owned, err = repo.FindOwnedAudioKeys(exec, other.ID, []string{messageKey, chunkKey})
+	require.NoError(t, err)
+	assert.Empty(t, owned, "another user's recordings aren't theirs")
+}
+
+func TestMessageRepository_FindPendingPronunciationBefore(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewMessageRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Pending"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+
+	startedAt := time.Now().UTC().Truncate(time.Second)
+	message := func(role, status string, sentAt time.Time) uuid.UUID {
+		m := &models.Message{ThreadID: thread.ID, Role: role, Content: "hi", PronunciationStatus: status, Timestamp: sentAt}
+		require.NoError(t, testDB.Create(m).Error)
+		return m.ID
+	}
+	older := message("user", "pending", startedAt.Add(-time.Hour))
+	newer := message("user", "pending", startedAt.Add(-time.Minute))
+	message("user", "pending", startedAt.Add(time.Minute))
+	message("user", "complete", startedAt.Add(-time.Hour))
+	message("assistant", "pending", startedAt.Add(-time.Hour))
+
+	pending, err := repo.FindPendingPronunciationBefore(exec, startedAt, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "only user messages pending from before the start")
+	assert.Equal(t, older, pending[0].ID)
+	assert.Equal(t, newer, pending[1].ID)
+	assert.Equal(t, "hi", pending[0].Content)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/repository"
+)
+
+// MockAccountMergeRepository is a mock implementation of AccountMergeRepository for testing.
+type MockAccountMergeRepository struct {
+	mock.Mock
+}
+
+// Ensure MockAccountMergeRepository implements AccountMergeRepository.
+var _ repository.AccountMergeRepository = (*MockAccountMergeRepository)(nil)
+
+func (m *MockAccountMergeRepository) MoveOwned(exec repository.Executor, fromUserID, toUserID uuid.UUID) (map[string]int64, error) {
+	args := m.Called(exec, fromUserID, toUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockAccountMergeRepository) MergePhonemeStats(exec repository.Executor, fromUserID, toUserID uuid.UUID) (int64, error) {
+	args := m.Called(exec, fromUserID, toUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAccountMergeRepository) MoveSingletons(exec repository.Executor, fromUserID, toUserID uuid.UUID) ([]string, error) {
+	args := m.Called(exec, fromUserID, toUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAccountMergeRepository) MoveSubscription(exec repository.Executor, fromUserID, toUserID uuid.UUID) error {
+	args := m.Called(exec, fromUserID, toUserID)
+	return args.Error(0)
+}
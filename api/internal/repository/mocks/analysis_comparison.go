@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockAnalysisComparisonRepository is a mock implementation of AnalysisComparisonRepository for testing.
+type MockAnalysisComparisonRepository struct {
+	mock.Mock
+}
+
+// Ensure MockAnalysisComparisonRepository implements AnalysisComparisonRepository.
+var _ repository.AnalysisComparisonRepository = (*MockAnalysisComparisonRepository)(nil)
+
+func (m *MockAnalysisComparisonRepository) Create(exec repository.Executor, comparison *models.AnalysisComparison) error {
+	args := m.Called(exec, comparison)
+	return args.Error(0)
+}
+
+func (m *MockAnalysisComparisonRepository) Summarize(exec repository.Executor, since time.Time) ([]models.AnalysisComparisonSummary, error) {
+	args := m.Called(exec, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AnalysisComparisonSummary), args.Error(1)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockAnalyticsEventRepository is a mock implementation of AnalyticsEventRepository for testing.
+type MockAnalyticsEventRepository struct {
+	mock.Mock
+}
+
+// Ensure MockAnalyticsEventRepository implements AnalyticsEventRepository.
+var _ repository.AnalyticsEventRepository = (*MockAnalyticsEventRepository)(nil)
+
+func (m *MockAnalyticsEventRepository) CreateBatch(exec repository.Executor, events []models.AnalyticsEvent) error {
+	args := m.Called(exec, events)
+	return args.Error(0)
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockAudioKeyMigrationRepository is a mock implementation of AudioKeyMigrationRepository for testing.
+type MockAudioKeyMigrationRepository struct {
+	mock.Mock
+}
+
+// Ensure MockAudioKeyMigrationRepository implements AudioKeyMigrationRepository.
+var _ repository.AudioKeyMigrationRepository = (*MockAudioKeyMigrationRepository)(nil)
+
+func (m *MockAudioKeyMigrationRepository) FindLegacy(exec repository.Executor, limit int) ([]repository.LegacyAudio, error) {
+	args := m.Called(exec, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.LegacyAudio), args.Error(1)
+}
+
+func (m *MockAudioKeyMigrationRepository) Switch(exec repository.Executor, recording repository.LegacyAudio, key, format string, at time.Time) (bool, error) {
+	args := m.Called(exec, recording, key, format, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAudioKeyMigrationRepository) FindMigratedBefore(exec repository.Executor, before time.Time, limit int) ([]models.AudioKeyMigration, error) {
+	args := m.Called(exec, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AudioKeyMigration), args.Error(1)
+}
+
+func (m *MockAudioKeyMigrationRepository) Delete(exec repository.Executor, id uuid.UUID) error {
+	args := m.Called(exec, id)
+	return args.Error(0)
+}
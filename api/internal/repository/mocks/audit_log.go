@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockAuditLogRepository is a mock implementation of AuditLogRepository for testing.
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+// Ensure MockAuditLogRepository implements AuditLogRepository.
+var _ repository.AuditLogRepository = (*MockAuditLogRepository)(nil)
+
+func (m *MockAuditLogRepository) Create(exec repository.Executor, entry *models.AuditLog) error {
+	args := m.Called(exec, entry)
+	return args.Error(0)
+}
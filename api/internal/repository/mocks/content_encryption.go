@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/repository"
+)
+
+// MockContentEncryptionRepository is a mock implementation of ContentEncryptionRepository for testing.
+type MockContentEncryptionRepository struct {
+	mock.Mock
+}
+
+// Ensure MockContentEncryptionRepository implements ContentEncryptionRepository.
+var _ repository.ContentEncryptionRepository = (*MockContentEncryptionRepository)(nil)
+
+func (m *MockContentEncryptionRepository) SealPendingMessages(exec repository.Executor, limit int) (int, error) {
+	args := m.Called(exec, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockContentEncryptionRepository) SealPendingChunks(exec repository.Executor, limit int) (int, error) {
+	args := m.Called(exec, limit)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockCreditDisputeRepository is a mock implementation of CreditDisputeRepository for testing.
+type MockCreditDisputeRepository struct {
+	mock.Mock
+}
+
+// Ensure MockCreditDisputeRepository implements CreditDisputeRepository.
+var _ repository.CreditDisputeRepository = (*MockCreditDisputeRepository)(nil)
+
+func (m *MockCreditDisputeRepository) Create(exec repository.Executor, dispute *models.CreditDispute) error {
+	args := m.Called(exec, dispute)
+	return args.Error(0)
+}
+
+func (m *MockCreditDisputeRepository) FindByTransactionID(exec repository.Executor, transactionID uuid.UUID) (*models.CreditDispute, error) {
+	args := m.Called(exec, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditDispute), args.Error(1)
+}
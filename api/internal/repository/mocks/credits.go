@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 
@@ -24,6 +26,14 @@ func (m *MockCreditsRepository) FindByUserID(exec repository.Executor, userID uu
 	return args.Get(0).(*models.Credits), args.Error(1)
 }
 
+func (m *MockCreditsRepository) FindByUserIDForUpdate(exec repository.Executor, userID uuid.UUID) (*models.Credits, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Credits), args.Error(1)
+}
+
 func (m *MockCreditsRepository) Create(exec repository.Executor, credits *models.Credits) error {
 	args := m.Called(exec, credits)
 	return args.Error(0)
@@ -39,6 +49,19 @@ func (m *MockCreditsRepository) UpdateAllowance(exec repository.Executor, userID
 	return args.Error(0)
 }
 
+func (m *MockCreditsRepository) FindFreeRefreshDue(exec repository.Executor, refreshedBefore time.Time, limit int) ([]models.Credits, error) {
+	args := m.Called(exec, refreshedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Credits), args.Error(1)
+}
+
+func (m *MockCreditsRepository) Refresh(exec repository.Executor, credits *models.Credits, balance int, refreshedAt time.Time) (bool, error) {
+	args := m.Called(exec, credits, balance, refreshedAt)
+	return args.Bool(0), args.Error(1)
+}
+
 // MockCreditTransactionRepository is a mock implementation of CreditTransactionRepository for testing.
 type MockCreditTransactionRepository struct {
 	mock.Mock
@@ -52,10 +75,75 @@ func (m *MockCreditTransactionRepository) Create(exec repository.Executor, tx *m
 	return args.Error(0)
 }
 
-func (m *MockCreditTransactionRepository) FindByUserID(exec repository.Executor, userID uuid.UUID, limit int) ([]models.CreditTransaction, error) {
-	args := m.Called(exec, userID, limit)
+func (m *MockCreditTransactionRepository) FindByUserID(exec repository.Executor, userID uuid.UUID, limit, offset int) ([]models.CreditTransaction, error) {
+	args := m.Called(exec, userID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.CreditTransaction), args.Error(1)
 }
+
+func (m *MockCreditTransactionRepository) FindByIDAndUserID(exec repository.Executor, id, userID uuid.UUID) (*models.CreditTransaction, error) {
+	args := m.Called(exec, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditTransaction), args.Error(1)
+}
+
+// MockCreditReservationRepository is a mock implementation of CreditReservationRepository for testing.
+type MockCreditReservationRepository struct {
+	mock.Mock
+}
+
+// Ensure MockCreditReservationRepository implements CreditReservationRepository.
+var _ repository.CreditReservationRepository = (*MockCreditReservationRepository)(nil)
+
+func (m *MockCreditReservationRepository) Create(exec repository.Executor, reservation *models.CreditReservation) error {
+	args := m.Called(exec, reservation)
+	return args.Error(0)
+}
+
+func (m *MockCreditReservationRepository) FindByID(exec repository.Executor, id uuid.UUID) (*models.CreditReservation, error) {
+	args := m.Called(exec, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditReservation), args.Error(1)
+}
+
+func (m *MockCreditReservationRepository) Settle(exec repository.Executor, id uuid.UUID, status models.CreditReservationStatus, settledAt time.Time) (bool, error) {
+	args := m.Called(exec, id, status, settledAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCreditReservationRepository) FindExpired(exec repository.Executor, now time.Time, limit int) ([]models.CreditReservation, error) {
+	args := m.Called(exec, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CreditReservation), args.Error(1)
+}
+
+func (m *MockCreditReservationRepository) FindByReference(exec repository.Executor, reference string) (*models.CreditReservation, error) {
+	args := m.Called(exec, reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditReservation), args.Error(1)
+}
+
+func (m *MockCreditReservationRepository) Reduce(exec repository.Executor, id uuid.UUID, from, to int) (bool, error) {
+	args := m.Called(exec, id, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCreditReservationRepository) SumHeld(exec repository.Executor, userID uuid.UUID) (int, error) {
+	args := m.Called(exec, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCreditReservationRepository) MarkRefunded(exec repository.Executor, id uuid.UUID, refundedAt time.Time) (bool, error) {
+	args := m.Called(exec, id, refundedAt)
+	return args.Bool(0), args.Error(1)
+}
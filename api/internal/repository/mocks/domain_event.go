@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockDomainEventRepository is a mock implementation of DomainEventRepository for testing.
+type MockDomainEventRepository struct {
+	mock.Mock
+}
+
+// Ensure MockDomainEventRepository implements DomainEventRepository.
+var _ repository.DomainEventRepository = (*MockDomainEventRepository)(nil)
+
+func (m *MockDomainEventRepository) Create(exec repository.Executor, event *models.DomainEvent) error {
+	args := m.Called(exec, event)
+	return args.Error(0)
+}
+
+func (m *MockDomainEventRepository) FindPage(exec repository.Executor, filter repository.DomainEventFilter, after *models.DomainEvent, limit int) ([]models.DomainEvent, error) {
+	args := m.Called(exec, filter, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DomainEvent), args.Error(1)
+}
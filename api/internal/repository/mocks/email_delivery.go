@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockEmailDeliveryRepository is a mock implementation of EmailDeliveryRepository for testing.
+type MockEmailDeliveryRepository struct {
+	mock.Mock
+}
+
+// Ensure MockEmailDeliveryRepository implements EmailDeliveryRepository.
+var _ repository.EmailDeliveryRepository = (*MockEmailDeliveryRepository)(nil)
+
+func (m *MockEmailDeliveryRepository) Create(exec repository.Executor, delivery *models.EmailDelivery) error {
+	args := m.Called(exec, delivery)
+	return args.Error(0)
+}
+
+func (m *MockEmailDeliveryRepository) CountSince(exec repository.Executor, userID uuid.UUID, kind string, since time.Time) (int64, error) {
+	args := m.Called(exec, userID, kind, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEmailDeliveryRepository) LastSentAt(exec repository.Executor, userID uuid.UUID, kind string) (*time.Time, error) {
+	args := m.Called(exec, userID, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockEmailVerificationRepository is a mock implementation of EmailVerificationRepository for testing.
+type MockEmailVerificationRepository struct {
+	mock.Mock
+}
+
+// Ensure MockEmailVerificationRepository implements EmailVerificationRepository.
+var _ repository.EmailVerificationRepository = (*MockEmailVerificationRepository)(nil)
+
+func (m *MockEmailVerificationRepository) Create(exec repository.Executor, verification *models.EmailVerification) error {
+	args := m.Called(exec, verification)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationRepository) FindByTokenHash(exec repository.Executor, tokenHash string) (*models.EmailVerification, error) {
+	args := m.Called(exec, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailVerification), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) Use(exec repository.Executor, id uuid.UUID, now time.Time) (bool, error) {
+	args := m.Called(exec, id, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) CountSince(exec repository.Executor, userID uuid.UUID, since time.Time) (int64, error) {
+	args := m.Called(exec, userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
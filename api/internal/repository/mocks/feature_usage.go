@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockFeatureUsageRepository is a mock implementation of FeatureUsageRepository for testing.
+type MockFeatureUsageRepository struct {
+	mock.Mock
+}
+
+// Ensure MockFeatureUsageRepository implements FeatureUsageRepository.
+var _ repository.FeatureUsageRepository = (*MockFeatureUsageRepository)(nil)
+
+func (m *MockFeatureUsageRepository) Create(exec repository.Executor, event *models.FeatureUsageEvent) error {
+	args := m.Called(exec, event)
+	return args.Error(0)
+}
+
+func (m *MockFeatureUsageRepository) RollupDay(exec repository.Executor, day time.Time) error {
+	args := m.Called(exec, day)
+	return args.Error(0)
+}
+
+func (m *MockFeatureUsageRepository) FindDaily(exec repository.Executor, from, to time.Time) ([]models.FeatureUsageDaily, error) {
+	args := m.Called(exec, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FeatureUsageDaily), args.Error(1)
+}
+
+func (m *MockFeatureUsageRepository) DeleteEventsBefore(exec repository.Executor, before time.Time) (int64, error) {
+	args := m.Called(exec, before)
+	return args.Get(0).(int64), args.Error(1)
+}
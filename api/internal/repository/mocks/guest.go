@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockGuestRepository is a mock implementation of GuestRepository for testing.
+type MockGuestRepository struct {
+	mock.Mock
+}
+
+// Ensure MockGuestRepository implements GuestRepository.
+var _ repository.GuestRepository = (*MockGuestRepository)(nil)
+
+func (m *MockGuestRepository) FindExpired(exec repository.Executor, now time.Time, limit int) ([]models.User, error) {
+	args := m.Called(exec, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockGuestRepository) CountCreatedFromIP(exec repository.Executor, ip string, since time.Time) (int64, error) {
+	args := m.Called(exec, ip, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockGuestRepository) FindAudioKeys(exec repository.Executor, userID uuid.UUID) ([]string, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockGuestRepository) MoveThreads(exec repository.Executor, fromUserID, toUserID uuid.UUID) (int64, error) {
+	args := m.Called(exec, fromUserID, toUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockGuestRepository) DeleteUser(exec repository.Executor, userID uuid.UUID) error {
+	args := m.Called(exec, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockIdempotencyKeyRepository is a mock implementation of IdempotencyKeyRepository for testing.
+type MockIdempotencyKeyRepository struct {
+	mock.Mock
+}
+
+// Ensure MockIdempotencyKeyRepository implements IdempotencyKeyRepository.
+var _ repository.IdempotencyKeyRepository = (*MockIdempotencyKeyRepository)(nil)
+
+func (m *MockIdempotencyKeyRepository) Reserve(exec repository.Executor, key *models.IdempotencyKey) (bool, error) {
+	args := m.Called(exec, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) FindByUserAndKey(exec repository.Executor, userID uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	args := m.Called(exec, userID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) Complete(exec repository.Executor, id uuid.UUID, status int, contentType string, body []byte, completedAt time.Time) error {
+	args := m.Called(exec, id, status, contentType, body, completedAt)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) Delete(exec repository.Executor, id uuid.UUID) error {
+	args := m.Called(exec, id)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) DeleteCreatedBefore(exec repository.Executor, t time.Time) (int64, error) {
+	args := m.Called(exec, t)
+	return args.Get(0).(int64), args.Error(1)
+}
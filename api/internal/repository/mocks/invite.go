@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockInviteCodeRepository is a mock implementation of InviteCodeRepository for testing.
+type MockInviteCodeRepository struct {
+	mock.Mock
+}
+
+// Ensure MockInviteCodeRepository implements InviteCodeRepository.
+var _ repository.InviteCodeRepository = (*MockInviteCodeRepository)(nil)
+
+func (m *MockInviteCodeRepository) Create(exec repository.Executor, invite *models.InviteCode) error {
+	args := m.Called(exec, invite)
+	return args.Error(0)
+}
+
+func (m *MockInviteCodeRepository) FindAll(exec repository.Executor, limit int) ([]models.InviteCode, error) {
+	args := m.Called(exec, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InviteCode), args.Error(1)
+}
+
+func (m *MockInviteCodeRepository) Redeem(exec repository.Executor, code string, now time.Time) error {
+	args := m.Called(exec, code, now)
+	return args.Error(0)
+}
+
+// MockWaitlistRepository is a mock implementation of WaitlistRepository for testing.
+type MockWaitlistRepository struct {
+	mock.Mock
+}
+
+// Ensure MockWaitlistRepository implements WaitlistRepository.
+var _ repository.WaitlistRepository = (*MockWaitlistRepository)(nil)
+
+func (m *MockWaitlistRepository) Create(exec repository.Executor, entry *models.WaitlistEntry) error {
+	args := m.Called(exec, entry)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistRepository) FindByID(exec repository.Executor, id uuid.UUID) (*models.WaitlistEntry, error) {
+	args := m.Called(exec, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) FindAll(exec repository.Executor, pendingOnly bool, limit int) ([]models.WaitlistEntry, error) {
+	args := m.Called(exec, pendingOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) Save(exec repository.Executor, entry *models.WaitlistEntry) error {
+	args := m.Called(exec, entry)
+	return args.Error(0)
+}
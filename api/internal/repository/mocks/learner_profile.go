@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockLearnerProfileRepository is a mock implementation of LearnerProfileRepository for testing.
+type MockLearnerProfileRepository struct {
+	mock.Mock
+}
+
+// Ensure MockLearnerProfileRepository implements LearnerProfileRepository.
+var _ repository.LearnerProfileRepository = (*MockLearnerProfileRepository)(nil)
+
+func (m *MockLearnerProfileRepository) FindByUserID(exec repository.Executor, userID uuid.UUID) (*models.LearnerProfile, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LearnerProfile), args.Error(1)
+}
+
+func (m *MockLearnerProfileRepository) Upsert(exec repository.Executor, profile *models.LearnerProfile) error {
+	args := m.Called(exec, profile)
+	return args.Error(0)
+}
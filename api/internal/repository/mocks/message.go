@@ -39,13 +39,18 @@ func (m *MockMessageRepository) FindByThreadID(exec repository.Executor, threadI
 	return args.Get(0).([]models.Message), args.Error(1)
 }
 
+func (m *MockMessageRepository) CountUserMessagesByUserID(exec repository.Executor, userID uuid.UUID) (int64, error) {
+	args := m.Called(exec, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockMessageRepository) UpdatePronunciationStatus(exec repository.Executor, id uuid.UUID, status string) error {
 	args := m.Called(exec, id, status)
 	return args.Error(0)
 }
 
-func (m *MockMessageRepository) UpdatePronunciationAnalysis(exec repository.Executor, id uuid.UUID, status string, analysis models.JSONMap, updatedAt time.Time) error {
-	args := m.Called(exec, id, status, analysis, updatedAt)
+func (m *MockMessageRepository) UpdatePronunciationAnalysis(exec repository.Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	args := m.Called(exec, id, status, analysis, quality, confidence, lowConfidence, updatedAt)
 	return args.Error(0)
 }
 
@@ -53,3 +58,95 @@ func (m *MockMessageRepository) UpdatePronunciationError(exec repository.Executo
 	args := m.Called(exec, id, status, errMsg, updatedAt)
 	return args.Error(0)
 }
+
+func (m *MockMessageRepository) FindExpiredUserAudio(exec repository.Executor, now time.Time, limit int) ([]models.Message, error) {
+	args := m.Called(exec, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindAnalyzedByUserID(exec repository.Executor, userID uuid.UUID, limit int) ([]models.Message, error) {
+	args := m.Called(exec, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindActiveDaysByUserID(exec repository.Executor, userID uuid.UUID, since time.Time, loc *time.Location) ([]time.Time, error) {
+	args := m.Called(exec, userID, since, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockMessageRepository) ClearAudio(exec repository.Executor, id uuid.UUID) error {
+	args := m.Called(exec, id)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) UpdateContent(exec repository.Executor, id uuid.UUID, content string, correctedAt time.Time) error {
+	args := m.Called(exec, id, content, correctedAt)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) ResetPronunciation(exec repository.Executor, id uuid.UUID, status string, updatedAt time.Time) error {
+	args := m.Called(exec, id, status, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) RetryPronunciation(exec repository.Executor, id uuid.UUID, maxRetries int, updatedAt time.Time) (bool, error) {
+	args := m.Called(exec, id, maxRetries, updatedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindUserMessagesByUserID(exec repository.Executor, userID uuid.UUID, before time.Time, limit int) ([]models.Message, error) {
+	args := m.Called(exec, userID, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) CountPronunciationStatuses(exec repository.Executor, since time.Time) (map[string]int64, error) {
+	args := m.Called(exec, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindPendingPronunciation(exec repository.Executor, limit int) ([]models.Message, error) {
+	args := m.Called(exec, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindPendingPronunciationBefore(exec repository.Executor, before time.Time, limit int) ([]models.Message, error) {
+	args := m.Called(exec, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindFailedPronunciation(exec repository.Executor, since time.Time, limit int) ([]models.Message, error) {
+	args := m.Called(exec, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindOwnedAudioKeys(exec repository.Executor, userID uuid.UUID, keys []string) ([]string, error) {
+	args := m.Called(exec, userID, keys)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockMessageChunkRepository is a mock implementation of MessageChunkRepository for testing.
+type MockMessageChunkRepository struct {
+	mock.Mock
+}
+
+// Ensure MockMessageChunkRepository implements MessageChunkRepository.
+var _ repository.MessageChunkRepository = (*MockMessageChunkRepository)(nil)
+
+func (m *MockMessageChunkRepository) CreateBatch(exec repository.Executor, chunks []models.MessageChunk) error {
+	args := m.Called(exec, chunks)
+	return args.Error(0)
+}
+
+func (m *MockMessageChunkRepository) FindByMessageID(exec repository.Executor, messageID uuid.UUID) ([]models.MessageChunk, error) {
+	args := m.Called(exec, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MessageChunk), args.Error(1)
+}
+
+func (m *MockMessageChunkRepository) UpdatePronunciationAnalysis(exec repository.Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	args := m.Called(exec, id, status, analysis, quality, confidence, lowConfidence, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockMessageChunkRepository) UpdatePronunciationError(exec repository.Executor, id uuid.UUID, status string, errMsg string, updatedAt time.Time) error {
+	args := m.Called(exec, id, status, errMsg, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockMessageChunkRepository) FindExpiredAudio(exec repository.Executor, now time.Time, limit int) ([]models.MessageChunk, error) {
+	args := m.Called(exec, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MessageChunk), args.Error(1)
+}
+
+func (m *MockMessageChunkRepository) ClearAudio(exec repository.Executor, id uuid.UUID) error {
+	args := m.Called(exec, id)
+	return args.Error(0)
+}
@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockNotificationRepository is a mock implementation of NotificationRepository for testing.
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+// Ensure MockNotificationRepository implements NotificationRepository.
+var _ repository.NotificationRepository = (*MockNotificationRepository)(nil)
+
+func (m *MockNotificationRepository) Create(exec repository.Executor, notification *models.Notification) error {
+	args := m.Called(exec, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) FindByUserID(exec repository.Executor, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, error) {
+	args := m.Called(exec, userID, unreadOnly, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CountUnread(exec repository.Executor, userID uuid.UUID) (int64, error) {
+	args := m.Called(exec, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(exec repository.Executor, id, userID uuid.UUID, readAt time.Time) error {
+	args := m.Called(exec, id, userID, readAt)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(exec repository.Executor, userID uuid.UUID, readAt time.Time) error {
+	args := m.Called(exec, userID, readAt)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) FindUsersWithPendingEmail(exec repository.Executor) ([]uuid.UUID, error) {
+	args := m.Called(exec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockNotificationRepository) FindPendingEmailByUserID(exec repository.Executor, userID uuid.UUID) ([]models.Notification, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) SetEmailStatus(exec repository.Executor, ids []uuid.UUID, status string) error {
+	args := m.Called(exec, ids, status)
+	return args.Error(0)
+}
@@ -21,19 +21,27 @@ func (m *MockPhonemeStatsRepository) Upsert(exec repository.Executor, stats *mod
 	return args.Error(0)
 }
 
-func (m *MockPhonemeStatsRepository) FindByUserID(exec repository.Executor, userID uuid.UUID) ([]models.PhonemeStats, error) {
-	args := m.Called(exec, userID)
+func (m *MockPhonemeStatsRepository) FindByUserID(exec repository.Executor, userID uuid.UUID, language string) ([]models.PhonemeStats, error) {
+	args := m.Called(exec, userID, language)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.PhonemeStats), args.Error(1)
 }
 
-func (m *MockPhonemeStatsRepository) GetAccuracyRanking(exec repository.Executor, userID uuid.UUID) ([]repository.PhonemeAccuracy, error) {
+func (m *MockPhonemeStatsRepository) FindLanguages(exec repository.Executor, userID uuid.UUID) ([]string, error) {
 	args := m.Called(exec, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockPhonemeStatsRepository) GetAccuracyRanking(exec repository.Executor, userID uuid.UUID, language string) ([]repository.PhonemeAccuracy, error) {
+	args := m.Called(exec, userID, language)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]repository.PhonemeAccuracy), args.Error(1)
 }
 
@@ -50,10 +58,31 @@ func (m *MockPhonemeSubstitutionRepository) Upsert(exec repository.Executor, sub
 	return args.Error(0)
 }
 
-func (m *MockPhonemeSubstitutionRepository) FindTopByUserID(exec repository.Executor, userID uuid.UUID, limit int) ([]models.PhonemeSubstitution, error) {
-	args := m.Called(exec, userID, limit)
+func (m *MockPhonemeSubstitutionRepository) FindTopByUserID(exec repository.Executor, userID uuid.UUID, language string, limit int) ([]models.PhonemeSubstitution, error) {
+	args := m.Called(exec, userID, language, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.PhonemeSubstitution), args.Error(1)
 }
+
+// MockPhonemeStatsSnapshotRepository is a mock implementation of PhonemeStatsSnapshotRepository for testing.
+type MockPhonemeStatsSnapshotRepository struct {
+	mock.Mock
+}
+
+// Ensure MockPhonemeStatsSnapshotRepository implements PhonemeStatsSnapshotRepository.
+var _ repository.PhonemeStatsSnapshotRepository = (*MockPhonemeStatsSnapshotRepository)(nil)
+
+func (m *MockPhonemeStatsSnapshotRepository) Save(exec repository.Executor, snapshot *models.PhonemeStatsSnapshot) error {
+	args := m.Called(exec, snapshot)
+	return args.Error(0)
+}
+
+func (m *MockPhonemeStatsSnapshotRepository) Take(exec repository.Executor, messageID uuid.UUID) (*models.PhonemeStatsSnapshot, error) {
+	args := m.Called(exec, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PhonemeStatsSnapshot), args.Error(1)
+}
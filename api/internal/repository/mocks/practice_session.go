@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockPracticeSessionRepository is a mock implementation of PracticeSessionRepository for testing.
+type MockPracticeSessionRepository struct {
+	mock.Mock
+}
+
+// Ensure MockPracticeSessionRepository implements PracticeSessionRepository.
+var _ repository.PracticeSessionRepository = (*MockPracticeSessionRepository)(nil)
+
+func (m *MockPracticeSessionRepository) Create(exec repository.Executor, session *models.PracticeSession) error {
+	args := m.Called(exec, session)
+	return args.Error(0)
+}
+
+func (m *MockPracticeSessionRepository) FindByIDAndUserID(exec repository.Executor, id, userID uuid.UUID) (*models.PracticeSession, error) {
+	args := m.Called(exec, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PracticeSession), args.Error(1)
+}
+
+func (m *MockPracticeSessionRepository) FindOpenByThreadID(exec repository.Executor, threadID uuid.UUID) (*models.PracticeSession, error) {
+	args := m.Called(exec, threadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PracticeSession), args.Error(1)
+}
+
+func (m *MockPracticeSessionRepository) FindByUserID(exec repository.Executor, userID uuid.UUID, limit, offset int) ([]models.PracticeSession, error) {
+	args := m.Called(exec, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PracticeSession), args.Error(1)
+}
+
+func (m *MockPracticeSessionRepository) End(exec repository.Executor, id uuid.UUID, endedAt time.Time, summary *models.PracticeSessionSummary) (bool, error) {
+	args := m.Called(exec, id, endedAt, summary)
+	return args.Bool(0), args.Error(1)
+}
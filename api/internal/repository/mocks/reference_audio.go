@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockReferenceAudioRepository is a mock implementation of ReferenceAudioRepository for testing.
+type MockReferenceAudioRepository struct {
+	mock.Mock
+}
+
+// Ensure MockReferenceAudioRepository implements ReferenceAudioRepository.
+var _ repository.ReferenceAudioRepository = (*MockReferenceAudioRepository)(nil)
+
+func (m *MockReferenceAudioRepository) Find(exec repository.Executor, text, language, voice string, speed float64) (*models.ReferenceAudio, error) {
+	args := m.Called(exec, text, language, voice, speed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReferenceAudio), args.Error(1)
+}
+
+func (m *MockReferenceAudioRepository) Create(exec repository.Executor, clip *models.ReferenceAudio) error {
+	args := m.Called(exec, clip)
+	return args.Error(0)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockRuntimeSettingRepository is a mock implementation of RuntimeSettingRepository for testing.
+type MockRuntimeSettingRepository struct {
+	mock.Mock
+}
+
+// Ensure MockRuntimeSettingRepository implements RuntimeSettingRepository.
+var _ repository.RuntimeSettingRepository = (*MockRuntimeSettingRepository)(nil)
+
+func (m *MockRuntimeSettingRepository) FindAll(exec repository.Executor) ([]models.RuntimeSetting, error) {
+	args := m.Called(exec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RuntimeSetting), args.Error(1)
+}
+
+func (m *MockRuntimeSettingRepository) Upsert(exec repository.Executor, setting *models.RuntimeSetting) error {
+	args := m.Called(exec, setting)
+	return args.Error(0)
+}
+
+func (m *MockRuntimeSettingRepository) Delete(exec repository.Executor, key string) error {
+	args := m.Called(exec, key)
+	return args.Error(0)
+}
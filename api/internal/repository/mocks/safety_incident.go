@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockSafetyIncidentRepository is a mock implementation of SafetyIncidentRepository for testing.
+type MockSafetyIncidentRepository struct {
+	mock.Mock
+}
+
+// Ensure MockSafetyIncidentRepository implements SafetyIncidentRepository.
+var _ repository.SafetyIncidentRepository = (*MockSafetyIncidentRepository)(nil)
+
+func (m *MockSafetyIncidentRepository) Create(exec repository.Executor, incident *models.SafetyIncident) error {
+	args := m.Called(exec, incident)
+	return args.Error(0)
+}
@@ -31,6 +31,19 @@ func (m *MockSessionRepository) FindByIDWithUser(exec repository.Executor, token
 	return args.Get(0).(*models.Session), args.Error(1)
 }
 
+func (m *MockSessionRepository) FindActiveByUserID(exec repository.Executor, userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	args := m.Called(exec, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) UpdateExpiresAt(exec repository.Executor, token string, expiresAt time.Time) error {
+	args := m.Called(exec, token, expiresAt)
+	return args.Error(0)
+}
+
 func (m *MockSessionRepository) DeleteByID(exec repository.Executor, token string) error {
 	args := m.Called(exec, token)
 	return args.Error(0)
@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockSignupSignalRepository is a mock implementation of SignupSignalRepository for testing.
+type MockSignupSignalRepository struct {
+	mock.Mock
+}
+
+// Ensure MockSignupSignalRepository implements SignupSignalRepository.
+var _ repository.SignupSignalRepository = (*MockSignupSignalRepository)(nil)
+
+func (m *MockSignupSignalRepository) Create(exec repository.Executor, signal *models.SignupSignal) error {
+	args := m.Called(exec, signal)
+	return args.Error(0)
+}
+
+func (m *MockSignupSignalRepository) Save(exec repository.Executor, signal *models.SignupSignal) error {
+	args := m.Called(exec, signal)
+	return args.Error(0)
+}
+
+func (m *MockSignupSignalRepository) FindByUserID(exec repository.Executor, userID uuid.UUID) (*models.SignupSignal, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SignupSignal), args.Error(1)
+}
+
+func (m *MockSignupSignalRepository) FindMatching(exec repository.Executor, ipAddress, fingerprintHash string, since time.Time) ([]models.SignupSignal, error) {
+	args := m.Called(exec, ipAddress, fingerprintHash, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SignupSignal), args.Error(1)
+}
+
+func (m *MockSignupSignalRepository) FindPendingReview(exec repository.Executor, limit int) ([]models.SignupSignal, error) {
+	args := m.Called(exec, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SignupSignal), args.Error(1)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockStatsBadgeRepository is a mock implementation of StatsBadgeRepository for testing.
+type MockStatsBadgeRepository struct {
+	mock.Mock
+}
+
+// Ensure MockStatsBadgeRepository implements StatsBadgeRepository.
+var _ repository.StatsBadgeRepository = (*MockStatsBadgeRepository)(nil)
+
+func (m *MockStatsBadgeRepository) FindByUserID(exec repository.Executor, userID uuid.UUID) (*models.StatsBadge, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StatsBadge), args.Error(1)
+}
+
+func (m *MockStatsBadgeRepository) FindByToken(exec repository.Executor, token string) (*models.StatsBadge, error) {
+	args := m.Called(exec, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StatsBadge), args.Error(1)
+}
+
+func (m *MockStatsBadgeRepository) Upsert(exec repository.Executor, badge *models.StatsBadge) error {
+	args := m.Called(exec, badge)
+	return args.Error(0)
+}
+
+func (m *MockStatsBadgeRepository) DeleteByUserID(exec repository.Executor, userID uuid.UUID) error {
+	args := m.Called(exec, userID)
+	return args.Error(0)
+}
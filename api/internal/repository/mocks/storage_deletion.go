@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockStorageDeletionRepository is a mock implementation of StorageDeletionRepository for testing.
+type MockStorageDeletionRepository struct {
+	mock.Mock
+}
+
+// Ensure MockStorageDeletionRepository implements StorageDeletionRepository.
+var _ repository.StorageDeletionRepository = (*MockStorageDeletionRepository)(nil)
+
+func (m *MockStorageDeletionRepository) Create(exec repository.Executor, deletions []models.PendingStorageDeletion) error {
+	args := m.Called(exec, deletions)
+	return args.Error(0)
+}
+
+func (m *MockStorageDeletionRepository) FindPending(exec repository.Executor, createdBefore time.Time, limit int) ([]models.PendingStorageDeletion, error) {
+	args := m.Called(exec, createdBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PendingStorageDeletion), args.Error(1)
+}
+
+func (m *MockStorageDeletionRepository) Delete(exec repository.Executor, id uuid.UUID) error {
+	args := m.Called(exec, id)
+	return args.Error(0)
+}
+
+func (m *MockStorageDeletionRepository) RecordFailure(exec repository.Executor, id uuid.UUID, errMsg string, at time.Time) error {
+	args := m.Called(exec, id, errMsg, at)
+	return args.Error(0)
+}
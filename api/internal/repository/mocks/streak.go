@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockStreakRepository is a mock implementation of StreakRepository for testing.
+type MockStreakRepository struct {
+	mock.Mock
+}
+
+// Ensure MockStreakRepository implements StreakRepository.
+var _ repository.StreakRepository = (*MockStreakRepository)(nil)
+
+func (m *MockStreakRepository) Advance(exec repository.Executor, userID uuid.UUID, day time.Time) (*models.UserStreak, bool, error) {
+	args := m.Called(exec, userID, day)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*models.UserStreak), args.Bool(1), args.Error(2)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 
@@ -54,3 +56,39 @@ func (m *MockSubscriptionRepository) UpdateStatus(exec repository.Executor, subs
 	args := m.Called(exec, subscriptionID, status)
 	return args.Error(0)
 }
+
+func (m *MockSubscriptionRepository) FindGraceExpired(exec repository.Executor, now time.Time, limit int) ([]models.Subscription, error) {
+	args := m.Called(exec, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) EndGracePeriod(exec repository.Executor, id uuid.UUID) (bool, error) {
+	args := m.Called(exec, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) StartDunning(exec repository.Executor, id uuid.UUID, failedAt time.Time) (bool, error) {
+	args := m.Called(exec, id, failedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) AdvanceDunning(exec repository.Executor, id uuid.UUID, remindersSent int) (bool, error) {
+	args := m.Called(exec, id, remindersSent)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) EndDunning(exec repository.Executor, id uuid.UUID) error {
+	args := m.Called(exec, id)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) FindDunningDue(exec repository.Executor, remindersSent int, failedBefore time.Time, limit int) ([]models.Subscription, error) {
+	args := m.Called(exec, remindersSent, failedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Subscription), args.Error(1)
+}
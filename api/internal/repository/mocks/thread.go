@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 
@@ -45,6 +47,14 @@ func (m *MockThreadRepository) FindByUserID(exec repository.Executor, userID uui
 	return args.Get(0).([]models.Thread), args.Error(1)
 }
 
+func (m *MockThreadRepository) FindSummariesByUserID(exec repository.Executor, userID uuid.UUID) ([]models.ThreadSummary, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ThreadSummary), args.Error(1)
+}
+
 func (m *MockThreadRepository) FindArchivedByUserID(exec repository.Executor, userID uuid.UUID) ([]models.Thread, error) {
 	args := m.Called(exec, userID)
 	if args.Get(0) == nil {
@@ -53,6 +63,11 @@ func (m *MockThreadRepository) FindArchivedByUserID(exec repository.Executor, us
 	return args.Get(0).([]models.Thread), args.Error(1)
 }
 
+func (m *MockThreadRepository) CountByUserID(exec repository.Executor, userID uuid.UUID) (int64, error) {
+	args := m.Called(exec, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockThreadRepository) FindByIDAndUserID(exec repository.Executor, id, userID uuid.UUID) (*models.Thread, error) {
 	args := m.Called(exec, id, userID)
 	if args.Get(0) == nil {
@@ -61,8 +76,8 @@ func (m *MockThreadRepository) FindByIDAndUserID(exec repository.Executor, id, u
 	return args.Get(0).(*models.Thread), args.Error(1)
 }
 
-func (m *MockThreadRepository) FindByIDAndUserIDWithMessages(exec repository.Executor, id, userID uuid.UUID) (*models.Thread, error) {
-	args := m.Called(exec, id, userID)
+func (m *MockThreadRepository) FindByIDAndUserIDWithMessages(exec repository.Executor, id, userID uuid.UUID, withAnalysis bool) (*models.Thread, error) {
+	args := m.Called(exec, id, userID, withAnalysis)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -83,3 +98,13 @@ func (m *MockThreadRepository) UpdateName(exec repository.Executor, id uuid.UUID
 	args := m.Called(exec, id, name)
 	return args.Error(0)
 }
+
+func (m *MockThreadRepository) MarkGoalCompleted(exec repository.Executor, id uuid.UUID, completedAt time.Time) (bool, error) {
+	args := m.Called(exec, id, completedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockThreadRepository) MarkEnded(exec repository.Executor, id uuid.UUID, endedAt time.Time) (bool, error) {
+	args := m.Called(exec, id, endedAt)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockThreadReadStateRepository is a mock implementation of ThreadReadStateRepository for testing.
+type MockThreadReadStateRepository struct {
+	mock.Mock
+}
+
+// Ensure MockThreadReadStateRepository implements ThreadReadStateRepository.
+var _ repository.ThreadReadStateRepository = (*MockThreadReadStateRepository)(nil)
+
+func (m *MockThreadReadStateRepository) Find(exec repository.Executor, userID, threadID uuid.UUID) (*models.ThreadReadState, error) {
+	args := m.Called(exec, userID, threadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ThreadReadState), args.Error(1)
+}
+
+func (m *MockThreadReadStateRepository) MarkRead(exec repository.Executor, userID, threadID uuid.UUID, readAt time.Time) error {
+	args := m.Called(exec, userID, threadID, readAt)
+	return args.Error(0)
+}
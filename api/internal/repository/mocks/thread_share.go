@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockThreadShareRepository is a mock implementation of ThreadShareRepository for testing.
+type MockThreadShareRepository struct {
+	mock.Mock
+}
+
+// Ensure MockThreadShareRepository implements ThreadShareRepository.
+var _ repository.ThreadShareRepository = (*MockThreadShareRepository)(nil)
+
+func (m *MockThreadShareRepository) Create(exec repository.Executor, share *models.ThreadShare) error {
+	args := m.Called(exec, share)
+	return args.Error(0)
+}
+
+func (m *MockThreadShareRepository) FindByToken(exec repository.Executor, token string) (*models.ThreadShare, error) {
+	args := m.Called(exec, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ThreadShare), args.Error(1)
+}
+
+func (m *MockThreadShareRepository) FindActiveByThreadID(exec repository.Executor, threadID uuid.UUID, now time.Time) ([]models.ThreadShare, error) {
+	args := m.Called(exec, threadID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ThreadShare), args.Error(1)
+}
+
+func (m *MockThreadShareRepository) Revoke(exec repository.Executor, id, threadID uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(exec, id, threadID, revokedAt)
+	return args.Error(0)
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 
 	"ling-app/api/internal/models"
@@ -15,6 +16,14 @@ type MockUserRepository struct {
 // Ensure MockUserRepository implements UserRepository.
 var _ repository.UserRepository = (*MockUserRepository)(nil)
 
+func (m *MockUserRepository) FindByID(exec repository.Executor, id uuid.UUID) (*models.User, error) {
+	args := m.Called(exec, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) FindByEmail(exec repository.Executor, email string) (*models.User, error) {
 	args := m.Called(exec, email)
 	if args.Get(0) == nil {
@@ -48,3 +57,24 @@ func (m *MockUserRepository) Save(exec repository.Executor, user *models.User) e
 	args := m.Called(exec, user)
 	return args.Error(0)
 }
+
+func (m *MockUserRepository) Search(exec repository.Executor, query string, limit int) ([]models.User, error) {
+	args := m.Called(exec, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindMergedInto(exec repository.Executor, id uuid.UUID) ([]models.User, error) {
+	args := m.Called(exec, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(exec repository.Executor, id uuid.UUID, email string) (bool, error) {
+	args := m.Called(exec, id, email)
+	return args.Bool(0), args.Error(1)
+}
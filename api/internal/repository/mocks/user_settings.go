@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockUserSettingsRepository is a mock implementation of UserSettingsRepository for testing.
+type MockUserSettingsRepository struct {
+	mock.Mock
+}
+
+// Ensure MockUserSettingsRepository implements UserSettingsRepository.
+var _ repository.UserSettingsRepository = (*MockUserSettingsRepository)(nil)
+
+func (m *MockUserSettingsRepository) FindByUserID(exec repository.Executor, userID uuid.UUID) (*models.UserSettings, error) {
+	args := m.Called(exec, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+func (m *MockUserSettingsRepository) Upsert(exec repository.Executor, settings *models.UserSettings) error {
+	args := m.Called(exec, settings)
+	return args.Error(0)
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MockWarehouseRepository is a mock implementation of WarehouseRepository for testing.
+type MockWarehouseRepository struct {
+	mock.Mock
+}
+
+// Ensure MockWarehouseRepository implements WarehouseRepository.
+var _ repository.WarehouseRepository = (*MockWarehouseRepository)(nil)
+
+func (m *MockWarehouseRepository) FindWatermarks(exec repository.Executor) ([]models.WarehouseWatermark, error) {
+	args := m.Called(exec)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WarehouseWatermark), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) SaveWatermark(exec repository.Executor, watermark *models.WarehouseWatermark) error {
+	args := m.Called(exec, watermark)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseRepository) FirstFactTime(exec repository.Executor, table string) (*time.Time, error) {
+	args := m.Called(exec, table)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) MessageFacts(exec repository.Executor, from, to time.Time) ([]models.WarehouseMessageFact, error) {
+	args := m.Called(exec, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WarehouseMessageFact), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) AnalysisFacts(exec repository.Executor, from, to time.Time) ([]models.WarehouseAnalysisFact, error) {
+	args := m.Called(exec, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WarehouseAnalysisFact), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) TransactionFacts(exec repository.Executor, from, to time.Time) ([]models.CreditTransaction, error) {
+	args := m.Called(exec, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CreditTransaction), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) UsageFacts(exec repository.Executor, from, to time.Time) ([]models.WarehouseUsageFact, error) {
+	args := m.Called(exec, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WarehouseUsageFact), args.Error(1)
+}
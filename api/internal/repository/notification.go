@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// notificationRepository implements NotificationRepository using GORM.
+type notificationRepository struct{}
+
+// NewNotificationRepository creates a new GORM-backed notification repository.
+func NewNotificationRepository() NotificationRepository {
+	return &notificationRepository{}
+}
+
+func (r *notificationRepository) Create(exec Executor, notification *models.Notification) error {
+	return exec.Create(notification).Error
+}
+
+func (r *notificationRepository) FindByUserID(exec Executor, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := exec.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) CountUnread(exec Executor, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := exec.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(exec Executor, id, userID uuid.UUID, readAt time.Time) error {
+	result := exec.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", readAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(exec Executor, userID uuid.UUID, readAt time.Time) error {
+	return exec.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", readAt).Error
+}
+
+func (r *notificationRepository) FindUsersWithPendingEmail(exec Executor) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := exec.Model(&models.Notification{}).
+		Where("email_status = ?", models.EmailStatusPending).
+		Distinct().Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *notificationRepository) FindPendingEmailByUserID(exec Executor, userID uuid.UUID) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := exec.Where("user_id = ? AND email_status = ?", userID, models.EmailStatusPending).
+		Order("created_at ASC").Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) SetEmailStatus(exec Executor, ids []uuid.UUID, status string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return exec.Model(&models.Notification{}).Where("id IN ?", ids).Update("email_status", status).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// The pgx repositories serve the hottest queries (session lookup, message
+// reads and writes) through sqlc-generated code instead of GORM. They
+// implement the same interfaces, so services don't know which backend they
+// are talking to.
+//
+// The interfaces still take an Executor. A pgx repository ignores it unless
+// it is a GORM transaction; those calls are handed to the GORM repository so
+// they commit or roll back with the rest of the transaction.
+
+// inGormTx reports whether exec is a *gorm.DB bound to an open transaction.
+func inGormTx(exec Executor) bool {
+	db, ok := exec.(*gorm.DB)
+	if !ok || db == nil || db.Statement == nil {
+		return false
+	}
+	_, ok = db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
+
+// pgxContext is the context for pgx queries. The repository interfaces
+// predate context plumbing, so queries run to completion.
+func pgxContext() context.Context {
+	return context.Background()
+}
+
+// pgxError maps pgx errors to repository errors.
+func pgxError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
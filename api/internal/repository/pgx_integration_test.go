@@ -0,0 +1,225 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+// setupRepoDB connects both backends to the test database and seeds a user
+// with one thread.
+func setupRepoDB(tb testing.TB) (*testutil.TestDB, *models.User, *models.Thread) {
+	tb.Helper()
+
+	testDB := testutil.NewTestDB(tb)
+	tb.Cleanup(testDB.Cleanup)
+	testDB.OpenPool()
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Bench"}
+	require.NoError(tb, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(tb, testDB.Create(thread).Error)
+
+	return testDB, user, thread
+}
+
+func TestPgxSessionRepository_MatchesGorm(t *testing.T) {
+	testDB, user, _ := setupRepoDB(t)
+	gormRepo := repository.NewSessionRepository()
+	pgxRepo := repository.NewPgxSessionRepository(testDB.Pool)
+
+	session := &models.Session{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		UserAgent: "test-agent",
+		IPAddress: "127.0.0.1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, pgxRepo.Create(testDB.DB.DB, session))
+
+	viaGorm, err := gormRepo.FindByIDWithUser(testDB.DB.DB, session.ID)
+	require.NoError(t, err)
+	viaPgx, err := pgxRepo.FindByIDWithUser(testDB.DB.DB, session.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, viaGorm.UserID, viaPgx.UserID)
+	assert.Equal(t, viaGorm.UserAgent, viaPgx.UserAgent)
+	assert.Equal(t, viaGorm.IPAddress, viaPgx.IPAddress)
+	assert.True(t, viaGorm.ExpiresAt.Equal(viaPgx.ExpiresAt))
+	assert.Equal(t, viaGorm.User.Email, viaPgx.User.Email)
+	assert.Equal(t, viaGorm.User.Name, viaPgx.User.Name)
+
+	require.NoError(t, pgxRepo.DeleteByID(testDB.DB.DB, session.ID))
+	_, err = pgxRepo.FindByIDWithUser(testDB.DB.DB, session.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestPgxSessionRepository_LoadsGuestAndMergedUsers(t *testing.T) {
+	testDB, user, _ := setupRepoDB(t)
+	pgxRepo := repository.NewPgxSessionRepository(testDB.Pool)
+
+	guestExpiresAt := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	mergedInto := uuid.New()
+	require.NoError(t, testDB.Model(user).Updates(map[string]any{
+		"guest_expires_at": guestExpiresAt,
+		"merged_into_id":   mergedInto,
+	}).Error)
+
+	session := &models.Session{ID: uuid.NewString(), UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, pgxRepo.Create(testDB.DB.DB, session))
+
+	viaPgx, err := pgxRepo.FindByIDWithUser(testDB.DB.DB, session.ID)
+	require.NoError(t, err)
+	assert.True(t, viaPgx.User.IsGuest())
+	assert.True(t, viaPgx.User.GuestExpiresAt.Equal(guestExpiresAt))
+	assert.True(t, viaPgx.User.IsMerged())
+	assert.Equal(t, mergedInto, *viaPgx.User.MergedIntoID)
+}
+
+func TestPgxMessageRepository_MatchesGorm(t *testing.T) {
+	testDB, user, thread := setupRepoDB(t)
+	gormRepo := repository.NewMessageRepository()
+	pgxRepo := repository.NewPgxMessageRepository(testDB.Pool)
+
+	audioURL := "user/test"
+	correctedID := uuid.New()
+	message := &models.Message{
+		ThreadID:             thread.ID,
+		Role:                 "user",
+		Content:              "hello",
+		AudioURL:             &audioURL,
+		AudioFormat:          models.AudioFormatWebM,
+		HasAudio:             true,
+		Timestamp:            time.Now(),
+		SuggestedReplies:     models.StringList{"hi", "hey"},
+		PronunciationQuality: "fast",
+		Corrections:          models.CorrectionSpans{{Start: 0, End: 5}},
+		CorrectedMessageID:   &correctedID,
+	}
+	require.NoError(t, pgxRepo.Create(testDB.DB.DB, message))
+	assert.Equal(t, "none", message.PronunciationStatus)
+
+	analysis := models.JSONMap{"score": 0.9}
+	require.NoError(t, pgxRepo.UpdatePronunciationAnalysis(testDB.DB.DB, message.ID, "complete", analysis, "accurate", 0.75, false, time.Now()))
+
+	viaGorm, err := gormRepo.FindByID(testDB.DB.DB, message.ID)
+	require.NoError(t, err)
+	viaPgx, err := pgxRepo.FindByID(testDB.DB.DB, message.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, viaGorm.Content, viaPgx.Content)
+	assert.Equal(t, viaGorm.AudioURL, viaPgx.AudioURL)
+	assert.Equal(t, models.AudioFormatWebM, viaPgx.AudioFormat)
+	assert.Equal(t, viaGorm.AudioFormat, viaPgx.AudioFormat)
+	assert.Equal(t, models.CorrectionSpans{{Start: 0, End: 5}}, viaPgx.Corrections)
+	assert.Equal(t, viaGorm.Corrections, viaPgx.Corrections)
+	assert.Equal(t, viaGorm.CorrectedMessageID, viaPgx.CorrectedMessageID)
+	assert.Equal(t, viaGorm.HasAudio, viaPgx.HasAudio)
+	assert.Equal(t, viaGorm.SuggestedReplies, viaPgx.SuggestedReplies)
+	assert.Equal(t, viaGorm.PronunciationStatus, viaPgx.PronunciationStatus)
+	assert.Equal(t, viaGorm.PronunciationAnalysis, viaPgx.PronunciationAnalysis)
+	assert.Equal(t, viaGorm.PronunciationConfidence, viaPgx.PronunciationConfidence)
+	assert.Equal(t, "accurate", viaPgx.PronunciationQuality)
+	assert.Equal(t, viaGorm.PronunciationQuality, viaPgx.PronunciationQuality)
+	assert.True(t, viaGorm.Timestamp.Equal(viaPgx.Timestamp))
+
+	messages, err := pgxRepo.FindByThreadID(testDB.DB.DB, thread.ID)
+	require.NoError(t, err)
+	assert.Len(t, messages, 1)
+
+	count, err := pgxRepo.CountUserMessagesByUserID(testDB.DB.DB, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestPgxMessageRepository_JoinsGormTransaction(t *testing.T) {
+	testDB, _, thread := setupRepoDB(t)
+	pgxRepo := repository.NewPgxMessageRepository(testDB.Pool)
+
+	message := &models.Message{ThreadID: thread.ID, Role: "user", Content: "rolled back", Timestamp: time.Now()}
+	err := testDB.Transaction(func(tx *gorm.DB) error {
+		if err := pgxRepo.Create(tx, message); err != nil {
+			return err
+		}
+		return fmt.Errorf("roll back")
+	})
+	require.Error(t, err)
+
+	_, err = pgxRepo.FindByID(testDB.DB.DB, message.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func BenchmarkSessionFindByIDWithUser(b *testing.B) {
+	testDB, user, _ := setupRepoDB(b)
+
+	session := &models.Session{ID: uuid.NewString(), UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(b, repository.NewSessionRepository().Create(testDB.DB.DB, session))
+
+	backends := map[string]repository.SessionRepository{
+		"gorm": repository.NewSessionRepository(),
+		"pgx":  repository.NewPgxSessionRepository(testDB.Pool),
+	}
+	for name, repo := range backends {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := repo.FindByIDWithUser(testDB.DB.DB, session.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMessageCreate(b *testing.B) {
+	testDB, _, thread := setupRepoDB(b)
+
+	backends := map[string]repository.MessageRepository{
+		"gorm": repository.NewMessageRepository(),
+		"pgx":  repository.NewPgxMessageRepository(testDB.Pool),
+	}
+	for name, repo := range backends {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				message := &models.Message{ThreadID: thread.ID, Role: "user", Content: "hello", Timestamp: time.Now()}
+				if err := repo.Create(testDB.DB.DB, message); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMessageFindByThreadID(b *testing.B) {
+	testDB, _, thread := setupRepoDB(b)
+
+	seed := repository.NewMessageRepository()
+	for i := range 20 {
+		message := &models.Message{ThreadID: thread.ID, Role: "user", Content: fmt.Sprintf("message %d", i), Timestamp: time.Now()}
+		require.NoError(b, seed.Create(testDB.DB.DB, message))
+	}
+
+	backends := map[string]repository.MessageRepository{
+		"gorm": repository.NewMessageRepository(),
+		"pgx":  repository.NewPgxMessageRepository(testDB.Pool),
+	}
+	for name, repo := range backends {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := repo.FindByThreadID(testDB.DB.DB, thread.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
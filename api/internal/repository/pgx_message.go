@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/db/sqlcgen"
+	"ling-app/api/internal/models"
+)
+
+// pgxMessageRepository implements MessageRepository using sqlc/pgx.
+type pgxMessageRepository struct {
+	queries *sqlcgen.Queries
+	gorm    MessageRepository
+}
+
+// NewPgxMessageRepository creates a message repository backed by pgx.
+// Calls made inside a GORM transaction fall back to the GORM repository.
+func NewPgxMessageRepository(conn sqlcgen.DBTX) MessageRepository {
+	return &pgxMessageRepository{
+		queries: sqlcgen.New(conn),
+		gorm:    NewMessageRepository(),
+	}
+}
+
+func (r *pgxMessageRepository) Create(exec Executor, message *models.Message) error {
+	if inGormTx(exec) {
+		return r.gorm.Create(exec, message)
+	}
+
+	// Mirror the GORM hook and the column default GORM leaves to the database
+	if message.ID == uuid.Nil {
+		message.ID = uuid.New()
+	}
+	if message.PronunciationStatus == "" {
+		message.PronunciationStatus = "none"
+	}
+
+	return r.queries.CreateMessage(pgxContext(), sqlcgen.CreateMessageParams{
+		ID:                         message.ID,
+		ThreadID:                   message.ThreadID,
+		Role:                       message.Role,
+		Content:                    message.Content,
+		AudioUrl:                   message.AudioURL,
+		AudioDurationSeconds:       message.AudioDurationSeconds,
+		HasAudio:                   &message.HasAudio,
+		Timestamp:                  &message.Timestamp,
+		SuggestedReplies:           message.SuggestedReplies,
+		ExpectedText:               message.ExpectedText,
+		PronunciationStatus:        &message.PronunciationStatus,
+		PronunciationAnalysis:      message.PronunciationAnalysis,
+		PronunciationError:         message.PronunciationError,
+		PronunciationUpdatedAt:     message.PronunciationUpdatedAt,
+		PronunciationConfidence:    message.PronunciationConfidence,
+		PronunciationLowConfidence: &message.PronunciationLowConfidence,
+		SpokenText:                 message.SpokenText,
+		Adaptation:                 message.Adaptation,
+		Kind:                       &message.Kind,
+		RawTranscript:              message.RawTranscript,
+		Tone:                       &message.Tone,
+		AudioFormat:                &message.AudioFormat,
+		PronunciationQuality:       &message.PronunciationQuality,
+		Corrections:                message.Corrections,
+		CorrectedMessageID:         message.CorrectedMessageID,
+	})
+}
+
+func (r *pgxMessageRepository) FindByID(exec Executor, id uuid.UUID) (*models.Message, error) {
+	if inGormTx(exec) {
+		return r.gorm.FindByID(exec, id)
+	}
+
+	row, err := r.queries.GetMessage(pgxContext(), id)
+	if err != nil {
+		return nil, pgxError(err)
+	}
+	message := messageFromRow(row)
+	return &message, nil
+}
+
+func (r *pgxMessageRepository) FindByThreadID(exec Executor, threadID uuid.UUID) ([]models.Message, error) {
+	if inGormTx(exec) {
+		return r.gorm.FindByThreadID(exec, threadID)
+	}
+
+	rows, err := r.queries.ListMessagesByThread(pgxContext(), threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]models.Message, len(rows))
+	for i, row := range rows {
+		messages[i] = messageFromRow(row)
+	}
+	return messages, nil
+}
+
+func (r *pgxMessageRepository) CountUserMessagesByUserID(exec Executor, userID uuid.UUID) (int64, error) {
+	if inGormTx(exec) {
+		return r.gorm.CountUserMessagesByUserID(exec, userID)
+	}
+	return r.queries.CountUserMessagesByUser(pgxContext(), userID)
+}
+
+func (r *pgxMessageRepository) UpdatePronunciationStatus(exec Executor, id uuid.UUID, status string) error {
+	if inGormTx(exec) {
+		return r.gorm.UpdatePronunciationStatus(exec, id, status)
+	}
+	return r.queries.UpdatePronunciationStatus(pgxContext(), sqlcgen.UpdatePronunciationStatusParams{
+		ID:                  id,
+		PronunciationStatus: &status,
+	})
+}
+
+func (r *pgxMessageRepository) UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	if inGormTx(exec) {
+		return r.gorm.UpdatePronunciationAnalysis(exec, id, status, analysis, quality, confidence, lowConfidence, updatedAt)
+	}
+	return r.queries.UpdatePronunciationAnalysis(pgxContext(), sqlcgen.UpdatePronunciationAnalysisParams{
+		ID:                         id,
+		PronunciationStatus:        &status,
+		PronunciationAnalysis:      analysis,
+		PronunciationConfidence:    &confidence,
+		PronunciationLowConfidence: &lowConfidence,
+		PronunciationUpdatedAt:     &updatedAt,
+		PronunciationQuality:       &quality,
+	})
+}
+
+func (r *pgxMessageRepository) UpdatePronunciationError(exec Executor, id uuid.UUID, status string, errMsg string, updatedAt time.Time) error {
+	if inGormTx(exec) {
+		return r.gorm.UpdatePronunciationError(exec, id, status, errMsg, updatedAt)
+	}
+	return r.queries.UpdatePronunciationError(pgxContext(), sqlcgen.UpdatePronunciationErrorParams{
+		ID:                     id,
+		PronunciationStatus:    &status,
+		PronunciationError:     &errMsg,
+		PronunciationUpdatedAt: &updatedAt,
+	})
+}
+
+// The retention purge is a background sweep, not a hot path, so it stays on GORM.
+func (r *pgxMessageRepository) FindExpiredUserAudio(exec Executor, now time.Time, limit int) ([]models.Message, error) {
+	return r.gorm.FindExpiredUserAudio(exec, now, limit)
+}
+
+// Practice exports run in background jobs, so this stays on GORM too.
+func (r *pgxMessageRepository) FindAnalyzedByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.Message, error) {
+	return r.gorm.FindAnalyzedByUserID(exec, userID, limit)
+}
+
+// Badge stats are served behind HTTP caching, so they stay on GORM as well.
+func (r *pgxMessageRepository) FindActiveDaysByUserID(exec Executor, userID uuid.UUID, since time.Time, loc *time.Location) ([]time.Time, error) {
+	return r.gorm.FindActiveDaysByUserID(exec, userID, since, loc)
+}
+
+func (r *pgxMessageRepository) ClearAudio(exec Executor, id uuid.UUID) error {
+	return r.gorm.ClearAudio(exec, id)
+}
+
+func (r *pgxMessageRepository) UpdateContent(exec Executor, id uuid.UUID, content string, correctedAt time.Time) error {
+	return r.gorm.UpdateContent(exec, id, content, correctedAt)
+}
+
+func (r *pgxMessageRepository) ResetPronunciation(exec Executor, id uuid.UUID, status string, updatedAt time.Time) error {
+	return r.gorm.ResetPronunciation(exec, id, status, updatedAt)
+}
+
+func (r *pgxMessageRepository) RetryPronunciation(exec Executor, id uuid.UUID, maxRetries int, updatedAt time.Time) (bool, error) {
+	return r.gorm.RetryPronunciation(exec, id, maxRetries, updatedAt)
+}
+
+// Session summaries are computed once per session, so this stays on GORM.
+func (r *pgxMessageRepository) FindUserMessagesByUserID(exec Executor, userID uuid.UUID, before time.Time, limit int) ([]models.Message, error) {
+	return r.gorm.FindUserMessagesByUserID(exec, userID, before, limit)
+}
+
+// The job monitor reads these on admin requests only, so they stay on GORM.
+func (r *pgxMessageRepository) CountPronunciationStatuses(exec Executor, since time.Time) (map[string]int64, error) {
+	return r.gorm.CountPronunciationStatuses(exec, since)
+}
+
+func (r *pgxMessageRepository) FindPendingPronunciation(exec Executor, limit int) ([]models.Message, error) {
+	return r.gorm.FindPendingPronunciation(exec, limit)
+}
+
+// Recovery of pending analyses runs once at startup
+func (r *pgxMessageRepository) FindPendingPronunciationBefore(exec Executor, before time.Time, limit int) ([]models.Message, error) {
+	return r.gorm.FindPendingPronunciationBefore(exec, before, limit)
+}
+
+func (r *pgxMessageRepository) FindFailedPronunciation(exec Executor, since time.Time, limit int) ([]models.Message, error) {
+	return r.gorm.FindFailedPronunciation(exec, since, limit)
+}
+
+func (r *pgxMessageRepository) FindOwnedAudioKeys(exec Executor, userID uuid.UUID, keys []string) ([]string, error) {
+	return r.gorm.FindOwnedAudioKeys(exec, userID, keys)
+}
+
+func messageFromRow(row sqlcgen.Message) models.Message {
+	return models.Message{
+		ID:                         row.ID,
+		ThreadID:                   row.ThreadID,
+		Role:                       row.Role,
+		Content:                    row.Content,
+		AudioURL:                   row.AudioUrl,
+		AudioFormat:                deref(row.AudioFormat),
+		AudioDurationSeconds:       row.AudioDurationSeconds,
+		HasAudio:                   deref(row.HasAudio),
+		Timestamp:                  deref(row.Timestamp),
+		SuggestedReplies:           row.SuggestedReplies,
+		ExpectedText:               row.ExpectedText,
+		PronunciationStatus:        deref(row.PronunciationStatus),
+		PronunciationAnalysis:      row.PronunciationAnalysis,
+		PronunciationError:         row.PronunciationError,
+		PronunciationUpdatedAt:     row.PronunciationUpdatedAt,
+		PronunciationConfidence:    row.PronunciationConfidence,
+		PronunciationLowConfidence: deref(row.PronunciationLowConfidence),
+		SpokenText:                 row.SpokenText,
+		Adaptation:                 row.Adaptation,
+		Kind:                       deref(row.Kind),
+		RawTranscript:              row.RawTranscript,
+		TranscriptCorrectedAt:      row.TranscriptCorrectedAt,
+		Tone:                       deref(row.Tone),
+		PronunciationQuality:       deref(row.PronunciationQuality),
+		PronunciationRetries:       int(deref(row.PronunciationRetries)),
+		Corrections:                row.Corrections,
+		CorrectedMessageID:         row.CorrectedMessageID,
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/db/sqlcgen"
+	"ling-app/api/internal/models"
+)
+
+// pgxSessionRepository implements SessionRepository using sqlc/pgx.
+type pgxSessionRepository struct {
+	queries *sqlcgen.Queries
+	gorm    SessionRepository
+}
+
+// NewPgxSessionRepository creates a session repository backed by pgx.
+// Calls made inside a GORM transaction fall back to the GORM repository.
+func NewPgxSessionRepository(conn sqlcgen.DBTX) SessionRepository {
+	return &pgxSessionRepository{
+		queries: sqlcgen.New(conn),
+		gorm:    NewSessionRepository(),
+	}
+}
+
+func (r *pgxSessionRepository) Create(exec Executor, session *models.Session) error {
+	if inGormTx(exec) {
+		return r.gorm.Create(exec, session)
+	}
+
+	// GORM fills CreatedAt on insert; keep the same behaviour
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+
+	return r.queries.CreateSession(pgxContext(), sqlcgen.CreateSessionParams{
+		ID:        session.ID,
+		UserID:    session.UserID,
+		UserAgent: &session.UserAgent,
+		IpAddress: &session.IPAddress,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: &session.CreatedAt,
+	})
+}
+
+func (r *pgxSessionRepository) FindByIDWithUser(exec Executor, token string) (*models.Session, error) {
+	if inGormTx(exec) {
+		return r.gorm.FindByIDWithUser(exec, token)
+	}
+
+	row, err := r.queries.GetSessionWithUser(pgxContext(), token)
+	if err != nil {
+		return nil, pgxError(err)
+	}
+
+	return &models.Session{
+		ID:        row.Session.ID,
+		UserID:    row.Session.UserID,
+		User:      userFromRow(row.User),
+		UserAgent: deref(row.Session.UserAgent),
+		IPAddress: deref(row.Session.IpAddress),
+		ExpiresAt: row.Session.ExpiresAt,
+		CreatedAt: deref(row.Session.CreatedAt),
+	}, nil
+}
+
+// Listing devices is a settings page, not the per-request lookup, so it stays on GORM.
+func (r *pgxSessionRepository) FindActiveByUserID(exec Executor, userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	return r.gorm.FindActiveByUserID(exec, userID, now)
+}
+
+// A session is extended at most once per refresh window, so this stays on GORM.
+func (r *pgxSessionRepository) UpdateExpiresAt(exec Executor, token string, expiresAt time.Time) error {
+	return r.gorm.UpdateExpiresAt(exec, token, expiresAt)
+}
+
+func (r *pgxSessionRepository) DeleteByID(exec Executor, token string) error {
+	if inGormTx(exec) {
+		return r.gorm.DeleteByID(exec, token)
+	}
+	return r.queries.DeleteSession(pgxContext(), token)
+}
+
+func (r *pgxSessionRepository) DeleteByUserID(exec Executor, userID uuid.UUID) error {
+	if inGormTx(exec) {
+		return r.gorm.DeleteByUserID(exec, userID)
+	}
+	return r.queries.DeleteSessionsByUser(pgxContext(), userID)
+}
+
+func (r *pgxSessionRepository) DeleteExpiredBefore(exec Executor, t time.Time) (int64, error) {
+	if inGormTx(exec) {
+		return r.gorm.DeleteExpiredBefore(exec, t)
+	}
+	return r.queries.DeleteSessionsExpiredBefore(pgxContext(), t)
+}
+
+func userFromRow(row sqlcgen.User) models.User {
+	return models.User{
+		ID:             row.ID,
+		Email:          row.Email,
+		PasswordHash:   row.PasswordHash,
+		Name:           deref(row.Name),
+		AvatarURL:      row.AvatarUrl,
+		GoogleID:       row.GoogleID,
+		GitHubID:       row.GitHubID,
+		EmailVerified:  deref(row.EmailVerified),
+		Role:           models.UserRole(row.Role),
+		GuestExpiresAt: row.GuestExpiresAt,
+		MergedIntoID:   row.MergedIntoID,
+		MergedAt:       row.MergedAt,
+		CreatedAt:      deref(row.CreatedAt),
+		UpdatedAt:      deref(row.UpdatedAt),
+	}
+}
+
+// deref returns the value p points to, or the zero value for a NULL column.
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
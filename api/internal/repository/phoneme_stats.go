@@ -17,7 +17,7 @@ func NewPhonemeStatsRepository() PhonemeStatsRepository {
 
 func (r *phonemeStatsRepository) Upsert(exec Executor, stats *models.PhonemeStats) error {
 	return exec.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "user_id"}, {Name: "phoneme"}},
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "language"}, {Name: "phoneme"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{
 			"total_attempts": clause.Expr{SQL: "phoneme_stats.total_attempts + ?", Vars: []interface{}{stats.TotalAttempts}},
 			"correct_count":  clause.Expr{SQL: "phoneme_stats.correct_count + ?", Vars: []interface{}{stats.CorrectCount}},
@@ -27,20 +27,33 @@ func (r *phonemeStatsRepository) Upsert(exec Executor, stats *models.PhonemeStat
 	}).Create(stats).Error
 }
 
-func (r *phonemeStatsRepository) FindByUserID(exec Executor, userID uuid.UUID) ([]models.PhonemeStats, error) {
+func (r *phonemeStatsRepository) FindByUserID(exec Executor, userID uuid.UUID, language string) ([]models.PhonemeStats, error) {
 	var stats []models.PhonemeStats
-	err := exec.Where("user_id = ?", userID).Find(&stats).Error
+	err := exec.Where("user_id = ? AND language = ?", userID, language).Find(&stats).Error
 	if err != nil {
 		return nil, err
 	}
 	return stats, nil
 }
 
-func (r *phonemeStatsRepository) GetAccuracyRanking(exec Executor, userID uuid.UUID) ([]PhonemeAccuracy, error) {
+func (r *phonemeStatsRepository) FindLanguages(exec Executor, userID uuid.UUID) ([]string, error) {
+	var languages []string
+	err := exec.Model(&models.PhonemeStats{}).
+		Where("user_id = ?", userID).
+		Distinct("language").
+		Order("language").
+		Pluck("language", &languages).Error
+	if err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
+
+func (r *phonemeStatsRepository) GetAccuracyRanking(exec Executor, userID uuid.UUID, language string) ([]PhonemeAccuracy, error) {
 	var phonemeStats []PhonemeAccuracy
 	err := exec.Model(&models.PhonemeStats{}).
 		Select("phoneme, total_attempts, correct_count, deletion_count, (CAST(correct_count AS FLOAT) / CAST(total_attempts AS FLOAT) * 100) as accuracy").
-		Where("user_id = ?", userID).
+		Where("user_id = ? AND language = ?", userID, language).
 		Order("accuracy ASC").
 		Scan(&phonemeStats).Error
 	if err != nil {
@@ -59,7 +72,7 @@ func NewPhonemeSubstitutionRepository() PhonemeSubstitutionRepository {
 
 func (r *phonemeSubstitutionRepository) Upsert(exec Executor, sub *models.PhonemeSubstitution) error {
 	return exec.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "user_id"}, {Name: "expected_phoneme"}, {Name: "actual_phoneme"}},
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "language"}, {Name: "expected_phoneme"}, {Name: "actual_phoneme"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{
 			"occurrence_count": clause.Expr{SQL: "phoneme_substitutions.occurrence_count + ?", Vars: []interface{}{sub.OccurrenceCount}},
 			"updated_at":       clause.Expr{SQL: "NOW()"},
@@ -67,9 +80,9 @@ func (r *phonemeSubstitutionRepository) Upsert(exec Executor, sub *models.Phonem
 	}).Create(sub).Error
 }
 
-func (r *phonemeSubstitutionRepository) FindTopByUserID(exec Executor, userID uuid.UUID, limit int) ([]models.PhonemeSubstitution, error) {
+func (r *phonemeSubstitutionRepository) FindTopByUserID(exec Executor, userID uuid.UUID, language string, limit int) ([]models.PhonemeSubstitution, error) {
 	var substitutions []models.PhonemeSubstitution
-	err := exec.Where("user_id = ?", userID).
+	err := exec.Where("user_id = ? AND language = ?", userID, language).
 		Order("occurrence_count DESC").
 		Limit(limit).
 		Find(&substitutions).Error
@@ -78,3 +91,32 @@ func (r *phonemeSubstitutionRepository) FindTopByUserID(exec Executor, userID uu
 	}
 	return substitutions, nil
 }
+
+// phonemeStatsSnapshotRepository implements PhonemeStatsSnapshotRepository using GORM.
+type phonemeStatsSnapshotRepository struct{}
+
+// NewPhonemeStatsSnapshotRepository creates a new GORM-backed phoneme stats snapshot repository.
+func NewPhonemeStatsSnapshotRepository() PhonemeStatsSnapshotRepository {
+	return &phonemeStatsSnapshotRepository{}
+}
+
+func (r *phonemeStatsSnapshotRepository) Save(exec Executor, snapshot *models.PhonemeStatsSnapshot) error {
+	return exec.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "tally", "created_at"}),
+	}).Create(snapshot).Error
+}
+
+func (r *phonemeStatsSnapshotRepository) Take(exec Executor, messageID uuid.UUID) (*models.PhonemeStatsSnapshot, error) {
+	var snapshots []models.PhonemeStatsSnapshot
+	result := exec.Clauses(clause.Returning{}).
+		Where("message_id = ?", messageID).
+		Delete(&snapshots)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 || len(snapshots) == 0 {
+		return nil, ErrNotFound
+	}
+	return &snapshots[0], nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+)
+
+// practiceSessionRepository implements PracticeSessionRepository using GORM.
+type practiceSessionRepository struct{}
+
+// NewPracticeSessionRepository creates a new GORM-backed practice session repository.
+func NewPracticeSessionRepository() PracticeSessionRepository {
+	return &practiceSessionRepository{}
+}
+
+func (r *practiceSessionRepository) Create(exec Executor, session *models.PracticeSession) error {
+	return exec.Create(session).Error
+}
+
+func (r *practiceSessionRepository) FindByIDAndUserID(exec Executor, id, userID uuid.UUID) (*models.PracticeSession, error) {
+	var session models.PracticeSession
+	err := exec.Where("id = ? AND user_id = ?", id, userID).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *practiceSessionRepository) FindOpenByThreadID(exec Executor, threadID uuid.UUID) (*models.PracticeSession, error) {
+	var session models.PracticeSession
+	err := exec.Where("thread_id = ? AND ended_at IS NULL", threadID).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *practiceSessionRepository) FindByUserID(exec Executor, userID uuid.UUID, limit, offset int) ([]models.PracticeSession, error) {
+	var sessions []models.PracticeSession
+	err := exec.Where("user_id = ?", userID).Order("started_at DESC").Offset(offset).Limit(limit).Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *practiceSessionRepository) End(exec Executor, id uuid.UUID, endedAt time.Time, summary *models.PracticeSessionSummary) (bool, error) {
+	result := exec.Model(&models.PracticeSession{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"ended_at": endedAt,
+			"summary":  summary,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
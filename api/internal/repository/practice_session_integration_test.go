@@ -0,0 +1,117 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestPracticeSessionRepository_Lifecycle(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewPracticeSessionRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Sessions"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+
+	start := time.Now().Truncate(time.Microsecond)
+	session := &models.PracticeSession{
+		UserID:         user.ID,
+		ThreadID:       thread.ID,
+		PlannedMinutes: 10,
+		StartedAt:      start,
+		TimerEndsAt:    start.Add(10 * time.Minute),
+	}
+	require.NoError(t, repo.Create(exec, session))
+
+	second := &models.PracticeSession{UserID: user.ID, ThreadID: thread.ID, PlannedMinutes: 5, StartedAt: start, TimerEndsAt: start.Add(5 * time.Minute)}
+	assert.Error(t, repo.Create(exec, second), "a thread has one open session at a time")
+
+	open, err := repo.FindOpenByThreadID(exec, thread.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, open.ID)
+	assert.Nil(t, open.Summary)
+
+	_, err = repo.FindByIDAndUserID(exec, session.ID, uuid.New())
+	assert.ErrorIs(t, err, repository.ErrNotFound, "sessions are only found by their owner")
+
+	accuracy := 80.0
+	summary := &models.PracticeSessionSummary{DurationSeconds: 240, Turns: 3, Accuracy: &accuracy, ScoredTurns: 2, NewVocabulary: models.StringList{"leche"}}
+	ended, err := repo.End(exec, session.ID, start.Add(4*time.Minute), summary)
+	require.NoError(t, err)
+	assert.True(t, ended)
+	ended, err = repo.End(exec, session.ID, start.Add(5*time.Minute), &models.PracticeSessionSummary{})
+	require.NoError(t, err)
+	assert.False(t, ended, "an ended session keeps its first summary")
+
+	_, err = repo.FindOpenByThreadID(exec, thread.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	found, err := repo.FindByIDAndUserID(exec, session.ID, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.EndedAt)
+	assert.True(t, start.Add(4*time.Minute).Equal(*found.EndedAt))
+	assert.Equal(t, summary, found.Summary)
+
+	next := &models.PracticeSession{UserID: user.ID, ThreadID: thread.ID, PlannedMinutes: 5, StartedAt: start.Add(time.Hour), TimerEndsAt: start.Add(time.Hour + 5*time.Minute)}
+	require.NoError(t, repo.Create(exec, next))
+
+	sessions, err := repo.FindByUserID(exec, user.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, next.ID, sessions[0].ID, "newest first")
+
+	sessions, err = repo.FindByUserID(exec, user.ID, 10, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, session.ID, sessions[0].ID, "offset skips the newest")
+
+	require.NoError(t, testDB.Delete(thread).Error)
+	sessions, err = repo.FindByUserID(exec, user.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "sessions go with their thread")
+}
+
+func TestMessageRepository_FindUserMessagesByUserID(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewMessageRepository()
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Vocabulary"}
+	require.NoError(t, testDB.Create(user).Error)
+	other := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Other"}
+	require.NoError(t, testDB.Create(other).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	otherThread := &models.Thread{UserID: other.ID}
+	require.NoError(t, testDB.Create(otherThread).Error)
+
+	now := time.Now()
+	messages := []models.Message{
+		{ThreadID: thread.ID, Role: "user", Content: "older", Timestamp: now.Add(-2 * time.Hour)},
+		{ThreadID: thread.ID, Role: "user", Content: "newer", Timestamp: now.Add(-time.Hour)},
+		{ThreadID: thread.ID, Role: "assistant", Content: "reply", Timestamp: now.Add(-time.Hour)},
+		{ThreadID: thread.ID, Role: "user", Content: "too late", Timestamp: now.Add(time.Hour)},
+		{ThreadID: otherThread.ID, Role: "user", Content: "someone else", Timestamp: now.Add(-time.Hour)},
+	}
+	require.NoError(t, testDB.Create(&messages).Error)
+
+	found, err := repo.FindUserMessagesByUserID(testDB.DB.DB, user.ID, now, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "newer", found[0].Content)
+	assert.Equal(t, "older", found[1].Content)
+}
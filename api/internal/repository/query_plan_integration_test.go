@@ -0,0 +1,125 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+// capturedQuery is the SQL GORM built for a repository call
+type capturedQuery struct {
+	sql  string
+	vars []interface{}
+}
+
+// captureQuery runs call against a dry-run session and returns the SQL it
+// would have executed, so the test EXPLAINs exactly what the repository sends.
+func captureQuery(t *testing.T, testDB *testutil.TestDB, call func(exec repository.Executor)) capturedQuery {
+	t.Helper()
+
+	var captured capturedQuery
+	capture := func(db *gorm.DB) {
+		captured = capturedQuery{sql: db.Statement.SQL.String(), vars: db.Statement.Vars}
+	}
+
+	callbacks := testDB.Callback()
+	require.NoError(t, callbacks.Query().After("gorm:query").Register("test:capture_query", capture))
+	require.NoError(t, callbacks.Delete().After("gorm:delete").Register("test:capture_delete", capture))
+	defer func() {
+		_ = callbacks.Query().Remove("test:capture_query")
+		_ = callbacks.Delete().Remove("test:capture_delete")
+	}()
+
+	call(testDB.Session(&gorm.Session{DryRun: true}))
+	require.NotEmpty(t, captured.sql, "repository call issued no query")
+	return captured
+}
+
+// explain returns the query plan with sequential scans disabled, so the plan
+// shows whether a usable index exists even on near-empty test tables.
+func explain(t *testing.T, testDB *testutil.TestDB, query capturedQuery) string {
+	t.Helper()
+
+	var lines []string
+	err := testDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL enable_seqscan = off").Error; err != nil {
+			return err
+		}
+		return tx.Raw("EXPLAIN "+query.sql, query.vars...).Scan(&lines).Error
+	})
+	require.NoError(t, err)
+	return strings.Join(lines, "\n")
+}
+
+func TestQueryPlans_UseIndexes(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name  string
+		index string
+		call  func(exec repository.Executor)
+	}{
+		{
+			name:  "thread messages",
+			index: "idx_messages_thread_timestamp",
+			call: func(exec repository.Executor) {
+				_, _ = repository.NewMessageRepository().FindByThreadID(exec, uuid.New())
+			},
+		},
+		{
+			name:  "active threads",
+			index: "idx_threads_user_archived_created",
+			call: func(exec repository.Executor) {
+				_, _ = repository.NewThreadRepository().FindByUserID(exec, userID)
+			},
+		},
+		{
+			name:  "archived threads",
+			index: "idx_threads_user_archived_created",
+			call: func(exec repository.Executor) {
+				_, _ = repository.NewThreadRepository().FindArchivedByUserID(exec, userID)
+			},
+		},
+		{
+			name:  "expired sessions",
+			index: "idx_sessions_expires_at",
+			call: func(exec repository.Executor) {
+				_, _ = repository.NewSessionRepository().DeleteExpiredBefore(exec, time.Now())
+			},
+		},
+		{
+			name:  "credit history",
+			index: "idx_credit_transactions_user_created",
+			call: func(exec repository.Executor) {
+				_, _ = repository.NewCreditTransactionRepository().FindByUserID(exec, userID, 50, 0)
+			},
+		},
+		{
+			name:  "phoneme stats",
+			index: "idx_phoneme_stats_user_language_phoneme",
+			call: func(exec repository.Executor) {
+				_, _ = repository.NewPhonemeStatsRepository().FindByUserID(exec, userID, "en")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := explain(t, testDB, captureQuery(t, testDB, tt.call))
+			assert.Contains(t, plan, tt.index, "query no longer uses %s:\n%s", tt.index, plan)
+		})
+	}
+}
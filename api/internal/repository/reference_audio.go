@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// referenceAudioRepository implements ReferenceAudioRepository using GORM.
+type referenceAudioRepository struct{}
+
+// NewReferenceAudioRepository creates a new GORM-backed reference audio repository.
+func NewReferenceAudioRepository() ReferenceAudioRepository {
+	return &referenceAudioRepository{}
+}
+
+func (r *referenceAudioRepository) Find(exec Executor, text, language, voice string, speed float64) (*models.ReferenceAudio, error) {
+	var clip models.ReferenceAudio
+	err := exec.Where("text = ? AND language = ? AND voice = ? AND speed = ?", text, language, voice, speed).First(&clip).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &clip, nil
+}
+
+// Create ignores a clip recorded concurrently: both point at the same object
+func (r *referenceAudioRepository) Create(exec Executor, clip *models.ReferenceAudio) error {
+	return exec.Clauses(clause.OnConflict{DoNothing: true}).Create(clip).Error
+}
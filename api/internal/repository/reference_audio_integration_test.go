@@ -0,0 +1,48 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestReferenceAudioRepository_FindAndCreate(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewReferenceAudioRepository()
+	exec := testDB.DB.DB
+
+	_, err := repo.Find(exec, "think", "en-us", "openai:alloy", 1.0)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	clip := &models.ReferenceAudio{
+		Text:      "think",
+		Language:  "en-us",
+		Voice:     "openai:alloy",
+		Speed:     1.0,
+		AudioKey:  models.ReferenceAudioKey("think", "en-us", "openai:alloy", 1.0),
+		CreatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, repo.Create(exec, clip))
+
+	// A clip recorded concurrently by another request is ignored
+	duplicate := *clip
+	duplicate.ID = uuid.Nil
+	require.NoError(t, repo.Create(exec, &duplicate))
+
+	found, err := repo.Find(exec, "think", "en-us", "openai:alloy", 1.0)
+	require.NoError(t, err)
+	assert.Equal(t, clip.AudioKey, found.AudioKey)
+
+	_, err = repo.Find(exec, "think", "en-us", "openai:alloy", 0.75)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "each speed is its own clip")
+}
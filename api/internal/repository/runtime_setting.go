@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// runtimeSettingRepository implements RuntimeSettingRepository using GORM.
+type runtimeSettingRepository struct{}
+
+// NewRuntimeSettingRepository creates a new GORM-backed runtime setting repository.
+func NewRuntimeSettingRepository() RuntimeSettingRepository {
+	return &runtimeSettingRepository{}
+}
+
+func (r *runtimeSettingRepository) FindAll(exec Executor) ([]models.RuntimeSetting, error) {
+	var settings []models.RuntimeSetting
+	if err := exec.Order("key").Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *runtimeSettingRepository) Upsert(exec Executor, setting *models.RuntimeSetting) error {
+	return exec.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+	}).Create(setting).Error
+}
+
+func (r *runtimeSettingRepository) Delete(exec Executor, key string) error {
+	result := exec.Where("key = ?", key).Delete(&models.RuntimeSetting{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
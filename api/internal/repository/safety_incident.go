@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"ling-app/api/internal/models"
+)
+
+// safetyIncidentRepository implements SafetyIncidentRepository using GORM.
+type safetyIncidentRepository struct{}
+
+// NewSafetyIncidentRepository creates a new GORM-backed safety incident repository.
+func NewSafetyIncidentRepository() SafetyIncidentRepository {
+	return &safetyIncidentRepository{}
+}
+
+func (r *safetyIncidentRepository) Create(exec Executor, incident *models.SafetyIncident) error {
+	return exec.Create(incident).Error
+}
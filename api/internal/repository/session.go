@@ -34,6 +34,18 @@ func (r *sessionRepository) FindByIDWithUser(exec Executor, token string) (*mode
 	return &session, nil
 }
 
+func (r *sessionRepository) FindActiveByUserID(exec Executor, userID uuid.UUID, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := exec.Where("user_id = ? AND expires_at > ?", userID, now).
+		Order("created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *sessionRepository) UpdateExpiresAt(exec Executor, token string, expiresAt time.Time) error {
+	return exec.Model(&models.Session{}).Where("id = ?", token).Update("expires_at", expiresAt).Error
+}
+
 func (r *sessionRepository) DeleteByID(exec Executor, token string) error {
 	return exec.Where("id = ?", token).Delete(&models.Session{}).Error
 }
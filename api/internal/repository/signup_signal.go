@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+)
+
+// signupSignalRepository implements SignupSignalRepository using GORM.
+type signupSignalRepository struct{}
+
+// NewSignupSignalRepository creates a new GORM-backed signup signal repository.
+func NewSignupSignalRepository() SignupSignalRepository {
+	return &signupSignalRepository{}
+}
+
+func (r *signupSignalRepository) Create(exec Executor, signal *models.SignupSignal) error {
+	return exec.Create(signal).Error
+}
+
+func (r *signupSignalRepository) Save(exec Executor, signal *models.SignupSignal) error {
+	return exec.Save(signal).Error
+}
+
+func (r *signupSignalRepository) FindByUserID(exec Executor, userID uuid.UUID) (*models.SignupSignal, error) {
+	var signal models.SignupSignal
+	err := exec.Where("user_id = ?", userID).First(&signal).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &signal, nil
+}
+
+func (r *signupSignalRepository) FindMatching(exec Executor, ipAddress, fingerprintHash string, since time.Time) ([]models.SignupSignal, error) {
+	var signals []models.SignupSignal
+	query := exec.Where("ip_address = ? AND created_at >= ?", ipAddress, since)
+	if fingerprintHash != "" {
+		query = query.Or("fingerprint_hash = ?", fingerprintHash)
+	}
+	if err := query.Order("created_at DESC").Limit(100).Find(&signals).Error; err != nil {
+		return nil, err
+	}
+	return signals, nil
+}
+
+func (r *signupSignalRepository) FindPendingReview(exec Executor, limit int) ([]models.SignupSignal, error) {
+	var signals []models.SignupSignal
+	err := exec.Where("verdict <> ? AND reviewed_at IS NULL", models.SignupClear).
+		Order("created_at").
+		Limit(limit).
+		Find(&signals).Error
+	if err != nil {
+		return nil, err
+	}
+	return signals, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// statsBadgeRepository implements StatsBadgeRepository using GORM.
+type statsBadgeRepository struct{}
+
+// NewStatsBadgeRepository creates a new GORM-backed stats badge repository.
+func NewStatsBadgeRepository() StatsBadgeRepository {
+	return &statsBadgeRepository{}
+}
+
+func (r *statsBadgeRepository) FindByUserID(exec Executor, userID uuid.UUID) (*models.StatsBadge, error) {
+	var badge models.StatsBadge
+	err := exec.Where("user_id = ?", userID).First(&badge).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (r *statsBadgeRepository) FindByToken(exec Executor, token string) (*models.StatsBadge, error) {
+	var badge models.StatsBadge
+	err := exec.Where("token = ?", token).First(&badge).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+// Upsert replaces the user's badge, so the previous token stops working
+func (r *statsBadgeRepository) Upsert(exec Executor, badge *models.StatsBadge) error {
+	return exec.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token", "created_at"}),
+	}).Create(badge).Error
+}
+
+func (r *statsBadgeRepository) DeleteByUserID(exec Executor, userID uuid.UUID) error {
+	return exec.Where("user_id = ?", userID).Delete(&models.StatsBadge{}).Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+)
+
+// storageDeletionRepository implements StorageDeletionRepository using GORM.
+type storageDeletionRepository struct{}
+
+// NewStorageDeletionRepository creates a new GORM-backed pending storage deletion repository.
+func NewStorageDeletionRepository() StorageDeletionRepository {
+	return &storageDeletionRepository{}
+}
+
+// Create inserts in batches, keeping an account with many recordings under
+// the database's limit on query parameters
+func (r *storageDeletionRepository) Create(exec Executor, deletions []models.PendingStorageDeletion) error {
+	for start := 0; start < len(deletions); start += 500 {
+		batch := deletions[start:min(start+500, len(deletions))]
+		if err := exec.Create(&batch).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *storageDeletionRepository) FindPending(exec Executor, createdBefore time.Time, limit int) ([]models.PendingStorageDeletion, error) {
+	var deletions []models.PendingStorageDeletion
+	err := exec.Where("created_at < ?", createdBefore).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&deletions).Error
+	if err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+func (r *storageDeletionRepository) Delete(exec Executor, id uuid.UUID) error {
+	return exec.Delete(&models.PendingStorageDeletion{}, "id = ?", id).Error
+}
+
+func (r *storageDeletionRepository) RecordFailure(exec Executor, id uuid.UUID, errMsg string, at time.Time) error {
+	if len(errMsg) > 500 {
+		errMsg = errMsg[:500]
+	}
+	return exec.Model(&models.PendingStorageDeletion{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": errMsg,
+		"updated_at": at,
+	}).Error
+}
@@ -0,0 +1,51 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestStorageDeletionRepository_Lifecycle(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewStorageDeletionRepository()
+	exec := testDB.DB.DB
+
+	userID := uuid.New()
+	deletions := []models.PendingStorageDeletion{
+		{UserID: userID, Key: "v2/user/t/m"},
+		{UserID: userID, Key: "exports/" + userID.String() + "/", Prefix: true},
+	}
+	require.NoError(t, repo.Create(exec, deletions))
+	require.NotEqual(t, uuid.Nil, deletions[0].ID)
+
+	found := func() map[uuid.UUID]models.PendingStorageDeletion {
+		pending, err := repo.FindPending(exec, time.Now().Add(time.Minute), 1000)
+		require.NoError(t, err)
+		byID := map[uuid.UUID]models.PendingStorageDeletion{}
+		for _, d := range pending {
+			byID[d.ID] = d
+		}
+		return byID
+	}
+	assert.Contains(t, found(), deletions[0].ID)
+
+	require.NoError(t, repo.RecordFailure(exec, deletions[1].ID, "storage down", time.Now()))
+	failed := found()[deletions[1].ID]
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, "storage down", failed.LastError)
+	assert.True(t, failed.Prefix)
+
+	require.NoError(t, repo.Delete(exec, deletions[0].ID))
+	assert.NotContains(t, found(), deletions[0].ID)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// streakRepository implements StreakRepository using GORM.
+type streakRepository struct{}
+
+// NewStreakRepository creates a new GORM-backed streak repository.
+func NewStreakRepository() StreakRepository {
+	return &streakRepository{}
+}
+
+// Advance upserts in one statement, so concurrent messages on the same day
+// count it once. day is the user's local date, at midnight UTC.
+func (r *streakRepository) Advance(exec Executor, userID uuid.UUID, day time.Time) (*models.UserStreak, bool, error) {
+	day = day.UTC().Truncate(24 * time.Hour)
+	today := day.Format(time.DateOnly)
+	yesterday := day.AddDate(0, 0, -1).Format(time.DateOnly)
+	next := gorm.Expr("CASE WHEN user_streaks.last_active_day = ?::date THEN user_streaks.current_days + 1 ELSE 1 END", yesterday)
+
+	streak := models.UserStreak{UserID: userID, CurrentDays: 1, LongestDays: 1, LastActiveDay: day}
+	result := exec.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"current_days":    next,
+				"longest_days":    gorm.Expr("GREATEST(user_streaks.longest_days, (?))", next),
+				"last_active_day": gorm.Expr("?::date", today),
+				"updated_at":      time.Now().UTC(),
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				gorm.Expr("user_streaks.last_active_day < ?::date", today),
+			}},
+		},
+		clause.Returning{},
+	).Create(&streak)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, false, nil
+	}
+	return &streak, true, nil
+}
@@ -0,0 +1,52 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestStreakRepository_Advance(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewStreakRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Streak"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	advance := func(d time.Time) (*models.UserStreak, bool) {
+		streak, advanced, err := repo.Advance(exec, user.ID, d)
+		require.NoError(t, err)
+		return streak, advanced
+	}
+
+	streak, advanced := advance(day)
+	require.True(t, advanced)
+	assert.Equal(t, 1, streak.CurrentDays)
+
+	_, advanced = advance(day.Add(5 * time.Hour))
+	assert.False(t, advanced, "a second message the same day does not move the streak")
+
+	streak, advanced = advance(day.AddDate(0, 0, 1))
+	require.True(t, advanced)
+	assert.Equal(t, 2, streak.CurrentDays)
+	assert.Equal(t, 2, streak.LongestDays)
+
+	// Skipping a day starts over but keeps the longest streak
+	streak, advanced = advance(day.AddDate(0, 0, 3))
+	require.True(t, advanced)
+	assert.Equal(t, 1, streak.CurrentDays)
+	assert.Equal(t, 2, streak.LongestDays)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -66,3 +67,69 @@ func (r *subscriptionRepository) UpdateStatus(exec Executor, subscriptionID stri
 		Where("stripe_subscription_id = ?", subscriptionID).
 		Update("status", status).Error
 }
+
+func (r *subscriptionRepository) FindGraceExpired(exec Executor, now time.Time, limit int) ([]models.Subscription, error) {
+	var subs []models.Subscription
+	err := exec.Where("grace_ends_at IS NOT NULL AND grace_ends_at <= ?", now).
+		Order("grace_ends_at ASC").
+		Limit(limit).
+		Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// EndGracePeriod clears the grace window. It returns false if the grace period
+// was already ended (or cleared by a resubscribe), so only one caller finalizes it.
+func (r *subscriptionRepository) EndGracePeriod(exec Executor, id uuid.UUID) (bool, error) {
+	result := exec.Model(&models.Subscription{}).
+		Where("id = ? AND grace_ends_at IS NOT NULL", id).
+		Update("grace_ends_at", nil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, exec.Model(&models.Subscription{}).Where("id = ?", id).Update("grace_tier", nil).Error
+}
+
+func (r *subscriptionRepository) StartDunning(exec Executor, id uuid.UUID, failedAt time.Time) (bool, error) {
+	result := exec.Model(&models.Subscription{}).
+		Where("id = ? AND payment_failed_at IS NULL", id).
+		Updates(map[string]interface{}{"payment_failed_at": failedAt, "dunning_reminders_sent": 0})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *subscriptionRepository) AdvanceDunning(exec Executor, id uuid.UUID, remindersSent int) (bool, error) {
+	result := exec.Model(&models.Subscription{}).
+		Where("id = ? AND payment_failed_at IS NOT NULL AND dunning_reminders_sent = ?", id, remindersSent).
+		Update("dunning_reminders_sent", remindersSent+1)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *subscriptionRepository) EndDunning(exec Executor, id uuid.UUID) error {
+	return exec.Model(&models.Subscription{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"payment_failed_at": nil, "dunning_reminders_sent": 0}).Error
+}
+
+func (r *subscriptionRepository) FindDunningDue(exec Executor, remindersSent int, failedBefore time.Time, limit int) ([]models.Subscription, error) {
+	var subs []models.Subscription
+	err := exec.Where("status = ? AND payment_failed_at IS NOT NULL AND payment_failed_at <= ? AND dunning_reminders_sent = ?",
+		"past_due", failedBefore, remindersSent).
+		Order("payment_failed_at ASC").
+		Limit(limit).
+		Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"errors"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"ling-app/api/internal/crypt"
 	"ling-app/api/internal/models"
 )
 
@@ -56,6 +59,80 @@ func (r *threadRepository) FindByUserID(exec Executor, userID uuid.UUID) ([]mode
 	return threads, nil
 }
 
+// threadMessageStats is one thread's row from the message aggregate in FindSummariesByUserID
+type threadMessageStats struct {
+	ThreadID      uuid.UUID
+	Preview       string
+	LastMessageAt time.Time
+	MessageCount  int64
+	UnreadCount   int64
+	LastReadAt    *time.Time
+}
+
+// FindSummariesByUserID returns the user's non-archived threads with their
+// message count, unread count and newest message, most recently active first.
+// It runs two queries however many threads there are: the threads, then one
+// pass over their messages that picks each thread's newest row and counts the
+// rest.
+//
+// Unread means an assistant reply, or a pronunciation result on any message,
+// newer than the user's read position. Threads never marked read have none.
+//
+// Sealed previews come back whole, since a cut ciphertext can't be opened;
+// the encrypted repository opens and cuts them.
+func (r *threadRepository) FindSummariesByUserID(exec Executor, userID uuid.UUID) ([]models.ThreadSummary, error) {
+	threads, err := r.FindByUserID(exec, userID)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]models.ThreadSummary, len(threads))
+	if len(threads) == 0 {
+		return summaries, nil
+	}
+
+	var stats []threadMessageStats
+	err = exec.Model(&models.Message{}).
+		Select("DISTINCT ON (messages.thread_id) messages.thread_id, "+
+			"CASE WHEN starts_with(messages.content, ?) THEN messages.content ELSE LEFT(messages.content, ?) END AS preview, "+
+			"messages.timestamp AS last_message_at, "+
+			"COUNT(*) OVER (PARTITION BY messages.thread_id) AS message_count, "+
+			"COUNT(*) FILTER (WHERE (messages.role = 'assistant' AND messages.timestamp > rs.last_read_at) "+
+			"OR messages.pronunciation_updated_at > rs.last_read_at) OVER (PARTITION BY messages.thread_id) AS unread_count, "+
+			"rs.last_read_at", crypt.SealedPrefix, models.ThreadPreviewLength).
+		Joins("LEFT JOIN thread_read_states rs ON rs.thread_id = messages.thread_id AND rs.user_id = ?", userID).
+		Where("messages.thread_id IN (?)", exec.Model(&models.Thread{}).Select("id").
+			Where("user_id = ? AND archived_at IS NULL", userID)).
+		Order("messages.thread_id, messages.timestamp DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byThread := make(map[uuid.UUID]threadMessageStats, len(stats))
+	for _, s := range stats {
+		byThread[s.ThreadID] = s
+	}
+	for i, thread := range threads {
+		summaries[i] = models.ThreadSummary{Thread: thread, LastActivityAt: thread.CreatedAt}
+		if s, ok := byThread[thread.ID]; ok {
+			preview := s.Preview
+			summaries[i].LastMessagePreview = &preview
+			summaries[i].MessageCount = s.MessageCount
+			summaries[i].UnreadCount = s.UnreadCount
+			summaries[i].LastReadAt = s.LastReadAt
+			if s.LastMessageAt.After(thread.CreatedAt) {
+				summaries[i].LastActivityAt = s.LastMessageAt
+			}
+		}
+	}
+
+	// Threads come back newest first, so ties keep that order
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].LastActivityAt.After(summaries[j].LastActivityAt)
+	})
+	return summaries, nil
+}
+
 func (r *threadRepository) FindArchivedByUserID(exec Executor, userID uuid.UUID) ([]models.Thread, error) {
 	var threads []models.Thread
 	err := exec.Where("user_id = ? AND archived_at IS NOT NULL", userID).Order("archived_at DESC").Find(&threads).Error
@@ -65,6 +142,12 @@ func (r *threadRepository) FindArchivedByUserID(exec Executor, userID uuid.UUID)
 	return threads, nil
 }
 
+func (r *threadRepository) CountByUserID(exec Executor, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := exec.Model(&models.Thread{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
 func (r *threadRepository) FindByIDAndUserID(exec Executor, id, userID uuid.UUID) (*models.Thread, error) {
 	var thread models.Thread
 	err := exec.Where("id = ? AND user_id = ?", id, userID).First(&thread).Error
@@ -77,9 +160,14 @@ func (r *threadRepository) FindByIDAndUserID(exec Executor, id, userID uuid.UUID
 	return &thread, nil
 }
 
-func (r *threadRepository) FindByIDAndUserIDWithMessages(exec Executor, id, userID uuid.UUID) (*models.Thread, error) {
+// FindByIDAndUserIDWithMessages loads a thread with its messages. Pronunciation
+// analyses can run to tens of KB each, so they're only read when withAnalysis is set.
+func (r *threadRepository) FindByIDAndUserIDWithMessages(exec Executor, id, userID uuid.UUID, withAnalysis bool) (*models.Thread, error) {
 	var thread models.Thread
 	err := exec.Preload("Messages", func(db *gorm.DB) *gorm.DB {
+		if !withAnalysis {
+			db = db.Omit("pronunciation_analysis")
+		}
 		return db.Order("timestamp ASC")
 	}).Where("id = ? AND user_id = ?", id, userID).First(&thread).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -102,3 +190,27 @@ func (r *threadRepository) Delete(exec Executor, thread *models.Thread) error {
 func (r *threadRepository) UpdateName(exec Executor, id uuid.UUID, name string) error {
 	return exec.Model(&models.Thread{}).Where("id = ?", id).Update("name", name).Error
 }
+
+// MarkGoalCompleted sets GoalCompletedAt if it isn't already set.
+// Returns false if the goal was already completed (or the thread is gone).
+func (r *threadRepository) MarkGoalCompleted(exec Executor, id uuid.UUID, completedAt time.Time) (bool, error) {
+	result := exec.Model(&models.Thread{}).
+		Where("id = ? AND goal IS NOT NULL AND goal_completed_at IS NULL", id).
+		Update("goal_completed_at", completedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkEnded sets EndedAt if it isn't already set.
+// Returns false if the thread had already ended (or is gone).
+func (r *threadRepository) MarkEnded(exec Executor, id uuid.UUID, endedAt time.Time) (bool, error) {
+	result := exec.Model(&models.Thread{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", endedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// ThreadCache keeps each user's last active thread, with its messages, in
+// memory for a short time so opening the app doesn't load it cold. The
+// cached thread repository serves it to GetThread as well, and the cached
+// repositories drop an entry on every write to its thread or messages.
+//
+// Each server instance has its own cache and only sees its own writes, so
+// a write served by another instance shows up once the TTL runs out.
+type ThreadCache struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	entries  map[uuid.UUID]threadCacheEntry // by user
+	threads  map[uuid.UUID]uuid.UUID        // cached thread -> user
+	messages map[uuid.UUID]uuid.UUID        // cached message -> user
+
+	now func() time.Time
+}
+
+type threadCacheEntry struct {
+	thread    models.Thread
+	expiresAt time.Time
+}
+
+// NewThreadCache creates a cache whose entries live for ttl
+func NewThreadCache(ttl time.Duration) *ThreadCache {
+	return NewThreadCacheForTest(ttl, time.Now)
+}
+
+// NewThreadCacheForTest creates a ThreadCache with an injected clock for testing.
+func NewThreadCacheForTest(ttl time.Duration, now func() time.Time) *ThreadCache {
+	return &ThreadCache{
+		ttl:      ttl,
+		entries:  make(map[uuid.UUID]threadCacheEntry),
+		threads:  make(map[uuid.UUID]uuid.UUID),
+		messages: make(map[uuid.UUID]uuid.UUID),
+		now:      now,
+	}
+}
+
+// Get returns a copy of the user's cached thread, if there is a fresh one
+func (c *ThreadCache) Get(userID uuid.UUID) (*models.Thread, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		c.drop(userID)
+		return nil, false
+	}
+	return copyThread(&entry.thread), true
+}
+
+// Put caches a copy of thread, loaded with its messages, as the user's last
+// active thread
+func (c *ThreadCache) Put(userID uuid.UUID, thread *models.Thread) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.drop(userID)
+	c.entries[userID] = threadCacheEntry{thread: *copyThread(thread), expiresAt: c.now().Add(c.ttl)}
+	c.threads[thread.ID] = userID
+	for _, message := range thread.Messages {
+		c.messages[message.ID] = userID
+	}
+}
+
+// InvalidateUser drops the user's cached thread
+func (c *ThreadCache) InvalidateUser(userID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.drop(userID)
+}
+
+// invalidateThread drops the entry holding the thread, reporting whether
+// there was one
+func (c *ThreadCache) invalidateThread(threadID uuid.UUID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	userID, ok := c.threads[threadID]
+	if ok {
+		c.drop(userID)
+	}
+	return ok
+}
+
+// invalidateMessage drops the entry holding the message
+func (c *ThreadCache) invalidateMessage(messageID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if userID, ok := c.messages[messageID]; ok {
+		c.drop(userID)
+	}
+}
+
+// empty reports whether nothing is cached, so writes can skip looking up
+// whose thread they touched
+func (c *ThreadCache) empty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries) == 0
+}
+
+// drop removes the user's entry and its index rows; the caller holds mu
+func (c *ThreadCache) drop(userID uuid.UUID) {
+	entry, ok := c.entries[userID]
+	if !ok {
+		return
+	}
+	delete(c.entries, userID)
+	delete(c.threads, entry.thread.ID)
+	for _, message := range entry.thread.Messages {
+		delete(c.messages, message.ID)
+	}
+}
+
+// copyThread copies the thread and its message list, so callers can't change
+// what's cached
+func copyThread(thread *models.Thread) *models.Thread {
+	copied := *thread
+	copied.Messages = make([]models.Message, len(thread.Messages))
+	copy(copied.Messages, thread.Messages)
+	return &copied
+}
+
+// cachedThreadRepository serves the cached thread and drops it on writes
+type cachedThreadRepository struct {
+	ThreadRepository
+	cache *ThreadCache
+}
+
+// NewCachedThreadRepository wraps a thread repository with the last active
+// thread cache
+func NewCachedThreadRepository(inner ThreadRepository, cache *ThreadCache) ThreadRepository {
+	return &cachedThreadRepository{ThreadRepository: inner, cache: cache}
+}
+
+// FindByIDAndUserIDWithMessages answers from the cache when the user's cached
+// thread is the one asked for. Analyses aren't cached.
+func (r *cachedThreadRepository) FindByIDAndUserIDWithMessages(exec Executor, id, userID uuid.UUID, withAnalysis bool) (*models.Thread, error) {
+	if !withAnalysis {
+		if thread, ok := r.cache.Get(userID); ok && thread.ID == id {
+			return thread, nil
+		}
+	}
+	return r.ThreadRepository.FindByIDAndUserIDWithMessages(exec, id, userID, withAnalysis)
+}
+
+func (r *cachedThreadRepository) Create(exec Executor, thread *models.Thread) error {
+	defer r.cache.InvalidateUser(thread.UserID)
+	return r.ThreadRepository.Create(exec, thread)
+}
+
+func (r *cachedThreadRepository) Save(exec Executor, thread *models.Thread) error {
+	defer r.cache.InvalidateUser(thread.UserID)
+	return r.ThreadRepository.Save(exec, thread)
+}
+
+func (r *cachedThreadRepository) Delete(exec Executor, thread *models.Thread) error {
+	defer r.cache.InvalidateUser(thread.UserID)
+	return r.ThreadRepository.Delete(exec, thread)
+}
+
+func (r *cachedThreadRepository) UpdateName(exec Executor, id uuid.UUID, name string) error {
+	defer r.cache.invalidateThread(id)
+	return r.ThreadRepository.UpdateName(exec, id, name)
+}
+
+func (r *cachedThreadRepository) MarkGoalCompleted(exec Executor, id uuid.UUID, completedAt time.Time) (bool, error) {
+	defer r.cache.invalidateThread(id)
+	return r.ThreadRepository.MarkGoalCompleted(exec, id, completedAt)
+}
+
+func (r *cachedThreadRepository) MarkEnded(exec Executor, id uuid.UUID, endedAt time.Time) (bool, error) {
+	defer r.cache.invalidateThread(id)
+	return r.ThreadRepository.MarkEnded(exec, id, endedAt)
+}
+
+// cachedMessageRepository drops the cached thread when its messages change
+type cachedMessageRepository struct {
+	MessageRepository
+	cache *ThreadCache
+}
+
+// NewCachedMessageRepository wraps a message repository so its writes drop
+// the last active thread cache
+func NewCachedMessageRepository(inner MessageRepository, cache *ThreadCache) MessageRepository {
+	return &cachedMessageRepository{MessageRepository: inner, cache: cache}
+}
+
+// Create drops the thread owner's entry even when it holds another thread:
+// the thread written to is now their last active one
+func (r *cachedMessageRepository) Create(exec Executor, message *models.Message) error {
+	if err := r.MessageRepository.Create(exec, message); err != nil {
+		return err
+	}
+	if r.cache.invalidateThread(message.ThreadID) || r.cache.empty() {
+		return nil
+	}
+	var owners []uuid.UUID
+	if err := exec.Model(&models.Thread{}).Where("id = ?", message.ThreadID).Pluck("user_id", &owners).Error; err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		r.cache.InvalidateUser(owner)
+	}
+	return nil
+}
+
+func (r *cachedMessageRepository) UpdatePronunciationStatus(exec Executor, id uuid.UUID, status string) error {
+	defer r.cache.invalidateMessage(id)
+	return r.MessageRepository.UpdatePronunciationStatus(exec, id, status)
+}
+
+func (r *cachedMessageRepository) UpdatePronunciationAnalysis(exec Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	defer r.cache.invalidateMessage(id)
+	return r.MessageRepository.UpdatePronunciationAnalysis(exec, id, status, analysis, quality, confidence, lowConfidence, updatedAt)
+}
+
+func (r *cachedMessageRepository) UpdatePronunciationError(exec Executor, id uuid.UUID, status string, errMsg string, updatedAt time.Time) error {
+	defer r.cache.invalidateMessage(id)
+	return r.MessageRepository.UpdatePronunciationError(exec, id, status, errMsg, updatedAt)
+}
+
+func (r *cachedMessageRepository) ClearAudio(exec Executor, id uuid.UUID) error {
+	defer r.cache.invalidateMessage(id)
+	return r.MessageRepository.ClearAudio(exec, id)
+}
+
+func (r *cachedMessageRepository) UpdateContent(exec Executor, id uuid.UUID, content string, correctedAt time.Time) error {
+	defer r.cache.invalidateMessage(id)
+	return r.MessageRepository.UpdateContent(exec, id, content, correctedAt)
+}
+
+func (r *cachedMessageRepository) ResetPronunciation(exec Executor, id uuid.UUID, status string, updatedAt time.Time) error {
+	defer r.cache.invalidateMessage(id)
+	return r.MessageRepository.ResetPronunciation(exec, id, status, updatedAt)
+}
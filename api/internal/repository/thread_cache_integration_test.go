@@ -0,0 +1,87 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestCachedThreadRepository(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	exec := testDB.DB.DB
+
+	now := time.Now()
+	cache := repository.NewThreadCacheForTest(time.Minute, func() time.Time { return now })
+	threads := repository.NewCachedThreadRepository(repository.NewThreadRepository(), cache)
+	messages := repository.NewCachedMessageRepository(repository.NewMessageRepository(), cache)
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Cache"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, threads.Create(exec, thread))
+	first := &models.Message{ThreadID: thread.ID, Role: "user", Content: "Hello"}
+	require.NoError(t, messages.Create(exec, first))
+
+	// load reads the thread and caches it as the user's last one
+	load := func() *models.Thread {
+		t.Helper()
+		loaded, err := threads.FindByIDAndUserIDWithMessages(exec, thread.ID, user.ID, false)
+		require.NoError(t, err)
+		cache.Put(user.ID, loaded)
+		return loaded
+	}
+	cached := func() bool {
+		_, ok := cache.Get(user.ID)
+		return ok
+	}
+
+	t.Run("serves the cached thread without a query", func(t *testing.T) {
+		load()
+		queries := countQueries(t, testDB, func() {
+			got, err := threads.FindByIDAndUserIDWithMessages(exec, thread.ID, user.ID, false)
+			require.NoError(t, err)
+			assert.Len(t, got.Messages, 1)
+		})
+		assert.Zero(t, queries)
+	})
+
+	t.Run("message writes drop the thread", func(t *testing.T) {
+		load()
+		require.NoError(t, messages.UpdateContent(exec, first.ID, "Hello there", now))
+		assert.False(t, cached())
+
+		got := load()
+		assert.Equal(t, "Hello there", got.Messages[0].Content)
+	})
+
+	t.Run("a message in another thread drops the user's entry", func(t *testing.T) {
+		load()
+		other := &models.Thread{UserID: user.ID}
+		require.NoError(t, repository.NewThreadRepository().Create(exec, other))
+		require.NoError(t, messages.Create(exec, &models.Message{ThreadID: other.ID, Role: "user", Content: "Hi"}))
+		assert.False(t, cached(), "the other thread is now the last active one")
+	})
+
+	t.Run("thread writes drop the thread", func(t *testing.T) {
+		load()
+		require.NoError(t, threads.UpdateName(exec, thread.ID, "Renamed"))
+		assert.False(t, cached())
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		load()
+		now = now.Add(time.Minute)
+		assert.False(t, cached())
+	})
+}
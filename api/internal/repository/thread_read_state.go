@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// threadReadStateRepository implements ThreadReadStateRepository using GORM.
+type threadReadStateRepository struct{}
+
+// NewThreadReadStateRepository creates a new GORM-backed thread read state repository.
+func NewThreadReadStateRepository() ThreadReadStateRepository {
+	return &threadReadStateRepository{}
+}
+
+func (r *threadReadStateRepository) Find(exec Executor, userID, threadID uuid.UUID) (*models.ThreadReadState, error) {
+	var state models.ThreadReadState
+	err := exec.Where("user_id = ? AND thread_id = ?", userID, threadID).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// MarkRead records that the user has read the thread up to readAt. The read
+// position only moves forward, so a device that reports late can't mark
+// newer messages unread again.
+func (r *threadReadStateRepository) MarkRead(exec Executor, userID, threadID uuid.UUID, readAt time.Time) error {
+	state := models.ThreadReadState{UserID: userID, ThreadID: threadID, LastReadAt: readAt, UpdatedAt: time.Now()}
+	return exec.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "thread_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "last_read_at"}, Value: gorm.Expr("GREATEST(thread_read_states.last_read_at, excluded.last_read_at)")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("excluded.updated_at")},
+		},
+	}).Create(&state).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+)
+
+// threadShareRepository implements ThreadShareRepository using GORM.
+type threadShareRepository struct{}
+
+// NewThreadShareRepository creates a new GORM-backed thread share repository.
+func NewThreadShareRepository() ThreadShareRepository {
+	return &threadShareRepository{}
+}
+
+func (r *threadShareRepository) Create(exec Executor, share *models.ThreadShare) error {
+	return exec.Create(share).Error
+}
+
+func (r *threadShareRepository) FindByToken(exec Executor, token string) (*models.ThreadShare, error) {
+	var share models.ThreadShare
+	err := exec.Where("token = ?", token).First(&share).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *threadShareRepository) FindActiveByThreadID(exec Executor, threadID uuid.UUID, now time.Time) ([]models.ThreadShare, error) {
+	var shares []models.ThreadShare
+	err := exec.Where("thread_id = ? AND revoked_at IS NULL AND expires_at > ?", threadID, now).
+		Order("created_at DESC").
+		Find(&shares).Error
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (r *threadShareRepository) Revoke(exec Executor, id, threadID uuid.UUID, revokedAt time.Time) error {
+	result := exec.Model(&models.ThreadShare{}).
+		Where("id = ? AND thread_id = ? AND revoked_at IS NULL", id, threadID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
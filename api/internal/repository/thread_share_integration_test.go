@@ -0,0 +1,52 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestThreadShareRepository_Lifecycle(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewThreadShareRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Shares"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+
+	now := time.Now().Truncate(time.Microsecond)
+	active := &models.ThreadShare{ThreadID: thread.ID, UserID: user.ID, Token: uuid.NewString(), ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	expired := &models.ThreadShare{ThreadID: thread.ID, UserID: user.ID, Token: uuid.NewString(), ExpiresAt: now.Add(-time.Minute), CreatedAt: now.Add(-time.Hour)}
+	require.NoError(t, repo.Create(exec, active))
+	require.NoError(t, repo.Create(exec, expired))
+
+	found, err := repo.FindByToken(exec, active.Token)
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, found.ID)
+
+	shares, err := repo.FindActiveByThreadID(exec, thread.ID, now)
+	require.NoError(t, err)
+	require.Len(t, shares, 1, "expired shares aren't listed")
+	assert.Equal(t, active.ID, shares[0].ID)
+
+	assert.ErrorIs(t, repo.Revoke(exec, active.ID, uuid.New(), now), repository.ErrNotFound, "shares are revoked through their thread")
+	require.NoError(t, repo.Revoke(exec, active.ID, thread.ID, now))
+	assert.ErrorIs(t, repo.Revoke(exec, active.ID, thread.ID, now), repository.ErrNotFound)
+
+	shares, err = repo.FindActiveByThreadID(exec, thread.ID, now)
+	require.NoError(t, err)
+	assert.Empty(t, shares)
+}
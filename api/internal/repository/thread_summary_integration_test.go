@@ -0,0 +1,155 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+// countQueries counts the SELECTs GORM issues while call runs
+func countQueries(t *testing.T, testDB *testutil.TestDB, call func()) int64 {
+	t.Helper()
+
+	var queries atomic.Int64
+	callbacks := testDB.Callback()
+	require.NoError(t, callbacks.Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) {
+		queries.Add(1)
+	}))
+	defer func() { _ = callbacks.Query().Remove("test:count_queries") }()
+
+	call()
+	return queries.Load()
+}
+
+func TestThreadRepository_FindSummariesByUserID_ManyThreads(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewThreadRepository()
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Summaries"}
+	require.NoError(t, testDB.Create(user).Error)
+
+	// Thread i has i%4 messages; every tenth thread is archived
+	const threadCount = 500
+	start := time.Now().Add(-24 * time.Hour).Truncate(time.Microsecond)
+	threads := make([]models.Thread, threadCount)
+	var messages []models.Message
+	wantCounts := map[uuid.UUID]int64{}
+	wantPreviews := map[uuid.UUID]string{}
+	for i := range threads {
+		threads[i] = models.Thread{ID: uuid.New(), UserID: user.ID, CreatedAt: start.Add(time.Duration(i) * time.Second)}
+		if i%10 == 0 {
+			archivedAt := start
+			threads[i].ArchivedAt = &archivedAt
+		}
+		for j := 0; j < i%4; j++ {
+			content := fmt.Sprintf("thread %d message %d", i, j)
+			messages = append(messages, models.Message{
+				ThreadID:  threads[i].ID,
+				Role:      "user",
+				Content:   content,
+				Timestamp: start.Add(time.Duration(threadCount-i)*time.Minute + time.Duration(j)*time.Second),
+			})
+			wantPreviews[threads[i].ID] = content
+		}
+		wantCounts[threads[i].ID] = int64(i % 4)
+	}
+	require.NoError(t, testDB.CreateInBatches(threads, 100).Error)
+	require.NoError(t, testDB.CreateInBatches(messages, 200).Error)
+
+	var summaries []models.ThreadSummary
+	queries := countQueries(t, testDB, func() {
+		var err error
+		summaries, err = repo.FindSummariesByUserID(testDB.DB.DB, user.ID)
+		require.NoError(t, err)
+	})
+
+	assert.EqualValues(t, 2, queries, "summaries must not issue a query per thread")
+	assert.Len(t, summaries, threadCount-threadCount/10)
+	for i, summary := range summaries {
+		assert.Nil(t, summary.ArchivedAt)
+		assert.Equal(t, wantCounts[summary.ID], summary.MessageCount)
+		if preview, ok := wantPreviews[summary.ID]; ok {
+			require.NotNil(t, summary.LastMessagePreview)
+			assert.Equal(t, preview, *summary.LastMessagePreview)
+		} else {
+			assert.Nil(t, summary.LastMessagePreview)
+			assert.True(t, summary.LastActivityAt.Equal(summary.CreatedAt))
+		}
+		if i > 0 {
+			assert.False(t, summary.LastActivityAt.After(summaries[i-1].LastActivityAt), "summaries out of order at %d", i)
+		}
+	}
+}
+
+func TestThreadRepository_FindSummariesByUserID_TruncatesPreview(t *testing.T) {
+	testDB, user, thread := setupRepoDB(t)
+	repo := repository.NewThreadRepository()
+
+	long := strings.Repeat("á", models.ThreadPreviewLength+50)
+	require.NoError(t, testDB.Create(&models.Message{ThreadID: thread.ID, Role: "assistant", Content: long, Timestamp: time.Now()}).Error)
+
+	summaries, err := repo.FindSummariesByUserID(testDB.DB.DB, user.ID)
+
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.NotNil(t, summaries[0].LastMessagePreview)
+	assert.Equal(t, strings.Repeat("á", models.ThreadPreviewLength), *summaries[0].LastMessagePreview)
+	assert.EqualValues(t, 1, summaries[0].MessageCount)
+}
+
+func TestThreadRepository_FindSummariesByUserID_NoThreads(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+
+	summaries, err := repository.NewThreadRepository().FindSummariesByUserID(testDB.DB.DB, uuid.New())
+
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+	assert.NotNil(t, summaries, "an empty list must encode as [] rather than null")
+}
+
+func TestThreadRepository_FindSummariesByUserID_UnreadCounts(t *testing.T) {
+	testDB, user, thread := setupRepoDB(t)
+	repo := repository.NewThreadRepository()
+	readStates := repository.NewThreadReadStateRepository()
+
+	readAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	analyzedAt := readAt.Add(10 * time.Minute)
+	require.NoError(t, testDB.Create(&[]models.Message{
+		{ThreadID: thread.ID, Role: "assistant", Content: "seen", Timestamp: readAt.Add(-time.Minute)},
+		// Sent before the read, but its analysis arrived after
+		{ThreadID: thread.ID, Role: "user", Content: "analyzed later", Timestamp: readAt.Add(-time.Second), PronunciationUpdatedAt: &analyzedAt},
+		{ThreadID: thread.ID, Role: "user", Content: "own message", Timestamp: readAt.Add(time.Minute)},
+		{ThreadID: thread.ID, Role: "assistant", Content: "new reply", Timestamp: readAt.Add(2 * time.Minute)},
+	}).Error)
+
+	summaries, err := repo.FindSummariesByUserID(testDB.DB.DB, user.ID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Zero(t, summaries[0].UnreadCount, "threads never marked read have nothing unread")
+
+	require.NoError(t, readStates.MarkRead(testDB.DB.DB, user.ID, thread.ID, readAt))
+	// A stale device reporting an older position doesn't move it back
+	require.NoError(t, readStates.MarkRead(testDB.DB.DB, user.ID, thread.ID, readAt.Add(-time.Hour)))
+
+	summaries, err = repo.FindSummariesByUserID(testDB.DB.DB, user.ID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.EqualValues(t, 2, summaries[0].UnreadCount)
+	require.NotNil(t, summaries[0].LastReadAt)
+	assert.True(t, summaries[0].LastReadAt.Equal(readAt))
+}
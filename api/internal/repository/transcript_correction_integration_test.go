@@ -0,0 +1,89 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestPhonemeStatsSnapshotRepository_SaveTake(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewPhonemeStatsSnapshotRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Snapshots"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	message := &models.Message{ThreadID: thread.ID, Role: "user", Content: "I sink so", Timestamp: time.Now()}
+	require.NoError(t, testDB.Create(message).Error)
+
+	first := models.PhonemeTally{Phonemes: map[string]models.PhonemeCounts{"s": {TotalAttempts: 1, CorrectCount: 1}}}
+	require.NoError(t, repo.Save(exec, &models.PhonemeStatsSnapshot{MessageID: message.ID, UserID: user.ID, Tally: first}))
+	second := models.PhonemeTally{
+		Phonemes:      map[string]models.PhonemeCounts{"θ": {TotalAttempts: 1}},
+		Substitutions: map[string]map[string]int{"θ": {"s": 1}},
+	}
+	require.NoError(t, repo.Save(exec, &models.PhonemeStatsSnapshot{MessageID: message.ID, UserID: user.ID, Tally: second}), "saving again replaces")
+
+	taken, err := repo.Take(exec, message.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, taken.UserID)
+	assert.Equal(t, second, taken.Tally)
+
+	_, err = repo.Take(exec, message.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "a snapshot is only taken once")
+
+	require.NoError(t, repo.Save(exec, &models.PhonemeStatsSnapshot{MessageID: message.ID, UserID: user.ID, Tally: first}))
+	require.NoError(t, testDB.Delete(message).Error)
+	var left int64
+	require.NoError(t, testDB.Model(&models.PhonemeStatsSnapshot{}).Count(&left).Error)
+	assert.Zero(t, left, "snapshots go with their message")
+}
+
+func TestMessageRepository_CorrectTranscript(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewMessageRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Corrections"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID}
+	require.NoError(t, testDB.Create(thread).Error)
+	confidence := 0.9
+	message := &models.Message{
+		ThreadID:                thread.ID,
+		Role:                    "user",
+		Content:                 "I sink so",
+		Timestamp:               time.Now(),
+		PronunciationStatus:     "complete",
+		PronunciationAnalysis:   models.JSONMap{"phoneme_count": 5.0},
+		PronunciationConfidence: &confidence,
+	}
+	require.NoError(t, testDB.Create(message).Error)
+
+	now := time.Now().Truncate(time.Microsecond)
+	require.NoError(t, repo.ResetPronunciation(exec, message.ID, "pending", now))
+	require.NoError(t, repo.UpdateContent(exec, message.ID, "I think so", now))
+
+	found, err := repo.FindByID(exec, message.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "I think so", found.Content)
+	require.NotNil(t, found.TranscriptCorrectedAt)
+	assert.True(t, now.Equal(*found.TranscriptCorrectedAt))
+	assert.Equal(t, "pending", found.PronunciationStatus)
+	assert.Nil(t, found.PronunciationAnalysis)
+	assert.Nil(t, found.PronunciationConfidence)
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"ling-app/api/internal/models"
@@ -16,6 +18,18 @@ func NewUserRepository() UserRepository {
 	return &userRepository{}
 }
 
+func (r *userRepository) FindByID(exec Executor, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := exec.Where("id = ?", id).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) FindByEmail(exec Executor, email string) (*models.User, error) {
 	var user models.User
 	err := exec.Where("email = ?", email).First(&user).Error
@@ -59,3 +73,41 @@ func (r *userRepository) Create(exec Executor, user *models.User) error {
 func (r *userRepository) Save(exec Executor, user *models.User) error {
 	return exec.Save(user).Error
 }
+
+func (r *userRepository) Search(exec Executor, query string, limit int) ([]models.User, error) {
+	var users []models.User
+	tx := exec.Order("created_at DESC").Limit(limit)
+	if query = strings.TrimSpace(query); query != "" {
+		pattern := "%" + escapeLike(query) + "%"
+		tx = tx.Where("email ILIKE ? OR name ILIKE ?", pattern, pattern)
+	}
+	if err := tx.Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *userRepository) FindMergedInto(exec Executor, id uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if err := exec.Where("merged_into_id = ?", id).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// likeEscaper makes a search term match literally in a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+func (r *userRepository) MarkEmailVerified(exec Executor, id uuid.UUID, email string) (bool, error) {
+	result := exec.Model(&models.User{}).
+		Where("id = ? AND email = ?", id, email).
+		Update("email_verified", true)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/models"
+)
+
+// userSettingsRepository implements UserSettingsRepository using GORM.
+type userSettingsRepository struct{}
+
+// NewUserSettingsRepository creates a new GORM-backed user settings repository.
+func NewUserSettingsRepository() UserSettingsRepository {
+	return &userSettingsRepository{}
+}
+
+func (r *userSettingsRepository) FindByUserID(exec Executor, userID uuid.UUID) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	err := exec.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *userSettingsRepository) Upsert(exec Executor, settings *models.UserSettings) error {
+	return exec.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"audio_retention_days", "reply_length", "speech_rate", "encrypt_content", "timezone", "email_achievements", "email_updates", "updated_at"}),
+	}).Create(settings).Error
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"ling-app/api/internal/crypt"
+	"ling-app/api/internal/models"
+)
+
+// warehouseRepository implements WarehouseRepository using GORM.
+type warehouseRepository struct{}
+
+// NewWarehouseRepository creates a new GORM-backed warehouse repository.
+func NewWarehouseRepository() WarehouseRepository {
+	return &warehouseRepository{}
+}
+
+func (r *warehouseRepository) FindWatermarks(exec Executor) ([]models.WarehouseWatermark, error) {
+	var watermarks []models.WarehouseWatermark
+	if err := exec.Find(&watermarks).Error; err != nil {
+		return nil, err
+	}
+	return watermarks, nil
+}
+
+func (r *warehouseRepository) SaveWatermark(exec Executor, watermark *models.WarehouseWatermark) error {
+	return exec.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "fact_table"}},
+		DoUpdates: clause.AssignmentColumns([]string{"exported_through", "updated_at"}),
+	}).Create(watermark).Error
+}
+
+func (r *warehouseRepository) FirstFactTime(exec Executor, table string) (*time.Time, error) {
+	var first struct {
+		First *time.Time
+	}
+
+	var err error
+	switch table {
+	case models.WarehouseTableMessages, models.WarehouseTableUsage:
+		err = exec.Model(&models.Message{}).Select("MIN(timestamp) AS first").Scan(&first).Error
+	case models.WarehouseTableAnalyses:
+		err = exec.Model(&models.Message{}).Select("MIN(pronunciation_updated_at) AS first").
+			Where("pronunciation_status = ?", "complete").Scan(&first).Error
+	case models.WarehouseTableTransactions:
+		err = exec.Model(&models.CreditTransaction{}).Select("MIN(created_at) AS first").Scan(&first).Error
+	default:
+		return nil, fmt.Errorf("unknown warehouse table %q", table)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return first.First, nil
+}
+
+// MessageFacts leaves the content length of sealed messages out: the length
+// of the ciphertext would give away roughly how long the text is
+func (r *warehouseRepository) MessageFacts(exec Executor, from, to time.Time) ([]models.WarehouseMessageFact, error) {
+	var facts []models.WarehouseMessageFact
+	err := exec.Model(&models.Message{}).
+		Select("messages.id, messages.thread_id, threads.user_id, threads.locale, messages.role, messages.kind, "+
+			"messages.has_audio, messages.audio_duration_seconds, "+
+			"CASE WHEN starts_with(messages.content, ?) THEN NULL ELSE LENGTH(messages.content) END AS content_length, "+
+			"messages.pronunciation_status, messages.tone, messages.timestamp", crypt.SealedPrefix).
+		Joins("JOIN threads ON threads.id = messages.thread_id").
+		Where("messages.timestamp >= ? AND messages.timestamp < ?", from, to).
+		Order("messages.timestamp").
+		Scan(&facts).Error
+	if err != nil {
+		return nil, err
+	}
+	return facts, nil
+}
+
+func (r *warehouseRepository) AnalysisFacts(exec Executor, from, to time.Time) ([]models.WarehouseAnalysisFact, error) {
+	var facts []models.WarehouseAnalysisFact
+	err := exec.Model(&models.Message{}).
+		Select("messages.id, threads.user_id, threads.locale, messages.pronunciation_analysis, "+
+			"messages.pronunciation_confidence, messages.pronunciation_low_confidence, messages.pronunciation_quality, "+
+			"messages.pronunciation_updated_at").
+		Joins("JOIN threads ON threads.id = messages.thread_id").
+		Where("messages.pronunciation_status = ?", "complete").
+		Where("messages.pronunciation_analysis->>? IS NULL", sealedMapKey).
+		Where("messages.pronunciation_updated_at >= ? AND messages.pronunciation_updated_at < ?", from, to).
+		Order("messages.pronunciation_updated_at").
+		Scan(&facts).Error
+	if err != nil {
+		return nil, err
+	}
+	return facts, nil
+}
+
+func (r *warehouseRepository) TransactionFacts(exec Executor, from, to time.Time) ([]models.CreditTransaction, error) {
+	var transactions []models.CreditTransaction
+	err := exec.Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *warehouseRepository) UsageFacts(exec Executor, from, to time.Time) ([]models.WarehouseUsageFact, error) {
+	var facts []models.WarehouseUsageFact
+	err := exec.Model(&models.Message{}).
+		Select("threads.user_id, "+
+			"COUNT(*) FILTER (WHERE messages.role = 'user') AS user_messages, "+
+			"COUNT(*) FILTER (WHERE messages.role = 'user' AND messages.has_audio) AS voice_messages, "+
+			"COUNT(*) FILTER (WHERE messages.role = 'assistant') AS assistant_messages, "+
+			"COALESCE(SUM(messages.audio_duration_seconds) FILTER (WHERE messages.role = 'user'), 0) AS audio_seconds, "+
+			"COUNT(DISTINCT messages.thread_id) AS active_threads").
+		Joins("JOIN threads ON threads.id = messages.thread_id").
+		Where("messages.timestamp >= ? AND messages.timestamp < ?", from, to).
+		Group("threads.user_id").
+		Order("threads.user_id").
+		Scan(&facts).Error
+	if err != nil {
+		return nil, err
+	}
+	return facts, nil
+}
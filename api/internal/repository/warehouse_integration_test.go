@@ -0,0 +1,86 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/crypt"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil"
+)
+
+func TestWarehouseRepository_Facts(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewWarehouseRepository()
+	exec := testDB.DB.DB
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Warehouse"}
+	require.NoError(t, testDB.Create(user).Error)
+	thread := &models.Thread{UserID: user.ID, Locale: "es-MX"}
+	require.NoError(t, testDB.Create(thread).Error)
+
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	duration := 4.5
+	analyzedAt := day.Add(2 * time.Hour)
+	messages := []models.Message{
+		{ThreadID: thread.ID, Role: "user", Content: "hola", HasAudio: true, AudioDurationSeconds: &duration,
+			PronunciationStatus: "complete", PronunciationAnalysis: models.JSONMap{"phoneme_count": 4.0},
+			PronunciationUpdatedAt: &analyzedAt, Timestamp: day.Add(time.Hour)},
+		{ThreadID: thread.ID, Role: "assistant", Content: "¡Hola! ¿Qué tal?", Timestamp: day.Add(time.Hour + time.Second)},
+		{ThreadID: thread.ID, Role: "user", Content: crypt.SealedPrefix + "c2VhbGVkIG1lc3NhZ2U", Timestamp: day.Add(time.Hour + 2*time.Second)},
+		{ThreadID: thread.ID, Role: "user", Content: "next day", Timestamp: day.AddDate(0, 0, 1)},
+	}
+	require.NoError(t, testDB.Create(&messages).Error)
+
+	first, err := repo.FirstFactTime(exec, models.WarehouseTableMessages)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.True(t, first.Equal(day.Add(time.Hour)))
+
+	facts, err := repo.MessageFacts(exec, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, facts, 3, "the day's end is exclusive")
+	assert.Equal(t, user.ID, facts[0].UserID)
+	assert.Equal(t, "es-MX", facts[0].Locale)
+	if assert.NotNil(t, facts[0].ContentLength) {
+		assert.Equal(t, int64(4), *facts[0].ContentLength)
+	}
+	assert.Nil(t, facts[2].ContentLength, "a sealed message's length is left out")
+	assert.Equal(t, 4.5, *facts[0].AudioDurationSeconds)
+
+	analyses, err := repo.AnalysisFacts(exec, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, analyses, 1)
+	assert.Equal(t, 4.0, analyses[0].PronunciationAnalysis["phoneme_count"])
+
+	usage, err := repo.UsageFacts(exec, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []models.WarehouseUsageFact{{
+		UserID: user.ID, UserMessages: 2, VoiceMessages: 1, AssistantMessages: 1, AudioSeconds: 4.5, ActiveThreads: 1,
+	}}, usage)
+}
+
+func TestWarehouseRepository_SaveWatermark(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	repo := repository.NewWarehouseRepository()
+	exec := testDB.DB.DB
+
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveWatermark(exec, &models.WarehouseWatermark{Table: models.WarehouseTableUsage, ExportedThrough: day}))
+	require.NoError(t, repo.SaveWatermark(exec, &models.WarehouseWatermark{Table: models.WarehouseTableUsage, ExportedThrough: day.AddDate(0, 0, 1)}))
+
+	watermarks, err := repo.FindWatermarks(exec)
+	require.NoError(t, err)
+	require.Len(t, watermarks, 1)
+	assert.True(t, watermarks[0].ExportedThrough.Equal(day.AddDate(0, 0, 1)))
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SubscriptionCanceller ends a paid subscription for good, for an account
+// being deleted
+type SubscriptionCanceller interface {
+	CancelForDeletion(userID uuid.UUID) error
+}
+
+// AccountDeleter defines the interface for deleting accounts
+type AccountDeleter interface {
+	DeleteAccount(user *models.User) error
+}
+
+// storageSweepBatchSize caps how many pending deletions are tried per sweep
+const storageSweepBatchSize = 500
+
+// storageSweepGrace keeps the sweep off deletions just recorded, which the
+// deletion's own job is about to work through
+const storageSweepGrace = 5 * time.Minute
+
+// AccountDeletionService deletes an account and everything that belongs to
+// it when its owner asks to be forgotten: threads and messages, stats,
+// credits, sessions, accounts merged into it, and its recordings, decks and
+// reports in storage.
+type AccountDeletionService struct {
+	exec        repository.Executor
+	txRunner    TxRunner
+	userRepo    repository.UserRepository
+	guestRepo   repository.GuestRepository
+	storageRepo repository.StorageDeletionRepository
+	billing     SubscriptionCanceller
+	storage     client.StorageClient
+	queue       *jobs.Queue
+	interval    time.Duration
+
+	now func() time.Time
+}
+
+// NewAccountDeletionService creates a new account deletion service. Stored
+// objects are deleted by a job on queue, or straight away if queue is nil;
+// what is left is retried every interval.
+func NewAccountDeletionService(
+	database *db.DB,
+	userRepo repository.UserRepository,
+	guestRepo repository.GuestRepository,
+	storageRepo repository.StorageDeletionRepository,
+	billing SubscriptionCanceller,
+	storage client.StorageClient,
+	queue *jobs.Queue,
+	interval time.Duration,
+) *AccountDeletionService {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	return &AccountDeletionService{
+		exec:        database.DB,
+		txRunner:    database.DB,
+		userRepo:    userRepo,
+		guestRepo:   guestRepo,
+		storageRepo: storageRepo,
+		billing:     billing,
+		storage:     storage,
+		queue:       queue,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// NewAccountDeletionServiceForTest creates an AccountDeletionService with injected dependencies for testing.
+func NewAccountDeletionServiceForTest(
+	exec repository.Executor,
+	txRunner TxRunner,
+	userRepo repository.UserRepository,
+	guestRepo repository.GuestRepository,
+	storageRepo repository.StorageDeletionRepository,
+	billing SubscriptionCanceller,
+	storage client.StorageClient,
+	now func() time.Time,
+) *AccountDeletionService {
+	return &AccountDeletionService{
+		exec:        exec,
+		txRunner:    txRunner,
+		userRepo:    userRepo,
+		guestRepo:   guestRepo,
+		storageRepo: storageRepo,
+		billing:     billing,
+		storage:     storage,
+		interval:    15 * time.Minute,
+		now:         now,
+	}
+}
+
+// DeleteAccount cancels the user's subscription, then deletes the account
+// and its rows in one transaction. If the subscription can't be cancelled
+// nothing is deleted, so the user isn't billed for an account they no longer
+// have. The same transaction records the stored objects to delete, which a
+// background job then deletes; whatever it doesn't get to, e.g. because of a
+// restart or a storage failure, is retried by Start's sweep.
+func (s *AccountDeletionService) DeleteAccount(user *models.User) error {
+	if err := s.billing.CancelForDeletion(user.ID); err != nil {
+		return fmt.Errorf("cancel subscription: %w", err)
+	}
+
+	merged, err := s.userRepo.FindMergedInto(s.exec, user.ID)
+	if err != nil {
+		return fmt.Errorf("find merged accounts: %w", err)
+	}
+	userIDs := []uuid.UUID{user.ID}
+	for _, m := range merged {
+		userIDs = append(userIDs, m.ID)
+	}
+
+	var deletions []models.PendingStorageDeletion
+	for _, id := range userIDs {
+		keys, err := s.guestRepo.FindAudioKeys(s.exec, id)
+		if err != nil {
+			return fmt.Errorf("find recordings: %w", err)
+		}
+		deletions = append(deletions, storageDeletions(user.ID, id, keys)...)
+	}
+
+	// Tombstones go first; they point at the account
+	err = s.txRunner.Transaction(func(tx *gorm.DB) error {
+		for i := len(userIDs) - 1; i >= 0; i-- {
+			if err := s.guestRepo.DeleteUser(tx, userIDs[i]); err != nil {
+				return err
+			}
+		}
+		return s.storageRepo.Create(tx, deletions)
+	})
+	if err != nil {
+		return fmt.Errorf("delete account: %w", err)
+	}
+	log.Printf("[AccountDeletion] Deleted user %s with %d merged accounts", user.ID, len(merged))
+
+	cleanup := func(ctx context.Context) error {
+		return s.deleteObjects(ctx, deletions)
+	}
+	if s.queue != nil {
+		err := s.queue.Enqueue(jobs.Job{
+			Name: "account_cleanup:" + user.ID.String(),
+			Lane: jobs.LaneStandard,
+			Run:  cleanup,
+		})
+		if err == nil {
+			return nil
+		}
+		log.Printf("[AccountDeletion] Failed to queue cleanup of user %s, running it now: %v", user.ID, err)
+	}
+	if err := cleanup(context.Background()); err != nil {
+		log.Printf("[AccountDeletion] %v", err)
+	}
+	return nil
+}
+
+// storageDeletions lists what a deleted user had in storage: the recordings,
+// with their low-bitrate variants, and the prefixes of its decks and reports.
+// They are recorded under the account being deleted, ownerID.
+func storageDeletions(ownerID, userID uuid.UUID, keys []string) []models.PendingStorageDeletion {
+	var deletions []models.PendingStorageDeletion
+	for _, key := range keys {
+		for _, k := range []string{key, models.LowBitrateAudioKey(key)} {
+			if k != "" {
+				deletions = append(deletions, models.PendingStorageDeletion{UserID: ownerID, Key: k})
+			}
+		}
+	}
+	for _, prefix := range []string{ankiExportPrefix(userID), pronunciationReportPrefix(userID)} {
+		deletions = append(deletions, models.PendingStorageDeletion{UserID: ownerID, Key: prefix, Prefix: true})
+	}
+	return deletions
+}
+
+// Start retries stored objects of deleted accounts that are still to be
+// deleted, until ctx is cancelled
+func (s *AccountDeletionService) Start(ctx context.Context) {
+	log.Printf("[AccountDeletion] Retrying pending storage deletions every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep tries a batch of pending storage deletions, least recently tried
+// first
+func (s *AccountDeletionService) Sweep(ctx context.Context) {
+	pending, err := s.storageRepo.FindPending(s.exec, s.now().Add(-storageSweepGrace), storageSweepBatchSize)
+	if err != nil {
+		log.Printf("[AccountDeletion] Failed to find pending storage deletions: %v", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+	if err := s.deleteObjects(ctx, pending); err != nil {
+		log.Printf("[AccountDeletion] %v", err)
+	}
+}
+
+// deleteObjects deletes the objects and prefixes of pending deletions,
+// removing each record once its objects are gone. It carries on past
+// failures, which stay recorded to be retried, and reports how many were
+// left.
+func (s *AccountDeletionService) deleteObjects(ctx context.Context, deletions []models.PendingStorageDeletion) error {
+	left := 0
+	for _, d := range deletions {
+		var err error
+		if d.Prefix {
+			_, err = s.storage.DeletePrefix(ctx, d.Key)
+		} else {
+			err = s.storage.DeleteAudio(ctx, d.Key)
+		}
+		if err != nil {
+			log.Printf("[AccountDeletion] Failed to delete %s of user %s: %v", d.Key, d.UserID, err)
+			if err := s.storageRepo.RecordFailure(s.exec, d.ID, err.Error(), s.now()); err != nil {
+				log.Printf("[AccountDeletion] Failed to record failed deletion of %s: %v", d.Key, err)
+			}
+			left++
+			continue
+		}
+		if err := s.storageRepo.Delete(s.exec, d.ID); err != nil {
+			log.Printf("[AccountDeletion] Failed to clear deletion of %s: %v", d.Key, err)
+		}
+	}
+
+	if left > 0 {
+		return fmt.Errorf("%d stored objects of deleted accounts could not be deleted and will be retried", left)
+	}
+	return nil
+}
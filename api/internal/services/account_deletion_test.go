@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+// fakeCanceller records which users' subscriptions were cancelled
+type fakeCanceller struct {
+	err       error
+	cancelled []uuid.UUID
+}
+
+func (f *fakeCanceller) CancelForDeletion(userID uuid.UUID) error {
+	f.cancelled = append(f.cancelled, userID)
+	return f.err
+}
+
+func TestAccountDeletionService_DeleteAccount(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "ana@example.com"}
+	now := func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	t.Run("deletes the account, accounts merged into it and their stored objects", func(t *testing.T) {
+		tombstone := models.User{ID: uuid.New()}
+		userRepo := new(repomocks.MockUserRepository)
+		guestRepo := new(repomocks.MockGuestRepository)
+		storageRepo := new(repomocks.MockStorageDeletionRepository)
+		storage := new(clientmocks.MockStorageClient)
+		txRunner := new(mockTxRunner)
+		billing := &fakeCanceller{}
+
+		userRepo.On("FindMergedInto", mock.Anything, user.ID).Return([]models.User{tombstone}, nil)
+		guestRepo.On("FindAudioKeys", mock.Anything, user.ID).Return([]string{"v2/user/t/m", "v2/assistant/t/r"}, nil)
+		guestRepo.On("FindAudioKeys", mock.Anything, tombstone.ID).Return([]string{"v2/user/t2/m2"}, nil)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		var deleted []uuid.UUID
+		guestRepo.On("DeleteUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			deleted = append(deleted, args.Get(1).(uuid.UUID))
+		}).Return(nil)
+		var recorded []models.PendingStorageDeletion
+		storageRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = args.Get(1).([]models.PendingStorageDeletion)
+		}).Return(nil)
+		storageRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		var keys []string
+		for _, key := range []string{"v2/user/t/m", "v2/assistant/t/r", models.LowBitrateAudioKey("v2/assistant/t/r"), "v2/user/t2/m2"} {
+			if key != "" {
+				storage.On("DeleteAudio", mock.Anything, key).Return(nil)
+				keys = append(keys, key)
+			}
+		}
+		for _, id := range []uuid.UUID{user.ID, tombstone.ID} {
+			storage.On("DeletePrefix", mock.Anything, ankiExportPrefix(id)).Return(1, nil)
+			storage.On("DeletePrefix", mock.Anything, pronunciationReportPrefix(id)).Return(0, nil)
+			keys = append(keys, ankiExportPrefix(id), pronunciationReportPrefix(id))
+		}
+
+		service := NewAccountDeletionServiceForTest(nil, txRunner, userRepo, guestRepo, storageRepo, billing, storage, now)
+		require.NoError(t, service.DeleteAccount(user))
+
+		assert.Equal(t, []uuid.UUID{user.ID}, billing.cancelled)
+		assert.Equal(t, []uuid.UUID{tombstone.ID, user.ID}, deleted)
+		recordedKeys := make([]string, len(recorded))
+		for i, d := range recorded {
+			assert.Equal(t, user.ID, d.UserID)
+			recordedKeys[i] = d.Key
+		}
+		assert.ElementsMatch(t, keys, recordedKeys)
+		storage.AssertExpectations(t)
+		storageRepo.AssertNumberOfCalls(t, "Delete", len(recorded))
+	})
+
+	t.Run("keeps everything if the subscription can't be cancelled", func(t *testing.T) {
+		userRepo := new(repomocks.MockUserRepository)
+		guestRepo := new(repomocks.MockGuestRepository)
+		storageRepo := new(repomocks.MockStorageDeletionRepository)
+		storage := new(clientmocks.MockStorageClient)
+		billing := &fakeCanceller{err: errors.New("stripe down")}
+
+		service := NewAccountDeletionServiceForTest(nil, new(mockTxRunner), userRepo, guestRepo, storageRepo, billing, storage, now)
+		err := service.DeleteAccount(user)
+
+		assert.Error(t, err)
+		guestRepo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+		storageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		storage.AssertNotCalled(t, "DeleteAudio", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a stored object that can't be deleted stays recorded for a retry", func(t *testing.T) {
+		userRepo := new(repomocks.MockUserRepository)
+		guestRepo := new(repomocks.MockGuestRepository)
+		storageRepo := new(repomocks.MockStorageDeletionRepository)
+		storage := new(clientmocks.MockStorageClient)
+		txRunner := new(mockTxRunner)
+
+		userRepo.On("FindMergedInto", mock.Anything, user.ID).Return(nil, nil)
+		guestRepo.On("FindAudioKeys", mock.Anything, user.ID).Return([]string{"v2/user/t/m"}, nil)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		guestRepo.On("DeleteUser", mock.Anything, user.ID).Return(nil)
+		storageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		storageRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		storageRepo.On("RecordFailure", mock.Anything, mock.Anything, "storage down", now()).Return(nil)
+		storage.On("DeleteAudio", mock.Anything, "v2/user/t/m").Return(errors.New("storage down"))
+		storage.On("DeletePrefix", mock.Anything, mock.Anything).Return(0, nil)
+
+		service := NewAccountDeletionServiceForTest(nil, txRunner, userRepo, guestRepo, storageRepo, &fakeCanceller{}, storage, now)
+
+		assert.NoError(t, service.DeleteAccount(user))
+		guestRepo.AssertExpectations(t)
+		storageRepo.AssertNumberOfCalls(t, "RecordFailure", 1)
+		storageRepo.AssertNumberOfCalls(t, "Delete", 2)
+	})
+}
+
+func TestAccountDeletionService_Sweep(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	done := models.PendingStorageDeletion{ID: uuid.New(), UserID: userID, Key: "v2/user/t/m"}
+	failing := models.PendingStorageDeletion{ID: uuid.New(), UserID: userID, Key: ankiExportPrefix(userID), Prefix: true, Attempts: 2}
+
+	storageRepo := new(repomocks.MockStorageDeletionRepository)
+	storage := new(clientmocks.MockStorageClient)
+	storageRepo.On("FindPending", mock.Anything, now.Add(-storageSweepGrace), storageSweepBatchSize).
+		Return([]models.PendingStorageDeletion{done, failing}, nil)
+	storage.On("DeleteAudio", mock.Anything, done.Key).Return(nil)
+	storage.On("DeletePrefix", mock.Anything, failing.Key).Return(0, errors.New("storage down"))
+	storageRepo.On("Delete", mock.Anything, done.ID).Return(nil)
+	storageRepo.On("RecordFailure", mock.Anything, failing.ID, "storage down", now).Return(nil)
+
+	service := NewAccountDeletionServiceForTest(nil, nil, nil, nil, storageRepo, nil, storage, func() time.Time { return now })
+	service.Sweep(context.Background())
+
+	storageRepo.AssertExpectations(t)
+	storage.AssertExpectations(t)
+}
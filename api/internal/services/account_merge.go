@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditActionAccountMerged is the audit log action for a merged account
+const AuditActionAccountMerged = "account.merged"
+
+var (
+	ErrMergeSameAccount    = errors.New("an account can't be merged into itself")
+	ErrMergeNotAllowed     = errors.New("guest, admin and already merged accounts can't be merged")
+	ErrMergeBothSubscribed = errors.New("both accounts have a paid plan; cancel one before merging")
+)
+
+// AccountMergeResult reports what a merge moved onto the surviving account
+type AccountMergeResult struct {
+	FromUserID uuid.UUID `json:"fromUserId"`
+	IntoUserID uuid.UUID `json:"intoUserId"`
+
+	Moved             map[string]int64 `json:"moved"`            // rows per table
+	PhonemesCombined  int64            `json:"phonemesCombined"` // stats rows added into existing ones
+	SingletonsMoved   []string         `json:"singletonsMoved"`
+	CreditsMoved      int              `json:"creditsMoved"`
+	SubscriptionMoved bool             `json:"subscriptionMoved"`
+	LinkedProviders   []string         `json:"linkedProviders"` // sign-in providers moved over
+}
+
+// AccountMerger defines the interface for merging duplicate accounts
+type AccountMerger interface {
+	Merge(fromUserID, intoUserID, actor uuid.UUID) (*AccountMergeResult, error)
+}
+
+// AccountMergeService folds a duplicate account into the one its owner keeps,
+// e.g. after they signed up with email and later with Google under another
+// address. The duplicate is left as a credential-less tombstone pointing at
+// the survivor.
+type AccountMergeService struct {
+	txRunner     TxRunner
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	creditsRepo  repository.CreditsRepository
+	creditTxRepo repository.CreditTransactionRepository
+	subRepo      repository.SubscriptionRepository
+	mergeRepo    repository.AccountMergeRepository
+	auditRepo    repository.AuditLogRepository
+
+	now func() time.Time
+}
+
+// NewAccountMergeService creates a new account merge service
+func NewAccountMergeService(
+	database *db.DB,
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	creditsRepo repository.CreditsRepository,
+	creditTxRepo repository.CreditTransactionRepository,
+	subRepo repository.SubscriptionRepository,
+	mergeRepo repository.AccountMergeRepository,
+	auditRepo repository.AuditLogRepository,
+) *AccountMergeService {
+	return &AccountMergeService{
+		txRunner:     database.DB,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		creditsRepo:  creditsRepo,
+		creditTxRepo: creditTxRepo,
+		subRepo:      subRepo,
+		mergeRepo:    mergeRepo,
+		auditRepo:    auditRepo,
+		now:          time.Now,
+	}
+}
+
+// NewAccountMergeServiceForTest creates an AccountMergeService with injected dependencies for testing.
+func NewAccountMergeServiceForTest(
+	txRunner TxRunner,
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	creditsRepo repository.CreditsRepository,
+	creditTxRepo repository.CreditTransactionRepository,
+	subRepo repository.SubscriptionRepository,
+	mergeRepo repository.AccountMergeRepository,
+	auditRepo repository.AuditLogRepository,
+	now func() time.Time,
+) *AccountMergeService {
+	return &AccountMergeService{
+		txRunner:     txRunner,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		creditsRepo:  creditsRepo,
+		creditTxRepo: creditTxRepo,
+		subRepo:      subRepo,
+		mergeRepo:    mergeRepo,
+		auditRepo:    auditRepo,
+		now:          now,
+	}
+}
+
+// Merge moves everything of fromUserID onto intoUserID in one transaction,
+// audit record included: threads with their messages, credit history and
+// balance, stats, notifications and events. Phoneme counts are added up;
+// settings, profile, streak and badge move only where the survivor has none.
+// A paid plan moves if the survivor has none, and two paid plans are refused.
+// Google and GitHub sign-ins move to the survivor when it has none of its own.
+// The duplicate is signed out and keeps only its email, signup signals and
+// content key.
+func (s *AccountMergeService) Merge(fromUserID, intoUserID, actor uuid.UUID) (*AccountMergeResult, error) {
+	if fromUserID == intoUserID {
+		return nil, ErrMergeSameAccount
+	}
+
+	result := &AccountMergeResult{FromUserID: fromUserID, IntoUserID: intoUserID, LinkedProviders: []string{}}
+	err := s.txRunner.Transaction(func(tx *gorm.DB) error {
+		from, err := s.userRepo.FindByID(tx, fromUserID)
+		if err != nil {
+			return fmt.Errorf("find merged account: %w", err)
+		}
+		into, err := s.userRepo.FindByID(tx, intoUserID)
+		if err != nil {
+			return fmt.Errorf("find surviving account: %w", err)
+		}
+		if from.IsGuest() || from.IsMerged() || from.IsAdmin() || into.IsGuest() || into.IsMerged() {
+			return ErrMergeNotAllowed
+		}
+
+		if result.SubscriptionMoved, err = s.mergeSubscription(tx, fromUserID, intoUserID); err != nil {
+			return err
+		}
+		if result.Moved, err = s.mergeRepo.MoveOwned(tx, fromUserID, intoUserID); err != nil {
+			return fmt.Errorf("move rows: %w", err)
+		}
+		if result.CreditsMoved, err = s.mergeCredits(tx, from, intoUserID, result.SubscriptionMoved); err != nil {
+			return err
+		}
+		if result.PhonemesCombined, err = s.mergeRepo.MergePhonemeStats(tx, fromUserID, intoUserID); err != nil {
+			return fmt.Errorf("merge phoneme stats: %w", err)
+		}
+		if result.SingletonsMoved, err = s.mergeRepo.MoveSingletons(tx, fromUserID, intoUserID); err != nil {
+			return fmt.Errorf("move settings: %w", err)
+		}
+
+		if err := s.retire(tx, from, into, result); err != nil {
+			return err
+		}
+		if err := s.sessionRepo.DeleteByUserID(tx, fromUserID); err != nil {
+			return fmt.Errorf("revoke sessions: %w", err)
+		}
+
+		return s.auditRepo.Create(tx, &models.AuditLog{
+			Action:  AuditActionAccountMerged,
+			Actor:   actor.String(),
+			Outcome: models.AuditOutcomeSuccess,
+			Details: models.JSONMap{
+				"fromUserId":        fromUserID.String(),
+				"fromEmail":         from.Email,
+				"intoUserId":        intoUserID.String(),
+				"moved":             result.Moved,
+				"phonemesCombined":  result.PhonemesCombined,
+				"singletonsMoved":   result.SingletonsMoved,
+				"creditsMoved":      result.CreditsMoved,
+				"subscriptionMoved": result.SubscriptionMoved,
+				"linkedProviders":   result.LinkedProviders,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[AccountMerge] Merged user %s into %s (%d threads, %d credits)", fromUserID, intoUserID, result.Moved["threads"], result.CreditsMoved)
+	return result, nil
+}
+
+// mergeSubscription hands the duplicate's paid plan to the survivor if the
+// survivor has none. A free or cancelled subscription stays with the duplicate.
+func (s *AccountMergeService) mergeSubscription(tx repository.Executor, fromUserID, intoUserID uuid.UUID) (bool, error) {
+	from, err := s.subRepo.FindByUserID(tx, fromUserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("find merged subscription: %w", err)
+	}
+	if !from.IsPaid() {
+		return false, nil
+	}
+
+	into, err := s.subRepo.FindByUserID(tx, intoUserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return false, fmt.Errorf("find surviving subscription: %w", err)
+	}
+	if err == nil && into.IsPaid() {
+		return false, ErrMergeBothSubscribed
+	}
+	if err := s.mergeRepo.MoveSubscription(tx, fromUserID, intoUserID); err != nil {
+		return false, fmt.Errorf("move subscription: %w", err)
+	}
+	return true, nil
+}
+
+// mergeCredits adds the duplicate's balance to the survivor's, recorded as
+// one credit transaction, and takes the duplicate's allowance along with its
+// plan. The duplicate's history has already moved.
+func (s *AccountMergeService) mergeCredits(tx repository.Executor, from *models.User, intoUserID uuid.UUID, planMoved bool) (int, error) {
+	fromCredits, err := s.creditsRepo.FindByUserIDForUpdate(tx, from.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("find merged credits: %w", err)
+	}
+	intoCredits, err := s.creditsRepo.FindByUserIDForUpdate(tx, intoUserID)
+	if err != nil {
+		return 0, fmt.Errorf("find surviving credits: %w", err)
+	}
+
+	moved := max(fromCredits.Balance, 0)
+	if moved == 0 && !planMoved {
+		return 0, nil
+	}
+	intoCredits.Balance += moved
+	if planMoved {
+		intoCredits.MonthlyAllowance = fromCredits.MonthlyAllowance
+	}
+	if err := s.creditsRepo.Save(tx, intoCredits); err != nil {
+		return 0, fmt.Errorf("save surviving credits: %w", err)
+	}
+	if moved == 0 {
+		return 0, nil
+	}
+
+	fromCredits.Balance = 0
+	if err := s.creditsRepo.Save(tx, fromCredits); err != nil {
+		return 0, fmt.Errorf("save merged credits: %w", err)
+	}
+	reference := from.ID.String()
+	err = s.creditTxRepo.Create(tx, &models.CreditTransaction{
+		UserID:       intoUserID,
+		Type:         models.TransactionCredit,
+		Amount:       moved,
+		BalanceAfter: intoCredits.Balance,
+		Reference:    &reference,
+		Description:  "Credits from merged account " + from.Email,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("record merged credits: %w", err)
+	}
+	return moved, nil
+}
+
+// retire moves the duplicate's sign-in providers to the survivor where it
+// has none, and leaves the duplicate without credentials, pointing at the
+// survivor. The duplicate is saved first to free the provider IDs.
+func (s *AccountMergeService) retire(tx repository.Executor, from, into *models.User, result *AccountMergeResult) error {
+	googleID, gitHubID := from.GoogleID, from.GitHubID
+	now := s.now()
+	from.PasswordHash = nil
+	from.GoogleID = nil
+	from.GitHubID = nil
+	from.MergedIntoID = &into.ID
+	from.MergedAt = &now
+	if err := s.userRepo.Save(tx, from); err != nil {
+		return fmt.Errorf("retire merged account: %w", err)
+	}
+
+	linked := false
+	if googleID != nil && into.GoogleID == nil {
+		into.GoogleID = googleID
+		result.LinkedProviders = append(result.LinkedProviders, "google")
+		linked = true
+	}
+	if gitHubID != nil && into.GitHubID == nil {
+		into.GitHubID = gitHubID
+		result.LinkedProviders = append(result.LinkedProviders, "github")
+		linked = true
+	}
+	if !linked {
+		return nil
+	}
+	if err := s.userRepo.Save(tx, into); err != nil {
+		return fmt.Errorf("link sign-in providers: %w", err)
+	}
+	return nil
+}
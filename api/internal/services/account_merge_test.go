@@ -0,0 +1,172 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+type accountMergeMocks struct {
+	users    *repomocks.MockUserRepository
+	sessions *repomocks.MockSessionRepository
+	credits  *repomocks.MockCreditsRepository
+	creditTx *repomocks.MockCreditTransactionRepository
+	subs     *repomocks.MockSubscriptionRepository
+	merges   *repomocks.MockAccountMergeRepository
+	audit    *repomocks.MockAuditLogRepository
+}
+
+func newAccountMergeTest(now time.Time) (*AccountMergeService, *accountMergeMocks) {
+	m := &accountMergeMocks{
+		users:    new(repomocks.MockUserRepository),
+		sessions: new(repomocks.MockSessionRepository),
+		credits:  new(repomocks.MockCreditsRepository),
+		creditTx: new(repomocks.MockCreditTransactionRepository),
+		subs:     new(repomocks.MockSubscriptionRepository),
+		merges:   new(repomocks.MockAccountMergeRepository),
+		audit:    new(repomocks.MockAuditLogRepository),
+	}
+	txRunner := new(mockTxRunner)
+	txRunner.On("Transaction", mock.Anything).Return(nil)
+	service := NewAccountMergeServiceForTest(txRunner, m.users, m.sessions, m.credits, m.creditTx, m.subs, m.merges, m.audit, func() time.Time { return now })
+	return service, m
+}
+
+func TestAccountMergeService_Merge(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	actor := uuid.New()
+
+	t.Run("moves data, credits, plan and sign-in onto the survivor", func(t *testing.T) {
+		service, m := newAccountMergeTest(now)
+		googleID := "google-123"
+		hash := "hash"
+		from := &models.User{ID: uuid.New(), Email: "ana.work@example.com", GoogleID: &googleID, PasswordHash: &hash}
+		into := &models.User{ID: uuid.New(), Email: "ana@example.com"}
+		subscriptionID := "sub_123"
+
+		m.users.On("FindByID", mock.Anything, from.ID).Return(from, nil)
+		m.users.On("FindByID", mock.Anything, into.ID).Return(into, nil)
+		m.subs.On("FindByUserID", mock.Anything, from.ID).Return(&models.Subscription{UserID: from.ID, Tier: models.TierPro, StripeSubscriptionID: &subscriptionID}, nil)
+		m.subs.On("FindByUserID", mock.Anything, into.ID).Return(&models.Subscription{UserID: into.ID, Tier: models.TierFree}, nil)
+		m.merges.On("MoveSubscription", mock.Anything, from.ID, into.ID).Return(nil)
+		m.merges.On("MoveOwned", mock.Anything, from.ID, into.ID).Return(map[string]int64{"threads": 3}, nil)
+		m.merges.On("MergePhonemeStats", mock.Anything, from.ID, into.ID).Return(int64(4), nil)
+		m.merges.On("MoveSingletons", mock.Anything, from.ID, into.ID).Return([]string{"learner_profiles"}, nil)
+
+		fromCredits := &models.Credits{UserID: from.ID, Balance: 150, MonthlyAllowance: 1200}
+		intoCredits := &models.Credits{UserID: into.ID, Balance: 10, MonthlyAllowance: 20}
+		m.credits.On("FindByUserIDForUpdate", mock.Anything, from.ID).Return(fromCredits, nil)
+		m.credits.On("FindByUserIDForUpdate", mock.Anything, into.ID).Return(intoCredits, nil)
+		m.credits.On("Save", mock.Anything, mock.Anything).Return(nil)
+		m.creditTx.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.UserID == into.ID && tx.Amount == 150 && tx.BalanceAfter == 160
+		})).Return(nil)
+
+		m.users.On("Save", mock.Anything, from).Return(nil)
+		m.users.On("Save", mock.Anything, into).Return(nil)
+		m.sessions.On("DeleteByUserID", mock.Anything, from.ID).Return(nil)
+		m.audit.On("Create", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+			return entry.Action == AuditActionAccountMerged && entry.Actor == actor.String() && entry.Details["fromUserId"] == from.ID.String()
+		})).Return(nil)
+
+		result, err := service.Merge(from.ID, into.ID, actor)
+
+		require.NoError(t, err)
+		assert.True(t, result.SubscriptionMoved)
+		assert.Equal(t, 150, result.CreditsMoved)
+		assert.Equal(t, int64(3), result.Moved["threads"])
+		assert.Equal(t, []string{"google"}, result.LinkedProviders)
+
+		assert.Equal(t, 160, intoCredits.Balance)
+		assert.Equal(t, 1200, intoCredits.MonthlyAllowance, "the allowance follows the plan")
+		assert.Equal(t, 0, fromCredits.Balance)
+
+		assert.Equal(t, &googleID, into.GoogleID)
+		assert.Nil(t, from.GoogleID)
+		assert.Nil(t, from.PasswordHash)
+		assert.Equal(t, &into.ID, from.MergedIntoID)
+		assert.Equal(t, now, *from.MergedAt)
+		m.sessions.AssertExpectations(t)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("keeps the survivor's own sign-in and plan", func(t *testing.T) {
+		service, m := newAccountMergeTest(now)
+		fromGoogle, intoGoogle := "google-1", "google-2"
+		from := &models.User{ID: uuid.New(), GoogleID: &fromGoogle}
+		into := &models.User{ID: uuid.New(), GoogleID: &intoGoogle}
+
+		m.users.On("FindByID", mock.Anything, from.ID).Return(from, nil)
+		m.users.On("FindByID", mock.Anything, into.ID).Return(into, nil)
+		m.subs.On("FindByUserID", mock.Anything, from.ID).Return(nil, repository.ErrNotFound)
+		m.merges.On("MoveOwned", mock.Anything, from.ID, into.ID).Return(map[string]int64{}, nil)
+		m.credits.On("FindByUserIDForUpdate", mock.Anything, from.ID).Return(&models.Credits{UserID: from.ID}, nil)
+		m.credits.On("FindByUserIDForUpdate", mock.Anything, into.ID).Return(&models.Credits{UserID: into.ID, Balance: 5}, nil)
+		m.merges.On("MergePhonemeStats", mock.Anything, from.ID, into.ID).Return(int64(0), nil)
+		m.merges.On("MoveSingletons", mock.Anything, from.ID, into.ID).Return([]string{}, nil)
+		m.users.On("Save", mock.Anything, from).Return(nil)
+		m.sessions.On("DeleteByUserID", mock.Anything, from.ID).Return(nil)
+		m.audit.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		result, err := service.Merge(from.ID, into.ID, actor)
+
+		require.NoError(t, err)
+		assert.False(t, result.SubscriptionMoved)
+		assert.Empty(t, result.LinkedProviders)
+		assert.Equal(t, &intoGoogle, into.GoogleID)
+		m.users.AssertNotCalled(t, "Save", mock.Anything, into)
+		m.credits.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses two paid plans", func(t *testing.T) {
+		service, m := newAccountMergeTest(now)
+		from := &models.User{ID: uuid.New()}
+		into := &models.User{ID: uuid.New()}
+
+		m.users.On("FindByID", mock.Anything, from.ID).Return(from, nil)
+		m.users.On("FindByID", mock.Anything, into.ID).Return(into, nil)
+		m.subs.On("FindByUserID", mock.Anything, from.ID).Return(&models.Subscription{Tier: models.TierBasic}, nil)
+		m.subs.On("FindByUserID", mock.Anything, into.ID).Return(&models.Subscription{Tier: models.TierPro}, nil)
+
+		_, err := service.Merge(from.ID, into.ID, actor)
+
+		assert.ErrorIs(t, err, ErrMergeBothSubscribed)
+		m.merges.AssertNotCalled(t, "MoveOwned", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses guests, admins and merged accounts", func(t *testing.T) {
+		expiresAt := now.Add(time.Hour)
+		mergedInto := uuid.New()
+		for name, from := range map[string]*models.User{
+			"guest":  {ID: uuid.New(), GuestExpiresAt: &expiresAt},
+			"admin":  {ID: uuid.New(), Role: models.RoleAdmin},
+			"merged": {ID: uuid.New(), MergedIntoID: &mergedInto},
+		} {
+			service, m := newAccountMergeTest(now)
+			into := &models.User{ID: uuid.New()}
+			m.users.On("FindByID", mock.Anything, from.ID).Return(from, nil)
+			m.users.On("FindByID", mock.Anything, into.ID).Return(into, nil)
+
+			_, err := service.Merge(from.ID, into.ID, actor)
+
+			assert.ErrorIs(t, err, ErrMergeNotAllowed, name)
+		}
+	})
+
+	t.Run("refuses merging an account into itself", func(t *testing.T) {
+		service, _ := newAccountMergeTest(now)
+		id := uuid.New()
+
+		_, err := service.Merge(id, id, actor)
+
+		assert.ErrorIs(t, err, ErrMergeSameAccount)
+	})
+}
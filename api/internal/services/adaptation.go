@@ -0,0 +1,199 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+)
+
+// AdaptationLevel is how the assistant pitches its next reply
+type AdaptationLevel string
+
+const (
+	AdaptSimplify  AdaptationLevel = "simplify"  // learner is struggling
+	AdaptSteady    AdaptationLevel = "steady"    // no change
+	AdaptChallenge AdaptationLevel = "challenge" // learner is cruising
+)
+
+// Adaptation thresholds over the learner's last adaptationWindow turns. Each
+// metric votes struggling, cruising or neither; metrics without data abstain.
+const (
+	adaptationWindow   = 3
+	adaptationMinTurns = 2
+
+	struggleWER            = 0.4
+	cruiseWER              = 0.1
+	struggleLatencySeconds = 15.0
+	cruiseLatencySeconds   = 5.0
+	struggleWords          = 3.0
+	cruiseWords            = 12.0
+
+	// Longer gaps mean the learner stepped away, not that they were stuck
+	maxLatencySeconds = 120.0
+
+	// SimplifiedSpeechRate slows the reply's audio when the learner struggles
+	SimplifiedSpeechRate = 0.85
+)
+
+// TurnMetrics are rolling averages over the learner's recent turns. WER is
+// only known in practice mode, against the line the learner was asked to
+// say; latency is the time from the previous reply to the learner speaking.
+type TurnMetrics struct {
+	Turns          int
+	WER            *float64
+	LatencySeconds *float64
+	Words          float64
+}
+
+// Adaptation is the decision for one assistant reply
+type Adaptation struct {
+	Level      AdaptationLevel
+	Reasons    []string
+	Metrics    TurnMetrics
+	SpeechRate float64 // 1.0 is normal speed
+}
+
+// AdaptationService tunes the difficulty of each reply to how the learner is
+// doing: when they struggle the assistant simplifies and speaks slower, and
+// when they are cruising it raises the complexity
+type AdaptationService struct{}
+
+// NewAdaptationService creates a new adaptation service
+func NewAdaptationService() *AdaptationService {
+	return &AdaptationService{}
+}
+
+// Decide picks the level for the next reply from the thread's messages so far
+func (s *AdaptationService) Decide(messages []models.Message) Adaptation {
+	metrics := RollingTurnMetrics(messages)
+	decision := Adaptation{Level: AdaptSteady, Metrics: metrics, SpeechRate: 1.0}
+	if metrics.Turns < adaptationMinTurns {
+		return decision
+	}
+
+	var struggling, cruising []string
+	if metrics.WER != nil {
+		switch {
+		case *metrics.WER >= struggleWER:
+			struggling = append(struggling, fmt.Sprintf("word error rate %.0f%%", *metrics.WER*100))
+		case *metrics.WER <= cruiseWER:
+			cruising = append(cruising, fmt.Sprintf("word error rate %.0f%%", *metrics.WER*100))
+		}
+	}
+	if metrics.LatencySeconds != nil {
+		switch {
+		case *metrics.LatencySeconds >= struggleLatencySeconds:
+			struggling = append(struggling, fmt.Sprintf("%.0fs to respond", *metrics.LatencySeconds))
+		case *metrics.LatencySeconds <= cruiseLatencySeconds:
+			cruising = append(cruising, fmt.Sprintf("%.0fs to respond", *metrics.LatencySeconds))
+		}
+	}
+	switch {
+	case metrics.Words <= struggleWords:
+		struggling = append(struggling, fmt.Sprintf("%.1f words per message", metrics.Words))
+	case metrics.Words >= cruiseWords:
+		cruising = append(cruising, fmt.Sprintf("%.1f words per message", metrics.Words))
+	}
+
+	// Struggling needs two signals; cruising needs two and none against it
+	switch {
+	case len(struggling) >= 2 && len(struggling) > len(cruising):
+		decision.Level = AdaptSimplify
+		decision.Reasons = struggling
+		decision.SpeechRate = SimplifiedSpeechRate
+	case len(cruising) >= 2 && len(struggling) == 0:
+		decision.Level = AdaptChallenge
+		decision.Reasons = cruising
+	}
+	return decision
+}
+
+// RollingTurnMetrics averages the learner's last adaptationWindow turns
+func RollingTurnMetrics(messages []models.Message) TurnMetrics {
+	var metrics TurnMetrics
+	var werSum, latencySum, wordSum float64
+	var werCount, latencyCount int
+
+	for i := len(messages) - 1; i >= 0 && metrics.Turns < adaptationWindow; i-- {
+		msg := messages[i]
+		if msg.Role != "user" {
+			continue
+		}
+		metrics.Turns++
+		wordSum += float64(len(strings.Fields(msg.Content)))
+
+		if msg.ExpectedText != nil && strings.TrimSpace(*msg.ExpectedText) != "" {
+			werSum += WordErrorRate(*msg.ExpectedText, msg.Content)
+			werCount++
+		}
+
+		// Time from the reply before this turn to the learner finishing speaking,
+		// less the recording itself
+		if i > 0 && messages[i-1].Role == "assistant" {
+			latency := msg.Timestamp.Sub(messages[i-1].Timestamp).Seconds()
+			if msg.AudioDurationSeconds != nil {
+				latency -= *msg.AudioDurationSeconds
+			}
+			if latency >= 0 && latency <= maxLatencySeconds {
+				latencySum += latency
+				latencyCount++
+			}
+		}
+	}
+
+	if metrics.Turns > 0 {
+		metrics.Words = wordSum / float64(metrics.Turns)
+	}
+	if werCount > 0 {
+		wer := werSum / float64(werCount)
+		metrics.WER = &wer
+	}
+	if latencyCount > 0 {
+		latency := latencySum / float64(latencyCount)
+		metrics.LatencySeconds = &latency
+	}
+	return metrics
+}
+
+// SystemPrompt returns the instruction for the LLM, or nil at AdaptSteady
+func (a Adaptation) SystemPrompt() *client.ConversationMessage {
+	var content string
+	switch a.Level {
+	case AdaptSimplify:
+		content = "The learner is struggling. Keep your next reply short: simple sentences, common words, " +
+			"present tense where you can, and one easy question at a time. If they seem stuck, offer a word or two they could use."
+	case AdaptChallenge:
+		content = "The learner is comfortable at this level. Make your next reply a little more challenging: " +
+			"richer vocabulary, longer sentences, varied tenses and an open-ended question."
+	default:
+		return nil
+	}
+	return &client.ConversationMessage{Role: "system", Content: content}
+}
+
+// Details records the decision on the assistant message
+func (a Adaptation) Details() models.JSONMap {
+	details := models.JSONMap{
+		"level":      string(a.Level),
+		"turns":      a.Metrics.Turns,
+		"words":      round2(a.Metrics.Words),
+		"speechRate": a.SpeechRate,
+	}
+	if len(a.Reasons) > 0 {
+		details["reasons"] = a.Reasons
+	}
+	if a.Metrics.WER != nil {
+		details["wer"] = round2(*a.Metrics.WER)
+	}
+	if a.Metrics.LatencySeconds != nil {
+		details["latencySeconds"] = round2(*a.Metrics.LatencySeconds)
+	}
+	return details
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+)
+
+// exchange returns an assistant prompt and the learner's answer latency
+// seconds later
+func exchange(at time.Time, latency float64, answer string) []models.Message {
+	return []models.Message{
+		{Role: "assistant", Content: "¿Y tú?", Timestamp: at},
+		{Role: "user", Content: answer, Timestamp: at.Add(time.Duration(latency * float64(time.Second)))},
+	}
+}
+
+func TestAdaptationService_Decide(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewAdaptationService()
+
+	t.Run("simplifies for short, slow answers", func(t *testing.T) {
+		messages := append(exchange(start, 25, "no sé"), exchange(start.Add(time.Minute), 20, "sí")...)
+
+		decision := svc.Decide(messages)
+		assert.Equal(t, AdaptSimplify, decision.Level)
+		assert.Equal(t, SimplifiedSpeechRate, decision.SpeechRate)
+		assert.Len(t, decision.Reasons, 2)
+		require.NotNil(t, decision.SystemPrompt())
+	})
+
+	t.Run("challenges a learner who is cruising", func(t *testing.T) {
+		long := "ayer fui al mercado con mi hermana y compramos fruta, pan y un poco de queso para la cena"
+		messages := append(exchange(start, 3, long), exchange(start.Add(time.Minute), 4, long)...)
+
+		decision := svc.Decide(messages)
+		assert.Equal(t, AdaptChallenge, decision.Level)
+		assert.Equal(t, 1.0, decision.SpeechRate)
+	})
+
+	t.Run("practice mode counts word errors against the script", func(t *testing.T) {
+		script := "me gustaría reservar una mesa para dos personas"
+		messages := []models.Message{
+			{Role: "user", Content: "me gusta una mesa", ExpectedText: &script, Timestamp: start},
+			{Role: "user", Content: "mesa dos", ExpectedText: &script, Timestamp: start.Add(time.Minute)},
+		}
+
+		decision := svc.Decide(messages)
+		assert.Equal(t, AdaptSimplify, decision.Level)
+		require.NotNil(t, decision.Metrics.WER)
+		assert.Greater(t, *decision.Metrics.WER, struggleWER)
+		assert.Nil(t, decision.Metrics.LatencySeconds)
+	})
+
+	t.Run("waits for a couple of turns", func(t *testing.T) {
+		decision := svc.Decide(exchange(start, 40, "sí"))
+		assert.Equal(t, AdaptSteady, decision.Level)
+		assert.Nil(t, decision.SystemPrompt())
+	})
+
+	t.Run("mixed signals keep the level", func(t *testing.T) {
+		messages := append(exchange(start, 2, "sí"), exchange(start.Add(time.Minute), 3, "no")...)
+		assert.Equal(t, AdaptSteady, svc.Decide(messages).Level)
+	})
+}
+
+func TestRollingTurnMetrics(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	duration := 4.0
+
+	messages := exchange(start, 10, "uno dos")
+	messages[1].AudioDurationSeconds = &duration
+	// Stepped away for an hour: not counted as latency
+	messages = append(messages, exchange(start.Add(time.Minute), 3600, "tres cuatro cinco seis")...)
+
+	metrics := RollingTurnMetrics(messages)
+	assert.Equal(t, 2, metrics.Turns)
+	assert.Equal(t, 3.0, metrics.Words)
+	require.NotNil(t, metrics.LatencySeconds)
+	assert.Equal(t, 6.0, *metrics.LatencySeconds)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// AdminUserView is what support sees about one account
+type AdminUserView struct {
+	User    *models.User    `json:"user"`
+	Credits *models.Credits `json:"credits,omitempty"`
+	Signup  *SignupReport   `json:"signup,omitempty"` // nil for accounts created before signup checks
+}
+
+// SignupReport is an account's signup signals and the accounts they matched
+type SignupReport struct {
+	Signal          *models.SignupSignal `json:"signal"`
+	RelatedAccounts []RelatedSignup      `json:"relatedAccounts"`
+}
+
+// RelatedSignup is another account that shares a device or network with the user
+type RelatedSignup struct {
+	UserID     uuid.UUID `json:"userId"`
+	Email      string    `json:"email,omitempty"`
+	SignedUpAt time.Time `json:"signedUpAt"`
+	MatchedOn  []string  `json:"matchedOn"` // fingerprint, ip, userAgent
+	Verdict    string    `json:"verdict"`
+}
+
+// AdminUserProvider defines the interface for the admin account view
+type AdminUserProvider interface {
+	GetUser(userID uuid.UUID) (*AdminUserView, error)
+	ListUsers(query string, limit int) ([]models.User, error)
+}
+
+// AdminUserService assembles account details for admins
+type AdminUserService struct {
+	exec        repository.Executor
+	userRepo    repository.UserRepository
+	creditsRepo repository.CreditsRepository
+	signalRepo  repository.SignupSignalRepository
+}
+
+// NewAdminUserService creates a new admin user service
+func NewAdminUserService(
+	database *db.DB,
+	userRepo repository.UserRepository,
+	creditsRepo repository.CreditsRepository,
+	signalRepo repository.SignupSignalRepository,
+) *AdminUserService {
+	return &AdminUserService{
+		exec:        database.DB,
+		userRepo:    userRepo,
+		creditsRepo: creditsRepo,
+		signalRepo:  signalRepo,
+	}
+}
+
+// NewAdminUserServiceForTest creates an AdminUserService with injected dependencies for testing.
+func NewAdminUserServiceForTest(
+	exec repository.Executor,
+	userRepo repository.UserRepository,
+	creditsRepo repository.CreditsRepository,
+	signalRepo repository.SignupSignalRepository,
+) *AdminUserService {
+	return &AdminUserService{
+		exec:        exec,
+		userRepo:    userRepo,
+		creditsRepo: creditsRepo,
+		signalRepo:  signalRepo,
+	}
+}
+
+// GetUser returns the admin view of an account
+func (s *AdminUserService) GetUser(userID uuid.UUID) (*AdminUserView, error) {
+	user, err := s.userRepo.FindByID(s.exec, userID)
+	if err != nil {
+		return nil, err
+	}
+	view := &AdminUserView{User: user}
+
+	credits, err := s.creditsRepo.FindByUserID(s.exec, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get credits: %w", err)
+	}
+	view.Credits = credits
+
+	signal, err := s.signalRepo.FindByUserID(s.exec, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return view, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signup signals: %w", err)
+	}
+
+	related, err := s.relatedSignups(signal)
+	if err != nil {
+		return nil, err
+	}
+	view.Signup = &SignupReport{Signal: signal, RelatedAccounts: related}
+	return view, nil
+}
+
+// ListUsers returns the newest accounts, or with a query those whose email or
+// name contains it
+func (s *AdminUserService) ListUsers(query string, limit int) ([]models.User, error) {
+	users, err := s.userRepo.Search(s.exec, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+	return users, nil
+}
+
+// relatedSignups finds the other accounts sharing the signal's device, or its
+// network within the duplicate check window
+func (s *AdminUserService) relatedSignups(signal *models.SignupSignal) ([]RelatedSignup, error) {
+	fingerprint := ""
+	if signal.FingerprintHash != nil {
+		fingerprint = *signal.FingerprintHash
+	}
+	matches, err := s.signalRepo.FindMatching(s.exec, signal.IPAddress, fingerprint, signal.CreatedAt.Add(-signupIPWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find related signups: %w", err)
+	}
+
+	related := []RelatedSignup{}
+	for _, m := range matches {
+		if m.UserID == signal.UserID {
+			continue
+		}
+		r := RelatedSignup{
+			UserID:     m.UserID,
+			SignedUpAt: m.CreatedAt,
+			Verdict:    string(m.Verdict),
+		}
+		if fingerprint != "" && m.FingerprintHash != nil && *m.FingerprintHash == fingerprint {
+			r.MatchedOn = append(r.MatchedOn, "fingerprint")
+		}
+		if m.IPAddress == signal.IPAddress {
+			r.MatchedOn = append(r.MatchedOn, "ip")
+			if m.UserAgent == signal.UserAgent {
+				r.MatchedOn = append(r.MatchedOn, "userAgent")
+			}
+		}
+		if user, err := s.userRepo.FindByID(s.exec, m.UserID); err == nil {
+			r.Email = user.Email
+		}
+		related = append(related, r)
+	}
+	return related, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/testutil"
+	"ling-app/api/internal/testutil/factory"
+)
+
+// The analysis stored in messages.pronunciation_analysis is read back by
+// phoneme stats, reports and exports, and served to the app as is. These
+// tests pin its shape.
+
+func TestGoldenPronunciationAnalysis(t *testing.T) {
+	analysis := factory.Analysis().
+		Match("h", "ɛ").
+		Substitute("l", "w").
+		Delete("oʊ").
+		Insert("ə").
+		AudioQuality(72.5, 18, 1.4, "low_volume").
+		Quality(client.QualityFast).
+		Build()
+
+	stored, err := analysisToMap(analysis)
+	require.NoError(t, err)
+	testutil.AssertGoldenJSON(t, "pronunciation_analysis", stored)
+
+	assertReadsBack(t, stored, analysis)
+}
+
+func TestGoldenLongFormPronunciationAnalysis(t *testing.T) {
+	combined := CombineChunkAnalyses([]*client.PronunciationAnalysis{
+		factory.Analysis().Match("o", "l", "a").AudioQuality(90, 30, 10, "clipping").Build(),
+		factory.Analysis().Match("p", "e").Substitute("ɾ", "j").Match("o").AudioQuality(60, 15, 20).Build(),
+	})
+
+	stored, err := analysisToMap(combined)
+	require.NoError(t, err)
+	// As AnalyzeChunks adds them
+	stored["chunk_count"] = 3
+	stored["analyzed_chunk_count"] = 2
+	testutil.AssertGoldenJSON(t, "pronunciation_analysis_long_form", stored)
+
+	assertReadsBack(t, stored, combined)
+}
+
+// assertReadsBack checks that a stored analysis decodes to what was stored
+func assertReadsBack(t *testing.T, stored map[string]interface{}, want *client.PronunciationAnalysis) {
+	t.Helper()
+	encoded, err := json.Marshal(stored)
+	require.NoError(t, err)
+	var got client.PronunciationAnalysis
+	require.NoError(t, json.Unmarshal(encoded, &got))
+	assert.Equal(t, want, &got)
+}
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNothingToExport = errors.New("no weak phonemes to export")
+	ErrExportNotFound  = errors.New("export not found")
+)
+
+// Deck contents. A phoneme is weak once it has enough attempts to judge and
+// the user gets it wrong often; phrases are the user's own practice lines
+// where a weak phoneme went wrong.
+const (
+	AnkiMinAttempts     = 5
+	AnkiWeakAccuracy    = 80.0
+	AnkiMaxPhonemes     = 25
+	AnkiMaxPhrases      = 50
+	ankiMessageLookback = 300
+	ankiDeckName        = "Ling App::Pronunciation"
+	ankiExportTimeout   = 10 * time.Minute
+)
+
+// AnkiExporter builds practice decks in the background
+type AnkiExporter interface {
+	RequestExport(userID uuid.UUID) (uuid.UUID, error)
+	OpenExport(ctx context.Context, userID, exportID uuid.UUID) (*client.StorageObject, error)
+}
+
+// AnkiExportService turns a user's weak phonemes and flagged phrases into a
+// flashcard deck with reference audio.
+//
+// The deck is a zip holding an Anki text-import file (deck.txt) and the MP3s
+// its cards reference. A native .apkg is a SQLite database, which the API
+// has no driver for; the text format imports into Anki, AnkiDroid and most
+// other SRS apps. Phrases stand in for words because the pronunciation
+// analysis is aligned by phoneme, not by word.
+type AnkiExportService struct {
+	exec          repository.Executor
+	messageRepo   repository.MessageRepository
+	statsRepo     repository.PhonemeStatsRepository
+	subsRepo      repository.PhonemeSubstitutionRepository
+	tts           client.TTSClient
+	storage       client.StorageClient
+	notifications NotificationManager
+	queue         *jobs.Queue
+
+	// ReferenceAudio voices an example word on phoneme cards that have no
+	// phrase of the user's. Without it those cards have no audio.
+	ReferenceAudio ReferenceClipper
+}
+
+// NewAnkiExportService creates a new Anki export service
+func NewAnkiExportService(
+	database *db.DB,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+	subsRepo repository.PhonemeSubstitutionRepository,
+	tts client.TTSClient,
+	storage client.StorageClient,
+	notifications NotificationManager,
+	queue *jobs.Queue,
+) *AnkiExportService {
+	return &AnkiExportService{
+		exec:          database.DB,
+		messageRepo:   messageRepo,
+		statsRepo:     statsRepo,
+		subsRepo:      subsRepo,
+		tts:           tts,
+		storage:       storage,
+		notifications: notifications,
+		queue:         queue,
+	}
+}
+
+// NewAnkiExportServiceForTest creates an AnkiExportService with injected dependencies for testing.
+func NewAnkiExportServiceForTest(
+	exec repository.Executor,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+	subsRepo repository.PhonemeSubstitutionRepository,
+	tts client.TTSClient,
+	storage client.StorageClient,
+	notifications NotificationManager,
+	queue *jobs.Queue,
+) *AnkiExportService {
+	return &AnkiExportService{
+		exec:          exec,
+		messageRepo:   messageRepo,
+		statsRepo:     statsRepo,
+		subsRepo:      subsRepo,
+		tts:           tts,
+		storage:       storage,
+		notifications: notifications,
+		queue:         queue,
+	}
+}
+
+// ankiExportKey is where a finished deck is stored. The user ID in the key
+// is what scopes downloads to their owner.
+func ankiExportKey(userID, exportID uuid.UUID) string {
+	return fmt.Sprintf("%s%s.zip", ankiExportPrefix(userID), exportID)
+}
+
+// ankiExportPrefix is where all of a user's decks are stored
+func ankiExportPrefix(userID uuid.UUID) string {
+	return fmt.Sprintf("exports/anki/%s/", userID)
+}
+
+// AnkiExportDownloadPath is the API path a finished export is served from
+func AnkiExportDownloadPath(exportID uuid.UUID) string {
+	return "/api/practice/export/anki/" + exportID.String()
+}
+
+// RequestExport checks there is something to export and queues the build.
+// The user is notified with a download link when it is ready.
+func (s *AnkiExportService) RequestExport(userID uuid.UUID) (uuid.UUID, error) {
+	weak, err := s.weakPhonemes(userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(weak) == 0 {
+		return uuid.Nil, ErrNothingToExport
+	}
+
+	exportID := uuid.New()
+	if s.queue == nil {
+		go s.runExport(context.Background(), userID, exportID)
+		return exportID, nil
+	}
+
+	err = s.queue.Enqueue(jobs.Job{
+		Name: "anki-export:" + exportID.String(),
+		Lane: jobs.LaneStandard,
+		Run: func(ctx context.Context) error {
+			return s.runExport(ctx, userID, exportID)
+		},
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("enqueue anki export: %w", err)
+	}
+	return exportID, nil
+}
+
+// OpenExport streams a finished export. The caller must close Body.
+func (s *AnkiExportService) OpenExport(ctx context.Context, userID, exportID uuid.UUID) (*client.StorageObject, error) {
+	obj, err := s.storage.GetObject(ctx, ankiExportKey(userID, exportID), "")
+	if errors.Is(err, client.ErrObjectNotFound) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open anki export: %w", err)
+	}
+	return obj, nil
+}
+
+func (s *AnkiExportService) runExport(ctx context.Context, userID, exportID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, ankiExportTimeout)
+	defer cancel()
+
+	cardCount, err := s.Export(ctx, userID, exportID)
+	if err != nil {
+		log.Printf("[AnkiExport] Export %s for user %s failed: %v", exportID, userID, err)
+		s.notify(userID, models.NotificationExportFailed, "Your practice deck couldn't be built",
+			"Something went wrong while building your Anki deck. Please try again in a few minutes.",
+			models.JSONMap{"exportId": exportID.String()})
+		return err
+	}
+
+	s.notify(userID, models.NotificationExportReady, "Your practice deck is ready",
+		fmt.Sprintf("%d cards for the sounds you find hardest, with reference audio. Unzip it and import deck.txt into Anki.", cardCount),
+		models.JSONMap{
+			"exportId":    exportID.String(),
+			"downloadUrl": AnkiExportDownloadPath(exportID),
+			"cardCount":   cardCount,
+		})
+	return nil
+}
+
+func (s *AnkiExportService) notify(userID uuid.UUID, notificationType models.NotificationType, title, body string, data models.JSONMap) {
+	if s.notifications == nil {
+		return
+	}
+	if err := s.notifications.Notify(userID, notificationType, title, body, data); err != nil {
+		log.Printf("[AnkiExport] Failed to notify user %s: %v", userID, err)
+	}
+}
+
+// Export builds the deck and uploads it, returning the number of cards
+func (s *AnkiExportService) Export(ctx context.Context, userID, exportID uuid.UUID) (int, error) {
+	cards, err := s.buildCards(userID)
+	if err != nil {
+		return 0, err
+	}
+	if len(cards) == 0 {
+		return 0, ErrNothingToExport
+	}
+
+	archive, err := s.writeDeck(ctx, cards)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := s.storage.UploadAudio(ctx, bytes.NewReader(archive), ankiExportKey(userID, exportID), "application/zip"); err != nil {
+		return 0, fmt.Errorf("upload anki export: %w", err)
+	}
+	return len(cards), nil
+}
+
+// ankiCard is one note in the deck. Audio is the text to synthesize for the
+// back of the card; empty means no audio.
+type ankiCard struct {
+	front, back string
+	audio       string
+	reference   bool // audio is an example word from the reference library
+	tags        []string
+}
+
+func (s *AnkiExportService) weakPhonemes(userID uuid.UUID) ([]repository.PhonemeAccuracy, error) {
+	ranking, err := s.statsRepo.GetAccuracyRanking(s.exec, userID, DefaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("get phoneme ranking: %w", err)
+	}
+
+	// Ranking is weakest first
+	var weak []repository.PhonemeAccuracy
+	for _, p := range ranking {
+		if p.TotalAttempts < AnkiMinAttempts || p.Accuracy >= AnkiWeakAccuracy {
+			continue
+		}
+		weak = append(weak, p)
+		if len(weak) == AnkiMaxPhonemes {
+			break
+		}
+	}
+	return weak, nil
+}
+
+// buildCards makes a card per weak phoneme, illustrated with a phrase the
+// user got it wrong in, and a card per flagged phrase
+func (s *AnkiExportService) buildCards(userID uuid.UUID) ([]ankiCard, error) {
+	weak, err := s.weakPhonemes(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(weak) == 0 {
+		return nil, nil
+	}
+	isWeak := make(map[string]bool, len(weak))
+	for _, p := range weak {
+		isWeak[p.Phoneme] = true
+	}
+
+	subs, err := s.subsRepo.FindTopByUserID(s.exec, userID, DefaultLanguage, 100)
+	if err != nil {
+		return nil, fmt.Errorf("get substitutions: %w", err)
+	}
+	// Top substitutions come most frequent first, so the first one seen wins
+	saidAs := make(map[string]string)
+	for _, sub := range subs {
+		if _, ok := saidAs[sub.ExpectedPhoneme]; !ok {
+			saidAs[sub.ExpectedPhoneme] = sub.ActualPhoneme
+		}
+	}
+
+	phrases, err := s.flaggedPhrases(userID, isWeak)
+	if err != nil {
+		return nil, err
+	}
+	example := make(map[string]string)
+	for _, phrase := range phrases {
+		for _, p := range phrase.phonemes {
+			if _, ok := example[p]; !ok {
+				example[p] = phrase.text
+			}
+		}
+	}
+
+	cards := make([]ankiCard, 0, len(weak)+len(phrases))
+	for _, p := range weak {
+		ex, reference := example[p.Phoneme], false
+		if words := PhonemeExampleWords[p.Phoneme]; ex == "" && len(words) > 0 && s.ReferenceAudio != nil {
+			ex, reference = words[0], true
+		}
+		front := "/" + html.EscapeString(p.Phoneme) + "/"
+		if ex != "" {
+			front += "<br><i>" + html.EscapeString(ex) + "</i>"
+		}
+		back := fmt.Sprintf("Your accuracy: %.0f%% over %d attempts", p.Accuracy, p.TotalAttempts)
+		if actual := saidAs[p.Phoneme]; actual != "" {
+			back += "<br>Often said as /" + html.EscapeString(actual) + "/"
+		}
+		cards = append(cards, ankiCard{
+			front:     front,
+			back:      back,
+			audio:     ex,
+			reference: reference,
+			tags:      []string{"lingapp", "phoneme"},
+		})
+	}
+	for _, phrase := range phrases {
+		sounds := make([]string, len(phrase.phonemes))
+		for i, p := range phrase.phonemes {
+			sounds[i] = "/" + html.EscapeString(p) + "/"
+		}
+		cards = append(cards, ankiCard{
+			front: html.EscapeString(phrase.text),
+			back:  "Watch: " + strings.Join(sounds, " "),
+			audio: phrase.text,
+			tags:  []string{"lingapp", "phrase"},
+		})
+	}
+	return cards, nil
+}
+
+type flaggedPhrase struct {
+	text     string
+	phonemes []string // Weak phonemes missed in this phrase, sorted
+}
+
+// flaggedPhrases returns recent practice lines where the user substituted or
+// dropped a weak phoneme, newest first and without repeats
+func (s *AnkiExportService) flaggedPhrases(userID uuid.UUID, isWeak map[string]bool) ([]flaggedPhrase, error) {
+	messages, err := s.messageRepo.FindAnalyzedByUserID(s.exec, userID, ankiMessageLookback)
+	if err != nil {
+		return nil, fmt.Errorf("get analyzed messages: %w", err)
+	}
+	return findFlaggedPhrases(messages, isWeak, AnkiMaxPhrases), nil
+}
+
+// findFlaggedPhrases picks up to max phrases from analyzed messages, which
+// must be newest first
+func findFlaggedPhrases(messages []models.Message, isWeak map[string]bool, max int) []flaggedPhrase {
+	seen := make(map[string]bool)
+	var phrases []flaggedPhrase
+	for _, msg := range messages {
+		text := strings.TrimSpace(msg.Content)
+		if msg.ExpectedText != nil && *msg.ExpectedText != "" {
+			text = strings.TrimSpace(*msg.ExpectedText)
+		}
+		key := strings.ToLower(text)
+		if text == "" || seen[key] {
+			continue
+		}
+
+		missed := missedPhonemes(msg.PronunciationAnalysis, isWeak)
+		if len(missed) == 0 {
+			continue
+		}
+		seen[key] = true
+		phrases = append(phrases, flaggedPhrase{text: text, phonemes: missed})
+		if len(phrases) == max {
+			break
+		}
+	}
+	return phrases
+}
+
+// missedPhonemes lists the weak phonemes an analysis marked as substituted
+// or deleted
+func missedPhonemes(analysis models.JSONMap, isWeak map[string]bool) []string {
+	parsed, ok := parseAnalysis(analysis)
+	if !ok {
+		return nil
+	}
+
+	missed := make(map[string]bool)
+	for _, d := range parsed.PhonemeDetails {
+		if (d.Type == "substitute" || d.Type == "delete") && isWeak[d.Expected] {
+			missed[d.Expected] = true
+		}
+	}
+	phonemes := make([]string, 0, len(missed))
+	for p := range missed {
+		phonemes = append(phonemes, p)
+	}
+	sort.Strings(phonemes)
+	return phonemes
+}
+
+// parseAnalysis reads a stored pronunciation analysis back into its typed form
+func parseAnalysis(analysis models.JSONMap) (*client.PronunciationAnalysis, bool) {
+	raw, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, false
+	}
+	var parsed client.PronunciationAnalysis
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// writeDeck synthesizes each card's audio and zips it with the deck file.
+// A card whose audio can't be synthesized is exported without it.
+func (s *AnkiExportService) writeDeck(ctx context.Context, cards []ankiCard) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	var deck strings.Builder
+	deck.WriteString("#separator:tab\n#html:true\n#notetype:Basic\n")
+	deck.WriteString("#deck:" + ankiDeckName + "\n#tags column:3\n")
+
+	media := make(map[string]string) // text -> file name, "" if synthesis failed
+	for _, card := range cards {
+		back := card.back
+		if card.audio != "" {
+			name, ok := media[card.audio]
+			if !ok {
+				name = s.addAudio(ctx, zw, card.audio, card.reference)
+				media[card.audio] = name
+			}
+			if name != "" {
+				back += "<br>[sound:" + name + "]"
+			}
+		}
+		fmt.Fprintf(&deck, "%s\t%s\t%s\n", ankiField(card.front), ankiField(back), strings.Join(card.tags, " "))
+	}
+
+	if err := writeZipFile(zw, "deck.txt", strings.NewReader(deck.String())); err != nil {
+		return nil, err
+	}
+	if err := writeZipFile(zw, "README.txt", strings.NewReader(ankiReadme)); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close anki export: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// addAudio synthesizes text into the archive and returns the media file
+// name, or "" on failure. Example words come from the reference library
+// rather than being synthesized again. Names are content-addressed so
+// re-importing a newer deck doesn't duplicate files in Anki's media folder.
+func (s *AnkiExportService) addAudio(ctx context.Context, zw *zip.Writer, text string, reference bool) string {
+	var audio []byte
+	if reference {
+		clip, err := s.ReferenceAudio.ClipAudio(ctx, text)
+		if err != nil {
+			log.Printf("[AnkiExport] Failed to get reference clip for %q: %v", text, err)
+			return ""
+		}
+		audio = clip
+	} else {
+		result, err := s.tts.Synthesize(ctx, text)
+		if err != nil {
+			log.Printf("[AnkiExport] Failed to synthesize %q: %v", text, err)
+			return ""
+		}
+		audio = result.AudioBytes
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	name := "lingapp-" + hex.EncodeToString(sum[:8]) + ".mp3"
+	if err := writeZipFile(zw, "media/"+name, bytes.NewReader(audio)); err != nil {
+		log.Printf("[AnkiExport] Failed to add %s: %v", name, err)
+		return ""
+	}
+	return name
+}
+
+func writeZipFile(zw *zip.Writer, name string, r io.Reader) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// ankiField keeps a field on one line of the tab-separated deck file
+func ankiField(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", "", "\n", "<br>").Replace(s)
+}
+
+const ankiReadme = `Ling App pronunciation deck
+
+1. Copy everything in the media folder into your Anki collection.media
+   folder (Tools > Check Media > View Files shows where it is).
+2. In Anki, choose File > Import and pick deck.txt. The note type, deck
+   and tags are set by the file.
+`
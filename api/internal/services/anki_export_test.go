@@ -0,0 +1,145 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func analysisWith(details ...client.PhonemeDetail) models.JSONMap {
+	entries := make([]interface{}, len(details))
+	for i, d := range details {
+		entries[i] = map[string]interface{}{"expected": d.Expected, "actual": d.Actual, "type": d.Type, "position": i}
+	}
+	return models.JSONMap{"phoneme_details": entries}
+}
+
+func TestAnkiExportService_Export(t *testing.T) {
+	userID, exportID := uuid.New(), uuid.New()
+	practiceLine := "Think about three things"
+
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+	subsRepo := new(repomocks.MockPhonemeSubstitutionRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	tts := new(clientmocks.MockTTSClient)
+	storage := new(clientmocks.MockStorageClient)
+
+	statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return([]repository.PhonemeAccuracy{
+		{Phoneme: "θ", TotalAttempts: 20, CorrectCount: 8, Accuracy: 40},
+		{Phoneme: "r", TotalAttempts: 3, CorrectCount: 0, Accuracy: 0}, // too few attempts to judge
+		{Phoneme: "s", TotalAttempts: 50, CorrectCount: 48, Accuracy: 96},
+	}, nil)
+	subsRepo.On("FindTopByUserID", mock.Anything, userID, "en", mock.Anything).Return([]models.PhonemeSubstitution{
+		{ExpectedPhoneme: "θ", ActualPhoneme: "t", OccurrenceCount: 9},
+		{ExpectedPhoneme: "θ", ActualPhoneme: "s", OccurrenceCount: 2},
+	}, nil)
+	messageRepo.On("FindAnalyzedByUserID", mock.Anything, userID, ankiMessageLookback).Return([]models.Message{
+		{Content: "thing about tree tings", ExpectedText: &practiceLine, PronunciationAnalysis: analysisWith(
+			client.PhonemeDetail{Expected: "θ", Actual: "t", Type: "substitute"},
+		)},
+		// Same line again; exported once
+		{Content: "think about tree things", ExpectedText: &practiceLine, PronunciationAnalysis: analysisWith(
+			client.PhonemeDetail{Expected: "θ", Type: "delete"},
+		)},
+		// Only a strong phoneme missed; not flagged
+		{Content: "Sunny days", PronunciationAnalysis: analysisWith(
+			client.PhonemeDetail{Expected: "s", Actual: "z", Type: "substitute"},
+		)},
+	}, nil)
+	tts.On("Synthesize", mock.Anything, practiceLine).Return(&client.TTSResult{AudioBytes: []byte("mp3")}, nil).Once()
+
+	var uploaded []byte
+	storage.On("UploadAudio", mock.Anything, mock.Anything, "exports/anki/"+userID.String()+"/"+exportID.String()+".zip", "application/zip").
+		Run(func(args mock.Arguments) {
+			uploaded, _ = io.ReadAll(args.Get(1).(io.Reader))
+		}).Return("", nil)
+
+	svc := NewAnkiExportServiceForTest(nil, messageRepo, statsRepo, subsRepo, tts, storage, nil, nil)
+	cards, err := svc.Export(context.Background(), userID, exportID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, cards, "one phoneme card and one phrase card")
+	tts.AssertExpectations(t)
+
+	archive, err := zip.NewReader(bytes.NewReader(uploaded), int64(len(uploaded)))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, _ := io.ReadAll(r)
+		files[f.Name] = string(content)
+	}
+	require.Contains(t, files, "deck.txt")
+	assert.Contains(t, files, "README.txt")
+
+	var audioName string
+	for name, content := range files {
+		if strings.HasPrefix(name, "media/") {
+			audioName = strings.TrimPrefix(name, "media/")
+			assert.Equal(t, "mp3", content)
+		}
+	}
+	require.NotEmpty(t, audioName)
+
+	lines := strings.Split(strings.TrimSpace(files["deck.txt"]), "\n")
+	assert.Contains(t, lines, "#notetype:Basic")
+	assert.Contains(t, lines,
+		"/θ/<br><i>"+practiceLine+"</i>\tYour accuracy: 40% over 20 attempts<br>Often said as /t/<br>[sound:"+audioName+"]\tlingapp phoneme")
+	assert.Contains(t, lines, practiceLine+"\tWatch: /θ/<br>[sound:"+audioName+"]\tlingapp phrase")
+}
+
+func TestAnkiExportService_Export_KeepsCardsWhenTTSFails(t *testing.T) {
+	userID := uuid.New()
+
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+	subsRepo := new(repomocks.MockPhonemeSubstitutionRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	tts := new(clientmocks.MockTTSClient)
+	storage := new(clientmocks.MockStorageClient)
+
+	statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return([]repository.PhonemeAccuracy{
+		{Phoneme: "ʃ", TotalAttempts: 10, CorrectCount: 5, Accuracy: 50},
+	}, nil)
+	subsRepo.On("FindTopByUserID", mock.Anything, userID, "en", mock.Anything).Return([]models.PhonemeSubstitution{}, nil)
+	messageRepo.On("FindAnalyzedByUserID", mock.Anything, userID, mock.Anything).Return([]models.Message{
+		{Content: "She sells", PronunciationAnalysis: analysisWith(client.PhonemeDetail{Expected: "ʃ", Actual: "s", Type: "substitute"})},
+	}, nil)
+	tts.On("Synthesize", mock.Anything, "She sells").Return(nil, errors.New("tts unavailable"))
+	storage.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, "application/zip").Return("", nil)
+
+	svc := NewAnkiExportServiceForTest(nil, messageRepo, statsRepo, subsRepo, tts, storage, nil, nil)
+	cards, err := svc.Export(context.Background(), userID, uuid.New())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, cards)
+	tts.AssertNumberOfCalls(t, "Synthesize", 1)
+}
+
+func TestAnkiExportService_RequestExport_NothingToExport(t *testing.T) {
+	userID := uuid.New()
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+	statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return([]repository.PhonemeAccuracy{
+		{Phoneme: "s", TotalAttempts: 50, CorrectCount: 49, Accuracy: 98},
+	}, nil)
+
+	svc := NewAnkiExportServiceForTest(nil, nil, statsRepo, nil, nil, nil, nil, nil)
+	_, err := svc.RequestExport(userID)
+
+	assert.ErrorIs(t, err, ErrNothingToExport)
+}
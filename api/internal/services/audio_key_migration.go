@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// audioKeyMigrationBatchSize caps how many recordings are moved per sweep
+const audioKeyMigrationBatchSize = 100
+
+// DefaultAudioKeyMigrationGrace is how long a legacy object outlives its
+// migration, so playback URLs handed out for it keep working
+const DefaultAudioKeyMigrationGrace = 24 * time.Hour
+
+// AudioKeyMigrationWorker moves recordings stored under legacy keys, which
+// baked the format into the extension, to the current key scheme. Each object
+// is copied and checked before its message or chunk is pointed at the copy;
+// the legacy object is deleted once the grace period is over.
+type AudioKeyMigrationWorker struct {
+	exec     repository.Executor
+	txRunner TxRunner
+	repo     repository.AudioKeyMigrationRepository
+	storage  client.StorageClient
+	interval time.Duration
+	grace    time.Duration
+
+	now func() time.Time
+}
+
+// NewAudioKeyMigrationWorker creates a new audio key migration worker. storage
+// must read keys as given, without the legacy key fallback.
+func NewAudioKeyMigrationWorker(
+	database *db.DB,
+	repo repository.AudioKeyMigrationRepository,
+	storage client.StorageClient,
+	interval time.Duration,
+	grace time.Duration,
+) *AudioKeyMigrationWorker {
+	return NewAudioKeyMigrationWorkerForTest(database.DB, database.DB, repo, storage, interval, grace, time.Now)
+}
+
+// NewAudioKeyMigrationWorkerForTest creates an AudioKeyMigrationWorker with injected dependencies for testing.
+func NewAudioKeyMigrationWorkerForTest(
+	exec repository.Executor,
+	txRunner TxRunner,
+	repo repository.AudioKeyMigrationRepository,
+	storage client.StorageClient,
+	interval time.Duration,
+	grace time.Duration,
+	now func() time.Time,
+) *AudioKeyMigrationWorker {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	if grace <= 0 {
+		grace = DefaultAudioKeyMigrationGrace
+	}
+	return &AudioKeyMigrationWorker{
+		exec:     exec,
+		txRunner: txRunner,
+		repo:     repo,
+		storage:  storage,
+		interval: interval,
+		grace:    grace,
+		now:      now,
+	}
+}
+
+// Start migrates recordings until ctx is cancelled
+func (w *AudioKeyMigrationWorker) Start(ctx context.Context) {
+	log.Printf("[AudioKeyMigration] Migrating recordings to the current key scheme every %s", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Migrate(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Migrate deletes the legacy objects whose grace period is over, then moves
+// one batch of recordings and returns how many were moved. A recording that
+// fails keeps its legacy key and is retried on the next sweep.
+func (w *AudioKeyMigrationWorker) Migrate(ctx context.Context) int {
+	w.deleteLegacy(ctx)
+
+	recordings, err := w.repo.FindLegacy(w.exec, audioKeyMigrationBatchSize)
+	if err != nil {
+		log.Printf("[AudioKeyMigration] Failed to find legacy recordings: %v", err)
+		return 0
+	}
+
+	migrated := 0
+	for _, recording := range recordings {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := w.migrate(ctx, recording); err != nil {
+			log.Printf("[AudioKeyMigration] Failed to migrate %s: %v", recording.Key, err)
+			continue
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("[AudioKeyMigration] Migrated %d recordings", migrated)
+	}
+	return migrated
+}
+
+// migrate copies one recording, and its low-bitrate variant, to the current
+// scheme and switches its row to the copy. Copies always go to the same key,
+// so a retry overwrites what a failed attempt left behind. A recording
+// already missing from storage is switched too, so it isn't picked up again.
+func (w *AudioKeyMigrationWorker) migrate(ctx context.Context, recording repository.LegacyAudio) error {
+	key, format, ok := models.MigratedAudioKey(recording.Key)
+	if !ok {
+		return errors.New("not a legacy key")
+	}
+
+	if err := w.copy(ctx, recording.Key, key); err != nil && !errors.Is(err, client.ErrObjectNotFound) {
+		return err
+	}
+	if variant := models.LowBitrateAudioKey(recording.Key); variant != "" {
+		if err := w.copy(ctx, variant, models.LowBitrateAudioKey(key)); err != nil && !errors.Is(err, client.ErrObjectNotFound) {
+			return err
+		}
+	}
+
+	var switched bool
+	err := w.txRunner.Transaction(func(tx *gorm.DB) error {
+		var err error
+		switched, err = w.repo.Switch(tx, recording, key, format, w.now())
+		return err
+	})
+	if err != nil {
+		// Left in place: another worker may have switched the row to it
+		return err
+	}
+	if !switched {
+		// The recording was deleted or replaced meanwhile
+		w.discard(ctx, key)
+		return errors.New("recording changed during migration")
+	}
+	return nil
+}
+
+// copy copies src to dst and checks the copy is complete. A bad copy is left
+// to be overwritten by the retry, since another worker may be using it.
+func (w *AudioKeyMigrationWorker) copy(ctx context.Context, src, dst string) error {
+	if err := w.storage.CopyObject(ctx, src, dst); err != nil {
+		return err
+	}
+	original, err := w.storage.StatObject(ctx, src)
+	if err != nil {
+		return err
+	}
+	copied, err := w.storage.StatObject(ctx, dst)
+	if err != nil {
+		return err
+	}
+	if copied.Size != original.Size {
+		return fmt.Errorf("copy of %s has %d bytes, want %d", src, copied.Size, original.Size)
+	}
+	return nil
+}
+
+// discard deletes the copies of a recording that wasn't switched
+func (w *AudioKeyMigrationWorker) discard(ctx context.Context, key string) {
+	w.deleteObject(ctx, key)
+	if variant := models.LowBitrateAudioKey(key); variant != "" {
+		w.deleteObject(ctx, variant)
+	}
+}
+
+// deleteLegacy deletes one batch of legacy objects whose grace period is over
+func (w *AudioKeyMigrationWorker) deleteLegacy(ctx context.Context) {
+	migrations, err := w.repo.FindMigratedBefore(w.exec, w.now().Add(-w.grace), audioKeyMigrationBatchSize)
+	if err != nil {
+		log.Printf("[AudioKeyMigration] Failed to find migrated recordings: %v", err)
+		return
+	}
+
+	for _, migration := range migrations {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := w.storage.DeleteAudio(ctx, migration.LegacyKey); err != nil {
+			log.Printf("[AudioKeyMigration] Failed to delete %s: %v", migration.LegacyKey, err)
+			continue
+		}
+		if variant := models.LowBitrateAudioKey(migration.LegacyKey); variant != "" {
+			if err := w.storage.DeleteAudio(ctx, variant); err != nil {
+				log.Printf("[AudioKeyMigration] Failed to delete %s: %v", variant, err)
+				continue
+			}
+		}
+		if err := w.repo.Delete(w.exec, migration.ID); err != nil {
+			log.Printf("[AudioKeyMigration] Failed to delete migration %s: %v", migration.ID, err)
+		}
+	}
+}
+
+func (w *AudioKeyMigrationWorker) deleteObject(ctx context.Context, key string) {
+	if err := w.storage.DeleteAudio(ctx, key); err != nil {
+		log.Printf("[AudioKeyMigration] Failed to delete %s: %v", key, err)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestAudioKeyMigrationWorker_Migrate(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	threadID, messageID := uuid.New(), uuid.New()
+	legacy := repository.LegacyAudio{ID: messageID, Key: "assistant/" + threadID.String() + "/" + messageID.String() + ".mp3"}
+	key := models.AssistantAudioKey(threadID, messageID)
+
+	newWorker := func() (*AudioKeyMigrationWorker, *repomocks.MockAudioKeyMigrationRepository, *clientmocks.MockStorageClient) {
+		repo := new(repomocks.MockAudioKeyMigrationRepository)
+		storage := new(clientmocks.MockStorageClient)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		repo.On("FindMigratedBefore", mock.Anything, now.Add(-DefaultAudioKeyMigrationGrace), audioKeyMigrationBatchSize).Return([]models.AudioKeyMigration{}, nil)
+		return NewAudioKeyMigrationWorkerForTest(nil, txRunner, repo, storage, 0, 0, func() time.Time { return now }), repo, storage
+	}
+
+	t.Run("copies the recording and its variant, then switches the row", func(t *testing.T) {
+		worker, repo, storage := newWorker()
+		repo.On("FindLegacy", mock.Anything, audioKeyMigrationBatchSize).Return([]repository.LegacyAudio{legacy}, nil)
+		storage.On("CopyObject", mock.Anything, legacy.Key, key).Return(nil)
+		storage.On("StatObject", mock.Anything, legacy.Key).Return(&client.ObjectInfo{Size: 2048}, nil)
+		storage.On("StatObject", mock.Anything, key).Return(&client.ObjectInfo{Size: 2048}, nil)
+		storage.On("CopyObject", mock.Anything, models.LowBitrateAudioKey(legacy.Key), key+".low.ogg").Return(client.ErrObjectNotFound)
+		repo.On("Switch", mock.Anything, legacy, key, models.AudioFormatMP3, now).Return(true, nil)
+
+		assert.Equal(t, 1, worker.Migrate(context.Background()))
+		repo.AssertExpectations(t)
+		storage.AssertNotCalled(t, "DeleteAudio", mock.Anything, mock.Anything)
+	})
+
+	t.Run("keeps the legacy key when the copy is incomplete", func(t *testing.T) {
+		worker, repo, storage := newWorker()
+		repo.On("FindLegacy", mock.Anything, audioKeyMigrationBatchSize).Return([]repository.LegacyAudio{legacy}, nil)
+		storage.On("CopyObject", mock.Anything, legacy.Key, key).Return(nil)
+		storage.On("StatObject", mock.Anything, legacy.Key).Return(&client.ObjectInfo{Size: 2048}, nil)
+		storage.On("StatObject", mock.Anything, key).Return(&client.ObjectInfo{Size: 1024}, nil)
+
+		assert.Equal(t, 0, worker.Migrate(context.Background()))
+		repo.AssertNotCalled(t, "Switch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("removes the copy of a recording deleted meanwhile", func(t *testing.T) {
+		worker, repo, storage := newWorker()
+		repo.On("FindLegacy", mock.Anything, audioKeyMigrationBatchSize).Return([]repository.LegacyAudio{legacy}, nil)
+		storage.On("CopyObject", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		storage.On("StatObject", mock.Anything, mock.Anything).Return(&client.ObjectInfo{Size: 2048}, nil)
+		repo.On("Switch", mock.Anything, legacy, key, models.AudioFormatMP3, now).Return(false, nil)
+		storage.On("DeleteAudio", mock.Anything, key).Return(nil)
+		storage.On("DeleteAudio", mock.Anything, key+".low.ogg").Return(nil)
+
+		assert.Equal(t, 0, worker.Migrate(context.Background()))
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("deletes legacy objects once the grace period is over", func(t *testing.T) {
+		repo := new(repomocks.MockAudioKeyMigrationRepository)
+		storage := new(clientmocks.MockStorageClient)
+		migration := models.AudioKeyMigration{ID: uuid.New(), LegacyKey: legacy.Key, Key: key}
+		repo.On("FindMigratedBefore", mock.Anything, now.Add(-48*time.Hour), audioKeyMigrationBatchSize).Return([]models.AudioKeyMigration{migration}, nil)
+		repo.On("FindLegacy", mock.Anything, audioKeyMigrationBatchSize).Return([]repository.LegacyAudio{}, nil)
+		storage.On("DeleteAudio", mock.Anything, legacy.Key).Return(nil)
+		storage.On("DeleteAudio", mock.Anything, models.LowBitrateAudioKey(legacy.Key)).Return(nil)
+		repo.On("Delete", mock.Anything, migration.ID).Return(nil)
+
+		worker := NewAudioKeyMigrationWorkerForTest(nil, new(mockTxRunner), repo, storage, 0, 48*time.Hour, func() time.Time { return now })
+
+		assert.Equal(t, 0, worker.Migrate(context.Background()))
+		repo.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/repository"
+)
+
+// audioPurgeBatchSize caps how many recordings are deleted per sweep
+const audioPurgeBatchSize = 200
+
+// AudioRetentionWorker deletes user recordings that are older than the
+// owner's audio retention setting. Transcripts and pronunciation analyses are
+// kept; the message just stops pointing at audio.
+type AudioRetentionWorker struct {
+	exec        repository.Executor
+	messageRepo repository.MessageRepository
+	storage     client.StorageClient
+	interval    time.Duration
+
+	// Chunks also purges the recordings of long-form messages (optional)
+	Chunks repository.MessageChunkRepository
+
+	now func() time.Time
+}
+
+// NewAudioRetentionWorker creates a new audio retention worker
+func NewAudioRetentionWorker(
+	database *db.DB,
+	messageRepo repository.MessageRepository,
+	storage client.StorageClient,
+	interval time.Duration,
+) *AudioRetentionWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &AudioRetentionWorker{
+		exec:        database.DB,
+		messageRepo: messageRepo,
+		storage:     storage,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// NewAudioRetentionWorkerForTest creates an AudioRetentionWorker with injected dependencies for testing.
+func NewAudioRetentionWorkerForTest(
+	exec repository.Executor,
+	messageRepo repository.MessageRepository,
+	storage client.StorageClient,
+	interval time.Duration,
+) *AudioRetentionWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &AudioRetentionWorker{
+		exec:        exec,
+		messageRepo: messageRepo,
+		storage:     storage,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// Start purges expired recordings until ctx is cancelled
+func (w *AudioRetentionWorker) Start(ctx context.Context) {
+	log.Printf("[AudioRetention] Purging expired recordings every %s", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes one batch of expired recordings and returns how many were
+// removed. A recording whose delete fails is left attached to its message so
+// the next sweep retries it.
+func (w *AudioRetentionWorker) Purge(ctx context.Context) int {
+	messages, err := w.messageRepo.FindExpiredUserAudio(w.exec, w.now(), audioPurgeBatchSize)
+	if err != nil {
+		log.Printf("[AudioRetention] Failed to find expired recordings: %v", err)
+		return 0
+	}
+
+	purged := 0
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := w.storage.DeleteAudio(ctx, *message.AudioURL); err != nil {
+			log.Printf("[AudioRetention] Failed to delete audio for message %s: %v", message.ID, err)
+			continue
+		}
+		if err := w.messageRepo.ClearAudio(w.exec, message.ID); err != nil {
+			log.Printf("[AudioRetention] Failed to clear audio on message %s: %v", message.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	purged += w.purgeChunks(ctx)
+
+	if purged > 0 {
+		log.Printf("[AudioRetention] Purged %d expired recordings", purged)
+	}
+	return purged
+}
+
+// purgeChunks deletes one batch of expired long-form recordings
+func (w *AudioRetentionWorker) purgeChunks(ctx context.Context) int {
+	if w.Chunks == nil || ctx.Err() != nil {
+		return 0
+	}
+
+	chunks, err := w.Chunks.FindExpiredAudio(w.exec, w.now(), audioPurgeBatchSize)
+	if err != nil {
+		log.Printf("[AudioRetention] Failed to find expired long-form recordings: %v", err)
+		return 0
+	}
+
+	purged := 0
+	for _, chunk := range chunks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := w.storage.DeleteAudio(ctx, *chunk.AudioURL); err != nil {
+			log.Printf("[AudioRetention] Failed to delete audio for chunk %s: %v", chunk.ID, err)
+			continue
+		}
+		if err := w.Chunks.ClearAudio(w.exec, chunk.ID); err != nil {
+			log.Printf("[AudioRetention] Failed to clear audio on chunk %s: %v", chunk.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestAudioRetentionWorker_Purge(t *testing.T) {
+	keyA, keyB := "user/a.webm", "user/b.webm"
+	expired := []models.Message{
+		{ID: uuid.New(), Role: "user", AudioURL: &keyA, HasAudio: true},
+		{ID: uuid.New(), Role: "user", AudioURL: &keyB, HasAudio: true},
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("deletes audio and clears messages", func(t *testing.T) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		storage := new(clientmocks.MockStorageClient)
+
+		messageRepo.On("FindExpiredUserAudio", mock.Anything, now, audioPurgeBatchSize).Return(expired, nil)
+		storage.On("DeleteAudio", mock.Anything, keyA).Return(nil)
+		storage.On("DeleteAudio", mock.Anything, keyB).Return(nil)
+		messageRepo.On("ClearAudio", mock.Anything, expired[0].ID).Return(nil)
+		messageRepo.On("ClearAudio", mock.Anything, expired[1].ID).Return(nil)
+
+		worker := NewAudioRetentionWorkerForTest(nil, messageRepo, storage, 0)
+		worker.now = func() time.Time { return now }
+
+		assert.Equal(t, 2, worker.Purge(context.Background()))
+		messageRepo.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("purges long-form chunks too", func(t *testing.T) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		chunkRepo := new(repomocks.MockMessageChunkRepository)
+		storage := new(clientmocks.MockStorageClient)
+		chunkKey := "user/t/m-0.webm"
+		chunk := models.MessageChunk{ID: uuid.New(), AudioURL: &chunkKey}
+
+		messageRepo.On("FindExpiredUserAudio", mock.Anything, now, audioPurgeBatchSize).Return([]models.Message{}, nil)
+		chunkRepo.On("FindExpiredAudio", mock.Anything, now, audioPurgeBatchSize).Return([]models.MessageChunk{chunk}, nil)
+		storage.On("DeleteAudio", mock.Anything, chunkKey).Return(nil)
+		chunkRepo.On("ClearAudio", mock.Anything, chunk.ID).Return(nil)
+
+		worker := NewAudioRetentionWorkerForTest(nil, messageRepo, storage, 0)
+		worker.Chunks = chunkRepo
+		worker.now = func() time.Time { return now }
+
+		assert.Equal(t, 1, worker.Purge(context.Background()))
+		chunkRepo.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("keeps message pointing at audio when delete fails", func(t *testing.T) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		storage := new(clientmocks.MockStorageClient)
+
+		messageRepo.On("FindExpiredUserAudio", mock.Anything, now, audioPurgeBatchSize).Return(expired, nil)
+		storage.On("DeleteAudio", mock.Anything, keyA).Return(errors.New("s3 down"))
+		storage.On("DeleteAudio", mock.Anything, keyB).Return(nil)
+		messageRepo.On("ClearAudio", mock.Anything, expired[1].ID).Return(nil)
+
+		worker := NewAudioRetentionWorkerForTest(nil, messageRepo, storage, 0)
+		worker.now = func() time.Time { return now }
+
+		assert.Equal(t, 1, worker.Purge(context.Background()))
+		messageRepo.AssertNotCalled(t, "ClearAudio", mock.Anything, expired[0].ID)
+	})
+
+	t.Run("lookup failure purges nothing", func(t *testing.T) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		storage := new(clientmocks.MockStorageClient)
+
+		messageRepo.On("FindExpiredUserAudio", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		worker := NewAudioRetentionWorkerForTest(nil, messageRepo, storage, 0)
+
+		assert.Equal(t, 0, worker.Purge(context.Background()))
+		storage.AssertNotCalled(t, "DeleteAudio", mock.Anything, mock.Anything)
+	})
+}
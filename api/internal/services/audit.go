@@ -0,0 +1,47 @@
+package services
+
+import (
+	"log"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// AuditLogger defines the interface for recording security-relevant actions
+type AuditLogger interface {
+	Record(entry *models.AuditLog)
+}
+
+// AuditService writes entries to the audit log
+type AuditService struct {
+	exec      repository.Executor
+	auditRepo repository.AuditLogRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(database *db.DB, auditRepo repository.AuditLogRepository) *AuditService {
+	return &AuditService{
+		exec:      database.DB,
+		auditRepo: auditRepo,
+	}
+}
+
+// NewAuditServiceForTest creates an AuditService with injected dependencies for testing.
+func NewAuditServiceForTest(exec repository.Executor, auditRepo repository.AuditLogRepository) *AuditService {
+	return &AuditService{
+		exec:      exec,
+		auditRepo: auditRepo,
+	}
+}
+
+// Record logs and stores an audit entry. A failed write is logged rather than
+// returned: auditing must never turn an allowed action into an error, and a
+// rejected action is rejected either way.
+func (s *AuditService) Record(entry *models.AuditLog) {
+	log.Printf("[Audit] %s by %s: %s %v", entry.Action, entry.Actor, entry.Outcome, map[string]interface{}(entry.Details))
+
+	if err := s.auditRepo.Create(s.exec, entry); err != nil {
+		log.Printf("[Audit] Failed to store %s entry: %v", entry.Action, err)
+	}
+}
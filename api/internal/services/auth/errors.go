@@ -8,4 +8,5 @@ var (
 	ErrEmailTaken         = errors.New("email already registered")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrSessionNotFound    = errors.New("session not found or expired")
+	ErrUnknownSession     = errors.New("no such session")
 )
@@ -46,6 +46,10 @@ func (s *AuthService) FindOrCreateOAuthUser(provider, providerID, email, name, a
 
 	// Try to find by email (user might have registered with email first)
 	user, err = s.userRepo.FindByEmail(s.exec, email)
+	if err == nil && user.IsMerged() {
+		// The email belongs to an account merged into another: sign in there
+		user, err = s.userRepo.FindByID(s.exec, *user.MergedIntoID)
+	}
 	if err == nil {
 		// Link OAuth to existing account
 		switch provider {
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/repository/mocks"
+)
+
+func TestFindOrCreateOAuthUser_MergedEmail(t *testing.T) {
+	mockExec := &mocks.MockExecutor{}
+	mockUserRepo := &mocks.MockUserRepository{}
+	service := NewAuthServiceForTest(mockExec, nil, mockUserRepo, &mocks.MockSessionRepository{}, 86400)
+
+	survivor := &models.User{ID: uuid.New(), Email: "ana@example.com"}
+	mergedAt := time.Now()
+	merged := &models.User{ID: uuid.New(), Email: "ana.work@example.com", MergedIntoID: &survivor.ID, MergedAt: &mergedAt}
+
+	mockUserRepo.On("FindByGitHubID", mockExec, "gh-1").Return(nil, repository.ErrNotFound)
+	mockUserRepo.On("FindByEmail", mockExec, merged.Email).Return(merged, nil)
+	mockUserRepo.On("FindByID", mockExec, survivor.ID).Return(survivor, nil)
+	mockUserRepo.On("Save", mockExec, mock.MatchedBy(func(u *models.User) bool {
+		return u.ID == survivor.ID && u.GitHubID != nil && *u.GitHubID == "gh-1"
+	})).Return(nil)
+
+	user, isNew, err := service.FindOrCreateOAuthUser("github", "gh-1", merged.Email, "Ana", "", nil)
+
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, survivor.ID, user.ID, "signs in to the account the email was merged into")
+	mockUserRepo.AssertExpectations(t)
+}
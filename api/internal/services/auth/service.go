@@ -26,6 +26,15 @@ type AuthService struct {
 	sessionRepo   repository.SessionRepository
 	sessionMaxAge time.Duration
 	bcryptCost    int
+
+	// RefreshPercent, if set, slides sessions: once that percentage of the
+	// max age has passed, validating a session extends it to a full max age
+	// again, so active users aren't logged out mid-conversation
+	RefreshPercent int
+
+	// AbsoluteMaxAge, if set, caps how long after it was created a session
+	// can be slid to
+	AbsoluteMaxAge time.Duration
 }
 
 // NewAuthService creates a new auth service.
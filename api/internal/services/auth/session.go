@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,12 @@ import (
 // CreateSession creates a new session for a user.
 // Returns the session token to be stored in a cookie.
 func (s *AuthService) CreateSession(userID uuid.UUID, userAgent, ipAddress string) (string, error) {
+	return s.CreateSessionUntil(userID, userAgent, ipAddress, time.Now().Add(s.sessionMaxAge))
+}
+
+// CreateSessionUntil creates a session that expires at expiresAt instead of
+// after the usual max age, e.g. with a guest's demo.
+func (s *AuthService) CreateSessionUntil(userID uuid.UUID, userAgent, ipAddress string, expiresAt time.Time) (string, error) {
 	token, err := s.GenerateSessionToken()
 	if err != nil {
 		return "", err
@@ -23,7 +30,7 @@ func (s *AuthService) CreateSession(userID uuid.UUID, userAgent, ipAddress strin
 		UserID:    userID,
 		UserAgent: userAgent,
 		IPAddress: ipAddress,
-		ExpiresAt: time.Now().Add(s.sessionMaxAge),
+		ExpiresAt: expiresAt,
 		CreatedAt: time.Now(),
 	}
 
@@ -36,21 +43,60 @@ func (s *AuthService) CreateSession(userID uuid.UUID, userAgent, ipAddress strin
 
 // ValidateSession checks if a session token is valid and returns the associated user.
 func (s *AuthService) ValidateSession(token string) (*models.User, error) {
+	user, _, err := s.Authenticate(token)
+	return user, err
+}
+
+// Authenticate is ValidateSession for the auth middleware. It also returns
+// the session's new expiry when validating it slid the expiry forward, so the
+// cookie can be extended to match, or nil when it didn't.
+func (s *AuthService) Authenticate(token string) (*models.User, *time.Time, error) {
 	session, err := s.sessionRepo.FindByIDWithUser(s.exec, token)
 	if errors.Is(err, repository.ErrNotFound) {
-		return nil, ErrSessionNotFound
+		return nil, nil, ErrSessionNotFound
 	}
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if session.IsExpired() {
 		// Clean up expired session
 		_ = s.sessionRepo.DeleteByID(s.exec, token)
-		return nil, ErrSessionNotFound
+		return nil, nil, ErrSessionNotFound
+	}
+
+	return &session.User, s.slide(session, time.Now()), nil
+}
+
+// slide extends a session that has used up RefreshPercent of its max age,
+// though never past AbsoluteMaxAge from when it was created. Guest sessions
+// end with the demo, so they never slide. A failed update leaves the session
+// as it was; it is tried again on the next request.
+func (s *AuthService) slide(session *models.Session, now time.Time) *time.Time {
+	if s.RefreshPercent <= 0 || session.User.IsGuest() {
+		return nil
+	}
+
+	remaining := s.sessionMaxAge * time.Duration(100-s.RefreshPercent) / 100
+	if session.ExpiresAt.Sub(now) > remaining {
+		return nil
+	}
+
+	expiresAt := now.Add(s.sessionMaxAge)
+	if s.AbsoluteMaxAge > 0 {
+		if deadline := session.CreatedAt.Add(s.AbsoluteMaxAge); expiresAt.After(deadline) {
+			expiresAt = deadline
+		}
+	}
+	if !expiresAt.After(session.ExpiresAt) {
+		return nil
 	}
 
-	return &session.User, nil
+	if err := s.sessionRepo.UpdateExpiresAt(s.exec, session.ID, expiresAt); err != nil {
+		log.Printf("[Auth] Failed to extend session %s of user %s: %v", session.PublicID(), session.UserID, err)
+		return nil
+	}
+	return &expiresAt
 }
 
 // DeleteSession removes a session (logout).
@@ -58,8 +104,28 @@ func (s *AuthService) DeleteSession(token string) error {
 	return s.sessionRepo.DeleteByID(s.exec, token)
 }
 
+// ActiveSessions returns the user's unexpired sessions, newest first.
+func (s *AuthService) ActiveSessions(userID uuid.UUID) ([]models.Session, error) {
+	return s.sessionRepo.FindActiveByUserID(s.exec, userID, time.Now())
+}
+
+// RevokeSession signs one of the user's devices out, by the session's public ID.
+// Returns ErrUnknownSession if the user has no active session with that ID.
+func (s *AuthService) RevokeSession(userID uuid.UUID, publicID string) error {
+	sessions, err := s.ActiveSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		if sessions[i].PublicID() == publicID {
+			return s.sessionRepo.DeleteByID(s.exec, sessions[i].ID)
+		}
+	}
+	return ErrUnknownSession
+}
+
 // DeleteAllUserSessions removes all sessions for a user (logout everywhere).
-// TODO: Wire to handler for "logout everywhere" feature
 func (s *AuthService) DeleteAllUserSessions(userID uuid.UUID) error {
 	return s.sessionRepo.DeleteByUserID(s.exec, userID)
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -135,6 +136,92 @@ func TestValidateSession(t *testing.T) {
 	})
 }
 
+func TestAuthenticate_SlidingExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		age       time.Duration
+		guest     bool
+		extended  bool
+		want      time.Duration // from now, when extended
+	}{
+		{name: "fresh session is left alone", remaining: 20 * time.Hour, age: 4 * time.Hour},
+		{name: "session past half its age is extended", remaining: 6 * time.Hour, age: 18 * time.Hour, extended: true, want: 24 * time.Hour},
+		{name: "guest session keeps the demo's expiry", remaining: time.Hour, age: 23 * time.Hour, guest: true},
+		{name: "extension stops at the absolute max age", remaining: 6 * time.Hour, age: 29*24*time.Hour + 12*time.Hour, extended: true, want: 12 * time.Hour},
+		{name: "session at its absolute max age is left alone", remaining: 6 * time.Hour, age: 30*24*time.Hour - 6*time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := &mocks.MockExecutor{}
+			mockSessionRepo := &mocks.MockSessionRepository{}
+
+			service := NewAuthServiceForTest(mockExec, nil, &mocks.MockUserRepository{}, mockSessionRepo, 86400)
+			service.RefreshPercent = 50
+			service.AbsoluteMaxAge = 30 * 24 * time.Hour
+
+			user := models.User{ID: uuid.New()}
+			if tt.guest {
+				guestExpiresAt := time.Now().Add(tt.remaining)
+				user.GuestExpiresAt = &guestExpiresAt
+			}
+			now := time.Now()
+			session := &models.Session{ID: "token", UserID: user.ID, User: user, ExpiresAt: now.Add(tt.remaining), CreatedAt: now.Add(-tt.age)}
+
+			mockSessionRepo.On("FindByIDWithUser", mockExec, "token").Return(session, nil)
+			mockSessionRepo.On("UpdateExpiresAt", mockExec, "token", mock.AnythingOfType("time.Time")).Return(nil).Maybe()
+
+			got, expiresAt, err := service.Authenticate("token")
+
+			assert.NoError(t, err)
+			assert.Equal(t, user.ID, got.ID)
+			if tt.extended {
+				if assert.NotNil(t, expiresAt) {
+					assert.WithinDuration(t, time.Now().Add(tt.want), *expiresAt, time.Minute)
+				}
+				mockSessionRepo.AssertCalled(t, "UpdateExpiresAt", mockExec, "token", *expiresAt)
+			} else {
+				assert.Nil(t, expiresAt)
+				mockSessionRepo.AssertNotCalled(t, "UpdateExpiresAt", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+
+	t.Run("failed extension keeps the session as it was", func(t *testing.T) {
+		mockExec := &mocks.MockExecutor{}
+		mockSessionRepo := &mocks.MockSessionRepository{}
+
+		service := NewAuthServiceForTest(mockExec, nil, &mocks.MockUserRepository{}, mockSessionRepo, 86400)
+		service.RefreshPercent = 50
+
+		session := &models.Session{ID: "token", ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now().Add(-23 * time.Hour)}
+		mockSessionRepo.On("FindByIDWithUser", mockExec, "token").Return(session, nil)
+		mockSessionRepo.On("UpdateExpiresAt", mockExec, "token", mock.AnythingOfType("time.Time")).Return(errors.New("db down"))
+
+		_, expiresAt, err := service.Authenticate("token")
+
+		assert.NoError(t, err)
+		assert.Nil(t, expiresAt)
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		mockExec := &mocks.MockExecutor{}
+		mockSessionRepo := &mocks.MockSessionRepository{}
+
+		service := NewAuthServiceForTest(mockExec, nil, &mocks.MockUserRepository{}, mockSessionRepo, 86400)
+
+		session := &models.Session{ID: "token", ExpiresAt: time.Now().Add(time.Minute)}
+		mockSessionRepo.On("FindByIDWithUser", mockExec, "token").Return(session, nil)
+
+		_, expiresAt, err := service.Authenticate("token")
+
+		assert.NoError(t, err)
+		assert.Nil(t, expiresAt)
+		mockSessionRepo.AssertExpectations(t)
+	})
+}
+
 func TestDeleteSession(t *testing.T) {
 	t.Run("deletes session successfully", func(t *testing.T) {
 		mockExec := &mocks.MockExecutor{}
@@ -153,6 +240,45 @@ func TestDeleteSession(t *testing.T) {
 	})
 }
 
+func TestRevokeSession(t *testing.T) {
+	userID := uuid.New()
+	sessions := []models.Session{{ID: "phone-token", UserID: userID}, {ID: "laptop-token", UserID: userID}}
+
+	t.Run("deletes the session with that public ID", func(t *testing.T) {
+		mockExec := &mocks.MockExecutor{}
+		mockSessionRepo := &mocks.MockSessionRepository{}
+
+		service := NewAuthServiceForTest(mockExec, nil, &mocks.MockUserRepository{}, mockSessionRepo, 86400)
+
+		mockSessionRepo.On("FindActiveByUserID", mockExec, userID, mock.AnythingOfType("time.Time")).
+			Return(sessions, nil)
+		mockSessionRepo.On("DeleteByID", mockExec, "laptop-token").
+			Return(nil)
+
+		err := service.RevokeSession(userID, sessions[1].PublicID())
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, sessions[0].PublicID(), sessions[1].PublicID())
+		mockSessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("another user's session is unknown", func(t *testing.T) {
+		mockExec := &mocks.MockExecutor{}
+		mockSessionRepo := &mocks.MockSessionRepository{}
+
+		service := NewAuthServiceForTest(mockExec, nil, &mocks.MockUserRepository{}, mockSessionRepo, 86400)
+
+		mockSessionRepo.On("FindActiveByUserID", mockExec, userID, mock.AnythingOfType("time.Time")).
+			Return(sessions, nil)
+
+		other := models.Session{ID: "someone-elses-token"}
+		err := service.RevokeSession(userID, other.PublicID())
+
+		assert.ErrorIs(t, err, ErrUnknownSession)
+		mockSessionRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+	})
+}
+
 func TestDeleteAllUserSessions(t *testing.T) {
 	t.Run("deletes all user sessions successfully", func(t *testing.T) {
 		mockExec := &mocks.MockExecutor{}
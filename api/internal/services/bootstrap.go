@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// Bootstrap section names, as listed in Bootstrap.Unavailable
+const (
+	BootstrapSectionCredits    = "credits"
+	BootstrapSectionLastThread = "lastThread"
+)
+
+// Bootstrapper defines the interface for the data the app loads on start
+type Bootstrapper interface {
+	GetBootstrap(user *models.User) *Bootstrap
+}
+
+// Bootstrap is what the app needs to open: the credit balance and the last
+// active thread with its messages. Like the home screen, a section that
+// fails to load is left empty and named in Unavailable.
+type Bootstrap struct {
+	Credits     *models.Credits `json:"credits"`
+	LastThread  *models.Thread  `json:"lastThread"` // nil for users without messages
+	Unavailable []string        `json:"unavailable,omitempty"`
+}
+
+// BootstrapService loads the app's start-up data in one go
+type BootstrapService struct {
+	exec       repository.Executor
+	threadRepo repository.ThreadRepository
+	credits    CreditsManager
+
+	// Threads caches each user's last active thread between app opens;
+	// nil loads it every time
+	Threads *repository.ThreadCache
+}
+
+// NewBootstrapService creates a new bootstrap service
+func NewBootstrapService(database *db.DB, threadRepo repository.ThreadRepository, credits CreditsManager) *BootstrapService {
+	return &BootstrapService{
+		exec:       database.DB,
+		threadRepo: threadRepo,
+		credits:    credits,
+	}
+}
+
+// NewBootstrapServiceForTest creates a BootstrapService with injected dependencies for testing.
+func NewBootstrapServiceForTest(exec repository.Executor, threadRepo repository.ThreadRepository, credits CreditsManager) *BootstrapService {
+	return &BootstrapService{
+		exec:       exec,
+		threadRepo: threadRepo,
+		credits:    credits,
+	}
+}
+
+// GetBootstrap loads the credits and the last active thread concurrently.
+// Analyses are left out of the thread, as in GetThread.
+func (s *BootstrapService) GetBootstrap(user *models.User) *Bootstrap {
+	bootstrap := &Bootstrap{}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	section := func(name string, load func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := func() (err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("panic: %v", r)
+					}
+				}()
+				return load()
+			}()
+			if err != nil {
+				log.Printf("[Bootstrap] Failed to load %s for user %s: %v", name, user.ID, err)
+				mu.Lock()
+				bootstrap.Unavailable = append(bootstrap.Unavailable, name)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	section(BootstrapSectionCredits, func() error {
+		credits, err := s.credits.GetCredits(user.ID)
+		if err != nil {
+			return err
+		}
+		bootstrap.Credits = credits
+		return nil
+	})
+	section(BootstrapSectionLastThread, func() error {
+		thread, err := s.lastThread(user)
+		if err != nil {
+			return err
+		}
+		bootstrap.LastThread = thread
+		return nil
+	})
+	wg.Wait()
+
+	sort.Strings(bootstrap.Unavailable)
+	return bootstrap
+}
+
+// lastThread returns the user's last active thread, from the cache when it
+// holds one
+func (s *BootstrapService) lastThread(user *models.User) (*models.Thread, error) {
+	if s.Threads != nil {
+		if thread, ok := s.Threads.Get(user.ID); ok {
+			return thread, nil
+		}
+	}
+
+	summaries, err := s.threadRepo.FindSummariesByUserID(s.exec, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	last := lastActiveThread(summaries)
+	if last == nil {
+		return nil, nil
+	}
+	thread, err := s.threadRepo.FindByIDAndUserIDWithMessages(s.exec, last.ID, user.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	if s.Threads != nil {
+		s.Threads.Put(user.ID, thread)
+	}
+	return thread, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestBootstrapService_GetBootstrap(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	older := models.ThreadSummary{Thread: models.Thread{ID: uuid.New()}, LastActivityAt: now.Add(-time.Hour), MessageCount: 4}
+	newest := models.ThreadSummary{Thread: models.Thread{ID: uuid.New()}, LastActivityAt: now, MessageCount: 2}
+	empty := models.ThreadSummary{Thread: models.Thread{ID: uuid.New()}, LastActivityAt: now.Add(time.Minute)}
+	thread := &models.Thread{ID: newest.ID, UserID: user.ID, Messages: []models.Message{{ID: uuid.New()}, {ID: uuid.New()}}}
+
+	t.Run("loads the credits and the last active thread", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		credits := new(stubCredits)
+		service := NewBootstrapServiceForTest(nil, threadRepo, credits)
+
+		credits.On("GetCredits", user.ID).Return(&models.Credits{Balance: 42}, nil)
+		threadRepo.On("FindSummariesByUserID", mock.Anything, user.ID).Return([]models.ThreadSummary{older, empty, newest}, nil)
+		threadRepo.On("FindByIDAndUserIDWithMessages", mock.Anything, newest.ID, user.ID, false).Return(thread, nil)
+
+		bootstrap := service.GetBootstrap(user)
+
+		assert.Empty(t, bootstrap.Unavailable)
+		assert.Equal(t, 42, bootstrap.Credits.Balance)
+		require.NotNil(t, bootstrap.LastThread)
+		assert.Equal(t, newest.ID, bootstrap.LastThread.ID, "the newest thread with messages")
+	})
+
+	t.Run("serves the thread from the cache on the next open", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		credits := new(stubCredits)
+		service := NewBootstrapServiceForTest(nil, threadRepo, credits)
+		service.Threads = repository.NewThreadCache(time.Minute)
+
+		credits.On("GetCredits", user.ID).Return(&models.Credits{}, nil)
+		threadRepo.On("FindSummariesByUserID", mock.Anything, user.ID).Return([]models.ThreadSummary{newest}, nil).Once()
+		threadRepo.On("FindByIDAndUserIDWithMessages", mock.Anything, newest.ID, user.ID, false).Return(thread, nil).Once()
+
+		service.GetBootstrap(user)
+		bootstrap := service.GetBootstrap(user)
+
+		require.NotNil(t, bootstrap.LastThread)
+		assert.Len(t, bootstrap.LastThread.Messages, 2)
+		threadRepo.AssertExpectations(t)
+	})
+
+	t.Run("a failing section is reported, not fatal", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		credits := new(stubCredits)
+		service := NewBootstrapServiceForTest(nil, threadRepo, credits)
+
+		credits.On("GetCredits", user.ID).Return(nil, errors.New("db down"))
+		threadRepo.On("FindSummariesByUserID", mock.Anything, user.ID).Return([]models.ThreadSummary{}, nil)
+
+		bootstrap := service.GetBootstrap(user)
+
+		assert.Equal(t, []string{BootstrapSectionCredits}, bootstrap.Unavailable)
+		assert.Nil(t, bootstrap.LastThread, "no thread with messages yet")
+	})
+}
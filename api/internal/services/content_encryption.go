@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/repository"
+)
+
+// contentSealBatchSize caps how many rows are sealed per query
+const contentSealBatchSize = 100
+
+// ContentEncryptionWorker seals the messages a user wrote before turning on
+// content encryption. New content is sealed as it is written; this catches up
+// on what came before, a batch at a time, so a large history doesn't hold one
+// long transaction.
+type ContentEncryptionWorker struct {
+	exec     repository.Executor
+	repo     repository.ContentEncryptionRepository
+	interval time.Duration
+}
+
+// NewContentEncryptionWorker creates a new content encryption worker
+func NewContentEncryptionWorker(database *db.DB, repo repository.ContentEncryptionRepository, interval time.Duration) *ContentEncryptionWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &ContentEncryptionWorker{
+		exec:     database.DB,
+		repo:     repo,
+		interval: interval,
+	}
+}
+
+// NewContentEncryptionWorkerForTest creates a ContentEncryptionWorker with injected dependencies for testing.
+func NewContentEncryptionWorkerForTest(exec repository.Executor, repo repository.ContentEncryptionRepository, interval time.Duration) *ContentEncryptionWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &ContentEncryptionWorker{
+		exec:     exec,
+		repo:     repo,
+		interval: interval,
+	}
+}
+
+// Start seals pending content until ctx is cancelled
+func (w *ContentEncryptionWorker) Start(ctx context.Context) {
+	log.Printf("[ContentEncryption] Sealing existing content every %s", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.SealPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SealPending seals batches of messages, then of long-form chunks, until
+// none are left or a batch fails, and returns how many rows were sealed
+func (w *ContentEncryptionWorker) SealPending(ctx context.Context) int {
+	sealed := w.drain(ctx, "messages", w.repo.SealPendingMessages)
+	sealed += w.drain(ctx, "long-form chunks", w.repo.SealPendingChunks)
+
+	if sealed > 0 {
+		log.Printf("[ContentEncryption] Sealed %d rows", sealed)
+	}
+	return sealed
+}
+
+func (w *ContentEncryptionWorker) drain(ctx context.Context, what string, seal func(repository.Executor, int) (int, error)) int {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := seal(w.exec, contentSealBatchSize)
+		total += n
+		if err != nil {
+			log.Printf("[ContentEncryption] Failed to seal %s: %v", what, err)
+			break
+		}
+		if n < contentSealBatchSize {
+			break
+		}
+	}
+	return total
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestContentEncryptionWorker_SealPending(t *testing.T) {
+	t.Run("repeats full batches until one comes back short", func(t *testing.T) {
+		repo := new(repomocks.MockContentEncryptionRepository)
+		repo.On("SealPendingMessages", mock.Anything, contentSealBatchSize).Return(contentSealBatchSize, nil).Twice()
+		repo.On("SealPendingMessages", mock.Anything, contentSealBatchSize).Return(7, nil).Once()
+		repo.On("SealPendingChunks", mock.Anything, contentSealBatchSize).Return(0, nil).Once()
+
+		worker := NewContentEncryptionWorkerForTest(nil, repo, 0)
+
+		assert.Equal(t, 2*contentSealBatchSize+7, worker.SealPending(context.Background()))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("a failed batch stops that table until the next sweep", func(t *testing.T) {
+		repo := new(repomocks.MockContentEncryptionRepository)
+		repo.On("SealPendingMessages", mock.Anything, contentSealBatchSize).Return(3, errors.New("no content key")).Once()
+		repo.On("SealPendingChunks", mock.Anything, contentSealBatchSize).Return(2, nil).Once()
+
+		worker := NewContentEncryptionWorkerForTest(nil, repo, 0)
+
+		assert.Equal(t, 5, worker.SealPending(context.Background()))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		repo := new(repomocks.MockContentEncryptionRepository)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		worker := NewContentEncryptionWorkerForTest(nil, repo, 0)
+
+		assert.Equal(t, 0, worker.SealPending(ctx))
+		repo.AssertNotCalled(t, "SealPendingMessages", mock.Anything, mock.Anything)
+	})
+}
@@ -3,13 +3,18 @@ package services
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"mime/multipart"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"ling-app/api/internal/client"
+	"ling-app/api/internal/events"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
 
@@ -18,7 +23,39 @@ import (
 
 // ConversationProcessor defines the interface for processing conversation messages
 type ConversationProcessor interface {
-	ProcessAudioMessage(ctx context.Context, threadID uuid.UUID, audioFile multipart.File, fileHeader *multipart.FileHeader) (*ConversationTurn, error)
+	ProcessAudioMessage(ctx context.Context, threadID uuid.UUID, audioFile multipart.File, fileHeader *multipart.FileHeader, expectedText string) (*ConversationTurn, error)
+	StreamAudioMessage(ctx context.Context, threadID uuid.UUID, audioFile multipart.File, fileHeader *multipart.FileHeader, expectedText string, progress TurnProgress) (*ConversationTurn, error)
+	SendTextMessage(ctx context.Context, threadID uuid.UUID, content string, speak bool) (*ConversationTurn, error)
+}
+
+// MaxTextMessageLength is the longest typed message accepted, in characters
+const MaxTextMessageLength = 2000
+
+// Turn event types, in the order a voice turn emits them
+const (
+	TurnEventTranscribed = "transcribed" // the user message is saved with its transcript
+	TurnEventToken       = "token"       // a piece of the reply as the LLM writes it
+	TurnEventRetracted   = "retracted"   // the streamed reply failed the safety check and is being rewritten
+	TurnEventReply       = "reply"       // the final reply text, about to be spoken
+)
+
+// TurnEvent is an intermediate result of a voice turn
+type TurnEvent struct {
+	Type    string          `json:"-"`
+	Message *models.Message `json:"message,omitempty"` // TurnEventTranscribed
+	Text    string          `json:"text,omitempty"`    // TurnEventToken and TurnEventReply
+}
+
+// TurnProgress receives a voice turn's events as they happen, on the
+// goroutine processing the turn. Streamed tokens are a draft: they include
+// any tone or correction markup, and the reply event has the text to keep.
+type TurnProgress func(TurnEvent)
+
+// emit sends event to progress, if anyone is listening
+func (progress TurnProgress) emit(event TurnEvent) {
+	if progress != nil {
+		progress(event)
+	}
 }
 
 // ConversationService handles audio message processing and AI conversation flow
@@ -31,13 +68,106 @@ type ConversationService struct {
 	ttsClient           client.TTSClient
 	storage             client.StorageClient
 	pronunciationWorker *PronunciationWorker
-	maxAudioFileSize    int64
+	credits             CreditsManager
+	safety              *OutputSafetyChecker
+	runtime             *RuntimeSettingsService
+
+	// Memory adds what the assistant remembers about the learner to the
+	// prompt (optional)
+	Memory *LearnerProfileService
+
+	// Adaptation pitches each reply to how the learner is doing (optional)
+	Adaptation *AdaptationService
+
+	// Normalizer punctuates unpunctuated transcripts before they are saved
+	// (optional)
+	Normalizer TranscriptNormalizer
+
+	// Settings supplies the user's preferred reply length and speaking rate
+	// (optional; threads without it use the defaults and their overrides)
+	Settings SettingsManager
+
+	// Tones has the LLM pick a tone for each reply, which sets how
+	// expressive the TTS voice is and is stored on the message (optional)
+	Tones *TonePolicy
+
+	// Citations has the LLM cite the learner's words each reply corrects,
+	// stored on the reply as spans of their message (optional)
+	Citations *CorrectionCitations
+
+	// Events receives MessageProcessed after each answered turn (optional)
+	Events *events.Bus
+
+	// ThreadCaps decides when a thread has run long enough that the
+	// assistant wraps it up and it ends (optional; threads never end without it)
+	ThreadCaps ThreadCapper
+
+	// LowBitrateAudio also stores a low-bitrate Opus copy of each reply for
+	// listeners on mobile data, when the TTS backend can make one
+	LowBitrateAudio bool
 }
 
 // ConversationTurn represents a complete user-assistant conversation exchange
 type ConversationTurn struct {
 	UserMessage      *models.Message `json:"userMessage"`
 	AssistantMessage *models.Message `json:"assistantMessage"`
+
+	// Credits charged for the turn
+	Credits int `json:"-"`
+
+	// The thread reached its cap and ended with this turn; the learner
+	// continues in a new thread
+	ThreadEnded bool `json:"threadEnded"`
+
+	// The messages' text was left out for a speech-only thread
+	TextWithheld bool `json:"textWithheld"`
+
+	// The reply wasn't meant to be spoken (a typed turn that didn't ask for
+	// speech), so it has no audio by design
+	Silent bool `json:"-"`
+}
+
+// WithoutText returns the turn with the text of both messages left out, for
+// speech-only threads: transcripts, reply text, suggested replies and
+// correction highlights. The stored messages keep all of it.
+func (t *ConversationTurn) WithoutText() *ConversationTurn {
+	withheld := *t
+	withheld.UserMessage = withoutText(t.UserMessage)
+	withheld.AssistantMessage = withoutText(t.AssistantMessage)
+	withheld.TextWithheld = true
+	return &withheld
+}
+
+// WithoutText returns the event as sent to a speech-only thread: the
+// transcribed message without its text, and false for the events that are
+// nothing but text
+func (e TurnEvent) WithoutText() (TurnEvent, bool) {
+	if e.Type != TurnEventTranscribed {
+		return e, false
+	}
+	e.Message = withoutText(e.Message)
+	return e, true
+}
+
+// withoutText copies the message without its text
+func withoutText(message *models.Message) *models.Message {
+	if message == nil {
+		return nil
+	}
+	withheld := *message
+	withheld.Content = ""
+	withheld.SpokenText = nil
+	withheld.RawTranscript = nil
+	withheld.SuggestedReplies = nil
+	withheld.Corrections = nil
+	if len(message.Chunks) > 0 {
+		withheld.Chunks = make([]models.MessageChunk, len(message.Chunks))
+		for i, chunk := range message.Chunks {
+			chunk.Transcript = ""
+			withheld.Chunks[i] = chunk
+		}
+	}
+	return &withheld
 }
 
 // NewConversationService creates a new conversation service
@@ -50,7 +180,9 @@ func NewConversationService(
 	ttsClient client.TTSClient,
 	storage client.StorageClient,
 	pronunciationWorker *PronunciationWorker,
-	maxAudioFileSize int64,
+	credits CreditsManager,
+	safety *OutputSafetyChecker,
+	runtime *RuntimeSettingsService,
 ) *ConversationService {
 	return &ConversationService{
 		exec:                exec,
@@ -61,38 +193,137 @@ func NewConversationService(
 		ttsClient:           ttsClient,
 		storage:             storage,
 		pronunciationWorker: pronunciationWorker,
-		maxAudioFileSize:    maxAudioFileSize,
+		credits:             credits,
+		safety:              safety,
+		runtime:             runtime,
 	}
 }
 
 // ProcessAudioMessage handles the complete flow of processing an audio message
-// and generating an AI response with TTS audio
+// and generating an AI response with TTS audio. expectedText is the line the
+// user was asked to say (practice mode); empty means free conversation, where
+// pronunciation is scored against the transcript itself.
+//
+// The message's credits are reserved before any work starts, referenced by
+// the user message ID. They are captured once the assistant replies, and
+// released if the turn fails before then.
 func (s *ConversationService) ProcessAudioMessage(
 	ctx context.Context,
 	threadID uuid.UUID,
 	audioFile multipart.File,
 	fileHeader *multipart.FileHeader,
+	expectedText string,
 ) (*ConversationTurn, error) {
+	return s.StreamAudioMessage(ctx, threadID, audioFile, fileHeader, expectedText, nil)
+}
+
+// StreamAudioMessage is ProcessAudioMessage reporting its progress as it
+// goes: the transcript once the user message is saved, the reply as the LLM
+// writes it (when the client can stream) and the final reply before it is
+// spoken. The returned turn is the same.
+func (s *ConversationService) StreamAudioMessage(
+	ctx context.Context,
+	threadID uuid.UUID,
+	audioFile multipart.File,
+	fileHeader *multipart.FileHeader,
+	expectedText string,
+	progress TurnProgress,
+) (*ConversationTurn, error) {
+	// One snapshot for the whole turn, so its limits and cost can't change mid-way
+	settings := s.runtime.Current()
+
 	// Validate file size
-	if s.maxAudioFileSize > 0 && fileHeader.Size > s.maxAudioFileSize {
-		return nil, fmt.Errorf("audio file too large: %d bytes (max: %d)", fileHeader.Size, s.maxAudioFileSize)
+	if fileHeader.Size > settings.MaxAudioFileSize {
+		return nil, fmt.Errorf("audio file too large: %d bytes (max: %d)", fileHeader.Size, settings.MaxAudioFileSize)
+	}
+
+	// Create user message ID
+	userMessageID := uuid.New()
+
+	// Reserve up front so concurrent requests can't spend the same credit
+	cost := settings.CreditCostPerMessage
+	hold, err := s.reserveTurn(threadID, userMessageID, cost, "Voice message")
+	if err != nil {
+		return nil, err
 	}
 
 	// Process user audio message
-	userMessage, err := s.processUserAudio(ctx, threadID, audioFile, fileHeader)
+	userMessage, err := s.processUserAudio(ctx, threadID, userMessageID, audioFile, fileHeader, expectedText, settings)
 	if err != nil {
+		s.releaseTurn(hold, releaseReason(err))
 		return nil, fmt.Errorf("failed to process user audio: %w", err)
 	}
+	progress.emit(TurnEvent{Type: TurnEventTranscribed, Message: userMessage})
 
 	// Generate assistant response
-	assistantMessage, err := s.generateAssistantResponse(ctx, threadID)
+	assistantMessage, ended, err := s.generateAssistantResponse(ctx, threadID, true, progress)
 	if err != nil {
+		s.releaseTurn(hold, "no reply was generated")
 		return nil, fmt.Errorf("failed to generate assistant response: %w", err)
 	}
 
+	charged := s.settleTurn(threadID, hold, cost, assistantMessage, true)
+	charged = s.refundLowConfidence(userMessageID, charged)
+	s.publishProcessed(ctx, threadID, userMessageID, payer(hold), "", charged)
+
 	return &ConversationTurn{
 		UserMessage:      userMessage,
 		AssistantMessage: assistantMessage,
+		Credits:          charged,
+		ThreadEnded:      ended,
+	}, nil
+}
+
+// SendTextMessage handles a typed turn, for when the user can't speak: the
+// text is saved as the user message, with nothing to transcribe or score,
+// and the assistant replies as it does to a voice message. The reply is
+// spoken only when speak is set, or always in a speech-only thread, which
+// has no text to show. Typed messages cost CreditCostPerTextMessage, reserved
+// and settled like voice messages.
+func (s *ConversationService) SendTextMessage(ctx context.Context, threadID uuid.UUID, content string, speak bool) (*ConversationTurn, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > MaxTextMessageLength {
+		return nil, ErrInvalidTextMessage
+	}
+	if thread := s.findThread(threadID); thread != nil && thread.SpeechOnly {
+		speak = true
+	}
+
+	userMessageID := uuid.New()
+	cost := s.runtime.Current().CreditCostPerTextMessage
+	hold, err := s.reserveTurn(threadID, userMessageID, cost, "Text message")
+	if err != nil {
+		return nil, err
+	}
+
+	userMessage := models.Message{
+		ID:        userMessageID,
+		ThreadID:  threadID,
+		Role:      "user",
+		Content:   content,
+		Kind:      models.MessageKindText,
+		Timestamp: time.Now(),
+	}
+	if err := s.messageRepo.Create(s.exec, &userMessage); err != nil {
+		s.releaseTurn(hold, "text message could not be saved")
+		return nil, &VoiceTurnError{Stage: StagePersist, Err: fmt.Errorf("failed to create message: %w", err)}
+	}
+
+	assistantMessage, ended, err := s.generateAssistantResponse(ctx, threadID, speak, nil)
+	if err != nil {
+		s.releaseTurn(hold, "no reply was generated")
+		return nil, fmt.Errorf("failed to generate assistant response: %w", err)
+	}
+
+	charged := s.settleTurn(threadID, hold, cost, assistantMessage, speak)
+	s.publishProcessed(ctx, threadID, userMessageID, payer(hold), models.MessageKindText, charged)
+
+	return &ConversationTurn{
+		UserMessage:      &userMessage,
+		AssistantMessage: assistantMessage,
+		Credits:          charged,
+		ThreadEnded:      ended,
+		Silent:           !speak,
 	}, nil
 }
 
@@ -100,118 +331,630 @@ func (s *ConversationService) ProcessAudioMessage(
 func (s *ConversationService) processUserAudio(
 	ctx context.Context,
 	threadID uuid.UUID,
+	userMessageID uuid.UUID,
 	audioFile multipart.File,
 	fileHeader *multipart.FileHeader,
+	expectedText string,
+	settings RuntimeSettings,
 ) (*models.Message, error) {
-	// Create user message ID
-	userMessageID := uuid.New()
-
-	// Upload user audio to storage
-	userAudioKey := fmt.Sprintf("user/%s/%s.webm", threadID, userMessageID)
-	_, err := s.storage.UploadAudio(ctx, audioFile, userAudioKey, "audio/webm")
+	userAudioKey := models.UserAudioKey(threadID, userMessageID)
+	thread := s.findThread(threadID)
+	transcription, err := s.uploadAndTranscribe(ctx, audioFile, userAudioKey, TranscriptionLanguage(thread))
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload audio: %w", err)
+		return nil, err
 	}
 
-	// Get presigned URL for ML service to access the audio
-	audioPresignedURL, err := s.storage.GetPresignedURL(ctx, userAudioKey, 5*time.Minute)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get presigned URL: %w", err)
+	// Validate audio duration
+	if transcription.Duration < settings.MinAudioDurationSeconds {
+		return nil, &AudioDurationError{Err: ErrAudioTooShort, Seconds: transcription.Duration, Limit: settings.MinAudioDurationSeconds}
 	}
-
-	// Transcribe audio
-	transcription, err := s.whisperClient.TranscribeFromURL(ctx, audioPresignedURL)
-	if err != nil {
-		// Check if error indicates audio is too short
-		errMsg := err.Error()
-		if strings.Contains(errMsg, "too short") || strings.Contains(errMsg, "AUDIO_TOO_SHORT") {
-			return nil, ErrAudioTooShort
-		}
-		return nil, fmt.Errorf("failed to transcribe audio: %w", err)
+	if transcription.Duration > settings.MaxAudioDurationSeconds {
+		return nil, &AudioDurationError{Err: ErrAudioTooLong, Seconds: transcription.Duration, Limit: settings.MaxAudioDurationSeconds}
 	}
 
-	// Validate audio duration
-	if transcription.Duration < 1.0 {
-		return nil, ErrAudioTooShort
-	}
-	if transcription.Duration > 30.0 {
-		return nil, ErrAudioTooLong
+	// Score against the practice line when given, unless the user went so far
+	// off-script that phoneme alignment would be meaningless
+	pronunciationStatus := "pending"
+	scoringText := transcription.Text
+	var expected *string
+	if expectedText = strings.TrimSpace(expectedText); expectedText != "" {
+		expected = &expectedText
+		scoringText = expectedText
+		if TranscriptDiverges(expectedText, transcription.Text) {
+			pronunciationStatus = "skipped_divergent"
+		}
 	}
 
-	// Save user message with audio (pronunciation analysis pending)
+	// Save user message with audio; scoring above keeps the raw transcript
+	content, rawTranscript := s.normalizeTranscript(ctx, threadID, transcription.Text)
 	userMessage := models.Message{
 		ID:                   userMessageID,
 		ThreadID:             threadID,
 		Role:                 "user",
-		Content:              transcription.Text,
+		Content:              content,
+		RawTranscript:        rawTranscript,
 		AudioURL:             &userAudioKey,
+		AudioFormat:          models.AudioFormatWebM,
 		AudioDurationSeconds: &transcription.Duration,
 		HasAudio:             true,
 		Timestamp:            time.Now(),
-		PronunciationStatus:  "pending",
+		ExpectedText:         expected,
+		PronunciationStatus:  pronunciationStatus,
 	}
 
 	if err := s.messageRepo.Create(s.exec, &userMessage); err != nil {
-		return nil, fmt.Errorf("failed to create message: %w", err)
+		return nil, &VoiceTurnError{Stage: StagePersist, Err: fmt.Errorf("failed to create message: %w", err)}
 	}
 
-	// Spawn pronunciation analysis in background (non-blocking)
-	if s.pronunciationWorker != nil {
-		go s.pronunciationWorker.AnalyzeAsync(userMessageID, userAudioKey, transcription.Text, "en-us")
+	// Queue pronunciation analysis in background (non-blocking)
+	if s.pronunciationWorker != nil && pronunciationStatus == "pending" {
+		s.pronunciationWorker.Enqueue(threadID, userMessageID, userAudioKey, scoringText, PhonemeLanguage(ThreadLanguage(thread)))
 	}
 
 	return &userMessage, nil
 }
 
-// generateAssistantResponse generates AI response with TTS audio
+// normalizeTranscript returns the content to store for a transcript and, when
+// normalization changed it, the raw transcript to keep alongside. Any failure,
+// or a result whose words differ from the raw text, keeps the raw transcript.
+func (s *ConversationService) normalizeTranscript(ctx context.Context, threadID uuid.UUID, raw string) (string, *string) {
+	if s.Normalizer == nil || !TranscriptNeedsPunctuation(raw) {
+		return raw, nil
+	}
+
+	normalized, err := s.Normalizer.Normalize(ctx, raw, ThreadLocale(s.findThread(threadID)))
+	if err != nil {
+		log.Printf("Error normalizing transcript for thread %s: %v", threadID, err)
+		return raw, nil
+	}
+	normalized = strings.TrimSpace(normalized)
+	if normalized == "" || normalized == raw {
+		return raw, nil
+	}
+	if !SameWords(raw, normalized) {
+		log.Printf("Discarding transcript normalization for thread %s: words changed", threadID)
+		return raw, nil
+	}
+	return normalized, &raw
+}
+
+// uploadAndTranscribe stores a user recording under key and transcribes it
+// in language, or the language Whisper detects when empty
+func (s *ConversationService) uploadAndTranscribe(ctx context.Context, audio io.Reader, key, language string) (*client.TranscriptionResult, error) {
+	// Upload user audio to storage
+	_, err := s.storage.UploadAudio(ctx, audio, key, "audio/webm")
+	if err != nil {
+		return nil, &VoiceTurnError{Stage: StageUpload, Err: fmt.Errorf("failed to upload audio: %w", err)}
+	}
+
+	// Get presigned URL for ML service to access the audio
+	audioPresignedURL, err := s.storage.GetPresignedURL(ctx, key, 5*time.Minute)
+	if err != nil {
+		return nil, &VoiceTurnError{Stage: StagePresign, Err: fmt.Errorf("failed to get presigned URL: %w", err)}
+	}
+
+	// Transcribe audio
+	transcription, err := s.whisperClient.TranscribeFromURL(ctx, audioPresignedURL, language)
+	if err != nil {
+		// Check if error indicates audio is too short
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "too short") || strings.Contains(errMsg, "AUDIO_TOO_SHORT") {
+			return nil, ErrAudioTooShort
+		}
+		return nil, &VoiceTurnError{Stage: StageTranscribe, Err: fmt.Errorf("failed to transcribe audio: %w", err)}
+	}
+	return transcription, nil
+}
+
+// generateAssistantResponse generates AI response with TTS audio. Once the
+// thread reaches its tier's cap the reply wraps the conversation up, and ended
+// reports that the thread was closed after it. The reply is only spoken when
+// speak is set. progress, when set, follows the reply as it is written.
 func (s *ConversationService) generateAssistantResponse(
 	ctx context.Context,
 	threadID uuid.UUID,
-) (*models.Message, error) {
+	speak bool,
+	progress TurnProgress,
+) (message *models.Message, ended bool, err error) {
 	// Get conversation history
 	messages, err := s.messageRepo.FindByThreadID(s.exec, threadID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+		return nil, false, &VoiceTurnError{Stage: StageGenerate, Err: fmt.Errorf("failed to fetch messages: %w", err)}
+	}
+
+	thread := s.findThread(threadID)
+	wrapUp := s.threadCapReached(thread, messages)
+
+	message, err = s.reply(ctx, threadID, thread, messages, wrapUp, speak, progress)
+	if err != nil || !wrapUp {
+		return message, false, err
+	}
+
+	ended, err = s.threadRepo.MarkEnded(s.exec, threadID, time.Now())
+	if err != nil {
+		// The reply already said goodbye; the next turn will try again
+		log.Printf("Error ending thread %s: %v", threadID, err)
+	}
+	return message, ended, nil
+}
+
+// threadCapReached reports whether this turn is the thread's last
+func (s *ConversationService) threadCapReached(thread *models.Thread, messages []models.Message) bool {
+	if s.ThreadCaps == nil || thread == nil {
+		return false
+	}
+	reached, err := s.ThreadCaps.ThreadCapReached(thread.UserID, messages)
+	if err != nil {
+		log.Printf("Error checking thread cap for %s: %v", thread.ID, err)
+		return false
 	}
+	return reached
+}
 
-	// Convert to OpenAI format
-	conversationHistory := make([]client.ConversationMessage, len(messages))
-	for i, msg := range messages {
-		conversationHistory[i] = client.ConversationMessage{
+// reply generates, speaks (when speak is set) and saves the assistant's
+// reply to messages
+func (s *ConversationService) reply(
+	ctx context.Context,
+	threadID uuid.UUID,
+	thread *models.Thread,
+	messages []models.Message,
+	wrapUp bool,
+	speak bool,
+	progress TurnProgress,
+) (*models.Message, error) {
+	// Convert to OpenAI format, leading with the language to speak, what the
+	// assistant remembers about the learner and the thread's goal if it has one
+	conversationHistory := make([]client.ConversationMessage, 0, len(messages)+3)
+	if prompt := LanguageSystemPrompt(ThreadLanguage(thread)); prompt != nil {
+		conversationHistory = append(conversationHistory, *prompt)
+	}
+	if thread != nil && s.Memory != nil {
+		if memory := s.Memory.SystemPrompt(thread.UserID); memory != nil {
+			conversationHistory = append(conversationHistory, *memory)
+		}
+	}
+	if thread != nil && thread.Goal != nil && thread.GoalCompletedAt == nil {
+		conversationHistory = append(conversationHistory, GoalSystemPrompt(*thread.Goal))
+	}
+	for _, msg := range messages {
+		conversationHistory = append(conversationHistory, client.ConversationMessage{
 			Role:    msg.Role,
 			Content: msg.Content,
+		})
+	}
+
+	// Keep to the learner's preferred reply length, then simplify or stretch
+	// the reply depending on how they are doing
+	style := s.replyStyle(thread)
+	var instructions []client.ConversationMessage
+	if s.Tones != nil {
+		instructions = append(instructions, s.Tones.SystemPrompt())
+	}
+	if s.Citations != nil {
+		instructions = append(instructions, s.Citations.SystemPrompt())
+	}
+	if prompt := style.SystemPrompt(); prompt != nil {
+		instructions = append(instructions, *prompt)
+	}
+	var adaptation *Adaptation
+	if s.Adaptation != nil {
+		decision := s.Adaptation.Decide(messages)
+		if _, ok := s.ttsClient.(client.RateSynthesizer); !ok {
+			decision.SpeechRate = 1.0 // the TTS backend can't change speed
 		}
+		adaptation = &decision
+		if prompt := decision.SystemPrompt(); prompt != nil {
+			instructions = append(instructions, *prompt)
+		}
+	}
+	if wrapUp {
+		instructions = append(instructions, ThreadWrapUpPrompt())
+	}
+	generationHistory := conversationHistory
+	if len(instructions) > 0 {
+		generationHistory = append(slices.Clone(conversationHistory), instructions...)
+	}
+	var adaptationDetails models.JSONMap
+	if adaptation != nil {
+		adaptationDetails = adaptation.Details()
 	}
 
-	// Generate AI response
-	aiResponse, err := s.openAIClient.Generate(conversationHistory)
+	// Generate AI response, screened before it is spoken or stored
+	aiResponse, err := s.generateSafeResponse(ctx, threadID, generationHistory, progress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate AI response: %w", err)
+		return nil, err
+	}
+	var tone string
+	var voice *TTSVoice
+	if s.Tones != nil {
+		tone, aiResponse = s.Tones.Parse(aiResponse)
+		v := s.Tones.Voice(tone)
+		voice = &v
+	}
+	var corrections models.CorrectionSpans
+	var correctedMessageID *uuid.UUID
+	if s.Citations != nil {
+		learner := lastUserMessage(messages)
+		learnerText := ""
+		if learner != nil {
+			learnerText = learner.Content
+		}
+		aiResponse, corrections = s.Citations.Parse(aiResponse, learnerText)
+		if corrections != nil {
+			correctedMessageID = &learner.ID
+		}
 	}
 
+	progress.emit(TurnEvent{Type: TurnEventReply, Text: aiResponse})
+
 	assistantMessageID := uuid.New()
 
-	// Try to generate TTS for AI response
-	ttsResult, err := s.ttsClient.Synthesize(ctx, aiResponse)
+	// Suggested learner replies (roleplay scaffolding) - best effort, no extra credits
+	var suggestions models.StringList
+	if thread != nil && thread.SuggestReplies {
+		history := append(conversationHistory, client.ConversationMessage{Role: "assistant", Content: aiResponse})
+		suggestions, err = s.openAIClient.SuggestReplies(history)
+		if err != nil {
+			log.Printf("Error generating suggested replies: %v", err)
+			suggestions = nil
+		}
+		suggestions = s.safeSuggestions(ctx, threadID, suggestions)
+	}
+
+	if !speak {
+		return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, nil, nil, nil, false, suggestions, adaptationDetails, tone, corrections, correctedMessageID)
+	}
+
+	// Try to generate TTS for AI response in the thread's language, read the
+	// way its locale says numbers and dates
+	tts := s.ttsFor(thread)
+	if tts == nil {
+		log.Printf("No TTS voice for thread %s's language %q, replying without audio", threadID, ThreadLanguage(thread))
+		return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, nil, nil, nil, false, suggestions, adaptationDetails, tone, corrections, correctedMessageID)
+	}
+	spokenText := SpeechNormalizerFor(ThreadLocale(thread)).Normalize(aiResponse)
+	rate := style.CombinedSpeechRate(adaptation)
+	lowBitrate := s.synthesizeLowBitrate(ctx, tts, spokenText, rate)
+	ttsResult, err := s.synthesize(ctx, tts, spokenText, rate, voice)
 	if err != nil {
 		log.Printf("Error generating TTS: %v", err)
 		// Continue without audio - save text-only response
-		return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, nil, nil, false)
+		return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, nil, nil, nil, false, suggestions, adaptationDetails, tone, corrections, correctedMessageID)
 	}
 
 	// Upload TTS audio to storage
-	assistantAudioKey := fmt.Sprintf("assistant/%s/%s.mp3", threadID, assistantMessageID)
+	assistantAudioKey := models.AssistantAudioKey(threadID, assistantMessageID)
 	audioReader := bytes.NewReader(ttsResult.AudioBytes)
 	_, err = s.storage.UploadAudio(ctx, audioReader, assistantAudioKey, "audio/mpeg")
 	if err != nil {
 		log.Printf("Error uploading TTS audio: %v", err)
 		// Continue without audio
-		return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, nil, nil, false)
+		return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, nil, nil, nil, false, suggestions, adaptationDetails, tone, corrections, correctedMessageID)
 	}
+	s.uploadLowBitrate(ctx, lowBitrate, assistantAudioKey)
 
-	// Save AI response with audio
+	// Save AI response with audio, keeping the spoken text when it differs
+	// so the audio can be aligned against what was actually said
+	var spoken *string
+	if spokenText != aiResponse {
+		spoken = &spokenText
+	}
 	ttsDuration := ttsResult.Duration
-	return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, &assistantAudioKey, &ttsDuration, true)
+	return s.createAssistantMessage(assistantMessageID, threadID, aiResponse, spoken, &assistantAudioKey, &ttsDuration, true, suggestions, adaptationDetails, tone, corrections, correctedMessageID)
+}
+
+// ttsFor returns the TTS client speaking the thread's language, or nil when
+// the backend only speaks English and the thread practises another language
+func (s *ConversationService) ttsFor(thread *models.Thread) client.TTSClient {
+	language := ThreadLanguage(thread)
+	if language == DefaultLanguage {
+		return s.ttsClient
+	}
+	speaker, ok := s.ttsClient.(client.LanguageSynthesizer)
+	if !ok {
+		return nil
+	}
+	return speaker.ForLanguage(language)
+}
+
+// synthesize speaks the reply with tts at rate, when the backend can change
+// speed, and in voice when given. Backends that can change speed have no
+// exaggeration setting, so the two never apply together.
+func (s *ConversationService) synthesize(ctx context.Context, tts client.TTSClient, text string, rate float64, voice *TTSVoice) (*client.TTSResult, error) {
+	if rate != 1.0 {
+		if rater, ok := tts.(client.RateSynthesizer); ok {
+			return rater.SynthesizeAtRate(ctx, text, rate)
+		}
+	}
+	if voice != nil {
+		return tts.SynthesizeWithOptions(ctx, text, voice.Exaggeration, voice.Format)
+	}
+	return tts.Synthesize(ctx, text)
+}
+
+// synthesizeLowBitrate starts speaking the low-bitrate variant alongside the
+// standard audio. The channel yields nil if it failed, and is nil itself when
+// the variant is off or the TTS backend can't make one.
+func (s *ConversationService) synthesizeLowBitrate(ctx context.Context, tts client.TTSClient, text string, rate float64) <-chan *client.TTSResult {
+	if !s.LowBitrateAudio {
+		return nil
+	}
+	synthesizer, ok := tts.(client.LowBitrateSynthesizer)
+	if !ok {
+		return nil
+	}
+	result := make(chan *client.TTSResult, 1)
+	go func() {
+		audio, err := synthesizer.SynthesizeLowBitrate(ctx, text, rate)
+		if err != nil {
+			log.Printf("Error generating low-bitrate TTS: %v", err)
+		}
+		result <- audio
+	}()
+	return result
+}
+
+// uploadLowBitrate stores the variant next to the reply's standard audio.
+// Best effort: without it clients asking for low quality get the standard file.
+func (s *ConversationService) uploadLowBitrate(ctx context.Context, lowBitrate <-chan *client.TTSResult, key string) {
+	if lowBitrate == nil {
+		return
+	}
+	audio := <-lowBitrate
+	if audio == nil {
+		return
+	}
+	if _, err := s.storage.UploadAudio(ctx, bytes.NewReader(audio.AudioBytes), models.LowBitrateAudioKey(key), "audio/ogg"); err != nil {
+		log.Printf("Error uploading low-bitrate TTS audio: %v", err)
+	}
+}
+
+// replyStyle resolves the reply length and speaking rate for the thread
+func (s *ConversationService) replyStyle(thread *models.Thread) ReplyStyle {
+	if thread == nil || s.Settings == nil {
+		return ResolveReplyStyle(nil, thread)
+	}
+	settings, err := s.Settings.GetSettings(thread.UserID)
+	if err != nil {
+		log.Printf("Error fetching reply settings: %v", err)
+		return ResolveReplyStyle(nil, thread)
+	}
+	return ResolveReplyStyle(settings, thread)
+}
+
+// generateSafeResponse generates the assistant reply and runs it through the
+// output safety check, regenerating a flagged reply up to
+// MaxSafetyRegenerations times before falling back to SafeFallbackResponse.
+// Only the first attempt is streamed to progress; a flagged reply is
+// retracted and its rewrites arrive with the reply event.
+func (s *ConversationService) generateSafeResponse(
+	ctx context.Context,
+	threadID uuid.UUID,
+	history []client.ConversationMessage,
+	progress TurnProgress,
+) (string, error) {
+	aiResponse, err := s.generate(ctx, history, progress)
+	if err != nil {
+		return "", &VoiceTurnError{Stage: StageGenerate, Err: fmt.Errorf("failed to generate AI response: %w", err)}
+	}
+	if s.safety == nil {
+		return aiResponse, nil
+	}
+
+	// Full slice expression so the retry prompt never lands in history's spare capacity
+	retryHistory := append(history[:len(history):len(history)], SafetyRetryPrompt())
+	for attempt := 1; ; attempt++ {
+		verdict := s.safety.Check(ctx, aiResponse)
+		if verdict.Safe {
+			return aiResponse, nil
+		}
+		s.safety.RecordIncident(threadID, models.SafetyIncidentResponse, verdict, attempt)
+		if attempt == 1 {
+			progress.emit(TurnEvent{Type: TurnEventRetracted})
+		}
+
+		if attempt > MaxSafetyRegenerations {
+			return SafeFallbackResponse, nil
+		}
+		aiResponse, err = s.openAIClient.Generate(retryHistory)
+		if err != nil {
+			return "", &VoiceTurnError{Stage: StageGenerate, Err: fmt.Errorf("failed to regenerate AI response: %w", err)}
+		}
+	}
+}
+
+// generate writes the reply, streaming it to progress when someone is
+// listening and the LLM client can stream
+func (s *ConversationService) generate(ctx context.Context, history []client.ConversationMessage, progress TurnProgress) (string, error) {
+	streamer, ok := s.openAIClient.(client.StreamGenerator)
+	if progress == nil || !ok {
+		return s.openAIClient.Generate(history)
+	}
+	return streamer.GenerateStream(ctx, history, func(token string) {
+		progress.emit(TurnEvent{Type: TurnEventToken, Text: token})
+	})
+}
+
+// safeSuggestions drops any suggested reply that fails the output safety check
+func (s *ConversationService) safeSuggestions(ctx context.Context, threadID uuid.UUID, suggestions models.StringList) models.StringList {
+	if s.safety == nil || len(suggestions) == 0 {
+		return suggestions
+	}
+
+	safe := suggestions[:0]
+	for _, suggestion := range suggestions {
+		verdict := s.safety.Check(ctx, suggestion)
+		if !verdict.Safe {
+			s.safety.RecordIncident(threadID, models.SafetyIncidentSuggestion, verdict, 1)
+			continue
+		}
+		safe = append(safe, suggestion)
+	}
+	return safe
+}
+
+// reserveTurn holds the cost of a turn on the thread owner's balance until
+// it is captured or released. It returns nil when credits aren't enforced.
+func (s *ConversationService) reserveTurn(threadID, userMessageID uuid.UUID, cost int, description string) (*models.CreditReservation, error) {
+	if s.credits == nil {
+		return nil, nil
+	}
+
+	thread, err := s.threadRepo.FindByID(s.exec, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch thread: %w", err)
+	}
+
+	return s.credits.ReserveCredits(thread.UserID, cost, userMessageID.String(), description)
+}
+
+// settleTurn captures the credits held for a delivered turn and returns what
+// the turn cost. A speech-only reply that was meant to be spoken but couldn't
+// be leaves the learner nothing to hear or read, so what was charged is
+// refunded against the reservation.
+func (s *ConversationService) settleTurn(threadID uuid.UUID, hold *models.CreditReservation, cost int, reply *models.Message, spoken bool) int {
+	if !s.captureTurn(hold) || !spoken || reply.HasAudio {
+		return cost
+	}
+	if thread := s.findThread(threadID); thread == nil || !thread.SpeechOnly {
+		return cost
+	}
+
+	refunded, err := s.credits.RefundReservation(hold.Reference, "Refund: reply could not be spoken")
+	if err != nil {
+		log.Printf("CRITICAL: Failed to refund credits for user %s, message %s: %v", hold.UserID, hold.Reference, err)
+		return cost
+	}
+	if !refunded {
+		return cost
+	}
+	return 0
+}
+
+// refundLowConfidence refunds a charged voice turn whose recording was scored
+// low-confidence before the turn was captured. The pronunciation worker
+// refunds the turns it scores after the capture; between them every
+// low-confidence turn is refunded, once.
+func (s *ConversationService) refundLowConfidence(userMessageID uuid.UUID, charged int) int {
+	if s.credits == nil || charged == 0 {
+		return charged
+	}
+
+	message, err := s.messageRepo.FindByID(s.exec, userMessageID)
+	if err != nil {
+		log.Printf("[Conversation] Failed to check the score of message %s: %v", userMessageID, err)
+		return charged
+	}
+	if !message.PronunciationLowConfidence || message.TranscriptCorrectedAt != nil {
+		return charged
+	}
+
+	refunded, err := s.credits.RefundReservation(userMessageID.String(), LowConfidenceRefundDescription)
+	if err != nil {
+		log.Printf("CRITICAL: Failed to refund low-confidence message %s: %v", userMessageID, err)
+		return charged
+	}
+	if !refunded {
+		return charged
+	}
+	return 0
+}
+
+// reduceTurn lowers the credits held for a turn to its cost once known,
+// giving the rest back
+func (s *ConversationService) reduceTurn(hold *models.CreditReservation, cost int) error {
+	if hold == nil {
+		return nil
+	}
+	return s.credits.ReduceReservation(hold.ID, cost)
+}
+
+// captureTurn charges the credits held for a delivered turn, reporting
+// whether there was a charge. A failed capture is logged rather than
+// returned, since the user has their reply; the credits stay held until the
+// reservation expires and ReservationExpiryWorker gives them back.
+func (s *ConversationService) captureTurn(hold *models.CreditReservation) bool {
+	if hold == nil {
+		return false
+	}
+
+	if err := s.credits.CaptureReservation(hold.ID); err != nil {
+		log.Printf("CRITICAL: Failed to capture credit reservation %s for user %s, message %s: %v", hold.ID, hold.UserID, hold.Reference, err)
+		return false
+	}
+	return true
+}
+
+// releaseTurn gives back the credits held for a turn that failed. A failed
+// release is logged rather than returned so the original error reaches the
+// user; ReservationExpiryWorker gives the credits back once it expires.
+func (s *ConversationService) releaseTurn(hold *models.CreditReservation, reason string) {
+	if hold == nil {
+		return
+	}
+
+	if err := s.credits.ReleaseReservation(hold.ID); err != nil {
+		log.Printf("CRITICAL: Failed to release credit reservation %s for user %s, message %s (%s): %v", hold.ID, hold.UserID, hold.Reference, reason, err)
+	}
+}
+
+// payer returns the user a reservation is held for, or uuid.Nil when credits
+// aren't enforced
+func payer(hold *models.CreditReservation) uuid.UUID {
+	if hold == nil {
+		return uuid.Nil
+	}
+	return hold.UserID
+}
+
+// releaseReason describes why the user's audio couldn't be turned into a message
+func releaseReason(err error) string {
+	switch {
+	case errors.Is(err, ErrAudioTooShort):
+		return "recording too short"
+	case errors.Is(err, ErrAudioTooLong):
+		return "recording too long"
+	default:
+		return "voice message could not be processed"
+	}
+}
+
+// publishProcessed tells subscribers a turn was answered. userID is the
+// charged user, or uuid.Nil to look up the thread's owner.
+func (s *ConversationService) publishProcessed(ctx context.Context, threadID, messageID, userID uuid.UUID, kind string, credits int) {
+	if s.Events == nil {
+		return
+	}
+	if userID == uuid.Nil {
+		thread := s.findThread(threadID)
+		if thread == nil {
+			return
+		}
+		userID = thread.UserID
+	}
+
+	s.Events.Publish(ctx, events.MessageProcessed{
+		UserID:    userID,
+		ThreadID:  threadID,
+		MessageID: messageID,
+		Kind:      kind,
+		Credits:   credits,
+		At:        time.Now(),
+	})
+}
+
+// findThread loads the thread's settings (goal, reply suggestions, locale).
+// Returns nil if they can't be loaded; the turn proceeds with defaults.
+func (s *ConversationService) findThread(threadID uuid.UUID) *models.Thread {
+	if s.threadRepo == nil {
+		return nil
+	}
+	thread, err := s.threadRepo.FindByID(s.exec, threadID)
+	if err != nil {
+		log.Printf("Error fetching thread settings: %v", err)
+		return nil
+	}
+	return thread
 }
 
 // createAssistantMessage creates and saves an assistant message
@@ -219,23 +962,38 @@ func (s *ConversationService) createAssistantMessage(
 	messageID uuid.UUID,
 	threadID uuid.UUID,
 	content string,
+	spokenText *string,
 	audioURL *string,
 	audioDuration *float64,
 	hasAudio bool,
+	suggestedReplies models.StringList,
+	adaptation models.JSONMap,
+	tone string,
+	corrections models.CorrectionSpans,
+	correctedMessageID *uuid.UUID,
 ) (*models.Message, error) {
 	responseMessage := models.Message{
 		ID:                   messageID,
 		ThreadID:             threadID,
 		Role:                 "assistant",
 		Content:              content,
+		SpokenText:           spokenText,
 		AudioURL:             audioURL,
 		AudioDurationSeconds: audioDuration,
 		HasAudio:             hasAudio,
+		SuggestedReplies:     suggestedReplies,
+		Adaptation:           adaptation,
+		Tone:                 tone,
+		Corrections:          corrections,
+		CorrectedMessageID:   correctedMessageID,
 		Timestamp:            time.Now(),
 	}
+	if audioURL != nil {
+		responseMessage.AudioFormat = models.AudioFormatMP3
+	}
 
 	if err := s.messageRepo.Create(s.exec, &responseMessage); err != nil {
-		return nil, fmt.Errorf("failed to create AI response: %w", err)
+		return nil, &VoiceTurnError{Stage: StagePersist, Err: fmt.Errorf("failed to create AI response: %w", err)}
 	}
 
 	return &responseMessage, nil
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/client/fake"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/testutil/factory"
+)
+
+// The voice pipeline with the fake speech and pronunciation clients and
+// in-memory stores, so the numbers measure this code rather than the network
+// or a database. Compare runs with `make bench-check` (see README).
+
+// benchStorage accepts every upload and signs every key
+type benchStorage struct {
+	client.StorageClient
+}
+
+func (s *benchStorage) UploadAudio(ctx context.Context, file io.Reader, key string, contentType string) (string, error) {
+	if _, err := io.Copy(io.Discard, file); err != nil {
+		return "", err
+	}
+	return "https://storage.local/" + key, nil
+}
+
+func (s *benchStorage) GetPresignedURL(ctx context.Context, key string, expiration time.Duration) (string, error) {
+	return "https://storage.local/" + key + "?signature=bench", nil
+}
+
+// benchLLM answers every turn with the same reply
+type benchLLM struct {
+	client.OpenAIClient
+}
+
+func (l *benchLLM) Generate(messages []client.ConversationMessage) (string, error) {
+	return "That sounds lovely! What did you do at the park?", nil
+}
+
+// benchThreads serves one open thread
+type benchThreads struct {
+	repository.ThreadRepository
+	thread *models.Thread
+}
+
+func (r *benchThreads) FindByID(exec repository.Executor, id uuid.UUID) (*models.Thread, error) {
+	return r.thread, nil
+}
+
+// benchMessages keeps a fixed history, so every turn does the same work
+type benchMessages struct {
+	repository.MessageRepository
+	history []models.Message
+	message *models.Message
+}
+
+func (r *benchMessages) Create(exec repository.Executor, message *models.Message) error {
+	return nil
+}
+
+func (r *benchMessages) FindByThreadID(exec repository.Executor, threadID uuid.UUID) ([]models.Message, error) {
+	return r.history, nil
+}
+
+func (r *benchMessages) FindByID(exec repository.Executor, id uuid.UUID) (*models.Message, error) {
+	return r.message, nil
+}
+
+func (r *benchMessages) UpdatePronunciationAnalysis(exec repository.Executor, id uuid.UUID, status string, analysis models.JSONMap, quality string, confidence float64, lowConfidence bool, updatedAt time.Time) error {
+	return nil
+}
+
+// quietLogs drops the pipeline's progress logs for the rest of the benchmark
+func quietLogs(b *testing.B) {
+	output := log.Writer()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(output) })
+}
+
+// benchHistory is a thread of turns, each a learner and an assistant message
+func benchHistory(threadID uuid.UUID, turns int) []models.Message {
+	var history []models.Message
+	for i := range turns {
+		history = append(history,
+			*factory.VoiceMessage(threadID, fmt.Sprintf("%s (%d)", fake.Transcripts[i%len(fake.Transcripts)], i)),
+			*factory.AssistantMessage(threadID, "Tell me more about that."),
+		)
+	}
+	return history
+}
+
+func BenchmarkProcessAudioMessage(b *testing.B) {
+	quietLogs(b)
+	user := factory.User()
+	thread := factory.Thread(user.ID)
+	messages := &benchMessages{history: benchHistory(thread.ID, 20)}
+	service := NewConversationService(nil, messages, &benchThreads{thread: thread},
+		fake.NewWhisperClient(), &benchLLM{}, fake.NewTTSClient(), &benchStorage{},
+		nil, nil, nil, nil)
+	audio := make([]byte, 64*1024) // About four seconds of Opus
+	header := &multipart.FileHeader{Filename: "turn.webm", Size: int64(len(audio))}
+
+	for b.Loop() {
+		if _, err := service.ProcessAudioMessage(context.Background(), thread.ID, newMockMultipartFile(audio), header, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPronunciationAnalysis(b *testing.B) {
+	quietLogs(b)
+	thread := factory.Thread(uuid.New())
+	message := factory.VoiceMessage(thread.ID, fake.Transcripts[2])
+	messages := &benchMessages{message: message}
+	worker := NewPronunciationWorkerForTest(nil, messages, &benchThreads{thread: thread}, fake.NewMLClient(), &benchStorage{}, nil)
+
+	for b.Loop() {
+		worker.AnalyzeAsync(context.Background(), message.ID, *message.AudioURL, message.Content, PronunciationLanguage, client.QualityAccurate)
+	}
+}
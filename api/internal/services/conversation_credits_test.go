@@ -0,0 +1,443 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+// Pipeline stages in the order ProcessAudioMessage runs them
+const (
+	stageUpload = iota
+	stagePresign
+	stageTranscribe
+	stageCreateMessage
+	stageGenerate
+	stageDone
+)
+
+type chargedTurnDeps struct {
+	messageRepo *repomocks.MockMessageRepository
+	threadRepo  *repomocks.MockThreadRepository
+	whisper     *clientmocks.MockWhisperClient
+	openAI      *clientmocks.MockOpenAIClient
+	tts         *clientmocks.MockTTSClient
+	storage     *clientmocks.MockStorageClient
+	credits     *stubCredits
+}
+
+// newChargedConversationService wires a service whose pipeline fails at
+// failAt (or succeeds when failAt is stageDone). duration is what Whisper
+// reports for the recording.
+func newChargedConversationService(threadID, userID uuid.UUID, failAt int, duration float64) (*ConversationService, *chargedTurnDeps) {
+	deps := &chargedTurnDeps{
+		messageRepo: new(repomocks.MockMessageRepository),
+		threadRepo:  new(repomocks.MockThreadRepository),
+		whisper:     new(clientmocks.MockWhisperClient),
+		openAI:      new(clientmocks.MockOpenAIClient),
+		tts:         new(clientmocks.MockTTSClient),
+		storage:     new(clientmocks.MockStorageClient),
+		credits:     new(stubCredits),
+	}
+	failure := errors.New("boom")
+
+	deps.threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+
+	if failAt == stageUpload {
+		deps.storage.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, "audio/webm").Return("", failure)
+	} else {
+		deps.storage.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, "audio/webm").Return("url", nil)
+	}
+
+	if failAt == stagePresign {
+		deps.storage.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).Return("", failure)
+	} else {
+		deps.storage.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).Return("https://presigned", nil)
+	}
+
+	if failAt == stageTranscribe {
+		deps.whisper.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).Return(nil, failure)
+	} else {
+		deps.whisper.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).Return(&client.TranscriptionResult{
+			Text:     "hello",
+			Duration: duration,
+		}, nil)
+	}
+
+	if failAt == stageCreateMessage {
+		deps.messageRepo.On("Create", mock.Anything, mock.Anything).Return(failure)
+	} else {
+		deps.messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	}
+
+	// The user message, re-read once the turn is captured, scored confidently
+	deps.messageRepo.On("FindByID", mock.Anything, mock.Anything).Return(&models.Message{}, nil).Maybe()
+	deps.messageRepo.On("FindByThreadID", mock.Anything, threadID).Return([]models.Message{{Role: "user", Content: "hello"}}, nil)
+	if failAt == stageGenerate {
+		deps.openAI.On("Generate", mock.Anything).Return("", failure)
+	} else {
+		deps.openAI.On("Generate", mock.Anything).Return("Hi!", nil)
+	}
+	deps.tts.On("Synthesize", mock.Anything, mock.Anything).Return(nil, failure)
+
+	service := NewConversationService(
+		nil,
+		deps.messageRepo,
+		deps.threadRepo,
+		deps.whisper,
+		deps.openAI,
+		deps.tts,
+		deps.storage,
+		nil,
+		deps.credits,
+		nil,
+		nil, // runtime settings (defaults)
+	)
+	return service, deps
+}
+
+func newTestAudio() (multipart.File, *multipart.FileHeader) {
+	content := []byte("fake audio data")
+	return newMockMultipartFile(content), &multipart.FileHeader{Filename: "test.webm", Size: int64(len(content))}
+}
+
+// expectReservation makes ReserveCredits hold cost for userID, and lets the
+// hold be captured or released
+func expectReservation(deps *chargedTurnDeps, userID uuid.UUID, cost int, description string) *models.CreditReservation {
+	hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: cost, Status: models.ReservationHeld, Description: description}
+	deps.credits.On("ReserveCredits", userID, cost, mock.Anything, description).
+		Run(func(args mock.Arguments) { hold.Reference = args.String(2) }).
+		Return(hold, nil)
+	deps.credits.On("CaptureReservation", hold.ID).Return(nil)
+	deps.credits.On("ReleaseReservation", hold.ID).Return(nil)
+	return hold
+}
+
+func TestConversationService_ProcessAudioMessage_CapturesOnce(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+	service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+	hold := expectReservation(deps, userID, models.CreditCostPerMessage, "Voice message")
+
+	file, header := newTestAudio()
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+	require.NoError(t, err)
+	deps.credits.AssertNumberOfCalls(t, "ReserveCredits", 1)
+	deps.credits.AssertNumberOfCalls(t, "CaptureReservation", 1)
+	deps.credits.AssertNotCalled(t, "ReleaseReservation", mock.Anything)
+
+	// The reservation references the user message so it can be audited later
+	assert.Equal(t, turn.UserMessage.ID.String(), hold.Reference)
+}
+
+func TestConversationService_ProcessAudioMessage_ReleasesFailedStages(t *testing.T) {
+	tests := []struct {
+		name      string
+		failAt    int
+		duration  float64
+		wantErr   error
+		wantStage string
+	}{
+		{name: "upload", failAt: stageUpload, duration: 2.5, wantStage: StageUpload},
+		{name: "presigned URL", failAt: stagePresign, duration: 2.5, wantStage: StagePresign},
+		{name: "transcription", failAt: stageTranscribe, duration: 2.5, wantStage: StageTranscribe},
+		{name: "too short", failAt: stageDone, duration: 0.5, wantErr: ErrAudioTooShort},
+		{name: "too long", failAt: stageDone, duration: 45, wantErr: ErrAudioTooLong},
+		{name: "message create", failAt: stageCreateMessage, duration: 2.5, wantStage: StagePersist},
+		{name: "assistant generation", failAt: stageGenerate, duration: 2.5, wantStage: StageGenerate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadID, userID := uuid.New(), uuid.New()
+			service, deps := newChargedConversationService(threadID, userID, tt.failAt, tt.duration)
+			hold := expectReservation(deps, userID, models.CreditCostPerMessage, "Voice message")
+
+			file, header := newTestAudio()
+			turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+			require.Error(t, err)
+			assert.Nil(t, turn)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantStage, TurnStage(err))
+			deps.credits.AssertNumberOfCalls(t, "ReleaseReservation", 1)
+			deps.credits.AssertCalled(t, "ReleaseReservation", hold.ID)
+			deps.credits.AssertNotCalled(t, "CaptureReservation", mock.Anything)
+		})
+	}
+}
+
+func TestConversationService_ProcessAudioMessage_InsufficientCredits(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+	service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+
+	deps.credits.On("ReserveCredits", userID, models.CreditCostPerMessage, mock.Anything, "Voice message").
+		Return(nil, ErrInsufficientCredits)
+
+	file, header := newTestAudio()
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+	assert.ErrorIs(t, err, ErrInsufficientCredits)
+	assert.Nil(t, turn)
+	deps.storage.AssertNotCalled(t, "UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	deps.credits.AssertNotCalled(t, "ReleaseReservation", mock.Anything)
+}
+
+func TestConversationService_ProcessAudioMessage_ReleaseFailureKeepsOriginalError(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+	service, deps := newChargedConversationService(threadID, userID, stageDone, 0.5)
+
+	hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: models.CreditCostPerMessage}
+	deps.credits.On("ReserveCredits", userID, models.CreditCostPerMessage, mock.Anything, "Voice message").Return(hold, nil)
+	deps.credits.On("ReleaseReservation", hold.ID).Return(errors.New("db down"))
+
+	file, header := newTestAudio()
+	_, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+	assert.ErrorIs(t, err, ErrAudioTooShort)
+}
+
+func TestConversationService_ProcessAudioMessage_CaptureFailureStillReturnsTurn(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+	service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+
+	hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: models.CreditCostPerMessage}
+	deps.credits.On("ReserveCredits", userID, models.CreditCostPerMessage, mock.Anything, "Voice message").Return(hold, nil)
+	deps.credits.On("CaptureReservation", hold.ID).Return(errors.New("db down"))
+
+	file, header := newTestAudio()
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+	require.NoError(t, err)
+	assert.NotNil(t, turn.AssistantMessage)
+	deps.credits.AssertNotCalled(t, "ReleaseReservation", mock.Anything)
+}
+func TestConversationService_ProcessAudioMessage_RefundsUnspokenSpeechOnlyReply(t *testing.T) {
+	t.Run("speech-only thread", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+		deps.threadRepo.ExpectedCalls = nil
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID, SpeechOnly: true}, nil)
+		hold := expectReservation(deps, userID, models.CreditCostPerMessage, "Voice message")
+		deps.credits.On("RefundReservation", mock.Anything, "Refund: reply could not be spoken").Return(true, nil)
+
+		file, header := newTestAudio()
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+		require.NoError(t, err)
+		assert.Zero(t, turn.Credits)
+		deps.credits.AssertCalled(t, "CaptureReservation", hold.ID)
+		deps.credits.AssertCalled(t, "RefundReservation", turn.UserMessage.ID.String(), "Refund: reply could not be spoken")
+	})
+
+	t.Run("records the refund against the user's message", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+		deps.threadRepo.ExpectedCalls = nil
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID, SpeechOnly: true}, nil)
+
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		holdRepo := new(repomocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 20}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		hold := &models.CreditReservation{}
+		holdRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { *hold = *args.Get(1).(*models.CreditReservation) }).
+			Return(nil)
+		holdRepo.On("FindByID", mock.Anything, mock.Anything).Return(hold, nil)
+		holdRepo.On("Settle", mock.Anything, mock.Anything, models.ReservationCaptured, mock.Anything).Return(true, nil)
+		holdRepo.On("FindByReference", mock.Anything, mock.Anything).Return(hold, nil)
+		holdRepo.On("MarkRefunded", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		txRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		service.credits = NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+
+		file, header := newTestAudio()
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+		require.NoError(t, err)
+		assert.Zero(t, turn.Credits)
+		txRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Type == models.TransactionRefund && tx.Amount == models.CreditCostPerMessage &&
+				tx.Reference != nil && *tx.Reference == turn.UserMessage.ID.String() &&
+				tx.Description == "Refund: reply could not be spoken"
+		}))
+	})
+
+	t.Run("other threads keep the charge", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+		expectReservation(deps, userID, models.CreditCostPerMessage, "Voice message")
+
+		file, header := newTestAudio()
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.CreditCostPerMessage, turn.Credits)
+		deps.credits.AssertNotCalled(t, "RefundReservation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("nothing to refund when the capture failed", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+		deps.threadRepo.ExpectedCalls = nil
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID, SpeechOnly: true}, nil)
+		hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: models.CreditCostPerMessage}
+		deps.credits.On("ReserveCredits", userID, models.CreditCostPerMessage, mock.Anything, "Voice message").Return(hold, nil)
+		deps.credits.On("CaptureReservation", hold.ID).Return(errors.New("db down"))
+
+		file, header := newTestAudio()
+		_, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+		require.NoError(t, err)
+		deps.credits.AssertNotCalled(t, "RefundReservation", mock.Anything, mock.Anything)
+	})
+}
+
+func TestConversationService_ProcessAudioMessage_RefundsLowConfidenceScoredBeforeCapture(t *testing.T) {
+	t.Run("refunds a recording already scored low-confidence", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+		deps.messageRepo.ExpectedCalls = filterCalls(deps.messageRepo.ExpectedCalls, "FindByID")
+		deps.messageRepo.On("FindByID", mock.Anything, mock.Anything).Return(&models.Message{PronunciationLowConfidence: true}, nil)
+		hold := expectReservation(deps, userID, models.CreditCostPerMessage, "Voice message")
+		deps.credits.On("RefundReservation", mock.Anything, LowConfidenceRefundDescription).Return(true, nil)
+
+		file, header := newTestAudio()
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+		require.NoError(t, err)
+		assert.Zero(t, turn.Credits)
+		deps.credits.AssertCalled(t, "CaptureReservation", hold.ID)
+		deps.credits.AssertCalled(t, "RefundReservation", turn.UserMessage.ID.String(), LowConfidenceRefundDescription)
+	})
+
+	t.Run("keeps the charge when the refund was already made", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+		deps.messageRepo.ExpectedCalls = filterCalls(deps.messageRepo.ExpectedCalls, "FindByID")
+		deps.messageRepo.On("FindByID", mock.Anything, mock.Anything).Return(&models.Message{PronunciationLowConfidence: true}, nil)
+		expectReservation(deps, userID, models.CreditCostPerMessage, "Voice message")
+		deps.credits.On("RefundReservation", mock.Anything, LowConfidenceRefundDescription).Return(false, nil)
+
+		file, header := newTestAudio()
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.CreditCostPerMessage, turn.Credits)
+	})
+
+	t.Run("leaves a confident recording charged", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 2.5)
+		expectReservation(deps, userID, models.CreditCostPerMessage, "Voice message")
+
+		file, header := newTestAudio()
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, file, header, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.CreditCostPerMessage, turn.Credits)
+		deps.credits.AssertNotCalled(t, "RefundReservation", mock.Anything, mock.Anything)
+	})
+}
+
+// filterCalls drops the expectations set for method
+func filterCalls(calls []*mock.Call, method string) []*mock.Call {
+	kept := calls[:0:0]
+	for _, call := range calls {
+		if call.Method != method {
+			kept = append(kept, call)
+		}
+	}
+	return kept
+}
+
+func TestConversationService_SendTextMessage(t *testing.T) {
+	t.Run("saves the typed message and replies without speaking", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 0)
+		expectReservation(deps, userID, models.CreditCostPerTextMessage, "Text message")
+
+		turn, err := service.SendTextMessage(context.Background(), threadID, "  I can't talk right now  ", false)
+
+		require.NoError(t, err)
+		assert.Equal(t, "I can't talk right now", turn.UserMessage.Content)
+		assert.Equal(t, models.MessageKindText, turn.UserMessage.Kind)
+		assert.False(t, turn.UserMessage.HasAudio)
+		assert.Equal(t, "Hi!", turn.AssistantMessage.Content)
+		assert.False(t, turn.AssistantMessage.HasAudio)
+		assert.True(t, turn.Silent)
+		assert.Equal(t, models.CreditCostPerTextMessage, turn.Credits)
+		deps.tts.AssertNotCalled(t, "Synthesize", mock.Anything, mock.Anything)
+		deps.whisper.AssertNotCalled(t, "TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("speaks the reply when asked", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 0)
+		expectReservation(deps, userID, models.CreditCostPerTextMessage, "Text message")
+
+		turn, err := service.SendTextMessage(context.Background(), threadID, "hello", true)
+
+		require.NoError(t, err)
+		assert.False(t, turn.Silent)
+		deps.tts.AssertCalled(t, "Synthesize", mock.Anything, "Hi!")
+	})
+
+	t.Run("always speaks in a speech-only thread", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageDone, 0)
+		deps.threadRepo.ExpectedCalls = nil
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID, SpeechOnly: true}, nil)
+		expectReservation(deps, userID, models.CreditCostPerTextMessage, "Text message")
+		deps.credits.On("RefundReservation", mock.Anything, "Refund: reply could not be spoken").Return(true, nil)
+
+		turn, err := service.SendTextMessage(context.Background(), threadID, "hello", false)
+
+		require.NoError(t, err)
+		assert.False(t, turn.Silent)
+		deps.tts.AssertCalled(t, "Synthesize", mock.Anything, "Hi!")
+	})
+
+	t.Run("releases the credits when no reply is generated", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		service, deps := newChargedConversationService(threadID, userID, stageGenerate, 0)
+		hold := expectReservation(deps, userID, models.CreditCostPerTextMessage, "Text message")
+
+		turn, err := service.SendTextMessage(context.Background(), threadID, "hello", false)
+
+		require.Error(t, err)
+		assert.Nil(t, turn)
+		assert.Equal(t, StageGenerate, TurnStage(err))
+		deps.credits.AssertCalled(t, "ReleaseReservation", hold.ID)
+		deps.credits.AssertNotCalled(t, "CaptureReservation", mock.Anything)
+	})
+
+	t.Run("rejects empty and overlong messages without charging", func(t *testing.T) {
+		for _, content := range []string{"", "   ", strings.Repeat("a", MaxTextMessageLength+1)} {
+			threadID, userID := uuid.New(), uuid.New()
+			service, deps := newChargedConversationService(threadID, userID, stageDone, 0)
+
+			_, err := service.SendTextMessage(context.Background(), threadID, content, false)
+
+			assert.ErrorIs(t, err, ErrInvalidTextMessage)
+			deps.credits.AssertNotCalled(t, "ReserveCredits", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+}
@@ -5,11 +5,14 @@ import (
 	"context"
 	"errors"
 	"mime/multipart"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"ling-app/api/internal/client"
 	clientmocks "ling-app/api/internal/client/mocks"
@@ -61,7 +64,7 @@ func TestConversationService_ProcessAudioMessage_Success(t *testing.T) {
 		Return("https://presigned.url/audio.webm", nil)
 
 	// Whisper: transcribe audio
-	whisperClient.On("TranscribeFromURL", mock.Anything, "https://presigned.url/audio.webm").
+	whisperClient.On("TranscribeFromURL", mock.Anything, "https://presigned.url/audio.webm", mock.Anything).
 		Return(&client.TranscriptionResult{
 			Text:     "hello world",
 			Language: "en",
@@ -73,6 +76,9 @@ func TestConversationService_ProcessAudioMessage_Success(t *testing.T) {
 		return msg.Role == "user" && msg.Content == "hello world" && msg.HasAudio == true
 	})).Return(nil)
 
+	// Thread repo: no goal set
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+
 	// Message repo: find by thread ID (for conversation history)
 	messageRepo.On("FindByThreadID", mock.Anything, threadID).Return([]models.Message{
 		{
@@ -113,11 +119,13 @@ func TestConversationService_ProcessAudioMessage_Success(t *testing.T) {
 		ttsClient,
 		storageClient,
 		nil, // pronunciation worker
-		10*1024*1024,
+		nil, // credits
+		nil, // output safety
+		nil, // runtime settings (defaults)
 	)
 
 	// Execute
-	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader)
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
 
 	// Assert
 	assert.NoError(t, err)
@@ -149,12 +157,12 @@ func TestConversationService_ProcessAudioMessage_FileSizeTooLarge(t *testing.T)
 
 	// Create service with 10MB limit
 	service := NewConversationService(
-		nil, nil, nil, nil, nil, nil, nil, nil,
-		10*1024*1024,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, // runtime settings (defaults)
 	)
 
 	// Execute
-	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader)
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
 
 	// Assert
 	assert.Error(t, err)
@@ -179,18 +187,19 @@ func TestConversationService_ProcessAudioMessage_UploadFails(t *testing.T) {
 
 	// Create service
 	service := NewConversationService(
-		nil, nil, nil, nil, nil, nil, storageClient, nil,
-		10*1024*1024,
+		nil, nil, nil, nil, nil, nil, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
 	)
 
 	// Execute
-	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader)
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
 
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, turn)
 	assert.Contains(t, err.Error(), "failed to process user audio")
 	assert.Contains(t, err.Error(), "failed to upload audio")
+	assert.Equal(t, StageUpload, TurnStage(err))
 	storageClient.AssertExpectations(t)
 }
 
@@ -213,23 +222,24 @@ func TestConversationService_ProcessAudioMessage_TranscriptionFails(t *testing.T
 
 	// Mock whisper to fail
 	whisperClient := new(clientmocks.MockWhisperClient)
-	whisperClient.On("TranscribeFromURL", mock.Anything, "https://presigned.url/audio.webm").
+	whisperClient.On("TranscribeFromURL", mock.Anything, "https://presigned.url/audio.webm", mock.Anything).
 		Return(nil, errors.New("transcription failed"))
 
 	// Create service
 	service := NewConversationService(
-		nil, nil, nil, whisperClient, nil, nil, storageClient, nil,
-		10*1024*1024,
+		nil, nil, nil, whisperClient, nil, nil, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
 	)
 
 	// Execute
-	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader)
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
 
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, turn)
 	assert.Contains(t, err.Error(), "failed to process user audio")
 	assert.Contains(t, err.Error(), "failed to transcribe audio")
+	assert.Equal(t, StageTranscribe, TurnStage(err))
 	storageClient.AssertExpectations(t)
 	whisperClient.AssertExpectations(t)
 }
@@ -258,7 +268,7 @@ func TestConversationService_ProcessAudioMessage_TTSFails_ContinuesWithoutAudio(
 		Return("https://storage.url/audio.webm", nil)
 	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
 		Return("https://presigned.url/audio.webm", nil)
-	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything).
+	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
 		Return(&client.TranscriptionResult{Text: "hello", Duration: 1.0}, nil)
 	messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
 		return msg.Role == "user"
@@ -278,12 +288,12 @@ func TestConversationService_ProcessAudioMessage_TTSFails_ContinuesWithoutAudio(
 
 	// Create service
 	service := NewConversationService(
-		nil, messageRepo, nil, whisperClient, openAIClient, ttsClient, storageClient, nil,
-		10*1024*1024,
+		nil, messageRepo, nil, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
 	)
 
 	// Execute
-	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader)
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
 
 	// Assert - should succeed despite TTS failure
 	assert.NoError(t, err)
@@ -316,7 +326,7 @@ func TestConversationService_ProcessAudioMessage_WithPronunciationWorker(t *test
 		Return("https://storage.url/file", nil)
 	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
 		Return("https://presigned.url/file", nil)
-	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything).
+	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
 		Return(&client.TranscriptionResult{Text: "test", Duration: 1.0}, nil)
 	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
 	messageRepo.On("FindByThreadID", mock.Anything, threadID).
@@ -331,12 +341,12 @@ func TestConversationService_ProcessAudioMessage_WithPronunciationWorker(t *test
 
 	// Create service without worker (testing it handles nil gracefully)
 	service := NewConversationService(
-		nil, messageRepo, nil, whisperClient, openAIClient, ttsClient, storageClient, nil,
-		10*1024*1024,
+		nil, messageRepo, nil, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
 	)
 
 	// Execute
-	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader)
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
 
 	// Assert
 	assert.NoError(t, err)
@@ -344,6 +354,54 @@ func TestConversationService_ProcessAudioMessage_WithPronunciationWorker(t *test
 	// Test passes even without pronunciation worker
 }
 
+func TestConversationService_ProcessAudioMessage_DivergentTranscriptSkipsScoring(t *testing.T) {
+	threadID := uuid.New()
+	audioContent := []byte("fake audio data")
+	audioFile := newMockMultipartFile(audioContent)
+	fileHeader := &multipart.FileHeader{
+		Filename: "test.webm",
+		Size:     int64(len(audioContent)),
+	}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	whisperClient := new(clientmocks.MockWhisperClient)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("https://storage.url/file", nil)
+	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
+		Return("https://presigned.url/file", nil)
+	// User went off-script
+	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
+		Return(&client.TranscriptionResult{Text: "what time does the train leave", Duration: 2.0}, nil)
+	messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+		return msg.Role == "user"
+	})).Return(nil)
+	messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+		return msg.Role == "assistant"
+	})).Return(nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{Role: "user", Content: "what time does the train leave"}}, nil)
+	openAIClient.On("Generate", mock.Anything).Return("Response", nil)
+	ttsClient.On("Synthesize", mock.Anything, mock.Anything).
+		Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 1.0}, nil)
+
+	service := NewConversationService(
+		nil, messageRepo, nil, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
+	)
+
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "Could I have a coffee, please?")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "skipped_divergent", turn.UserMessage.PronunciationStatus)
+	if assert.NotNil(t, turn.UserMessage.ExpectedText) {
+		assert.Equal(t, "Could I have a coffee, please?", *turn.UserMessage.ExpectedText)
+	}
+}
+
 func TestConversationService_ProcessAudioMessage_AudioTooShort(t *testing.T) {
 	// Setup
 	threadID := uuid.New()
@@ -366,7 +424,7 @@ func TestConversationService_ProcessAudioMessage_AudioTooShort(t *testing.T) {
 	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).Return("http://example.com/audio.webm", nil)
 
 	// Mock transcription with short audio (0.5 seconds)
-	whisperClient.On("TranscribeFromURL", mock.Anything, "http://example.com/audio.webm").Return(&client.TranscriptionResult{
+	whisperClient.On("TranscribeFromURL", mock.Anything, "http://example.com/audio.webm", mock.Anything).Return(&client.TranscriptionResult{
 		Text:     "hi",
 		Language: "en",
 		Duration: 0.5, // Less than 1 second
@@ -374,12 +432,12 @@ func TestConversationService_ProcessAudioMessage_AudioTooShort(t *testing.T) {
 
 	// Create service
 	service := NewConversationService(
-		nil, messageRepo, nil, whisperClient, nil, nil, storageClient, nil,
-		10*1024*1024,
+		nil, messageRepo, nil, whisperClient, nil, nil, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
 	)
 
 	// Execute
-	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader)
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
 
 	// Assert
 	assert.Error(t, err)
@@ -392,3 +450,584 @@ func TestConversationService_ProcessAudioMessage_AudioTooShort(t *testing.T) {
 	// Message should NOT be created since validation failed
 	messageRepo.AssertNotCalled(t, "Create")
 }
+
+func TestConversationService_ProcessAudioMessage_SuggestReplies(t *testing.T) {
+	threadID := uuid.New()
+	audioContent := []byte("fake audio data")
+	audioFile := newMockMultipartFile(audioContent)
+	fileHeader := &multipart.FileHeader{
+		Filename: "test.webm",
+		Size:     int64(len(audioContent)),
+	}
+	suggestions := []string{"Yes, please.", "No, thank you.", "Could you repeat that?"}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	whisperClient := new(clientmocks.MockWhisperClient)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("https://storage.url/file", nil)
+	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
+		Return("https://presigned.url/file", nil)
+	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
+		Return(&client.TranscriptionResult{Text: "hola", Duration: 1.5}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).
+		Return(&models.Thread{ID: threadID, SuggestReplies: true}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{Role: "user", Content: "hola"}}, nil)
+	openAIClient.On("Generate", mock.Anything).Return("¿Quieres un café?", nil)
+	openAIClient.On("SuggestReplies", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+		last := history[len(history)-1]
+		return last.Role == "assistant" && last.Content == "¿Quieres un café?"
+	})).Return(suggestions, nil)
+	ttsClient.On("Synthesize", mock.Anything, mock.Anything).
+		Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 1.0}, nil)
+	messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+		return msg.Role == "user"
+	})).Return(nil)
+	messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+		return msg.Role == "assistant" && len(msg.SuggestedReplies) == 3
+	})).Return(nil)
+
+	service := NewConversationService(
+		nil, messageRepo, threadRepo, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
+	)
+
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StringList(suggestions), turn.AssistantMessage.SuggestedReplies)
+	openAIClient.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestConversationService_ProcessAudioMessage_NormalizesTextForSpeech(t *testing.T) {
+	threadID := uuid.New()
+	audioContent := []byte("fake audio data")
+	audioFile := newMockMultipartFile(audioContent)
+	fileHeader := &multipart.FileHeader{
+		Filename: "test.webm",
+		Size:     int64(len(audioContent)),
+	}
+	reply := "Añade 3/4 de taza, cuesta 2 €."
+	spoken := "Añade tres cuartos de taza, cuesta dos euros."
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	whisperClient := new(clientmocks.MockWhisperClient)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("https://storage.url/file", nil)
+	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
+		Return("https://presigned.url/file", nil)
+	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
+		Return(&client.TranscriptionResult{Text: "hola", Duration: 1.5}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).
+		Return(&models.Thread{ID: threadID, Locale: "es-ES"}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{Role: "user", Content: "hola"}}, nil)
+	openAIClient.On("Generate", mock.Anything).Return(reply, nil)
+	ttsClient.On("Synthesize", mock.Anything, spoken).
+		Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 2.0}, nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	service := NewConversationService(
+		nil, messageRepo, threadRepo, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
+	)
+
+	turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, reply, turn.AssistantMessage.Content)
+	require.NotNil(t, turn.AssistantMessage.SpokenText)
+	assert.Equal(t, spoken, *turn.AssistantMessage.SpokenText)
+	ttsClient.AssertExpectations(t)
+}
+
+func TestConversationService_ProcessAudioMessage_PractisesThreadLanguage(t *testing.T) {
+	threadID := uuid.New()
+	audioContent := []byte("fake audio data")
+	fileHeader := &multipart.FileHeader{
+		Filename: "test.webm",
+		Size:     int64(len(audioContent)),
+	}
+
+	setup := func(tts client.TTSClient) (*ConversationService, *clientmocks.MockWhisperClient, *clientmocks.MockOpenAIClient) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		threadRepo := new(repomocks.MockThreadRepository)
+		whisperClient := new(clientmocks.MockWhisperClient)
+		openAIClient := new(clientmocks.MockOpenAIClient)
+		storageClient := new(clientmocks.MockStorageClient)
+
+		storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return("https://storage.url/file", nil)
+		storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
+			Return("https://presigned.url/file", nil)
+		whisperClient.On("TranscribeFromURL", mock.Anything, "https://presigned.url/file", "fr").
+			Return(&client.TranscriptionResult{Text: "bonjour", Duration: 1.5}, nil)
+		threadRepo.On("FindByID", mock.Anything, threadID).
+			Return(&models.Thread{ID: threadID, Language: "fr"}, nil)
+		messageRepo.On("FindByThreadID", mock.Anything, threadID).
+			Return([]models.Message{{Role: "user", Content: "bonjour"}}, nil)
+		openAIClient.On("Generate", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+			return history[0].Role == "system" && strings.Contains(history[0].Content, "Always reply in French")
+		})).Return("Bonjour ! Ça va ?", nil)
+		messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		service := NewConversationService(
+			nil, messageRepo, threadRepo, whisperClient, openAIClient, tts, storageClient, nil, nil, nil,
+			nil, // runtime settings (defaults)
+		)
+		return service, whisperClient, openAIClient
+	}
+
+	t.Run("transcribes, replies and speaks in it", func(t *testing.T) {
+		ttsClient := new(clientmocks.MockTTSClient)
+		frenchVoice := new(clientmocks.MockTTSClient)
+		ttsClient.On("ForLanguage", "fr").Return(frenchVoice)
+		frenchVoice.On("Synthesize", mock.Anything, "Bonjour ! Ça va ?").
+			Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 1.0}, nil)
+		service, whisperClient, openAIClient := setup(ttsClient)
+
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, newMockMultipartFile(audioContent), fileHeader, "")
+
+		require.NoError(t, err)
+		assert.True(t, turn.AssistantMessage.HasAudio)
+		whisperClient.AssertExpectations(t)
+		openAIClient.AssertExpectations(t)
+		frenchVoice.AssertExpectations(t)
+		ttsClient.AssertNotCalled(t, "Synthesize", mock.Anything, mock.Anything)
+	})
+
+	t.Run("replies without audio when the TTS backend only speaks English", func(t *testing.T) {
+		service, _, _ := setup(&englishOnlyTTS{})
+
+		turn, err := service.ProcessAudioMessage(context.Background(), threadID, newMockMultipartFile(audioContent), fileHeader, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bonjour ! Ça va ?", turn.AssistantMessage.Content)
+		assert.False(t, turn.AssistantMessage.HasAudio)
+	})
+}
+
+// englishOnlyTTS is a TTS backend without other languages, like Chatterbox.
+// Calling it fails the test.
+type englishOnlyTTS struct {
+	client.TTSClient
+}
+
+func TestConversationService_ProcessAudioMessage_AdaptsToStrugglingLearner(t *testing.T) {
+	threadID := uuid.New()
+	audioContent := []byte("fake audio data")
+	audioFile := newMockMultipartFile(audioContent)
+	fileHeader := &multipart.FileHeader{
+		Filename: "test.webm",
+		Size:     int64(len(audioContent)),
+	}
+
+	// Two short answers, each given half a minute after the question
+	start := time.Now().Add(-time.Hour)
+	history := []models.Message{
+		{Role: "assistant", Content: "¿Qué hiciste ayer?", Timestamp: start},
+		{Role: "user", Content: "eh... yo", Timestamp: start.Add(30 * time.Second)},
+		{Role: "assistant", Content: "¿Fuiste al parque?", Timestamp: start.Add(time.Minute)},
+		{Role: "user", Content: "sí", Timestamp: start.Add(90 * time.Second)},
+	}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	whisperClient := new(clientmocks.MockWhisperClient)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("https://storage.url/file", nil)
+	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
+		Return("https://presigned.url/file", nil)
+	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
+		Return(&client.TranscriptionResult{Text: "sí", Duration: 1.5}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(history, nil)
+	openAIClient.On("Generate", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+		last := history[len(history)-1]
+		return last.Role == "system" && strings.Contains(last.Content, "struggling")
+	})).Return("¿Te gusta el parque?", nil)
+	ttsClient.On("SynthesizeAtRate", mock.Anything, "¿Te gusta el parque?", SimplifiedSpeechRate).
+		Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 1.0}, nil)
+	messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+		return msg.Role == "user"
+	})).Return(nil)
+	messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+		return msg.Role == "assistant" && msg.Adaptation["level"] == string(AdaptSimplify)
+	})).Return(nil)
+
+	service := NewConversationService(
+		nil, messageRepo, nil, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+		nil, // runtime settings (defaults)
+	)
+	service.Adaptation = NewAdaptationService()
+
+	_, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
+
+	require.NoError(t, err)
+	openAIClient.AssertExpectations(t)
+	ttsClient.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestConversationService_ProcessAudioMessage_PunctuatesTranscript(t *testing.T) {
+	tests := []struct {
+		name        string
+		transcript  string
+		normalized  string
+		normErr     error
+		wantContent string
+		wantRaw     bool
+	}{
+		{"restores punctuation", "i went to the market yesterday", "I went to the market yesterday.", nil, "I went to the market yesterday.", true},
+		{"changed words are discarded", "i goed to the market", "I went to the market.", nil, "i goed to the market", false},
+		{"failure keeps raw transcript", "i went to the market", "", errors.New("timeout"), "i went to the market", false},
+		{"punctuated transcript is left alone", "I went to the market.", "", nil, "I went to the market.", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadID := uuid.New()
+			audioContent := []byte("fake audio data")
+			audioFile := newMockMultipartFile(audioContent)
+			fileHeader := &multipart.FileHeader{
+				Filename: "test.webm",
+				Size:     int64(len(audioContent)),
+			}
+
+			messageRepo := new(repomocks.MockMessageRepository)
+			threadRepo := new(repomocks.MockThreadRepository)
+			whisperClient := new(clientmocks.MockWhisperClient)
+			openAIClient := new(clientmocks.MockOpenAIClient)
+			ttsClient := new(clientmocks.MockTTSClient)
+			storageClient := new(clientmocks.MockStorageClient)
+
+			storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return("https://storage.url/file", nil)
+			storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
+				Return("https://presigned.url/file", nil)
+			whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
+				Return(&client.TranscriptionResult{Text: tt.transcript, Duration: 2.0}, nil)
+			threadRepo.On("FindByID", mock.Anything, threadID).
+				Return(&models.Thread{ID: threadID, Locale: "en-GB"}, nil)
+			messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			messageRepo.On("FindByThreadID", mock.Anything, threadID).
+				Return([]models.Message{{Role: "user", Content: tt.wantContent}}, nil)
+			openAIClient.On("PunctuateTranscript", tt.transcript, "en-GB").Return(tt.normalized, tt.normErr)
+			openAIClient.On("Generate", mock.Anything).Return("Response", nil)
+			ttsClient.On("Synthesize", mock.Anything, mock.Anything).
+				Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 1.0}, nil)
+
+			service := NewConversationService(
+				nil, messageRepo, threadRepo, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+				nil, // runtime settings (defaults)
+			)
+			service.Normalizer = NewLLMTranscriptNormalizer(openAIClient)
+
+			turn, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantContent, turn.UserMessage.Content)
+			if tt.wantRaw {
+				require.NotNil(t, turn.UserMessage.RawTranscript)
+				assert.Equal(t, tt.transcript, *turn.UserMessage.RawTranscript)
+			} else {
+				assert.Nil(t, turn.UserMessage.RawTranscript)
+			}
+			if !TranscriptNeedsPunctuation(tt.transcript) {
+				openAIClient.AssertNotCalled(t, "PunctuateTranscript", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestConversationService_ProcessAudioMessage_UsesReplyStyle(t *testing.T) {
+	long, fast := models.ReplyLengthLong, 1.25
+	tests := []struct {
+		name       string
+		settings   *models.UserSettings
+		thread     models.Thread
+		wantPrompt string
+		wantRate   float64
+	}{
+		{
+			name:       "user settings",
+			settings:   &models.UserSettings{ReplyLength: models.ReplyLengthShort, SpeechRate: 0.8},
+			wantPrompt: "one or two sentences",
+			wantRate:   0.8,
+		},
+		{
+			name:       "thread overrides",
+			settings:   &models.UserSettings{ReplyLength: models.ReplyLengthShort, SpeechRate: 0.8},
+			thread:     models.Thread{ReplyLength: &long, SpeechRate: &fast},
+			wantPrompt: "four to six sentences",
+			wantRate:   1.25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadID, userID := uuid.New(), uuid.New()
+			audioContent := []byte("fake audio data")
+			audioFile := newMockMultipartFile(audioContent)
+			fileHeader := &multipart.FileHeader{
+				Filename: "test.webm",
+				Size:     int64(len(audioContent)),
+			}
+			thread := tt.thread
+			thread.ID, thread.UserID = threadID, userID
+			tt.settings.UserID = userID
+
+			messageRepo := new(repomocks.MockMessageRepository)
+			threadRepo := new(repomocks.MockThreadRepository)
+			settingsRepo := new(repomocks.MockUserSettingsRepository)
+			whisperClient := new(clientmocks.MockWhisperClient)
+			openAIClient := new(clientmocks.MockOpenAIClient)
+			ttsClient := new(clientmocks.MockTTSClient)
+			storageClient := new(clientmocks.MockStorageClient)
+
+			storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return("https://storage.url/file", nil)
+			storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).
+				Return("https://presigned.url/file", nil)
+			whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
+				Return(&client.TranscriptionResult{Text: "Hello there.", Duration: 1.5}, nil)
+			threadRepo.On("FindByID", mock.Anything, threadID).Return(&thread, nil)
+			settingsRepo.On("FindByUserID", mock.Anything, userID).Return(tt.settings, nil)
+			messageRepo.On("FindByThreadID", mock.Anything, threadID).
+				Return([]models.Message{{Role: "user", Content: "Hello there."}}, nil)
+			messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			openAIClient.On("Generate", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+				last := history[len(history)-1]
+				return last.Role == "system" && strings.Contains(last.Content, tt.wantPrompt)
+			})).Return("Hi! How are you?", nil)
+			ttsClient.On("SynthesizeAtRate", mock.Anything, "Hi! How are you?", tt.wantRate).
+				Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 1.0}, nil)
+
+			service := NewConversationService(
+				nil, messageRepo, threadRepo, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil,
+				nil, // runtime settings (defaults)
+			)
+			service.Settings = NewSettingsServiceForTest(nil, settingsRepo)
+
+			_, err := service.ProcessAudioMessage(context.Background(), threadID, audioFile, fileHeader, "")
+
+			require.NoError(t, err)
+			openAIClient.AssertExpectations(t)
+			ttsClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConversationService_GenerateAssistantResponse_SpeaksInTone(t *testing.T) {
+	threadID := uuid.New()
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{Role: "user", Content: "I passed my exam!"}}, nil)
+	openAIClient.On("Generate", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+		last := history[len(history)-1]
+		return last.Role == "system" && strings.Contains(last.Content, "[tone: cheerful]")
+	})).Return("[tone: cheerful] Congratulations, that's wonderful!", nil)
+	ttsClient.On("SynthesizeWithOptions", mock.Anything, "Congratulations, that's wonderful!", 0.7, "mp3").
+		Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 2.0}, nil)
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("https://storage.url/file", nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	service := NewConversationService(nil, messageRepo, threadRepo, nil, openAIClient, ttsClient, storageClient, nil, nil, nil, nil)
+	service.Tones = NewTonePolicy()
+
+	message, _, err := service.generateAssistantResponse(context.Background(), threadID, true, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Congratulations, that's wonderful!", message.Content)
+	assert.Equal(t, models.ToneCheerful, message.Tone)
+	ttsClient.AssertExpectations(t)
+}
+
+func TestConversationService_GenerateAssistantResponse_CitesCorrections(t *testing.T) {
+	threadID, learnerID := uuid.New(), uuid.New()
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{ID: learnerID, Role: "user", Content: "My brother go to work by bus"}}, nil)
+	openAIClient.On("Generate", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+		last := history[len(history)-1]
+		return last.Role == "system" && strings.Contains(last.Content, "[corrected:")
+	})).Return(`Nice! We say "he goes". [corrected: "go"]`, nil)
+	ttsClient.On("Synthesize", mock.Anything, `Nice! We say "he goes".`).
+		Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 2.0}, nil)
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("https://storage.url/file", nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	service := NewConversationService(nil, messageRepo, threadRepo, nil, openAIClient, ttsClient, storageClient, nil, nil, nil, nil)
+	service.Citations = NewCorrectionCitations()
+
+	message, _, err := service.generateAssistantResponse(context.Background(), threadID, true, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, `Nice! We say "he goes".`, message.Content, "the tag is neither spoken nor shown")
+	assert.Equal(t, models.CorrectionSpans{{Start: 11, End: 13}}, message.Corrections)
+	require.NotNil(t, message.CorrectedMessageID)
+	assert.Equal(t, learnerID, *message.CorrectedMessageID)
+	ttsClient.AssertExpectations(t)
+}
+
+func TestConversationService_GenerateAssistantResponse_StoresLowBitrateVariant(t *testing.T) {
+	threadID := uuid.New()
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{Role: "user", Content: "hello"}}, nil)
+	openAIClient.On("Generate", mock.Anything).Return("Hi there!", nil)
+	ttsClient.On("Synthesize", mock.Anything, "Hi there!").
+		Return(&client.TTSResult{AudioBytes: []byte("mp3"), Duration: 1.0}, nil)
+	ttsClient.On("SynthesizeLowBitrate", mock.Anything, "Hi there!", 1.0).
+		Return(&client.TTSResult{AudioBytes: []byte("opus"), Duration: 1.0}, nil)
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, "audio/mpeg").
+		Return("https://storage.url/file", nil)
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, "audio/ogg").
+		Return("https://storage.url/file", nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	service := NewConversationService(nil, messageRepo, threadRepo, nil, openAIClient, ttsClient, storageClient, nil, nil, nil, nil)
+	service.LowBitrateAudio = true
+
+	message, _, err := service.generateAssistantResponse(context.Background(), threadID, true, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, message.AudioURL)
+	storageClient.AssertCalled(t, "UploadAudio", mock.Anything, mock.Anything, models.LowBitrateAudioKey(*message.AudioURL), "audio/ogg")
+	ttsClient.AssertExpectations(t)
+}
+
+// stubThreadCaps reports every thread as capped or not
+type stubThreadCaps bool
+
+func (s stubThreadCaps) ThreadCapReached(uuid.UUID, []models.Message) (bool, error) {
+	return bool(s), nil
+}
+
+func TestConversationService_GenerateAssistantResponse_WrapsUpCappedThread(t *testing.T) {
+	threadID := uuid.New()
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	openAIClient := new(clientmocks.MockOpenAIClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: uuid.New()}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{Role: "user", Content: "One more thing"}}, nil)
+	openAIClient.On("Generate", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+		return history[len(history)-1] == ThreadWrapUpPrompt()
+	})).Return("Great work today! Start a new conversation to keep going.", nil)
+	ttsClient.On("Synthesize", mock.Anything, mock.Anything).
+		Return(&client.TTSResult{AudioBytes: []byte("audio"), Duration: 2.0}, nil)
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return("https://storage.url/file", nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	threadRepo.On("MarkEnded", mock.Anything, threadID, mock.Anything).Return(true, nil)
+
+	service := NewConversationService(nil, messageRepo, threadRepo, nil, openAIClient, ttsClient, storageClient, nil, nil, nil, nil)
+	service.ThreadCaps = stubThreadCaps(true)
+
+	_, ended, err := service.generateAssistantResponse(context.Background(), threadID, true, nil)
+
+	require.NoError(t, err)
+	assert.True(t, ended)
+	openAIClient.AssertExpectations(t)
+	threadRepo.AssertExpectations(t)
+}
+
+// streamingOpenAIClient streams its reply to GenerateStream a word at a time
+type streamingOpenAIClient struct {
+	*clientmocks.MockOpenAIClient
+	reply string
+}
+
+func (c *streamingOpenAIClient) GenerateStream(ctx context.Context, messages []client.ConversationMessage, onToken func(string)) (string, error) {
+	for i, word := range strings.SplitAfter(c.reply, " ") {
+		if i > 0 && word == "" {
+			continue
+		}
+		onToken(word)
+	}
+	return c.reply, nil
+}
+
+func TestConversationService_StreamAudioMessage_ReportsProgress(t *testing.T) {
+	threadID := uuid.New()
+	audioContent := []byte("fake audio data")
+	fileHeader := &multipart.FileHeader{Filename: "test.webm", Size: int64(len(audioContent))}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	whisperClient := new(clientmocks.MockWhisperClient)
+	ttsClient := new(clientmocks.MockTTSClient)
+	storageClient := new(clientmocks.MockStorageClient)
+	openAIClient := &streamingOpenAIClient{MockOpenAIClient: new(clientmocks.MockOpenAIClient), reply: "Hi there friend"}
+
+	storageClient.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).Return("https://presigned.url/audio.webm", nil)
+	whisperClient.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).
+		Return(&client.TranscriptionResult{Text: "hello world", Duration: 2.5}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+	messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).
+		Return([]models.Message{{ThreadID: threadID, Role: "user", Content: "hello world"}}, nil)
+	ttsClient.On("Synthesize", mock.Anything, "Hi there friend").
+		Return(&client.TTSResult{AudioBytes: []byte("tts"), Duration: 1}, nil)
+
+	service := NewConversationService(nil, messageRepo, threadRepo, whisperClient, openAIClient, ttsClient, storageClient, nil, nil, nil, nil)
+
+	var events []TurnEvent
+	turn, err := service.StreamAudioMessage(context.Background(), threadID, newMockMultipartFile(audioContent), fileHeader, "", func(event TurnEvent) {
+		events = append(events, event)
+	})
+	require.NoError(t, err)
+
+	var types []string
+	var streamed strings.Builder
+	for _, event := range events {
+		types = append(types, event.Type)
+		if event.Type == TurnEventToken {
+			streamed.WriteString(event.Text)
+		}
+	}
+	assert.Equal(t, []string{TurnEventTranscribed, TurnEventToken, TurnEventToken, TurnEventToken, TurnEventReply}, types)
+	assert.Equal(t, "hello world", events[0].Message.Content)
+	assert.Equal(t, "Hi there friend", streamed.String())
+	assert.Equal(t, "Hi there friend", events[len(events)-1].Text)
+	assert.Equal(t, "Hi there friend", turn.AssistantMessage.Content)
+	openAIClient.AssertNotCalled(t, "Generate", mock.Anything)
+}
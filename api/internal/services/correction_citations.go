@@ -0,0 +1,119 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+)
+
+// correctionTag matches a tag citing the learner's words a reply corrects,
+// e.g. [corrected: "he go"], with the space before it
+var correctionTag = regexp.MustCompile(`(?i)\s*\[\s*corrected\s*:\s*["“]([^"”\]]+)["”]\s*\]`)
+
+// CorrectionCitations has the LLM cite the words of the learner's message
+// that its reply corrects, and turns the citations into spans of that
+// message the UI can highlight
+type CorrectionCitations struct{}
+
+// NewCorrectionCitations creates correction citations
+func NewCorrectionCitations() *CorrectionCitations {
+	return &CorrectionCitations{}
+}
+
+// SystemPrompt asks the LLM to tag each correction with the learner's words
+func (c *CorrectionCitations) SystemPrompt() client.ConversationMessage {
+	return client.ConversationMessage{
+		Role: "system",
+		Content: "When your reply corrects a mistake in the learner's last message, end it with one " +
+			"[corrected: \"...\"] tag per mistake, quoting only the learner's mistaken words exactly as they " +
+			"appear in their message, e.g. [corrected: \"he go\"]. Don't add tags when you correct nothing.",
+	}
+}
+
+// Parse strips the correction tags from a reply and locates the cited words
+// in the learner's message. Citations that don't match the message, which
+// the LLM sometimes paraphrases, are dropped.
+func (c *CorrectionCitations) Parse(reply, learnerText string) (string, models.CorrectionSpans) {
+	matches := correctionTag.FindAllStringSubmatch(reply, -1)
+	if matches == nil {
+		return reply, nil
+	}
+	text := strings.TrimSpace(correctionTag.ReplaceAllString(reply, ""))
+
+	// Matching is case-insensitive and rune by rune, so offsets line up
+	// with the original text
+	content := lowerRunes(learnerText)
+	var found [][2]int
+	for _, match := range matches {
+		quote := lowerRunes(strings.TrimSpace(match[1]))
+		if start := findUnclaimed(content, quote, found); start >= 0 {
+			found = append(found, [2]int{start, start + len(quote)})
+		}
+	}
+	if len(found) == 0 {
+		return text, nil
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i][0] < found[j][0] })
+
+	original := []rune(learnerText)
+	spans := make(models.CorrectionSpans, len(found))
+	for i, span := range found {
+		spans[i] = models.CorrectionSpan{Start: utf16Len(original[:span[0]]), End: utf16Len(original[:span[1]])}
+	}
+	return text, spans
+}
+
+// findUnclaimed returns the first index of quote in content that doesn't
+// overlap a span already found, or -1. A mistake made twice is cited twice.
+func findUnclaimed(content, quote []rune, claimed [][2]int) int {
+	if len(quote) == 0 {
+		return -1
+	}
+next:
+	for start := 0; start+len(quote) <= len(content); start++ {
+		for i, r := range quote {
+			if content[start+i] != r {
+				continue next
+			}
+		}
+		for _, span := range claimed {
+			if start < span[1] && span[0] < start+len(quote) {
+				continue next
+			}
+		}
+		return start
+	}
+	return -1
+}
+
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// utf16Len is how many UTF-16 code units the runes take
+func utf16Len(runes []rune) int {
+	n := 0
+	for _, r := range runes {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// lastUserMessage is the learner's latest message in the history, or nil
+func lastUserMessage(messages []models.Message) *models.Message {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return &messages[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ling-app/api/internal/models"
+)
+
+func TestCorrectionCitations_Parse(t *testing.T) {
+	citations := NewCorrectionCitations()
+
+	tests := []struct {
+		name      string
+		reply     string
+		learner   string
+		wantText  string
+		wantSpans models.CorrectionSpans
+	}{
+		{
+			name:      "one correction",
+			reply:     `Almost! It's "he goes". [corrected: "he go"]`,
+			learner:   "Every day he go to school",
+			wantText:  `Almost! It's "he goes".`,
+			wantSpans: models.CorrectionSpans{{Start: 10, End: 15}},
+		},
+		{
+			name:      "several, in message order and case-insensitive",
+			reply:     `Nice story! [corrected: "I has"] [corrected: "Yesterday I go"]`,
+			learner:   "yesterday i go out and I has fun",
+			wantText:  "Nice story!",
+			wantSpans: models.CorrectionSpans{{Start: 0, End: 14}, {Start: 23, End: 28}},
+		},
+		{
+			name:      "the same mistake twice",
+			reply:     `Use "she is". [corrected: "she are"] [corrected: "she are"]`,
+			learner:   "she are tall and she are kind",
+			wantText:  `Use "she is".`,
+			wantSpans: models.CorrectionSpans{{Start: 0, End: 7}, {Start: 17, End: 24}},
+		},
+		{
+			name:      "offsets count UTF-16 units",
+			reply:     `[corrected: "yo sabo"] Se dice "yo sé".`,
+			learner:   "😀 ¡Hola! yo sabo",
+			wantText:  `Se dice "yo sé".`,
+			wantSpans: models.CorrectionSpans{{Start: 10, End: 17}},
+		},
+		{
+			name:     "paraphrased citations are dropped",
+			reply:    `It's "went". [corrected: "goed"]`,
+			learner:  "I go to the park",
+			wantText: `It's "went".`,
+		},
+		{
+			name:     "untagged reply is untouched",
+			reply:    "  Sounds fun!  ",
+			learner:  "I went to the park",
+			wantText: "  Sounds fun!  ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, spans := citations.Parse(tt.reply, tt.learner)
+			assert.Equal(t, tt.wantText, text)
+			assert.Equal(t, tt.wantSpans, spans)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTransactionNotFound      = errors.New("credit transaction not found")
+	ErrTransactionNotDisputable = errors.New("only debits can be disputed")
+	ErrDisputeExists            = errors.New("transaction already disputed")
+)
+
+// Limits for dispute input and message previews
+const (
+	MaxDisputeReasonLength = 1000
+	messageSnippetLength   = 120
+)
+
+// CreditAuditor defines the interface for explaining and disputing credit charges
+type CreditAuditor interface {
+	GetTransactionDetail(userID, transactionID uuid.UUID) (*TransactionDetail, error)
+	OpenDispute(userID, transactionID uuid.UUID, reason string) (*models.CreditDispute, error)
+}
+
+// TransactionDetail is a credit transaction with the message that caused it
+type TransactionDetail struct {
+	Transaction models.CreditTransaction `json:"transaction"`
+	Message     *ChargedMessage          `json:"message,omitempty"`
+	Dispute     *models.CreditDispute    `json:"dispute,omitempty"`
+}
+
+// ChargedMessage summarizes the message a transaction references
+type ChargedMessage struct {
+	ID         uuid.UUID `json:"id"`
+	ThreadID   uuid.UUID `json:"threadId"`
+	ThreadName *string   `json:"threadName,omitempty"`
+	Role       string    `json:"role"`
+	Snippet    string    `json:"snippet"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// CreditAuditService links credit transactions back to messages and records disputes
+type CreditAuditService struct {
+	exec        repository.Executor
+	txRepo      repository.CreditTransactionRepository
+	disputeRepo repository.CreditDisputeRepository
+	messageRepo repository.MessageRepository
+	threadRepo  repository.ThreadRepository
+}
+
+// NewCreditAuditService creates a new credit audit service
+func NewCreditAuditService(
+	database *db.DB,
+	txRepo repository.CreditTransactionRepository,
+	disputeRepo repository.CreditDisputeRepository,
+	messageRepo repository.MessageRepository,
+	threadRepo repository.ThreadRepository,
+) *CreditAuditService {
+	return &CreditAuditService{
+		exec:        database.DB,
+		txRepo:      txRepo,
+		disputeRepo: disputeRepo,
+		messageRepo: messageRepo,
+		threadRepo:  threadRepo,
+	}
+}
+
+// NewCreditAuditServiceForTest creates a CreditAuditService with injected dependencies for testing.
+func NewCreditAuditServiceForTest(
+	exec repository.Executor,
+	txRepo repository.CreditTransactionRepository,
+	disputeRepo repository.CreditDisputeRepository,
+	messageRepo repository.MessageRepository,
+	threadRepo repository.ThreadRepository,
+) *CreditAuditService {
+	return &CreditAuditService{
+		exec:        exec,
+		txRepo:      txRepo,
+		disputeRepo: disputeRepo,
+		messageRepo: messageRepo,
+		threadRepo:  threadRepo,
+	}
+}
+
+// GetTransactionDetail returns one of the user's transactions along with the
+// referenced message (if the reference is a message the user owns) and any dispute
+func (s *CreditAuditService) GetTransactionDetail(userID, transactionID uuid.UUID) (*TransactionDetail, error) {
+	transaction, err := s.findTransaction(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &TransactionDetail{
+		Transaction: *transaction,
+		Message:     s.findChargedMessage(userID, transaction.Reference),
+	}
+
+	dispute, err := s.disputeRepo.FindByTransactionID(s.exec, transaction.ID)
+	if err == nil {
+		detail.Dispute = dispute
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("find dispute: %w", err)
+	}
+
+	return detail, nil
+}
+
+// OpenDispute flags a debit for admin review. Each transaction can be disputed once.
+func (s *CreditAuditService) OpenDispute(userID, transactionID uuid.UUID, reason string) (*models.CreditDispute, error) {
+	transaction, err := s.findTransaction(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Type != models.TransactionDebit {
+		return nil, ErrTransactionNotDisputable
+	}
+
+	_, err = s.disputeRepo.FindByTransactionID(s.exec, transaction.ID)
+	if err == nil {
+		return nil, ErrDisputeExists
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("find dispute: %w", err)
+	}
+
+	dispute := &models.CreditDispute{
+		UserID:        userID,
+		TransactionID: transaction.ID,
+		Reason:        reason,
+		Status:        models.DisputeOpen,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.disputeRepo.Create(s.exec, dispute); err != nil {
+		return nil, fmt.Errorf("create dispute: %w", err)
+	}
+	return dispute, nil
+}
+
+func (s *CreditAuditService) findTransaction(userID, transactionID uuid.UUID) (*models.CreditTransaction, error) {
+	transaction, err := s.txRepo.FindByIDAndUserID(s.exec, transactionID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find transaction: %w", err)
+	}
+	return transaction, nil
+}
+
+// findChargedMessage resolves a transaction reference to a message in one of
+// the user's threads. References that aren't message IDs (subscription changes,
+// goal bonuses) or point at deleted messages yield nil.
+func (s *CreditAuditService) findChargedMessage(userID uuid.UUID, reference *string) *ChargedMessage {
+	if reference == nil {
+		return nil
+	}
+	messageID, err := uuid.Parse(*reference)
+	if err != nil {
+		return nil
+	}
+
+	message, err := s.messageRepo.FindByID(s.exec, messageID)
+	if err != nil {
+		return nil
+	}
+	thread, err := s.threadRepo.FindByIDAndUserID(s.exec, message.ThreadID, userID)
+	if err != nil {
+		return nil
+	}
+
+	return &ChargedMessage{
+		ID:         message.ID,
+		ThreadID:   thread.ID,
+		ThreadName: thread.Name,
+		Role:       message.Role,
+		Snippet:    snippet(message.Content, messageSnippetLength),
+		Timestamp:  message.Timestamp,
+	}
+}
+
+// snippet truncates text to at most n runes, adding an ellipsis when cut
+func snippet(text string, n int) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+type creditAuditTestDeps struct {
+	txRepo      *repomocks.MockCreditTransactionRepository
+	disputeRepo *repomocks.MockCreditDisputeRepository
+	messageRepo *repomocks.MockMessageRepository
+	threadRepo  *repomocks.MockThreadRepository
+}
+
+func newCreditAuditTestService() (*CreditAuditService, creditAuditTestDeps) {
+	deps := creditAuditTestDeps{
+		txRepo:      new(repomocks.MockCreditTransactionRepository),
+		disputeRepo: new(repomocks.MockCreditDisputeRepository),
+		messageRepo: new(repomocks.MockMessageRepository),
+		threadRepo:  new(repomocks.MockThreadRepository),
+	}
+	service := NewCreditAuditServiceForTest(nil, deps.txRepo, deps.disputeRepo, deps.messageRepo, deps.threadRepo)
+	return service, deps
+}
+
+func TestCreditAuditService_GetTransactionDetail(t *testing.T) {
+	userID := uuid.New()
+	threadID := uuid.New()
+	messageID := uuid.New()
+	txID := uuid.New()
+	reference := messageID.String()
+	threadName := "Ordering coffee"
+
+	t.Run("links the referenced message and thread", func(t *testing.T) {
+		service, deps := newCreditAuditTestService()
+
+		deps.txRepo.On("FindByIDAndUserID", mock.Anything, txID, userID).Return(&models.CreditTransaction{
+			ID: txID, UserID: userID, Type: models.TransactionDebit, Amount: -1, Reference: &reference,
+		}, nil)
+		deps.messageRepo.On("FindByID", mock.Anything, messageID).Return(&models.Message{
+			ID: messageID, ThreadID: threadID, Role: "assistant", Content: "Sure, what size would you like?", Timestamp: time.Now(),
+		}, nil)
+		deps.threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{
+			ID: threadID, UserID: userID, Name: &threadName,
+		}, nil)
+		deps.disputeRepo.On("FindByTransactionID", mock.Anything, txID).Return(nil, repository.ErrNotFound)
+
+		detail, err := service.GetTransactionDetail(userID, txID)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, detail.Message) {
+			assert.Equal(t, "Sure, what size would you like?", detail.Message.Snippet)
+			assert.Equal(t, &threadName, detail.Message.ThreadName)
+		}
+		assert.Nil(t, detail.Dispute)
+	})
+
+	t.Run("non-message reference has no message", func(t *testing.T) {
+		service, deps := newCreditAuditTestService()
+		subscriptionRef := "Upgraded to pro"
+
+		deps.txRepo.On("FindByIDAndUserID", mock.Anything, txID, userID).Return(&models.CreditTransaction{
+			ID: txID, UserID: userID, Type: models.TransactionCredit, Reference: &subscriptionRef,
+		}, nil)
+		deps.disputeRepo.On("FindByTransactionID", mock.Anything, txID).Return(nil, repository.ErrNotFound)
+
+		detail, err := service.GetTransactionDetail(userID, txID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, detail.Message)
+	})
+
+	t.Run("returns ErrTransactionNotFound for another user's transaction", func(t *testing.T) {
+		service, deps := newCreditAuditTestService()
+		deps.txRepo.On("FindByIDAndUserID", mock.Anything, txID, userID).Return(nil, repository.ErrNotFound)
+
+		_, err := service.GetTransactionDetail(userID, txID)
+
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
+
+func TestCreditAuditService_OpenDispute(t *testing.T) {
+	userID := uuid.New()
+	txID := uuid.New()
+
+	t.Run("opens a dispute for a debit", func(t *testing.T) {
+		service, deps := newCreditAuditTestService()
+
+		deps.txRepo.On("FindByIDAndUserID", mock.Anything, txID, userID).Return(&models.CreditTransaction{
+			ID: txID, UserID: userID, Type: models.TransactionDebit,
+		}, nil)
+		deps.disputeRepo.On("FindByTransactionID", mock.Anything, txID).Return(nil, repository.ErrNotFound)
+		deps.disputeRepo.On("Create", mock.Anything, mock.MatchedBy(func(d *models.CreditDispute) bool {
+			return d.TransactionID == txID && d.Status == models.DisputeOpen && d.Reason == "Charged twice"
+		})).Return(nil)
+
+		dispute, err := service.OpenDispute(userID, txID, "Charged twice")
+
+		assert.NoError(t, err)
+		assert.Equal(t, userID, dispute.UserID)
+		deps.disputeRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects non-debit transactions", func(t *testing.T) {
+		service, deps := newCreditAuditTestService()
+		deps.txRepo.On("FindByIDAndUserID", mock.Anything, txID, userID).Return(&models.CreditTransaction{
+			ID: txID, UserID: userID, Type: models.TransactionRefresh,
+		}, nil)
+
+		_, err := service.OpenDispute(userID, txID, "Why?")
+
+		assert.ErrorIs(t, err, ErrTransactionNotDisputable)
+	})
+
+	t.Run("rejects a second dispute", func(t *testing.T) {
+		service, deps := newCreditAuditTestService()
+		deps.txRepo.On("FindByIDAndUserID", mock.Anything, txID, userID).Return(&models.CreditTransaction{
+			ID: txID, UserID: userID, Type: models.TransactionDebit,
+		}, nil)
+		deps.disputeRepo.On("FindByTransactionID", mock.Anything, txID).Return(&models.CreditDispute{ID: uuid.New()}, nil)
+
+		_, err := service.OpenDispute(userID, txID, "Again")
+
+		assert.ErrorIs(t, err, ErrDisputeExists)
+	})
+}
@@ -1,12 +1,14 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
 	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
 
@@ -17,8 +19,17 @@ import (
 var (
 	ErrInsufficientCredits = errors.New("insufficient credits")
 	ErrCreditsNotFound     = errors.New("credits record not found")
+	ErrReservationSettled  = errors.New("credit reservation already settled")
 )
 
+// LowCreditsThreshold is the balance below which a debit publishes CreditsLow
+const LowCreditsThreshold = 5
+
+// ReservationTTL is how long a reservation may stay held. Turns settle theirs
+// within seconds; one still held after this was abandoned, and
+// ReservationExpiryWorker gives the credits back.
+const ReservationTTL = 30 * time.Minute
+
 // TxRunner is an interface for running database transactions.
 type TxRunner interface {
 	Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error
@@ -31,19 +42,35 @@ type CreditsManager interface {
 	HasCredits(userID uuid.UUID, amount int) (bool, error)
 	DeductCredits(userID uuid.UUID, amount int, reference, description string) error
 	AddCredits(userID uuid.UUID, amount int, description string) error
+	RefundCredits(userID uuid.UUID, amount int, reference, description string) error
+	ReserveCredits(userID uuid.UUID, amount int, reference, description string) (*models.CreditReservation, error)
+	CaptureReservation(id uuid.UUID) error
+	ReduceReservation(id uuid.UUID, amount int) error
+	ReleaseReservation(id uuid.UUID) error
+	RefundReservation(reference, description string) (bool, error)
 	RefreshMonthlyCredits(userID uuid.UUID) error
 	InitializeCredits(userID uuid.UUID, tier models.SubscriptionTier) error
 	UpdateAllowance(userID uuid.UUID, tier models.SubscriptionTier) error
-	GetTransactionHistory(userID uuid.UUID, limit int) ([]models.CreditTransaction, error)
+	GetTransactionHistory(userID uuid.UUID, limit, offset int) ([]models.CreditTransaction, error)
 }
 
-// CreditsService handles credit balance operations
+// CreditsService handles credit balance operations. Everything that writes a
+// balance reads the row with FindByUserIDForUpdate first, so grants, refunds
+// and refreshes can't overwrite a reservation made meanwhile.
 type CreditsService struct {
 	db          *db.DB
 	exec        repository.Executor
 	txRunner    TxRunner
 	creditsRepo repository.CreditsRepository
 	txRepo      repository.CreditTransactionRepository
+	holdRepo    repository.CreditReservationRepository
+
+	// Runtime supplies the tier allowances in force; nil uses the defaults
+	Runtime *RuntimeSettingsService
+
+	// Events receives CreditsLow when a debit crosses LowCreditsThreshold
+	// (optional)
+	Events *events.Bus
 }
 
 // NewCreditsService creates a new credits service
@@ -51,6 +78,7 @@ func NewCreditsService(
 	database *db.DB,
 	creditsRepo repository.CreditsRepository,
 	txRepo repository.CreditTransactionRepository,
+	holdRepo repository.CreditReservationRepository,
 ) *CreditsService {
 	return &CreditsService{
 		db:          database,
@@ -58,6 +86,7 @@ func NewCreditsService(
 		txRunner:    database.DB,
 		creditsRepo: creditsRepo,
 		txRepo:      txRepo,
+		holdRepo:    holdRepo,
 	}
 }
 
@@ -67,6 +96,7 @@ func NewCreditsServiceForTest(
 	txRunner TxRunner,
 	creditsRepo repository.CreditsRepository,
 	txRepo repository.CreditTransactionRepository,
+	holdRepo repository.CreditReservationRepository,
 ) *CreditsService {
 	return &CreditsService{
 		db:          nil,
@@ -74,6 +104,7 @@ func NewCreditsServiceForTest(
 		txRunner:    txRunner,
 		creditsRepo: creditsRepo,
 		txRepo:      txRepo,
+		holdRepo:    holdRepo,
 	}
 }
 
@@ -113,8 +144,9 @@ func (s *CreditsService) HasCredits(userID uuid.UUID, amount int) (bool, error)
 
 // DeductCredits removes credits from a user's balance
 func (s *CreditsService) DeductCredits(userID uuid.UUID, amount int, reference, description string) error {
-	return s.txRunner.Transaction(func(tx *gorm.DB) error {
-		credits, err := s.creditsRepo.FindByUserID(tx, userID)
+	var before, after int
+	err := s.txRunner.Transaction(func(tx *gorm.DB) error {
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, userID)
 		if err != nil {
 			return fmt.Errorf("failed to get credits: %w", err)
 		}
@@ -124,7 +156,9 @@ func (s *CreditsService) DeductCredits(userID uuid.UUID, amount int, reference,
 		}
 
 		// Update balance
+		before = credits.Balance
 		credits.Balance -= amount
+		after = credits.Balance
 		credits.UsedThisPeriod += amount
 		if err := s.creditsRepo.Save(tx, credits); err != nil {
 			return fmt.Errorf("failed to update credits: %w", err)
@@ -145,18 +179,290 @@ func (s *CreditsService) DeductCredits(userID uuid.UUID, amount int, reference,
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.publishIfLow(userID, before, after)
+	return nil
+}
+
+// ReserveCredits takes amount from the user's balance and holds it for work
+// about to be done. The credits row stays locked until the hold is written,
+// so concurrent requests can't spend the same credits. The hold must end
+// with CaptureReservation once the work is delivered, or ReleaseReservation
+// if it fails.
+func (s *CreditsService) ReserveCredits(userID uuid.UUID, amount int, reference, description string) (*models.CreditReservation, error) {
+	var before, after int
+	reservation := &models.CreditReservation{
+		UserID:      userID,
+		Amount:      amount,
+		Status:      models.ReservationHeld,
+		Reference:   reference,
+		Description: description,
+		ExpiresAt:   time.Now().Add(ReservationTTL),
+	}
+	err := s.txRunner.Transaction(func(tx *gorm.DB) error {
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get credits: %w", err)
+		}
+
+		if credits.Balance < amount {
+			return ErrInsufficientCredits
+		}
+
+		before = credits.Balance
+		credits.Balance -= amount
+		after = credits.Balance
+		if err := s.creditsRepo.Save(tx, credits); err != nil {
+			return fmt.Errorf("failed to update credits: %w", err)
+		}
+
+		if err := s.holdRepo.Create(tx, reservation); err != nil {
+			return fmt.Errorf("failed to create reservation: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishIfLow(userID, before, after)
+	return reservation, nil
+}
+
+// CaptureReservation charges held credits for good: they count towards the
+// period's usage and show in the history as a debit with the reservation's
+// reference. A reservation no longer held (captured, released or expired)
+// gives ErrReservationSettled.
+func (s *CreditsService) CaptureReservation(id uuid.UUID) error {
+	return s.txRunner.Transaction(func(tx *gorm.DB) error {
+		reservation, err := s.settle(tx, id, models.ReservationCaptured)
+		if err != nil {
+			return err
+		}
+
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, reservation.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get credits: %w", err)
+		}
+
+		credits.UsedThisPeriod += reservation.Amount
+		if err := s.creditsRepo.Save(tx, credits); err != nil {
+			return fmt.Errorf("failed to update credits: %w", err)
+		}
+
+		transaction := &models.CreditTransaction{
+			UserID:       reservation.UserID,
+			Type:         models.TransactionDebit,
+			Amount:       -reservation.Amount,
+			BalanceAfter: credits.Balance,
+			Reference:    &reservation.Reference,
+			Description:  reservation.Description,
+		}
+		if err := s.txRepo.Create(tx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ReduceReservation lowers a held reservation to amount and gives the rest
+// back to the balance, for a turn reserved at an upper bound before its
+// cost was known. Nothing is recorded in the history; the capture records
+// what is charged. A reservation no longer held gives ErrReservationSettled.
+func (s *CreditsService) ReduceReservation(id uuid.UUID, amount int) error {
+	return s.txRunner.Transaction(func(tx *gorm.DB) error {
+		reservation, err := s.holdRepo.FindByID(tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get reservation: %w", err)
+		}
+		if reservation.Status != models.ReservationHeld {
+			return ErrReservationSettled
+		}
+		if amount >= reservation.Amount {
+			return nil
+		}
+
+		reduced, err := s.holdRepo.Reduce(tx, id, reservation.Amount, amount)
+		if err != nil {
+			return fmt.Errorf("failed to reduce reservation: %w", err)
+		}
+		if !reduced {
+			return ErrReservationSettled
+		}
+
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, reservation.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get credits: %w", err)
+		}
+		credits.Balance += reservation.Amount - amount
+		if err := s.creditsRepo.Save(tx, credits); err != nil {
+			return fmt.Errorf("failed to update credits: %w", err)
+		}
+		return nil
+	})
+}
+
+// ReleaseReservation gives held credits back to the balance, for work that
+// was never delivered. Nothing is recorded in the history. A reservation no
+// longer held gives ErrReservationSettled.
+func (s *CreditsService) ReleaseReservation(id uuid.UUID) error {
+	return s.txRunner.Transaction(func(tx *gorm.DB) error {
+		reservation, err := s.settle(tx, id, models.ReservationReleased)
+		if err != nil {
+			return err
+		}
+
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, reservation.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get credits: %w", err)
+		}
+
+		credits.Balance += reservation.Amount
+		if err := s.creditsRepo.Save(tx, credits); err != nil {
+			return fmt.Errorf("failed to update credits: %w", err)
+		}
+		return nil
+	})
+}
+
+// RefundReservation gives back what was charged for the turn reserved under
+// reference, for a turn that fell short of what was paid for. Only a
+// captured reservation is refunded, and only once: a turn still held,
+// released, expired or already refunded reports false and changes nothing.
+// The refund shows in the history with the reservation's reference.
+func (s *CreditsService) RefundReservation(reference, description string) (bool, error) {
+	refunded := false
+	err := s.txRunner.Transaction(func(tx *gorm.DB) error {
+		reservation, err := s.holdRepo.FindByReference(tx, reference)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get reservation: %w", err)
+		}
+
+		ok, err := s.holdRepo.MarkRefunded(tx, reservation.ID, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to mark reservation refunded: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, reservation.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get credits: %w", err)
+		}
+		credits.Balance += reservation.Amount
+		credits.UsedThisPeriod = max(credits.UsedThisPeriod-reservation.Amount, 0)
+		if err := s.creditsRepo.Save(tx, credits); err != nil {
+			return fmt.Errorf("failed to update credits: %w", err)
+		}
+
+		transaction := &models.CreditTransaction{
+			UserID:       reservation.UserID,
+			Type:         models.TransactionRefund,
+			Amount:       reservation.Amount,
+			BalanceAfter: credits.Balance,
+			Reference:    &reservation.Reference,
+			Description:  description,
+		}
+		if err := s.txRepo.Create(tx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+		refunded = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return refunded, nil
+}
+
+// settle moves a held reservation to status and returns it
+func (s *CreditsService) settle(exec repository.Executor, id uuid.UUID, status models.CreditReservationStatus) (*models.CreditReservation, error) {
+	reservation, err := s.holdRepo.FindByID(exec, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	settled, err := s.holdRepo.Settle(exec, id, status, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to settle reservation: %w", err)
+	}
+	if !settled {
+		return nil, ErrReservationSettled
+	}
+	return reservation, nil
+}
+
+// publishIfLow publishes CreditsLow when a debit takes the balance from
+// before to after across LowCreditsThreshold
+func (s *CreditsService) publishIfLow(userID uuid.UUID, before, after int) {
+	if before >= LowCreditsThreshold && after < LowCreditsThreshold {
+		s.Events.Publish(context.Background(), events.CreditsLow{
+			UserID:    userID,
+			Balance:   after,
+			Threshold: LowCreditsThreshold,
+		})
+	}
 }
 
 // AddCredits adds credits to a user's balance
 func (s *CreditsService) AddCredits(userID uuid.UUID, amount int, description string) error {
 	return s.txRunner.Transaction(func(tx *gorm.DB) error {
-		credits, err := s.creditsRepo.FindByUserID(tx, userID)
+		return s.addCreditsWithTx(tx, userID, amount, description)
+	})
+}
+
+func (s *CreditsService) addCreditsWithTx(exec repository.Executor, userID uuid.UUID, amount int, description string) error {
+	credits, err := s.creditsRepo.FindByUserIDForUpdate(exec, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get credits: %w", err)
+	}
+
+	// Update balance
+	credits.Balance += amount
+	if err := s.creditsRepo.Save(exec, credits); err != nil {
+		return fmt.Errorf("failed to update credits: %w", err)
+	}
+
+	// Record transaction
+	transaction := &models.CreditTransaction{
+		UserID:       userID,
+		Type:         models.TransactionCredit,
+		Amount:       amount,
+		BalanceAfter: credits.Balance,
+		Description:  description,
+	}
+	if err := s.txRepo.Create(exec, transaction); err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RefundCredits returns credits taken by DeductCredits or a captured
+// reservation for work that was never delivered. Unlike AddCredits it also
+// gives back the period usage and records a TransactionRefund with the
+// reference, so the refund can be matched to the original debit.
+func (s *CreditsService) RefundCredits(userID uuid.UUID, amount int, reference, description string) error {
+	return s.txRunner.Transaction(func(tx *gorm.DB) error {
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, userID)
 		if err != nil {
 			return fmt.Errorf("failed to get credits: %w", err)
 		}
 
 		// Update balance
 		credits.Balance += amount
+		credits.UsedThisPeriod -= amount
+		if credits.UsedThisPeriod < 0 {
+			credits.UsedThisPeriod = 0
+		}
 		if err := s.creditsRepo.Save(tx, credits); err != nil {
 			return fmt.Errorf("failed to update credits: %w", err)
 		}
@@ -164,9 +470,10 @@ func (s *CreditsService) AddCredits(userID uuid.UUID, amount int, description st
 		// Record transaction
 		transaction := &models.CreditTransaction{
 			UserID:       userID,
-			Type:         models.TransactionCredit,
+			Type:         models.TransactionRefund,
 			Amount:       amount,
 			BalanceAfter: credits.Balance,
+			Reference:    &reference,
 			Description:  description,
 		}
 		if err := s.txRepo.Create(tx, transaction); err != nil {
@@ -180,14 +487,19 @@ func (s *CreditsService) AddCredits(userID uuid.UUID, amount int, description st
 // RefreshMonthlyCredits resets the user's credits to their monthly allowance
 func (s *CreditsService) RefreshMonthlyCredits(userID uuid.UUID) error {
 	return s.txRunner.Transaction(func(tx *gorm.DB) error {
-		credits, err := s.creditsRepo.FindByUserID(tx, userID)
+		credits, err := s.creditsRepo.FindByUserIDForUpdate(tx, userID)
 		if err != nil {
 			return fmt.Errorf("failed to get credits: %w", err)
 		}
 
-		// Reset to monthly allowance
+		// Reset to monthly allowance, less what turns in flight hold; those
+		// credits come back to the balance when their hold is released
+		held, err := s.holdRepo.SumHeld(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get held credits: %w", err)
+		}
 		oldBalance := credits.Balance
-		credits.Balance = credits.MonthlyAllowance
+		credits.Balance = max(credits.MonthlyAllowance-held, 0)
 		credits.UsedThisPeriod = 0
 		credits.LastRefreshedAt = time.Now()
 		if err := s.creditsRepo.Save(tx, credits); err != nil {
@@ -198,7 +510,7 @@ func (s *CreditsService) RefreshMonthlyCredits(userID uuid.UUID) error {
 		transaction := &models.CreditTransaction{
 			UserID:       userID,
 			Type:         models.TransactionRefresh,
-			Amount:       credits.MonthlyAllowance - oldBalance,
+			Amount:       credits.Balance - oldBalance,
 			BalanceAfter: credits.Balance,
 			Description:  "Monthly credit refresh",
 		}
@@ -217,14 +529,17 @@ func (s *CreditsService) InitializeCredits(userID uuid.UUID, tier models.Subscri
 
 // InitializeCreditsWithTx creates a credits record using an existing transaction/executor
 func (s *CreditsService) InitializeCreditsWithTx(exec repository.Executor, userID uuid.UUID, tier models.SubscriptionTier) error {
-	allowance := models.TierCredits[tier]
-	if allowance == 0 {
-		allowance = models.TierCredits[models.TierFree]
-	}
+	return s.InitializeTrialCreditsWithTx(exec, userID, tier, s.Runtime.Current().TierAllowance(tier))
+}
+
+// InitializeTrialCreditsWithTx creates a credits record with the tier's
+// allowance but a different starting balance, e.g. a reduced trial
+func (s *CreditsService) InitializeTrialCreditsWithTx(exec repository.Executor, userID uuid.UUID, tier models.SubscriptionTier, balance int) error {
+	allowance := s.Runtime.Current().TierAllowance(tier)
 
 	credits := &models.Credits{
 		UserID:           userID,
-		Balance:          allowance,
+		Balance:          balance,
 		MonthlyAllowance: allowance,
 		UsedThisPeriod:   0,
 		LastRefreshedAt:  time.Now(),
@@ -236,19 +551,44 @@ func (s *CreditsService) InitializeCreditsWithTx(exec repository.Executor, userI
 	return nil
 }
 
+// InitializeDemoCreditsWithTx creates a guest's credits record: a one-off
+// demo balance with no monthly allowance, recorded as a demo transaction so
+// it stays apart from credits users are granted or pay for
+func (s *CreditsService) InitializeDemoCreditsWithTx(exec repository.Executor, userID uuid.UUID, balance int) error {
+	credits := &models.Credits{
+		UserID:           userID,
+		Balance:          balance,
+		MonthlyAllowance: 0,
+		LastRefreshedAt:  time.Now(),
+	}
+	if err := s.creditsRepo.Create(exec, credits); err != nil {
+		return fmt.Errorf("failed to initialize credits: %w", err)
+	}
+
+	transaction := &models.CreditTransaction{
+		UserID:       userID,
+		Type:         models.TransactionDemo,
+		Amount:       balance,
+		BalanceAfter: balance,
+		Description:  "Demo credits",
+	}
+	if err := s.txRepo.Create(exec, transaction); err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return nil
+}
+
 // UpdateAllowance updates the monthly allowance based on subscription tier
 func (s *CreditsService) UpdateAllowance(userID uuid.UUID, tier models.SubscriptionTier) error {
-	allowance := models.TierCredits[tier]
-	if allowance == 0 {
-		allowance = models.TierCredits[models.TierFree]
-	}
+	allowance := s.Runtime.Current().TierAllowance(tier)
 
 	return s.creditsRepo.UpdateAllowance(s.exec, userID, allowance)
 }
 
-// GetTransactionHistory returns recent credit transactions for a user
-func (s *CreditsService) GetTransactionHistory(userID uuid.UUID, limit int) ([]models.CreditTransaction, error) {
-	transactions, err := s.txRepo.FindByUserID(s.exec, userID, limit)
+// GetTransactionHistory returns a page of a user's credit transactions,
+// newest first, skipping the first offset
+func (s *CreditsService) GetTransactionHistory(userID uuid.UUID, limit, offset int) ([]models.CreditTransaction, error) {
+	transactions, err := s.txRepo.FindByUserID(s.exec, userID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction history: %w", err)
 	}
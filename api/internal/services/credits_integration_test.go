@@ -0,0 +1,56 @@
+//go:build integration
+
+package services_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services"
+	"ling-app/api/internal/testutil"
+)
+
+func TestCreditsService_ReserveCredits_Concurrent(t *testing.T) {
+	testDB := testutil.NewTestDB(t)
+	t.Cleanup(testDB.Cleanup)
+	creditsRepo := repository.NewCreditsRepository()
+	service := services.NewCreditsService(testDB.DB, creditsRepo, repository.NewCreditTransactionRepository(), repository.NewCreditReservationRepository())
+
+	user := &models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), Name: "Reserve"}
+	require.NoError(t, testDB.Create(user).Error)
+	require.NoError(t, testDB.Create(&models.Credits{UserID: user.ID, Balance: 5, MonthlyAllowance: 20}).Error)
+
+	// Twice as many turns as the balance covers, all started at once
+	const turns = 10
+	var wg sync.WaitGroup
+	errs := make([]error, turns)
+	for i := range turns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = service.ReserveCredits(user.ID, 1, uuid.NewString(), "Voice message")
+		}()
+	}
+	wg.Wait()
+
+	reserved := 0
+	for _, err := range errs {
+		if err == nil {
+			reserved++
+			continue
+		}
+		assert.ErrorIs(t, err, services.ErrInsufficientCredits)
+	}
+	assert.Equal(t, 5, reserved)
+
+	credits, err := creditsRepo.FindByUserID(testDB.DB.DB, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, credits.Balance)
+}
@@ -1,15 +1,18 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 
+	"ling-app/api/internal/events"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
 	"ling-app/api/internal/repository/mocks"
@@ -29,6 +32,14 @@ func (m *mockTxRunner) Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOp
 	return args.Error(0)
 }
 
+// noHeldCredits is a reservation repository for tests where no credits are
+// held, so a monthly refresh grants the whole allowance
+func noHeldCredits() *mocks.MockCreditReservationRepository {
+	holdRepo := new(mocks.MockCreditReservationRepository)
+	holdRepo.On("SumHeld", mock.Anything, mock.Anything).Return(0, nil).Maybe()
+	return holdRepo
+}
+
 func TestCreditsService_GetCredits(t *testing.T) {
 	userID := uuid.New()
 
@@ -45,7 +56,7 @@ func TestCreditsService_GetCredits(t *testing.T) {
 
 		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(expectedCredits, nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		credits, err := service.GetCredits(userID)
 
 		assert.NoError(t, err)
@@ -60,7 +71,7 @@ func TestCreditsService_GetCredits(t *testing.T) {
 
 		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		credits, err := service.GetCredits(userID)
 
 		assert.ErrorIs(t, err, ErrCreditsNotFound)
@@ -76,7 +87,7 @@ func TestCreditsService_GetCredits(t *testing.T) {
 		dbError := errors.New("database connection failed")
 		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, dbError)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		credits, err := service.GetCredits(userID)
 
 		assert.Error(t, err)
@@ -99,7 +110,7 @@ func TestCreditsService_GetBalance(t *testing.T) {
 			Balance: 50,
 		}, nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		balance, err := service.GetBalance(userID)
 
 		assert.NoError(t, err)
@@ -113,7 +124,7 @@ func TestCreditsService_GetBalance(t *testing.T) {
 
 		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		balance, err := service.GetBalance(userID)
 
 		assert.ErrorIs(t, err, ErrCreditsNotFound)
@@ -134,7 +145,7 @@ func TestCreditsService_HasCredits(t *testing.T) {
 			Balance: 100,
 		}, nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		hasCredits, err := service.HasCredits(userID, 50)
 
 		assert.NoError(t, err)
@@ -151,7 +162,7 @@ func TestCreditsService_HasCredits(t *testing.T) {
 			Balance: 10,
 		}, nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		hasCredits, err := service.HasCredits(userID, 50)
 
 		assert.NoError(t, err)
@@ -165,7 +176,7 @@ func TestCreditsService_HasCredits(t *testing.T) {
 
 		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		hasCredits, err := service.HasCredits(userID, 50)
 
 		assert.NoError(t, err)
@@ -188,7 +199,7 @@ func TestCreditsService_DeductCredits(t *testing.T) {
 		}
 
 		txRunner.On("Transaction", mock.Anything).Return(nil)
-		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
 		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
 			return c.Balance == 90 && c.UsedThisPeriod == 10
 		})).Return(nil)
@@ -196,7 +207,7 @@ func TestCreditsService_DeductCredits(t *testing.T) {
 			return tx.Amount == -10 && tx.Type == models.TransactionDebit
 		})).Return(nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		err := service.DeductCredits(userID, 10, "msg-123", "Test deduction")
 
 		assert.NoError(t, err)
@@ -216,15 +227,249 @@ func TestCreditsService_DeductCredits(t *testing.T) {
 		}
 
 		txRunner.On("Transaction", mock.Anything).Return(nil)
-		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		err := service.DeductCredits(userID, 10, "msg-123", "Test deduction")
 
 		assert.ErrorIs(t, err, ErrInsufficientCredits)
 	})
 }
 
+func TestCreditsService_DeductCredits_PublishesCreditsLow(t *testing.T) {
+	userID := uuid.New()
+
+	deduct := func(balance, amount int) []events.CreditsLow {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: balance}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		var published []events.CreditsLow
+		bus := events.NewBus()
+		events.Subscribe(bus, "test", func(_ context.Context, e events.CreditsLow) error {
+			published = append(published, e)
+			return nil
+		})
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
+		service.Events = bus
+		assert.NoError(t, service.DeductCredits(userID, amount, "msg-123", "Test deduction"))
+		return published
+	}
+
+	t.Run("publishes when the balance crosses the threshold", func(t *testing.T) {
+		published := deduct(LowCreditsThreshold+1, 2)
+
+		assert.Equal(t, []events.CreditsLow{{UserID: userID, Balance: LowCreditsThreshold - 1, Threshold: LowCreditsThreshold}}, published)
+	})
+
+	t.Run("does not publish above the threshold", func(t *testing.T) {
+		assert.Empty(t, deduct(100, 10))
+	})
+
+	t.Run("does not publish again once already low", func(t *testing.T) {
+		assert.Empty(t, deduct(LowCreditsThreshold-1, 1))
+	})
+}
+
+func TestCreditsService_ReserveCredits(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("holds credits without recording a debit", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 10, UsedThisPeriod: 3}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 9 && c.UsedThisPeriod == 3
+		})).Return(nil)
+		holdRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+		hold, err := service.ReserveCredits(userID, 1, "msg-123", "Voice message")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReservationHeld, hold.Status)
+		assert.Equal(t, 1, hold.Amount)
+		assert.Equal(t, "msg-123", hold.Reference)
+		assert.WithinDuration(t, time.Now().Add(ReservationTTL), hold.ExpiresAt, time.Minute)
+		holdRepo.AssertCalled(t, "Create", mock.Anything, hold)
+		txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns ErrInsufficientCredits when balance too low", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 0}, nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, nil, holdRepo)
+		hold, err := service.ReserveCredits(userID, 1, "msg-123", "Voice message")
+
+		assert.ErrorIs(t, err, ErrInsufficientCredits)
+		assert.Nil(t, hold)
+		holdRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreditsService_CaptureReservation(t *testing.T) {
+	userID := uuid.New()
+	hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 2, Status: models.ReservationHeld, Reference: "msg-123", Description: "Voice message"}
+
+	t.Run("records the debit and period usage", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+		holdRepo.On("Settle", mock.Anything, hold.ID, models.ReservationCaptured, mock.Anything).Return(true, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 8, UsedThisPeriod: 3}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 8 && c.UsedThisPeriod == 5
+		})).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == -2 && tx.Type == models.TransactionDebit && tx.BalanceAfter == 8 &&
+				tx.Reference != nil && *tx.Reference == "msg-123" && tx.Description == "Voice message"
+		})).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+
+		assert.NoError(t, service.CaptureReservation(hold.ID))
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("returns ErrReservationSettled once settled", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+		holdRepo.On("Settle", mock.Anything, hold.ID, models.ReservationCaptured, mock.Anything).Return(false, nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, nil, holdRepo)
+
+		assert.ErrorIs(t, service.CaptureReservation(hold.ID), ErrReservationSettled)
+		creditsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreditsService_ReleaseReservation(t *testing.T) {
+	userID := uuid.New()
+	hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 2, Status: models.ReservationHeld, Reference: "msg-123"}
+
+	t.Run("gives the credits back without recording anything", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+		holdRepo.On("Settle", mock.Anything, hold.ID, models.ReservationReleased, mock.Anything).Return(true, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 8, UsedThisPeriod: 3}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 10 && c.UsedThisPeriod == 3
+		})).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+
+		assert.NoError(t, service.ReleaseReservation(hold.ID))
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not give back a captured reservation", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+		holdRepo.On("Settle", mock.Anything, hold.ID, models.ReservationReleased, mock.Anything).Return(false, nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, nil, holdRepo)
+
+		assert.ErrorIs(t, service.ReleaseReservation(hold.ID), ErrReservationSettled)
+		creditsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreditsService_ReduceReservation(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("gives back what the turn won't cost", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+		hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 10, Status: models.ReservationHeld}
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+		holdRepo.On("Reduce", mock.Anything, hold.ID, 10, 4).Return(true, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 5, UsedThisPeriod: 3}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 11 && c.UsedThisPeriod == 3
+		})).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+
+		assert.NoError(t, service.ReduceReservation(hold.ID, 4))
+		holdRepo.AssertExpectations(t)
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("leaves a hold already at the cost", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+		hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 4, Status: models.ReservationHeld}
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, nil, holdRepo)
+
+		assert.NoError(t, service.ReduceReservation(hold.ID, 4))
+		holdRepo.AssertNotCalled(t, "Reduce", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		creditsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not reduce a settled reservation", func(t *testing.T) {
+		// Settled before the read, or between the read and the update
+		for _, status := range []models.CreditReservationStatus{models.ReservationExpired, models.ReservationHeld} {
+			creditsRepo := new(mocks.MockCreditsRepository)
+			holdRepo := new(mocks.MockCreditReservationRepository)
+			txRunner := new(mockTxRunner)
+			hold := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 10, Status: status}
+
+			txRunner.On("Transaction", mock.Anything).Return(nil)
+			holdRepo.On("FindByID", mock.Anything, hold.ID).Return(hold, nil)
+			holdRepo.On("Reduce", mock.Anything, hold.ID, 10, 4).Return(false, nil)
+
+			service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, nil, holdRepo)
+
+			assert.ErrorIs(t, service.ReduceReservation(hold.ID, 4), ErrReservationSettled)
+			creditsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		}
+	})
+}
+
 func TestCreditsService_AddCredits(t *testing.T) {
 	userID := uuid.New()
 
@@ -239,7 +484,7 @@ func TestCreditsService_AddCredits(t *testing.T) {
 		}
 
 		txRunner.On("Transaction", mock.Anything).Return(nil)
-		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
 		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
 			return c.Balance == 100
 		})).Return(nil)
@@ -247,7 +492,7 @@ func TestCreditsService_AddCredits(t *testing.T) {
 			return tx.Amount == 50 && tx.Type == models.TransactionCredit
 		})).Return(nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		err := service.AddCredits(userID, 50, "Bonus credits")
 
 		assert.NoError(t, err)
@@ -257,6 +502,143 @@ func TestCreditsService_AddCredits(t *testing.T) {
 	})
 }
 
+func TestCreditsService_RefundCredits(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("restores balance and period usage", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		txRunner := new(mockTxRunner)
+
+		credits := &models.Credits{
+			UserID:         userID,
+			Balance:        9,
+			UsedThisPeriod: 11,
+		}
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 10 && c.UsedThisPeriod == 10
+		})).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == 1 && tx.Type == models.TransactionRefund &&
+				tx.Reference != nil && *tx.Reference == "msg-123"
+		})).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
+		err := service.RefundCredits(userID, 1, "msg-123", "Refund: transcription failed")
+
+		assert.NoError(t, err)
+		txRunner.AssertExpectations(t)
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("does not drive period usage negative after a refresh", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		txRunner := new(mockTxRunner)
+
+		credits := &models.Credits{
+			UserID:         userID,
+			Balance:        20,
+			UsedThisPeriod: 0,
+		}
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 21 && c.UsedThisPeriod == 0
+		})).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
+		err := service.RefundCredits(userID, 1, "msg-123", "Refund: transcription failed")
+
+		assert.NoError(t, err)
+		creditsRepo.AssertExpectations(t)
+	})
+}
+
+func TestCreditsService_RefundReservation(t *testing.T) {
+	userID := uuid.New()
+	messageID := uuid.New().String()
+
+	t.Run("refunds what a captured turn was charged", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		reservation := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 3, Reference: messageID, Status: models.ReservationCaptured}
+		credits := &models.Credits{UserID: userID, Balance: 7, UsedThisPeriod: 13}
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByReference", mock.Anything, messageID).Return(reservation, nil)
+		holdRepo.On("MarkRefunded", mock.Anything, reservation.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 10 && c.UsedThisPeriod == 10
+		})).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == 3 && tx.Type == models.TransactionRefund && tx.BalanceAfter == 10 &&
+				tx.Reference != nil && *tx.Reference == messageID
+		})).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+		refunded, err := service.RefundReservation(messageID, "Refund: low-confidence pronunciation score")
+
+		assert.NoError(t, err)
+		assert.True(t, refunded)
+		holdRepo.AssertExpectations(t)
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+	})
+
+	// MarkRefunded only moves a captured reservation, so a turn refunded
+	// already, released, expired or still held is left alone
+	t.Run("skips a reservation that is not captured", func(t *testing.T) {
+		for _, status := range []models.CreditReservationStatus{models.ReservationRefunded, models.ReservationReleased, models.ReservationExpired, models.ReservationHeld} {
+			creditsRepo := new(mocks.MockCreditsRepository)
+			txRepo := new(mocks.MockCreditTransactionRepository)
+			holdRepo := new(mocks.MockCreditReservationRepository)
+			txRunner := new(mockTxRunner)
+
+			reservation := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 3, Reference: messageID, Status: status}
+			txRunner.On("Transaction", mock.Anything).Return(nil)
+			holdRepo.On("FindByReference", mock.Anything, messageID).Return(reservation, nil)
+			holdRepo.On("MarkRefunded", mock.Anything, reservation.ID, mock.Anything).Return(false, nil)
+
+			service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+			refunded, err := service.RefundReservation(messageID, "Refund: low-confidence pronunciation score")
+
+			assert.NoError(t, err, status)
+			assert.False(t, refunded, status)
+			creditsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+			txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("skips a turn that was never reserved", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		holdRepo.On("FindByReference", mock.Anything, messageID).Return(nil, repository.ErrNotFound)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+		refunded, err := service.RefundReservation(messageID, "Refund: low-confidence pronunciation score")
+
+		assert.NoError(t, err)
+		assert.False(t, refunded)
+		holdRepo.AssertNotCalled(t, "MarkRefunded", mock.Anything, mock.Anything, mock.Anything)
+		txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
 func TestCreditsService_RefreshMonthlyCredits(t *testing.T) {
 	userID := uuid.New()
 
@@ -273,15 +655,17 @@ func TestCreditsService_RefreshMonthlyCredits(t *testing.T) {
 		}
 
 		txRunner.On("Transaction", mock.Anything).Return(nil)
-		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
 		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
 			return c.Balance == 100 && c.UsedThisPeriod == 0
 		})).Return(nil)
 		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
 			return tx.Amount == 80 && tx.Type == models.TransactionRefresh
 		})).Return(nil)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		holdRepo.On("SumHeld", mock.Anything, userID).Return(0, nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
 		err := service.RefreshMonthlyCredits(userID)
 
 		assert.NoError(t, err)
@@ -289,6 +673,34 @@ func TestCreditsService_RefreshMonthlyCredits(t *testing.T) {
 		creditsRepo.AssertExpectations(t)
 		txRepo.AssertExpectations(t)
 	})
+
+	t.Run("leaves out credits held by turns in flight", func(t *testing.T) {
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		holdRepo := new(mocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+
+		// 3 of the 20 left are held; releasing them later must not take the
+		// balance past the allowance
+		credits := &models.Credits{UserID: userID, Balance: 17, MonthlyAllowance: 100, UsedThisPeriod: 80}
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
+		holdRepo.On("SumHeld", mock.Anything, userID).Return(3, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 97 && c.UsedThisPeriod == 0
+		})).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == 80 && tx.BalanceAfter == 97
+		})).Return(nil)
+
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+		err := service.RefreshMonthlyCredits(userID)
+
+		assert.NoError(t, err)
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+	})
 }
 
 func TestCreditsService_InitializeCredits(t *testing.T) {
@@ -305,7 +717,7 @@ func TestCreditsService_InitializeCredits(t *testing.T) {
 				c.MonthlyAllowance == models.TierCredits[models.TierFree]
 		})).Return(nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		err := service.InitializeCredits(userID, models.TierFree)
 
 		assert.NoError(t, err)
@@ -321,7 +733,7 @@ func TestCreditsService_InitializeCredits(t *testing.T) {
 			return c.Balance == models.TierCredits[models.TierPro]
 		})).Return(nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		err := service.InitializeCredits(userID, models.TierPro)
 
 		assert.NoError(t, err)
@@ -339,7 +751,7 @@ func TestCreditsService_UpdateAllowance(t *testing.T) {
 
 		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierBasic]).Return(nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		err := service.UpdateAllowance(userID, models.TierBasic)
 
 		assert.NoError(t, err)
@@ -360,10 +772,10 @@ func TestCreditsService_GetTransactionHistory(t *testing.T) {
 			{UserID: userID, Amount: 100, Type: models.TransactionCredit},
 		}
 
-		txRepo.On("FindByUserID", mock.Anything, userID, 50).Return(expectedTxs, nil)
+		txRepo.On("FindByUserID", mock.Anything, userID, 50, 0).Return(expectedTxs, nil)
 
-		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
-		txs, err := service.GetTransactionHistory(userID, 50)
+		service := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
+		txs, err := service.GetTransactionHistory(userID, 50, 0)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTxs, txs)
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// DunningReminderDays are how many days after the first failed payment each
+// reminder goes out
+var DunningReminderDays = []int{3, 7}
+
+// dunningSweepBatchSize caps how many reminders are queued per sweep and step
+const dunningSweepBatchSize = 100
+
+// Payment banner states, from the first failed payment until the last
+// reminder and after it
+const (
+	PaymentBannerFailed      = "payment_failed"
+	PaymentBannerFinalNotice = "final_notice"
+)
+
+// PaymentBanner is what the app shows a user whose renewal payment failed
+type PaymentBanner struct {
+	State    string    `json:"state"`
+	FailedAt time.Time `json:"failedAt"`
+	// Billing portal page for updating the payment method; empty if Stripe
+	// couldn't create one, in which case the regular portal link still works
+	PortalURL string `json:"portalUrl,omitempty"`
+}
+
+// PaymentBannerFor returns the banner for a subscription, or nil when its
+// payments are in good standing
+func PaymentBannerFor(sub *models.Subscription) *PaymentBanner {
+	if sub.PaymentFailedAt == nil || sub.Status != "past_due" {
+		return nil
+	}
+	state := PaymentBannerFailed
+	if sub.DunningRemindersSent >= len(DunningReminderDays) {
+		state = PaymentBannerFinalNotice
+	}
+	return &PaymentBanner{State: state, FailedAt: *sub.PaymentFailedAt}
+}
+
+// DunningService tells users their renewal payment failed: a notification and
+// email straight away, then reminders DunningReminderDays after. Reminders are
+// found by sweeping the subscriptions table, so they survive restarts, and
+// stop once a payment goes through.
+type DunningService struct {
+	exec          repository.Executor
+	subRepo       repository.SubscriptionRepository
+	userRepo      repository.UserRepository
+	notifications NotificationManager
+	email         client.EmailClient // nil = in-app notifications only
+	queue         *jobs.Queue
+	interval      time.Duration
+	billingURL    string // where emails send users to update their card
+
+	now func() time.Time
+}
+
+// NewDunningService creates a new dunning service
+func NewDunningService(
+	database *db.DB,
+	subRepo repository.SubscriptionRepository,
+	userRepo repository.UserRepository,
+	notifications NotificationManager,
+	email client.EmailClient,
+	queue *jobs.Queue,
+	interval time.Duration,
+	frontendURL string,
+) *DunningService {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &DunningService{
+		exec:          database.DB,
+		subRepo:       subRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
+		email:         email,
+		queue:         queue,
+		interval:      interval,
+		billingURL:    frontendURL + "/settings",
+		now:           time.Now,
+	}
+}
+
+// NewDunningServiceForTest creates a DunningService with injected dependencies for testing.
+func NewDunningServiceForTest(
+	exec repository.Executor,
+	subRepo repository.SubscriptionRepository,
+	userRepo repository.UserRepository,
+	notifications NotificationManager,
+	email client.EmailClient,
+	queue *jobs.Queue,
+	now func() time.Time,
+) *DunningService {
+	return &DunningService{
+		exec:          exec,
+		subRepo:       subRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
+		email:         email,
+		queue:         queue,
+		interval:      time.Hour,
+		billingURL:    "http://localhost:3000/settings",
+		now:           now,
+	}
+}
+
+// PaymentFailed starts dunning for a subscription whose payment just failed.
+// Stripe retries the charge several times; only the first failure of a run
+// sends the notice.
+func (s *DunningService) PaymentFailed(sub *models.Subscription) error {
+	started, err := s.subRepo.StartDunning(s.exec, sub.ID, s.now())
+	if err != nil {
+		return fmt.Errorf("start dunning: %w", err)
+	}
+	if !started {
+		return nil
+	}
+
+	log.Printf("[Dunning] Payment failed for user %s", sub.UserID)
+	s.send(sub, 0)
+	return nil
+}
+
+// PaymentSucceeded ends dunning once a payment goes through
+func (s *DunningService) PaymentSucceeded(sub *models.Subscription) error {
+	if sub.PaymentFailedAt == nil {
+		return nil
+	}
+	if err := s.subRepo.EndDunning(s.exec, sub.ID); err != nil {
+		return fmt.Errorf("end dunning: %w", err)
+	}
+	log.Printf("[Dunning] Payment recovered for user %s", sub.UserID)
+	return nil
+}
+
+// Start sweeps for due reminders until ctx is cancelled
+func (s *DunningService) Start(ctx context.Context) {
+	log.Printf("[Dunning] Checking for due payment reminders every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Sweep()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep queues a job for every reminder that is due
+func (s *DunningService) Sweep() {
+	now := s.now()
+	for sent, days := range DunningReminderDays {
+		subs, err := s.subRepo.FindDunningDue(s.exec, sent, now.AddDate(0, 0, -days), dunningSweepBatchSize)
+		if err != nil {
+			log.Printf("[Dunning] Failed to find due reminders: %v", err)
+			return
+		}
+
+		for _, sub := range subs {
+			if s.queue == nil {
+				if err := s.Remind(&sub); err != nil {
+					log.Printf("[Dunning] Failed to remind user %s: %v", sub.UserID, err)
+				}
+				continue
+			}
+
+			err := s.queue.Enqueue(jobs.Job{
+				Name: "dunning:" + sub.ID.String(),
+				Lane: jobs.LaneStandard,
+				Run: func(ctx context.Context) error {
+					return s.Remind(&sub)
+				},
+			})
+			if err != nil {
+				log.Printf("[Dunning] Failed to enqueue reminder for user %s: %v", sub.UserID, err)
+			}
+		}
+	}
+}
+
+// Remind sends the subscription's next reminder. It is a no-op if that
+// reminder was already sent, e.g. by an earlier sweep.
+func (s *DunningService) Remind(sub *models.Subscription) error {
+	advanced, err := s.subRepo.AdvanceDunning(s.exec, sub.ID, sub.DunningRemindersSent)
+	if err != nil {
+		return fmt.Errorf("advance dunning: %w", err)
+	}
+	if !advanced {
+		return nil
+	}
+
+	s.send(sub, sub.DunningRemindersSent+1)
+	return nil
+}
+
+// send notifies the user in the app and by email. step 0 is the first
+// notice, then one per reminder.
+func (s *DunningService) send(sub *models.Subscription, step int) {
+	plan := string(sub.Tier)
+	var title, body string
+	switch {
+	case step == 0:
+		title = "Your payment didn't go through"
+		body = fmt.Sprintf("We couldn't charge your card for your %s plan. "+
+			"Update your payment method to keep your plan and credits.", plan)
+	case step < len(DunningReminderDays):
+		title = "Reminder: update your payment method"
+		body = fmt.Sprintf("Your %s plan payment is still outstanding. "+
+			"Update your payment method to keep your plan and credits.", plan)
+	default:
+		title = "Final reminder: your plan is at risk"
+		body = fmt.Sprintf("We still couldn't charge your card for your %s plan. "+
+			"If the payment keeps failing, your plan will be cancelled and you'll move to the free plan.", plan)
+	}
+
+	if s.notifications != nil {
+		err := s.notifications.Notify(sub.UserID, models.NotificationPaymentFailed, title, body, models.JSONMap{
+			"reminder": step,
+		})
+		if err != nil {
+			log.Printf("[Dunning] Failed to notify user %s: %v", sub.UserID, err)
+		}
+	}
+
+	if s.email == nil {
+		return
+	}
+	user, err := s.userRepo.FindByID(s.exec, sub.UserID)
+	if err != nil {
+		log.Printf("[Dunning] Failed to find user %s for email: %v", sub.UserID, err)
+		return
+	}
+	greeting := "Hi"
+	if user.Name != "" {
+		greeting += " " + user.Name
+	}
+	text := fmt.Sprintf("%s,\n\n%s\n\nUpdate your payment method: %s\n", greeting, body, s.billingURL)
+	if err := s.email.Send(context.Background(), user.Email, title, text); err != nil {
+		log.Printf("[Dunning] Failed to email user %s: %v", sub.UserID, err)
+	}
+}
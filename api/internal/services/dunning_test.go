@@ -0,0 +1,195 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+type dunningTestDeps struct {
+	subRepo       *repomocks.MockSubscriptionRepository
+	userRepo      *repomocks.MockUserRepository
+	notifications *stubNotifications
+	email         *clientmocks.MockEmailClient
+}
+
+func newDunningServiceWithMocks(now time.Time) (*DunningService, *dunningTestDeps) {
+	deps := &dunningTestDeps{
+		subRepo:       new(repomocks.MockSubscriptionRepository),
+		userRepo:      new(repomocks.MockUserRepository),
+		notifications: new(stubNotifications),
+		email:         new(clientmocks.MockEmailClient),
+	}
+	service := NewDunningServiceForTest(nil, deps.subRepo, deps.userRepo, deps.notifications, deps.email, nil,
+		func() time.Time { return now })
+	return service, deps
+}
+
+func TestDunningService_PaymentFailed(t *testing.T) {
+	now := time.Now()
+	user := &models.User{ID: uuid.New(), Email: "learner@example.com", Name: "Ana"}
+	sub := &models.Subscription{ID: uuid.New(), UserID: user.ID, Tier: models.TierPro, Status: "past_due"}
+
+	t.Run("notifies and emails on the first failure", func(t *testing.T) {
+		service, deps := newDunningServiceWithMocks(now)
+		deps.subRepo.On("StartDunning", mock.Anything, sub.ID, now).Return(true, nil)
+		deps.notifications.On("Notify", user.ID, models.NotificationPaymentFailed, "Your payment didn't go through",
+			mock.Anything, models.JSONMap{"reminder": 0}).Return(nil)
+		deps.userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+		deps.email.On("Send", mock.Anything, user.Email, "Your payment didn't go through",
+			mock.MatchedBy(func(body string) bool {
+				return assert.Contains(t, body, "Hi Ana,") && assert.Contains(t, body, "http://localhost:3000/settings")
+			})).Return(nil)
+
+		err := service.PaymentFailed(sub)
+
+		assert.NoError(t, err)
+		deps.notifications.AssertExpectations(t)
+		deps.email.AssertExpectations(t)
+	})
+
+	t.Run("skips retries of the same failed payment", func(t *testing.T) {
+		service, deps := newDunningServiceWithMocks(now)
+		deps.subRepo.On("StartDunning", mock.Anything, sub.ID, now).Return(false, nil)
+
+		err := service.PaymentFailed(sub)
+
+		assert.NoError(t, err)
+		deps.notifications.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		deps.email.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("notifies in the app only without an email client", func(t *testing.T) {
+		subRepo := new(repomocks.MockSubscriptionRepository)
+		notifications := new(stubNotifications)
+		subRepo.On("StartDunning", mock.Anything, sub.ID, now).Return(true, nil)
+		notifications.On("Notify", user.ID, models.NotificationPaymentFailed, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		service := NewDunningServiceForTest(nil, subRepo, nil, notifications, nil, nil, func() time.Time { return now })
+		err := service.PaymentFailed(sub)
+
+		assert.NoError(t, err)
+		notifications.AssertExpectations(t)
+	})
+}
+
+func TestDunningService_Remind(t *testing.T) {
+	now := time.Now()
+	failedAt := now.AddDate(0, 0, -7)
+	user := &models.User{ID: uuid.New(), Email: "learner@example.com"}
+
+	t.Run("sends the final notice after the last reminder", func(t *testing.T) {
+		sub := &models.Subscription{ID: uuid.New(), UserID: user.ID, Tier: models.TierPro, Status: "past_due",
+			PaymentFailedAt: &failedAt, DunningRemindersSent: 1}
+
+		service, deps := newDunningServiceWithMocks(now)
+		deps.subRepo.On("AdvanceDunning", mock.Anything, sub.ID, 1).Return(true, nil)
+		deps.notifications.On("Notify", user.ID, models.NotificationPaymentFailed, "Final reminder: your plan is at risk",
+			mock.Anything, models.JSONMap{"reminder": 2}).Return(nil)
+		deps.userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+		deps.email.On("Send", mock.Anything, user.Email, "Final reminder: your plan is at risk",
+			mock.MatchedBy(func(body string) bool { return assert.Contains(t, body, "Hi,\n") })).Return(nil)
+
+		err := service.Remind(sub)
+
+		assert.NoError(t, err)
+		deps.notifications.AssertExpectations(t)
+		deps.email.AssertExpectations(t)
+	})
+
+	t.Run("skips a reminder that was already sent", func(t *testing.T) {
+		sub := &models.Subscription{ID: uuid.New(), UserID: user.ID, PaymentFailedAt: &failedAt}
+
+		service, deps := newDunningServiceWithMocks(now)
+		deps.subRepo.On("AdvanceDunning", mock.Anything, sub.ID, 0).Return(false, nil)
+
+		err := service.Remind(sub)
+
+		assert.NoError(t, err)
+		deps.notifications.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDunningService_Sweep(t *testing.T) {
+	now := time.Now()
+	first := models.Subscription{ID: uuid.New(), UserID: uuid.New(), DunningRemindersSent: 0}
+	second := models.Subscription{ID: uuid.New(), UserID: uuid.New(), DunningRemindersSent: 1}
+
+	subRepo := new(repomocks.MockSubscriptionRepository)
+	notifications := new(stubNotifications)
+	subRepo.On("FindDunningDue", mock.Anything, 0, now.AddDate(0, 0, -3), dunningSweepBatchSize).
+		Return([]models.Subscription{first}, nil)
+	subRepo.On("FindDunningDue", mock.Anything, 1, now.AddDate(0, 0, -7), dunningSweepBatchSize).
+		Return([]models.Subscription{second}, nil)
+	subRepo.On("AdvanceDunning", mock.Anything, first.ID, 0).Return(true, nil)
+	subRepo.On("AdvanceDunning", mock.Anything, second.ID, 1).Return(true, nil)
+	notifications.On("Notify", first.UserID, models.NotificationPaymentFailed, "Reminder: update your payment method",
+		mock.Anything, models.JSONMap{"reminder": 1}).Return(nil)
+	notifications.On("Notify", second.UserID, models.NotificationPaymentFailed, "Final reminder: your plan is at risk",
+		mock.Anything, models.JSONMap{"reminder": 2}).Return(nil)
+
+	// Without a queue, reminders are sent inline
+	service := NewDunningServiceForTest(nil, subRepo, nil, notifications, nil, nil, func() time.Time { return now })
+	service.Sweep()
+
+	subRepo.AssertExpectations(t)
+	notifications.AssertExpectations(t)
+}
+
+func TestDunningService_PaymentSucceeded(t *testing.T) {
+	failedAt := time.Now()
+
+	t.Run("ends dunning", func(t *testing.T) {
+		sub := &models.Subscription{ID: uuid.New(), UserID: uuid.New(), PaymentFailedAt: &failedAt}
+		service, deps := newDunningServiceWithMocks(time.Now())
+		deps.subRepo.On("EndDunning", mock.Anything, sub.ID).Return(nil)
+
+		assert.NoError(t, service.PaymentSucceeded(sub))
+		deps.subRepo.AssertExpectations(t)
+	})
+
+	t.Run("does nothing for payments in good standing", func(t *testing.T) {
+		sub := &models.Subscription{ID: uuid.New(), UserID: uuid.New()}
+		service, deps := newDunningServiceWithMocks(time.Now())
+
+		assert.NoError(t, service.PaymentSucceeded(sub))
+		deps.subRepo.AssertNotCalled(t, "EndDunning", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPaymentBannerFor(t *testing.T) {
+	failedAt := time.Now()
+
+	tests := []struct {
+		name string
+		sub  models.Subscription
+		want string
+	}{
+		{"good standing", models.Subscription{Status: "active"}, ""},
+		{"recovered but not yet ended", models.Subscription{Status: "active", PaymentFailedAt: &failedAt}, ""},
+		{"failed", models.Subscription{Status: "past_due", PaymentFailedAt: &failedAt}, PaymentBannerFailed},
+		{"reminded", models.Subscription{Status: "past_due", PaymentFailedAt: &failedAt, DunningRemindersSent: 1}, PaymentBannerFailed},
+		{"final notice", models.Subscription{Status: "past_due", PaymentFailedAt: &failedAt, DunningRemindersSent: 2}, PaymentBannerFinalNotice},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			banner := PaymentBannerFor(&tt.sub)
+			if tt.want == "" {
+				assert.Nil(t, banner)
+				return
+			}
+			if assert.NotNil(t, banner) {
+				assert.Equal(t, tt.want, banner.State)
+				assert.Equal(t, failedAt, banner.FailedAt)
+			}
+		})
+	}
+}
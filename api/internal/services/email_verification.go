@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MaxVerificationEmailsPerHour caps the verification emails one account can
+// ask for, so resending can't be used to flood an inbox
+const MaxVerificationEmailsPerHour = 3
+
+var (
+	ErrEmailDisabled             = errors.New("email is not configured")
+	ErrEmailAlreadyVerified      = errors.New("email already verified")
+	ErrTooManyVerificationEmails = errors.New("too many verification emails")
+	ErrInvalidVerificationToken  = errors.New("verification link is invalid or has expired")
+)
+
+// EmailVerifier defines the interface for email verification
+type EmailVerifier interface {
+	SendVerification(user *models.User) error
+	Verify(token string) (*models.User, error)
+}
+
+// EmailVerificationService confirms that password accounts own their email
+// address by emailing them a single-use link. OAuth accounts are verified by
+// their provider.
+type EmailVerificationService struct {
+	exec      repository.Executor
+	repo      repository.EmailVerificationRepository
+	userRepo  repository.UserRepository
+	email     client.EmailClient // nil = email disabled
+	queue     *jobs.Queue
+	verifyURL string
+
+	// Required keeps unverified accounts out of the features that reach
+	// other people, e.g. sharing threads
+	Required bool
+
+	now func() time.Time
+}
+
+// NewEmailVerificationService creates a new email verification service
+func NewEmailVerificationService(
+	database *db.DB,
+	repo repository.EmailVerificationRepository,
+	userRepo repository.UserRepository,
+	email client.EmailClient,
+	queue *jobs.Queue,
+	verifyURL string,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		exec:      database.DB,
+		repo:      repo,
+		userRepo:  userRepo,
+		email:     email,
+		queue:     queue,
+		verifyURL: verifyURL,
+		now:       time.Now,
+	}
+}
+
+// NewEmailVerificationServiceForTest creates an EmailVerificationService with injected dependencies for testing.
+func NewEmailVerificationServiceForTest(
+	exec repository.Executor,
+	repo repository.EmailVerificationRepository,
+	userRepo repository.UserRepository,
+	email client.EmailClient,
+	now func() time.Time,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		exec:      exec,
+		repo:      repo,
+		userRepo:  userRepo,
+		email:     email,
+		verifyURL: "http://localhost:8080/api/auth/verify",
+		now:       now,
+	}
+}
+
+// SendVerification emails the user a link that verifies their current
+// address. Earlier links keep working until they expire. The email goes out
+// from the job queue when there is one.
+func (s *EmailVerificationService) SendVerification(user *models.User) error {
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+	if s.email == nil {
+		return ErrEmailDisabled
+	}
+
+	now := s.now()
+	sent, err := s.repo.CountSince(s.exec, user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("count verification emails: %w", err)
+	}
+	if sent >= MaxVerificationEmailsPerHour {
+		return ErrTooManyVerificationEmails
+	}
+
+	token, err := newVerificationToken()
+	if err != nil {
+		return err
+	}
+	verification := &models.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashVerificationToken(token),
+		ExpiresAt: now.Add(models.EmailVerificationLifetime),
+	}
+	if err := s.repo.Create(s.exec, verification); err != nil {
+		return fmt.Errorf("create verification: %w", err)
+	}
+
+	send := func(ctx context.Context) error {
+		return s.send(ctx, user, token)
+	}
+	if s.queue == nil {
+		return send(context.Background())
+	}
+	err = s.queue.Enqueue(jobs.Job{
+		Name: "email_verification:" + user.ID.String(),
+		Lane: jobs.LanePriority,
+		Run:  send,
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue verification email: %w", err)
+	}
+	return nil
+}
+
+func (s *EmailVerificationService) send(ctx context.Context, user *models.User, token string) error {
+	greeting := "Hi"
+	if user.Name != "" {
+		greeting += " " + user.Name
+	}
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("%s,\n\nConfirm your email address by opening this link within %d hours:\n\n%s\n\n"+
+		"If you didn't create an account, you can ignore this email.\n",
+		greeting, int(models.EmailVerificationLifetime.Hours()), link)
+
+	return s.email.Send(ctx, user.Email, "Confirm your email address", body)
+}
+
+// Verify uses a verification link's token and marks its address verified.
+// Each link works once, and only while the account still has the address it
+// was sent to.
+func (s *EmailVerificationService) Verify(token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+	now := s.now()
+
+	verification, err := s.repo.FindByTokenHash(s.exec, hashVerificationToken(token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find verification: %w", err)
+	}
+	if !verification.Usable(now) {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	used, err := s.repo.Use(s.exec, verification.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("use verification: %w", err)
+	}
+	if !used {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	verified, err := s.userRepo.MarkEmailVerified(s.exec, verification.UserID, verification.Email)
+	if err != nil {
+		return nil, fmt.Errorf("mark email verified: %w", err)
+	}
+	if !verified {
+		return nil, ErrInvalidVerificationToken
+	}
+	log.Printf("[EmailVerification] Verified email of user %s", verification.UserID)
+
+	return s.userRepo.FindByID(s.exec, verification.UserID)
+}
+
+func newVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate verification token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashVerificationToken is what is stored for a token, so a database leak
+// doesn't hand out working links
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+type verificationTestDeps struct {
+	repo     *repomocks.MockEmailVerificationRepository
+	userRepo *repomocks.MockUserRepository
+	email    *clientmocks.MockEmailClient
+}
+
+func newVerificationServiceWithMocks(now time.Time) (*EmailVerificationService, *verificationTestDeps) {
+	deps := &verificationTestDeps{
+		repo:     new(repomocks.MockEmailVerificationRepository),
+		userRepo: new(repomocks.MockUserRepository),
+		email:    new(clientmocks.MockEmailClient),
+	}
+	service := NewEmailVerificationServiceForTest(nil, deps.repo, deps.userRepo, deps.email, func() time.Time { return now })
+	return service, deps
+}
+
+var verifyLinkPattern = regexp.MustCompile(`http://localhost:8080/api/auth/verify\?token=(\S+)`)
+
+func TestEmailVerificationService_SendVerification(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Email: "ana@example.com", Name: "Ana"}
+
+	t.Run("emails a link and stores only the token's hash", func(t *testing.T) {
+		service, deps := newVerificationServiceWithMocks(now)
+		var stored *models.EmailVerification
+		var body string
+		deps.repo.On("CountSince", mock.Anything, user.ID, now.Add(-time.Hour)).Return(int64(0), nil)
+		deps.repo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.EmailVerification)
+		}).Return(nil)
+		deps.email.On("Send", mock.Anything, "ana@example.com", "Confirm your email address", mock.Anything).Run(func(args mock.Arguments) {
+			body = args.String(3)
+		}).Return(nil)
+
+		require.NoError(t, service.SendVerification(user))
+
+		match := verifyLinkPattern.FindStringSubmatch(body)
+		require.Len(t, match, 2)
+		token, err := url.QueryUnescape(match[1])
+		require.NoError(t, err)
+		assert.Equal(t, hashVerificationToken(token), stored.TokenHash)
+		assert.NotContains(t, stored.TokenHash, token)
+		assert.Equal(t, "ana@example.com", stored.Email)
+		assert.Equal(t, now.Add(models.EmailVerificationLifetime), stored.ExpiresAt)
+	})
+
+	t.Run("already verified", func(t *testing.T) {
+		service, deps := newVerificationServiceWithMocks(now)
+
+		err := service.SendVerification(&models.User{ID: uuid.New(), EmailVerified: true})
+
+		assert.ErrorIs(t, err, ErrEmailAlreadyVerified)
+		deps.email.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too many this hour", func(t *testing.T) {
+		service, deps := newVerificationServiceWithMocks(now)
+		deps.repo.On("CountSince", mock.Anything, user.ID, now.Add(-time.Hour)).Return(int64(MaxVerificationEmailsPerHour), nil)
+
+		assert.ErrorIs(t, service.SendVerification(user), ErrTooManyVerificationEmails)
+		deps.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("email not configured", func(t *testing.T) {
+		service := NewEmailVerificationServiceForTest(nil, nil, nil, nil, time.Now)
+
+		assert.ErrorIs(t, service.SendVerification(user), ErrEmailDisabled)
+	})
+}
+
+func TestEmailVerificationService_Verify(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	token := "tok-abc"
+	hash := hashVerificationToken(token)
+	usable := func() *models.EmailVerification {
+		return &models.EmailVerification{ID: uuid.New(), UserID: userID, Email: "ana@example.com", TokenHash: hash, ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("verifies the address", func(t *testing.T) {
+		service, deps := newVerificationServiceWithMocks(now)
+		verification := usable()
+		deps.repo.On("FindByTokenHash", mock.Anything, hash).Return(verification, nil)
+		deps.repo.On("Use", mock.Anything, verification.ID, now).Return(true, nil)
+		deps.userRepo.On("MarkEmailVerified", mock.Anything, userID, "ana@example.com").Return(true, nil)
+		deps.userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, EmailVerified: true}, nil)
+
+		user, err := service.Verify(token)
+		require.NoError(t, err)
+		assert.True(t, user.EmailVerified)
+	})
+
+	tests := []struct {
+		name  string
+		setup func(*verificationTestDeps)
+	}{
+		{
+			name: "unknown token",
+			setup: func(d *verificationTestDeps) {
+				d.repo.On("FindByTokenHash", mock.Anything, hash).Return(nil, repository.ErrNotFound)
+			},
+		},
+		{
+			name: "expired",
+			setup: func(d *verificationTestDeps) {
+				v := usable()
+				v.ExpiresAt = now.Add(-time.Minute)
+				d.repo.On("FindByTokenHash", mock.Anything, hash).Return(v, nil)
+			},
+		},
+		{
+			name: "used in the meantime",
+			setup: func(d *verificationTestDeps) {
+				v := usable()
+				d.repo.On("FindByTokenHash", mock.Anything, hash).Return(v, nil)
+				d.repo.On("Use", mock.Anything, v.ID, now).Return(false, nil)
+			},
+		},
+		{
+			name: "address changed since the link was sent",
+			setup: func(d *verificationTestDeps) {
+				v := usable()
+				d.repo.On("FindByTokenHash", mock.Anything, hash).Return(v, nil)
+				d.repo.On("Use", mock.Anything, v.ID, now).Return(true, nil)
+				d.userRepo.On("MarkEmailVerified", mock.Anything, userID, "ana@example.com").Return(false, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, deps := newVerificationServiceWithMocks(now)
+			tt.setup(deps)
+
+			_, err := service.Verify(token)
+
+			assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package services
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Validation errors
 var (
@@ -8,3 +11,72 @@ var (
 	ErrAudioTooLong  = errors.New("audio too long")
 	ErrAudioInvalid  = errors.New("audio invalid")
 )
+
+// Long-form message errors
+var (
+	ErrNoAudioChunks      = errors.New("no audio chunks")
+	ErrTooManyAudioChunks = errors.New("too many audio chunks")
+	ErrAudioFileTooLarge  = errors.New("audio file too large")
+)
+
+// Text message errors
+var ErrInvalidTextMessage = errors.New("invalid text message")
+
+// Transcript correction errors
+var (
+	ErrInvalidTranscript     = errors.New("invalid transcript")
+	ErrTranscriptNotEditable = errors.New("transcript cannot be corrected")
+	ErrAnalysisPending       = errors.New("pronunciation analysis is still running")
+)
+
+// Voice turn stages, in the order they run. A failure in one of them comes
+// back as a VoiceTurnError, except for tts: a reply that can't be spoken is
+// saved without audio.
+const (
+	StageUpload     = "upload"     // storing the recording
+	StagePresign    = "presign"    // handing the recording to the transcriber
+	StageTranscribe = "transcribe" // speech to text
+	StageGenerate   = "generate"   // writing the reply
+	StageTTS        = "tts"        // speaking the reply
+	StagePersist    = "persist"    // saving the messages
+)
+
+// VoiceTurnError reports the stage a voice turn failed at
+type VoiceTurnError struct {
+	Stage string // one of the Stage constants
+	Err   error
+}
+
+func (e *VoiceTurnError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.Stage, e.Err)
+}
+
+func (e *VoiceTurnError) Unwrap() error {
+	return e.Err
+}
+
+// TurnStage returns the stage a voice turn failed at, or "" when err doesn't
+// come from one
+func TurnStage(err error) string {
+	var turnErr *VoiceTurnError
+	if errors.As(err, &turnErr) {
+		return turnErr.Stage
+	}
+	return ""
+}
+
+// AudioDurationError reports a recording outside the allowed duration.
+// It matches ErrAudioTooShort or ErrAudioTooLong.
+type AudioDurationError struct {
+	Err     error   // ErrAudioTooShort or ErrAudioTooLong
+	Seconds float64 // recording length
+	Limit   float64 // the minimum or maximum it broke
+}
+
+func (e *AudioDurationError) Error() string {
+	return fmt.Sprintf("%v: %.1fs (limit %gs)", e.Err, e.Seconds, e.Limit)
+}
+
+func (e *AudioDurationError) Unwrap() error {
+	return e.Err
+}
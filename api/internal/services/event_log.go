@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// EventLogSubscriber is the event log's name on the bus. Events can't be
+// replayed into it.
+const EventLogSubscriber = "event_log"
+
+// eventReplayPageSize is how many logged events a replay loads at a time
+const eventReplayPageSize = 500
+
+// maxEventReplayErrors caps the errors listed in a replay report
+const maxEventReplayErrors = 50
+
+var (
+	ErrUnknownEventConsumer = errors.New("no subscriber with that name")
+	ErrInvalidEventReplay   = errors.New("replay range must end after it starts")
+)
+
+// EventReplayOptions selects the logged events to replay and where to
+type EventReplayOptions struct {
+	// Consumer is the bus subscriber the events are delivered to
+	Consumer string `json:"consumer"`
+	// UserID limits the replay to one user's events; uuid.Nil replays everyone's
+	UserID uuid.UUID `json:"userId,omitempty"`
+	// Names limits the replay to these event types; empty replays all
+	Names []string `json:"names,omitempty"`
+	// From and To bound when the events occurred, [From, To); zero is unbounded
+	From time.Time `json:"from,omitempty"`
+	To   time.Time `json:"to,omitempty"`
+	// DryRun counts the events that would be replayed without delivering them
+	DryRun bool `json:"dryRun"`
+}
+
+// EventReplayReport is what a replay delivered
+type EventReplayReport struct {
+	Consumer string   `json:"consumer"`
+	DryRun   bool     `json:"dryRun"`
+	Matched  int      `json:"matched"`  // Logged events in the selection
+	Replayed int      `json:"replayed"` // Delivered to the consumer without error
+	Skipped  int      `json:"skipped"`  // Types the consumer doesn't subscribe to
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors"`
+}
+
+// EventLogService stores every published domain event, so a projection added
+// later can be backfilled by replaying history into its subscriber
+type EventLogService struct {
+	exec repository.Executor
+	repo repository.DomainEventRepository
+
+	now func() time.Time
+}
+
+// NewEventLogService creates a new event log service
+func NewEventLogService(database *db.DB, repo repository.DomainEventRepository) *EventLogService {
+	return &EventLogService{
+		exec: database.DB,
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// NewEventLogServiceForTest creates an EventLogService with injected dependencies for testing.
+func NewEventLogServiceForTest(exec repository.Executor, repo repository.DomainEventRepository) *EventLogService {
+	return &EventLogService{
+		exec: exec,
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Subscribe logs every event published on bus
+func (s *EventLogService) Subscribe(bus *events.Bus) {
+	events.SubscribeAll(bus, EventLogSubscriber, s.Append)
+}
+
+// Append stores one event
+func (s *EventLogService) Append(ctx context.Context, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	var payload models.JSONMap
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	return s.repo.Create(s.exec, &models.DomainEvent{
+		Name:       event.EventName(),
+		UserID:     event.EventUser(),
+		Payload:    payload,
+		OccurredAt: s.now(),
+	})
+}
+
+// Replay delivers the selected logged events, oldest first, to the subscriber
+// of bus named opts.Consumer. Only that subscriber sees them, so replaying
+// into one projection doesn't repeat notifications or webhooks. The consumer
+// gets the events again on top of what it already holds: projections that
+// aren't idempotent should be replayed into from empty.
+func (s *EventLogService) Replay(ctx context.Context, bus *events.Bus, opts EventReplayOptions) (*EventReplayReport, error) {
+	if opts.Consumer == EventLogSubscriber || !bus.HasSubscriber(opts.Consumer) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEventConsumer, opts.Consumer)
+	}
+	if !opts.From.IsZero() && !opts.To.IsZero() && !opts.To.After(opts.From) {
+		return nil, ErrInvalidEventReplay
+	}
+
+	report := &EventReplayReport{Consumer: opts.Consumer, DryRun: opts.DryRun, Errors: []string{}}
+	filter := repository.DomainEventFilter{UserID: opts.UserID, Names: opts.Names, From: opts.From, To: opts.To}
+	var after *models.DomainEvent
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		page, err := s.repo.FindPage(s.exec, filter, after, eventReplayPageSize)
+		if err != nil {
+			return report, fmt.Errorf("load events: %w", err)
+		}
+		for i := range page {
+			report.Matched++
+			if !opts.DryRun {
+				s.replayOne(ctx, bus, opts.Consumer, &page[i], report)
+			}
+		}
+		if len(page) < eventReplayPageSize {
+			return report, nil
+		}
+		after = &page[len(page)-1]
+	}
+}
+
+// replayOne decodes and delivers one logged event, counting the outcome
+func (s *EventLogService) replayOne(ctx context.Context, bus *events.Bus, consumer string, logged *models.DomainEvent, report *EventReplayReport) {
+	fail := func(err error) {
+		report.Failed++
+		if len(report.Errors) < maxEventReplayErrors {
+			report.Errors = append(report.Errors, fmt.Sprintf("event %s (%s): %v", logged.ID, logged.Name, err))
+		}
+	}
+
+	data, err := json.Marshal(logged.Payload)
+	if err != nil {
+		fail(err)
+		return
+	}
+	event, err := events.Decode(logged.Name, data)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	handled, err := bus.Replay(ctx, consumer, event)
+	switch {
+	case err != nil:
+		fail(err)
+	case !handled:
+		report.Skipped++
+	default:
+		report.Replayed++
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestEventLogService_Append(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(repomocks.MockDomainEventRepository)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.DomainEvent) bool {
+		return e.Name == events.NameCreditsLow && e.UserID == userID && e.OccurredAt.Equal(now) &&
+			e.Payload["balance"] == float64(3) && e.Payload["userId"] == userID.String()
+	})).Return(nil)
+
+	service := NewEventLogServiceForTest(nil, repo)
+	service.now = func() time.Time { return now }
+	bus := events.NewBus()
+	service.Subscribe(bus)
+	bus.Publish(context.Background(), events.CreditsLow{UserID: userID, Balance: 3, Threshold: 5})
+
+	repo.AssertExpectations(t)
+}
+
+func TestEventLogService_Replay(t *testing.T) {
+	userID := uuid.New()
+	logged := func(name string, payload models.JSONMap) models.DomainEvent {
+		return models.DomainEvent{ID: uuid.New(), Name: name, UserID: userID, Payload: payload}
+	}
+	page := []models.DomainEvent{
+		logged(events.NameCreditsLow, models.JSONMap{"userId": userID.String(), "balance": 4, "threshold": 5}),
+		logged(events.NameMessageProcessed, models.JSONMap{"userId": userID.String()}),
+		logged(events.NameCreditsLow, models.JSONMap{"userId": userID.String(), "balance": 1, "threshold": 5}),
+		logged("message.deleted", models.JSONMap{}),
+	}
+
+	newBus := func(received *[]events.CreditsLow) *events.Bus {
+		bus := events.NewBus()
+		events.Subscribe(bus, "projection", func(_ context.Context, e events.CreditsLow) error {
+			*received = append(*received, e)
+			return nil
+		})
+		events.Subscribe(bus, "notifications", func(_ context.Context, e events.CreditsLow) error {
+			t.Error("replay reached another subscriber")
+			return nil
+		})
+		return bus
+	}
+
+	t.Run("delivers matching events to the consumer only", func(t *testing.T) {
+		repo := new(repomocks.MockDomainEventRepository)
+		filter := repository.DomainEventFilter{UserID: userID}
+		repo.On("FindPage", mock.Anything, filter, (*models.DomainEvent)(nil), eventReplayPageSize).Return(page, nil)
+
+		var received []events.CreditsLow
+		report, err := NewEventLogServiceForTest(nil, repo).Replay(context.Background(), newBus(&received), EventReplayOptions{
+			Consumer: "projection",
+			UserID:   userID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, report.Matched)
+		assert.Equal(t, 2, report.Replayed)
+		assert.Equal(t, 1, report.Skipped, "projection doesn't subscribe to message.processed")
+		assert.Equal(t, 1, report.Failed, "unknown event types fail")
+		assert.Len(t, report.Errors, 1)
+		require.Len(t, received, 2)
+		assert.Equal(t, events.CreditsLow{UserID: userID, Balance: 4, Threshold: 5}, received[0])
+		assert.Equal(t, 1, received[1].Balance)
+	})
+
+	t.Run("dry run only counts", func(t *testing.T) {
+		repo := new(repomocks.MockDomainEventRepository)
+		repo.On("FindPage", mock.Anything, mock.Anything, mock.Anything, eventReplayPageSize).Return(page, nil)
+
+		var received []events.CreditsLow
+		report, err := NewEventLogServiceForTest(nil, repo).Replay(context.Background(), newBus(&received), EventReplayOptions{
+			Consumer: "projection",
+			DryRun:   true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, report.Matched)
+		assert.Zero(t, report.Replayed)
+		assert.Empty(t, received)
+	})
+
+	t.Run("pages through the log", func(t *testing.T) {
+		repo := new(repomocks.MockDomainEventRepository)
+		full := make([]models.DomainEvent, eventReplayPageSize)
+		for i := range full {
+			full[i] = logged(events.NameCreditsLow, models.JSONMap{"userId": userID.String()})
+		}
+		repo.On("FindPage", mock.Anything, mock.Anything, (*models.DomainEvent)(nil), eventReplayPageSize).Return(full, nil)
+		repo.On("FindPage", mock.Anything, mock.Anything, &full[len(full)-1], eventReplayPageSize).Return(page[:1], nil)
+
+		var received []events.CreditsLow
+		report, err := NewEventLogServiceForTest(nil, repo).Replay(context.Background(), newBus(&received), EventReplayOptions{Consumer: "projection"})
+
+		require.NoError(t, err)
+		assert.Equal(t, eventReplayPageSize+1, report.Replayed)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown consumers and the log itself", func(t *testing.T) {
+		var received []events.CreditsLow
+		bus := newBus(&received)
+		NewEventLogServiceForTest(nil, nil).Subscribe(bus)
+
+		for _, consumer := range []string{"missing", EventLogSubscriber} {
+			_, err := NewEventLogServiceForTest(nil, nil).Replay(context.Background(), bus, EventReplayOptions{Consumer: consumer})
+			assert.ErrorIs(t, err, ErrUnknownEventConsumer)
+		}
+	})
+
+	t.Run("rejects an empty range", func(t *testing.T) {
+		var received []events.CreditsLow
+		from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+		_, err := NewEventLogServiceForTest(nil, nil).Replay(context.Background(), newBus(&received), EventReplayOptions{
+			Consumer: "projection",
+			From:     from,
+			To:       from,
+		})
+		assert.ErrorIs(t, err, ErrInvalidEventReplay)
+	})
+
+	t.Run("returns load errors", func(t *testing.T) {
+		repo := new(repomocks.MockDomainEventRepository)
+		repo.On("FindPage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		var received []events.CreditsLow
+		_, err := NewEventLogServiceForTest(nil, repo).Replay(context.Background(), newBus(&received), EventReplayOptions{Consumer: "projection"})
+		assert.Error(t, err)
+	})
+}
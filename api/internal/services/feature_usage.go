@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// FeatureUsageRetentionDays is how long raw usage events are kept once they
+// have been rolled up
+const FeatureUsageRetentionDays = 90
+
+// Default and max number of days in a feature usage report
+const (
+	DefaultFeatureUsageDays = 30
+	MaxFeatureUsageDays     = 365
+)
+
+// FeatureUsageRecorder records a use of a feature
+type FeatureUsageRecorder interface {
+	Record(userID uuid.UUID, feature string, credits int, latency time.Duration, success bool)
+}
+
+// FeatureUsageReporter defines the interface for the admin usage report
+type FeatureUsageReporter interface {
+	Report(days int) (*FeatureUsageReport, error)
+}
+
+// FeatureUsageSummary is a feature's usage over a whole report
+type FeatureUsageSummary struct {
+	Feature      string `json:"feature"`
+	Uses         int64  `json:"uses"`
+	Failures     int64  `json:"failures"`
+	Credits      int64  `json:"credits"`
+	AvgLatencyMs int64  `json:"avgLatencyMs"`
+}
+
+// FeatureUsageReport is per-feature usage over the last few days, with the
+// daily rollup rows it was summed from
+type FeatureUsageReport struct {
+	From     time.Time                  `json:"from"`
+	To       time.Time                  `json:"to"`
+	Features []FeatureUsageSummary      `json:"features"` // Most used first
+	Days     []models.FeatureUsageDaily `json:"days"`
+}
+
+// FeatureUsageService records how often each paid-for feature is used, what
+// it earns in credits, how long it takes and how often it fails, so pricing
+// can be weighed against cost. Events are rolled up into daily rows on an
+// interval; today's row fills in as the day goes on.
+type FeatureUsageService struct {
+	exec     repository.Executor
+	repo     repository.FeatureUsageRepository
+	interval time.Duration
+
+	now func() time.Time
+}
+
+// NewFeatureUsageService creates a new feature usage service
+func NewFeatureUsageService(database *db.DB, repo repository.FeatureUsageRepository, interval time.Duration) *FeatureUsageService {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	return &FeatureUsageService{
+		exec:     database.DB,
+		repo:     repo,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// NewFeatureUsageServiceForTest creates a FeatureUsageService with injected dependencies for testing.
+func NewFeatureUsageServiceForTest(exec repository.Executor, repo repository.FeatureUsageRepository, now func() time.Time) *FeatureUsageService {
+	return &FeatureUsageService{
+		exec:     exec,
+		repo:     repo,
+		interval: 15 * time.Minute,
+		now:      now,
+	}
+}
+
+// Record saves one use of a feature. Failures are logged rather than
+// returned: usage logging never fails the request it describes.
+func (s *FeatureUsageService) Record(userID uuid.UUID, feature string, credits int, latency time.Duration, success bool) {
+	event := &models.FeatureUsageEvent{
+		UserID:    userID,
+		Feature:   feature,
+		Credits:   credits,
+		LatencyMs: latency.Milliseconds(),
+		Success:   success,
+		CreatedAt: s.now(),
+	}
+	if err := s.repo.Create(s.exec, event); err != nil {
+		log.Printf("[FeatureUsage] Failed to record %s for user %s: %v", feature, userID, err)
+	}
+}
+
+// Start rolls up usage until ctx is cancelled
+func (s *FeatureUsageService) Start(ctx context.Context) {
+	log.Printf("[FeatureUsage] Rolling up feature usage every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Rollup()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rollup recomputes yesterday's and today's rows, so events recorded just
+// before midnight are counted, then prunes events past retention
+func (s *FeatureUsageService) Rollup() {
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if err := s.repo.RollupDay(s.exec, day); err != nil {
+			log.Printf("[FeatureUsage] Failed to roll up %s: %v", day.Format(time.DateOnly), err)
+			return
+		}
+	}
+
+	pruned, err := s.repo.DeleteEventsBefore(s.exec, today.AddDate(0, 0, -FeatureUsageRetentionDays))
+	if err != nil {
+		log.Printf("[FeatureUsage] Failed to prune old events: %v", err)
+		return
+	}
+	if pruned > 0 {
+		log.Printf("[FeatureUsage] Pruned %d events older than %d days", pruned, FeatureUsageRetentionDays)
+	}
+}
+
+// Report sums the last days of rollups (today included) per feature
+func (s *FeatureUsageService) Report(days int) (*FeatureUsageReport, error) {
+	if days <= 0 {
+		days = DefaultFeatureUsageDays
+	}
+	days = min(days, MaxFeatureUsageDays)
+
+	to := s.now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -(days - 1))
+	rows, err := s.repo.FindDaily(s.exec, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("find daily usage: %w", err)
+	}
+
+	byFeature := make(map[string]*FeatureUsageSummary)
+	latencyTotals := make(map[string]int64)
+	for _, row := range rows {
+		summary, ok := byFeature[row.Feature]
+		if !ok {
+			summary = &FeatureUsageSummary{Feature: row.Feature}
+			byFeature[row.Feature] = summary
+		}
+		summary.Uses += row.Uses
+		summary.Failures += row.Failures
+		summary.Credits += row.Credits
+		latencyTotals[row.Feature] += row.AvgLatencyMs * row.Uses
+	}
+
+	features := make([]FeatureUsageSummary, 0, len(byFeature))
+	for feature, summary := range byFeature {
+		if summary.Uses > 0 {
+			summary.AvgLatencyMs = latencyTotals[feature] / summary.Uses
+		}
+		features = append(features, *summary)
+	}
+	sort.Slice(features, func(i, j int) bool {
+		if features[i].Uses != features[j].Uses {
+			return features[i].Uses > features[j].Uses
+		}
+		return features[i].Feature < features[j].Feature
+	})
+
+	if rows == nil {
+		rows = []models.FeatureUsageDaily{}
+	}
+	return &FeatureUsageReport{From: from, To: to, Features: features, Days: rows}, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestFeatureUsageService_Record(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	t.Run("saves the event", func(t *testing.T) {
+		repo := new(repomocks.MockFeatureUsageRepository)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.FeatureUsageEvent) bool {
+			return e.UserID == userID && e.Feature == models.FeatureVoiceMessage && e.Credits == 1 &&
+				e.LatencyMs == 2500 && e.Success && e.CreatedAt.Equal(now)
+		})).Return(nil)
+
+		service := NewFeatureUsageServiceForTest(nil, repo, func() time.Time { return now })
+		service.Record(userID, models.FeatureVoiceMessage, 1, 2500*time.Millisecond, true)
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("a failed write doesn't panic", func(t *testing.T) {
+		repo := new(repomocks.MockFeatureUsageRepository)
+		repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		service := NewFeatureUsageServiceForTest(nil, repo, func() time.Time { return now })
+		service.Record(userID, models.FeatureAnkiExport, 0, time.Second, false)
+
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestFeatureUsageService_Rollup(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 10, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	t.Run("rolls up yesterday and today, then prunes", func(t *testing.T) {
+		repo := new(repomocks.MockFeatureUsageRepository)
+		repo.On("RollupDay", mock.Anything, today.AddDate(0, 0, -1)).Return(nil).Once()
+		repo.On("RollupDay", mock.Anything, today).Return(nil).Once()
+		repo.On("DeleteEventsBefore", mock.Anything, today.AddDate(0, 0, -FeatureUsageRetentionDays)).Return(int64(3), nil)
+
+		NewFeatureUsageServiceForTest(nil, repo, func() time.Time { return now }).Rollup()
+
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("keeps events when a rollup fails", func(t *testing.T) {
+		repo := new(repomocks.MockFeatureUsageRepository)
+		repo.On("RollupDay", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		NewFeatureUsageServiceForTest(nil, repo, func() time.Time { return now }).Rollup()
+
+		repo.AssertNotCalled(t, "DeleteEventsBefore", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFeatureUsageService_Report(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	repo := new(repomocks.MockFeatureUsageRepository)
+	repo.On("FindDaily", mock.Anything, today.AddDate(0, 0, -6), today).Return([]models.FeatureUsageDaily{
+		{Day: yesterday, Feature: models.FeatureVoiceMessage, Uses: 30, Failures: 3, Credits: 27, AvgLatencyMs: 4000},
+		{Day: yesterday, Feature: models.FeatureAnkiExport, Uses: 2, Credits: 0, AvgLatencyMs: 50},
+		{Day: today, Feature: models.FeatureVoiceMessage, Uses: 10, Failures: 1, Credits: 9, AvgLatencyMs: 2000},
+	}, nil)
+
+	service := NewFeatureUsageServiceForTest(nil, repo, func() time.Time { return now })
+	report, err := service.Report(7)
+
+	require.NoError(t, err)
+	assert.Equal(t, today.AddDate(0, 0, -6), report.From)
+	assert.Equal(t, today, report.To)
+	assert.Len(t, report.Days, 3)
+	assert.Equal(t, []FeatureUsageSummary{
+		// Latency is weighted by uses: (30*4000 + 10*2000) / 40
+		{Feature: models.FeatureVoiceMessage, Uses: 40, Failures: 4, Credits: 36, AvgLatencyMs: 3500},
+		{Feature: models.FeatureAnkiExport, Uses: 2, AvgLatencyMs: 50},
+	}, report.Features)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// freeRefreshBatchSize caps how many accounts are refreshed per query; a
+// sweep keeps going while batches come back full
+const freeRefreshBatchSize = 500
+
+// FreeCreditRefreshWorker refreshes the monthly credits of free-tier
+// accounts. Paid plans are refreshed when Stripe reports the renewal invoice
+// paid; free accounts have no invoice, so this sweeps the credits table on an
+// interval for those last refreshed over a month ago. Every instance can run
+// it: a refresh only applies to the row as it was read, so an account is
+// refreshed once however many sweeps find it. Credits held for turns in
+// flight are taken out of the new balance, since they are handed back to it
+// when the hold is released.
+type FreeCreditRefreshWorker struct {
+	exec        repository.Executor
+	txRunner    TxRunner
+	creditsRepo repository.CreditsRepository
+	txRepo      repository.CreditTransactionRepository
+	holdRepo    repository.CreditReservationRepository
+	interval    time.Duration
+
+	now func() time.Time
+}
+
+// NewFreeCreditRefreshWorker creates a new free credit refresh worker
+func NewFreeCreditRefreshWorker(
+	database *db.DB,
+	creditsRepo repository.CreditsRepository,
+	txRepo repository.CreditTransactionRepository,
+	holdRepo repository.CreditReservationRepository,
+	interval time.Duration,
+) *FreeCreditRefreshWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &FreeCreditRefreshWorker{
+		exec:        database.DB,
+		txRunner:    database.DB,
+		creditsRepo: creditsRepo,
+		txRepo:      txRepo,
+		holdRepo:    holdRepo,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// NewFreeCreditRefreshWorkerForTest creates a FreeCreditRefreshWorker with injected dependencies for testing.
+func NewFreeCreditRefreshWorkerForTest(
+	exec repository.Executor,
+	txRunner TxRunner,
+	creditsRepo repository.CreditsRepository,
+	txRepo repository.CreditTransactionRepository,
+	holdRepo repository.CreditReservationRepository,
+	now func() time.Time,
+) *FreeCreditRefreshWorker {
+	return &FreeCreditRefreshWorker{
+		exec:        exec,
+		txRunner:    txRunner,
+		creditsRepo: creditsRepo,
+		txRepo:      txRepo,
+		holdRepo:    holdRepo,
+		interval:    time.Hour,
+		now:         now,
+	}
+}
+
+// Start sweeps for free accounts due a refresh until ctx is cancelled
+func (w *FreeCreditRefreshWorker) Start(ctx context.Context) {
+	log.Printf("[FreeCreditRefresh] Checking for free accounts due a credit refresh every %s", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep refreshes every free account last refreshed over a month ago and
+// returns how many it refreshed. Accounts another instance got to first, or
+// whose credits changed while the sweep ran, are skipped until the next
+// sweep.
+func (w *FreeCreditRefreshWorker) Sweep(ctx context.Context) int {
+	now := w.now()
+	refreshed := 0
+	for ctx.Err() == nil {
+		due, err := w.creditsRepo.FindFreeRefreshDue(w.exec, now.AddDate(0, -1, 0), freeRefreshBatchSize)
+		if err != nil {
+			log.Printf("[FreeCreditRefresh] Failed to find accounts due a refresh: %v", err)
+			break
+		}
+
+		batch := 0
+		for i := range due {
+			ok, err := w.Refresh(&due[i], now)
+			if err != nil {
+				log.Printf("[FreeCreditRefresh] Failed to refresh credits for user %s: %v", due[i].UserID, err)
+				continue
+			}
+			if ok {
+				batch++
+			}
+		}
+		refreshed += batch
+
+		// A full batch that refreshed nothing would come back unchanged
+		if len(due) < freeRefreshBatchSize || batch == 0 {
+			break
+		}
+	}
+
+	if refreshed > 0 {
+		log.Printf("[FreeCreditRefresh] Refreshed credits for %d free accounts", refreshed)
+	}
+	return refreshed
+}
+
+// Refresh resets one account's credits to its monthly allowance, less any
+// still held, and records the refresh. It reports false, changing nothing, if
+// the credits changed since they were read.
+func (w *FreeCreditRefreshWorker) Refresh(credits *models.Credits, now time.Time) (bool, error) {
+	refreshed := false
+	err := w.txRunner.Transaction(func(tx *gorm.DB) error {
+		held, err := w.holdRepo.SumHeld(tx, credits.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get held credits: %w", err)
+		}
+		balance := max(credits.MonthlyAllowance-held, 0)
+
+		ok, err := w.creditsRepo.Refresh(tx, credits, balance, now)
+		if err != nil {
+			return fmt.Errorf("failed to update credits: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		transaction := &models.CreditTransaction{
+			UserID:       credits.UserID,
+			Type:         models.TransactionRefresh,
+			Amount:       balance - credits.Balance,
+			BalanceAfter: balance,
+			Description:  "Monthly credit refresh",
+		}
+		if err := w.txRepo.Create(tx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+		refreshed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return refreshed, nil
+}
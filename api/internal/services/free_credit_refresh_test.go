@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestFreeCreditRefreshWorker_Sweep(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	due := func() []models.Credits {
+		return []models.Credits{
+			{ID: uuid.New(), UserID: uuid.New(), Balance: 4, MonthlyAllowance: 20, LastRefreshedAt: now.AddDate(0, -2, 0)},
+			{ID: uuid.New(), UserID: uuid.New(), Balance: 0, MonthlyAllowance: 20, LastRefreshedAt: now.AddDate(0, -1, -1)},
+		}
+	}
+
+	t.Run("refreshes accounts due and records each refresh", func(t *testing.T) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		holdRepo := new(repomocks.MockCreditReservationRepository)
+		holdRepo.On("SumHeld", mock.Anything, mock.Anything).Return(0, nil)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		credits := due()
+
+		creditsRepo.On("FindFreeRefreshDue", mock.Anything, now.AddDate(0, -1, 0), freeRefreshBatchSize).Return(credits, nil)
+		creditsRepo.On("Refresh", mock.Anything, mock.Anything, 20, now).Return(true, nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.UserID == credits[0].UserID && tx.Type == models.TransactionRefresh && tx.Amount == 16 && tx.BalanceAfter == 20
+		})).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.UserID == credits[1].UserID && tx.Amount == 20 && tx.BalanceAfter == 20
+		})).Return(nil)
+
+		worker := NewFreeCreditRefreshWorkerForTest(nil, txRunner, creditsRepo, txRepo, holdRepo, func() time.Time { return now })
+
+		assert.Equal(t, 2, worker.Sweep(context.Background()))
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("leaves out credits held by turns in flight", func(t *testing.T) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		holdRepo := new(repomocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		credits := due()[:1]
+
+		creditsRepo.On("FindFreeRefreshDue", mock.Anything, mock.Anything, mock.Anything).Return(credits, nil)
+		holdRepo.On("SumHeld", mock.Anything, credits[0].UserID).Return(2, nil)
+		creditsRepo.On("Refresh", mock.Anything, mock.Anything, 18, now).Return(true, nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == 14 && tx.BalanceAfter == 18
+		})).Return(nil)
+
+		worker := NewFreeCreditRefreshWorkerForTest(nil, txRunner, creditsRepo, txRepo, holdRepo, func() time.Time { return now })
+
+		assert.Equal(t, 1, worker.Sweep(context.Background()))
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("skips accounts another instance refreshed first", func(t *testing.T) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		holdRepo := new(repomocks.MockCreditReservationRepository)
+		holdRepo.On("SumHeld", mock.Anything, mock.Anything).Return(0, nil)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+
+		creditsRepo.On("FindFreeRefreshDue", mock.Anything, mock.Anything, mock.Anything).Return(due(), nil)
+		creditsRepo.On("Refresh", mock.Anything, mock.Anything, 20, now).Return(false, nil)
+
+		worker := NewFreeCreditRefreshWorkerForTest(nil, txRunner, creditsRepo, txRepo, holdRepo, func() time.Time { return now })
+
+		assert.Equal(t, 0, worker.Sweep(context.Background()))
+		txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
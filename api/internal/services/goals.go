@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// MaxGoalLength caps how long a thread goal can be
+const MaxGoalLength = 500
+
+// GoalSystemPrompt builds the system message that steers the tutor toward the thread's goal
+func GoalSystemPrompt(goal string) client.ConversationMessage {
+	return client.ConversationMessage{
+		Role: "system",
+		Content: fmt.Sprintf("The learner's goal for this conversation is: %q. "+
+			"Stay in character and steer the roleplay so they have a natural chance to accomplish it, "+
+			"but let them do the work rather than completing it for them.", goal),
+	}
+}
+
+// GoalService detects when a thread's conversation goal has been accomplished
+type GoalService struct {
+	exec                repository.Executor
+	threadRepo          repository.ThreadRepository
+	messageRepo         repository.MessageRepository
+	openAIClient        client.OpenAIClient
+	creditsService      CreditsManager
+	notificationService NotificationManager
+}
+
+// NewGoalService creates a new goal service
+func NewGoalService(
+	database *db.DB,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	openAIClient client.OpenAIClient,
+	creditsService CreditsManager,
+	notificationService NotificationManager,
+) *GoalService {
+	return &GoalService{
+		exec:                database.DB,
+		threadRepo:          threadRepo,
+		messageRepo:         messageRepo,
+		openAIClient:        openAIClient,
+		creditsService:      creditsService,
+		notificationService: notificationService,
+	}
+}
+
+// NewGoalServiceForTest creates a GoalService with injected dependencies for testing.
+func NewGoalServiceForTest(
+	exec repository.Executor,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	openAIClient client.OpenAIClient,
+	creditsService CreditsManager,
+	notificationService NotificationManager,
+) *GoalService {
+	return &GoalService{
+		exec:                exec,
+		threadRepo:          threadRepo,
+		messageRepo:         messageRepo,
+		openAIClient:        openAIClient,
+		creditsService:      creditsService,
+		notificationService: notificationService,
+	}
+}
+
+// CheckCompletion runs the post-turn goal check for a thread. If the goal is
+// met it is marked complete, bonus credits are awarded, and the user is notified.
+// Returns true if this call completed the goal.
+func (s *GoalService) CheckCompletion(threadID uuid.UUID) (bool, error) {
+	thread, err := s.threadRepo.FindByID(s.exec, threadID)
+	if err != nil {
+		return false, fmt.Errorf("get thread: %w", err)
+	}
+	if thread.Goal == nil || thread.GoalCompletedAt != nil {
+		return false, nil
+	}
+
+	messages, err := s.messageRepo.FindByThreadID(s.exec, threadID)
+	if err != nil {
+		return false, fmt.Errorf("get messages: %w", err)
+	}
+
+	history := make([]client.ConversationMessage, len(messages))
+	for i, msg := range messages {
+		history[i] = client.ConversationMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	achieved, err := s.openAIClient.EvaluateGoal(*thread.Goal, history)
+	if errors.Is(err, ErrLLMDeferred) {
+		// Over the LLM budget; the check after the next turn covers this one
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("evaluate goal: %w", err)
+	}
+	if !achieved {
+		return false, nil
+	}
+
+	// Conditional update so concurrent checks can't award the bonus twice
+	marked, err := s.threadRepo.MarkGoalCompleted(s.exec, threadID, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("mark goal completed: %w", err)
+	}
+	if !marked {
+		return false, nil
+	}
+
+	if err := s.creditsService.AddCredits(thread.UserID, models.GoalCompletionBonusCredits, "Goal completed: "+*thread.Goal); err != nil {
+		log.Printf("[GoalService] Failed to award goal bonus for thread %s: %v", threadID, err)
+	}
+
+	body := fmt.Sprintf("You completed your goal %q and earned %d bonus credits.", *thread.Goal, models.GoalCompletionBonusCredits)
+	data := models.JSONMap{
+		"threadId":     threadID.String(),
+		"bonusCredits": models.GoalCompletionBonusCredits,
+	}
+	if err := s.notificationService.Notify(thread.UserID, models.NotificationGoalCompleted, "Goal completed!", body, data); err != nil {
+		log.Printf("[GoalService] Failed to notify goal completion for thread %s: %v", threadID, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+// stubCredits stubs the CreditsManager methods used by services under test.
+// (services/mocks can't be imported here without an import cycle.)
+type stubCredits struct {
+	CreditsManager
+	mock.Mock
+}
+
+func (m *stubCredits) AddCredits(userID uuid.UUID, amount int, description string) error {
+	return m.Called(userID, amount, description).Error(0)
+}
+
+func (m *stubCredits) DeductCredits(userID uuid.UUID, amount int, reference, description string) error {
+	return m.Called(userID, amount, reference, description).Error(0)
+}
+
+func (m *stubCredits) RefundCredits(userID uuid.UUID, amount int, reference, description string) error {
+	return m.Called(userID, amount, reference, description).Error(0)
+}
+
+func (m *stubCredits) ReserveCredits(userID uuid.UUID, amount int, reference, description string) (*models.CreditReservation, error) {
+	args := m.Called(userID, amount, reference, description)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditReservation), args.Error(1)
+}
+
+func (m *stubCredits) CaptureReservation(id uuid.UUID) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *stubCredits) ReduceReservation(id uuid.UUID, amount int) error {
+	return m.Called(id, amount).Error(0)
+}
+
+func (m *stubCredits) ReleaseReservation(id uuid.UUID) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *stubCredits) RefundReservation(reference, description string) (bool, error) {
+	args := m.Called(reference, description)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *stubCredits) UpdateAllowance(userID uuid.UUID, tier models.SubscriptionTier) error {
+	return m.Called(userID, tier).Error(0)
+}
+
+// stubNotifications stubs NotificationManager.Notify.
+type stubNotifications struct {
+	NotificationManager
+	mock.Mock
+}
+
+func (m *stubNotifications) Notify(userID uuid.UUID, notificationType models.NotificationType, title, body string, data models.JSONMap) error {
+	return m.Called(userID, notificationType, title, body, data).Error(0)
+}
+
+type goalTestDeps struct {
+	threadRepo    *repomocks.MockThreadRepository
+	messageRepo   *repomocks.MockMessageRepository
+	openAI        *clientmocks.MockOpenAIClient
+	credits       *stubCredits
+	notifications *stubNotifications
+}
+
+func newGoalServiceWithMocks() (*GoalService, *goalTestDeps) {
+	deps := &goalTestDeps{
+		threadRepo:    new(repomocks.MockThreadRepository),
+		messageRepo:   new(repomocks.MockMessageRepository),
+		openAI:        new(clientmocks.MockOpenAIClient),
+		credits:       new(stubCredits),
+		notifications: new(stubNotifications),
+	}
+	service := NewGoalServiceForTest(nil, deps.threadRepo, deps.messageRepo, deps.openAI, deps.credits, deps.notifications)
+	return service, deps
+}
+
+func TestGoalService_CheckCompletion(t *testing.T) {
+	threadID := uuid.New()
+	userID := uuid.New()
+	goal := "order food and ask for the bill"
+	messages := []models.Message{
+		{Role: "assistant", Content: "What can I get you?"},
+		{Role: "user", Content: "The soup please, and the bill."},
+	}
+
+	t.Run("awards bonus and notifies when goal met", func(t *testing.T) {
+		service, deps := newGoalServiceWithMocks()
+
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).
+			Return(&models.Thread{ID: threadID, UserID: userID, Goal: &goal}, nil)
+		deps.messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(messages, nil)
+		deps.openAI.On("EvaluateGoal", goal, mock.Anything).Return(true, nil)
+		deps.threadRepo.On("MarkGoalCompleted", mock.Anything, threadID, mock.Anything).Return(true, nil)
+		deps.credits.On("AddCredits", userID, models.GoalCompletionBonusCredits, mock.Anything).Return(nil)
+		deps.notifications.On("Notify", userID, models.NotificationGoalCompleted, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		completed, err := service.CheckCompletion(threadID)
+
+		assert.NoError(t, err)
+		assert.True(t, completed)
+		deps.credits.AssertExpectations(t)
+		deps.notifications.AssertExpectations(t)
+	})
+
+	t.Run("goal not met", func(t *testing.T) {
+		service, deps := newGoalServiceWithMocks()
+
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).
+			Return(&models.Thread{ID: threadID, UserID: userID, Goal: &goal}, nil)
+		deps.messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(messages, nil)
+		deps.openAI.On("EvaluateGoal", goal, mock.Anything).Return(false, nil)
+
+		completed, err := service.CheckCompletion(threadID)
+
+		assert.NoError(t, err)
+		assert.False(t, completed)
+		deps.threadRepo.AssertNotCalled(t, "MarkGoalCompleted", mock.Anything, mock.Anything, mock.Anything)
+		deps.credits.AssertNotCalled(t, "AddCredits", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no bonus when another check already completed it", func(t *testing.T) {
+		service, deps := newGoalServiceWithMocks()
+
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).
+			Return(&models.Thread{ID: threadID, UserID: userID, Goal: &goal}, nil)
+		deps.messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(messages, nil)
+		deps.openAI.On("EvaluateGoal", goal, mock.Anything).Return(true, nil)
+		deps.threadRepo.On("MarkGoalCompleted", mock.Anything, threadID, mock.Anything).Return(false, nil)
+
+		completed, err := service.CheckCompletion(threadID)
+
+		assert.NoError(t, err)
+		assert.False(t, completed)
+		deps.credits.AssertNotCalled(t, "AddCredits", mock.Anything, mock.Anything, mock.Anything)
+		deps.notifications.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("thread without goal is skipped", func(t *testing.T) {
+		service, deps := newGoalServiceWithMocks()
+
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).
+			Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+
+		completed, err := service.CheckCompletion(threadID)
+
+		assert.NoError(t, err)
+		assert.False(t, completed)
+		deps.openAI.AssertNotCalled(t, "EvaluateGoal", mock.Anything, mock.Anything)
+	})
+
+	t.Run("evaluation error", func(t *testing.T) {
+		service, deps := newGoalServiceWithMocks()
+
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).
+			Return(&models.Thread{ID: threadID, UserID: userID, Goal: &goal}, nil)
+		deps.messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(messages, nil)
+		deps.openAI.On("EvaluateGoal", goal, mock.Anything).Return(false, errors.New("rate limited"))
+
+		completed, err := service.CheckCompletion(threadID)
+
+		assert.Error(t, err)
+		assert.False(t, completed)
+	})
+
+	t.Run("evaluation deferred over the LLM budget", func(t *testing.T) {
+		service, deps := newGoalServiceWithMocks()
+
+		deps.threadRepo.On("FindByID", mock.Anything, threadID).
+			Return(&models.Thread{ID: threadID, UserID: userID, Goal: &goal}, nil)
+		deps.messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(messages, nil)
+		deps.openAI.On("EvaluateGoal", goal, mock.Anything).Return(false, ErrLLMDeferred)
+
+		completed, err := service.CheckCompletion(threadID)
+
+		assert.NoError(t, err)
+		assert.False(t, completed)
+		deps.threadRepo.AssertNotCalled(t, "MarkGoalCompleted", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
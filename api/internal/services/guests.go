@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GuestLifetime is how long a guest demo lasts before it and its data are purged
+const GuestLifetime = 24 * time.Hour
+
+// GuestThreadLimit is how many threads a guest can have
+const GuestThreadLimit = 1
+
+// GuestsPerIP is how many guests can be started from one IP address per
+// GuestLifetime, so the demo can't be reset for free messages indefinitely
+const GuestsPerIP = 3
+
+// guestPurgeBatchSize caps how many expired guests are purged per sweep
+const guestPurgeBatchSize = 100
+
+// guestEmailDomain is the reserved domain of the placeholder emails guests
+// are given, since every user needs a unique email
+const guestEmailDomain = "guest.invalid"
+
+var (
+	ErrGuestModeDisabled = errors.New("guest mode is disabled")
+	ErrGuestThreadLimit  = errors.New("guests can only have one thread")
+	ErrTooManyGuests     = errors.New("too many guest demos from this address")
+)
+
+// GuestLimiter defines the interface for the limits on guest demos
+type GuestLimiter interface {
+	CheckThreadLimit(user *models.User) error
+}
+
+// DemoCreditsInitializer grants a new guest their demo credits
+type DemoCreditsInitializer interface {
+	InitializeDemoCreditsWithTx(exec repository.Executor, userID uuid.UUID, balance int) error
+}
+
+// GuestService runs the try-before-signup demo: short-lived accounts without
+// an email, limited to one thread and a few voice messages paid for from
+// demo credits. Signing up or in from a guest session moves the demo thread
+// to the real account; guests that don't are purged after GuestLifetime.
+type GuestService struct {
+	exec       repository.Executor
+	txRunner   TxRunner
+	userRepo   repository.UserRepository
+	guestRepo  repository.GuestRepository
+	threadRepo repository.ThreadRepository
+	credits    DemoCreditsInitializer
+	storage    client.StorageClient
+
+	enabled      bool
+	messageLimit int
+	interval     time.Duration
+
+	// Runtime supplies the credit cost of a voice message; nil uses the default
+	Runtime *RuntimeSettingsService
+
+	now func() time.Time
+}
+
+// NewGuestService creates a new guest service. messageLimit is how many voice
+// messages a guest can send; interval is the time between purges.
+func NewGuestService(
+	database *db.DB,
+	userRepo repository.UserRepository,
+	guestRepo repository.GuestRepository,
+	threadRepo repository.ThreadRepository,
+	credits DemoCreditsInitializer,
+	storage client.StorageClient,
+	enabled bool,
+	messageLimit int,
+	interval time.Duration,
+) *GuestService {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	return &GuestService{
+		exec:         database.DB,
+		txRunner:     database.DB,
+		userRepo:     userRepo,
+		guestRepo:    guestRepo,
+		threadRepo:   threadRepo,
+		credits:      credits,
+		storage:      storage,
+		enabled:      enabled,
+		messageLimit: messageLimit,
+		interval:     interval,
+		now:          time.Now,
+	}
+}
+
+// NewGuestServiceForTest creates a GuestService with injected dependencies for testing.
+func NewGuestServiceForTest(
+	exec repository.Executor,
+	txRunner TxRunner,
+	userRepo repository.UserRepository,
+	guestRepo repository.GuestRepository,
+	threadRepo repository.ThreadRepository,
+	credits DemoCreditsInitializer,
+	storage client.StorageClient,
+	messageLimit int,
+) *GuestService {
+	return &GuestService{
+		exec:         exec,
+		txRunner:     txRunner,
+		userRepo:     userRepo,
+		guestRepo:    guestRepo,
+		threadRepo:   threadRepo,
+		credits:      credits,
+		storage:      storage,
+		enabled:      true,
+		messageLimit: messageLimit,
+		interval:     time.Hour,
+		now:          time.Now,
+	}
+}
+
+// Enabled reports whether guests can be created
+func (s *GuestService) Enabled() bool {
+	return s != nil && s.enabled
+}
+
+// Create starts a guest demo for a visitor at ipAddress: a user with a
+// placeholder email that expires after GuestLifetime, and demo credits for
+// the allowed voice messages
+func (s *GuestService) Create(ipAddress string) (*models.User, error) {
+	if !s.Enabled() {
+		return nil, ErrGuestModeDisabled
+	}
+	recent, err := s.guestRepo.CountCreatedFromIP(s.exec, ipAddress, s.now().Add(-GuestLifetime))
+	if err != nil {
+		return nil, fmt.Errorf("count guests: %w", err)
+	}
+	if recent >= GuestsPerIP {
+		return nil, ErrTooManyGuests
+	}
+
+	id := uuid.New()
+	expiresAt := s.now().Add(GuestLifetime)
+	guest := &models.User{
+		ID:             id,
+		Email:          fmt.Sprintf("guest-%s@%s", id, guestEmailDomain),
+		Name:           "Guest",
+		GuestExpiresAt: &expiresAt,
+	}
+	err = s.txRunner.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.Create(tx, guest); err != nil {
+			return fmt.Errorf("create guest: %w", err)
+		}
+		return s.credits.InitializeDemoCreditsWithTx(tx, guest.ID, s.messageLimit*s.Runtime.CreditCostPerMessage())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return guest, nil
+}
+
+// CheckThreadLimit returns ErrGuestThreadLimit if user is a guest who already
+// has their thread. Other users aren't limited here.
+func (s *GuestService) CheckThreadLimit(user *models.User) error {
+	if !user.IsGuest() {
+		return nil
+	}
+	count, err := s.threadRepo.CountByUserID(s.exec, user.ID)
+	if err != nil {
+		return fmt.Errorf("count threads: %w", err)
+	}
+	if count >= GuestThreadLimit {
+		return ErrGuestThreadLimit
+	}
+	return nil
+}
+
+// Upgrade moves a guest's demo thread to the account they signed up or in
+// with, then deletes the guest. Demo credits and stats aren't carried over.
+func (s *GuestService) Upgrade(guestID, userID uuid.UUID) error {
+	return s.txRunner.Transaction(func(tx *gorm.DB) error {
+		guest, err := s.userRepo.FindByID(tx, guestID)
+		if err != nil {
+			return fmt.Errorf("find guest: %w", err)
+		}
+		if !guest.IsGuest() {
+			return fmt.Errorf("user %s is not a guest", guestID)
+		}
+
+		moved, err := s.guestRepo.MoveThreads(tx, guestID, userID)
+		if err != nil {
+			return fmt.Errorf("move threads: %w", err)
+		}
+		if err := s.guestRepo.DeleteUser(tx, guestID); err != nil {
+			return fmt.Errorf("delete guest: %w", err)
+		}
+		log.Printf("[Guests] Moved %d demo threads from guest %s to user %s", moved, guestID, userID)
+		return nil
+	})
+}
+
+// Start purges expired guests until ctx is cancelled
+func (s *GuestService) Start(ctx context.Context) {
+	log.Printf("[Guests] Purging expired guests every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes one batch of expired guests with their recordings and data,
+// and returns how many were removed. A guest whose recordings can't all be
+// deleted is kept so the next sweep retries it.
+func (s *GuestService) Purge(ctx context.Context) int {
+	guests, err := s.guestRepo.FindExpired(s.exec, s.now(), guestPurgeBatchSize)
+	if err != nil {
+		log.Printf("[Guests] Failed to find expired guests: %v", err)
+		return 0
+	}
+
+	purged := 0
+	for _, guest := range guests {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.purgeOne(ctx, guest.ID); err != nil {
+			log.Printf("[Guests] Failed to purge guest %s: %v", guest.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	if purged > 0 {
+		log.Printf("[Guests] Purged %d expired guests", purged)
+	}
+	return purged
+}
+
+func (s *GuestService) purgeOne(ctx context.Context, guestID uuid.UUID) error {
+	keys, err := s.guestRepo.FindAudioKeys(s.exec, guestID)
+	if err != nil {
+		return fmt.Errorf("find recordings: %w", err)
+	}
+	for _, key := range keys {
+		if err := s.storage.DeleteAudio(ctx, key); err != nil {
+			return fmt.Errorf("delete recording %s: %w", key, err)
+		}
+		if variant := models.LowBitrateAudioKey(key); variant != "" {
+			if err := s.storage.DeleteAudio(ctx, variant); err != nil {
+				return fmt.Errorf("delete recording %s: %w", variant, err)
+			}
+		}
+	}
+
+	return s.txRunner.Transaction(func(tx *gorm.DB) error {
+		return s.guestRepo.DeleteUser(tx, guestID)
+	})
+}
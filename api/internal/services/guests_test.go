@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+// stubDemoCredits is a mock DemoCreditsInitializer for testing.
+type stubDemoCredits struct {
+	mock.Mock
+}
+
+func (m *stubDemoCredits) InitializeDemoCreditsWithTx(exec repository.Executor, userID uuid.UUID, balance int) error {
+	return m.Called(exec, userID, balance).Error(0)
+}
+
+func TestGuestService_Create(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("creates a guest with demo credits", func(t *testing.T) {
+		userRepo := new(repomocks.MockUserRepository)
+		guestRepo := new(repomocks.MockGuestRepository)
+		credits := new(stubDemoCredits)
+		txRunner := new(mockTxRunner)
+
+		guestRepo.On("CountCreatedFromIP", mock.Anything, "203.0.113.7", now.Add(-GuestLifetime)).Return(int64(1), nil)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		userRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.IsGuest() && u.GuestExpiresAt.Equal(now.Add(GuestLifetime))
+		})).Return(nil)
+		credits.On("InitializeDemoCreditsWithTx", mock.Anything, mock.Anything, 5*models.CreditCostPerMessage).Return(nil)
+
+		service := NewGuestServiceForTest(nil, txRunner, userRepo, guestRepo, nil, credits, nil, 5)
+		service.now = func() time.Time { return now }
+		guest, err := service.Create("203.0.113.7")
+
+		require.NoError(t, err)
+		assert.True(t, guest.IsGuest())
+		assert.Contains(t, guest.Email, "@"+guestEmailDomain)
+		userRepo.AssertExpectations(t)
+		credits.AssertExpectations(t)
+	})
+
+	t.Run("limits guests per address", func(t *testing.T) {
+		guestRepo := new(repomocks.MockGuestRepository)
+		guestRepo.On("CountCreatedFromIP", mock.Anything, "203.0.113.7", mock.Anything).Return(int64(GuestsPerIP), nil)
+
+		_, err := NewGuestServiceForTest(nil, nil, nil, guestRepo, nil, nil, nil, 5).Create("203.0.113.7")
+
+		assert.ErrorIs(t, err, ErrTooManyGuests)
+	})
+
+	t.Run("rejects when disabled", func(t *testing.T) {
+		service := NewGuestServiceForTest(nil, nil, nil, nil, nil, nil, nil, 5)
+		service.enabled = false
+
+		_, err := service.Create("203.0.113.7")
+
+		assert.ErrorIs(t, err, ErrGuestModeDisabled)
+	})
+}
+
+func TestGuestService_CheckThreadLimit(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	guest := &models.User{ID: uuid.New(), GuestExpiresAt: &expiresAt}
+
+	t.Run("allows the first thread", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("CountByUserID", mock.Anything, guest.ID).Return(int64(0), nil)
+
+		err := NewGuestServiceForTest(nil, nil, nil, nil, threadRepo, nil, nil, 5).CheckThreadLimit(guest)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects a second thread", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("CountByUserID", mock.Anything, guest.ID).Return(int64(GuestThreadLimit), nil)
+
+		err := NewGuestServiceForTest(nil, nil, nil, nil, threadRepo, nil, nil, 5).CheckThreadLimit(guest)
+
+		assert.ErrorIs(t, err, ErrGuestThreadLimit)
+	})
+
+	t.Run("doesn't limit registered users", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+
+		err := NewGuestServiceForTest(nil, nil, nil, nil, threadRepo, nil, nil, 5).CheckThreadLimit(&models.User{ID: uuid.New()})
+
+		assert.NoError(t, err)
+		threadRepo.AssertNotCalled(t, "CountByUserID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGuestService_Upgrade(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	guestID, userID := uuid.New(), uuid.New()
+
+	t.Run("moves threads and deletes the guest", func(t *testing.T) {
+		userRepo := new(repomocks.MockUserRepository)
+		guestRepo := new(repomocks.MockGuestRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		userRepo.On("FindByID", mock.Anything, guestID).Return(&models.User{ID: guestID, GuestExpiresAt: &expiresAt}, nil)
+		guestRepo.On("MoveThreads", mock.Anything, guestID, userID).Return(int64(1), nil)
+		guestRepo.On("DeleteUser", mock.Anything, guestID).Return(nil)
+
+		err := NewGuestServiceForTest(nil, txRunner, userRepo, guestRepo, nil, nil, nil, 5).Upgrade(guestID, userID)
+
+		assert.NoError(t, err)
+		guestRepo.AssertExpectations(t)
+	})
+
+	t.Run("refuses to delete a registered user", func(t *testing.T) {
+		userRepo := new(repomocks.MockUserRepository)
+		guestRepo := new(repomocks.MockGuestRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		userRepo.On("FindByID", mock.Anything, guestID).Return(&models.User{ID: guestID}, nil)
+
+		err := NewGuestServiceForTest(nil, txRunner, userRepo, guestRepo, nil, nil, nil, 5).Upgrade(guestID, userID)
+
+		assert.Error(t, err)
+		guestRepo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGuestService_Purge(t *testing.T) {
+	now := time.Date(2026, 6, 2, 12, 0, 0, 0, time.UTC)
+	kept, purged := uuid.New(), uuid.New()
+
+	guestRepo := new(repomocks.MockGuestRepository)
+	storage := new(clientmocks.MockStorageClient)
+	txRunner := new(mockTxRunner)
+	txRunner.On("Transaction", mock.Anything).Return(nil)
+	guestRepo.On("FindExpired", mock.Anything, now, guestPurgeBatchSize).Return([]models.User{{ID: kept}, {ID: purged}}, nil)
+	guestRepo.On("FindAudioKeys", mock.Anything, kept).Return([]string{"audio/kept.webm"}, nil)
+	guestRepo.On("FindAudioKeys", mock.Anything, purged).Return([]string{"audio/purged.webm", "assistant/t/reply.mp3"}, nil)
+	storage.On("DeleteAudio", mock.Anything, "audio/kept.webm").Return(errors.New("storage down"))
+	storage.On("DeleteAudio", mock.Anything, "audio/purged.webm").Return(nil)
+	storage.On("DeleteAudio", mock.Anything, "assistant/t/reply.mp3").Return(nil)
+	storage.On("DeleteAudio", mock.Anything, "assistant/t/reply.low.ogg").Return(nil)
+	guestRepo.On("DeleteUser", mock.Anything, purged).Return(nil)
+
+	service := NewGuestServiceForTest(nil, txRunner, nil, guestRepo, nil, nil, storage, 5)
+	service.now = func() time.Time { return now }
+
+	assert.Equal(t, 1, service.Purge(context.Background()))
+	guestRepo.AssertNotCalled(t, "DeleteUser", mock.Anything, kept)
+	guestRepo.AssertExpectations(t)
+	storage.AssertExpectations(t)
+}
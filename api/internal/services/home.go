@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Home screen limits
+const (
+	HomeMaxDueReviews = 5
+	// A weak phoneme is due for review once it hasn't been practiced for this long
+	homeReviewInterval = 24 * time.Hour
+	// How long a request waits for the day's motivational message before
+	// falling back to a canned one
+	homeMotivationTimeout = 3 * time.Second
+)
+
+// Home screen section names, as listed in HomeScreen.Unavailable
+const (
+	HomeSectionStreak     = "streak"
+	HomeSectionCredits    = "credits"
+	HomeSectionScenario   = "recommendedScenario"
+	HomeSectionReviews    = "dueReviews"
+	HomeSectionLastThread = "lastActiveThread"
+)
+
+// Recommendation reasons
+const (
+	ScenarioReasonStarter     = "starter"
+	ScenarioReasonWeakPhoneme = "weak_phoneme"
+	ScenarioReasonInterest    = "interest"
+	ScenarioReasonDaily       = "daily"
+)
+
+// fallbackMotivations are shown when the day's message can't be generated
+var fallbackMotivations = []string{
+	"Every conversation makes the next one easier. Let's talk!",
+	"A few minutes of speaking today beats an hour next week.",
+	"Mistakes are how your ear learns. Keep going!",
+	"Your accent is getting clearer one sentence at a time.",
+}
+
+const motivationPrompt = `You write the one-line greeting on a language learning app's home screen. ` +
+	`Write one short, warm, specific sentence (at most 20 words) encouraging the learner to practice speaking English today. ` +
+	`No quotes, no emojis, no hashtags.`
+
+// HomeProvider defines the interface for the home screen
+type HomeProvider interface {
+	GetHome(ctx context.Context, user *models.User) *HomeScreen
+}
+
+// HomeScreen is everything the home screen shows. A section that fails to
+// load is left empty and named in Unavailable, so one failing source doesn't
+// take down the page.
+type HomeScreen struct {
+	Streak              *HomeStreak           `json:"streak"`
+	Credits             *HomeCredits          `json:"credits"`
+	RecommendedScenario *RecommendedScenario  `json:"recommendedScenario"`
+	DueReviews          []DueReview           `json:"dueReviews"`
+	LastActiveThread    *models.ThreadSummary `json:"lastActiveThread"`
+	Motivation          string                `json:"motivation"`
+	Unavailable         []string              `json:"unavailable,omitempty"`
+}
+
+// HomeStreak is the learner's run of consecutive practice days
+type HomeStreak struct {
+	Days           int  `json:"days"`
+	PracticedToday bool `json:"practicedToday"`
+}
+
+// HomeCredits is the learner's credit balance
+type HomeCredits struct {
+	Balance          int `json:"balance"`
+	MonthlyAllowance int `json:"monthlyAllowance"`
+}
+
+// RecommendedScenario is the scenario suggested today and why
+type RecommendedScenario struct {
+	Scenario
+	Reason  string `json:"reason"`
+	Phoneme string `json:"phoneme,omitempty"` // The weak phoneme it drills, for weak_phoneme
+}
+
+// DueReview is a weak phoneme that hasn't been practiced lately
+type DueReview struct {
+	Phoneme       string    `json:"phoneme"`
+	Accuracy      float64   `json:"accuracy"`
+	TotalAttempts int       `json:"totalAttempts"`
+	LastPracticed time.Time `json:"lastPracticed"`
+}
+
+// dailyMotivation is a generated message and the user's day it is for
+type dailyMotivation struct {
+	day  string
+	text string
+}
+
+// HomeService assembles the home screen from the learner's streak, credits,
+// phoneme stats, memory and threads
+type HomeService struct {
+	exec        repository.Executor
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+	statsRepo   repository.PhonemeStatsRepository
+	credits     CreditsManager
+	memory      LearnerMemory
+	openAI      client.OpenAIClient
+
+	// Timezones starts each user's day, for the streak, the daily scenario
+	// and the motivational message, at their local midnight; nil uses UTC
+	Timezones UserTimezones
+
+	// The day's motivational message per user. Generated messages are kept
+	// in memory until the day changes; each server instance makes its own.
+	mu          sync.Mutex
+	motivations map[uuid.UUID]dailyMotivation
+	generating  map[uuid.UUID]chan struct{}
+
+	now func() time.Time
+}
+
+// NewHomeService creates a new home screen service. Without an LLM client,
+// the motivational message is always a canned one.
+func NewHomeService(
+	database *db.DB,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+	credits CreditsManager,
+	memory LearnerMemory,
+	openAI client.OpenAIClient,
+) *HomeService {
+	return &HomeService{
+		exec:        database.DB,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		statsRepo:   statsRepo,
+		credits:     credits,
+		memory:      memory,
+		openAI:      openAI,
+		motivations: make(map[uuid.UUID]dailyMotivation),
+		generating:  make(map[uuid.UUID]chan struct{}),
+		now:         time.Now,
+	}
+}
+
+// NewHomeServiceForTest creates a HomeService with injected dependencies for testing.
+func NewHomeServiceForTest(
+	exec repository.Executor,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+	credits CreditsManager,
+	memory LearnerMemory,
+	openAI client.OpenAIClient,
+) *HomeService {
+	return &HomeService{
+		exec:        exec,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		statsRepo:   statsRepo,
+		credits:     credits,
+		memory:      memory,
+		openAI:      openAI,
+		motivations: make(map[uuid.UUID]dailyMotivation),
+		generating:  make(map[uuid.UUID]chan struct{}),
+		now:         time.Now,
+	}
+}
+
+// GetHome loads every section concurrently. The motivational message comes
+// last, since the LLM is asked about it at most once a day per user.
+func (s *HomeService) GetHome(ctx context.Context, user *models.User) *HomeScreen {
+	now := s.now().In(userLocation(s.Timezones, user.ID))
+	home := &HomeScreen{DueReviews: []DueReview{}}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	// Phoneme stats feed both the reviews and the recommendation
+	phonemeStats := sync.OnceValues(func() ([]models.PhonemeStats, error) {
+		return s.statsRepo.FindByUserID(s.exec, user.ID, DefaultLanguage)
+	})
+
+	section := func(name string, load func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := func() (err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("panic: %v", r)
+					}
+				}()
+				return load()
+			}()
+			if err != nil {
+				log.Printf("[Home] Failed to load %s for user %s: %v", name, user.ID, err)
+				mu.Lock()
+				home.Unavailable = append(home.Unavailable, name)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	section(HomeSectionStreak, func() error {
+		days, err := s.messageRepo.FindActiveDaysByUserID(s.exec, user.ID, now.AddDate(0, 0, -maxBadgeStreakDays), now.Location())
+		if err != nil {
+			return err
+		}
+		home.Streak = &HomeStreak{
+			Days:           currentStreak(days, now),
+			PracticedToday: len(days) > 0 && sameDay(days[0], now),
+		}
+		return nil
+	})
+	section(HomeSectionCredits, func() error {
+		credits, err := s.credits.GetCredits(user.ID)
+		if err != nil {
+			return err
+		}
+		home.Credits = &HomeCredits{Balance: credits.Balance, MonthlyAllowance: credits.MonthlyAllowance}
+		return nil
+	})
+	section(HomeSectionReviews, func() error {
+		stats, err := phonemeStats()
+		if err != nil {
+			return err
+		}
+		home.DueReviews = dueReviews(stats, now)
+		return nil
+	})
+	section(HomeSectionScenario, func() error {
+		stats, err := phonemeStats()
+		if err != nil {
+			return err
+		}
+		var interests []string
+		if s.memory != nil {
+			profile, err := s.memory.GetProfile(user.ID)
+			if err != nil {
+				return err
+			}
+			if !profile.Disabled {
+				interests = profile.Interests
+			}
+		}
+		home.RecommendedScenario = recommendScenario(stats, interests, now)
+		return nil
+	})
+	section(HomeSectionLastThread, func() error {
+		summaries, err := s.threadRepo.FindSummariesByUserID(s.exec, user.ID)
+		if err != nil {
+			return err
+		}
+		home.LastActiveThread = lastActiveThread(summaries)
+		return nil
+	})
+	wg.Wait()
+
+	sort.Strings(home.Unavailable)
+	home.Motivation = s.motivation(ctx, user, now)
+	return home
+}
+
+// lastActiveThread returns the thread with the latest activity, leaving out
+// threads without messages; nil if there is none
+func lastActiveThread(summaries []models.ThreadSummary) *models.ThreadSummary {
+	var last *models.ThreadSummary
+	for i := range summaries {
+		if summaries[i].MessageCount == 0 {
+			continue
+		}
+		if last == nil || summaries[i].LastActivityAt.After(last.LastActivityAt) {
+			last = &summaries[i]
+		}
+	}
+	return last
+}
+
+// dueReviews returns the weakest phonemes, by the Anki deck's definition,
+// that haven't been practiced within the review interval
+func dueReviews(stats []models.PhonemeStats, now time.Time) []DueReview {
+	reviews := []DueReview{}
+	for _, st := range stats {
+		if st.TotalAttempts < AnkiMinAttempts || now.Sub(st.UpdatedAt) < homeReviewInterval {
+			continue
+		}
+		accuracy := float64(st.CorrectCount) / float64(st.TotalAttempts) * 100
+		if accuracy >= AnkiWeakAccuracy {
+			continue
+		}
+		reviews = append(reviews, DueReview{
+			Phoneme:       st.Phoneme,
+			Accuracy:      accuracy,
+			TotalAttempts: st.TotalAttempts,
+			LastPracticed: st.UpdatedAt,
+		})
+	}
+	sort.SliceStable(reviews, func(i, j int) bool { return reviews[i].Accuracy < reviews[j].Accuracy })
+	if len(reviews) > HomeMaxDueReviews {
+		reviews = reviews[:HomeMaxDueReviews]
+	}
+	return reviews
+}
+
+// recommendScenario picks today's scenario: the starter for learners with no
+// results yet, then one drilling their weakest phoneme, then one matching
+// their interests, and otherwise the catalog in daily rotation
+func recommendScenario(stats []models.PhonemeStats, interests []string, now time.Time) *RecommendedScenario {
+	var weakest *models.PhonemeStats
+	var weakestAccuracy float64
+	for i, st := range stats {
+		if st.TotalAttempts < AnkiMinAttempts {
+			continue
+		}
+		accuracy := float64(st.CorrectCount) / float64(st.TotalAttempts) * 100
+		if accuracy >= AnkiWeakAccuracy {
+			continue
+		}
+		if _, ok := scenarioForPhoneme(st.Phoneme); ok && (weakest == nil || accuracy < weakestAccuracy) {
+			weakest, weakestAccuracy = &stats[i], accuracy
+		}
+	}
+
+	switch {
+	case len(stats) == 0 && len(interests) == 0:
+		scenario, _ := scenarioByID(starterScenario)
+		return &RecommendedScenario{Scenario: scenario, Reason: ScenarioReasonStarter}
+	case weakest != nil:
+		scenario, _ := scenarioForPhoneme(weakest.Phoneme)
+		return &RecommendedScenario{Scenario: scenario, Reason: ScenarioReasonWeakPhoneme, Phoneme: weakest.Phoneme}
+	}
+	if scenario, ok := scenarioForInterests(interests); ok {
+		return &RecommendedScenario{Scenario: scenario, Reason: ScenarioReasonInterest}
+	}
+	return &RecommendedScenario{Scenario: Scenarios[dayNumber(now)%len(Scenarios)], Reason: ScenarioReasonDaily}
+}
+
+// motivation returns the user's message for the day, generating it if
+// needed. If the LLM is slow the request gets a canned message and the
+// generated one is kept for later requests.
+func (s *HomeService) motivation(ctx context.Context, user *models.User, now time.Time) string {
+	day := now.Format(time.DateOnly)
+	fallback := fallbackMotivations[dayNumber(now)%len(fallbackMotivations)]
+	if s.openAI == nil {
+		return fallback
+	}
+
+	s.mu.Lock()
+	if m, ok := s.motivations[user.ID]; ok && m.day == day {
+		s.mu.Unlock()
+		return m.text
+	}
+	done, ok := s.generating[user.ID]
+	if !ok {
+		done = make(chan struct{})
+		s.generating[user.ID] = done
+		go s.generateMotivation(user, day, done)
+	}
+	s.mu.Unlock()
+
+	timer := time.NewTimer(homeMotivationTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		return fallback
+	case <-ctx.Done():
+		return fallback
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m, ok := s.motivations[user.ID]; ok && m.day == day {
+		return m.text
+	}
+	return fallback
+}
+
+// generateMotivation asks the LLM for the day's message and keeps it.
+// Messages from earlier days are dropped on the way.
+func (s *HomeService) generateMotivation(user *models.User, day string, done chan struct{}) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.generating, user.ID)
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	prompt := "The learner's name is " + user.Name + "."
+	if user.Name == "" {
+		prompt = "The learner hasn't told us their name."
+	}
+	text, err := s.openAI.Generate([]client.ConversationMessage{
+		{Role: "system", Content: motivationPrompt},
+		{Role: "user", Content: prompt},
+	})
+	text = strings.Trim(strings.TrimSpace(text), `"`)
+	if err != nil || text == "" {
+		log.Printf("[Home] Failed to generate motivation for user %s: %v", user.ID, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, m := range s.motivations {
+		if m.day != day {
+			delete(s.motivations, id)
+		}
+	}
+	s.motivations[user.ID] = dailyMotivation{day: day, text: text}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func (m *stubCredits) GetCredits(userID uuid.UUID) (*models.Credits, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Credits), args.Error(1)
+}
+
+// stubMemory stubs LearnerMemory.GetProfile.
+type stubMemory struct {
+	LearnerMemory
+	mock.Mock
+}
+
+func (m *stubMemory) GetProfile(userID uuid.UUID) (*models.LearnerProfile, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LearnerProfile), args.Error(1)
+}
+
+type homeTestDeps struct {
+	threadRepo  *repomocks.MockThreadRepository
+	messageRepo *repomocks.MockMessageRepository
+	statsRepo   *repomocks.MockPhonemeStatsRepository
+	credits     *stubCredits
+	memory      *stubMemory
+	openAI      *clientmocks.MockOpenAIClient
+}
+
+func newHomeServiceWithMocks(now time.Time) (*HomeService, *homeTestDeps) {
+	deps := &homeTestDeps{
+		threadRepo:  new(repomocks.MockThreadRepository),
+		messageRepo: new(repomocks.MockMessageRepository),
+		statsRepo:   new(repomocks.MockPhonemeStatsRepository),
+		credits:     new(stubCredits),
+		memory:      new(stubMemory),
+		openAI:      new(clientmocks.MockOpenAIClient),
+	}
+	service := NewHomeServiceForTest(nil, deps.threadRepo, deps.messageRepo, deps.statsRepo, deps.credits, deps.memory, deps.openAI)
+	service.now = func() time.Time { return now }
+	return service, deps
+}
+
+func TestHomeService_GetHome_ColdStart(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Name: "Ana"}
+	service, deps := newHomeServiceWithMocks(now)
+
+	deps.messageRepo.On("FindActiveDaysByUserID", mock.Anything, user.ID, mock.Anything, time.UTC).Return([]time.Time{}, nil)
+	deps.credits.On("GetCredits", user.ID).Return(&models.Credits{Balance: 20, MonthlyAllowance: 20}, nil)
+	deps.statsRepo.On("FindByUserID", mock.Anything, user.ID, "en").Return([]models.PhonemeStats{}, nil).Once()
+	deps.memory.On("GetProfile", user.ID).Return(&models.LearnerProfile{UserID: user.ID}, nil)
+	deps.threadRepo.On("FindSummariesByUserID", mock.Anything, user.ID).Return([]models.ThreadSummary{}, nil)
+	deps.openAI.On("Generate", mock.Anything).Return(`"Ready for your first conversation, Ana?"`, nil).Once()
+
+	home := service.GetHome(context.Background(), user)
+
+	assert.Empty(t, home.Unavailable)
+	require.NotNil(t, home.Streak)
+	assert.Zero(t, home.Streak.Days)
+	assert.Equal(t, &HomeCredits{Balance: 20, MonthlyAllowance: 20}, home.Credits)
+	require.NotNil(t, home.RecommendedScenario)
+	assert.Equal(t, starterScenario, home.RecommendedScenario.ID)
+	assert.Equal(t, ScenarioReasonStarter, home.RecommendedScenario.Reason)
+	assert.Empty(t, home.DueReviews)
+	assert.Nil(t, home.LastActiveThread)
+	assert.Equal(t, "Ready for your first conversation, Ana?", home.Motivation)
+
+	// The message is generated once a day
+	home = service.GetHome(context.Background(), user)
+	assert.Equal(t, "Ready for your first conversation, Ana?", home.Motivation)
+	deps.openAI.AssertNumberOfCalls(t, "Generate", 1)
+	deps.statsRepo.AssertNumberOfCalls(t, "FindByUserID", 1)
+}
+
+func TestHomeService_GetHome_Personalized(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New()}
+	service, deps := newHomeServiceWithMocks(now)
+
+	stats := []models.PhonemeStats{
+		{Phoneme: "θ", TotalAttempts: 10, CorrectCount: 4, UpdatedAt: now.Add(-48 * time.Hour)},
+		{Phoneme: "r", TotalAttempts: 10, CorrectCount: 6, UpdatedAt: now.Add(-time.Hour)},
+		{Phoneme: "s", TotalAttempts: 10, CorrectCount: 10, UpdatedAt: now.Add(-48 * time.Hour)},
+		{Phoneme: "ʒ", TotalAttempts: 2, CorrectCount: 0, UpdatedAt: now.Add(-48 * time.Hour)},
+	}
+	older := models.ThreadSummary{Thread: models.Thread{ID: uuid.New()}, MessageCount: 4, LastActivityAt: now.Add(-3 * time.Hour)}
+	newer := models.ThreadSummary{Thread: models.Thread{ID: uuid.New()}, MessageCount: 2, LastActivityAt: now.Add(-time.Hour)}
+	empty := models.ThreadSummary{Thread: models.Thread{ID: uuid.New()}, LastActivityAt: now}
+
+	deps.messageRepo.On("FindActiveDaysByUserID", mock.Anything, user.ID, mock.Anything, time.UTC).
+		Return([]time.Time{now.Truncate(24 * time.Hour), now.AddDate(0, 0, -1).Truncate(24 * time.Hour)}, nil)
+	deps.credits.On("GetCredits", user.ID).Return(&models.Credits{Balance: 3, MonthlyAllowance: 20}, nil)
+	deps.statsRepo.On("FindByUserID", mock.Anything, user.ID, "en").Return(stats, nil)
+	deps.memory.On("GetProfile", user.ID).Return(&models.LearnerProfile{UserID: user.ID, Interests: models.StringList{"travel"}}, nil)
+	deps.threadRepo.On("FindSummariesByUserID", mock.Anything, user.ID).Return([]models.ThreadSummary{older, empty, newer}, nil)
+	deps.openAI.On("Generate", mock.Anything).Return("", errors.New("rate limited"))
+
+	home := service.GetHome(context.Background(), user)
+
+	assert.Equal(t, &HomeStreak{Days: 2, PracticedToday: true}, home.Streak)
+	require.Len(t, home.DueReviews, 1, "r was practiced within the day and s isn't weak")
+	assert.Equal(t, "θ", home.DueReviews[0].Phoneme)
+	assert.InDelta(t, 40.0, home.DueReviews[0].Accuracy, 0.001)
+	require.NotNil(t, home.RecommendedScenario)
+	assert.Equal(t, ScenarioReasonWeakPhoneme, home.RecommendedScenario.Reason)
+	assert.Equal(t, "θ", home.RecommendedScenario.Phoneme)
+	assert.Equal(t, "job-interview", home.RecommendedScenario.ID)
+	require.NotNil(t, home.LastActiveThread)
+	assert.Equal(t, newer.ID, home.LastActiveThread.ID, "threads without messages aren't resumed")
+	assert.Contains(t, fallbackMotivations, home.Motivation)
+}
+
+func TestHomeService_GetHome_IsolatesFailures(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New()}
+	service, deps := newHomeServiceWithMocks(now)
+	service.openAI = nil
+
+	deps.messageRepo.On("FindActiveDaysByUserID", mock.Anything, user.ID, mock.Anything, time.UTC).Return([]time.Time{}, nil)
+	deps.credits.On("GetCredits", user.ID).Return(nil, ErrCreditsNotFound)
+	deps.statsRepo.On("FindByUserID", mock.Anything, user.ID, "en").Return([]models.PhonemeStats{}, nil)
+	deps.memory.On("GetProfile", user.ID).Return(nil, errors.New("connection reset"))
+	deps.threadRepo.On("FindSummariesByUserID", mock.Anything, user.ID).Return(nil, errors.New("connection reset"))
+
+	home := service.GetHome(context.Background(), user)
+
+	assert.Equal(t, []string{HomeSectionCredits, HomeSectionLastThread, HomeSectionScenario}, home.Unavailable)
+	assert.NotNil(t, home.Streak)
+	assert.NotNil(t, home.DueReviews)
+	assert.Nil(t, home.Credits)
+	assert.Nil(t, home.RecommendedScenario)
+	assert.NotEmpty(t, home.Motivation)
+}
+
+func TestRecommendScenario(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	practiced := []models.PhonemeStats{{Phoneme: "s", TotalAttempts: 10, CorrectCount: 10}}
+
+	interest := recommendScenario(practiced, []string{"Cooking Italian food"}, now)
+	assert.Equal(t, ScenarioReasonInterest, interest.Reason)
+	assert.Equal(t, "coffee-shop", interest.ID)
+
+	daily := recommendScenario(practiced, nil, now)
+	assert.Equal(t, ScenarioReasonDaily, daily.Reason)
+	tomorrow := recommendScenario(practiced, nil, now.AddDate(0, 0, 1))
+	assert.NotEqual(t, daily.ID, tomorrow.ID, "the daily suggestion rotates")
+}
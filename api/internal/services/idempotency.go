@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// idempotencyAbandonAfter is how long a request can hold its key before a
+// retry takes the key over, for requests whose instance died mid-way. It is
+// well past the slowest voice turn.
+const idempotencyAbandonAfter = 10 * time.Minute
+
+// idempotencyPurgeInterval is how often expired keys are deleted
+const idempotencyPurgeInterval = time.Hour
+
+var (
+	ErrIdempotencyKeyInUse  = errors.New("a request with this idempotency key is still being processed")
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+)
+
+// IdempotencyStore defines the interface for Idempotency-Key handling
+type IdempotencyStore interface {
+	Begin(userID uuid.UUID, key, requestPath, requestHash string) (*models.IdempotencyKey, error)
+	Complete(id uuid.UUID, status int, contentType string, body []byte) error
+	Release(id uuid.UUID) error
+}
+
+// IdempotencyService remembers requests sent with an Idempotency-Key header,
+// so a client retrying one after a timeout gets the original response instead
+// of a second charge and a duplicate message
+type IdempotencyService struct {
+	exec repository.Executor
+	repo repository.IdempotencyKeyRepository
+
+	now func() time.Time
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(database *db.DB, repo repository.IdempotencyKeyRepository) *IdempotencyService {
+	return NewIdempotencyServiceForTest(database.DB, repo, time.Now)
+}
+
+// NewIdempotencyServiceForTest creates an IdempotencyService with injected dependencies for testing.
+func NewIdempotencyServiceForTest(exec repository.Executor, repo repository.IdempotencyKeyRepository, now func() time.Time) *IdempotencyService {
+	return &IdempotencyService{
+		exec: exec,
+		repo: repo,
+		now:  now,
+	}
+}
+
+// Begin claims a key for a request. It returns the new key, ready to be
+// completed or released, or the key of an earlier request that already
+// completed, whose response should be replayed. A key still held by a
+// request in flight gives ErrIdempotencyKeyInUse; a key first sent to
+// another path, or with another body, gives ErrIdempotencyKeyReused.
+func (s *IdempotencyService) Begin(userID uuid.UUID, key, requestPath, requestHash string) (*models.IdempotencyKey, error) {
+	// Two tries: the second follows clearing out an expired or abandoned key
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestPath: requestPath,
+			RequestHash: requestHash,
+			CreatedAt:   s.now(),
+		}
+		reserved, err := s.repo.Reserve(s.exec, claimed)
+		if err != nil {
+			return nil, fmt.Errorf("reserve idempotency key: %w", err)
+		}
+		if reserved {
+			return claimed, nil
+		}
+
+		existing, err := s.repo.FindByUserAndKey(s.exec, userID, key)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue // Released in the meantime
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find idempotency key: %w", err)
+		}
+
+		if s.stale(existing) {
+			if err := s.repo.Delete(s.exec, existing.ID); err != nil {
+				return nil, fmt.Errorf("delete idempotency key: %w", err)
+			}
+			continue
+		}
+		if existing.RequestPath != requestPath {
+			return nil, ErrIdempotencyKeyReused
+		}
+		if existing.RequestHash != "" && existing.RequestHash != requestHash {
+			return nil, ErrIdempotencyKeyReused
+		}
+		if !existing.Completed() {
+			return nil, ErrIdempotencyKeyInUse
+		}
+		return existing, nil
+	}
+	return nil, ErrIdempotencyKeyInUse
+}
+
+// stale reports whether a key has expired, or was claimed by a request that
+// never finished
+func (s *IdempotencyService) stale(key *models.IdempotencyKey) bool {
+	age := s.now().Sub(key.CreatedAt)
+	if key.Completed() {
+		return age > models.IdempotencyKeyLifetime
+	}
+	return age > idempotencyAbandonAfter
+}
+
+// Complete stores the response of a request that claimed its key
+func (s *IdempotencyService) Complete(id uuid.UUID, status int, contentType string, body []byte) error {
+	return s.repo.Complete(s.exec, id, status, contentType, body, s.now())
+}
+
+// Release gives up a key whose request failed, so a retry is processed again
+func (s *IdempotencyService) Release(id uuid.UUID) error {
+	return s.repo.Delete(s.exec, id)
+}
+
+// Start deletes expired keys until ctx is cancelled
+func (s *IdempotencyService) Start(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.Purge()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes keys older than their lifetime and returns how many there were
+func (s *IdempotencyService) Purge() int64 {
+	deleted, err := s.repo.DeleteCreatedBefore(s.exec, s.now().Add(-models.IdempotencyKeyLifetime))
+	if err != nil {
+		log.Printf("[Idempotency] Failed to purge expired keys: %v", err)
+		return 0
+	}
+	if deleted > 0 {
+		log.Printf("[Idempotency] Purged %d expired keys", deleted)
+	}
+	return deleted
+}
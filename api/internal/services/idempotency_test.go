@@ -0,0 +1,148 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	path := "/api/threads/abc/messages/audio"
+	hash := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	completedAt := now.Add(-time.Minute)
+	completed := func(createdAt time.Time) *models.IdempotencyKey {
+		return &models.IdempotencyKey{
+			ID: uuid.New(), UserID: userID, Key: "k1", RequestPath: path, RequestHash: hash,
+			ResponseStatus: 201, ResponseBody: []byte(`{"id":"m1"}`),
+			CreatedAt: createdAt, CompletedAt: &completedAt,
+		}
+	}
+	pending := func(createdAt time.Time) *models.IdempotencyKey {
+		return &models.IdempotencyKey{ID: uuid.New(), UserID: userID, Key: "k1", RequestPath: path, RequestHash: hash, CreatedAt: createdAt}
+	}
+
+	t.Run("claims a new key", func(t *testing.T) {
+		repo := new(repomocks.MockIdempotencyKeyRepository)
+		repo.On("Reserve", mock.Anything, mock.Anything).Return(true, nil)
+		service := NewIdempotencyServiceForTest(nil, repo, func() time.Time { return now })
+
+		key, err := service.Begin(userID, "k1", path, hash)
+
+		require.NoError(t, err)
+		assert.False(t, key.Completed())
+		assert.Equal(t, path, key.RequestPath)
+		assert.Equal(t, hash, key.RequestHash)
+		assert.Equal(t, now, key.CreatedAt)
+	})
+
+	t.Run("returns a completed key for replay", func(t *testing.T) {
+		repo := new(repomocks.MockIdempotencyKeyRepository)
+		existing := completed(now.Add(-time.Hour))
+		repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+		repo.On("FindByUserAndKey", mock.Anything, userID, "k1").Return(existing, nil)
+		service := NewIdempotencyServiceForTest(nil, repo, func() time.Time { return now })
+
+		key, err := service.Begin(userID, "k1", path, hash)
+
+		require.NoError(t, err)
+		assert.Equal(t, existing, key)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("takes over an expired or abandoned key", func(t *testing.T) {
+		for _, existing := range []*models.IdempotencyKey{
+			completed(now.Add(-models.IdempotencyKeyLifetime - time.Minute)),
+			pending(now.Add(-idempotencyAbandonAfter - time.Minute)),
+		} {
+			repo := new(repomocks.MockIdempotencyKeyRepository)
+			repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil).Once()
+			repo.On("FindByUserAndKey", mock.Anything, userID, "k1").Return(existing, nil).Once()
+			repo.On("Delete", mock.Anything, existing.ID).Return(nil)
+			repo.On("Reserve", mock.Anything, mock.Anything).Return(true, nil).Once()
+			service := NewIdempotencyServiceForTest(nil, repo, func() time.Time { return now })
+
+			key, err := service.Begin(userID, "k1", path, hash)
+
+			require.NoError(t, err)
+			assert.False(t, key.Completed())
+			repo.AssertExpectations(t)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		existing *models.IdempotencyKey
+		path     string
+		hash     string
+		wantErr  error
+	}{
+		{name: "still in flight", existing: pending(now.Add(-time.Minute)), path: path, hash: hash, wantErr: ErrIdempotencyKeyInUse},
+		{name: "sent to another path", existing: completed(now.Add(-time.Minute)), path: "/api/threads/xyz/messages/audio", hash: hash, wantErr: ErrIdempotencyKeyReused},
+		{name: "sent with another body", existing: completed(now.Add(-time.Minute)), path: path, hash: "other", wantErr: ErrIdempotencyKeyReused},
+		{name: "in flight with another body", existing: pending(now.Add(-time.Minute)), path: path, hash: "other", wantErr: ErrIdempotencyKeyReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(repomocks.MockIdempotencyKeyRepository)
+			repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+			repo.On("FindByUserAndKey", mock.Anything, userID, "k1").Return(tt.existing, nil)
+			service := NewIdempotencyServiceForTest(nil, repo, func() time.Time { return now })
+
+			_, err := service.Begin(userID, "k1", tt.path, tt.hash)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("released between reserve and lookup", func(t *testing.T) {
+		repo := new(repomocks.MockIdempotencyKeyRepository)
+		repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil).Once()
+		repo.On("FindByUserAndKey", mock.Anything, userID, "k1").Return(nil, repository.ErrNotFound).Once()
+		repo.On("Reserve", mock.Anything, mock.Anything).Return(true, nil).Once()
+		service := NewIdempotencyServiceForTest(nil, repo, func() time.Time { return now })
+
+		_, err := service.Begin(userID, "k1", path, hash)
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestIdempotencyService_Purge(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(repomocks.MockIdempotencyKeyRepository)
+	repo.On("DeleteCreatedBefore", mock.Anything, now.Add(-models.IdempotencyKeyLifetime)).Return(int64(3), nil)
+	service := NewIdempotencyServiceForTest(nil, repo, func() time.Time { return now })
+
+	assert.Equal(t, int64(3), service.Purge())
+}
+
+func TestIdempotencyService_Begin_ReplaysKeysStoredWithoutHash(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	completedAt := now.Add(-time.Minute)
+	existing := &models.IdempotencyKey{
+		ID: uuid.New(), UserID: userID, Key: "k1", RequestPath: "/api/threads/abc/messages/audio",
+		ResponseStatus: 200, CreatedAt: now.Add(-time.Hour), CompletedAt: &completedAt,
+	}
+	repo := new(repomocks.MockIdempotencyKeyRepository)
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+	repo.On("FindByUserAndKey", mock.Anything, userID, "k1").Return(existing, nil)
+	service := NewIdempotencyServiceForTest(nil, repo, func() time.Time { return now })
+
+	key, err := service.Begin(userID, "k1", existing.RequestPath, "any")
+
+	require.NoError(t, err)
+	assert.Equal(t, existing, key)
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/services/auth"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInviteRequired    = errors.New("an invite code is required to sign up")
+	ErrInvalidInviteCode = errors.New("invite code is invalid, used up or expired")
+	ErrAlreadyInvited    = errors.New("waitlist entry has already been invited")
+	ErrInvalidInvite     = errors.New("invalid invite")
+)
+
+// Audit log actions for invites
+const (
+	AuditActionInviteMinted    = "invite.minted"
+	AuditActionWaitlistInvited = "waitlist.invited"
+)
+
+// Invite code format: unambiguous characters only (no 0/O, 1/I/L), so codes
+// survive being read aloud or retyped
+const (
+	inviteCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+	inviteCodeLength   = 10
+	MaxInviteCodeUses  = 1000
+)
+
+// MintInviteOptions configures a new invite code
+type MintInviteOptions struct {
+	Uses      int        // defaults to 1
+	ExpiresAt *time.Time // nil never expires
+	Note      string     // who or what the code is for
+}
+
+// InviteManager defines the interface for invite-only signups and the waitlist
+type InviteManager interface {
+	InviteOnly() bool
+	JoinWaitlist(email string) error
+	Mint(actor uuid.UUID, opts MintInviteOptions) (*models.InviteCode, error)
+	ListInvites(limit int) ([]models.InviteCode, error)
+	Waitlist(pendingOnly bool, limit int) ([]models.WaitlistEntry, error)
+	InviteFromWaitlist(actor, entryID uuid.UUID, expiresAt *time.Time) (*models.WaitlistEntry, *models.InviteCode, error)
+}
+
+// InviteService gates registration behind invite codes while signups are
+// invite-only, and keeps the waitlist of people asking to join
+type InviteService struct {
+	exec         repository.Executor
+	txRunner     TxRunner
+	codeRepo     repository.InviteCodeRepository
+	waitlistRepo repository.WaitlistRepository
+	audit        AuditLogger
+	inviteOnly   bool
+
+	now func() time.Time
+}
+
+// NewInviteService creates a new invite service
+func NewInviteService(
+	database *db.DB,
+	codeRepo repository.InviteCodeRepository,
+	waitlistRepo repository.WaitlistRepository,
+	audit AuditLogger,
+	inviteOnly bool,
+) *InviteService {
+	return &InviteService{
+		exec:         database.DB,
+		txRunner:     database.DB,
+		codeRepo:     codeRepo,
+		waitlistRepo: waitlistRepo,
+		audit:        audit,
+		inviteOnly:   inviteOnly,
+		now:          time.Now,
+	}
+}
+
+// NewInviteServiceForTest creates an InviteService with injected dependencies for testing.
+func NewInviteServiceForTest(
+	exec repository.Executor,
+	txRunner TxRunner,
+	codeRepo repository.InviteCodeRepository,
+	waitlistRepo repository.WaitlistRepository,
+	audit AuditLogger,
+	inviteOnly bool,
+	now func() time.Time,
+) *InviteService {
+	return &InviteService{
+		exec:         exec,
+		txRunner:     txRunner,
+		codeRepo:     codeRepo,
+		waitlistRepo: waitlistRepo,
+		audit:        audit,
+		inviteOnly:   inviteOnly,
+		now:          now,
+	}
+}
+
+// InviteOnly reports whether new accounts need an invite code
+func (s *InviteService) InviteOnly() bool {
+	return s.inviteOnly
+}
+
+// Gate wraps a credits initializer so that, while signups are invite-only,
+// creating an account uses up one use of code in the same transaction. A
+// failed signup doesn't spend the code; existing accounts never reach it.
+func (s *InviteService) Gate(code string, next auth.CreditsInitializer) auth.CreditsInitializer {
+	if !s.inviteOnly {
+		return next
+	}
+	return &inviteGate{invites: s, code: NormalizeInviteCode(code), next: next}
+}
+
+type inviteGate struct {
+	invites *InviteService
+	code    string
+	next    auth.CreditsInitializer
+}
+
+func (g *inviteGate) InitializeCreditsWithTx(exec repository.Executor, userID uuid.UUID, tier models.SubscriptionTier) error {
+	if g.code == "" {
+		return ErrInviteRequired
+	}
+	err := g.invites.codeRepo.Redeem(exec, g.code, g.invites.now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidInviteCode
+	}
+	if err != nil {
+		return fmt.Errorf("failed to redeem invite code: %w", err)
+	}
+	return g.next.InitializeCreditsWithTx(exec, userID, tier)
+}
+
+// NormalizeInviteCode accepts codes as people type them: any case, with
+// spaces or dashes
+func NormalizeInviteCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// JoinWaitlist records an email on the waitlist. Joining twice is not an
+// error, so the endpoint doesn't reveal who is already on the list.
+func (s *InviteService) JoinWaitlist(email string) error {
+	entry := &models.WaitlistEntry{Email: strings.ToLower(strings.TrimSpace(email))}
+	if err := s.waitlistRepo.Create(s.exec, entry); err != nil {
+		return fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	return nil
+}
+
+// Mint creates a new invite code
+func (s *InviteService) Mint(actor uuid.UUID, opts MintInviteOptions) (*models.InviteCode, error) {
+	invite, err := s.mintWithTx(s.exec, actor, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.record(AuditActionInviteMinted, actor, models.JSONMap{
+		"inviteId": invite.ID.String(),
+		"uses":     invite.UsesRemaining,
+		"note":     invite.Note,
+	})
+	return invite, nil
+}
+
+func (s *InviteService) mintWithTx(exec repository.Executor, actor uuid.UUID, opts MintInviteOptions) (*models.InviteCode, error) {
+	if opts.Uses == 0 {
+		opts.Uses = 1
+	}
+	if opts.Uses < 0 || opts.Uses > MaxInviteCodeUses {
+		return nil, fmt.Errorf("%w: uses must be between 1 and %d", ErrInvalidInvite, MaxInviteCodeUses)
+	}
+	if opts.ExpiresAt != nil && !opts.ExpiresAt.After(s.now()) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", ErrInvalidInvite)
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	invite := &models.InviteCode{
+		Code:          code,
+		UsesRemaining: opts.Uses,
+		ExpiresAt:     opts.ExpiresAt,
+		Note:          opts.Note,
+		CreatedBy:     &actor,
+	}
+	if err := s.codeRepo.Create(exec, invite); err != nil {
+		return nil, fmt.Errorf("failed to create invite code: %w", err)
+	}
+	return invite, nil
+}
+
+// ListInvites returns the most recently minted invite codes
+func (s *InviteService) ListInvites(limit int) ([]models.InviteCode, error) {
+	invites, err := s.codeRepo.FindAll(s.exec, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invite codes: %w", err)
+	}
+	return invites, nil
+}
+
+// Waitlist returns waitlist entries, oldest first
+func (s *InviteService) Waitlist(pendingOnly bool, limit int) ([]models.WaitlistEntry, error) {
+	entries, err := s.waitlistRepo.FindAll(s.exec, pendingOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist: %w", err)
+	}
+	return entries, nil
+}
+
+// InviteFromWaitlist mints a single-use code for a waitlist entry and marks
+// it invited. The code is returned for the admin to send on.
+func (s *InviteService) InviteFromWaitlist(actor, entryID uuid.UUID, expiresAt *time.Time) (*models.WaitlistEntry, *models.InviteCode, error) {
+	var entry *models.WaitlistEntry
+	var invite *models.InviteCode
+	err := s.txRunner.Transaction(func(tx *gorm.DB) error {
+		var err error
+		entry, err = s.waitlistRepo.FindByID(tx, entryID)
+		if err != nil {
+			return err
+		}
+		if entry.InvitedAt != nil {
+			return ErrAlreadyInvited
+		}
+
+		invite, err = s.mintWithTx(tx, actor, MintInviteOptions{Uses: 1, ExpiresAt: expiresAt, Note: entry.Email})
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		entry.InviteCodeID = &invite.ID
+		entry.InvitedAt = &now
+		if err := s.waitlistRepo.Save(tx, entry); err != nil {
+			return fmt.Errorf("failed to update waitlist entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.record(AuditActionWaitlistInvited, actor, models.JSONMap{
+		"waitlistId": entry.ID.String(),
+		"inviteId":   invite.ID.String(),
+	})
+	return entry, invite, nil
+}
+
+func (s *InviteService) record(action string, actor uuid.UUID, details models.JSONMap) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(&models.AuditLog{
+		Action:  action,
+		Actor:   actor.String(),
+		Outcome: models.AuditOutcomeSuccess,
+		Details: details,
+	})
+}
+
+// generateInviteCode returns a random code from inviteCodeAlphabet
+func generateInviteCode() (string, error) {
+	b := make([]byte, inviteCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(inviteCodeAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate invite code: %w", err)
+		}
+		b[i] = inviteCodeAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
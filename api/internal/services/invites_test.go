@@ -0,0 +1,146 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestInviteService_Gate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	newCredits := func() (*CreditsService, *repomocks.MockCreditsRepository) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		return NewCreditsServiceForTest(nil, nil, creditsRepo, nil, nil), creditsRepo
+	}
+
+	t.Run("open signups skip the check", func(t *testing.T) {
+		credits, _ := newCredits()
+		svc := NewInviteServiceForTest(nil, nil, nil, nil, nil, false, func() time.Time { return now })
+		assert.Same(t, credits, svc.Gate("", credits))
+	})
+
+	t.Run("requires a code", func(t *testing.T) {
+		credits, creditsRepo := newCredits()
+		svc := NewInviteServiceForTest(nil, nil, nil, nil, nil, true, func() time.Time { return now })
+
+		err := svc.Gate("  ", credits).InitializeCreditsWithTx(nil, userID, models.TierFree)
+		assert.ErrorIs(t, err, ErrInviteRequired)
+		creditsRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("redeems the normalized code before creating credits", func(t *testing.T) {
+		credits, creditsRepo := newCredits()
+		codeRepo := new(repomocks.MockInviteCodeRepository)
+		codeRepo.On("Redeem", mock.Anything, "ABCD2345EF", now).Return(nil)
+		creditsRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		svc := NewInviteServiceForTest(nil, nil, codeRepo, nil, nil, true, func() time.Time { return now })
+		require.NoError(t, svc.Gate("abcd-2345 ef", credits).InitializeCreditsWithTx(nil, userID, models.TierFree))
+		codeRepo.AssertExpectations(t)
+		creditsRepo.AssertExpectations(t)
+	})
+
+	t.Run("used up or expired code", func(t *testing.T) {
+		credits, creditsRepo := newCredits()
+		codeRepo := new(repomocks.MockInviteCodeRepository)
+		codeRepo.On("Redeem", mock.Anything, "SPENT", now).Return(repository.ErrNotFound)
+
+		svc := NewInviteServiceForTest(nil, nil, codeRepo, nil, nil, true, func() time.Time { return now })
+		err := svc.Gate("spent", credits).InitializeCreditsWithTx(nil, userID, models.TierFree)
+		assert.ErrorIs(t, err, ErrInvalidInviteCode)
+		creditsRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestInviteService_Mint(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	adminID := uuid.New()
+
+	t.Run("defaults to one use", func(t *testing.T) {
+		codeRepo := new(repomocks.MockInviteCodeRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		codeRepo.On("Create", mock.Anything, mock.MatchedBy(func(i *models.InviteCode) bool {
+			return i.UsesRemaining == 1 && len(i.Code) == inviteCodeLength && *i.CreatedBy == adminID
+		})).Return(nil)
+		auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditLog) bool {
+			return e.Action == AuditActionInviteMinted
+		})).Return(nil)
+
+		svc := NewInviteServiceForTest(nil, nil, codeRepo, nil, NewAuditServiceForTest(nil, auditRepo), true, func() time.Time { return now })
+		invite, err := svc.Mint(adminID, MintInviteOptions{Note: "beta testers"})
+		require.NoError(t, err)
+		for _, r := range invite.Code {
+			assert.True(t, strings.ContainsRune(inviteCodeAlphabet, r), "unexpected character %q", r)
+		}
+		codeRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects past expiry", func(t *testing.T) {
+		past := now.Add(-time.Hour)
+		svc := NewInviteServiceForTest(nil, nil, nil, nil, nil, true, func() time.Time { return now })
+		_, err := svc.Mint(adminID, MintInviteOptions{ExpiresAt: &past})
+		assert.ErrorIs(t, err, ErrInvalidInvite)
+	})
+}
+
+func TestInviteService_InviteFromWaitlist(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	adminID := uuid.New()
+	entryID := uuid.New()
+
+	t.Run("mints a single-use code and marks the entry", func(t *testing.T) {
+		txRunner := new(mockTxRunner)
+		codeRepo := new(repomocks.MockInviteCodeRepository)
+		waitlistRepo := new(repomocks.MockWaitlistRepository)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		waitlistRepo.On("FindByID", mock.Anything, entryID).Return(&models.WaitlistEntry{ID: entryID, Email: "learner@example.com"}, nil)
+		codeRepo.On("Create", mock.Anything, mock.MatchedBy(func(i *models.InviteCode) bool {
+			return i.UsesRemaining == 1 && i.Note == "learner@example.com"
+		})).Return(nil)
+		waitlistRepo.On("Save", mock.Anything, mock.MatchedBy(func(e *models.WaitlistEntry) bool {
+			return e.InvitedAt != nil && e.InvitedAt.Equal(now) && e.InviteCodeID != nil
+		})).Return(nil)
+
+		svc := NewInviteServiceForTest(nil, txRunner, codeRepo, waitlistRepo, nil, true, func() time.Time { return now })
+		entry, invite, err := svc.InviteFromWaitlist(adminID, entryID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, invite.ID, *entry.InviteCodeID)
+		codeRepo.AssertExpectations(t)
+		waitlistRepo.AssertExpectations(t)
+	})
+
+	t.Run("already invited", func(t *testing.T) {
+		invitedAt := now.Add(-time.Hour)
+		txRunner := new(mockTxRunner)
+		waitlistRepo := new(repomocks.MockWaitlistRepository)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		waitlistRepo.On("FindByID", mock.Anything, entryID).Return(&models.WaitlistEntry{ID: entryID, InvitedAt: &invitedAt}, nil)
+
+		svc := NewInviteServiceForTest(nil, txRunner, nil, waitlistRepo, nil, true, func() time.Time { return now })
+		_, _, err := svc.InviteFromWaitlist(adminID, entryID, nil)
+		assert.ErrorIs(t, err, ErrAlreadyInvited)
+	})
+
+	t.Run("unknown entry", func(t *testing.T) {
+		txRunner := new(mockTxRunner)
+		waitlistRepo := new(repomocks.MockWaitlistRepository)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		waitlistRepo.On("FindByID", mock.Anything, entryID).Return(nil, repository.ErrNotFound)
+
+		svc := NewInviteServiceForTest(nil, txRunner, nil, waitlistRepo, nil, true, func() time.Time { return now })
+		_, _, err := svc.InviteFromWaitlist(adminID, entryID, nil)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+)
+
+// DefaultLanguage is the language of threads that haven't set one
+const DefaultLanguage = "en"
+
+var (
+	ErrInvalidLanguage        = errors.New("unsupported language")
+	ErrLocaleLanguageMismatch = errors.New("locale is for another language")
+	ErrThreadLanguageLocked   = errors.New("thread language can't change once it has messages")
+)
+
+// Language is a language learners can practise, with what each stage of
+// the voice pipeline calls it
+type Language struct {
+	Name     string // In the tutor's instructions, e.g. "Spanish"
+	Locale   string // How numbers and dates are read aloud when the thread sets no locale
+	Phonemes string // What pronunciation is scored in, a gruut language code
+}
+
+// Languages are the ones supported end to end, by ISO 639-1 code, which is
+// also what Whisper and the TTS voices are chosen by. Adding a language means
+// adding an entry (and a speech normalizer for it, if it has one).
+var Languages = map[string]Language{
+	"en": {Name: "English", Locale: DefaultLocale, Phonemes: PronunciationLanguage},
+	"es": {Name: "Spanish", Locale: "es-ES", Phonemes: "es-es"},
+	"fr": {Name: "French", Locale: "fr-FR", Phonemes: "fr-fr"},
+	"de": {Name: "German", Locale: "de-DE", Phonemes: "de-de"},
+}
+
+// ValidLanguage reports whether code is one of Languages
+func ValidLanguage(code string) bool {
+	_, ok := Languages[code]
+	return ok
+}
+
+// LanguageCodes lists the supported language codes in order
+func LanguageCodes() []string {
+	codes := make([]string, 0, len(Languages))
+	for code := range Languages {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// LocaleMatchesLanguage reports whether locale, such as "es-MX", is a
+// variant of language
+func LocaleMatchesLanguage(locale, language string) bool {
+	primary, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return strings.EqualFold(primary, language)
+}
+
+// ThreadLanguage returns the language code the thread is practised in; a nil
+// thread, or one that hasn't set a language, uses DefaultLanguage
+func ThreadLanguage(thread *models.Thread) string {
+	if thread == nil || !ValidLanguage(thread.Language) {
+		return DefaultLanguage
+	}
+	return thread.Language
+}
+
+// TranscriptionLanguage returns the language to transcribe the thread's
+// recordings in, or "" to let Whisper detect it for threads that haven't
+// chosen one
+func TranscriptionLanguage(thread *models.Thread) string {
+	if thread == nil || !ValidLanguage(thread.Language) {
+		return ""
+	}
+	return thread.Language
+}
+
+// ThreadLocale returns the locale the thread's replies are read aloud in:
+// its own, or its language's
+func ThreadLocale(thread *models.Thread) string {
+	if thread != nil && thread.Locale != "" {
+		return thread.Locale
+	}
+	return Languages[ThreadLanguage(thread)].Locale
+}
+
+// PhonemeLanguage returns the code pronunciation is scored in for a thread
+// language
+func PhonemeLanguage(language string) string {
+	if lang, ok := Languages[language]; ok {
+		return lang.Phonemes
+	}
+	return PronunciationLanguage
+}
+
+// LanguageSystemPrompt tells the tutor which language to speak, or returns
+// nil for English, which it speaks unprompted
+func LanguageSystemPrompt(language string) *client.ConversationMessage {
+	if language == DefaultLanguage || !ValidLanguage(language) {
+		return nil
+	}
+	return &client.ConversationMessage{
+		Role: "system",
+		Content: fmt.Sprintf("The learner is practising %s. Always reply in %s, even when they write or speak in another language, "+
+			"keeping to vocabulary and grammar a learner can follow.", Languages[language].Name, Languages[language].Name),
+	}
+}
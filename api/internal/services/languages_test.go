@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+)
+
+func TestThreadLanguage(t *testing.T) {
+	tests := []struct {
+		name              string
+		thread            *models.Thread
+		wantLanguage      string
+		wantTranscription string
+		wantLocale        string
+		wantPhonemes      string
+	}{
+		{"no thread", nil, "en", "", "en-US", "en-us"},
+		{"thread without a language", &models.Thread{Locale: "en-GB"}, "en", "", "en-GB", "en-us"},
+		{"spanish thread", &models.Thread{Language: "es"}, "es", "es", "es-ES", "es-es"},
+		{"spanish thread with a locale", &models.Thread{Language: "es", Locale: "es-MX"}, "es", "es", "es-MX", "es-es"},
+		{"unsupported language", &models.Thread{Language: "xx"}, "en", "", "en-US", "en-us"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLanguage, ThreadLanguage(tt.thread))
+			assert.Equal(t, tt.wantTranscription, TranscriptionLanguage(tt.thread))
+			assert.Equal(t, tt.wantLocale, ThreadLocale(tt.thread))
+			assert.Equal(t, tt.wantPhonemes, PhonemeLanguage(ThreadLanguage(tt.thread)))
+		})
+	}
+}
+
+func TestLocaleMatchesLanguage(t *testing.T) {
+	assert.True(t, LocaleMatchesLanguage("es-MX", "es"))
+	assert.True(t, LocaleMatchesLanguage("FR_ca", "fr"))
+	assert.True(t, LocaleMatchesLanguage("de", "de"))
+	assert.False(t, LocaleMatchesLanguage("es-MX", "en"))
+	assert.False(t, LocaleMatchesLanguage("", "en"))
+}
+
+func TestLanguageSystemPrompt(t *testing.T) {
+	assert.Nil(t, LanguageSystemPrompt("en"))
+
+	prompt := LanguageSystemPrompt("de")
+	require.NotNil(t, prompt)
+	assert.Equal(t, "system", prompt.Role)
+	assert.Contains(t, prompt.Content, "Always reply in German")
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidLearnerProfile = errors.New("invalid learner profile")
+
+// Learner memory limits. Facts are extracted once a thread has
+// LearnerMemoryMinMessages user messages the profile hasn't seen yet.
+const (
+	LearnerMemoryMinMessages   = 6
+	learnerMemoryMaxHistory    = 40
+	MaxLearnerProfileItems     = 10
+	MaxLearnerProfileItemLen   = 100
+	MaxLearnerPreferredNameLen = 50
+)
+
+// LearnerProfileUpdate edits a learner profile. Nil fields are left as they
+// are; turning memory off forgets everything remembered so far.
+type LearnerProfileUpdate struct {
+	Enabled           *bool
+	PreferredName     *string
+	Interests         *[]string
+	RecurringMistakes *[]string
+}
+
+// LearnerMemory defines the interface for what the assistant remembers about a learner
+type LearnerMemory interface {
+	GetProfile(userID uuid.UUID) (*models.LearnerProfile, error)
+	UpdateProfile(userID uuid.UUID, update LearnerProfileUpdate) (*models.LearnerProfile, error)
+	SystemPrompt(userID uuid.UUID) *client.ConversationMessage
+	RequestExtraction(userID, threadID uuid.UUID)
+}
+
+// LearnerProfileService keeps the learner's name, interests and recurring
+// mistakes across threads. Facts are extracted from conversations in the
+// background, deduplicated per user, and added to the assistant's system
+// prompt unless the learner turned memory off.
+type LearnerProfileService struct {
+	exec        repository.Executor
+	profileRepo repository.LearnerProfileRepository
+	messageRepo repository.MessageRepository
+	openAI      client.OpenAIClient
+	queue       *jobs.Queue
+
+	mu       sync.Mutex
+	inflight map[uuid.UUID]bool
+
+	now func() time.Time
+}
+
+// NewLearnerProfileService creates a new learner profile service
+func NewLearnerProfileService(
+	database *db.DB,
+	profileRepo repository.LearnerProfileRepository,
+	messageRepo repository.MessageRepository,
+	openAI client.OpenAIClient,
+	queue *jobs.Queue,
+) *LearnerProfileService {
+	return &LearnerProfileService{
+		exec:        database.DB,
+		profileRepo: profileRepo,
+		messageRepo: messageRepo,
+		openAI:      openAI,
+		queue:       queue,
+		inflight:    make(map[uuid.UUID]bool),
+		now:         time.Now,
+	}
+}
+
+// NewLearnerProfileServiceForTest creates a LearnerProfileService with injected dependencies for testing.
+func NewLearnerProfileServiceForTest(
+	exec repository.Executor,
+	profileRepo repository.LearnerProfileRepository,
+	messageRepo repository.MessageRepository,
+	openAI client.OpenAIClient,
+	queue *jobs.Queue,
+	now func() time.Time,
+) *LearnerProfileService {
+	return &LearnerProfileService{
+		exec:        exec,
+		profileRepo: profileRepo,
+		messageRepo: messageRepo,
+		openAI:      openAI,
+		queue:       queue,
+		inflight:    make(map[uuid.UUID]bool),
+		now:         now,
+	}
+}
+
+// GetProfile returns the learner's profile, or an empty one with memory on
+// if nothing has been remembered yet
+func (s *LearnerProfileService) GetProfile(userID uuid.UUID) (*models.LearnerProfile, error) {
+	profile, err := s.profileRepo.FindByUserID(s.exec, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		profile = &models.LearnerProfile{UserID: userID}
+	} else if err != nil {
+		return nil, fmt.Errorf("find learner profile: %w", err)
+	}
+	if profile.Interests == nil {
+		profile.Interests = models.StringList{}
+	}
+	if profile.RecurringMistakes == nil {
+		profile.RecurringMistakes = models.StringList{}
+	}
+	return profile, nil
+}
+
+// UpdateProfile applies the learner's edits to their profile
+func (s *LearnerProfileService) UpdateProfile(userID uuid.UUID, update LearnerProfileUpdate) (*models.LearnerProfile, error) {
+	profile, err := s.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.PreferredName != nil {
+		name := strings.TrimSpace(*update.PreferredName)
+		if utf8.RuneCountInString(name) > MaxLearnerPreferredNameLen {
+			return nil, fmt.Errorf("%w: name must be at most %d characters", ErrInvalidLearnerProfile, MaxLearnerPreferredNameLen)
+		}
+		profile.PreferredName = name
+	}
+	if update.Interests != nil {
+		if profile.Interests, err = validateProfileItems("interests", *update.Interests); err != nil {
+			return nil, err
+		}
+	}
+	if update.RecurringMistakes != nil {
+		if profile.RecurringMistakes, err = validateProfileItems("recurringMistakes", *update.RecurringMistakes); err != nil {
+			return nil, err
+		}
+	}
+	if update.Enabled != nil {
+		profile.Disabled = !*update.Enabled
+	}
+	if profile.Disabled {
+		// Off means forgotten, not just hidden
+		profile.PreferredName = ""
+		profile.Interests = models.StringList{}
+		profile.RecurringMistakes = models.StringList{}
+		profile.ExtractedAt = nil
+	}
+	profile.UpdatedAt = s.now()
+
+	if err := s.profileRepo.Upsert(s.exec, profile); err != nil {
+		return nil, fmt.Errorf("save learner profile: %w", err)
+	}
+	return profile, nil
+}
+
+// SystemPrompt returns the system message telling the assistant what it
+// remembers about the learner, or nil if memory is off or empty
+func (s *LearnerProfileService) SystemPrompt(userID uuid.UUID) *client.ConversationMessage {
+	profile, err := s.GetProfile(userID)
+	if err != nil {
+		log.Printf("[LearnerMemory] Failed to load profile for user %s: %v", userID, err)
+		return nil
+	}
+	if profile.Disabled || profile.Empty() {
+		return nil
+	}
+	return &client.ConversationMessage{Role: "system", Content: LearnerProfilePrompt(profile)}
+}
+
+// LearnerProfilePrompt describes the profile for the assistant
+func LearnerProfilePrompt(profile *models.LearnerProfile) string {
+	var b strings.Builder
+	b.WriteString("What you remember about the learner from earlier conversations. " +
+		"Use it naturally without reciting it, and gently help with their recurring mistakes when they come up.")
+	if profile.PreferredName != "" {
+		fmt.Fprintf(&b, "\nName: %s", profile.PreferredName)
+	}
+	if len(profile.Interests) > 0 {
+		fmt.Fprintf(&b, "\nInterests: %s", strings.Join(profile.Interests, "; "))
+	}
+	if len(profile.RecurringMistakes) > 0 {
+		fmt.Fprintf(&b, "\nRecurring mistakes: %s", strings.Join(profile.RecurringMistakes, "; "))
+	}
+	return b.String()
+}
+
+// RequestExtraction queues reading the thread for new facts about the
+// learner unless a request for them is already in flight. It never blocks
+// on the LLM.
+func (s *LearnerProfileService) RequestExtraction(userID, threadID uuid.UUID) {
+	s.mu.Lock()
+	if s.inflight[userID] {
+		s.mu.Unlock()
+		return
+	}
+	s.inflight[userID] = true
+	s.mu.Unlock()
+
+	if s.queue == nil {
+		go s.extract(userID, threadID)
+		return
+	}
+
+	err := s.queue.Enqueue(jobs.Job{
+		Name: "learner-memory:" + userID.String(),
+		Lane: jobs.LaneStandard,
+		Run: func(ctx context.Context) error {
+			return s.extract(userID, threadID)
+		},
+	})
+	if err != nil {
+		log.Printf("[LearnerMemory] Failed to queue extraction for user %s: %v", userID, err)
+		s.done(userID)
+	}
+}
+
+// extract updates the profile from the thread's messages the profile
+// hasn't seen, once there are enough of them
+func (s *LearnerProfileService) extract(userID, threadID uuid.UUID) error {
+	defer s.done(userID)
+
+	profile, err := s.GetProfile(userID)
+	if err != nil {
+		return err
+	}
+	if profile.Disabled || s.openAI == nil {
+		return nil
+	}
+
+	messages, err := s.messageRepo.FindByThreadID(s.exec, threadID)
+	if err != nil {
+		return fmt.Errorf("find messages: %w", err)
+	}
+
+	history := make([]client.ConversationMessage, 0, len(messages))
+	userMessages := 0
+	var through time.Time
+	for _, msg := range messages {
+		if profile.ExtractedAt != nil && !msg.Timestamp.After(*profile.ExtractedAt) {
+			continue
+		}
+		history = append(history, client.ConversationMessage{Role: msg.Role, Content: msg.Content})
+		if msg.Role == "user" {
+			userMessages++
+		}
+		if msg.Timestamp.After(through) {
+			through = msg.Timestamp
+		}
+	}
+	if userMessages < LearnerMemoryMinMessages {
+		return nil
+	}
+	if len(history) > learnerMemoryMaxHistory {
+		history = history[len(history)-learnerMemoryMaxHistory:]
+	}
+
+	facts, err := s.openAI.ExtractLearnerFacts(client.LearnerFacts{
+		PreferredName:     profile.PreferredName,
+		Interests:         profile.Interests,
+		RecurringMistakes: profile.RecurringMistakes,
+	}, history)
+	if errors.Is(err, ErrLLMDeferred) {
+		// ExtractedAt stays put, so the next extraction picks these messages up
+		log.Printf("[LearnerMemory] Over the LLM budget; deferring extraction for user %s", userID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("extract learner facts: %w", err)
+	}
+
+	// The learner may have edited or turned off memory while the LLM ran
+	profile, err = s.GetProfile(userID)
+	if err != nil {
+		return err
+	}
+	if profile.Disabled {
+		return nil
+	}
+	profile.PreferredName = truncateRunes(strings.TrimSpace(facts.PreferredName), MaxLearnerPreferredNameLen)
+	profile.Interests = cleanProfileItems(facts.Interests)
+	profile.RecurringMistakes = cleanProfileItems(facts.RecurringMistakes)
+	profile.ExtractedAt = &through
+	profile.UpdatedAt = s.now()
+
+	if err := s.profileRepo.Upsert(s.exec, profile); err != nil {
+		return fmt.Errorf("save learner profile: %w", err)
+	}
+	return nil
+}
+
+func (s *LearnerProfileService) done(userID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.inflight, userID)
+	s.mu.Unlock()
+}
+
+// validateProfileItems cleans a list the learner entered, rejecting one
+// that is too long rather than dropping what they typed
+func validateProfileItems(field string, items []string) (models.StringList, error) {
+	if len(items) > MaxLearnerProfileItems {
+		return nil, fmt.Errorf("%w: at most %d %s", ErrInvalidLearnerProfile, MaxLearnerProfileItems, field)
+	}
+	for _, item := range items {
+		if utf8.RuneCountInString(strings.TrimSpace(item)) > MaxLearnerProfileItemLen {
+			return nil, fmt.Errorf("%w: %s entries must be at most %d characters", ErrInvalidLearnerProfile, field, MaxLearnerProfileItemLen)
+		}
+	}
+	return cleanProfileItems(items), nil
+}
+
+// cleanProfileItems trims items, drops blanks and case-insensitive
+// duplicates, and caps the list at MaxLearnerProfileItems
+func cleanProfileItems(items []string) models.StringList {
+	cleaned := models.StringList{}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		item = truncateRunes(strings.TrimSpace(item), MaxLearnerProfileItemLen)
+		key := strings.ToLower(item)
+		if item == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, item)
+		if len(cleaned) == MaxLearnerProfileItems {
+			break
+		}
+	}
+	return cleaned
+}
+
+// truncateRunes cuts s to at most n runes
+func truncateRunes(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return strings.TrimSpace(string(runes[:n]))
+	}
+	return s
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestLearnerProfileService_UpdateProfile(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	t.Run("cleans the learner's edits", func(t *testing.T) {
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+		profileRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+		name := " Ana "
+		interests := []string{"cooking", " Cooking ", "", "football"}
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, nil, nil, nil, func() time.Time { return now })
+		profile, err := svc.UpdateProfile(userID, LearnerProfileUpdate{PreferredName: &name, Interests: &interests})
+		require.NoError(t, err)
+		assert.Equal(t, "Ana", profile.PreferredName)
+		assert.Equal(t, models.StringList{"cooking", "football"}, profile.Interests)
+		assert.Equal(t, models.StringList{}, profile.RecurringMistakes)
+		profileRepo.AssertExpectations(t)
+	})
+
+	t.Run("turning memory off forgets the profile", func(t *testing.T) {
+		extractedAt := now.Add(-time.Hour)
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(&models.LearnerProfile{
+			UserID:            userID,
+			PreferredName:     "Ana",
+			Interests:         models.StringList{"cooking"},
+			RecurringMistakes: models.StringList{"confuses ser and estar"},
+			ExtractedAt:       &extractedAt,
+		}, nil)
+		profileRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(p *models.LearnerProfile) bool {
+			return p.Disabled && p.Empty() && p.ExtractedAt == nil
+		})).Return(nil)
+
+		enabled := false
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, nil, nil, nil, func() time.Time { return now })
+		_, err := svc.UpdateProfile(userID, LearnerProfileUpdate{Enabled: &enabled})
+		require.NoError(t, err)
+		profileRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects overlong entries", func(t *testing.T) {
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+
+		mistakes := []string{strings.Repeat("x", MaxLearnerProfileItemLen+1)}
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, nil, nil, nil, func() time.Time { return now })
+		_, err := svc.UpdateProfile(userID, LearnerProfileUpdate{RecurringMistakes: &mistakes})
+		assert.ErrorIs(t, err, ErrInvalidLearnerProfile)
+		profileRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLearnerProfileService_SystemPrompt(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("describes the profile", func(t *testing.T) {
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(&models.LearnerProfile{
+			UserID:            userID,
+			PreferredName:     "Ana",
+			RecurringMistakes: models.StringList{"confuses ser and estar"},
+		}, nil)
+
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, nil, nil, nil, time.Now)
+		prompt := svc.SystemPrompt(userID)
+		require.NotNil(t, prompt)
+		assert.Equal(t, "system", prompt.Role)
+		assert.Contains(t, prompt.Content, "Name: Ana")
+		assert.Contains(t, prompt.Content, "Recurring mistakes: confuses ser and estar")
+		assert.NotContains(t, prompt.Content, "Interests:")
+	})
+
+	t.Run("nothing when memory is off", func(t *testing.T) {
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(&models.LearnerProfile{UserID: userID, Disabled: true}, nil)
+
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, nil, nil, nil, time.Now)
+		assert.Nil(t, svc.SystemPrompt(userID))
+	})
+}
+
+func TestLearnerProfileService_Extract(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	threadID := uuid.New()
+
+	// conversation returns n user/assistant exchanges a minute apart, starting at start
+	conversation := func(start time.Time, n int) []models.Message {
+		messages := make([]models.Message, 0, 2*n)
+		for i := 0; i < n; i++ {
+			at := start.Add(time.Duration(i) * time.Minute)
+			messages = append(messages,
+				models.Message{Role: "user", Content: fmt.Sprintf("user %d", i), Timestamp: at},
+				models.Message{Role: "assistant", Content: fmt.Sprintf("assistant %d", i), Timestamp: at.Add(time.Second)},
+			)
+		}
+		return messages
+	}
+
+	t.Run("merges facts from unseen messages", func(t *testing.T) {
+		start := now.Add(-time.Hour)
+		extractedAt := start.Add(2*time.Minute + time.Second)
+		messages := conversation(start, 3+LearnerMemoryMinMessages)
+		last := messages[len(messages)-1].Timestamp
+
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(&models.LearnerProfile{
+			UserID:      userID,
+			Interests:   models.StringList{"cooking"},
+			ExtractedAt: &extractedAt,
+		}, nil)
+		messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(messages, nil)
+		openAI.On("ExtractLearnerFacts",
+			client.LearnerFacts{Interests: []string{"cooking"}, RecurringMistakes: []string{}},
+			mock.MatchedBy(func(history []client.ConversationMessage) bool {
+				return len(history) == 2*LearnerMemoryMinMessages && history[0].Content == "user 3"
+			}),
+		).Return(&client.LearnerFacts{
+			PreferredName:     "Ana",
+			Interests:         []string{"cooking", "hiking"},
+			RecurringMistakes: []string{"confuses ser and estar"},
+		}, nil)
+		profileRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(p *models.LearnerProfile) bool {
+			return p.PreferredName == "Ana" && len(p.Interests) == 2 && p.ExtractedAt.Equal(last)
+		})).Return(nil)
+
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, messageRepo, openAI, nil, func() time.Time { return now })
+		require.NoError(t, svc.extract(userID, threadID))
+		openAI.AssertExpectations(t)
+		profileRepo.AssertExpectations(t)
+	})
+
+	t.Run("waits for enough new messages", func(t *testing.T) {
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+		messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(conversation(now, LearnerMemoryMinMessages-1), nil)
+
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, messageRepo, openAI, nil, func() time.Time { return now })
+		require.NoError(t, svc.extract(userID, threadID))
+		openAI.AssertNotCalled(t, "ExtractLearnerFacts", mock.Anything, mock.Anything)
+		profileRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips learners who turned memory off", func(t *testing.T) {
+		profileRepo := new(repomocks.MockLearnerProfileRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		profileRepo.On("FindByUserID", mock.Anything, userID).Return(&models.LearnerProfile{UserID: userID, Disabled: true}, nil)
+
+		svc := NewLearnerProfileServiceForTest(nil, profileRepo, messageRepo, openAI, nil, func() time.Time { return now })
+		require.NoError(t, svc.extract(userID, threadID))
+		messageRepo.AssertNotCalled(t, "FindByThreadID", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"ling-app/api/internal/events"
+
+	"github.com/google/uuid"
+)
+
+var ErrTooManyLiveConnections = errors.New("too many live update connections")
+
+// LiveUpdatesSubscriber is the live update hub's name on the event bus
+const LiveUpdatesSubscriber = "live_updates"
+
+// liveMaxConnections caps one user's open connections (tabs and devices)
+const liveMaxConnections = 10
+
+// liveUpdateBuffer is how many updates a connection can fall behind before
+// it is closed; the client reconnects and refetches what it shows
+const liveUpdateBuffer = 32
+
+// Live update types
+const (
+	LiveUpdateMessage  = "message"  // A voice turn was answered
+	LiveUpdateAnalysis = "analysis" // A message's pronunciation analysis finished or failed
+	LiveUpdateTitle    = "title"    // A thread was named
+)
+
+// Why a connection was closed
+const (
+	LiveClosedBehind  = "behind"
+	LiveClosedRestart = "restart" // The server is shutting down; reconnect
+)
+
+// LiveUpdate tells a client that something it may be showing changed. It
+// carries IDs rather than the data, which the client fetches as usual.
+type LiveUpdate struct {
+	Type      string     `json:"type"`
+	ThreadID  uuid.UUID  `json:"threadId"`
+	MessageID *uuid.UUID `json:"messageId,omitempty"`
+
+	// LiveUpdateAnalysis: "complete" or "failed"
+	Status string `json:"status,omitempty"`
+	// LiveUpdateTitle
+	Title string `json:"title,omitempty"`
+}
+
+// LiveConnection is one client's stream of its user's updates
+type LiveConnection struct {
+	updates chan LiveUpdate
+	done    chan struct{}
+	close   func()
+
+	mu     sync.Mutex
+	reason string
+}
+
+// Updates delivers the user's updates as they happen
+func (c *LiveConnection) Updates() <-chan LiveUpdate {
+	return c.updates
+}
+
+// Done is closed when the connection falls too far behind or the server
+// shuts down
+func (c *LiveConnection) Done() <-chan struct{} {
+	return c.done
+}
+
+// Reason is why Done was closed
+func (c *LiveConnection) Reason() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reason
+}
+
+// Close stops the stream. The client's handler calls it when they disconnect.
+func (c *LiveConnection) Close() {
+	c.close()
+}
+
+// stop closes the stream for reason; only the first reason is kept
+func (c *LiveConnection) stop(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reason == "" {
+		c.reason = reason
+		close(c.done)
+	}
+}
+
+// LiveUpdateHub pushes background results to the user's open clients, so
+// they don't poll for them. Connections are held in memory: a client only
+// gets the updates published on the instance it is connected to.
+type LiveUpdateHub struct {
+	mu          sync.Mutex
+	connections map[uuid.UUID]map[*LiveConnection]struct{} // By user ID
+}
+
+// NewLiveUpdateHub creates a hub with no connections
+func NewLiveUpdateHub() *LiveUpdateHub {
+	return &LiveUpdateHub{
+		connections: make(map[uuid.UUID]map[*LiveConnection]struct{}),
+	}
+}
+
+// Connect opens a stream of the user's updates
+func (h *LiveUpdateHub) Connect(userID uuid.UUID) (*LiveConnection, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.connections[userID]) >= liveMaxConnections {
+		return nil, ErrTooManyLiveConnections
+	}
+
+	conn := &LiveConnection{
+		updates: make(chan LiveUpdate, liveUpdateBuffer),
+		done:    make(chan struct{}),
+	}
+	conn.close = func() { h.remove(userID, conn) }
+	if h.connections[userID] == nil {
+		h.connections[userID] = make(map[*LiveConnection]struct{})
+	}
+	h.connections[userID][conn] = struct{}{}
+	return conn, nil
+}
+
+// CloseAll closes every open connection, so a shutting-down server isn't
+// held open by clients; they reconnect to another instance
+func (h *LiveUpdateHub) CloseAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for userID, connections := range h.connections {
+		for conn := range connections {
+			conn.stop(LiveClosedRestart)
+		}
+		delete(h.connections, userID)
+	}
+}
+
+// Subscribe pushes answered turns, finished analyses and new thread titles
+// to their user's clients
+func (h *LiveUpdateHub) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, LiveUpdatesSubscriber, func(ctx context.Context, e events.MessageProcessed) error {
+		h.Publish(e.UserID, LiveUpdate{Type: LiveUpdateMessage, ThreadID: e.ThreadID, MessageID: &e.MessageID})
+		return nil
+	})
+	events.Subscribe(bus, LiveUpdatesSubscriber, func(ctx context.Context, e events.AnalysisCompleted) error {
+		h.Publish(e.UserID, LiveUpdate{Type: LiveUpdateAnalysis, ThreadID: e.ThreadID, MessageID: &e.MessageID, Status: "complete"})
+		return nil
+	})
+	events.Subscribe(bus, LiveUpdatesSubscriber, func(ctx context.Context, e events.AnalysisFailed) error {
+		h.Publish(e.UserID, LiveUpdate{Type: LiveUpdateAnalysis, ThreadID: e.ThreadID, MessageID: &e.MessageID, Status: "failed"})
+		return nil
+	})
+	events.Subscribe(bus, LiveUpdatesSubscriber, func(ctx context.Context, e events.ThreadTitled) error {
+		h.Publish(e.UserID, LiveUpdate{Type: LiveUpdateTitle, ThreadID: e.ThreadID, Title: e.Title})
+		return nil
+	})
+}
+
+// Publish queues update for every connection of the user, closing the
+// connections too far behind to take it
+func (h *LiveUpdateHub) Publish(userID uuid.UUID, update LiveUpdate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.connections[userID] {
+		select {
+		case conn.updates <- update:
+			continue
+		default:
+		}
+		log.Printf("[LiveUpdates] Closing a connection of user %s that fell behind", userID)
+		conn.stop(LiveClosedBehind)
+		h.removeLocked(userID, conn)
+	}
+}
+
+func (h *LiveUpdateHub) remove(userID uuid.UUID, conn *LiveConnection) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(userID, conn)
+}
+
+func (h *LiveUpdateHub) removeLocked(userID uuid.UUID, conn *LiveConnection) {
+	delete(h.connections[userID], conn)
+	if len(h.connections[userID]) == 0 {
+		delete(h.connections, userID)
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/events"
+)
+
+func TestLiveUpdateHub_DeliversToTheUsersConnections(t *testing.T) {
+	userID, otherID, threadID, messageID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	hub := NewLiveUpdateHub()
+	bus := events.NewBus()
+	hub.Subscribe(bus)
+
+	phone, err := hub.Connect(userID)
+	require.NoError(t, err)
+	defer phone.Close()
+	laptop, err := hub.Connect(userID)
+	require.NoError(t, err)
+	defer laptop.Close()
+	other, err := hub.Connect(otherID)
+	require.NoError(t, err)
+	defer other.Close()
+
+	bus.Publish(context.Background(), events.AnalysisCompleted{UserID: userID, ThreadID: threadID, MessageID: messageID})
+	bus.Publish(context.Background(), events.ThreadTitled{UserID: userID, ThreadID: threadID, Title: "Ordering coffee"})
+
+	for _, conn := range []*LiveConnection{phone, laptop} {
+		analysis := <-conn.Updates()
+		assert.Equal(t, LiveUpdateAnalysis, analysis.Type)
+		assert.Equal(t, "complete", analysis.Status)
+		assert.Equal(t, messageID, *analysis.MessageID)
+		title := <-conn.Updates()
+		assert.Equal(t, LiveUpdateTitle, title.Type)
+		assert.Equal(t, "Ordering coffee", title.Title)
+	}
+	assert.Empty(t, other.Updates())
+}
+
+func TestLiveUpdateHub_ClosesConnectionsThatFallBehind(t *testing.T) {
+	userID := uuid.New()
+	hub := NewLiveUpdateHub()
+	conn, err := hub.Connect(userID)
+	require.NoError(t, err)
+
+	for i := 0; i <= liveUpdateBuffer; i++ {
+		hub.Publish(userID, LiveUpdate{Type: LiveUpdateMessage})
+	}
+
+	<-conn.Done()
+	assert.Equal(t, LiveClosedBehind, conn.Reason())
+	assert.Empty(t, hub.connections)
+}
+
+func TestLiveUpdateHub_LimitsConnectionsPerUser(t *testing.T) {
+	userID := uuid.New()
+	hub := NewLiveUpdateHub()
+	for i := 0; i < liveMaxConnections; i++ {
+		_, err := hub.Connect(userID)
+		require.NoError(t, err)
+	}
+
+	_, err := hub.Connect(userID)
+	assert.ErrorIs(t, err, ErrTooManyLiveConnections)
+
+	hub.CloseAll()
+	_, err = hub.Connect(userID)
+	assert.NoError(t, err)
+}
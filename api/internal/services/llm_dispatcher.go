@@ -0,0 +1,243 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"ling-app/api/internal/client"
+)
+
+var ErrLLMDeferred = errors.New("llm token budget reserved for interactive requests; try again later")
+
+// Share of the per-minute token budget background requests may use. The
+// rest is headroom for requests a user is waiting on.
+const LLMBackgroundShare = 0.7
+
+// Tokens budgeted for each kind of response, on top of the prompt
+const (
+	llmReplyTokens       = 500
+	llmTitleTokens       = 20
+	llmGoalTokens        = 5
+	llmSuggestionTokens  = 100
+	llmLearnerFactTokens = 300
+)
+
+// LLMStats is a snapshot of the dispatcher's budget for the last minute
+type LLMStats struct {
+	TokensPerMinute int   `json:"tokensPerMinute"`
+	UsedTokens      int   `json:"usedTokens"`
+	Deferred        int64 `json:"deferred"`
+	Coalesced       int64 `json:"coalesced"`
+}
+
+// LLMDispatcher wraps the OpenAI client with a per-minute token budget shared
+// by every feature. Requests a user is waiting on (replies, suggestions,
+// transcript punctuation) always go through. Background requests (titles,
+// goal checks, learner memory) may only use LLMBackgroundShare of the budget
+// and fail with ErrLLMDeferred past it; identical background requests in
+// flight at the same time share one call.
+//
+// Token counts are estimated from the prompt length since the client doesn't
+// report usage.
+type LLMDispatcher struct {
+	openAI          client.OpenAIClient
+	tokensPerMinute int // 0 = unlimited
+
+	mu        sync.Mutex
+	spent     []llmSpend // oldest first, pruned to the last minute
+	inflight  map[string]*llmCall
+	deferred  int64
+	coalesced int64
+
+	now func() time.Time
+}
+
+type llmSpend struct {
+	at     time.Time
+	tokens int
+}
+
+// llmCall is a background request that callers with the same key wait on
+type llmCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+var _ client.OpenAIClient = (*LLMDispatcher)(nil)
+
+// NewLLMDispatcher creates a dispatcher allowing tokensPerMinute estimated
+// tokens a minute; 0 disables the budget but keeps coalescing
+func NewLLMDispatcher(openAI client.OpenAIClient, tokensPerMinute int) *LLMDispatcher {
+	return &LLMDispatcher{
+		openAI:          openAI,
+		tokensPerMinute: tokensPerMinute,
+		inflight:        make(map[string]*llmCall),
+		now:             time.Now,
+	}
+}
+
+// Generate writes an assistant reply (interactive)
+func (d *LLMDispatcher) Generate(messages []client.ConversationMessage) (string, error) {
+	d.spend(messagesTokens(messages) + llmReplyTokens)
+	return d.openAI.Generate(messages)
+}
+
+// SuggestReplies suggests what the learner could say next (interactive)
+func (d *LLMDispatcher) SuggestReplies(messages []client.ConversationMessage) ([]string, error) {
+	d.spend(messagesTokens(messages) + llmSuggestionTokens)
+	return d.openAI.SuggestReplies(messages)
+}
+
+// PunctuateTranscript punctuates a transcript before it's shown (interactive)
+func (d *LLMDispatcher) PunctuateTranscript(text, locale string) (string, error) {
+	d.spend(2*textTokens(text) + textTokens(locale))
+	return d.openAI.PunctuateTranscript(text, locale)
+}
+
+// GenerateTitle names a thread (background)
+func (d *LLMDispatcher) GenerateTitle(content string) (string, error) {
+	key := llmKey("title", content)
+	return dispatch(d, key, textTokens(content)+llmTitleTokens, func() (string, error) {
+		return d.openAI.GenerateTitle(content)
+	})
+}
+
+// EvaluateGoal checks whether a thread's goal was met (background)
+func (d *LLMDispatcher) EvaluateGoal(goal string, messages []client.ConversationMessage) (bool, error) {
+	key := llmKey("goal", goal, messagesKey(messages))
+	return dispatch(d, key, textTokens(goal)+messagesTokens(messages)+llmGoalTokens, func() (bool, error) {
+		return d.openAI.EvaluateGoal(goal, messages)
+	})
+}
+
+// ExtractLearnerFacts updates learner memory from a conversation (background)
+func (d *LLMDispatcher) ExtractLearnerFacts(known client.LearnerFacts, messages []client.ConversationMessage) (*client.LearnerFacts, error) {
+	interests := strings.Join(known.Interests, "\n")
+	mistakes := strings.Join(known.RecurringMistakes, "\n")
+	key := llmKey("facts", known.PreferredName, interests, mistakes, messagesKey(messages))
+	tokens := textTokens(known.PreferredName+interests+mistakes) + messagesTokens(messages) + llmLearnerFactTokens
+	return dispatch(d, key, tokens, func() (*client.LearnerFacts, error) {
+		return d.openAI.ExtractLearnerFacts(known, messages)
+	})
+}
+
+// Stats returns the budget used over the last minute and counters since start
+func (d *LLMDispatcher) Stats() LLMStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return LLMStats{
+		TokensPerMinute: d.tokensPerMinute,
+		UsedTokens:      d.usedLocked(d.now()),
+		Deferred:        d.deferred,
+		Coalesced:       d.coalesced,
+	}
+}
+
+// dispatch runs a background request, or joins an identical one already in
+// flight. It fails with ErrLLMDeferred rather than wait when the background
+// share of the budget is used up.
+func dispatch[T any](d *LLMDispatcher, key string, tokens int, call func() (T, error)) (T, error) {
+	var zero T
+
+	d.mu.Lock()
+	if running, ok := d.inflight[key]; ok {
+		d.coalesced++
+		d.mu.Unlock()
+		<-running.done
+		if running.err != nil {
+			return zero, running.err
+		}
+		return running.value.(T), nil
+	}
+
+	now := d.now()
+	used := d.usedLocked(now)
+	if d.tokensPerMinute > 0 && float64(used+tokens) > LLMBackgroundShare*float64(d.tokensPerMinute) {
+		d.deferred++
+		d.mu.Unlock()
+		log.Printf("[LLM] Deferring background request; %d of %d tokens used this minute", used, d.tokensPerMinute)
+		return zero, ErrLLMDeferred
+	}
+	d.spent = append(d.spent, llmSpend{at: now, tokens: tokens})
+	running := &llmCall{done: make(chan struct{}), err: ErrLLMDeferred}
+	d.inflight[key] = running
+	d.mu.Unlock()
+
+	// Waiters are released even if the call panics
+	defer func() {
+		d.mu.Lock()
+		delete(d.inflight, key)
+		d.mu.Unlock()
+		close(running.done)
+	}()
+
+	value, err := call()
+	running.value, running.err = value, err
+	return value, err
+}
+
+// spend records an interactive request against the budget
+func (d *LLMDispatcher) spend(tokens int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.spent = append(d.spent, llmSpend{at: d.now(), tokens: tokens})
+}
+
+// usedLocked drops spends older than a minute and totals the rest
+func (d *LLMDispatcher) usedLocked(now time.Time) int {
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(d.spent) && !d.spent[i].at.After(cutoff) {
+		i++
+	}
+	d.spent = d.spent[i:]
+
+	used := 0
+	for _, s := range d.spent {
+		used += s.tokens
+	}
+	return used
+}
+
+// textTokens estimates the tokens in text at about four characters each
+func textTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// messagesTokens estimates a conversation's tokens, with a few per message
+// for the role and framing
+func messagesTokens(messages []client.ConversationMessage) int {
+	total := 0
+	for _, m := range messages {
+		total += textTokens(m.Content) + 4
+	}
+	return total
+}
+
+func messagesKey(messages []client.ConversationMessage) string {
+	h := sha256.New()
+	for _, m := range messages {
+		h.Write([]byte(m.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(m.Content))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// llmKey identifies a background request by its kind and inputs
+func llmKey(kind string, parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return kind + ":" + hex.EncodeToString(h.Sum(nil))
+}
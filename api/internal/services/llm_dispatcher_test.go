@@ -0,0 +1,109 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+)
+
+func newTestLLMDispatcher(tokensPerMinute int) (*LLMDispatcher, *clientmocks.MockOpenAIClient, *time.Time) {
+	openAI := new(clientmocks.MockOpenAIClient)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewLLMDispatcher(openAI, tokensPerMinute)
+	d.now = func() time.Time { return now }
+	return d, openAI, &now
+}
+
+func TestLLMDispatcher_Budget(t *testing.T) {
+	long := []client.ConversationMessage{{Role: "user", Content: string(make([]byte, 4000))}}
+
+	t.Run("interactive requests always go through", func(t *testing.T) {
+		d, openAI, _ := newTestLLMDispatcher(1000)
+		openAI.On("Generate", long).Return("reply", nil)
+
+		for i := 0; i < 3; i++ {
+			reply, err := d.Generate(long)
+			require.NoError(t, err)
+			assert.Equal(t, "reply", reply)
+		}
+		assert.Greater(t, d.Stats().UsedTokens, 1000)
+	})
+
+	t.Run("background requests are deferred past their share", func(t *testing.T) {
+		d, openAI, _ := newTestLLMDispatcher(2000)
+		openAI.On("Generate", long).Return("reply", nil)
+		_, err := d.Generate(long) // ~1500 tokens, past the 1400 background share
+		require.NoError(t, err)
+
+		_, err = d.GenerateTitle("Sure, what size?")
+
+		assert.ErrorIs(t, err, ErrLLMDeferred)
+		openAI.AssertNotCalled(t, "GenerateTitle", "Sure, what size?")
+		assert.Equal(t, int64(1), d.Stats().Deferred)
+	})
+
+	t.Run("budget frees up after a minute", func(t *testing.T) {
+		d, openAI, now := newTestLLMDispatcher(2000)
+		openAI.On("Generate", long).Return("reply", nil)
+		openAI.On("GenerateTitle", "Sure, what size?").Return("Ordering Coffee", nil)
+		_, err := d.Generate(long)
+		require.NoError(t, err)
+
+		*now = now.Add(time.Minute)
+		title, err := d.GenerateTitle("Sure, what size?")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Ordering Coffee", title)
+		assert.Equal(t, textTokens("Sure, what size?")+llmTitleTokens, d.Stats().UsedTokens)
+	})
+
+	t.Run("zero budget is unlimited", func(t *testing.T) {
+		d, openAI, _ := newTestLLMDispatcher(0)
+		openAI.On("Generate", long).Return("reply", nil)
+		openAI.On("EvaluateGoal", "order food", long).Return(true, nil)
+		_, err := d.Generate(long)
+		require.NoError(t, err)
+
+		achieved, err := d.EvaluateGoal("order food", long)
+
+		require.NoError(t, err)
+		assert.True(t, achieved)
+	})
+}
+
+func TestLLMDispatcher_CoalescesIdenticalBackgroundRequests(t *testing.T) {
+	d, openAI, _ := newTestLLMDispatcher(0)
+	release := make(chan time.Time)
+	openAI.On("GenerateTitle", "Sure, what size?").WaitUntil(release).Return("Ordering Coffee", nil).Once()
+
+	titles := make([]string, 2)
+	var wg sync.WaitGroup
+	call := func(i int) {
+		defer wg.Done()
+		title, err := d.GenerateTitle("Sure, what size?")
+		assert.NoError(t, err)
+		titles[i] = title
+	}
+
+	wg.Add(1)
+	go call(0)
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return len(d.inflight) == 1
+	}, time.Second, time.Millisecond)
+	wg.Add(1)
+	go call(1)
+	require.Eventually(t, func() bool { return d.Stats().Coalesced == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"Ordering Coffee", "Ordering Coffee"}, titles)
+	openAI.AssertNumberOfCalls(t, "GenerateTitle", 1)
+}
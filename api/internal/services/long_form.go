@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Long-form limits. Each chunk keeps the usual file size and duration limits,
+// except on Pro, where a single chunk may be as long as the whole message.
+const (
+	LongFormMaxChunks          = 10
+	LongFormMaxDurationSeconds = 300.0
+	LongFormMaxFileSize        = 25 << 20 // 25MB, Whisper's upload limit
+)
+
+// LongFormProcessor defines the interface for long-form speaking practice
+type LongFormProcessor interface {
+	ProcessLongFormMessage(ctx context.Context, threadID uuid.UUID, chunks []*multipart.FileHeader, tier models.SubscriptionTier) (*ConversationTurn, error)
+	GetChunks(messageID uuid.UUID) ([]models.MessageChunk, error)
+}
+
+// LongFormService handles monologues too long for one voice message. The
+// learner sends up to LongFormMaxChunks recordings in order; each is
+// transcribed, the transcripts are stitched into a single user message, and
+// every chunk is analyzed on its own with a combined report on the message.
+// Long-form messages are charged per started minute rather than per message.
+type LongFormService struct {
+	conversation *ConversationService
+	chunkRepo    repository.MessageChunkRepository
+}
+
+// NewLongFormService creates a new long-form service on top of the
+// conversation service that generates the reply
+func NewLongFormService(conversation *ConversationService, chunkRepo repository.MessageChunkRepository) *LongFormService {
+	return &LongFormService{
+		conversation: conversation,
+		chunkRepo:    chunkRepo,
+	}
+}
+
+// ProcessLongFormMessage transcribes the chunks, charges for their total
+// length, saves the stitched message and generates the assistant's reply.
+//
+// The cost depends on the transcribed duration, so before any upload or
+// transcription the most the chunks could cost is reserved: as many chunks
+// at the longest a chunk may run, up to LongFormMaxDurationSeconds. Once
+// they are transcribed the reservation is reduced to the actual cost, which
+// is captured when the reply is delivered. If anything fails the
+// reservation is released and the uploaded recordings are deleted.
+func (s *LongFormService) ProcessLongFormMessage(
+	ctx context.Context,
+	threadID uuid.UUID,
+	chunks []*multipart.FileHeader,
+	tier models.SubscriptionTier,
+) (*ConversationTurn, error) {
+	settings := s.conversation.runtime.Current()
+
+	if len(chunks) == 0 {
+		return nil, ErrNoAudioChunks
+	}
+	if len(chunks) > LongFormMaxChunks {
+		return nil, fmt.Errorf("%w: %d (max %d)", ErrTooManyAudioChunks, len(chunks), LongFormMaxChunks)
+	}
+
+	maxFileSize, maxChunkSeconds := settings.MaxAudioFileSize, settings.MaxAudioDurationSeconds
+	if tier == models.TierPro {
+		maxFileSize = max(maxFileSize, LongFormMaxFileSize)
+		maxChunkSeconds = max(maxChunkSeconds, LongFormMaxDurationSeconds)
+	}
+	for _, header := range chunks {
+		if header.Size > maxFileSize {
+			return nil, fmt.Errorf("%w: %d bytes (max: %d)", ErrAudioFileTooLarge, header.Size, maxFileSize)
+		}
+	}
+
+	thread := s.conversation.findThread(threadID)
+	messageID := uuid.New()
+
+	bound := min(float64(len(chunks))*maxChunkSeconds, LongFormMaxDurationSeconds)
+	hold, err := s.conversation.reserveTurn(threadID, messageID, LongFormCost(bound, settings.LongFormCreditCostPerMinute), "Long-form message")
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make([]models.MessageChunk, 0, len(chunks))
+	var uploaded []string
+	var total float64
+	for position, header := range chunks {
+		key := models.ChunkAudioKey(threadID, messageID, position)
+		uploaded = append(uploaded, key)
+
+		chunk, err := s.transcribeChunk(ctx, header, key, TranscriptionLanguage(thread), maxChunkSeconds, settings)
+		if err != nil {
+			s.conversation.releaseTurn(hold, releaseReason(err))
+			s.discardAudio(uploaded)
+			return nil, fmt.Errorf("chunk %d: %w", position+1, err)
+		}
+		total += chunk.DurationSeconds
+		if total > LongFormMaxDurationSeconds {
+			s.conversation.releaseTurn(hold, "recording too long")
+			s.discardAudio(uploaded)
+			return nil, &AudioDurationError{Err: ErrAudioTooLong, Seconds: total, Limit: LongFormMaxDurationSeconds}
+		}
+
+		chunk.MessageID = messageID
+		chunk.Position = position
+		saved = append(saved, *chunk)
+	}
+
+	cost := LongFormCost(total, settings.LongFormCreditCostPerMinute)
+	if err := s.conversation.reduceTurn(hold, cost); err != nil {
+		s.conversation.releaseTurn(hold, "long-form reservation could not be reduced")
+		s.discardAudio(uploaded)
+		return nil, &VoiceTurnError{Stage: StagePersist, Err: fmt.Errorf("failed to reduce credit reservation: %w", err)}
+	}
+
+	content, rawTranscript := s.conversation.normalizeTranscript(ctx, threadID, StitchTranscripts(saved))
+	userMessage := models.Message{
+		ID:                   messageID,
+		ThreadID:             threadID,
+		Role:                 "user",
+		Content:              content,
+		RawTranscript:        rawTranscript,
+		AudioDurationSeconds: &total,
+		Kind:                 models.MessageKindLongForm,
+		Timestamp:            time.Now(),
+		PronunciationStatus:  "pending",
+	}
+	if err := s.conversation.messageRepo.Create(s.conversation.exec, &userMessage); err != nil {
+		s.conversation.releaseTurn(hold, "long-form message could not be saved")
+		s.discardAudio(uploaded)
+		return nil, &VoiceTurnError{Stage: StagePersist, Err: fmt.Errorf("failed to create message: %w", err)}
+	}
+	if err := s.chunkRepo.CreateBatch(s.conversation.exec, saved); err != nil {
+		s.conversation.releaseTurn(hold, "long-form message could not be saved")
+		s.discardAudio(uploaded)
+		return nil, &VoiceTurnError{Stage: StagePersist, Err: fmt.Errorf("failed to save chunks: %w", err)}
+	}
+	userMessage.Chunks = saved
+
+	// Analyze each chunk in the background (non-blocking)
+	if worker := s.conversation.pronunciationWorker; worker != nil {
+		worker.EnqueueChunks(threadID, messageID, saved, PhonemeLanguage(ThreadLanguage(thread)))
+	}
+
+	assistantMessage, ended, err := s.conversation.generateAssistantResponse(ctx, threadID, true, nil)
+	if err != nil {
+		s.conversation.releaseTurn(hold, "no reply was generated")
+		return nil, fmt.Errorf("failed to generate assistant response: %w", err)
+	}
+
+	charged := s.conversation.settleTurn(threadID, hold, cost, assistantMessage, true)
+	s.conversation.publishProcessed(ctx, threadID, messageID, payer(hold), models.MessageKindLongForm, charged)
+
+	return &ConversationTurn{
+		UserMessage:      &userMessage,
+		AssistantMessage: assistantMessage,
+		Credits:          charged,
+		ThreadEnded:      ended,
+	}, nil
+}
+
+// GetChunks returns a long-form message's recordings in order
+func (s *LongFormService) GetChunks(messageID uuid.UUID) ([]models.MessageChunk, error) {
+	chunks, err := s.chunkRepo.FindByMessageID(s.conversation.exec, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("find chunks: %w", err)
+	}
+	return chunks, nil
+}
+
+// transcribeChunk uploads one recording under key, transcribes it in
+// language ("" detects it) and checks its duration
+func (s *LongFormService) transcribeChunk(
+	ctx context.Context,
+	header *multipart.FileHeader,
+	key string,
+	language string,
+	maxSeconds float64,
+	settings RuntimeSettings,
+) (*models.MessageChunk, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAudioInvalid, err)
+	}
+	defer file.Close()
+
+	transcription, err := s.conversation.uploadAndTranscribe(ctx, file, key, language)
+	if err != nil {
+		return nil, err
+	}
+	if transcription.Duration < settings.MinAudioDurationSeconds {
+		return nil, &AudioDurationError{Err: ErrAudioTooShort, Seconds: transcription.Duration, Limit: settings.MinAudioDurationSeconds}
+	}
+	if transcription.Duration > maxSeconds {
+		return nil, &AudioDurationError{Err: ErrAudioTooLong, Seconds: transcription.Duration, Limit: maxSeconds}
+	}
+
+	return &models.MessageChunk{
+		ID:                  uuid.New(),
+		Transcript:          strings.TrimSpace(transcription.Text),
+		AudioURL:            &key,
+		AudioFormat:         models.AudioFormatWebM,
+		DurationSeconds:     transcription.Duration,
+		PronunciationStatus: "pending",
+		CreatedAt:           time.Now(),
+	}, nil
+}
+
+// discardAudio deletes recordings of a long-form message that won't be
+// saved. Failures are logged; the audio retention sweep never sees these.
+func (s *LongFormService) discardAudio(keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, key := range keys {
+		if err := s.conversation.storage.DeleteAudio(ctx, key); err != nil {
+			log.Printf("[LongForm] Failed to delete discarded audio %s: %v", key, err)
+		}
+	}
+}
+
+// LongFormCost is the credit cost of a long-form message: perMinute for every
+// started minute
+func LongFormCost(seconds float64, perMinute int) int {
+	minutes := max(int(math.Ceil(seconds/60)), 1)
+	return minutes * perMinute
+}
+
+// StitchTranscripts joins the chunks' transcripts, in order, into the text of
+// the long-form message
+func StitchTranscripts(chunks []models.MessageChunk) string {
+	parts := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		if text := strings.TrimSpace(chunk.Transcript); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CombineChunkAnalyses merges per-chunk analyses into one report for the
+// long-form message, in the same shape as a single recording's. Counts are
+// summed, IPA and phoneme details are concatenated in order with positions
+// offset so they stay unique, and audio quality is averaged by duration.
+func CombineChunkAnalyses(analyses []*client.PronunciationAnalysis) *client.PronunciationAnalysis {
+	combined := &client.PronunciationAnalysis{PhonemeDetails: []client.PhonemeDetail{}}
+	var audioIPA, expectedIPA []string
+	var quality client.AudioQuality
+	var qualityWeight float64
+	seenWarnings := make(map[string]bool)
+
+	for _, analysis := range analyses {
+		if analysis == nil {
+			continue
+		}
+		offset := len(combined.PhonemeDetails)
+		for _, detail := range analysis.PhonemeDetails {
+			detail.Position += offset
+			combined.PhonemeDetails = append(combined.PhonemeDetails, detail)
+		}
+		if analysis.AudioIPA != "" {
+			audioIPA = append(audioIPA, analysis.AudioIPA)
+		}
+		if analysis.ExpectedIPA != "" {
+			expectedIPA = append(expectedIPA, analysis.ExpectedIPA)
+		}
+		combined.PhonemeCount += analysis.PhonemeCount
+		combined.MatchCount += analysis.MatchCount
+		combined.SubstitutionCount += analysis.SubstitutionCount
+		combined.DeletionCount += analysis.DeletionCount
+		combined.InsertionCount += analysis.InsertionCount
+		combined.ProcessingTimeMs += analysis.ProcessingTimeMs
+		if analysis.Quality != "" {
+			combined.Quality = analysis.Quality
+		}
+
+		if q := analysis.AudioQuality; q != nil {
+			weight := max(q.DurationSeconds, 1)
+			quality.QualityScore += q.QualityScore * weight
+			quality.SNRDB += q.SNRDB * weight
+			quality.DurationSeconds += q.DurationSeconds
+			qualityWeight += weight
+			for _, warning := range q.Warnings {
+				if !seenWarnings[warning] {
+					seenWarnings[warning] = true
+					quality.Warnings = append(quality.Warnings, warning)
+				}
+			}
+		}
+	}
+
+	combined.AudioIPA = strings.Join(audioIPA, " ")
+	combined.ExpectedIPA = strings.Join(expectedIPA, " ")
+	if qualityWeight > 0 {
+		quality.QualityScore /= qualityWeight
+		quality.SNRDB /= qualityWeight
+		combined.AudioQuality = &quality
+	}
+	return combined
+}
+
+// combinedConfidence averages the chunks' confidences, weighted by how many
+// phonemes each one scored
+func combinedConfidence(analyses []*client.PronunciationAnalysis, confidences []float64) float64 {
+	var sum, weights float64
+	for i, analysis := range analyses {
+		weight := float64(max(analysis.PhonemeCount, 1))
+		sum += confidences[i] * weight
+		weights += weight
+	}
+	if weights == 0 {
+		return 0
+	}
+	return sum / weights
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+// newTestChunks builds n multipart file headers the way a form upload would
+func newTestChunks(t *testing.T, n int) []*multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i := range n {
+		part, err := writer.CreateFormFile("audio", fmt.Sprintf("chunk-%d.webm", i))
+		require.NoError(t, err)
+		_, err = part.Write([]byte("fake audio data"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["audio"]
+}
+
+type longFormDeps struct {
+	chargedTurnDeps
+	chunkRepo *repomocks.MockMessageChunkRepository
+}
+
+// newTestLongFormService wires a long-form service whose chunks each
+// transcribe as text lasting duration seconds
+func newTestLongFormService(threadID, userID uuid.UUID, text string, duration float64) (*LongFormService, *longFormDeps) {
+	conversation, deps := newChargedConversationService(threadID, userID, stageDone, duration)
+	deps.whisper.ExpectedCalls = nil
+	deps.whisper.On("TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything).Return(&client.TranscriptionResult{
+		Text:     text,
+		Duration: duration,
+	}, nil)
+	deps.storage.On("DeleteAudio", mock.Anything, mock.Anything).Return(nil)
+
+	chunkRepo := new(repomocks.MockMessageChunkRepository)
+	chunkRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+
+	return NewLongFormService(conversation, chunkRepo), &longFormDeps{chargedTurnDeps: *deps, chunkRepo: chunkRepo}
+}
+
+// expectLongFormReservation makes ReserveCredits hold bound for userID and
+// lets the hold be reduced to cost, then captured or released
+func expectLongFormReservation(deps *longFormDeps, userID uuid.UUID, bound, cost int) *models.CreditReservation {
+	hold := expectReservation(&deps.chargedTurnDeps, userID, bound, "Long-form message")
+	deps.credits.On("ReduceReservation", hold.ID, cost).Return(nil)
+	return hold
+}
+
+func TestLongFormService_StitchesChunksAndChargesPerMinute(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+	service, deps := newTestLongFormService(threadID, userID, " Hoy fui al mercado. ", 25)
+	// 3 x 25s = 75s, two started minutes at the default 2 credits each; free
+	// chunks run at most 30s, so 90s is reserved up front
+	hold := expectLongFormReservation(deps, userID, 4, 4)
+
+	turn, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 3), models.TierFree)
+	require.NoError(t, err)
+
+	msg := turn.UserMessage
+	assert.Equal(t, models.MessageKindLongForm, msg.Kind)
+	assert.Equal(t, "Hoy fui al mercado. Hoy fui al mercado. Hoy fui al mercado.", msg.Content)
+	assert.Equal(t, 75.0, *msg.AudioDurationSeconds)
+	assert.Equal(t, "pending", msg.PronunciationStatus)
+	assert.Nil(t, msg.AudioURL)
+
+	require.Len(t, msg.Chunks, 3)
+	for i, chunk := range msg.Chunks {
+		assert.Equal(t, msg.ID, chunk.MessageID)
+		assert.Equal(t, i, chunk.Position)
+		assert.Equal(t, models.ChunkAudioKey(threadID, msg.ID, i), *chunk.AudioURL)
+		assert.Equal(t, models.AudioFormatWebM, chunk.AudioFormat)
+	}
+	deps.chunkRepo.AssertCalled(t, "CreateBatch", mock.Anything, msg.Chunks)
+	assert.Equal(t, msg.ID.String(), hold.Reference)
+	deps.credits.AssertCalled(t, "ReduceReservation", hold.ID, 4)
+	deps.credits.AssertCalled(t, "CaptureReservation", hold.ID)
+	deps.storage.AssertNotCalled(t, "DeleteAudio", mock.Anything, mock.Anything)
+}
+
+func TestLongFormService_ReservesTheBoundBeforeTranscribing(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+
+	t.Run("reduces the hold to the transcribed length", func(t *testing.T) {
+		service, deps := newTestLongFormService(threadID, userID, "hola", 70)
+		// One Pro chunk may run the whole 5 minutes: 10 credits held, 4 charged
+		hold := expectLongFormReservation(deps, userID, 10, 4)
+
+		turn, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 1), models.TierPro)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, turn.Credits)
+		deps.credits.AssertCalled(t, "ReduceReservation", hold.ID, 4)
+		deps.credits.AssertCalled(t, "CaptureReservation", hold.ID)
+	})
+
+	t.Run("releases the hold when it can't be reduced", func(t *testing.T) {
+		service, deps := newTestLongFormService(threadID, userID, "hola", 70)
+		hold := expectReservation(&deps.chargedTurnDeps, userID, 10, "Long-form message")
+		deps.credits.On("ReduceReservation", hold.ID, 4).Return(errors.New("db down"))
+
+		_, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 1), models.TierPro)
+
+		require.Error(t, err)
+		assert.Equal(t, StagePersist, TurnStage(err))
+		deps.credits.AssertCalled(t, "ReleaseReservation", hold.ID)
+		deps.credits.AssertNotCalled(t, "CaptureReservation", mock.Anything)
+		deps.storage.AssertCalled(t, "DeleteAudio", mock.Anything, mock.Anything)
+		deps.messageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLongFormService_ChunkLimits(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+
+	t.Run("free tier chunks keep the voice message limit", func(t *testing.T) {
+		service, deps := newTestLongFormService(threadID, userID, "hola", 45)
+		hold := expectReservation(&deps.chargedTurnDeps, userID, 2, "Long-form message")
+
+		_, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 1), models.TierFree)
+
+		var durationErr *AudioDurationError
+		require.ErrorAs(t, err, &durationErr)
+		assert.ErrorIs(t, err, ErrAudioTooLong)
+		assert.Equal(t, 30.0, durationErr.Limit)
+		deps.credits.AssertCalled(t, "ReleaseReservation", hold.ID)
+		deps.storage.AssertCalled(t, "DeleteAudio", mock.Anything, mock.Anything)
+	})
+
+	t.Run("pro can send one long recording", func(t *testing.T) {
+		service, deps := newTestLongFormService(threadID, userID, "hola", 150)
+		expectLongFormReservation(deps, userID, 10, 6)
+
+		turn, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 1), models.TierPro)
+
+		require.NoError(t, err)
+		assert.Len(t, turn.UserMessage.Chunks, 1)
+	})
+
+	t.Run("total is capped and the uploads are discarded", func(t *testing.T) {
+		service, deps := newTestLongFormService(threadID, userID, "hola", 200)
+		hold := expectReservation(&deps.chargedTurnDeps, userID, 10, "Long-form message")
+
+		_, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 2), models.TierPro)
+
+		var durationErr *AudioDurationError
+		require.ErrorAs(t, err, &durationErr)
+		assert.Equal(t, LongFormMaxDurationSeconds, durationErr.Limit)
+		assert.Equal(t, 400.0, durationErr.Seconds)
+		deps.storage.AssertNumberOfCalls(t, "DeleteAudio", 2)
+		deps.credits.AssertCalled(t, "ReleaseReservation", hold.ID)
+		deps.credits.AssertNotCalled(t, "ReduceReservation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("too many chunks", func(t *testing.T) {
+		service, deps := newTestLongFormService(threadID, userID, "hola", 5)
+
+		_, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, LongFormMaxChunks+1), models.TierFree)
+
+		assert.ErrorIs(t, err, ErrTooManyAudioChunks)
+		deps.storage.AssertNotCalled(t, "UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLongFormService_InsufficientCreditsUploadsNothing(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+	service, deps := newTestLongFormService(threadID, userID, "hola", 20)
+	deps.credits.On("ReserveCredits", userID, 2, mock.Anything, "Long-form message").Return(nil, ErrInsufficientCredits)
+
+	_, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 2), models.TierFree)
+
+	assert.ErrorIs(t, err, ErrInsufficientCredits)
+	deps.storage.AssertNotCalled(t, "UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	deps.whisper.AssertNotCalled(t, "TranscribeFromURL", mock.Anything, mock.Anything, mock.Anything)
+	deps.messageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestLongFormService_ReleasesWhenNoReply(t *testing.T) {
+	threadID, userID := uuid.New(), uuid.New()
+	service, deps := newTestLongFormService(threadID, userID, "hola", 20)
+	deps.openAI.ExpectedCalls = nil
+	deps.openAI.On("Generate", mock.Anything).Return("", errors.New("boom"))
+	hold := expectLongFormReservation(deps, userID, 2, 2)
+
+	_, err := service.ProcessLongFormMessage(context.Background(), threadID, newTestChunks(t, 2), models.TierFree)
+
+	require.Error(t, err)
+	deps.credits.AssertCalled(t, "ReleaseReservation", hold.ID)
+	deps.credits.AssertNotCalled(t, "CaptureReservation", mock.Anything)
+}
+
+func TestLongFormCost(t *testing.T) {
+	assert.Equal(t, 2, LongFormCost(0.5, 2))
+	assert.Equal(t, 2, LongFormCost(60, 2))
+	assert.Equal(t, 4, LongFormCost(60.1, 2))
+	assert.Equal(t, 15, LongFormCost(300, 3))
+}
+
+func TestCombineChunkAnalyses(t *testing.T) {
+	combined := CombineChunkAnalyses([]*client.PronunciationAnalysis{
+		{
+			AudioIPA: "ola", ExpectedIPA: "ola", PhonemeCount: 3, MatchCount: 3,
+			PhonemeDetails: []client.PhonemeDetail{
+				{Expected: "o", Actual: "o", Type: "match", Position: 0},
+				{Expected: "l", Actual: "l", Type: "match", Position: 1},
+				{Expected: "a", Actual: "a", Type: "match", Position: 2},
+			},
+			AudioQuality: &client.AudioQuality{QualityScore: 90, SNRDB: 30, DurationSeconds: 10, Warnings: []string{"clipping"}},
+		},
+		{
+			AudioIPA: "pero", ExpectedIPA: "pejo", PhonemeCount: 4, MatchCount: 3, SubstitutionCount: 1,
+			PhonemeDetails: []client.PhonemeDetail{
+				{Expected: "p", Actual: "p", Type: "match", Position: 0},
+				{Expected: "e", Actual: "e", Type: "match", Position: 1},
+				{Expected: "ɾ", Actual: "j", Type: "substitution", Position: 2},
+				{Expected: "o", Actual: "o", Type: "match", Position: 3},
+			},
+			AudioQuality: &client.AudioQuality{QualityScore: 60, SNRDB: 15, DurationSeconds: 20, Warnings: []string{"clipping", "low_volume"}},
+		},
+	})
+
+	assert.Equal(t, "ola pero", combined.AudioIPA)
+	assert.Equal(t, "ola pejo", combined.ExpectedIPA)
+	assert.Equal(t, 7, combined.PhonemeCount)
+	assert.Equal(t, 6, combined.MatchCount)
+	assert.Equal(t, 1, combined.SubstitutionCount)
+	require.Len(t, combined.PhonemeDetails, 7)
+	assert.Equal(t, 5, combined.PhonemeDetails[5].Position)
+	assert.Equal(t, "j", combined.PhonemeDetails[5].Actual)
+
+	require.NotNil(t, combined.AudioQuality)
+	assert.InDelta(t, 70, combined.AudioQuality.QualityScore, 0.001)
+	assert.InDelta(t, 20, combined.AudioQuality.SNRDB, 0.001)
+	assert.Equal(t, 30.0, combined.AudioQuality.DurationSeconds)
+	assert.Equal(t, []string{"clipping", "low_volume"}, combined.AudioQuality.Warnings)
+}
+
+func TestPronunciationWorker_AnalyzeChunks(t *testing.T) {
+	messageID, threadID, userID := uuid.New(), uuid.New(), uuid.New()
+	key0, key1 := "user/t/m-0.webm", "user/t/m-1.webm"
+	chunks := []models.MessageChunk{
+		{ID: uuid.New(), MessageID: messageID, Position: 0, Transcript: "hola", AudioURL: &key0},
+		{ID: uuid.New(), MessageID: messageID, Position: 1, Transcript: "adiós", AudioURL: &key1},
+	}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	chunkRepo := new(repomocks.MockMessageChunkRepository)
+	storageClient := new(clientmocks.MockStorageClient)
+	mlClient := new(clientmocks.MockMLClient)
+	phonemeStatsRepo := new(repomocks.MockPhonemeStatsRepository)
+	phonemeSubsRepo := new(repomocks.MockPhonemeSubstitutionRepository)
+
+	storageClient.On("GetPresignedURL", mock.Anything, key0, time.Hour).Return("https://presigned/0", nil)
+	storageClient.On("GetPresignedURL", mock.Anything, key1, time.Hour).Return("https://presigned/1", nil)
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned/0", "hola", "es", client.QualityFast).
+		Return(&client.PronunciationResponse{
+			Status: "success",
+			Analysis: &client.PronunciationAnalysis{
+				PhonemeCount: 4,
+				MatchCount:   4,
+				PhonemeDetails: []client.PhonemeDetail{
+					{Expected: "o", Actual: "o", Type: "match"},
+					{Expected: "l", Actual: "l", Type: "match"},
+					{Expected: "a", Actual: "a", Type: "match"},
+				},
+			},
+		}, nil)
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned/1", "adiós", "es", client.QualityFast).
+		Return(nil, &client.MLServiceError{Code: "AUDIO_TOO_NOISY", Message: "too noisy"})
+
+	chunkRepo.On("UpdatePronunciationAnalysis", mock.Anything, chunks[0].ID, "complete", mock.AnythingOfType("models.JSONMap"), "fast", mock.AnythingOfType("float64"), false, mock.AnythingOfType("time.Time")).Return(nil)
+	chunkRepo.On("UpdatePronunciationError", mock.Anything, chunks[1].ID, "failed", "AUDIO_TOO_NOISY: too noisy", mock.AnythingOfType("time.Time")).Return(nil)
+
+	var stored models.JSONMap
+	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), "fast", mock.AnythingOfType("float64"), false, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { stored = args.Get(3).(models.JSONMap) }).
+		Return(nil)
+	messageRepo.On("FindByID", mock.Anything, messageID).Return(&models.Message{ID: messageID, ThreadID: threadID}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+	phonemeStatsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, statsBus(NewPhonemeStatsServiceForTest(nil, phonemeStatsRepo, phonemeSubsRepo)))
+	worker.Chunks = chunkRepo
+	worker.AnalyzeChunks(context.Background(), messageID, chunks, "es", client.QualityFast)
+
+	chunkRepo.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+	assert.Equal(t, 2, stored["chunk_count"])
+	assert.Equal(t, 1, stored["analyzed_chunk_count"])
+	assert.EqualValues(t, 4, stored["phoneme_count"])
+	phonemeStatsRepo.AssertNumberOfCalls(t, "Upsert", 3)
+}
+
+func TestPronunciationWorker_AnalyzeChunks_AllFail(t *testing.T) {
+	messageID := uuid.New()
+	chunks := []models.MessageChunk{{ID: uuid.New(), MessageID: messageID, Transcript: "hola"}}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	chunkRepo := new(repomocks.MockMessageChunkRepository)
+	chunkRepo.On("UpdatePronunciationError", mock.Anything, chunks[0].ID, "failed", "NO_AUDIO: recording was deleted", mock.Anything).Return(nil)
+	messageRepo.On("UpdatePronunciationError", mock.Anything, messageID, "failed", "CHUNKS_FAILED: none of the recordings could be analyzed", mock.Anything).Return(nil)
+
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, nil, nil, nil, nil)
+	worker.Chunks = chunkRepo
+	worker.AnalyzeChunks(context.Background(), messageID, chunks, "es", client.QualityFast)
+
+	chunkRepo.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCallbackToken = errors.New("invalid callback token")
+
+// DefaultMLCallbackTTL is how long the ML service has to post a result back
+const DefaultMLCallbackTTL = 2 * time.Hour
+
+// MLCallbackSigner issues the token sent with an async pronunciation job and
+// verifies it when the ML service posts the result back. The token names the
+// message and expires, so it can't be replayed against another message.
+//
+// Format: <message ID>.<expiry unix seconds>.<hex HMAC-SHA256 of the first two>
+type MLCallbackSigner struct {
+	secret []byte
+	ttl    time.Duration
+
+	now func() time.Time
+}
+
+// NewMLCallbackSigner creates a signer. A ttl of 0 uses DefaultMLCallbackTTL.
+func NewMLCallbackSigner(secret string, ttl time.Duration) *MLCallbackSigner {
+	if ttl <= 0 {
+		ttl = DefaultMLCallbackTTL
+	}
+	return &MLCallbackSigner{
+		secret: []byte(secret),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Sign returns a callback token for the message
+func (s *MLCallbackSigner) Sign(messageID uuid.UUID) string {
+	payload := fmt.Sprintf("%s.%d", messageID, s.now().Add(s.ttl).Unix())
+	return payload + "." + s.mac(payload)
+}
+
+// Verify checks a callback token and returns the message it was issued for
+func (s *MLCallbackSigner) Verify(token string) (uuid.UUID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return uuid.Nil, ErrInvalidCallbackToken
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.mac(payload))) {
+		return uuid.Nil, ErrInvalidCallbackToken
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || s.now().Unix() > expiresAt {
+		return uuid.Nil, ErrInvalidCallbackToken
+	}
+
+	messageID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, ErrInvalidCallbackToken
+	}
+	return messageID, nil
+}
+
+func (s *MLCallbackSigner) mac(payload string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
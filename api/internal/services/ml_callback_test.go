@@ -0,0 +1,51 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCallbackSecret = "test-callback-secret-that-is-32-chars"
+
+func TestMLCallbackSigner_RoundTrip(t *testing.T) {
+	signer := NewMLCallbackSigner(testCallbackSecret, time.Hour)
+	messageID := uuid.New()
+
+	got, err := signer.Verify(signer.Sign(messageID))
+
+	require.NoError(t, err)
+	assert.Equal(t, messageID, got)
+}
+
+func TestMLCallbackSigner_Rejects(t *testing.T) {
+	signer := NewMLCallbackSigner(testCallbackSecret, time.Hour)
+	token := signer.Sign(uuid.New())
+	parts := strings.Split(token, ".")
+
+	expired := NewMLCallbackSigner(testCallbackSecret, time.Hour)
+	expired.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "malformed", token: "not-a-token"},
+		{name: "other message", token: uuid.NewString() + "." + parts[1] + "." + parts[2]},
+		{name: "extended expiry", token: parts[0] + ".9999999999." + parts[2]},
+		{name: "other secret", token: NewMLCallbackSigner("another-secret-that-is-32-chars-long", time.Hour).Sign(uuid.New())},
+		{name: "expired", token: expired.Sign(uuid.New())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(tt.token)
+			assert.ErrorIs(t, err, ErrInvalidCallbackToken)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+)
+
+// MLLoadMonitor polls the ML service's queue depth in the background so
+// request middleware can shed load without an extra network call per request.
+type MLLoadMonitor struct {
+	MLClient     client.MLClient
+	interval     time.Duration
+	maxDepth     int // Free tier is shed at this depth (0 disables shedding)
+	paidMaxDepth int // Paid tiers are shed at this depth (0 never sheds paid tiers)
+
+	depth     atomic.Int64
+	reachable atomic.Bool
+}
+
+// NewMLLoadMonitor creates a new ML load monitor
+func NewMLLoadMonitor(mlClient client.MLClient, interval time.Duration, maxDepth, paidMaxDepth int) *MLLoadMonitor {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &MLLoadMonitor{
+		MLClient:     mlClient,
+		interval:     interval,
+		maxDepth:     maxDepth,
+		paidMaxDepth: paidMaxDepth,
+	}
+}
+
+// Enabled reports whether load shedding is configured
+func (m *MLLoadMonitor) Enabled() bool {
+	return m.maxDepth > 0
+}
+
+// Start polls the ML service until ctx is cancelled
+func (m *MLLoadMonitor) Start(ctx context.Context) {
+	if !m.Enabled() {
+		return
+	}
+
+	log.Printf("[MLLoadMonitor] Polling ML queue depth every %s (shed free at %d, paid at %d)",
+		m.interval, m.maxDepth, m.paidMaxDepth)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the current queue depth once.
+// On failure the last known depth is kept; requests will fail on their own
+// if the ML service is actually down.
+func (m *MLLoadMonitor) poll(ctx context.Context) {
+	pollCtx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	health, err := m.MLClient.Health(pollCtx)
+	if err != nil {
+		if m.reachable.Swap(false) {
+			log.Printf("[MLLoadMonitor] ML health check failed: %v", err)
+		}
+		return
+	}
+
+	if !m.reachable.Swap(true) {
+		log.Printf("[MLLoadMonitor] ML service reachable (status=%s, queue depth=%d)", health.Status, health.QueueDepth)
+	}
+	m.depth.Store(int64(health.QueueDepth))
+}
+
+// QueueDepth returns the last observed ML queue depth
+func (m *MLLoadMonitor) QueueDepth() int {
+	return int(m.depth.Load())
+}
+
+// Overloaded reports whether any tier is currently being shed
+func (m *MLLoadMonitor) Overloaded() bool {
+	return m.Enabled() && m.QueueDepth() >= m.maxDepth
+}
+
+// ShouldShed reports whether a new submission from the given tier should be rejected.
+// Paid tiers get a higher threshold so they keep working while free users are shed.
+func (m *MLLoadMonitor) ShouldShed(tier models.SubscriptionTier) bool {
+	if !m.Overloaded() {
+		return false
+	}
+	if tier == models.TierBasic || tier == models.TierPro {
+		return m.paidMaxDepth > 0 && m.QueueDepth() >= m.paidMaxDepth
+	}
+	return true
+}
+
+// RetryAfter is how long shed clients should wait before retrying
+func (m *MLLoadMonitor) RetryAfter() time.Duration {
+	return 2 * m.interval
+}
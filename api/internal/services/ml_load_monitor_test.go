@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+)
+
+func TestMLLoadMonitor_ShouldShed(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxDepth     int
+		paidMaxDepth int
+		depth        int
+		tier         models.SubscriptionTier
+		want         bool
+	}{
+		{"disabled", 0, 0, 100, models.TierFree, false},
+		{"free under threshold", 10, 20, 9, models.TierFree, false},
+		{"free at threshold", 10, 20, 10, models.TierFree, true},
+		{"pro between thresholds", 10, 20, 15, models.TierPro, false},
+		{"basic at paid threshold", 10, 20, 20, models.TierBasic, true},
+		{"paid never shed", 10, 0, 500, models.TierPro, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := NewMLLoadMonitor(nil, time.Second, tt.maxDepth, tt.paidMaxDepth)
+			monitor.depth.Store(int64(tt.depth))
+
+			assert.Equal(t, tt.want, monitor.ShouldShed(tt.tier))
+		})
+	}
+}
+
+func TestMLLoadMonitor_Poll(t *testing.T) {
+	t.Run("updates queue depth", func(t *testing.T) {
+		mlClient := new(clientmocks.MockMLClient)
+		mlClient.On("Health", mock.Anything).Return(&client.MLHealth{Status: "healthy", QueueDepth: 7}, nil)
+
+		monitor := NewMLLoadMonitor(mlClient, time.Second, 5, 10)
+		monitor.poll(context.Background())
+
+		assert.Equal(t, 7, monitor.QueueDepth())
+		assert.True(t, monitor.Overloaded())
+		mlClient.AssertExpectations(t)
+	})
+
+	t.Run("keeps last depth on error", func(t *testing.T) {
+		mlClient := new(clientmocks.MockMLClient)
+		mlClient.On("Health", mock.Anything).Return(nil, errors.New("connection refused"))
+
+		monitor := NewMLLoadMonitor(mlClient, time.Second, 5, 10)
+		monitor.depth.Store(3)
+		monitor.poll(context.Background())
+
+		assert.Equal(t, 3, monitor.QueueDepth())
+	})
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Default and max number of days in a shadow analysis report
+const (
+	DefaultMLShadowDays = 7
+	MaxMLShadowDays     = 90
+)
+
+// mlShadowTimeout bounds one shadow analysis
+const mlShadowTimeout = 5 * time.Minute
+
+// MLShadowReporter defines the interface for the admin shadow analysis report
+type MLShadowReporter interface {
+	Report(days int) (*MLShadowReport, error)
+}
+
+// MLShadowReport compares the shadow model with the primary one over the
+// last few days, per analysis quality
+type MLShadowReport struct {
+	From      time.Time                          `json:"from"`
+	To        time.Time                          `json:"to"`
+	Qualities []models.AnalysisComparisonSummary `json:"qualities"`
+}
+
+// MLShadow sends a share of pronunciation analyses to a second ML service
+// running a model under evaluation, and stores how its result compares with
+// the primary one. Shadow results are only ever stored for the report; a
+// nil *MLShadow samples nothing.
+type MLShadow struct {
+	exec   repository.Executor
+	repo   repository.AnalysisComparisonRepository
+	Client client.MLClient
+
+	// Runtime supplies the share of analyses sampled; nil samples none
+	Runtime *RuntimeSettingsService
+
+	now  func() time.Time
+	roll func() float64 // In [0, 100)
+}
+
+// NewMLShadow creates a shadow analyzer sending to mlClient
+func NewMLShadow(database *db.DB, repo repository.AnalysisComparisonRepository, mlClient client.MLClient, runtime *RuntimeSettingsService) *MLShadow {
+	shadow := NewMLShadowForTest(database.DB, repo, mlClient, time.Now, func() float64 { return rand.Float64() * 100 })
+	shadow.Runtime = runtime
+	return shadow
+}
+
+// NewMLShadowForTest creates an MLShadow with injected dependencies for testing.
+func NewMLShadowForTest(
+	exec repository.Executor,
+	repo repository.AnalysisComparisonRepository,
+	mlClient client.MLClient,
+	now func() time.Time,
+	roll func() float64,
+) *MLShadow {
+	return &MLShadow{
+		exec:   exec,
+		repo:   repo,
+		Client: mlClient,
+		now:    now,
+		roll:   roll,
+	}
+}
+
+// Sampled decides whether an analysis is also sent to the shadow model
+func (s *MLShadow) Sampled() bool {
+	if s == nil || s.Runtime == nil {
+		return false
+	}
+	return s.roll() < s.Runtime.Current().MLShadowPercent
+}
+
+// Compare runs the analysis the primary model answered with primary, which
+// took primaryLatency, on the shadow model and stores the comparison.
+// Failures are logged: the shadow never affects the user's result.
+func (s *MLShadow) Compare(messageID uuid.UUID, audioURL, expectedText, language string, quality client.AnalysisQuality, primary *client.PronunciationResponse, primaryLatency time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), mlShadowTimeout)
+	defer cancel()
+
+	start := s.now()
+	shadow, err := s.Client.AnalyzePronunciation(ctx, audioURL, expectedText, language, quality)
+	shadowLatency := s.now().Sub(start)
+	if err != nil {
+		shadow = &client.PronunciationResponse{
+			Status: "error",
+			Error:  &client.PronunciationError{Code: "REQUEST_FAILED", Message: err.Error()},
+		}
+	}
+
+	comparison := compareAnalyses(primary, shadow)
+	comparison.MessageID = messageID
+	comparison.Quality = string(quality)
+	comparison.PrimaryLatencyMs = primaryLatency.Milliseconds()
+	comparison.ShadowLatencyMs = shadowLatency.Milliseconds()
+	comparison.CreatedAt = s.now()
+	if err := s.repo.Create(s.exec, comparison); err != nil {
+		log.Printf("[MLShadow] Failed to store comparison for message %s: %v", messageID, err)
+	}
+}
+
+// compareAnalyses scores two results of the same analysis against each other
+func compareAnalyses(primary, shadow *client.PronunciationResponse) *models.AnalysisComparison {
+	comparison := &models.AnalysisComparison{
+		PrimaryStatus: analysisStatus(primary),
+		ShadowStatus:  analysisStatus(shadow),
+	}
+	if comparison.ShadowStatus != "success" && shadow.Error != nil {
+		comparison.ShadowError = shadow.Error.Code
+	}
+	if comparison.PrimaryStatus == "success" {
+		comparison.PrimaryPhonemes = primary.Analysis.PhonemeCount
+		comparison.PrimaryAccuracy = matchedShare(primary.Analysis)
+	}
+	if comparison.ShadowStatus == "success" {
+		comparison.ShadowPhonemes = shadow.Analysis.PhonemeCount
+		comparison.ShadowAccuracy = matchedShare(shadow.Analysis)
+	}
+	if comparison.PrimaryStatus == "success" && comparison.ShadowStatus == "success" {
+		agreement := phonemeAgreement(primary.Analysis.PhonemeDetails, shadow.Analysis.PhonemeDetails)
+		comparison.Agreement = &agreement
+	}
+	return comparison
+}
+
+// analysisStatus is "success" for a result with an analysis, "error" otherwise
+func analysisStatus(result *client.PronunciationResponse) string {
+	if result == nil || result.Status == "error" || result.Analysis == nil {
+		return "error"
+	}
+	return "success"
+}
+
+func matchedShare(analysis *client.PronunciationAnalysis) float64 {
+	if analysis.PhonemeCount == 0 {
+		return 0
+	}
+	return float64(analysis.MatchCount) / float64(analysis.PhonemeCount)
+}
+
+// phonemeAgreement is the share of expected phonemes the two models judged
+// the same way, matched or not. Phonemes are paired by position in the
+// expected transcription; insertions, which have no expected phoneme, are
+// left out. Two results with no expected phonemes agree fully.
+func phonemeAgreement(primary, shadow []client.PhonemeDetail) float64 {
+	shadowMatched := make(map[string]bool)
+	for _, detail := range shadow {
+		if detail.Type != "insert" {
+			shadowMatched[phonemeSlot(detail)] = detail.Type == "match"
+		}
+	}
+
+	var expected, agreed int
+	for _, detail := range primary {
+		if detail.Type == "insert" {
+			continue
+		}
+		expected++
+		// A phoneme the shadow skipped counts as not matched
+		if shadowMatched[phonemeSlot(detail)] == (detail.Type == "match") {
+			agreed++
+		}
+	}
+	if expected == 0 {
+		return 1
+	}
+	return float64(agreed) / float64(expected)
+}
+
+func phonemeSlot(detail client.PhonemeDetail) string {
+	return fmt.Sprintf("%d:%s", detail.Position, detail.Expected)
+}
+
+// Report sums the comparisons of the last days (today included)
+func (s *MLShadow) Report(days int) (*MLShadowReport, error) {
+	if days <= 0 {
+		days = DefaultMLShadowDays
+	}
+	days = min(days, MaxMLShadowDays)
+
+	to := s.now().UTC()
+	from := to.Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	qualities, err := s.repo.Summarize(s.exec, from)
+	if err != nil {
+		return nil, fmt.Errorf("summarize analysis comparisons: %w", err)
+	}
+	if qualities == nil {
+		qualities = []models.AnalysisComparisonSummary{}
+	}
+	return &MLShadowReport{From: from, To: to, Qualities: qualities}, nil
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+	"ling-app/api/internal/testutil/factory"
+)
+
+// shadowRuntime returns runtime settings sampling percent of analyses
+func shadowRuntime(t *testing.T, percent string) *RuntimeSettingsService {
+	repo := new(repomocks.MockRuntimeSettingRepository)
+	repo.On("FindAll", mock.Anything).Return([]models.RuntimeSetting{{Key: "mlShadowPercent", Value: percent}}, nil)
+	runtime := NewRuntimeSettingsServiceForTest(nil, repo, nil, 0)
+	require.NoError(t, runtime.Refresh())
+	return runtime
+}
+
+// steppingClock returns a clock that moves step forward on every reading
+func steppingClock(start time.Time, step time.Duration) func() time.Time {
+	now := start
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func TestMLShadow_Sampled(t *testing.T) {
+	var disabled *MLShadow
+	assert.False(t, disabled.Sampled(), "nil shadow")
+
+	tests := []struct {
+		name    string
+		percent string
+		roll    float64
+		want    bool
+	}{
+		{"off by default", "0", 0, false},
+		{"roll under the share", "25", 10, true},
+		{"roll over the share", "25", 40, false},
+		{"everything", "100", 99.9, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shadow := NewMLShadowForTest(nil, nil, nil, time.Now, func() float64 { return tt.roll })
+			shadow.Runtime = shadowRuntime(t, tt.percent)
+			assert.Equal(t, tt.want, shadow.Sampled())
+		})
+	}
+}
+
+func TestMLShadow_Compare(t *testing.T) {
+	messageID := uuid.New()
+	primary := factory.Analysis().Substitute("θ", "s").Match("ɪ", "ŋ", "k").Response()
+
+	t.Run("stores agreement, accuracy and latency", func(t *testing.T) {
+		mlClient := new(clientmocks.MockMLClient)
+		mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url", "think", "en-us", client.QualityFast).
+			Return(factory.Analysis().Substitute("θ", "s").Match("ɪ").Substitute("ŋ", "n").Match("k").Insert("ə").Response(), nil)
+		repo := new(repomocks.MockAnalysisComparisonRepository)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.AnalysisComparison) bool {
+			return c.MessageID == messageID && c.Quality == "fast" &&
+				c.PrimaryStatus == "success" && c.ShadowStatus == "success" && c.ShadowError == "" &&
+				c.PrimaryAccuracy == 0.75 && c.ShadowAccuracy == 0.4 &&
+				c.Agreement != nil && *c.Agreement == 0.75 &&
+				c.PrimaryLatencyMs == 1200 && c.ShadowLatencyMs == 800
+		})).Return(nil)
+
+		clock := steppingClock(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), 800*time.Millisecond)
+		shadow := NewMLShadowForTest(nil, repo, mlClient, clock, nil)
+		shadow.Compare(messageID, "https://presigned.url", "think", "en-us", client.QualityFast, primary, 1200*time.Millisecond)
+
+		mlClient.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("records a failed shadow without agreement", func(t *testing.T) {
+		mlClient := new(clientmocks.MockMLClient)
+		mlClient.On("AnalyzePronunciation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("connection refused"))
+		repo := new(repomocks.MockAnalysisComparisonRepository)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.AnalysisComparison) bool {
+			return c.PrimaryStatus == "success" && c.ShadowStatus == "error" &&
+				c.ShadowError == "REQUEST_FAILED" && c.Agreement == nil && c.PrimaryPhonemes == 4
+		})).Return(nil)
+
+		shadow := NewMLShadowForTest(nil, repo, mlClient, time.Now, nil)
+		shadow.Compare(messageID, "https://presigned.url", "think", "en-us", client.QualityAccurate, primary, time.Second)
+
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestPhonemeAgreement(t *testing.T) {
+	match := func(position int, phoneme string) client.PhonemeDetail {
+		return client.PhonemeDetail{Expected: phoneme, Actual: phoneme, Type: "match", Position: position}
+	}
+	deletion := func(position int, phoneme string) client.PhonemeDetail {
+		return client.PhonemeDetail{Expected: phoneme, Type: "delete", Position: position}
+	}
+
+	tests := []struct {
+		name            string
+		primary, shadow []client.PhonemeDetail
+		want            float64
+	}{
+		{"identical", []client.PhonemeDetail{match(0, "h"), match(1, "aɪ")}, []client.PhonemeDetail{match(0, "h"), match(1, "aɪ")}, 1},
+		{"one disagreement", []client.PhonemeDetail{match(0, "h"), match(1, "aɪ")}, []client.PhonemeDetail{match(0, "h"), deletion(1, "aɪ")}, 0.5},
+		{"both missed it", []client.PhonemeDetail{deletion(0, "h")}, []client.PhonemeDetail{deletion(0, "h")}, 1},
+		{"phoneme the shadow skipped", []client.PhonemeDetail{match(0, "h"), match(1, "aɪ")}, []client.PhonemeDetail{match(0, "h")}, 0.5},
+		{"nothing expected", nil, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, phonemeAgreement(tt.primary, tt.shadow))
+		})
+	}
+}
+
+func TestMLShadow_Report(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	t.Run("sums the last days per quality", func(t *testing.T) {
+		repo := new(repomocks.MockAnalysisComparisonRepository)
+		repo.On("Summarize", mock.Anything, time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)).
+			Return([]models.AnalysisComparisonSummary{{Quality: "fast", Comparisons: 40, AvgAgreement: 0.92}}, nil)
+
+		report, err := NewMLShadowForTest(nil, repo, nil, func() time.Time { return now }, nil).Report(DefaultMLShadowDays)
+		require.NoError(t, err)
+		require.Len(t, report.Qualities, 1)
+		assert.Equal(t, int64(40), report.Qualities[0].Comparisons)
+		assert.Equal(t, now, report.To)
+	})
+
+	t.Run("no comparisons yet", func(t *testing.T) {
+		repo := new(repomocks.MockAnalysisComparisonRepository)
+		repo.On("Summarize", mock.Anything, mock.Anything).Return(nil, nil)
+
+		report, err := NewMLShadowForTest(nil, repo, nil, func() time.Time { return now }, nil).Report(0)
+		require.NoError(t, err)
+		assert.NotNil(t, report.Qualities)
+	})
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAccountDeleter is a mock implementation of AccountDeleter interface
+type MockAccountDeleter struct {
+	mock.Mock
+}
+
+// DeleteAccount mocks the DeleteAccount method
+func (m *MockAccountDeleter) DeleteAccount(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAccountMerger is a mock implementation of AccountMerger interface
+type MockAccountMerger struct {
+	mock.Mock
+}
+
+// Merge mocks the Merge method
+func (m *MockAccountMerger) Merge(fromUserID, intoUserID, actor uuid.UUID) (*services.AccountMergeResult, error) {
+	args := m.Called(fromUserID, intoUserID, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.AccountMergeResult), args.Error(1)
+}
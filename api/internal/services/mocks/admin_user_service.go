@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAdminUserProvider is a mock implementation of AdminUserProvider interface
+type MockAdminUserProvider struct {
+	mock.Mock
+}
+
+// GetUser mocks the GetUser method
+func (m *MockAdminUserProvider) GetUser(userID uuid.UUID) (*services.AdminUserView, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.AdminUserView), args.Error(1)
+}
+
+// ListUsers mocks the ListUsers method
+func (m *MockAdminUserProvider) ListUsers(query string, limit int) ([]models.User, error) {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.User), args.Error(1)
+}
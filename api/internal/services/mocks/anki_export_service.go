@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"context"
+
+	"ling-app/api/internal/client"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAnkiExporter is a mock implementation of AnkiExporter interface
+type MockAnkiExporter struct {
+	mock.Mock
+}
+
+// RequestExport mocks the RequestExport method
+func (m *MockAnkiExporter) RequestExport(userID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(userID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+// OpenExport mocks the OpenExport method
+func (m *MockAnkiExporter) OpenExport(ctx context.Context, userID, exportID uuid.UUID) (*client.StorageObject, error) {
+	args := m.Called(ctx, userID, exportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.StorageObject), args.Error(1)
+}
@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditLogger is a mock implementation of AuditLogger interface
+type MockAuditLogger struct {
+	mock.Mock
+}
+
+// Record mocks the Record method
+func (m *MockAuditLogger) Record(entry *models.AuditLog) {
+	m.Called(entry)
+}
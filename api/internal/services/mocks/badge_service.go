@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBadgeManager is a mock implementation of BadgeManager interface
+type MockBadgeManager struct {
+	mock.Mock
+}
+
+// GetBadge mocks the GetBadge method
+func (m *MockBadgeManager) GetBadge(userID uuid.UUID) (*models.StatsBadge, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StatsBadge), args.Error(1)
+}
+
+// EnableBadge mocks the EnableBadge method
+func (m *MockBadgeManager) EnableBadge(userID uuid.UUID) (*models.StatsBadge, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StatsBadge), args.Error(1)
+}
+
+// RevokeBadge mocks the RevokeBadge method
+func (m *MockBadgeManager) RevokeBadge(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// PublicStats mocks the PublicStats method
+func (m *MockBadgeManager) PublicStats(token string) (*services.BadgeStats, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.BadgeStats), args.Error(1)
+}
@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBootstrapper is a mock implementation of Bootstrapper interface
+type MockBootstrapper struct {
+	mock.Mock
+}
+
+// GetBootstrap mocks the GetBootstrap method
+func (m *MockBootstrapper) GetBootstrap(user *models.User) *services.Bootstrap {
+	args := m.Called(user)
+	return args.Get(0).(*services.Bootstrap)
+}
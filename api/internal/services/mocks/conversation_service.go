@@ -21,8 +21,34 @@ func (m *MockConversationProcessor) ProcessAudioMessage(
 	threadID uuid.UUID,
 	audioFile multipart.File,
 	fileHeader *multipart.FileHeader,
+	expectedText string,
 ) (*services.ConversationTurn, error) {
-	args := m.Called(ctx, threadID, audioFile, fileHeader)
+	args := m.Called(ctx, threadID, audioFile, fileHeader, expectedText)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ConversationTurn), args.Error(1)
+}
+
+// StreamAudioMessage mocks the StreamAudioMessage method
+func (m *MockConversationProcessor) StreamAudioMessage(
+	ctx context.Context,
+	threadID uuid.UUID,
+	audioFile multipart.File,
+	fileHeader *multipart.FileHeader,
+	expectedText string,
+	progress services.TurnProgress,
+) (*services.ConversationTurn, error) {
+	args := m.Called(ctx, threadID, audioFile, fileHeader, expectedText, progress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ConversationTurn), args.Error(1)
+}
+
+// SendTextMessage mocks the SendTextMessage method
+func (m *MockConversationProcessor) SendTextMessage(ctx context.Context, threadID uuid.UUID, content string, speak bool) (*services.ConversationTurn, error) {
+	args := m.Called(ctx, threadID, content, speak)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCreditAuditor is a mock implementation of CreditAuditor interface
+type MockCreditAuditor struct {
+	mock.Mock
+}
+
+// GetTransactionDetail mocks the GetTransactionDetail method
+func (m *MockCreditAuditor) GetTransactionDetail(userID, transactionID uuid.UUID) (*services.TransactionDetail, error) {
+	args := m.Called(userID, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TransactionDetail), args.Error(1)
+}
+
+// OpenDispute mocks the OpenDispute method
+func (m *MockCreditAuditor) OpenDispute(userID, transactionID uuid.UUID, reason string) (*models.CreditDispute, error) {
+	args := m.Called(userID, transactionID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditDispute), args.Error(1)
+}
@@ -40,6 +40,39 @@ func (m *MockCreditsManager) AddCredits(userID uuid.UUID, amount int, descriptio
 	return args.Error(0)
 }
 
+func (m *MockCreditsManager) RefundCredits(userID uuid.UUID, amount int, reference, description string) error {
+	args := m.Called(userID, amount, reference, description)
+	return args.Error(0)
+}
+
+func (m *MockCreditsManager) ReserveCredits(userID uuid.UUID, amount int, reference, description string) (*models.CreditReservation, error) {
+	args := m.Called(userID, amount, reference, description)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditReservation), args.Error(1)
+}
+
+func (m *MockCreditsManager) RefundReservation(reference, description string) (bool, error) {
+	args := m.Called(reference, description)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCreditsManager) CaptureReservation(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCreditsManager) ReduceReservation(id uuid.UUID, amount int) error {
+	args := m.Called(id, amount)
+	return args.Error(0)
+}
+
+func (m *MockCreditsManager) ReleaseReservation(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockCreditsManager) RefreshMonthlyCredits(userID uuid.UUID) error {
 	args := m.Called(userID)
 	return args.Error(0)
@@ -55,8 +88,8 @@ func (m *MockCreditsManager) UpdateAllowance(userID uuid.UUID, tier models.Subsc
 	return args.Error(0)
 }
 
-func (m *MockCreditsManager) GetTransactionHistory(userID uuid.UUID, limit int) ([]models.CreditTransaction, error) {
-	args := m.Called(userID, limit)
+func (m *MockCreditsManager) GetTransactionHistory(userID uuid.UUID, limit, offset int) ([]models.CreditTransaction, error) {
+	args := m.Called(userID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEmailVerifier is a mock implementation of EmailVerifier interface
+type MockEmailVerifier struct {
+	mock.Mock
+}
+
+// SendVerification mocks the SendVerification method
+func (m *MockEmailVerifier) SendVerification(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+// Verify mocks the Verify method
+func (m *MockEmailVerifier) Verify(token string) (*models.User, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"time"
+
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFeatureUsageRecorder is a mock implementation of FeatureUsageRecorder interface
+type MockFeatureUsageRecorder struct {
+	mock.Mock
+}
+
+// Record mocks the Record method
+func (m *MockFeatureUsageRecorder) Record(userID uuid.UUID, feature string, credits int, latency time.Duration, success bool) {
+	m.Called(userID, feature, credits, latency, success)
+}
+
+// MockFeatureUsageReporter is a mock implementation of FeatureUsageReporter interface
+type MockFeatureUsageReporter struct {
+	mock.Mock
+}
+
+// Report mocks the Report method
+func (m *MockFeatureUsageReporter) Report(days int) (*services.FeatureUsageReport, error) {
+	args := m.Called(days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.FeatureUsageReport), args.Error(1)
+}
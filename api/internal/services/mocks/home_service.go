@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHomeProvider is a mock implementation of HomeProvider interface
+type MockHomeProvider struct {
+	mock.Mock
+}
+
+// GetHome mocks the GetHome method
+func (m *MockHomeProvider) GetHome(ctx context.Context, user *models.User) *services.HomeScreen {
+	args := m.Called(ctx, user)
+	return args.Get(0).(*services.HomeScreen)
+}
@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"time"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockInviteManager is a mock implementation of InviteManager interface
+type MockInviteManager struct {
+	mock.Mock
+}
+
+// InviteOnly mocks the InviteOnly method
+func (m *MockInviteManager) InviteOnly() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+// JoinWaitlist mocks the JoinWaitlist method
+func (m *MockInviteManager) JoinWaitlist(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+// Mint mocks the Mint method
+func (m *MockInviteManager) Mint(actor uuid.UUID, opts services.MintInviteOptions) (*models.InviteCode, error) {
+	args := m.Called(actor, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InviteCode), args.Error(1)
+}
+
+// ListInvites mocks the ListInvites method
+func (m *MockInviteManager) ListInvites(limit int) ([]models.InviteCode, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.InviteCode), args.Error(1)
+}
+
+// Waitlist mocks the Waitlist method
+func (m *MockInviteManager) Waitlist(pendingOnly bool, limit int) ([]models.WaitlistEntry, error) {
+	args := m.Called(pendingOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WaitlistEntry), args.Error(1)
+}
+
+// InviteFromWaitlist mocks the InviteFromWaitlist method
+func (m *MockInviteManager) InviteFromWaitlist(actor, entryID uuid.UUID, expiresAt *time.Time) (*models.WaitlistEntry, *models.InviteCode, error) {
+	args := m.Called(actor, entryID, expiresAt)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.WaitlistEntry), args.Get(1).(*models.InviteCode), args.Error(2)
+}
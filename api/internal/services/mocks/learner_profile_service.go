@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLearnerMemory is a mock implementation of LearnerMemory interface
+type MockLearnerMemory struct {
+	mock.Mock
+}
+
+// GetProfile mocks the GetProfile method
+func (m *MockLearnerMemory) GetProfile(userID uuid.UUID) (*models.LearnerProfile, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LearnerProfile), args.Error(1)
+}
+
+// UpdateProfile mocks the UpdateProfile method
+func (m *MockLearnerMemory) UpdateProfile(userID uuid.UUID, update services.LearnerProfileUpdate) (*models.LearnerProfile, error) {
+	args := m.Called(userID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LearnerProfile), args.Error(1)
+}
+
+// SystemPrompt mocks the SystemPrompt method
+func (m *MockLearnerMemory) SystemPrompt(userID uuid.UUID) *client.ConversationMessage {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*client.ConversationMessage)
+}
+
+// RequestExtraction mocks the RequestExtraction method
+func (m *MockLearnerMemory) RequestExtraction(userID, threadID uuid.UUID) {
+	m.Called(userID, threadID)
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+	"mime/multipart"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLongFormProcessor is a mock implementation of LongFormProcessor interface
+type MockLongFormProcessor struct {
+	mock.Mock
+}
+
+// ProcessLongFormMessage mocks the ProcessLongFormMessage method
+func (m *MockLongFormProcessor) ProcessLongFormMessage(
+	ctx context.Context,
+	threadID uuid.UUID,
+	chunks []*multipart.FileHeader,
+	tier models.SubscriptionTier,
+) (*services.ConversationTurn, error) {
+	args := m.Called(ctx, threadID, chunks, tier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ConversationTurn), args.Error(1)
+}
+
+// GetChunks mocks the GetChunks method
+func (m *MockLongFormProcessor) GetChunks(messageID uuid.UUID) ([]models.MessageChunk, error) {
+	args := m.Called(messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MessageChunk), args.Error(1)
+}
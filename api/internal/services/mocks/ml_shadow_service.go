@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"ling-app/api/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMLShadowReporter is a mock implementation of MLShadowReporter interface
+type MockMLShadowReporter struct {
+	mock.Mock
+}
+
+// Report mocks the Report method
+func (m *MockMLShadowReporter) Report(days int) (*services.MLShadowReport, error) {
+	args := m.Called(days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.MLShadowReport), args.Error(1)
+}
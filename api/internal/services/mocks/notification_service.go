@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationManager is a mock implementation of NotificationManager interface
+type MockNotificationManager struct {
+	mock.Mock
+}
+
+// Notify mocks the Notify method
+func (m *MockNotificationManager) Notify(userID uuid.UUID, notificationType models.NotificationType, title, body string, data models.JSONMap) error {
+	args := m.Called(userID, notificationType, title, body, data)
+	return args.Error(0)
+}
+
+// List mocks the List method
+func (m *MockNotificationManager) List(userID uuid.UUID, unreadOnly bool, limit, offset int) (*services.NotificationList, error) {
+	args := m.Called(userID, unreadOnly, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.NotificationList), args.Error(1)
+}
+
+// MarkRead mocks the MarkRead method
+func (m *MockNotificationManager) MarkRead(userID, notificationID uuid.UUID) error {
+	args := m.Called(userID, notificationID)
+	return args.Error(0)
+}
+
+// MarkAllRead mocks the MarkAllRead method
+func (m *MockNotificationManager) MarkAllRead(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
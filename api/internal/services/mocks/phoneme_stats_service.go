@@ -14,8 +14,8 @@ type MockPhonemeStatsProvider struct {
 }
 
 // GetUserStats mocks the GetUserStats method
-func (m *MockPhonemeStatsProvider) GetUserStats(userID uuid.UUID) (*services.UserPhonemeStatsResponse, error) {
-	args := m.Called(userID)
+func (m *MockPhonemeStatsProvider) GetUserStats(userID uuid.UUID, language string) (*services.UserPhonemeStatsResponse, error) {
+	args := m.Called(userID, language)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
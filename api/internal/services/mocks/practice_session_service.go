@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPracticeSessionManager is a mock implementation of PracticeSessionManager interface
+type MockPracticeSessionManager struct {
+	mock.Mock
+}
+
+// Start mocks the Start method
+func (m *MockPracticeSessionManager) Start(userID, threadID uuid.UUID, minutes int) (*models.PracticeSession, error) {
+	args := m.Called(userID, threadID, minutes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PracticeSession), args.Error(1)
+}
+
+// End mocks the End method
+func (m *MockPracticeSessionManager) End(userID, sessionID uuid.UUID) (*models.PracticeSession, error) {
+	args := m.Called(userID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PracticeSession), args.Error(1)
+}
+
+// List mocks the List method
+func (m *MockPracticeSessionManager) List(userID uuid.UUID, limit, offset int) ([]models.PracticeSession, error) {
+	args := m.Called(userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PracticeSession), args.Error(1)
+}
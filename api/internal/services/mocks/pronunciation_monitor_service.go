@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"ling-app/api/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPronunciationJobMonitor is a mock implementation of PronunciationJobMonitor interface
+type MockPronunciationJobMonitor struct {
+	mock.Mock
+}
+
+// Report mocks the Report method
+func (m *MockPronunciationJobMonitor) Report(limit int) (*services.PronunciationJobReport, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.PronunciationJobReport), args.Error(1)
+}
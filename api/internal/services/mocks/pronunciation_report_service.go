@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPronunciationReporter is a mock implementation of PronunciationReporter interface
+type MockPronunciationReporter struct {
+	mock.Mock
+}
+
+// CreateReport mocks the CreateReport method
+func (m *MockPronunciationReporter) CreateReport(ctx context.Context, user *models.User) (*services.PronunciationReport, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.PronunciationReport), args.Error(1)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPronunciationRetrier is a mock implementation of PronunciationRetrier interface
+type MockPronunciationRetrier struct {
+	mock.Mock
+}
+
+// RetryAnalysis mocks the RetryAnalysis method
+func (m *MockPronunciationRetrier) RetryAnalysis(userID, messageID uuid.UUID) (*models.Message, error) {
+	args := m.Called(userID, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Message), args.Error(1)
+}
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"ling-app/api/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPhonemeExampleProvider is a mock implementation of PhonemeExampleProvider interface
+type MockPhonemeExampleProvider struct {
+	mock.Mock
+}
+
+// PhonemeExamples mocks the PhonemeExamples method
+func (m *MockPhonemeExampleProvider) PhonemeExamples(ctx context.Context, phoneme string, speed float64) (*services.PhonemeExamples, error) {
+	args := m.Called(ctx, phoneme, speed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.PhonemeExamples), args.Error(1)
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"encoding/json"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRuntimeSettingsManager is a mock implementation of RuntimeSettingsManager interface
+type MockRuntimeSettingsManager struct {
+	mock.Mock
+}
+
+// Current mocks the Current method
+func (m *MockRuntimeSettingsManager) Current() services.RuntimeSettings {
+	args := m.Called()
+	return args.Get(0).(services.RuntimeSettings)
+}
+
+// Overrides mocks the Overrides method
+func (m *MockRuntimeSettingsManager) Overrides() ([]models.RuntimeSetting, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RuntimeSetting), args.Error(1)
+}
+
+// Set mocks the Set method
+func (m *MockRuntimeSettingsManager) Set(key string, value json.RawMessage, actor uuid.UUID) (services.RuntimeSettings, error) {
+	args := m.Called(key, value, actor)
+	return args.Get(0).(services.RuntimeSettings), args.Error(1)
+}
+
+// Reset mocks the Reset method
+func (m *MockRuntimeSettingsManager) Reset(key string, actor uuid.UUID) (services.RuntimeSettings, error) {
+	args := m.Called(key, actor)
+	return args.Get(0).(services.RuntimeSettings), args.Error(1)
+}
+
+// Refresh mocks the Refresh method
+func (m *MockRuntimeSettingsManager) Refresh() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,76 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSettingsManager is a mock implementation of SettingsManager interface
+type MockSettingsManager struct {
+	mock.Mock
+}
+
+// GetSettings mocks the GetSettings method
+func (m *MockSettingsManager) GetSettings(userID uuid.UUID) (*models.UserSettings, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+// SetAudioRetention mocks the SetAudioRetention method
+func (m *MockSettingsManager) SetAudioRetention(userID uuid.UUID, days int) (*models.UserSettings, error) {
+	args := m.Called(userID, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+// SetReplyLength mocks the SetReplyLength method
+func (m *MockSettingsManager) SetReplyLength(userID uuid.UUID, length string) (*models.UserSettings, error) {
+	args := m.Called(userID, length)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+// SetSpeechRate mocks the SetSpeechRate method
+func (m *MockSettingsManager) SetSpeechRate(userID uuid.UUID, rate float64) (*models.UserSettings, error) {
+	args := m.Called(userID, rate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+// SetEncryptContent mocks the SetEncryptContent method
+func (m *MockSettingsManager) SetEncryptContent(userID uuid.UUID, enabled bool) (*models.UserSettings, error) {
+	args := m.Called(userID, enabled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+// SetTimezone mocks the SetTimezone method
+func (m *MockSettingsManager) SetTimezone(userID uuid.UUID, timezone string) (*models.UserSettings, error) {
+	args := m.Called(userID, timezone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+// SetEmailFrequency mocks the SetEmailFrequency method
+func (m *MockSettingsManager) SetEmailFrequency(userID uuid.UUID, category, frequency string) (*models.UserSettings, error) {
+	args := m.Called(userID, category, frequency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
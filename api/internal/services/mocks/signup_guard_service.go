@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSignupReviewer is a mock implementation of SignupReviewer interface
+type MockSignupReviewer struct {
+	mock.Mock
+}
+
+// PendingReview mocks the PendingReview method
+func (m *MockSignupReviewer) PendingReview(limit int) ([]models.SignupSignal, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SignupSignal), args.Error(1)
+}
+
+// Review mocks the Review method
+func (m *MockSignupReviewer) Review(userID, reviewer uuid.UUID, grantCredits bool) (*models.SignupSignal, error) {
+	args := m.Called(userID, reviewer, grantCredits)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SignupSignal), args.Error(1)
+}
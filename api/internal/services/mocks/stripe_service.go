@@ -1,7 +1,10 @@
 package mocks
 
 import (
+	"context"
+
 	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -28,8 +31,8 @@ func (m *MockStripeProcessor) GetOrCreateSubscription(userID uuid.UUID, email, n
 	return args.Get(0).(*models.Subscription), args.Error(1)
 }
 
-func (m *MockStripeProcessor) CreateCheckoutSession(userID uuid.UUID, email, name string, tier models.SubscriptionTier) (string, error) {
-	args := m.Called(userID, email, name, tier)
+func (m *MockStripeProcessor) CreateCheckoutSession(userID uuid.UUID, email, name string, tier models.SubscriptionTier, currency string) (string, error) {
+	args := m.Called(userID, email, name, tier, currency)
 	return args.String(0), args.Error(1)
 }
 
@@ -38,7 +41,28 @@ func (m *MockStripeProcessor) CreatePortalSession(userID uuid.UUID) (string, err
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockStripeProcessor) PaymentBanner(sub *models.Subscription) *services.PaymentBanner {
+	args := m.Called(sub)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*services.PaymentBanner)
+}
+
 func (m *MockStripeProcessor) HandleWebhook(payload []byte, signature string) error {
 	args := m.Called(payload, signature)
 	return args.Error(0)
 }
+
+// MockPricingProvider is a mock implementation of PricingProvider interface
+type MockPricingProvider struct {
+	mock.Mock
+}
+
+func (m *MockPricingProvider) Pricing(ctx context.Context, currency, country string) (*services.Pricing, error) {
+	args := m.Called(currency, country)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Pricing), args.Error(1)
+}
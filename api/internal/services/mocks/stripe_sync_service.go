@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"ling-app/api/internal/services"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStripeSyncer is a mock implementation of StripeSyncer interface
+type MockStripeSyncer struct {
+	mock.Mock
+}
+
+// Sync mocks the Sync method
+func (m *MockStripeSyncer) Sync(ctx context.Context, opts services.StripeSyncOptions) (*services.StripeSyncReport, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.StripeSyncReport), args.Error(1)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAccountSupport is a mock implementation of AccountSupport interface
+type MockAccountSupport struct {
+	mock.Mock
+}
+
+// AdjustCredits mocks the AdjustCredits method
+func (m *MockAccountSupport) AdjustCredits(actor string, userID uuid.UUID, amount int, reason string) error {
+	args := m.Called(actor, userID, amount, reason)
+	return args.Error(0)
+}
+
+// OverrideTier mocks the OverrideTier method
+func (m *MockAccountSupport) OverrideTier(actor string, userID uuid.UUID, tier *models.SubscriptionTier, reason string) (*models.Subscription, error) {
+	args := m.Called(actor, userID, tier, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
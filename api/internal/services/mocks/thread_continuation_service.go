@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockThreadContinuer is a mock implementation of ThreadContinuer interface
+type MockThreadContinuer struct {
+	mock.Mock
+}
+
+// Continue mocks the Continue method
+func (m *MockThreadContinuer) Continue(userID, threadID uuid.UUID) (*models.Thread, error) {
+	args := m.Called(userID, threadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Thread), args.Error(1)
+}
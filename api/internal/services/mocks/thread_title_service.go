@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockThreadTitler is a mock implementation of ThreadTitler interface
+type MockThreadTitler struct {
+	mock.Mock
+}
+
+// RequestTitle mocks the RequestTitle method
+func (m *MockThreadTitler) RequestTitle(threadID uuid.UUID, userText, assistantText string) {
+	m.Called(threadID, userText, assistantText)
+}
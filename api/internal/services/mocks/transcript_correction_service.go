@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTranscriptCorrector is a mock implementation of TranscriptCorrector interface
+type MockTranscriptCorrector struct {
+	mock.Mock
+}
+
+// CorrectTranscript mocks the CorrectTranscript method
+func (m *MockTranscriptCorrector) CorrectTranscript(userID, threadID, messageID uuid.UUID, content string) (*models.Message, error) {
+	args := m.Called(userID, threadID, messageID, content)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Message), args.Error(1)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUsageLimiter is a mock implementation of UsageLimiter interface
+type MockUsageLimiter struct {
+	mock.Mock
+}
+
+// CheckThreadLimit mocks the CheckThreadLimit method
+func (m *MockUsageLimiter) CheckThreadLimit(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// CheckMessageLimit mocks the CheckMessageLimit method
+func (m *MockUsageLimiter) CheckMessageLimit(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// GetUsage mocks the GetUsage method
+func (m *MockUsageLimiter) GetUsage(userID uuid.UUID) (*services.Usage, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Usage), args.Error(1)
+}
+
+// Tier mocks the Tier method
+func (m *MockUsageLimiter) Tier(userID uuid.UUID) (models.SubscriptionTier, error) {
+	args := m.Called(userID)
+	return args.Get(0).(models.SubscriptionTier), args.Error(1)
+}
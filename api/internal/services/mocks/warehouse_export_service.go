@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"ling-app/api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWarehouseExporter is a mock implementation of WarehouseExporter interface
+type MockWarehouseExporter struct {
+	mock.Mock
+}
+
+// Export mocks the Export method
+func (m *MockWarehouseExporter) Export(ctx context.Context, actor *uuid.UUID) (*services.WarehouseExportReport, error) {
+	args := m.Called(ctx, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.WarehouseExportReport), args.Error(1)
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// EmailDigestHour is the local hour from which a user's daily digest goes out
+const EmailDigestHour = 9
+
+// NotificationEmailPolicy limits how often a user is emailed
+type NotificationEmailPolicy struct {
+	// MaxPerDay caps the instant emails a user gets in any 24 hours.
+	// Notifications over the cap wait for the daily digest. Billing emails
+	// and the digest itself don't count.
+	MaxPerDay int
+
+	// BatchWindow is how long an instant notification waits for others to
+	// share its email
+	BatchWindow time.Duration
+}
+
+// NotificationEmailWorker emails notifications. It sweeps for notifications
+// waiting to be emailed and, per user: sends billing ones straight away,
+// batches the rest of the instant ones into one email per batch window up to
+// the daily cap, and gathers daily ones (and any over the cap) into a digest
+// sent once a day from EmailDigestHour in the user's timezone. Notifications
+// the user turned off, or read in the app before their email went out, are
+// skipped.
+type NotificationEmailWorker struct {
+	exec             repository.Executor
+	notificationRepo repository.NotificationRepository
+	deliveryRepo     repository.EmailDeliveryRepository
+	userRepo         repository.UserRepository
+	settings         SettingsManager
+	email            client.EmailClient
+	policy           NotificationEmailPolicy
+	interval         time.Duration
+	appURL           string
+
+	now func() time.Time
+}
+
+// NewNotificationEmailWorker creates a new notification email worker
+func NewNotificationEmailWorker(
+	database *db.DB,
+	notificationRepo repository.NotificationRepository,
+	deliveryRepo repository.EmailDeliveryRepository,
+	userRepo repository.UserRepository,
+	settings SettingsManager,
+	email client.EmailClient,
+	policy NotificationEmailPolicy,
+	interval time.Duration,
+	frontendURL string,
+) *NotificationEmailWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &NotificationEmailWorker{
+		exec:             database.DB,
+		notificationRepo: notificationRepo,
+		deliveryRepo:     deliveryRepo,
+		userRepo:         userRepo,
+		settings:         settings,
+		email:            email,
+		policy:           policy,
+		interval:         interval,
+		appURL:           frontendURL,
+		now:              time.Now,
+	}
+}
+
+// NewNotificationEmailWorkerForTest creates a NotificationEmailWorker with injected dependencies for testing.
+func NewNotificationEmailWorkerForTest(
+	exec repository.Executor,
+	notificationRepo repository.NotificationRepository,
+	deliveryRepo repository.EmailDeliveryRepository,
+	userRepo repository.UserRepository,
+	settings SettingsManager,
+	email client.EmailClient,
+	policy NotificationEmailPolicy,
+	now func() time.Time,
+) *NotificationEmailWorker {
+	return &NotificationEmailWorker{
+		exec:             exec,
+		notificationRepo: notificationRepo,
+		deliveryRepo:     deliveryRepo,
+		userRepo:         userRepo,
+		settings:         settings,
+		email:            email,
+		policy:           policy,
+		interval:         time.Minute,
+		appURL:           "http://localhost:3000",
+		now:              now,
+	}
+}
+
+// Start sweeps for notifications to email until ctx is cancelled
+func (w *NotificationEmailWorker) Start(ctx context.Context) {
+	log.Printf("[NotificationEmail] Checking for notifications to email every %s", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Sweep()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep delivers whatever is due for every user with notifications waiting
+func (w *NotificationEmailWorker) Sweep() {
+	userIDs, err := w.notificationRepo.FindUsersWithPendingEmail(w.exec)
+	if err != nil {
+		log.Printf("[NotificationEmail] Failed to find pending notifications: %v", err)
+		return
+	}
+	for _, userID := range userIDs {
+		if err := w.Deliver(userID); err != nil {
+			log.Printf("[NotificationEmail] Failed to deliver to user %s: %v", userID, err)
+		}
+	}
+}
+
+// Deliver emails the user's notifications that are due. Notifications that
+// aren't due yet stay pending for a later sweep.
+func (w *NotificationEmailWorker) Deliver(userID uuid.UUID) error {
+	pending, err := w.notificationRepo.FindPendingEmailByUserID(w.exec, userID)
+	if err != nil {
+		return fmt.Errorf("find pending notifications: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	user, err := w.userRepo.FindByID(w.exec, userID)
+	if err != nil {
+		return fmt.Errorf("find user: %w", err)
+	}
+	settings, err := w.settings.GetSettings(userID)
+	if err != nil {
+		return fmt.Errorf("get settings: %w", err)
+	}
+
+	var billing, instant, daily []models.Notification
+	var skipped []uuid.UUID
+	for _, n := range pending {
+		category := n.Type.EmailCategory()
+		switch {
+		case user.IsGuest():
+			skipped = append(skipped, n.ID)
+		case category == models.EmailCategoryBilling:
+			billing = append(billing, n)
+		case n.ReadAt != nil:
+			skipped = append(skipped, n.ID)
+		case settings.EmailFrequency(category) == models.EmailFrequencyInstant:
+			instant = append(instant, n)
+		case settings.EmailFrequency(category) == models.EmailFrequencyDaily:
+			daily = append(daily, n)
+		default:
+			skipped = append(skipped, n.ID)
+		}
+	}
+	if len(skipped) > 0 {
+		if err := w.notificationRepo.SetEmailStatus(w.exec, skipped, models.EmailStatusSkipped); err != nil {
+			return fmt.Errorf("skip notifications: %w", err)
+		}
+	}
+
+	now := w.now()
+	for _, n := range billing {
+		if err := w.send(user, models.EmailDeliveryBilling, []models.Notification{n}, now); err != nil {
+			return err
+		}
+	}
+
+	if len(instant) > 0 && now.Sub(instant[0].CreatedAt) >= w.policy.BatchWindow {
+		sent, err := w.deliveryRepo.CountSince(w.exec, userID, models.EmailDeliveryInstant, now.Add(-24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("count emails: %w", err)
+		}
+		if sent < int64(w.policy.MaxPerDay) {
+			if err := w.send(user, models.EmailDeliveryInstant, instant, now); err != nil {
+				return err
+			}
+		} else {
+			daily = append(daily, instant...)
+		}
+	}
+
+	if len(daily) > 0 {
+		last, err := w.deliveryRepo.LastSentAt(w.exec, userID, models.EmailDeliveryDigest)
+		if err != nil {
+			return fmt.Errorf("find last digest: %w", err)
+		}
+		if digestDue(now, settings.Location(), last) {
+			return w.send(user, models.EmailDeliveryDigest, daily, now)
+		}
+	}
+	return nil
+}
+
+// digestDue reports whether today's digest, in loc, can go out at now: it is
+// past EmailDigestHour and the last digest went out before that
+func digestDue(now time.Time, loc *time.Location, last *time.Time) bool {
+	local := now.In(loc)
+	from := time.Date(local.Year(), local.Month(), local.Day(), EmailDigestHour, 0, 0, 0, loc)
+	if local.Before(from) {
+		return false
+	}
+	return last == nil || last.Before(from)
+}
+
+// send emails the notifications as one message, then records the email and
+// marks them sent
+func (w *NotificationEmailWorker) send(user *models.User, kind string, notifications []models.Notification, now time.Time) error {
+	subject, body := w.compose(user, kind, notifications)
+	if err := w.email.Send(context.Background(), user.Email, subject, body); err != nil {
+		return fmt.Errorf("send %s email: %w", kind, err)
+	}
+
+	ids := make([]uuid.UUID, len(notifications))
+	for i, n := range notifications {
+		ids[i] = n.ID
+	}
+	if err := w.notificationRepo.SetEmailStatus(w.exec, ids, models.EmailStatusSent); err != nil {
+		return fmt.Errorf("mark notifications emailed: %w", err)
+	}
+	delivery := &models.EmailDelivery{UserID: user.ID, Kind: kind, Notifications: len(notifications), SentAt: now}
+	if err := w.deliveryRepo.Create(w.exec, delivery); err != nil {
+		// The email is out; at worst the user gets one more before the cap
+		log.Printf("[NotificationEmail] Failed to record email to user %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+// compose writes the email: a single notification as itself, several as a
+// list
+func (w *NotificationEmailWorker) compose(user *models.User, kind string, notifications []models.Notification) (subject, body string) {
+	var b strings.Builder
+	b.WriteString("Hi")
+	if user.Name != "" {
+		b.WriteString(" " + user.Name)
+	}
+	b.WriteString(",\n\n")
+
+	switch {
+	case len(notifications) == 1:
+		subject = notifications[0].Title
+		b.WriteString(notifications[0].Body + "\n\n")
+	default:
+		if kind == models.EmailDeliveryDigest {
+			subject = fmt.Sprintf("Your daily summary: %d updates", len(notifications))
+		} else {
+			subject = fmt.Sprintf("You have %d new updates", len(notifications))
+		}
+		for _, n := range notifications {
+			b.WriteString("- " + n.Title + "\n")
+			if n.Body != "" {
+				b.WriteString("  " + n.Body + "\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("Open the app: " + w.appURL + "\n")
+	if kind == models.EmailDeliveryBilling {
+		b.WriteString("Manage your plan: " + w.appURL + "/settings\n")
+	} else {
+		b.WriteString("Choose which emails you get: " + w.appURL + "/settings\n")
+	}
+	return subject, b.String()
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+type notificationEmailDeps struct {
+	notifications *repomocks.MockNotificationRepository
+	deliveries    *repomocks.MockEmailDeliveryRepository
+	users         *repomocks.MockUserRepository
+	settings      *repomocks.MockUserSettingsRepository
+	email         *clientmocks.MockEmailClient
+}
+
+func newNotificationEmailWorker(now time.Time, user *models.User, settings *models.UserSettings, pending []models.Notification) (*NotificationEmailWorker, *notificationEmailDeps) {
+	deps := &notificationEmailDeps{
+		notifications: new(repomocks.MockNotificationRepository),
+		deliveries:    new(repomocks.MockEmailDeliveryRepository),
+		users:         new(repomocks.MockUserRepository),
+		settings:      new(repomocks.MockUserSettingsRepository),
+		email:         new(clientmocks.MockEmailClient),
+	}
+	deps.notifications.On("FindPendingEmailByUserID", mock.Anything, user.ID).Return(pending, nil)
+	deps.users.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	deps.settings.On("FindByUserID", mock.Anything, user.ID).Return(settings, nil)
+
+	worker := NewNotificationEmailWorkerForTest(
+		nil,
+		deps.notifications,
+		deps.deliveries,
+		deps.users,
+		NewSettingsServiceForTest(nil, deps.settings),
+		deps.email,
+		NotificationEmailPolicy{MaxPerDay: 2, BatchWindow: 10 * time.Minute},
+		func() time.Time { return now },
+	)
+	return worker, deps
+}
+
+func pendingNotification(userID uuid.UUID, notificationType models.NotificationType, title string, createdAt time.Time) models.Notification {
+	return models.Notification{ID: uuid.New(), UserID: userID, Type: notificationType, Title: title, Body: title + " body", CreatedAt: createdAt}
+}
+
+func TestNotificationEmailWorker_Deliver(t *testing.T) {
+	// 14:00 UTC is past the digest hour in UTC
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Email: "learner@example.com", Name: "Ana"}
+	settings := models.DefaultUserSettings(user.ID)
+
+	t.Run("sends billing straight away, even over the cap", func(t *testing.T) {
+		n := pendingNotification(user.ID, models.NotificationSubscriptionEnding, "Your plan ends soon", now)
+		worker, deps := newNotificationEmailWorker(now, user, settings, []models.Notification{n})
+		deps.email.On("Send", mock.Anything, user.Email, "Your plan ends soon", mock.MatchedBy(func(body string) bool {
+			return assert.Contains(t, body, "Hi Ana,") && assert.Contains(t, body, "Manage your plan")
+		})).Return(nil).Once()
+		deps.notifications.On("SetEmailStatus", mock.Anything, []uuid.UUID{n.ID}, models.EmailStatusSent).Return(nil)
+		deps.deliveries.On("Create", mock.Anything, mock.MatchedBy(func(d *models.EmailDelivery) bool {
+			return d.Kind == models.EmailDeliveryBilling && d.Notifications == 1
+		})).Return(nil)
+
+		require.NoError(t, worker.Deliver(user.ID))
+		deps.email.AssertExpectations(t)
+		deps.deliveries.AssertNotCalled(t, "CountSince", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("waits out the batch window", func(t *testing.T) {
+		n := pendingNotification(user.ID, models.NotificationExportReady, "Your export is ready", now.Add(-time.Minute))
+		worker, deps := newNotificationEmailWorker(now, user, settings, []models.Notification{n})
+
+		require.NoError(t, worker.Deliver(user.ID))
+		deps.email.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		deps.notifications.AssertNotCalled(t, "SetEmailStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("batches instant notifications into one email", func(t *testing.T) {
+		first := pendingNotification(user.ID, models.NotificationExportReady, "Your export is ready", now.Add(-15*time.Minute))
+		second := pendingNotification(user.ID, models.NotificationCreditsLow, "You're running low on credits", now.Add(-time.Minute))
+		worker, deps := newNotificationEmailWorker(now, user, settings, []models.Notification{first, second})
+		deps.deliveries.On("CountSince", mock.Anything, user.ID, models.EmailDeliveryInstant, now.Add(-24*time.Hour)).Return(int64(1), nil)
+		deps.email.On("Send", mock.Anything, user.Email, "You have 2 new updates", mock.MatchedBy(func(body string) bool {
+			return assert.Contains(t, body, "- Your export is ready\n") && assert.Contains(t, body, "- You're running low on credits\n")
+		})).Return(nil).Once()
+		deps.notifications.On("SetEmailStatus", mock.Anything, []uuid.UUID{first.ID, second.ID}, models.EmailStatusSent).Return(nil)
+		deps.deliveries.On("Create", mock.Anything, mock.MatchedBy(func(d *models.EmailDelivery) bool {
+			return d.Kind == models.EmailDeliveryInstant && d.Notifications == 2
+		})).Return(nil)
+
+		require.NoError(t, worker.Deliver(user.ID))
+		deps.email.AssertExpectations(t)
+		deps.deliveries.AssertExpectations(t)
+	})
+
+	t.Run("folds instant notifications over the cap into the digest", func(t *testing.T) {
+		n := pendingNotification(user.ID, models.NotificationExportReady, "Your export is ready", now.Add(-time.Hour))
+		worker, deps := newNotificationEmailWorker(now, user, settings, []models.Notification{n})
+		deps.deliveries.On("CountSince", mock.Anything, user.ID, models.EmailDeliveryInstant, mock.Anything).Return(int64(2), nil)
+		yesterday := now.Add(-24 * time.Hour)
+		deps.deliveries.On("LastSentAt", mock.Anything, user.ID, models.EmailDeliveryDigest).Return(&yesterday, nil)
+		deps.email.On("Send", mock.Anything, user.Email, "Your export is ready", mock.Anything).Return(nil).Once()
+		deps.notifications.On("SetEmailStatus", mock.Anything, []uuid.UUID{n.ID}, models.EmailStatusSent).Return(nil)
+		deps.deliveries.On("Create", mock.Anything, mock.MatchedBy(func(d *models.EmailDelivery) bool {
+			return d.Kind == models.EmailDeliveryDigest
+		})).Return(nil)
+
+		require.NoError(t, worker.Deliver(user.ID))
+		deps.deliveries.AssertExpectations(t)
+	})
+
+	t.Run("sends the digest once a day", func(t *testing.T) {
+		n := pendingNotification(user.ID, models.NotificationGoalCompleted, "Goal completed!", now.Add(-time.Hour))
+		worker, deps := newNotificationEmailWorker(now, user, settings, []models.Notification{n})
+		earlier := now.Add(-3 * time.Hour)
+		deps.deliveries.On("LastSentAt", mock.Anything, user.ID, models.EmailDeliveryDigest).Return(&earlier, nil)
+
+		require.NoError(t, worker.Deliver(user.ID))
+		deps.email.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips read and turned off notifications", func(t *testing.T) {
+		off := models.DefaultUserSettings(user.ID)
+		off.EmailUpdates = models.EmailFrequencyOff
+		readAt := now
+		read := pendingNotification(user.ID, models.NotificationGoalCompleted, "Goal completed!", now.Add(-time.Hour))
+		read.ReadAt = &readAt
+		muted := pendingNotification(user.ID, models.NotificationExportReady, "Your export is ready", now.Add(-time.Hour))
+		worker, deps := newNotificationEmailWorker(now, user, off, []models.Notification{read, muted})
+		deps.notifications.On("SetEmailStatus", mock.Anything, []uuid.UUID{read.ID, muted.ID}, models.EmailStatusSkipped).Return(nil)
+
+		require.NoError(t, worker.Deliver(user.ID))
+		deps.notifications.AssertExpectations(t)
+		deps.email.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDigestDue(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// 23:30 UTC on the 9th is 08:30 on the 10th in Tokyo
+	beforeNine := time.Date(2026, 3, 9, 23, 30, 0, 0, time.UTC)
+	afterNine := beforeNine.Add(time.Hour)
+	yesterday := afterNine.Add(-24 * time.Hour)
+
+	assert.False(t, digestDue(beforeNine, tokyo, nil), "before 9am local")
+	assert.True(t, digestDue(afterNine, tokyo, nil), "after 9am local, never sent")
+	assert.True(t, digestDue(afterNine, tokyo, &yesterday), "last sent yesterday")
+	assert.False(t, digestDue(afterNine, tokyo, &afterNine), "already sent today")
+	assert.True(t, digestDue(beforeNine, time.UTC, &yesterday), "23:30 is past 9am in UTC")
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// Default and max page sizes for notification listings
+const (
+	DefaultNotificationLimit = 20
+	MaxNotificationLimit     = 100
+)
+
+// NotificationManager defines the interface for in-app notification operations
+type NotificationManager interface {
+	Notify(userID uuid.UUID, notificationType models.NotificationType, title, body string, data models.JSONMap) error
+	List(userID uuid.UUID, unreadOnly bool, limit, offset int) (*NotificationList, error)
+	MarkRead(userID, notificationID uuid.UUID) error
+	MarkAllRead(userID uuid.UUID) error
+}
+
+// NotificationList is a page of notifications plus the user's unread count
+type NotificationList struct {
+	Notifications []models.Notification `json:"notifications"`
+	UnreadCount   int64                 `json:"unreadCount"`
+}
+
+// NotificationService stores and serves in-app notifications
+type NotificationService struct {
+	exec             repository.Executor
+	notificationRepo repository.NotificationRepository
+
+	// EmailDelivery queues notifications with an email category for the
+	// NotificationEmailWorker. Off when email isn't configured.
+	EmailDelivery bool
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(database *db.DB, notificationRepo repository.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		exec:             database.DB,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// NewNotificationServiceForTest creates a NotificationService with injected dependencies for testing.
+func NewNotificationServiceForTest(exec repository.Executor, notificationRepo repository.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		exec:             exec,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Notify creates a notification for a user
+func (s *NotificationService) Notify(userID uuid.UUID, notificationType models.NotificationType, title, body string, data models.JSONMap) error {
+	notification := &models.Notification{
+		UserID:    userID,
+		Type:      notificationType,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+	if s.EmailDelivery && notificationType.EmailCategory() != "" {
+		notification.EmailStatus = models.EmailStatusPending
+	} else {
+		notification.EmailStatus = models.EmailStatusNone
+	}
+	if err := s.notificationRepo.Create(s.exec, notification); err != nil {
+		return fmt.Errorf("create notification: %w", err)
+	}
+	return nil
+}
+
+// Subscribe warns users whose balance runs low
+func (s *NotificationService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "notifications", func(ctx context.Context, e events.CreditsLow) error {
+		body := fmt.Sprintf("You have %d credits left. Upgrade your plan or wait for your monthly refresh to keep practicing.", e.Balance)
+		return s.Notify(e.UserID, models.NotificationCreditsLow, "You're running low on credits", body, models.JSONMap{
+			"balance": e.Balance,
+		})
+	})
+}
+
+// List returns a page of the user's notifications, newest first, skipping the
+// first offset
+func (s *NotificationService) List(userID uuid.UUID, unreadOnly bool, limit, offset int) (*NotificationList, error) {
+	if limit <= 0 {
+		limit = DefaultNotificationLimit
+	}
+	if limit > MaxNotificationLimit {
+		limit = MaxNotificationLimit
+	}
+
+	notifications, err := s.notificationRepo.FindByUserID(s.exec, userID, unreadOnly, limit, max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	unread, err := s.notificationRepo.CountUnread(s.exec, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count unread notifications: %w", err)
+	}
+
+	return &NotificationList{
+		Notifications: notifications,
+		UnreadCount:   unread,
+	}, nil
+}
+
+// MarkRead marks a single notification as read
+func (s *NotificationService) MarkRead(userID, notificationID uuid.UUID) error {
+	err := s.notificationRepo.MarkRead(s.exec, notificationID, userID, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotificationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification for the user as read
+func (s *NotificationService) MarkAllRead(userID uuid.UUID) error {
+	if err := s.notificationRepo.MarkAllRead(s.exec, userID, time.Now()); err != nil {
+		return fmt.Errorf("mark all notifications read: %w", err)
+	}
+	return nil
+}
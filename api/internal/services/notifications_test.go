@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	userID := uuid.New()
+	repo := new(repomocks.MockNotificationRepository)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == userID && n.Type == models.NotificationGoalCompleted && n.Title == "Goal completed!"
+	})).Return(nil)
+
+	service := NewNotificationServiceForTest(nil, repo)
+	err := service.Notify(userID, models.NotificationGoalCompleted, "Goal completed!", "Nice work", nil)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestNotificationService_Notify_QueuesEmail(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name             string
+		emailDelivery    bool
+		notificationType models.NotificationType
+		want             string
+	}{
+		{"emailable type", true, models.NotificationGoalCompleted, models.EmailStatusPending},
+		{"dunning emails payment failures itself", true, models.NotificationPaymentFailed, models.EmailStatusNone},
+		{"email not configured", false, models.NotificationGoalCompleted, models.EmailStatusNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(repomocks.MockNotificationRepository)
+			repo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+				return n.EmailStatus == tt.want
+			})).Return(nil)
+
+			service := NewNotificationServiceForTest(nil, repo)
+			service.EmailDelivery = tt.emailDelivery
+
+			assert.NoError(t, service.Notify(userID, tt.notificationType, "Title", "Body", nil))
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_Subscribe_CreditsLow(t *testing.T) {
+	userID := uuid.New()
+	repo := new(repomocks.MockNotificationRepository)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == userID && n.Type == models.NotificationCreditsLow && n.Data["balance"] == 3
+	})).Return(nil)
+
+	service := NewNotificationServiceForTest(nil, repo)
+	bus := events.NewBus()
+	service.Subscribe(bus)
+	bus.Publish(context.Background(), events.CreditsLow{UserID: userID, Balance: 3, Threshold: LowCreditsThreshold})
+
+	repo.AssertExpectations(t)
+}
+
+func TestNotificationService_List(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("clamps limit and returns unread count", func(t *testing.T) {
+		repo := new(repomocks.MockNotificationRepository)
+		repo.On("FindByUserID", mock.Anything, userID, true, MaxNotificationLimit, 0).
+			Return([]models.Notification{{Title: "a"}}, nil)
+		repo.On("CountUnread", mock.Anything, userID).Return(int64(3), nil)
+
+		service := NewNotificationServiceForTest(nil, repo)
+		list, err := service.List(userID, true, 1000, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, list.Notifications, 1)
+		assert.Equal(t, int64(3), list.UnreadCount)
+	})
+
+	t.Run("empty list is not nil", func(t *testing.T) {
+		repo := new(repomocks.MockNotificationRepository)
+		repo.On("FindByUserID", mock.Anything, userID, false, DefaultNotificationLimit, 0).Return(nil, nil)
+		repo.On("CountUnread", mock.Anything, userID).Return(int64(0), nil)
+
+		service := NewNotificationServiceForTest(nil, repo)
+		list, err := service.List(userID, false, 0, 0)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, list.Notifications)
+	})
+}
+
+func TestNotificationService_MarkRead_NotFound(t *testing.T) {
+	userID := uuid.New()
+	notificationID := uuid.New()
+	repo := new(repomocks.MockNotificationRepository)
+	repo.On("MarkRead", mock.Anything, notificationID, userID, mock.Anything).Return(repository.ErrNotFound)
+
+	service := NewNotificationServiceForTest(nil, repo)
+	err := service.MarkRead(userID, notificationID)
+
+	assert.ErrorIs(t, err, ErrNotificationNotFound)
+}
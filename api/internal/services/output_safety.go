@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"log"
+	"regexp"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// MaxSafetyRegenerations is how many times a flagged reply is regenerated
+// before the learner gets SafeFallbackResponse instead
+const MaxSafetyRegenerations = 2
+
+// SafeFallbackResponse replaces a reply that stays unsafe after every regeneration
+const SafeFallbackResponse = "Sorry, let's talk about something else. What would you like to practice next?"
+
+// Sources of a safety verdict
+const (
+	SafetySourceRules      = "rules"
+	SafetySourceModeration = "moderation"
+)
+
+// profanityPattern is the built-in word filter. It only needs to catch what
+// a tutor should never say; the moderation API handles everything subtler.
+var profanityPattern = regexp.MustCompile(`(?i)\b(?:` +
+	`fuck\w*|motherfuck\w*|shit\w*|bullshit|bitch\w*|cunt\w*|asshole\w*|bastard\w*|` +
+	`whore\w*|slut\w*|pussy|pussies|nigger\w*|nigga\w*|faggot\w*|retard\w*` +
+	`)\b`)
+
+// SafetyRetryPrompt is the system message added when regenerating a flagged reply
+func SafetyRetryPrompt() client.ConversationMessage {
+	return client.ConversationMessage{
+		Role: "system",
+		Content: "Your previous reply was withheld because it was not appropriate for a language learner, " +
+			"who may be a minor. Reply again in character, keeping it friendly and suitable for all ages: " +
+			"no profanity, sexual content, violence, self-harm, or hateful language.",
+	}
+}
+
+// SafetyVerdict is the outcome of an output safety check
+type SafetyVerdict struct {
+	Safe       bool
+	Source     string
+	Categories []string
+}
+
+// OutputSafetyChecker screens generated text before it is spoken or stored.
+// The word filter always runs; the moderation API runs when configured, and
+// if it is unreachable the word filter's verdict stands.
+type OutputSafetyChecker struct {
+	exec         repository.Executor
+	moderation   client.ModerationClient
+	incidentRepo repository.SafetyIncidentRepository
+}
+
+// NewOutputSafetyChecker creates a new output safety checker. moderation may be nil.
+func NewOutputSafetyChecker(
+	database *db.DB,
+	moderation client.ModerationClient,
+	incidentRepo repository.SafetyIncidentRepository,
+) *OutputSafetyChecker {
+	return &OutputSafetyChecker{
+		exec:         database.DB,
+		moderation:   moderation,
+		incidentRepo: incidentRepo,
+	}
+}
+
+// NewOutputSafetyCheckerForTest creates an OutputSafetyChecker with injected dependencies for testing.
+func NewOutputSafetyCheckerForTest(
+	exec repository.Executor,
+	moderation client.ModerationClient,
+	incidentRepo repository.SafetyIncidentRepository,
+) *OutputSafetyChecker {
+	return &OutputSafetyChecker{
+		exec:         exec,
+		moderation:   moderation,
+		incidentRepo: incidentRepo,
+	}
+}
+
+// Check returns whether text is safe to speak and store
+func (c *OutputSafetyChecker) Check(ctx context.Context, text string) SafetyVerdict {
+	if profanityPattern.MatchString(text) {
+		return SafetyVerdict{Source: SafetySourceRules, Categories: []string{"profanity"}}
+	}
+
+	if c.moderation == nil {
+		return SafetyVerdict{Safe: true}
+	}
+
+	result, err := c.moderation.Moderate(ctx, text)
+	if err != nil {
+		log.Printf("[OutputSafety] Moderation unavailable, relying on word filter: %v", err)
+		return SafetyVerdict{Safe: true}
+	}
+	if result.Flagged {
+		return SafetyVerdict{Source: SafetySourceModeration, Categories: result.Categories}
+	}
+	return SafetyVerdict{Safe: true}
+}
+
+// RecordIncident logs and stores a flagged reply. The text isn't stored, only
+// what flagged it, so incidents can be reviewed without keeping unsafe content.
+func (c *OutputSafetyChecker) RecordIncident(threadID uuid.UUID, kind models.SafetyIncidentKind, verdict SafetyVerdict, attempt int) {
+	log.Printf("[OutputSafety] Withheld %s for thread %s (attempt %d): flagged by %s %v",
+		kind, threadID, attempt, verdict.Source, verdict.Categories)
+
+	incident := &models.SafetyIncident{
+		ThreadID:   threadID,
+		Kind:       kind,
+		Source:     verdict.Source,
+		Categories: verdict.Categories,
+		Attempt:    attempt,
+	}
+	if err := c.incidentRepo.Create(c.exec, incident); err != nil {
+		log.Printf("[OutputSafety] Failed to record incident for thread %s: %v", threadID, err)
+	}
+}
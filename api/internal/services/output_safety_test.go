@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestOutputSafetyChecker_Check(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		moderation     *client.ModerationResult
+		moderationErr  error
+		wantSafe       bool
+		wantSource     string
+		wantCategories []string
+	}{
+		{
+			name:       "clean reply",
+			text:       "¿Qué tal tu fin de semana?",
+			moderation: &client.ModerationResult{},
+			wantSafe:   true,
+		},
+		{
+			name:           "profanity caught by word filter",
+			text:           "That's some bullshit, honestly.",
+			wantSource:     SafetySourceRules,
+			wantCategories: []string{"profanity"},
+		},
+		{
+			name:       "word filter respects word boundaries",
+			text:       "Let's order a cocktail at Scunthorpe station.",
+			moderation: &client.ModerationResult{},
+			wantSafe:   true,
+		},
+		{
+			name:           "flagged by moderation",
+			text:           "Some subtly harmful reply",
+			moderation:     &client.ModerationResult{Flagged: true, Categories: []string{"self-harm"}},
+			wantSource:     SafetySourceModeration,
+			wantCategories: []string{"self-harm"},
+		},
+		{
+			name:          "moderation outage falls back to word filter",
+			text:          "Hola, ¿cómo estás?",
+			moderationErr: errors.New("timeout"),
+			wantSafe:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moderation := new(clientmocks.MockModerationClient)
+			moderation.On("Moderate", mock.Anything, tt.text).Return(tt.moderation, tt.moderationErr).Maybe()
+			checker := NewOutputSafetyCheckerForTest(nil, moderation, nil)
+
+			verdict := checker.Check(context.Background(), tt.text)
+
+			assert.Equal(t, tt.wantSafe, verdict.Safe)
+			assert.Equal(t, tt.wantSource, verdict.Source)
+			assert.Equal(t, tt.wantCategories, verdict.Categories)
+		})
+	}
+}
+
+func TestOutputSafetyChecker_Check_WithoutModeration(t *testing.T) {
+	checker := NewOutputSafetyCheckerForTest(nil, nil, nil)
+
+	assert.True(t, checker.Check(context.Background(), "Buenos días").Safe)
+	assert.False(t, checker.Check(context.Background(), "What the fuck").Safe)
+}
+
+type safetyTurnDeps struct {
+	messageRepo  *repomocks.MockMessageRepository
+	openAI       *clientmocks.MockOpenAIClient
+	moderation   *clientmocks.MockModerationClient
+	incidentRepo *repomocks.MockSafetyIncidentRepository
+}
+
+// newSafetyConversationService wires a service with the output safety check
+// whose TTS always fails, so replies are saved text-only.
+func newSafetyConversationService(threadID uuid.UUID, thread *models.Thread) (*ConversationService, *safetyTurnDeps) {
+	deps := &safetyTurnDeps{
+		messageRepo:  new(repomocks.MockMessageRepository),
+		openAI:       new(clientmocks.MockOpenAIClient),
+		moderation:   new(clientmocks.MockModerationClient),
+		incidentRepo: new(repomocks.MockSafetyIncidentRepository),
+	}
+	threadRepo := new(repomocks.MockThreadRepository)
+	tts := new(clientmocks.MockTTSClient)
+
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(thread, nil)
+	deps.messageRepo.On("FindByThreadID", mock.Anything, threadID).Return([]models.Message{{Role: "user", Content: "hola"}}, nil)
+	tts.On("Synthesize", mock.Anything, mock.Anything).Return(nil, errors.New("tts down"))
+	deps.incidentRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	safety := NewOutputSafetyCheckerForTest(nil, deps.moderation, deps.incidentRepo)
+	service := NewConversationService(
+		nil, deps.messageRepo, threadRepo, nil, deps.openAI, tts, nil, nil, nil, safety,
+		nil, // runtime settings (defaults)
+	)
+	return service, deps
+}
+
+func TestConversationService_GenerateAssistantResponse_RegeneratesFlaggedReply(t *testing.T) {
+	threadID := uuid.New()
+	service, deps := newSafetyConversationService(threadID, &models.Thread{ID: threadID})
+
+	deps.openAI.On("Generate", mock.Anything).Return("Some unsafe reply", nil).Once()
+	deps.openAI.On("Generate", mock.MatchedBy(func(history []client.ConversationMessage) bool {
+		return history[len(history)-1] == SafetyRetryPrompt()
+	})).Return("¡Hola! ¿Cómo estás?", nil).Once()
+	deps.moderation.On("Moderate", mock.Anything, "Some unsafe reply").
+		Return(&client.ModerationResult{Flagged: true, Categories: []string{"harassment"}}, nil)
+	deps.moderation.On("Moderate", mock.Anything, "¡Hola! ¿Cómo estás?").Return(&client.ModerationResult{}, nil)
+	deps.messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	message, _, err := service.generateAssistantResponse(context.Background(), threadID, true, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "¡Hola! ¿Cómo estás?", message.Content)
+	deps.openAI.AssertNumberOfCalls(t, "Generate", 2)
+	deps.incidentRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(incident *models.SafetyIncident) bool {
+		return incident.ThreadID == threadID &&
+			incident.Kind == models.SafetyIncidentResponse &&
+			incident.Source == SafetySourceModeration &&
+			incident.Attempt == 1
+	}))
+	deps.messageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+		return msg.Content == "Some unsafe reply"
+	}))
+}
+
+func TestConversationService_GenerateAssistantResponse_FallsBackWhenStillUnsafe(t *testing.T) {
+	threadID := uuid.New()
+	service, deps := newSafetyConversationService(threadID, &models.Thread{ID: threadID})
+
+	deps.openAI.On("Generate", mock.Anything).Return("Well, shit.", nil)
+	deps.messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	message, _, err := service.generateAssistantResponse(context.Background(), threadID, true, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, SafeFallbackResponse, message.Content)
+	deps.openAI.AssertNumberOfCalls(t, "Generate", MaxSafetyRegenerations+1)
+	deps.incidentRepo.AssertNumberOfCalls(t, "Create", MaxSafetyRegenerations+1)
+	deps.moderation.AssertNotCalled(t, "Moderate", mock.Anything, mock.Anything)
+}
+
+func TestConversationService_GenerateAssistantResponse_DropsUnsafeSuggestions(t *testing.T) {
+	threadID := uuid.New()
+	service, deps := newSafetyConversationService(threadID, &models.Thread{ID: threadID, SuggestReplies: true})
+
+	deps.openAI.On("Generate", mock.Anything).Return("¿Quieres un café?", nil)
+	deps.openAI.On("SuggestReplies", mock.Anything).Return([]string{"Sí, por favor", "Fuck off", "No, gracias"}, nil)
+	deps.moderation.On("Moderate", mock.Anything, mock.Anything).Return(&client.ModerationResult{}, nil)
+	deps.messageRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	message, _, err := service.generateAssistantResponse(context.Background(), threadID, true, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StringList{"Sí, por favor", "No, gracias"}, message.SuggestedReplies)
+	deps.incidentRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(incident *models.SafetyIncident) bool {
+		return incident.Kind == models.SafetyIncidentSuggestion && incident.Source == SafetySourceRules
+	}))
+}
@@ -1,8 +1,12 @@
 package services
 
 import (
+	"context"
+	"errors"
+
 	"ling-app/api/internal/client"
 	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
 
@@ -11,7 +15,7 @@ import (
 
 // PhonemeStatsProvider defines the interface for phoneme statistics operations
 type PhonemeStatsProvider interface {
-	GetUserStats(userID uuid.UUID) (*UserPhonemeStatsResponse, error)
+	GetUserStats(userID uuid.UUID, language string) (*UserPhonemeStatsResponse, error)
 	RecordPhonemeResults(userID uuid.UUID, phonemeDetails []client.PhonemeDetail) error
 }
 
@@ -21,6 +25,13 @@ type PhonemeStatsService struct {
 	exec     repository.Executor
 	statsRepo repository.PhonemeStatsRepository
 	subsRepo  repository.PhonemeSubstitutionRepository
+
+	// Snapshots keeps what each message added to the stats, so a corrected
+	// transcript's analysis can be taken back out (optional)
+	Snapshots repository.PhonemeStatsSnapshotRepository
+
+	// Runtime holds the phoneme weights; nil weighs every phoneme equally
+	Runtime *RuntimeSettingsService
 }
 
 // NewPhonemeStatsService creates a new phoneme stats service
@@ -37,78 +48,169 @@ func NewPhonemeStatsService(
 	}
 }
 
-// RecordPhonemeResults processes phoneme details from pronunciation analysis
-// and updates the user's aggregate statistics
+// RecordPhonemeResults processes phoneme details from an English
+// pronunciation analysis and updates the user's aggregate statistics
 func (s *PhonemeStatsService) RecordPhonemeResults(userID uuid.UUID, phonemeDetails []client.PhonemeDetail) error {
-	if len(phonemeDetails) == 0 {
-		return nil
+	return s.applyTally(userID, TallyPhonemes(phonemeDetails), 1)
+}
+
+// RecordMessageResults adds the phoneme details of a message's analysis (one
+// list per long-form chunk) to the user's stats in the thread's language and,
+// with Snapshots set, keeps a snapshot of what it added. A message analyzed
+// again has its earlier contribution taken out first, so it is never counted
+// twice.
+func (s *PhonemeStatsService) RecordMessageResults(userID, messageID uuid.UUID, language string, phonemeDetails ...[]client.PhonemeDetail) error {
+	tally := TallyPhonemes(phonemeDetails...)
+	tally.Language = language
+	if s.Snapshots == nil {
+		return s.applyTally(userID, tally, 1)
 	}
 
-	// Aggregate stats from this analysis
-	statsMap := make(map[string]*models.PhonemeStats)
-	subsMap := make(map[string]*models.PhonemeSubstitution)
+	if err := s.reverseSnapshot(messageID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if len(tally.Phonemes) == 0 {
+		return nil
+	}
+	if err := s.applyTally(userID, tally, 1); err != nil {
+		return err
+	}
+	return s.Snapshots.Save(s.exec, &models.PhonemeStatsSnapshot{
+		MessageID: messageID,
+		UserID:    userID,
+		Tally:     tally,
+	})
+}
 
-	for _, detail := range phonemeDetails {
-		// Skip insertions (extra phonemes user added) - we only track expected phonemes
-		if detail.Type == "insert" {
-			continue
+// Subscribe records the phoneme results of every confident analysis
+func (s *PhonemeStatsService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "phoneme_stats", func(ctx context.Context, e events.AnalysisCompleted) error {
+		if len(e.Phonemes) == 0 {
+			return nil
 		}
+		return s.RecordMessageResults(e.UserID, e.MessageID, e.Language, e.Phonemes...)
+	})
+}
 
-		expected := detail.Expected
-		if expected == "" {
-			continue
+// ReverseMessageResults takes what a message's analysis added back out of
+// its user's stats. Messages analyzed before snapshots were kept fall back to
+// the phoneme details stored on the message, which are what was recorded for
+// a confident single recording. Only English was recorded then, so for other
+// languages, and other messages, nothing is taken out.
+func (s *PhonemeStatsService) ReverseMessageResults(userID uuid.UUID, message *models.Message, language string) error {
+	if s.Snapshots != nil {
+		err := s.reverseSnapshot(message.ID)
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
 		}
+	}
+
+	if language != DefaultLanguage || message.PronunciationStatus != "complete" || message.PronunciationLowConfidence || message.Kind == models.MessageKindLongForm {
+		return nil
+	}
+	analysis, ok := parseAnalysis(message.PronunciationAnalysis)
+	if !ok {
+		return nil
+	}
+	return s.applyTally(userID, TallyPhonemes(analysis.PhonemeDetails), -1)
+}
+
+// reverseSnapshot takes a message's snapshot out of the stats.
+// repository.ErrNotFound if it has none.
+func (s *PhonemeStatsService) reverseSnapshot(messageID uuid.UUID) error {
+	snapshot, err := s.Snapshots.Take(s.exec, messageID)
+	if err != nil {
+		return err
+	}
+	return s.applyTally(snapshot.UserID, snapshot.Tally, -1)
+}
+
+// TallyPhonemes counts what phoneme details add to a user's stats
+func TallyPhonemes(phonemeDetails ...[]client.PhonemeDetail) models.PhonemeTally {
+	tally := models.PhonemeTally{
+		Phonemes:      map[string]models.PhonemeCounts{},
+		Substitutions: map[string]map[string]int{},
+	}
 
-		// Initialize or update stats for this phoneme
-		if _, exists := statsMap[expected]; !exists {
-			statsMap[expected] = &models.PhonemeStats{
-				UserID:        userID,
-				Phoneme:       expected,
-				TotalAttempts: 0,
-				CorrectCount:  0,
-				DeletionCount: 0,
+	for _, details := range phonemeDetails {
+		for _, detail := range details {
+			// Skip insertions (extra phonemes user added) - we only track expected phonemes
+			if detail.Type == "insert" {
+				continue
 			}
-		}
-		statsMap[expected].TotalAttempts++
-
-		if detail.Type == "match" {
-			statsMap[expected].CorrectCount++
-		} else if detail.Type == "delete" {
-			statsMap[expected].DeletionCount++
-		} else if detail.Type == "substitute" && detail.Actual != "" {
-			// Track substitution pattern
-			subKey := expected + "->" + detail.Actual
-			if _, exists := subsMap[subKey]; !exists {
-				subsMap[subKey] = &models.PhonemeSubstitution{
-					UserID:          userID,
-					ExpectedPhoneme: expected,
-					ActualPhoneme:   detail.Actual,
-					OccurrenceCount: 0,
+
+			expected := detail.Expected
+			if expected == "" {
+				continue
+			}
+
+			counts := tally.Phonemes[expected]
+			counts.TotalAttempts++
+			if detail.Type == "match" {
+				counts.CorrectCount++
+			} else if detail.Type == "delete" {
+				counts.DeletionCount++
+			} else if detail.Type == "substitute" && detail.Actual != "" {
+				// Track substitution pattern
+				if tally.Substitutions[expected] == nil {
+					tally.Substitutions[expected] = map[string]int{}
 				}
+				tally.Substitutions[expected][detail.Actual]++
 			}
-			subsMap[subKey].OccurrenceCount++
+			tally.Phonemes[expected] = counts
 		}
 	}
 
+	return tally
+}
+
+// applyTally adds a tally to the user's stats in its language, or with sign
+// -1 takes it out
+func (s *PhonemeStatsService) applyTally(userID uuid.UUID, tally models.PhonemeTally, sign int) error {
+	language := tally.Language
+	if !ValidLanguage(language) {
+		language = DefaultLanguage
+	}
+
 	// Upsert phoneme stats using repository
-	for _, stats := range statsMap {
+	for phoneme, counts := range tally.Phonemes {
+		stats := &models.PhonemeStats{
+			UserID:        userID,
+			Language:      language,
+			Phoneme:       phoneme,
+			TotalAttempts: sign * counts.TotalAttempts,
+			CorrectCount:  sign * counts.CorrectCount,
+			DeletionCount: sign * counts.DeletionCount,
+		}
 		if err := s.statsRepo.Upsert(s.exec, stats); err != nil {
 			return err
 		}
 	}
 
 	// Upsert substitution patterns using repository
-	for _, sub := range subsMap {
-		if err := s.subsRepo.Upsert(s.exec, sub); err != nil {
-			return err
+	for expected, actuals := range tally.Substitutions {
+		for actual, count := range actuals {
+			sub := &models.PhonemeSubstitution{
+				UserID:          userID,
+				Language:        language,
+				ExpectedPhoneme: expected,
+				ActualPhoneme:   actual,
+				OccurrenceCount: sign * count,
+			}
+			if err := s.subsRepo.Upsert(s.exec, sub); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-// UserPhonemeStatsResponse contains aggregated phoneme stats for a user
+// UserPhonemeStatsResponse contains aggregated phoneme stats for a user in
+// one language
 type UserPhonemeStatsResponse struct {
+	Language            string                `json:"language"`
+	Languages           []string              `json:"languages"` // Every language the user has stats in
 	TotalPhonemes       int                   `json:"totalPhonemes"`
 	OverallAccuracy     float64               `json:"overallAccuracy"`
 	PhonemeStats        []PhonemeAccuracy     `json:"phonemeStats"`
@@ -122,6 +224,7 @@ type PhonemeAccuracy struct {
 	CorrectCount  int     `json:"correctCount"`
 	DeletionCount int     `json:"deletionCount"`
 	Accuracy      float64 `json:"accuracy"`
+	Weight        float64 `json:"weight"` // How much it counts toward OverallAccuracy
 }
 
 // SubstitutionPattern represents a common substitution error
@@ -131,31 +234,46 @@ type SubstitutionPattern struct {
 	Count           int    `json:"count"`
 }
 
-// GetUserStats retrieves aggregated phoneme statistics for a user
-func (s *PhonemeStatsService) GetUserStats(userID uuid.UUID) (*UserPhonemeStatsResponse, error) {
-	// Get all stats for this user
-	stats, err := s.statsRepo.FindByUserID(s.exec, userID)
+// GetUserStats retrieves aggregated phoneme statistics for a user in one
+// thread language; "" is DefaultLanguage. Overall accuracy and the ranking
+// apply the phoneme weights in force, computed from the raw counts on every
+// call, so a weight change shows on the next one.
+func (s *PhonemeStatsService) GetUserStats(userID uuid.UUID, language string) (*UserPhonemeStatsResponse, error) {
+	if language == "" {
+		language = DefaultLanguage
+	}
+	if !ValidLanguage(language) {
+		return nil, ErrInvalidLanguage
+	}
+
+	// Get all stats for this user in the language
+	stats, err := s.statsRepo.FindByUserID(s.exec, userID, language)
 	if err != nil {
 		return nil, err
 	}
+	languages, err := s.statsRepo.FindLanguages(s.exec, userID)
+	if err != nil {
+		return nil, err
+	}
+	if languages == nil {
+		languages = []string{}
+	}
 
 	// Calculate totals
-	var totalAttempts, totalCorrect int
+	var totalAttempts int
 	for _, stat := range stats {
 		totalAttempts += stat.TotalAttempts
-		totalCorrect += stat.CorrectCount
 	}
 
-	overallAccuracy := 0.0
-	if totalAttempts > 0 {
-		overallAccuracy = float64(totalCorrect) / float64(totalAttempts) * 100
-	}
+	weights := s.Runtime.Current().PhonemeWeights[PhonemeLanguage(language)]
+	overallAccuracy := weights.Accuracy(stats)
 
-	// Get accuracy ranking from repository
-	repoAccuracy, err := s.statsRepo.GetAccuracyRanking(s.exec, userID)
+	// Get accuracy ranking from repository, most costly phonemes first
+	repoAccuracy, err := s.statsRepo.GetAccuracyRanking(s.exec, userID, language)
 	if err != nil {
 		return nil, err
 	}
+	weights.Rank(repoAccuracy)
 
 	// Convert repository PhonemeAccuracy to service PhonemeAccuracy
 	phonemeStats := make([]PhonemeAccuracy, len(repoAccuracy))
@@ -166,11 +284,12 @@ func (s *PhonemeStatsService) GetUserStats(userID uuid.UUID) (*UserPhonemeStatsR
 			CorrectCount:  pa.CorrectCount,
 			DeletionCount: pa.DeletionCount,
 			Accuracy:      pa.Accuracy,
+			Weight:        weights.Of(pa.Phoneme),
 		}
 	}
 
 	// Get common substitutions
-	substitutions, err := s.subsRepo.FindTopByUserID(s.exec, userID, 10)
+	substitutions, err := s.subsRepo.FindTopByUserID(s.exec, userID, language, 10)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +304,8 @@ func (s *PhonemeStatsService) GetUserStats(userID uuid.UUID) (*UserPhonemeStatsR
 	}
 
 	return &UserPhonemeStatsResponse{
+		Language:            language,
+		Languages:           languages,
 		TotalPhonemes:       totalAttempts,
 		OverallAccuracy:     overallAccuracy,
 		PhonemeStats:        phonemeStats,
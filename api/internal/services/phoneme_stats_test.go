@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"ling-app/api/internal/client"
 	"ling-app/api/internal/models"
@@ -220,12 +221,13 @@ func TestPhonemeStatsService_GetUserStats(t *testing.T) {
 			{UserID: userID, ExpectedPhoneme: "θ", ActualPhoneme: "f", OccurrenceCount: 5},
 		}
 
-		statsRepo.On("FindByUserID", mock.Anything, userID).Return(phonemeStats, nil)
-		statsRepo.On("GetAccuracyRanking", mock.Anything, userID).Return(accuracyRanking, nil)
-		subsRepo.On("FindTopByUserID", mock.Anything, userID, 10).Return(substitutions, nil)
+		statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return(phonemeStats, nil)
+		statsRepo.On("FindLanguages", mock.Anything, userID).Return([]string{"en"}, nil)
+		statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return(accuracyRanking, nil)
+		subsRepo.On("FindTopByUserID", mock.Anything, userID, "en", 10).Return(substitutions, nil)
 
 		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
-		result, err := service.GetUserStats(userID)
+		result, err := service.GetUserStats(userID, "")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -239,34 +241,101 @@ func TestPhonemeStatsService_GetUserStats(t *testing.T) {
 		assert.Equal(t, 5, result.CommonSubstitutions[0].Count)
 	})
 
+	t.Run("applies the phoneme weights", func(t *testing.T) {
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+
+		statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return([]models.PhonemeStats{
+			{UserID: userID, Phoneme: "θ", TotalAttempts: 10, CorrectCount: 2},
+			{UserID: userID, Phoneme: "r", TotalAttempts: 10, CorrectCount: 7},
+		}, nil)
+		statsRepo.On("FindLanguages", mock.Anything, userID).Return([]string{"en"}, nil)
+		statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return([]repository.PhonemeAccuracy{
+			{Phoneme: "θ", TotalAttempts: 10, CorrectCount: 2, Accuracy: 20},
+			{Phoneme: "r", TotalAttempts: 10, CorrectCount: 7, Accuracy: 70},
+		}, nil)
+		subsRepo.On("FindTopByUserID", mock.Anything, userID, "en", 10).Return([]models.PhonemeSubstitution{}, nil)
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		service.Runtime = runtimeSettingsWith(t, "phonemeWeights", `{"en-us": {"θ": 0.25, "r": 3}}`)
+		result, err := service.GetUserStats(userID, "")
+
+		require.NoError(t, err)
+		// (0.25*2 + 3*7) / (0.25*10 + 3*10) = 21.5 / 32.5
+		assert.InDelta(t, 66.15, result.OverallAccuracy, 0.01)
+		require.Len(t, result.PhonemeStats, 2)
+		assert.Equal(t, "r", result.PhonemeStats[0].Phoneme, "30 points off at weight 3 cost more than 80 at 0.25")
+		assert.Equal(t, 3.0, result.PhonemeStats[0].Weight)
+		assert.Equal(t, 0.25, result.PhonemeStats[1].Weight)
+	})
+
 	t.Run("returns empty stats for user with no data", func(t *testing.T) {
 		statsRepo := new(mocks.MockPhonemeStatsRepository)
 		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
 
-		statsRepo.On("FindByUserID", mock.Anything, userID).Return([]models.PhonemeStats{}, nil)
-		statsRepo.On("GetAccuracyRanking", mock.Anything, userID).Return([]repository.PhonemeAccuracy{}, nil)
-		subsRepo.On("FindTopByUserID", mock.Anything, userID, 10).Return([]models.PhonemeSubstitution{}, nil)
+		statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return([]models.PhonemeStats{}, nil)
+		statsRepo.On("FindLanguages", mock.Anything, userID).Return(nil, nil)
+		statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return([]repository.PhonemeAccuracy{}, nil)
+		subsRepo.On("FindTopByUserID", mock.Anything, userID, "en", 10).Return([]models.PhonemeSubstitution{}, nil)
 
 		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
-		result, err := service.GetUserStats(userID)
+		result, err := service.GetUserStats(userID, "")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
+		assert.Equal(t, []string{}, result.Languages)
 		assert.Equal(t, 0, result.TotalPhonemes)
 		assert.Equal(t, 0.0, result.OverallAccuracy)
 		assert.Len(t, result.PhonemeStats, 0)
 		assert.Len(t, result.CommonSubstitutions, 0)
 	})
 
+	t.Run("returns one language's stats", func(t *testing.T) {
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+
+		statsRepo.On("FindByUserID", mock.Anything, userID, "es").Return([]models.PhonemeStats{
+			{UserID: userID, Language: "es", Phoneme: "r", TotalAttempts: 4, CorrectCount: 1},
+		}, nil)
+		statsRepo.On("FindLanguages", mock.Anything, userID).Return([]string{"en", "es"}, nil)
+		statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "es").Return([]repository.PhonemeAccuracy{
+			{Phoneme: "r", TotalAttempts: 4, CorrectCount: 1, Accuracy: 25},
+		}, nil)
+		subsRepo.On("FindTopByUserID", mock.Anything, userID, "es", 10).Return([]models.PhonemeSubstitution{}, nil)
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		// Weights are looked up by the language's phoneme set
+		service.Runtime = runtimeSettingsWith(t, "phonemeWeights", `{"es-es": {"r": 2}}`)
+		result, err := service.GetUserStats(userID, "es")
+
+		require.NoError(t, err)
+		assert.Equal(t, "es", result.Language)
+		assert.Equal(t, []string{"en", "es"}, result.Languages)
+		assert.Equal(t, 4, result.TotalPhonemes)
+		require.Len(t, result.PhonemeStats, 1)
+		assert.Equal(t, 2.0, result.PhonemeStats[0].Weight)
+	})
+
+	t.Run("rejects an unsupported language", func(t *testing.T) {
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		_, err := service.GetUserStats(userID, "xx")
+
+		assert.ErrorIs(t, err, ErrInvalidLanguage)
+		statsRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("returns error when FindByUserID fails", func(t *testing.T) {
 		statsRepo := new(mocks.MockPhonemeStatsRepository)
 		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
 
 		dbError := errors.New("database error")
-		statsRepo.On("FindByUserID", mock.Anything, userID).Return([]models.PhonemeStats{}, dbError)
+		statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return([]models.PhonemeStats{}, dbError)
 
 		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
-		result, err := service.GetUserStats(userID)
+		result, err := service.GetUserStats(userID, "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -277,12 +346,13 @@ func TestPhonemeStatsService_GetUserStats(t *testing.T) {
 		statsRepo := new(mocks.MockPhonemeStatsRepository)
 		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
 
-		statsRepo.On("FindByUserID", mock.Anything, userID).Return([]models.PhonemeStats{}, nil)
+		statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return([]models.PhonemeStats{}, nil)
+		statsRepo.On("FindLanguages", mock.Anything, userID).Return([]string{"en"}, nil)
 		dbError := errors.New("ranking error")
-		statsRepo.On("GetAccuracyRanking", mock.Anything, userID).Return([]repository.PhonemeAccuracy{}, dbError)
+		statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return([]repository.PhonemeAccuracy{}, dbError)
 
 		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
-		result, err := service.GetUserStats(userID)
+		result, err := service.GetUserStats(userID, "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -292,15 +362,153 @@ func TestPhonemeStatsService_GetUserStats(t *testing.T) {
 		statsRepo := new(mocks.MockPhonemeStatsRepository)
 		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
 
-		statsRepo.On("FindByUserID", mock.Anything, userID).Return([]models.PhonemeStats{}, nil)
-		statsRepo.On("GetAccuracyRanking", mock.Anything, userID).Return([]repository.PhonemeAccuracy{}, nil)
+		statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return([]models.PhonemeStats{}, nil)
+		statsRepo.On("FindLanguages", mock.Anything, userID).Return([]string{"en"}, nil)
+		statsRepo.On("GetAccuracyRanking", mock.Anything, userID, "en").Return([]repository.PhonemeAccuracy{}, nil)
 		dbError := errors.New("substitution error")
-		subsRepo.On("FindTopByUserID", mock.Anything, userID, 10).Return([]models.PhonemeSubstitution{}, dbError)
+		subsRepo.On("FindTopByUserID", mock.Anything, userID, "en", 10).Return([]models.PhonemeSubstitution{}, dbError)
 
 		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
-		result, err := service.GetUserStats(userID)
+		result, err := service.GetUserStats(userID, "")
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
 }
+
+func TestPhonemeStatsService_RecordMessageResults(t *testing.T) {
+	userID := uuid.New()
+	messageID := uuid.New()
+
+	t.Run("replaces an earlier snapshot of the message", func(t *testing.T) {
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+		snapshots := new(mocks.MockPhonemeStatsSnapshotRepository)
+
+		snapshots.On("Take", mock.Anything, messageID).Return(&models.PhonemeStatsSnapshot{
+			MessageID: messageID,
+			UserID:    userID,
+			Tally: models.PhonemeTally{
+				Phonemes:      map[string]models.PhonemeCounts{"θ": {TotalAttempts: 1}},
+				Substitutions: map[string]map[string]int{"θ": {"f": 1}},
+			},
+		}, nil)
+		// The old contribution comes out...
+		statsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStats) bool {
+			return s.Phoneme == "θ" && s.TotalAttempts == -1 && s.Language == "en"
+		})).Return(nil).Once()
+		subsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeSubstitution) bool {
+			return s.ExpectedPhoneme == "θ" && s.ActualPhoneme == "f" && s.OccurrenceCount == -1
+		})).Return(nil).Once()
+		// ...and the new one, from both chunks, goes in
+		statsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStats) bool {
+			return s.Phoneme == "θ" && s.TotalAttempts == 2 && s.CorrectCount == 2 && s.Language == "en"
+		})).Return(nil).Once()
+		snapshots.On("Save", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStatsSnapshot) bool {
+			return s.MessageID == messageID && s.UserID == userID && s.Tally.Phonemes["θ"].TotalAttempts == 2
+		})).Return(nil)
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		service.Snapshots = snapshots
+		err := service.RecordMessageResults(userID, messageID, "en",
+			[]client.PhonemeDetail{{Expected: "θ", Actual: "θ", Type: "match"}},
+			[]client.PhonemeDetail{{Expected: "θ", Actual: "θ", Type: "match"}},
+		)
+
+		assert.NoError(t, err)
+		statsRepo.AssertExpectations(t)
+		subsRepo.AssertExpectations(t)
+		snapshots.AssertExpectations(t)
+	})
+
+	t.Run("records without a snapshot repository", func(t *testing.T) {
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+		statsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil).Once()
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		err := service.RecordMessageResults(userID, messageID, "en", []client.PhonemeDetail{{Expected: "a", Actual: "a", Type: "match"}})
+
+		assert.NoError(t, err)
+		statsRepo.AssertExpectations(t)
+	})
+
+	t.Run("keeps each language's stats apart", func(t *testing.T) {
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+		snapshots := new(mocks.MockPhonemeStatsSnapshotRepository)
+
+		// A snapshot from before stats had languages was English
+		snapshots.On("Take", mock.Anything, messageID).Return(&models.PhonemeStatsSnapshot{
+			MessageID: messageID,
+			UserID:    userID,
+			Tally:     models.PhonemeTally{Phonemes: map[string]models.PhonemeCounts{"r": {TotalAttempts: 1}}},
+		}, nil)
+		statsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStats) bool {
+			return s.Phoneme == "r" && s.TotalAttempts == -1 && s.Language == "en"
+		})).Return(nil).Once()
+		statsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStats) bool {
+			return s.Phoneme == "r" && s.TotalAttempts == 1 && s.Language == "es"
+		})).Return(nil).Once()
+		subsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeSubstitution) bool {
+			return s.ExpectedPhoneme == "r" && s.ActualPhoneme == "ɾ" && s.Language == "es"
+		})).Return(nil).Once()
+		snapshots.On("Save", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStatsSnapshot) bool {
+			return s.Tally.Language == "es"
+		})).Return(nil)
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		service.Snapshots = snapshots
+		err := service.RecordMessageResults(userID, messageID, "es", []client.PhonemeDetail{{Expected: "r", Actual: "ɾ", Type: "substitute"}})
+
+		assert.NoError(t, err)
+		statsRepo.AssertExpectations(t)
+		subsRepo.AssertExpectations(t)
+		snapshots.AssertExpectations(t)
+	})
+}
+
+func TestPhonemeStatsService_ReverseMessageResults(t *testing.T) {
+	userID := uuid.New()
+	analysis := models.JSONMap{
+		"phoneme_details": []interface{}{
+			map[string]interface{}{"expected": "r", "actual": "l", "type": "substitute"},
+		},
+	}
+
+	t.Run("falls back to the stored analysis without a snapshot", func(t *testing.T) {
+		message := &models.Message{ID: uuid.New(), PronunciationStatus: "complete", PronunciationAnalysis: analysis}
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+		snapshots := new(mocks.MockPhonemeStatsSnapshotRepository)
+		snapshots.On("Take", mock.Anything, message.ID).Return(nil, repository.ErrNotFound)
+		statsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStats) bool {
+			return s.UserID == userID && s.Phoneme == "r" && s.TotalAttempts == -1
+		})).Return(nil)
+		subsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeSubstitution) bool {
+			return s.ExpectedPhoneme == "r" && s.ActualPhoneme == "l" && s.OccurrenceCount == -1
+		})).Return(nil)
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		service.Snapshots = snapshots
+
+		assert.NoError(t, service.ReverseMessageResults(userID, message, "en"))
+		statsRepo.AssertExpectations(t)
+		subsRepo.AssertExpectations(t)
+	})
+
+	t.Run("takes nothing out for analyses that were never recorded", func(t *testing.T) {
+		message := &models.Message{ID: uuid.New(), PronunciationStatus: "complete", PronunciationLowConfidence: true, PronunciationAnalysis: analysis}
+		statsRepo := new(mocks.MockPhonemeStatsRepository)
+		subsRepo := new(mocks.MockPhonemeSubstitutionRepository)
+		snapshots := new(mocks.MockPhonemeStatsSnapshotRepository)
+		snapshots.On("Take", mock.Anything, message.ID).Return(nil, repository.ErrNotFound)
+
+		service := NewPhonemeStatsServiceForTest(nil, statsRepo, subsRepo)
+		service.Snapshots = snapshots
+
+		assert.NoError(t, service.ReverseMessageResults(userID, message, "en"))
+		statsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+		subsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// MaxPhonemeWeight is the largest weight a phoneme can be given
+const MaxPhonemeWeight = 10
+
+// phonemeLanguagePattern matches the language codes pronunciation is scored
+// in, e.g. "en-us"
+var phonemeLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// PhonemeWeights is how much each phoneme of a language counts toward overall
+// accuracy, relative to the others. Errors on phonemes that matter more for
+// being understood can be weighted up, and ones that barely do weighted down;
+// 0 leaves a phoneme out. Phonemes without a weight count 1.
+type PhonemeWeights map[string]float64
+
+// Of returns the phoneme's weight
+func (w PhonemeWeights) Of(phoneme string) float64 {
+	if weight, ok := w[phoneme]; ok {
+		return weight
+	}
+	return 1
+}
+
+// Accuracy returns the weighted share of correct attempts across the stats,
+// as a percentage; 0 if nothing weighted was attempted
+func (w PhonemeWeights) Accuracy(stats []models.PhonemeStats) float64 {
+	var attempts, correct float64
+	for _, st := range stats {
+		weight := w.Of(st.Phoneme)
+		attempts += weight * float64(st.TotalAttempts)
+		correct += weight * float64(st.CorrectCount)
+	}
+	if attempts == 0 {
+		return 0
+	}
+	return correct / attempts * 100
+}
+
+// Rank orders phonemes by how much they cost overall accuracy, their error
+// rate times their weight, most costly first. Equal ones keep their order.
+func (w PhonemeWeights) Rank(ranking []repository.PhonemeAccuracy) {
+	cost := func(p repository.PhonemeAccuracy) float64 {
+		return (100 - p.Accuracy) * w.Of(p.Phoneme)
+	}
+	sort.SliceStable(ranking, func(i, j int) bool { return cost(ranking[i]) > cost(ranking[j]) })
+}
+
+// checkPhonemeWeights validates one language's weights for the
+// phonemeWeights runtime setting
+func checkPhonemeWeights(language string, weights PhonemeWeights) error {
+	if !phonemeLanguagePattern.MatchString(language) {
+		return fmt.Errorf("%q is not a language code like %q", language, PronunciationLanguage)
+	}
+	for phoneme, weight := range weights {
+		if phoneme == "" || utf8.RuneCountInString(phoneme) > 10 {
+			return fmt.Errorf("%s: %q is not a phoneme", language, phoneme)
+		}
+		if weight < 0 || weight > MaxPhonemeWeight {
+			return fmt.Errorf("%s: weight of %q must be between 0 and %d", language, phoneme, MaxPhonemeWeight)
+		}
+	}
+	return nil
+}
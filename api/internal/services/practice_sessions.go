@@ -0,0 +1,270 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPracticeSessionRunning       = errors.New("thread already has a practice session running")
+	ErrInvalidPracticeSessionLength = errors.New("invalid practice session length")
+)
+
+// Default and max page sizes for practice session history
+const (
+	DefaultPracticeSessionLimit = 20
+	MaxPracticeSessionLimit     = 100
+)
+
+// vocabularyHistoryMessages is how many of the user's earlier messages a
+// session's words are checked against when looking for new vocabulary
+const vocabularyHistoryMessages = 2000
+
+// PracticeSessionManager defines the interface for timed practice sessions
+type PracticeSessionManager interface {
+	Start(userID, threadID uuid.UUID, minutes int) (*models.PracticeSession, error)
+	End(userID, sessionID uuid.UUID) (*models.PracticeSession, error)
+	List(userID uuid.UUID, limit, offset int) ([]models.PracticeSession, error)
+}
+
+// PracticeSessionService runs timed practice sessions in threads and
+// summarizes them when they end
+type PracticeSessionService struct {
+	exec        repository.Executor
+	sessionRepo repository.PracticeSessionRepository
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+
+	now func() time.Time
+}
+
+// NewPracticeSessionService creates a new practice session service
+func NewPracticeSessionService(
+	database *db.DB,
+	sessionRepo repository.PracticeSessionRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+) *PracticeSessionService {
+	return &PracticeSessionService{
+		exec:        database.DB,
+		sessionRepo: sessionRepo,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		now:         time.Now,
+	}
+}
+
+// NewPracticeSessionServiceForTest creates a PracticeSessionService with injected dependencies for testing.
+func NewPracticeSessionServiceForTest(
+	exec repository.Executor,
+	sessionRepo repository.PracticeSessionRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+) *PracticeSessionService {
+	return &PracticeSessionService{
+		exec:        exec,
+		sessionRepo: sessionRepo,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		now:         time.Now,
+	}
+}
+
+// Start opens a session in one of the user's threads with a timer of the
+// given length; 0 uses the default. A session whose timer ran out without
+// being ended is ended first.
+func (s *PracticeSessionService) Start(userID, threadID uuid.UUID, minutes int) (*models.PracticeSession, error) {
+	if minutes == 0 {
+		minutes = models.DefaultPracticeSessionMinutes
+	}
+	if minutes < models.MinPracticeSessionMinutes || minutes > models.MaxPracticeSessionMinutes {
+		return nil, ErrInvalidPracticeSessionLength
+	}
+	if _, err := s.threadRepo.FindByIDAndUserID(s.exec, threadID, userID); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	open, err := s.sessionRepo.FindOpenByThreadID(s.exec, threadID)
+	switch {
+	case err == nil && now.Before(open.TimerEndsAt):
+		return nil, ErrPracticeSessionRunning
+	case err == nil:
+		if _, err := s.finish(open, open.TimerEndsAt); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, fmt.Errorf("find open practice session: %w", err)
+	}
+
+	session := &models.PracticeSession{
+		UserID:         userID,
+		ThreadID:       threadID,
+		PlannedMinutes: minutes,
+		StartedAt:      now,
+		TimerEndsAt:    now.Add(time.Duration(minutes) * time.Minute),
+	}
+	if err := s.sessionRepo.Create(s.exec, session); err != nil {
+		return nil, fmt.Errorf("create practice session: %w", err)
+	}
+	return session, nil
+}
+
+// End ends one of the user's sessions, no later than its timer, and returns
+// it with its summary. Ending a session that already ended returns it as is.
+func (s *PracticeSessionService) End(userID, sessionID uuid.UUID) (*models.PracticeSession, error) {
+	session, err := s.sessionRepo.FindByIDAndUserID(s.exec, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.EndedAt != nil {
+		return session, nil
+	}
+
+	endedAt := s.now()
+	if endedAt.After(session.TimerEndsAt) {
+		endedAt = session.TimerEndsAt
+	}
+	return s.finish(session, endedAt)
+}
+
+// List returns a page of the user's sessions, newest first, skipping the first
+// offset. Sessions whose timer ran out are ended on the way.
+func (s *PracticeSessionService) List(userID uuid.UUID, limit, offset int) ([]models.PracticeSession, error) {
+	if limit <= 0 {
+		limit = DefaultPracticeSessionLimit
+	}
+	if limit > MaxPracticeSessionLimit {
+		limit = MaxPracticeSessionLimit
+	}
+
+	sessions, err := s.sessionRepo.FindByUserID(s.exec, userID, limit, max(offset, 0))
+	if err != nil {
+		return nil, fmt.Errorf("list practice sessions: %w", err)
+	}
+	if sessions == nil {
+		sessions = []models.PracticeSession{}
+	}
+
+	now := s.now()
+	for i, session := range sessions {
+		if session.EndedAt != nil || now.Before(session.TimerEndsAt) {
+			continue
+		}
+		ended, err := s.finish(&session, session.TimerEndsAt)
+		if err != nil {
+			return nil, err
+		}
+		sessions[i] = *ended
+	}
+	return sessions, nil
+}
+
+// finish summarizes a session and stores it as ended. If another request
+// ended it first, the stored session is returned instead.
+func (s *PracticeSessionService) finish(session *models.PracticeSession, endedAt time.Time) (*models.PracticeSession, error) {
+	summary, err := s.summarize(session, endedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	ended, err := s.sessionRepo.End(s.exec, session.ID, endedAt, summary)
+	if err != nil {
+		return nil, fmt.Errorf("end practice session: %w", err)
+	}
+	if !ended {
+		return s.sessionRepo.FindByIDAndUserID(s.exec, session.ID, session.UserID)
+	}
+
+	session.EndedAt = &endedAt
+	session.Summary = summary
+	return session, nil
+}
+
+// summarize counts the user's messages in the thread between the start of the
+// session and endedAt. Accuracy covers the turns already analyzed when the
+// session ends; long-form messages are scored per chunk and don't count
+// towards it.
+func (s *PracticeSessionService) summarize(session *models.PracticeSession, endedAt time.Time) (*models.PracticeSessionSummary, error) {
+	messages, err := s.messageRepo.FindByThreadID(s.exec, session.ThreadID)
+	if err != nil {
+		return nil, fmt.Errorf("find session messages: %w", err)
+	}
+
+	summary := &models.PracticeSessionSummary{
+		DurationSeconds: int(endedAt.Sub(session.StartedAt).Seconds()),
+		NewVocabulary:   models.StringList{},
+	}
+	var words []string
+	var details [][]client.PhonemeDetail
+	for _, m := range messages {
+		if m.Role != "user" || m.Timestamp.Before(session.StartedAt) || m.Timestamp.After(endedAt) {
+			continue
+		}
+		summary.Turns++
+		words = append(words, vocabularyWords(m.Content)...)
+
+		if m.PronunciationStatus != "complete" || m.PronunciationLowConfidence {
+			continue
+		}
+		if analysis, ok := parseAnalysis(m.PronunciationAnalysis); ok {
+			details = append(details, analysis.PhonemeDetails)
+			summary.ScoredTurns++
+		}
+	}
+
+	var attempts, correct int
+	for _, counts := range TallyPhonemes(details...).Phonemes {
+		attempts += counts.TotalAttempts
+		correct += counts.CorrectCount
+	}
+	if attempts > 0 {
+		accuracy := float64(correct) / float64(attempts) * 100
+		summary.Accuracy = &accuracy
+	}
+
+	if len(words) == 0 {
+		return summary, nil
+	}
+	earlier, err := s.messageRepo.FindUserMessagesByUserID(s.exec, session.UserID, session.StartedAt, vocabularyHistoryMessages)
+	if err != nil {
+		return nil, fmt.Errorf("find earlier messages: %w", err)
+	}
+	known := make(map[string]bool)
+	for _, m := range earlier {
+		for _, word := range vocabularyWords(m.Content) {
+			known[word] = true
+		}
+	}
+	for _, word := range words {
+		if !known[word] {
+			summary.NewVocabulary = append(summary.NewVocabulary, word)
+			known[word] = true
+		}
+	}
+	return summary, nil
+}
+
+// vocabularyWords returns the lowercased words of a message, leaving out
+// numbers
+func vocabularyWords(text string) []string {
+	var words []string
+	for _, word := range normalizeWords(text) {
+		for _, r := range word {
+			if unicode.IsLetter(r) {
+				words = append(words, word)
+				break
+			}
+		}
+	}
+	return words
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func newPracticeSessionService(now time.Time) (*PracticeSessionService, *repomocks.MockPracticeSessionRepository, *repomocks.MockThreadRepository, *repomocks.MockMessageRepository) {
+	sessionRepo := new(repomocks.MockPracticeSessionRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	service := NewPracticeSessionServiceForTest(nil, sessionRepo, threadRepo, messageRepo)
+	service.now = func() time.Time { return now }
+	return service, sessionRepo, threadRepo, messageRepo
+}
+
+func TestPracticeSessionService_Start(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	now := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	t.Run("starts the timer", func(t *testing.T) {
+		service, sessionRepo, threadRepo, _ := newPracticeSessionService(now)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+		sessionRepo.On("FindOpenByThreadID", mock.Anything, threadID).Return(nil, repository.ErrNotFound)
+		sessionRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.PracticeSession")).Return(nil)
+
+		session, err := service.Start(userID, threadID, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, models.DefaultPracticeSessionMinutes, session.PlannedMinutes)
+		assert.Equal(t, now, session.StartedAt)
+		assert.Equal(t, now.Add(10*time.Minute), session.TimerEndsAt)
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("one session per thread", func(t *testing.T) {
+		service, sessionRepo, threadRepo, _ := newPracticeSessionService(now)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+		sessionRepo.On("FindOpenByThreadID", mock.Anything, threadID).Return(&models.PracticeSession{
+			ID: uuid.New(), ThreadID: threadID, StartedAt: now.Add(-time.Minute), TimerEndsAt: now.Add(4 * time.Minute),
+		}, nil)
+
+		_, err := service.Start(userID, threadID, 5)
+
+		assert.ErrorIs(t, err, ErrPracticeSessionRunning)
+		sessionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ends a session whose timer ran out", func(t *testing.T) {
+		service, sessionRepo, threadRepo, messageRepo := newPracticeSessionService(now)
+		stale := &models.PracticeSession{
+			ID: uuid.New(), UserID: userID, ThreadID: threadID, StartedAt: now.Add(-time.Hour), TimerEndsAt: now.Add(-50 * time.Minute),
+		}
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+		sessionRepo.On("FindOpenByThreadID", mock.Anything, threadID).Return(stale, nil)
+		messageRepo.On("FindByThreadID", mock.Anything, threadID).Return([]models.Message{}, nil)
+		sessionRepo.On("End", mock.Anything, stale.ID, stale.TimerEndsAt, mock.AnythingOfType("*models.PracticeSessionSummary")).Return(true, nil)
+		sessionRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.PracticeSession")).Return(nil)
+
+		_, err := service.Start(userID, threadID, 5)
+		require.NoError(t, err)
+
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects lengths out of range", func(t *testing.T) {
+		service, _, _, _ := newPracticeSessionService(now)
+		_, err := service.Start(userID, threadID, models.MaxPracticeSessionMinutes+1)
+		assert.ErrorIs(t, err, ErrInvalidPracticeSessionLength)
+		_, err = service.Start(userID, threadID, -1)
+		assert.ErrorIs(t, err, ErrInvalidPracticeSessionLength)
+	})
+}
+
+func TestPracticeSessionService_End_Summarizes(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	start := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	now := start.Add(5 * time.Minute)
+	service, sessionRepo, _, messageRepo := newPracticeSessionService(now)
+
+	session := &models.PracticeSession{
+		ID: uuid.New(), UserID: userID, ThreadID: threadID, PlannedMinutes: 10, StartedAt: start, TimerEndsAt: start.Add(10 * time.Minute),
+	}
+	analysis := models.JSONMap{"phoneme_details": []interface{}{
+		map[string]interface{}{"type": "match", "expected": "s", "actual": "s"},
+		map[string]interface{}{"type": "substitute", "expected": "θ", "actual": "s"},
+		map[string]interface{}{"type": "match", "expected": "k", "actual": "k"},
+		map[string]interface{}{"type": "match", "expected": "o", "actual": "o"},
+	}}
+	messages := []models.Message{
+		{Role: "user", Content: "Before the session", Timestamp: start.Add(-time.Minute)},
+		{Role: "user", Content: "Quiero un café", Timestamp: start.Add(time.Minute), PronunciationStatus: "complete", PronunciationAnalysis: analysis},
+		{Role: "assistant", Content: "¿Con leche?", Timestamp: start.Add(2 * time.Minute)},
+		{Role: "user", Content: "Sí, con leche y 2 azúcares", Timestamp: start.Add(3 * time.Minute), PronunciationStatus: "complete", PronunciationAnalysis: analysis, PronunciationLowConfidence: true},
+		{Role: "user", Content: "quiero", Timestamp: start.Add(4 * time.Minute), PronunciationStatus: "pending"},
+	}
+	sessionRepo.On("FindByIDAndUserID", mock.Anything, session.ID, userID).Return(session, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(messages, nil)
+	messageRepo.On("FindUserMessagesByUserID", mock.Anything, userID, start, vocabularyHistoryMessages).
+		Return([]models.Message{{Role: "user", Content: "Quiero agua, con hielo"}}, nil)
+	sessionRepo.On("End", mock.Anything, session.ID, now, mock.AnythingOfType("*models.PracticeSessionSummary")).Return(true, nil)
+
+	ended, err := service.End(userID, session.ID)
+	require.NoError(t, err)
+
+	require.NotNil(t, ended.Summary)
+	assert.Equal(t, now, *ended.EndedAt)
+	assert.Equal(t, 300, ended.Summary.DurationSeconds)
+	assert.Equal(t, 3, ended.Summary.Turns)
+	assert.Equal(t, 1, ended.Summary.ScoredTurns, "low-confidence and pending turns aren't scored")
+	require.NotNil(t, ended.Summary.Accuracy)
+	assert.InDelta(t, 75.0, *ended.Summary.Accuracy, 0.001)
+	assert.Equal(t, models.StringList{"un", "café", "sí", "leche", "y", "azúcares"}, ended.Summary.NewVocabulary)
+}
+
+func TestPracticeSessionService_End(t *testing.T) {
+	userID := uuid.New()
+	start := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	t.Run("stops at the timer", func(t *testing.T) {
+		service, sessionRepo, _, messageRepo := newPracticeSessionService(start.Add(time.Hour))
+		session := &models.PracticeSession{ID: uuid.New(), UserID: userID, ThreadID: uuid.New(), StartedAt: start, TimerEndsAt: start.Add(5 * time.Minute)}
+		sessionRepo.On("FindByIDAndUserID", mock.Anything, session.ID, userID).Return(session, nil)
+		messageRepo.On("FindByThreadID", mock.Anything, session.ThreadID).Return([]models.Message{}, nil)
+		sessionRepo.On("End", mock.Anything, session.ID, session.TimerEndsAt, mock.AnythingOfType("*models.PracticeSessionSummary")).Return(true, nil)
+
+		ended, err := service.End(userID, session.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 300, ended.Summary.DurationSeconds)
+		assert.Nil(t, ended.Summary.Accuracy)
+		messageRepo.AssertNotCalled(t, "FindUserMessagesByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("already ended", func(t *testing.T) {
+		service, sessionRepo, _, messageRepo := newPracticeSessionService(start.Add(time.Hour))
+		endedAt := start.Add(3 * time.Minute)
+		session := &models.PracticeSession{ID: uuid.New(), UserID: userID, StartedAt: start, EndedAt: &endedAt, Summary: &models.PracticeSessionSummary{Turns: 2}}
+		sessionRepo.On("FindByIDAndUserID", mock.Anything, session.ID, userID).Return(session, nil)
+
+		ended, err := service.End(userID, session.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, ended.Summary.Turns)
+		messageRepo.AssertNotCalled(t, "FindByThreadID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ended by another request", func(t *testing.T) {
+		service, sessionRepo, _, messageRepo := newPracticeSessionService(start.Add(time.Minute))
+		session := &models.PracticeSession{ID: uuid.New(), UserID: userID, ThreadID: uuid.New(), StartedAt: start, TimerEndsAt: start.Add(5 * time.Minute)}
+		endedAt := start.Add(30 * time.Second)
+		stored := &models.PracticeSession{ID: session.ID, UserID: userID, EndedAt: &endedAt, Summary: &models.PracticeSessionSummary{DurationSeconds: 30}}
+		sessionRepo.On("FindByIDAndUserID", mock.Anything, session.ID, userID).Return(session, nil).Once()
+		sessionRepo.On("FindByIDAndUserID", mock.Anything, session.ID, userID).Return(stored, nil).Once()
+		messageRepo.On("FindByThreadID", mock.Anything, session.ThreadID).Return([]models.Message{}, nil)
+		sessionRepo.On("End", mock.Anything, session.ID, start.Add(time.Minute), mock.Anything).Return(false, nil)
+
+		ended, err := service.End(userID, session.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 30, ended.Summary.DurationSeconds)
+	})
+}
+
+func TestPracticeSessionService_List_EndsExpired(t *testing.T) {
+	userID := uuid.New()
+	start := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	service, sessionRepo, _, messageRepo := newPracticeSessionService(start.Add(time.Hour))
+
+	running := models.PracticeSession{ID: uuid.New(), UserID: userID, StartedAt: start.Add(50 * time.Minute), TimerEndsAt: start.Add(70 * time.Minute)}
+	expired := models.PracticeSession{ID: uuid.New(), UserID: userID, ThreadID: uuid.New(), StartedAt: start, TimerEndsAt: start.Add(10 * time.Minute)}
+	sessionRepo.On("FindByUserID", mock.Anything, userID, MaxPracticeSessionLimit, 0).Return([]models.PracticeSession{running, expired}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, expired.ThreadID).Return([]models.Message{}, nil)
+	sessionRepo.On("End", mock.Anything, expired.ID, expired.TimerEndsAt, mock.Anything).Return(true, nil)
+
+	sessions, err := service.List(userID, 500, 0)
+	require.NoError(t, err)
+
+	require.Len(t, sessions, 2)
+	assert.Nil(t, sessions[0].EndedAt, "a running session is left alone")
+	require.NotNil(t, sessions[1].EndedAt)
+	assert.Equal(t, 600, sessions[1].Summary.DurationSeconds)
+	sessionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"math"
+
+	"ling-app/api/internal/client"
+)
+
+// DefaultMinPronunciationConfidence is the confidence below which an analysis
+// is flagged as low confidence
+const DefaultMinPronunciationConfidence = 0.5
+
+const (
+	// neutralConfidence is used when the ML service reports no audio quality
+	neutralConfidence = 0.8
+
+	// SNR bands in dB; the ML service warns below 20 dB
+	noisySNRDB     = 20.0
+	veryNoisySNRDB = 10.0
+
+	// A match ratio this low usually means ASR heard something else entirely
+	minPlausibleMatchRatio = 0.2
+
+	// Too few phonemes to say much about pronunciation
+	minReliablePhonemes = 3
+)
+
+// ComputeConfidence estimates how far a pronunciation analysis can be trusted,
+// from 0 (noise) to 1 (clean audio, plausible alignment). It starts from the
+// ML audio quality score and discounts for low SNR, quality warnings, an
+// implausibly low match ratio, and very short utterances.
+func ComputeConfidence(analysis *client.PronunciationAnalysis) float64 {
+	if analysis == nil {
+		return 0
+	}
+
+	confidence := neutralConfidence
+	if q := analysis.AudioQuality; q != nil {
+		confidence = q.QualityScore / 100
+
+		switch {
+		case q.SNRDB < veryNoisySNRDB:
+			confidence *= 0.5
+		case q.SNRDB < noisySNRDB:
+			confidence *= 0.8
+		}
+
+		for range q.Warnings {
+			confidence *= 0.9
+		}
+	}
+
+	if analysis.PhonemeCount > 0 {
+		matchRatio := float64(analysis.MatchCount) / float64(analysis.PhonemeCount)
+		if matchRatio < minPlausibleMatchRatio {
+			confidence *= 0.6
+		}
+	}
+
+	if analysis.PhonemeCount < minReliablePhonemes {
+		confidence *= 0.7
+	}
+
+	confidence = math.Max(0, math.Min(1, confidence))
+	return math.Round(confidence*1000) / 1000
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ling-app/api/internal/client"
+)
+
+func TestComputeConfidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis *client.PronunciationAnalysis
+		want     float64
+	}{
+		{
+			name:     "nil analysis",
+			analysis: nil,
+			want:     0,
+		},
+		{
+			name:     "no audio quality uses neutral confidence",
+			analysis: &client.PronunciationAnalysis{PhonemeCount: 5, MatchCount: 4},
+			want:     0.8,
+		},
+		{
+			name: "clean audio",
+			analysis: &client.PronunciationAnalysis{
+				PhonemeCount: 10,
+				MatchCount:   8,
+				AudioQuality: &client.AudioQuality{QualityScore: 90, SNRDB: 30},
+			},
+			want: 0.9,
+		},
+		{
+			name: "moderate noise with a warning",
+			analysis: &client.PronunciationAnalysis{
+				PhonemeCount: 10,
+				MatchCount:   8,
+				AudioQuality: &client.AudioQuality{QualityScore: 80, SNRDB: 15, Warnings: []string{"noise"}},
+			},
+			want: 0.576,
+		},
+		{
+			name: "very noisy audio",
+			analysis: &client.PronunciationAnalysis{
+				PhonemeCount: 10,
+				MatchCount:   8,
+				AudioQuality: &client.AudioQuality{QualityScore: 80, SNRDB: 5},
+			},
+			want: 0.4,
+		},
+		{
+			name: "implausibly low match ratio",
+			analysis: &client.PronunciationAnalysis{
+				PhonemeCount: 10,
+				MatchCount:   1,
+				AudioQuality: &client.AudioQuality{QualityScore: 100, SNRDB: 30},
+			},
+			want: 0.6,
+		},
+		{
+			name: "too few phonemes",
+			analysis: &client.PronunciationAnalysis{
+				PhonemeCount: 2,
+				MatchCount:   2,
+				AudioQuality: &client.AudioQuality{QualityScore: 100, SNRDB: 30},
+			},
+			want: 0.7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, ComputeConfidence(tt.analysis), 0.0001)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// pronunciationMonitorWindow is how far back the job report counts
+const pronunciationMonitorWindow = 24 * time.Hour
+
+// PronunciationJobMonitor defines the interface for the admin pronunciation
+// job report
+type PronunciationJobMonitor interface {
+	Report(limit int) (*PronunciationJobReport, error)
+}
+
+// PronunciationJobReport shows how pronunciation analysis is keeping up: the
+// job queue, what happened to analyses over the last day, the ones waiting
+// longest and the latest failures
+type PronunciationJobReport struct {
+	Since          time.Time                    `json:"since"`
+	Lanes          map[jobs.Lane]jobs.LaneStats `json:"lanes"`
+	StatusCounts   map[string]int64             `json:"statusCounts"` // pending, complete, failed, skipped_divergent
+	OldestPending  []PronunciationJob           `json:"oldestPending"`
+	RecentFailures []PronunciationJob           `json:"recentFailures"`
+}
+
+// PronunciationJob is one message's analysis as the monitor shows it, without
+// the message content
+type PronunciationJob struct {
+	MessageID uuid.UUID  `json:"messageId"`
+	ThreadID  uuid.UUID  `json:"threadId"`
+	Status    string     `json:"status"`
+	Error     *string    `json:"error,omitempty"`
+	Retries   int        `json:"retries"`
+	SentAt    time.Time  `json:"sentAt"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// PronunciationMonitorService reports on pronunciation analysis jobs for
+// admins
+type PronunciationMonitorService struct {
+	exec        repository.Executor
+	messageRepo repository.MessageRepository
+	queue       *jobs.Queue
+
+	now func() time.Time
+}
+
+// NewPronunciationMonitorService creates a new pronunciation monitor service
+func NewPronunciationMonitorService(database *db.DB, messageRepo repository.MessageRepository, queue *jobs.Queue) *PronunciationMonitorService {
+	return NewPronunciationMonitorServiceForTest(database.DB, messageRepo, queue, time.Now)
+}
+
+// NewPronunciationMonitorServiceForTest creates a PronunciationMonitorService with injected dependencies for testing.
+func NewPronunciationMonitorServiceForTest(exec repository.Executor, messageRepo repository.MessageRepository, queue *jobs.Queue, now func() time.Time) *PronunciationMonitorService {
+	return &PronunciationMonitorService{
+		exec:        exec,
+		messageRepo: messageRepo,
+		queue:       queue,
+		now:         now,
+	}
+}
+
+// Report returns the job report, listing up to limit pending analyses and
+// failures
+func (s *PronunciationMonitorService) Report(limit int) (*PronunciationJobReport, error) {
+	since := s.now().Add(-pronunciationMonitorWindow)
+	report := &PronunciationJobReport{Since: since, Lanes: map[jobs.Lane]jobs.LaneStats{}}
+	if s.queue != nil {
+		report.Lanes = s.queue.Stats()
+	}
+
+	counts, err := s.messageRepo.CountPronunciationStatuses(s.exec, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count analyses: %w", err)
+	}
+	report.StatusCounts = counts
+
+	pending, err := s.messageRepo.FindPendingPronunciation(s.exec, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending analyses: %w", err)
+	}
+	report.OldestPending = pronunciationJobs(pending)
+
+	failed, err := s.messageRepo.FindFailedPronunciation(s.exec, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed analyses: %w", err)
+	}
+	report.RecentFailures = pronunciationJobs(failed)
+
+	return report, nil
+}
+
+func pronunciationJobs(messages []models.Message) []PronunciationJob {
+	out := make([]PronunciationJob, len(messages))
+	for i, m := range messages {
+		out[i] = PronunciationJob{
+			MessageID: m.ID,
+			ThreadID:  m.ThreadID,
+			Status:    m.PronunciationStatus,
+			Error:     m.PronunciationError,
+			Retries:   m.PronunciationRetries,
+			SentAt:    m.Timestamp,
+			UpdatedAt: m.PronunciationUpdatedAt,
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestPronunciationMonitorService_Report(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-24 * time.Hour)
+	errMsg := "ml service timeout"
+	failedAt := now.Add(-time.Hour)
+	pending := models.Message{ID: uuid.New(), ThreadID: uuid.New(), PronunciationStatus: "pending", Timestamp: now.Add(-10 * time.Minute)}
+	failed := models.Message{ID: uuid.New(), ThreadID: uuid.New(), PronunciationStatus: "failed", PronunciationError: &errMsg, PronunciationRetries: 2, PronunciationUpdatedAt: &failedAt}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	messageRepo.On("CountPronunciationStatuses", mock.Anything, since).Return(map[string]int64{"complete": 40, "failed": 1, "pending": 1}, nil)
+	messageRepo.On("FindPendingPronunciation", mock.Anything, 20).Return([]models.Message{pending}, nil)
+	messageRepo.On("FindFailedPronunciation", mock.Anything, since, 20).Return([]models.Message{failed}, nil)
+
+	svc := NewPronunciationMonitorServiceForTest(nil, messageRepo, jobs.NewQueue(jobs.Config{}), func() time.Time { return now })
+
+	report, err := svc.Report(20)
+	require.NoError(t, err)
+
+	assert.Equal(t, since, report.Since)
+	assert.Contains(t, report.Lanes, jobs.LanePriority)
+	assert.Equal(t, int64(40), report.StatusCounts["complete"])
+	require.Len(t, report.OldestPending, 1)
+	assert.Equal(t, pending.ID, report.OldestPending[0].MessageID)
+	assert.Equal(t, pending.Timestamp, report.OldestPending[0].SentAt)
+	require.Len(t, report.RecentFailures, 1)
+	assert.Equal(t, &errMsg, report.RecentFailures[0].Error)
+	assert.Equal(t, 2, report.RecentFailures[0].Retries)
+	messageRepo.AssertExpectations(t)
+}
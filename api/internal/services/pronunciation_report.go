@@ -0,0 +1,347 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrNothingToReport = errors.New("no pronunciation results to report")
+
+// Report contents. Weak phonemes follow the Anki deck's definition so the
+// report and the deck agree on what needs work.
+const (
+	ReportLinkExpiry         = 7 * 24 * time.Hour // The longest an S3 presigned URL may live
+	reportTrendWeeks         = 12
+	reportMessageLookback    = 500
+	reportMaxHardestPhonemes = 8
+	reportMaxSubstitutions   = 8
+	reportMaxDrills          = 5
+	reportExamplesPerDrill   = 2
+)
+
+// PronunciationReporter generates shareable pronunciation reports
+type PronunciationReporter interface {
+	CreateReport(ctx context.Context, user *models.User) (*PronunciationReport, error)
+}
+
+// PronunciationReport is a generated report and the link it can be shared by
+type PronunciationReport struct {
+	ReportID  uuid.UUID `json:"reportId"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PronunciationReportService renders a learner's pronunciation progress as
+// a PDF they can hand to a tutor: overall accuracy, a weekly trend, the
+// hardest sounds, frequent substitutions and drills for the weakest sounds.
+type PronunciationReportService struct {
+	exec        repository.Executor
+	messageRepo repository.MessageRepository
+	statsRepo   repository.PhonemeStatsRepository
+	subsRepo    repository.PhonemeSubstitutionRepository
+	storage     client.StorageClient
+
+	now func() time.Time
+}
+
+// NewPronunciationReportService creates a new pronunciation report service
+func NewPronunciationReportService(
+	database *db.DB,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+	subsRepo repository.PhonemeSubstitutionRepository,
+	storage client.StorageClient,
+) *PronunciationReportService {
+	return &PronunciationReportService{
+		exec:        database.DB,
+		messageRepo: messageRepo,
+		statsRepo:   statsRepo,
+		subsRepo:    subsRepo,
+		storage:     storage,
+		now:         time.Now,
+	}
+}
+
+// NewPronunciationReportServiceForTest creates a PronunciationReportService with injected dependencies for testing.
+func NewPronunciationReportServiceForTest(
+	exec repository.Executor,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+	subsRepo repository.PhonemeSubstitutionRepository,
+	storage client.StorageClient,
+	now func() time.Time,
+) *PronunciationReportService {
+	return &PronunciationReportService{
+		exec:        exec,
+		messageRepo: messageRepo,
+		statsRepo:   statsRepo,
+		subsRepo:    subsRepo,
+		storage:     storage,
+		now:         now,
+	}
+}
+
+// pronunciationReportKey is where a report is stored. Reports are only
+// reachable through presigned links, so the key is never exposed.
+func pronunciationReportKey(userID, reportID uuid.UUID) string {
+	return fmt.Sprintf("%s%s.pdf", pronunciationReportPrefix(userID), reportID)
+}
+
+// pronunciationReportPrefix is where all of a user's reports are stored
+func pronunciationReportPrefix(userID uuid.UUID) string {
+	return fmt.Sprintf("reports/pronunciation/%s/", userID)
+}
+
+// CreateReport renders the user's report, stores it and returns a link that
+// works without signing in until it expires
+func (s *PronunciationReportService) CreateReport(ctx context.Context, user *models.User) (*PronunciationReport, error) {
+	data, err := s.collect(user)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf, err := renderPronunciationReport(data)
+	if err != nil {
+		return nil, err
+	}
+
+	reportID := uuid.New()
+	key := pronunciationReportKey(user.ID, reportID)
+	if _, err := s.storage.UploadAudio(ctx, bytes.NewReader(pdf), key, "application/pdf"); err != nil {
+		return nil, fmt.Errorf("upload pronunciation report: %w", err)
+	}
+
+	url, err := s.storage.GetPresignedURL(ctx, key, ReportLinkExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("presign pronunciation report: %w", err)
+	}
+
+	return &PronunciationReport{
+		ReportID:  reportID,
+		URL:       url,
+		ExpiresAt: data.generatedAt.Add(ReportLinkExpiry),
+	}, nil
+}
+
+// reportData is everything the PDF shows
+type reportData struct {
+	learnerName string
+	generatedAt time.Time
+
+	accuracy          float64 // Percentage over all phoneme attempts
+	attempts          int
+	phonemesPracticed int
+	recordings        int // Analyzed recordings in the trend window
+
+	trend         []reportWeek
+	hardest       []repository.PhonemeAccuracy // Weakest first
+	substitutions []models.PhonemeSubstitution
+	drills        []reportDrill
+}
+
+// reportWeek is one bar of the trend chart. Weeks start on Monday (UTC).
+type reportWeek struct {
+	start      time.Time
+	accuracy   float64
+	recordings int // 0 means no data that week
+}
+
+// reportDrill is a recommended exercise for one weak phoneme
+type reportDrill struct {
+	phoneme  string
+	accuracy float64
+	attempts int
+	saidAs   string
+	tip      string
+	examples []string // The user's own practice lines where they missed it
+}
+
+func (s *PronunciationReportService) collect(user *models.User) (*reportData, error) {
+	stats, err := s.statsRepo.FindByUserID(s.exec, user.ID, DefaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("find phoneme stats: %w", err)
+	}
+	data := &reportData{
+		learnerName: user.Name,
+		generatedAt: s.now().UTC(),
+	}
+	var correct int
+	for _, st := range stats {
+		data.attempts += st.TotalAttempts
+		correct += st.CorrectCount
+		if st.TotalAttempts > 0 {
+			data.phonemesPracticed++
+		}
+	}
+	if data.attempts == 0 {
+		return nil, ErrNothingToReport
+	}
+	data.accuracy = float64(correct) / float64(data.attempts) * 100
+
+	ranking, err := s.statsRepo.GetAccuracyRanking(s.exec, user.ID, DefaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("get phoneme ranking: %w", err)
+	}
+	for _, p := range ranking {
+		if p.TotalAttempts < AnkiMinAttempts {
+			continue
+		}
+		data.hardest = append(data.hardest, p)
+		if len(data.hardest) == reportMaxHardestPhonemes {
+			break
+		}
+	}
+
+	subs, err := s.subsRepo.FindTopByUserID(s.exec, user.ID, DefaultLanguage, reportMaxSubstitutions)
+	if err != nil {
+		return nil, fmt.Errorf("get substitutions: %w", err)
+	}
+	data.substitutions = subs
+
+	messages, err := s.messageRepo.FindAnalyzedByUserID(s.exec, user.ID, reportMessageLookback)
+	if err != nil {
+		return nil, fmt.Errorf("get analyzed messages: %w", err)
+	}
+	data.trend, data.recordings = weeklyAccuracy(messages, data.generatedAt, reportTrendWeeks)
+	data.drills = recommendDrills(data.hardest, subs, messages)
+
+	return data, nil
+}
+
+// weeklyAccuracy buckets analyzed messages into the last n weeks, oldest
+// first, and counts the messages that fell inside them
+func weeklyAccuracy(messages []models.Message, now time.Time, n int) ([]reportWeek, int) {
+	thisWeek := weekStart(now)
+	weeks := make([]reportWeek, n)
+	matches := make([]int, n)
+	phonemes := make([]int, n)
+	for i := range weeks {
+		weeks[i].start = thisWeek.AddDate(0, 0, -7*(n-1-i))
+	}
+
+	recordings := 0
+	for _, msg := range messages {
+		i := n - 1 - int(thisWeek.Sub(weekStart(msg.Timestamp)).Hours()/(24*7))
+		if i < 0 || i >= n {
+			continue
+		}
+		analysis, ok := parseAnalysis(msg.PronunciationAnalysis)
+		if !ok || analysis.PhonemeCount == 0 {
+			continue
+		}
+		matches[i] += analysis.MatchCount
+		phonemes[i] += analysis.PhonemeCount
+		weeks[i].recordings++
+		recordings++
+	}
+
+	for i := range weeks {
+		if phonemes[i] > 0 {
+			weeks[i].accuracy = math.Min(100, float64(matches[i])/float64(phonemes[i])*100)
+		}
+	}
+	return weeks, recordings
+}
+
+// weekStart is midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// recommendDrills suggests an exercise for each of the weakest phonemes,
+// illustrated with practice lines where the user missed it
+func recommendDrills(hardest []repository.PhonemeAccuracy, subs []models.PhonemeSubstitution, messages []models.Message) []reportDrill {
+	var drills []reportDrill
+	isWeak := make(map[string]bool)
+	for _, p := range hardest {
+		if p.Accuracy >= AnkiWeakAccuracy {
+			continue
+		}
+		isWeak[p.Phoneme] = true
+		drills = append(drills, reportDrill{
+			phoneme:  p.Phoneme,
+			accuracy: p.Accuracy,
+			attempts: p.TotalAttempts,
+			tip:      drillTip(p.Phoneme),
+		})
+		if len(drills) == reportMaxDrills {
+			break
+		}
+	}
+	if len(drills) == 0 {
+		return nil
+	}
+
+	// Top substitutions come most frequent first, so the first one seen wins
+	saidAs := make(map[string]string)
+	for _, sub := range subs {
+		if _, ok := saidAs[sub.ExpectedPhoneme]; !ok {
+			saidAs[sub.ExpectedPhoneme] = sub.ActualPhoneme
+		}
+	}
+
+	examples := make(map[string][]string)
+	for _, phrase := range findFlaggedPhrases(messages, isWeak, reportMaxDrills*reportExamplesPerDrill*2) {
+		for _, p := range phrase.phonemes {
+			if len(examples[p]) < reportExamplesPerDrill {
+				examples[p] = append(examples[p], phrase.text)
+			}
+		}
+	}
+
+	for i := range drills {
+		drills[i].saidAs = saidAs[drills[i].phoneme]
+		drills[i].examples = examples[drills[i].phoneme]
+	}
+	return drills
+}
+
+// drillTips are articulation cues for the sounds learners most often
+// struggle with. Anything else gets the generic listen-and-repeat drill.
+var drillTips = map[string]string{
+	"θ":  "Rest the tip of your tongue lightly between your teeth and blow air out without using your voice.",
+	"ð":  "Tongue between the teeth as for /θ/, but add voice: you should feel a buzz.",
+	"r":  "Pull the tongue tip back without touching the roof of your mouth, and round your lips slightly.",
+	"ɹ":  "Pull the tongue tip back without touching the roof of your mouth, and round your lips slightly.",
+	"l":  "Press the tongue tip on the ridge behind your upper teeth and let air flow around the sides.",
+	"v":  "Rest your upper teeth on your lower lip and add voice so the sound buzzes.",
+	"w":  "Start with tightly rounded lips and open them into the vowel; the teeth don't touch the lip.",
+	"z":  "Say a long /s/, then add voice without moving your tongue.",
+	"ʃ":  "Push your lips forward and pull the tongue a little further back than for /s/.",
+	"ʒ":  "Shape the lips as for /ʃ/ and add voice.",
+	"tʃ": "Start from /t/ and release straight into /ʃ/ in one movement.",
+	"dʒ": "Start from /d/ and release straight into /ʒ/ in one movement.",
+	"ŋ":  "Raise the back of the tongue to the soft palate and let the sound out through your nose; don't release a /g/.",
+	"h":  "Breathe out through an open mouth, as if fogging a mirror.",
+	"æ":  "Drop your jaw and spread your lips, as in \"cat\".",
+	"ɪ":  "Keep it short and relaxed; shorter and lower than /iː/.",
+	"i":  "Spread your lips into a slight smile and hold the sound.",
+	"iː": "Spread your lips into a slight smile and hold the sound longer than /ɪ/.",
+	"ʊ":  "Round your lips loosely and keep the sound short.",
+	"u":  "Round your lips tightly and push them forward.",
+	"uː": "Round your lips tightly, push them forward and hold the sound.",
+	"ə":  "Relax everything: the unstressed vowel in \"about\" is short and neutral.",
+	"ʌ":  "Keep the mouth half open and relaxed, as in \"cup\".",
+}
+
+const genericDrillTip = "Listen to the sound in a slow recording, then repeat the example slowly and at normal speed."
+
+func drillTip(phoneme string) string {
+	if tip, ok := drillTips[phoneme]; ok {
+		return tip
+	}
+	return genericDrillTip
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// The PDF core fonts only cover Latin-1, which has none of the IPA symbols
+// the report is full of, so a Unicode font is embedded. See fonts/README.md.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	reportFontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	reportFontBold []byte
+)
+
+// Layout, in millimetres on A4
+const (
+	reportFont        = "DejaVu"
+	reportMargin      = 18.0
+	reportLineHeight  = 5.5
+	reportChartHeight = 45.0
+	reportBarHeight   = 6.0
+)
+
+var (
+	reportTextColor  = [3]int{17, 24, 39}
+	reportMutedColor = [3]int{107, 114, 128}
+	reportRuleColor  = [3]int{229, 231, 235}
+)
+
+// renderPronunciationReport lays out the report as an A4 PDF
+func renderPronunciationReport(data *reportData) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(reportFont, "", reportFontRegular)
+	pdf.AddUTF8FontFromBytes(reportFont, "B", reportFontBold)
+	pdf.SetMargins(reportMargin, reportMargin, reportMargin)
+	pdf.SetAutoPageBreak(true, reportMargin)
+	pdf.SetTitle("Pronunciation report", true)
+	pdf.SetCreator("Ling App", true)
+	pdf.SetCreationDate(data.generatedAt)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		setColor(pdf, reportMutedColor)
+		pdf.SetFont(reportFont, "", 8)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Ling App · page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	r := &reportRenderer{pdf: pdf, data: data}
+	pdf.AddPage()
+	r.header()
+	r.summary()
+	r.trendChart()
+	r.hardestSounds()
+	r.substitutionTable()
+	r.drillList()
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render pronunciation report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+type reportRenderer struct {
+	pdf  *gofpdf.Fpdf
+	data *reportData
+}
+
+// contentWidth is the printable width between the margins
+func (r *reportRenderer) contentWidth() float64 {
+	pageWidth, _ := r.pdf.GetPageSize()
+	left, _, right, _ := r.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+// ensureSpace starts a new page unless h millimetres fit on this one, so
+// charts aren't split across pages
+func (r *reportRenderer) ensureSpace(h float64) {
+	_, pageHeight := r.pdf.GetPageSize()
+	_, bottom := r.pdf.GetAutoPageBreak()
+	if r.pdf.GetY()+h > pageHeight-bottom {
+		r.pdf.AddPage()
+	}
+}
+
+func (r *reportRenderer) heading(title string) {
+	r.ensureSpace(20)
+	r.pdf.Ln(6)
+	setColor(r.pdf, reportTextColor)
+	r.pdf.SetFont(reportFont, "B", 13)
+	r.pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+}
+
+func (r *reportRenderer) note(text string) {
+	setColor(r.pdf, reportMutedColor)
+	r.pdf.SetFont(reportFont, "", 9)
+	r.pdf.MultiCell(0, 4.5, text, "", "L", false)
+}
+
+func (r *reportRenderer) header() {
+	pdf := r.pdf
+	setColor(pdf, reportTextColor)
+	pdf.SetFont(reportFont, "B", 20)
+	pdf.CellFormat(0, 10, "Pronunciation report", "", 1, "L", false, 0, "")
+
+	subtitle := "Generated " + r.data.generatedAt.Format("2 January 2006")
+	if r.data.learnerName != "" {
+		subtitle = r.data.learnerName + " · " + subtitle
+	}
+	setColor(pdf, reportMutedColor)
+	pdf.SetFont(reportFont, "", 10)
+	pdf.CellFormat(0, 6, subtitle, "", 1, "L", false, 0, "")
+}
+
+// summary is a row of headline figures
+func (r *reportRenderer) summary() {
+	pdf := r.pdf
+	figures := []struct{ value, label string }{
+		{fmt.Sprintf("%.0f%%", r.data.accuracy), "overall accuracy"},
+		{strconv.Itoa(r.data.attempts), "sounds attempted"},
+		{strconv.Itoa(r.data.phonemesPracticed), "different sounds practiced"},
+		{strconv.Itoa(r.data.recordings), fmt.Sprintf("recordings in %d weeks", reportTrendWeeks)},
+	}
+
+	pdf.Ln(6)
+	gap := 4.0
+	boxWidth := (r.contentWidth() - gap*float64(len(figures)-1)) / float64(len(figures))
+	x, y := pdf.GetX(), pdf.GetY()
+	for i, f := range figures {
+		bx := x + float64(i)*(boxWidth+gap)
+		setDrawColor(pdf, reportRuleColor)
+		pdf.RoundedRect(bx, y, boxWidth, 22, 2, "1234", "D")
+
+		pdf.SetXY(bx, y+3)
+		if i == 0 {
+			setColor(pdf, hexColor(accuracyColor(r.data.accuracy)))
+		} else {
+			setColor(pdf, reportTextColor)
+		}
+		pdf.SetFont(reportFont, "B", 16)
+		pdf.CellFormat(boxWidth, 8, f.value, "", 0, "C", false, 0, "")
+
+		pdf.SetXY(bx, y+12)
+		setColor(pdf, reportMutedColor)
+		pdf.SetFont(reportFont, "", 8)
+		pdf.CellFormat(boxWidth, 5, f.label, "", 0, "C", false, 0, "")
+	}
+	pdf.SetXY(x, y+22)
+	pdf.Ln(2)
+	r.note("Accuracy is the share of sounds pronounced as expected across every analyzed recording.")
+}
+
+// trendChart is a bar per week, with gaps for weeks without practice
+func (r *reportRenderer) trendChart() {
+	r.heading("Accuracy by week")
+	if r.data.recordings == 0 {
+		r.note(fmt.Sprintf("No recordings were analyzed in the last %d weeks.", reportTrendWeeks))
+		return
+	}
+	r.ensureSpace(reportChartHeight + 12)
+
+	pdf := r.pdf
+	axisWidth := 10.0
+	x0, y0 := pdf.GetX()+axisWidth, pdf.GetY()+2
+	plotWidth := r.contentWidth() - axisWidth
+
+	pdf.SetFont(reportFont, "", 7)
+	for _, pct := range []float64{0, 25, 50, 75, 100} {
+		y := y0 + reportChartHeight*(1-pct/100)
+		setDrawColor(pdf, reportRuleColor)
+		pdf.Line(x0, y, x0+plotWidth, y)
+		setColor(pdf, reportMutedColor)
+		pdf.SetXY(x0-axisWidth, y-2)
+		pdf.CellFormat(axisWidth-1.5, 4, fmt.Sprintf("%.0f%%", pct), "", 0, "R", false, 0, "")
+	}
+
+	slot := plotWidth / float64(len(r.data.trend))
+	barWidth := slot * 0.6
+	for i, week := range r.data.trend {
+		bx := x0 + float64(i)*slot + (slot-barWidth)/2
+		if week.recordings > 0 {
+			h := reportChartHeight * week.accuracy / 100
+			setFillColor(pdf, hexColor(accuracyColor(week.accuracy)))
+			pdf.Rect(bx, y0+reportChartHeight-h, barWidth, h, "F")
+
+			setColor(pdf, reportTextColor)
+			pdf.SetXY(bx-2, y0+reportChartHeight-h-4.5)
+			pdf.CellFormat(barWidth+4, 4, fmt.Sprintf("%.0f", week.accuracy), "", 0, "C", false, 0, "")
+		}
+		setColor(pdf, reportMutedColor)
+		pdf.SetXY(x0+float64(i)*slot, y0+reportChartHeight+1)
+		pdf.CellFormat(slot, 4, week.start.Format("2 Jan"), "", 0, "C", false, 0, "")
+	}
+
+	pdf.SetXY(x0-axisWidth, y0+reportChartHeight+6)
+	r.note("Weeks start on Monday. Empty weeks had no analyzed recordings.")
+}
+
+// hardestSounds is a horizontal bar per phoneme, weakest first
+func (r *reportRenderer) hardestSounds() {
+	r.heading("Hardest sounds")
+	if len(r.data.hardest) == 0 {
+		r.note(fmt.Sprintf("Sounds appear here once they've been attempted at least %d times.", AnkiMinAttempts))
+		return
+	}
+
+	pdf := r.pdf
+	labelWidth, valueWidth := 18.0, 40.0
+	barSpace := r.contentWidth() - labelWidth - valueWidth
+	for _, p := range r.data.hardest {
+		r.ensureSpace(reportBarHeight + 2)
+		x, y := pdf.GetX(), pdf.GetY()
+
+		setColor(pdf, reportTextColor)
+		pdf.SetFont(reportFont, "B", 10)
+		pdf.CellFormat(labelWidth, reportBarHeight, "/"+p.Phoneme+"/", "", 0, "L", false, 0, "")
+
+		setFillColor(pdf, reportRuleColor)
+		pdf.Rect(x+labelWidth, y+1, barSpace, reportBarHeight-2, "F")
+		setFillColor(pdf, hexColor(accuracyColor(p.Accuracy)))
+		pdf.Rect(x+labelWidth, y+1, barSpace*p.Accuracy/100, reportBarHeight-2, "F")
+
+		pdf.SetXY(x+labelWidth+barSpace, y)
+		setColor(pdf, reportMutedColor)
+		pdf.SetFont(reportFont, "", 9)
+		pdf.CellFormat(valueWidth, reportBarHeight, fmt.Sprintf("%.0f%% of %d", p.Accuracy, p.TotalAttempts), "", 1, "R", false, 0, "")
+		pdf.Ln(1.5)
+	}
+}
+
+func (r *reportRenderer) substitutionTable() {
+	r.heading("Most frequent substitutions")
+	if len(r.data.substitutions) == 0 {
+		r.note("No sound has been replaced by another yet.")
+		return
+	}
+
+	pdf := r.pdf
+	cols := []float64{40, 40, r.contentWidth() - 80}
+	row := func(style string, color [3]int, cells ...string) {
+		r.ensureSpace(reportLineHeight + 2)
+		setColor(pdf, color)
+		pdf.SetFont(reportFont, style, 10)
+		for i, cell := range cells {
+			align := "L"
+			if i == len(cells)-1 {
+				align = "R"
+			}
+			pdf.CellFormat(cols[i], reportLineHeight+1, cell, "B", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	setDrawColor(pdf, reportRuleColor)
+	row("B", reportMutedColor, "Expected", "Said as", "Times")
+	for _, sub := range r.data.substitutions {
+		row("", reportTextColor, "/"+sub.ExpectedPhoneme+"/", "/"+sub.ActualPhoneme+"/", strconv.Itoa(sub.OccurrenceCount))
+	}
+}
+
+func (r *reportRenderer) drillList() {
+	r.heading("Recommended drills")
+	if len(r.data.drills) == 0 {
+		r.note(fmt.Sprintf("Every sound with enough attempts is above %.0f%%. Keep it up with new material.", AnkiWeakAccuracy))
+		return
+	}
+
+	pdf := r.pdf
+	for i, d := range r.data.drills {
+		r.ensureSpace(24)
+		if i > 0 {
+			pdf.Ln(3)
+		}
+
+		title := fmt.Sprintf("/%s/  %.0f%% over %d attempts", d.phoneme, d.accuracy, d.attempts)
+		if d.saidAs != "" {
+			title += fmt.Sprintf(", often said as /%s/", d.saidAs)
+		}
+		setColor(pdf, reportTextColor)
+		pdf.SetFont(reportFont, "B", 10.5)
+		pdf.MultiCell(0, reportLineHeight+0.5, title, "", "L", false)
+
+		pdf.SetFont(reportFont, "", 10)
+		pdf.MultiCell(0, reportLineHeight, d.tip, "", "L", false)
+
+		if len(d.examples) > 0 {
+			quoted := make([]string, len(d.examples))
+			for j, ex := range d.examples {
+				quoted[j] = "“" + ex + "”"
+			}
+			setColor(pdf, reportMutedColor)
+			pdf.MultiCell(0, reportLineHeight, "Practice: "+strings.Join(quoted, "  "), "", "L", false)
+		}
+	}
+}
+
+func setColor(pdf *gofpdf.Fpdf, c [3]int)     { pdf.SetTextColor(c[0], c[1], c[2]) }
+func setFillColor(pdf *gofpdf.Fpdf, c [3]int) { pdf.SetFillColor(c[0], c[1], c[2]) }
+func setDrawColor(pdf *gofpdf.Fpdf, c [3]int) { pdf.SetDrawColor(c[0], c[1], c[2]) }
+
+// hexColor converts "#rrggbb" to RGB
+func hexColor(hex string) [3]int {
+	v, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return reportTextColor
+	}
+	return [3]int{int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff)}
+}
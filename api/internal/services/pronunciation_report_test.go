@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestPronunciationReportService_CreateReport(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Name: "Ana"}
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC) // A Thursday
+	practiceLine := "Think about three things"
+
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+	subsRepo := new(repomocks.MockPhonemeSubstitutionRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	storage := new(clientmocks.MockStorageClient)
+
+	statsRepo.On("FindByUserID", mock.Anything, user.ID, "en").Return([]models.PhonemeStats{
+		{Phoneme: "θ", TotalAttempts: 20, CorrectCount: 8},
+		{Phoneme: "s", TotalAttempts: 80, CorrectCount: 76},
+	}, nil)
+	statsRepo.On("GetAccuracyRanking", mock.Anything, user.ID, "en").Return([]repository.PhonemeAccuracy{
+		{Phoneme: "θ", TotalAttempts: 20, CorrectCount: 8, Accuracy: 40},
+		{Phoneme: "s", TotalAttempts: 80, CorrectCount: 76, Accuracy: 95},
+	}, nil)
+	subsRepo.On("FindTopByUserID", mock.Anything, user.ID, "en", reportMaxSubstitutions).Return([]models.PhonemeSubstitution{
+		{ExpectedPhoneme: "θ", ActualPhoneme: "t", OccurrenceCount: 9},
+	}, nil)
+	analysis := analysisWith(client.PhonemeDetail{Expected: "θ", Actual: "t", Type: "substitute"})
+	analysis["phoneme_count"], analysis["match_count"] = 10, 8
+	messageRepo.On("FindAnalyzedByUserID", mock.Anything, user.ID, reportMessageLookback).Return([]models.Message{
+		{Content: "tink about tree tings", ExpectedText: &practiceLine, Timestamp: now.Add(-time.Hour), PronunciationAnalysis: analysis},
+	}, nil)
+
+	var uploaded []byte
+	var key string
+	storage.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, "application/pdf").
+		Run(func(args mock.Arguments) {
+			uploaded, _ = io.ReadAll(args.Get(1).(io.Reader))
+			key = args.String(2)
+		}).Return("", nil)
+	storage.On("GetPresignedURL", mock.Anything, mock.Anything, ReportLinkExpiry).Return("https://s3.example/report.pdf?sig", nil)
+
+	svc := NewPronunciationReportServiceForTest(nil, messageRepo, statsRepo, subsRepo, storage, func() time.Time { return now })
+	report, err := svc.CreateReport(context.Background(), user)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://s3.example/report.pdf?sig", report.URL)
+	assert.Equal(t, now.Add(ReportLinkExpiry), report.ExpiresAt)
+	assert.Equal(t, "reports/pronunciation/"+user.ID.String()+"/"+report.ReportID.String()+".pdf", key)
+	storage.AssertCalled(t, "GetPresignedURL", mock.Anything, key, ReportLinkExpiry)
+
+	assert.True(t, bytes.HasPrefix(uploaded, []byte("%PDF-")), "uploads a PDF")
+	assert.True(t, bytes.HasSuffix(bytes.TrimSpace(uploaded), []byte("%%EOF")))
+}
+
+func TestPronunciationReportService_CreateReport_NothingToReport(t *testing.T) {
+	user := &models.User{ID: uuid.New()}
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+	storage := new(clientmocks.MockStorageClient)
+	statsRepo.On("FindByUserID", mock.Anything, user.ID, "en").Return([]models.PhonemeStats{}, nil)
+
+	svc := NewPronunciationReportServiceForTest(nil, nil, statsRepo, nil, storage, time.Now)
+	_, err := svc.CreateReport(context.Background(), user)
+
+	assert.True(t, errors.Is(err, ErrNothingToReport))
+	storage.AssertNotCalled(t, "UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWeeklyAccuracy(t *testing.T) {
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC) // Thursday; the week starts Monday 9 March
+	withCounts := func(phonemes, matches int) models.JSONMap {
+		return models.JSONMap{"phoneme_count": phonemes, "match_count": matches}
+	}
+	messages := []models.Message{
+		{Timestamp: time.Date(2026, 3, 9, 0, 30, 0, 0, time.UTC), PronunciationAnalysis: withCounts(10, 9)},
+		{Timestamp: time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC), PronunciationAnalysis: withCounts(10, 7)},
+		{Timestamp: time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC), PronunciationAnalysis: withCounts(4, 2)},  // Previous week
+		{Timestamp: time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC), PronunciationAnalysis: withCounts(5, 5)}, // Outside the window
+		{Timestamp: now, PronunciationAnalysis: models.JSONMap{}},                                           // No phonemes; ignored
+	}
+
+	weeks, recordings := weeklyAccuracy(messages, now, 4)
+
+	require.Len(t, weeks, 4)
+	assert.Equal(t, 3, recordings)
+	assert.Equal(t, time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC), weeks[0].start)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), weeks[3].start)
+	assert.Equal(t, 0, weeks[0].recordings)
+	assert.Equal(t, 1, weeks[2].recordings)
+	assert.InDelta(t, 50, weeks[2].accuracy, 0.001)
+	assert.Equal(t, 2, weeks[3].recordings)
+	assert.InDelta(t, 80, weeks[3].accuracy, 0.001)
+}
+
+func TestRecommendDrills(t *testing.T) {
+	line := "Think about three things"
+	hardest := []repository.PhonemeAccuracy{
+		{Phoneme: "θ", TotalAttempts: 20, Accuracy: 40},
+		{Phoneme: "ʁ", TotalAttempts: 10, Accuracy: 70},
+		{Phoneme: "s", TotalAttempts: 80, Accuracy: 95}, // Not weak
+	}
+	subs := []models.PhonemeSubstitution{
+		{ExpectedPhoneme: "θ", ActualPhoneme: "t", OccurrenceCount: 9},
+		{ExpectedPhoneme: "θ", ActualPhoneme: "s", OccurrenceCount: 2},
+	}
+	messages := []models.Message{
+		{Content: "tink", ExpectedText: &line, PronunciationAnalysis: analysisWith(
+			client.PhonemeDetail{Expected: "θ", Actual: "t", Type: "substitute"},
+		)},
+	}
+
+	drills := recommendDrills(hardest, subs, messages)
+
+	require.Len(t, drills, 2)
+	assert.Equal(t, "θ", drills[0].phoneme)
+	assert.Equal(t, "t", drills[0].saidAs)
+	assert.Equal(t, []string{line}, drills[0].examples)
+	assert.True(t, strings.Contains(drills[0].tip, "teeth"))
+	assert.Equal(t, "ʁ", drills[1].phoneme)
+	assert.Equal(t, genericDrillTip, drills[1].tip)
+	assert.Empty(t, drills[1].examples)
+}
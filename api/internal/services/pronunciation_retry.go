@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// MaxPronunciationRetries is how many times a user can have one message's
+// failed analysis run again
+const MaxPronunciationRetries = 3
+
+var ErrTooManyAnalysisRetries = errors.New("pronunciation analysis retried too many times")
+
+// PronunciationRetrier defines the interface for retrying failed analyses
+type PronunciationRetrier interface {
+	RetryAnalysis(userID, messageID uuid.UUID) (*models.Message, error)
+}
+
+// PronunciationRetryService lets users run a failed pronunciation analysis
+// again, for instance after the ML service was briefly down. Like the
+// support team's requeue, it covers single voice messages whose recording
+// is still stored.
+type PronunciationRetryService struct {
+	exec        repository.Executor
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+	analyzer    PronunciationAnalyzer
+
+	now func() time.Time
+}
+
+// NewPronunciationRetryService creates a new pronunciation retry service
+func NewPronunciationRetryService(
+	database *db.DB,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	analyzer PronunciationAnalyzer,
+) *PronunciationRetryService {
+	return NewPronunciationRetryServiceForTest(database.DB, threadRepo, messageRepo, analyzer, time.Now)
+}
+
+// NewPronunciationRetryServiceForTest creates a PronunciationRetryService with injected dependencies for testing.
+func NewPronunciationRetryServiceForTest(
+	exec repository.Executor,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	analyzer PronunciationAnalyzer,
+	now func() time.Time,
+) *PronunciationRetryService {
+	return &PronunciationRetryService{
+		exec:        exec,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		analyzer:    analyzer,
+		now:         now,
+	}
+}
+
+// RetryAnalysis queues one of the user's failed analyses again and returns
+// the message, now pending. Messages of other users are not found.
+func (s *PronunciationRetryService) RetryAnalysis(userID, messageID uuid.UUID) (*models.Message, error) {
+	message, err := s.messageRepo.FindByID(s.exec, messageID)
+	if err != nil {
+		return nil, err
+	}
+	thread, err := s.threadRepo.FindByIDAndUserID(s.exec, message.ThreadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if message.PronunciationStatus != "failed" {
+		return nil, ErrAnalysisNotFailed
+	}
+	if message.Role != "user" || message.AudioURL == nil || message.Kind == models.MessageKindLongForm {
+		return nil, ErrAnalysisNotRequeuable
+	}
+	if message.PronunciationRetries >= MaxPronunciationRetries {
+		return nil, ErrTooManyAnalysisRetries
+	}
+
+	// Checked again in the update, so two quick retries queue one analysis
+	retried, err := s.messageRepo.RetryPronunciation(s.exec, message.ID, MaxPronunciationRetries, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark analysis pending: %w", err)
+	}
+	if !retried {
+		return nil, ErrAnalysisNotFailed
+	}
+	s.analyzer.Enqueue(message.ThreadID, message.ID, *message.AudioURL, scoringText(message), PhonemeLanguage(ThreadLanguage(thread)))
+
+	return s.messageRepo.FindByID(s.exec, message.ID)
+}
+
+// scoringText is what a message's recording is analyzed against: the
+// practice line, or else the transcript; until the user corrects it, the raw
+// Whisper text
+func scoringText(message *models.Message) string {
+	if message.ExpectedText != nil {
+		return *message.ExpectedText
+	}
+	if message.RawTranscript != nil && message.TranscriptCorrectedAt == nil {
+		return *message.RawTranscript
+	}
+	return message.Content
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+	"ling-app/api/internal/testutil/factory"
+)
+
+func failedVoiceMessage() *models.Message {
+	return factory.VoiceMessage(uuid.New(), "I think so", func(m *models.Message) {
+		raw := "i think so"
+		m.RawTranscript = &raw
+		m.PronunciationStatus = "failed"
+	})
+}
+
+func TestPronunciationRetryService_RetryAnalysis(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("queues the analysis against the raw transcript", func(t *testing.T) {
+		message := failedVoiceMessage()
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, message.ThreadID, userID).Return(&models.Thread{ID: message.ThreadID, UserID: userID}, nil)
+		messageRepo := new(repomocks.MockMessageRepository)
+		messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+		messageRepo.On("RetryPronunciation", mock.Anything, message.ID, MaxPronunciationRetries, now).Return(true, nil)
+		analyzer := new(stubAnalyzer)
+		analyzer.On("Enqueue", message.ThreadID, message.ID, *message.AudioURL, "i think so", PronunciationLanguage).Return()
+
+		service := NewPronunciationRetryServiceForTest(nil, threadRepo, messageRepo, analyzer, clock)
+		_, err := service.RetryAnalysis(userID, message.ID)
+		require.NoError(t, err)
+
+		messageRepo.AssertExpectations(t)
+		analyzer.AssertExpectations(t)
+	})
+
+	t.Run("another user's message", func(t *testing.T) {
+		message := failedVoiceMessage()
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, message.ThreadID, userID).Return(nil, repository.ErrNotFound)
+		messageRepo := new(repomocks.MockMessageRepository)
+		messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+
+		service := NewPronunciationRetryServiceForTest(nil, threadRepo, messageRepo, new(stubAnalyzer), clock)
+		_, err := service.RetryAnalysis(userID, message.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(*models.Message)
+		want   error
+	}{
+		{"analysis didn't fail", func(m *models.Message) { m.PronunciationStatus = "complete" }, ErrAnalysisNotFailed},
+		{"recording deleted", func(m *models.Message) { m.AudioURL = nil }, ErrAnalysisNotRequeuable},
+		{"long-form message", func(m *models.Message) { m.Kind = models.MessageKindLongForm }, ErrAnalysisNotRequeuable},
+		{"out of retries", func(m *models.Message) { m.PronunciationRetries = MaxPronunciationRetries }, ErrTooManyAnalysisRetries},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := failedVoiceMessage()
+			tt.modify(message)
+			threadRepo := new(repomocks.MockThreadRepository)
+			threadRepo.On("FindByIDAndUserID", mock.Anything, message.ThreadID, userID).Return(&models.Thread{ID: message.ThreadID, UserID: userID}, nil)
+			messageRepo := new(repomocks.MockMessageRepository)
+			messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+
+			service := NewPronunciationRetryServiceForTest(nil, threadRepo, messageRepo, new(stubAnalyzer), clock)
+			_, err := service.RetryAnalysis(userID, message.ID)
+			assert.ErrorIs(t, err, tt.want)
+			messageRepo.AssertNotCalled(t, "RetryPronunciation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("lost the race to another retry", func(t *testing.T) {
+		message := failedVoiceMessage()
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, message.ThreadID, userID).Return(&models.Thread{ID: message.ThreadID, UserID: userID}, nil)
+		messageRepo := new(repomocks.MockMessageRepository)
+		messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+		messageRepo.On("RetryPronunciation", mock.Anything, message.ID, MaxPronunciationRetries, now).Return(false, nil)
+		analyzer := new(stubAnalyzer)
+
+		service := NewPronunciationRetryServiceForTest(nil, threadRepo, messageRepo, analyzer, clock)
+		_, err := service.RetryAnalysis(userID, message.ID)
+		assert.ErrorIs(t, err, ErrAnalysisNotFailed)
+		analyzer.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
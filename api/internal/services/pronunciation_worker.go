@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
+	"ling-app/api/internal/analytics"
 	"ling-app/api/internal/client"
 	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/jobs"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
 
@@ -17,30 +21,65 @@ import (
 
 // PronunciationWorker handles async pronunciation analysis
 type PronunciationWorker struct {
-	DB                  *db.DB
-	exec                repository.Executor
-	messageRepo         repository.MessageRepository
-	threadRepo          repository.ThreadRepository
-	MLClient            client.MLClient
-	Storage             client.StorageClient
-	PhonemeStatsService *PhonemeStatsService
+	DB            *db.DB
+	exec          repository.Executor
+	messageRepo   repository.MessageRepository
+	threadRepo    repository.ThreadRepository
+	subRepo       repository.SubscriptionRepository
+	MLClient      client.MLClient
+	Storage       client.StorageClient
+	Events        *events.Bus // receives AnalysisCompleted
+	Queue         *jobs.Queue
+	Credits       CreditsManager
+	MinConfidence float64
+	Analytics     analytics.Tracker
+
+	// CallbackURL and CallbackSigner switch the worker to async mode: jobs are
+	// submitted to the ML service, which posts results to CallbackURL, instead
+	// of holding a connection open for the whole analysis.
+	CallbackURL    string
+	CallbackSigner *MLCallbackSigner
+
+	// Runtime supplies the credit cost refunded for low-confidence results
+	// and each tier's analysis quality; nil uses the defaults
+	Runtime *RuntimeSettingsService
+
+	// Chunks stores the per-recording results of long-form messages
+	Chunks repository.MessageChunkRepository
+
+	// Shadow also sends a sample of analyses to a model under evaluation;
+	// nil disables it. Only awaited analyses are sampled: with callbacks
+	// the primary model's latency isn't known.
+	Shadow *MLShadow
 }
 
 // NewPronunciationWorker creates a new pronunciation worker
 func NewPronunciationWorker(
 	database *db.DB,
+	messageRepo repository.MessageRepository,
+	threadRepo repository.ThreadRepository,
 	mlClient client.MLClient,
 	storage client.StorageClient,
-	phonemeStatsService *PhonemeStatsService,
+	bus *events.Bus,
+	queue *jobs.Queue,
+	credits CreditsManager,
+	minConfidence float64,
+	tracker analytics.Tracker,
 ) *PronunciationWorker {
 	return &PronunciationWorker{
-		DB:                  database,
-		exec:                database.DB,
-		messageRepo:         repository.NewMessageRepository(),
-		threadRepo:          repository.NewThreadRepository(),
-		MLClient:            mlClient,
-		Storage:             storage,
-		PhonemeStatsService: phonemeStatsService,
+		DB:            database,
+		exec:          database.DB,
+		messageRepo:   messageRepo,
+		threadRepo:    threadRepo,
+		subRepo:       repository.NewSubscriptionRepository(),
+		MLClient:      mlClient,
+		Storage:       storage,
+		Events:        bus,
+		Queue:         queue,
+		Credits:       credits,
+		MinConfidence: minConfidence,
+		Analytics:     tracker,
+		Chunks:        repository.NewMessageChunkRepository(),
 	}
 }
 
@@ -51,51 +90,171 @@ func NewPronunciationWorkerForTest(
 	threadRepo repository.ThreadRepository,
 	mlClient client.MLClient,
 	storage client.StorageClient,
-	phonemeStatsService *PhonemeStatsService,
+	bus *events.Bus,
 ) *PronunciationWorker {
 	return &PronunciationWorker{
-		DB:                  nil,
-		exec:                exec,
-		messageRepo:         messageRepo,
-		threadRepo:          threadRepo,
-		MLClient:            mlClient,
-		Storage:             storage,
-		PhonemeStatsService: phonemeStatsService,
+		DB:            nil,
+		exec:          exec,
+		messageRepo:   messageRepo,
+		threadRepo:    threadRepo,
+		MLClient:      mlClient,
+		Storage:       storage,
+		Events:        bus,
+		MinConfidence: DefaultMinPronunciationConfidence,
+	}
+}
+
+// TierAnalysisQuality is each tier's default pronunciation analysis quality
+// (the values in force come from RuntimeSettings): the fast model for free
+// users, the full one for paid tiers
+var TierAnalysisQuality = map[models.SubscriptionTier]client.AnalysisQuality{
+	models.TierFree:  client.QualityFast,
+	models.TierBasic: client.QualityAccurate,
+	models.TierPro:   client.QualityAccurate,
+}
+
+// LowConfidenceRefundDescription describes the refund of a turn whose
+// recording scored too low to trust
+const LowConfidenceRefundDescription = "Refund: low-confidence pronunciation score"
+
+// PronunciationLanguage is the code English is scored in, and what
+// PhonemeLanguage falls back to for a language it doesn't know. Other thread
+// languages score in their own PhonemeLanguage.
+const PronunciationLanguage = "en-us"
+
+// Enqueue schedules pronunciation analysis on the job queue, in the priority
+// lane for paid tiers and at the quality set for the tier. Without a queue it
+// falls back to a bare goroutine.
+func (w *PronunciationWorker) Enqueue(threadID, messageID uuid.UUID, audioKey, expectedText, language string) {
+	tier := w.tierForThread(threadID)
+	quality := w.Runtime.Current().AnalysisQuality(tier)
+	if w.Queue == nil {
+		go w.AnalyzeAsync(context.Background(), messageID, audioKey, expectedText, language, quality)
+		return
+	}
+
+	err := w.Queue.Enqueue(jobs.Job{
+		Name: "pronunciation:" + messageID.String(),
+		Lane: LaneForTier(tier),
+		Run: func(ctx context.Context) error {
+			return w.AnalyzeAsync(ctx, messageID, audioKey, expectedText, language, quality)
+		},
+	})
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to enqueue analysis for message %s: %v", messageID, err)
+		w.markFailed(messageID, "QUEUE_ERROR", err.Error())
 	}
 }
 
-// AnalyzeAsync runs pronunciation analysis asynchronously
-// This should be called from a goroutine so it doesn't block the HTTP response
-func (w *PronunciationWorker) AnalyzeAsync(messageID uuid.UUID, audioKey, expectedText, language string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+// tierForThread returns the thread owner's subscription tier, which picks the
+// job lane and analysis quality. Owners whose tier can't be found are treated
+// as free.
+func (w *PronunciationWorker) tierForThread(threadID uuid.UUID) models.SubscriptionTier {
+	if w.subRepo == nil {
+		return models.TierFree
+	}
+
+	thread, err := w.threadRepo.FindByID(w.exec, threadID)
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to fetch thread for tier lookup: %v", err)
+		return models.TierFree
+	}
+
+	sub, err := w.subRepo.FindByUserID(w.exec, thread.UserID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("[PronunciationWorker] Failed to fetch subscription for tier lookup: %v", err)
+		}
+		return models.TierFree
+	}
+
+	return sub.EffectiveTier()
+}
+
+// LaneForTier maps a subscription tier to its job queue lane
+func LaneForTier(tier models.SubscriptionTier) jobs.Lane {
+	switch tier {
+	case models.TierBasic, models.TierPro:
+		return jobs.LanePriority
+	default:
+		return jobs.LaneStandard
+	}
+}
+
+// AnalyzeAsync runs pronunciation analysis, off the request that sent the
+// message: as a job on the queue, or in a goroutine without one. A failed
+// analysis is marked failed on the message and returned. If ctx is cancelled,
+// as on shutdown, the message is left pending for RecoverPending.
+func (w *PronunciationWorker) AnalyzeAsync(ctx context.Context, messageID uuid.UUID, audioKey, expectedText, language string, quality client.AnalysisQuality) error {
+	analysisCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
 	log.Printf("[PronunciationWorker] Starting analysis for message %s", messageID)
 
 	// Generate presigned URL for the audio (1 hour expiration)
-	presignedURL, err := w.Storage.GetPresignedURL(ctx, audioKey, 1*time.Hour)
+	presignedURL, err := w.Storage.GetPresignedURL(analysisCtx, audioKey, 1*time.Hour)
 	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("analysis of message %s interrupted: %w", messageID, ctx.Err())
+		}
 		log.Printf("[PronunciationWorker] Failed to generate presigned URL: %v", err)
 		w.markFailed(messageID, "PRESIGNED_URL_ERROR", err.Error())
-		return
+		return fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+
+	if w.asyncCallbacks() {
+		token := w.CallbackSigner.Sign(messageID)
+		if err := w.MLClient.SubmitPronunciation(analysisCtx, presignedURL, expectedText, language, quality, w.CallbackURL, token); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("analysis of message %s interrupted: %w", messageID, ctx.Err())
+			}
+			w.markMLFailed(messageID, err)
+			return fmt.Errorf("failed to submit analysis: %w", err)
+		}
+		log.Printf("[PronunciationWorker] Submitted analysis for message %s; awaiting callback", messageID)
+		return nil
 	}
 
 	// Call ML service
-	result, err := w.MLClient.AnalyzePronunciation(ctx, presignedURL, expectedText, language)
+	start := time.Now()
+	result, err := w.MLClient.AnalyzePronunciation(analysisCtx, presignedURL, expectedText, language, quality)
 	if err != nil {
-		// Check if it's a structured ML service error
-		var mlErr *client.MLServiceError
-		if errors.As(err, &mlErr) {
-			log.Printf("[PronunciationWorker] ML service error: %s - %s", mlErr.Code, mlErr.Message)
-			w.markFailed(messageID, mlErr.Code, mlErr.Message)
-		} else {
-			// Network or other error
-			log.Printf("[PronunciationWorker] ML service call failed: %v", err)
-			w.markFailed(messageID, "ML_SERVICE_ERROR", err.Error())
+		if ctx.Err() != nil {
+			return fmt.Errorf("analysis of message %s interrupted: %w", messageID, ctx.Err())
 		}
-		return
+		w.markMLFailed(messageID, err)
+		return fmt.Errorf("failed to analyze pronunciation: %w", err)
+	}
+	withQuality(result.Analysis, quality)
+	if w.Shadow.Sampled() {
+		go w.Shadow.Compare(messageID, presignedURL, expectedText, language, quality, result, time.Since(start))
 	}
 
+	w.HandleResult(analysisCtx, messageID, result)
+	return nil
+}
+
+// HandleCallback applies a result the ML service posted back for an async
+// job. Results for messages that are no longer pending (a retried callback,
+// or a job that was already marked failed) are ignored.
+func (w *PronunciationWorker) HandleCallback(ctx context.Context, messageID uuid.UUID, result *client.PronunciationResponse) error {
+	message, err := w.messageRepo.FindByID(w.exec, messageID)
+	if err != nil {
+		return err
+	}
+	if message.PronunciationStatus != "pending" {
+		log.Printf("[PronunciationWorker] Ignoring callback for message %s in status %s", messageID, message.PronunciationStatus)
+		return nil
+	}
+
+	w.HandleResult(ctx, messageID, result)
+	return nil
+}
+
+// HandleResult stores an ML analysis result on the message and records the
+// user's phoneme stats, whether it arrived synchronously or by callback. The
+// quality recorded is the one the analysis reports.
+func (w *PronunciationWorker) HandleResult(ctx context.Context, messageID uuid.UUID, result *client.PronunciationResponse) {
 	// Check if ML returned an error
 	if result.Status == "error" {
 		errMsg := "Unknown error"
@@ -117,51 +276,114 @@ func (w *PronunciationWorker) AnalyzeAsync(messageID uuid.UUID, audioKey, expect
 	}
 
 	// Convert analysis to JSONMap for proper serialization
-	analysisJSON, err := json.Marshal(result.Analysis)
+	analysisMap, err := analysisToMap(result.Analysis)
 	if err != nil {
-		log.Printf("[PronunciationWorker] Failed to marshal analysis: %v", err)
+		log.Printf("[PronunciationWorker] Failed to convert analysis: %v", err)
 		w.markFailed(messageID, "JSON_ERROR", err.Error())
 		return
 	}
 
-	var analysisMap models.JSONMap
-	if err := json.Unmarshal(analysisJSON, &analysisMap); err != nil {
-		log.Printf("[PronunciationWorker] Failed to unmarshal analysis to map: %v", err)
-		w.markFailed(messageID, "JSON_ERROR", err.Error())
-		return
-	}
+	confidence := ComputeConfidence(result.Analysis)
+	lowConfidence := confidence < w.MinConfidence
 
 	// Update message with results
 	now := time.Now()
-	if err := w.messageRepo.UpdatePronunciationAnalysis(w.exec, messageID, "complete", analysisMap, now); err != nil {
+	quality := string(result.Analysis.Quality)
+	if err := w.messageRepo.UpdatePronunciationAnalysis(w.exec, messageID, "complete", analysisMap, quality, confidence, lowConfidence, now); err != nil {
 		log.Printf("[PronunciationWorker] Failed to update message: %v", err)
 		return
 	}
 
-	log.Printf("[PronunciationWorker] Analysis complete for message %s: %d/%d phonemes matched",
-		messageID, result.Analysis.MatchCount, result.Analysis.PhonemeCount)
+	log.Printf("[PronunciationWorker] Analysis complete for message %s: %d/%d phonemes matched (confidence %.2f)",
+		messageID, result.Analysis.MatchCount, result.Analysis.PhonemeCount, confidence)
 
-	// Record phoneme stats for the user
-	if w.PhonemeStatsService != nil && len(result.Analysis.PhonemeDetails) > 0 {
-		// Get user ID from message -> thread -> user
-		message, err := w.messageRepo.FindByID(w.exec, messageID)
-		if err != nil {
-			log.Printf("[PronunciationWorker] Failed to fetch message for phoneme stats: %v", err)
-			return
-		}
+	// Get user ID from message -> thread -> user
+	message, thread, err := w.findThreadForMessage(messageID)
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to fetch thread for message %s: %v", messageID, err)
+		return
+	}
 
-		thread, err := w.threadRepo.FindByID(w.exec, message.ThreadID)
-		if err != nil {
-			log.Printf("[PronunciationWorker] Failed to fetch thread for phoneme stats: %v", err)
-			return
-		}
+	if w.Analytics != nil {
+		w.Analytics.Track(ctx, thread.UserID, analytics.EventAnalysisCompleted, map[string]any{
+			"messageId":     messageID.String(),
+			"phonemeCount":  result.Analysis.PhonemeCount,
+			"matchCount":    result.Analysis.MatchCount,
+			"confidence":    confidence,
+			"lowConfidence": lowConfidence,
+			"quality":       quality,
+		})
+	}
 
-		if err := w.PhonemeStatsService.RecordPhonemeResults(thread.UserID, result.Analysis.PhonemeDetails); err != nil {
-			log.Printf("[PronunciationWorker] Failed to record phoneme stats: %v", err)
-		} else {
-			log.Printf("[PronunciationWorker] Recorded phoneme stats for user %s", thread.UserID)
+	// Low-confidence results stay out of the user's stats, and what the turn
+	// was charged is refunded so re-recording is free. Only a captured turn is
+	// refunded, once: a turn whose reply failed was never charged, and one
+	// still held is refunded by the conversation once captured. A
+	// re-analysis after a transcript correction refunds nothing: the credit
+	// was settled the first time.
+	if lowConfidence && w.Credits != nil && message.TranscriptCorrectedAt == nil {
+		if _, err := w.Credits.RefundReservation(messageID.String(), LowConfidenceRefundDescription); err != nil {
+			log.Printf("[PronunciationWorker] Failed to refund low-confidence message %s: %v", messageID, err)
 		}
 	}
+
+	completed := events.AnalysisCompleted{
+		UserID:        thread.UserID,
+		ThreadID:      thread.ID,
+		MessageID:     messageID,
+		PhonemeCount:  result.Analysis.PhonemeCount,
+		MatchCount:    result.Analysis.MatchCount,
+		Confidence:    confidence,
+		LowConfidence: lowConfidence,
+		Quality:       quality,
+		Language:      ThreadLanguage(thread),
+	}
+	if !lowConfidence && len(result.Analysis.PhonemeDetails) > 0 {
+		completed.Phonemes = [][]client.PhonemeDetail{result.Analysis.PhonemeDetails}
+	}
+	w.Events.Publish(ctx, completed)
+}
+
+// withQuality fills in the quality of an analysis from an ML service too old
+// to report it with the quality that was requested
+func withQuality(analysis *client.PronunciationAnalysis, quality client.AnalysisQuality) {
+	if analysis != nil && analysis.Quality == "" {
+		analysis.Quality = quality
+	}
+}
+
+// asyncCallbacks reports whether jobs are submitted for callback rather than awaited
+func (w *PronunciationWorker) asyncCallbacks() bool {
+	return w.CallbackURL != "" && w.CallbackSigner != nil
+}
+
+// markMLFailed records a failed ML service call on the message
+func (w *PronunciationWorker) markMLFailed(messageID uuid.UUID, err error) {
+	// Check if it's a structured ML service error
+	var mlErr *client.MLServiceError
+	if errors.As(err, &mlErr) {
+		log.Printf("[PronunciationWorker] ML service error: %s - %s", mlErr.Code, mlErr.Message)
+		w.markFailed(messageID, mlErr.Code, mlErr.Message)
+		return
+	}
+
+	// Network or other error
+	log.Printf("[PronunciationWorker] ML service call failed: %v", err)
+	w.markFailed(messageID, "ML_SERVICE_ERROR", err.Error())
+}
+
+// findThreadForMessage loads a message and the thread (and so the user) it
+// belongs to
+func (w *PronunciationWorker) findThreadForMessage(messageID uuid.UUID) (*models.Message, *models.Thread, error) {
+	message, err := w.messageRepo.FindByID(w.exec, messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	thread, err := w.threadRepo.FindByID(w.exec, message.ThreadID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return message, thread, nil
 }
 
 // markFailed updates the message with a failed status
@@ -170,10 +392,261 @@ func (w *PronunciationWorker) markFailed(messageID uuid.UUID, code, message stri
 	errMsg := code + ": " + message
 	if err := w.messageRepo.UpdatePronunciationError(w.exec, messageID, "failed", errMsg, now); err != nil {
 		log.Printf("[PronunciationWorker] Failed to update message with error status: %v", err)
+		return
+	}
+
+	if w.Events == nil {
+		return
 	}
+	_, thread, err := w.findThreadForMessage(messageID)
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to fetch thread for message %s: %v", messageID, err)
+		return
+	}
+	w.Events.Publish(context.Background(), events.AnalysisFailed{
+		UserID:    thread.UserID,
+		ThreadID:  thread.ID,
+		MessageID: messageID,
+		Code:      code,
+	})
 }
 
 // MarkPending marks a message as pending for pronunciation analysis
 func (w *PronunciationWorker) MarkPending(messageID uuid.UUID) error {
 	return w.messageRepo.UpdatePronunciationStatus(w.exec, messageID, "pending")
 }
+
+// analysisToMap converts an ML analysis to the JSON object stored on messages
+func analysisToMap(analysis *client.PronunciationAnalysis) (models.JSONMap, error) {
+	analysisJSON, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, err
+	}
+
+	var analysisMap models.JSONMap
+	if err := json.Unmarshal(analysisJSON, &analysisMap); err != nil {
+		return nil, err
+	}
+	return analysisMap, nil
+}
+
+// EnqueueChunks schedules analysis of a long-form message's recordings on the
+// job queue. Chunks always wait for the ML service, even with callbacks on,
+// since callbacks carry a single message.
+func (w *PronunciationWorker) EnqueueChunks(threadID, messageID uuid.UUID, chunks []models.MessageChunk, language string) {
+	tier := w.tierForThread(threadID)
+	quality := w.Runtime.Current().AnalysisQuality(tier)
+	if w.Queue == nil {
+		go w.AnalyzeChunks(context.Background(), messageID, chunks, language, quality)
+		return
+	}
+
+	err := w.Queue.Enqueue(jobs.Job{
+		Name: "pronunciation:" + messageID.String(),
+		Lane: LaneForTier(tier),
+		Run: func(ctx context.Context) error {
+			return w.AnalyzeChunks(ctx, messageID, chunks, language, quality)
+		},
+	})
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to enqueue chunk analysis for message %s: %v", messageID, err)
+		w.markFailed(messageID, "QUEUE_ERROR", err.Error())
+	}
+}
+
+// pendingRecoveryLimit caps how many pending analyses one recovery pass
+// re-enqueues, twice what the job queue's lanes hold by default
+const pendingRecoveryLimit = 2000
+
+// RecoverPending re-enqueues the analyses a previous run left pending. The
+// job queue lives in memory, so a restart or crash drops the jobs waiting in
+// it, and a shutdown leaves the ones it interrupts pending. It runs once at
+// startup over messages sent before it and returns how many it re-enqueued.
+// With async callbacks, an analysis the ML service still holds is submitted
+// again; the first result wins and the other is ignored.
+func (w *PronunciationWorker) RecoverPending(ctx context.Context, before time.Time) int {
+	pending, err := w.messageRepo.FindPendingPronunciationBefore(w.exec, before, pendingRecoveryLimit)
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to find pending analyses to recover: %v", err)
+		return 0
+	}
+
+	recovered := 0
+	for i := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		message := &pending[i]
+
+		thread, err := w.threadRepo.FindByID(w.exec, message.ThreadID)
+		if err != nil {
+			log.Printf("[PronunciationWorker] Failed to fetch thread to recover analysis of message %s: %v", message.ID, err)
+			continue
+		}
+		language := PhonemeLanguage(ThreadLanguage(thread))
+
+		switch {
+		case message.Kind == models.MessageKindLongForm && w.Chunks != nil:
+			chunks, err := w.Chunks.FindByMessageID(w.exec, message.ID)
+			if err != nil {
+				log.Printf("[PronunciationWorker] Failed to fetch chunks to recover analysis of message %s: %v", message.ID, err)
+				continue
+			}
+			w.EnqueueChunks(thread.ID, message.ID, chunks, language)
+		case message.AudioURL == nil:
+			w.markFailed(message.ID, "NO_AUDIO", "recording was deleted")
+			continue
+		default:
+			w.Enqueue(thread.ID, message.ID, *message.AudioURL, scoringText(message), language)
+		}
+		recovered++
+	}
+
+	if recovered > 0 {
+		log.Printf("[PronunciationWorker] Re-enqueued %d analyses left pending by a previous run", recovered)
+	}
+	if len(pending) == pendingRecoveryLimit {
+		log.Printf("[PronunciationWorker] More than %d analyses were pending; the rest stay pending until retried", pendingRecoveryLimit)
+	}
+	return recovered
+}
+
+// AnalyzeChunks scores each recording of a long-form message against its own
+// transcript, then stores the combined report on the message. Chunks that
+// fail are left out of the report; the message only fails if all of them do.
+// Low-confidence chunks stay out of the user's phoneme stats, but unlike a
+// single voice message nothing is refunded. A message that fails is marked
+// failed and the error returned; one whose ctx is cancelled is left pending
+// for RecoverPending.
+func (w *PronunciationWorker) AnalyzeChunks(ctx context.Context, messageID uuid.UUID, chunks []models.MessageChunk, language string, quality client.AnalysisQuality) error {
+	log.Printf("[PronunciationWorker] Starting analysis of %d chunks for message %s", len(chunks), messageID)
+
+	var analyses []*client.PronunciationAnalysis
+	var confidences []float64
+	var confident [][]client.PhonemeDetail
+	for _, chunk := range chunks {
+		analysis, err := w.analyzeChunk(ctx, chunk, language, quality)
+		if ctx.Err() != nil {
+			return fmt.Errorf("analysis of message %s interrupted: %w", messageID, ctx.Err())
+		}
+		now := time.Now()
+		if err != nil {
+			log.Printf("[PronunciationWorker] Chunk %d of message %s failed: %v", chunk.Position, messageID, err)
+			if err := w.Chunks.UpdatePronunciationError(w.exec, chunk.ID, "failed", err.Error(), now); err != nil {
+				log.Printf("[PronunciationWorker] Failed to update chunk with error status: %v", err)
+			}
+			continue
+		}
+
+		analysisMap, err := analysisToMap(analysis)
+		if err != nil {
+			log.Printf("[PronunciationWorker] Failed to convert chunk analysis: %v", err)
+			continue
+		}
+		confidence := ComputeConfidence(analysis)
+		lowConfidence := confidence < w.MinConfidence
+		if err := w.Chunks.UpdatePronunciationAnalysis(w.exec, chunk.ID, "complete", analysisMap, string(analysis.Quality), confidence, lowConfidence, now); err != nil {
+			log.Printf("[PronunciationWorker] Failed to update chunk: %v", err)
+		}
+
+		analyses = append(analyses, analysis)
+		confidences = append(confidences, confidence)
+		if !lowConfidence {
+			confident = append(confident, analysis.PhonemeDetails)
+		}
+	}
+
+	if len(analyses) == 0 {
+		w.markFailed(messageID, "CHUNKS_FAILED", "none of the recordings could be analyzed")
+		return fmt.Errorf("none of the %d recordings of message %s could be analyzed", len(chunks), messageID)
+	}
+
+	combined := CombineChunkAnalyses(analyses)
+	analysisMap, err := analysisToMap(combined)
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to convert combined analysis: %v", err)
+		w.markFailed(messageID, "JSON_ERROR", err.Error())
+		return fmt.Errorf("failed to convert combined analysis: %w", err)
+	}
+	analysisMap["chunk_count"] = len(chunks)
+	analysisMap["analyzed_chunk_count"] = len(analyses)
+
+	confidence := combinedConfidence(analyses, confidences)
+	lowConfidence := confidence < w.MinConfidence
+	if err := w.messageRepo.UpdatePronunciationAnalysis(w.exec, messageID, "complete", analysisMap, string(combined.Quality), confidence, lowConfidence, time.Now()); err != nil {
+		log.Printf("[PronunciationWorker] Failed to update message: %v", err)
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+
+	log.Printf("[PronunciationWorker] Analysis complete for long-form message %s: %d/%d chunks, %d/%d phonemes matched (confidence %.2f)",
+		messageID, len(analyses), len(chunks), combined.MatchCount, combined.PhonemeCount, confidence)
+
+	_, thread, err := w.findThreadForMessage(messageID)
+	if err != nil {
+		log.Printf("[PronunciationWorker] Failed to fetch thread for message %s: %v", messageID, err)
+		return nil
+	}
+
+	if w.Analytics != nil {
+		w.Analytics.Track(context.Background(), thread.UserID, analytics.EventAnalysisCompleted, map[string]any{
+			"messageId":     messageID.String(),
+			"phonemeCount":  combined.PhonemeCount,
+			"matchCount":    combined.MatchCount,
+			"confidence":    confidence,
+			"lowConfidence": lowConfidence,
+			"quality":       string(combined.Quality),
+			"chunks":        len(chunks),
+		})
+	}
+
+	w.Events.Publish(context.Background(), events.AnalysisCompleted{
+		UserID:        thread.UserID,
+		ThreadID:      thread.ID,
+		MessageID:     messageID,
+		PhonemeCount:  combined.PhonemeCount,
+		MatchCount:    combined.MatchCount,
+		Confidence:    confidence,
+		LowConfidence: lowConfidence,
+		Quality:       string(combined.Quality),
+		Chunks:        len(chunks),
+		Language:      ThreadLanguage(thread),
+		Phonemes:      confident,
+	})
+	return nil
+}
+
+// analyzeChunk runs one long-form recording through the ML service. Errors
+// read "CODE: message", like a failed message's.
+func (w *PronunciationWorker) analyzeChunk(ctx context.Context, chunk models.MessageChunk, language string, quality client.AnalysisQuality) (*client.PronunciationAnalysis, error) {
+	if chunk.AudioURL == nil {
+		return nil, errors.New("NO_AUDIO: recording was deleted")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	presignedURL, err := w.Storage.GetPresignedURL(ctx, *chunk.AudioURL, 1*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("PRESIGNED_URL_ERROR: %w", err)
+	}
+
+	result, err := w.MLClient.AnalyzePronunciation(ctx, presignedURL, chunk.Transcript, language, quality)
+	if err != nil {
+		var mlErr *client.MLServiceError
+		if errors.As(err, &mlErr) {
+			return nil, fmt.Errorf("%s: %s", mlErr.Code, mlErr.Message)
+		}
+		return nil, fmt.Errorf("ML_SERVICE_ERROR: %w", err)
+	}
+	if result.Status == "error" {
+		if result.Error != nil {
+			return nil, fmt.Errorf("%s: %s", result.Error.Code, result.Error.Message)
+		}
+		return nil, errors.New("UNKNOWN: Unknown error")
+	}
+	if result.Analysis == nil {
+		return nil, errors.New("NO_ANALYSIS: ML service returned success but no analysis data")
+	}
+	withQuality(result.Analysis, quality)
+	return result.Analysis, nil
+}
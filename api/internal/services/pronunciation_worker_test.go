@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -11,10 +12,21 @@ import (
 
 	"ling-app/api/internal/client"
 	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/jobs"
 	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
 	repomocks "ling-app/api/internal/repository/mocks"
+	"ling-app/api/internal/testutil/factory"
 )
 
+// statsBus returns a bus with stats subscribed, as app.go wires it
+func statsBus(stats *PhonemeStatsService) *events.Bus {
+	bus := events.NewBus()
+	stats.Subscribe(bus)
+	return bus
+}
+
 func TestPronunciationWorker_AnalyzeAsync_Success(t *testing.T) {
 	messageID := uuid.New()
 	userID := uuid.New()
@@ -38,7 +50,7 @@ func TestPronunciationWorker_AnalyzeAsync_Success(t *testing.T) {
 		Return("https://presigned.url/test.wav", nil)
 
 	// ML returns successful analysis
-	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", expectedText, language).
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", expectedText, language, client.QualityAccurate).
 		Return(&client.PronunciationResponse{
 			Status: "success",
 			Analysis: &client.PronunciationAnalysis{
@@ -54,7 +66,7 @@ func TestPronunciationWorker_AnalyzeAsync_Success(t *testing.T) {
 		}, nil)
 
 	// Message repo updates with analysis
-	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), mock.AnythingOfType("time.Time")).
+	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), "accurate", mock.AnythingOfType("float64"), false, mock.AnythingOfType("time.Time")).
 		Return(nil)
 
 	// For phoneme stats, we need to get message and thread
@@ -67,8 +79,8 @@ func TestPronunciationWorker_AnalyzeAsync_Success(t *testing.T) {
 	// Phoneme stats recording (for match phonemes)
 	phonemeStatsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
 
-	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, phonemeStatsService)
-	worker.AnalyzeAsync(messageID, audioKey, expectedText, language)
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, statsBus(phonemeStatsService))
+	worker.AnalyzeAsync(context.Background(), messageID, audioKey, expectedText, language, client.QualityAccurate)
 
 	storageClient.AssertExpectations(t)
 	mlClient.AssertExpectations(t)
@@ -91,7 +103,9 @@ func TestPronunciationWorker_AnalyzeAsync_PresignedURLError(t *testing.T) {
 		Return(nil)
 
 	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, nil)
-	worker.AnalyzeAsync(messageID, "audio/test.wav", "hello", "en")
+	err := worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "hello", "en", client.QualityAccurate)
+
+	assert.Error(t, err)
 
 	storageClient.AssertExpectations(t)
 	messageRepo.AssertExpectations(t)
@@ -110,14 +124,16 @@ func TestPronunciationWorker_AnalyzeAsync_MLServiceError(t *testing.T) {
 	storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", time.Hour).
 		Return("https://presigned.url/test.wav", nil)
 
-	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en").
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en", client.QualityAccurate).
 		Return(nil, errors.New("ML service unavailable"))
 
 	messageRepo.On("UpdatePronunciationError", mock.Anything, messageID, "failed", "ML_SERVICE_ERROR: ML service unavailable", mock.AnythingOfType("time.Time")).
 		Return(nil)
 
 	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, nil)
-	worker.AnalyzeAsync(messageID, "audio/test.wav", "hello", "en")
+	err := worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "hello", "en", client.QualityAccurate)
+
+	assert.Error(t, err)
 
 	storageClient.AssertExpectations(t)
 	mlClient.AssertExpectations(t)
@@ -135,7 +151,7 @@ func TestPronunciationWorker_AnalyzeAsync_MLReturnsError(t *testing.T) {
 	storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", time.Hour).
 		Return("https://presigned.url/test.wav", nil)
 
-	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en").
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en", client.QualityAccurate).
 		Return(&client.PronunciationResponse{
 			Status: "error",
 			Error: &client.PronunciationError{
@@ -148,13 +164,58 @@ func TestPronunciationWorker_AnalyzeAsync_MLReturnsError(t *testing.T) {
 		Return(nil)
 
 	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, nil)
-	worker.AnalyzeAsync(messageID, "audio/test.wav", "hello", "en")
+	worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "hello", "en", client.QualityAccurate)
 
 	storageClient.AssertExpectations(t)
 	mlClient.AssertExpectations(t)
 	messageRepo.AssertExpectations(t)
 }
 
+func TestPronunciationWorker_AnalyzeAsync_Shadow(t *testing.T) {
+	messageID := uuid.New()
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	storageClient := new(clientmocks.MockStorageClient)
+	mlClient := new(clientmocks.MockMLClient)
+	shadowClient := new(clientmocks.MockMLClient)
+	comparisons := new(repomocks.MockAnalysisComparisonRepository)
+
+	storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", time.Hour).
+		Return("https://presigned.url/test.wav", nil)
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en", client.QualityAccurate).
+		Return(&client.PronunciationResponse{
+			Status: "error",
+			Error:  &client.PronunciationError{Code: "AUDIO_TOO_SHORT", Message: "Audio is too short for analysis"},
+		}, nil)
+	messageRepo.On("UpdatePronunciationError", mock.Anything, messageID, "failed", mock.Anything, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	shadowClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en", client.QualityAccurate).
+		Return(&client.PronunciationResponse{
+			Status:   "success",
+			Analysis: &client.PronunciationAnalysis{PhonemeCount: 4, MatchCount: 4},
+		}, nil)
+	stored := make(chan *models.AnalysisComparison, 1)
+	comparisons.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored <- args.Get(1).(*models.AnalysisComparison) }).
+		Return(nil)
+
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, new(repomocks.MockThreadRepository), mlClient, storageClient, nil)
+	worker.Shadow = NewMLShadowForTest(nil, comparisons, shadowClient, time.Now, func() float64 { return 0 })
+	worker.Shadow.Runtime = shadowRuntime(t, "5")
+	worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "hello", "en", client.QualityAccurate)
+
+	select {
+	case comparison := <-stored:
+		assert.Equal(t, messageID, comparison.MessageID)
+		assert.Equal(t, "error", comparison.PrimaryStatus)
+		assert.Equal(t, "success", comparison.ShadowStatus)
+		assert.Equal(t, 1.0, comparison.ShadowAccuracy)
+	case <-time.After(time.Second):
+		t.Fatal("comparison not stored")
+	}
+	messageRepo.AssertExpectations(t)
+}
+
 func TestPronunciationWorker_AnalyzeAsync_NoAnalysisData(t *testing.T) {
 	messageID := uuid.New()
 
@@ -166,7 +227,7 @@ func TestPronunciationWorker_AnalyzeAsync_NoAnalysisData(t *testing.T) {
 	storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", time.Hour).
 		Return("https://presigned.url/test.wav", nil)
 
-	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en").
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en", client.QualityAccurate).
 		Return(&client.PronunciationResponse{
 			Status:   "success",
 			Analysis: nil, // No analysis data
@@ -176,7 +237,7 @@ func TestPronunciationWorker_AnalyzeAsync_NoAnalysisData(t *testing.T) {
 		Return(nil)
 
 	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, nil)
-	worker.AnalyzeAsync(messageID, "audio/test.wav", "hello", "en")
+	worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "hello", "en", client.QualityAccurate)
 
 	storageClient.AssertExpectations(t)
 	mlClient.AssertExpectations(t)
@@ -232,7 +293,7 @@ func TestPronunciationWorker_AnalyzeAsync_WithSubstitutions(t *testing.T) {
 		Return("https://presigned.url/test.wav", nil)
 
 	// ML returns analysis with substitutions
-	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "think", "en").
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "think", "en", client.QualityAccurate).
 		Return(&client.PronunciationResponse{
 			Status: "success",
 			Analysis: &client.PronunciationAnalysis{
@@ -248,7 +309,7 @@ func TestPronunciationWorker_AnalyzeAsync_WithSubstitutions(t *testing.T) {
 			},
 		}, nil)
 
-	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), mock.AnythingOfType("time.Time")).
+	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), "accurate", mock.AnythingOfType("float64"), false, mock.AnythingOfType("time.Time")).
 		Return(nil)
 
 	messageRepo.On("FindByID", mock.Anything, messageID).
@@ -263,11 +324,431 @@ func TestPronunciationWorker_AnalyzeAsync_WithSubstitutions(t *testing.T) {
 		return s.ExpectedPhoneme == "θ" && s.ActualPhoneme == "f"
 	})).Return(nil)
 
-	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, phonemeStatsService)
-	worker.AnalyzeAsync(messageID, "audio/test.wav", "think", "en")
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, statsBus(phonemeStatsService))
+	worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "think", "en", client.QualityAccurate)
 
 	storageClient.AssertExpectations(t)
 	mlClient.AssertExpectations(t)
 	messageRepo.AssertExpectations(t)
 	threadRepo.AssertExpectations(t)
 }
+
+func TestPronunciationWorker_AnalyzeAsync_LowConfidence(t *testing.T) {
+	messageID := uuid.New()
+	userID := uuid.New()
+	threadID := uuid.New()
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	storageClient := new(clientmocks.MockStorageClient)
+	mlClient := new(clientmocks.MockMLClient)
+	phonemeStatsRepo := new(repomocks.MockPhonemeStatsRepository)
+	phonemeSubsRepo := new(repomocks.MockPhonemeSubstitutionRepository)
+	credits := new(stubCredits)
+
+	phonemeStatsService := NewPhonemeStatsServiceForTest(nil, phonemeStatsRepo, phonemeSubsRepo)
+
+	storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", time.Hour).
+		Return("https://presigned.url/test.wav", nil)
+
+	// Noisy audio: low quality score, low SNR, and a warning
+	mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en", client.QualityAccurate).
+		Return(&client.PronunciationResponse{
+			Status: "success",
+			Analysis: &client.PronunciationAnalysis{
+				PhonemeCount: 4,
+				MatchCount:   2,
+				PhonemeDetails: []client.PhonemeDetail{
+					{Expected: "h", Actual: "h", Type: "match"},
+					{Expected: "ɛ", Actual: "a", Type: "substitute"},
+				},
+				AudioQuality: &client.AudioQuality{
+					QualityScore: 60,
+					SNRDB:        8,
+					Warnings:     []string{"Low signal-to-noise ratio"},
+				},
+			},
+		}, nil)
+
+	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), "accurate", mock.AnythingOfType("float64"), true, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	messageRepo.On("FindByID", mock.Anything, messageID).
+		Return(&models.Message{ID: messageID, ThreadID: threadID}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).
+		Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+
+	// What the turn was charged is refunded so the re-record is free
+	credits.On("RefundReservation", messageID.String(), LowConfidenceRefundDescription).Return(true, nil)
+
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, statsBus(phonemeStatsService))
+	worker.Credits = credits
+	worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "hello", "en", client.QualityAccurate)
+
+	messageRepo.AssertExpectations(t)
+	credits.AssertExpectations(t)
+	// Low-confidence results are kept out of aggregate stats
+	phonemeStatsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	phonemeSubsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestPronunciationWorker_HandleResult_CorrectedLowConfidenceNoRefund(t *testing.T) {
+	messageID := uuid.New()
+	threadID := uuid.New()
+	correctedAt := time.Now()
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	credits := new(stubCredits)
+
+	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), "", mock.AnythingOfType("float64"), true, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	messageRepo.On("FindByID", mock.Anything, messageID).
+		Return(&models.Message{ID: messageID, ThreadID: threadID, TranscriptCorrectedAt: &correctedAt}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).
+		Return(&models.Thread{ID: threadID, UserID: uuid.New()}, nil)
+
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, nil, nil, nil)
+	worker.Credits = credits
+	worker.HandleResult(context.Background(), messageID, &client.PronunciationResponse{
+		Status: "success",
+		Analysis: &client.PronunciationAnalysis{
+			PhonemeCount:   4,
+			MatchCount:     1,
+			PhonemeDetails: []client.PhonemeDetail{{Expected: "h", Actual: "h", Type: "match"}},
+			AudioQuality:   &client.AudioQuality{QualityScore: 40, SNRDB: 5},
+		},
+	})
+
+	// The re-analysis of a corrected transcript was never charged
+	credits.AssertNotCalled(t, "RefundReservation", mock.Anything, mock.Anything)
+}
+
+func TestPronunciationWorker_HandleResult_RefundsLowConfidenceOnce(t *testing.T) {
+	messageID := uuid.New()
+	threadID := uuid.New()
+	userID := uuid.New()
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	creditsRepo := new(repomocks.MockCreditsRepository)
+	txRepo := new(repomocks.MockCreditTransactionRepository)
+	holdRepo := new(repomocks.MockCreditReservationRepository)
+	txRunner := new(mockTxRunner)
+
+	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), "", mock.AnythingOfType("float64"), true, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	messageRepo.On("FindByID", mock.Anything, messageID).
+		Return(&models.Message{ID: messageID, ThreadID: threadID}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).
+		Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+
+	// The turn was charged a long-form rate, not the per-message cost; the
+	// second result finds the reservation refunded already
+	reservation := &models.CreditReservation{ID: uuid.New(), UserID: userID, Amount: 4, Reference: messageID.String(), Status: models.ReservationCaptured}
+	txRunner.On("Transaction", mock.Anything).Return(nil)
+	holdRepo.On("FindByReference", mock.Anything, messageID.String()).Return(reservation, nil)
+	holdRepo.On("MarkRefunded", mock.Anything, reservation.ID, mock.Anything).Return(true, nil).Once()
+	holdRepo.On("MarkRefunded", mock.Anything, reservation.ID, mock.Anything).Return(false, nil)
+	creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 6}, nil)
+	creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	txRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, nil, nil, nil)
+	worker.Credits = NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, holdRepo)
+	result := &client.PronunciationResponse{
+		Status: "success",
+		Analysis: &client.PronunciationAnalysis{
+			PhonemeCount:   4,
+			MatchCount:     1,
+			PhonemeDetails: []client.PhonemeDetail{{Expected: "h", Actual: "h", Type: "match"}},
+			AudioQuality:   &client.AudioQuality{QualityScore: 40, SNRDB: 5},
+		},
+	}
+	worker.HandleResult(context.Background(), messageID, result)
+	worker.HandleResult(context.Background(), messageID, result)
+
+	txRepo.AssertNumberOfCalls(t, "Create", 1)
+	txRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+		return tx.Type == models.TransactionRefund && tx.Amount == 4 && tx.BalanceAfter == 10 &&
+			tx.Reference != nil && *tx.Reference == messageID.String()
+	}))
+}
+
+func TestPronunciationWorker_HandleResult_RecordsStatsInThreadLanguage(t *testing.T) {
+	messageID := uuid.New()
+	threadID := uuid.New()
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	phonemeStatsRepo := new(repomocks.MockPhonemeStatsRepository)
+	phonemeSubsRepo := new(repomocks.MockPhonemeSubstitutionRepository)
+
+	messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.AnythingOfType("models.JSONMap"), "accurate", mock.AnythingOfType("float64"), false, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	messageRepo.On("FindByID", mock.Anything, messageID).
+		Return(&models.Message{ID: messageID, ThreadID: threadID}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).
+		Return(&models.Thread{ID: threadID, UserID: uuid.New(), Language: "de"}, nil)
+	phonemeStatsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStats) bool {
+		return s.Language == "de"
+	})).Return(nil).Times(3)
+	phonemeSubsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeSubstitution) bool {
+		return s.Language == "de" && s.ExpectedPhoneme == "t" && s.ActualPhoneme == "d"
+	})).Return(nil).Once()
+
+	stats := NewPhonemeStatsServiceForTest(nil, phonemeStatsRepo, phonemeSubsRepo)
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, nil, nil, statsBus(stats))
+	worker.HandleResult(context.Background(), messageID, factory.Analysis().Match("ɡ", "uː").Substitute("t", "d").Response())
+
+	// German phonemes are kept apart from the user's English ones
+	messageRepo.AssertExpectations(t)
+	phonemeStatsRepo.AssertExpectations(t)
+	phonemeSubsRepo.AssertExpectations(t)
+}
+
+func TestPronunciationWorker_Enqueue_LaneByTier(t *testing.T) {
+	tierPro := models.TierPro
+	tests := []struct {
+		name string
+		sub  *models.Subscription
+		err  error
+		want jobs.Lane
+	}{
+		{"pro goes to priority lane", &models.Subscription{Tier: models.TierPro}, nil, jobs.LanePriority},
+		{"basic goes to priority lane", &models.Subscription{Tier: models.TierBasic}, nil, jobs.LanePriority},
+		{"free goes to standard lane", &models.Subscription{Tier: models.TierFree}, nil, jobs.LaneStandard},
+		{"admin override to pro goes to priority lane", &models.Subscription{Tier: models.TierFree, TierOverride: &tierPro}, nil, jobs.LanePriority},
+		{"no subscription goes to standard lane", nil, repository.ErrNotFound, jobs.LaneStandard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadID := uuid.New()
+			userID := uuid.New()
+
+			threadRepo := new(repomocks.MockThreadRepository)
+			subRepo := new(repomocks.MockSubscriptionRepository)
+			threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+			subRepo.On("FindByUserID", mock.Anything, userID).Return(tt.sub, tt.err)
+
+			// Queue is never started, so the job stays put for inspection
+			queue := jobs.NewQueue(jobs.Config{})
+			worker := NewPronunciationWorkerForTest(nil, nil, threadRepo, nil, nil, nil)
+			worker.subRepo = subRepo
+			worker.Queue = queue
+
+			worker.Enqueue(threadID, uuid.New(), "user/key.webm", "hello", "en-us")
+
+			assert.Equal(t, 1, queue.Stats()[tt.want].Depth)
+		})
+	}
+}
+
+func TestPronunciationWorker_Enqueue_JobReportsFailedAnalysis(t *testing.T) {
+	messageID := uuid.New()
+	storageClient := new(clientmocks.MockStorageClient)
+	messageRepo := new(repomocks.MockMessageRepository)
+	storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", time.Hour).Return("", errors.New("storage error"))
+	messageRepo.On("UpdatePronunciationError", mock.Anything, messageID, "failed", "PRESIGNED_URL_ERROR: storage error", mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := jobs.NewQueue(jobs.Config{Workers: 1})
+	queue.Start(ctx)
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, nil, nil, storageClient, nil)
+	worker.Queue = queue
+
+	worker.Enqueue(uuid.New(), messageID, "audio/test.wav", "hello", "en-us")
+
+	assert.Eventually(t, func() bool { return queue.Stats()[jobs.LaneStandard].Failed == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestPronunciationWorker_AnalyzeAsync_InterruptedStaysPending(t *testing.T) {
+	messageID := uuid.New()
+	storageClient := new(clientmocks.MockStorageClient)
+	messageRepo := new(repomocks.MockMessageRepository)
+	storageClient.On("GetPresignedURL", mock.Anything, "audio/test.wav", time.Hour).Return("", context.Canceled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, nil, nil, storageClient, nil)
+	err := worker.AnalyzeAsync(ctx, messageID, "audio/test.wav", "hello", "en-us", client.QualityAccurate)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	// Left pending for the recovery pass at the next start
+	messageRepo.AssertNotCalled(t, "UpdatePronunciationError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPronunciationWorker_RecoverPending(t *testing.T) {
+	threadID := uuid.New()
+	startedAt := time.Now()
+	voiceKey := "user/voice.webm"
+	practiceLine := "the practice line"
+	voice := models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", Content: "hello", ExpectedText: &practiceLine, AudioURL: &voiceKey, PronunciationStatus: "pending"}
+	longForm := models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", Kind: models.MessageKindLongForm, PronunciationStatus: "pending"}
+	deleted := models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", PronunciationStatus: "pending"}
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	chunkRepo := new(repomocks.MockMessageChunkRepository)
+	messageRepo.On("FindPendingPronunciationBefore", mock.Anything, startedAt, pendingRecoveryLimit).Return([]models.Message{voice, longForm, deleted}, nil)
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+	chunkKey := "user/chunk-0.webm"
+	chunkRepo.On("FindByMessageID", mock.Anything, longForm.ID).Return([]models.MessageChunk{{ID: uuid.New(), MessageID: longForm.ID, AudioURL: &chunkKey}}, nil)
+	messageRepo.On("UpdatePronunciationError", mock.Anything, deleted.ID, "failed", "NO_AUDIO: recording was deleted", mock.Anything).Return(nil)
+
+	// Queue is never started, so the jobs stay put for inspection
+	queue := jobs.NewQueue(jobs.Config{})
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, nil, nil, nil)
+	worker.Chunks = chunkRepo
+	worker.Queue = queue
+
+	recovered := worker.RecoverPending(context.Background(), startedAt)
+
+	assert.Equal(t, 2, recovered)
+	assert.Equal(t, 2, queue.Stats()[jobs.LaneStandard].Depth)
+	chunkRepo.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestPronunciationWorker_Enqueue_QualityByTier(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     *models.Subscription
+		err     error
+		runtime *RuntimeSettingsService
+		want    client.AnalysisQuality
+	}{
+		{"free gets the fast model", &models.Subscription{Tier: models.TierFree}, nil, nil, client.QualityFast},
+		{"pro gets the full model", &models.Subscription{Tier: models.TierPro}, nil, nil, client.QualityAccurate},
+		{"no subscription counts as free", nil, repository.ErrNotFound, nil, client.QualityFast},
+		{"runtime override", &models.Subscription{Tier: models.TierFree}, nil,
+			runtimeSettingsWith(t, "tierAnalysisQuality", `{"free": "accurate"}`), client.QualityAccurate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threadID, userID, messageID := uuid.New(), uuid.New(), uuid.New()
+
+			messageRepo := new(repomocks.MockMessageRepository)
+			threadRepo := new(repomocks.MockThreadRepository)
+			subRepo := new(repomocks.MockSubscriptionRepository)
+			storageClient := new(clientmocks.MockStorageClient)
+			mlClient := new(clientmocks.MockMLClient)
+			done := make(chan struct{})
+
+			threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+			subRepo.On("FindByUserID", mock.Anything, userID).Return(tt.sub, tt.err)
+			storageClient.On("GetPresignedURL", mock.Anything, "user/key.webm", time.Hour).Return("https://presigned.url", nil)
+			mlClient.On("AnalyzePronunciation", mock.Anything, "https://presigned.url", "hello", "en-us", tt.want).
+				Return(nil, errors.New("stop here"))
+			messageRepo.On("UpdatePronunciationError", mock.Anything, messageID, "failed", mock.Anything, mock.Anything).
+				Run(func(mock.Arguments) { close(done) }).
+				Return(nil)
+
+			// Without a queue, the analysis runs in a goroutine
+			worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, mlClient, storageClient, nil)
+			worker.subRepo = subRepo
+			worker.Runtime = tt.runtime
+			worker.Enqueue(threadID, messageID, "user/key.webm", "hello", "en-us")
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("analysis never ran")
+			}
+			mlClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPronunciationWorker_AnalyzeAsync_SubmitsForCallback(t *testing.T) {
+	messageID := uuid.New()
+	audioKey := "audio/test.wav"
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	storageClient := new(clientmocks.MockStorageClient)
+	mlClient := new(clientmocks.MockMLClient)
+
+	storageClient.On("GetPresignedURL", mock.Anything, audioKey, time.Hour).
+		Return("https://presigned.url/test.wav", nil)
+
+	var token string
+	mlClient.On("SubmitPronunciation", mock.Anything, "https://presigned.url/test.wav", "hello", "en", client.QualityAccurate,
+		"http://api/api/internal/ml/callbacks", mock.Anything).
+		Run(func(args mock.Arguments) { token = args.String(6) }).
+		Return(nil)
+
+	signer := NewMLCallbackSigner(testCallbackSecret, time.Hour)
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, nil, mlClient, storageClient, nil)
+	worker.CallbackURL = "http://api/api/internal/ml/callbacks"
+	worker.CallbackSigner = signer
+	worker.AnalyzeAsync(context.Background(), messageID, audioKey, "hello", "en", client.QualityAccurate)
+
+	mlClient.AssertNotCalled(t, "AnalyzePronunciation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	messageRepo.AssertNotCalled(t, "UpdatePronunciationError", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	signedFor, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, messageID, signedFor)
+}
+
+func TestPronunciationWorker_AnalyzeAsync_SubmitError(t *testing.T) {
+	messageID := uuid.New()
+
+	messageRepo := new(repomocks.MockMessageRepository)
+	storageClient := new(clientmocks.MockStorageClient)
+	mlClient := new(clientmocks.MockMLClient)
+
+	storageClient.On("GetPresignedURL", mock.Anything, mock.Anything, mock.Anything).Return("https://presigned.url", nil)
+	mlClient.On("SubmitPronunciation", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&client.MLServiceError{Code: "MODELS_NOT_LOADED", Message: "starting"})
+	messageRepo.On("UpdatePronunciationError", mock.Anything, messageID, "failed", "MODELS_NOT_LOADED: starting", mock.Anything).Return(nil)
+
+	worker := NewPronunciationWorkerForTest(nil, messageRepo, nil, mlClient, storageClient, nil)
+	worker.CallbackURL = "http://api/api/internal/ml/callbacks"
+	worker.CallbackSigner = NewMLCallbackSigner(testCallbackSecret, time.Hour)
+	err := worker.AnalyzeAsync(context.Background(), messageID, "audio/test.wav", "hello", "en", client.QualityAccurate)
+
+	assert.Error(t, err)
+
+	messageRepo.AssertExpectations(t)
+}
+
+func TestPronunciationWorker_HandleCallback(t *testing.T) {
+	messageID := uuid.New()
+	threadID := uuid.New()
+	result := &client.PronunciationResponse{
+		Status:   "success",
+		Analysis: &client.PronunciationAnalysis{PhonemeCount: 1, MatchCount: 1},
+	}
+
+	t.Run("stores result for pending message", func(t *testing.T) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		threadRepo := new(repomocks.MockThreadRepository)
+
+		messageRepo.On("FindByID", mock.Anything, messageID).
+			Return(&models.Message{ID: messageID, ThreadID: threadID, PronunciationStatus: "pending"}, nil)
+		messageRepo.On("UpdatePronunciationAnalysis", mock.Anything, messageID, "complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+		threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: uuid.New()}, nil)
+
+		worker := NewPronunciationWorkerForTest(nil, messageRepo, threadRepo, nil, nil, nil)
+		err := worker.HandleCallback(context.Background(), messageID, result)
+
+		assert.NoError(t, err)
+		messageRepo.AssertExpectations(t)
+	})
+
+	t.Run("ignores repeated callback", func(t *testing.T) {
+		messageRepo := new(repomocks.MockMessageRepository)
+		messageRepo.On("FindByID", mock.Anything, messageID).
+			Return(&models.Message{ID: messageID, ThreadID: threadID, PronunciationStatus: "complete"}, nil)
+
+		worker := NewPronunciationWorkerForTest(nil, messageRepo, nil, nil, nil, nil)
+		err := worker.HandleCallback(context.Background(), messageID, result)
+
+		assert.NoError(t, err)
+		messageRepo.AssertNotCalled(t, "UpdatePronunciationAnalysis", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+)
+
+// ReferenceAudioLanguage is the language example clips are spoken in
+const ReferenceAudioLanguage = "en-us"
+
+var ErrUnknownPhoneme = errors.New("no examples for phoneme")
+
+// PhonemeExampleWords are the core example words for each drilled phoneme,
+// the ones the pregeneration command synthesizes ahead of time
+var PhonemeExampleWords = map[string][]string{
+	"θ":  {"think", "three", "bath", "month"},
+	"ð":  {"this", "mother", "breathe", "weather"},
+	"r":  {"red", "right", "sorry", "around"},
+	"ɹ":  {"red", "right", "sorry", "around"},
+	"l":  {"light", "hello", "feel", "yellow"},
+	"v":  {"very", "love", "seven", "voice"},
+	"w":  {"water", "window", "away", "swim"},
+	"z":  {"zoo", "easy", "busy", "rose"},
+	"ʃ":  {"she", "shop", "wash", "nation"},
+	"ʒ":  {"measure", "vision", "usual", "garage"},
+	"tʃ": {"chair", "teacher", "watch", "kitchen"},
+	"dʒ": {"job", "bridge", "orange", "giant"},
+	"ŋ":  {"sing", "long", "thinking", "finger"},
+	"h":  {"hat", "hello", "behind", "house"},
+	"æ":  {"cat", "apple", "black", "happy"},
+	"ɪ":  {"sit", "fish", "big", "ship"},
+	"i":  {"see", "happy", "key", "machine"},
+	"iː": {"see", "sheep", "eat", "green"},
+	"ʊ":  {"book", "good", "put", "could"},
+	"u":  {"blue", "food", "true", "moon"},
+	"uː": {"blue", "food", "true", "moon"},
+	"ə":  {"about", "banana", "sofa", "today"},
+	"ʌ":  {"cup", "love", "sun", "money"},
+}
+
+// PhonemeExample is an example word and the key of its clip, which is empty
+// if it couldn't be synthesized
+type PhonemeExample struct {
+	Text     string `json:"text"`
+	AudioKey string `json:"audioKey,omitempty"`
+}
+
+// PhonemeExamples is everything a drill shows for one phoneme
+type PhonemeExamples struct {
+	Phoneme  string           `json:"phoneme"`
+	Tip      string           `json:"tip"`
+	Examples []PhonemeExample `json:"examples"`
+}
+
+// ReferencePregenerateReport is the outcome of a pregeneration run
+type ReferencePregenerateReport struct {
+	Generated int      `json:"generated"`
+	Cached    int      `json:"cached"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// PhonemeExampleProvider defines the interface for phoneme drill examples
+type PhonemeExampleProvider interface {
+	PhonemeExamples(ctx context.Context, phoneme string, speed float64) (*PhonemeExamples, error)
+}
+
+// ReferenceClipper returns the audio of example text, synthesizing it only
+// the first time
+type ReferenceClipper interface {
+	ClipAudio(ctx context.Context, text string) ([]byte, error)
+}
+
+// ReferenceAudioService keeps a library of synthesized example clips. Each
+// text is synthesized once per language, voice and speed; after that its
+// stored clip is reused. Only fixed example text belongs here, never a
+// user's own words, since clips are shared by everyone.
+type ReferenceAudioService struct {
+	exec    repository.Executor
+	repo    repository.ReferenceAudioRepository
+	tts     client.TTSClient
+	storage client.StorageClient
+	voice   string
+}
+
+// NewReferenceAudioService creates a new reference audio service. voice names
+// the TTS backend and voice, so switching either synthesizes fresh clips.
+func NewReferenceAudioService(
+	database *db.DB,
+	repo repository.ReferenceAudioRepository,
+	tts client.TTSClient,
+	storage client.StorageClient,
+	voice string,
+) *ReferenceAudioService {
+	return &ReferenceAudioService{
+		exec:    database.DB,
+		repo:    repo,
+		tts:     tts,
+		storage: storage,
+		voice:   voice,
+	}
+}
+
+// NewReferenceAudioServiceForTest creates a ReferenceAudioService with injected dependencies for testing.
+func NewReferenceAudioServiceForTest(
+	exec repository.Executor,
+	repo repository.ReferenceAudioRepository,
+	tts client.TTSClient,
+	storage client.StorageClient,
+	voice string,
+) *ReferenceAudioService {
+	return &ReferenceAudioService{
+		exec:    exec,
+		repo:    repo,
+		tts:     tts,
+		storage: storage,
+		voice:   voice,
+	}
+}
+
+// Clip returns the stored clip of text at speed, synthesizing and storing it
+// if there is none yet. TTS backends that can't change speed get clips at
+// normal speed.
+func (s *ReferenceAudioService) Clip(ctx context.Context, text string, speed float64) (*models.ReferenceAudio, error) {
+	clip, _, err := s.clip(ctx, text, speed)
+	return clip, err
+}
+
+// clip is Clip, also reporting whether the clip was synthesized just now
+func (s *ReferenceAudioService) clip(ctx context.Context, text string, speed float64) (*models.ReferenceAudio, bool, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, false, errors.New("reference text is empty")
+	}
+	rates, canChangeRate := s.tts.(client.RateSynthesizer)
+	if speed == 0 || !canChangeRate {
+		speed = models.DefaultSpeechRate
+	}
+
+	clip, err := s.repo.Find(s.exec, text, ReferenceAudioLanguage, s.voice, speed)
+	if err == nil {
+		return clip, false, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, false, fmt.Errorf("find clip: %w", err)
+	}
+
+	var result *client.TTSResult
+	if speed == models.DefaultSpeechRate {
+		result, err = s.tts.Synthesize(ctx, text)
+	} else {
+		result, err = rates.SynthesizeAtRate(ctx, text, speed)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("synthesize %q: %w", text, err)
+	}
+
+	key := models.ReferenceAudioKey(text, ReferenceAudioLanguage, s.voice, speed)
+	if _, err := s.storage.UploadAudio(ctx, bytes.NewReader(result.AudioBytes), key, "audio/mpeg"); err != nil {
+		return nil, false, fmt.Errorf("upload clip: %w", err)
+	}
+	clip = &models.ReferenceAudio{
+		Text:      text,
+		Language:  ReferenceAudioLanguage,
+		Voice:     s.voice,
+		Speed:     speed,
+		AudioKey:  key,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.Create(s.exec, clip); err != nil {
+		return nil, false, fmt.Errorf("record clip: %w", err)
+	}
+	return clip, true, nil
+}
+
+// ClipAudio returns the bytes of text's clip at normal speed
+func (s *ReferenceAudioService) ClipAudio(ctx context.Context, text string) ([]byte, error) {
+	clip, err := s.Clip(ctx, text, models.DefaultSpeechRate)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.storage.GetObject(ctx, clip.AudioKey, "")
+	if err != nil {
+		return nil, fmt.Errorf("get clip: %w", err)
+	}
+	defer obj.Body.Close()
+	return io.ReadAll(obj.Body)
+}
+
+// PhonemeExamples returns the phoneme's tip and example words with their
+// clips. A word that can't be synthesized is returned without a clip.
+func (s *ReferenceAudioService) PhonemeExamples(ctx context.Context, phoneme string, speed float64) (*PhonemeExamples, error) {
+	words, ok := PhonemeExampleWords[phoneme]
+	if !ok {
+		return nil, ErrUnknownPhoneme
+	}
+
+	examples := &PhonemeExamples{Phoneme: phoneme, Tip: drillTip(phoneme), Examples: make([]PhonemeExample, 0, len(words))}
+	for _, word := range words {
+		example := PhonemeExample{Text: word}
+		if clip, err := s.Clip(ctx, word, speed); err != nil {
+			log.Printf("[ReferenceAudio] Failed to get clip for %q: %v", word, err)
+		} else {
+			example.AudioKey = clip.AudioKey
+		}
+		examples.Examples = append(examples.Examples, example)
+	}
+	return examples, nil
+}
+
+// Pregenerate synthesizes the example words of the given phonemes, or of
+// every phoneme if none are given, so drills never wait for TTS. Words
+// already in the library are skipped, so it is safe to run repeatedly.
+func (s *ReferenceAudioService) Pregenerate(ctx context.Context, phonemes []string, speed float64) (*ReferencePregenerateReport, error) {
+	if len(phonemes) == 0 {
+		for p := range PhonemeExampleWords {
+			phonemes = append(phonemes, p)
+		}
+		sort.Strings(phonemes)
+	}
+
+	// Phonemes share words, such as /r/ and /ɹ/; each is synthesized once
+	var words []string
+	seen := make(map[string]bool)
+	for _, p := range phonemes {
+		list, ok := PhonemeExampleWords[p]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPhoneme, p)
+		}
+		for _, word := range list {
+			if !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+
+	report := &ReferencePregenerateReport{}
+	for _, word := range words {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		_, created, err := s.clip(ctx, word, speed)
+		switch {
+		case err != nil:
+			report.Failed++
+			report.Errors = append(report.Errors, err.Error())
+		case created:
+			report.Generated++
+		default:
+			report.Cached++
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestReferenceAudioService_Clip(t *testing.T) {
+	t.Run("reuses a stored clip", func(t *testing.T) {
+		repo := new(repomocks.MockReferenceAudioRepository)
+		tts := new(clientmocks.MockTTSClient)
+		stored := &models.ReferenceAudio{Text: "think", AudioKey: "reference/think.mp3"}
+		repo.On("Find", mock.Anything, "think", ReferenceAudioLanguage, "openai:alloy", 0.75).Return(stored, nil)
+
+		svc := NewReferenceAudioServiceForTest(nil, repo, tts, nil, "openai:alloy")
+		clip, err := svc.Clip(context.Background(), " think ", 0.75)
+
+		require.NoError(t, err)
+		assert.Equal(t, stored, clip)
+		tts.AssertNotCalled(t, "SynthesizeAtRate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("synthesizes, stores and records a new clip", func(t *testing.T) {
+		repo := new(repomocks.MockReferenceAudioRepository)
+		tts := new(clientmocks.MockTTSClient)
+		storage := new(clientmocks.MockStorageClient)
+		key := models.ReferenceAudioKey("think", ReferenceAudioLanguage, "openai:alloy", 0.75)
+		repo.On("Find", mock.Anything, "think", ReferenceAudioLanguage, "openai:alloy", 0.75).Return(nil, repository.ErrNotFound)
+		tts.On("SynthesizeAtRate", mock.Anything, "think", 0.75).Return(&client.TTSResult{AudioBytes: []byte("mp3")}, nil)
+		storage.On("UploadAudio", mock.Anything, mock.Anything, key, "audio/mpeg").Return(key, nil)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.ReferenceAudio) bool {
+			return c.Text == "think" && c.Speed == 0.75 && c.AudioKey == key
+		})).Return(nil)
+
+		svc := NewReferenceAudioServiceForTest(nil, repo, tts, storage, "openai:alloy")
+		clip, err := svc.Clip(context.Background(), "think", 0.75)
+
+		require.NoError(t, err)
+		assert.Equal(t, key, clip.AudioKey)
+		repo.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("records nothing when synthesis fails", func(t *testing.T) {
+		repo := new(repomocks.MockReferenceAudioRepository)
+		tts := new(clientmocks.MockTTSClient)
+		repo.On("Find", mock.Anything, "think", ReferenceAudioLanguage, "openai:alloy", 1.0).Return(nil, repository.ErrNotFound)
+		tts.On("Synthesize", mock.Anything, "think").Return(nil, errors.New("tts down"))
+
+		svc := NewReferenceAudioServiceForTest(nil, repo, tts, nil, "openai:alloy")
+		_, err := svc.Clip(context.Background(), "think", 0)
+
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReferenceAudioService_ClipAudio(t *testing.T) {
+	repo := new(repomocks.MockReferenceAudioRepository)
+	storage := new(clientmocks.MockStorageClient)
+	repo.On("Find", mock.Anything, "three", ReferenceAudioLanguage, "chatterbox", 1.0).
+		Return(&models.ReferenceAudio{AudioKey: "reference/three.mp3"}, nil)
+	storage.On("GetObject", mock.Anything, "reference/three.mp3", "").
+		Return(&client.StorageObject{Body: io.NopCloser(strings.NewReader("mp3"))}, nil)
+
+	svc := NewReferenceAudioServiceForTest(nil, repo, new(clientmocks.MockTTSClient), storage, "chatterbox")
+	audio, err := svc.ClipAudio(context.Background(), "three")
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte("mp3"), audio)
+}
+
+func TestReferenceAudioService_PhonemeExamples(t *testing.T) {
+	t.Run("lists each word, with a clip where there is one", func(t *testing.T) {
+		repo := new(repomocks.MockReferenceAudioRepository)
+		tts := new(clientmocks.MockTTSClient)
+		words := PhonemeExampleWords["θ"]
+		repo.On("Find", mock.Anything, words[0], mock.Anything, mock.Anything, 1.0).Return(nil, repository.ErrNotFound)
+		tts.On("Synthesize", mock.Anything, words[0]).Return(nil, errors.New("tts down"))
+		for _, word := range words[1:] {
+			repo.On("Find", mock.Anything, word, mock.Anything, mock.Anything, 1.0).
+				Return(&models.ReferenceAudio{Text: word, AudioKey: "reference/" + word + ".mp3"}, nil)
+		}
+
+		svc := NewReferenceAudioServiceForTest(nil, repo, tts, nil, "openai:alloy")
+		examples, err := svc.PhonemeExamples(context.Background(), "θ", 1.0)
+
+		require.NoError(t, err)
+		assert.Equal(t, "θ", examples.Phoneme)
+		assert.NotEmpty(t, examples.Tip)
+		require.Len(t, examples.Examples, len(words))
+		assert.Empty(t, examples.Examples[0].AudioKey, "a word that can't be synthesized has no clip")
+		assert.Equal(t, "reference/"+words[1]+".mp3", examples.Examples[1].AudioKey)
+	})
+
+	t.Run("rejects phonemes without examples", func(t *testing.T) {
+		svc := NewReferenceAudioServiceForTest(nil, nil, nil, nil, "openai:alloy")
+
+		_, err := svc.PhonemeExamples(context.Background(), "q", 1.0)
+		assert.ErrorIs(t, err, ErrUnknownPhoneme)
+	})
+}
+
+func TestReferenceAudioService_Pregenerate(t *testing.T) {
+	repo := new(repomocks.MockReferenceAudioRepository)
+	tts := new(clientmocks.MockTTSClient)
+	storage := new(clientmocks.MockStorageClient)
+
+	// /r/ and /ɹ/ share their words, so each is looked up once
+	for i, word := range PhonemeExampleWords["r"] {
+		switch i {
+		case 0:
+			repo.On("Find", mock.Anything, word, mock.Anything, mock.Anything, 1.0).Return(&models.ReferenceAudio{Text: word}, nil).Once()
+		case 1:
+			repo.On("Find", mock.Anything, word, mock.Anything, mock.Anything, 1.0).Return(nil, repository.ErrNotFound).Once()
+			tts.On("Synthesize", mock.Anything, word).Return(nil, errors.New("tts down")).Once()
+		default:
+			repo.On("Find", mock.Anything, word, mock.Anything, mock.Anything, 1.0).Return(nil, repository.ErrNotFound).Once()
+			tts.On("Synthesize", mock.Anything, word).Return(&client.TTSResult{AudioBytes: []byte("mp3")}, nil).Once()
+		}
+	}
+	storage.On("UploadAudio", mock.Anything, mock.Anything, mock.Anything, "audio/mpeg").Return("", nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	svc := NewReferenceAudioServiceForTest(nil, repo, tts, storage, "openai:alloy")
+	report, err := svc.Pregenerate(context.Background(), []string{"r", "ɹ"}, 1.0)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Generated)
+	assert.Equal(t, 1, report.Cached)
+	assert.Equal(t, 1, report.Failed)
+	assert.Len(t, report.Errors, 1)
+	repo.AssertExpectations(t)
+	tts.AssertExpectations(t)
+
+	_, err = svc.Pregenerate(context.Background(), []string{"q"}, 1.0)
+	assert.ErrorIs(t, err, ErrUnknownPhoneme)
+}
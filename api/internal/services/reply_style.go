@@ -0,0 +1,64 @@
+package services
+
+import (
+	"math"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+)
+
+// ReplyStyle is how long the assistant's reply should be and how fast it is
+// read aloud
+type ReplyStyle struct {
+	Length     string
+	SpeechRate float64 // 1.0 is normal speed
+}
+
+// ResolveReplyStyle applies the thread's overrides to the user's settings.
+// Either may be nil, leaving the defaults.
+func ResolveReplyStyle(settings *models.UserSettings, thread *models.Thread) ReplyStyle {
+	style := ReplyStyle{Length: models.ReplyLengthMedium, SpeechRate: models.DefaultSpeechRate}
+	if settings != nil {
+		if ValidReplyLength(settings.ReplyLength) {
+			style.Length = settings.ReplyLength
+		}
+		if ValidSpeechRate(settings.SpeechRate) {
+			style.SpeechRate = settings.SpeechRate
+		}
+	}
+	if thread != nil {
+		if thread.ReplyLength != nil && ValidReplyLength(*thread.ReplyLength) {
+			style.Length = *thread.ReplyLength
+		}
+		if thread.SpeechRate != nil && ValidSpeechRate(*thread.SpeechRate) {
+			style.SpeechRate = *thread.SpeechRate
+		}
+	}
+	return style
+}
+
+// SystemPrompt returns the length instruction for the LLM, or nil for medium
+// replies, which are the model's default
+func (s ReplyStyle) SystemPrompt() *client.ConversationMessage {
+	var content string
+	switch s.Length {
+	case models.ReplyLengthShort:
+		content = "Keep every reply short: one or two sentences, at most one question."
+	case models.ReplyLengthLong:
+		content = "Give fuller replies of four to six sentences: react to what the learner said, " +
+			"add a detail or example of your own, then ask a follow-up question."
+	default:
+		return nil
+	}
+	return &client.ConversationMessage{Role: "system", Content: content}
+}
+
+// CombinedSpeechRate is the learner's speaking rate scaled by adaptation's,
+// e.g. 1.2 × 0.85 when a learner who likes fast speech is struggling
+func (s ReplyStyle) CombinedSpeechRate(adaptation *Adaptation) float64 {
+	rate := s.SpeechRate
+	if adaptation != nil {
+		rate *= adaptation.SpeechRate
+	}
+	return math.Round(rate*100) / 100
+}
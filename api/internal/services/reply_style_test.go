@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ling-app/api/internal/models"
+)
+
+func TestResolveReplyStyle(t *testing.T) {
+	short, long, invalid := models.ReplyLengthShort, models.ReplyLengthLong, "epic"
+	slow, tooFast := 0.75, 3.0
+
+	tests := []struct {
+		name     string
+		settings *models.UserSettings
+		thread   *models.Thread
+		want     ReplyStyle
+	}{
+		{"defaults", nil, nil, ReplyStyle{Length: models.ReplyLengthMedium, SpeechRate: 1.0}},
+		{"zero-value settings", &models.UserSettings{}, nil, ReplyStyle{Length: models.ReplyLengthMedium, SpeechRate: 1.0}},
+		{"user settings", &models.UserSettings{ReplyLength: short, SpeechRate: 1.2}, &models.Thread{}, ReplyStyle{Length: short, SpeechRate: 1.2}},
+		{"thread overrides", &models.UserSettings{ReplyLength: short, SpeechRate: 1.2}, &models.Thread{ReplyLength: &long, SpeechRate: &slow}, ReplyStyle{Length: long, SpeechRate: 0.75}},
+		{"invalid overrides are ignored", &models.UserSettings{ReplyLength: short, SpeechRate: 1.2}, &models.Thread{ReplyLength: &invalid, SpeechRate: &tooFast}, ReplyStyle{Length: short, SpeechRate: 1.2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ResolveReplyStyle(tt.settings, tt.thread))
+		})
+	}
+}
+
+func TestReplyStyle_SystemPrompt(t *testing.T) {
+	assert.Nil(t, ReplyStyle{Length: models.ReplyLengthMedium}.SystemPrompt())
+	assert.Contains(t, ReplyStyle{Length: models.ReplyLengthShort}.SystemPrompt().Content, "short")
+	assert.Contains(t, ReplyStyle{Length: models.ReplyLengthLong}.SystemPrompt().Content, "four to six sentences")
+}
+
+func TestReplyStyle_CombinedSpeechRate(t *testing.T) {
+	style := ReplyStyle{SpeechRate: 1.2}
+	assert.Equal(t, 1.2, style.CombinedSpeechRate(nil))
+	assert.Equal(t, 1.02, style.CombinedSpeechRate(&Adaptation{SpeechRate: SimplifiedSpeechRate}))
+}
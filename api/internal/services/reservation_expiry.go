@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// reservationExpiryBatchSize caps how many reservations are expired per
+// query; a sweep keeps going while batches come back full
+const reservationExpiryBatchSize = 500
+
+// ReservationExpiryWorker gives back credits whose reservation was never
+// settled: the instance running the turn crashed, or its capture or release
+// failed. Each expired reservation returns its credits to the balance and
+// shows in the credit history as a release. Every instance can run it: a
+// reservation is only expired while still held, so once however many sweeps
+// find it.
+type ReservationExpiryWorker struct {
+	exec        repository.Executor
+	txRunner    TxRunner
+	creditsRepo repository.CreditsRepository
+	txRepo      repository.CreditTransactionRepository
+	holdRepo    repository.CreditReservationRepository
+	interval    time.Duration
+
+	now func() time.Time
+}
+
+// NewReservationExpiryWorker creates a new reservation expiry worker
+func NewReservationExpiryWorker(
+	database *db.DB,
+	creditsRepo repository.CreditsRepository,
+	txRepo repository.CreditTransactionRepository,
+	holdRepo repository.CreditReservationRepository,
+	interval time.Duration,
+) *ReservationExpiryWorker {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &ReservationExpiryWorker{
+		exec:        database.DB,
+		txRunner:    database.DB,
+		creditsRepo: creditsRepo,
+		txRepo:      txRepo,
+		holdRepo:    holdRepo,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// NewReservationExpiryWorkerForTest creates a ReservationExpiryWorker with injected dependencies for testing.
+func NewReservationExpiryWorkerForTest(
+	exec repository.Executor,
+	txRunner TxRunner,
+	creditsRepo repository.CreditsRepository,
+	txRepo repository.CreditTransactionRepository,
+	holdRepo repository.CreditReservationRepository,
+	now func() time.Time,
+) *ReservationExpiryWorker {
+	return &ReservationExpiryWorker{
+		exec:        exec,
+		txRunner:    txRunner,
+		creditsRepo: creditsRepo,
+		txRepo:      txRepo,
+		holdRepo:    holdRepo,
+		interval:    5 * time.Minute,
+		now:         now,
+	}
+}
+
+// Start sweeps for expired reservations until ctx is cancelled. The first
+// sweep runs straight away, which settles the holds of turns a restart cut off.
+func (w *ReservationExpiryWorker) Start(ctx context.Context) {
+	log.Printf("[ReservationExpiry] Checking for expired credit reservations every %s", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep expires every reservation held past its expiry and returns how many
+// it expired
+func (w *ReservationExpiryWorker) Sweep(ctx context.Context) int {
+	now := w.now()
+	expired := 0
+	for ctx.Err() == nil {
+		due, err := w.holdRepo.FindExpired(w.exec, now, reservationExpiryBatchSize)
+		if err != nil {
+			log.Printf("[ReservationExpiry] Failed to find expired reservations: %v", err)
+			break
+		}
+
+		batch := 0
+		for i := range due {
+			ok, err := w.Expire(&due[i], now)
+			if err != nil {
+				log.Printf("[ReservationExpiry] Failed to expire reservation %s for user %s: %v", due[i].ID, due[i].UserID, err)
+				continue
+			}
+			if ok {
+				batch++
+			}
+		}
+		expired += batch
+
+		// A full batch that expired nothing would come back unchanged
+		if len(due) < reservationExpiryBatchSize || batch == 0 {
+			break
+		}
+	}
+
+	if expired > 0 {
+		log.Printf("[ReservationExpiry] Gave back the credits of %d expired reservations", expired)
+	}
+	return expired
+}
+
+// Expire gives a reservation's credits back to the balance and records the
+// release. It reports false, changing nothing, if the reservation was settled
+// meanwhile.
+func (w *ReservationExpiryWorker) Expire(reservation *models.CreditReservation, now time.Time) (bool, error) {
+	expired := false
+	err := w.txRunner.Transaction(func(tx *gorm.DB) error {
+		ok, err := w.holdRepo.Settle(tx, reservation.ID, models.ReservationExpired, now)
+		if err != nil {
+			return fmt.Errorf("failed to settle reservation: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+
+		credits, err := w.creditsRepo.FindByUserIDForUpdate(tx, reservation.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get credits: %w", err)
+		}
+		credits.Balance += reservation.Amount
+		if err := w.creditsRepo.Save(tx, credits); err != nil {
+			return fmt.Errorf("failed to update credits: %w", err)
+		}
+
+		transaction := &models.CreditTransaction{
+			UserID:       reservation.UserID,
+			Type:         models.TransactionRelease,
+			Amount:       reservation.Amount,
+			BalanceAfter: credits.Balance,
+			Reference:    &reservation.Reference,
+			Description:  "Released: " + reservation.Description,
+		}
+		if err := w.txRepo.Create(tx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+		expired = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return expired, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestReservationExpiryWorker_Sweep(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	hold := models.CreditReservation{
+		ID: uuid.New(), UserID: userID, Amount: 2, Status: models.ReservationHeld,
+		Reference: "msg-123", Description: "Voice message", ExpiresAt: now.Add(-time.Minute),
+	}
+
+	t.Run("gives the credits back and records the release", func(t *testing.T) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		holdRepo := new(repomocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+
+		holdRepo.On("FindExpired", mock.Anything, now, reservationExpiryBatchSize).Return([]models.CreditReservation{hold}, nil)
+		holdRepo.On("Settle", mock.Anything, hold.ID, models.ReservationExpired, now).Return(true, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 3}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool { return c.Balance == 5 })).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Type == models.TransactionRelease && tx.Amount == 2 && tx.BalanceAfter == 5 &&
+				tx.Reference != nil && *tx.Reference == "msg-123"
+		})).Return(nil)
+
+		worker := NewReservationExpiryWorkerForTest(nil, txRunner, creditsRepo, txRepo, holdRepo, func() time.Time { return now })
+
+		assert.Equal(t, 1, worker.Sweep(context.Background()))
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("leaves reservations settled meanwhile alone", func(t *testing.T) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		holdRepo := new(repomocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+
+		holdRepo.On("FindExpired", mock.Anything, now, reservationExpiryBatchSize).Return([]models.CreditReservation{hold}, nil)
+		holdRepo.On("Settle", mock.Anything, hold.ID, models.ReservationExpired, now).Return(false, nil)
+
+		worker := NewReservationExpiryWorkerForTest(nil, txRunner, creditsRepo, txRepo, holdRepo, func() time.Time { return now })
+
+		assert.Equal(t, 0, worker.Sweep(context.Background()))
+		creditsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("carries on past a failed reservation", func(t *testing.T) {
+		other := hold
+		other.ID = uuid.New()
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		holdRepo := new(repomocks.MockCreditReservationRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+
+		holdRepo.On("FindExpired", mock.Anything, now, reservationExpiryBatchSize).Return([]models.CreditReservation{hold, other}, nil)
+		holdRepo.On("Settle", mock.Anything, hold.ID, models.ReservationExpired, now).Return(false, errors.New("db down"))
+		holdRepo.On("Settle", mock.Anything, other.ID, models.ReservationExpired, now).Return(true, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		worker := NewReservationExpiryWorkerForTest(nil, txRunner, creditsRepo, txRepo, holdRepo, func() time.Time { return now })
+
+		assert.Equal(t, 1, worker.Sweep(context.Background()))
+	})
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRestoreSampleUsers is how many users a restore check rebuilds
+const DefaultRestoreSampleUsers = 10
+
+// DefaultRestoreMaxRowDrift is the share of a table's rows a restore may
+// differ from the live database by. A point-in-time restore trails the live
+// database by a few minutes, so counts rarely match exactly.
+const DefaultRestoreMaxRowDrift = 0.01
+
+// restoreRowSlack is the drift always allowed, so small tables aren't
+// flagged for a handful of new rows
+const restoreRowSlack = 10
+
+// RestoreDatabase is what a restore check reads from a database beyond the
+// repositories; *db.DB implements it
+type RestoreDatabase interface {
+	TableRowCounts(ctx context.Context) (map[string]int64, error)
+	ReferenceViolations(ctx context.Context) ([]db.ReferenceViolation, error)
+	RandomUserIDs(ctx context.Context, n int) ([]uuid.UUID, error)
+}
+
+// RestoreCheckOptions tunes a restore check
+type RestoreCheckOptions struct {
+	// SampleUsers is how many random users to rebuild
+	SampleUsers int `json:"sampleUsers"`
+	// MaxRowDrift is the share of a table's live rows its restored count may
+	// differ by
+	MaxRowDrift float64 `json:"maxRowDrift"`
+}
+
+// RestoreTableCount is one table's row count in the restore, and in the
+// live database when the check compares against it
+type RestoreTableCount struct {
+	Table    string `json:"table"`
+	Restored *int64 `json:"restored"`
+	Live     *int64 `json:"live,omitempty"`
+	OK       bool   `json:"ok"`
+}
+
+// RestoreUserCheck is what rebuilding one user from the restore found
+type RestoreUserCheck struct {
+	UserID       uuid.UUID `json:"userId"`
+	Threads      int       `json:"threads"`
+	Messages     int       `json:"messages"`
+	AudioObjects int       `json:"audioObjects"`
+	MissingAudio []string  `json:"missingAudio"`
+	Problems     []string  `json:"problems"`
+}
+
+// RestoreCheckReport is the result of checking a restored database. The
+// restore is usable when Failures is empty.
+type RestoreCheckReport struct {
+	CheckedAt           time.Time               `json:"checkedAt"`
+	Tables              []RestoreTableCount     `json:"tables"`
+	ReferenceViolations []db.ReferenceViolation `json:"referenceViolations"`
+	Users               []RestoreUserCheck      `json:"users"`
+	Failures            []string                `json:"failures"`
+}
+
+// Passed reports whether every check succeeded
+func (r *RestoreCheckReport) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+func (r *RestoreCheckReport) fail(format string, args ...any) {
+	r.Failures = append(r.Failures, fmt.Sprintf(format, args...))
+}
+
+// RestoreCheckService verifies that a database restored from backup is
+// complete and consistent: row counts against the live database, foreign key
+// and id references, and a sample of users rebuilt through the repositories
+// with their recordings looked up in storage.
+type RestoreCheckService struct {
+	restored    RestoreDatabase
+	live        RestoreDatabase
+	exec        repository.Executor
+	userRepo    repository.UserRepository
+	creditsRepo repository.CreditsRepository
+	subRepo     repository.SubscriptionRepository
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+	chunkRepo   repository.MessageChunkRepository
+	storage     client.StorageClient
+	now         func() time.Time
+}
+
+// NewRestoreCheckService creates a check of the restored database. live may
+// be nil to skip the row count comparison, and storage nil to skip the audio
+// lookups.
+func NewRestoreCheckService(restored, live *db.DB, storage client.StorageClient) *RestoreCheckService {
+	s := &RestoreCheckService{
+		restored:    restored,
+		exec:        restored.DB,
+		userRepo:    repository.NewUserRepository(),
+		creditsRepo: repository.NewCreditsRepository(),
+		subRepo:     repository.NewSubscriptionRepository(),
+		threadRepo:  repository.NewThreadRepository(),
+		messageRepo: repository.NewMessageRepository(),
+		chunkRepo:   repository.NewMessageChunkRepository(),
+		storage:     storage,
+		now:         time.Now,
+	}
+	if live != nil {
+		s.live = live
+	}
+	return s
+}
+
+// NewRestoreCheckServiceForTest creates a RestoreCheckService with custom
+// dependencies (for testing)
+func NewRestoreCheckServiceForTest(
+	restored, live RestoreDatabase,
+	exec repository.Executor,
+	userRepo repository.UserRepository,
+	creditsRepo repository.CreditsRepository,
+	subRepo repository.SubscriptionRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	chunkRepo repository.MessageChunkRepository,
+	storage client.StorageClient,
+) *RestoreCheckService {
+	return &RestoreCheckService{
+		restored:    restored,
+		live:        live,
+		exec:        exec,
+		userRepo:    userRepo,
+		creditsRepo: creditsRepo,
+		subRepo:     subRepo,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		chunkRepo:   chunkRepo,
+		storage:     storage,
+		now:         time.Now,
+	}
+}
+
+// Check runs every check and reports what failed. Only a cancelled context
+// stops it early; a check that can't run is itself a failure.
+func (s *RestoreCheckService) Check(ctx context.Context, opts RestoreCheckOptions) (*RestoreCheckReport, error) {
+	if opts.SampleUsers <= 0 {
+		opts.SampleUsers = DefaultRestoreSampleUsers
+	}
+	if opts.MaxRowDrift <= 0 {
+		opts.MaxRowDrift = DefaultRestoreMaxRowDrift
+	}
+
+	report := &RestoreCheckReport{
+		CheckedAt:           s.now(),
+		Tables:              []RestoreTableCount{},
+		ReferenceViolations: []db.ReferenceViolation{},
+		Users:               []RestoreUserCheck{},
+		Failures:            []string{},
+	}
+
+	s.checkRowCounts(ctx, opts.MaxRowDrift, report)
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	violations, err := s.restored.ReferenceViolations(ctx)
+	if err != nil {
+		report.fail("references: %v", err)
+	} else {
+		report.ReferenceViolations = violations
+		for _, v := range violations {
+			report.fail("%s: %d rows of %s reference missing %s", v.Name, v.Orphans, v.Table, v.RefTable)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	userIDs, err := s.restored.RandomUserIDs(ctx, opts.SampleUsers)
+	if err != nil {
+		report.fail("users: %v", err)
+		return report, nil
+	}
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		check := s.checkUser(ctx, userID)
+		for _, problem := range check.Problems {
+			report.fail("user %s: %s", userID, problem)
+		}
+		if len(check.MissingAudio) > 0 {
+			report.fail("user %s: %d of %d recordings missing from storage", userID, len(check.MissingAudio), check.AudioObjects)
+		}
+		report.Users = append(report.Users, check)
+	}
+
+	log.Printf("[RestoreCheck] %d tables, %d reference violations, %d users rebuilt, %d failures",
+		len(report.Tables), len(report.ReferenceViolations), len(report.Users), len(report.Failures))
+	return report, nil
+}
+
+// checkRowCounts fails a table the restore is missing, or whose count is
+// further from the live one than maxDrift allows. Without a live database
+// only an empty users table fails.
+func (s *RestoreCheckService) checkRowCounts(ctx context.Context, maxDrift float64, report *RestoreCheckReport) {
+	restored, err := s.restored.TableRowCounts(ctx)
+	if err != nil {
+		report.fail("row counts: %v", err)
+		return
+	}
+
+	var live map[string]int64
+	if s.live != nil {
+		if live, err = s.live.TableRowCounts(ctx); err != nil {
+			report.fail("live row counts: %v", err)
+			live = nil
+		}
+	}
+
+	tables := make([]string, 0, len(restored))
+	for table := range restored {
+		tables = append(tables, table)
+	}
+	for table := range live {
+		if _, ok := restored[table]; !ok {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		count := RestoreTableCount{Table: table, OK: true}
+		restoredCount, inRestore := restored[table]
+		if inRestore {
+			count.Restored = &restoredCount
+		}
+		if liveCount, ok := live[table]; ok {
+			count.Live = &liveCount
+			allowed := int64(float64(liveCount)*maxDrift) + restoreRowSlack
+			switch {
+			case !inRestore:
+				count.OK = false
+				report.fail("%s: missing from the restore", table)
+			case abs64(liveCount-restoredCount) > allowed:
+				count.OK = false
+				report.fail("%s: %d rows restored, %d live", table, restoredCount, liveCount)
+			}
+		}
+		report.Tables = append(report.Tables, count)
+	}
+
+	if n, ok := restored["users"]; !ok || n == 0 {
+		report.fail("users: no rows restored")
+	}
+}
+
+// checkUser rebuilds a user the way the app loads them: the account, its
+// credits and subscription, every thread with its messages, and the
+// recordings those messages point at
+func (s *RestoreCheckService) checkUser(ctx context.Context, userID uuid.UUID) RestoreUserCheck {
+	check := RestoreUserCheck{UserID: userID, MissingAudio: []string{}, Problems: []string{}}
+	problem := func(format string, args ...any) {
+		check.Problems = append(check.Problems, fmt.Sprintf(format, args...))
+	}
+
+	if _, err := s.userRepo.FindByID(s.exec, userID); err != nil {
+		problem("load user: %v", err)
+		return check
+	}
+	if _, err := s.creditsRepo.FindByUserID(s.exec, userID); err != nil {
+		problem("load credits: %v", err)
+	}
+	if _, err := s.subRepo.FindByUserID(s.exec, userID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		problem("load subscription: %v", err)
+	}
+
+	active, err := s.threadRepo.FindByUserID(s.exec, userID)
+	if err != nil {
+		problem("load threads: %v", err)
+		return check
+	}
+	archived, err := s.threadRepo.FindArchivedByUserID(s.exec, userID)
+	if err != nil {
+		problem("load archived threads: %v", err)
+		return check
+	}
+
+	for _, thread := range append(active, archived...) {
+		check.Threads++
+		messages, err := s.messageRepo.FindByThreadID(s.exec, thread.ID)
+		if err != nil {
+			problem("load messages of thread %s: %v", thread.ID, err)
+			continue
+		}
+		for _, message := range messages {
+			check.Messages++
+			s.checkAudio(ctx, message.AudioURL, &check)
+			if message.Kind != models.MessageKindLongForm {
+				continue
+			}
+			chunks, err := s.chunkRepo.FindByMessageID(s.exec, message.ID)
+			if err != nil {
+				problem("load chunks of message %s: %v", message.ID, err)
+				continue
+			}
+			for _, chunk := range chunks {
+				s.checkAudio(ctx, chunk.AudioURL, &check)
+			}
+		}
+	}
+	return check
+}
+
+// checkAudio looks up a recording's key in storage
+func (s *RestoreCheckService) checkAudio(ctx context.Context, key *string, check *RestoreUserCheck) {
+	if s.storage == nil || key == nil || *key == "" {
+		return
+	}
+	check.AudioObjects++
+	_, err := s.storage.StatObject(ctx, *key)
+	switch {
+	case errors.Is(err, client.ErrObjectNotFound):
+		check.MissingAudio = append(check.MissingAudio, *key)
+	case err != nil:
+		check.Problems = append(check.Problems, fmt.Sprintf("stat %s: %v", *key, err))
+	}
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
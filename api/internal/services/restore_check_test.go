@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+// fakeRestoreDatabase serves fixed counts, violations and users
+type fakeRestoreDatabase struct {
+	counts     map[string]int64
+	violations []db.ReferenceViolation
+	users      []uuid.UUID
+	err        error
+}
+
+func (f *fakeRestoreDatabase) TableRowCounts(ctx context.Context) (map[string]int64, error) {
+	return f.counts, f.err
+}
+
+func (f *fakeRestoreDatabase) ReferenceViolations(ctx context.Context) ([]db.ReferenceViolation, error) {
+	return f.violations, f.err
+}
+
+func (f *fakeRestoreDatabase) RandomUserIDs(ctx context.Context, n int) ([]uuid.UUID, error) {
+	return f.users, f.err
+}
+
+type restoreCheckMocks struct {
+	users    *repomocks.MockUserRepository
+	credits  *repomocks.MockCreditsRepository
+	subs     *repomocks.MockSubscriptionRepository
+	threads  *repomocks.MockThreadRepository
+	messages *repomocks.MockMessageRepository
+	chunks   *repomocks.MockMessageChunkRepository
+	storage  *clientmocks.MockStorageClient
+}
+
+func newRestoreCheckForTest(restored, live RestoreDatabase) (*RestoreCheckService, *restoreCheckMocks) {
+	m := &restoreCheckMocks{
+		users:    new(repomocks.MockUserRepository),
+		credits:  new(repomocks.MockCreditsRepository),
+		subs:     new(repomocks.MockSubscriptionRepository),
+		threads:  new(repomocks.MockThreadRepository),
+		messages: new(repomocks.MockMessageRepository),
+		chunks:   new(repomocks.MockMessageChunkRepository),
+		storage:  new(clientmocks.MockStorageClient),
+	}
+	svc := NewRestoreCheckServiceForTest(restored, live, nil, m.users, m.credits, m.subs, m.threads, m.messages, m.chunks, m.storage)
+	return svc, m
+}
+
+// expectUser makes userID rebuild cleanly with one thread holding a voice
+// message and a long-form message of one chunk
+func (m *restoreCheckMocks) expectUser(userID uuid.UUID, audioKey, chunkKey string) {
+	threadID, longFormID := uuid.New(), uuid.New()
+	m.users.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+	m.credits.On("FindByUserID", mock.Anything, userID).Return(&models.Credits{UserID: userID}, nil)
+	m.subs.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+	m.threads.On("FindByUserID", mock.Anything, userID).Return([]models.Thread{{ID: threadID, UserID: userID}}, nil)
+	m.threads.On("FindArchivedByUserID", mock.Anything, userID).Return([]models.Thread{}, nil)
+	m.messages.On("FindByThreadID", mock.Anything, threadID).Return([]models.Message{
+		{ID: uuid.New(), ThreadID: threadID, Role: "user", AudioURL: &audioKey, HasAudio: true},
+		{ID: uuid.New(), ThreadID: threadID, Role: "assistant"},
+		{ID: longFormID, ThreadID: threadID, Role: "user", Kind: models.MessageKindLongForm},
+	}, nil)
+	m.chunks.On("FindByMessageID", mock.Anything, longFormID).Return([]models.MessageChunk{
+		{ID: uuid.New(), MessageID: longFormID, AudioURL: &chunkKey},
+	}, nil)
+}
+
+func TestRestoreCheckService_Check(t *testing.T) {
+	t.Run("a complete restore passes", func(t *testing.T) {
+		userID := uuid.New()
+		restored := &fakeRestoreDatabase{
+			counts: map[string]int64{"users": 1000, "messages": 50000},
+			users:  []uuid.UUID{userID},
+		}
+		live := &fakeRestoreDatabase{counts: map[string]int64{"users": 1004, "messages": 50300}}
+		svc, m := newRestoreCheckForTest(restored, live)
+		m.expectUser(userID, "audio/a.webm", "audio/a-chunk.webm")
+		m.storage.On("StatObject", mock.Anything, "audio/a.webm").Return(&client.ObjectInfo{Size: 10}, nil)
+		m.storage.On("StatObject", mock.Anything, "audio/a-chunk.webm").Return(&client.ObjectInfo{Size: 10}, nil)
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, report.Passed(), report.Failures)
+		require.Len(t, report.Tables, 2)
+		assert.Equal(t, "messages", report.Tables[0].Table)
+		assert.True(t, report.Tables[0].OK)
+		require.Len(t, report.Users, 1)
+		assert.Equal(t, RestoreUserCheck{
+			UserID: userID, Threads: 1, Messages: 3, AudioObjects: 2, MissingAudio: []string{}, Problems: []string{},
+		}, report.Users[0])
+		m.storage.AssertExpectations(t)
+	})
+
+	t.Run("tables missing or short of rows fail", func(t *testing.T) {
+		restored := &fakeRestoreDatabase{counts: map[string]int64{"users": 1000, "messages": 40000}}
+		live := &fakeRestoreDatabase{counts: map[string]int64{"users": 1000, "messages": 50000, "threads": 900}}
+		svc, _ := newRestoreCheckForTest(restored, live)
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		assert.False(t, report.Passed())
+		assert.ElementsMatch(t, []string{
+			"messages: 40000 rows restored, 50000 live",
+			"threads: missing from the restore",
+		}, report.Failures)
+	})
+
+	t.Run("small tables get some slack", func(t *testing.T) {
+		restored := &fakeRestoreDatabase{counts: map[string]int64{"users": 3}}
+		live := &fakeRestoreDatabase{counts: map[string]int64{"users": 9}}
+		svc, _ := newRestoreCheckForTest(restored, live)
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		assert.True(t, report.Passed(), report.Failures)
+	})
+
+	t.Run("without a live database an empty restore still fails", func(t *testing.T) {
+		svc, _ := newRestoreCheckForTest(&fakeRestoreDatabase{counts: map[string]int64{"users": 0}}, nil)
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"users: no rows restored"}, report.Failures)
+		assert.Nil(t, report.Tables[0].Live)
+	})
+
+	t.Run("orphaned references fail", func(t *testing.T) {
+		restored := &fakeRestoreDatabase{
+			counts: map[string]int64{"users": 10},
+			violations: []db.ReferenceViolation{{
+				Reference: db.Reference{Name: "threads.user_id", Table: "threads", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+				Orphans:   3,
+			}},
+		}
+		svc, _ := newRestoreCheckForTest(restored, nil)
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"threads.user_id: 3 rows of threads reference missing users"}, report.Failures)
+	})
+
+	t.Run("recordings missing from storage fail", func(t *testing.T) {
+		userID := uuid.New()
+		restored := &fakeRestoreDatabase{counts: map[string]int64{"users": 10}, users: []uuid.UUID{userID}}
+		svc, m := newRestoreCheckForTest(restored, nil)
+		m.expectUser(userID, "audio/gone.webm", "audio/chunk.webm")
+		m.storage.On("StatObject", mock.Anything, "audio/gone.webm").Return(nil, client.ErrObjectNotFound)
+		m.storage.On("StatObject", mock.Anything, "audio/chunk.webm").Return(nil, errors.New("access denied"))
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"audio/gone.webm"}, report.Users[0].MissingAudio)
+		assert.Equal(t, []string{
+			"user " + userID.String() + ": stat audio/chunk.webm: access denied",
+			"user " + userID.String() + ": 1 of 2 recordings missing from storage",
+		}, report.Failures)
+	})
+
+	t.Run("a user that can't be loaded fails", func(t *testing.T) {
+		userID := uuid.New()
+		restored := &fakeRestoreDatabase{counts: map[string]int64{"users": 10}, users: []uuid.UUID{userID}}
+		svc, m := newRestoreCheckForTest(restored, nil)
+		m.users.On("FindByID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		require.Len(t, report.Failures, 1)
+		assert.Contains(t, report.Failures[0], "load user")
+		m.threads.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failing query is reported, not returned", func(t *testing.T) {
+		svc, _ := newRestoreCheckForTest(&fakeRestoreDatabase{err: errors.New("connection refused")}, nil)
+
+		report, err := svc.Check(context.Background(), RestoreCheckOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"row counts: connection refused",
+			"references: connection refused",
+			"users: connection refused",
+		}, report.Failures)
+	})
+}
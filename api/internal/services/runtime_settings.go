@@ -0,0 +1,419 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"sync/atomic"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownRuntimeSetting = errors.New("unknown runtime setting")
+	ErrInvalidRuntimeSetting = errors.New("invalid runtime setting")
+)
+
+// Audit log actions for runtime setting changes
+const (
+	AuditActionRuntimeSettingUpdated = "runtime_setting.updated"
+	AuditActionRuntimeSettingReset   = "runtime_setting.reset"
+)
+
+// DefaultRuntimeSettingsRefresh is how often settings are reloaded when no
+// interval is configured
+const DefaultRuntimeSettingsRefresh = 30 * time.Second
+
+// RuntimeSettings are the non-secret tunables that can change without a
+// restart. Each JSON field name is a key in the runtime_settings table; keys
+// without a row keep their default. Maps are shared between readers and must
+// not be modified.
+type RuntimeSettings struct {
+	MaxAudioFileSize            int64                                              `json:"maxAudioFileSize"` // bytes
+	MinAudioDurationSeconds     float64                                            `json:"minAudioDurationSeconds"`
+	MaxAudioDurationSeconds     float64                                            `json:"maxAudioDurationSeconds"`
+	CreditCostPerMessage        int                                                `json:"creditCostPerMessage"`
+	CreditCostPerTextMessage    int                                                `json:"creditCostPerTextMessage"`
+	LongFormCreditCostPerMinute int                                                `json:"longFormCreditCostPerMinute"` // per started minute
+	TierCredits                 map[models.SubscriptionTier]int                    `json:"tierCredits"`                 // monthly allowance
+	TierLimits                  map[models.SubscriptionTier]models.TierLimit       `json:"tierLimits"`
+	TierAnalysisQuality         map[models.SubscriptionTier]client.AnalysisQuality `json:"tierAnalysisQuality"`
+	PhonemeWeights              map[string]PhonemeWeights                          `json:"phonemeWeights"`  // by language
+	MLShadowPercent             float64                                            `json:"mlShadowPercent"` // share of analyses also sent to the shadow model
+}
+
+// DefaultRuntimeSettings returns the built-in values used until overridden
+func DefaultRuntimeSettings() RuntimeSettings {
+	return RuntimeSettings{
+		MaxAudioFileSize:            10 << 20, // 10MB
+		MinAudioDurationSeconds:     1,
+		MaxAudioDurationSeconds:     30,
+		CreditCostPerMessage:        models.CreditCostPerMessage,
+		CreditCostPerTextMessage:    models.CreditCostPerTextMessage,
+		LongFormCreditCostPerMinute: models.LongFormCreditCostPerMinute,
+		TierCredits:                 maps.Clone(models.TierCredits),
+		TierLimits:                  maps.Clone(models.TierLimits),
+		TierAnalysisQuality:         maps.Clone(TierAnalysisQuality),
+		PhonemeWeights:              map[string]PhonemeWeights{},
+	}
+}
+
+// TierAllowance returns the tier's monthly credits; unknown tiers get the free allowance
+func (r RuntimeSettings) TierAllowance(tier models.SubscriptionTier) int {
+	if allowance, ok := r.TierCredits[tier]; ok {
+		return allowance
+	}
+	return r.TierCredits[models.TierFree]
+}
+
+// AnalysisQuality returns the tier's pronunciation analysis quality; unknown
+// tiers get the free tier's
+func (r RuntimeSettings) AnalysisQuality(tier models.SubscriptionTier) client.AnalysisQuality {
+	if quality, ok := r.TierAnalysisQuality[tier]; ok {
+		return quality
+	}
+	return r.TierAnalysisQuality[models.TierFree]
+}
+
+// with returns a copy of r with one setting overridden. Map settings are
+// merged: only the tiers (and, for limits, the fields) in value change.
+// Phoneme weights are replaced per language; an empty table removes one.
+func (r RuntimeSettings) with(key, value string) (RuntimeSettings, error) {
+	next := r
+	var err error
+	switch key {
+	case "maxAudioFileSize":
+		err = decodeStrict(value, &next.MaxAudioFileSize)
+		if err == nil && next.MaxAudioFileSize <= 0 {
+			err = errors.New("must be positive")
+		}
+	case "minAudioDurationSeconds":
+		err = decodeStrict(value, &next.MinAudioDurationSeconds)
+		if err == nil && next.MinAudioDurationSeconds < 0 {
+			err = errors.New("must not be negative")
+		}
+	case "maxAudioDurationSeconds":
+		err = decodeStrict(value, &next.MaxAudioDurationSeconds)
+	case "creditCostPerMessage":
+		err = decodeStrict(value, &next.CreditCostPerMessage)
+		if err == nil && next.CreditCostPerMessage < 1 {
+			err = errors.New("must be at least 1")
+		}
+	case "creditCostPerTextMessage":
+		err = decodeStrict(value, &next.CreditCostPerTextMessage)
+		if err == nil && next.CreditCostPerTextMessage < 1 {
+			err = errors.New("must be at least 1")
+		}
+	case "longFormCreditCostPerMinute":
+		err = decodeStrict(value, &next.LongFormCreditCostPerMinute)
+		if err == nil && next.LongFormCreditCostPerMinute < 1 {
+			err = errors.New("must be at least 1")
+		}
+	case "tierCredits":
+		var credits map[models.SubscriptionTier]int
+		if err = decodeStrict(value, &credits); err != nil {
+			break
+		}
+		next.TierCredits = maps.Clone(r.TierCredits)
+		for tier, allowance := range credits {
+			if err = checkTier(tier, r.TierCredits); err != nil {
+				break
+			}
+			if allowance < 0 {
+				err = fmt.Errorf("%s: must not be negative", tier)
+				break
+			}
+			next.TierCredits[tier] = allowance
+		}
+	case "tierLimits":
+		var limits map[models.SubscriptionTier]json.RawMessage
+		if err = decodeStrict(value, &limits); err != nil {
+			break
+		}
+		next.TierLimits = maps.Clone(r.TierLimits)
+		for tier, raw := range limits {
+			if err = checkTier(tier, r.TierLimits); err != nil {
+				break
+			}
+			limit := next.TierLimits[tier]
+			if err = decodeStrict(string(raw), &limit); err != nil {
+				err = fmt.Errorf("%s: %w", tier, err)
+				break
+			}
+			if limit.MaxThreads < 0 || limit.MaxMessages < 0 || limit.MaxThreadMessages < 0 || limit.MaxThreadMinutes < 0 {
+				err = fmt.Errorf("%s: limits must not be negative (0 is unlimited)", tier)
+				break
+			}
+			next.TierLimits[tier] = limit
+		}
+	case "tierAnalysisQuality":
+		var qualities map[models.SubscriptionTier]client.AnalysisQuality
+		if err = decodeStrict(value, &qualities); err != nil {
+			break
+		}
+		next.TierAnalysisQuality = maps.Clone(r.TierAnalysisQuality)
+		for tier, quality := range qualities {
+			if err = checkTier(tier, r.TierAnalysisQuality); err != nil {
+				break
+			}
+			if quality != client.QualityFast && quality != client.QualityAccurate {
+				err = fmt.Errorf("%s: quality must be %q or %q", tier, client.QualityFast, client.QualityAccurate)
+				break
+			}
+			next.TierAnalysisQuality[tier] = quality
+		}
+	case "mlShadowPercent":
+		err = decodeStrict(value, &next.MLShadowPercent)
+		if err == nil && (next.MLShadowPercent < 0 || next.MLShadowPercent > 100) {
+			err = errors.New("must be between 0 and 100")
+		}
+	case "phonemeWeights":
+		var weights map[string]PhonemeWeights
+		if err = decodeStrict(value, &weights); err != nil {
+			break
+		}
+		next.PhonemeWeights = maps.Clone(r.PhonemeWeights)
+		for language, table := range weights {
+			if err = checkPhonemeWeights(language, table); err != nil {
+				break
+			}
+			if len(table) == 0 {
+				delete(next.PhonemeWeights, language)
+				continue
+			}
+			next.PhonemeWeights[language] = table
+		}
+	default:
+		return r, fmt.Errorf("%w: %q", ErrUnknownRuntimeSetting, key)
+	}
+	if err == nil && next.MaxAudioDurationSeconds <= next.MinAudioDurationSeconds {
+		err = errors.New("maxAudioDurationSeconds must be greater than minAudioDurationSeconds")
+	}
+	if err != nil {
+		return r, fmt.Errorf("%w: %s: %v", ErrInvalidRuntimeSetting, key, err)
+	}
+	return next, nil
+}
+
+// checkTier rejects tiers the app doesn't have, so a typo can't go unnoticed
+func checkTier[V any](tier models.SubscriptionTier, known map[models.SubscriptionTier]V) error {
+	if _, ok := known[tier]; !ok {
+		return fmt.Errorf("unknown tier %q", tier)
+	}
+	return nil
+}
+
+// decodeStrict unmarshals JSON, rejecting unknown fields and trailing data
+func decodeStrict(value string, v any) error {
+	dec := json.NewDecoder(bytes.NewReader([]byte(value)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after the value")
+	}
+	return nil
+}
+
+// RuntimeSettingsManager defines the interface for reading and changing runtime settings
+type RuntimeSettingsManager interface {
+	Current() RuntimeSettings
+	Overrides() ([]models.RuntimeSetting, error)
+	Set(key string, value json.RawMessage, actor uuid.UUID) (RuntimeSettings, error)
+	Reset(key string, actor uuid.UUID) (RuntimeSettings, error)
+	Refresh() error
+}
+
+// RuntimeSettingsService caches the runtime settings in memory and reloads
+// them from the database on an interval, so a change made on one instance
+// reaches the others within one interval. A nil *RuntimeSettingsService
+// serves the defaults.
+type RuntimeSettingsService struct {
+	exec     repository.Executor
+	repo     repository.RuntimeSettingRepository
+	audit    AuditLogger
+	interval time.Duration
+
+	current atomic.Pointer[RuntimeSettings]
+}
+
+// NewRuntimeSettingsService creates a new runtime settings service
+func NewRuntimeSettingsService(
+	database *db.DB,
+	repo repository.RuntimeSettingRepository,
+	audit AuditLogger,
+	interval time.Duration,
+) *RuntimeSettingsService {
+	if interval <= 0 {
+		interval = DefaultRuntimeSettingsRefresh
+	}
+	return &RuntimeSettingsService{
+		exec:     database.DB,
+		repo:     repo,
+		audit:    audit,
+		interval: interval,
+	}
+}
+
+// NewRuntimeSettingsServiceForTest creates a RuntimeSettingsService with injected dependencies for testing.
+func NewRuntimeSettingsServiceForTest(
+	exec repository.Executor,
+	repo repository.RuntimeSettingRepository,
+	audit AuditLogger,
+	interval time.Duration,
+) *RuntimeSettingsService {
+	return &RuntimeSettingsService{
+		exec:     exec,
+		repo:     repo,
+		audit:    audit,
+		interval: interval,
+	}
+}
+
+// Current returns the settings in force
+func (s *RuntimeSettingsService) Current() RuntimeSettings {
+	if s == nil {
+		return DefaultRuntimeSettings()
+	}
+	if current := s.current.Load(); current != nil {
+		return *current
+	}
+	return DefaultRuntimeSettings()
+}
+
+// CreditCostPerMessage returns the credits charged per voice message
+func (s *RuntimeSettingsService) CreditCostPerMessage() int {
+	return s.Current().CreditCostPerMessage
+}
+
+// CreditCostPerTextMessage returns the credits charged per typed message
+func (s *RuntimeSettingsService) CreditCostPerTextMessage() int {
+	return s.Current().CreditCostPerTextMessage
+}
+
+// LongFormCreditCostPerMinute returns the credits charged per started minute
+// of a long-form message
+func (s *RuntimeSettingsService) LongFormCreditCostPerMinute() int {
+	return s.Current().LongFormCreditCostPerMinute
+}
+
+// Start reloads the settings until ctx is cancelled
+func (s *RuntimeSettingsService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(); err != nil {
+			log.Printf("[RuntimeSettings] Refresh failed, keeping the previous settings: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh reloads the settings from the database now. Overrides that no
+// longer parse (e.g. edited by hand) are logged and skipped.
+func (s *RuntimeSettingsService) Refresh() error {
+	rows, err := s.repo.FindAll(s.exec)
+	if err != nil {
+		return fmt.Errorf("load runtime settings: %w", err)
+	}
+
+	settings := DefaultRuntimeSettings()
+	for _, row := range rows {
+		next, err := settings.with(row.Key, row.Value)
+		if err != nil {
+			log.Printf("[RuntimeSettings] Ignoring override: %v", err)
+			continue
+		}
+		settings = next
+	}
+	s.current.Store(&settings)
+	return nil
+}
+
+// Overrides returns the stored overrides
+func (s *RuntimeSettingsService) Overrides() ([]models.RuntimeSetting, error) {
+	rows, err := s.repo.FindAll(s.exec)
+	if err != nil {
+		return nil, fmt.Errorf("load runtime settings: %w", err)
+	}
+	return rows, nil
+}
+
+// Set stores an override after checking it against the other overrides, and
+// applies it to this instance immediately
+func (s *RuntimeSettingsService) Set(key string, value json.RawMessage, actor uuid.UUID) (RuntimeSettings, error) {
+	rows, err := s.repo.FindAll(s.exec)
+	if err != nil {
+		return RuntimeSettings{}, fmt.Errorf("load runtime settings: %w", err)
+	}
+	settings := DefaultRuntimeSettings()
+	for _, row := range rows {
+		if row.Key == key {
+			continue
+		}
+		if next, err := settings.with(row.Key, row.Value); err == nil {
+			settings = next
+		}
+	}
+	if _, err := settings.with(key, string(value)); err != nil {
+		return RuntimeSettings{}, err
+	}
+
+	setting := &models.RuntimeSetting{
+		Key:       key,
+		Value:     string(value),
+		UpdatedBy: &actor,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.repo.Upsert(s.exec, setting); err != nil {
+		return RuntimeSettings{}, fmt.Errorf("save runtime setting: %w", err)
+	}
+	s.record(AuditActionRuntimeSettingUpdated, actor, models.JSONMap{"key": key, "value": string(value)})
+
+	if err := s.Refresh(); err != nil {
+		return RuntimeSettings{}, err
+	}
+	return s.Current(), nil
+}
+
+// Reset removes an override so the default applies again
+func (s *RuntimeSettingsService) Reset(key string, actor uuid.UUID) (RuntimeSettings, error) {
+	if err := s.repo.Delete(s.exec, key); err != nil {
+		return RuntimeSettings{}, fmt.Errorf("delete runtime setting %q: %w", key, err)
+	}
+	s.record(AuditActionRuntimeSettingReset, actor, models.JSONMap{"key": key})
+
+	if err := s.Refresh(); err != nil {
+		return RuntimeSettings{}, err
+	}
+	return s.Current(), nil
+}
+
+func (s *RuntimeSettingsService) record(action string, actor uuid.UUID, details models.JSONMap) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(&models.AuditLog{
+		Action:  action,
+		Actor:   actor.String(),
+		Outcome: models.AuditOutcomeSuccess,
+		Details: details,
+	})
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestRuntimeSettings_With(t *testing.T) {
+	defaults := DefaultRuntimeSettings()
+
+	t.Run("scalar override", func(t *testing.T) {
+		next, err := defaults.with("creditCostPerMessage", "2")
+		require.NoError(t, err)
+		assert.Equal(t, 2, next.CreditCostPerMessage)
+		assert.Equal(t, models.CreditCostPerMessage, defaults.CreditCostPerMessage, "original untouched")
+	})
+
+	t.Run("tier limits merge per field", func(t *testing.T) {
+		next, err := defaults.with("tierLimits", `{"free": {"maxThreads": 30}}`)
+		require.NoError(t, err)
+		want := models.TierLimits[models.TierFree]
+		want.MaxThreads = 30
+		assert.Equal(t, want, next.TierLimits[models.TierFree])
+		assert.Equal(t, models.TierLimits[models.TierBasic], next.TierLimits[models.TierBasic])
+		assert.Equal(t, 20, models.TierLimits[models.TierFree].MaxThreads, "package defaults untouched")
+	})
+
+	t.Run("analysis quality merges per tier", func(t *testing.T) {
+		next, err := defaults.with("tierAnalysisQuality", `{"free": "accurate"}`)
+		require.NoError(t, err)
+		assert.Equal(t, client.QualityAccurate, next.AnalysisQuality(models.TierFree))
+		assert.Equal(t, client.QualityFast, defaults.AnalysisQuality(models.TierFree), "original untouched")
+		assert.Equal(t, client.QualityFast, defaults.AnalysisQuality("gold"), "unknown tiers get the free quality")
+	})
+
+	t.Run("tier credits merge per tier", func(t *testing.T) {
+		next, err := defaults.with("tierCredits", `{"pro": 2000}`)
+		require.NoError(t, err)
+		assert.Equal(t, 2000, next.TierAllowance(models.TierPro))
+		assert.Equal(t, models.TierCredits[models.TierBasic], next.TierAllowance(models.TierBasic))
+	})
+
+	t.Run("phoneme weights replace per language", func(t *testing.T) {
+		weighted, err := defaults.with("phonemeWeights", `{"en-us": {"θ": 0.5, "r": 2}, "en-gb": {"r": 0.5}}`)
+		require.NoError(t, err)
+		next, err := weighted.with("phonemeWeights", `{"en-us": {"ð": 0.5}, "en-gb": {}}`)
+		require.NoError(t, err)
+		assert.Equal(t, PhonemeWeights{"ð": 0.5}, next.PhonemeWeights["en-us"])
+		assert.NotContains(t, next.PhonemeWeights, "en-gb", "an empty table removes the language")
+		assert.Equal(t, 2.0, weighted.PhonemeWeights["en-us"].Of("r"), "original untouched")
+		assert.Equal(t, 1.0, next.PhonemeWeights["en-us"].Of("r"), "unlisted phonemes weigh 1")
+	})
+
+	invalid := []struct {
+		name, key, value string
+	}{
+		{"not JSON", "maxAudioFileSize", "ten megabytes"},
+		{"zero file size", "maxAudioFileSize", "0"},
+		{"free messages", "creditCostPerMessage", "0"},
+		{"min above max", "minAudioDurationSeconds", "45"},
+		{"unknown tier", "tierCredits", `{"gold": 5000}`},
+		{"misspelled limit", "tierLimits", `{"free": {"maxThread": 30}}`},
+		{"negative limit", "tierLimits", `{"basic": {"maxMessages": -1}}`},
+		{"unknown quality", "tierAnalysisQuality", `{"pro": "best"}`},
+		{"bad language", "phonemeWeights", `{"English": {"θ": 0.5}}`},
+		{"negative weight", "phonemeWeights", `{"en-us": {"θ": -1}}`},
+		{"weight too large", "phonemeWeights", `{"en-us": {"θ": 11}}`},
+		{"shadow share above 100", "mlShadowPercent", "150"},
+		{"trailing data", "creditCostPerMessage", "2 3"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := defaults.with(tt.key, tt.value)
+			assert.ErrorIs(t, err, ErrInvalidRuntimeSetting)
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := defaults.with("maxAudioFileSizeBytes", "1")
+		assert.ErrorIs(t, err, ErrUnknownRuntimeSetting)
+	})
+}
+
+func TestRuntimeSettingsService_Current(t *testing.T) {
+	var nilService *RuntimeSettingsService
+	assert.Equal(t, DefaultRuntimeSettings(), nilService.Current())
+
+	repo := new(repomocks.MockRuntimeSettingRepository)
+	repo.On("FindAll", mock.Anything).Return([]models.RuntimeSetting{
+		{Key: "maxAudioDurationSeconds", Value: "60"},
+		{Key: "maxAudioFileSize", Value: `"big"`}, // Hand-edited and broken; skipped
+	}, nil)
+	svc := NewRuntimeSettingsServiceForTest(nil, repo, nil, 0)
+	assert.Equal(t, DefaultRuntimeSettings(), svc.Current(), "defaults before the first refresh")
+
+	require.NoError(t, svc.Refresh())
+	assert.Equal(t, 60.0, svc.Current().MaxAudioDurationSeconds)
+	assert.Equal(t, DefaultRuntimeSettings().MaxAudioFileSize, svc.Current().MaxAudioFileSize)
+
+	// A failed reload keeps the last good settings
+	repo.ExpectedCalls = nil
+	repo.On("FindAll", mock.Anything).Return(nil, errors.New("connection reset"))
+	assert.Error(t, svc.Refresh())
+	assert.Equal(t, 60.0, svc.Current().MaxAudioDurationSeconds)
+}
+
+func TestRuntimeSettingsService_Set(t *testing.T) {
+	actor := uuid.New()
+
+	t.Run("stores, audits and applies the override", func(t *testing.T) {
+		repo := new(repomocks.MockRuntimeSettingRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		repo.On("FindAll", mock.Anything).Return([]models.RuntimeSetting{}, nil).Once()
+		repo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.RuntimeSetting) bool {
+			return s.Key == "creditCostPerMessage" && s.Value == "2" && *s.UpdatedBy == actor
+		})).Return(nil)
+		repo.On("FindAll", mock.Anything).Return([]models.RuntimeSetting{
+			{Key: "creditCostPerMessage", Value: "2"},
+		}, nil).Once()
+		auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditLog) bool {
+			return e.Action == AuditActionRuntimeSettingUpdated && e.Actor == actor.String()
+		})).Return(nil)
+
+		svc := NewRuntimeSettingsServiceForTest(nil, repo, NewAuditServiceForTest(nil, auditRepo), 0)
+		settings, err := svc.Set("creditCostPerMessage", json.RawMessage("2"), actor)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, settings.CreditCostPerMessage)
+		assert.Equal(t, 2, svc.CreditCostPerMessage())
+		repo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("checks the value against the other overrides", func(t *testing.T) {
+		repo := new(repomocks.MockRuntimeSettingRepository)
+		repo.On("FindAll", mock.Anything).Return([]models.RuntimeSetting{
+			{Key: "maxAudioDurationSeconds", Value: "10"},
+		}, nil)
+
+		svc := NewRuntimeSettingsServiceForTest(nil, repo, nil, 0)
+		_, err := svc.Set("minAudioDurationSeconds", json.RawMessage("12"), actor)
+
+		assert.ErrorIs(t, err, ErrInvalidRuntimeSetting)
+		repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+// runtimeSettingsWith returns a settings service with one override loaded
+func runtimeSettingsWith(t *testing.T, key, value string) *RuntimeSettingsService {
+	repo := new(repomocks.MockRuntimeSettingRepository)
+	repo.On("FindAll", mock.Anything).Return([]models.RuntimeSetting{{Key: key, Value: value}}, nil)
+	svc := NewRuntimeSettingsServiceForTest(nil, repo, nil, 0)
+	require.NoError(t, svc.Refresh())
+	return svc
+}
@@ -0,0 +1,127 @@
+package services
+
+import "strings"
+
+// Scenario is a ready-made conversation the home screen can suggest. Its goal
+// becomes the goal of the thread started from it.
+type Scenario struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Goal   string `json:"goal"`
+	Opener string `json:"opener"` // A first line for the learner to say
+
+	// Interests the scenario suits, matched against the learner's memory
+	topics []string
+	// Phonemes its vocabulary gives a lot of practice with
+	phonemes []string
+}
+
+// starterScenario is suggested to learners who haven't practiced yet
+const starterScenario = "introductions"
+
+// Scenarios is the catalog, in the order they rotate through as the daily
+// suggestion
+var Scenarios = []Scenario{
+	{
+		ID:       "introductions",
+		Title:    "Introduce yourself",
+		Goal:     "say who you are, where you're from and what you do",
+		Opener:   "Hi! Nice to meet you. My name is…",
+		topics:   []string{"people", "family", "friends"},
+		phonemes: []string{"m", "n", "j"},
+	},
+	{
+		ID:       "coffee-shop",
+		Title:    "Order at a coffee shop",
+		Goal:     "order a drink and a snack and pay",
+		Opener:   "Hi, could I get a coffee, please?",
+		topics:   []string{"food", "coffee", "cooking"},
+		phonemes: []string{"k", "f", "ɔ"},
+	},
+	{
+		ID:       "directions",
+		Title:    "Ask for directions",
+		Goal:     "find out how to get to the train station",
+		Opener:   "Excuse me, where is the nearest train station?",
+		topics:   []string{"travel", "cities"},
+		phonemes: []string{"r", "ɹ", "ʃ", "tʃ"},
+	},
+	{
+		ID:       "weekend-plans",
+		Title:    "Make weekend plans",
+		Goal:     "agree on a plan for Saturday with a friend",
+		Opener:   "What do you want to do this weekend?",
+		topics:   []string{"movies", "music", "sports", "hiking"},
+		phonemes: []string{"w", "v", "æ"},
+	},
+	{
+		ID:       "job-interview",
+		Title:    "Job interview",
+		Goal:     "answer three interview questions about your experience",
+		Opener:   "Thank you for having me. I've been working as…",
+		topics:   []string{"work", "business", "technology", "careers"},
+		phonemes: []string{"θ", "ð", "ɪ"},
+	},
+	{
+		ID:       "doctor",
+		Title:    "At the doctor's",
+		Goal:     "describe how you feel and understand the advice",
+		Opener:   "Hello doctor, I haven't been feeling well.",
+		topics:   []string{"health", "fitness"},
+		phonemes: []string{"h", "iː", "i"},
+	},
+	{
+		ID:       "hotel-check-in",
+		Title:    "Check in to a hotel",
+		Goal:     "check in and ask about breakfast and wifi",
+		Opener:   "Hi, I have a reservation under the name…",
+		topics:   []string{"travel", "vacation"},
+		phonemes: []string{"l", "z", "eɪ"},
+	},
+	{
+		ID:       "shopping",
+		Title:    "Return something at a shop",
+		Goal:     "return a shirt that doesn't fit and get a refund",
+		Opener:   "Hi, I'd like to return this shirt.",
+		topics:   []string{"fashion", "shopping"},
+		phonemes: []string{"ʃ", "ɜː", "ɝ"},
+	},
+}
+
+// scenarioByID returns the catalog entry with the given ID
+func scenarioByID(id string) (Scenario, bool) {
+	for _, s := range Scenarios {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// scenarioForPhoneme returns the first scenario that drills the phoneme
+func scenarioForPhoneme(phoneme string) (Scenario, bool) {
+	for _, s := range Scenarios {
+		for _, p := range s.phonemes {
+			if p == phoneme {
+				return s, true
+			}
+		}
+	}
+	return Scenario{}, false
+}
+
+// scenarioForInterests returns the first scenario whose topics appear in
+// one of the interests, e.g. "travel" in "traveling in Japan"
+func scenarioForInterests(interests []string) (Scenario, bool) {
+	for _, interest := range interests {
+		interest = strings.ToLower(interest)
+		for _, s := range Scenarios {
+			for _, topic := range s.topics {
+				if strings.Contains(interest, topic) {
+					return s, true
+				}
+			}
+		}
+	}
+	return Scenario{}, false
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidAudioRetention = errors.New("invalid audio retention period")
+	ErrInvalidReplyLength    = errors.New("invalid reply length")
+	ErrInvalidSpeechRate     = errors.New("invalid speech rate")
+	ErrInvalidTimezone       = errors.New("invalid timezone")
+	ErrInvalidEmailFrequency = errors.New("invalid email frequency")
+
+	ErrContentEncryptionUnavailable = errors.New("content encryption is not available")
+)
+
+// SettingsManager defines the interface for user settings operations
+type SettingsManager interface {
+	GetSettings(userID uuid.UUID) (*models.UserSettings, error)
+	SetAudioRetention(userID uuid.UUID, days int) (*models.UserSettings, error)
+	SetReplyLength(userID uuid.UUID, length string) (*models.UserSettings, error)
+	SetSpeechRate(userID uuid.UUID, rate float64) (*models.UserSettings, error)
+	SetEncryptContent(userID uuid.UUID, enabled bool) (*models.UserSettings, error)
+	SetTimezone(userID uuid.UUID, timezone string) (*models.UserSettings, error)
+	SetEmailFrequency(userID uuid.UUID, category, frequency string) (*models.UserSettings, error)
+}
+
+// UserTimezones looks up the timezone a user's day starts in
+type UserTimezones interface {
+	Location(userID uuid.UUID) *time.Location
+}
+
+// SettingsService stores per-user account settings
+type SettingsService struct {
+	exec         repository.Executor
+	settingsRepo repository.UserSettingsRepository
+
+	// ContentEncryption is set when the server has a master key, so users
+	// can turn content encryption on
+	ContentEncryption bool
+}
+
+// NewSettingsService creates a new settings service
+func NewSettingsService(database *db.DB, settingsRepo repository.UserSettingsRepository) *SettingsService {
+	return &SettingsService{
+		exec:         database.DB,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// NewSettingsServiceForTest creates a SettingsService with injected dependencies for testing.
+func NewSettingsServiceForTest(exec repository.Executor, settingsRepo repository.UserSettingsRepository) *SettingsService {
+	return &SettingsService{
+		exec:         exec,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// GetSettings returns the user's settings, or the defaults if they never changed any
+func (s *SettingsService) GetSettings(userID uuid.UUID) (*models.UserSettings, error) {
+	settings, err := s.settingsRepo.FindByUserID(s.exec, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.DefaultUserSettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Location returns the user's timezone. If their settings can't be read the
+// day falls back to UTC rather than failing the caller.
+func (s *SettingsService) Location(userID uuid.UUID) *time.Location {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		log.Printf("[Settings] Failed to get timezone for user %s: %v", userID, err)
+		return time.UTC
+	}
+	return settings.Location()
+}
+
+// userLocation returns the user's timezone, or UTC without a lookup
+func userLocation(timezones UserTimezones, userID uuid.UUID) *time.Location {
+	if timezones == nil {
+		return time.UTC
+	}
+	return timezones.Location(userID)
+}
+
+// SetAudioRetention sets how many days the user's recordings are kept.
+// days must be one of models.AudioRetentionOptions.
+func (s *SettingsService) SetAudioRetention(userID uuid.UUID, days int) (*models.UserSettings, error) {
+	if !slices.Contains(models.AudioRetentionOptions, days) {
+		return nil, ErrInvalidAudioRetention
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.AudioRetentionDays = days
+	return s.save(settings)
+}
+
+// SetReplyLength sets how long the assistant's replies are. length must be
+// one of models.ReplyLengthOptions.
+func (s *SettingsService) SetReplyLength(userID uuid.UUID, length string) (*models.UserSettings, error) {
+	if !ValidReplyLength(length) {
+		return nil, ErrInvalidReplyLength
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.ReplyLength = length
+	return s.save(settings)
+}
+
+// SetSpeechRate sets how fast replies are read aloud, between
+// models.MinSpeechRate and models.MaxSpeechRate
+func (s *SettingsService) SetSpeechRate(userID uuid.UUID, rate float64) (*models.UserSettings, error) {
+	if !ValidSpeechRate(rate) {
+		return nil, ErrInvalidSpeechRate
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.SpeechRate = rate
+	return s.save(settings)
+}
+
+// SetEncryptContent turns content encryption on or off for the user's
+// transcripts and analyses. Turning it on also seals what they already have,
+// in the background; turning it off leaves that sealed.
+func (s *SettingsService) SetEncryptContent(userID uuid.UUID, enabled bool) (*models.UserSettings, error) {
+	if enabled && !s.ContentEncryption {
+		return nil, ErrContentEncryptionUnavailable
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.EncryptContent = enabled
+	return s.save(settings)
+}
+
+// SetTimezone sets the IANA timezone whose midnight starts the user's day
+func (s *SettingsService) SetTimezone(userID uuid.UUID, timezone string) (*models.UserSettings, error) {
+	if !ValidTimezone(timezone) {
+		return nil, ErrInvalidTimezone
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.Timezone = timezone
+	return s.save(settings)
+}
+
+// SetEmailFrequency sets how often notifications of an email category are
+// emailed. Only achievements and updates can be changed; billing is always
+// emailed.
+func (s *SettingsService) SetEmailFrequency(userID uuid.UUID, category, frequency string) (*models.UserSettings, error) {
+	if !ValidEmailFrequency(frequency) {
+		return nil, ErrInvalidEmailFrequency
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	switch category {
+	case models.EmailCategoryAchievements:
+		settings.EmailAchievements = frequency
+	case models.EmailCategoryUpdates:
+		settings.EmailUpdates = frequency
+	default:
+		return nil, ErrInvalidEmailFrequency
+	}
+	return s.save(settings)
+}
+
+func (s *SettingsService) save(settings *models.UserSettings) (*models.UserSettings, error) {
+	settings.UpdatedAt = time.Now().UTC()
+	if err := s.settingsRepo.Upsert(s.exec, settings); err != nil {
+		return nil, fmt.Errorf("save settings: %w", err)
+	}
+	return settings, nil
+}
+
+// ValidReplyLength reports whether length is one of models.ReplyLengthOptions
+func ValidReplyLength(length string) bool {
+	return slices.Contains(models.ReplyLengthOptions, length)
+}
+
+// ValidSpeechRate reports whether rate is within the allowed speaking rates
+func ValidSpeechRate(rate float64) bool {
+	return rate >= models.MinSpeechRate && rate <= models.MaxSpeechRate
+}
+
+// ValidTimezone reports whether timezone is an IANA timezone name. "Local"
+// is refused: it would mean the server's timezone.
+func ValidTimezone(timezone string) bool {
+	if timezone == "" || timezone == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(timezone)
+	return err == nil
+}
+
+// ValidEmailFrequency reports whether frequency is one of models.EmailFrequencyOptions
+func ValidEmailFrequency(frequency string) bool {
+	return slices.Contains(models.EmailFrequencyOptions, frequency)
+}
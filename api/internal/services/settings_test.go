@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestSettingsService_GetSettings_DefaultsToKeep(t *testing.T) {
+	userID := uuid.New()
+	settingsRepo := new(repomocks.MockUserSettingsRepository)
+	settingsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+
+	settings, err := NewSettingsServiceForTest(nil, settingsRepo).GetSettings(userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.AudioRetentionKeep, settings.AudioRetentionDays)
+}
+
+func TestSettingsService_SetAudioRetention(t *testing.T) {
+	tests := []struct {
+		name    string
+		days    int
+		wantErr error
+	}{
+		{name: "keep", days: 0},
+		{name: "seven days", days: 7},
+		{name: "ninety days", days: 90},
+		{name: "unsupported period", days: 14, wantErr: ErrInvalidAudioRetention},
+		{name: "negative", days: -1, wantErr: ErrInvalidAudioRetention},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			settingsRepo := new(repomocks.MockUserSettingsRepository)
+			settingsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.UserSettings{UserID: userID}, nil)
+			settingsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+			settings, err := NewSettingsServiceForTest(nil, settingsRepo).SetAudioRetention(userID, tt.days)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				settingsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.days, settings.AudioRetentionDays)
+			settingsRepo.AssertCalled(t, "Upsert", mock.Anything, mock.MatchedBy(func(s *models.UserSettings) bool {
+				return s.UserID == userID && s.AudioRetentionDays == tt.days
+			}))
+		})
+	}
+}
+
+func TestSettingsService_GetSettings_DefaultReplyStyle(t *testing.T) {
+	userID := uuid.New()
+	settingsRepo := new(repomocks.MockUserSettingsRepository)
+	settingsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+
+	settings, err := NewSettingsServiceForTest(nil, settingsRepo).GetSettings(userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ReplyLengthMedium, settings.ReplyLength)
+	assert.Equal(t, models.DefaultSpeechRate, settings.SpeechRate)
+}
+
+func TestSettingsService_SetReplyLength(t *testing.T) {
+	userID := uuid.New()
+	settingsRepo := new(repomocks.MockUserSettingsRepository)
+	settingsRepo.On("FindByUserID", mock.Anything, userID).Return(models.DefaultUserSettings(userID), nil)
+	settingsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	service := NewSettingsServiceForTest(nil, settingsRepo)
+
+	_, err := service.SetReplyLength(userID, "epic")
+	assert.ErrorIs(t, err, ErrInvalidReplyLength)
+	settingsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+
+	settings, err := service.SetReplyLength(userID, models.ReplyLengthShort)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReplyLengthShort, settings.ReplyLength)
+	settingsRepo.AssertCalled(t, "Upsert", mock.Anything, mock.MatchedBy(func(s *models.UserSettings) bool {
+		return s.UserID == userID && s.ReplyLength == models.ReplyLengthShort
+	}))
+}
+
+func TestSettingsService_SetSpeechRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64
+		wantErr error
+	}{
+		{name: "slowest", rate: models.MinSpeechRate},
+		{name: "fast", rate: 1.25},
+		{name: "fastest", rate: models.MaxSpeechRate},
+		{name: "too slow", rate: 0.25, wantErr: ErrInvalidSpeechRate},
+		{name: "too fast", rate: 2, wantErr: ErrInvalidSpeechRate},
+		{name: "zero", rate: 0, wantErr: ErrInvalidSpeechRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			settingsRepo := new(repomocks.MockUserSettingsRepository)
+			settingsRepo.On("FindByUserID", mock.Anything, userID).Return(models.DefaultUserSettings(userID), nil)
+			settingsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+			settings, err := NewSettingsServiceForTest(nil, settingsRepo).SetSpeechRate(userID, tt.rate)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				settingsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rate, settings.SpeechRate)
+		})
+	}
+}
+
+func TestSettingsService_SetTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		wantErr  error
+	}{
+		{name: "iana name", timezone: "America/New_York"},
+		{name: "utc", timezone: "UTC"},
+		{name: "unknown", timezone: "Mars/Olympus_Mons", wantErr: ErrInvalidTimezone},
+		{name: "server local", timezone: "Local", wantErr: ErrInvalidTimezone},
+		{name: "empty", timezone: "", wantErr: ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			settingsRepo := new(repomocks.MockUserSettingsRepository)
+			settingsRepo.On("FindByUserID", mock.Anything, userID).Return(models.DefaultUserSettings(userID), nil)
+			settingsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+			settings, err := NewSettingsServiceForTest(nil, settingsRepo).SetTimezone(userID, tt.timezone)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				settingsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.timezone, settings.Timezone)
+		})
+	}
+}
+
+func TestSettingsService_SetEmailFrequency(t *testing.T) {
+	tests := []struct {
+		name      string
+		category  string
+		frequency string
+		wantErr   error
+	}{
+		{name: "achievements daily", category: models.EmailCategoryAchievements, frequency: models.EmailFrequencyDaily},
+		{name: "updates off", category: models.EmailCategoryUpdates, frequency: models.EmailFrequencyOff},
+		{name: "unknown frequency", category: models.EmailCategoryUpdates, frequency: "hourly", wantErr: ErrInvalidEmailFrequency},
+		{name: "billing can't be changed", category: models.EmailCategoryBilling, frequency: models.EmailFrequencyOff, wantErr: ErrInvalidEmailFrequency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			settingsRepo := new(repomocks.MockUserSettingsRepository)
+			settingsRepo.On("FindByUserID", mock.Anything, userID).Return(models.DefaultUserSettings(userID), nil)
+			settingsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+			settings, err := NewSettingsServiceForTest(nil, settingsRepo).SetEmailFrequency(userID, tt.category, tt.frequency)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				settingsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.frequency, settings.EmailFrequency(tt.category))
+		})
+	}
+}
+
+func TestSettingsService_Location(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("defaults to UTC", func(t *testing.T) {
+		settingsRepo := new(repomocks.MockUserSettingsRepository)
+		settingsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+
+		assert.Equal(t, time.UTC, NewSettingsServiceForTest(nil, settingsRepo).Location(userID))
+	})
+
+	t.Run("the user's timezone", func(t *testing.T) {
+		settingsRepo := new(repomocks.MockUserSettingsRepository)
+		settingsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.UserSettings{UserID: userID, Timezone: "Europe/Madrid"}, nil)
+
+		assert.Equal(t, "Europe/Madrid", NewSettingsServiceForTest(nil, settingsRepo).Location(userID).String())
+	})
+
+	t.Run("falls back to UTC when settings can't be read", func(t *testing.T) {
+		settingsRepo := new(repomocks.MockUserSettingsRepository)
+		settingsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, errors.New("db down"))
+
+		assert.Equal(t, time.UTC, NewSettingsServiceForTest(nil, settingsRepo).Location(userID))
+	})
+}
+
+func TestSettingsService_SetEncryptContent(t *testing.T) {
+	userID := uuid.New()
+	settingsRepo := new(repomocks.MockUserSettingsRepository)
+	settingsRepo.On("FindByUserID", mock.Anything, userID).Return(models.DefaultUserSettings(userID), nil)
+	settingsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	service := NewSettingsServiceForTest(nil, settingsRepo)
+
+	_, err := service.SetEncryptContent(userID, true)
+	assert.ErrorIs(t, err, ErrContentEncryptionUnavailable, "no master key configured")
+	settingsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+
+	settings, err := service.SetEncryptContent(userID, false)
+	require.NoError(t, err, "turning it off never needs the key")
+	assert.False(t, settings.EncryptContent)
+
+	service.ContentEncryption = true
+	settings, err = service.SetEncryptContent(userID, true)
+	require.NoError(t, err)
+	assert.True(t, settings.EncryptContent)
+}
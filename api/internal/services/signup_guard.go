@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrSignupNotPendingReview = errors.New("signup is not pending review")
+
+// AuditActionSignupReviewed is the audit log action for a manual signup review
+const AuditActionSignupReviewed = "signup.reviewed"
+
+// Duplicate signup heuristics. An IP address alone never sends an account to
+// review: schools and offices put many real users behind one address.
+const (
+	signupIPWindow            = 24 * time.Hour
+	signupScoreFingerprint    = 3 // same device as an earlier account
+	signupScoreIPAndAgent     = 2 // same IP and browser as an account in the window
+	signupMaxScoreIP          = 2 // one point per other account on the same IP in the window
+	signupReducedScore        = 2
+	signupReviewScore         = 3
+	SignupReducedTrialCredits = 5
+)
+
+// SignupSignals are what a registration request tells us about where it came from
+type SignupSignals struct {
+	IPAddress       string
+	UserAgent       string
+	FingerprintHash string // optional, computed client-side
+}
+
+// SignupAssessment is the duplicate check result for a new account
+type SignupAssessment struct {
+	Score          int
+	Verdict        models.SignupVerdict
+	Reasons        []string
+	MatchedUserIDs []uuid.UUID
+}
+
+// trialCredits returns the starting balance for the verdict
+func (a *SignupAssessment) trialCredits(allowance int) int {
+	switch a.Verdict {
+	case models.SignupReview:
+		return 0
+	case models.SignupReduced:
+		return min(allowance, SignupReducedTrialCredits)
+	default:
+		return allowance
+	}
+}
+
+// SignupReviewer defines the interface for reviewing flagged signups
+type SignupReviewer interface {
+	PendingReview(limit int) ([]models.SignupSignal, error)
+	Review(userID, reviewer uuid.UUID, grantCredits bool) (*models.SignupSignal, error)
+}
+
+// SignupGuard detects probable duplicate accounts at registration and holds
+// back their trial credits
+type SignupGuard struct {
+	exec       repository.Executor
+	signalRepo repository.SignupSignalRepository
+	credits    *CreditsService
+	audit      AuditLogger
+
+	now func() time.Time
+}
+
+// NewSignupGuard creates a new signup guard
+func NewSignupGuard(
+	database *db.DB,
+	signalRepo repository.SignupSignalRepository,
+	credits *CreditsService,
+	audit AuditLogger,
+) *SignupGuard {
+	return &SignupGuard{
+		exec:       database.DB,
+		signalRepo: signalRepo,
+		credits:    credits,
+		audit:      audit,
+		now:        time.Now,
+	}
+}
+
+// NewSignupGuardForTest creates a SignupGuard with injected dependencies for testing.
+func NewSignupGuardForTest(
+	exec repository.Executor,
+	signalRepo repository.SignupSignalRepository,
+	credits *CreditsService,
+	audit AuditLogger,
+	now func() time.Time,
+) *SignupGuard {
+	return &SignupGuard{
+		exec:       exec,
+		signalRepo: signalRepo,
+		credits:    credits,
+		audit:      audit,
+		now:        now,
+	}
+}
+
+// SignupAdmission sets up a new account's trial credits from its duplicate
+// check and records its signup signals. Pass it to the auth service as the
+// credits initializer so both happen in the account's creation transaction;
+// existing accounts (e.g. an OAuth login) never touch it.
+type SignupAdmission struct {
+	guard   *SignupGuard
+	method  string
+	signals SignupSignals
+
+	// Assessment is set once the account has been created
+	Assessment *SignupAssessment
+}
+
+// Admit prepares the credits initializer for a registration
+func (g *SignupGuard) Admit(method string, signals SignupSignals) *SignupAdmission {
+	return &SignupAdmission{guard: g, method: method, signals: signals}
+}
+
+// InitializeCreditsWithTx assesses the signup and creates the account's credits
+func (a *SignupAdmission) InitializeCreditsWithTx(exec repository.Executor, userID uuid.UUID, tier models.SubscriptionTier) error {
+	g := a.guard
+	assessment, err := g.assess(exec, a.signals)
+	if err != nil {
+		return err
+	}
+
+	allowance := g.credits.Runtime.Current().TierAllowance(tier)
+	balance := assessment.trialCredits(allowance)
+	if err := g.credits.InitializeTrialCreditsWithTx(exec, userID, tier, balance); err != nil {
+		return err
+	}
+
+	matched := make([]string, len(assessment.MatchedUserIDs))
+	for i, id := range assessment.MatchedUserIDs {
+		matched[i] = id.String()
+	}
+	signal := &models.SignupSignal{
+		UserID:          userID,
+		Method:          a.method,
+		IPAddress:       a.signals.IPAddress,
+		UserAgent:       truncateUserAgent(a.signals.UserAgent),
+		Score:           assessment.Score,
+		Verdict:         assessment.Verdict,
+		Reasons:         models.JSONMap{"reasons": assessment.Reasons, "matchedUserIds": matched},
+		CreditsWithheld: allowance - balance,
+	}
+	if a.signals.FingerprintHash != "" {
+		signal.FingerprintHash = &a.signals.FingerprintHash
+	}
+	if err := g.signalRepo.Create(exec, signal); err != nil {
+		return fmt.Errorf("failed to record signup signals: %w", err)
+	}
+
+	if assessment.Verdict != models.SignupClear {
+		log.Printf("[SignupGuard] Flagged user %s for %s (score %d): %v", userID, assessment.Verdict, assessment.Score, assessment.Reasons)
+	}
+	a.Assessment = assessment
+	return nil
+}
+
+// assess scores a signup against earlier ones from the same device or network
+func (g *SignupGuard) assess(exec repository.Executor, signals SignupSignals) (*SignupAssessment, error) {
+	matches, err := g.signalRepo.FindMatching(exec, signals.IPAddress, signals.FingerprintHash, g.now().Add(-signupIPWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find matching signups: %w", err)
+	}
+
+	var sameDevice, sameIPAndAgent, sameIP int
+	assessment := &SignupAssessment{Verdict: models.SignupClear}
+	for _, m := range matches {
+		assessment.MatchedUserIDs = append(assessment.MatchedUserIDs, m.UserID)
+		switch {
+		case signals.FingerprintHash != "" && m.FingerprintHash != nil && *m.FingerprintHash == signals.FingerprintHash:
+			sameDevice++
+		case m.IPAddress == signals.IPAddress && m.UserAgent == signals.UserAgent:
+			sameIPAndAgent++
+		default:
+			sameIP++
+		}
+	}
+
+	if sameDevice > 0 {
+		assessment.Score += signupScoreFingerprint
+		assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("same device as %d earlier account(s)", sameDevice))
+	}
+	if sameIPAndAgent > 0 {
+		assessment.Score += signupScoreIPAndAgent
+		assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("same IP and browser as %d account(s) in the last %s", sameIPAndAgent, signupIPWindow))
+	}
+	if sameIP > 0 {
+		assessment.Score += min(sameIP, signupMaxScoreIP)
+		assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("same IP as %d account(s) in the last %s", sameIP, signupIPWindow))
+	}
+
+	switch {
+	case assessment.Score >= signupReviewScore && sameDevice > 0:
+		assessment.Verdict = models.SignupReview
+	case assessment.Score >= signupReducedScore:
+		assessment.Verdict = models.SignupReduced
+	}
+	return assessment, nil
+}
+
+// PendingReview returns flagged signups nobody has reviewed yet, oldest first
+func (g *SignupGuard) PendingReview(limit int) ([]models.SignupSignal, error) {
+	signals, err := g.signalRepo.FindPendingReview(g.exec, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending signups: %w", err)
+	}
+	return signals, nil
+}
+
+// Review closes a flagged signup. When grantCredits is set, the withheld
+// trial credits are added to the account; otherwise it keeps what it has.
+func (g *SignupGuard) Review(userID, reviewer uuid.UUID, grantCredits bool) (*models.SignupSignal, error) {
+	var signal *models.SignupSignal
+	err := g.credits.txRunner.Transaction(func(tx *gorm.DB) error {
+		var err error
+		signal, err = g.signalRepo.FindByUserID(tx, userID)
+		if err != nil {
+			return err
+		}
+		if signal.Verdict == models.SignupClear || signal.ReviewedAt != nil {
+			return ErrSignupNotPendingReview
+		}
+
+		now := g.now()
+		signal.ReviewedBy = &reviewer
+		signal.ReviewedAt = &now
+		signal.CreditsGranted = grantCredits && signal.CreditsWithheld > 0
+		if err := g.signalRepo.Save(tx, signal); err != nil {
+			return fmt.Errorf("failed to save signup review: %w", err)
+		}
+
+		if signal.CreditsGranted {
+			return g.credits.addCreditsWithTx(tx, userID, signal.CreditsWithheld, "Trial credits released after review")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if g.audit != nil {
+		g.audit.Record(&models.AuditLog{
+			Action:  AuditActionSignupReviewed,
+			Actor:   reviewer.String(),
+			Outcome: models.AuditOutcomeSuccess,
+			Details: models.JSONMap{
+				"userId":         userID.String(),
+				"verdict":        string(signal.Verdict),
+				"creditsGranted": signal.CreditsGranted,
+				"credits":        signal.CreditsWithheld,
+			},
+		})
+	}
+	return signal, nil
+}
+
+// truncateUserAgent keeps user agents within the column size
+func truncateUserAgent(ua string) string {
+	if runes := []rune(ua); len(runes) > 500 {
+		return string(runes[:500])
+	}
+	return ua
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestSignupGuard_Assess(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	fingerprint := "device-hash"
+	signals := SignupSignals{IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0", FingerprintHash: fingerprint}
+
+	tests := []struct {
+		name    string
+		matches []models.SignupSignal
+		verdict models.SignupVerdict
+		score   int
+	}{
+		{
+			name:    "no earlier signups",
+			verdict: models.SignupClear,
+		},
+		{
+			name: "one other account on the network",
+			matches: []models.SignupSignal{
+				{UserID: uuid.New(), IPAddress: signals.IPAddress, UserAgent: "Safari"},
+			},
+			verdict: models.SignupClear,
+			score:   1,
+		},
+		{
+			name: "a busy shared network never goes to review",
+			matches: []models.SignupSignal{
+				{UserID: uuid.New(), IPAddress: signals.IPAddress, UserAgent: "Safari"},
+				{UserID: uuid.New(), IPAddress: signals.IPAddress, UserAgent: "Firefox"},
+				{UserID: uuid.New(), IPAddress: signals.IPAddress, UserAgent: "Edge"},
+				{UserID: uuid.New(), IPAddress: signals.IPAddress, UserAgent: signals.UserAgent},
+			},
+			verdict: models.SignupReduced,
+			score:   signupScoreIPAndAgent + signupMaxScoreIP,
+		},
+		{
+			name: "same IP and browser",
+			matches: []models.SignupSignal{
+				{UserID: uuid.New(), IPAddress: signals.IPAddress, UserAgent: signals.UserAgent},
+			},
+			verdict: models.SignupReduced,
+			score:   signupScoreIPAndAgent,
+		},
+		{
+			name: "same device",
+			matches: []models.SignupSignal{
+				{UserID: uuid.New(), IPAddress: "198.51.100.1", FingerprintHash: &fingerprint},
+			},
+			verdict: models.SignupReview,
+			score:   signupScoreFingerprint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signalRepo := new(repomocks.MockSignupSignalRepository)
+			signalRepo.On("FindMatching", mock.Anything, signals.IPAddress, fingerprint, now.Add(-signupIPWindow)).Return(tt.matches, nil)
+
+			guard := NewSignupGuardForTest(nil, signalRepo, nil, nil, func() time.Time { return now })
+			assessment, err := guard.assess(nil, signals)
+			require.NoError(t, err)
+			assert.Equal(t, tt.verdict, assessment.Verdict)
+			assert.Equal(t, tt.score, assessment.Score)
+			assert.Len(t, assessment.MatchedUserIDs, len(tt.matches))
+		})
+	}
+}
+
+func TestSignupAdmission_InitializeCreditsWithTx(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	allowance := models.TierCredits[models.TierFree]
+	fingerprint := "device-hash"
+
+	tests := []struct {
+		name     string
+		matches  []models.SignupSignal
+		balance  int
+		withheld int
+	}{
+		{"clear gets the full trial", nil, allowance, 0},
+		{"reduced gets a small trial", []models.SignupSignal{
+			{UserID: uuid.New(), IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0"},
+		}, SignupReducedTrialCredits, allowance - SignupReducedTrialCredits},
+		{"review gets nothing until reviewed", []models.SignupSignal{
+			{UserID: uuid.New(), FingerprintHash: &fingerprint},
+		}, 0, allowance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signalRepo := new(repomocks.MockSignupSignalRepository)
+			creditsRepo := new(repomocks.MockCreditsRepository)
+			signalRepo.On("FindMatching", mock.Anything, "203.0.113.7", "device-hash", mock.Anything).Return(tt.matches, nil)
+			creditsRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+				return c.UserID == userID && c.Balance == tt.balance && c.MonthlyAllowance == allowance
+			})).Return(nil)
+			signalRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *models.SignupSignal) bool {
+				return s.UserID == userID && s.Method == "password" && s.CreditsWithheld == tt.withheld &&
+					s.FingerprintHash != nil && *s.FingerprintHash == "device-hash"
+			})).Return(nil)
+
+			credits := NewCreditsServiceForTest(nil, nil, creditsRepo, nil, nil)
+			guard := NewSignupGuardForTest(nil, signalRepo, credits, nil, func() time.Time { return now })
+			admission := guard.Admit("password", SignupSignals{IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0", FingerprintHash: "device-hash"})
+
+			require.NoError(t, admission.InitializeCreditsWithTx(nil, userID, models.TierFree))
+			require.NotNil(t, admission.Assessment)
+			creditsRepo.AssertExpectations(t)
+			signalRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSignupGuard_Review(t *testing.T) {
+	now := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	t.Run("releases the withheld credits", func(t *testing.T) {
+		signalRepo := new(repomocks.MockSignupSignalRepository)
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		txRepo := new(repomocks.MockCreditTransactionRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		txRunner := new(mockTxRunner)
+
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		signalRepo.On("FindByUserID", mock.Anything, userID).
+			Return(&models.SignupSignal{UserID: userID, Verdict: models.SignupReview, CreditsWithheld: 50}, nil)
+		signalRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *models.SignupSignal) bool {
+			return s.CreditsGranted && *s.ReviewedBy == adminID && s.ReviewedAt.Equal(now)
+		})).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool {
+			return c.Balance == 50
+		})).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == 50 && tx.Type == models.TransactionCredit
+		})).Return(nil)
+		auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditLog) bool {
+			return e.Action == AuditActionSignupReviewed && e.Actor == adminID.String()
+		})).Return(nil)
+
+		credits := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
+		guard := NewSignupGuardForTest(nil, signalRepo, credits, NewAuditServiceForTest(nil, auditRepo), func() time.Time { return now })
+		signal, err := guard.Review(userID, adminID, true)
+
+		require.NoError(t, err)
+		assert.True(t, signal.CreditsGranted)
+		signalRepo.AssertExpectations(t)
+		creditsRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		reviewedAt := now.Add(-time.Hour)
+		signalRepo := new(repomocks.MockSignupSignalRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		signalRepo.On("FindByUserID", mock.Anything, userID).
+			Return(&models.SignupSignal{UserID: userID, Verdict: models.SignupReduced, ReviewedAt: &reviewedAt}, nil)
+
+		credits := NewCreditsServiceForTest(nil, txRunner, nil, nil, nil)
+		guard := NewSignupGuardForTest(nil, signalRepo, credits, nil, func() time.Time { return now })
+		_, err := guard.Review(userID, adminID, true)
+
+		assert.ErrorIs(t, err, ErrSignupNotPendingReview)
+		signalRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
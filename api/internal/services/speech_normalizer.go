@@ -0,0 +1,238 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used for threads that haven't set one
+const DefaultLocale = "en-US"
+
+// MaxLocaleLength bounds the BCP 47 tag accepted from the client
+const MaxLocaleLength = 35
+
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(?:[-_][A-Za-z0-9]{2,8})*$`)
+
+// ValidLocale reports whether locale looks like a BCP 47 tag such as "es-MX"
+func ValidLocale(locale string) bool {
+	return len(locale) <= MaxLocaleLength && localePattern.MatchString(locale)
+}
+
+// SpeechNormalizer rewrites text the way it should be read aloud, spelling
+// out numbers, dates, fractions and amounts of money so TTS doesn't have to
+// guess how "3/4" or "2024" is pronounced.
+type SpeechNormalizer interface {
+	Normalize(text string) string
+}
+
+// speechNormalizers builds the normalizer for a language from the locale's
+// region (uppercase, possibly empty). Adding a language means adding an entry.
+var speechNormalizers = map[string]func(region string) SpeechNormalizer{
+	"en": newEnglishNormalizer,
+	"es": newSpanishNormalizer,
+}
+
+// SpeechNormalizerFor returns the normalizer for a locale such as "es-MX".
+// Languages without one get their text back unchanged.
+func SpeechNormalizerFor(locale string) SpeechNormalizer {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return passthroughNormalizer{}
+	}
+
+	newNormalizer, ok := speechNormalizers[strings.ToLower(parts[0])]
+	if !ok {
+		return passthroughNormalizer{}
+	}
+	region := ""
+	if len(parts) > 1 {
+		region = strings.ToUpper(parts[len(parts)-1])
+	}
+	return newNormalizer(region)
+}
+
+type passthroughNormalizer struct{}
+
+func (passthroughNormalizer) Normalize(text string) string { return text }
+
+// currencyNames are the spoken names of a currency's units
+type currencyNames struct {
+	one, many           string
+	minorOne, minorMany string
+}
+
+// Patterns shared by every language. Numbers inside them are plain digits;
+// grouped and decimal numbers use the language's separators (see numberRules).
+var (
+	isoDatePattern   = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	slashDatePattern = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(\d{4}|\d{2})\b`)
+	fractionPattern  = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})\b`)
+)
+
+// numberRules are the patterns for numbers written with one convention of
+// thousands and decimal separators
+type numberRules struct {
+	groupSep, decimalSep string
+
+	number      *regexp.Regexp
+	moneyPrefix *regexp.Regexp
+	moneySuffix *regexp.Regexp
+	percent     *regexp.Regexp
+}
+
+func newNumberRules(groupSep, decimalSep string) *numberRules {
+	g, d := regexp.QuoteMeta(groupSep), regexp.QuoteMeta(decimalSep)
+	number := `\d{1,3}(?:` + g + `\d{3})+(?:` + d + `\d+)?|\d+(?:` + d + `\d+)?`
+	return &numberRules{
+		groupSep:    groupSep,
+		decimalSep:  decimalSep,
+		number:      regexp.MustCompile(`\b(?:` + number + `)\b`),
+		moneyPrefix: regexp.MustCompile(`([$€£])\s?(` + number + `)\b`),
+		moneySuffix: regexp.MustCompile(`\b(` + number + `)\s?([$€£])`),
+		percent:     regexp.MustCompile(`\b(` + number + `)\s?%`),
+	}
+}
+
+var (
+	dotDecimalNumbers   = newNumberRules(",", ".")
+	commaDecimalNumbers = newNumberRules(".", ",")
+)
+
+// speechRules is a SpeechNormalizer assembled from one language's spelling
+// functions. Rewrites run from most to least specific, so "$3.50" is read as
+// money before its digits would be read as a plain number.
+type speechRules struct {
+	numbers    *numberRules
+	currencies map[string]currencyNames
+	percent    string
+
+	cardinal func(n int64) string
+	decimal  func(whole int64, fraction string) string
+	year     func(n int64) string
+	date     func(day, month int, year int64) string
+	money    func(major, minor int64, names currencyNames) string
+
+	// fraction returns "" for fractions it doesn't read aloud
+	fraction func(numerator, denominator int64) string
+
+	// ordinal spells the first capture group; the whole match is replaced
+	ordinalPattern *regexp.Regexp
+	ordinal        func(n int64, marker string) string
+
+	dayFirst bool
+}
+
+func (r *speechRules) Normalize(text string) string {
+	text = isoDatePattern.ReplaceAllStringFunc(text, func(m string) string {
+		g := isoDatePattern.FindStringSubmatch(m)
+		return r.spellDate(m, g[3], g[2], g[1])
+	})
+	text = slashDatePattern.ReplaceAllStringFunc(text, func(m string) string {
+		g := slashDatePattern.FindStringSubmatch(m)
+		day, month := g[1], g[2]
+		if !r.dayFirst {
+			day, month = month, day
+		}
+		year := g[3]
+		if len(year) == 2 {
+			year = "20" + year
+		}
+		return r.spellDate(m, day, month, year)
+	})
+	text = r.numbers.moneyPrefix.ReplaceAllStringFunc(text, func(m string) string {
+		g := r.numbers.moneyPrefix.FindStringSubmatch(m)
+		return r.spellMoney(m, g[1], g[2])
+	})
+	text = r.numbers.moneySuffix.ReplaceAllStringFunc(text, func(m string) string {
+		g := r.numbers.moneySuffix.FindStringSubmatch(m)
+		return r.spellMoney(m, g[2], g[1])
+	})
+	text = r.numbers.percent.ReplaceAllStringFunc(text, func(m string) string {
+		g := r.numbers.percent.FindStringSubmatch(m)
+		return r.spellNumber(g[1]) + " " + r.percent
+	})
+	text = fractionPattern.ReplaceAllStringFunc(text, func(m string) string {
+		g := fractionPattern.FindStringSubmatch(m)
+		numerator, _ := strconv.ParseInt(g[1], 10, 64)
+		denominator, _ := strconv.ParseInt(g[2], 10, 64)
+		if spoken := r.fraction(numerator, denominator); spoken != "" {
+			return spoken
+		}
+		return m
+	})
+	text = r.ordinalPattern.ReplaceAllStringFunc(text, func(m string) string {
+		g := r.ordinalPattern.FindStringSubmatch(m)
+		n, err := strconv.ParseInt(g[1], 10, 64)
+		if err != nil {
+			return m
+		}
+		return r.ordinal(n, g[2])
+	})
+	return r.numbers.number.ReplaceAllStringFunc(text, r.spellNumber)
+}
+
+// spellNumber reads a number written with the language's separators. Plain
+// four-digit numbers go through year, which some languages read differently.
+func (r *speechRules) spellNumber(s string) string {
+	whole, fraction, _ := strings.Cut(s, r.numbers.decimalSep)
+	grouped := strings.Contains(whole, r.numbers.groupSep)
+	n, err := strconv.ParseInt(strings.ReplaceAll(whole, r.numbers.groupSep, ""), 10, 64)
+	if err != nil {
+		return s
+	}
+
+	switch {
+	case fraction != "":
+		return r.decimal(n, fraction)
+	case !grouped && len(whole) == 4 && r.year != nil:
+		return r.year(n)
+	default:
+		return r.cardinal(n)
+	}
+}
+
+func (r *speechRules) spellDate(original, day, month, year string) string {
+	d, _ := strconv.Atoi(day)
+	m, _ := strconv.Atoi(month)
+	y, _ := strconv.ParseInt(year, 10, 64)
+	if d < 1 || d > 31 || m < 1 || m > 12 {
+		return original
+	}
+	return r.date(d, m, y)
+}
+
+func (r *speechRules) spellMoney(original, symbol, amount string) string {
+	names, ok := r.currencies[symbol]
+	if !ok {
+		return original
+	}
+
+	whole, fraction, _ := strings.Cut(amount, r.numbers.decimalSep)
+	major, err := strconv.ParseInt(strings.ReplaceAll(whole, r.numbers.groupSep, ""), 10, 64)
+	if err != nil {
+		return original
+	}
+	switch len(fraction) {
+	case 0:
+		return r.money(major, 0, names)
+	case 2:
+		minor, _ := strconv.ParseInt(fraction, 10, 64)
+		return r.money(major, minor, names)
+	default:
+		// Not a price ("$1.5 million"); read the number and the plural unit
+		return r.decimal(major, fraction) + " " + names.many
+	}
+}
+
+// spellDigits reads each digit separately, for the digits after a decimal point
+func spellDigits(digits string, cardinal func(int64) string) string {
+	words := make([]string, 0, len(digits))
+	for _, d := range digits {
+		words = append(words, cardinal(int64(d-'0')))
+	}
+	return strings.Join(words, " ")
+}
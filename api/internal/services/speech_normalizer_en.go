@@ -0,0 +1,180 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	englishOnes = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	englishTens = []string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+	}
+	englishScales = []struct {
+		value int64
+		name  string
+	}{
+		{1_000_000_000_000, "trillion"},
+		{1_000_000_000, "billion"},
+		{1_000_000, "million"},
+		{1_000, "thousand"},
+	}
+	englishMonths = []string{
+		"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December",
+	}
+	englishIrregularOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+
+	englishOrdinalPattern = regexp.MustCompile(`(?i)\b(\d+)(st|nd|rd|th)\b`)
+)
+
+func newEnglishNormalizer(region string) SpeechNormalizer {
+	monthFirst := region == "US" || region == ""
+	return &speechRules{
+		numbers: dotDecimalNumbers,
+		currencies: map[string]currencyNames{
+			"$": {"dollar", "dollars", "cent", "cents"},
+			"€": {"euro", "euros", "cent", "cents"},
+			"£": {"pound", "pounds", "penny", "pence"},
+		},
+		percent:        "percent",
+		cardinal:       englishCardinal,
+		decimal:        englishDecimal,
+		year:           englishYear,
+		date:           englishDate(monthFirst),
+		money:          englishMoney,
+		fraction:       englishFraction,
+		ordinalPattern: englishOrdinalPattern,
+		ordinal:        func(n int64, _ string) string { return englishOrdinal(n) },
+		dayFirst:       !monthFirst,
+	}
+}
+
+func englishCardinal(n int64) string {
+	if n < 0 {
+		return "minus " + englishCardinal(-n)
+	}
+	if n < 20 {
+		return englishOnes[n]
+	}
+	if n < 100 {
+		if n%10 == 0 {
+			return englishTens[n/10]
+		}
+		return englishTens[n/10] + "-" + englishOnes[n%10]
+	}
+	if n < 1000 {
+		return joinNonZero(englishOnes[n/100]+" hundred", n%100, englishCardinal)
+	}
+	for _, scale := range englishScales {
+		if n >= scale.value {
+			return joinNonZero(englishCardinal(n/scale.value)+" "+scale.name, n%scale.value, englishCardinal)
+		}
+	}
+	return strconv.FormatInt(n, 10) // unreachable: n >= 1000 matches a scale
+}
+
+// englishYear reads four-digit numbers in pairs ("nineteen ninety-nine",
+// "twenty twenty-four"), except 2000-2009, which are said in full
+func englishYear(n int64) string {
+	if n < 1100 || n >= 2100 || (n >= 2000 && n < 2010) {
+		return englishCardinal(n)
+	}
+	century, rest := n/100, n%100
+	switch {
+	case rest == 0:
+		return englishCardinal(century) + " hundred"
+	case rest < 10:
+		return englishCardinal(century) + " oh " + englishCardinal(rest)
+	default:
+		return englishCardinal(century) + " " + englishCardinal(rest)
+	}
+}
+
+func englishOrdinal(n int64) string {
+	words := englishCardinal(n)
+	cut := strings.LastIndexAny(words, " -") + 1
+	last := words[cut:]
+	switch {
+	case englishIrregularOrdinals[last] != "":
+		last = englishIrregularOrdinals[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return words[:cut] + last
+}
+
+func englishDecimal(whole int64, fraction string) string {
+	return englishCardinal(whole) + " point " + spellDigits(fraction, englishCardinal)
+}
+
+func englishFraction(numerator, denominator int64) string {
+	if numerator < 1 || denominator < 2 || denominator > 10 || numerator >= denominator {
+		return ""
+	}
+
+	var unit string
+	switch denominator {
+	case 2:
+		unit = "half"
+	case 4:
+		unit = "quarter"
+	default:
+		unit = englishOrdinal(denominator)
+	}
+	if numerator > 1 {
+		if unit == "half" {
+			unit = "halves"
+		} else {
+			unit += "s"
+		}
+	}
+	return englishCardinal(numerator) + " " + unit
+}
+
+// englishDate reads dates the American way ("March fourth, twenty
+// twenty-four") or the British way ("the fourth of March, ...")
+func englishDate(monthFirst bool) func(day, month int, year int64) string {
+	return func(day, month int, year int64) string {
+		if monthFirst {
+			return englishMonths[month-1] + " " + englishOrdinal(int64(day)) + ", " + englishYear(year)
+		}
+		return "the " + englishOrdinal(int64(day)) + " of " + englishMonths[month-1] + ", " + englishYear(year)
+	}
+}
+
+func englishMoney(major, minor int64, names currencyNames) string {
+	var parts []string
+	if major > 0 || minor == 0 {
+		parts = append(parts, englishCardinal(major)+" "+pluralUnit(major, names.one, names.many))
+	}
+	if minor > 0 {
+		parts = append(parts, englishCardinal(minor)+" "+pluralUnit(minor, names.minorOne, names.minorMany))
+	}
+	return strings.Join(parts, " and ")
+}
+
+func pluralUnit(n int64, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// joinNonZero appends the spelled remainder, if any, to a spelled leading part
+func joinNonZero(lead string, rest int64, spell func(int64) string) string {
+	if rest == 0 {
+		return lead
+	}
+	return lead + " " + spell(rest)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	spanishUnits = []string{
+		"cero", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve",
+		"diez", "once", "doce", "trece", "catorce", "quince", "dieciséis", "diecisiete",
+		"dieciocho", "diecinueve", "veinte", "veintiuno", "veintidós", "veintitrés",
+		"veinticuatro", "veinticinco", "veintiséis", "veintisiete", "veintiocho", "veintinueve",
+	}
+	spanishTens = []string{
+		"", "", "", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa",
+	}
+	spanishHundreds = []string{
+		"", "ciento", "doscientos", "trescientos", "cuatrocientos", "quinientos",
+		"seiscientos", "setecientos", "ochocientos", "novecientos",
+	}
+	spanishMonths = []string{
+		"enero", "febrero", "marzo", "abril", "mayo", "junio",
+		"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+	}
+	spanishOrdinals = []string{
+		"", "primero", "segundo", "tercero", "cuarto", "quinto",
+		"sexto", "séptimo", "octavo", "noveno", "décimo",
+	}
+
+	// "1.º", "2ª"; "°" is a common stand-in for "º"
+	spanishOrdinalPattern = regexp.MustCompile(`\b(\d+)\.?([ºª°])`)
+
+	// Countries where "$" is the peso rather than the dollar
+	pesoRegions = map[string]bool{
+		"MX": true, "AR": true, "CO": true, "CL": true, "UY": true, "DO": true, "CU": true,
+	}
+)
+
+func newSpanishNormalizer(region string) SpeechNormalizer {
+	dollar := currencyNames{"dólar", "dólares", "centavo", "centavos"}
+	if pesoRegions[region] {
+		dollar = currencyNames{"peso", "pesos", "centavo", "centavos"}
+	}
+
+	return &speechRules{
+		numbers: commaDecimalNumbers,
+		currencies: map[string]currencyNames{
+			"$": dollar,
+			"€": {"euro", "euros", "céntimo", "céntimos"},
+		},
+		percent:        "por ciento",
+		cardinal:       spanishCardinal,
+		decimal:        spanishDecimal,
+		date:           spanishDate,
+		money:          spanishMoney,
+		fraction:       spanishFraction,
+		ordinalPattern: spanishOrdinalPattern,
+		ordinal:        spanishOrdinal,
+		dayFirst:       true,
+	}
+}
+
+func spanishCardinal(n int64) string {
+	switch {
+	case n < 0:
+		return "menos " + spanishCardinal(-n)
+	case n < 30:
+		return spanishUnits[n]
+	case n < 100:
+		if n%10 == 0 {
+			return spanishTens[n/10]
+		}
+		return spanishTens[n/10] + " y " + spanishUnits[n%10]
+	case n == 100:
+		return "cien"
+	case n < 1000:
+		return joinNonZero(spanishHundreds[n/100], n%100, spanishCardinal)
+	case n < 1_000_000:
+		thousands := "mil"
+		if n/1000 > 1 {
+			thousands = spanishApocope(spanishCardinal(n/1000)) + " mil"
+		}
+		return joinNonZero(thousands, n%1000, spanishCardinal)
+	case n < 1_000_000_000_000:
+		return joinNonZero(spanishScale(n/1_000_000, "millón", "millones"), n%1_000_000, spanishCardinal)
+	case n < 1_000_000_000_000_000_000:
+		return joinNonZero(spanishScale(n/1_000_000_000_000, "billón", "billones"), n%1_000_000_000_000, spanishCardinal)
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
+
+func spanishScale(count int64, one, many string) string {
+	if count == 1 {
+		return "un " + one
+	}
+	return spanishApocope(spanishCardinal(count)) + " " + many
+}
+
+// spanishApocope shortens a trailing "uno" before a noun: "un euro",
+// "veintiún dólares", "treinta y un mil"
+func spanishApocope(words string) string {
+	switch {
+	case strings.HasSuffix(words, "veintiuno"):
+		return strings.TrimSuffix(words, "uno") + "ún"
+	case words == "uno" || strings.HasSuffix(words, " uno"):
+		return strings.TrimSuffix(words, "o")
+	default:
+		return words
+	}
+}
+
+// spanishDecimal reads the digits after the comma as a number ("tres coma
+// catorce"), keeping any leading zeros ("cero coma cero cinco")
+func spanishDecimal(whole int64, fraction string) string {
+	trimmed := strings.TrimLeft(fraction, "0")
+	words := []string{spanishCardinal(whole), "coma"}
+	for range len(fraction) - len(trimmed) {
+		words = append(words, "cero")
+	}
+	if trimmed != "" {
+		n, err := strconv.ParseInt(trimmed, 10, 64)
+		if err != nil {
+			return spanishCardinal(whole) + " coma " + spellDigits(fraction, spanishCardinal)
+		}
+		words = append(words, spanishCardinal(n))
+	}
+	return strings.Join(words, " ")
+}
+
+func spanishOrdinal(n int64, marker string) string {
+	if n < 1 || n >= int64(len(spanishOrdinals)) {
+		return spanishCardinal(n)
+	}
+	ordinal := spanishOrdinals[n]
+	if marker == "ª" {
+		return strings.TrimSuffix(ordinal, "o") + "a"
+	}
+	return ordinal
+}
+
+func spanishFraction(numerator, denominator int64) string {
+	if numerator < 1 || denominator < 2 || denominator > 10 || numerator >= denominator {
+		return ""
+	}
+
+	var unit string
+	switch denominator {
+	case 2:
+		unit = "medio"
+	case 3:
+		unit = "tercio"
+	default:
+		unit = spanishOrdinals[denominator]
+	}
+	if numerator > 1 {
+		unit += "s"
+	}
+	return spanishApocope(spanishCardinal(numerator)) + " " + unit
+}
+
+func spanishDate(day, month int, year int64) string {
+	return spanishCardinal(int64(day)) + " de " + spanishMonths[month-1] + " de " + spanishCardinal(year)
+}
+
+func spanishMoney(major, minor int64, names currencyNames) string {
+	var parts []string
+	if major > 0 || minor == 0 {
+		amount := spanishApocope(spanishCardinal(major))
+		// "un millón de euros"
+		if strings.HasSuffix(amount, "llón") || strings.HasSuffix(amount, "llones") {
+			amount += " de"
+		}
+		parts = append(parts, amount+" "+pluralUnit(major, names.one, names.many))
+	}
+	if minor > 0 {
+		parts = append(parts, spanishApocope(spanishCardinal(minor))+" "+pluralUnit(minor, names.minorOne, names.minorMany))
+	}
+	return strings.Join(parts, " con ")
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeechNormalizer_English(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		text   string
+		want   string
+	}{
+		{name: "fraction", locale: "en-US", text: "Add 3/4 of a cup.", want: "Add three quarters of a cup."},
+		{name: "half", locale: "en-US", text: "About 1/2 an hour", want: "About one half an hour"},
+		{name: "year", locale: "en-US", text: "It was 2024.", want: "It was twenty twenty-four."},
+		{name: "early 2000s", locale: "en-US", text: "in 2005", want: "in two thousand five"},
+		{name: "year with oh", locale: "en-US", text: "since 1905", want: "since nineteen oh five"},
+		{name: "cardinal", locale: "en-US", text: "I have 42 cats", want: "I have forty-two cats"},
+		{name: "grouped", locale: "en-US", text: "1,250 people", want: "one thousand two hundred fifty people"},
+		{name: "decimal", locale: "en-US", text: "pi is 3.14", want: "pi is three point one four"},
+		{name: "dollars and cents", locale: "en-US", text: "It costs $5.50.", want: "It costs five dollars and fifty cents."},
+		{name: "one pound", locale: "en-GB", text: "£1", want: "one pound"},
+		{name: "cents only", locale: "en-US", text: "$0.99", want: "ninety-nine cents"},
+		{name: "percent", locale: "en-US", text: "50% off", want: "fifty percent off"},
+		{name: "ordinal", locale: "en-US", text: "the 21st century", want: "the twenty-first century"},
+		{name: "US date", locale: "en-US", text: "on 3/4/2024", want: "on March fourth, twenty twenty-four"},
+		{name: "UK date", locale: "en-GB", text: "on 3/4/2024", want: "on the third of April, twenty twenty-four"},
+		{name: "ISO date", locale: "en", text: "2024-12-25", want: "December twenty-fifth, twenty twenty-four"},
+		{name: "invalid date not read as a date", locale: "en-US", text: "13/45/2024", want: "thirteen/forty-five/twenty twenty-four"},
+		{name: "digits inside words left alone", locale: "en-US", text: "an mp3 file", want: "an mp3 file"},
+		{name: "no numbers", locale: "en-US", text: "Hello there!", want: "Hello there!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SpeechNormalizerFor(tt.locale).Normalize(tt.text))
+		})
+	}
+}
+
+func TestSpeechNormalizer_Spanish(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		text   string
+		want   string
+	}{
+		{name: "fraction", locale: "es-ES", text: "3/4 de taza", want: "tres cuartos de taza"},
+		{name: "one third", locale: "es-ES", text: "1/3", want: "un tercio"},
+		{name: "year", locale: "es-ES", text: "en 2024", want: "en dos mil veinticuatro"},
+		{name: "compound", locale: "es-ES", text: "son 31", want: "son treinta y uno"},
+		{name: "hundreds", locale: "es-ES", text: "100 y 115", want: "cien y ciento quince"},
+		{name: "grouped thousands", locale: "es-ES", text: "21.000 personas", want: "veintiún mil personas"},
+		{name: "decimal", locale: "es-ES", text: "3,05 metros", want: "tres coma cero cinco metros"},
+		{name: "euros", locale: "es-ES", text: "Cuesta 21,50 €", want: "Cuesta veintiún euros con cincuenta céntimos"},
+		{name: "one million", locale: "es-ES", text: "1.000.000 €", want: "un millón de euros"},
+		{name: "pesos in Mexico", locale: "es-MX", text: "$1", want: "un peso"},
+		{name: "dollars elsewhere", locale: "es-US", text: "$2", want: "dos dólares"},
+		{name: "percent", locale: "es", text: "un 20 %", want: "un veinte por ciento"},
+		{name: "ordinal", locale: "es-ES", text: "la 1ª vez, el 2.º piso", want: "la primera vez, el segundo piso"},
+		{name: "date", locale: "es-MX", text: "el 3/4/2024", want: "el tres de abril de dos mil veinticuatro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SpeechNormalizerFor(tt.locale).Normalize(tt.text))
+		})
+	}
+}
+
+func TestSpeechNormalizerFor_UnsupportedLanguage(t *testing.T) {
+	assert.Equal(t, "Ich habe 3 Katzen", SpeechNormalizerFor("de-DE").Normalize("Ich habe 3 Katzen"))
+}
+
+func TestValidLocale(t *testing.T) {
+	assert.True(t, ValidLocale("en-US"))
+	assert.True(t, ValidLocale("es_419"))
+	assert.True(t, ValidLocale("zh-Hant-TW"))
+	assert.False(t, ValidLocale(""))
+	assert.False(t, ValidLocale("english"))
+	assert.False(t, ValidLocale("en US"))
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrBadgeNotFound = errors.New("badge not found")
+
+// maxBadgeStreakDays bounds how far back the streak query looks
+const maxBadgeStreakDays = 1000
+
+// BadgeManager defines the interface for public stats badge operations
+type BadgeManager interface {
+	GetBadge(userID uuid.UUID) (*models.StatsBadge, error)
+	EnableBadge(userID uuid.UUID) (*models.StatsBadge, error)
+	RevokeBadge(userID uuid.UUID) error
+	PublicStats(token string) (*BadgeStats, error)
+}
+
+// BadgeStats is everything a public badge shows. It is deliberately
+// aggregate-only: no name, email or conversation content.
+type BadgeStats struct {
+	StreakDays        int       `json:"streakDays"`
+	Accuracy          *float64  `json:"accuracy"` // Rounded percentage; nil before any analysis
+	PhonemesPracticed int       `json:"phonemesPracticed"`
+	GeneratedAt       time.Time `json:"generatedAt"`
+}
+
+// StatsBadgeService issues badge tokens and computes the stats behind them
+type StatsBadgeService struct {
+	exec        repository.Executor
+	badgeRepo   repository.StatsBadgeRepository
+	messageRepo repository.MessageRepository
+	statsRepo   repository.PhonemeStatsRepository
+
+	// Timezones starts each user's day at their local midnight; nil uses UTC
+	Timezones UserTimezones
+
+	now func() time.Time
+}
+
+// NewStatsBadgeService creates a new stats badge service
+func NewStatsBadgeService(
+	database *db.DB,
+	badgeRepo repository.StatsBadgeRepository,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+) *StatsBadgeService {
+	return &StatsBadgeService{
+		exec:        database.DB,
+		badgeRepo:   badgeRepo,
+		messageRepo: messageRepo,
+		statsRepo:   statsRepo,
+		now:         time.Now,
+	}
+}
+
+// NewStatsBadgeServiceForTest creates a StatsBadgeService with injected dependencies for testing.
+func NewStatsBadgeServiceForTest(
+	exec repository.Executor,
+	badgeRepo repository.StatsBadgeRepository,
+	messageRepo repository.MessageRepository,
+	statsRepo repository.PhonemeStatsRepository,
+) *StatsBadgeService {
+	return &StatsBadgeService{
+		exec:        exec,
+		badgeRepo:   badgeRepo,
+		messageRepo: messageRepo,
+		statsRepo:   statsRepo,
+		now:         time.Now,
+	}
+}
+
+// GetBadge returns the user's badge, or ErrBadgeNotFound if they haven't opted in
+func (s *StatsBadgeService) GetBadge(userID uuid.UUID) (*models.StatsBadge, error) {
+	badge, err := s.badgeRepo.FindByUserID(s.exec, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrBadgeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find badge: %w", err)
+	}
+	return badge, nil
+}
+
+// EnableBadge issues a new badge token. Calling it again rotates the token,
+// which breaks every embed of the old one.
+func (s *StatsBadgeService) EnableBadge(userID uuid.UUID) (*models.StatsBadge, error) {
+	token, err := newBadgeToken()
+	if err != nil {
+		return nil, err
+	}
+
+	badge := &models.StatsBadge{
+		UserID:    userID,
+		Token:     token,
+		CreatedAt: s.now(),
+	}
+	if err := s.badgeRepo.Upsert(s.exec, badge); err != nil {
+		return nil, fmt.Errorf("save badge: %w", err)
+	}
+	return badge, nil
+}
+
+// RevokeBadge turns the public badge off
+func (s *StatsBadgeService) RevokeBadge(userID uuid.UUID) error {
+	if err := s.badgeRepo.DeleteByUserID(s.exec, userID); err != nil {
+		return fmt.Errorf("delete badge: %w", err)
+	}
+	return nil
+}
+
+// PublicStats looks up a badge by token and computes its stats
+func (s *StatsBadgeService) PublicStats(token string) (*BadgeStats, error) {
+	badge, err := s.badgeRepo.FindByToken(s.exec, token)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrBadgeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find badge: %w", err)
+	}
+
+	now := s.now().In(userLocation(s.Timezones, badge.UserID))
+	days, err := s.messageRepo.FindActiveDaysByUserID(s.exec, badge.UserID, now.AddDate(0, 0, -maxBadgeStreakDays), now.Location())
+	if err != nil {
+		return nil, fmt.Errorf("find active days: %w", err)
+	}
+
+	stats, err := s.statsRepo.FindByUserID(s.exec, badge.UserID, DefaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("find phoneme stats: %w", err)
+	}
+	var attempts, correct int
+	for _, st := range stats {
+		attempts += st.TotalAttempts
+		correct += st.CorrectCount
+	}
+
+	result := &BadgeStats{
+		StreakDays:        currentStreak(days, now),
+		PhonemesPracticed: attempts,
+		GeneratedAt:       now.UTC(),
+	}
+	if attempts > 0 {
+		accuracy := math.Round(float64(correct) / float64(attempts) * 100)
+		result.Accuracy = &accuracy
+	}
+	return result, nil
+}
+
+// currentStreak counts consecutive active days ending today, or yesterday
+// so a streak isn't shown as broken before the user has practiced today.
+// days must be distinct dates, most recent first, in now's timezone.
+func currentStreak(days []time.Time, now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if len(days) == 0 {
+		return 0
+	}
+
+	expected := today
+	if !sameDay(days[0], today) {
+		expected = today.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for _, day := range days {
+		if !sameDay(day, expected) {
+			break
+		}
+		streak++
+		expected = expected.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// dayNumber counts the calendar days from the Unix epoch to t's date in t's
+// own timezone, for things that rotate daily
+func dayNumber(t time.Time) int {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Unix() / int64(24*time.Hour/time.Second))
+}
+
+func newBadgeToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate badge token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Summary is the badge's one-line text, e.g. "120-day streak · 92% accuracy"
+func (b *BadgeStats) Summary() string {
+	streak := fmt.Sprintf("%d-day streak", b.StreakDays)
+	if b.Accuracy == nil {
+		return streak
+	}
+	return fmt.Sprintf("%s · %.0f%% accuracy", streak, *b.Accuracy)
+}
+
+// badgeCharWidth approximates Verdana 11px, which the badge is drawn in
+const badgeCharWidth = 6.5
+
+// SVG renders the badge in the flat two-part style READMEs and blogs use
+func (b *BadgeStats) SVG() string {
+	label, value := "Ling App", b.Summary()
+	labelWidth := badgeTextWidth(label)
+	valueWidth := badgeTextWidth(value)
+	width := labelWidth + valueWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+
+		`<title>%s: %s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%d" y="14">%s</text><text x="%d" y="14">%s</text></g></svg>`,
+		width, html.EscapeString(label), html.EscapeString(value),
+		html.EscapeString(label), html.EscapeString(value),
+		width,
+		labelWidth, labelWidth, valueWidth, b.color(), width,
+		labelWidth/2, html.EscapeString(label), labelWidth+valueWidth/2, html.EscapeString(value),
+	)
+}
+
+// color follows the accuracy bands of the pronunciation dashboard
+func (b *BadgeStats) color() string {
+	if b.Accuracy == nil {
+		return "#6b7280"
+	}
+	return accuracyColor(*b.Accuracy)
+}
+
+// accuracyColor is the band color for an accuracy percentage
+func accuracyColor(accuracy float64) string {
+	switch {
+	case accuracy >= 80:
+		return "#10b981"
+	case accuracy >= 60:
+		return "#eab308"
+	case accuracy >= 40:
+		return "#f97316"
+	default:
+		return "#ef4444"
+	}
+}
+
+func badgeTextWidth(s string) int {
+	return int(math.Ceil(float64(len([]rune(s)))*badgeCharWidth)) + 20
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func utcDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCurrentStreak(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		days []time.Time
+		want int
+	}{
+		{name: "no activity", days: nil, want: 0},
+		{name: "today only", days: []time.Time{utcDay(2024, 3, 10)}, want: 1},
+		{name: "through today", days: []time.Time{utcDay(2024, 3, 10), utcDay(2024, 3, 9), utcDay(2024, 3, 8)}, want: 3},
+		{name: "not yet practiced today", days: []time.Time{utcDay(2024, 3, 9), utcDay(2024, 3, 8)}, want: 2},
+		{name: "gap breaks streak", days: []time.Time{utcDay(2024, 3, 10), utcDay(2024, 3, 8), utcDay(2024, 3, 7)}, want: 1},
+		{name: "lapsed", days: []time.Time{utcDay(2024, 3, 8), utcDay(2024, 3, 7)}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, currentStreak(tt.days, now))
+		})
+	}
+
+	t.Run("across month end", func(t *testing.T) {
+		days := []time.Time{utcDay(2024, 3, 1), utcDay(2024, 2, 29)}
+		assert.Equal(t, 2, currentStreak(days, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)))
+	})
+}
+
+func TestStatsBadgeService_PublicStats(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	badgeRepo := new(repomocks.MockStatsBadgeRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+
+	badgeRepo.On("FindByToken", mock.Anything, "tok").Return(&models.StatsBadge{UserID: userID, Token: "tok"}, nil)
+	messageRepo.On("FindActiveDaysByUserID", mock.Anything, userID, now.AddDate(0, 0, -maxBadgeStreakDays), time.UTC).
+		Return([]time.Time{utcDay(2024, 3, 9), utcDay(2024, 3, 8)}, nil)
+	statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return([]models.PhonemeStats{
+		{TotalAttempts: 40, CorrectCount: 37},
+		{TotalAttempts: 10, CorrectCount: 9},
+	}, nil)
+
+	svc := NewStatsBadgeServiceForTest(nil, badgeRepo, messageRepo, statsRepo)
+	svc.now = func() time.Time { return now }
+	stats, err := svc.PublicStats("tok")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.StreakDays)
+	require.NotNil(t, stats.Accuracy)
+	assert.Equal(t, 92.0, *stats.Accuracy)
+	assert.Equal(t, 50, stats.PhonemesPracticed)
+	assert.Equal(t, "2-day streak · 92% accuracy", stats.Summary())
+}
+
+func TestStatsBadgeService_PublicStats_Revoked(t *testing.T) {
+	badgeRepo := new(repomocks.MockStatsBadgeRepository)
+	badgeRepo.On("FindByToken", mock.Anything, "old").Return(nil, repository.ErrNotFound)
+
+	svc := NewStatsBadgeServiceForTest(nil, badgeRepo, nil, nil)
+	_, err := svc.PublicStats("old")
+
+	assert.ErrorIs(t, err, ErrBadgeNotFound)
+}
+
+func TestStatsBadgeService_EnableBadge_RotatesToken(t *testing.T) {
+	userID := uuid.New()
+	badgeRepo := new(repomocks.MockStatsBadgeRepository)
+	badgeRepo.On("Upsert", mock.Anything, mock.AnythingOfType("*models.StatsBadge")).Return(nil)
+
+	svc := NewStatsBadgeServiceForTest(nil, badgeRepo, nil, nil)
+	first, err := svc.EnableBadge(userID)
+	require.NoError(t, err)
+	second, err := svc.EnableBadge(userID)
+	require.NoError(t, err)
+
+	assert.Len(t, first.Token, 32)
+	assert.NotEqual(t, first.Token, second.Token)
+}
+
+func TestBadgeStats_SVG(t *testing.T) {
+	accuracy := 92.0
+	svg := (&BadgeStats{StreakDays: 120, Accuracy: &accuracy}).SVG()
+
+	assert.Contains(t, svg, "<svg")
+	assert.Contains(t, svg, "120-day streak · 92% accuracy")
+	assert.Contains(t, svg, "#10b981")
+
+	noAnalysis := (&BadgeStats{StreakDays: 3}).SVG()
+	assert.Contains(t, noAnalysis, ">3-day streak<")
+}
+
+func TestStatsBadgeService_PublicStats_UserTimezone(t *testing.T) {
+	userID := uuid.New()
+	// 23:00 UTC on the 10th is already the 11th in Tokyo
+	now := time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	badgeRepo := new(repomocks.MockStatsBadgeRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+	settingsRepo := new(repomocks.MockUserSettingsRepository)
+	badgeRepo.On("FindByToken", mock.Anything, "tok").Return(&models.StatsBadge{UserID: userID, Token: "tok"}, nil)
+	settingsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.UserSettings{UserID: userID, Timezone: "Asia/Tokyo"}, nil)
+	messageRepo.On("FindActiveDaysByUserID", mock.Anything, userID, mock.Anything, tokyo).
+		Return([]time.Time{utcDay(2024, 3, 11), utcDay(2024, 3, 10)}, nil)
+	statsRepo.On("FindByUserID", mock.Anything, userID, "en").Return([]models.PhonemeStats{}, nil)
+
+	svc := NewStatsBadgeServiceForTest(nil, badgeRepo, messageRepo, statsRepo)
+	svc.Timezones = NewSettingsServiceForTest(nil, settingsRepo)
+	svc.now = func() time.Time { return now }
+	stats, err := svc.PublicStats("tok")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.StreakDays, "the local day has started, so today counts")
+	assert.Equal(t, now, stats.GeneratedAt)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// StreakMilestones are the streak lengths, in days, that earn a notification
+var StreakMilestones = []int{3, 7, 30, 100, 365}
+
+// StreakService keeps each user's daily practice streak up to date from the
+// messages they send, and congratulates them at each milestone
+type StreakService struct {
+	exec          repository.Executor
+	repo          repository.StreakRepository
+	notifications NotificationManager // nil = no milestone notifications
+
+	// Timezones starts each user's day at their local midnight; nil uses UTC
+	Timezones UserTimezones
+}
+
+// NewStreakService creates a new streak service
+func NewStreakService(database *db.DB, repo repository.StreakRepository, notifications NotificationManager) *StreakService {
+	return &StreakService{
+		exec:          database.DB,
+		repo:          repo,
+		notifications: notifications,
+	}
+}
+
+// NewStreakServiceForTest creates a StreakService with injected dependencies for testing.
+func NewStreakServiceForTest(exec repository.Executor, repo repository.StreakRepository, notifications NotificationManager) *StreakService {
+	return &StreakService{
+		exec:          exec,
+		repo:          repo,
+		notifications: notifications,
+	}
+}
+
+// Subscribe counts every processed message towards its user's streak
+func (s *StreakService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "streaks", func(ctx context.Context, e events.MessageProcessed) error {
+		return s.RecordActivity(e.UserID, e.At)
+	})
+}
+
+// RecordActivity counts the day of at, in the user's timezone, as active for
+// the user. Only the first message of a day moves the streak.
+func (s *StreakService) RecordActivity(userID uuid.UUID, at time.Time) error {
+	local := at.In(userLocation(s.Timezones, userID))
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	streak, advanced, err := s.repo.Advance(s.exec, userID, day)
+	if err != nil {
+		return fmt.Errorf("advance streak: %w", err)
+	}
+	if !advanced || s.notifications == nil || !slices.Contains(StreakMilestones, streak.CurrentDays) {
+		return nil
+	}
+
+	title := fmt.Sprintf("%d-day streak!", streak.CurrentDays)
+	body := fmt.Sprintf("You've practiced %d days in a row. Keep it going tomorrow.", streak.CurrentDays)
+	return s.notifications.Notify(userID, models.NotificationStreakMilestone, title, body, models.JSONMap{
+		"days": streak.CurrentDays,
+	})
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestStreakService_RecordActivity(t *testing.T) {
+	userID := uuid.New()
+	at := time.Date(2026, 3, 14, 21, 30, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	t.Run("notifies at a milestone", func(t *testing.T) {
+		repo := new(repomocks.MockStreakRepository)
+		notifications := new(stubNotifications)
+		repo.On("Advance", mock.Anything, userID, day).Return(&models.UserStreak{UserID: userID, CurrentDays: 7}, true, nil)
+		notifications.On("Notify", userID, models.NotificationStreakMilestone, "7-day streak!", mock.Anything, models.JSONMap{"days": 7}).Return(nil)
+
+		err := NewStreakServiceForTest(nil, repo, notifications).RecordActivity(userID, at)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		notifications.AssertExpectations(t)
+	})
+
+	t.Run("stays quiet between milestones", func(t *testing.T) {
+		repo := new(repomocks.MockStreakRepository)
+		notifications := new(stubNotifications)
+		repo.On("Advance", mock.Anything, userID, day).Return(&models.UserStreak{UserID: userID, CurrentDays: 8}, true, nil)
+
+		err := NewStreakServiceForTest(nil, repo, notifications).RecordActivity(userID, at)
+
+		assert.NoError(t, err)
+		notifications.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("only the first message of a day counts", func(t *testing.T) {
+		repo := new(repomocks.MockStreakRepository)
+		notifications := new(stubNotifications)
+		repo.On("Advance", mock.Anything, userID, day).Return(&models.UserStreak{UserID: userID, CurrentDays: 7}, false, nil)
+
+		err := NewStreakServiceForTest(nil, repo, notifications).RecordActivity(userID, at)
+
+		assert.NoError(t, err)
+		notifications.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("counts the day in the user's timezone", func(t *testing.T) {
+		repo := new(repomocks.MockStreakRepository)
+		settingsRepo := new(repomocks.MockUserSettingsRepository)
+		settingsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.UserSettings{UserID: userID, Timezone: "Asia/Tokyo"}, nil)
+		// 21:30 UTC is already 06:30 the next morning in Tokyo
+		repo.On("Advance", mock.Anything, userID, day.AddDate(0, 0, 1)).Return(&models.UserStreak{UserID: userID, CurrentDays: 2}, true, nil)
+
+		svc := NewStreakServiceForTest(nil, repo, nil)
+		svc.Timezones = NewSettingsServiceForTest(nil, settingsRepo)
+		err := svc.RecordActivity(userID, at)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		repo := new(repomocks.MockStreakRepository)
+		repo.On("Advance", mock.Anything, userID, day).Return(nil, false, errors.New("db down"))
+
+		err := NewStreakServiceForTest(nil, repo, nil).RecordActivity(userID, at)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestStreakService_Subscribe(t *testing.T) {
+	userID := uuid.New()
+	at := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	repo := new(repomocks.MockStreakRepository)
+	repo.On("Advance", mock.Anything, userID, time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)).
+		Return(&models.UserStreak{UserID: userID, CurrentDays: 1}, true, nil)
+
+	bus := events.NewBus()
+	NewStreakServiceForTest(nil, repo, nil).Subscribe(bus)
+	bus.Publish(context.Background(), events.MessageProcessed{UserID: userID, At: at})
+
+	repo.AssertExpectations(t)
+}
@@ -1,13 +1,18 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"ling-app/api/internal/analytics"
 	"ling-app/api/internal/config"
 	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
 
@@ -30,8 +35,9 @@ var (
 type StripeProcessor interface {
 	GetSubscription(userID uuid.UUID) (*models.Subscription, error)
 	GetOrCreateSubscription(userID uuid.UUID, email, name string) (*models.Subscription, error)
-	CreateCheckoutSession(userID uuid.UUID, email, name string, tier models.SubscriptionTier) (string, error)
+	CreateCheckoutSession(userID uuid.UUID, email, name string, tier models.SubscriptionTier, currency string) (string, error)
 	CreatePortalSession(userID uuid.UUID) (string, error)
+	PaymentBanner(sub *models.Subscription) *PaymentBanner
 	HandleWebhook(payload []byte, signature string) error
 }
 
@@ -42,6 +48,18 @@ type StripeService struct {
 	txRunner       TxRunner
 	subRepo        repository.SubscriptionRepository
 	creditsService *CreditsService
+	notifications  NotificationManager
+	tracker        analytics.Tracker
+
+	// Runtime supplies the tier allowances in force; nil uses the defaults
+	Runtime *RuntimeSettingsService
+
+	// Dunning reminds users whose renewal payment failed (optional)
+	Dunning *DunningService
+
+	// Events receives SubscriptionChanged when a tier or status changes
+	// (optional)
+	Events *events.Bus
 }
 
 func NewStripeService(
@@ -49,6 +67,8 @@ func NewStripeService(
 	database *db.DB,
 	subRepo repository.SubscriptionRepository,
 	creditsService *CreditsService,
+	notifications NotificationManager,
+	tracker analytics.Tracker,
 ) *StripeService {
 	stripe.Key = cfg.StripeSecretKey
 	return &StripeService{
@@ -58,6 +78,8 @@ func NewStripeService(
 		txRunner:       database.DB,
 		subRepo:        subRepo,
 		creditsService: creditsService,
+		notifications:  notifications,
+		tracker:        tracker,
 	}
 }
 
@@ -73,13 +95,15 @@ func (s *StripeService) GetSubscription(userID uuid.UUID) (*models.Subscription,
 	return sub, nil
 }
 
-// GetOrCreateSubscription returns existing or creates a new free subscription
+// GetOrCreateSubscription returns existing or creates a new free subscription.
+// A subscription an admin created for a tier override gets its Stripe
+// customer here.
 func (s *StripeService) GetOrCreateSubscription(userID uuid.UUID, email, name string) (*models.Subscription, error) {
 	sub, err := s.GetSubscription(userID)
-	if err == nil {
+	if err == nil && sub.StripeCustomerID != "" {
 		return sub, nil
 	}
-	if !errors.Is(err, ErrSubscriptionNotFound) {
+	if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
 		return nil, err
 	}
 
@@ -93,6 +117,14 @@ func (s *StripeService) GetOrCreateSubscription(userID uuid.UUID, email, name st
 		return nil, fmt.Errorf("create stripe customer: %w", err)
 	}
 
+	if sub != nil {
+		sub.StripeCustomerID = cust.ID
+		if err := s.subRepo.Save(s.exec, sub); err != nil {
+			return nil, fmt.Errorf("update subscription: %w", err)
+		}
+		return sub, nil
+	}
+
 	// Create subscription record with free tier
 	sub = &models.Subscription{
 		UserID:           userID,
@@ -107,32 +139,39 @@ func (s *StripeService) GetOrCreateSubscription(userID uuid.UUID, email, name st
 	return sub, nil
 }
 
-// CreateCheckoutSession creates a Stripe checkout URL for upgrading
-func (s *StripeService) CreateCheckoutSession(userID uuid.UUID, email, name string, tier models.SubscriptionTier) (string, error) {
+// CreateCheckoutSession creates a Stripe checkout URL for upgrading. The plan
+// is charged in currency, the one the pricing showed; empty charges the
+// default price.
+func (s *StripeService) CreateCheckoutSession(userID uuid.UUID, email, name string, tier models.SubscriptionTier, currency string) (string, error) {
+	if tier != models.TierBasic && tier != models.TierPro {
+		return "", fmt.Errorf("invalid tier: %s", tier)
+	}
 	sub, err := s.GetOrCreateSubscription(userID, email, name)
 	if err != nil {
 		return "", err
 	}
+	prices := NewPriceBook(s.config)
+	currency = strings.ToLower(strings.TrimSpace(currency))
 
-	var priceID string
-	switch tier {
-	case models.TierBasic:
-		priceID = s.config.StripePriceBasic
-	case models.TierPro:
-		priceID = s.config.StripePricePro
-	default:
-		return "", fmt.Errorf("invalid tier: %s", tier)
+	// If user already has an active subscription, update it instead of
+	// creating new. A subscription can't change currency, so it moves to the
+	// new tier's price in the currency it is already paid in.
+	if sub.StripeSubscriptionID != nil && *sub.StripeSubscriptionID != "" {
+		if sub.StripePriceID != nil {
+			_, currency, _ = prices.Lookup(*sub.StripePriceID)
+		}
+		priceID := prices.PriceID(tier, currency)
+		if priceID == "" {
+			return "", fmt.Errorf("price not configured for tier: %s", tier)
+		}
+		return s.updateExistingSubscription(sub, priceID, tier)
 	}
 
+	priceID := prices.PriceID(tier, currency)
 	if priceID == "" {
 		return "", fmt.Errorf("price not configured for tier: %s", tier)
 	}
 
-	// If user already has an active subscription, update it instead of creating new
-	if sub.StripeSubscriptionID != nil && *sub.StripeSubscriptionID != "" {
-		return s.updateExistingSubscription(sub, priceID, tier)
-	}
-
 	// Create new subscription via checkout
 	params := &stripe.CheckoutSessionParams{
 		Customer: stripe.String(sub.StripeCustomerID),
@@ -145,6 +184,20 @@ func (s *StripeService) CreateCheckoutSession(userID uuid.UUID, email, name stri
 		},
 		SuccessURL: stripe.String(s.config.StripeSuccessURL + "?session_id={CHECKOUT_SESSION_ID}"),
 		CancelURL:  stripe.String(s.config.StripeCancelURL),
+		// The billing country decides the tax, and is kept on the subscription
+		BillingAddressCollection: stripe.String(string(stripe.CheckoutSessionBillingAddressCollectionRequired)),
+	}
+	if currency != "" && !prices.Regional(currency) {
+		// Charged in one of the default price's currency options
+		params.Currency = stripe.String(currency)
+	}
+	if s.config.StripeTaxEnabled {
+		params.AutomaticTax = &stripe.CheckoutSessionAutomaticTaxParams{Enabled: stripe.Bool(true)}
+		// Stripe Tax reads the address from the customer, so checkout saves it there
+		params.CustomerUpdate = &stripe.CheckoutSessionCustomerUpdateParams{
+			Address: stripe.String("auto"),
+			Name:    stripe.String("auto"),
+		}
 	}
 	params.AddMetadata("user_id", userID.String())
 	params.AddMetadata("tier", string(tier))
@@ -157,8 +210,10 @@ func (s *StripeService) CreateCheckoutSession(userID uuid.UUID, email, name stri
 	return sess.URL, nil
 }
 
-// updateExistingSubscription updates an existing Stripe subscription to a new price
-// This handles upgrades (Basic → Pro) and downgrades (Pro → Basic) with automatic proration
+// updateExistingSubscription updates an existing Stripe subscription to a new price.
+// An upgrade (Basic → Pro) is invoiced for the rest of the period and grants
+// prorated credits straight away. A downgrade (Pro → Basic) bills the lower
+// price from the next period, and the user keeps their tier until then.
 func (s *StripeService) updateExistingSubscription(sub *models.Subscription, newPriceID string, newTier models.SubscriptionTier) (string, error) {
 	// Get the current Stripe subscription
 	stripeSub, err := subscription.Get(*sub.StripeSubscriptionID, nil)
@@ -167,21 +222,29 @@ func (s *StripeService) updateExistingSubscription(sub *models.Subscription, new
 	}
 
 	// Get the current subscription item ID
-	if len(stripeSub.Items.Data) == 0 {
+	item := firstItem(stripeSub)
+	if item == nil {
 		return "", fmt.Errorf("no items in subscription")
 	}
-	itemID := stripeSub.Items.Data[0].ID
+	if start, end := unixTime(item.CurrentPeriodStart), unixTime(item.CurrentPeriodEnd); start != nil && end != nil {
+		sub.CurrentPeriodStart, sub.CurrentPeriodEnd = start, end
+	}
 
-	// Update the subscription with new price
-	// Stripe automatically handles proration (credits for unused time)
+	// Update the subscription with new price. Upgrades are charged for the
+	// unused time now; downgrades get nothing back, as the current plan runs
+	// to the end of the period.
+	proration := "none"
+	if newTier.Rank() > sub.Tier.Rank() {
+		proration = "always_invoice"
+	}
 	updateParams := &stripe.SubscriptionParams{
 		Items: []*stripe.SubscriptionItemsParams{
 			{
-				ID:    stripe.String(itemID),
+				ID:    stripe.String(item.ID),
 				Price: stripe.String(newPriceID),
 			},
 		},
-		ProrationBehavior: stripe.String("always_invoice"),
+		ProrationBehavior: stripe.String(proration),
 	}
 
 	_, err = subscription.Update(*sub.StripeSubscriptionID, updateParams)
@@ -190,21 +253,25 @@ func (s *StripeService) updateExistingSubscription(sub *models.Subscription, new
 	}
 
 	// Update local database
-	sub.Tier = newTier
+	previousTier, previousStatus := sub.Tier, sub.Status
+	grant := s.changeTier(sub, newTier, time.Now())
 	sub.StripePriceID = &newPriceID
 	if err := s.subRepo.Save(s.exec, sub); err != nil {
 		log.Printf("Failed to update local subscription: %v", err)
+	} else {
+		s.publishChanged(sub, previousTier, previousStatus)
 	}
 
 	// Update credits allowance
-	if err := s.creditsService.UpdateAllowance(sub.UserID, newTier); err != nil {
+	if err := s.creditsService.UpdateAllowance(sub.UserID, sub.Tier); err != nil {
 		log.Printf("Failed to update allowance: %v", err)
 	}
 
-	// Grant new tier credits immediately
-	newAllowance := models.TierCredits[newTier]
-	if err := s.creditsService.AddCredits(sub.UserID, newAllowance, fmt.Sprintf("Upgraded to %s", newTier)); err != nil {
-		log.Printf("Failed to add upgrade credits: %v", err)
+	// Grant the upgrade's credits for the rest of the period
+	if grant > 0 {
+		if err := s.creditsService.AddCredits(sub.UserID, grant, fmt.Sprintf("Upgraded to %s", sub.Tier)); err != nil {
+			log.Printf("Failed to add upgrade credits: %v", err)
+		}
 	}
 
 	// Return success URL (user stays on same page, subscription updated)
@@ -231,6 +298,58 @@ func (s *StripeService) CreatePortalSession(userID uuid.UUID) (string, error) {
 	return sess.URL, nil
 }
 
+// PaymentBanner returns the failed-payment banner for a subscription with a
+// billing portal link straight to updating the payment method, or nil when
+// its payments are in good standing
+func (s *StripeService) PaymentBanner(sub *models.Subscription) *PaymentBanner {
+	banner := PaymentBannerFor(sub)
+	if banner == nil {
+		return nil
+	}
+
+	params := &stripe.BillingPortalSessionParams{
+		Customer:  stripe.String(sub.StripeCustomerID),
+		ReturnURL: stripe.String(s.config.FrontendURL + "/settings"),
+		FlowData: &stripe.BillingPortalSessionFlowDataParams{
+			Type: stripe.String(string(stripe.BillingPortalSessionFlowTypePaymentMethodUpdate)),
+		},
+	}
+	sess, err := portalsession.New(params)
+	if err != nil {
+		log.Printf("Failed to create payment update portal session for user %s: %v", sub.UserID, err)
+		return banner
+	}
+	banner.PortalURL = sess.URL
+	return banner
+}
+
+// CancelForDeletion ends the user's Stripe subscription straight away, for an
+// account being deleted. There is no proration and no grace period. Users
+// without a Stripe subscription are left alone, and so is a subscription
+// Stripe no longer has.
+func (s *StripeService) CancelForDeletion(userID uuid.UUID) error {
+	sub, err := s.subRepo.FindByUserID(s.exec, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get subscription: %w", err)
+	}
+	if sub.StripeSubscriptionID == nil {
+		return nil
+	}
+
+	_, err = subscription.Cancel(*sub.StripeSubscriptionID, nil)
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) && stripeErr.Code == stripe.ErrorCodeResourceMissing {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cancel stripe subscription: %w", err)
+	}
+	return nil
+}
+
 // HandleWebhook processes a Stripe webhook event
 // payload is the raw request body, signature is the Stripe-Signature header
 func (s *StripeService) HandleWebhook(payload []byte, signature string) error {
@@ -286,11 +405,15 @@ func (s *StripeService) handleCheckoutCompleted(data json.RawMessage) error {
 	}
 	tier := models.SubscriptionTier(tierStr)
 
-	return s.txRunner.Transaction(func(tx *gorm.DB) error {
-		sub, err := s.subRepo.FindByUserID(tx, userID)
+	var sub *models.Subscription
+	var previousTier models.SubscriptionTier
+	var previousStatus string
+	err = s.txRunner.Transaction(func(tx *gorm.DB) error {
+		sub, err = s.subRepo.FindByUserID(tx, userID)
 		if err != nil {
 			return fmt.Errorf("find subscription: %w", err)
 		}
+		previousTier, previousStatus = sub.Tier, sub.Status
 
 		// Get subscription ID from the checkout session
 		var subID string
@@ -301,6 +424,14 @@ func (s *StripeService) handleCheckoutCompleted(data json.RawMessage) error {
 		sub.StripeSubscriptionID = &subID
 		sub.Tier = tier
 		sub.Status = "active"
+		sub.BillingCountry, sub.TaxStatus = checkoutTax(&sess)
+		// Resubscribing ends any pending downgrade, and paying ends an
+		// admin's tier override
+		sub.GraceTier = nil
+		sub.GraceEndsAt = nil
+		sub.TierOverride = nil
+		sub.PendingTier = nil
+		sub.PendingTierAt = nil
 
 		if err := s.subRepo.Save(tx, sub); err != nil {
 			return fmt.Errorf("update subscription: %w", err)
@@ -312,13 +443,34 @@ func (s *StripeService) handleCheckoutCompleted(data json.RawMessage) error {
 		}
 
 		// Grant new tier credits immediately
-		newAllowance := models.TierCredits[tier]
+		newAllowance := s.Runtime.Current().TierAllowance(tier)
 		if err := s.creditsService.AddCredits(userID, newAllowance, fmt.Sprintf("Upgraded to %s", tier)); err != nil {
 			log.Printf("Failed to add upgrade credits: %v", err)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.tracker.Track(context.Background(), userID, analytics.EventSubscriptionUpgraded, map[string]any{
+		"tier": string(tier),
+	})
+	s.publishChanged(sub, previousTier, previousStatus)
+	return nil
+}
+
+// checkoutTax is the billing country of a completed checkout and, if Stripe
+// Tax ran, its status
+func checkoutTax(sess *stripe.CheckoutSession) (country, taxStatus string) {
+	if sess.CustomerDetails != nil && sess.CustomerDetails.Address != nil {
+		country = sess.CustomerDetails.Address.Country
+	}
+	if sess.AutomaticTax != nil && sess.AutomaticTax.Enabled {
+		taxStatus = string(sess.AutomaticTax.Status)
+	}
+	return country, taxStatus
 }
 
 func (s *StripeService) handleSubscriptionUpdated(data json.RawMessage) error {
@@ -336,19 +488,28 @@ func (s *StripeService) handleSubscriptionUpdated(data json.RawMessage) error {
 		return fmt.Errorf("find subscription: %w", err)
 	}
 
+	now := time.Now()
+	previousTier, previousStatus := sub.Tier, sub.Status
 	sub.Status = string(stripeSub.Status)
 	sub.CancelAtPeriodEnd = stripeSub.CancelAtPeriodEnd
 
+	// A new period ends the one a downgrade was waiting for
+	item := firstItem(&stripeSub)
+	if item != nil {
+		if start, end := unixTime(item.CurrentPeriodStart), unixTime(item.CurrentPeriodEnd); start != nil && end != nil {
+			sub.CurrentPeriodStart, sub.CurrentPeriodEnd = start, end
+		}
+	}
+	applyDueTierChange(sub, now)
+
 	// Determine tier from price
-	if len(stripeSub.Items.Data) > 0 {
-		priceID := stripeSub.Items.Data[0].Price.ID
+	grant := 0
+	if item != nil && item.Price != nil {
+		priceID := item.Price.ID
 		sub.StripePriceID = &priceID
 
-		switch priceID {
-		case s.config.StripePriceBasic:
-			sub.Tier = models.TierBasic
-		case s.config.StripePricePro:
-			sub.Tier = models.TierPro
+		if tier, ok := s.tierForPrice(priceID); ok {
+			grant = s.changeTier(sub, tier, now)
 		}
 
 		if err := s.creditsService.UpdateAllowance(sub.UserID, sub.Tier); err != nil {
@@ -356,7 +517,17 @@ func (s *StripeService) handleSubscriptionUpdated(data json.RawMessage) error {
 		}
 	}
 
-	return s.subRepo.Save(s.exec, sub)
+	if err := s.subRepo.Save(s.exec, sub); err != nil {
+		return err
+	}
+	s.publishChanged(sub, previousTier, previousStatus)
+
+	if grant > 0 {
+		if err := s.creditsService.AddCredits(sub.UserID, grant, fmt.Sprintf("Upgraded to %s", sub.Tier)); err != nil {
+			log.Printf("Failed to add upgrade credits: %v", err)
+		}
+	}
+	return nil
 }
 
 func (s *StripeService) handleSubscriptionDeleted(data json.RawMessage) error {
@@ -373,17 +544,87 @@ func (s *StripeService) handleSubscriptionDeleted(data json.RawMessage) error {
 		return fmt.Errorf("find subscription: %w", err)
 	}
 
-	// Downgrade to free
+	return s.cancelSubscription(sub)
+}
+
+// cancelSubscription moves a subscription Stripe has ended to the free tier
+func (s *StripeService) cancelSubscription(sub *models.Subscription) error {
+	previousTier, previousStatus := sub.Tier, sub.Status
+
+	// Downgrade to free. With a grace period, paid features stay readable and
+	// the credit allowance change is left to SubscriptionGraceWorker.
+	graceDays := s.config.SubscriptionGraceDays
+	inGrace := graceDays > 0 && previousTier != models.TierFree
+
 	sub.Tier = models.TierFree
 	sub.Status = "canceled"
 	sub.StripeSubscriptionID = nil
 	sub.StripePriceID = nil
+	sub.PaymentFailedAt = nil
+	sub.DunningRemindersSent = 0
+	sub.PendingTier = nil
+	sub.PendingTierAt = nil
+	if inGrace {
+		graceEndsAt := time.Now().AddDate(0, 0, graceDays)
+		sub.GraceTier = &previousTier
+		sub.GraceEndsAt = &graceEndsAt
+	}
 
 	if err := s.subRepo.Save(s.exec, sub); err != nil {
 		return fmt.Errorf("update subscription: %w", err)
 	}
 
-	return s.creditsService.UpdateAllowance(sub.UserID, models.TierFree)
+	s.tracker.Track(context.Background(), sub.UserID, analytics.EventSubscriptionCancelled, map[string]any{
+		"previousTier": string(previousTier),
+		"graceDays":    graceDays,
+	})
+	s.publishChanged(sub, previousTier, previousStatus)
+
+	if !inGrace {
+		return s.creditsService.UpdateAllowance(sub.UserID, models.TierFree)
+	}
+
+	s.notifySubscriptionEnding(sub.UserID, previousTier, *sub.GraceEndsAt)
+	return nil
+}
+
+// publishChanged tells subscribers a saved subscription changed tier or status
+func (s *StripeService) publishChanged(sub *models.Subscription, previousTier models.SubscriptionTier, previousStatus string) {
+	if sub.Tier == previousTier && sub.Status == previousStatus {
+		return
+	}
+	s.Events.Publish(context.Background(), events.SubscriptionChanged{
+		UserID:       sub.UserID,
+		PreviousTier: previousTier,
+		Tier:         sub.Tier,
+		Status:       sub.Status,
+	})
+}
+
+// tierForPrice maps a configured Stripe price, default or regional, to its tier
+func (s *StripeService) tierForPrice(priceID string) (models.SubscriptionTier, bool) {
+	tier, _, ok := NewPriceBook(s.config).Lookup(priceID)
+	return tier, ok
+}
+
+// notifySubscriptionEnding tells the user what the cancellation changes and when
+func (s *StripeService) notifySubscriptionEnding(userID uuid.UUID, previousTier models.SubscriptionTier, graceEndsAt time.Time) {
+	if s.notifications == nil {
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Your %s plan has been cancelled. Until %s you can still review your %s history and stats, "+
+			"but new messages use free-tier limits. After that, your monthly allowance drops to %d credits.",
+		previousTier, graceEndsAt.Format("January 2, 2006"), previousTier, s.Runtime.Current().TierAllowance(models.TierFree),
+	)
+	err := s.notifications.Notify(userID, models.NotificationSubscriptionEnding, "Your subscription has ended", body, models.JSONMap{
+		"previousTier": string(previousTier),
+		"graceEndsAt":  graceEndsAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		log.Printf("Failed to notify user %s of subscription ending: %v", userID, err)
+	}
 }
 
 func (s *StripeService) handleInvoicePaid(data json.RawMessage) error {
@@ -406,6 +647,29 @@ func (s *StripeService) handleInvoicePaid(data json.RawMessage) error {
 		return fmt.Errorf("find subscription: %w", err)
 	}
 
+	if s.Dunning != nil {
+		if err := s.Dunning.PaymentSucceeded(sub); err != nil {
+			return err
+		}
+	}
+
+	// An upgrade's proration invoice isn't a new period; its credits were
+	// granted with the upgrade
+	if invoice.BillingReason == stripe.InvoiceBillingReasonSubscriptionUpdate {
+		return nil
+	}
+
+	// A renewal starts the period a downgrade was waiting for
+	previousTier, previousStatus := sub.Tier, sub.Status
+	if applyDueTierChange(sub, time.Now()) {
+		if err := s.subRepo.Save(s.exec, sub); err != nil {
+			return fmt.Errorf("update subscription: %w", err)
+		}
+		s.publishChanged(sub, previousTier, previousStatus)
+		if err := s.creditsService.UpdateAllowance(sub.UserID, sub.Tier); err != nil {
+			return fmt.Errorf("update allowance: %w", err)
+		}
+	}
 	return s.creditsService.RefreshMonthlyCredits(sub.UserID)
 }
 
@@ -421,5 +685,19 @@ func (s *StripeService) handleInvoicePaymentFailed(data json.RawMessage) error {
 	}
 	subID := invoice.Parent.SubscriptionDetails.Subscription.ID
 
-	return s.subRepo.UpdateStatus(s.exec, subID, "past_due")
+	if err := s.subRepo.UpdateStatus(s.exec, subID, "past_due"); err != nil {
+		return err
+	}
+	if s.Dunning == nil {
+		return nil
+	}
+
+	sub, err := s.subRepo.FindByStripeSubscriptionID(s.exec, subID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("find subscription: %w", err)
+	}
+	return s.Dunning.PaymentFailed(sub)
 }
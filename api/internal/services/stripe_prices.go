@@ -0,0 +1,93 @@
+package services
+
+import (
+	"strings"
+
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/models"
+)
+
+// PriceBook holds the Stripe price of each paid tier by currency. The ""
+// currency holds the default prices, charged wherever no regional price
+// applies.
+type PriceBook map[string]map[models.SubscriptionTier]string
+
+// NewPriceBook collects the configured default and regional prices
+func NewPriceBook(cfg *config.Config) PriceBook {
+	book := PriceBook{"": {
+		models.TierBasic: cfg.StripePriceBasic,
+		models.TierPro:   cfg.StripePricePro,
+	}}
+	for _, p := range cfg.StripeRegionalPrices {
+		book[p.Currency] = map[models.SubscriptionTier]string{
+			models.TierBasic: p.Basic,
+			models.TierPro:   p.Pro,
+		}
+	}
+	return book
+}
+
+// PriceID is the tier's price in currency, or the default price if there is
+// no regional one
+func (b PriceBook) PriceID(tier models.SubscriptionTier, currency string) string {
+	if id := b[currency][tier]; id != "" {
+		return id
+	}
+	return b[""][tier]
+}
+
+// Lookup finds a configured price's tier and currency, "" for a default
+// price
+func (b PriceBook) Lookup(priceID string) (tier models.SubscriptionTier, currency string, ok bool) {
+	if priceID == "" {
+		return "", "", false
+	}
+	for currency, tiers := range b {
+		for tier, id := range tiers {
+			if id == priceID {
+				return tier, currency, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Regional reports whether currency has its own prices
+func (b PriceBook) Regional(currency string) bool {
+	return currency != "" && len(b[currency]) > 0
+}
+
+// countryCurrencies is the currency customers in each country pay in, for
+// countries whose currency prices are commonly set for
+var countryCurrencies = map[string]string{
+	"GB": "gbp", "CA": "cad", "AU": "aud", "NZ": "nzd", "JP": "jpy", "IN": "inr",
+	"BR": "brl", "MX": "mxn", "CH": "chf", "SE": "sek", "NO": "nok", "DK": "dkk",
+	"PL": "pln", "CZ": "czk", "HU": "huf", "KR": "krw", "SG": "sgd", "HK": "hkd",
+	"ZA": "zar", "TR": "try", "IL": "ils", "AE": "aed", "US": "usd",
+	// Eurozone
+	"AT": "eur", "BE": "eur", "HR": "eur", "CY": "eur", "EE": "eur", "FI": "eur",
+	"FR": "eur", "DE": "eur", "GR": "eur", "IE": "eur", "IT": "eur", "LV": "eur",
+	"LT": "eur", "LU": "eur", "MT": "eur", "NL": "eur", "PT": "eur", "SK": "eur",
+	"SI": "eur", "ES": "eur",
+}
+
+// CurrencyForCountry is the local currency of an ISO 3166 country, or ""
+// if unknown
+func CurrencyForCountry(country string) string {
+	return countryCurrencies[strings.ToUpper(country)]
+}
+
+// CountryFromLocale reads the region of the first Accept-Language entry
+// that has one, e.g. "DE" from "de-DE,de;q=0.9", or "" if none does
+func CountryFromLocale(acceptLanguage string) string {
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+		for _, part := range parts[min(1, len(parts)):] {
+			if len(part) == 2 && countryCode.MatchString(strings.ToUpper(part)) {
+				return strings.ToUpper(part)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/models"
+
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/price"
+	"github.com/stripe/stripe-go/v82/tax/calculation"
+)
+
+// Prices are cached briefly, so a price changed in Stripe shows within
+// minutes. Tax estimates are billed per calculation and rates rarely change,
+// so they are kept for a day.
+const (
+	pricingPriceTTL = 10 * time.Minute
+	pricingTaxTTL   = 24 * time.Hour
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("plans aren't sold in that currency")
+	ErrInvalidCountry      = errors.New("country must be a two-letter ISO 3166 code")
+)
+
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// paidTiers are the tiers with a Stripe price, in display order
+var paidTiers = []models.SubscriptionTier{models.TierBasic, models.TierPro}
+
+// StripeCatalog is the part of the Stripe API pricing reads
+type StripeCatalog interface {
+	// Price returns a price with its currency options
+	Price(ctx context.Context, priceID string) (*stripe.Price, error)
+	// Tax returns the tax Stripe Tax charges a customer in country on amount,
+	// and the total they pay
+	Tax(ctx context.Context, amount int64, currency, taxBehavior, country string) (tax, total int64, err error)
+}
+
+// PricingProvider defines the interface for the plans' prices
+type PricingProvider interface {
+	Pricing(ctx context.Context, currency, country string) (*Pricing, error)
+}
+
+// TierPrice is what a paid tier costs each month in one currency. Amounts
+// are in the currency's smallest unit, as Stripe charges them.
+type TierPrice struct {
+	Tier        models.SubscriptionTier `json:"tier"`
+	Credits     int                     `json:"credits"`
+	UnitAmount  int64                   `json:"unitAmount"`
+	Interval    string                  `json:"interval"`
+	TaxBehavior string                  `json:"taxBehavior"` // inclusive, exclusive or unspecified
+
+	// Tax is the estimated tax in the country and AmountWithTax what the
+	// customer pays with it. Both are nil when tax can't be estimated, e.g.
+	// without a country; an inclusive price still has AmountWithTax.
+	Tax           *int64 `json:"tax"`
+	AmountWithTax *int64 `json:"amountWithTax"`
+}
+
+// Pricing lists the paid tiers' prices in one currency
+type Pricing struct {
+	Currency   string      `json:"currency"`
+	Country    string      `json:"country,omitempty"`
+	TaxEnabled bool        `json:"taxEnabled"`
+	Tiers      []TierPrice `json:"tiers"`
+}
+
+type cachedPrice struct {
+	price     *stripe.Price
+	fetchedAt time.Time
+}
+
+type cachedTax struct {
+	tax, total int64
+	fetchedAt  time.Time
+}
+
+// StripePricingService reads the plans' prices from Stripe, so the amounts
+// shown are the ones charged, in every currency a price offers
+type StripePricingService struct {
+	catalog    StripeCatalog
+	prices     PriceBook
+	taxEnabled bool
+
+	// Runtime supplies the tier allowances in force; nil uses the defaults
+	Runtime *RuntimeSettingsService
+
+	mu         sync.Mutex
+	priceCache map[string]cachedPrice
+	taxCache   map[string]cachedTax
+
+	now func() time.Time
+}
+
+// NewStripePricingService creates a new pricing service for the configured prices
+func NewStripePricingService(cfg *config.Config, catalog StripeCatalog) *StripePricingService {
+	return NewStripePricingServiceForTest(catalog, NewPriceBook(cfg), cfg.StripeTaxEnabled, time.Now)
+}
+
+// NewStripePricingServiceForTest creates a StripePricingService with injected dependencies for testing.
+func NewStripePricingServiceForTest(catalog StripeCatalog, prices PriceBook, taxEnabled bool, now func() time.Time) *StripePricingService {
+	return &StripePricingService{
+		catalog:    catalog,
+		prices:     prices,
+		taxEnabled: taxEnabled,
+		priceCache: make(map[string]cachedPrice),
+		taxCache:   make(map[string]cachedTax),
+		now:        now,
+	}
+}
+
+// Pricing returns the paid tiers' prices in currency. Without one, it uses
+// the local currency of country if the plans are sold in it, and the
+// default prices' currency otherwise. With Stripe Tax enabled and a country,
+// it estimates the tax a customer there pays. Tiers without a configured
+// price are left out.
+func (s *StripePricingService) Pricing(ctx context.Context, currency, country string) (*Pricing, error) {
+	currency = strings.ToLower(strings.TrimSpace(currency))
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country != "" && !countryCode.MatchString(country) {
+		return nil, ErrInvalidCountry
+	}
+
+	if currency == "" {
+		if local := CurrencyForCountry(country); local != "" {
+			pricing, err := s.pricing(ctx, local, country)
+			if !errors.Is(err, ErrUnsupportedCurrency) {
+				return pricing, err
+			}
+		}
+	}
+	return s.pricing(ctx, currency, country)
+}
+
+func (s *StripePricingService) pricing(ctx context.Context, currency, country string) (*Pricing, error) {
+	pricing := &Pricing{Currency: currency, Country: country, TaxEnabled: s.taxEnabled, Tiers: []TierPrice{}}
+	for _, tier := range paidTiers {
+		priceID := s.prices.PriceID(tier, currency)
+		if priceID == "" {
+			continue
+		}
+		p, err := s.price(ctx, priceID)
+		if err != nil {
+			return nil, fmt.Errorf("get %s price: %w", tier, err)
+		}
+		if pricing.Currency == "" {
+			pricing.Currency = string(p.Currency)
+		}
+
+		tierPrice, ok := localizedPrice(p, pricing.Currency)
+		if !ok {
+			return nil, ErrUnsupportedCurrency
+		}
+		tierPrice.Tier = tier
+		tierPrice.Credits = s.Runtime.Current().TierAllowance(tier)
+		s.estimateTax(ctx, priceID, pricing, &tierPrice)
+		pricing.Tiers = append(pricing.Tiers, tierPrice)
+	}
+	return pricing, nil
+}
+
+// localizedPrice is the price's amount and tax behavior in currency
+func localizedPrice(p *stripe.Price, currency string) (TierPrice, bool) {
+	tierPrice := TierPrice{Interval: "month"}
+	if p.Recurring != nil {
+		tierPrice.Interval = string(p.Recurring.Interval)
+	}
+	if string(p.Currency) == currency {
+		tierPrice.UnitAmount = p.UnitAmount
+		tierPrice.TaxBehavior = string(p.TaxBehavior)
+		return tierPrice, true
+	}
+	option, ok := p.CurrencyOptions[currency]
+	if !ok || option == nil {
+		return tierPrice, false
+	}
+	tierPrice.UnitAmount = option.UnitAmount
+	tierPrice.TaxBehavior = string(option.TaxBehavior)
+	return tierPrice, true
+}
+
+// estimateTax fills in the tax on a tier's price. A failed estimate, e.g.
+// for a country where Stripe needs a postal code, leaves it out.
+func (s *StripePricingService) estimateTax(ctx context.Context, priceID string, pricing *Pricing, tierPrice *TierPrice) {
+	if tierPrice.TaxBehavior == string(stripe.PriceTaxBehaviorInclusive) {
+		amount := tierPrice.UnitAmount
+		tierPrice.AmountWithTax = &amount
+	}
+	if !s.taxEnabled || pricing.Country == "" {
+		return
+	}
+
+	key := strings.Join([]string{priceID, pricing.Currency, pricing.Country}, ":")
+	s.mu.Lock()
+	cached, ok := s.taxCache[key]
+	s.mu.Unlock()
+	if !ok || s.now().Sub(cached.fetchedAt) >= pricingTaxTTL {
+		tax, total, err := s.catalog.Tax(ctx, tierPrice.UnitAmount, pricing.Currency, tierPrice.TaxBehavior, pricing.Country)
+		if err != nil {
+			log.Printf("[Pricing] Failed to estimate %s tax in %s: %v", tierPrice.Tier, pricing.Country, err)
+			return
+		}
+		cached = cachedTax{tax: tax, total: total, fetchedAt: s.now()}
+		s.mu.Lock()
+		s.taxCache[key] = cached
+		s.mu.Unlock()
+	}
+	tierPrice.Tax = &cached.tax
+	tierPrice.AmountWithTax = &cached.total
+}
+
+// price returns the Stripe price, fetching it at most every pricingPriceTTL
+func (s *StripePricingService) price(ctx context.Context, priceID string) (*stripe.Price, error) {
+	s.mu.Lock()
+	cached, ok := s.priceCache[priceID]
+	s.mu.Unlock()
+	if ok && s.now().Sub(cached.fetchedAt) < pricingPriceTTL {
+		return cached.price, nil
+	}
+
+	p, err := s.catalog.Price(ctx, priceID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.priceCache[priceID] = cachedPrice{price: p, fetchedAt: s.now()}
+	s.mu.Unlock()
+	return p, nil
+}
+
+type stripeAPICatalog struct{}
+
+// NewStripeAPICatalog creates a StripeCatalog backed by the Stripe API. It
+// uses the key set by NewStripeService.
+func NewStripeAPICatalog() StripeCatalog {
+	return stripeAPICatalog{}
+}
+
+func (stripeAPICatalog) Price(ctx context.Context, priceID string) (*stripe.Price, error) {
+	params := &stripe.PriceParams{}
+	params.AddExpand("currency_options")
+	params.Context = ctx
+	return price.Get(priceID, params)
+}
+
+func (stripeAPICatalog) Tax(ctx context.Context, amount int64, currency, taxBehavior, country string) (int64, int64, error) {
+	if taxBehavior != string(stripe.PriceTaxBehaviorInclusive) {
+		taxBehavior = string(stripe.PriceTaxBehaviorExclusive)
+	}
+	params := &stripe.TaxCalculationParams{
+		Currency: stripe.String(currency),
+		CustomerDetails: &stripe.TaxCalculationCustomerDetailsParams{
+			Address:       &stripe.AddressParams{Country: stripe.String(country)},
+			AddressSource: stripe.String("billing"),
+		},
+		LineItems: []*stripe.TaxCalculationLineItemParams{{
+			Amount:      stripe.Int64(amount),
+			Reference:   stripe.String("subscription"),
+			TaxBehavior: stripe.String(taxBehavior),
+		}},
+	}
+	params.Context = ctx
+	calc, err := calculation.New(params)
+	if err != nil {
+		return 0, 0, err
+	}
+	return calc.TaxAmountExclusive + calc.TaxAmountInclusive, calc.AmountTotal, nil
+}
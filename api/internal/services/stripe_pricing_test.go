@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/models"
+)
+
+type stubCatalog struct {
+	mock.Mock
+}
+
+func (m *stubCatalog) Price(ctx context.Context, priceID string) (*stripe.Price, error) {
+	args := m.Called(priceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*stripe.Price), args.Error(1)
+}
+
+func (m *stubCatalog) Tax(ctx context.Context, amount int64, currency, taxBehavior, country string) (int64, int64, error) {
+	args := m.Called(amount, currency, taxBehavior, country)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+var pricingPrices = PriceBook{"": {
+	models.TierBasic: "price_basic",
+	models.TierPro:   "price_pro",
+}}
+
+func stripePrice(amount int64, behavior stripe.PriceTaxBehavior, options map[string]*stripe.PriceCurrencyOptions) *stripe.Price {
+	return &stripe.Price{
+		Currency:        stripe.CurrencyUSD,
+		UnitAmount:      amount,
+		TaxBehavior:     behavior,
+		Recurring:       &stripe.PriceRecurring{Interval: stripe.PriceRecurringIntervalMonth},
+		CurrencyOptions: options,
+	}
+}
+
+func TestStripePricingService_Pricing(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("default currency", func(t *testing.T) {
+		catalog := new(stubCatalog)
+		catalog.On("Price", "price_basic").Return(stripePrice(2000, stripe.PriceTaxBehaviorExclusive, nil), nil)
+		catalog.On("Price", "price_pro").Return(stripePrice(5000, stripe.PriceTaxBehaviorExclusive, nil), nil)
+		svc := NewStripePricingServiceForTest(catalog, pricingPrices, false, func() time.Time { return now })
+
+		pricing, err := svc.Pricing(ctx, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "usd", pricing.Currency)
+		require.Len(t, pricing.Tiers, 2)
+		assert.Equal(t, models.TierBasic, pricing.Tiers[0].Tier)
+		assert.Equal(t, int64(2000), pricing.Tiers[0].UnitAmount)
+		assert.Equal(t, "month", pricing.Tiers[0].Interval)
+		assert.Equal(t, models.TierCredits[models.TierBasic], pricing.Tiers[0].Credits)
+		assert.Nil(t, pricing.Tiers[0].AmountWithTax, "tax isn't known without Stripe Tax")
+	})
+
+	t.Run("localized and inclusive of tax", func(t *testing.T) {
+		eur := map[string]*stripe.PriceCurrencyOptions{
+			"eur": {UnitAmount: 1900, TaxBehavior: stripe.PriceCurrencyOptionsTaxBehaviorInclusive},
+		}
+		catalog := new(stubCatalog)
+		catalog.On("Price", "price_basic").Return(stripePrice(2000, stripe.PriceTaxBehaviorExclusive, eur), nil)
+		catalog.On("Tax", int64(1900), "eur", "inclusive", "DE").Return(int64(303), int64(1900), nil).Once()
+		svc := NewStripePricingServiceForTest(catalog, PriceBook{"": {models.TierBasic: "price_basic"}}, true, func() time.Time { return now })
+
+		pricing, err := svc.Pricing(ctx, "EUR", "de")
+		require.NoError(t, err)
+		assert.Equal(t, "DE", pricing.Country)
+		require.Len(t, pricing.Tiers, 1)
+		assert.Equal(t, int64(1900), pricing.Tiers[0].UnitAmount)
+		assert.Equal(t, int64(303), *pricing.Tiers[0].Tax)
+		assert.Equal(t, int64(1900), *pricing.Tiers[0].AmountWithTax)
+
+		// Prices and tax estimates are cached
+		_, err = svc.Pricing(ctx, "eur", "DE")
+		require.NoError(t, err)
+		catalog.AssertNumberOfCalls(t, "Price", 1)
+		catalog.AssertNumberOfCalls(t, "Tax", 1)
+	})
+
+	t.Run("adds tax to exclusive prices", func(t *testing.T) {
+		catalog := new(stubCatalog)
+		catalog.On("Price", "price_pro").Return(stripePrice(5000, stripe.PriceTaxBehaviorExclusive, nil), nil)
+		catalog.On("Tax", int64(5000), "usd", "exclusive", "GB").Return(int64(1000), int64(6000), nil)
+		svc := NewStripePricingServiceForTest(catalog, PriceBook{"": {models.TierPro: "price_pro"}}, true, func() time.Time { return now })
+
+		pricing, err := svc.Pricing(ctx, "", "GB")
+		require.NoError(t, err)
+		assert.Equal(t, int64(6000), *pricing.Tiers[0].AmountWithTax)
+	})
+
+	t.Run("leaves out a failed tax estimate", func(t *testing.T) {
+		catalog := new(stubCatalog)
+		catalog.On("Price", "price_pro").Return(stripePrice(5000, stripe.PriceTaxBehaviorExclusive, nil), nil)
+		catalog.On("Tax", int64(5000), "usd", "exclusive", "US").Return(int64(0), int64(0), errors.New("postal code required"))
+		svc := NewStripePricingServiceForTest(catalog, PriceBook{"": {models.TierPro: "price_pro"}}, true, func() time.Time { return now })
+
+		pricing, err := svc.Pricing(ctx, "", "US")
+		require.NoError(t, err)
+		assert.Nil(t, pricing.Tiers[0].Tax)
+		assert.Nil(t, pricing.Tiers[0].AmountWithTax)
+	})
+
+	t.Run("rejects a currency the price isn't sold in", func(t *testing.T) {
+		catalog := new(stubCatalog)
+		catalog.On("Price", "price_basic").Return(stripePrice(2000, stripe.PriceTaxBehaviorExclusive, nil), nil)
+		svc := NewStripePricingServiceForTest(catalog, PriceBook{"": {models.TierBasic: "price_basic"}}, false, func() time.Time { return now })
+
+		_, err := svc.Pricing(ctx, "jpy", "")
+		assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+	})
+
+	t.Run("regional prices for the country's currency", func(t *testing.T) {
+		book := PriceBook{
+			"":    {models.TierBasic: "price_basic", models.TierPro: "price_pro"},
+			"gbp": {models.TierBasic: "price_basic_gbp", models.TierPro: "price_pro_gbp"},
+		}
+		gbp := stripePrice(1600, stripe.PriceTaxBehaviorInclusive, nil)
+		gbp.Currency = stripe.CurrencyGBP
+		catalog := new(stubCatalog)
+		catalog.On("Price", "price_basic_gbp").Return(gbp, nil)
+		catalog.On("Price", "price_pro_gbp").Return(gbp, nil)
+		svc := NewStripePricingServiceForTest(catalog, book, false, func() time.Time { return now })
+
+		pricing, err := svc.Pricing(ctx, "", "GB")
+		require.NoError(t, err)
+		assert.Equal(t, "gbp", pricing.Currency)
+		assert.Equal(t, int64(1600), pricing.Tiers[0].UnitAmount)
+		assert.Equal(t, int64(1600), *pricing.Tiers[0].AmountWithTax, "inclusive prices are the amount paid")
+	})
+
+	t.Run("default currency where the local one isn't sold", func(t *testing.T) {
+		catalog := new(stubCatalog)
+		catalog.On("Price", "price_basic").Return(stripePrice(2000, stripe.PriceTaxBehaviorExclusive, nil), nil)
+		svc := NewStripePricingServiceForTest(catalog, PriceBook{"": {models.TierBasic: "price_basic"}}, false, func() time.Time { return now })
+
+		pricing, err := svc.Pricing(ctx, "", "JP")
+		require.NoError(t, err)
+		assert.Equal(t, "usd", pricing.Currency)
+		assert.Equal(t, "JP", pricing.Country)
+	})
+
+	t.Run("rejects a malformed country", func(t *testing.T) {
+		svc := NewStripePricingServiceForTest(new(stubCatalog), pricingPrices, true, func() time.Time { return now })
+
+		_, err := svc.Pricing(ctx, "", "Germany")
+		assert.ErrorIs(t, err, ErrInvalidCountry)
+	})
+}
+
+func TestCheckoutTax(t *testing.T) {
+	sess := &stripe.CheckoutSession{
+		CustomerDetails: &stripe.CheckoutSessionCustomerDetails{Address: &stripe.Address{Country: "FR"}},
+		AutomaticTax:    &stripe.CheckoutSessionAutomaticTax{Enabled: true, Status: stripe.CheckoutSessionAutomaticTaxStatusComplete},
+	}
+	country, status := checkoutTax(sess)
+	assert.Equal(t, "FR", country)
+	assert.Equal(t, "complete", status)
+
+	country, status = checkoutTax(&stripe.CheckoutSession{})
+	assert.Empty(t, country)
+	assert.Empty(t, status, "no tax status when Stripe Tax didn't run")
+}
+
+func TestPriceBook(t *testing.T) {
+	book := NewPriceBook(&config.Config{
+		StripePriceBasic:     "price_basic",
+		StripePricePro:       "price_pro",
+		StripeRegionalPrices: []config.StripeRegionalPrice{{Currency: "eur", Basic: "price_basic_eur", Pro: "price_pro_eur"}},
+	})
+
+	assert.Equal(t, "price_pro_eur", book.PriceID(models.TierPro, "eur"))
+	assert.Equal(t, "price_pro", book.PriceID(models.TierPro, "gbp"), "falls back to the default price")
+
+	tier, currency, ok := book.Lookup("price_basic_eur")
+	assert.True(t, ok)
+	assert.Equal(t, models.TierBasic, tier)
+	assert.Equal(t, "eur", currency)
+	_, _, ok = book.Lookup("price_unknown")
+	assert.False(t, ok)
+}
+
+func TestCountryFromLocale(t *testing.T) {
+	assert.Equal(t, "DE", CountryFromLocale("de-DE,de;q=0.9,en;q=0.8"))
+	assert.Equal(t, "BR", CountryFromLocale("pt_BR"))
+	assert.Equal(t, "TW", CountryFromLocale("en;q=0.9, zh-Hant-TW"))
+	assert.Empty(t, CountryFromLocale("es-419"))
+	assert.Empty(t, CountryFromLocale(""))
+	assert.Equal(t, "eur", CurrencyForCountry("de"))
+}
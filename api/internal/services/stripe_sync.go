@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/event"
+	"github.com/stripe/stripe-go/v82/subscription"
+)
+
+// DefaultStripeSyncWindow is how far back a sync looks for Stripe events when
+// no start is given. Stripe keeps events for 30 days.
+const DefaultStripeSyncWindow = 72 * time.Hour
+
+// MaxStripeSyncWindow is the oldest event Stripe can still list
+const MaxStripeSyncWindow = 30 * 24 * time.Hour
+
+// AuditActionStripeSync is the audit log action for a sync started by an admin
+const AuditActionStripeSync = "stripe.sync"
+
+var ErrInvalidStripeSyncWindow = errors.New("sync start must be within the last 30 days")
+
+// stripeSyncEventTypes are the events whose missed webhooks leave local
+// billing state stale
+var stripeSyncEventTypes = []string{
+	"checkout.session.completed",
+	"customer.subscription.created",
+	"customer.subscription.updated",
+	"customer.subscription.deleted",
+	"invoice.paid",
+	"invoice.payment_failed",
+}
+
+// StripeBilling is the read-only part of the Stripe API the sync uses
+type StripeBilling interface {
+	// ChangedCustomers returns the customers with billing events since the given time
+	ChangedCustomers(ctx context.Context, since time.Time) ([]string, error)
+	// AllCustomers returns every customer that has ever had a subscription
+	AllCustomers(ctx context.Context) ([]string, error)
+	// CustomerSubscriptions returns a customer's subscriptions in any status
+	CustomerSubscriptions(ctx context.Context, customerID string) ([]*stripe.Subscription, error)
+}
+
+// StripeSyncer defines the interface for reconciling billing state with Stripe
+type StripeSyncer interface {
+	Sync(ctx context.Context, opts StripeSyncOptions) (*StripeSyncReport, error)
+}
+
+// StripeSyncOptions selects which customers a sync checks
+type StripeSyncOptions struct {
+	// Since checks customers with billing events after this time
+	Since time.Time `json:"since"`
+	// All checks every customer with a Stripe subscription instead
+	All bool `json:"all"`
+	// DryRun reports discrepancies without fixing them
+	DryRun bool `json:"dryRun"`
+	// Actor is the admin who started the sync; nil from the command line
+	Actor *uuid.UUID `json:"-"`
+}
+
+// StripeDiscrepancy is one difference between local state and Stripe
+type StripeDiscrepancy struct {
+	UserID     uuid.UUID `json:"userId"`
+	CustomerID string    `json:"customerId"`
+	Field      string    `json:"field"`
+	Local      string    `json:"local"`
+	Stripe     string    `json:"stripe"`
+	Fixed      bool      `json:"fixed"`
+}
+
+// StripeSyncReport is what a sync found and fixed
+type StripeSyncReport struct {
+	DryRun        bool                `json:"dryRun"`
+	Since         *time.Time          `json:"since,omitempty"`
+	Checked       int                 `json:"checked"`
+	Unknown       []string            `json:"unknownCustomers"`
+	Discrepancies []StripeDiscrepancy `json:"discrepancies"`
+	Errors        []string            `json:"errors"`
+}
+
+// StripeSyncService reconciles local Subscription and Credits records with
+// Stripe, for when webhooks were missed. Stripe's current state is the source
+// of truth and is applied the way the webhooks would have, so running a sync
+// again finds nothing left to fix.
+type StripeSyncService struct {
+	stripe  *StripeService
+	billing StripeBilling
+	audit   AuditLogger
+}
+
+// NewStripeSyncService creates a sync on top of the Stripe service that
+// handles webhooks
+func NewStripeSyncService(stripeService *StripeService, billing StripeBilling, audit AuditLogger) *StripeSyncService {
+	return &StripeSyncService{
+		stripe:  stripeService,
+		billing: billing,
+		audit:   audit,
+	}
+}
+
+// Sync checks the selected customers and fixes what differs from Stripe
+func (s *StripeSyncService) Sync(ctx context.Context, opts StripeSyncOptions) (*StripeSyncReport, error) {
+	report := &StripeSyncReport{
+		DryRun:        opts.DryRun,
+		Unknown:       []string{},
+		Discrepancies: []StripeDiscrepancy{},
+		Errors:        []string{},
+	}
+
+	var customers []string
+	var err error
+	if opts.All {
+		customers, err = s.billing.AllCustomers(ctx)
+	} else {
+		if opts.Since.IsZero() {
+			opts.Since = time.Now().Add(-DefaultStripeSyncWindow)
+		}
+		if time.Since(opts.Since) > MaxStripeSyncWindow {
+			return nil, ErrInvalidStripeSyncWindow
+		}
+		report.Since = &opts.Since
+		customers, err = s.billing.ChangedCustomers(ctx, opts.Since)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list stripe customers: %w", err)
+	}
+
+	for _, customerID := range customers {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := s.syncCustomer(ctx, customerID, opts.DryRun, report); err != nil {
+			log.Printf("[StripeSync] Customer %s: %v", customerID, err)
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", customerID, err))
+		}
+	}
+
+	s.record(opts, report)
+	return report, nil
+}
+
+// syncCustomer compares one customer's local records with Stripe and,
+// unless dryRun, fixes them
+func (s *StripeSyncService) syncCustomer(ctx context.Context, customerID string, dryRun bool, report *StripeSyncReport) error {
+	sub, err := s.stripe.subRepo.FindByStripeCustomerID(s.stripe.exec, customerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		report.Unknown = append(report.Unknown, customerID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("find subscription: %w", err)
+	}
+	report.Checked++
+
+	stripeSubs, err := s.billing.CustomerSubscriptions(ctx, customerID)
+	if err != nil {
+		return fmt.Errorf("list stripe subscriptions: %w", err)
+	}
+	current := CurrentStripeSubscription(stripeSubs)
+
+	found := func(field, local, remote string) {
+		report.Discrepancies = append(report.Discrepancies, StripeDiscrepancy{
+			UserID: sub.UserID, CustomerID: customerID, Field: field, Local: local, Stripe: remote, Fixed: !dryRun,
+		})
+	}
+
+	if current == nil {
+		// Stripe has nothing live: a missed customer.subscription.deleted
+		if sub.IsPaid() {
+			found("tier", string(sub.Tier), string(models.TierFree))
+			if !dryRun {
+				if err := s.stripe.cancelSubscription(sub); err != nil {
+					return fmt.Errorf("cancel subscription: %w", err)
+				}
+			}
+		}
+		return s.syncCredits(sub, nil, dryRun, found)
+	}
+
+	previousTier, previousStatus := sub.Tier, sub.Status
+	changed := false
+	if sub.StripeSubscriptionID == nil || *sub.StripeSubscriptionID != current.ID {
+		found("subscription", stringValue(sub.StripeSubscriptionID), current.ID)
+		sub.StripeSubscriptionID = &current.ID
+		changed = true
+	}
+	if sub.Status != string(current.Status) {
+		found("status", sub.Status, string(current.Status))
+		sub.Status = string(current.Status)
+		changed = true
+	}
+	if sub.CancelAtPeriodEnd != current.CancelAtPeriodEnd {
+		found("cancelAtPeriodEnd", fmt.Sprint(sub.CancelAtPeriodEnd), fmt.Sprint(current.CancelAtPeriodEnd))
+		sub.CancelAtPeriodEnd = current.CancelAtPeriodEnd
+		changed = true
+	}
+	if item := firstItem(current); item != nil && item.Price != nil {
+		priceID := item.Price.ID
+		if stringValue(sub.StripePriceID) != priceID {
+			found("price", stringValue(sub.StripePriceID), priceID)
+			sub.StripePriceID = &priceID
+			changed = true
+		}
+		if tier, ok := s.stripe.tierForPrice(priceID); !ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: price %s is not a configured plan", customerID, priceID))
+		} else if sub.Tier != tier && !downgradeScheduled(sub, tier, time.Now()) {
+			found("tier", string(sub.Tier), string(tier))
+			sub.Tier = tier
+			sub.PendingTier, sub.PendingTierAt = nil, nil
+			changed = true
+		}
+		if start, end := unixTime(item.CurrentPeriodStart), unixTime(item.CurrentPeriodEnd); start != nil && end != nil &&
+			(sub.CurrentPeriodStart == nil || !sub.CurrentPeriodStart.Equal(*start) || sub.CurrentPeriodEnd == nil || !sub.CurrentPeriodEnd.Equal(*end)) {
+			sub.CurrentPeriodStart, sub.CurrentPeriodEnd = start, end
+			changed = true
+		}
+	}
+	if changed && sub.GraceTier != nil {
+		// Resubscribing ends any pending downgrade
+		sub.GraceTier = nil
+		sub.GraceEndsAt = nil
+	}
+
+	if changed && !dryRun {
+		if err := s.stripe.subRepo.Save(s.stripe.exec, sub); err != nil {
+			return fmt.Errorf("update subscription: %w", err)
+		}
+		s.stripe.publishChanged(sub, previousTier, previousStatus)
+		// A missed checkout.session.completed: grant the plan's credits as it would have
+		if previousTier == models.TierFree && sub.IsPaid() {
+			allowance := s.stripe.Runtime.Current().TierAllowance(sub.Tier)
+			if err := s.stripe.creditsService.AddCredits(sub.UserID, allowance, fmt.Sprintf("Upgraded to %s", sub.Tier)); err != nil {
+				return fmt.Errorf("add upgrade credits: %w", err)
+			}
+		}
+	}
+	return s.syncCredits(sub, current, dryRun, found)
+}
+
+// syncCredits fixes the monthly allowance and applies a missed monthly
+// refresh (invoice.paid) for a paid period that began after the last one
+func (s *StripeSyncService) syncCredits(
+	sub *models.Subscription,
+	current *stripe.Subscription,
+	dryRun bool,
+	found func(field, local, remote string),
+) error {
+	credits, err := s.stripe.creditsService.GetCredits(sub.UserID)
+	if err != nil {
+		return fmt.Errorf("get credits: %w", err)
+	}
+
+	// During a grace period the allowance stays at the cancelled plan's, and
+	// an admin's tier override sets it outright
+	allowanceTier := sub.Tier
+	if sub.InGracePeriod(time.Now()) {
+		allowanceTier = *sub.GraceTier
+	}
+	if sub.TierOverride != nil {
+		allowanceTier = *sub.TierOverride
+	}
+	allowance := s.stripe.Runtime.Current().TierAllowance(allowanceTier)
+	if credits.MonthlyAllowance != allowance {
+		found("monthlyAllowance", fmt.Sprint(credits.MonthlyAllowance), fmt.Sprint(allowance))
+		if !dryRun {
+			if err := s.stripe.creditsService.UpdateAllowance(sub.UserID, allowanceTier); err != nil {
+				return fmt.Errorf("update allowance: %w", err)
+			}
+		}
+	}
+
+	item := firstItem(current)
+	if item == nil || current.Status != stripe.SubscriptionStatusActive {
+		return nil
+	}
+	periodStart := unixTime(item.CurrentPeriodStart)
+	if periodStart != nil && credits.LastRefreshedAt.Before(*periodStart) {
+		found("lastRefreshedAt", credits.LastRefreshedAt.Format(time.RFC3339), periodStart.Format(time.RFC3339))
+		if !dryRun {
+			if err := s.stripe.creditsService.RefreshMonthlyCredits(sub.UserID); err != nil {
+				return fmt.Errorf("refresh credits: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// record writes an admin-started sync that changed something to the audit log
+func (s *StripeSyncService) record(opts StripeSyncOptions, report *StripeSyncReport) {
+	if s.audit == nil || opts.Actor == nil {
+		return
+	}
+	s.audit.Record(&models.AuditLog{
+		Action:  AuditActionStripeSync,
+		Actor:   opts.Actor.String(),
+		Outcome: models.AuditOutcomeSuccess,
+		Details: models.JSONMap{
+			"all":           opts.All,
+			"dryRun":        opts.DryRun,
+			"checked":       report.Checked,
+			"discrepancies": len(report.Discrepancies),
+			"errors":        len(report.Errors),
+		},
+	})
+}
+
+// liveStripeStatuses are the statuses of a subscription still billing the customer
+var liveStripeStatuses = []stripe.SubscriptionStatus{
+	stripe.SubscriptionStatusActive,
+	stripe.SubscriptionStatusTrialing,
+	stripe.SubscriptionStatusPastDue,
+	stripe.SubscriptionStatusUnpaid,
+}
+
+// CurrentStripeSubscription picks the subscription that decides a customer's
+// plan: the newest one still live, or nil if all have ended
+func CurrentStripeSubscription(subs []*stripe.Subscription) *stripe.Subscription {
+	var current *stripe.Subscription
+	for _, sub := range subs {
+		if sub == nil || !slices.Contains(liveStripeStatuses, sub.Status) {
+			continue
+		}
+		if current == nil || sub.Created > current.Created {
+			current = sub
+		}
+	}
+	return current
+}
+
+// downgradeScheduled reports whether Stripe billing tier instead of the
+// subscription's tier is a downgrade still waiting for its period to end
+func downgradeScheduled(sub *models.Subscription, tier models.SubscriptionTier, now time.Time) bool {
+	return sub.PendingTier != nil && *sub.PendingTier == tier && !sub.TierChangeDue(now)
+}
+
+// firstItem returns the subscription's plan item; plans have exactly one
+func firstItem(sub *stripe.Subscription) *stripe.SubscriptionItem {
+	if sub == nil || sub.Items == nil || len(sub.Items.Data) == 0 {
+		return nil
+	}
+	return sub.Items.Data[0]
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func unixTime(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0)
+	return &t
+}
+
+// stripeAPIBilling reads billing state from the Stripe API
+type stripeAPIBilling struct{}
+
+// NewStripeAPIBilling creates a StripeBilling backed by the Stripe API. It
+// uses the key set by NewStripeService.
+func NewStripeAPIBilling() StripeBilling {
+	return stripeAPIBilling{}
+}
+
+func (stripeAPIBilling) ChangedCustomers(ctx context.Context, since time.Time) ([]string, error) {
+	params := &stripe.EventListParams{
+		CreatedRange: &stripe.RangeQueryParams{GreaterThanOrEqual: since.Unix()},
+	}
+	for _, eventType := range stripeSyncEventTypes {
+		params.Types = append(params.Types, stripe.String(eventType))
+	}
+	params.Context = ctx
+
+	var customers []string
+	seen := make(map[string]bool)
+	iter := event.List(params)
+	for iter.Next() {
+		customerID, _ := iter.Event().Data.Object["customer"].(string)
+		if customerID != "" && !seen[customerID] {
+			seen[customerID] = true
+			customers = append(customers, customerID)
+		}
+	}
+	return customers, iter.Err()
+}
+
+func (stripeAPIBilling) AllCustomers(ctx context.Context) ([]string, error) {
+	params := &stripe.SubscriptionListParams{Status: stripe.String("all")}
+	params.Context = ctx
+
+	var customers []string
+	seen := make(map[string]bool)
+	iter := subscription.List(params)
+	for iter.Next() {
+		if c := iter.Subscription().Customer; c != nil && !seen[c.ID] {
+			seen[c.ID] = true
+			customers = append(customers, c.ID)
+		}
+	}
+	return customers, iter.Err()
+}
+
+func (stripeAPIBilling) CustomerSubscriptions(ctx context.Context, customerID string) ([]*stripe.Subscription, error) {
+	params := &stripe.SubscriptionListParams{
+		Customer: stripe.String(customerID),
+		Status:   stripe.String("all"),
+	}
+	params.Context = ctx
+
+	var subs []*stripe.Subscription
+	iter := subscription.List(params)
+	for iter.Next() {
+		subs = append(subs, iter.Subscription())
+	}
+	return subs, iter.Err()
+}
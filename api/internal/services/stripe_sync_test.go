@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v82"
+
+	"ling-app/api/internal/config"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	"ling-app/api/internal/repository/mocks"
+)
+
+// fakeStripeBilling serves canned Stripe state to the sync
+type fakeStripeBilling struct {
+	changed []string
+	subs    map[string][]*stripe.Subscription
+	since   time.Time
+}
+
+func (f *fakeStripeBilling) ChangedCustomers(ctx context.Context, since time.Time) ([]string, error) {
+	f.since = since
+	return f.changed, nil
+}
+
+func (f *fakeStripeBilling) AllCustomers(ctx context.Context) ([]string, error) {
+	var customers []string
+	for customerID := range f.subs {
+		customers = append(customers, customerID)
+	}
+	return customers, nil
+}
+
+func (f *fakeStripeBilling) CustomerSubscriptions(ctx context.Context, customerID string) ([]*stripe.Subscription, error) {
+	return f.subs[customerID], nil
+}
+
+func stripeSubscription(id string, status stripe.SubscriptionStatus, priceID string, periodStart time.Time) *stripe.Subscription {
+	return &stripe.Subscription{
+		ID:      id,
+		Status:  status,
+		Created: periodStart.Unix(),
+		Items: &stripe.SubscriptionItemList{Data: []*stripe.SubscriptionItem{{
+			Price:              &stripe.Price{ID: priceID},
+			CurrentPeriodStart: periodStart.Unix(),
+			CurrentPeriodEnd:   periodStart.AddDate(0, 1, 0).Unix(),
+		}}},
+	}
+}
+
+type stripeSyncDeps struct {
+	subRepo     *mocks.MockSubscriptionRepository
+	creditsRepo *mocks.MockCreditsRepository
+	txRepo      *mocks.MockCreditTransactionRepository
+	billing     *fakeStripeBilling
+}
+
+func newStripeSyncWithMocks() (*StripeSyncService, *stripeSyncDeps) {
+	deps := &stripeSyncDeps{
+		subRepo:     new(mocks.MockSubscriptionRepository),
+		creditsRepo: new(mocks.MockCreditsRepository),
+		txRepo:      new(mocks.MockCreditTransactionRepository),
+		billing:     &fakeStripeBilling{subs: map[string][]*stripe.Subscription{}},
+	}
+	txRunner := new(mockTxRunner)
+	txRunner.On("Transaction", mock.Anything).Return(nil)
+
+	cfg := &config.Config{StripePriceBasic: "price_basic", StripePricePro: "price_pro"}
+	creditsService := NewCreditsServiceForTest(nil, txRunner, deps.creditsRepo, deps.txRepo, noHeldCredits())
+	stripeService := NewStripeServiceForTest(cfg, nil, txRunner, deps.subRepo, creditsService)
+	return NewStripeSyncService(stripeService, deps.billing, nil), deps
+}
+
+func TestStripeSyncService_Sync_MissedUpgrade(t *testing.T) {
+	userID := uuid.New()
+	periodStart := time.Now().Add(-time.Hour).Truncate(time.Second)
+	sync, deps := newStripeSyncWithMocks()
+	deps.billing.changed = []string{"cus_1"}
+	deps.billing.subs["cus_1"] = []*stripe.Subscription{
+		stripeSubscription("sub_1", stripe.SubscriptionStatusActive, "price_pro", periodStart),
+	}
+
+	deps.subRepo.On("FindByStripeCustomerID", mock.Anything, "cus_1").
+		Return(&models.Subscription{UserID: userID, StripeCustomerID: "cus_1", Tier: models.TierFree, Status: "active"}, nil)
+	deps.subRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *models.Subscription) bool {
+		return s.Tier == models.TierPro && *s.StripeSubscriptionID == "sub_1" && *s.StripePriceID == "price_pro" &&
+			s.CurrentPeriodStart.Equal(periodStart)
+	})).Return(nil)
+	deps.creditsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Credits{
+		UserID: userID, Balance: 3, MonthlyAllowance: models.TierCredits[models.TierFree], LastRefreshedAt: periodStart.Add(-24 * time.Hour),
+	}, nil)
+	deps.creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{
+		UserID: userID, Balance: 3, MonthlyAllowance: models.TierCredits[models.TierFree], LastRefreshedAt: periodStart.Add(-24 * time.Hour),
+	}, nil)
+	deps.creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	deps.creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierPro]).Return(nil)
+	deps.txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+		return tx.Description == "Upgraded to pro" && tx.Amount == models.TierCredits[models.TierPro]
+	})).Return(nil)
+	deps.txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+		return tx.Type == models.TransactionRefresh
+	})).Return(nil)
+
+	report, err := sync.Sync(context.Background(), StripeSyncOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Checked)
+	assert.Empty(t, report.Errors)
+	fields := make([]string, 0, len(report.Discrepancies))
+	for _, d := range report.Discrepancies {
+		assert.True(t, d.Fixed)
+		fields = append(fields, d.Field)
+	}
+	assert.ElementsMatch(t, []string{"subscription", "price", "tier", "monthlyAllowance", "lastRefreshedAt"}, fields)
+	assert.WithinDuration(t, time.Now().Add(-DefaultStripeSyncWindow), deps.billing.since, time.Minute)
+	deps.subRepo.AssertExpectations(t)
+	deps.txRepo.AssertExpectations(t)
+}
+
+func TestStripeSyncService_Sync_MissedCancellation(t *testing.T) {
+	userID := uuid.New()
+	subID := "sub_1"
+	sync, deps := newStripeSyncWithMocks()
+	deps.billing.changed = []string{"cus_1"}
+	deps.billing.subs["cus_1"] = []*stripe.Subscription{
+		stripeSubscription(subID, stripe.SubscriptionStatusCanceled, "price_basic", time.Now().AddDate(0, -1, 0)),
+	}
+
+	deps.subRepo.On("FindByStripeCustomerID", mock.Anything, "cus_1").Return(&models.Subscription{
+		UserID: userID, StripeCustomerID: "cus_1", StripeSubscriptionID: &subID, Tier: models.TierBasic, Status: "active",
+	}, nil)
+	deps.subRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *models.Subscription) bool {
+		return s.Tier == models.TierFree && s.Status == "canceled" && s.StripeSubscriptionID == nil
+	})).Return(nil)
+	deps.creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierFree]).Return(nil).Once()
+	deps.creditsRepo.On("FindByUserID", mock.Anything, userID).
+		Return(&models.Credits{UserID: userID, MonthlyAllowance: models.TierCredits[models.TierFree]}, nil)
+
+	report, err := sync.Sync(context.Background(), StripeSyncOptions{})
+
+	require.NoError(t, err)
+	require.Len(t, report.Discrepancies, 1)
+	assert.Equal(t, StripeDiscrepancy{
+		UserID: userID, CustomerID: "cus_1", Field: "tier", Local: "basic", Stripe: "free", Fixed: true,
+	}, report.Discrepancies[0])
+	deps.subRepo.AssertExpectations(t)
+	deps.creditsRepo.AssertExpectations(t)
+}
+
+func TestStripeSyncService_Sync_InSyncChangesNothing(t *testing.T) {
+	userID := uuid.New()
+	periodStart := time.Now().Add(-time.Hour).Truncate(time.Second)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+	subID, priceID := "sub_1", "price_basic"
+	sync, deps := newStripeSyncWithMocks()
+	deps.billing.subs["cus_1"] = []*stripe.Subscription{
+		stripeSubscription("sub_old", stripe.SubscriptionStatusCanceled, "price_pro", periodStart.AddDate(-1, 0, 0)),
+		stripeSubscription(subID, stripe.SubscriptionStatusActive, priceID, periodStart),
+	}
+
+	deps.subRepo.On("FindByStripeCustomerID", mock.Anything, "cus_1").Return(&models.Subscription{
+		UserID: userID, StripeCustomerID: "cus_1", StripeSubscriptionID: &subID, StripePriceID: &priceID,
+		Tier: models.TierBasic, Status: "active", CurrentPeriodStart: &periodStart, CurrentPeriodEnd: &periodEnd,
+	}, nil)
+	deps.creditsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Credits{
+		UserID: userID, MonthlyAllowance: models.TierCredits[models.TierBasic], LastRefreshedAt: periodStart.Add(time.Minute),
+	}, nil)
+
+	report, err := sync.Sync(context.Background(), StripeSyncOptions{All: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Checked)
+	assert.Empty(t, report.Discrepancies)
+	assert.Nil(t, report.Since)
+	deps.subRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	deps.creditsRepo.AssertNotCalled(t, "UpdateAllowance", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStripeSyncService_Sync_DryRun(t *testing.T) {
+	userID := uuid.New()
+	sync, deps := newStripeSyncWithMocks()
+	deps.billing.changed = []string{"cus_1", "cus_unknown"}
+	deps.billing.subs["cus_1"] = []*stripe.Subscription{
+		stripeSubscription("sub_1", stripe.SubscriptionStatusPastDue, "price_basic", time.Now().Add(-time.Hour)),
+	}
+
+	deps.subRepo.On("FindByStripeCustomerID", mock.Anything, "cus_1").
+		Return(&models.Subscription{UserID: userID, StripeCustomerID: "cus_1", Tier: models.TierFree, Status: "active"}, nil)
+	deps.subRepo.On("FindByStripeCustomerID", mock.Anything, "cus_unknown").Return(nil, repository.ErrNotFound)
+	deps.creditsRepo.On("FindByUserID", mock.Anything, userID).
+		Return(&models.Credits{UserID: userID, MonthlyAllowance: models.TierCredits[models.TierFree]}, nil)
+
+	report, err := sync.Sync(context.Background(), StripeSyncOptions{DryRun: true})
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []string{"cus_unknown"}, report.Unknown)
+	assert.NotEmpty(t, report.Discrepancies)
+	for _, d := range report.Discrepancies {
+		assert.False(t, d.Fixed)
+	}
+	deps.subRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	deps.creditsRepo.AssertNotCalled(t, "UpdateAllowance", mock.Anything, mock.Anything, mock.Anything)
+	deps.txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestStripeSyncService_Sync_RejectsOldWindow(t *testing.T) {
+	sync, _ := newStripeSyncWithMocks()
+
+	_, err := sync.Sync(context.Background(), StripeSyncOptions{Since: time.Now().AddDate(0, 0, -31)})
+
+	assert.ErrorIs(t, err, ErrInvalidStripeSyncWindow)
+}
+
+func TestCurrentStripeSubscription(t *testing.T) {
+	now := time.Now()
+	old := stripeSubscription("sub_old", stripe.SubscriptionStatusActive, "price_basic", now.AddDate(0, -2, 0))
+	newer := stripeSubscription("sub_new", stripe.SubscriptionStatusTrialing, "price_pro", now)
+	ended := stripeSubscription("sub_ended", stripe.SubscriptionStatusCanceled, "price_pro", now.Add(time.Hour))
+
+	assert.Equal(t, newer, CurrentStripeSubscription([]*stripe.Subscription{old, newer, ended}))
+	assert.Nil(t, CurrentStripeSubscription([]*stripe.Subscription{ended}))
+	assert.Nil(t, CurrentStripeSubscription(nil))
+}
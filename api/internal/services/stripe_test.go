@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 
+	"ling-app/api/internal/analytics"
 	"ling-app/api/internal/config"
 	"ling-app/api/internal/models"
 	"ling-app/api/internal/repository"
@@ -53,6 +55,7 @@ func NewStripeServiceForTest(
 		txRunner:       txRunner,
 		subRepo:        subRepo,
 		creditsService: creditsService,
+		tracker:        analytics.Nop{},
 	}
 }
 
@@ -153,16 +156,120 @@ func TestStripeService_handleSubscriptionUpdated(t *testing.T) {
 		})).Return(nil)
 
 		// Credits service mock - UpdateAllowance is called
-		creditsService := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		creditsService := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 
 		// Mock the UpdateAllowance dependency
 		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierPro]).Return(nil)
 
+		// Without a known period the upgrade grants the whole difference
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 100}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == models.TierCredits[models.TierPro]-models.TierCredits[models.TierBasic]
+		})).Return(nil)
+
 		service := NewStripeServiceForTest(cfg, nil, nil, subRepo, creditsService)
 		err := service.handleSubscriptionUpdated(data)
 
 		assert.NoError(t, err)
 		subRepo.AssertExpectations(t)
+		txRepo.AssertExpectations(t)
+	})
+
+	t.Run("schedules a downgrade for the end of the period", func(t *testing.T) {
+		subRepo := new(mocks.MockSubscriptionRepository)
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+
+		cfg := &config.Config{
+			StripePriceBasic: "price_basic",
+			StripePricePro:   "price_pro",
+		}
+
+		periodStart := time.Now().Add(-10 * 24 * time.Hour).Truncate(time.Second)
+		periodEnd := periodStart.Add(30 * 24 * time.Hour)
+		existingSub := &models.Subscription{
+			UserID:               userID,
+			StripeSubscriptionID: &stripeSubID,
+			Tier:                 models.TierPro,
+			Status:               "active",
+		}
+
+		webhookData := map[string]interface{}{
+			"id":     stripeSubID,
+			"status": "active",
+			"items": map[string]interface{}{
+				"data": []map[string]interface{}{
+					{
+						"price":                map[string]interface{}{"id": "price_basic"},
+						"current_period_start": periodStart.Unix(),
+						"current_period_end":   periodEnd.Unix(),
+					},
+				},
+			},
+		}
+		data, _ := json.Marshal(webhookData)
+
+		subRepo.On("FindByStripeSubscriptionID", mock.Anything, stripeSubID).Return(existingSub, nil)
+		subRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		// The allowance stays at Pro's until the downgrade takes effect
+		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierPro]).Return(nil)
+
+		creditsService := NewCreditsServiceForTest(nil, nil, creditsRepo, txRepo, nil)
+		service := NewStripeServiceForTest(cfg, nil, nil, subRepo, creditsService)
+
+		assert.NoError(t, service.handleSubscriptionUpdated(data))
+		assert.Equal(t, models.TierPro, existingSub.Tier)
+		if assert.NotNil(t, existingSub.PendingTier) {
+			assert.Equal(t, models.TierBasic, *existingSub.PendingTier)
+			assert.True(t, periodEnd.Equal(*existingSub.PendingTierAt))
+		}
+		txRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("going back to the current plan calls off a scheduled downgrade", func(t *testing.T) {
+		subRepo := new(mocks.MockSubscriptionRepository)
+		creditsRepo := new(mocks.MockCreditsRepository)
+
+		cfg := &config.Config{
+			StripePriceBasic: "price_basic",
+			StripePricePro:   "price_pro",
+		}
+
+		pending := models.TierBasic
+		pendingAt := time.Now().Add(24 * time.Hour)
+		existingSub := &models.Subscription{
+			UserID:               userID,
+			StripeSubscriptionID: &stripeSubID,
+			Tier:                 models.TierPro,
+			Status:               "active",
+			PendingTier:          &pending,
+			PendingTierAt:        &pendingAt,
+		}
+
+		webhookData := map[string]interface{}{
+			"id":     stripeSubID,
+			"status": "active",
+			"items": map[string]interface{}{
+				"data": []map[string]interface{}{
+					{"price": map[string]interface{}{"id": "price_pro"}},
+				},
+			},
+		}
+		data, _ := json.Marshal(webhookData)
+
+		subRepo.On("FindByStripeSubscriptionID", mock.Anything, stripeSubID).Return(existingSub, nil)
+		subRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierPro]).Return(nil)
+
+		creditsService := NewCreditsServiceForTest(nil, nil, creditsRepo, nil, nil)
+		service := NewStripeServiceForTest(cfg, nil, nil, subRepo, creditsService)
+
+		assert.NoError(t, service.handleSubscriptionUpdated(data))
+		assert.Equal(t, models.TierPro, existingSub.Tier)
+		assert.Nil(t, existingSub.PendingTier)
+		assert.Nil(t, existingSub.PendingTierAt)
 	})
 
 	t.Run("returns nil when subscription not found", func(t *testing.T) {
@@ -212,7 +319,7 @@ func TestStripeService_handleSubscriptionDeleted(t *testing.T) {
 			return s.Tier == models.TierFree && s.Status == "canceled" && s.StripeSubscriptionID == nil
 		})).Return(nil)
 
-		creditsService := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		creditsService := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, nil)
 		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierFree]).Return(nil)
 
 		service := NewStripeServiceForTest(cfg, nil, nil, subRepo, creditsService)
@@ -222,6 +329,41 @@ func TestStripeService_handleSubscriptionDeleted(t *testing.T) {
 		subRepo.AssertExpectations(t)
 	})
 
+	t.Run("keeps a grace period and defers the allowance change", func(t *testing.T) {
+		subRepo := new(mocks.MockSubscriptionRepository)
+		notifications := new(stubNotifications)
+		cfg := &config.Config{SubscriptionGraceDays: 7}
+
+		existingSub := &models.Subscription{
+			UserID:               userID,
+			StripeSubscriptionID: &stripeSubID,
+			Tier:                 models.TierPro,
+			Status:               "active",
+		}
+
+		webhookData := map[string]interface{}{
+			"id": stripeSubID,
+		}
+		data, _ := json.Marshal(webhookData)
+
+		subRepo.On("FindByStripeSubscriptionID", mock.Anything, stripeSubID).Return(existingSub, nil)
+		subRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *models.Subscription) bool {
+			return s.Tier == models.TierFree &&
+				s.GraceTier != nil && *s.GraceTier == models.TierPro &&
+				s.GraceEndsAt != nil && s.GraceEndsAt.After(time.Now().AddDate(0, 0, 6))
+		})).Return(nil)
+		notifications.On("Notify", userID, models.NotificationSubscriptionEnding, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		// No credits service: the allowance must not change until the grace period ends
+		service := NewStripeServiceForTest(cfg, nil, nil, subRepo, nil)
+		service.notifications = notifications
+		err := service.handleSubscriptionDeleted(data)
+
+		assert.NoError(t, err)
+		subRepo.AssertExpectations(t)
+		notifications.AssertExpectations(t)
+	})
+
 	t.Run("returns nil when subscription not found", func(t *testing.T) {
 		subRepo := new(mocks.MockSubscriptionRepository)
 		cfg := &config.Config{}
@@ -281,11 +423,11 @@ func TestStripeService_handleInvoicePaid(t *testing.T) {
 			UsedThisPeriod:   80,
 		}
 		txRunner.On("Transaction", mock.Anything).Return(nil)
-		creditsRepo.On("FindByUserID", mock.Anything, userID).Return(credits, nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(credits, nil)
 		creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
 		txRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		creditsService := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo)
+		creditsService := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, noHeldCredits())
 
 		service := NewStripeServiceForTest(cfg, nil, nil, subRepo, creditsService)
 		err := service.handleInvoicePaid(data)
@@ -294,6 +436,89 @@ func TestStripeService_handleInvoicePaid(t *testing.T) {
 		subRepo.AssertExpectations(t)
 	})
 
+	t.Run("applies a due downgrade before the refresh", func(t *testing.T) {
+		subRepo := new(mocks.MockSubscriptionRepository)
+		creditsRepo := new(mocks.MockCreditsRepository)
+		txRepo := new(mocks.MockCreditTransactionRepository)
+		txRunner := new(mockTxRunner)
+
+		pending := models.TierBasic
+		pendingAt := time.Now().Add(-time.Minute)
+		existingSub := &models.Subscription{
+			UserID:               userID,
+			StripeSubscriptionID: &stripeSubID,
+			Tier:                 models.TierPro,
+			Status:               "active",
+			PendingTier:          &pending,
+			PendingTierAt:        &pendingAt,
+		}
+
+		webhookData := map[string]interface{}{
+			"id":             "inv_123",
+			"billing_reason": "subscription_cycle",
+			"parent": map[string]interface{}{
+				"type": "subscription_details",
+				"subscription_details": map[string]interface{}{
+					"subscription": map[string]interface{}{
+						"id": stripeSubID,
+					},
+				},
+			},
+		}
+		data, _ := json.Marshal(webhookData)
+
+		subRepo.On("FindByStripeSubscriptionID", mock.Anything, stripeSubID).Return(existingSub, nil)
+		subRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *models.Subscription) bool {
+			return s.Tier == models.TierBasic && s.PendingTier == nil
+		})).Return(nil)
+		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierBasic]).Return(nil)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, MonthlyAllowance: models.TierCredits[models.TierBasic]}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+		txRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		creditsService := NewCreditsServiceForTest(nil, txRunner, creditsRepo, txRepo, noHeldCredits())
+		service := NewStripeServiceForTest(&config.Config{}, nil, nil, subRepo, creditsService)
+
+		assert.NoError(t, service.handleInvoicePaid(data))
+		subRepo.AssertExpectations(t)
+		creditsRepo.AssertExpectations(t)
+	})
+
+	t.Run("does not refresh for an upgrade's proration invoice", func(t *testing.T) {
+		subRepo := new(mocks.MockSubscriptionRepository)
+		creditsRepo := new(mocks.MockCreditsRepository)
+
+		existingSub := &models.Subscription{
+			UserID:               userID,
+			StripeSubscriptionID: &stripeSubID,
+			Tier:                 models.TierPro,
+			Status:               "active",
+		}
+
+		webhookData := map[string]interface{}{
+			"id":             "inv_123",
+			"billing_reason": "subscription_update",
+			"parent": map[string]interface{}{
+				"type": "subscription_details",
+				"subscription_details": map[string]interface{}{
+					"subscription": map[string]interface{}{
+						"id": stripeSubID,
+					},
+				},
+			},
+		}
+		data, _ := json.Marshal(webhookData)
+
+		subRepo.On("FindByStripeSubscriptionID", mock.Anything, stripeSubID).Return(existingSub, nil)
+
+		creditsService := NewCreditsServiceForTest(nil, nil, creditsRepo, nil, nil)
+		service := NewStripeServiceForTest(&config.Config{}, nil, nil, subRepo, creditsService)
+
+		assert.NoError(t, service.handleInvoicePaid(data))
+		creditsRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything)
+	})
+
 	t.Run("returns nil when subscription not found", func(t *testing.T) {
 		subRepo := new(mocks.MockSubscriptionRepository)
 		cfg := &config.Config{}
@@ -404,3 +629,28 @@ func (m *stripeTestTxRunner) Transaction(fc func(tx *gorm.DB) error, opts ...*sq
 	}
 	return args.Error(0)
 }
+
+func TestProratedUpgradeCredits(t *testing.T) {
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		from, to   int
+		start, end *time.Time
+		now        time.Time
+		want       int
+	}{
+		{name: "start of the period", from: 400, to: 1200, start: &start, end: &end, now: start, want: 800},
+		{name: "a third in", from: 400, to: 1200, start: &start, end: &end, now: start.Add(10 * 24 * time.Hour), want: 534},
+		{name: "period over", from: 400, to: 1200, start: &start, end: &end, now: end, want: 0},
+		{name: "unknown period", from: 400, to: 1200, now: start, want: 800},
+		{name: "not an upgrade", from: 1200, to: 400, start: &start, end: &end, now: start, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ProratedUpgradeCredits(tt.from, tt.to, tt.start, tt.end, tt.now))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// graceSweepBatchSize caps how many expired grace periods are queued per sweep
+const graceSweepBatchSize = 100
+
+// SubscriptionGraceWorker finalizes downgrades once a cancelled plan's grace
+// period is over. It sweeps the subscriptions table on an interval, so pending
+// downgrades survive restarts, and runs each finalization on the job queue.
+type SubscriptionGraceWorker struct {
+	exec          repository.Executor
+	subRepo       repository.SubscriptionRepository
+	credits       CreditsManager
+	notifications NotificationManager
+	queue         *jobs.Queue
+	interval      time.Duration
+
+	now func() time.Time
+
+	// Runtime supplies the tier allowances in force; nil uses the defaults
+	Runtime *RuntimeSettingsService
+}
+
+// NewSubscriptionGraceWorker creates a new subscription grace worker
+func NewSubscriptionGraceWorker(
+	database *db.DB,
+	subRepo repository.SubscriptionRepository,
+	credits CreditsManager,
+	notifications NotificationManager,
+	queue *jobs.Queue,
+	interval time.Duration,
+) *SubscriptionGraceWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &SubscriptionGraceWorker{
+		exec:          database.DB,
+		subRepo:       subRepo,
+		credits:       credits,
+		notifications: notifications,
+		queue:         queue,
+		interval:      interval,
+		now:           time.Now,
+	}
+}
+
+// NewSubscriptionGraceWorkerForTest creates a SubscriptionGraceWorker with injected dependencies for testing.
+func NewSubscriptionGraceWorkerForTest(
+	exec repository.Executor,
+	subRepo repository.SubscriptionRepository,
+	credits CreditsManager,
+	notifications NotificationManager,
+	queue *jobs.Queue,
+	interval time.Duration,
+) *SubscriptionGraceWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &SubscriptionGraceWorker{
+		exec:          exec,
+		subRepo:       subRepo,
+		credits:       credits,
+		notifications: notifications,
+		queue:         queue,
+		interval:      interval,
+		now:           time.Now,
+	}
+}
+
+// Start sweeps for expired grace periods until ctx is cancelled
+func (w *SubscriptionGraceWorker) Start(ctx context.Context) {
+	log.Printf("[SubscriptionGrace] Checking for expired grace periods every %s", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.Sweep()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep queues a finalization job for every subscription whose grace period has ended
+func (w *SubscriptionGraceWorker) Sweep() {
+	subs, err := w.subRepo.FindGraceExpired(w.exec, w.now(), graceSweepBatchSize)
+	if err != nil {
+		log.Printf("[SubscriptionGrace] Failed to find expired grace periods: %v", err)
+		return
+	}
+
+	for _, sub := range subs {
+		if w.queue == nil {
+			if err := w.Finalize(&sub); err != nil {
+				log.Printf("[SubscriptionGrace] Failed to finalize downgrade for user %s: %v", sub.UserID, err)
+			}
+			continue
+		}
+
+		err := w.queue.Enqueue(jobs.Job{
+			Name: "subscription-grace:" + sub.ID.String(),
+			Lane: jobs.LaneStandard,
+			Run: func(ctx context.Context) error {
+				return w.Finalize(&sub)
+			},
+		})
+		if err != nil {
+			log.Printf("[SubscriptionGrace] Failed to enqueue downgrade for user %s: %v", sub.UserID, err)
+		}
+	}
+}
+
+// Finalize applies the rest of the downgrade: the free-tier credit allowance
+// and a notification. It is a no-op if the grace period was already ended,
+// e.g. by a resubscribe or an earlier sweep.
+func (w *SubscriptionGraceWorker) Finalize(sub *models.Subscription) error {
+	ended, err := w.subRepo.EndGracePeriod(w.exec, sub.ID)
+	if err != nil {
+		return fmt.Errorf("end grace period: %w", err)
+	}
+	if !ended {
+		return nil
+	}
+
+	// The cancelled plan is free, unless an admin has overridden the tier
+	allowanceTier := models.TierFree
+	if sub.TierOverride != nil {
+		allowanceTier = *sub.TierOverride
+	}
+	if err := w.credits.UpdateAllowance(sub.UserID, allowanceTier); err != nil {
+		return fmt.Errorf("update allowance: %w", err)
+	}
+
+	previousTier := "paid"
+	if sub.GraceTier != nil {
+		previousTier = string(*sub.GraceTier)
+	}
+	log.Printf("[SubscriptionGrace] Finalized downgrade from %s for user %s", previousTier, sub.UserID)
+
+	w.notifyDowngraded(sub.UserID, previousTier)
+	return nil
+}
+
+func (w *SubscriptionGraceWorker) notifyDowngraded(userID uuid.UUID, previousTier string) {
+	if w.notifications == nil {
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Your %s grace period is over. Your account is now on the free plan with %d credits per month. "+
+			"Your conversations and stats are kept; upgrade any time to pick up where you left off.",
+		previousTier, w.Runtime.Current().TierAllowance(models.TierFree),
+	)
+	err := w.notifications.Notify(userID, models.NotificationSubscriptionDowngrade, "You're now on the free plan", body, models.JSONMap{
+		"previousTier": previousTier,
+	})
+	if err != nil {
+		log.Printf("[SubscriptionGrace] Failed to notify user %s of downgrade: %v", userID, err)
+	}
+}
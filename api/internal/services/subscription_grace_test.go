@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestSubscriptionGraceWorker_Finalize(t *testing.T) {
+	userID := uuid.New()
+	proTier := models.TierPro
+	graceEndsAt := time.Now().Add(-time.Minute)
+	sub := &models.Subscription{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Tier:        models.TierFree,
+		GraceTier:   &proTier,
+		GraceEndsAt: &graceEndsAt,
+	}
+
+	t.Run("applies free allowance and notifies", func(t *testing.T) {
+		subRepo := new(repomocks.MockSubscriptionRepository)
+		credits := new(stubCredits)
+		notifications := new(stubNotifications)
+
+		subRepo.On("EndGracePeriod", mock.Anything, sub.ID).Return(true, nil)
+		credits.On("UpdateAllowance", userID, models.TierFree).Return(nil)
+		notifications.On("Notify", userID, models.NotificationSubscriptionDowngrade, mock.Anything, mock.Anything,
+			models.JSONMap{"previousTier": "pro"}).Return(nil)
+
+		worker := NewSubscriptionGraceWorkerForTest(nil, subRepo, credits, notifications, nil, 0)
+		err := worker.Finalize(sub)
+
+		assert.NoError(t, err)
+		credits.AssertExpectations(t)
+		notifications.AssertExpectations(t)
+	})
+
+	t.Run("skips when grace period already ended", func(t *testing.T) {
+		subRepo := new(repomocks.MockSubscriptionRepository)
+		credits := new(stubCredits)
+		notifications := new(stubNotifications)
+
+		subRepo.On("EndGracePeriod", mock.Anything, sub.ID).Return(false, nil)
+
+		worker := NewSubscriptionGraceWorkerForTest(nil, subRepo, credits, notifications, nil, 0)
+		err := worker.Finalize(sub)
+
+		assert.NoError(t, err)
+		credits.AssertNotCalled(t, "UpdateAllowance", mock.Anything, mock.Anything)
+		notifications.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSubscriptionGraceWorker_Sweep(t *testing.T) {
+	now := time.Now()
+	subs := []models.Subscription{
+		{ID: uuid.New(), UserID: uuid.New()},
+		{ID: uuid.New(), UserID: uuid.New()},
+	}
+
+	subRepo := new(repomocks.MockSubscriptionRepository)
+	credits := new(stubCredits)
+
+	subRepo.On("FindGraceExpired", mock.Anything, now, graceSweepBatchSize).Return(subs, nil)
+	subRepo.On("EndGracePeriod", mock.Anything, mock.Anything).Return(true, nil)
+	credits.On("UpdateAllowance", mock.Anything, models.TierFree).Return(nil)
+
+	// Without a queue, finalization runs inline
+	worker := NewSubscriptionGraceWorkerForTest(nil, subRepo, credits, nil, nil, 0)
+	worker.now = func() time.Time { return now }
+	worker.Sweep()
+
+	subRepo.AssertNumberOfCalls(t, "EndGracePeriod", 2)
+	credits.AssertNumberOfCalls(t, "UpdateAllowance", 2)
+}
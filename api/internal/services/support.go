@@ -0,0 +1,366 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionSupportLookup          = "support.user_lookup"
+	AuditActionSupportGrantCredits    = "support.credits_granted"
+	AuditActionSupportRevokeSessions  = "support.sessions_revoked"
+	AuditActionSupportRequeueAnalysis = "support.analysis_requeued"
+	AuditActionSupportExport          = "support.user_exported"
+	AuditActionSupportDeductCredits   = "support.credits_deducted"
+	AuditActionSupportOverrideTier    = "support.tier_overridden"
+)
+
+// MaxSupportCreditGrant is the most credits one support grant can add; larger
+// amounts go through billing
+const MaxSupportCreditGrant = 5000
+
+var (
+	ErrSupportActorRequired  = errors.New("support actions need an operator")
+	ErrSupportReasonRequired = errors.New("support actions need a reason")
+	ErrInvalidCreditGrant    = fmt.Errorf("credit grants must be between 1 and %d", MaxSupportCreditGrant)
+	ErrAnalysisNotFailed     = errors.New("message analysis has not failed")
+	ErrAnalysisNotRequeuable = errors.New("message has no recording to analyze")
+
+	ErrInvalidCreditAdjustment = fmt.Errorf("credit adjustments must be between -%d and %d, and not 0", MaxSupportCreditGrant, MaxSupportCreditGrant)
+	ErrInvalidTier             = errors.New("unknown subscription tier")
+)
+
+// AccountSupport is the part of SupportService the admin API exposes
+type AccountSupport interface {
+	AdjustCredits(actor string, userID uuid.UUID, amount int, reason string) error
+	OverrideTier(actor string, userID uuid.UUID, tier *models.SubscriptionTier, reason string) (*models.Subscription, error)
+}
+
+// UserExport is everything stored about one account, for data requests
+type UserExport struct {
+	ExportedAt         time.Time                  `json:"exportedAt"`
+	User               *models.User               `json:"user"`
+	Credits            *models.Credits            `json:"credits,omitempty"`
+	CreditTransactions []models.CreditTransaction `json:"creditTransactions"`
+	Threads            []models.Thread            `json:"threads"` // archived ones included, with their messages
+}
+
+// SupportService carries out the support team's account actions. Every
+// action, including lookups and failed attempts, is written to the audit log
+// under the operator who ran it.
+type SupportService struct {
+	exec         repository.Executor
+	users        AdminUserProvider
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	threadRepo   repository.ThreadRepository
+	messageRepo  repository.MessageRepository
+	creditTxRepo repository.CreditTransactionRepository
+	credits      CreditsManager
+	analyzer     PronunciationAnalyzer
+	audit        AuditLogger
+
+	// Subscriptions stores tier overrides; OverrideTier needs it
+	Subscriptions repository.SubscriptionRepository
+
+	now func() time.Time
+}
+
+// NewSupportService creates a new support service
+func NewSupportService(
+	database *db.DB,
+	users AdminUserProvider,
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	creditTxRepo repository.CreditTransactionRepository,
+	credits CreditsManager,
+	analyzer PronunciationAnalyzer,
+	audit AuditLogger,
+) *SupportService {
+	return &SupportService{
+		exec:         database.DB,
+		users:        users,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		threadRepo:   threadRepo,
+		messageRepo:  messageRepo,
+		creditTxRepo: creditTxRepo,
+		credits:      credits,
+		analyzer:     analyzer,
+		audit:        audit,
+		now:          time.Now,
+	}
+}
+
+// NewSupportServiceForTest creates a SupportService with injected dependencies for testing.
+func NewSupportServiceForTest(
+	exec repository.Executor,
+	users AdminUserProvider,
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	creditTxRepo repository.CreditTransactionRepository,
+	credits CreditsManager,
+	analyzer PronunciationAnalyzer,
+	audit AuditLogger,
+) *SupportService {
+	return &SupportService{
+		exec:         exec,
+		users:        users,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		threadRepo:   threadRepo,
+		messageRepo:  messageRepo,
+		creditTxRepo: creditTxRepo,
+		credits:      credits,
+		analyzer:     analyzer,
+		audit:        audit,
+		now:          time.Now,
+	}
+}
+
+// FindUser returns the admin view of the account with this email
+func (s *SupportService) FindUser(actor, email string) (*AdminUserView, error) {
+	if actor == "" {
+		return nil, ErrSupportActorRequired
+	}
+	details := models.JSONMap{"email": email}
+
+	user, err := s.userRepo.FindByEmail(s.exec, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		s.record(AuditActionSupportLookup, actor, details, err)
+		return nil, err
+	}
+	details["userId"] = user.ID.String()
+
+	view, err := s.users.GetUser(user.ID)
+	s.record(AuditActionSupportLookup, actor, details, err)
+	return view, err
+}
+
+// GrantCredits adds credits to an account as a goodwill or make-good grant.
+// The reason goes on the credit transaction the user sees.
+func (s *SupportService) GrantCredits(actor string, userID uuid.UUID, amount int, reason string) error {
+	if err := checkSupportRequest(actor, reason); err != nil {
+		return err
+	}
+	details := models.JSONMap{"userId": userID.String(), "amount": amount, "reason": reason}
+	if amount < 1 || amount > MaxSupportCreditGrant {
+		s.record(AuditActionSupportGrantCredits, actor, details, ErrInvalidCreditGrant)
+		return ErrInvalidCreditGrant
+	}
+
+	err := s.credits.AddCredits(userID, amount, "Support grant: "+reason)
+	s.record(AuditActionSupportGrantCredits, actor, details, err)
+	return err
+}
+
+// AdjustCredits adds credits to an account, or with a negative amount takes
+// them away (a grant made in error, credits obtained by abuse). Additions
+// are support grants; either way the reason goes on the credit transaction.
+func (s *SupportService) AdjustCredits(actor string, userID uuid.UUID, amount int, reason string) error {
+	if amount > 0 {
+		return s.GrantCredits(actor, userID, amount, reason)
+	}
+	if err := checkSupportRequest(actor, reason); err != nil {
+		return err
+	}
+	details := models.JSONMap{"userId": userID.String(), "amount": amount, "reason": reason}
+	if amount == 0 || amount < -MaxSupportCreditGrant {
+		s.record(AuditActionSupportDeductCredits, actor, details, ErrInvalidCreditAdjustment)
+		return ErrInvalidCreditAdjustment
+	}
+
+	err := s.credits.DeductCredits(userID, -amount, "support", "Support deduction: "+reason)
+	s.record(AuditActionSupportDeductCredits, actor, details, err)
+	return err
+}
+
+// OverrideTier gives the user a tier regardless of what they pay for, or with
+// a nil tier goes back to their paid one. Users who never subscribed get a
+// free subscription to carry the override. The credit allowance follows at
+// once.
+func (s *SupportService) OverrideTier(actor string, userID uuid.UUID, tier *models.SubscriptionTier, reason string) (*models.Subscription, error) {
+	if err := checkSupportRequest(actor, reason); err != nil {
+		return nil, err
+	}
+	details := models.JSONMap{"userId": userID.String(), "tier": nil, "reason": reason}
+	if tier != nil {
+		details["tier"] = string(*tier)
+		if _, ok := models.TierCredits[*tier]; !ok {
+			s.record(AuditActionSupportOverrideTier, actor, details, ErrInvalidTier)
+			return nil, ErrInvalidTier
+		}
+	}
+
+	sub, err := s.overrideTier(userID, tier)
+	s.record(AuditActionSupportOverrideTier, actor, details, err)
+	return sub, err
+}
+
+func (s *SupportService) overrideTier(userID uuid.UUID, tier *models.SubscriptionTier) (*models.Subscription, error) {
+	if _, err := s.userRepo.FindByID(s.exec, userID); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.Subscriptions.FindByUserID(s.exec, userID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		sub = &models.Subscription{UserID: userID, Tier: models.TierFree, Status: "active", TierOverride: tier}
+		err = s.Subscriptions.Create(s.exec, sub)
+	case err == nil:
+		sub.TierOverride = tier
+		err = s.Subscriptions.Save(s.exec, sub)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save subscription: %w", err)
+	}
+
+	if err := s.credits.UpdateAllowance(userID, sub.EffectiveTier()); err != nil {
+		return nil, fmt.Errorf("failed to update allowance: %w", err)
+	}
+	return sub, nil
+}
+
+// RevokeSessions signs the user out everywhere
+func (s *SupportService) RevokeSessions(actor string, userID uuid.UUID, reason string) error {
+	if err := checkSupportRequest(actor, reason); err != nil {
+		return err
+	}
+	details := models.JSONMap{"userId": userID.String(), "reason": reason}
+
+	if _, err := s.userRepo.FindByID(s.exec, userID); err != nil {
+		s.record(AuditActionSupportRevokeSessions, actor, details, err)
+		return err
+	}
+	err := s.sessionRepo.DeleteByUserID(s.exec, userID)
+	s.record(AuditActionSupportRevokeSessions, actor, details, err)
+	return err
+}
+
+// RequeueAnalysis analyzes a voice message whose pronunciation analysis
+// failed again. Long-form messages, analyzed in chunks, aren't covered.
+func (s *SupportService) RequeueAnalysis(actor string, messageID uuid.UUID, reason string) error {
+	if err := checkSupportRequest(actor, reason); err != nil {
+		return err
+	}
+	details := models.JSONMap{"messageId": messageID.String(), "reason": reason}
+
+	err := s.requeueAnalysis(messageID)
+	s.record(AuditActionSupportRequeueAnalysis, actor, details, err)
+	return err
+}
+
+func (s *SupportService) requeueAnalysis(messageID uuid.UUID) error {
+	message, err := s.messageRepo.FindByID(s.exec, messageID)
+	if err != nil {
+		return err
+	}
+	if message.PronunciationStatus != "failed" {
+		return ErrAnalysisNotFailed
+	}
+	if message.Role != "user" || message.AudioURL == nil || message.Kind == models.MessageKindLongForm {
+		return ErrAnalysisNotRequeuable
+	}
+
+	thread, err := s.threadRepo.FindByID(s.exec, message.ThreadID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.messageRepo.UpdatePronunciationStatus(s.exec, message.ID, "pending"); err != nil {
+		return fmt.Errorf("failed to mark analysis pending: %w", err)
+	}
+	scoringText := message.Content
+	if message.ExpectedText != nil {
+		scoringText = *message.ExpectedText
+	}
+	s.analyzer.Enqueue(message.ThreadID, message.ID, *message.AudioURL, scoringText, PhonemeLanguage(ThreadLanguage(thread)))
+	return nil
+}
+
+// ExportUser gathers the account's data for a data request
+func (s *SupportService) ExportUser(actor string, userID uuid.UUID, reason string) (*UserExport, error) {
+	if err := checkSupportRequest(actor, reason); err != nil {
+		return nil, err
+	}
+	details := models.JSONMap{"userId": userID.String(), "reason": reason}
+
+	export, err := s.exportUser(userID)
+	if err == nil {
+		details["threads"] = len(export.Threads)
+	}
+	s.record(AuditActionSupportExport, actor, details, err)
+	return export, err
+}
+
+func (s *SupportService) exportUser(userID uuid.UUID) (*UserExport, error) {
+	view, err := s.users.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	export := &UserExport{ExportedAt: s.now().UTC(), User: view.User, Credits: view.Credits}
+
+	// -1 lifts the limit: the export has the whole history
+	if export.CreditTransactions, err = s.creditTxRepo.FindByUserID(s.exec, userID, -1, 0); err != nil {
+		return nil, fmt.Errorf("failed to get credit transactions: %w", err)
+	}
+
+	active, err := s.threadRepo.FindByUserID(s.exec, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get threads: %w", err)
+	}
+	archived, err := s.threadRepo.FindArchivedByUserID(s.exec, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived threads: %w", err)
+	}
+	export.Threads = append(active, archived...)
+	for i := range export.Threads {
+		messages, err := s.messageRepo.FindByThreadID(s.exec, export.Threads[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages: %w", err)
+		}
+		export.Threads[i].Messages = messages
+	}
+	return export, nil
+}
+
+// checkSupportRequest makes sure a change names who made it and why
+func checkSupportRequest(actor, reason string) error {
+	if actor == "" {
+		return ErrSupportActorRequired
+	}
+	if strings.TrimSpace(reason) == "" {
+		return ErrSupportReasonRequired
+	}
+	return nil
+}
+
+// record writes a support action to the audit log, as a failure if err is set
+func (s *SupportService) record(action, actor string, details models.JSONMap, err error) {
+	if s.audit == nil {
+		return
+	}
+	outcome := models.AuditOutcomeSuccess
+	if err != nil {
+		outcome = models.AuditOutcomeFailure
+		details["error"] = err.Error()
+	}
+	s.audit.Record(&models.AuditLog{
+		Action:  action,
+		Actor:   actor,
+		Outcome: outcome,
+		Details: details,
+	})
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+// auditedAs expects one audit entry for the action with the outcome
+func auditedAs(auditRepo *repomocks.MockAuditLogRepository, action string, outcome models.AuditOutcome) {
+	auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *models.AuditLog) bool {
+		return e.Action == action && e.Outcome == outcome && e.Actor == "lingctl:sam"
+	})).Return(nil).Once()
+}
+
+func TestSupportService_GrantCredits(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("adds the credits and audits the grant", func(t *testing.T) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		creditTxRepo := new(repomocks.MockCreditTransactionRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 10}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool { return c.Balance == 60 })).Return(nil)
+		creditTxRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == 50 && tx.Description == "Support grant: outage make-good"
+		})).Return(nil)
+		auditedAs(auditRepo, AuditActionSupportGrantCredits, models.AuditOutcomeSuccess)
+
+		credits := NewCreditsServiceForTest(nil, txRunner, creditsRepo, creditTxRepo, nil)
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, nil, nil, nil, credits, nil, NewAuditServiceForTest(nil, auditRepo))
+
+		require.NoError(t, svc.GrantCredits("lingctl:sam", userID, 50, "outage make-good"))
+		creditsRepo.AssertExpectations(t)
+		creditTxRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("caps the amount and audits the refusal", func(t *testing.T) {
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		auditedAs(auditRepo, AuditActionSupportGrantCredits, models.AuditOutcomeFailure)
+
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, nil, nil, nil, nil, nil, NewAuditServiceForTest(nil, auditRepo))
+
+		assert.ErrorIs(t, svc.GrantCredits("lingctl:sam", userID, MaxSupportCreditGrant+1, "typo"), ErrInvalidCreditGrant)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("needs a reason", func(t *testing.T) {
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		assert.ErrorIs(t, svc.GrantCredits("lingctl:sam", userID, 50, " "), ErrSupportReasonRequired)
+	})
+}
+
+func TestSupportService_AdjustCredits(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("takes credits away and audits the deduction", func(t *testing.T) {
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		creditTxRepo := new(repomocks.MockCreditTransactionRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		creditsRepo.On("FindByUserIDForUpdate", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 100}, nil)
+		creditsRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *models.Credits) bool { return c.Balance == 70 })).Return(nil)
+		creditTxRepo.On("Create", mock.Anything, mock.MatchedBy(func(tx *models.CreditTransaction) bool {
+			return tx.Amount == -30 && tx.Description == "Support deduction: duplicate grant"
+		})).Return(nil)
+		auditedAs(auditRepo, AuditActionSupportDeductCredits, models.AuditOutcomeSuccess)
+
+		credits := NewCreditsServiceForTest(nil, txRunner, creditsRepo, creditTxRepo, nil)
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, nil, nil, nil, credits, nil, NewAuditServiceForTest(nil, auditRepo))
+
+		require.NoError(t, svc.AdjustCredits("lingctl:sam", userID, -30, "duplicate grant"))
+		creditsRepo.AssertExpectations(t)
+		creditTxRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("positive amounts are support grants", func(t *testing.T) {
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		auditedAs(auditRepo, AuditActionSupportGrantCredits, models.AuditOutcomeFailure)
+
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, nil, nil, nil, nil, nil, NewAuditServiceForTest(nil, auditRepo))
+
+		assert.ErrorIs(t, svc.AdjustCredits("lingctl:sam", userID, MaxSupportCreditGrant+1, "typo"), ErrInvalidCreditGrant)
+		auditRepo.AssertExpectations(t)
+	})
+
+	for _, amount := range []int{0, -MaxSupportCreditGrant - 1} {
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		auditedAs(auditRepo, AuditActionSupportDeductCredits, models.AuditOutcomeFailure)
+
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, nil, nil, nil, nil, nil, NewAuditServiceForTest(nil, auditRepo))
+
+		assert.ErrorIs(t, svc.AdjustCredits("lingctl:sam", userID, amount, "typo"), ErrInvalidCreditAdjustment)
+		auditRepo.AssertExpectations(t)
+	}
+}
+
+func TestSupportService_OverrideTier(t *testing.T) {
+	pro := models.TierPro
+
+	setup := func(userID uuid.UUID) (*SupportService, *repomocks.MockSubscriptionRepository, *repomocks.MockCreditsRepository, *repomocks.MockAuditLogRepository) {
+		userRepo := new(repomocks.MockUserRepository)
+		subsRepo := new(repomocks.MockSubscriptionRepository)
+		creditsRepo := new(repomocks.MockCreditsRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+
+		credits := NewCreditsServiceForTest(nil, nil, creditsRepo, nil, nil)
+		svc := NewSupportServiceForTest(nil, nil, userRepo, nil, nil, nil, nil, credits, nil, NewAuditServiceForTest(nil, auditRepo))
+		svc.Subscriptions = subsRepo
+		return svc, subsRepo, creditsRepo, auditRepo
+	}
+
+	t.Run("gives a user without a subscription the tier", func(t *testing.T) {
+		userID := uuid.New()
+		svc, subsRepo, creditsRepo, auditRepo := setup(userID)
+		subsRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+		subsRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *models.Subscription) bool {
+			return s.UserID == userID && s.Tier == models.TierFree && s.StripeCustomerID == "" && *s.TierOverride == models.TierPro
+		})).Return(nil)
+		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierPro]).Return(nil)
+		auditedAs(auditRepo, AuditActionSupportOverrideTier, models.AuditOutcomeSuccess)
+
+		sub, err := svc.OverrideTier("lingctl:sam", userID, &pro, "beta tester")
+		require.NoError(t, err)
+		assert.Equal(t, models.TierPro, sub.EffectiveTier())
+		subsRepo.AssertExpectations(t)
+		creditsRepo.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("clearing goes back to the paid tier", func(t *testing.T) {
+		userID := uuid.New()
+		svc, subsRepo, creditsRepo, auditRepo := setup(userID)
+		subsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Subscription{UserID: userID, Tier: models.TierBasic, TierOverride: &pro}, nil)
+		subsRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *models.Subscription) bool { return s.TierOverride == nil })).Return(nil)
+		creditsRepo.On("UpdateAllowance", mock.Anything, userID, models.TierCredits[models.TierBasic]).Return(nil)
+		auditedAs(auditRepo, AuditActionSupportOverrideTier, models.AuditOutcomeSuccess)
+
+		sub, err := svc.OverrideTier("lingctl:sam", userID, nil, "trial over")
+		require.NoError(t, err)
+		assert.Equal(t, models.TierBasic, sub.EffectiveTier())
+		creditsRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an unknown tier", func(t *testing.T) {
+		userID := uuid.New()
+		svc, subsRepo, _, auditRepo := setup(userID)
+		auditedAs(auditRepo, AuditActionSupportOverrideTier, models.AuditOutcomeFailure)
+		gold := models.SubscriptionTier("gold")
+
+		_, err := svc.OverrideTier("lingctl:sam", userID, &gold, "typo")
+		assert.ErrorIs(t, err, ErrInvalidTier)
+		subsRepo.AssertNotCalled(t, "FindByUserID", mock.Anything, mock.Anything)
+		auditRepo.AssertExpectations(t)
+	})
+}
+
+func TestSupportService_RevokeSessions(t *testing.T) {
+	userID := uuid.New()
+	userRepo := new(repomocks.MockUserRepository)
+	sessionRepo := new(repomocks.MockSessionRepository)
+	auditRepo := new(repomocks.MockAuditLogRepository)
+	userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID}, nil)
+	sessionRepo.On("DeleteByUserID", mock.Anything, userID).Return(nil)
+	auditedAs(auditRepo, AuditActionSupportRevokeSessions, models.AuditOutcomeSuccess)
+
+	svc := NewSupportServiceForTest(nil, nil, userRepo, sessionRepo, nil, nil, nil, nil, nil, NewAuditServiceForTest(nil, auditRepo))
+
+	require.NoError(t, svc.RevokeSessions("lingctl:sam", userID, "account takeover report"))
+	sessionRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
+}
+
+func TestSupportService_RequeueAnalysis(t *testing.T) {
+	threadID := uuid.New()
+	audioKey := "user/recording.webm"
+
+	t.Run("requeues a failed analysis in the thread's language", func(t *testing.T) {
+		message := &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", Content: "hola", AudioURL: &audioKey, PronunciationStatus: "failed"}
+		threadRepo := new(repomocks.MockThreadRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		analyzer := new(stubAnalyzer)
+		threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, Language: "es"}, nil)
+		messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+		messageRepo.On("UpdatePronunciationStatus", mock.Anything, message.ID, "pending").Return(nil)
+		analyzer.On("Enqueue", threadID, message.ID, audioKey, "hola", "es-es").Return()
+		auditedAs(auditRepo, AuditActionSupportRequeueAnalysis, models.AuditOutcomeSuccess)
+
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, threadRepo, messageRepo, nil, nil, analyzer, NewAuditServiceForTest(nil, auditRepo))
+
+		require.NoError(t, svc.RequeueAnalysis("lingctl:sam", message.ID, "ML outage"))
+		analyzer.AssertExpectations(t)
+		auditRepo.AssertExpectations(t)
+	})
+
+	t.Run("leaves other analyses alone", func(t *testing.T) {
+		message := &models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", AudioURL: &audioKey, PronunciationStatus: "complete"}
+		messageRepo := new(repomocks.MockMessageRepository)
+		auditRepo := new(repomocks.MockAuditLogRepository)
+		messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+		auditedAs(auditRepo, AuditActionSupportRequeueAnalysis, models.AuditOutcomeFailure)
+
+		svc := NewSupportServiceForTest(nil, nil, nil, nil, nil, messageRepo, nil, nil, nil, NewAuditServiceForTest(nil, auditRepo))
+
+		assert.ErrorIs(t, svc.RequeueAnalysis("lingctl:sam", message.ID, "ML outage"), ErrAnalysisNotFailed)
+		messageRepo.AssertNotCalled(t, "UpdatePronunciationStatus", mock.Anything, mock.Anything, mock.Anything)
+		auditRepo.AssertExpectations(t)
+	})
+}
+
+func TestSupportService_ExportUser(t *testing.T) {
+	userID := uuid.New()
+	active, archived := models.Thread{ID: uuid.New(), UserID: userID}, models.Thread{ID: uuid.New(), UserID: userID}
+
+	userRepo := new(repomocks.MockUserRepository)
+	creditsRepo := new(repomocks.MockCreditsRepository)
+	signalRepo := new(repomocks.MockSignupSignalRepository)
+	threadRepo := new(repomocks.MockThreadRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	creditTxRepo := new(repomocks.MockCreditTransactionRepository)
+	auditRepo := new(repomocks.MockAuditLogRepository)
+	userRepo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, Email: "ana@example.com"}, nil)
+	creditsRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Credits{UserID: userID, Balance: 5}, nil)
+	signalRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+	creditTxRepo.On("FindByUserID", mock.Anything, userID, -1, 0).Return([]models.CreditTransaction{{UserID: userID, Amount: 5}}, nil)
+	threadRepo.On("FindByUserID", mock.Anything, userID).Return([]models.Thread{active}, nil)
+	threadRepo.On("FindArchivedByUserID", mock.Anything, userID).Return([]models.Thread{archived}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, active.ID).Return([]models.Message{{ThreadID: active.ID, Content: "hola"}}, nil)
+	messageRepo.On("FindByThreadID", mock.Anything, archived.ID).Return([]models.Message{}, nil)
+	auditedAs(auditRepo, AuditActionSupportExport, models.AuditOutcomeSuccess)
+
+	users := NewAdminUserServiceForTest(nil, userRepo, creditsRepo, signalRepo)
+	svc := NewSupportServiceForTest(nil, users, userRepo, nil, threadRepo, messageRepo, creditTxRepo, nil, nil, NewAuditServiceForTest(nil, auditRepo))
+
+	export, err := svc.ExportUser("lingctl:sam", userID, "data request #12")
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", export.User.Email)
+	assert.Equal(t, 5, export.Credits.Balance)
+	assert.Len(t, export.CreditTransactions, 1)
+	require.Len(t, export.Threads, 2, "archived threads are exported too")
+	assert.Equal(t, "hola", export.Threads[0].Messages[0].Content)
+	auditRepo.AssertExpectations(t)
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrThreadEnded    = errors.New("thread has ended")
+	ErrThreadNotEnded = errors.New("thread has not ended")
+)
+
+// ThreadWrapUpPrompt asks the assistant to bring the thread to a close on its
+// last turn, once the tier's per-thread cap is reached
+func ThreadWrapUpPrompt() client.ConversationMessage {
+	return client.ConversationMessage{
+		Role: "system",
+		Content: "This conversation has reached its length limit and this is your last reply. " +
+			"Respond to the learner briefly, then wrap up warmly: recap what they practiced, " +
+			"and suggest they start a new conversation to keep going. Don't ask a question.",
+	}
+}
+
+// threadRecapPrompt asks for the recap that opens a continuation thread
+const threadRecapPrompt = "Summarize the conversation so far in two or three sentences, " +
+	"in the language it was held in, addressed to the learner as the opening line of a new conversation " +
+	"that picks up where this one left off (e.g. \"Last time we talked about...\"). " +
+	"Mention the topics and the scenario, not their mistakes. Reply with the summary only."
+
+// ThreadContinuer starts a new thread that carries on from an ended one
+type ThreadContinuer interface {
+	Continue(userID, threadID uuid.UUID) (*models.Thread, error)
+}
+
+// ThreadContinuationService starts continuation threads: the ended thread's
+// settings and unfinished goal carry over, and the new thread opens with an
+// assistant recap of the old one so the LLM keeps the context without the
+// whole history.
+type ThreadContinuationService struct {
+	exec        repository.Executor
+	txRunner    TxRunner
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+	openAI      client.OpenAIClient
+
+	now func() time.Time
+}
+
+// NewThreadContinuationService creates a new thread continuation service
+func NewThreadContinuationService(
+	database *db.DB,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	openAI client.OpenAIClient,
+) *ThreadContinuationService {
+	return &ThreadContinuationService{
+		exec:        database.DB,
+		txRunner:    database.DB,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		openAI:      openAI,
+		now:         time.Now,
+	}
+}
+
+// NewThreadContinuationServiceForTest creates a ThreadContinuationService with injected dependencies for testing.
+func NewThreadContinuationServiceForTest(
+	exec repository.Executor,
+	txRunner TxRunner,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	openAI client.OpenAIClient,
+) *ThreadContinuationService {
+	return &ThreadContinuationService{
+		exec:        exec,
+		txRunner:    txRunner,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		openAI:      openAI,
+		now:         time.Now,
+	}
+}
+
+// Continue starts a thread that carries on from the user's ended thread.
+// Without a recap (the LLM failed) the new thread simply starts empty.
+func (s *ThreadContinuationService) Continue(userID, threadID uuid.UUID) (*models.Thread, error) {
+	ended, err := s.threadRepo.FindByIDAndUserID(s.exec, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if ended.EndedAt == nil {
+		return nil, ErrThreadNotEnded
+	}
+
+	messages, err := s.messageRepo.FindByThreadID(s.exec, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch messages: %w", err)
+	}
+	recap := s.recap(messages)
+
+	thread := &models.Thread{
+		UserID:          userID,
+		Name:            ended.Name,
+		SuggestReplies:  ended.SuggestReplies,
+		SpeechOnly:      ended.SpeechOnly,
+		Language:        ended.Language,
+		Locale:          ended.Locale,
+		ReplyLength:     ended.ReplyLength,
+		SpeechRate:      ended.SpeechRate,
+		ContinuedFromID: &ended.ID,
+		CreatedAt:       s.now(),
+	}
+	if ended.GoalCompletedAt == nil {
+		thread.Goal = ended.Goal
+	}
+
+	err = s.txRunner.Transaction(func(tx *gorm.DB) error {
+		if err := s.threadRepo.Create(tx, thread); err != nil {
+			return fmt.Errorf("create thread: %w", err)
+		}
+		if recap == "" {
+			return nil
+		}
+		opening := models.Message{
+			ID:        uuid.New(),
+			ThreadID:  thread.ID,
+			Role:      "assistant",
+			Content:   recap,
+			Timestamp: s.now(),
+		}
+		if err := s.messageRepo.Create(tx, &opening); err != nil {
+			return fmt.Errorf("create recap: %w", err)
+		}
+		thread.Messages = []models.Message{opening}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// recap summarizes messages for the continuation's opening line, or returns
+// "" when there is nothing to summarize or the LLM failed
+func (s *ThreadContinuationService) recap(messages []models.Message) string {
+	if len(messages) == 0 || s.openAI == nil {
+		return ""
+	}
+
+	history := make([]client.ConversationMessage, 0, len(messages)+1)
+	for _, msg := range messages {
+		history = append(history, client.ConversationMessage{Role: msg.Role, Content: msg.Content})
+	}
+	history = append(history, client.ConversationMessage{Role: "system", Content: threadRecapPrompt})
+
+	recap, err := s.openAI.Generate(history)
+	if err != nil {
+		log.Printf("Error summarizing thread for continuation: %v", err)
+		return ""
+	}
+	return strings.TrimSpace(recap)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/client"
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestThreadContinuationService_Continue(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	endedAt := time.Now()
+	name, goal, rate := "At the market", "buy three apples", 0.8
+	ended := &models.Thread{
+		ID: threadID, UserID: userID, Name: &name, Goal: &goal, Locale: "es-MX",
+		SpeechRate: &rate, SuggestReplies: true, EndedAt: &endedAt,
+	}
+	history := []models.Message{
+		{Role: "user", Content: "Hola, quiero manzanas"},
+		{Role: "assistant", Content: "¡Claro! ¿Cuántas quiere?"},
+	}
+
+	t.Run("carries the settings over and opens with a recap", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(ended, nil)
+		messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(history, nil)
+		openAI.On("Generate", mock.MatchedBy(func(messages []client.ConversationMessage) bool {
+			return len(messages) == 3 && messages[2].Content == threadRecapPrompt
+		})).Return(" La última vez compraste fruta en el mercado. ", nil)
+		threadRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		messageRepo.On("Create", mock.Anything, mock.MatchedBy(func(msg *models.Message) bool {
+			return msg.Role == "assistant" && msg.Content == "La última vez compraste fruta en el mercado."
+		})).Return(nil)
+
+		thread, err := NewThreadContinuationServiceForTest(nil, txRunner, threadRepo, messageRepo, openAI).Continue(userID, threadID)
+
+		require.NoError(t, err)
+		assert.Equal(t, threadID, *thread.ContinuedFromID)
+		assert.Equal(t, &name, thread.Name)
+		assert.Equal(t, &goal, thread.Goal)
+		assert.Equal(t, "es-MX", thread.Locale)
+		assert.Equal(t, &rate, thread.SpeechRate)
+		assert.True(t, thread.SuggestReplies)
+		assert.Nil(t, thread.EndedAt)
+		require.Len(t, thread.Messages, 1)
+		messageRepo.AssertExpectations(t)
+	})
+
+	t.Run("starts empty when the recap fails", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		txRunner := new(mockTxRunner)
+		txRunner.On("Transaction", mock.Anything).Return(nil)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(ended, nil)
+		messageRepo.On("FindByThreadID", mock.Anything, threadID).Return(history, nil)
+		openAI.On("Generate", mock.Anything).Return("", errors.New("rate limited"))
+		threadRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		thread, err := NewThreadContinuationServiceForTest(nil, txRunner, threadRepo, messageRepo, openAI).Continue(userID, threadID)
+
+		require.NoError(t, err)
+		assert.Empty(t, thread.Messages)
+		messageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("only continues ended threads", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+
+		_, err := NewThreadContinuationServiceForTest(nil, nil, threadRepo, nil, nil).Continue(userID, threadID)
+
+		assert.ErrorIs(t, err, ErrThreadNotEnded)
+	})
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrThreadShareNotFound = errors.New("thread share not found")
+	ErrTooManyShareViewers = errors.New("too many viewers on this thread share")
+)
+
+// ThreadSharesSubscriber is the thread share hub's name on the event bus
+const ThreadSharesSubscriber = "thread_shares"
+
+// threadShareMaxViewers caps the open streams of one share
+const threadShareMaxViewers = 5
+
+// threadWatchBuffer is how many updates a viewer can fall behind before its
+// stream is closed; the viewer reconnects and starts from a fresh snapshot
+const threadWatchBuffer = 32
+
+// Thread update types, sent as the SSE event name
+const (
+	ThreadUpdateMessage  = "message"  // A new message in the thread
+	ThreadUpdateAnalysis = "analysis" // A message's pronunciation status changed
+)
+
+// Why a viewer's stream was closed
+const (
+	ThreadWatchRevoked = "revoked"
+	ThreadWatchExpired = "expired"
+	ThreadWatchBehind  = "behind"
+	ThreadWatchRestart = "restart" // The server is shutting down; reconnect
+)
+
+// ThreadSharer defines the interface for share-for-review links
+type ThreadSharer interface {
+	Create(userID, threadID uuid.UUID) (*models.ThreadShare, error)
+	List(userID, threadID uuid.UUID) ([]models.ThreadShare, error)
+	Revoke(userID, threadID, shareID uuid.UUID) error
+	Watch(token string) (*SharedThread, *ThreadWatch, error)
+	Check(watch *ThreadWatch) error
+}
+
+// SharedThread is what a viewer sees on opening a share: the thread so far
+type SharedThread struct {
+	Thread    *models.Thread `json:"thread"`
+	ExpiresAt time.Time      `json:"expiresAt"`
+}
+
+// ThreadUpdate is one change to a shared thread
+type ThreadUpdate struct {
+	Type    string          `json:"type"`
+	Message *models.Message `json:"message"`
+}
+
+// ThreadWatch is one viewer's live stream of a shared thread
+type ThreadWatch struct {
+	Token     string
+	ExpiresAt time.Time
+
+	watcher *threadWatcher
+	close   func()
+}
+
+// Updates delivers the thread's changes as they happen
+func (w *ThreadWatch) Updates() <-chan ThreadUpdate {
+	return w.watcher.updates
+}
+
+// Done is closed when the share is revoked, the viewer falls too far behind
+// or the server shuts down
+func (w *ThreadWatch) Done() <-chan struct{} {
+	return w.watcher.done
+}
+
+// Reason is why Done was closed
+func (w *ThreadWatch) Reason() string {
+	w.watcher.mu.Lock()
+	defer w.watcher.mu.Unlock()
+	return w.watcher.reason
+}
+
+// Close stops the stream. The viewer's handler calls it when they disconnect.
+func (w *ThreadWatch) Close() {
+	w.close()
+}
+
+type threadWatcher struct {
+	shareID uuid.UUID
+	updates chan ThreadUpdate
+	done    chan struct{}
+
+	mu     sync.Mutex
+	reason string
+}
+
+// stop closes the stream for reason; only the first reason is kept
+func (w *threadWatcher) stop(reason string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.reason == "" {
+		w.reason = reason
+		close(w.done)
+	}
+}
+
+// ThreadShareService issues share-for-review links and streams shared
+// threads to their viewers. The viewers are held in memory: a viewer only
+// gets the updates published on the instance they are connected to.
+type ThreadShareService struct {
+	exec        repository.Executor
+	shareRepo   repository.ThreadShareRepository
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+
+	mu       sync.Mutex
+	watchers map[uuid.UUID]map[*threadWatcher]struct{} // By thread ID
+
+	now func() time.Time
+}
+
+// NewThreadShareService creates a new thread share service
+func NewThreadShareService(
+	database *db.DB,
+	shareRepo repository.ThreadShareRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+) *ThreadShareService {
+	return &ThreadShareService{
+		exec:        database.DB,
+		shareRepo:   shareRepo,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		watchers:    make(map[uuid.UUID]map[*threadWatcher]struct{}),
+		now:         time.Now,
+	}
+}
+
+// NewThreadShareServiceForTest creates a ThreadShareService with injected dependencies for testing.
+func NewThreadShareServiceForTest(
+	exec repository.Executor,
+	shareRepo repository.ThreadShareRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+) *ThreadShareService {
+	return &ThreadShareService{
+		exec:        exec,
+		shareRepo:   shareRepo,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		watchers:    make(map[uuid.UUID]map[*threadWatcher]struct{}),
+		now:         time.Now,
+	}
+}
+
+// Create issues a link to one of the user's threads that works for
+// models.ThreadShareLifetime
+func (s *ThreadShareService) Create(userID, threadID uuid.UUID) (*models.ThreadShare, error) {
+	if _, err := s.threadRepo.FindByIDAndUserID(s.exec, threadID, userID); err != nil {
+		return nil, err
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	share := &models.ThreadShare{
+		ThreadID:  threadID,
+		UserID:    userID,
+		Token:     token,
+		ExpiresAt: now.Add(models.ThreadShareLifetime),
+		CreatedAt: now,
+	}
+	if err := s.shareRepo.Create(s.exec, share); err != nil {
+		return nil, fmt.Errorf("create thread share: %w", err)
+	}
+	return share, nil
+}
+
+// List returns the thread's links that still work
+func (s *ThreadShareService) List(userID, threadID uuid.UUID) ([]models.ThreadShare, error) {
+	if _, err := s.threadRepo.FindByIDAndUserID(s.exec, threadID, userID); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.FindActiveByThreadID(s.exec, threadID, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("list thread shares: %w", err)
+	}
+	if shares == nil {
+		shares = []models.ThreadShare{}
+	}
+	return shares, nil
+}
+
+// Revoke stops a link from working and closes its open streams
+func (s *ThreadShareService) Revoke(userID, threadID, shareID uuid.UUID) error {
+	if _, err := s.threadRepo.FindByIDAndUserID(s.exec, threadID, userID); err != nil {
+		return err
+	}
+
+	err := s.shareRepo.Revoke(s.exec, shareID, threadID, s.now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrThreadShareNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("revoke thread share: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for w := range s.watchers[threadID] {
+		if w.shareID == shareID {
+			w.stop(ThreadWatchRevoked)
+			s.removeLocked(threadID, w)
+		}
+	}
+	return nil
+}
+
+// Watch opens a share for a viewer: the thread so far, and a stream of its
+// changes. The stream starts before the snapshot is loaded, so an update can
+// repeat a message the snapshot already has; viewers replace messages by ID.
+func (s *ThreadShareService) Watch(token string) (*SharedThread, *ThreadWatch, error) {
+	share, err := s.activeShare(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	watcher := &threadWatcher{
+		shareID: share.ID,
+		updates: make(chan ThreadUpdate, threadWatchBuffer),
+		done:    make(chan struct{}),
+	}
+	if err := s.add(share, watcher); err != nil {
+		return nil, nil, err
+	}
+	watch := &ThreadWatch{
+		Token:     token,
+		ExpiresAt: share.ExpiresAt,
+		watcher:   watcher,
+		close:     func() { s.remove(share.ThreadID, watcher) },
+	}
+
+	thread, err := s.threadRepo.FindByIDWithMessages(s.exec, share.ThreadID)
+	if err != nil {
+		watch.Close()
+		return nil, nil, fmt.Errorf("find shared thread: %w", err)
+	}
+	return &SharedThread{Thread: thread, ExpiresAt: share.ExpiresAt}, watch, nil
+}
+
+// Check returns ErrThreadShareNotFound once a watched share is revoked.
+// Streams poll it so a link revoked on another instance still closes.
+func (s *ThreadShareService) Check(watch *ThreadWatch) error {
+	_, err := s.activeShare(watch.Token)
+	return err
+}
+
+// CloseAll closes every open stream, so a shutting-down server isn't held
+// open by viewers; they reconnect to another instance
+func (s *ThreadShareService) CloseAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for threadID, watchers := range s.watchers {
+		for w := range watchers {
+			w.stop(ThreadWatchRestart)
+		}
+		delete(s.watchers, threadID)
+	}
+}
+
+// Subscribe streams new messages and analysis results to the viewers of
+// their thread
+func (s *ThreadShareService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, ThreadSharesSubscriber, func(ctx context.Context, e events.MessageProcessed) error {
+		return s.publishMessages(e.ThreadID, e.MessageID)
+	})
+	events.Subscribe(bus, ThreadSharesSubscriber, func(ctx context.Context, e events.AnalysisCompleted) error {
+		return s.publishAnalysis(e.ThreadID, e.MessageID)
+	})
+	events.Subscribe(bus, ThreadSharesSubscriber, func(ctx context.Context, e events.AnalysisFailed) error {
+		return s.publishAnalysis(e.ThreadID, e.MessageID)
+	})
+}
+
+// publishMessages sends the processed message and the replies after it
+func (s *ThreadShareService) publishMessages(threadID, messageID uuid.UUID) error {
+	if !s.watched(threadID) {
+		return nil
+	}
+
+	messages, err := s.messageRepo.FindByThreadID(s.exec, threadID)
+	if err != nil {
+		return fmt.Errorf("find messages: %w", err)
+	}
+	for i := range messages {
+		if messages[i].ID != messageID {
+			continue
+		}
+		updates := make([]ThreadUpdate, 0, len(messages)-i)
+		for j := i; j < len(messages); j++ {
+			updates = append(updates, ThreadUpdate{Type: ThreadUpdateMessage, Message: &messages[j]})
+		}
+		s.broadcast(threadID, updates)
+		break
+	}
+	return nil
+}
+
+// publishAnalysis sends a message whose pronunciation status changed
+func (s *ThreadShareService) publishAnalysis(threadID, messageID uuid.UUID) error {
+	if !s.watched(threadID) {
+		return nil
+	}
+
+	message, err := s.messageRepo.FindByID(s.exec, messageID)
+	if err != nil {
+		return fmt.Errorf("find message: %w", err)
+	}
+	s.broadcast(threadID, []ThreadUpdate{{Type: ThreadUpdateAnalysis, Message: message}})
+	return nil
+}
+
+// broadcast queues updates for every viewer of the thread, closing the
+// streams of viewers too far behind to take them
+func (s *ThreadShareService) broadcast(threadID uuid.UUID, updates []ThreadUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for w := range s.watchers[threadID] {
+		for _, update := range updates {
+			select {
+			case w.updates <- update:
+				continue
+			default:
+			}
+			log.Printf("[ThreadShares] Closing a viewer of thread %s that fell behind", threadID)
+			w.stop(ThreadWatchBehind)
+			s.removeLocked(threadID, w)
+			break
+		}
+	}
+}
+
+// activeShare finds the share for token, if it still works
+func (s *ThreadShareService) activeShare(token string) (*models.ThreadShare, error) {
+	share, err := s.shareRepo.FindByToken(s.exec, token)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrThreadShareNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find thread share: %w", err)
+	}
+	if !share.Active(s.now()) {
+		return nil, ErrThreadShareNotFound
+	}
+	return share, nil
+}
+
+func (s *ThreadShareService) watched(threadID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.watchers[threadID]) > 0
+}
+
+func (s *ThreadShareService) add(share *models.ThreadShare, watcher *threadWatcher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	viewers := 0
+	for w := range s.watchers[share.ThreadID] {
+		if w.shareID == share.ID {
+			viewers++
+		}
+	}
+	if viewers >= threadShareMaxViewers {
+		return ErrTooManyShareViewers
+	}
+
+	if s.watchers[share.ThreadID] == nil {
+		s.watchers[share.ThreadID] = make(map[*threadWatcher]struct{})
+	}
+	s.watchers[share.ThreadID][watcher] = struct{}{}
+	return nil
+}
+
+func (s *ThreadShareService) remove(threadID uuid.UUID, watcher *threadWatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(threadID, watcher)
+}
+
+func (s *ThreadShareService) removeLocked(threadID uuid.UUID, watcher *threadWatcher) {
+	delete(s.watchers[threadID], watcher)
+	if len(s.watchers[threadID]) == 0 {
+		delete(s.watchers, threadID)
+	}
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestThreadShareService_Create(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	now := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("issues a one-hour link", func(t *testing.T) {
+		shareRepo := new(repomocks.MockThreadShareRepository)
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID}, nil)
+		shareRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		service := NewThreadShareServiceForTest(nil, shareRepo, threadRepo, nil)
+		service.now = func() time.Time { return now }
+		share, err := service.Create(userID, threadID)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, share.Token)
+		assert.Equal(t, now.Add(time.Hour), share.ExpiresAt)
+	})
+
+	t.Run("only for the user's own threads", func(t *testing.T) {
+		threadRepo := new(repomocks.MockThreadRepository)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(nil, repository.ErrNotFound)
+
+		_, err := NewThreadShareServiceForTest(nil, nil, threadRepo, nil).Create(userID, threadID)
+
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestThreadShareService_Watch(t *testing.T) {
+	userID, threadID := uuid.New(), uuid.New()
+	now := time.Now()
+	share := &models.ThreadShare{ID: uuid.New(), ThreadID: threadID, UserID: userID, Token: "tok", ExpiresAt: now.Add(time.Hour)}
+	userMessage := models.Message{ID: uuid.New(), ThreadID: threadID, Role: "user", PronunciationStatus: "pending"}
+	reply := models.Message{ID: uuid.New(), ThreadID: threadID, Role: "assistant"}
+
+	newService := func() (*ThreadShareService, *repomocks.MockThreadShareRepository, *repomocks.MockMessageRepository) {
+		shareRepo := new(repomocks.MockThreadShareRepository)
+		threadRepo := new(repomocks.MockThreadRepository)
+		messageRepo := new(repomocks.MockMessageRepository)
+		shareRepo.On("FindByToken", mock.Anything, "tok").Return(share, nil)
+		threadRepo.On("FindByIDAndUserID", mock.Anything, threadID, userID).Return(&models.Thread{ID: threadID}, nil)
+		threadRepo.On("FindByIDWithMessages", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+		return NewThreadShareServiceForTest(nil, shareRepo, threadRepo, messageRepo), shareRepo, messageRepo
+	}
+
+	t.Run("streams new messages and analysis results", func(t *testing.T) {
+		service, _, messageRepo := newService()
+		messageRepo.On("FindByThreadID", mock.Anything, threadID).Return([]models.Message{userMessage, reply}, nil)
+		analyzed := userMessage
+		analyzed.PronunciationStatus = "complete"
+		messageRepo.On("FindByID", mock.Anything, userMessage.ID).Return(&analyzed, nil)
+		bus := events.NewBus()
+		service.Subscribe(bus)
+
+		shared, watch, err := service.Watch("tok")
+		require.NoError(t, err)
+		defer watch.Close()
+		assert.Equal(t, threadID, shared.Thread.ID)
+
+		bus.Publish(context.Background(), events.MessageProcessed{UserID: userID, ThreadID: threadID, MessageID: userMessage.ID})
+		bus.Publish(context.Background(), events.AnalysisCompleted{UserID: userID, ThreadID: threadID, MessageID: userMessage.ID})
+
+		var updates []ThreadUpdate
+		for range 3 {
+			updates = append(updates, <-watch.Updates())
+		}
+		assert.Equal(t, ThreadUpdateMessage, updates[0].Type)
+		assert.Equal(t, userMessage.ID, updates[0].Message.ID)
+		assert.Equal(t, reply.ID, updates[1].Message.ID)
+		assert.Equal(t, ThreadUpdateAnalysis, updates[2].Type)
+		assert.Equal(t, "complete", updates[2].Message.PronunciationStatus)
+	})
+
+	t.Run("skips threads nobody is watching", func(t *testing.T) {
+		service, _, messageRepo := newService()
+		bus := events.NewBus()
+		service.Subscribe(bus)
+
+		bus.Publish(context.Background(), events.MessageProcessed{UserID: userID, ThreadID: threadID, MessageID: userMessage.ID})
+
+		messageRepo.AssertNotCalled(t, "FindByThreadID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("revoking closes open streams", func(t *testing.T) {
+		service, shareRepo, _ := newService()
+		shareRepo.On("Revoke", mock.Anything, share.ID, threadID, mock.Anything).Return(nil)
+
+		_, watch, err := service.Watch("tok")
+		require.NoError(t, err)
+		require.NoError(t, service.Revoke(userID, threadID, share.ID))
+
+		select {
+		case <-watch.Done():
+			assert.Equal(t, ThreadWatchRevoked, watch.Reason())
+		default:
+			t.Fatal("stream still open after revoke")
+		}
+	})
+
+	t.Run("closes viewers that fall behind", func(t *testing.T) {
+		service, _, messageRepo := newService()
+		messageRepo.On("FindByID", mock.Anything, userMessage.ID).Return(&userMessage, nil)
+
+		_, watch, err := service.Watch("tok")
+		require.NoError(t, err)
+		for range threadWatchBuffer + 1 {
+			require.NoError(t, service.publishAnalysis(threadID, userMessage.ID))
+		}
+
+		<-watch.Done()
+		assert.Equal(t, ThreadWatchBehind, watch.Reason())
+	})
+
+	t.Run("caps viewers per link", func(t *testing.T) {
+		service, _, _ := newService()
+		for range threadShareMaxViewers {
+			_, _, err := service.Watch("tok")
+			require.NoError(t, err)
+		}
+
+		_, _, err := service.Watch("tok")
+		assert.ErrorIs(t, err, ErrTooManyShareViewers)
+	})
+
+	t.Run("rejects expired and revoked links", func(t *testing.T) {
+		revokedAt := now
+		for _, stale := range []*models.ThreadShare{
+			{ID: uuid.New(), ThreadID: threadID, Token: "old", ExpiresAt: now.Add(-time.Minute)},
+			{ID: uuid.New(), ThreadID: threadID, Token: "old", ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt},
+		} {
+			shareRepo := new(repomocks.MockThreadShareRepository)
+			shareRepo.On("FindByToken", mock.Anything, "old").Return(stale, nil)
+
+			_, _, err := NewThreadShareServiceForTest(nil, shareRepo, nil, nil).Watch("old")
+			assert.ErrorIs(t, err, ErrThreadShareNotFound)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Title generation limits. Past the rate, threads get a heuristic title
+// instead of waiting for the LLM.
+const (
+	TitleRequestsPerMinute = 30
+	titleBurst             = 10
+	heuristicTitleWords    = 6
+	heuristicTitleMaxRunes = 50
+)
+
+// ThreadTitler names untitled threads in the background
+type ThreadTitler interface {
+	RequestTitle(threadID uuid.UUID, userText, assistantText string)
+}
+
+// ThreadTitleService names threads after their first exchange. Requests are
+// deduplicated per thread and LLM calls are rate limited; when the LLM is
+// unavailable or over the limit, the title is taken from the user's first
+// sentence.
+type ThreadTitleService struct {
+	exec       repository.Executor
+	threadRepo repository.ThreadRepository
+	openAI     client.OpenAIClient
+	queue      *jobs.Queue
+
+	// Events receives ThreadTitled when a thread is named (optional)
+	Events *events.Bus
+
+	mu       sync.Mutex
+	inflight map[uuid.UUID]bool
+	tokens   float64 // LLM calls available now, refilled at TitleRequestsPerMinute
+	refilled time.Time
+
+	now func() time.Time
+}
+
+// NewThreadTitleService creates a new thread title service
+func NewThreadTitleService(
+	database *db.DB,
+	threadRepo repository.ThreadRepository,
+	openAI client.OpenAIClient,
+	queue *jobs.Queue,
+) *ThreadTitleService {
+	return &ThreadTitleService{
+		exec:       database.DB,
+		threadRepo: threadRepo,
+		openAI:     openAI,
+		queue:      queue,
+		inflight:   make(map[uuid.UUID]bool),
+		tokens:     titleBurst,
+		refilled:   time.Now(),
+		now:        time.Now,
+	}
+}
+
+// NewThreadTitleServiceForTest creates a ThreadTitleService with injected dependencies for testing.
+func NewThreadTitleServiceForTest(
+	exec repository.Executor,
+	threadRepo repository.ThreadRepository,
+	openAI client.OpenAIClient,
+	queue *jobs.Queue,
+	now func() time.Time,
+) *ThreadTitleService {
+	return &ThreadTitleService{
+		exec:       exec,
+		threadRepo: threadRepo,
+		openAI:     openAI,
+		queue:      queue,
+		inflight:   make(map[uuid.UUID]bool),
+		tokens:     titleBurst,
+		refilled:   now(),
+		now:        now,
+	}
+}
+
+// RequestTitle queues naming the thread unless a request for it is already
+// in flight. It never blocks on the LLM.
+func (s *ThreadTitleService) RequestTitle(threadID uuid.UUID, userText, assistantText string) {
+	s.mu.Lock()
+	if s.inflight[threadID] {
+		s.mu.Unlock()
+		return
+	}
+	s.inflight[threadID] = true
+	s.mu.Unlock()
+
+	if s.queue == nil {
+		go s.run(threadID, userText, assistantText)
+		return
+	}
+
+	err := s.queue.Enqueue(jobs.Job{
+		Name: "thread-title:" + threadID.String(),
+		Lane: jobs.LaneStandard,
+		Run: func(ctx context.Context) error {
+			return s.run(threadID, userText, assistantText)
+		},
+	})
+	if err != nil {
+		log.Printf("[ThreadTitle] Failed to queue title for thread %s: %v", threadID, err)
+		s.done(threadID)
+	}
+}
+
+// run names the thread if it still has no name
+func (s *ThreadTitleService) run(threadID uuid.UUID, userText, assistantText string) error {
+	defer s.done(threadID)
+
+	thread, err := s.threadRepo.FindByID(s.exec, threadID)
+	if err != nil {
+		return fmt.Errorf("find thread: %w", err)
+	}
+	if thread.Name != nil {
+		return nil
+	}
+
+	title := s.generate(threadID, assistantText)
+	if title == "" {
+		title = HeuristicTitle(userText)
+	}
+	if title == "" {
+		return nil
+	}
+
+	if err := s.threadRepo.UpdateName(s.exec, threadID, title); err != nil {
+		return fmt.Errorf("update thread name: %w", err)
+	}
+	s.Events.Publish(context.Background(), events.ThreadTitled{
+		UserID:   thread.UserID,
+		ThreadID: threadID,
+		Title:    title,
+	})
+	return nil
+}
+
+// generate asks the LLM for a title, or returns "" if it can't right now
+func (s *ThreadTitleService) generate(threadID uuid.UUID, assistantText string) string {
+	if s.openAI == nil || strings.TrimSpace(assistantText) == "" {
+		return ""
+	}
+	if !s.allow() {
+		log.Printf("[ThreadTitle] Rate limited; using a heuristic title for thread %s", threadID)
+		return ""
+	}
+
+	title, err := s.openAI.GenerateTitle(assistantText)
+	if errors.Is(err, ErrLLMDeferred) {
+		log.Printf("[ThreadTitle] Over the LLM budget; using a heuristic title for thread %s", threadID)
+		return ""
+	}
+	if err != nil {
+		log.Printf("[ThreadTitle] Failed to generate title for thread %s: %v", threadID, err)
+		return ""
+	}
+	return strings.TrimSpace(title)
+}
+
+// allow takes a token from the LLM call bucket if one is available
+func (s *ThreadTitleService) allow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.tokens += now.Sub(s.refilled).Minutes() * TitleRequestsPerMinute
+	if s.tokens > titleBurst {
+		s.tokens = titleBurst
+	}
+	s.refilled = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+func (s *ThreadTitleService) done(threadID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.inflight, threadID)
+	s.mu.Unlock()
+}
+
+// HeuristicTitle makes a title from the first sentence of text, capped at a
+// few words, e.g. "I'd like to order a coffee, please" -> "I'd like to
+// order a coffee"
+func HeuristicTitle(text string) string {
+	sentence := strings.TrimSpace(text)
+	if i := strings.IndexFunc(sentence, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || r == '\n'
+	}); i >= 0 {
+		sentence = sentence[:i]
+	}
+
+	words := strings.Fields(sentence)
+	if len(words) > heuristicTitleWords {
+		words = words[:heuristicTitleWords]
+	}
+	title := strings.TrimRightFunc(strings.Join(words, " "), func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+	if runes := []rune(title); len(runes) > heuristicTitleMaxRunes {
+		title = strings.TrimSpace(string(runes[:heuristicTitleMaxRunes]))
+	}
+	if title == "" {
+		return ""
+	}
+
+	runes := []rune(title)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/events"
+	"ling-app/api/internal/jobs"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+func TestThreadTitleService_Run(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("names the thread with the LLM", func(t *testing.T) {
+		threadID, userID := uuid.New(), uuid.New()
+		threadRepo := new(repomocks.MockThreadRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, UserID: userID}, nil)
+		openAI.On("GenerateTitle", "Sure, what size?").Return("Ordering Coffee", nil)
+		threadRepo.On("UpdateName", mock.Anything, threadID, "Ordering Coffee").Return(nil)
+
+		bus := events.NewBus()
+		var titled []events.ThreadTitled
+		events.Subscribe(bus, "test", func(ctx context.Context, e events.ThreadTitled) error {
+			titled = append(titled, e)
+			return nil
+		})
+
+		svc := NewThreadTitleServiceForTest(nil, threadRepo, openAI, nil, func() time.Time { return now })
+		svc.Events = bus
+		require.NoError(t, svc.run(threadID, "A coffee, please.", "Sure, what size?"))
+		threadRepo.AssertExpectations(t)
+		assert.Equal(t, []events.ThreadTitled{{UserID: userID, ThreadID: threadID, Title: "Ordering Coffee"}}, titled)
+	})
+
+	t.Run("skips threads that already have a name", func(t *testing.T) {
+		threadID := uuid.New()
+		name := "My thread"
+		threadRepo := new(repomocks.MockThreadRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID, Name: &name}, nil)
+
+		svc := NewThreadTitleServiceForTest(nil, threadRepo, openAI, nil, func() time.Time { return now })
+		require.NoError(t, svc.run(threadID, "Hello", "Hi!"))
+		openAI.AssertNotCalled(t, "GenerateTitle", mock.Anything)
+		threadRepo.AssertNotCalled(t, "UpdateName", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("falls back to the first user sentence when the LLM fails", func(t *testing.T) {
+		threadID := uuid.New()
+		threadRepo := new(repomocks.MockThreadRepository)
+		openAI := new(clientmocks.MockOpenAIClient)
+		threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil)
+		openAI.On("GenerateTitle", mock.Anything).Return("", errors.New("rate limited"))
+		threadRepo.On("UpdateName", mock.Anything, threadID, "Where is the train station").Return(nil)
+
+		svc := NewThreadTitleServiceForTest(nil, threadRepo, openAI, nil, func() time.Time { return now })
+		require.NoError(t, svc.run(threadID, "where is the train station? I'm lost.", "It's two blocks away."))
+		threadRepo.AssertExpectations(t)
+	})
+}
+
+func TestThreadTitleService_RateLimit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewThreadTitleServiceForTest(nil, nil, nil, nil, func() time.Time { return now })
+
+	for i := 0; i < titleBurst; i++ {
+		assert.True(t, svc.allow(), "burst call %d", i)
+	}
+	assert.False(t, svc.allow(), "bucket is empty")
+
+	now = now.Add(time.Minute / TitleRequestsPerMinute)
+	assert.True(t, svc.allow(), "refilled one call")
+	assert.False(t, svc.allow())
+}
+
+func TestThreadTitleService_RequestTitle_Dedupes(t *testing.T) {
+	threadID := uuid.New()
+	queue := jobs.NewQueue(jobs.Config{Workers: 1})
+	threadRepo := new(repomocks.MockThreadRepository)
+	threadRepo.On("FindByID", mock.Anything, threadID).Return(&models.Thread{ID: threadID}, nil).Once()
+	threadRepo.On("UpdateName", mock.Anything, threadID, "Hello there").Return(nil).Once()
+
+	svc := NewThreadTitleServiceForTest(nil, threadRepo, nil, queue, time.Now)
+	svc.RequestTitle(threadID, "Hello there", "")
+	svc.RequestTitle(threadID, "Hello there", "") // Still in flight; dropped
+	assert.Equal(t, 1, queue.Stats()[jobs.LaneStandard].Depth)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue.Start(ctx)
+	assert.Eventually(t, func() bool {
+		return queue.Stats()[jobs.LaneStandard].Completed == 1
+	}, time.Second, 5*time.Millisecond)
+	threadRepo.AssertExpectations(t)
+
+	// Finished requests don't block later ones
+	svc.mu.Lock()
+	assert.Empty(t, svc.inflight)
+	svc.mu.Unlock()
+}
+
+func TestHeuristicTitle(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"I'd like to order a coffee, please", "I'd like to order a coffee"},
+		{"hola! ¿qué tal?", "Hola"},
+		{"  where is the station?\nThanks", "Where is the station"},
+		{"...", ""},
+		{"", ""},
+		{"Supercalifragilisticexpialidocious-antidisestablishmentarianism-floccinaucinihilipilification", "Supercalifragilisticexpialidocious-antidisestablis"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, HeuristicTitle(tt.text), tt.text)
+	}
+}
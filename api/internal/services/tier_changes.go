@@ -0,0 +1,74 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"ling-app/api/internal/models"
+)
+
+// ProratedUpgradeCredits is what an upgrade mid-period grants: the difference
+// between the two plans' allowances, for the part of the period that is
+// left, rounded up. Without a known period the whole difference is granted.
+func ProratedUpgradeCredits(fromAllowance, toAllowance int, periodStart, periodEnd *time.Time, now time.Time) int {
+	diff := toAllowance - fromAllowance
+	if diff <= 0 {
+		return 0
+	}
+	if periodStart == nil || periodEnd == nil || !periodEnd.After(*periodStart) {
+		return diff
+	}
+
+	total := periodEnd.Sub(*periodStart)
+	left := min(periodEnd.Sub(now), total)
+	if left <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(diff) * float64(left) / float64(total)))
+}
+
+// changeTier moves a subscription to the tier Stripe now bills it for, and
+// returns the credits to grant for it. An upgrade applies straight away, with
+// its prorated credits; a downgrade is scheduled for the end of the current
+// period, or applies straight away if the period isn't known. Going back to
+// the current tier calls off a scheduled downgrade. The caller saves the
+// subscription.
+func (s *StripeService) changeTier(sub *models.Subscription, tier models.SubscriptionTier, now time.Time) int {
+	switch {
+	case tier == sub.Tier:
+		sub.PendingTier, sub.PendingTierAt = nil, nil
+		return 0
+
+	case tier.Rank() > sub.Tier.Rank():
+		settings := s.Runtime.Current()
+		grant := ProratedUpgradeCredits(
+			settings.TierAllowance(sub.Tier), settings.TierAllowance(tier),
+			sub.CurrentPeriodStart, sub.CurrentPeriodEnd, now,
+		)
+		sub.Tier = tier
+		sub.PendingTier, sub.PendingTierAt = nil, nil
+		return grant
+
+	case sub.CurrentPeriodEnd != nil && now.Before(*sub.CurrentPeriodEnd):
+		at := *sub.CurrentPeriodEnd
+		sub.PendingTier, sub.PendingTierAt = &tier, &at
+		return 0
+
+	default:
+		sub.Tier = tier
+		sub.PendingTier, sub.PendingTierAt = nil, nil
+		return 0
+	}
+}
+
+// applyDueTierChange moves a subscription to its scheduled tier once the
+// period it waited for has ended, reporting whether it did. The caller saves
+// the subscription.
+func applyDueTierChange(sub *models.Subscription, now time.Time) bool {
+	if !sub.TierChangeDue(now) {
+		return false
+	}
+	sub.Tier = *sub.PendingTier
+	sub.PendingTier, sub.PendingTierAt = nil, nil
+	return true
+}
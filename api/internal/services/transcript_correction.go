@@ -0,0 +1,171 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// MaxTranscriptLength is the longest corrected transcript accepted, in characters
+const MaxTranscriptLength = 2000
+
+// TranscriptCorrector defines the interface for correcting transcripts
+type TranscriptCorrector interface {
+	CorrectTranscript(userID, threadID, messageID uuid.UUID, content string) (*models.Message, error)
+}
+
+// PronunciationAnalyzer queues a recording for pronunciation analysis
+type PronunciationAnalyzer interface {
+	Enqueue(threadID, messageID uuid.UUID, audioKey, expectedText, language string)
+}
+
+// TranscriptCorrectionService applies users' corrections to the transcripts
+// of their voice messages. When the correction changes what the recording is
+// scored against, the phoneme stats its analysis added are taken back out and
+// the recording is analyzed again; the new results go into the stats like any
+// other analysis.
+type TranscriptCorrectionService struct {
+	exec        repository.Executor
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+	stats       *PhonemeStatsService
+	analyzer    PronunciationAnalyzer
+
+	now func() time.Time
+}
+
+// NewTranscriptCorrectionService creates a new transcript correction service.
+// Without an analyzer, corrected messages are left unscored.
+func NewTranscriptCorrectionService(
+	database *db.DB,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	stats *PhonemeStatsService,
+	analyzer PronunciationAnalyzer,
+) *TranscriptCorrectionService {
+	return &TranscriptCorrectionService{
+		exec:        database.DB,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		stats:       stats,
+		analyzer:    analyzer,
+		now:         time.Now,
+	}
+}
+
+// NewTranscriptCorrectionServiceForTest creates a TranscriptCorrectionService with injected dependencies for testing.
+func NewTranscriptCorrectionServiceForTest(
+	exec repository.Executor,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+	stats *PhonemeStatsService,
+	analyzer PronunciationAnalyzer,
+) *TranscriptCorrectionService {
+	return &TranscriptCorrectionService{
+		exec:        exec,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+		stats:       stats,
+		analyzer:    analyzer,
+		now:         time.Now,
+	}
+}
+
+// CorrectTranscript replaces the transcript of one of the user's voice
+// messages and returns the updated message. Messages still being analyzed
+// can't be corrected until the analysis finishes, and long-form messages,
+// whose chunks are scored against their own transcripts, can't be corrected.
+func (s *TranscriptCorrectionService) CorrectTranscript(userID, threadID, messageID uuid.UUID, content string) (*models.Message, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > MaxTranscriptLength {
+		return nil, ErrInvalidTranscript
+	}
+
+	thread, err := s.threadRepo.FindByIDAndUserID(s.exec, threadID, userID)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.messageRepo.FindByID(s.exec, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.ThreadID != threadID {
+		return nil, repository.ErrNotFound
+	}
+	if message.Role != "user" || message.Kind == models.MessageKindLongForm || message.Kind == models.MessageKindText {
+		return nil, ErrTranscriptNotEditable
+	}
+	if message.PronunciationStatus == "pending" {
+		return nil, ErrAnalysisPending
+	}
+	if content == message.Content {
+		return message, nil
+	}
+
+	now := s.now()
+	status := s.rescoreStatus(message, content)
+	if status != "" {
+		// Drop the old analysis before taking its stats out: if taking them
+		// out fails, the snapshot stays and the next analysis still replaces it
+		if err := s.messageRepo.ResetPronunciation(s.exec, message.ID, status, now); err != nil {
+			return nil, fmt.Errorf("failed to reset pronunciation: %w", err)
+		}
+		if s.stats != nil {
+			if err := s.stats.ReverseMessageResults(userID, message, ThreadLanguage(thread)); err != nil {
+				return nil, fmt.Errorf("failed to reverse phoneme stats: %w", err)
+			}
+		}
+	}
+	if err := s.messageRepo.UpdateContent(s.exec, message.ID, content, now); err != nil {
+		return nil, fmt.Errorf("failed to update transcript: %w", err)
+	}
+
+	if status == "pending" {
+		scoringText := content
+		if message.ExpectedText != nil {
+			scoringText = *message.ExpectedText
+		}
+		s.analyzer.Enqueue(threadID, message.ID, *message.AudioURL, scoringText, PhonemeLanguage(ThreadLanguage(thread)))
+	}
+
+	return s.messageRepo.FindByID(s.exec, message.ID)
+}
+
+// rescoreStatus returns the pronunciation status a message takes on with its
+// corrected transcript, or "" if its analysis still holds. Free conversation
+// is scored against the transcript itself, so any change of words needs a new
+// analysis. Practice lines are scored against the line, so only a change in
+// whether the user stuck to it does. Recordings already deleted, or with no
+// analyzer to score them, are left unscored.
+func (s *TranscriptCorrectionService) rescoreStatus(message *models.Message, content string) string {
+	if message.ExpectedText != nil {
+		diverges := TranscriptDiverges(*message.ExpectedText, content)
+		if diverges == (message.PronunciationStatus == "skipped_divergent") {
+			return ""
+		}
+		if diverges {
+			return "skipped_divergent"
+		}
+	} else {
+		// Until the first correction, scoring used the raw Whisper text
+		scored := message.Content
+		if message.RawTranscript != nil && message.TranscriptCorrectedAt == nil {
+			scored = *message.RawTranscript
+		}
+		if SameWords(scored, content) {
+			return ""
+		}
+	}
+
+	if message.AudioURL == nil || s.analyzer == nil {
+		return "none"
+	}
+	return "pending"
+}
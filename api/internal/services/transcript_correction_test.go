@@ -0,0 +1,227 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+	"ling-app/api/internal/testutil/factory"
+)
+
+type stubAnalyzer struct {
+	mock.Mock
+}
+
+func (m *stubAnalyzer) Enqueue(threadID, messageID uuid.UUID, audioKey, expectedText, language string) {
+	m.Called(threadID, messageID, audioKey, expectedText, language)
+}
+
+type correctionDeps struct {
+	threadRepo  *repomocks.MockThreadRepository
+	messageRepo *repomocks.MockMessageRepository
+	statsRepo   *repomocks.MockPhonemeStatsRepository
+	subsRepo    *repomocks.MockPhonemeSubstitutionRepository
+	snapshots   *repomocks.MockPhonemeStatsSnapshotRepository
+	analyzer    *stubAnalyzer
+}
+
+func newCorrectionService(userID uuid.UUID, message *models.Message) (*TranscriptCorrectionService, *correctionDeps) {
+	deps := &correctionDeps{
+		threadRepo:  new(repomocks.MockThreadRepository),
+		messageRepo: new(repomocks.MockMessageRepository),
+		statsRepo:   new(repomocks.MockPhonemeStatsRepository),
+		subsRepo:    new(repomocks.MockPhonemeSubstitutionRepository),
+		snapshots:   new(repomocks.MockPhonemeStatsSnapshotRepository),
+		analyzer:    new(stubAnalyzer),
+	}
+	deps.threadRepo.On("FindByIDAndUserID", mock.Anything, message.ThreadID, userID).
+		Return(&models.Thread{ID: message.ThreadID, UserID: userID}, nil)
+	deps.messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+
+	stats := NewPhonemeStatsServiceForTest(nil, deps.statsRepo, deps.subsRepo)
+	stats.Snapshots = deps.snapshots
+	service := NewTranscriptCorrectionServiceForTest(nil, deps.threadRepo, deps.messageRepo, stats, deps.analyzer)
+	return service, deps
+}
+
+func voiceMessage(content string) *models.Message {
+	audioKey := "user/thread/message.webm"
+	return &models.Message{
+		ID:                  uuid.New(),
+		ThreadID:            uuid.New(),
+		Role:                "user",
+		Content:             content,
+		AudioURL:            &audioKey,
+		HasAudio:            true,
+		PronunciationStatus: "complete",
+	}
+}
+
+func TestTranscriptCorrectionService_CorrectTranscript_Reanalyzes(t *testing.T) {
+	userID := uuid.New()
+	message := voiceMessage("I sink so")
+	service, deps := newCorrectionService(userID, message)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	deps.messageRepo.On("ResetPronunciation", mock.Anything, message.ID, "pending", now).Return(nil)
+	deps.snapshots.On("Take", mock.Anything, message.ID).Return(&models.PhonemeStatsSnapshot{
+		MessageID: message.ID,
+		UserID:    userID,
+		Tally: models.PhonemeTally{
+			Phonemes: map[string]models.PhonemeCounts{"s": {TotalAttempts: 1, CorrectCount: 1}},
+		},
+	}, nil)
+	deps.statsRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PhonemeStats) bool {
+		return s.UserID == userID && s.Phoneme == "s" && s.TotalAttempts == -1 && s.CorrectCount == -1
+	})).Return(nil)
+	deps.messageRepo.On("UpdateContent", mock.Anything, message.ID, "I think so", now).Return(nil)
+	deps.analyzer.On("Enqueue", message.ThreadID, message.ID, *message.AudioURL, "I think so", "en-us")
+
+	_, err := service.CorrectTranscript(userID, message.ThreadID, message.ID, "  I think so ")
+	require.NoError(t, err)
+
+	deps.messageRepo.AssertExpectations(t)
+	deps.statsRepo.AssertExpectations(t)
+	deps.analyzer.AssertExpectations(t)
+}
+
+func TestTranscriptCorrectionService_CorrectTranscript_ReanalyzesInThreadLanguage(t *testing.T) {
+	userID := uuid.New()
+	message := voiceMessage("pero lo que quiero")
+	analysis, err := analysisToMap(factory.Analysis().Match("p", "e").Substitute("ɾ", "r").Match("o").Build())
+	require.NoError(t, err)
+	message.PronunciationAnalysis = analysis
+	threadRepo := new(repomocks.MockThreadRepository)
+	messageRepo := new(repomocks.MockMessageRepository)
+	statsRepo := new(repomocks.MockPhonemeStatsRepository)
+	snapshots := new(repomocks.MockPhonemeStatsSnapshotRepository)
+	analyzer := new(stubAnalyzer)
+	threadRepo.On("FindByIDAndUserID", mock.Anything, message.ThreadID, userID).
+		Return(&models.Thread{ID: message.ThreadID, UserID: userID, Language: "es"}, nil)
+	snapshots.On("Take", mock.Anything, message.ID).Return(nil, repository.ErrNotFound)
+	statsRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil).Maybe()
+	messageRepo.On("FindByID", mock.Anything, message.ID).Return(message, nil)
+	messageRepo.On("ResetPronunciation", mock.Anything, message.ID, "pending", mock.Anything).Return(nil)
+	messageRepo.On("UpdateContent", mock.Anything, message.ID, "perro lo que quiero", mock.Anything).Return(nil)
+	analyzer.On("Enqueue", message.ThreadID, message.ID, *message.AudioURL, "perro lo que quiero", "es-es")
+
+	stats := NewPhonemeStatsServiceForTest(nil, statsRepo, new(repomocks.MockPhonemeSubstitutionRepository))
+	stats.Snapshots = snapshots
+	service := NewTranscriptCorrectionServiceForTest(nil, threadRepo, messageRepo, stats, analyzer)
+
+	_, err = service.CorrectTranscript(userID, message.ThreadID, message.ID, "perro lo que quiero")
+	require.NoError(t, err)
+
+	analyzer.AssertExpectations(t)
+	// Without a snapshot nothing was recorded: the stored analysis is only a
+	// fallback for English messages from before snapshots
+	statsRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestTranscriptCorrectionService_CorrectTranscript_KeepsAnalysis(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("same words in free conversation", func(t *testing.T) {
+		message := voiceMessage("i think so")
+		service, deps := newCorrectionService(userID, message)
+		deps.messageRepo.On("UpdateContent", mock.Anything, message.ID, "I think so.", mock.Anything).Return(nil)
+
+		_, err := service.CorrectTranscript(userID, message.ThreadID, message.ID, "I think so.")
+		require.NoError(t, err)
+
+		deps.messageRepo.AssertNotCalled(t, "ResetPronunciation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		deps.snapshots.AssertNotCalled(t, "Take", mock.Anything, mock.Anything)
+		deps.analyzer.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("practice line still followed", func(t *testing.T) {
+		message := voiceMessage("the weather is nice today")
+		expected := "The weather is nice today."
+		message.ExpectedText = &expected
+		service, deps := newCorrectionService(userID, message)
+		deps.messageRepo.On("UpdateContent", mock.Anything, message.ID, "The weather is nice to day", mock.Anything).Return(nil)
+
+		_, err := service.CorrectTranscript(userID, message.ThreadID, message.ID, "The weather is nice to day")
+		require.NoError(t, err)
+
+		deps.messageRepo.AssertNotCalled(t, "ResetPronunciation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTranscriptCorrectionService_CorrectTranscript_Unscored(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("recording deleted", func(t *testing.T) {
+		message := voiceMessage("I sink so")
+		message.AudioURL = nil
+		message.HasAudio = false
+		service, deps := newCorrectionService(userID, message)
+		deps.messageRepo.On("ResetPronunciation", mock.Anything, message.ID, "none", mock.Anything).Return(nil)
+		deps.snapshots.On("Take", mock.Anything, message.ID).Return(&models.PhonemeStatsSnapshot{MessageID: message.ID, UserID: userID}, nil)
+		deps.messageRepo.On("UpdateContent", mock.Anything, message.ID, "I think so", mock.Anything).Return(nil)
+
+		_, err := service.CorrectTranscript(userID, message.ThreadID, message.ID, "I think so")
+		require.NoError(t, err)
+
+		deps.messageRepo.AssertExpectations(t)
+		deps.snapshots.AssertExpectations(t)
+		deps.analyzer.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("user strayed from the practice line", func(t *testing.T) {
+		message := voiceMessage("the weather is nice today")
+		expected := "The weather is nice today."
+		message.ExpectedText = &expected
+		service, deps := newCorrectionService(userID, message)
+		deps.messageRepo.On("ResetPronunciation", mock.Anything, message.ID, "skipped_divergent", mock.Anything).Return(nil)
+		deps.snapshots.On("Take", mock.Anything, message.ID).Return(&models.PhonemeStatsSnapshot{MessageID: message.ID, UserID: userID}, nil)
+		deps.messageRepo.On("UpdateContent", mock.Anything, message.ID, "I would like a coffee please", mock.Anything).Return(nil)
+
+		_, err := service.CorrectTranscript(userID, message.ThreadID, message.ID, "I would like a coffee please")
+		require.NoError(t, err)
+
+		deps.messageRepo.AssertExpectations(t)
+		deps.analyzer.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTranscriptCorrectionService_CorrectTranscript_Rejects(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		modify  func(*models.Message)
+		content string
+		wantErr error
+	}{
+		{name: "empty", content: "   ", wantErr: ErrInvalidTranscript},
+		{name: "too long", content: strings.Repeat("a", MaxTranscriptLength+1), wantErr: ErrInvalidTranscript},
+		{name: "assistant message", modify: func(m *models.Message) { m.Role = "assistant" }, content: "hola", wantErr: ErrTranscriptNotEditable},
+		{name: "long-form", modify: func(m *models.Message) { m.Kind = models.MessageKindLongForm }, content: "hola", wantErr: ErrTranscriptNotEditable},
+		{name: "analysis running", modify: func(m *models.Message) { m.PronunciationStatus = "pending" }, content: "hola", wantErr: ErrAnalysisPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := voiceMessage("ola")
+			if tt.modify != nil {
+				tt.modify(message)
+			}
+			service, deps := newCorrectionService(userID, message)
+
+			_, err := service.CorrectTranscript(userID, message.ThreadID, message.ID, tt.content)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			deps.messageRepo.AssertNotCalled(t, "UpdateContent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxTranscriptDivergence is the word error rate between the expected text and
+// the transcript above which phoneme scoring is skipped: the user said
+// something else, so aligning their phonemes against the script is noise.
+const MaxTranscriptDivergence = 0.5
+
+// TranscriptDiverges reports whether the transcript strays too far from the
+// expected text for pronunciation scoring to be meaningful
+func TranscriptDiverges(expected, transcript string) bool {
+	return WordErrorRate(expected, transcript) > MaxTranscriptDivergence
+}
+
+// WordErrorRate returns the word-level edit distance between reference and
+// hypothesis divided by the reference length, ignoring case and punctuation.
+// It can exceed 1 when the hypothesis is much longer than the reference.
+func WordErrorRate(reference, hypothesis string) float64 {
+	ref := normalizeWords(reference)
+	hyp := normalizeWords(hypothesis)
+
+	if len(ref) == 0 {
+		if len(hyp) == 0 {
+			return 0
+		}
+		return 1
+	}
+
+	// Single-row Levenshtein over words
+	prev := make([]int, len(hyp)+1)
+	curr := make([]int, len(hyp)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ref); i++ {
+		curr[0] = i
+		for j := 1; j <= len(hyp); j++ {
+			cost := 1
+			if ref[i-1] == hyp[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return float64(prev[len(hyp)]) / float64(len(ref))
+}
+
+// normalizeWords lowercases text and splits it into words, dropping
+// punctuation other than apostrophes inside words
+func normalizeWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+}
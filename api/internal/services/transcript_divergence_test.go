@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordErrorRate(t *testing.T) {
+	tests := []struct {
+		name       string
+		reference  string
+		hypothesis string
+		want       float64
+	}{
+		{"identical", "the cat sat", "the cat sat", 0},
+		{"ignores case and punctuation", "Hello, world!", "hello world", 0},
+		{"keeps contractions", "I don't know", "I dont know", 1.0 / 3},
+		{"one substitution", "the cat sat", "the bat sat", 1.0 / 3},
+		{"deletion", "the cat sat down", "the cat down", 0.25},
+		{"completely different", "where is the station", "I like pizza", 1},
+		{"both empty", "", "", 0},
+		{"empty reference", "", "something", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WordErrorRate(tt.reference, tt.hypothesis), 0.0001)
+		})
+	}
+}
+
+func TestTranscriptDiverges(t *testing.T) {
+	assert.False(t, TranscriptDiverges("Could I have a coffee, please?", "could I have coffee please"))
+	assert.True(t, TranscriptDiverges("Could I have a coffee, please?", "what time does the train leave"))
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+
+	"ling-app/api/internal/client"
+)
+
+// TranscriptNormalizer restores punctuation and casing to a raw speech
+// transcript before it is stored as the message content. It must not change
+// the words: the transcript is the learner's own speech, mistakes included.
+type TranscriptNormalizer interface {
+	Normalize(ctx context.Context, text, locale string) (string, error)
+}
+
+// LLMTranscriptNormalizer punctuates transcripts with a small LLM call
+type LLMTranscriptNormalizer struct {
+	openAIClient client.OpenAIClient
+}
+
+// NewLLMTranscriptNormalizer creates a transcript normalizer backed by the
+// OpenAI client
+func NewLLMTranscriptNormalizer(openAIClient client.OpenAIClient) *LLMTranscriptNormalizer {
+	return &LLMTranscriptNormalizer{openAIClient: openAIClient}
+}
+
+// Normalize asks the model to punctuate text in the given locale
+func (n *LLMTranscriptNormalizer) Normalize(ctx context.Context, text, locale string) (string, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	return n.openAIClient.PunctuateTranscript(text, locale)
+}
+
+// TranscriptNeedsPunctuation reports whether a transcript looks unpunctuated:
+// it has no sentence punctuation at all or starts with a lowercase letter.
+// Whisper usually punctuates, so well-formed transcripts skip the extra call.
+func TranscriptNeedsPunctuation(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false
+	}
+	if first := []rune(text)[0]; unicode.IsLower(first) {
+		return true
+	}
+	return !strings.ContainsAny(text, ".?!…。？！")
+}
+
+// SameWords reports whether two transcripts contain the same words in the
+// same order, ignoring case and punctuation
+func SameWords(a, b string) bool {
+	return slices.Equal(normalizeWords(a), normalizeWords(b))
+}
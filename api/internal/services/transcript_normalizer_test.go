@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscriptNeedsPunctuation(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"I went to the market.", false},
+		{"Where is the station?", false},
+		{"¿Dónde está la estación?", false},
+		{"i went to the market.", true},
+		{"I went to the market", true},
+		{"where is the station", true},
+		{"", false},
+		{"   ", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, TranscriptNeedsPunctuation(tt.text), tt.text)
+	}
+}
+
+func TestSameWords(t *testing.T) {
+	assert.True(t, SameWords("i dont know what you mean", "I dont know what you mean."))
+	assert.True(t, SameWords("well its fine", "Well, its fine!"))
+	assert.False(t, SameWords("i goed home", "I went home."))
+	assert.False(t, SameWords("i went home", "I went home today."))
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrTierLimitReached is matched by every *TierLimitError
+var ErrTierLimitReached = errors.New("tier limit reached")
+
+// Resources limited per tier (see RuntimeSettings.TierLimits)
+const (
+	LimitThreads  = "threads"
+	LimitMessages = "messages"
+)
+
+// TierLimitError reports which tier limit blocked a request
+type TierLimitError struct {
+	Resource string
+	Tier     models.SubscriptionTier
+	Limit    int
+	Count    int64
+}
+
+func (e *TierLimitError) Error() string {
+	return fmt.Sprintf("%s limit reached: %d of %d on the %s tier", e.Resource, e.Count, e.Limit, e.Tier)
+}
+
+func (e *TierLimitError) Unwrap() error {
+	return ErrTierLimitReached
+}
+
+// UsageLimiter defines the interface for tier limit checks
+type UsageLimiter interface {
+	CheckThreadLimit(userID uuid.UUID) error
+	CheckMessageLimit(userID uuid.UUID) error
+	GetUsage(userID uuid.UUID) (*Usage, error)
+	Tier(userID uuid.UUID) (models.SubscriptionTier, error)
+}
+
+// ThreadCapper decides when a thread has run as long as its owner's tier allows
+type ThreadCapper interface {
+	ThreadCapReached(userID uuid.UUID, messages []models.Message) (bool, error)
+}
+
+// UsageCount is how much of a limited resource a user has stored.
+// Limit is 0 when the tier is unlimited.
+type UsageCount struct {
+	Count int64 `json:"count"`
+	Limit int   `json:"limit"`
+}
+
+// Usage summarizes a user's stored threads and messages against their tier limits
+type Usage struct {
+	Tier     models.SubscriptionTier `json:"tier"`
+	Threads  UsageCount              `json:"threads"`
+	Messages UsageCount              `json:"messages"`
+}
+
+// UsageService enforces the per-tier thread and message limits
+type UsageService struct {
+	exec        repository.Executor
+	subRepo     repository.SubscriptionRepository
+	threadRepo  repository.ThreadRepository
+	messageRepo repository.MessageRepository
+
+	// Runtime supplies the tier limits in force; nil uses the defaults
+	Runtime *RuntimeSettingsService
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(
+	database *db.DB,
+	subRepo repository.SubscriptionRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+) *UsageService {
+	return &UsageService{
+		exec:        database.DB,
+		subRepo:     subRepo,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+	}
+}
+
+// NewUsageServiceForTest creates a UsageService with injected dependencies for testing.
+func NewUsageServiceForTest(
+	exec repository.Executor,
+	subRepo repository.SubscriptionRepository,
+	threadRepo repository.ThreadRepository,
+	messageRepo repository.MessageRepository,
+) *UsageService {
+	return &UsageService{
+		exec:        exec,
+		subRepo:     subRepo,
+		threadRepo:  threadRepo,
+		messageRepo: messageRepo,
+	}
+}
+
+// CheckThreadLimit returns a *TierLimitError if the user can't create another thread
+func (s *UsageService) CheckThreadLimit(userID uuid.UUID) error {
+	tier, err := s.Tier(userID)
+	if err != nil {
+		return err
+	}
+
+	limit := s.Runtime.Current().TierLimits[tier].MaxThreads
+	if limit == 0 {
+		return nil
+	}
+
+	count, err := s.threadRepo.CountByUserID(s.exec, userID)
+	if err != nil {
+		return fmt.Errorf("count threads: %w", err)
+	}
+	if count >= int64(limit) {
+		return &TierLimitError{Resource: LimitThreads, Tier: tier, Limit: limit, Count: count}
+	}
+	return nil
+}
+
+// CheckMessageLimit returns a *TierLimitError if the user can't send another voice message
+func (s *UsageService) CheckMessageLimit(userID uuid.UUID) error {
+	tier, err := s.Tier(userID)
+	if err != nil {
+		return err
+	}
+
+	limit := s.Runtime.Current().TierLimits[tier].MaxMessages
+	if limit == 0 {
+		return nil
+	}
+
+	count, err := s.messageRepo.CountUserMessagesByUserID(s.exec, userID)
+	if err != nil {
+		return fmt.Errorf("count messages: %w", err)
+	}
+	if count >= int64(limit) {
+		return &TierLimitError{Resource: LimitMessages, Tier: tier, Limit: limit, Count: count}
+	}
+	return nil
+}
+
+// ThreadCapReached reports whether a thread holding messages has used up the
+// tier's per-thread cap: its learner messages, or minutes of learner speech
+func (s *UsageService) ThreadCapReached(userID uuid.UUID, messages []models.Message) (bool, error) {
+	tier, err := s.Tier(userID)
+	if err != nil {
+		return false, err
+	}
+	limits := s.Runtime.Current().TierLimits[tier]
+
+	var sent int
+	var seconds float64
+	for _, message := range messages {
+		if message.Role != "user" {
+			continue
+		}
+		sent++
+		if message.AudioDurationSeconds != nil {
+			seconds += *message.AudioDurationSeconds
+		}
+	}
+
+	if limits.MaxThreadMessages > 0 && sent >= limits.MaxThreadMessages {
+		return true, nil
+	}
+	return limits.MaxThreadMinutes > 0 && seconds >= float64(limits.MaxThreadMinutes*60), nil
+}
+
+// GetUsage returns the user's thread and message counts with their tier limits
+func (s *UsageService) GetUsage(userID uuid.UUID) (*Usage, error) {
+	tier, err := s.Tier(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := s.threadRepo.CountByUserID(s.exec, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count threads: %w", err)
+	}
+	messages, err := s.messageRepo.CountUserMessagesByUserID(s.exec, userID)
+	if err != nil {
+		return nil, fmt.Errorf("count messages: %w", err)
+	}
+
+	limits := s.Runtime.Current().TierLimits[tier]
+	return &Usage{
+		Tier:     tier,
+		Threads:  UsageCount{Count: threads, Limit: limits.MaxThreads},
+		Messages: UsageCount{Count: messages, Limit: limits.MaxMessages},
+	}, nil
+}
+
+// Tier returns the user's current tier, an admin override included; users
+// without a subscription are on free. A grace period doesn't raise limits: it
+// only keeps paid features read-only.
+func (s *UsageService) Tier(userID uuid.UUID) (models.SubscriptionTier, error) {
+	sub, err := s.subRepo.FindByUserID(s.exec, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.TierFree, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find subscription: %w", err)
+	}
+	tier := sub.EffectiveTier()
+	if _, ok := s.Runtime.Current().TierLimits[tier]; !ok {
+		return models.TierFree, nil
+	}
+	return tier, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/repository"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+type usageTestDeps struct {
+	subRepo     *repomocks.MockSubscriptionRepository
+	threadRepo  *repomocks.MockThreadRepository
+	messageRepo *repomocks.MockMessageRepository
+}
+
+func newUsageTestService() (*UsageService, usageTestDeps) {
+	deps := usageTestDeps{
+		subRepo:     new(repomocks.MockSubscriptionRepository),
+		threadRepo:  new(repomocks.MockThreadRepository),
+		messageRepo: new(repomocks.MockMessageRepository),
+	}
+	return NewUsageServiceForTest(nil, deps.subRepo, deps.threadRepo, deps.messageRepo), deps
+}
+
+func TestUsageService_CheckThreadLimit(t *testing.T) {
+	userID := uuid.New()
+	freeLimit := models.TierLimits[models.TierFree].MaxThreads
+
+	t.Run("allows threads under the limit", func(t *testing.T) {
+		service, deps := newUsageTestService()
+		deps.subRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+		deps.threadRepo.On("CountByUserID", mock.Anything, userID).Return(int64(freeLimit-1), nil)
+
+		assert.NoError(t, service.CheckThreadLimit(userID))
+	})
+
+	t.Run("blocks at the limit with counts", func(t *testing.T) {
+		service, deps := newUsageTestService()
+		deps.subRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Subscription{Tier: models.TierFree}, nil)
+		deps.threadRepo.On("CountByUserID", mock.Anything, userID).Return(int64(freeLimit), nil)
+
+		err := service.CheckThreadLimit(userID)
+
+		require.ErrorIs(t, err, ErrTierLimitReached)
+		var limitErr *TierLimitError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, LimitThreads, limitErr.Resource)
+		assert.Equal(t, models.TierFree, limitErr.Tier)
+		assert.Equal(t, freeLimit, limitErr.Limit)
+		assert.Equal(t, int64(freeLimit), limitErr.Count)
+	})
+
+	t.Run("unlimited tier skips the count", func(t *testing.T) {
+		service, deps := newUsageTestService()
+		deps.subRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Subscription{Tier: models.TierPro}, nil)
+
+		assert.NoError(t, service.CheckThreadLimit(userID))
+		deps.threadRepo.AssertNotCalled(t, "CountByUserID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUsageService_CheckMessageLimit(t *testing.T) {
+	userID := uuid.New()
+	basicLimit := models.TierLimits[models.TierBasic].MaxMessages
+
+	t.Run("blocks at the limit", func(t *testing.T) {
+		service, deps := newUsageTestService()
+		deps.subRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Subscription{Tier: models.TierBasic}, nil)
+		deps.messageRepo.On("CountUserMessagesByUserID", mock.Anything, userID).Return(int64(basicLimit), nil)
+
+		err := service.CheckMessageLimit(userID)
+
+		var limitErr *TierLimitError
+		require.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, LimitMessages, limitErr.Resource)
+		assert.Equal(t, basicLimit, limitErr.Limit)
+	})
+
+	t.Run("grace period uses the current tier", func(t *testing.T) {
+		service, deps := newUsageTestService()
+		graceTier := models.TierPro
+		deps.subRepo.On("FindByUserID", mock.Anything, userID).Return(&models.Subscription{Tier: models.TierFree, GraceTier: &graceTier}, nil)
+		deps.messageRepo.On("CountUserMessagesByUserID", mock.Anything, userID).Return(int64(models.TierLimits[models.TierFree].MaxMessages), nil)
+
+		assert.ErrorIs(t, service.CheckMessageLimit(userID), ErrTierLimitReached)
+	})
+}
+
+func TestUsageService_ThreadCapReached(t *testing.T) {
+	userID := uuid.New()
+	freeLimit := models.TierLimits[models.TierFree]
+	thread := func(sent int, secondsEach float64) []models.Message {
+		var messages []models.Message
+		for range sent {
+			messages = append(messages,
+				models.Message{Role: "user", AudioDurationSeconds: &secondsEach},
+				models.Message{Role: "assistant"},
+			)
+		}
+		return messages
+	}
+
+	tests := []struct {
+		name     string
+		messages []models.Message
+		reached  bool
+	}{
+		{"under both caps", thread(freeLimit.MaxThreadMessages-1, 5), false},
+		{"at the message cap", thread(freeLimit.MaxThreadMessages, 5), true},
+		{"at the speaking-time cap", thread(freeLimit.MaxThreadMinutes, 60), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, deps := newUsageTestService()
+			deps.subRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+
+			reached, err := service.ThreadCapReached(userID, tt.messages)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.reached, reached)
+		})
+	}
+}
+
+func TestUsageService_GetUsage(t *testing.T) {
+	userID := uuid.New()
+	service, deps := newUsageTestService()
+	deps.subRepo.On("FindByUserID", mock.Anything, userID).Return(nil, repository.ErrNotFound)
+	deps.threadRepo.On("CountByUserID", mock.Anything, userID).Return(int64(3), nil)
+	deps.messageRepo.On("CountUserMessagesByUserID", mock.Anything, userID).Return(int64(42), nil)
+
+	usage, err := service.GetUsage(userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, &Usage{
+		Tier:     models.TierFree,
+		Threads:  UsageCount{Count: 3, Limit: models.TierLimits[models.TierFree].MaxThreads},
+		Messages: UsageCount{Count: 42, Limit: models.TierLimits[models.TierFree].MaxMessages},
+	}, usage)
+}
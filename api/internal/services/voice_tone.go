@@ -0,0 +1,74 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/models"
+)
+
+// TTSVoice is how a reply is synthesized: Exaggeration from 0 (monotone) to
+// 1 (very expressive) and the audio format
+type TTSVoice struct {
+	Exaggeration float64
+	Format       string
+}
+
+// DefaultToneVoices is the voice each tone is spoken with. Replies are stored
+// as mp3, so every tone uses it; neutral matches the TTS defaults.
+var DefaultToneVoices = map[string]TTSVoice{
+	models.ToneNeutral:     {Exaggeration: 0.5, Format: "mp3"},
+	models.ToneCheerful:    {Exaggeration: 0.7, Format: "mp3"},
+	models.ToneCalm:        {Exaggeration: 0.3, Format: "mp3"},
+	models.ToneQuestioning: {Exaggeration: 0.6, Format: "mp3"},
+}
+
+// toneTag matches the tag the LLM puts in front of its reply, e.g.
+// "[tone: cheerful]"
+var toneTag = regexp.MustCompile(`(?i)^\s*\[\s*tone\s*:\s*([a-zA-Z]+)\s*\]\s*`)
+
+// TonePolicy has the LLM tag each reply with the tone it should be spoken in
+// and picks the TTS voice for it. OpenAI TTS has no exaggeration setting, so
+// there every tone sounds the same and the tone is only recorded.
+type TonePolicy struct {
+	Voices map[string]TTSVoice
+}
+
+// NewTonePolicy creates a tone policy with the default voices
+func NewTonePolicy() *TonePolicy {
+	return &TonePolicy{Voices: DefaultToneVoices}
+}
+
+// SystemPrompt asks the LLM to lead its reply with a tone tag
+func (p *TonePolicy) SystemPrompt() client.ConversationMessage {
+	return client.ConversationMessage{
+		Role: "system",
+		Content: "Begin your reply with the tone it should be spoken in, as [tone: cheerful], [tone: calm], " +
+			"[tone: questioning] or [tone: neutral], then the reply itself. Use cheerful for praise and good news, " +
+			"calm for corrections and reassurance, and questioning when the reply is mostly a question.",
+	}
+}
+
+// Parse splits the tone tag off a reply. Replies without a tag, or with a
+// tone the policy has no voice for, are neutral.
+func (p *TonePolicy) Parse(reply string) (tone, text string) {
+	match := toneTag.FindStringSubmatch(reply)
+	if match == nil {
+		return models.ToneNeutral, reply
+	}
+	text = reply[len(match[0]):]
+	tone = strings.ToLower(match[1])
+	if _, ok := p.Voices[tone]; !ok {
+		tone = models.ToneNeutral
+	}
+	return tone, text
+}
+
+// Voice returns the voice for a tone, falling back to neutral's
+func (p *TonePolicy) Voice(tone string) TTSVoice {
+	if voice, ok := p.Voices[tone]; ok {
+		return voice
+	}
+	return p.Voices[models.ToneNeutral]
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"ling-app/api/internal/models"
+)
+
+func TestTonePolicy_Parse(t *testing.T) {
+	policy := NewTonePolicy()
+
+	tests := []struct {
+		name     string
+		reply    string
+		wantTone string
+		wantText string
+	}{
+		{"tagged", "[tone: calm] Almost! Try it once more.", models.ToneCalm, "Almost! Try it once more."},
+		{"loose spacing and case", "  [Tone:Questioning]What did you do next?", models.ToneQuestioning, "What did you do next?"},
+		{"untagged", "Nice to meet you.", models.ToneNeutral, "Nice to meet you."},
+		{"unknown tone", "[tone: furious] Hello.", models.ToneNeutral, "Hello."},
+		{"tag mid-reply is left alone", "Hello. [tone: calm]", models.ToneNeutral, "Hello. [tone: calm]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tone, text := policy.Parse(tt.reply)
+			assert.Equal(t, tt.wantTone, tone)
+			assert.Equal(t, tt.wantText, text)
+		})
+	}
+}
+
+func TestTonePolicy_Voice(t *testing.T) {
+	policy := NewTonePolicy()
+
+	assert.Equal(t, 0.7, policy.Voice(models.ToneCheerful).Exaggeration)
+	assert.Equal(t, policy.Voices[models.ToneNeutral], policy.Voice("unknown"))
+}
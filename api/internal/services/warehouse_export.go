@@ -0,0 +1,451 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"ling-app/api/internal/client"
+	"ling-app/api/internal/db"
+	"ling-app/api/internal/models"
+	"ling-app/api/internal/parquet"
+	"ling-app/api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// AuditActionWarehouseExport is recorded when an admin starts an export
+const AuditActionWarehouseExport = "warehouse.export"
+
+// warehouseExportLag keeps a day out of the export until it has been over for
+// a while, so rows committed just after midnight with an earlier timestamp
+// aren't skipped
+const warehouseExportLag = 15 * time.Minute
+
+var (
+	ErrWarehouseExportDisabled = errors.New("warehouse export is not configured")
+	ErrWarehouseExportRunning  = errors.New("warehouse export already running")
+)
+
+// WarehouseExporter defines the interface for the warehouse export
+type WarehouseExporter interface {
+	Export(ctx context.Context, actor *uuid.UUID) (*WarehouseExportReport, error)
+}
+
+// WarehouseExportFile is one Parquet file written by an export
+type WarehouseExportFile struct {
+	Table string `json:"table"`
+	Day   string `json:"day"`
+	Key   string `json:"key"`
+	Rows  int    `json:"rows"`
+}
+
+// WarehouseExportReport lists what an export wrote. Through is the midnight
+// every table was exported up to, unless the table is named in Errors.
+type WarehouseExportReport struct {
+	Through time.Time             `json:"through"`
+	Files   []WarehouseExportFile `json:"files"`
+	Errors  []string              `json:"errors,omitempty"`
+}
+
+// WarehouseExportService writes anonymized fact tables to S3 as Parquet for
+// BI tools. Each table is exported one whole UTC day at a time, one file per
+// day, and its watermark advances after every file, so an interrupted export
+// resumes where it stopped. A day's file always has the same key: exporting a
+// day again replaces it instead of duplicating rows.
+type WarehouseExportService struct {
+	exec    repository.Executor
+	repo    repository.WarehouseRepository
+	storage client.StorageClient
+	prefix  string
+	hashKey []byte
+	hour    int
+	audit   *AuditService
+
+	running sync.Mutex
+	now     func() time.Time
+}
+
+// NewWarehouseExportService creates a new warehouse export service. A nil
+// storage client disables the export.
+func NewWarehouseExportService(
+	database *db.DB,
+	repo repository.WarehouseRepository,
+	storage client.StorageClient,
+	prefix, hashKey string,
+	hour int,
+	audit *AuditService,
+) *WarehouseExportService {
+	return &WarehouseExportService{
+		exec:    database.DB,
+		repo:    repo,
+		storage: storage,
+		prefix:  prefix,
+		hashKey: []byte(hashKey),
+		hour:    hour,
+		audit:   audit,
+		now:     time.Now,
+	}
+}
+
+// NewWarehouseExportServiceForTest creates a WarehouseExportService with injected dependencies for testing.
+func NewWarehouseExportServiceForTest(
+	exec repository.Executor,
+	repo repository.WarehouseRepository,
+	storage client.StorageClient,
+	prefix, hashKey string,
+) *WarehouseExportService {
+	return &WarehouseExportService{
+		exec:    exec,
+		repo:    repo,
+		storage: storage,
+		prefix:  prefix,
+		hashKey: []byte(hashKey),
+		now:     time.Now,
+	}
+}
+
+// Start exports every night at the configured UTC hour until ctx is cancelled
+func (s *WarehouseExportService) Start(ctx context.Context) {
+	if s.storage == nil {
+		return
+	}
+	log.Printf("[WarehouseExport] Exporting to %s/ nightly at %02d:00 UTC", s.prefix, s.hour)
+
+	for {
+		timer := time.NewTimer(s.nextRun(s.now()).Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.Export(ctx, nil); err != nil {
+			log.Printf("[WarehouseExport] Export failed: %v", err)
+		}
+	}
+}
+
+// nextRun returns the next time the nightly export is due after now
+func (s *WarehouseExportService) nextRun(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Export writes every day that has ended since each table's watermark. A
+// table that fails is reported and left at its last exported day; the
+// others still run. actor is the admin who started it, or nil for the
+// nightly run.
+func (s *WarehouseExportService) Export(ctx context.Context, actor *uuid.UUID) (*WarehouseExportReport, error) {
+	if s.storage == nil {
+		return nil, ErrWarehouseExportDisabled
+	}
+	if !s.running.TryLock() {
+		return nil, ErrWarehouseExportRunning
+	}
+	defer s.running.Unlock()
+
+	watermarks, err := s.repo.FindWatermarks(s.exec)
+	if err != nil {
+		return nil, fmt.Errorf("load watermarks: %w", err)
+	}
+	exportedThrough := make(map[string]time.Time, len(watermarks))
+	for _, watermark := range watermarks {
+		exportedThrough[watermark.Table] = watermark.ExportedThrough
+	}
+
+	report := &WarehouseExportReport{
+		Through: startOfDay(s.now().Add(-warehouseExportLag)),
+		Files:   []WarehouseExportFile{},
+	}
+	for _, table := range s.tables() {
+		if err := s.exportTable(ctx, table, exportedThrough[table.name], report); err != nil {
+			log.Printf("[WarehouseExport] Failed to export %s: %v", table.name, err)
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", table.name, err))
+		}
+	}
+
+	if len(report.Files) > 0 {
+		log.Printf("[WarehouseExport] Wrote %d files, exported up to %s", len(report.Files), report.Through.Format(time.DateOnly))
+	}
+	s.record(actor, report)
+	return report, nil
+}
+
+// warehouseTable is one fact table: rows returns its anonymized rows for
+// [from, to) as a slice parquet.Marshal accepts, and how many there are
+type warehouseTable struct {
+	name string
+	rows func(from, to time.Time) (any, int, error)
+}
+
+func (s *WarehouseExportService) tables() []warehouseTable {
+	return []warehouseTable{
+		{models.WarehouseTableMessages, s.messageRows},
+		{models.WarehouseTableAnalyses, s.analysisRows},
+		{models.WarehouseTableTransactions, s.transactionRows},
+		{models.WarehouseTableUsage, s.usageRows},
+	}
+}
+
+// exportTable writes one file per day from the table's watermark up to
+// report.Through. A table with no watermark starts at its earliest row.
+func (s *WarehouseExportService) exportTable(ctx context.Context, table warehouseTable, from time.Time, report *WarehouseExportReport) error {
+	if from.IsZero() {
+		first, err := s.repo.FirstFactTime(s.exec, table.name)
+		if err != nil {
+			return fmt.Errorf("find first row: %w", err)
+		}
+		if first == nil {
+			return nil
+		}
+		from = *first
+	}
+	from = startOfDay(from)
+
+	for day := from; day.Before(report.Through); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		next := day.AddDate(0, 0, 1)
+		rows, n, err := table.rows(day, next)
+		if err != nil {
+			return fmt.Errorf("%s: %w", day.Format(time.DateOnly), err)
+		}
+		if n > 0 {
+			data, err := parquet.Marshal(rows)
+			if err != nil {
+				return fmt.Errorf("%s: %w", day.Format(time.DateOnly), err)
+			}
+			key := fmt.Sprintf("%s/%s/dt=%s/part-0.parquet", s.prefix, table.name, day.Format(time.DateOnly))
+			if _, err := s.storage.UploadAudio(ctx, bytes.NewReader(data), key, "application/vnd.apache.parquet"); err != nil {
+				return fmt.Errorf("%s: %w", day.Format(time.DateOnly), err)
+			}
+			report.Files = append(report.Files, WarehouseExportFile{
+				Table: table.name,
+				Day:   day.Format(time.DateOnly),
+				Key:   key,
+				Rows:  n,
+			})
+		}
+
+		watermark := &models.WarehouseWatermark{Table: table.name, ExportedThrough: next}
+		if err := s.repo.SaveWatermark(s.exec, watermark); err != nil {
+			return fmt.Errorf("save watermark: %w", err)
+		}
+	}
+	return nil
+}
+
+// pseudonym replaces an ID with a keyed hash, so rows for the same user,
+// thread or message still join across tables but can't be traced back to
+// the account without the key
+func (s *WarehouseExportService) pseudonym(id uuid.UUID) string {
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write(id[:])
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+type warehouseMessageRow struct {
+	MessageKey           string    `parquet:"message_key"`
+	ThreadKey            string    `parquet:"thread_key"`
+	UserKey              string    `parquet:"user_key"`
+	Locale               string    `parquet:"locale"`
+	Role                 string    `parquet:"role"`
+	Kind                 string    `parquet:"kind"`
+	HasAudio             bool      `parquet:"has_audio"`
+	AudioDurationSeconds *float64  `parquet:"audio_duration_seconds"`
+	ContentLength        *int64    `parquet:"content_length"`
+	PronunciationStatus  string    `parquet:"pronunciation_status"`
+	Tone                 string    `parquet:"tone"`
+	SentAt               time.Time `parquet:"sent_at"`
+}
+
+func (s *WarehouseExportService) messageRows(from, to time.Time) (any, int, error) {
+	facts, err := s.repo.MessageFacts(s.exec, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows := make([]warehouseMessageRow, len(facts))
+	for i, fact := range facts {
+		rows[i] = warehouseMessageRow{
+			MessageKey:           s.pseudonym(fact.ID),
+			ThreadKey:            s.pseudonym(fact.ThreadID),
+			UserKey:              s.pseudonym(fact.UserID),
+			Locale:               localeOrDefault(fact.Locale),
+			Role:                 fact.Role,
+			Kind:                 fact.Kind,
+			HasAudio:             fact.HasAudio,
+			AudioDurationSeconds: fact.AudioDurationSeconds,
+			ContentLength:        fact.ContentLength,
+			PronunciationStatus:  fact.PronunciationStatus,
+			Tone:                 fact.Tone,
+			SentAt:               fact.Timestamp,
+		}
+	}
+	return rows, len(rows), nil
+}
+
+type warehouseAnalysisRow struct {
+	MessageKey        string    `parquet:"message_key"`
+	UserKey           string    `parquet:"user_key"`
+	Locale            string    `parquet:"locale"`
+	PhonemeCount      int64     `parquet:"phoneme_count"`
+	MatchCount        int64     `parquet:"match_count"`
+	SubstitutionCount int64     `parquet:"substitution_count"`
+	DeletionCount     int64     `parquet:"deletion_count"`
+	InsertionCount    int64     `parquet:"insertion_count"`
+	Accuracy          *float64  `parquet:"accuracy"`
+	Confidence        *float64  `parquet:"confidence"`
+	LowConfidence     bool      `parquet:"low_confidence"`
+	AnalysisQuality   string    `parquet:"analysis_quality"` // "fast" or "accurate"; empty before tiers had a quality
+	AudioQualityScore *float64  `parquet:"audio_quality_score"`
+	ChunkCount        *int64    `parquet:"chunk_count"`
+	ProcessingTimeMs  int64     `parquet:"processing_time_ms"`
+	AnalyzedAt        time.Time `parquet:"analyzed_at"`
+}
+
+func (s *WarehouseExportService) analysisRows(from, to time.Time) (any, int, error) {
+	facts, err := s.repo.AnalysisFacts(s.exec, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows := make([]warehouseAnalysisRow, 0, len(facts))
+	for _, fact := range facts {
+		analysis, ok := parseAnalysis(fact.PronunciationAnalysis)
+		if !ok {
+			continue
+		}
+		row := warehouseAnalysisRow{
+			MessageKey:        s.pseudonym(fact.ID),
+			UserKey:           s.pseudonym(fact.UserID),
+			Locale:            localeOrDefault(fact.Locale),
+			PhonemeCount:      int64(analysis.PhonemeCount),
+			MatchCount:        int64(analysis.MatchCount),
+			SubstitutionCount: int64(analysis.SubstitutionCount),
+			DeletionCount:     int64(analysis.DeletionCount),
+			InsertionCount:    int64(analysis.InsertionCount),
+			Confidence:        fact.PronunciationConfidence,
+			LowConfidence:     fact.PronunciationLowConfidence,
+			AnalysisQuality:   fact.PronunciationQuality,
+			ProcessingTimeMs:  analysis.ProcessingTimeMs,
+			AnalyzedAt:        fact.PronunciationUpdatedAt,
+		}
+		if analysis.PhonemeCount > 0 {
+			accuracy := float64(analysis.MatchCount) / float64(analysis.PhonemeCount)
+			row.Accuracy = &accuracy
+		}
+		if analysis.AudioQuality != nil {
+			row.AudioQualityScore = &analysis.AudioQuality.QualityScore
+		}
+		if chunks, ok := fact.PronunciationAnalysis["chunk_count"].(float64); ok {
+			chunkCount := int64(chunks)
+			row.ChunkCount = &chunkCount
+		}
+		rows = append(rows, row)
+	}
+	return rows, len(rows), nil
+}
+
+type warehouseTransactionRow struct {
+	TransactionKey string    `parquet:"transaction_key"`
+	UserKey        string    `parquet:"user_key"`
+	Type           string    `parquet:"type"`
+	Amount         int64     `parquet:"amount"`
+	BalanceAfter   int64     `parquet:"balance_after"`
+	CreatedAt      time.Time `parquet:"created_at"`
+}
+
+// transactionRows leaves out the reference and description, which can
+// carry IDs and free text
+func (s *WarehouseExportService) transactionRows(from, to time.Time) (any, int, error) {
+	transactions, err := s.repo.TransactionFacts(s.exec, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows := make([]warehouseTransactionRow, len(transactions))
+	for i, tx := range transactions {
+		rows[i] = warehouseTransactionRow{
+			TransactionKey: s.pseudonym(tx.ID),
+			UserKey:        s.pseudonym(tx.UserID),
+			Type:           string(tx.Type),
+			Amount:         int64(tx.Amount),
+			BalanceAfter:   int64(tx.BalanceAfter),
+			CreatedAt:      tx.CreatedAt,
+		}
+	}
+	return rows, len(rows), nil
+}
+
+type warehouseUsageRow struct {
+	Day               time.Time `parquet:"day,date"`
+	UserKey           string    `parquet:"user_key"`
+	UserMessages      int64     `parquet:"user_messages"`
+	VoiceMessages     int64     `parquet:"voice_messages"`
+	AssistantMessages int64     `parquet:"assistant_messages"`
+	AudioSeconds      float64   `parquet:"audio_seconds"`
+	ActiveThreads     int64     `parquet:"active_threads"`
+}
+
+func (s *WarehouseExportService) usageRows(from, to time.Time) (any, int, error) {
+	facts, err := s.repo.UsageFacts(s.exec, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows := make([]warehouseUsageRow, len(facts))
+	for i, fact := range facts {
+		rows[i] = warehouseUsageRow{
+			Day:               from,
+			UserKey:           s.pseudonym(fact.UserID),
+			UserMessages:      fact.UserMessages,
+			VoiceMessages:     fact.VoiceMessages,
+			AssistantMessages: fact.AssistantMessages,
+			AudioSeconds:      fact.AudioSeconds,
+			ActiveThreads:     fact.ActiveThreads,
+		}
+	}
+	return rows, len(rows), nil
+}
+
+// record writes an admin-started export to the audit log
+func (s *WarehouseExportService) record(actor *uuid.UUID, report *WarehouseExportReport) {
+	if s.audit == nil || actor == nil {
+		return
+	}
+	s.audit.Record(&models.AuditLog{
+		Action:  AuditActionWarehouseExport,
+		Actor:   actor.String(),
+		Outcome: models.AuditOutcomeSuccess,
+		Details: models.JSONMap{
+			"through": report.Through.Format(time.DateOnly),
+			"files":   len(report.Files),
+			"errors":  len(report.Errors),
+		},
+	})
+}
+
+// startOfDay truncates t to midnight UTC
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func localeOrDefault(locale string) string {
+	if locale == "" {
+		return DefaultLocale
+	}
+	return locale
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	clientmocks "ling-app/api/internal/client/mocks"
+	"ling-app/api/internal/models"
+	repomocks "ling-app/api/internal/repository/mocks"
+)
+
+const testWarehouseHashKey = "0123456789abcdef0123456789abcdef"
+
+func TestWarehouseExportService_Export(t *testing.T) {
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	dayOne, dayTwo := today.AddDate(0, 0, -2), today.AddDate(0, 0, -1)
+
+	t.Run("first run exports each finished day from the earliest row", func(t *testing.T) {
+		repo := new(repomocks.MockWarehouseRepository)
+		storage := new(clientmocks.MockStorageClient)
+		userID := uuid.New()
+		first := dayOne.Add(10 * time.Hour)
+		contentLength := int64(12)
+
+		repo.On("FindWatermarks", mock.Anything).Return([]models.WarehouseWatermark{}, nil)
+		repo.On("FirstFactTime", mock.Anything, models.WarehouseTableMessages).Return(&first, nil)
+		repo.On("FirstFactTime", mock.Anything, mock.Anything).Return(nil, nil)
+		repo.On("MessageFacts", mock.Anything, dayOne, dayTwo).Return([]models.WarehouseMessageFact{
+			{ID: uuid.New(), ThreadID: uuid.New(), UserID: userID, Role: "user", HasAudio: true, ContentLength: &contentLength, Timestamp: first},
+			{ID: uuid.New(), ThreadID: uuid.New(), UserID: userID, Role: "assistant", Timestamp: first.Add(time.Second)},
+		}, nil)
+		repo.On("MessageFacts", mock.Anything, dayTwo, today).Return([]models.WarehouseMessageFact{}, nil)
+		repo.On("SaveWatermark", mock.Anything, &models.WarehouseWatermark{Table: models.WarehouseTableMessages, ExportedThrough: dayTwo}).Return(nil)
+		repo.On("SaveWatermark", mock.Anything, &models.WarehouseWatermark{Table: models.WarehouseTableMessages, ExportedThrough: today}).Return(nil)
+
+		var uploaded []byte
+		storage.On("UploadAudio", mock.Anything, mock.Anything, "warehouse/messages/dt=2026-10-14/part-0.parquet", "application/vnd.apache.parquet").
+			Run(func(args mock.Arguments) {
+				uploaded, _ = io.ReadAll(args.Get(1).(io.Reader))
+			}).Return("warehouse/messages/dt=2026-10-14/part-0.parquet", nil).Once()
+
+		svc := NewWarehouseExportServiceForTest(nil, repo, storage, "warehouse", testWarehouseHashKey)
+		svc.now = func() time.Time { return now }
+
+		report, err := svc.Export(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, today, report.Through)
+		assert.Empty(t, report.Errors)
+		assert.Equal(t, []WarehouseExportFile{{
+			Table: models.WarehouseTableMessages,
+			Day:   "2026-10-14",
+			Key:   "warehouse/messages/dt=2026-10-14/part-0.parquet",
+			Rows:  2,
+		}}, report.Files)
+		assert.Equal(t, "PAR1", string(uploaded[:4]))
+		repo.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("nothing to do once every table is at the watermark", func(t *testing.T) {
+		repo := new(repomocks.MockWarehouseRepository)
+		storage := new(clientmocks.MockStorageClient)
+		repo.On("FindWatermarks", mock.Anything).Return([]models.WarehouseWatermark{
+			{Table: models.WarehouseTableMessages, ExportedThrough: today},
+			{Table: models.WarehouseTableAnalyses, ExportedThrough: today},
+			{Table: models.WarehouseTableTransactions, ExportedThrough: today},
+			{Table: models.WarehouseTableUsage, ExportedThrough: today},
+		}, nil)
+
+		svc := NewWarehouseExportServiceForTest(nil, repo, storage, "warehouse", testWarehouseHashKey)
+		svc.now = func() time.Time { return now }
+
+		report, err := svc.Export(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, report.Files)
+		repo.AssertNotCalled(t, "SaveWatermark", mock.Anything, mock.Anything)
+		storage.AssertNotCalled(t, "UploadAudio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failed upload keeps the table's watermark and the others still run", func(t *testing.T) {
+		repo := new(repomocks.MockWarehouseRepository)
+		storage := new(clientmocks.MockStorageClient)
+		repo.On("FindWatermarks", mock.Anything).Return([]models.WarehouseWatermark{
+			{Table: models.WarehouseTableMessages, ExportedThrough: today},
+			{Table: models.WarehouseTableAnalyses, ExportedThrough: today},
+			{Table: models.WarehouseTableTransactions, ExportedThrough: dayTwo},
+			{Table: models.WarehouseTableUsage, ExportedThrough: dayTwo},
+		}, nil)
+		repo.On("TransactionFacts", mock.Anything, dayTwo, today).Return([]models.CreditTransaction{
+			{ID: uuid.New(), UserID: uuid.New(), Type: models.TransactionDebit, Amount: -1, CreatedAt: dayTwo.Add(time.Hour)},
+		}, nil)
+		repo.On("UsageFacts", mock.Anything, dayTwo, today).Return([]models.WarehouseUsageFact{
+			{UserID: uuid.New(), UserMessages: 3, VoiceMessages: 3, AssistantMessages: 3, AudioSeconds: 12.5, ActiveThreads: 1},
+		}, nil)
+		storage.On("UploadAudio", mock.Anything, mock.Anything, "warehouse/credit_transactions/dt=2026-10-15/part-0.parquet", mock.Anything).
+			Return("", errors.New("s3 unavailable"))
+		storage.On("UploadAudio", mock.Anything, mock.Anything, "warehouse/usage_daily/dt=2026-10-15/part-0.parquet", mock.Anything).
+			Return("warehouse/usage_daily/dt=2026-10-15/part-0.parquet", nil)
+		repo.On("SaveWatermark", mock.Anything, &models.WarehouseWatermark{Table: models.WarehouseTableUsage, ExportedThrough: today}).Return(nil)
+
+		svc := NewWarehouseExportServiceForTest(nil, repo, storage, "warehouse", testWarehouseHashKey)
+		svc.now = func() time.Time { return now }
+
+		report, err := svc.Export(context.Background(), nil)
+
+		require.NoError(t, err)
+		require.Len(t, report.Errors, 1)
+		assert.Contains(t, report.Errors[0], "credit_transactions: 2026-10-15: s3 unavailable")
+		require.Len(t, report.Files, 1)
+		assert.Equal(t, models.WarehouseTableUsage, report.Files[0].Table)
+		repo.AssertNotCalled(t, "SaveWatermark", mock.Anything, &models.WarehouseWatermark{Table: models.WarehouseTableTransactions, ExportedThrough: today})
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("a day is held back until it has been over for the lag", func(t *testing.T) {
+		repo := new(repomocks.MockWarehouseRepository)
+		repo.On("FindWatermarks", mock.Anything).Return([]models.WarehouseWatermark{}, nil)
+		repo.On("FirstFactTime", mock.Anything, mock.Anything).Return(nil, nil)
+
+		svc := NewWarehouseExportServiceForTest(nil, repo, new(clientmocks.MockStorageClient), "warehouse", testWarehouseHashKey)
+		svc.now = func() time.Time { return today.Add(5 * time.Minute) }
+
+		report, err := svc.Export(context.Background(), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, dayTwo, report.Through)
+	})
+
+	t.Run("disabled without storage", func(t *testing.T) {
+		svc := NewWarehouseExportServiceForTest(nil, new(repomocks.MockWarehouseRepository), nil, "", "")
+
+		_, err := svc.Export(context.Background(), nil)
+
+		assert.ErrorIs(t, err, ErrWarehouseExportDisabled)
+	})
+
+	t.Run("one export at a time", func(t *testing.T) {
+		svc := NewWarehouseExportServiceForTest(nil, new(repomocks.MockWarehouseRepository), new(clientmocks.MockStorageClient), "warehouse", testWarehouseHashKey)
+		svc.running.Lock()
+		defer svc.running.Unlock()
+
+		_, err := svc.Export(context.Background(), nil)
+
+		assert.ErrorIs(t, err, ErrWarehouseExportRunning)
+	})
+}
+
+func TestWarehouseExportService_Anonymizes(t *testing.T) {
+	userID, messageID := uuid.New(), uuid.New()
+	repo := new(repomocks.MockWarehouseRepository)
+	svc := NewWarehouseExportServiceForTest(nil, repo, nil, "warehouse", testWarehouseHashKey)
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	confidence := 0.9
+
+	repo.On("AnalysisFacts", mock.Anything, day, day.AddDate(0, 0, 1)).Return([]models.WarehouseAnalysisFact{
+		{
+			ID:     messageID,
+			UserID: userID,
+			Locale: "",
+			PronunciationAnalysis: models.JSONMap{
+				"phoneme_count": 20.0, "match_count": 15.0, "substitution_count": 3.0,
+				"audio_quality": map[string]any{"quality_score": 0.8}, "chunk_count": 2.0,
+			},
+			PronunciationConfidence: &confidence,
+			PronunciationQuality:    "fast",
+			PronunciationUpdatedAt:  day.Add(time.Hour),
+		},
+		{ID: uuid.New(), UserID: userID, PronunciationAnalysis: models.JSONMap{"phoneme_count": "not a number"}},
+	}, nil)
+
+	rows, n, err := svc.analysisRows(day, day.AddDate(0, 0, 1))
+
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "an unreadable analysis is skipped")
+	row := rows.([]warehouseAnalysisRow)[0]
+	assert.Equal(t, svc.pseudonym(userID), row.UserKey)
+	assert.Len(t, row.UserKey, 32)
+	assert.NotContains(t, row.UserKey, userID.String())
+	assert.NotEqual(t, row.UserKey, row.MessageKey)
+	assert.Equal(t, DefaultLocale, row.Locale)
+	assert.Equal(t, 0.75, *row.Accuracy)
+	assert.Equal(t, 0.8, *row.AudioQualityScore)
+	assert.Equal(t, int64(2), *row.ChunkCount)
+	assert.Equal(t, &confidence, row.Confidence)
+	assert.Equal(t, "fast", row.AnalysisQuality)
+
+	other := NewWarehouseExportServiceForTest(nil, repo, nil, "warehouse", "another key entirely, 32+ chars..")
+	assert.NotEqual(t, svc.pseudonym(userID), other.pseudonym(userID), "pseudonyms depend on the key")
+	assert.False(t, bytes.Contains([]byte(row.MessageKey), []byte(messageID.String())))
+}
+
+func TestWarehouseExportService_NextRun(t *testing.T) {
+	svc := &WarehouseExportService{hour: 2}
+
+	assert.Equal(t, time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC), svc.nextRun(time.Date(2026, 10, 16, 1, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC), svc.nextRun(time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)))
+	// 23:00 PDT is already 06:00 the next day in UTC
+	assert.Equal(t, time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC), svc.nextRun(time.Date(2026, 10, 16, 23, 0, 0, 0, time.FixedZone("PDT", -7*3600))))
+}
@@ -0,0 +1,115 @@
+package factory
+
+import (
+	"strings"
+
+	"ling-app/api/internal/client"
+)
+
+// AnalysisBuilder builds a pronunciation analysis phoneme by phoneme, the
+// way the ML service aligns one: details are numbered in order, counts and
+// IPA strings follow from them, and the phoneme count includes insertions.
+type AnalysisBuilder struct {
+	details      []client.PhonemeDetail
+	audioQuality *client.AudioQuality
+	quality      client.AnalysisQuality
+}
+
+// Analysis starts an analysis of a clean, accurate-quality recording
+func Analysis() *AnalysisBuilder {
+	return &AnalysisBuilder{
+		audioQuality: &client.AudioQuality{QualityScore: 90, SNRDB: 30, DurationSeconds: 2.5, Warnings: []string{}},
+		quality:      client.QualityAccurate,
+	}
+}
+
+// Match adds phonemes said as expected
+func (b *AnalysisBuilder) Match(phonemes ...string) *AnalysisBuilder {
+	for _, phoneme := range phonemes {
+		b.add(phoneme, phoneme, "match")
+	}
+	return b
+}
+
+// Substitute adds an expected phoneme said as another
+func (b *AnalysisBuilder) Substitute(expected, actual string) *AnalysisBuilder {
+	return b.add(expected, actual, "substitute")
+}
+
+// Delete adds an expected phoneme that wasn't said
+func (b *AnalysisBuilder) Delete(expected string) *AnalysisBuilder {
+	return b.add(expected, "", "delete")
+}
+
+// Insert adds a phoneme said that wasn't expected
+func (b *AnalysisBuilder) Insert(actual string) *AnalysisBuilder {
+	return b.add("", actual, "insert")
+}
+
+func (b *AnalysisBuilder) add(expected, actual, kind string) *AnalysisBuilder {
+	b.details = append(b.details, client.PhonemeDetail{
+		Expected: expected,
+		Actual:   actual,
+		Type:     kind,
+		Position: len(b.details),
+	})
+	return b
+}
+
+// AudioQuality replaces the recording's quality report
+func (b *AnalysisBuilder) AudioQuality(score, snrDB, durationSeconds float64, warnings ...string) *AnalysisBuilder {
+	if warnings == nil {
+		warnings = []string{}
+	}
+	b.audioQuality = &client.AudioQuality{QualityScore: score, SNRDB: snrDB, DurationSeconds: durationSeconds, Warnings: warnings}
+	return b
+}
+
+// Quality sets the model quality that produced the analysis
+func (b *AnalysisBuilder) Quality(quality client.AnalysisQuality) *AnalysisBuilder {
+	b.quality = quality
+	return b
+}
+
+// Build returns the analysis
+func (b *AnalysisBuilder) Build() *client.PronunciationAnalysis {
+	analysis := &client.PronunciationAnalysis{
+		PhonemeCount:     len(b.details),
+		PhonemeDetails:   append([]client.PhonemeDetail{}, b.details...),
+		AudioQuality:     b.audioQuality,
+		ProcessingTimeMs: 120,
+		Quality:          b.quality,
+	}
+	var audioIPA, expectedIPA strings.Builder
+	for _, detail := range b.details {
+		audioIPA.WriteString(detail.Actual)
+		expectedIPA.WriteString(detail.Expected)
+		switch detail.Type {
+		case "match":
+			analysis.MatchCount++
+		case "substitute":
+			analysis.SubstitutionCount++
+		case "delete":
+			analysis.DeletionCount++
+		case "insert":
+			analysis.InsertionCount++
+		}
+	}
+	analysis.AudioIPA = audioIPA.String()
+	analysis.ExpectedIPA = expectedIPA.String()
+	return analysis
+}
+
+// Response wraps the analysis in a successful ML service response
+func (b *AnalysisBuilder) Response() *client.PronunciationResponse {
+	return &client.PronunciationResponse{Status: "success", Analysis: b.Build()}
+}
+
+// FailedAnalysis is the ML service's response when it couldn't analyze a
+// recording, e.g. code "AUDIO_TOO_SHORT"
+func FailedAnalysis(code, message string) *client.PronunciationResponse {
+	return &client.PronunciationResponse{
+		Status: "error",
+		Error:  &client.PronunciationError{Code: code, Message: message},
+	}
+}
@@ -0,0 +1,104 @@
+// Package factory builds valid test data with sensible defaults, so tests
+// only spell out the fields they are about. Builders never touch a database;
+// integration tests save what they build themselves.
+package factory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ling-app/api/internal/models"
+)
+
+// Now is the time every builder stamps records with
+var Now = time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+// User builds a verified learner with a unique email
+func User(opts ...func(*models.User)) *models.User {
+	id := uuid.New()
+	user := &models.User{
+		ID:            id,
+		Email:         fmt.Sprintf("learner-%s@example.com", id.String()[:8]),
+		Name:          "Test Learner",
+		EmailVerified: true,
+		Role:          models.RoleUser,
+		CreatedAt:     Now,
+		UpdatedAt:     Now,
+	}
+	for _, opt := range opts {
+		opt(user)
+	}
+	return user
+}
+
+// Thread builds an open, unnamed thread of the user's
+func Thread(userID uuid.UUID, opts ...func(*models.Thread)) *models.Thread {
+	thread := &models.Thread{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CreatedAt: Now,
+	}
+	for _, opt := range opts {
+		opt(thread)
+	}
+	return thread
+}
+
+// VoiceMessage builds a user's voice message saying content, with its
+// recording stored and its analysis pending
+func VoiceMessage(threadID uuid.UUID, content string, opts ...func(*models.Message)) *models.Message {
+	id := uuid.New()
+	audioKey := fmt.Sprintf("user/%s/%s.webm", threadID, id)
+	duration := 2.5
+	message := &models.Message{
+		ID:                   id,
+		ThreadID:             threadID,
+		Role:                 "user",
+		Content:              content,
+		AudioURL:             &audioKey,
+		AudioFormat:          models.AudioFormatWebM,
+		AudioDurationSeconds: &duration,
+		HasAudio:             true,
+		Timestamp:            Now,
+		PronunciationStatus:  "pending",
+	}
+	for _, opt := range opts {
+		opt(message)
+	}
+	return message
+}
+
+// AssistantMessage builds a text-only reply
+func AssistantMessage(threadID uuid.UUID, content string, opts ...func(*models.Message)) *models.Message {
+	message := &models.Message{
+		ID:                  uuid.New(),
+		ThreadID:            threadID,
+		Role:                "assistant",
+		Content:             content,
+		Timestamp:           Now,
+		PronunciationStatus: "none",
+	}
+	for _, opt := range opts {
+		opt(message)
+	}
+	return message
+}
+
+// Credits builds a free-tier balance of the user's
+func Credits(userID uuid.UUID, balance int, opts ...func(*models.Credits)) *models.Credits {
+	credits := &models.Credits{
+		ID:               uuid.New(),
+		UserID:           userID,
+		Balance:          balance,
+		MonthlyAllowance: models.TierCredits[models.TierFree],
+		LastRefreshedAt:  Now,
+		CreatedAt:        Now,
+		UpdatedAt:        Now,
+	}
+	for _, opt := range opts {
+		opt(credits)
+	}
+	return credits
+}
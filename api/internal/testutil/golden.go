@@ -0,0 +1,48 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// updateGolden rewrites golden files with what the tests produce:
+//
+//	go test ./internal/services/ -run Golden -update
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// AssertGoldenJSON compares got, encoded as indented JSON, with
+// testdata/golden/<name>.json in the test's package. A difference means a
+// stored or served JSON shape changed: if that was intended, rerun with
+// -update and review the diff.
+func AssertGoldenJSON(t testing.TB, name string, got any) {
+	t.Helper()
+
+	encoded, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to encode %s: %v", name, err)
+	}
+	encoded = append(encoded, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, encoded, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s (run with -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(encoded, want) {
+		t.Errorf("%s changed; rerun with -update if intended.\n--- want\n%s\n--- got\n%s", path, want, encoded)
+	}
+}
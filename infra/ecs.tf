@@ -187,6 +187,8 @@ resource "aws_ecs_task_definition" "api" {
         { name = "FRONTEND_URL", value = "https://${var.domain_name}" },
         { name = "GOOGLE_REDIRECT_URL", value = "https://${var.domain_name}/api/auth/google/callback" },
         { name = "GITHUB_REDIRECT_URL", value = "https://${var.domain_name}/api/auth/github/callback" },
+        { name = "STRIPE_SUCCESS_URL", value = "https://${var.domain_name}/subscription/success" },
+        { name = "STRIPE_CANCEL_URL", value = "https://${var.domain_name}/pricing" },
       ]
 
       secrets = [
@@ -199,6 +201,7 @@ resource "aws_ecs_task_definition" "api" {
         { name = "GITHUB_CLIENT_SECRET", valueFrom = aws_ssm_parameter.github_client_secret.arn },
         { name = "STRIPE_SECRET_KEY", valueFrom = aws_ssm_parameter.stripe_secret_key.arn },
         { name = "STRIPE_WEBHOOK_SECRET", valueFrom = aws_ssm_parameter.stripe_webhook_secret.arn },
+        { name = "INTERNAL_SERVICE_SECRET", valueFrom = aws_ssm_parameter.internal_service_secret.arn },
       ]
 
       logConfiguration = {
@@ -371,6 +374,10 @@ resource "aws_ecs_task_definition" "ml" {
         }
       ]
 
+      secrets = [
+        { name = "INTERNAL_SERVICE_SECRET", valueFrom = aws_ssm_parameter.internal_service_secret.arn },
+      ]
+
       logConfiguration = {
         logDriver = "awslogs"
         options = {
@@ -519,6 +526,24 @@ resource "aws_ssm_parameter" "stripe_webhook_secret" {
   }
 }
 
+# Shared by the API and ML service to sign requests to each other. To rotate,
+# set INTERNAL_SERVICE_PREVIOUS_SECRETS on both services to the old value
+# before replacing this one (terraform taint random_password.internal_service_secret).
+resource "random_password" "internal_service_secret" {
+  length  = 48
+  special = false
+}
+
+resource "aws_ssm_parameter" "internal_service_secret" {
+  name  = "/${local.name_prefix}/INTERNAL_SERVICE_SECRET"
+  type  = "SecureString"
+  value = random_password.internal_service_secret.result
+
+  tags = {
+    Name = "${local.name_prefix}-internal-service-secret"
+  }
+}
+
 # IAM policy for ECS to read SSM parameters
 resource "aws_iam_policy" "ssm_read" {
   name        = "${local.name_prefix}-ssm-read"
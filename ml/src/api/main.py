@@ -8,11 +8,12 @@ Run with:
 import os
 from contextlib import asynccontextmanager
 
-from fastapi import FastAPI
+from fastapi import Depends, FastAPI, Request
 from fastapi.middleware.cors import CORSMiddleware
 
-from .routes import router, load_models, get_models_loaded
+from .routes import router, load_models, get_models_loaded, pending_callback_jobs
 from .schemas import HealthResponse
+from .service_auth import require_service_signature
 
 
 @asynccontextmanager
@@ -48,8 +49,25 @@ app.add_middleware(
     allow_headers=["*"],
 )
 
-# Include routes
-app.include_router(router, prefix="/api/v1")
+# Include routes; every /api/v1 request must be signed by the API
+app.include_router(router, prefix="/api/v1", dependencies=[Depends(require_service_signature)])
+
+# In-flight inference requests, reported on /health so the API can shed load
+queue_depth = 0
+
+
+@app.middleware("http")
+async def track_queue_depth(request: Request, call_next):
+    """Count in-flight /api/v1 requests."""
+    global queue_depth
+    if not request.url.path.startswith("/api/v1"):
+        return await call_next(request)
+
+    queue_depth += 1
+    try:
+        return await call_next(request)
+    finally:
+        queue_depth -= 1
 
 
 @app.get("/health", response_model=HealthResponse)
@@ -57,7 +75,8 @@ async def health_check():
     """Health check endpoint."""
     return HealthResponse(
         status="healthy" if get_models_loaded() else "starting",
-        model_loaded=get_models_loaded()
+        model_loaded=get_models_loaded(),
+        queue_depth=queue_depth + pending_callback_jobs()
     )
 
 
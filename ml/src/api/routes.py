@@ -2,10 +2,14 @@
 API route handlers for pronunciation analysis.
 """
 
+import asyncio
+import json
+import os
 import time
-from typing import Optional
+from typing import Optional, Set, Tuple
+from urllib.parse import urlsplit
 
-from fastapi import APIRouter
+from fastapi import APIRouter, Response
 import httpx
 
 import base64
@@ -13,6 +17,7 @@ import base64
 from .schemas import (
     PronunciationRequest,
     PronunciationResponse,
+    PronunciationCallback,
     PronunciationAnalysis,
     PronunciationError,
     PhonemeDetail,
@@ -23,6 +28,7 @@ from .schemas import (
     SynthesizeResponse,
 )
 from .audio_fetcher import AudioFetcher
+from .service_auth import service_signer
 from src.ipa.audio_to_ipa import WhisperIPAConverter
 from src.ipa.text_to_ipa import GruutIPAConverter
 from src.ipa.aligner import PhonemeAligner
@@ -33,18 +39,30 @@ router = APIRouter()
 
 # Global model instances (loaded once at startup)
 whisper_converter: Optional[WhisperIPAConverter] = None
+fast_whisper_converter: Optional[WhisperIPAConverter] = None
 gruut_converter: Optional[GruutIPAConverter] = None
 aligner: Optional[PhonemeAligner] = None
 audio_fetcher: Optional[AudioFetcher] = None
 stt_transcriber: Optional[FasterWhisperTranscriber] = None
 tts_synthesizer: Optional[ChatterboxSynthesizer] = None
 
+# Async analyses still running; held so the tasks aren't garbage collected
+callback_tasks: Set[asyncio.Task] = set()
+
+# Delays between callback delivery attempts
+CALLBACK_RETRY_DELAYS = (1, 4, 16)
+
 
 def get_models_loaded() -> bool:
     """Check if models are loaded."""
     return whisper_converter is not None
 
 
+def pending_callback_jobs() -> int:
+    """Number of async analyses accepted but not yet delivered."""
+    return len(callback_tasks)
+
+
 def load_models(device: Optional[str] = None, language: str = "en-us"):
     """
     Load ML models. Called at startup.
@@ -53,11 +71,18 @@ def load_models(device: Optional[str] = None, language: str = "en-us"):
         device: Device for Whisper model ('cuda', 'cpu', or None for auto)
         language: Default language for text-to-IPA
     """
-    global whisper_converter, gruut_converter, aligner, audio_fetcher, stt_transcriber, tts_synthesizer
+    global whisper_converter, fast_whisper_converter, gruut_converter, aligner, audio_fetcher, stt_transcriber, tts_synthesizer
 
     print("Loading pronunciation analysis models...")
 
     whisper_converter = WhisperIPAConverter(device=device)
+
+    # Smaller IPA model for "fast" analyses; empty serves them with the full one
+    fast_model = os.getenv("ML_FAST_IPA_MODEL", "neurlang/ipa-whisper-base")
+    if fast_model:
+        print("Loading fast IPA model...")
+        fast_whisper_converter = WhisperIPAConverter(model_name=fast_model, device=device)
+
     gruut_converter = GruutIPAConverter(language=language)
     aligner = PhonemeAligner()
     audio_fetcher = AudioFetcher()
@@ -73,7 +98,7 @@ def load_models(device: Optional[str] = None, language: str = "en-us"):
 
 
 @router.post("/analyze-pronunciation", response_model=PronunciationResponse)
-async def analyze_pronunciation(request: PronunciationRequest) -> PronunciationResponse:
+async def analyze_pronunciation(request: PronunciationRequest, response: Response) -> PronunciationResponse:
     """
     Analyze pronunciation by comparing audio to expected text.
 
@@ -83,16 +108,74 @@ async def analyze_pronunciation(request: PronunciationRequest) -> PronunciationR
     3. Converts expected text to IPA using gruut
     4. Aligns and compares the phonemes
     5. Returns detailed analysis results
+
+    With a callback_url the job is accepted with 202 and the result (the same
+    body plus callback_token) is POSTed to that URL when it finishes.
     """
-    if not get_models_loaded():
-        return PronunciationResponse(
-            status="error",
-            error=PronunciationError(
-                code="MODELS_NOT_LOADED",
-                message="ML models are not loaded. Server may still be starting.",
-                retryable=True
-            )
+    if request.callback_url:
+        if not get_models_loaded():
+            return models_not_loaded_response()
+
+        task = asyncio.create_task(analyze_and_callback(request))
+        callback_tasks.add(task)
+        task.add_done_callback(callback_tasks.discard)
+
+        response.status_code = 202
+        return PronunciationResponse(status="accepted")
+
+    return await run_pronunciation_analysis(request)
+
+
+def ipa_converter_for(quality: str) -> Tuple[WhisperIPAConverter, str]:
+    """The audio-to-IPA converter for a requested quality, and the quality it gives."""
+    if quality == "fast" and fast_whisper_converter is not None:
+        return fast_whisper_converter, "fast"
+    return whisper_converter, "accurate"
+
+
+def models_not_loaded_response() -> PronunciationResponse:
+    """Error returned while models are still loading."""
+    return PronunciationResponse(
+        status="error",
+        error=PronunciationError(
+            code="MODELS_NOT_LOADED",
+            message="ML models are not loaded. Server may still be starting.",
+            retryable=True
         )
+    )
+
+
+async def analyze_and_callback(request: PronunciationRequest) -> None:
+    """Run an accepted async analysis and deliver the result to its callback URL."""
+    result = await run_pronunciation_analysis(request)
+    body = PronunciationCallback(callback_token=request.callback_token or "", **result.model_dump())
+    content = json.dumps(body.model_dump()).encode()
+    headers = {"Content-Type": "application/json"}
+    callback = urlsplit(request.callback_url)
+
+    async with httpx.AsyncClient(timeout=30.0) as client:
+        for attempt, delay in enumerate((0, *CALLBACK_RETRY_DELAYS), start=1):
+            await asyncio.sleep(delay)
+            # Signed per attempt so retries don't go stale
+            if service_signer is not None:
+                headers.update(service_signer.sign("POST", callback.path, callback.query, content))
+            try:
+                resp = await client.post(request.callback_url, content=content, headers=headers)
+                if resp.status_code < 500:
+                    if resp.status_code >= 400:
+                        print(f"[callback] Rejected with HTTP {resp.status_code}; not retrying")
+                    return
+                print(f"[callback] Attempt {attempt} got HTTP {resp.status_code}")
+            except httpx.RequestError as e:
+                print(f"[callback] Attempt {attempt} failed: {e}")
+
+    print(f"[callback] Giving up delivering result to {request.callback_url}")
+
+
+async def run_pronunciation_analysis(request: PronunciationRequest) -> PronunciationResponse:
+    """Run the full analysis pipeline, returning errors as structured responses."""
+    if not get_models_loaded():
+        return models_not_loaded_response()
 
     start_time = time.time()
 
@@ -130,7 +213,8 @@ async def analyze_pronunciation(request: PronunciationRequest) -> PronunciationR
         print(f"[DEBUG] Audio duration: {len(audio_array) / sample_rate:.2f}s")
         print(f"[DEBUG] Expected text: '{request.expected_text}'")
 
-        audio_ipa = whisper_converter.audio_to_ipa(
+        converter, quality = ipa_converter_for(request.quality)
+        audio_ipa = converter.audio_to_ipa(
             audio_array,
             sample_rate,
             language=whisper_lang
@@ -203,7 +287,8 @@ async def analyze_pronunciation(request: PronunciationRequest) -> PronunciationR
             insertion_count=insertion_count,
             phoneme_details=phoneme_details,
             audio_quality=audio_quality,
-            processing_time_ms=processing_time_ms
+            processing_time_ms=processing_time_ms,
+            quality=quality
         )
 
         return PronunciationResponse(
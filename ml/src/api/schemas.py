@@ -2,7 +2,7 @@
 Pydantic models for API request/response schemas.
 """
 
-from typing import List, Optional
+from typing import List, Literal, Optional
 from pydantic import BaseModel, Field
 
 
@@ -21,6 +21,18 @@ class PronunciationRequest(BaseModel):
         default="en-us",
         description="Language code for phoneme conversion (e.g., 'en-us', 'es', 'fr')"
     )
+    quality: Literal["fast", "accurate"] = Field(
+        default="accurate",
+        description="'fast' uses the smaller IPA model: quicker, less accurate"
+    )
+    callback_url: Optional[str] = Field(
+        default=None,
+        description="If set, respond 202 immediately and POST the result to this URL"
+    )
+    callback_token: Optional[str] = Field(
+        default=None,
+        description="Opaque token echoed back with the callback result"
+    )
 
 
 class PhonemeDetail(BaseModel):
@@ -66,6 +78,10 @@ class PronunciationAnalysis(BaseModel):
         description="Audio quality metrics"
     )
     processing_time_ms: int = Field(..., description="Processing time in milliseconds")
+    quality: str = Field(
+        default="accurate",
+        description="Quality level that produced the analysis"
+    )
 
 
 class PronunciationError(BaseModel):
@@ -84,7 +100,7 @@ class PronunciationResponse(BaseModel):
 
     status: str = Field(
         ...,
-        description="Status of the analysis: 'success' or 'error'"
+        description="Status of the analysis: 'success', 'error', or 'accepted' for async jobs"
     )
     analysis: Optional[PronunciationAnalysis] = Field(
         default=None,
@@ -96,11 +112,21 @@ class PronunciationResponse(BaseModel):
     )
 
 
+class PronunciationCallback(PronunciationResponse):
+    """Body POSTed to the callback URL when an async analysis finishes."""
+
+    callback_token: str = Field(..., description="Token from the original request")
+
+
 class HealthResponse(BaseModel):
     """Health check response."""
 
     status: str = Field(default="healthy")
     model_loaded: bool = Field(default=False)
+    queue_depth: int = Field(
+        default=0,
+        description="Number of inference requests currently in flight"
+    )
 
 
 # STT Schemas
@@ -0,0 +1,96 @@
+"""
+Signed requests between the ML service and the API.
+
+Both services share INTERNAL_SERVICE_SECRET. Every request carries a
+timestamp and a hex HMAC-SHA256 over
+
+    <unix timestamp>\\n<METHOD>\\n<path>\\n<raw query>\\n<hex SHA-256 of body>
+
+matching the API's client.ServiceSigner. The raw query is signed as sent, so
+a request's parameters cannot be changed in transit. Secrets listed in
+INTERNAL_SERVICE_PREVIOUS_SECRETS are still accepted, so the secret can be
+rotated one service at a time.
+"""
+
+import hashlib
+import hmac
+import os
+import time
+from typing import Dict, List, Optional
+
+from fastapi import HTTPException, Request
+
+HEADER_TIMESTAMP = "X-Service-Timestamp"
+HEADER_SIGNATURE = "X-Service-Signature"
+
+# How far a signed request's timestamp may be from now, in seconds
+MAX_CLOCK_SKEW = 300
+
+
+class ServiceSigner:
+    """Signs outgoing and verifies incoming internal requests."""
+
+    def __init__(self, secret: str, previous: Optional[List[str]] = None):
+        self.secret = secret.encode()
+        self.accepted = [self.secret] + [p.encode() for p in (previous or []) if p]
+
+    def sign(self, method: str, path: str, query: str, body: bytes) -> Dict[str, str]:
+        """Return the signature headers for a request."""
+        timestamp = str(int(time.time()))
+        return {
+            HEADER_TIMESTAMP: timestamp,
+            HEADER_SIGNATURE: _mac(self.secret, timestamp, method, path, query, body),
+        }
+
+    def verify(self, method: str, path: str, query: str, headers, body: bytes) -> Optional[str]:
+        """Check a request's signature. Returns the reason it failed, or None if valid."""
+        timestamp = headers.get(HEADER_TIMESTAMP)
+        signature = headers.get(HEADER_SIGNATURE)
+        if not timestamp or not signature:
+            return "missing signature headers"
+
+        try:
+            signed_at = int(timestamp)
+        except ValueError:
+            return "malformed timestamp"
+        if abs(time.time() - signed_at) > MAX_CLOCK_SKEW:
+            return "timestamp outside allowed skew"
+
+        for secret in self.accepted:
+            if hmac.compare_digest(signature, _mac(secret, timestamp, method, path, query, body)):
+                return None
+        return "signature mismatch"
+
+
+def _mac(secret: bytes, timestamp: str, method: str, path: str, query: str, body: bytes) -> str:
+    body_hash = hashlib.sha256(body).hexdigest()
+    message = f"{timestamp}\n{method}\n{path}\n{query}\n{body_hash}".encode()
+    return hmac.new(secret, message, hashlib.sha256).hexdigest()
+
+
+def signer_from_env() -> Optional[ServiceSigner]:
+    """Build the signer from the environment, or None if no secret is set."""
+    secret = os.getenv("INTERNAL_SERVICE_SECRET", "")
+    if not secret:
+        print("Warning: INTERNAL_SERVICE_SECRET not set; internal requests are not authenticated")
+        return None
+    previous = os.getenv("INTERNAL_SERVICE_PREVIOUS_SECRETS", "").split(",")
+    return ServiceSigner(secret, previous)
+
+
+service_signer: Optional[ServiceSigner] = signer_from_env()
+
+
+async def require_service_signature(request: Request) -> None:
+    """FastAPI dependency rejecting requests not signed by the API."""
+    if service_signer is None:
+        return
+
+    body = await request.body()
+    reason = service_signer.verify(
+        request.method, request.url.path, request.url.query, request.headers, body
+    )
+    if reason is not None:
+        client = request.client.host if request.client else "unknown"
+        print(f"[service-auth] Rejected {request.method} {request.url.path} from {client}: {reason}")
+        raise HTTPException(status_code=401, detail="Invalid service signature")
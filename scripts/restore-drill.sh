@@ -0,0 +1,89 @@
+#!/usr/bin/env bash
+#
+# Restore drill: restores the database to its latest restorable time in a
+# scratch RDS instance, runs restore-check against it as a one-off ECS task
+# (the database is only reachable from inside the VPC), then deletes the
+# scratch instance. Exits with restore-check's status.
+#
+# Usage:
+#     scripts/restore-drill.sh [environment]    # default: prod
+#
+# Requirements: aws CLI v2 with RDS, ECS and CloudWatch Logs access.
+
+set -euo pipefail
+
+ENVIRONMENT="${1:-prod}"
+PREFIX="ling-${ENVIRONMENT}"
+SOURCE="${PREFIX}-db"
+SCRATCH="${PREFIX}-restore-drill-$(date -u +%Y%m%d%H%M)"
+CLUSTER="${PREFIX}-cluster"
+SERVICE="${PREFIX}-api"
+
+log() { echo "[restore-drill] $*" >&2; }
+
+read -r SUBNET_GROUP SECURITY_GROUP INSTANCE_CLASS < <(aws rds describe-db-instances \
+  --db-instance-identifier "$SOURCE" \
+  --query 'DBInstances[0].[DBSubnetGroup.DBSubnetGroupName, VpcSecurityGroups[0].VpcSecurityGroupId, DBInstanceClass]' \
+  --output text)
+
+cleanup() {
+  log "Deleting ${SCRATCH}"
+  aws rds delete-db-instance \
+    --db-instance-identifier "$SCRATCH" \
+    --skip-final-snapshot \
+    --delete-automated-backups >/dev/null || log "Delete failed; remove ${SCRATCH} by hand"
+}
+
+log "Restoring ${SOURCE} at its latest restorable time into ${SCRATCH}"
+aws rds restore-db-instance-to-point-in-time \
+  --source-db-instance-identifier "$SOURCE" \
+  --target-db-instance-identifier "$SCRATCH" \
+  --use-latest-restorable-time \
+  --db-instance-class "$INSTANCE_CLASS" \
+  --db-subnet-group-name "$SUBNET_GROUP" \
+  --vpc-security-group-ids "$SECURITY_GROUP" \
+  --no-publicly-accessible \
+  --no-multi-az >/dev/null
+trap cleanup EXIT
+
+log "Waiting for ${SCRATCH} to become available (this takes a while)"
+aws rds wait db-instance-available --db-instance-identifier "$SCRATCH"
+
+HOST=$(aws rds describe-db-instances \
+  --db-instance-identifier "$SCRATCH" \
+  --query 'DBInstances[0].Endpoint.Address' \
+  --output text)
+log "Restored to ${HOST}"
+
+NETWORK=$(aws ecs describe-services \
+  --cluster "$CLUSTER" \
+  --services "$SERVICE" \
+  --query 'services[0].networkConfiguration' \
+  --output json)
+
+TASK=$(aws ecs run-task \
+  --cluster "$CLUSTER" \
+  --task-definition "$SERVICE" \
+  --launch-type FARGATE \
+  --network-configuration "$NETWORK" \
+  --overrides "{\"containerOverrides\":[{\"name\":\"api\",\"command\":[\"/app/restore-check\",\"-host\",\"${HOST}\"]}]}" \
+  --query 'tasks[0].taskArn' \
+  --output text)
+log "Running restore-check as ${TASK}"
+
+aws ecs wait tasks-stopped --cluster "$CLUSTER" --tasks "$TASK"
+
+EXIT_CODE=$(aws ecs describe-tasks \
+  --cluster "$CLUSTER" \
+  --tasks "$TASK" \
+  --query 'tasks[0].containers[0].exitCode' \
+  --output text)
+
+aws logs tail "/ecs/${SERVICE}" --log-stream-names "ecs/api/${TASK##*/}" --since 2h --format short || true
+
+if [ "$EXIT_CODE" = "0" ]; then
+  log "PASS"
+else
+  log "FAIL (restore-check exited with ${EXIT_CODE})"
+fi
+exit "${EXIT_CODE/None/1}"
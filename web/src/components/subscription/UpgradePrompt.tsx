@@ -8,8 +8,12 @@ import {
   DialogTitle,
 } from '@/components/ui/dialog'
 import { Button } from '@/components/ui/button'
-import { useCreateCheckout, useCredits } from '@/hooks/use-subscription'
-import { TIER_INFO } from '@/lib/api'
+import {
+  useCreateCheckout,
+  useCredits,
+  usePricing,
+} from '@/hooks/use-subscription'
+import { TIER_INFO, tierPriceLabel } from '@/lib/api'
 
 interface UpgradePromptProps {
   open: boolean
@@ -19,12 +23,13 @@ interface UpgradePromptProps {
 
 export function UpgradePrompt({ open, onOpenChange, creditsNeeded }: UpgradePromptProps) {
   const { data: credits } = useCredits()
+  const { data: pricing } = usePricing()
   const createCheckout = useCreateCheckout()
   const [selectedTier, setSelectedTier] = useState<'basic' | 'pro' | null>(null)
 
   const handleUpgrade = (tier: 'basic' | 'pro') => {
     setSelectedTier(tier)
-    createCheckout.mutate(tier)
+    createCheckout.mutate({ tier, currency: pricing?.currency })
   }
 
   return (
@@ -59,7 +64,7 @@ export function UpgradePrompt({ open, onOpenChange, creditsNeeded }: UpgradeProm
                 </p>
               </div>
               <div className="flex items-center gap-3">
-                <span className="font-semibold">${TIER_INFO.basic.price}/mo</span>
+                <span className="font-semibold">{tierPriceLabel(pricing, 'basic')}/mo</span>
                 <Button
                   size="sm"
                   variant="outline"
@@ -89,7 +94,7 @@ export function UpgradePrompt({ open, onOpenChange, creditsNeeded }: UpgradeProm
                 </p>
               </div>
               <div className="flex items-center gap-3">
-                <span className="font-semibold">${TIER_INFO.pro.price}/mo</span>
+                <span className="font-semibold">{tierPriceLabel(pricing, 'pro')}/mo</span>
                 <Button
                   size="sm"
                   onClick={() => handleUpgrade('pro')}
@@ -42,6 +42,8 @@ export function ConversationMessagesView({ threadId }: ConversationMessagesViewP
         {thread?.messages.map((message) => (
           <MessageBubble
             key={message.id}
+            threadId={threadId}
+            messageId={message.id}
             role={message.role as 'user' | 'assistant'}
             content={message.content}
             timestamp={message.timestamp}
@@ -50,6 +52,7 @@ export function ConversationMessagesView({ threadId }: ConversationMessagesViewP
             pronunciationStatus={message.pronunciationStatus}
             pronunciationAnalysis={message.pronunciationAnalysis}
             pronunciationError={message.pronunciationError}
+            expectedText={message.expectedText}
           />
         ))}
       </div>
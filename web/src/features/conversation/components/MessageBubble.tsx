@@ -6,21 +6,27 @@ import { formatMessageTime } from '@/lib/formatting'
 import { cn } from '@/lib/utils'
 import { Volume2, ChevronDown } from 'lucide-react'
 import { useAudioPlayerContext } from '@/contexts/AudioPlayerContext'
+import { useMessageAnalysis } from '@/hooks/use-thread'
 import { PronunciationDisplay } from './PronunciationDisplay'
-import type { PronunciationAnalysis } from '@/lib/api'
+import type { Message, PronunciationAnalysis } from '@/lib/api'
 
 interface MessageBubbleProps {
+  threadId: string
+  messageId: string
   role: 'user' | 'assistant'
   content: string
   timestamp: string | Date
   audioUrl?: string
   hasAudio?: boolean
-  pronunciationStatus?: 'none' | 'pending' | 'complete' | 'failed'
+  pronunciationStatus?: Message['pronunciationStatus']
   pronunciationAnalysis?: PronunciationAnalysis
   pronunciationError?: string
+  expectedText?: string
 }
 
 export function MessageBubble({
+  threadId,
+  messageId,
   role,
   content,
   timestamp,
@@ -29,6 +35,7 @@ export function MessageBubble({
   pronunciationStatus,
   pronunciationAnalysis,
   pronunciationError,
+  expectedText,
 }: MessageBubbleProps) {
   const isUser = role === 'user'
   const audioPlayer = useAudioPlayerContext()
@@ -41,6 +48,13 @@ export function MessageBubble({
   // Show chevron when pronunciation is complete
   const showPronunciationToggle = isUser && hasAudio && pronunciationStatus === 'complete'
 
+  // Threads load without analyses; fetch this one the first time it's expanded
+  const { data: fetchedAnalysis, isError: analysisFailed } = useMessageAnalysis(
+    threadId,
+    messageId,
+    showPronunciationToggle && isPronunciationExpanded && !pronunciationAnalysis,
+  )
+
   const handlePlayAudio = () => {
     if (!audioUrl) return
 
@@ -141,9 +155,10 @@ export function MessageBubble({
           {isUser && hasAudio && pronunciationStatus && pronunciationStatus !== 'none' && (
             <PronunciationDisplay
               status={pronunciationStatus}
-              analysis={pronunciationAnalysis}
+              analysis={pronunciationAnalysis ?? fetchedAnalysis}
+              analysisFailed={analysisFailed}
               error={pronunciationError}
-              expectedText={content}
+              expectedText={expectedText ?? content}
               isExpanded={isPronunciationExpanded}
               onToggleExpand={() => setIsPronunciationExpanded(!isPronunciationExpanded)}
             />
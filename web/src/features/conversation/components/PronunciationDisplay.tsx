@@ -23,8 +23,9 @@ function getFriendlyErrorMessage(error?: string): string {
 }
 
 interface PronunciationDisplayProps {
-  status: 'none' | 'pending' | 'complete' | 'failed'
+  status: 'none' | 'pending' | 'complete' | 'failed' | 'skipped_divergent'
   analysis?: PronunciationAnalysis
+  analysisFailed?: boolean
   error?: string
   expectedText?: string
   isExpanded?: boolean
@@ -121,6 +122,7 @@ function getWordColorClass(status: WordStatus): string {
 export function PronunciationDisplay({
   status,
   analysis,
+  analysisFailed,
   error,
   expectedText,
   isExpanded: controlledExpanded,
@@ -147,6 +149,18 @@ export function PronunciationDisplay({
     )
   }
 
+  // Skipped: the recording didn't match the practice line closely enough to score
+  if (status === 'skipped_divergent') {
+    return (
+      <div className="flex items-center gap-2 rounded-b-2xl bg-muted/50 px-4 py-2">
+        <AlertCircle className="h-4 w-4 text-muted-foreground" />
+        <span className="text-xs text-muted-foreground">
+          Not scored: what we heard was too different from the practice line.
+        </span>
+      </div>
+    )
+  }
+
   // Failed state
   if (status === 'failed') {
     return (
@@ -159,6 +173,21 @@ export function PronunciationDisplay({
     )
   }
 
+  // Complete, expanded, and the analysis is still being fetched
+  if (status === 'complete' && !analysis && isExpanded) {
+    return analysisFailed ? (
+      <div className="flex items-center gap-2 rounded-b-2xl bg-destructive/10 px-4 py-2">
+        <AlertCircle className="h-4 w-4 text-destructive" />
+        <span className="text-xs text-destructive">Couldn't load the analysis. Please try again.</span>
+      </div>
+    ) : (
+      <div className="flex items-center gap-2 rounded-b-2xl bg-muted/50 px-4 py-2">
+        <Loader2 className="h-4 w-4 animate-spin text-muted-foreground" />
+        <span className="text-xs text-muted-foreground">Loading analysis...</span>
+      </div>
+    )
+  }
+
   // Complete state
   if (status === 'complete' && analysis) {
     const wordStatuses = expectedText
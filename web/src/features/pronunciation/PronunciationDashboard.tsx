@@ -1,15 +1,19 @@
 import { useState, useCallback, useEffect } from 'react'
 import { Link } from '@tanstack/react-router'
-import { Loader2, ChevronLeft, ChevronRight, X } from 'lucide-react'
+import { Loader2, ChevronLeft, ChevronRight, X, Download, FileText } from 'lucide-react'
 import useEmblaCarousel from 'embla-carousel-react'
+import { toast } from 'sonner'
 import { cn } from '@/lib/utils'
 import { Button } from '@/components/ui/button'
-import { usePhonemeStats } from '@/hooks/use-phoneme-stats'
+import { usePhonemeStats, useExportAnkiDeck, useCreatePronunciationReport } from '@/hooks/use-phoneme-stats'
+import { handleError } from '@/lib/error-handler'
 import { PhonemeGrid } from './components/PhonemeGrid'
 import { CATEGORY_ORDER, CATEGORY_LABELS } from '@/data/phonemes'
 
 export function PronunciationDashboard() {
   const { data: stats, isLoading, error } = usePhonemeStats()
+  const exportDeck = useExportAnkiDeck()
+  const createReport = useCreatePronunciationReport()
   const [activeIndex, setActiveIndex] = useState(0)
 
   // Embla carousel for swipe gestures
@@ -32,6 +36,25 @@ export function PronunciationDashboard() {
   const scrollNext = useCallback(() => emblaApi?.scrollNext(), [emblaApi])
   const scrollTo = useCallback((index: number) => emblaApi?.scrollTo(index), [emblaApi])
 
+  const handleExport = () => {
+    exportDeck.mutate(undefined, {
+      onSuccess: () =>
+        toast.success("Building your Anki deck. We'll notify you when it's ready to download."),
+      onError: (err) => handleError(err, 'Export Anki deck'),
+    })
+  }
+
+  const handleReport = () => {
+    createReport.mutate(undefined, {
+      // Opened from the toast: a popup after an await would be blocked
+      onSuccess: (report) =>
+        toast.success('Your report is ready. The link works for 7 days.', {
+          action: { label: 'Open PDF', onClick: () => window.open(report.url, '_blank', 'noopener') },
+        }),
+      onError: (err) => handleError(err, 'Create pronunciation report'),
+    })
+  }
+
   if (isLoading) {
     return (
       <div className="h-full flex items-center justify-center">
@@ -156,6 +179,33 @@ export function PronunciationDashboard() {
                 )}>
                   {stats.overallAccuracy.toFixed(0)}%
                 </span>
+                <Button
+                  variant="outline"
+                  size="sm"
+                  onClick={handleExport}
+                  disabled={exportDeck.isPending}
+                  className="ml-2"
+                >
+                  {exportDeck.isPending ? (
+                    <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+                  ) : (
+                    <Download className="mr-2 h-4 w-4" />
+                  )}
+                  Export to Anki
+                </Button>
+                <Button
+                  variant="outline"
+                  size="sm"
+                  onClick={handleReport}
+                  disabled={createReport.isPending}
+                >
+                  {createReport.isPending ? (
+                    <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+                  ) : (
+                    <FileText className="mr-2 h-4 w-4" />
+                  )}
+                  Share report
+                </Button>
               </div>
             )}
           </div>
@@ -1,5 +1,5 @@
 import { useState, useRef, useEffect } from 'react'
-import type { Thread } from '@/lib/api'
+import type { Thread, ThreadSummary } from '@/lib/api'
 import { useNavigate, useParams } from '@tanstack/react-router'
 import {
   MessageSquare,
@@ -33,7 +33,7 @@ import {
 } from '@/hooks/use-thread'
 
 interface ThreadListItemProps {
-  thread: Thread
+  thread: Thread | ThreadSummary
 }
 
 export function ThreadListItem({ thread }: ThreadListItemProps) {
@@ -53,13 +53,17 @@ export function ThreadListItem({ thread }: ThreadListItemProps) {
   const archiveThread = useArchiveThread()
   const unarchiveThread = useUnarchiveThread()
 
-  // Get the display name: explicit name, or first user message, or fallback
+  // Get the display name: explicit name, latest message, first user message, or fallback
   const title =
     thread.name ||
+    ('lastMessagePreview' in thread && thread.lastMessagePreview) ||
     thread.messages.find((m) => m.role === 'user')?.content ||
     thread.messages[0]?.content ||
     'New conversation'
 
+  const unreadCount =
+    'unreadCount' in thread && !isActive ? thread.unreadCount : 0
+
   // Focus and select all when entering edit mode
   useEffect(() => {
     if (isEditing && inputRef.current) {
@@ -164,6 +168,14 @@ export function ThreadListItem({ thread }: ThreadListItemProps) {
               </Tooltip>
             )}
           </div>
+          {unreadCount > 0 && !isEditing && (
+            <span
+              className="mt-0.5 shrink-0 rounded-full bg-primary px-1.5 text-xs font-medium leading-4 text-primary-foreground"
+              aria-label={`${unreadCount} unread`}
+            >
+              {unreadCount > 99 ? '99+' : unreadCount}
+            </span>
+          )}
         </SidebarMenuButton>
         {/* Hover actions button */}
         {!isEditing && (
@@ -1,6 +1,8 @@
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
 import {
   getCurrentUser,
+  getSignupOptions,
+  joinWaitlist,
   login,
   logout,
   register,
@@ -10,6 +12,7 @@ import {
 // Query keys for auth - exported for use in route guards
 export const authKeys = {
   user: ['auth', 'user'] as const,
+  signupOptions: ['auth', 'signup-options'] as const,
 }
 
 /**
@@ -30,6 +33,26 @@ export function useCurrentUser() {
   })
 }
 
+/**
+ * Hook to get whether signups currently need an invite code.
+ */
+export function useSignupOptions() {
+  return useQuery({
+    queryKey: authKeys.signupOptions,
+    queryFn: getSignupOptions,
+    staleTime: 5 * 60 * 1000, // 5 minutes
+  })
+}
+
+/**
+ * Hook for joining the waitlist while signups are invite-only.
+ */
+export function useJoinWaitlist() {
+  return useMutation({
+    mutationFn: joinWaitlist,
+  })
+}
+
 /**
  * Hook for user registration.
  * On success, sets the user in cache (since backend returns user + sets cookie).
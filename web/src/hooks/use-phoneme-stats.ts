@@ -1,15 +1,27 @@
-import { useQuery } from '@tanstack/react-query'
-import { getPhonemeStats } from '@/lib/api'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { createPronunciationReport, exportAnkiDeck, getPhonemeStats, type ThreadLanguage } from '@/lib/api'
 
 export const phonemeStatsKeys = {
   all: ['phonemeStats'] as const,
-  stats: () => [...phonemeStatsKeys.all, 'stats'] as const,
+  stats: (language?: ThreadLanguage) => [...phonemeStatsKeys.all, 'stats', language ?? 'en'] as const,
 }
 
-export function usePhonemeStats() {
+export function usePhonemeStats(language?: ThreadLanguage) {
   return useQuery({
-    queryKey: phonemeStatsKeys.stats(),
-    queryFn: getPhonemeStats,
+    queryKey: phonemeStatsKeys.stats(language),
+    queryFn: () => getPhonemeStats(language),
     staleTime: 60 * 1000, // 1 minute
   })
 }
+
+export function useExportAnkiDeck() {
+  return useMutation({
+    mutationFn: exportAnkiDeck,
+  })
+}
+
+export function useCreatePronunciationReport() {
+  return useMutation({
+    mutationFn: createPronunciationReport,
+  })
+}
@@ -3,6 +3,7 @@ import {
   getSubscriptionStatus,
   getCredits,
   getCreditHistory,
+  getPricing,
   createCheckoutSession,
   createPortalSession,
 } from '@/lib/api'
@@ -12,6 +13,7 @@ export const subscriptionKeys = {
   status: () => [...subscriptionKeys.all, 'status'] as const,
   credits: () => [...subscriptionKeys.all, 'credits'] as const,
   creditHistory: () => [...subscriptionKeys.all, 'history'] as const,
+  pricing: () => [...subscriptionKeys.all, 'pricing'] as const,
 }
 
 export function useSubscription() {
@@ -30,6 +32,15 @@ export function useCredits() {
   })
 }
 
+// Plan prices in the user's currency, from Stripe
+export function usePricing() {
+  return useQuery({
+    queryKey: subscriptionKeys.pricing(),
+    queryFn: () => getPricing(),
+    staleTime: 10 * 60 * 1000, // 10 minutes, as long as the server caches prices
+  })
+}
+
 export function useCreditHistory() {
   return useQuery({
     queryKey: subscriptionKeys.creditHistory(),
@@ -39,7 +50,13 @@ export function useCreditHistory() {
 
 export function useCreateCheckout() {
   return useMutation({
-    mutationFn: (tier: 'basic' | 'pro') => createCheckoutSession(tier),
+    mutationFn: ({
+      tier,
+      currency,
+    }: {
+      tier: 'basic' | 'pro'
+      currency?: string
+    }) => createCheckoutSession(tier, currency),
     onSuccess: (data) => {
       // Redirect to Stripe checkout
       window.location.href = data.url
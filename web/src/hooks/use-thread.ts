@@ -1,3 +1,4 @@
+import { useEffect } from 'react'
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
 import {
   createThread,
@@ -10,12 +11,16 @@ import {
   deleteThread,
   archiveThread,
   unarchiveThread,
+  markThreadRead,
+  getMessageAnalysis,
 } from '@/lib/api'
 
 const threadKeys = {
   all: ['threads'] as const,
   archived: ['threads', 'archived'] as const,
   detail: (threadId: string) => ['threads', threadId] as const,
+  analysis: (threadId: string, messageId: string) =>
+    ['threads', threadId, 'messages', messageId, 'analysis'] as const,
 }
 
 const promptKeys = {
@@ -60,9 +65,29 @@ export function useThread(threadId: string) {
     },
   })
 
+  // Viewing the thread marks everything loaded so far as read
+  const queryClient = useQueryClient()
+  const { dataUpdatedAt } = query
+  useEffect(() => {
+    if (!dataUpdatedAt) return
+    markThreadRead(threadId, new Date(dataUpdatedAt).toISOString())
+      .then(() => queryClient.invalidateQueries({ queryKey: threadKeys.all, exact: true }))
+      .catch((error) => console.error('Failed to mark thread read:', error))
+  }, [threadId, dataUpdatedAt, queryClient])
+
   return query
 }
 
+// An analysis never changes once complete, so it's fetched once per message
+export function useMessageAnalysis(threadId: string, messageId: string, enabled: boolean) {
+  return useQuery({
+    queryKey: threadKeys.analysis(threadId, messageId),
+    queryFn: () => getMessageAnalysis(threadId, messageId),
+    enabled,
+    staleTime: Infinity,
+  })
+}
+
 export function useSendMessage(threadId: string) {
   const queryClient = useQueryClient()
 
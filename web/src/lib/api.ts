@@ -1,3 +1,5 @@
+import { deviceFingerprint } from '@/lib/device-fingerprint'
+
 // In production, nginx proxies /api/* to the internal API service (same origin)
 // In development, use VITE_API_URL to point to local API server
 const API_BASE_URL = import.meta.env.VITE_API_URL || ''
@@ -65,30 +67,126 @@ export interface PronunciationAnalysis {
   processing_time_ms: number
 }
 
+export interface CorrectionSpan {
+  start: number
+  end: number
+}
+
 export interface Message {
   id: string
   role: 'user' | 'assistant'
   content: string
   timestamp: string
   audioUrl?: string
+  // Format of the recording; unset for older ones, whose key ends in it
+  audioFormat?: 'webm' | 'mp3'
   audioDurationSeconds?: number
   hasAudio?: boolean
-  pronunciationStatus?: 'none' | 'pending' | 'complete' | 'failed'
+  expectedText?: string
+  pronunciationStatus?:
+    | 'none'
+    | 'pending'
+    | 'complete'
+    | 'failed'
+    | 'skipped_divergent'
+  pronunciationAnalysis?: PronunciationAnalysis
+  pronunciationError?: string
+  pronunciationConfidence?: number
+  pronunciationLowConfidence?: boolean
+  // Model that scored the recording; 'fast' is less accurate
+  pronunciationQuality?: AnalysisQuality
+  // Times a failed analysis was run again
+  pronunciationRetries?: number
+  suggestedReplies?: string[]
+  spokenText?: string
+  // Transcript as recognized, when punctuation was restored in content
+  rawTranscript?: string
+  // Set once the user has corrected the transcript
+  transcriptCorrectedAt?: string
+  adaptation?: MessageAdaptation
+  // Tone the assistant's reply was spoken in
+  tone?: 'neutral' | 'cheerful' | 'calm' | 'questioning'
+  // Parts of the learner's message correctedMessageId that the reply
+  // corrects, as UTF-16 offsets into its content (content.slice(start, end)).
+  // Stale once that message's transcript is corrected after the reply.
+  corrections?: CorrectionSpan[]
+  correctedMessageId?: string
+  // 'long_form' for a monologue sent as several recordings, 'text' for a typed message
+  kind?: 'long_form' | 'text'
+  chunks?: MessageChunk[]
+}
+
+// One recording of a long-form message, analyzed on its own
+export interface MessageChunk {
+  id: string
+  messageId: string
+  position: number
+  transcript: string
+  audioUrl?: string
+  audioFormat?: 'webm'
+  durationSeconds: number
+  pronunciationStatus: 'pending' | 'complete' | 'failed'
   pronunciationAnalysis?: PronunciationAnalysis
   pronunciationError?: string
+  pronunciationConfidence?: number
+  pronunciationLowConfidence?: boolean
+  pronunciationQuality?: AnalysisQuality
+  createdAt: string
+}
+
+export type AnalysisQuality = 'fast' | 'accurate'
+
+// How an assistant reply was pitched to the learner's recent turns
+export interface MessageAdaptation {
+  level: 'simplify' | 'steady' | 'challenge'
+  reasons?: string[]
+  turns: number
+  words: number
+  wer?: number
+  latencySeconds?: number
+  speechRate: number
 }
 
 export interface Thread {
   id: string
   name?: string | null
   archivedAt?: string | null
+  goal?: string | null
+  goalCompletedAt?: string | null
+  suggestReplies?: boolean
+  // Voice turns come back without text; see revealMessageText
+  speechOnly?: boolean
+  // Language practised; unset is English
+  language?: ThreadLanguage
+  locale?: string
+  // Overrides of the user's settings; unset uses them
+  replyLength?: ReplyLength
+  speechRate?: number
+  // Set once the thread reached its length cap; continue it in a new thread
+  endedAt?: string | null
+  continuedFromId?: string | null
   messages: Message[]
   createdAt: string
 }
 
+// A thread as listed by GET /api/threads, without its messages
+export interface ThreadSummary extends Thread {
+  lastMessagePreview: string | null
+  lastActivityAt: string
+  messageCount: number
+  unreadCount: number
+  lastReadAt: string | null
+}
+
 interface CreateThreadRequest {
   initialPrompt?: string
   firstUserMessage?: string
+  goal?: string
+  suggestReplies?: boolean
+  speechOnly?: boolean
+  language?: ThreadLanguage
+  // Must be a variant of the language, e.g. 'es-MX' for 'es'
+  locale?: string
 }
 
 export async function getRandomPrompt(): Promise<string> {
@@ -99,9 +197,16 @@ export async function getRandomPrompt(): Promise<string> {
 export async function createThread(
   request?: CreateThreadRequest,
 ): Promise<Thread> {
+  // The browser's locale decides how numbers and dates are read aloud, if
+  // it is a variant of the language practised
+  const language = request?.language
+  const locale =
+    !language || navigator.language.split('-')[0].toLowerCase() === language
+      ? navigator.language
+      : undefined
   return callAPI<Thread>('/api/threads', {
     method: 'POST',
-    body: request ? JSON.stringify(request) : undefined,
+    body: JSON.stringify({ locale, ...request }),
   })
 }
 
@@ -143,8 +248,8 @@ export async function sendMessage(
   })
 }
 
-export async function getThreads(): Promise<Thread[]> {
-  return callAPI<Thread[]>('/api/threads')
+export async function getThreads(): Promise<ThreadSummary[]> {
+  return callAPI<ThreadSummary[]>('/api/threads')
 }
 
 export async function getArchivedThreads(): Promise<Thread[]> {
@@ -153,7 +258,18 @@ export async function getArchivedThreads(): Promise<Thread[]> {
 
 export async function updateThread(
   threadId: string,
-  data: { name?: string | null },
+  data: {
+    name?: string | null
+    goal?: string
+    suggestReplies?: boolean
+    speechOnly?: boolean
+    // Only while the thread has no messages
+    language?: ThreadLanguage
+    locale?: string
+    // '' and 0 go back to the user's settings
+    replyLength?: ReplyLength | ''
+    speechRate?: number
+  },
 ): Promise<Thread> {
   return callAPI<Thread>(`/api/threads/${threadId}`, {
     method: 'PATCH',
@@ -179,25 +295,206 @@ export async function unarchiveThread(threadId: string): Promise<Thread> {
   })
 }
 
+// Starts a new thread from an ended one, opening with a recap of it
+export async function continueThread(threadId: string): Promise<Thread> {
+  return callAPI<Thread>(`/api/threads/${threadId}/continue`, {
+    method: 'POST',
+  })
+}
+
+export interface ThreadReadState {
+  threadId: string
+  lastReadAt: string
+  updatedAt: string
+}
+
+// Records how far the user has read; the server never moves it backwards
+export async function markThreadRead(
+  threadId: string,
+  readAt?: string,
+): Promise<ThreadReadState> {
+  return callAPI<ThreadReadState>(`/api/threads/${threadId}/read`, {
+    method: 'POST',
+    body: readAt ? JSON.stringify({ readAt }) : undefined,
+  })
+}
+
+export interface MessageAnalysis {
+  messageId: string
+  pronunciationStatus: Message['pronunciationStatus']
+  pronunciationAnalysis: PronunciationAnalysis
+}
+
+// Thread responses leave analyses out; they're fetched when a message is expanded
+export async function getMessageAnalysis(
+  threadId: string,
+  messageId: string,
+): Promise<PronunciationAnalysis> {
+  const response = await callAPI<MessageAnalysis>(
+    `/api/threads/${threadId}/messages/${messageId}/analysis`,
+  )
+  return response.pronunciationAnalysis
+}
+
+export interface MessageText {
+  messageId: string
+  content: string
+  rawTranscript?: string | null
+  spokenText?: string | null
+  suggestedReplies?: string[] | null
+  corrections?: CorrectionSpan[] | null
+  correctedMessageId?: string | null
+}
+
+// The text a speech-only thread left out of a voice turn
+export async function revealMessageText(
+  threadId: string,
+  messageId: string,
+): Promise<MessageText> {
+  return callAPI<MessageText>(
+    `/api/threads/${threadId}/messages/${messageId}/text`,
+  )
+}
+
+// Fixes a misheard transcript; a changed transcript is rescored
+export async function correctTranscript(
+  threadId: string,
+  messageId: string,
+  content: string,
+): Promise<Message> {
+  return callAPI<Message>(`/api/threads/${threadId}/messages/${messageId}`, {
+    method: 'PATCH',
+    body: JSON.stringify({ content }),
+  })
+}
+
+// Runs a failed pronunciation analysis again; the message comes back pending
+export async function retryPronunciation(messageId: string): Promise<Message> {
+  return callAPI<Message>(`/api/messages/${messageId}/pronunciation/retry`, {
+    method: 'POST',
+  })
+}
+
 export interface SendAudioMessageResponse {
   userMessage: Message
   assistantMessage: Message
+  // The reply wrapped up the thread, which takes no more messages
+  threadEnded: boolean
+  // Speech-only thread: the messages come without their text
+  textWithheld: boolean
+  // The reply couldn't be spoken and came back as text only
+  warning?: { code: 'SPEECH_FAILED'; error: string }
 }
 
+// Messages on the GET /api/ws live update socket
+export type LiveUpdate =
+  | { type: 'message'; threadId: string; messageId: string }
+  | {
+      type: 'analysis'
+      threadId: string
+      messageId: string
+      status: 'complete' | 'failed'
+    }
+  | { type: 'title'; threadId: string; title: string }
+  | { type: 'ping' }
+  | { type: 'closed'; reason: 'behind' | 'restart' }
+
+// Events from POST /api/threads/:id/messages/audio/stream, in order. Tokens
+// are a draft of the reply; the reply event has the text to keep.
+export type VoiceTurnEvent =
+  | { event: 'transcribed'; data: { message: Message } }
+  | { event: 'token'; data: { text: string } }
+  | { event: 'retracted'; data: Record<string, never> }
+  | { event: 'reply'; data: { text: string } }
+  | { event: 'turn'; data: SendAudioMessageResponse }
+  | {
+      event: 'error'
+      data: { code: string; error: string; details?: { stage: string } }
+    }
+
+// Sends a recorded turn. Pass the same idempotencyKey when retrying a send
+// that timed out, so the turn isn't charged or posted twice.
 export async function sendAudioMessage(
   threadId: string,
   audioBlob: Blob,
+  expectedText?: string,
+  idempotencyKey?: string,
 ): Promise<SendAudioMessageResponse> {
   const formData = new FormData()
   formData.append('audio', audioBlob, 'recording.webm')
+  if (expectedText) {
+    formData.append('expectedText', expectedText)
+  }
+
+  return postTurn(
+    `/api/threads/${threadId}/messages/audio`,
+    formData,
+    idempotencyKey,
+  )
+}
+
+// Sends a typed message, for when the user can't speak. The reply has no
+// audio unless speak is set (always spoken in speech-only threads).
+export async function sendTextMessage(
+  threadId: string,
+  content: string,
+  speak = false,
+): Promise<SendAudioMessageResponse> {
+  return postTurn(
+    `/api/threads/${threadId}/messages`,
+    JSON.stringify({ content, speak }),
+  )
+}
+
+// Sends a monologue as several recordings, in order. Pro users may send a
+// single recording of up to 5 minutes instead.
+export async function sendLongFormMessage(
+  threadId: string,
+  recordings: Blob[],
+): Promise<SendAudioMessageResponse> {
+  const formData = new FormData()
+  recordings.forEach((recording, i) => {
+    formData.append('audio', recording, `recording-${i}.webm`)
+  })
+
+  return postTurn(
+    `/api/threads/${threadId}/messages/long-form`,
+    formData,
+  )
+}
+
+export async function getMessageChunks(
+  threadId: string,
+  messageId: string,
+): Promise<MessageChunk[]> {
+  const response = await callAPI<{ messageId: string; chunks: MessageChunk[] }>(
+    `/api/threads/${threadId}/messages/${messageId}/chunks`,
+  )
+  return response.chunks
+}
 
-  const url = `${API_BASE_URL}/api/threads/${threadId}/messages/audio`
+// Posts a turn, as form data for recordings or a JSON string for typed text
+async function postTurn(
+  path: string,
+  body: FormData | string,
+  idempotencyKey?: string,
+): Promise<SendAudioMessageResponse> {
+  const url = `${API_BASE_URL}${path}`
   // Note: API_BASE_URL is empty in production (same-origin proxy via nginx)
 
   try {
+    const headers: Record<string, string> = {}
+    if (typeof body === 'string') {
+      headers['Content-Type'] = 'application/json'
+    }
+    if (idempotencyKey) {
+      headers['Idempotency-Key'] = idempotencyKey
+    }
+
     const response = await fetch(url, {
       method: 'POST',
-      body: formData,
+      body,
+      headers,
       credentials: 'include', // Send cookies with requests
     })
 
@@ -240,11 +537,48 @@ export async function sendAudioMessage(
   }
 }
 
-export async function getAudioUrl(audioKey: string): Promise<string> {
-  const response = await callAPI<{ url: string }>(`/api/audio/${audioKey}`)
+// quality 'low' asks for the smaller Opus copy of assistant replies when one
+// exists; browsers in data saver mode get it without asking
+export async function getAudioUrl(
+  audioKey: string,
+  quality?: 'low',
+): Promise<string> {
+  const query = quality ? `?quality=${quality}` : ''
+  const response = await callAPI<{ url: string }>(
+    `/api/audio/${audioKey}${query}`,
+    {
+      headers: { Accept: 'application/json' },
+    },
+  )
+  // In audio proxy mode the API returns a same-origin path instead of a presigned URL
+  if (response.url.startsWith('/')) {
+    return `${API_BASE_URL}${response.url}`
+  }
   return response.url
 }
 
+export interface RefreshedAudioUrls {
+  urls: Record<string, string>
+  // null in audio proxy mode, where URLs don't expire
+  expiresAt: string | null
+}
+
+// Renews the playback URLs of many recordings at once, keyed by audio key
+export async function refreshAudioUrls(
+  audioKeys: string[],
+): Promise<RefreshedAudioUrls> {
+  const response = await callAPI<RefreshedAudioUrls>('/api/audio/refresh', {
+    method: 'POST',
+    body: JSON.stringify({ keys: audioKeys }),
+  })
+  for (const [key, url] of Object.entries(response.urls)) {
+    if (url.startsWith('/')) {
+      response.urls[key] = `${API_BASE_URL}${url}`
+    }
+  }
+  return response
+}
+
 // ============================================
 // Auth API
 // ============================================
@@ -255,12 +589,15 @@ export interface User {
   name: string
   avatarUrl?: string
   emailVerified: boolean
+  guest?: boolean
+  guestExpiresAt?: string
 }
 
 interface RegisterRequest {
   email: string
   password: string
   name: string
+  inviteCode?: string
 }
 
 interface LoginRequest {
@@ -269,9 +606,25 @@ interface LoginRequest {
 }
 
 export async function register(data: RegisterRequest): Promise<User> {
+  const fingerprint = await deviceFingerprint()
   return callAPI<User>('/api/auth/register', {
     method: 'POST',
-    body: JSON.stringify(data),
+    body: JSON.stringify({ ...data, fingerprint }),
+  })
+}
+
+export interface SignupOptions {
+  inviteOnly: boolean
+}
+
+export async function getSignupOptions(): Promise<SignupOptions> {
+  return callAPI<SignupOptions>('/api/auth/signup-options')
+}
+
+export async function joinWaitlist(email: string): Promise<{ message: string }> {
+  return callAPI<{ message: string }>('/api/waitlist', {
+    method: 'POST',
+    body: JSON.stringify({ email }),
   })
 }
 
@@ -288,6 +641,44 @@ export async function logout(): Promise<void> {
   })
 }
 
+export async function resendVerification(): Promise<{ message: string }> {
+  return callAPI<{ message: string }>('/api/auth/verify/resend', {
+    method: 'POST',
+  })
+}
+
+export interface DeviceSession {
+  id: string
+  userAgent: string
+  ipAddress: string
+  createdAt: string
+  expiresAt: string
+  current: boolean
+}
+
+export async function getSessions(): Promise<DeviceSession[]> {
+  const data = await callAPI<{ sessions: DeviceSession[] }>('/api/auth/sessions')
+  return data.sessions
+}
+
+export async function revokeSession(id: string): Promise<void> {
+  await callAPI<{ message: string }>(`/api/auth/sessions/${encodeURIComponent(id)}`, {
+    method: 'DELETE',
+  })
+}
+
+export async function logoutEverywhere(): Promise<void> {
+  await callAPI<{ message: string }>('/api/auth/sessions', {
+    method: 'DELETE',
+  })
+}
+
+export async function deleteAccount(): Promise<void> {
+  await callAPI<{ message: string }>('/api/auth/me', {
+    method: 'DELETE',
+  })
+}
+
 export async function getCurrentUser(): Promise<User | null> {
   try {
     return await callAPI<User>('/api/auth/me')
@@ -312,6 +703,19 @@ export interface Subscription {
   currentPeriodStart?: string
   currentPeriodEnd?: string
   cancelAtPeriodEnd: boolean
+  // Set by an admin; replaces tier until cleared or the user pays for a plan
+  tierOverride?: SubscriptionTier
+  // Set while a cancelled paid plan is in its read-only grace period
+  graceTier?: SubscriptionTier
+  graceEndsAt?: string
+  // Set while a downgrade waits for the end of the paid period
+  pendingTier?: SubscriptionTier
+  pendingTierAt?: string
+  // Set while a renewal payment is outstanding
+  paymentFailedAt?: string
+  // From checkout: ISO country of the billing address, and Stripe Tax's result
+  billingCountry?: string
+  taxStatus?: 'complete' | 'failed' | 'requires_location_inputs'
 }
 
 export interface Credits {
@@ -322,14 +726,22 @@ export interface Credits {
   lastRefreshedAt: string
 }
 
+export interface PaymentBanner {
+  state: 'payment_failed' | 'final_notice'
+  failedAt: string
+  // Billing portal page for updating the card; fall back to createPortalSession without it
+  portalUrl?: string
+}
+
 export interface SubscriptionWithCredits {
   subscription: Subscription
   credits: Credits
+  paymentBanner: PaymentBanner | null
 }
 
 export interface CreditTransaction {
   id: string
-  type: 'debit' | 'credit' | 'refresh'
+  type: 'debit' | 'credit' | 'refresh' | 'refund' | 'demo' | 'release'
   amount: number
   balanceAfter: number
   reference?: string
@@ -337,6 +749,30 @@ export interface CreditTransaction {
   createdAt: string
 }
 
+export interface CreditDispute {
+  id: string
+  transactionId: string
+  reason: string
+  status: 'open' | 'refunded' | 'rejected'
+  resolvedAt?: string
+  createdAt: string
+}
+
+export interface ChargedMessage {
+  id: string
+  threadId: string
+  threadName?: string
+  role: 'user' | 'assistant'
+  snippet: string
+  timestamp: string
+}
+
+export interface CreditTransactionDetail {
+  transaction: CreditTransaction
+  message?: ChargedMessage
+  dispute?: CreditDispute
+}
+
 export interface CheckoutResponse {
   url: string
 }
@@ -359,15 +795,78 @@ export async function getCreditHistory(): Promise<{
   return callAPI<{ transactions: CreditTransaction[] }>('/api/credits/history')
 }
 
+export async function getCreditTransaction(
+  transactionId: string,
+): Promise<CreditTransactionDetail> {
+  return callAPI<CreditTransactionDetail>(
+    `/api/credits/history/${transactionId}`,
+  )
+}
+
+export async function disputeCreditTransaction(
+  transactionId: string,
+  reason: string,
+): Promise<CreditDispute> {
+  return callAPI<CreditDispute>(
+    `/api/credits/history/${transactionId}/dispute`,
+    {
+      method: 'POST',
+      body: JSON.stringify({ reason }),
+    },
+  )
+}
+
+// A paid tier's monthly price; amounts are in the currency's smallest unit
+export interface TierPrice {
+  tier: 'basic' | 'pro'
+  credits: number
+  unitAmount: number
+  interval: string
+  taxBehavior: 'inclusive' | 'exclusive' | 'unspecified'
+  // Estimated for the country; null when unknown
+  tax: number | null
+  amountWithTax: number | null
+}
+
+export interface Pricing {
+  currency: string
+  country?: string
+  taxEnabled: boolean
+  tiers: TierPrice[]
+}
+
+export async function getPricing(params?: {
+  currency?: string
+  country?: string
+}): Promise<Pricing> {
+  const query = new URLSearchParams()
+  if (params?.currency) query.set('currency', params.currency)
+  if (params?.country) query.set('country', params.country)
+  const qs = query.toString()
+  return callAPI<Pricing>(`/api/subscription/pricing${qs ? `?${qs}` : ''}`)
+}
+
+// currency is the one getPricing showed, so checkout charges that price
 export async function createCheckoutSession(
   tier: 'basic' | 'pro',
+  currency?: string,
 ): Promise<CheckoutResponse> {
   return callAPI<CheckoutResponse>('/api/subscription/checkout', {
     method: 'POST',
-    body: JSON.stringify({ tier }),
+    body: JSON.stringify({ tier, currency }),
   })
 }
 
+// Formats an amount in the currency's smallest unit, e.g. 1900 eur as €19.00
+export function formatPrice(amount: number, currency: string): string {
+  const format = new Intl.NumberFormat(undefined, {
+    style: 'currency',
+    currency: currency.toUpperCase(),
+  })
+  const digits = format.resolvedOptions().maximumFractionDigits ?? 2
+  return format.format(amount / 10 ** digits)
+}
+
 export async function createPortalSession(): Promise<PortalResponse> {
   return callAPI<PortalResponse>('/api/subscription/portal', {
     method: 'POST',
@@ -387,6 +886,18 @@ export const TIER_INFO = {
   pro: { name: 'Pro', price: 50, credits: 1200 },
 } as const
 
+// The tier's monthly price from Stripe in the user's currency, or the list
+// price in USD while pricing hasn't loaded
+export function tierPriceLabel(
+  pricing: Pricing | undefined,
+  tier: SubscriptionTier,
+): string {
+  if (tier === 'free') return formatPrice(0, pricing?.currency ?? 'usd')
+  const price = pricing?.tiers.find((t) => t.tier === tier)
+  if (!pricing || !price) return formatPrice(TIER_INFO[tier].price * 100, 'usd')
+  return formatPrice(price.amountWithTax ?? price.unitAmount, pricing.currency)
+}
+
 // Helper to check if an error is an insufficient credits error
 export function isInsufficientCreditsError(error: unknown): boolean {
   return (
@@ -396,6 +907,123 @@ export function isInsufficientCreditsError(error: unknown): boolean {
   )
 }
 
+// ============================================
+// Usage (per-tier thread and message limits)
+// ============================================
+
+export interface UsageCount {
+  count: number
+  limit: number // 0 = unlimited
+}
+
+export interface Usage {
+  tier: 'free' | 'basic' | 'pro'
+  threads: UsageCount
+  messages: UsageCount
+}
+
+export interface TierLimitDetails {
+  resource: 'threads' | 'messages'
+  tier: 'free' | 'basic' | 'pro'
+  limit: number
+  count: number
+}
+
+export async function getUsage(): Promise<Usage> {
+  return callAPI<Usage>('/api/usage')
+}
+
+// Helper to check if an error is a plan limit error; returns its details
+export function getTierLimitError(error: unknown): TierLimitDetails | null {
+  if (
+    error instanceof ApiError &&
+    error.status === 403 &&
+    (error.data as { code?: string })?.code === 'TIER_LIMIT_REACHED'
+  ) {
+    return (error.data as { details: TierLimitDetails }).details
+  }
+  return null
+}
+
+// ============================================
+// Settings API
+// ============================================
+
+// Days to keep raw recordings; 0 keeps them. Transcripts and analyses are never deleted.
+export type AudioRetentionDays = 0 | 7 | 30 | 90
+
+export type ReplyLength = 'short' | 'medium' | 'long'
+
+export type ThreadLanguage = 'en' | 'es' | 'fr' | 'de'
+
+export type EmailFrequency = 'instant' | 'daily' | 'off'
+
+export interface UserSettings {
+  audioRetentionDays: AudioRetentionDays
+  replyLength: ReplyLength
+  // How fast replies are read aloud, 0.5 to 1.5; 1 is normal speed
+  speechRate: number
+  // Transcripts and analyses are encrypted before they're stored
+  encryptContent: boolean
+  // IANA timezone whose midnight starts the user's day, e.g. 'Europe/Madrid'
+  timezone: string
+  // How often goal and streak notifications are emailed
+  emailAchievements: EmailFrequency
+  // How often export and credit notifications are emailed
+  emailUpdates: EmailFrequency
+  updatedAt: string
+}
+
+export async function getSettings(): Promise<UserSettings> {
+  return callAPI<UserSettings>('/api/settings')
+}
+
+export async function updateSettings(data: {
+  audioRetentionDays?: AudioRetentionDays
+  replyLength?: ReplyLength
+  speechRate?: number
+  encryptContent?: boolean
+  timezone?: string
+  emailAchievements?: EmailFrequency
+  emailUpdates?: EmailFrequency
+}): Promise<UserSettings> {
+  return callAPI<UserSettings>('/api/settings', {
+    method: 'PATCH',
+    body: JSON.stringify(data),
+  })
+}
+
+// ============================================
+// Learner Memory API
+// ============================================
+
+// What the assistant remembers about the learner across threads. Turning
+// memory off (enabled: false) also forgets everything remembered so far.
+export interface LearnerProfile {
+  disabled: boolean
+  preferredName: string
+  interests: string[]
+  recurringMistakes: string[]
+  extractedAt?: string
+  updatedAt: string
+}
+
+export async function getLearnerMemory(): Promise<LearnerProfile> {
+  return callAPI<LearnerProfile>('/api/profile/memory')
+}
+
+export async function updateLearnerMemory(data: {
+  enabled?: boolean
+  preferredName?: string
+  interests?: string[]
+  recurringMistakes?: string[]
+}): Promise<LearnerProfile> {
+  return callAPI<LearnerProfile>('/api/profile/memory', {
+    method: 'PATCH',
+    body: JSON.stringify(data),
+  })
+}
+
 // ============================================
 // Pronunciation Stats API
 // ============================================
@@ -406,6 +1034,8 @@ export interface PhonemeAccuracy {
   correctCount: number
   deletionCount: number
   accuracy: number
+  // How much the phoneme counts toward overallAccuracy (1 unless weighted)
+  weight: number
 }
 
 export interface SubstitutionPattern {
@@ -415,14 +1045,295 @@ export interface SubstitutionPattern {
 }
 
 export interface PhonemeStatsResponse {
+  language: ThreadLanguage
+  languages: ThreadLanguage[] // every language the user has stats in
   totalPhonemes: number
   overallAccuracy: number
   phonemeStats: PhonemeAccuracy[]
   commonSubstitutions: SubstitutionPattern[]
 }
 
-export async function getPhonemeStats(): Promise<PhonemeStatsResponse> {
-  return callAPI<PhonemeStatsResponse>('/api/pronunciation/stats')
+export async function getPhonemeStats(language?: ThreadLanguage): Promise<PhonemeStatsResponse> {
+  const query = language ? `?language=${language}` : ''
+  return callAPI<PhonemeStatsResponse>(`/api/pronunciation/stats${query}`)
+}
+
+export interface PhonemeExample {
+  text: string
+  audioKey?: string // play through getAudioUrl; missing if TTS failed
+}
+
+export interface PhonemeExamples {
+  phoneme: string
+  tip: string
+  examples: PhonemeExample[]
+}
+
+export async function getPhonemeExamples(
+  phoneme: string,
+  rate?: number
+): Promise<PhonemeExamples> {
+  const query = rate ? `?rate=${rate}` : ''
+  return callAPI<PhonemeExamples>(
+    `/api/pronunciation/phonemes/${encodeURIComponent(phoneme)}/examples${query}`
+  )
+}
+
+export interface AnkiExportResponse {
+  exportId: string
+  status: 'pending'
+  downloadUrl: string
+}
+
+// Builds the deck in the background; an export_ready notification carries
+// the download link
+export async function exportAnkiDeck(): Promise<AnkiExportResponse> {
+  return callAPI<AnkiExportResponse>('/api/practice/export/anki')
+}
+
+export interface PronunciationReport {
+  reportId: string
+  url: string
+  expiresAt: string
+}
+
+// Renders a PDF for sharing with a tutor. The link works without signing in
+// until it expires.
+export async function createPronunciationReport(): Promise<PronunciationReport> {
+  return callAPI<PronunciationReport>('/api/reports/pronunciation', {
+    method: 'POST',
+  })
+}
+
+// ============================================
+// Public Stats Badge API
+// ============================================
+
+export interface StatsBadge {
+  token: string
+  createdAt: string
+}
+
+export async function getStatsBadge(): Promise<StatsBadge | null> {
+  try {
+    return await callAPI<StatsBadge>('/api/badge')
+  } catch (error) {
+    if (error instanceof ApiError && error.status === 404) {
+      return null
+    }
+    throw error
+  }
+}
+
+// Enabling again rotates the token, breaking embeds of the old one
+export async function enableStatsBadge(): Promise<StatsBadge> {
+  return callAPI<StatsBadge>('/api/badge', { method: 'POST' })
+}
+
+export async function revokeStatsBadge(): Promise<void> {
+  await callAPI<{ message: string }>('/api/badge', { method: 'DELETE' })
+}
+
+// Embeddable image URL, e.g. for <img src=...> in a blog post
+export function statsBadgeImageUrl(token: string): string {
+  const base = API_BASE_URL || window.location.origin
+  return `${base}/api/public/badge/${token}.svg`
+}
+
+// Home screen
+
+export interface Scenario {
+  id: string
+  title: string
+  goal: string
+  opener: string
+}
+
+export interface HomeScreen {
+  streak: { days: number; practicedToday: boolean } | null
+  credits: { balance: number; monthlyAllowance: number } | null
+  recommendedScenario:
+    | (Scenario & {
+        reason: 'starter' | 'weak_phoneme' | 'interest' | 'daily'
+        phoneme?: string
+      })
+    | null
+  dueReviews: {
+    phoneme: string
+    accuracy: number
+    totalAttempts: number
+    lastPracticed: string
+  }[]
+  lastActiveThread: ThreadSummary | null
+  motivation: string
+  // Sections that failed to load
+  unavailable?: string[]
+}
+
+export async function getHome(): Promise<HomeScreen> {
+  return callAPI<HomeScreen>('/api/home')
+}
+
+// App start-up
+
+export interface Bootstrap {
+  user: User
+  credits: Credits | null
+  // Newest thread with messages, without analyses; null before the first one
+  lastThread: Thread | null
+  // Sections that failed to load
+  unavailable?: string[]
+}
+
+export async function getBootstrap(): Promise<Bootstrap> {
+  return callAPI<Bootstrap>('/api/bootstrap')
+}
+
+// Practice sessions
+
+export interface PracticeSessionSummary {
+  durationSeconds: number
+  turns: number
+  // Percent of phonemes pronounced correctly; null if no turn was scored
+  accuracy: number | null
+  scoredTurns: number
+  newVocabulary: string[]
+}
+
+export interface PracticeSession {
+  id: string
+  threadId: string
+  plannedMinutes: number
+  startedAt: string
+  timerEndsAt: string
+  endedAt?: string
+  summary?: PracticeSessionSummary
+}
+
+export async function startPracticeSession(
+  threadId: string,
+  minutes?: number,
+): Promise<PracticeSession> {
+  return callAPI<PracticeSession>('/api/sessions', {
+    method: 'POST',
+    body: JSON.stringify({ threadId, minutes }),
+  })
+}
+
+export async function endPracticeSession(
+  sessionId: string,
+): Promise<PracticeSession> {
+  return callAPI<PracticeSession>(`/api/sessions/${sessionId}/end`, {
+    method: 'POST',
+  })
+}
+
+export async function getPracticeSessions(
+  limit?: number,
+): Promise<PracticeSession[]> {
+  const query = limit ? `?limit=${limit}` : ''
+  const response = await callAPI<{ sessions: PracticeSession[] }>(
+    `/api/sessions${query}`,
+  )
+  return response.sessions
+}
+
+// Thread shares: one-hour read-only live links for a tutor
+
+export interface ThreadShare {
+  id: string
+  threadId: string
+  token: string
+  expiresAt: string
+  revokedAt?: string
+  createdAt: string
+}
+
+// Events on a share stream. "thread" comes first with the thread so far;
+// "message" and "analysis" carry a message to add or replace by ID.
+export type ThreadShareEvent =
+  | { event: 'thread'; data: { thread: Thread; expiresAt: string } }
+  | { event: 'message' | 'analysis'; data: { type: string; message: Message } }
+  | {
+      event: 'closed'
+      data: { reason: 'revoked' | 'expired' | 'behind' | 'restart' }
+    }
+
+export async function createThreadShare(
+  threadId: string,
+): Promise<ThreadShare> {
+  return callAPI<ThreadShare>(`/api/threads/${threadId}/shares`, {
+    method: 'POST',
+  })
+}
+
+export async function getThreadShares(
+  threadId: string,
+): Promise<ThreadShare[]> {
+  const response = await callAPI<{ shares: ThreadShare[] }>(
+    `/api/threads/${threadId}/shares`,
+  )
+  return response.shares
+}
+
+export async function revokeThreadShare(
+  threadId: string,
+  shareId: string,
+): Promise<void> {
+  await callAPI<{ message: string }>(
+    `/api/threads/${threadId}/shares/${shareId}`,
+    { method: 'DELETE' },
+  )
+}
+
+// Server-sent event stream for new EventSource(...). No login needed.
+export function threadShareStreamUrl(token: string): string {
+  const base = API_BASE_URL || window.location.origin
+  return `${base}/api/public/shares/${token}`
+}
+
+// Notifications
+
+export type NotificationType =
+  | 'goal_completed'
+  | 'payment_failed'
+  | 'export_ready'
+  | 'export_failed'
+  | 'credits_low'
+  | 'streak_milestone'
+
+export interface Notification {
+  id: string
+  type: NotificationType
+  title: string
+  body: string
+  data?: Record<string, unknown>
+  readAt?: string | null
+  createdAt: string
+}
+
+export interface NotificationList {
+  notifications: Notification[]
+  unreadCount: number
+}
+
+export async function getNotifications(
+  unreadOnly = false,
+): Promise<NotificationList> {
+  const query = unreadOnly ? '?unread=true' : ''
+  return callAPI<NotificationList>(`/api/notifications${query}`)
+}
+
+export async function markNotificationRead(id: string): Promise<void> {
+  await callAPI<{ message: string }>(`/api/notifications/${id}/read`, {
+    method: 'POST',
+  })
+}
+
+export async function markAllNotificationsRead(): Promise<void> {
+  await callAPI<{ message: string }>('/api/notifications/read-all', {
+    method: 'POST',
+  })
 }
 
 export { ApiError }
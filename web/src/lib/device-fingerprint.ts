@@ -0,0 +1,33 @@
+/**
+ * Coarse device hash sent with registrations so the API can spot repeat
+ * free-trial signups from one browser. It is built from stable browser
+ * traits only and never leaves the device unhashed.
+ */
+
+/**
+ * SHA-256 hex digest of the browser's traits, or undefined when the
+ * browser can't compute one (e.g. no SubtleCrypto outside secure contexts)
+ */
+export async function deviceFingerprint(): Promise<string | undefined> {
+  try {
+    if (typeof crypto === 'undefined' || !crypto.subtle) return undefined
+
+    const traits = [
+      navigator.userAgent,
+      navigator.language,
+      navigator.languages?.join(','),
+      navigator.hardwareConcurrency,
+      screen.width,
+      screen.height,
+      screen.colorDepth,
+      Intl.DateTimeFormat().resolvedOptions().timeZone,
+    ].join('|')
+
+    const digest = await crypto.subtle.digest('SHA-256', new TextEncoder().encode(traits))
+    return Array.from(new Uint8Array(digest))
+      .map((b) => b.toString(16).padStart(2, '0'))
+      .join('')
+  } catch {
+    return undefined
+  }
+}
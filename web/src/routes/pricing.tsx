@@ -2,8 +2,17 @@ import { createFileRoute, Link } from '@tanstack/react-router'
 import { Check, Loader2, ArrowLeft } from 'lucide-react'
 import { Button } from '@/components/ui/button'
 import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from '@/components/ui/card'
-import { useSubscription, useCreateCheckout } from '@/hooks/use-subscription'
-import { TIER_INFO, CREDIT_COSTS, type SubscriptionTier } from '@/lib/api'
+import {
+  useSubscription,
+  useCreateCheckout,
+  usePricing,
+} from '@/hooks/use-subscription'
+import {
+  TIER_INFO,
+  CREDIT_COSTS,
+  tierPriceLabel,
+  type SubscriptionTier,
+} from '@/lib/api'
 import { cn } from '@/lib/utils'
 import { useState } from 'react'
 
@@ -37,6 +46,7 @@ const features = {
 
 function PricingPage() {
   const { data: subscription, isLoading } = useSubscription()
+  const { data: pricing } = usePricing()
   const createCheckout = useCreateCheckout()
   const [selectedTier, setSelectedTier] = useState<'basic' | 'pro' | null>(null)
 
@@ -44,7 +54,7 @@ function PricingPage() {
 
   const handleUpgrade = (tier: 'basic' | 'pro') => {
     setSelectedTier(tier)
-    createCheckout.mutate(tier)
+    createCheckout.mutate({ tier, currency: pricing?.currency })
   }
 
   return (
@@ -69,7 +79,7 @@ function PricingPage() {
           <PricingCard
             tier="free"
             name={TIER_INFO.free.name}
-            price={TIER_INFO.free.price}
+            price={tierPriceLabel(pricing, 'free')}
             credits={TIER_INFO.free.credits}
             features={features.free}
             isCurrentPlan={currentTier === 'free'}
@@ -80,7 +90,7 @@ function PricingPage() {
           <PricingCard
             tier="basic"
             name={TIER_INFO.basic.name}
-            price={TIER_INFO.basic.price}
+            price={tierPriceLabel(pricing, 'basic')}
             credits={TIER_INFO.basic.credits}
             features={features.basic}
             isCurrentPlan={currentTier === 'basic'}
@@ -93,7 +103,7 @@ function PricingPage() {
           <PricingCard
             tier="pro"
             name={TIER_INFO.pro.name}
-            price={TIER_INFO.pro.price}
+            price={tierPriceLabel(pricing, 'pro')}
             credits={TIER_INFO.pro.credits}
             features={features.pro}
             isCurrentPlan={currentTier === 'pro'}
@@ -118,7 +128,7 @@ function PricingPage() {
 interface PricingCardProps {
   tier: SubscriptionTier
   name: string
-  price: number
+  price: string
   credits: number
   features: string[]
   isCurrentPlan: boolean
@@ -157,9 +167,9 @@ function PricingCard({
         <CardTitle>{name}</CardTitle>
         <CardDescription>
           <span className="text-3xl font-bold text-foreground">
-            ${price}
+            {price}
           </span>
-          {price > 0 && <span className="text-muted-foreground">/month</span>}
+          {tier !== 'free' && <span className="text-muted-foreground">/month</span>}
         </CardDescription>
       </CardHeader>
 
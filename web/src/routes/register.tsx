@@ -10,6 +10,7 @@ import {
 import { Input } from '@/components/ui/input'
 import { Label } from '@/components/ui/label'
 import { useAuth } from '@/contexts/AuthContext'
+import { useJoinWaitlist, useSignupOptions } from '@/hooks/use-auth'
 import { redirectIfAuthenticated } from '@/lib/auth-guard'
 import { createFileRoute, Link, useNavigate } from '@tanstack/react-router'
 import { useState } from 'react'
@@ -27,6 +28,30 @@ function RegisterPage() {
   const [password, setPassword] = useState('')
   const [confirmPassword, setConfirmPassword] = useState('')
   const [error, setError] = useState('')
+  const [inviteCode, setInviteCode] = useState(
+    () => new URLSearchParams(window.location.search).get('invite') ?? ''
+  )
+  const { data: signupOptions } = useSignupOptions()
+  const joinWaitlist = useJoinWaitlist()
+  const inviteOnly = signupOptions?.inviteOnly ?? false
+
+  const oauthURL = (provider: 'google' | 'github') => {
+    const base = `${import.meta.env.VITE_API_URL || ''}/api/auth/${provider}`
+    return inviteCode ? `${base}?invite=${encodeURIComponent(inviteCode)}` : base
+  }
+
+  const handleJoinWaitlist = async () => {
+    setError('')
+    if (!email) {
+      setError('Enter your email to join the waitlist')
+      return
+    }
+    try {
+      await joinWaitlist.mutateAsync(email)
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Could not join the waitlist')
+    }
+  }
 
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault()
@@ -45,7 +70,12 @@ function RegisterPage() {
     }
 
     try {
-      await register.mutateAsync({ email, password, name })
+      await register.mutateAsync({
+        email,
+        password,
+        name,
+        inviteCode: inviteOnly ? inviteCode : undefined,
+      })
       navigate({ to: '/' })
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Registration failed')
@@ -94,6 +124,38 @@ function RegisterPage() {
               />
             </div>
 
+            {inviteOnly && (
+              <div className="space-y-2">
+                <Label htmlFor="inviteCode">Invite code</Label>
+                <Input
+                  id="inviteCode"
+                  type="text"
+                  placeholder="e.g. K7QM3XPD2A"
+                  value={inviteCode}
+                  onChange={(e) => setInviteCode(e.target.value)}
+                  required
+                  autoComplete="off"
+                />
+                {joinWaitlist.isSuccess ? (
+                  <p className="text-sm text-muted-foreground">
+                    You're on the waitlist. We'll email you an invite.
+                  </p>
+                ) : (
+                  <p className="text-sm text-muted-foreground">
+                    No invite yet?{' '}
+                    <button
+                      type="button"
+                      className="text-primary hover:underline"
+                      onClick={handleJoinWaitlist}
+                      disabled={joinWaitlist.isPending}
+                    >
+                      Join the waitlist
+                    </button>
+                  </p>
+                )}
+              </div>
+            )}
+
             <div className="space-y-2">
               <Label htmlFor="password">Password</Label>
               <Input
@@ -146,7 +208,7 @@ function RegisterPage() {
                 type="button"
                 variant="outline"
                 onClick={() => {
-                  window.location.href = `${import.meta.env.VITE_API_URL || ''}/api/auth/google`
+                  window.location.href = oauthURL('google')
                 }}
               >
                 <svg className="mr-2 h-4 w-4" viewBox="0 0 24 24">
@@ -173,7 +235,7 @@ function RegisterPage() {
                 type="button"
                 variant="outline"
                 onClick={() => {
-                  window.location.href = `${import.meta.env.VITE_API_URL || ''}/api/auth/github`
+                  window.location.href = oauthURL('github')
                 }}
               >
                 <svg
@@ -4,8 +4,13 @@ import { Button } from '@/components/ui/button'
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
 import { Avatar, AvatarFallback } from '@/components/ui/avatar'
 import { useAuth } from '@/contexts/AuthContext'
-import { useSubscription, useCredits, useCreatePortal } from '@/hooks/use-subscription'
-import { TIER_INFO } from '@/lib/api'
+import {
+  useSubscription,
+  useCredits,
+  useCreatePortal,
+  usePricing,
+} from '@/hooks/use-subscription'
+import { TIER_INFO, tierPriceLabel } from '@/lib/api'
 
 export const Route = createFileRoute('/settings')({
   component: SettingsPage,
@@ -15,6 +20,7 @@ function SettingsPage() {
   const { user, logout } = useAuth()
   const { data: subscription, isLoading: subscriptionLoading } = useSubscription()
   const { data: credits } = useCredits()
+  const { data: pricing } = usePricing()
   const createPortal = useCreatePortal()
 
   const currentTier = subscription?.subscription?.tier ?? 'free'
@@ -107,7 +113,7 @@ function SettingsPage() {
                     <div>
                       <p className="font-medium">{tierInfo.name}</p>
                       <p className="text-sm text-muted-foreground">
-                        {tierInfo.price === 0 ? 'Free plan' : `$${tierInfo.price}/month`}
+                        {tierInfo.price === 0 ? 'Free plan' : `${tierPriceLabel(pricing, currentTier)}/month`}
                       </p>
                     </div>
                     <div className="text-right">